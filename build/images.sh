@@ -25,6 +25,8 @@ dev_images=(
   "async-gateway"
   "enqueuer"
   "dequeuer"
+  "model-cache"
+  "prepull"
   "autoscaler"
   "activator"
 )
@@ -55,13 +57,31 @@ non_dev_images=(
 
 # for linux/amd64 and linux/arm64
 multi_arch_images=(
+  "manager"
+  "operator"
+  "controller-manager"
   "proxy"
   "async-gateway"
   "enqueuer"
   "dequeuer"
+  "model-cache"
+  "prepull"
+  "autoscaler"
+  "activator"
+  "cluster-autoscaler"
+  "istio-proxy"
+  "istio-pilot"
   "fluent-bit"
+  "prometheus"
+  "prometheus-config-reloader"
+  "prometheus-operator"
+  "prometheus-statsd-exporter"
+  "prometheus-kube-state-metrics"
   "prometheus-node-exporter"
   "kube-rbac-proxy"
+  "grafana"
+  "event-exporter"
+  "metrics-server"
   "kubexit"
 )
 
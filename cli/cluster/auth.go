@@ -0,0 +1,101 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"net/http"
+
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/json"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+)
+
+// GetOIDCConfig retrieves the cluster's identity provider configuration; the request is not authenticated, since it's made before logging in
+func GetOIDCConfig(operatorConfig OperatorConfig) (schema.OIDCConfigResponse, error) {
+	req, err := operatorRequest(operatorConfig, http.MethodGet, "/auth/oidc", nil)
+	if err != nil {
+		return schema.OIDCConfigResponse{}, err
+	}
+
+	httpRes, err := doOperatorRequest(operatorConfig, req)
+	if err != nil {
+		return schema.OIDCConfigResponse{}, err
+	}
+
+	var oidcConfig schema.OIDCConfigResponse
+	if err = json.Unmarshal(httpRes, &oidcConfig); err != nil {
+		return schema.OIDCConfigResponse{}, errors.Wrap(err, "/auth/oidc", string(httpRes))
+	}
+	return oidcConfig, nil
+}
+
+func WhoAmI(operatorConfig OperatorConfig) (schema.WhoAmIResponse, error) {
+	httpRes, err := HTTPGet(operatorConfig, "/auth/whoami")
+	if err != nil {
+		return schema.WhoAmIResponse{}, err
+	}
+
+	var whoAmIRes schema.WhoAmIResponse
+	if err = json.Unmarshal(httpRes, &whoAmIRes); err != nil {
+		return schema.WhoAmIResponse{}, errors.Wrap(err, "/auth/whoami", string(httpRes))
+	}
+	return whoAmIRes, nil
+}
+
+func CreateRoleBinding(operatorConfig OperatorConfig, name string, role string, subject string) (schema.RoleBinding, error) {
+	params := map[string]string{
+		"role":    role,
+		"subject": subject,
+	}
+
+	httpRes, err := HTTPPostNoBody(operatorConfig, "/auth/bindings/"+name, params)
+	if err != nil {
+		return schema.RoleBinding{}, err
+	}
+
+	var binding schema.RoleBinding
+	if err = json.Unmarshal(httpRes, &binding); err != nil {
+		return schema.RoleBinding{}, errors.Wrap(err, "/auth/bindings", string(httpRes))
+	}
+	return binding, nil
+}
+
+func ListRoleBindings(operatorConfig OperatorConfig) ([]schema.RoleBinding, error) {
+	httpRes, err := HTTPGet(operatorConfig, "/auth/bindings")
+	if err != nil {
+		return nil, err
+	}
+
+	var bindings []schema.RoleBinding
+	if err = json.Unmarshal(httpRes, &bindings); err != nil {
+		return nil, errors.Wrap(err, "/auth/bindings", string(httpRes))
+	}
+	return bindings, nil
+}
+
+func DeleteRoleBinding(operatorConfig OperatorConfig, name string) (schema.DeleteRoleBindingResponse, error) {
+	httpRes, err := HTTPDelete(operatorConfig, "/auth/bindings/"+name)
+	if err != nil {
+		return schema.DeleteRoleBindingResponse{}, err
+	}
+
+	var deleteRes schema.DeleteRoleBindingResponse
+	if err = json.Unmarshal(httpRes, &deleteRes); err != nil {
+		return schema.DeleteRoleBindingResponse{}, errors.Wrap(err, "/auth/bindings", string(httpRes))
+	}
+	return deleteRes, nil
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/json"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+)
+
+// weight is nil to fully promote the canary
+func Promote(operatorConfig OperatorConfig, apiName string, weight *int32) (schema.PromoteResponse, error) {
+	params := map[string]string{}
+	if weight != nil {
+		params["weight"] = s.Int32(*weight)
+	}
+
+	httpRes, err := HTTPPostNoBody(operatorConfig, "/promote/"+apiName, params)
+	if err != nil {
+		return schema.PromoteResponse{}, err
+	}
+
+	var promoteRes schema.PromoteResponse
+	err = json.Unmarshal(httpRes, &promoteRes)
+	if err != nil {
+		return schema.PromoteResponse{}, errors.Wrap(err, "/promote", string(httpRes))
+	}
+
+	return promoteRes, nil
+}
+
+// toVersion is empty to remove the canary (if one is deployed) or to roll back to the most recent version with a different configuration
+func Rollback(operatorConfig OperatorConfig, apiName string, toVersion string, force bool) (schema.RollbackResponse, error) {
+	params := map[string]string{
+		"force": s.Bool(force),
+	}
+	if toVersion != "" {
+		params["toVersion"] = toVersion
+	}
+
+	httpRes, err := HTTPPostNoBody(operatorConfig, "/rollback/"+apiName, params)
+	if err != nil {
+		return schema.RollbackResponse{}, err
+	}
+
+	var rollbackRes schema.RollbackResponse
+	err = json.Unmarshal(httpRes, &rollbackRes)
+	if err != nil {
+		return schema.RollbackResponse{}, errors.Wrap(err, "/rollback", string(httpRes))
+	}
+
+	return rollbackRes, nil
+}
@@ -19,6 +19,7 @@ package cluster
 import (
 	"fmt"
 	"path"
+	"strings"
 
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
 	"github.com/cortexlabs/cortex/pkg/lib/json"
@@ -29,7 +30,7 @@ import (
 	"github.com/cortexlabs/cortex/pkg/types/userconfig"
 )
 
-func Delete(operatorConfig OperatorConfig, apiName string, keepCache bool, force bool) (schema.DeleteResponse, error) {
+func Delete(operatorConfig OperatorConfig, apiName string, keepCache bool, keepVolumes bool, force bool) (schema.DeleteResponse, error) {
 	if !force {
 		readyReplicas := getReadyRealtimeAPIReplicasOrNil(operatorConfig, apiName)
 		if readyReplicas != nil && *readyReplicas > 2 {
@@ -38,8 +39,9 @@ func Delete(operatorConfig OperatorConfig, apiName string, keepCache bool, force
 	}
 
 	params := map[string]string{
-		"apiName":   apiName,
-		"keepCache": s.Bool(keepCache),
+		"apiName":     apiName,
+		"keepCache":   s.Bool(keepCache),
+		"keepVolumes": s.Bool(keepVolumes),
 	}
 
 	httpRes, err := HTTPDelete(operatorConfig, "/delete/"+apiName, params)
@@ -56,6 +58,37 @@ func Delete(operatorConfig OperatorConfig, apiName string, keepCache bool, force
 	return deleteRes, nil
 }
 
+// DeleteAPIs deletes the apis which match the filters; if apiNames is not empty, only the matching apis which it lists are deleted
+func DeleteAPIs(operatorConfig OperatorConfig, all bool, kind string, selector string, apiNames []string, keepCache bool, keepVolumes bool, dryRun bool) (schema.BulkDeleteResponse, error) {
+	params := map[string]string{
+		"all":         s.Bool(all),
+		"kind":        kind,
+		"selector":    selector,
+		"keepCache":   s.Bool(keepCache),
+		"keepVolumes": s.Bool(keepVolumes),
+		"dryRun":      s.Bool(dryRun),
+	}
+	if len(apiNames) > 0 {
+		params["names"] = strings.Join(apiNames, ",")
+	}
+	if operatorConfig.Project != "" {
+		params["project"] = operatorConfig.Project
+	}
+
+	httpRes, err := HTTPDelete(operatorConfig, "/delete", params)
+	if err != nil {
+		return schema.BulkDeleteResponse{}, err
+	}
+
+	var deleteRes schema.BulkDeleteResponse
+	err = json.Unmarshal(httpRes, &deleteRes)
+	if err != nil {
+		return schema.BulkDeleteResponse{}, errors.Wrap(err, "/delete", string(httpRes))
+	}
+
+	return deleteRes, nil
+}
+
 func getReadyRealtimeAPIReplicasOrNil(operatorConfig OperatorConfig, apiName string) *int32 {
 	httpRes, err := HTTPGet(operatorConfig, "/get/"+apiName)
 	if err != nil {
@@ -23,13 +23,24 @@ import (
 	"github.com/cortexlabs/cortex/pkg/lib/json"
 	s "github.com/cortexlabs/cortex/pkg/lib/strings"
 	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
 )
 
-func Deploy(operatorConfig OperatorConfig, configPath string, deploymentBytesMap map[string][]byte, force bool) ([]schema.DeployResult, error) {
+func Deploy(operatorConfig OperatorConfig, configPath string, deploymentBytesMap map[string][]byte, force bool, skipScan bool, gitSource *userconfig.GitSource) ([]schema.DeployResult, error) {
 	params := map[string]string{
 		"force":          s.Bool(force),
+		"skipScan":       s.Bool(skipScan),
 		"configFileName": filepath.Base(configPath),
 	}
+	if gitSource != nil {
+		params["gitRepository"] = gitSource.Repository
+		params["gitPath"] = gitSource.Path
+		params["gitRef"] = gitSource.Ref
+		params["gitCommit"] = gitSource.Commit
+	}
+	if operatorConfig.Project != "" {
+		params["project"] = operatorConfig.Project
+	}
 	uploadInput := &HTTPUploadInput{
 		Bytes: deploymentBytesMap,
 	}
@@ -46,3 +57,27 @@ func Deploy(operatorConfig OperatorConfig, configPath string, deploymentBytesMap
 
 	return deployResults, nil
 }
+
+func Diff(operatorConfig OperatorConfig, configPath string, deploymentBytesMap map[string][]byte) ([]schema.DiffResult, error) {
+	params := map[string]string{
+		"configFileName": filepath.Base(configPath),
+	}
+	if operatorConfig.Project != "" {
+		params["project"] = operatorConfig.Project
+	}
+	uploadInput := &HTTPUploadInput{
+		Bytes: deploymentBytesMap,
+	}
+
+	response, err := HTTPUpload(operatorConfig, "/diff", uploadInput, params)
+	if err != nil {
+		return nil, err
+	}
+
+	var diffResults []schema.DiffResult
+	if err := json.Unmarshal(response, &diffResults); err != nil {
+		return nil, errors.Wrap(err, "/diff", string(response))
+	}
+
+	return diffResults, nil
+}
@@ -41,6 +41,7 @@ const (
 	ErrResponseUnknown               = "cli.response_unknown"
 	ErrOperatorResponseUnknown       = "cli.operator_response_unknown"
 	ErrOperatorStreamResponseUnknown = "cli.operator_stream_response_unknown"
+	ErrPortForwardFailed             = "cli.port_forward_failed"
 )
 
 func ErrorFailedToConnectOperator(originalError error, envName string, operatorURL string) error {
@@ -80,6 +81,14 @@ func ErrorOperatorSocketRead(err error) error {
 	})
 }
 
+func ErrorPortForwardFailed(reason string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:        ErrPortForwardFailed,
+		Message:     reason,
+		NoTelemetry: true,
+	})
+}
+
 func ErrorResponseUnknown(body string, statusCode int) error {
 	msg := body
 	if strings.TrimSpace(body) == "" {
@@ -0,0 +1,198 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"io"
+	"os"
+	"os/signal"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/cortexlabs/cortex/cli/lib/routines"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/json"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/gorilla/websocket"
+	"golang.org/x/term"
+)
+
+const (
+	_execTerminalSizePollPeriod = 250 * time.Millisecond
+	_execInterruptedExitCode    = 130
+)
+
+// ExecOptions configures a command which is run in a replica of an api (or a worker of a job)
+type ExecOptions struct {
+	JobID     string
+	Replica   string
+	Container string
+	Command   []string // the operator runs a shell if no command is specified
+	TTY       bool     // if set, the local terminal is put in raw mode and its size is forwarded
+}
+
+func (options ExecOptions) queryParams() (map[string]string, error) {
+	params := map[string]string{
+		"tty": s.Bool(options.TTY),
+	}
+	if options.JobID != "" {
+		params["jobID"] = options.JobID
+	}
+	if options.Replica != "" {
+		params["replica"] = options.Replica
+	}
+	if options.Container != "" {
+		params["container"] = options.Container
+	}
+	if len(options.Command) > 0 {
+		commandBytes, err := json.Marshal(options.Command)
+		if err != nil {
+			return nil, err
+		}
+		params["command"] = string(commandBytes)
+	}
+	return params, nil
+}
+
+// Exec runs a command in a running replica of the api through the operator, connecting it to the local stdin, stdout, and stderr; it returns the command's exit code
+func Exec(operatorConfig OperatorConfig, apiName string, options ExecOptions) (int, error) {
+	params, err := options.queryParams()
+	if err != nil {
+		return 1, err
+	}
+
+	connection, header, err := dialOperatorSocket(operatorConfig, "/exec/"+apiName, params)
+	if err != nil {
+		return 1, err
+	}
+	defer connection.Close()
+
+	os.Stderr.WriteString("connected to replica " + header.Get(schema.ReplicaHeader) + "\n")
+
+	writer := &execSocketWriter{connection: connection}
+
+	if options.TTY {
+		stdinFd := int(os.Stdin.Fd())
+		oldState, err := term.MakeRaw(stdinFd)
+		if err != nil {
+			return 1, errors.WithStack(err)
+		}
+		defer term.Restore(stdinFd, oldState)
+
+		routines.RunWithPanicHandler(func() {
+			forwardTerminalSize(writer)
+		}, false)
+	} else {
+		interrupt := make(chan os.Signal, 1)
+		signal.Notify(interrupt, os.Interrupt)
+		routines.RunWithPanicHandler(func() {
+			<-interrupt
+			writer.close()
+		}, false)
+	}
+
+	routines.RunWithPanicHandler(func() {
+		forwardStdin(writer)
+	}, false)
+
+	return readExecOutput(connection)
+}
+
+func readExecOutput(connection *websocket.Conn) (int, error) {
+	exitCode := _execInterruptedExitCode
+	for {
+		_, message, err := connection.ReadMessage()
+		if err != nil {
+			// the operator closes the connection once the command has exited
+			if websocket.IsCloseError(err, websocket.CloseNormalClosure) {
+				return exitCode, nil
+			}
+			return 1, ErrorOperatorSocketRead(err)
+		}
+		if len(message) == 0 {
+			continue
+		}
+
+		switch message[0] {
+		case schema.ExecStdoutStream:
+			os.Stdout.Write(message[1:])
+		case schema.ExecStderrStream:
+			os.Stderr.Write(message[1:])
+		case schema.ExecExitCodeStream:
+			if code, err := strconv.Atoi(string(message[1:])); err == nil {
+				exitCode = code
+			}
+		}
+	}
+}
+
+func forwardStdin(writer *execSocketWriter) {
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := os.Stdin.Read(buf)
+		if n > 0 {
+			if writeErr := writer.write(schema.ExecStdinStream, buf[:n]); writeErr != nil {
+				return
+			}
+		}
+		if err != nil {
+			if err == io.EOF {
+				writer.write(schema.ExecStdinStream, nil)
+			}
+			return
+		}
+	}
+}
+
+func forwardTerminalSize(writer *execSocketWriter) {
+	stdoutFd := int(os.Stdout.Fd())
+	var lastSize schema.ExecTerminalSize
+	for {
+		width, height, err := term.GetSize(stdoutFd)
+		if err == nil {
+			size := schema.ExecTerminalSize{Width: uint16(width), Height: uint16(height)}
+			if size != lastSize {
+				sizeBytes, _ := json.Marshal(size)
+				if err := writer.write(schema.ExecResizeStream, sizeBytes); err != nil {
+					return
+				}
+				lastSize = size
+			}
+		}
+		time.Sleep(_execTerminalSizePollPeriod)
+	}
+}
+
+// execSocketWriter serializes the writes of stdin, terminal resizes, and the close message to the socket
+type execSocketWriter struct {
+	connection *websocket.Conn
+	mux        sync.Mutex
+}
+
+func (w *execSocketWriter) write(stream byte, data []byte) error {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	return w.connection.WriteMessage(websocket.BinaryMessage, append([]byte{stream}, data...))
+}
+
+func (w *execSocketWriter) close() {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	w.connection.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+}
@@ -18,23 +18,53 @@ package cluster
 
 import (
 	"path"
+	"strings"
 
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
 	"github.com/cortexlabs/cortex/pkg/lib/json"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
 	"github.com/cortexlabs/cortex/pkg/operator/schema"
 )
 
-func GetAPIs(operatorConfig OperatorConfig) ([]schema.APIResponse, error) {
-	httpRes, err := HTTPGet(operatorConfig, "/get")
-	if err != nil {
-		return nil, err
-	}
-
-	var apisRes []schema.APIResponse
-	if err = json.Unmarshal(httpRes, &apisRes); err != nil {
-		return nil, errors.Wrap(err, "/get", string(httpRes))
+const _getAPIsPageSize = 100
+
+// GetAPIs retrieves the apis which match the label selector (if not empty) and the environment's project (if set) one page at a time;
+// if fields are specified, only those top-level fields of each api are retrieved (see schema.APIResponseFields)
+func GetAPIs(operatorConfig OperatorConfig, selector string, fields ...string) ([]schema.APIResponse, error) {
+	apisRes := []schema.APIResponse{}
+
+	offset := 0
+	for {
+		params := map[string]string{
+			"offset": s.Int(offset),
+			"limit":  s.Int(_getAPIsPageSize),
+		}
+		if selector != "" {
+			params["selector"] = selector
+		}
+		if operatorConfig.Project != "" {
+			params["project"] = operatorConfig.Project
+		}
+		if len(fields) > 0 {
+			params["fields"] = strings.Join(fields, ",")
+		}
+
+		httpRes, err := HTTPGet(operatorConfig, "/get", params)
+		if err != nil {
+			return nil, err
+		}
+
+		var page schema.GetAPIsPage
+		if err = json.Unmarshal(httpRes, &page); err != nil {
+			return nil, errors.Wrap(err, "/get", string(httpRes))
+		}
+		apisRes = append(apisRes, page.APIs...)
+
+		if page.NextOffset == nil {
+			return apisRes, nil
+		}
+		offset = *page.NextOffset
 	}
-	return apisRes, nil
 }
 
 func GetAPI(operatorConfig OperatorConfig, apiName string) ([]schema.APIResponse, error) {
@@ -94,6 +124,21 @@ func GetBatchJob(operatorConfig OperatorConfig, apiName string, jobID string) (s
 	return jobRes, nil
 }
 
+func GetBatchJobResults(operatorConfig OperatorConfig, apiName string, jobID string) (schema.BatchJobResultsResponse, error) {
+	endpoint := path.Join("/results", apiName)
+	httpRes, err := HTTPGet(operatorConfig, endpoint, map[string]string{"jobID": jobID})
+	if err != nil {
+		return schema.BatchJobResultsResponse{}, err
+	}
+
+	var resultsRes schema.BatchJobResultsResponse
+	if err = json.Unmarshal(httpRes, &resultsRes); err != nil {
+		return schema.BatchJobResultsResponse{}, errors.Wrap(err, endpoint, string(httpRes))
+	}
+
+	return resultsRes, nil
+}
+
 func GetTaskJob(operatorConfig OperatorConfig, apiName string, jobID string) (schema.TaskJobResponse, error) {
 	endpoint := path.Join("/tasks", apiName)
 	httpRes, err := HTTPGet(operatorConfig, endpoint, map[string]string{"jobID": jobID})
@@ -0,0 +1,69 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"strings"
+
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/json"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+)
+
+func CreateAPIKey(operatorConfig OperatorConfig, name string, apiNames []string) (schema.CreateAPIKeyResponse, error) {
+	params := map[string]string{}
+	if len(apiNames) > 0 {
+		params["apis"] = strings.Join(apiNames, ",")
+	}
+
+	httpRes, err := HTTPPostNoBody(operatorConfig, "/keys/"+name, params)
+	if err != nil {
+		return schema.CreateAPIKeyResponse{}, err
+	}
+
+	var createRes schema.CreateAPIKeyResponse
+	if err = json.Unmarshal(httpRes, &createRes); err != nil {
+		return schema.CreateAPIKeyResponse{}, errors.Wrap(err, "/keys", string(httpRes))
+	}
+	return createRes, nil
+}
+
+func ListAPIKeys(operatorConfig OperatorConfig) ([]schema.APIKey, error) {
+	httpRes, err := HTTPGet(operatorConfig, "/keys")
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []schema.APIKey
+	if err = json.Unmarshal(httpRes, &keys); err != nil {
+		return nil, errors.Wrap(err, "/keys", string(httpRes))
+	}
+	return keys, nil
+}
+
+func RevokeAPIKey(operatorConfig OperatorConfig, name string) (schema.RevokeAPIKeyResponse, error) {
+	httpRes, err := HTTPDelete(operatorConfig, "/keys/"+name)
+	if err != nil {
+		return schema.RevokeAPIKeyResponse{}, err
+	}
+
+	var revokeRes schema.RevokeAPIKeyResponse
+	if err = json.Unmarshal(httpRes, &revokeRes); err != nil {
+		return schema.RevokeAPIKeyResponse{}, errors.Wrap(err, "/keys", string(httpRes))
+	}
+	return revokeRes, nil
+}
@@ -23,8 +23,10 @@ import (
 	"io/ioutil"
 	"mime/multipart"
 	"net/http"
+	"os"
 	"time"
 
+	"github.com/cortexlabs/cortex/pkg/apikeys"
 	"github.com/cortexlabs/cortex/pkg/consts"
 	"github.com/cortexlabs/cortex/pkg/lib/archive"
 	"github.com/cortexlabs/cortex/pkg/lib/aws"
@@ -34,6 +36,8 @@ import (
 	"github.com/cortexlabs/cortex/pkg/operator/schema"
 )
 
+const _apiKeyEnvVar = "CORTEX_API_KEY"
+
 type OperatorClient struct {
 	*http.Client
 }
@@ -43,6 +47,9 @@ type OperatorConfig struct {
 	ClientID         string
 	EnvName          string
 	OperatorEndpoint string
+	Project          string
+	AWSProfile       string // the aws profile which authenticates requests (the default credential chain is used if empty)
+	IDToken          string // set if the environment is logged in with `cortex login`
 }
 
 func HTTPGet(operatorConfig OperatorConfig, endpoint string, qParams ...map[string]string) ([]byte, error) {
@@ -170,6 +177,32 @@ func operatorRequest(operatorConfig OperatorConfig, method string, endpoint stri
 	return req, nil
 }
 
+// setAuthHeader authenticates with the api key in $CORTEX_API_KEY if it's set (the key must be bound to a role, see `cortex auth bind`),
+// with the id token from `cortex login` if the environment is logged in, and with aws credentials (of the environment's aws profile, if set) otherwise
+func setAuthHeader(operatorConfig OperatorConfig, header http.Header) error {
+	if apiKey := os.Getenv(_apiKeyEnvVar); apiKey != "" {
+		header.Set(apikeys.Header, apiKey)
+		return nil
+	}
+
+	if operatorConfig.IDToken != "" {
+		header.Set("Authorization", "Bearer "+operatorConfig.IDToken)
+		return nil
+	}
+
+	awsClient, err := aws.NewForProfile("", operatorConfig.AWSProfile)
+	if err != nil {
+		return err
+	}
+
+	authHeader, err := awsClient.IdentityRequestAsHeader()
+	if err != nil {
+		return err
+	}
+	header.Set(consts.AuthHeader, authHeader)
+	return nil
+}
+
 func makeOperatorRequest(operatorConfig OperatorConfig, request *http.Request) ([]byte, error) {
 	if operatorConfig.Telemetry {
 		values := request.URL.Query()
@@ -178,16 +211,14 @@ func makeOperatorRequest(operatorConfig OperatorConfig, request *http.Request) (
 	}
 
 	request.Header.Set("CortexAPIVersion", consts.CortexVersion)
-	awsClient, err := aws.New()
-	if err != nil {
+	if err := setAuthHeader(operatorConfig, request.Header); err != nil {
 		return nil, err
 	}
 
-	authHeader, err := awsClient.IdentityRequestAsHeader()
-	if err != nil {
-		return nil, err
-	}
-	request.Header.Set(consts.AuthHeader, authHeader)
+	return doOperatorRequest(operatorConfig, request)
+}
+
+func doOperatorRequest(operatorConfig OperatorConfig, request *http.Request) ([]byte, error) {
 
 	timeout := 600 * time.Second
 	if request.URL.Path == "/info" {
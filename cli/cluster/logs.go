@@ -27,10 +27,10 @@ import (
 
 	"github.com/cortexlabs/cortex/cli/lib/routines"
 	"github.com/cortexlabs/cortex/pkg/consts"
-	"github.com/cortexlabs/cortex/pkg/lib/aws"
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
 	"github.com/cortexlabs/cortex/pkg/lib/exit"
 	"github.com/cortexlabs/cortex/pkg/lib/json"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
 	"github.com/cortexlabs/cortex/pkg/operator/schema"
 	"github.com/gorilla/websocket"
 )
@@ -71,14 +71,73 @@ func StreamJobLogs(operatorConfig OperatorConfig, apiName string, jobID string)
 	return streamLogs(operatorConfig, "/streamlogs/"+apiName, map[string]string{"jobID": jobID})
 }
 
+// LogStreamOptions configures streaming the logs of all of a workload's replicas (rather than a random replica)
+type LogStreamOptions struct {
+	Follow  bool
+	Since   string
+	Replica string
+	Filter  string
+}
+
+func (options LogStreamOptions) queryParams() map[string]string {
+	params := map[string]string{
+		"follow": s.Bool(options.Follow),
+	}
+	if options.Since != "" {
+		params["since"] = options.Since
+	}
+	if options.Replica != "" {
+		params["replica"] = options.Replica
+	}
+	if options.Filter != "" {
+		params["filter"] = options.Filter
+	}
+	return params
+}
+
+// StreamReplicaLogs streams the logs of the api's replicas, prefixing each line with the name of the replica
+func StreamReplicaLogs(operatorConfig OperatorConfig, apiName string, options LogStreamOptions) error {
+	return streamLogs(operatorConfig, "/streamlogs/"+apiName, options.queryParams())
+}
+
+// StreamJobReplicaLogs streams the logs of the job's workers, prefixing each line with the name of the worker
+func StreamJobReplicaLogs(operatorConfig OperatorConfig, apiName string, jobID string, options LogStreamOptions) error {
+	params := options.queryParams()
+	params["jobID"] = jobID
+	return streamLogs(operatorConfig, "/streamlogs/"+apiName, params)
+}
+
+// StreamAggregatedJobLogs streams the logs of all of the job's workers (including workers which have completed), prefixing each line with the index of the worker
+func StreamAggregatedJobLogs(operatorConfig OperatorConfig, apiName string, jobID string, filter string) error {
+	params := map[string]string{"jobID": jobID}
+	if filter != "" {
+		params["filter"] = filter
+	}
+	return streamLogs(operatorConfig, "/joblogs/"+apiName, params)
+}
+
 func streamLogs(operatorConfig OperatorConfig, path string, qParams ...map[string]string) error {
 	interrupt := make(chan os.Signal, 1)
 	signal.Notify(interrupt, os.Interrupt)
 
-	req, err := operatorRequest(operatorConfig, "GET", path, nil, qParams...)
+	connection, _, err := dialOperatorSocket(operatorConfig, path, qParams...)
 	if err != nil {
 		return err
 	}
+	defer connection.Close()
+
+	done := make(chan struct{})
+	handleConnection(connection, done)
+	closeConnection(connection, done, interrupt)
+	return nil
+}
+
+// dialOperatorSocket opens a websocket connection to the operator, returning the handshake response's headers
+func dialOperatorSocket(operatorConfig OperatorConfig, path string, qParams ...map[string]string) (*websocket.Conn, http.Header, error) {
+	req, err := operatorRequest(operatorConfig, "GET", path, nil, qParams...)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	values := req.URL.Query()
 	if operatorConfig.Telemetry {
@@ -91,49 +150,38 @@ func streamLogs(operatorConfig OperatorConfig, path string, qParams ...map[strin
 
 	header := http.Header{}
 	header.Set("CortexAPIVersion", consts.CortexVersion)
-	awsClient, err := aws.New()
-	if err != nil {
-		return err
+	if err := setAuthHeader(operatorConfig, header); err != nil {
+		return nil, nil, err
 	}
 
-	authHeader, err := awsClient.IdentityRequestAsHeader()
-	if err != nil {
-		return err
-	}
-	header.Set(consts.AuthHeader, authHeader)
-
 	var dialer = websocket.Dialer{
 		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
 	}
 
 	connection, response, err := dialer.Dial(wsURL, header)
 	if err != nil && response == nil {
-		return ErrorFailedToConnectOperator(err, operatorConfig.EnvName, strings.Replace(operatorConfig.OperatorEndpoint, "http", "ws", 1))
+		return nil, nil, ErrorFailedToConnectOperator(err, operatorConfig.EnvName, strings.Replace(operatorConfig.OperatorEndpoint, "http", "ws", 1))
 	}
 	defer response.Body.Close()
 
 	if err != nil {
 		bodyBytes, err := ioutil.ReadAll(response.Body)
 		if err != nil || bodyBytes == nil || string(bodyBytes) == "" {
-			return ErrorFailedToConnectOperator(err, operatorConfig.EnvName, strings.Replace(operatorConfig.OperatorEndpoint, "http", "ws", 1))
+			return nil, nil, ErrorFailedToConnectOperator(err, operatorConfig.EnvName, strings.Replace(operatorConfig.OperatorEndpoint, "http", "ws", 1))
 		}
 		var output schema.ErrorResponse
 		err = json.Unmarshal(bodyBytes, &output)
 		if err != nil || output.Message == "" {
-			return ErrorOperatorStreamResponseUnknown(string(bodyBytes), response.StatusCode)
+			return nil, nil, ErrorOperatorStreamResponseUnknown(string(bodyBytes), response.StatusCode)
 		}
-		return errors.WithStack(&errors.Error{
+		return nil, nil, errors.WithStack(&errors.Error{
 			Kind:        output.Kind,
 			Message:     output.Message,
 			NoTelemetry: true,
 		})
 	}
-	defer connection.Close()
 
-	done := make(chan struct{})
-	handleConnection(connection, done)
-	closeConnection(connection, done, interrupt)
-	return nil
+	return connection, response.Header, nil
 }
 
 func handleConnection(connection *websocket.Conn, done chan struct{}) {
@@ -142,6 +190,10 @@ func handleConnection(connection *websocket.Conn, done chan struct{}) {
 		for {
 			_, message, err := connection.ReadMessage()
 			if err != nil {
+				// the operator closes the connection once all of the logs have been written (when not following)
+				if websocket.IsCloseError(err, websocket.CloseNormalClosure) {
+					return
+				}
 				exit.Error(ErrorOperatorSocketRead(err))
 			}
 			fmt.Print(string(message))
@@ -0,0 +1,103 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"io"
+	"net"
+
+	"github.com/cortexlabs/cortex/cli/lib/routines"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/gorilla/websocket"
+)
+
+// PortForwardOptions configures which port of which replica (or job worker) connections are forwarded to
+type PortForwardOptions struct {
+	JobID   string
+	Replica string
+	Port    int32
+}
+
+func (options PortForwardOptions) queryParams() map[string]string {
+	params := map[string]string{
+		"port": s.Int32(options.Port),
+	}
+	if options.JobID != "" {
+		params["jobID"] = options.JobID
+	}
+	if options.Replica != "" {
+		params["replica"] = options.Replica
+	}
+	return params
+}
+
+// PortForward tunnels the connection to a port of a running replica of the api through the operator until either side closes the connection;
+// onConnect is called with the name of the replica once the tunnel has been established
+func PortForward(operatorConfig OperatorConfig, apiName string, options PortForwardOptions, conn net.Conn, onConnect func(replica string)) error {
+	defer conn.Close()
+
+	connection, header, err := dialOperatorSocket(operatorConfig, "/portforward/"+apiName, options.queryParams())
+	if err != nil {
+		return err
+	}
+	defer connection.Close()
+
+	if onConnect != nil {
+		onConnect(header.Get(schema.ReplicaHeader))
+	}
+
+	routines.RunWithPanicHandler(func() {
+		forwardConnection(conn, connection)
+	}, false)
+
+	for {
+		_, message, err := connection.ReadMessage()
+		if err != nil {
+			// the operator closes the socket normally once the replica has closed the connection
+			if websocket.IsCloseError(err, websocket.CloseNormalClosure) {
+				return nil
+			}
+			if closeErr, ok := err.(*websocket.CloseError); ok && closeErr.Text != "" {
+				return ErrorPortForwardFailed(closeErr.Text)
+			}
+			return ErrorOperatorSocketRead(err)
+		}
+		if _, err := conn.Write(message); err != nil {
+			return nil
+		}
+	}
+}
+
+// forwardConnection sends the data of the local connection to the operator, followed by an empty message once the local connection has no more data
+func forwardConnection(conn net.Conn, connection *websocket.Conn) {
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := conn.Read(buf)
+		if n > 0 {
+			if writeErr := connection.WriteMessage(websocket.BinaryMessage, buf[:n]); writeErr != nil {
+				return
+			}
+		}
+		if err != nil {
+			if err == io.EOF {
+				connection.WriteMessage(websocket.BinaryMessage, []byte{})
+			}
+			return
+		}
+	}
+}
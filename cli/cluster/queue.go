@@ -0,0 +1,49 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/json"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+)
+
+func GetJobQueue(operatorConfig OperatorConfig, apiName string) ([]schema.QueuedJob, error) {
+	httpRes, err := HTTPGet(operatorConfig, "/queue/"+apiName)
+	if err != nil {
+		return nil, err
+	}
+
+	var queuedJobs []schema.QueuedJob
+	if err = json.Unmarshal(httpRes, &queuedJobs); err != nil {
+		return nil, errors.Wrap(err, "/queue", string(httpRes))
+	}
+	return queuedJobs, nil
+}
+
+func MoveJobToFrontOfQueue(operatorConfig OperatorConfig, apiName string, jobID string) ([]schema.QueuedJob, error) {
+	httpRes, err := HTTPPostNoBody(operatorConfig, "/queue/"+apiName+"/"+jobID+"/front")
+	if err != nil {
+		return nil, err
+	}
+
+	var queuedJobs []schema.QueuedJob
+	if err = json.Unmarshal(httpRes, &queuedJobs); err != nil {
+		return nil, errors.Wrap(err, "/queue", string(httpRes))
+	}
+	return queuedJobs, nil
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/json"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
+)
+
+// ScheduleBatchJob creates a job schedule from a job submission which has its schedule set
+func ScheduleBatchJob(operatorConfig OperatorConfig, apiName string, submission schema.BatchJobSubmission) (spec.JobSchedule, error) {
+	httpRes, err := HTTPPostObjAsJSON(operatorConfig, "/batch/"+apiName, submission)
+	if err != nil {
+		return spec.JobSchedule{}, err
+	}
+
+	var jobSchedule spec.JobSchedule
+	if err = json.Unmarshal(httpRes, &jobSchedule); err != nil {
+		return spec.JobSchedule{}, errors.Wrap(err, "/batch", string(httpRes))
+	}
+	return jobSchedule, nil
+}
+
+func ListJobSchedules(operatorConfig OperatorConfig, apiName string) ([]spec.JobSchedule, error) {
+	params := map[string]string{}
+	if apiName != "" {
+		params["apiName"] = apiName
+	}
+
+	httpRes, err := HTTPGet(operatorConfig, "/schedules", params)
+	if err != nil {
+		return nil, err
+	}
+
+	var jobSchedules []spec.JobSchedule
+	if err = json.Unmarshal(httpRes, &jobSchedules); err != nil {
+		return nil, errors.Wrap(err, "/schedules", string(httpRes))
+	}
+	return jobSchedules, nil
+}
+
+func PauseJobSchedule(operatorConfig OperatorConfig, apiName string, scheduleID string) (spec.JobSchedule, error) {
+	return postJobScheduleAction(operatorConfig, apiName, scheduleID, "pause")
+}
+
+func ResumeJobSchedule(operatorConfig OperatorConfig, apiName string, scheduleID string) (spec.JobSchedule, error) {
+	return postJobScheduleAction(operatorConfig, apiName, scheduleID, "resume")
+}
+
+func DeleteJobSchedule(operatorConfig OperatorConfig, apiName string, scheduleID string) (schema.DeleteJobScheduleResponse, error) {
+	httpRes, err := HTTPDelete(operatorConfig, "/schedules/"+apiName+"/"+scheduleID)
+	if err != nil {
+		return schema.DeleteJobScheduleResponse{}, err
+	}
+
+	var deleteRes schema.DeleteJobScheduleResponse
+	if err = json.Unmarshal(httpRes, &deleteRes); err != nil {
+		return schema.DeleteJobScheduleResponse{}, errors.Wrap(err, "/schedules", string(httpRes))
+	}
+	return deleteRes, nil
+}
+
+func postJobScheduleAction(operatorConfig OperatorConfig, apiName string, scheduleID string, action string) (spec.JobSchedule, error) {
+	httpRes, err := HTTPPostNoBody(operatorConfig, "/schedules/"+apiName+"/"+scheduleID+"/"+action)
+	if err != nil {
+		return spec.JobSchedule{}, err
+	}
+
+	var jobSchedule spec.JobSchedule
+	if err = json.Unmarshal(httpRes, &jobSchedule); err != nil {
+		return spec.JobSchedule{}, errors.Wrap(err, "/schedules", string(httpRes))
+	}
+	return jobSchedule, nil
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/json"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+)
+
+func SetSecret(operatorConfig OperatorConfig, name string, value string) (schema.SetSecretResponse, error) {
+	httpRes, err := HTTPPostObjAsJSON(operatorConfig, "/secrets/"+name, schema.SetSecretRequest{Value: value})
+	if err != nil {
+		return schema.SetSecretResponse{}, err
+	}
+
+	var setRes schema.SetSecretResponse
+	if err = json.Unmarshal(httpRes, &setRes); err != nil {
+		return schema.SetSecretResponse{}, errors.Wrap(err, "/secrets", string(httpRes))
+	}
+	return setRes, nil
+}
+
+func GetSecret(operatorConfig OperatorConfig, name string) (schema.GetSecretResponse, error) {
+	httpRes, err := HTTPGet(operatorConfig, "/secrets/"+name)
+	if err != nil {
+		return schema.GetSecretResponse{}, err
+	}
+
+	var getRes schema.GetSecretResponse
+	if err = json.Unmarshal(httpRes, &getRes); err != nil {
+		return schema.GetSecretResponse{}, errors.Wrap(err, "/secrets", string(httpRes))
+	}
+	return getRes, nil
+}
+
+func ListSecrets(operatorConfig OperatorConfig) ([]schema.Secret, error) {
+	httpRes, err := HTTPGet(operatorConfig, "/secrets")
+	if err != nil {
+		return nil, err
+	}
+
+	var secrets []schema.Secret
+	if err = json.Unmarshal(httpRes, &secrets); err != nil {
+		return nil, errors.Wrap(err, "/secrets", string(httpRes))
+	}
+	return secrets, nil
+}
+
+func DeleteSecret(operatorConfig OperatorConfig, name string) (schema.DeleteSecretResponse, error) {
+	httpRes, err := HTTPDelete(operatorConfig, "/secrets/"+name)
+	if err != nil {
+		return schema.DeleteSecretResponse{}, err
+	}
+
+	var deleteRes schema.DeleteSecretResponse
+	if err = json.Unmarshal(httpRes, &deleteRes); err != nil {
+		return schema.DeleteSecretResponse{}, errors.Wrap(err, "/secrets", string(httpRes))
+	}
+	return deleteRes, nil
+}
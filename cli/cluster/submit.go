@@ -0,0 +1,69 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/json"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
+)
+
+func SubmitBatchJob(operatorConfig OperatorConfig, apiName string, submission schema.BatchJobSubmission) (spec.BatchJob, error) {
+	httpRes, err := HTTPPostObjAsJSON(operatorConfig, "/batch/"+apiName, submission)
+	if err != nil {
+		return spec.BatchJob{}, err
+	}
+
+	var batchJob spec.BatchJob
+	err = json.Unmarshal(httpRes, &batchJob)
+	if err != nil {
+		return spec.BatchJob{}, errors.Wrap(err, "/batch", string(httpRes))
+	}
+
+	return batchJob, nil
+}
+
+// DryRunBatchJob validates the job submission, and returns the list of files which would be processed (if applicable)
+func DryRunBatchJob(operatorConfig OperatorConfig, apiName string, submission schema.BatchJobSubmission) (string, error) {
+	params := map[string]string{
+		"dryRun": "true",
+	}
+
+	httpRes, err := HTTPPostObjAsJSON(operatorConfig, "/batch/"+apiName, submission, params)
+	if err != nil {
+		return "", err
+	}
+
+	return string(httpRes), nil
+}
+
+// RerunFailedBatches submits a new job which processes the batches that failed in the specified job
+func RerunFailedBatches(operatorConfig OperatorConfig, apiName string, jobID string) (spec.BatchJob, error) {
+	httpRes, err := HTTPPostNoBody(operatorConfig, "/rerun/"+apiName, map[string]string{"jobID": jobID})
+	if err != nil {
+		return spec.BatchJob{}, err
+	}
+
+	var batchJob spec.BatchJob
+	err = json.Unmarshal(httpRes, &batchJob)
+	if err != nil {
+		return spec.BatchJob{}, errors.Wrap(err, "/rerun", string(httpRes))
+	}
+
+	return batchJob, nil
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cortexlabs/cortex/cli/cluster"
+	"github.com/cortexlabs/cortex/cli/types/flags"
+	"github.com/cortexlabs/cortex/pkg/lib/exit"
+	libjson "github.com/cortexlabs/cortex/pkg/lib/json"
+	"github.com/cortexlabs/cortex/pkg/lib/print"
+	"github.com/cortexlabs/cortex/pkg/lib/telemetry"
+	"github.com/spf13/cobra"
+)
+
+var (
+	_flagAsyncRedriveEnv string
+)
+
+func asyncInit() {
+	_asyncRedriveCmd.Flags().SortFlags = false
+	_asyncRedriveCmd.Flags().StringVarP(&_flagAsyncRedriveEnv, "env", "e", "", "environment to use")
+	_asyncRedriveCmd.Flags().VarP(&_flagOutput, "output", "o", fmt.Sprintf("output format: one of %s", strings.Join(flags.OutputTypeStringsExcluding(flags.YAMLOutputType), "|")))
+	_asyncCmd.AddCommand(_asyncRedriveCmd)
+}
+
+var _asyncCmd = &cobra.Command{
+	Use:   "async",
+	Short: "manage async apis (contains subcommands)",
+}
+
+var _asyncRedriveCmd = &cobra.Command{
+	Use:   "redrive API_NAME",
+	Short: "resubmit the workloads in an async api's dead-letter queue",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		envName, err := getEnvFromFlag(_flagAsyncRedriveEnv)
+		if err != nil {
+			telemetry.Event("cli.async.redrive")
+			exit.Error(err)
+		}
+
+		env, err := ReadOrConfigureEnv(envName)
+		if err != nil {
+			telemetry.Event("cli.async.redrive")
+			exit.Error(err)
+		}
+		telemetry.Event("cli.async.redrive", map[string]interface{}{"env_name": env.Name})
+
+		err = printEnvIfNotSpecified(env.Name, cmd)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		redriveResponse, err := cluster.Redrive(MustGetOperatorConfig(env.Name), args[0])
+		if err != nil {
+			exit.Error(err)
+		}
+
+		if _flagOutput == flags.JSONOutputType {
+			bytes, err := libjson.Marshal(redriveResponse)
+			if err != nil {
+				exit.Error(err)
+			}
+			fmt.Print(string(bytes))
+			return
+		}
+
+		print.BoldFirstLine(redriveResponse.Message)
+	},
+}
@@ -0,0 +1,255 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cortexlabs/cortex/cli/cluster"
+	"github.com/cortexlabs/cortex/cli/types/cliconfig"
+	"github.com/cortexlabs/cortex/cli/types/flags"
+	"github.com/cortexlabs/cortex/pkg/lib/exit"
+	libjson "github.com/cortexlabs/cortex/pkg/lib/json"
+	"github.com/cortexlabs/cortex/pkg/lib/print"
+	"github.com/cortexlabs/cortex/pkg/lib/prompt"
+	"github.com/cortexlabs/cortex/pkg/lib/table"
+	"github.com/cortexlabs/cortex/pkg/lib/telemetry"
+	libtime "github.com/cortexlabs/cortex/pkg/lib/time"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/rbac"
+	"github.com/spf13/cobra"
+)
+
+var (
+	_flagAuthEnv         string
+	_flagAuthBindRole    string
+	_flagAuthBindSubject string
+	_flagAuthUnbindForce bool
+)
+
+func authInit() {
+	_authWhoAmICmd.Flags().SortFlags = false
+	_authWhoAmICmd.Flags().StringVarP(&_flagAuthEnv, "env", "e", "", "environment to use")
+	_authWhoAmICmd.Flags().VarP(&_flagOutput, "output", "o", fmt.Sprintf("output format: one of %s", strings.Join(flags.OutputTypeStringsExcluding(flags.YAMLOutputType), "|")))
+	_authCmd.AddCommand(_authWhoAmICmd)
+
+	_authBindCmd.Flags().SortFlags = false
+	_authBindCmd.Flags().StringVarP(&_flagAuthEnv, "env", "e", "", "environment to use")
+	_authBindCmd.Flags().StringVarP(&_flagAuthBindRole, "role", "r", "", fmt.Sprintf("role to grant: one of %s", strings.Join(rbac.RoleStrings(), "|")))
+	_authBindCmd.MarkFlagRequired("role")
+	_authBindCmd.Flags().StringVarP(&_flagAuthBindSubject, "subject", "s", "", "subject to grant the role to: iam:<aws account id, iam user arn, or iam role arn>, apikey:<api key name>, or oidc:<username>")
+	_authBindCmd.MarkFlagRequired("subject")
+	_authBindCmd.Flags().VarP(&_flagOutput, "output", "o", fmt.Sprintf("output format: one of %s", strings.Join(flags.OutputTypeStringsExcluding(flags.YAMLOutputType), "|")))
+	_authCmd.AddCommand(_authBindCmd)
+
+	_authListCmd.Flags().SortFlags = false
+	_authListCmd.Flags().StringVarP(&_flagAuthEnv, "env", "e", "", "environment to use")
+	_authListCmd.Flags().VarP(&_flagOutput, "output", "o", fmt.Sprintf("output format: one of %s", strings.Join(flags.OutputTypeStringsExcluding(flags.YAMLOutputType), "|")))
+	_authCmd.AddCommand(_authListCmd)
+
+	_authUnbindCmd.Flags().SortFlags = false
+	_authUnbindCmd.Flags().StringVarP(&_flagAuthEnv, "env", "e", "", "environment to use")
+	_authUnbindCmd.Flags().BoolVarP(&_flagAuthUnbindForce, "force", "f", false, "delete the role binding without confirmation")
+	_authUnbindCmd.Flags().VarP(&_flagOutput, "output", "o", fmt.Sprintf("output format: one of %s", strings.Join(flags.OutputTypeStringsExcluding(flags.YAMLOutputType), "|")))
+	_authCmd.AddCommand(_authUnbindCmd)
+}
+
+var _authCmd = &cobra.Command{
+	Use:   "auth",
+	Short: "manage role-based access to the operator (contains subcommands)",
+}
+
+var _authWhoAmICmd = &cobra.Command{
+	Use:   "whoami",
+	Short: "show the caller's identity and role",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		env := authEnvOrExit("cli.auth.whoami")
+
+		err := printEnvIfNotSpecified(env.Name, cmd)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		whoAmIResponse, err := cluster.WhoAmI(MustGetOperatorConfig(env.Name))
+		if err != nil {
+			exit.Error(err)
+		}
+
+		if _flagOutput == flags.JSONOutputType {
+			bytes, err := libjson.Marshal(whoAmIResponse)
+			if err != nil {
+				exit.Error(err)
+			}
+			fmt.Print(string(bytes))
+			return
+		}
+
+		role := "none"
+		if whoAmIResponse.Role != "" {
+			role = string(whoAmIResponse.Role)
+		}
+		fmt.Printf("caller: %s\n", whoAmIResponse.Caller)
+		fmt.Printf("role: %s\n", role)
+		if !whoAmIResponse.RBACEnabled {
+			fmt.Println("\nrbac is disabled because no role bindings exist, so all callers with aws credentials in the cluster's account (or which are logged in with `cortex login`) have the admin role (create a role binding with `cortex auth bind` to enable it)")
+		}
+	},
+}
+
+var _authBindCmd = &cobra.Command{
+	Use:   "bind BINDING_NAME",
+	Short: "grant a role to a subject",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		env := authEnvOrExit("cli.auth.bind")
+
+		err := printEnvIfNotSpecified(env.Name, cmd)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		if _, err := rbac.ParseRole(_flagAuthBindRole); err != nil {
+			exit.Error(err)
+		}
+		if err := rbac.ValidateSubject(_flagAuthBindSubject); err != nil {
+			exit.Error(err)
+		}
+
+		binding, err := cluster.CreateRoleBinding(MustGetOperatorConfig(env.Name), args[0], _flagAuthBindRole, _flagAuthBindSubject)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		if _flagOutput == flags.JSONOutputType {
+			bytes, err := libjson.Marshal(binding)
+			if err != nil {
+				exit.Error(err)
+			}
+			fmt.Print(string(bytes))
+			return
+		}
+
+		print.BoldFirstLine(fmt.Sprintf("created role binding %s, which grants the %s role to %s", binding.Name, binding.Role, binding.Subject))
+	},
+}
+
+var _authListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "list the role bindings",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		env := authEnvOrExit("cli.auth.list")
+
+		err := printEnvIfNotSpecified(env.Name, cmd)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		bindings, err := cluster.ListRoleBindings(MustGetOperatorConfig(env.Name))
+		if err != nil {
+			exit.Error(err)
+		}
+
+		if _flagOutput == flags.JSONOutputType {
+			bytes, err := libjson.Marshal(bindings)
+			if err != nil {
+				exit.Error(err)
+			}
+			fmt.Print(string(bytes))
+			return
+		}
+
+		if len(bindings) == 0 {
+			fmt.Println("no role bindings have been created, so rbac is disabled (create one with `cortex auth bind BINDING_NAME --role ROLE --subject SUBJECT`)")
+			return
+		}
+
+		t := roleBindingsTable(bindings)
+		fmt.Print(t.MustFormat())
+	},
+}
+
+var _authUnbindCmd = &cobra.Command{
+	Use:   "unbind BINDING_NAME",
+	Short: "delete a role binding",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		env := authEnvOrExit("cli.auth.unbind")
+
+		err := printEnvIfNotSpecified(env.Name, cmd)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		if !_flagAuthUnbindForce {
+			prompt.YesOrExit(fmt.Sprintf("are you sure you want to delete role binding %s?", args[0]), "", "")
+		}
+
+		deleteResponse, err := cluster.DeleteRoleBinding(MustGetOperatorConfig(env.Name), args[0])
+		if err != nil {
+			exit.Error(err)
+		}
+
+		if _flagOutput == flags.JSONOutputType {
+			bytes, err := libjson.Marshal(deleteResponse)
+			if err != nil {
+				exit.Error(err)
+			}
+			fmt.Print(string(bytes))
+			return
+		}
+
+		print.BoldFirstLine(deleteResponse.Message)
+	},
+}
+
+func authEnvOrExit(eventName string) cliconfig.Environment {
+	envName, err := getEnvFromFlag(_flagAuthEnv)
+	if err != nil {
+		telemetry.Event(eventName)
+		exit.Error(err)
+	}
+
+	env, err := ReadOrConfigureEnv(envName)
+	if err != nil {
+		telemetry.Event(eventName)
+		exit.Error(err)
+	}
+	telemetry.Event(eventName, map[string]interface{}{"env_name": env.Name})
+
+	return env
+}
+
+func roleBindingsTable(bindings []schema.RoleBinding) table.Table {
+	rows := make([][]interface{}, 0, len(bindings))
+	for _, binding := range bindings {
+		createdAt := binding.CreatedAt
+		rows = append(rows, []interface{}{binding.Name, binding.Role, binding.Subject, libtime.SinceStr(&createdAt)})
+	}
+
+	return table.Table{
+		Headers: []table.Header{
+			{Title: "name"},
+			{Title: "role"},
+			{Title: "subject"},
+			{Title: "created"},
+		},
+		Rows: rows,
+	}
+}
@@ -48,6 +48,7 @@ import (
 	"github.com/cortexlabs/cortex/pkg/lib/k8s"
 	libmath "github.com/cortexlabs/cortex/pkg/lib/math"
 	"github.com/cortexlabs/cortex/pkg/lib/pointer"
+	"github.com/cortexlabs/cortex/pkg/lib/print"
 	"github.com/cortexlabs/cortex/pkg/lib/prompt"
 	s "github.com/cortexlabs/cortex/pkg/lib/strings"
 	"github.com/cortexlabs/cortex/pkg/lib/table"
@@ -65,6 +66,8 @@ import (
 
 var (
 	_flagClusterUpEnv                string
+	_flagClusterUpInteractive        bool
+	_flagClusterUpOffline            bool
 	_flagClusterInfoEnv              string
 	_flagClusterConfig               string
 	_flagClusterName                 string
@@ -73,6 +76,11 @@ var (
 	_flagClusterInfoPrintConfig      bool
 	_flagClusterDisallowPrompt       bool
 	_flagClusterDownKeepAWSResources bool
+	_flagClusterCostDays             int
+	_flagClusterScaleNodeGroup       string
+	_flagClusterScaleMinInstances    int64
+	_flagClusterScaleMaxInstances    int64
+	_flagClusterExportFormat         = flags.ArchiveExportFormat
 )
 
 var _eksctlPrefixRegex = regexp.MustCompile(`^.*[0-9]{4}-[0-9]{2}-[0-9]{2} [0-9]{2}:[0-9]{2}:[0-9]{2} \[.+] {2}`)
@@ -80,9 +88,18 @@ var _eksctlPrefixRegex = regexp.MustCompile(`^.*[0-9]{4}-[0-9]{2}-[0-9]{2} [0-9]
 func clusterInit() {
 	_clusterUpCmd.Flags().SortFlags = false
 	_clusterUpCmd.Flags().StringVarP(&_flagClusterUpEnv, "configure-env", "e", "", "name of environment to configure (default: the name of your cluster)")
+	_clusterUpCmd.Flags().BoolVarP(&_flagClusterUpInteractive, "interactive", "i", false, "create the cluster configuration file by answering prompts (CLUSTER_CONFIG_FILE defaults to "+_defaultInteractiveClusterConfigPath+")")
+	_clusterUpCmd.Flags().BoolVar(&_flagClusterUpOffline, "offline", false, "create a cluster which doesn't make external network calls (images are pulled from the registry_mirror in your cluster configuration, and telemetry is disabled)")
 	_clusterUpCmd.Flags().BoolVarP(&_flagClusterDisallowPrompt, "yes", "y", false, "skip prompts")
 	_clusterCmd.AddCommand(_clusterUpCmd)
 
+	_clusterValidateCmd.Flags().SortFlags = false
+	_clusterValidateCmd.Flags().VarP(&_flagOutput, "output", "o", fmt.Sprintf("output format: one of %s", strings.Join(flags.OutputTypeStringsExcluding(flags.YAMLOutputType), "|")))
+	_clusterCmd.AddCommand(_clusterValidateCmd)
+
+	_clusterRenderCmd.Flags().SortFlags = false
+	_clusterCmd.AddCommand(_clusterRenderCmd)
+
 	_clusterInfoCmd.Flags().SortFlags = false
 	addClusterConfigFlag(_clusterInfoCmd)
 	addClusterNameFlag(_clusterInfoCmd)
@@ -98,6 +115,53 @@ func clusterInit() {
 	_clusterConfigureCmd.Flags().BoolVarP(&_flagClusterDisallowPrompt, "yes", "y", false, "skip prompts")
 	_clusterCmd.AddCommand(_clusterConfigureCmd)
 
+	_clusterScaleCmd.Flags().SortFlags = false
+	addClusterConfigFlag(_clusterScaleCmd)
+	addClusterNameFlag(_clusterScaleCmd)
+	addClusterRegionFlag(_clusterScaleCmd)
+	_clusterScaleCmd.Flags().StringVar(&_flagClusterScaleNodeGroup, "node-group", "", "name of the nodegroup to scale")
+	_clusterScaleCmd.MarkFlagRequired("node-group")
+	_clusterScaleCmd.Flags().Int64Var(&_flagClusterScaleMinInstances, "min", 0, "minimum number of instances")
+	_clusterScaleCmd.Flags().Int64Var(&_flagClusterScaleMaxInstances, "max", 0, "maximum number of instances")
+	_clusterScaleCmd.Flags().BoolVarP(&_flagClusterDisallowPrompt, "yes", "y", false, "skip prompts")
+	_clusterCmd.AddCommand(_clusterScaleCmd)
+
+	_clusterNodeGroupAddCmd.Flags().SortFlags = false
+	addClusterConfigFlag(_clusterNodeGroupAddCmd)
+	addClusterNameFlag(_clusterNodeGroupAddCmd)
+	addClusterRegionFlag(_clusterNodeGroupAddCmd)
+	_clusterNodeGroupAddCmd.Flags().BoolVarP(&_flagClusterDisallowPrompt, "yes", "y", false, "skip prompts")
+	_clusterNodeGroupCmd.AddCommand(_clusterNodeGroupAddCmd)
+
+	_clusterNodeGroupDeleteCmd.Flags().SortFlags = false
+	addClusterConfigFlag(_clusterNodeGroupDeleteCmd)
+	addClusterNameFlag(_clusterNodeGroupDeleteCmd)
+	addClusterRegionFlag(_clusterNodeGroupDeleteCmd)
+	_clusterNodeGroupDeleteCmd.Flags().BoolVarP(&_flagClusterDisallowPrompt, "yes", "y", false, "skip prompts")
+	_clusterNodeGroupCmd.AddCommand(_clusterNodeGroupDeleteCmd)
+
+	_clusterCmd.AddCommand(_clusterNodeGroupCmd)
+
+	_clusterPauseCmd.Flags().SortFlags = false
+	addClusterConfigFlag(_clusterPauseCmd)
+	addClusterNameFlag(_clusterPauseCmd)
+	addClusterRegionFlag(_clusterPauseCmd)
+	_clusterPauseCmd.Flags().BoolVarP(&_flagClusterDisallowPrompt, "yes", "y", false, "skip prompts")
+	_clusterCmd.AddCommand(_clusterPauseCmd)
+
+	_clusterResumeCmd.Flags().SortFlags = false
+	addClusterConfigFlag(_clusterResumeCmd)
+	addClusterNameFlag(_clusterResumeCmd)
+	addClusterRegionFlag(_clusterResumeCmd)
+	_clusterCmd.AddCommand(_clusterResumeCmd)
+
+	_clusterUpgradeCmd.Flags().SortFlags = false
+	addClusterConfigFlag(_clusterUpgradeCmd)
+	addClusterNameFlag(_clusterUpgradeCmd)
+	addClusterRegionFlag(_clusterUpgradeCmd)
+	_clusterUpgradeCmd.Flags().BoolVarP(&_flagClusterDisallowPrompt, "yes", "y", false, "skip prompts")
+	_clusterCmd.AddCommand(_clusterUpgradeCmd)
+
 	_clusterDownCmd.Flags().SortFlags = false
 	addClusterConfigFlag(_clusterDownCmd)
 	addClusterNameFlag(_clusterDownCmd)
@@ -110,14 +174,37 @@ func clusterInit() {
 	addClusterConfigFlag(_clusterExportCmd)
 	addClusterNameFlag(_clusterExportCmd)
 	addClusterRegionFlag(_clusterExportCmd)
+	_clusterExportCmd.Flags().Var(&_flagClusterExportFormat, "format", fmt.Sprintf("export format: one of %s", strings.Join(flags.ExportFormatStrings(), "|")))
 	_clusterCmd.AddCommand(_clusterExportCmd)
 
+	_clusterImportCmd.Flags().SortFlags = false
+	addClusterConfigFlag(_clusterImportCmd)
+	addClusterNameFlag(_clusterImportCmd)
+	addClusterRegionFlag(_clusterImportCmd)
+	_clusterImportCmd.Flags().BoolVarP(&_flagClusterDisallowPrompt, "yes", "y", false, "skip prompts")
+	_clusterCmd.AddCommand(_clusterImportCmd)
+
 	_clusterHealthCmd.Flags().SortFlags = false
 	addClusterConfigFlag(_clusterHealthCmd)
 	addClusterNameFlag(_clusterHealthCmd)
 	addClusterRegionFlag(_clusterHealthCmd)
 	_clusterHealthCmd.Flags().VarP(&_flagOutput, "output", "o", fmt.Sprintf("output format: one of %s", strings.Join(flags.OutputTypeStringsExcluding(flags.YAMLOutputType), "|")))
 	_clusterCmd.AddCommand(_clusterHealthCmd)
+
+	_clusterCostCmd.Flags().SortFlags = false
+	addClusterConfigFlag(_clusterCostCmd)
+	addClusterNameFlag(_clusterCostCmd)
+	addClusterRegionFlag(_clusterCostCmd)
+	_clusterCostCmd.Flags().IntVarP(&_flagClusterCostDays, "days", "d", 30, "number of days of spend to include (including today)")
+	_clusterCostCmd.Flags().VarP(&_flagOutput, "output", "o", fmt.Sprintf("output format: one of %s", strings.Join(flags.OutputTypeStringsExcluding(flags.YAMLOutputType), "|")))
+	_clusterCmd.AddCommand(_clusterCostCmd)
+
+	_clusterAuditCmd.Flags().SortFlags = false
+	addClusterConfigFlag(_clusterAuditCmd)
+	addClusterNameFlag(_clusterAuditCmd)
+	addClusterRegionFlag(_clusterAuditCmd)
+	_clusterAuditCmd.Flags().VarP(&_flagOutput, "output", "o", fmt.Sprintf("output format: one of %s", strings.Join(flags.OutputTypeStringsExcluding(flags.YAMLOutputType), "|")))
+	_clusterCmd.AddCommand(_clusterAuditCmd)
 }
 
 func addClusterConfigFlag(cmd *cobra.Command) {
@@ -144,16 +231,34 @@ var _clusterCmd = &cobra.Command{
 var _clusterUpCmd = &cobra.Command{
 	Use:   "up CLUSTER_CONFIG_FILE",
 	Short: "spin up a cluster on aws",
-	Args:  cobra.ExactArgs(1),
+	Args: func(cmd *cobra.Command, args []string) error {
+		if _flagClusterUpInteractive {
+			return cobra.MaximumNArgs(1)(cmd, args)
+		}
+		return cobra.ExactArgs(1)(cmd, args)
+	},
 	Run: func(cmd *cobra.Command, args []string) {
+		if _flagClusterUpOffline {
+			os.Setenv("CORTEX_TELEMETRY_DISABLE", "true")
+		}
+
 		telemetry.EventNotify("cli.cluster.up")
 
-		clusterConfigFile := args[0]
+		clusterConfigFile := _defaultInteractiveClusterConfigPath
+		if len(args) > 0 {
+			clusterConfigFile = args[0]
+		}
 
 		if _, err := docker.GetDockerClient(); err != nil {
 			exit.Error(err)
 		}
 
+		if _flagClusterUpInteractive {
+			if err := promptAndWriteClusterConfig(clusterConfigFile); err != nil {
+				exit.Error(err)
+			}
+		}
+
 		accessConfig, err := getNewClusterAccessConfig(clusterConfigFile)
 		if err != nil {
 			exit.Error(err)
@@ -193,13 +298,15 @@ var _clusterUpCmd = &cobra.Command{
 
 		promptIfNotAdmin(awsClient, _flagClusterDisallowPrompt)
 
-		clusterConfig, err := getInstallClusterConfig(awsClient, clusterConfigFile)
+		clusterConfig, err := getInstallClusterConfig(awsClient, clusterConfigFile, _flagClusterUpOffline)
 		if err != nil {
 			exit.Error(err)
 		}
 
 		confirmInstallClusterConfig(clusterConfig, awsClient, _flagClusterDisallowPrompt)
 
+		confirmClusterUpPermissions(clusterConfig, awsClient, _flagClusterDisallowPrompt)
+
 		err = createS3BucketIfNotFound(awsClient, clusterConfig.Bucket, clusterConfig.Tags)
 		if err != nil {
 			exit.Error(err)
@@ -221,11 +328,12 @@ var _clusterUpCmd = &cobra.Command{
 		}
 
 		err = clusterconfig.CreateDefaultPolicy(awsClient, clusterconfig.CortexPolicyTemplateArgs{
-			ClusterName: clusterConfig.ClusterName,
-			LogGroup:    clusterConfig.ClusterName,
-			Bucket:      clusterConfig.Bucket,
-			Region:      clusterConfig.Region,
-			AccountID:   accountID,
+			ClusterName:               clusterConfig.ClusterName,
+			LogGroup:                  clusterConfig.ClusterName,
+			Bucket:                    clusterConfig.Bucket,
+			Region:                    clusterConfig.Region,
+			AccountID:                 accountID,
+			CustomDomainHostedZoneIDs: clusterConfig.CustomDomainHostedZoneIDs,
 		})
 		if err != nil {
 			exit.Error(err)
@@ -390,10 +498,103 @@ var _clusterConfigureCmd = &cobra.Command{
 
 		confirmConfigureClusterConfig(configureChanges, oldClusterConfig, *newClusterConfig, _flagClusterDisallowPrompt)
 
-		out, exitCode, err := runManagerWithClusterConfig("/root/install.sh --configure", newClusterConfig, awsClient, nil, nil, []string{
-			"CORTEX_NODEGROUP_NAMES_TO_UPDATE=" + strings.Join(configureChanges.NodeGroupsToUpdate, " "),        // NodeGroupsToUpdate contain the cluster config node-group names
-			"CORTEX_NODEGROUP_NAMES_TO_ADD=" + strings.Join(configureChanges.NodeGroupsToAdd, " "),              // NodeGroupsToAdd contain the cluster config node-group names
-			"CORTEX_EKS_NODEGROUP_NAMES_TO_REMOVE=" + strings.Join(configureChanges.EKSNodeGroupsToRemove, " "), // EKSNodeGroupsToRemove contain the EKS node-group names
+		out, exitCode, err := runManagerWithNodeGroupChanges("/root/install.sh --configure", newClusterConfig, awsClient, configureChanges)
+		if err != nil {
+			exit.Error(err)
+		}
+		if exitCode == nil || *exitCode != 0 {
+			exitClusterConfigureFailure(out, oldClusterConfig.Region)
+		}
+	},
+}
+
+var _clusterScaleCmd = &cobra.Command{
+	Use:   "scale",
+	Short: "update the min/max instances of a nodegroup",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		telemetry.Event("cli.cluster.scale")
+
+		var minInstances, maxInstances *int64
+		if cmd.Flags().Changed("min") {
+			if _flagClusterScaleMinInstances < 0 {
+				exit.Error(ErrorMinInstancesLowerThan(0))
+			}
+			minInstances = pointer.Int64(_flagClusterScaleMinInstances)
+		}
+		if cmd.Flags().Changed("max") {
+			if _flagClusterScaleMaxInstances < 0 {
+				exit.Error(ErrorMaxInstancesLowerThan(0))
+			}
+			maxInstances = pointer.Int64(_flagClusterScaleMaxInstances)
+		}
+		if minInstances == nil && maxInstances == nil {
+			exit.Error(ErrorSpecifyAtLeastOneFlag("--min", "--max"))
+		}
+
+		if _, err := docker.GetDockerClient(); err != nil {
+			exit.Error(err)
+		}
+
+		accessConfig, err := getClusterAccessConfigWithCache(true)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		awsClient, err := newAWSClient(accessConfig.Region, true)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		restConfig, err := getClusterRESTConfig(awsClient, accessConfig.ClusterName)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		scheme := runtime.NewScheme()
+		if err := clientgoscheme.AddToScheme(scheme); err != nil {
+			exit.Error(err)
+		}
+
+		k8sClient, err := k8s.New(consts.DefaultNamespace, false, restConfig, scheme)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		stacks, err := clusterstate.GetClusterStacks(awsClient, accessConfig)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		state := clusterstate.GetClusterState(stacks)
+		if err := clusterstate.AssertClusterState(stacks, state, clusterstate.StateClusterExists); err != nil {
+			exit.Error(err)
+		}
+
+		oldClusterConfig := refreshCachedClusterConfig(awsClient, accessConfig, true)
+
+		promptIfNotAdmin(awsClient, _flagClusterDisallowPrompt)
+
+		newClusterConfig, err := oldClusterConfig.DeepCopy()
+		if err != nil {
+			exit.Error(err)
+		}
+
+		if err := newClusterConfig.ValidateOnScale(awsClient, k8sClient, _flagClusterScaleNodeGroup, minInstances, maxInstances); err != nil {
+			exit.Error(err)
+		}
+
+		oldNodeGroup := oldClusterConfig.GetNodeGroupByName(_flagClusterScaleNodeGroup)
+		newNodeGroup := newClusterConfig.GetNodeGroupByName(_flagClusterScaleNodeGroup)
+		if !newNodeGroup.HasChanged(oldNodeGroup) {
+			fmt.Printf("nodegroup %s is already set to %s %d and %s %d\n", newNodeGroup.Name, clusterconfig.MinInstancesKey, newNodeGroup.MinInstances, clusterconfig.MaxInstancesKey, newNodeGroup.MaxInstances)
+			exit.Ok()
+		}
+
+		confirmScaleNodeGroup(oldNodeGroup, newNodeGroup, newClusterConfig, _flagClusterDisallowPrompt)
+
+		out, exitCode, err := runManagerWithClusterConfig("/root/install.sh --scale", &newClusterConfig, awsClient, nil, nil, []string{
+			"CORTEX_NODEGROUP_NAMES_TO_UPDATE=" + newNodeGroup.Name,
 		})
 		if err != nil {
 			exit.Error(err)
@@ -403,17 +604,343 @@ var _clusterConfigureCmd = &cobra.Command{
 
 			helpStr := "\ndebugging tips (may or may not apply to this error):"
 			helpStr += fmt.Sprintf(
-				"\n* if your cluster was unable to provision/remove/scale some nodegroups, additional error information may be found in the description of your cloudformation stack (https://console.aws.amazon.com/cloudformation/home?region=%s#/stacks)"+
-					" or in the activity history of your cluster's autoscaling groups (select each autoscaling group and click the  \"Activity\" or \"Activity History\" tab) (https://console.aws.amazon.com/ec2/autoscaling/home?region=%s#AutoScalingGroups)",
-				oldClusterConfig.Region,
+				"\n* if your nodegroup was unable to scale, additional error information may be found in the activity history of your cluster's autoscaling groups (select each autoscaling group and click the  \"Activity\" or \"Activity History\" tab) (https://console.aws.amazon.com/ec2/autoscaling/home?region=%s#AutoScalingGroups)",
 				oldClusterConfig.Region,
 			)
 			fmt.Println(helpStr)
-			exit.Error(ErrorClusterConfigure(out + helpStr))
+			exit.Error(ErrorClusterScale(out + helpStr))
+		}
+	},
+}
+
+var _clusterNodeGroupCmd = &cobra.Command{
+	Use:   "nodegroup",
+	Short: "add or delete nodegroups of a running cluster (contains subcommands)",
+}
+
+var _clusterNodeGroupAddCmd = &cobra.Command{
+	Use:   "add NODEGROUP_CONFIG_FILE",
+	Short: "add a nodegroup to the cluster",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		telemetry.Event("cli.cluster.nodegroup.add")
+
+		nodeGroup, err := readNodeGroupConfigFile(args[0])
+		if err != nil {
+			exit.Error(err)
+		}
+
+		cmdUpdateNodeGroups(func(nodeGroups []*clusterconfig.NodeGroup) ([]*clusterconfig.NodeGroup, error) {
+			return append(nodeGroups, nodeGroup), nil
+		})
+	},
+}
+
+var _clusterNodeGroupDeleteCmd = &cobra.Command{
+	Use:   "delete NODEGROUP_NAME",
+	Short: "delete a nodegroup from the cluster",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		telemetry.Event("cli.cluster.nodegroup.delete")
+
+		nodeGroupName := args[0]
+
+		cmdUpdateNodeGroups(func(nodeGroups []*clusterconfig.NodeGroup) ([]*clusterconfig.NodeGroup, error) {
+			remainingNodeGroups := []*clusterconfig.NodeGroup{}
+			for _, nodeGroup := range nodeGroups {
+				if nodeGroup.Name != nodeGroupName {
+					remainingNodeGroups = append(remainingNodeGroups, nodeGroup)
+				}
+			}
+			if len(remainingNodeGroups) == len(nodeGroups) {
+				return nil, clusterconfig.ErrorNodeGroupNotFound(nodeGroupName, clusterconfig.GetNodeGroupNames(nodeGroups))
+			}
+			return remainingNodeGroups, nil
+		})
+	},
+}
+
+var _clusterPauseCmd = &cobra.Command{
+	Use:   "pause",
+	Short: "scale all of the cluster's nodegroups to zero and suspend the operator",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		telemetry.Event("cli.cluster.pause")
+
+		if _, err := docker.GetDockerClient(); err != nil {
+			exit.Error(err)
+		}
+
+		accessConfig, err := getClusterAccessConfigWithCache(true)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		awsClient, err := newAWSClient(accessConfig.Region, true)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		stacks, err := clusterstate.GetClusterStacks(awsClient, accessConfig)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		state := clusterstate.GetClusterState(stacks)
+		if err := clusterstate.AssertClusterState(stacks, state, clusterstate.StateClusterExists); err != nil {
+			exit.Error(err)
+		}
+
+		clusterConfig := refreshCachedClusterConfig(awsClient, accessConfig, true)
+
+		promptIfNotAdmin(awsClient, _flagClusterDisallowPrompt)
+
+		if !_flagClusterDisallowPrompt {
+			prompt.YesOrExit(fmt.Sprintf("your cluster named \"%s\" in %s will be paused: all of its instances will be terminated and your apis will be unavailable until you run `cortex cluster resume` (the cluster's state and api deployments will be preserved), are you sure you want to continue?", clusterConfig.ClusterName, clusterConfig.Region), "", "")
+		}
+
+		out, exitCode, err := runManagerWithClusterConfig("/root/install.sh --pause", &clusterConfig, awsClient, nil, nil, nil)
+		if err != nil {
+			exit.Error(err)
+		}
+		if exitCode == nil || *exitCode != 0 {
+			exit.Error(ErrorClusterPause(out))
+		}
+	},
+}
+
+var _clusterResumeCmd = &cobra.Command{
+	Use:   "resume",
+	Short: "restore the nodegroups and operator of a paused cluster",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		telemetry.Event("cli.cluster.resume")
+
+		if _, err := docker.GetDockerClient(); err != nil {
+			exit.Error(err)
+		}
+
+		accessConfig, err := getClusterAccessConfigWithCache(true)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		awsClient, err := newAWSClient(accessConfig.Region, true)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		stacks, err := clusterstate.GetClusterStacks(awsClient, accessConfig)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		state := clusterstate.GetClusterState(stacks)
+		if err := clusterstate.AssertClusterState(stacks, state, clusterstate.StateClusterExists); err != nil {
+			exit.Error(err)
+		}
+
+		clusterConfig := refreshCachedClusterConfig(awsClient, accessConfig, true)
+
+		out, exitCode, err := runManagerWithClusterConfig("/root/install.sh --resume", &clusterConfig, awsClient, nil, nil, nil)
+		if err != nil {
+			exit.Error(err)
+		}
+		if exitCode == nil || *exitCode != 0 {
+			exit.Error(ErrorClusterResume(out))
+		}
+	},
+}
+
+var _clusterUpgradeCmd = &cobra.Command{
+	Use:   "upgrade",
+	Short: "upgrade a cluster to the version of your cli (operator and manager images, kubernetes version, and node amis)",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		telemetry.Event("cli.cluster.upgrade")
+
+		if _, err := docker.GetDockerClient(); err != nil {
+			exit.Error(err)
+		}
+
+		accessConfig, err := getClusterAccessConfigWithCache(true)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		awsClient, err := newAWSClient(accessConfig.Region, true)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		stacks, err := clusterstate.GetClusterStacks(awsClient, accessConfig)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		state := clusterstate.GetClusterState(stacks)
+		if err := clusterstate.AssertClusterState(stacks, state, clusterstate.StateClusterExists); err != nil {
+			exit.Error(err)
+		}
+
+		clusterConfig := refreshCachedClusterConfig(awsClient, accessConfig, true)
+
+		promptIfNotAdmin(awsClient, _flagClusterDisallowPrompt)
+
+		clusterVersion := clusterCortexVersion(clusterConfig)
+		if clusterVersion == consts.CortexVersion {
+			fmt.Printf("your cluster is already running cortex %s\n", consts.CortexVersion)
+			exit.Ok()
+		}
+		if err := validateUpgradeVersionSkew(clusterVersion, consts.CortexVersion); err != nil {
+			exit.Error(err)
+		}
+
+		restConfig, err := getClusterRESTConfig(awsClient, accessConfig.ClusterName)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		scheme := runtime.NewScheme()
+		if err := clientgoscheme.AddToScheme(scheme); err != nil {
+			exit.Error(err)
+		}
+
+		k8sClient, err := k8s.New(consts.DefaultNamespace, false, restConfig, scheme)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		jobIDs, err := jobsInProgress(k8sClient)
+		if err != nil {
+			exit.Error(err)
+		}
+		if len(jobIDs) > 0 {
+			exit.Error(ErrorJobsInProgressDuringUpgrade(jobIDs))
+		}
+
+		specMigrations, numSkippedSpecs, err := getAPISpecMigrations(awsClient, clusterConfig, clusterVersion)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		upgradedClusterConfig, customImageKeys, err := upgradeClusterConfigImages(clusterConfig, clusterVersion, consts.CortexVersion)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		if len(customImageKeys) > 0 {
+			fmt.Printf("warning: the following images are not the default images for cortex %s and will not be upgraded: %s\n\n", clusterVersion, s.StrsAnd(customImageKeys))
+		}
+		if numSkippedSpecs > 0 {
+			fmt.Printf("warning: %d previous %s of your apis %s not compatible with cortex %s and will not be available after the upgrade (the current deployments of your apis are not affected)\n\n", numSkippedSpecs, s.PluralCustom("deployment", "deployments", numSkippedSpecs), s.PluralCustom("is", "are", numSkippedSpecs), consts.CortexVersion)
+		}
+
+		if !_flagClusterDisallowPrompt {
+			prompt.YesOrExit(fmt.Sprintf("your cluster named \"%s\" in %s will be upgraded from cortex %s to cortex %s; nodegroups whose amis are outdated will be replaced one at a time (which will cause the pods running on them to be rescheduled), are you sure you want to continue?", clusterConfig.ClusterName, clusterConfig.Region, clusterVersion, consts.CortexVersion), "", "")
+		}
+
+		if err := migrateAPISpecs(awsClient, clusterConfig.Bucket, specMigrations); err != nil {
+			exit.Error(err)
+		}
+
+		out, exitCode, err := runManagerWithClusterConfig("/root/install.sh --upgrade", &upgradedClusterConfig, awsClient, nil, nil, nil)
+		if err != nil {
+			exit.Error(err)
+		}
+		if exitCode == nil || *exitCode != 0 {
+			exit.Error(ErrorClusterUpgrade(out))
 		}
 	},
 }
 
+var _clusterValidateCmd = &cobra.Command{
+	Use:   "validate CLUSTER_CONFIG_FILE",
+	Short: "validate a cluster configuration file against your aws account without creating any resources",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		telemetry.Event("cli.cluster.validate")
+
+		clusterConfigFile := args[0]
+
+		result := validateClusterConfigFile(clusterConfigFile, _flagOutput == flags.PrettyOutputType)
+
+		if _flagOutput == flags.JSONOutputType {
+			bytes, err := libjson.Marshal(result)
+			if err != nil {
+				exit.Error(err)
+			}
+			fmt.Println(string(bytes))
+		} else {
+			printClusterValidationResult(clusterConfigFile, result)
+		}
+
+		if !result.Valid {
+			exit.Error(nil)
+		}
+	},
+}
+
+var _clusterRenderCmd = &cobra.Command{
+	Use:   "render CLUSTER_CONFIG_FILE",
+	Short: "render the helm values and the operator, gateway, and ingress manifests of a cluster configuration for review, without applying them",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		telemetry.Event("cli.cluster.render")
+
+		clusterConfigFile := args[0]
+
+		if _, err := docker.GetDockerClient(); err != nil {
+			exit.Error(err)
+		}
+
+		accessConfig, err := getNewClusterAccessConfig(clusterConfigFile)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		awsClient, err := newAWSClient(accessConfig.Region, true)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		clusterConfig, err := getInstallClusterConfig(awsClient, clusterConfigFile, false)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		renderDir := clusterRenderDirName(clusterConfig.ClusterName, clusterConfig.Region)
+		copyFromPaths := []dockerCopyFromPath{
+			{
+				containerPath: "/out/" + renderDir,
+				localDir:      _cwd,
+			},
+		}
+
+		out, exitCode, err := runManagerWithClusterConfig("/root/render.sh /out/"+renderDir, clusterConfig, awsClient, nil, copyFromPaths, nil)
+		if err != nil {
+			exit.Error(err)
+		}
+		if exitCode == nil || *exitCode != 0 {
+			exit.Error(ErrorClusterRender(out))
+		}
+
+		valuesBytes, err := yaml.Marshal(clusterconfig.NewHelmValues(clusterConfig))
+		if err != nil {
+			exit.Error(err)
+		}
+		if err := files.WriteFile(valuesBytes, filepath.Join(_cwd, renderDir, "values.yaml")); err != nil {
+			exit.Error(err)
+		}
+
+		fmt.Printf("the helm values and the operator, gateway, and ingress manifests of your cluster named \"%s\" in %s have been rendered to ./%s\n", clusterConfig.ClusterName, clusterConfig.Region, renderDir)
+	},
+}
+
+func clusterRenderDirName(clusterName string, region string) string {
+	return fmt.Sprintf("render-%s-%s", region, clusterName)
+}
+
 var _clusterInfoCmd = &cobra.Command{
 	Use:   "info",
 	Short: "get information about a cluster",
@@ -643,83 +1170,205 @@ var _clusterDownCmd = &cobra.Command{
 				}
 			}
 
-			fmt.Printf("￮ deleting log group %s ... ", accessConfig.ClusterName)
-			logGroupExists, err := awsClient.DoesLogGroupExist(accessConfig.ClusterName)
+			fmt.Printf("￮ deleting log group %s ... ", accessConfig.ClusterName)
+			logGroupExists, err := awsClient.DoesLogGroupExist(accessConfig.ClusterName)
+			if err != nil {
+				errorsList = append(errorsList, err)
+				fmt.Print("failed ✗")
+				fmt.Printf("\n\nfailed to list log group for deletion; please delete the log group associated with your cluster via the ec2 console: https://%s.console.aws.amazon.com/cloudwatch/home?#logsV2:log-groups\n", accessConfig.Region)
+				errors.PrintError(err)
+				fmt.Println()
+			} else {
+				if !logGroupExists {
+					fmt.Println("log group doesn't exist ✓")
+				} else {
+					err = awsClient.DeleteLogGroup(accessConfig.ClusterName)
+					if err != nil {
+						errorsList = append(errorsList, err)
+						fmt.Print("failed ✗")
+						fmt.Printf("\n\nfailed to delete log group %s; please delete the log group associated with your cluster via the ec2 console: https://%s.console.aws.amazon.com/cloudwatch/home?#logsV2:log-groups\n", accessConfig.ClusterName, accessConfig.Region)
+						errors.PrintError(err)
+						fmt.Println()
+					} else {
+						fmt.Println("✓")
+					}
+				}
+			}
+		}
+
+		// best-effort deletion of cached config
+		cachedClusterConfigPath := getCachedClusterConfigPath(accessConfig.ClusterName, accessConfig.Region)
+		_ = os.Remove(cachedClusterConfigPath)
+
+		if len(errorsList) > 0 {
+			exit.Error(errors.ListOfErrors(ErrClusterDown, false, errorsList...))
+		}
+		fmt.Printf("\nplease check CloudFormation to ensure that all resources for the %s cluster eventually become successfully deleted: %s\n", accessConfig.ClusterName, clusterstate.CloudFormationURL(accessConfig.ClusterName, accessConfig.Region))
+		if !_flagClusterDownKeepAWSResources && bucketExists {
+			fmt.Printf("\na lifecycle rule has been applied to the cluster's %s bucket to empty its contents within the next 24 hours; you can delete the %s bucket via the s3 console once it has been emptied (or you can empty and delete it now): https://s3.console.aws.amazon.com/s3/management/%s\n", bucketName, bucketName, bucketName)
+		}
+		fmt.Println()
+
+		// best-effort deletion of cli environment(s)
+		if loadBalancer != nil {
+			envNames, isDefaultEnv, _ := getEnvNamesByOperatorEndpoint(*loadBalancer.DNSName)
+			if len(envNames) > 0 {
+				for _, envName := range envNames {
+					err := removeEnvFromCLIConfig(envName)
+					if err != nil {
+						exit.Error(err)
+					}
+				}
+				fmt.Printf("deleted the %s environment configuration%s\n", s.StrsAnd(envNames), s.SIfPlural(len(envNames)))
+				if isDefaultEnv {
+					newDefaultEnv, err := getDefaultEnv()
+					if err != nil {
+						exit.Error(err)
+					}
+					if newDefaultEnv != nil {
+						fmt.Println(fmt.Sprintf("set the default environment to %s", *newDefaultEnv))
+					}
+				}
+			}
+		}
+	},
+}
+
+var _clusterExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "export the cluster configuration, the configurations of all APIs, and the environments of a cluster to a single file",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		telemetry.Event("cli.cluster.export")
+
+		if _, err := docker.GetDockerClient(); err != nil {
+			exit.Error(err)
+		}
+
+		accessConfig, err := getClusterAccessConfigWithCache(true)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		// Check AWS access
+		awsClient, err := newAWSClient(accessConfig.Region, true)
+		if err != nil {
+			exit.Error(err)
+		}
+		warnIfNotAdmin(awsClient)
+
+		stacks, err := clusterstate.GetClusterStacks(awsClient, accessConfig)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		state := clusterstate.GetClusterState(stacks)
+		if err := clusterstate.AssertClusterState(stacks, state, clusterstate.StateClusterExists); err != nil {
+			exit.Error(err)
+		}
+
+		clusterConfig := refreshCachedClusterConfig(awsClient, accessConfig, true)
+
+		if _flagClusterExportFormat == flags.TerraformExportFormat || _flagClusterExportFormat == flags.CloudFormationExportFormat {
+			iacExport, err := renderIACExport(clusterConfig, _flagClusterExportFormat)
+			if err != nil {
+				exit.Error(err)
+			}
+
+			exportPath := iacExportFileName(accessConfig.ClusterName, accessConfig.Region, _flagClusterExportFormat)
+			if err := files.WriteFile([]byte(iacExport), exportPath); err != nil {
+				exit.Error(err)
+			}
+
+			fmt.Printf("the infrastructure of your cluster named \"%s\" in %s has been exported as %s to %s\n", accessConfig.ClusterName, accessConfig.Region, _flagClusterExportFormat.String(), exportPath)
+			return
+		}
+
+		clusterConfigBytes, err := yaml.Marshal(clusterConfig.CoreConfig)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		loadBalancer, err := getNLBLoadBalancer(accessConfig.ClusterName, OperatorLoadBalancer, awsClient)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		operatorConfig := cluster.OperatorConfig{
+			Telemetry:        isTelemetryEnabled(),
+			ClientID:         clientID(),
+			OperatorEndpoint: "https://" + *loadBalancer.DNSName,
+		}
+
+		var apisResponse []schema.APIResponse
+		apisResponse, err = cluster.GetAPIs(operatorConfig, "", "metadata")
+		if err != nil {
+			exit.Error(err)
+		}
+
+		apiConfigs := map[string][]byte{}
+		for _, api := range apisResponse {
+			apisWithSpec, err := cluster.GetAPI(operatorConfig, api.Metadata.Name)
+			if err != nil {
+				exit.Error(err)
+			}
+
+			yamlBytes, err := yaml.Marshal(apisWithSpec[0].Spec.API.SubmittedAPISpec)
 			if err != nil {
-				errorsList = append(errorsList, err)
-				fmt.Print("failed ✗")
-				fmt.Printf("\n\nfailed to list log group for deletion; please delete the log group associated with your cluster via the ec2 console: https://%s.console.aws.amazon.com/cloudwatch/home?#logsV2:log-groups\n", accessConfig.Region)
-				errors.PrintError(err)
-				fmt.Println()
-			} else {
-				if !logGroupExists {
-					fmt.Println("log group doesn't exist ✓")
-				} else {
-					err = awsClient.DeleteLogGroup(accessConfig.ClusterName)
-					if err != nil {
-						errorsList = append(errorsList, err)
-						fmt.Print("failed ✗")
-						fmt.Printf("\n\nfailed to delete log group %s; please delete the log group associated with your cluster via the ec2 console: https://%s.console.aws.amazon.com/cloudwatch/home?#logsV2:log-groups\n", accessConfig.ClusterName, accessConfig.Region)
-						errors.PrintError(err)
-						fmt.Println()
-					} else {
-						fmt.Println("✓")
-					}
-				}
+				exit.Error(err)
 			}
+			apiConfigs[api.Metadata.Name] = yamlBytes
 		}
 
-		// best-effort deletion of cached config
-		cachedClusterConfigPath := getCachedClusterConfigPath(accessConfig.ClusterName, accessConfig.Region)
-		_ = os.Remove(cachedClusterConfigPath)
+		envNames, _, err := getEnvNamesByOperatorEndpoint(operatorConfig.OperatorEndpoint)
+		if err != nil {
+			exit.Error(err)
+		}
+		defaultEnv, err := getDefaultEnv()
+		if err != nil {
+			exit.Error(err)
+		}
 
-		if len(errorsList) > 0 {
-			exit.Error(errors.ListOfErrors(ErrClusterDown, false, errorsList...))
+		export := clusterExport{
+			Metadata:      newClusterExportMetadata(accessConfig.ClusterName, accessConfig.Region, envNames, defaultEnv),
+			ClusterConfig: clusterConfigBytes,
+			APIConfigs:    apiConfigs,
 		}
-		fmt.Printf("\nplease check CloudFormation to ensure that all resources for the %s cluster eventually become successfully deleted: %s\n", accessConfig.ClusterName, clusterstate.CloudFormationURL(accessConfig.ClusterName, accessConfig.Region))
-		if !_flagClusterDownKeepAWSResources && bucketExists {
-			fmt.Printf("\na lifecycle rule has been applied to the cluster's %s bucket to empty its contents within the next 24 hours; you can delete the %s bucket via the s3 console once it has been emptied (or you can empty and delete it now): https://s3.console.aws.amazon.com/s3/management/%s\n", bucketName, bucketName, bucketName)
+
+		exportPath := clusterExportFileName(accessConfig.ClusterName, accessConfig.Region)
+		if err := writeClusterExport(export, exportPath); err != nil {
+			exit.Error(err)
 		}
-		fmt.Println()
 
-		// best-effort deletion of cli environment(s)
-		if loadBalancer != nil {
-			envNames, isDefaultEnv, _ := getEnvNamesByOperatorEndpoint(*loadBalancer.DNSName)
-			if len(envNames) > 0 {
-				for _, envName := range envNames {
-					err := removeEnvFromCLIConfig(envName)
-					if err != nil {
-						exit.Error(err)
-					}
-				}
-				fmt.Printf("deleted the %s environment configuration%s\n", s.StrsAnd(envNames), s.SIfPlural(len(envNames)))
-				if isDefaultEnv {
-					newDefaultEnv, err := getDefaultEnv()
-					if err != nil {
-						exit.Error(err)
-					}
-					if newDefaultEnv != nil {
-						fmt.Println(fmt.Sprintf("set the default environment to %s", *newDefaultEnv))
-					}
-				}
-			}
+		for _, apiName := range export.sortedAPINames() {
+			fmt.Printf("exported %s\n", apiName)
 		}
+		fmt.Printf("\nthe cluster configuration, %d %s, and %d %s of your cluster named \"%s\" in %s have been exported to %s\n", len(apiConfigs), s.PluralS("api", len(apiConfigs)), len(envNames), s.PluralS("environment", len(envNames)), accessConfig.ClusterName, accessConfig.Region, exportPath)
 	},
 }
 
-var _clusterExportCmd = &cobra.Command{
-	Use:   "export",
-	Short: "download the configurations for all APIs",
-	Args:  cobra.NoArgs,
+var _clusterImportCmd = &cobra.Command{
+	Use:   "import EXPORT_FILE",
+	Short: "deploy the APIs and configure the environments from a cluster export onto a running cluster",
+	Args:  cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
-		telemetry.Event("cli.cluster.export")
+		telemetry.Event("cli.cluster.import")
+
+		exportPath := files.RelToAbsPath(args[0], _cwd)
+		if err := files.CheckFile(exportPath); err != nil {
+			exit.Error(err)
+		}
+
+		export, err := readClusterExport(exportPath)
+		if err != nil {
+			exit.Error(err)
+		}
 
 		accessConfig, err := getClusterAccessConfigWithCache(true)
 		if err != nil {
 			exit.Error(err)
 		}
 
-		// Check AWS access
 		awsClient, err := newAWSClient(accessConfig.Region, true)
 		if err != nil {
 			exit.Error(err)
@@ -747,41 +1396,55 @@ var _clusterExportCmd = &cobra.Command{
 			OperatorEndpoint: "https://" + *loadBalancer.DNSName,
 		}
 
-		var apisResponse []schema.APIResponse
-		apisResponse, err = cluster.GetAPIs(operatorConfig)
-		if err != nil {
-			exit.Error(err)
-		}
-		if len(apisResponse) == 0 {
-			fmt.Println(fmt.Sprintf("no apis found in your cluster named %s in %s", accessConfig.ClusterName, accessConfig.Region))
-			exit.Ok()
+		if export.Metadata.CortexVersion != consts.CortexVersion {
+			fmt.Printf("warning: %s was exported from a cluster running cortex %s, and your cli is version %s; the api configurations may need to be updated (see https://github.com/cortexlabs/cortex/releases)\n\n", exportPath, export.Metadata.CortexVersion, consts.CortexVersion)
 		}
 
-		exportPath := fmt.Sprintf("export-%s-%s", accessConfig.Region, accessConfig.ClusterName)
-
-		err = files.CreateDir(exportPath)
-		if err != nil {
-			exit.Error(err)
+		apiNames := export.sortedAPINames()
+		if !_flagClusterDisallowPrompt {
+			msg := fmt.Sprintf("%d %s exported from the cluster named \"%s\" in %s will be deployed to your cluster named \"%s\" in %s (apis with the same names will be updated)", len(apiNames), s.PluralS("api", len(apiNames)), export.Metadata.ClusterName, export.Metadata.Region, accessConfig.ClusterName, accessConfig.Region)
+			if len(export.Metadata.Environments) > 0 {
+				msg += fmt.Sprintf(", and the %s %s will be configured to point to it", s.StrsAnd(export.Metadata.Environments), s.PluralCustom("environment", "environments", len(export.Metadata.Environments)))
+			}
+			prompt.YesOrExit(msg+"; are you sure you want to continue?", "", "")
 		}
 
-		for _, api := range apisResponse {
-			apisWithSpec, err := cluster.GetAPI(operatorConfig, api.Metadata.Name)
+		var deployResults []schema.DeployResult
+		for _, apiName := range apiNames {
+			results, err := cluster.Deploy(operatorConfig, apiName+".yaml", map[string][]byte{"config": export.APIConfigs[apiName]}, false, false, nil)
 			if err != nil {
 				exit.Error(err)
 			}
+			deployResults = append(deployResults, results...)
+		}
 
-			specFilePath := filepath.Join(exportPath, api.Metadata.Name+".yaml")
-			fmt.Println(fmt.Sprintf("exporting %s to %s", api.Metadata.Name, specFilePath))
-
-			yamlBytes, err := yaml.Marshal(apisWithSpec[0].Spec.API.SubmittedAPISpec)
-			if err != nil {
-				exit.Error(err)
+		if len(deployResults) > 0 {
+			message := mergeResultMessages(deployResults)
+			if didAnyResultsError(deployResults) {
+				print.StderrBoldFirstBlock(message)
+			} else {
+				print.BoldFirstBlock(message)
 			}
+		}
 
-			err = files.WriteFile(yamlBytes, specFilePath)
-			if err != nil {
+		for _, envName := range export.Metadata.Environments {
+			setAsDefault := export.Metadata.DefaultEnvironment != nil && *export.Metadata.DefaultEnvironment == envName
+			newEnvironment := cliconfig.Environment{
+				Name:             envName,
+				OperatorEndpoint: operatorConfig.OperatorEndpoint,
+			}
+			if err := addEnvToCLIConfig(newEnvironment, setAsDefault); err != nil {
 				exit.Error(err)
 			}
+			if setAsDefault {
+				fmt.Printf(console.Bold("the environment named \"%s\" has been configured to point to this cluster (and was set as the default environment)\n"), envName)
+			} else {
+				fmt.Printf(console.Bold("the environment named \"%s\" has been configured to point to this cluster\n"), envName)
+			}
+		}
+
+		if didAnyResultsError(deployResults) {
+			exit.Error(nil)
 		}
 	},
 }
@@ -863,6 +1526,176 @@ var _clusterHealthCmd = &cobra.Command{
 	},
 }
 
+var _clusterCostCmd = &cobra.Command{
+	Use:   "cost",
+	Short: "show the cluster's aws spend per nodegroup (from cost explorer)",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		telemetry.Event("cli.cluster.cost")
+
+		if _flagClusterCostDays < 1 || _flagClusterCostDays > _maxClusterCostDays {
+			exit.Error(ErrorInvalidCostLookback(_flagClusterCostDays, _maxClusterCostDays))
+		}
+
+		accessConfig, err := getClusterAccessConfigWithCache(true)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		awsClient, err := awslib.NewForRegion(accessConfig.Region)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		clusterCost, err := getClusterCost(awsClient, accessConfig, _flagClusterCostDays)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		if _flagOutput == flags.JSONOutputType {
+			bytes, err := libjson.Marshal(clusterCost)
+			if err != nil {
+				exit.Error(err)
+			}
+			fmt.Println(string(bytes))
+			return
+		}
+
+		printClusterCost(clusterCost)
+	},
+}
+
+var _clusterAuditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "check the cluster's subnets, nat gateways, and security groups for drift",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		telemetry.Event("cli.cluster.audit")
+
+		if _, err := docker.GetDockerClient(); err != nil {
+			exit.Error(err)
+		}
+
+		accessConfig, err := getClusterAccessConfigWithCache(true)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		awsClient, err := newAWSClient(accessConfig.Region, _flagOutput == flags.PrettyOutputType)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		stacks, err := clusterstate.GetClusterStacks(awsClient, accessConfig)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		state := clusterstate.GetClusterState(stacks)
+		if err := clusterstate.AssertClusterState(stacks, state, clusterstate.StateClusterExists); err != nil {
+			exit.Error(err)
+		}
+
+		clusterConfig := refreshCachedClusterConfig(awsClient, accessConfig, _flagOutput == flags.PrettyOutputType)
+
+		findings, err := getClusterAuditFindings(awsClient, clusterConfig)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		if _flagOutput == flags.JSONOutputType {
+			bytes, err := libjson.Marshal(findings)
+			if err != nil {
+				exit.Error(err)
+			}
+			fmt.Println(string(bytes))
+			return
+		}
+
+		printClusterAuditFindings(clusterConfig.ClusterName, findings)
+	},
+}
+
+// updates the running cluster's nodegroups according to updateNodeGroups, without requiring a full cluster configuration file
+func cmdUpdateNodeGroups(updateNodeGroups func(nodeGroups []*clusterconfig.NodeGroup) ([]*clusterconfig.NodeGroup, error)) {
+	if _, err := docker.GetDockerClient(); err != nil {
+		exit.Error(err)
+	}
+
+	accessConfig, err := getClusterAccessConfigWithCache(true)
+	if err != nil {
+		exit.Error(err)
+	}
+
+	awsClient, err := newAWSClient(accessConfig.Region, true)
+	if err != nil {
+		exit.Error(err)
+	}
+
+	restConfig, err := getClusterRESTConfig(awsClient, accessConfig.ClusterName)
+	if err != nil {
+		exit.Error(err)
+	}
+
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		exit.Error(err)
+	}
+
+	k8sClient, err := k8s.New(consts.DefaultNamespace, false, restConfig, scheme)
+	if err != nil {
+		exit.Error(err)
+	}
+
+	stacks, err := clusterstate.GetClusterStacks(awsClient, accessConfig)
+	if err != nil {
+		exit.Error(err)
+	}
+
+	state := clusterstate.GetClusterState(stacks)
+	if err := clusterstate.AssertClusterState(stacks, state, clusterstate.StateClusterExists); err != nil {
+		exit.Error(err)
+	}
+
+	oldClusterConfig := refreshCachedClusterConfig(awsClient, accessConfig, true)
+
+	promptIfNotAdmin(awsClient, _flagClusterDisallowPrompt)
+
+	newClusterConfig, configureChanges, err := getNodeGroupsClusterConfig(awsClient, k8sClient, stacks, oldClusterConfig, updateNodeGroups)
+	if err != nil {
+		exit.Error(err)
+	}
+
+	if !configureChanges.HasChanges() {
+		fmt.Println("your cluster is already up to date")
+		exit.Ok()
+	}
+
+	confirmConfigureClusterConfig(configureChanges, oldClusterConfig, *newClusterConfig, _flagClusterDisallowPrompt)
+
+	out, exitCode, err := runManagerWithNodeGroupChanges("/root/install.sh --nodegroups", newClusterConfig, awsClient, configureChanges)
+	if err != nil {
+		exit.Error(err)
+	}
+	if exitCode == nil || *exitCode != 0 {
+		exitClusterConfigureFailure(out, oldClusterConfig.Region)
+	}
+}
+
+func exitClusterConfigureFailure(out string, region string) {
+	out = s.LastNChars(out, 8192) // get the last 8192 characters because that is the sentry message limit
+
+	helpStr := "\ndebugging tips (may or may not apply to this error):"
+	helpStr += fmt.Sprintf(
+		"\n* if your cluster was unable to provision/remove/scale some nodegroups, additional error information may be found in the description of your cloudformation stack (https://console.aws.amazon.com/cloudformation/home?region=%s#/stacks)"+
+			" or in the activity history of your cluster's autoscaling groups (select each autoscaling group and click the  \"Activity\" or \"Activity History\" tab) (https://console.aws.amazon.com/ec2/autoscaling/home?region=%s#AutoScalingGroups)",
+		region,
+		region,
+	)
+	fmt.Println(helpStr)
+	exit.Error(ErrorClusterConfigure(out + helpStr))
+}
+
 func cmdPrintConfig(awsClient *awslib.Client, accessConfig *clusterconfig.AccessConfig, outputType flags.OutputType) {
 	clusterConfig := refreshCachedClusterConfig(awsClient, accessConfig, outputType == flags.PrettyOutputType)
 
@@ -908,6 +1741,15 @@ func cmdInfo(awsClient *awslib.Client, accessConfig *clusterconfig.AccessConfig,
 		apiEndpoint = *apiLoadBalancer.DNSName
 	}
 
+	// the internal api load balancer only exists if the api load balancer is internet-facing
+	var internalAPIEndpoint string
+	if clusterConfig.APILoadBalancerScheme == clusterconfig.InternetFacingLoadBalancerScheme {
+		internalAPIEndpoint, err = getInternalAPILoadBalancerEndpoint(accessConfig.ClusterName, clusterConfig.APILoadBalancerType, awsClient)
+		if err != nil {
+			exit.Error(err)
+		}
+	}
+
 	if outputType == flags.JSONOutputType || outputType == flags.YAMLOutputType {
 		infoResponse, err := getInfoOperatorResponse(operatorEndpoint)
 		if err != nil {
@@ -923,6 +1765,9 @@ func cmdInfo(awsClient *awslib.Client, accessConfig *clusterconfig.AccessConfig,
 			"endpoint_operator":   operatorEndpoint,
 			"endpoint_api":        apiEndpoint,
 		}
+		if internalAPIEndpoint != "" {
+			infoInterface["endpoint_api_internal"] = internalAPIEndpoint
+		}
 
 		var outputBytes []byte
 		if outputType == flags.JSONOutputType {
@@ -939,6 +1784,9 @@ func cmdInfo(awsClient *awslib.Client, accessConfig *clusterconfig.AccessConfig,
 		fmt.Println(console.Bold("endpoints:"))
 		fmt.Println("operator:         ", operatorEndpoint)
 		fmt.Println("api load balancer:", apiEndpoint)
+		if internalAPIEndpoint != "" {
+			fmt.Println("internal api load balancer:", internalAPIEndpoint)
+		}
 		fmt.Println()
 
 		if err := printInfoOperatorResponse(clusterConfig, stacks, operatorEndpoint); err != nil {
@@ -977,6 +1825,7 @@ func printInfoOperatorResponse(clusterConfig clusterconfig.Config, stacks cluste
 
 	printInfoPricing(infoResponse, clusterConfig)
 	printInfoNodes(infoResponse)
+	printInfoSpotInterruptions(infoResponse)
 
 	return nil
 }
@@ -992,7 +1841,7 @@ func getInfoOperatorResponse(operatorEndpoint string) (*schema.InfoResponse, err
 
 func printInfoPricing(infoResponse *schema.InfoResponse, clusterConfig clusterconfig.Config) {
 	eksPrice := awslib.EKSPrices[clusterConfig.Region]
-	operatorInstancePrice := awslib.InstanceMetadatas[clusterConfig.Region]["t3.medium"].Price
+	operatorInstancePrice := awslib.InstanceMetadatas[clusterConfig.Region][clusterConfig.OperatorNodeGroupInstanceType()].Price
 	operatorEBSPrice := awslib.EBSMetadatas[clusterConfig.Region]["gp3"].PriceGB * 20 / 30 / 24
 	prometheusInstancePrice := awslib.InstanceMetadatas[clusterConfig.Region][clusterConfig.PrometheusInstanceType].Price
 	prometheusEBSPrice := awslib.EBSMetadatas[clusterConfig.Region]["gp3"].PriceGB * 20 / 30 / 24
@@ -1060,7 +1909,7 @@ func printInfoPricing(infoResponse *schema.InfoResponse, clusterConfig clusterco
 	totalPrice := eksPrice + totalNodeGroupsPrice + operatorNodeGroupPrice + prometheusNodeGroupPrice + loadBalancersPrice + natTotalPrice
 	fmt.Printf(console.Bold("\nyour cluster currently costs %s per hour\n\n"), s.DollarsAndCents(totalPrice))
 
-	rows = append(rows, []interface{}{fmt.Sprintf("%d t3.medium %s (cortex system)", len(infoResponse.OperatorNodeInfos), s.PluralS("instance", len(infoResponse.OperatorNodeInfos))), s.DollarsAndTenthsOfCents(operatorNodeGroupPrice) + " total"})
+	rows = append(rows, []interface{}{fmt.Sprintf("%d %s %s (cortex system)", len(infoResponse.OperatorNodeInfos), clusterConfig.OperatorNodeGroupInstanceType(), s.PluralS("instance", len(infoResponse.OperatorNodeInfos))), s.DollarsAndTenthsOfCents(operatorNodeGroupPrice) + " total"})
 	rows = append(rows, []interface{}{fmt.Sprintf("1 %s instance (prometheus)", clusterConfig.PrometheusInstanceType), s.DollarsAndTenthsOfCents(prometheusNodeGroupPrice)})
 	if usesELBForAPILoadBalancer {
 		rows = append(rows, []interface{}{"1 network load balancer", s.DollarsMaxPrecision(nlbPrice)})
@@ -1145,6 +1994,33 @@ func printInfoNodes(infoResponse *schema.InfoResponse) {
 	t.MustPrint(&table.Opts{Sort: pointer.Bool(false)})
 }
 
+func printInfoSpotInterruptions(infoResponse *schema.InfoResponse) {
+	var totalInterruptions int64
+	for _, count := range infoResponse.SpotInterruptions {
+		totalInterruptions += count
+	}
+	if totalInterruptions == 0 {
+		return
+	}
+
+	fmt.Printf(console.Bold("\n%d spot %s handled (the affected instances were drained and replaced)\n"), totalInterruptions, s.PluralS("interruption", totalInterruptions))
+
+	var rows [][]interface{}
+	for ngName, count := range infoResponse.SpotInterruptions {
+		rows = append(rows, []interface{}{ngName, count})
+	}
+
+	t := table.Table{
+		Headers: []table.Header{
+			{Title: "nodegroup"},
+			{Title: "spot interruptions"},
+		},
+		Rows: rows,
+	}
+	fmt.Println()
+	t.MustPrint(nil)
+}
+
 func updateCLIEnv(envName string, operatorEndpoint string, disallowPrompt bool, printToStdout bool) error {
 	prevEnv, err := readEnv(envName)
 	if err != nil {
@@ -1386,8 +2262,9 @@ func createLogGroupIfNotFound(awsClient *awslib.Client, logGroup string, tags ma
 type LoadBalancer string
 
 var (
-	OperatorLoadBalancer LoadBalancer = "operator"
-	APILoadBalancer      LoadBalancer = "api"
+	OperatorLoadBalancer    LoadBalancer = "operator"
+	APILoadBalancer         LoadBalancer = "api"
+	InternalAPILoadBalancer LoadBalancer = "api-internal"
 )
 
 func (lb LoadBalancer) String() string {
@@ -1411,6 +2288,34 @@ func getNLBLoadBalancer(clusterName string, whichLB LoadBalancer, awsClient *aws
 	return loadBalancer, nil
 }
 
+// Returns an empty string if the internal api load balancer doesn't exist (e.g. if the cluster was created before it was introduced)
+func getInternalAPILoadBalancerEndpoint(clusterName string, loadBalancerType clusterconfig.LoadBalancerType, awsClient *awslib.Client) (string, error) {
+	tags := map[string]string{
+		clusterconfig.ClusterNameTag: clusterName,
+		"cortex.dev/load-balancer":   InternalAPILoadBalancer.String(),
+	}
+
+	if loadBalancerType == clusterconfig.ELBLoadBalancerType {
+		loadBalancer, err := awsClient.FindLoadBalancer(tags)
+		if err != nil {
+			return "", errors.Wrap(err, fmt.Sprintf("unable to locate %s load balancer", InternalAPILoadBalancer.String()))
+		}
+		if loadBalancer == nil || loadBalancer.DNSName == nil {
+			return "", nil
+		}
+		return *loadBalancer.DNSName, nil
+	}
+
+	loadBalancer, err := awsClient.FindLoadBalancerV2(tags)
+	if err != nil {
+		return "", errors.Wrap(err, fmt.Sprintf("unable to locate %s load balancer", InternalAPILoadBalancer.String()))
+	}
+	if loadBalancer == nil || loadBalancer.DNSName == nil {
+		return "", nil
+	}
+	return *loadBalancer.DNSName, nil
+}
+
 // Will return error if the load balancer can't be found
 func getELBLoadBalancer(clusterName string, whichLB LoadBalancer, awsClient *awslib.Client) (*elb.LoadBalancerDescription, error) {
 	loadBalancer, err := awsClient.FindLoadBalancer(map[string]string{
@@ -21,19 +21,30 @@ import (
 	"strings"
 
 	"github.com/cortexlabs/cortex/cli/cluster"
+	"github.com/cortexlabs/cortex/cli/types/cliconfig"
 	"github.com/cortexlabs/cortex/cli/types/flags"
 	"github.com/cortexlabs/cortex/pkg/lib/exit"
 	libjson "github.com/cortexlabs/cortex/pkg/lib/json"
+	"github.com/cortexlabs/cortex/pkg/lib/pointer"
 	"github.com/cortexlabs/cortex/pkg/lib/print"
+	"github.com/cortexlabs/cortex/pkg/lib/prompt"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	"github.com/cortexlabs/cortex/pkg/lib/table"
 	"github.com/cortexlabs/cortex/pkg/lib/telemetry"
 	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
 	"github.com/spf13/cobra"
 )
 
 var (
-	_flagDeleteEnv       string
-	_flagDeleteKeepCache bool
-	_flagDeleteForce     bool
+	_flagDeleteEnv         string
+	_flagDeleteKeepCache   bool
+	_flagDeleteKeepVolumes bool
+	_flagDeleteForce       bool
+	_flagDeleteAll         bool
+	_flagDeleteKind        string
+	_flagDeleteSelector    string
+	_flagDeleteDryRun      bool
 )
 
 func deleteInit() {
@@ -42,13 +53,18 @@ func deleteInit() {
 
 	_deleteCmd.Flags().BoolVarP(&_flagDeleteForce, "force", "f", false, "delete the api without confirmation")
 	_deleteCmd.Flags().BoolVarP(&_flagDeleteKeepCache, "keep-cache", "c", false, "keep cached data for the api")
+	_deleteCmd.Flags().BoolVar(&_flagDeleteKeepVolumes, "keep-volumes", true, "keep the api's model in the model caches of the cluster's nodes, where it's evicted once the space is needed (use --keep-volumes=false to remove it immediately)")
+	_deleteCmd.Flags().BoolVarP(&_flagDeleteAll, "all", "a", false, "delete all apis")
+	_deleteCmd.Flags().StringVarP(&_flagDeleteKind, "kind", "k", "", fmt.Sprintf("delete all apis of the specified kind: one of %s", strings.Join(userconfig.KindStrings(), "|")))
+	_deleteCmd.Flags().StringVarP(&_flagDeleteSelector, "selector", "l", "", "delete all apis which match the label selector (e.g. apiKind=RealtimeAPI,apiName!=my-api)")
+	_deleteCmd.Flags().BoolVar(&_flagDeleteDryRun, "dry-run", false, "list the apis which would be deleted without deleting them")
 	_deleteCmd.Flags().VarP(&_flagOutput, "output", "o", fmt.Sprintf("output format: one of %s", strings.Join(flags.OutputTypeStringsExcluding(flags.YAMLOutputType), "|")))
 }
 
 var _deleteCmd = &cobra.Command{
-	Use:   "delete API_NAME [JOB_ID]",
-	Short: "delete an api or stop a job",
-	Args:  cobra.RangeArgs(1, 2),
+	Use:   "delete [API_NAME] [JOB_ID]",
+	Short: "delete an api, stop a job, or delete multiple apis",
+	Args:  cobra.RangeArgs(0, 2),
 	Run: func(cmd *cobra.Command, args []string) {
 		envName, err := getEnvFromFlag(_flagDeleteEnv)
 		if err != nil {
@@ -63,11 +79,32 @@ var _deleteCmd = &cobra.Command{
 		}
 		telemetry.Event("cli.delete", map[string]interface{}{"env_name": env.Name})
 
+		isBulkDelete := _flagDeleteAll || _flagDeleteKind != "" || _flagDeleteSelector != ""
+		if len(args) == 0 && !isBulkDelete {
+			exit.Error(ErrorDeleteTargetRequired())
+		}
+		if len(args) > 0 && isBulkDelete {
+			exit.Error(ErrorDeleteTargetConflict())
+		}
+		if len(args) == 2 && _flagDeleteDryRun {
+			exit.Error(ErrorMutuallyExclusiveFlags("--dry-run", "JOB_ID"))
+		}
+
 		err = printEnvIfNotSpecified(env.Name, cmd)
 		if err != nil {
 			exit.Error(err)
 		}
 
+		if isBulkDelete {
+			deleteAPIs(env, _flagDeleteAll, _flagDeleteKind, _flagDeleteSelector)
+			return
+		}
+
+		if _flagDeleteDryRun {
+			deleteAPIs(env, false, "", "apiName="+args[0])
+			return
+		}
+
 		var deleteResponse schema.DeleteResponse
 		if len(args) == 2 {
 			apisRes, err := cluster.GetAPI(MustGetOperatorConfig(env.Name), args[0])
@@ -80,7 +117,7 @@ var _deleteCmd = &cobra.Command{
 				exit.Error(err)
 			}
 		} else {
-			deleteResponse, err = cluster.Delete(MustGetOperatorConfig(env.Name), args[0], _flagDeleteKeepCache, _flagDeleteForce)
+			deleteResponse, err = cluster.Delete(MustGetOperatorConfig(env.Name), args[0], _flagDeleteKeepCache, _flagDeleteKeepVolumes, _flagDeleteForce)
 			if err != nil {
 				exit.Error(err)
 			}
@@ -98,3 +135,81 @@ var _deleteCmd = &cobra.Command{
 		print.BoldFirstLine(deleteResponse.Message)
 	},
 }
+
+func deleteAPIs(env cliconfig.Environment, all bool, kind string, selector string) {
+	operatorConfig := MustGetOperatorConfig(env.Name)
+
+	// list the matching apis first so that the user can confirm exactly what will be deleted;
+	// only the confirmed apis are deleted, even if more apis match the filters by the time the deletion is requested
+	var apiNames []string
+	if !_flagDeleteDryRun && !_flagDeleteForce {
+		dryRunResponse, err := cluster.DeleteAPIs(operatorConfig, all, kind, selector, nil, _flagDeleteKeepCache, _flagDeleteKeepVolumes, true)
+		if err != nil {
+			exit.Error(err)
+		}
+		if len(dryRunResponse.APIs) == 0 {
+			fmt.Println("no apis matched the provided filters")
+			return
+		}
+
+		apiNames = make([]string, len(dryRunResponse.APIs))
+		for i, result := range dryRunResponse.APIs {
+			apiNames[i] = result.Name
+		}
+		prompt.YesOrExit(fmt.Sprintf("are you sure you want to delete the following %s: %s?", s.PluralS("api", len(apiNames)), s.StrsAnd(apiNames)), "", "")
+	}
+
+	deleteResponse, err := cluster.DeleteAPIs(operatorConfig, all, kind, selector, apiNames, _flagDeleteKeepCache, _flagDeleteKeepVolumes, _flagDeleteDryRun)
+	if err != nil {
+		exit.Error(err)
+	}
+
+	var failedAPIs []string
+	for _, result := range deleteResponse.APIs {
+		if result.Error != "" {
+			failedAPIs = append(failedAPIs, result.Name)
+		}
+	}
+
+	if _flagOutput == flags.JSONOutputType {
+		bytes, err := libjson.Marshal(deleteResponse)
+		if err != nil {
+			exit.Error(err)
+		}
+		fmt.Print(string(bytes))
+	} else {
+		fmt.Print(bulkDeleteStr(deleteResponse))
+	}
+
+	if len(failedAPIs) > 0 {
+		exit.Error(ErrorFailedToDeleteAPIs(failedAPIs))
+	}
+}
+
+func bulkDeleteStr(deleteResponse schema.BulkDeleteResponse) string {
+	if len(deleteResponse.APIs) == 0 {
+		return "no apis matched the provided filters\n"
+	}
+
+	t := table.Table{
+		Headers: []table.Header{
+			{Title: "name"},
+			{Title: "kind"},
+			{Title: "result", Hidden: deleteResponse.DryRun},
+		},
+	}
+
+	for _, result := range deleteResponse.APIs {
+		resultStr := result.Message
+		if result.Error != "" {
+			resultStr = "error: " + result.Error
+		}
+		t.Rows = append(t.Rows, []interface{}{result.Name, result.Kind.String(), resultStr})
+	}
+
+	out := ""
+	if deleteResponse.DryRun {
+		out += fmt.Sprintf("the following %s would be deleted (dry run):\n\n", s.PluralS("api", len(deleteResponse.APIs)))
+	}
+	return out + t.MustFormat(&table.Opts{Sort: pointer.Bool(false)}) + "\n"
+}
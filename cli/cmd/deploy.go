@@ -26,8 +26,10 @@ import (
 	"github.com/cortexlabs/cortex/pkg/lib/files"
 	libjson "github.com/cortexlabs/cortex/pkg/lib/json"
 	"github.com/cortexlabs/cortex/pkg/lib/print"
+	"github.com/cortexlabs/cortex/pkg/lib/prompt"
 	"github.com/cortexlabs/cortex/pkg/lib/telemetry"
 	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
 	"github.com/spf13/cobra"
 )
 
@@ -42,6 +44,14 @@ var (
 	_flagDeployEnv            string
 	_flagDeployForce          bool
 	_flagDeployDisallowPrompt bool
+	_flagDeployDiff           bool
+	_flagDeploySkipScan       bool
+	_flagDeployLocal          bool
+	_flagDeployLocalAPI       string
+	_flagDeployLocalPort      int32
+
+	_flagConfigVars    []string
+	_flagConfigVarFile string
 )
 
 func deployInit() {
@@ -49,14 +59,25 @@ func deployInit() {
 	_deployCmd.Flags().StringVarP(&_flagDeployEnv, "env", "e", "", "environment to use")
 	_deployCmd.Flags().BoolVarP(&_flagDeployForce, "force", "f", false, "override the in-progress api update")
 	_deployCmd.Flags().BoolVarP(&_flagDeployDisallowPrompt, "yes", "y", false, "skip prompts")
+	_deployCmd.Flags().BoolVar(&_flagDeployDiff, "diff", false, "show the changes that will be made and prompt for confirmation before deploying")
+	_deployCmd.Flags().BoolVar(&_flagDeploySkipScan, "skip-scan", false, "deploy without checking the images' vulnerability scan results")
+	addConfigVarFlags(_deployCmd)
 	_deployCmd.Flags().VarP(&_flagOutput, "output", "o", fmt.Sprintf("output format: one of %s", strings.Join(flags.OutputTypeStringsExcluding(flags.YAMLOutputType), "|")))
+	_deployCmd.Flags().BoolVar(&_flagDeployLocal, "local", false, "run the api's containers locally with docker instead of deploying it to the cluster")
+	_deployCmd.Flags().StringVar(&_flagDeployLocalAPI, "local-api", "", "name of the api to run locally (required if the config file contains multiple apis)")
+	_deployCmd.Flags().Int32Var(&_flagDeployLocalPort, "local-port", 0, "local port on which to serve the api (defaults to the api's pod port)")
 }
 
 var _deployCmd = &cobra.Command{
-	Use:   "deploy [CONFIG_FILE]",
+	Use:   "deploy [CONFIG_FILE | GIT_REFERENCE]",
 	Short: "create or update apis",
 	Args:  cobra.RangeArgs(0, 1),
 	Run: func(cmd *cobra.Command, args []string) {
+		if _flagDeployLocal {
+			deployLocal(cmd, args)
+			return
+		}
+
 		envName, err := getEnvFromFlag(_flagDeployEnv)
 		if err != nil {
 			telemetry.Event("cli.deploy")
@@ -70,12 +91,16 @@ var _deployCmd = &cobra.Command{
 		}
 		telemetry.Event("cli.deploy", map[string]interface{}{"env_name": env.Name})
 
+		if _flagDeployDiff && _flagOutput == flags.JSONOutputType {
+			exit.Error(ErrorMutuallyExclusiveFlags("--diff", "--output json"))
+		}
+
 		err = printEnvIfNotSpecified(env.Name, cmd)
 		if err != nil {
 			exit.Error(err)
 		}
 
-		configPath := getConfigPath(args)
+		configPath, gitSource := getConfigSource(args)
 
 		projectRoot := files.Dir(configPath)
 		if projectRoot == _homeDir {
@@ -90,7 +115,11 @@ var _deployCmd = &cobra.Command{
 			exit.Error(err)
 		}
 
-		deployResults, err := cluster.Deploy(MustGetOperatorConfig(env.Name), configPath, deploymentBytes, _flagDeployForce)
+		if _flagDeployDiff {
+			confirmDeployDiff(env.Name, configPath, deploymentBytes)
+		}
+
+		deployResults, err := cluster.Deploy(MustGetOperatorConfig(env.Name), configPath, deploymentBytes, _flagDeployForce, _flagDeploySkipScan, gitSource)
 		if err != nil {
 			exit.Error(err)
 		}
@@ -117,6 +146,48 @@ var _deployCmd = &cobra.Command{
 	},
 }
 
+func deployLocal(cmd *cobra.Command, args []string) {
+	telemetry.Event("cli.deploy", map[string]interface{}{"local": true})
+
+	for _, flagName := range []string{"env", "force", "diff", "skip-scan", "output"} {
+		if cmd.Flags().Changed(flagName) {
+			exit.Error(ErrorMutuallyExclusiveFlags("--local", "--"+flagName))
+		}
+	}
+
+	configPath, _ := getConfigSource(args)
+
+	configBytes, err := readConfigBytes(configPath)
+	if err != nil {
+		exit.Error(err)
+	}
+
+	err = runLocal(configPath, configBytes, _flagDeployLocalAPI, _flagDeployLocalPort)
+	if err != nil {
+		exit.Error(err)
+	}
+}
+
+// getConfigSource returns the absolute path of the config file; if a git reference was provided (e.g. github.com/org/repo//path?ref=sha),
+// it is checked out first, and the returned git source identifies the commit
+func getConfigSource(args []string) (string, *userconfig.GitSource) {
+	if len(args) > 0 && !files.IsFile(args[0]) {
+		gitSource, isGitReference, err := parseGitReference(args[0])
+		if err != nil {
+			exit.Error(err)
+		}
+		if isGitReference {
+			configPath, err := checkoutGitSource(gitSource)
+			if err != nil {
+				exit.Error(err)
+			}
+			return configPath, gitSource
+		}
+	}
+
+	return getConfigPath(args), nil
+}
+
 // Returns absolute path
 func getConfigPath(args []string) string {
 	var configPath string
@@ -137,7 +208,7 @@ func getConfigPath(args []string) string {
 }
 
 func getDeploymentBytes(configPath string) (map[string][]byte, error) {
-	configBytes, err := files.ReadFileBytes(configPath)
+	configBytes, err := readConfigBytes(configPath)
 	if err != nil {
 		return nil, err
 	}
@@ -149,6 +220,27 @@ func getDeploymentBytes(configPath string) (map[string][]byte, error) {
 	return uploadBytes, nil
 }
 
+func confirmDeployDiff(envName string, configPath string, deploymentBytes map[string][]byte) {
+	diffResults, err := cluster.Diff(MustGetOperatorConfig(envName), configPath, deploymentBytes)
+	if err != nil {
+		exit.Error(err)
+	}
+
+	fmt.Print(diffResultsStr(diffResults))
+
+	if didAnyDiffResultsError(diffResults) {
+		exit.Error(nil)
+	}
+
+	if !didAnyDiffResultsChange(diffResults) {
+		exit.Ok()
+	}
+
+	if !_flagDeployDisallowPrompt {
+		prompt.YesOrExit("would you like to apply these changes?", "", "")
+	}
+}
+
 func mergeResultMessages(results []schema.DeployResult) string {
 	var okMessages []string
 	var errMessages []string
@@ -174,9 +266,56 @@ func mergeResultMessages(results []schema.DeployResult) string {
 		output += strings.Join(errMessages, "\n")
 	}
 
+	if imageScanMessage := imageScanResultsMessage(results); imageScanMessage != "" {
+		output += "\n\n" + imageScanMessage
+	}
+
 	return output
 }
 
+func imageScanResultsMessage(results []schema.DeployResult) string {
+	var lines []string
+	var warnings []string
+
+	for _, result := range results {
+		for _, imageScan := range result.ImageScans {
+			if imageScan.Status == "complete" {
+				lines = append(lines, fmt.Sprintf("%s: %s", imageScan.Image, severityCountsStr(imageScan.SeverityCounts)))
+			} else {
+				lines = append(lines, fmt.Sprintf("%s: %s", imageScan.Image, strings.ReplaceAll(imageScan.Status, "_", " ")))
+			}
+
+			if imageScan.Warning != "" {
+				warnings = append(warnings, fmt.Sprintf("warning: %s: %s", imageScan.Image, imageScan.Warning))
+			}
+		}
+	}
+
+	if len(lines) == 0 {
+		return ""
+	}
+
+	output := "image scan results:\n  " + strings.Join(lines, "\n  ")
+	if len(warnings) > 0 {
+		output += "\n\n" + strings.Join(warnings, "\n")
+	}
+
+	return output
+}
+
+func severityCountsStr(severityCounts map[string]int64) string {
+	var strs []string
+	for _, severity := range []string{"critical", "high", "medium", "low", "informational", "undefined"} {
+		if count, ok := severityCounts[severity]; ok && count > 0 {
+			strs = append(strs, fmt.Sprintf("%d %s", count, severity))
+		}
+	}
+	if len(strs) == 0 {
+		return "no vulnerabilities found"
+	}
+	return strings.Join(strs, ", ")
+}
+
 func didAllResultsError(results []schema.DeployResult) bool {
 	for _, result := range results {
 		if result.Error == "" {
@@ -31,6 +31,11 @@ import (
 const (
 	_titleReplicaStatus = "replica status"
 	_titleReplicaCount  = "replica count"
+	_titleEventLastSeen = "last seen"
+	_titleEventType     = "type"
+	_titleEventReason   = "reason"
+	_titleEventObject   = "object"
+	_titleEventMessage  = "message"
 )
 
 var (
@@ -0,0 +1,163 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cortexlabs/cortex/cli/cluster"
+	"github.com/cortexlabs/cortex/cli/types/flags"
+	"github.com/cortexlabs/cortex/pkg/lib/console"
+	"github.com/cortexlabs/cortex/pkg/lib/exit"
+	libjson "github.com/cortexlabs/cortex/pkg/lib/json"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	"github.com/cortexlabs/cortex/pkg/lib/structs"
+	"github.com/cortexlabs/cortex/pkg/lib/telemetry"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+	"github.com/spf13/cobra"
+)
+
+var _flagDiffEnv string
+
+func diffInit() {
+	_diffCmd.Flags().SortFlags = false
+	_diffCmd.Flags().StringVarP(&_flagDiffEnv, "env", "e", "", "environment to use")
+	addConfigVarFlags(_diffCmd)
+	_diffCmd.Flags().VarP(&_flagOutput, "output", "o", fmt.Sprintf("output format: one of %s", strings.Join(flags.OutputTypeStringsExcluding(flags.YAMLOutputType), "|")))
+}
+
+var _diffCmd = &cobra.Command{
+	Use:   "diff [CONFIG_FILE | GIT_REFERENCE]",
+	Short: "show the changes that deploying an api configuration would make",
+	Args:  cobra.RangeArgs(0, 1),
+	Run: func(cmd *cobra.Command, args []string) {
+		envName, err := getEnvFromFlag(_flagDiffEnv)
+		if err != nil {
+			telemetry.Event("cli.diff")
+			exit.Error(err)
+		}
+
+		env, err := ReadOrConfigureEnv(envName)
+		if err != nil {
+			telemetry.Event("cli.diff")
+			exit.Error(err)
+		}
+		telemetry.Event("cli.diff", map[string]interface{}{"env_name": env.Name})
+
+		err = printEnvIfNotSpecified(env.Name, cmd)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		configPath, _ := getConfigSource(args)
+
+		deploymentBytes, err := getDeploymentBytes(configPath)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		diffResults, err := cluster.Diff(MustGetOperatorConfig(env.Name), configPath, deploymentBytes)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		switch _flagOutput {
+		case flags.JSONOutputType:
+			bytes, err := libjson.Marshal(diffResults)
+			if err != nil {
+				exit.Error(err)
+			}
+			fmt.Print(string(bytes))
+		case flags.PrettyOutputType:
+			fmt.Print(diffResultsStr(diffResults))
+		}
+
+		if didAnyDiffResultsError(diffResults) {
+			exit.Error(nil)
+		}
+	},
+}
+
+func diffResultsStr(results []schema.DiffResult) string {
+	var out string
+
+	for _, result := range results {
+		resource := userconfig.Resource{Name: result.Name, Kind: result.Kind}
+
+		if result.Error != "" {
+			out += fmt.Sprintf("%s %s\n%s\n\n", console.Red("x"), console.Bold(resource.UserString()), s.Indent(result.Error, "  "))
+			continue
+		}
+
+		switch result.Action {
+		case schema.DiffActionCreate:
+			out += fmt.Sprintf("%s %s will be created\n", console.Green("+"), console.Bold(resource.UserString()))
+		case schema.DiffActionNone:
+			out += fmt.Sprintf("  %s is up to date\n", console.Bold(resource.UserString()))
+		case schema.DiffActionUpdate:
+			out += fmt.Sprintf("%s %s will be updated\n", console.Yellow("~"), console.Bold(resource.UserString()))
+			for _, change := range result.Changes {
+				out += "    " + changeStr(change) + "\n"
+			}
+			if result.Impact != "" {
+				out += "  " + result.Impact + "\n"
+			}
+		}
+		out += "\n"
+	}
+
+	return out
+}
+
+func changeStr(change structs.Change) string {
+	switch change.Type {
+	case structs.ChangeAdded:
+		return console.Green(fmt.Sprintf("+ %s: %s", change.Path, changeValueStr(change.New)))
+	case structs.ChangeRemoved:
+		return console.Red(fmt.Sprintf("- %s: %s", change.Path, changeValueStr(change.Old)))
+	default:
+		return console.Yellow(fmt.Sprintf("~ %s: %s → %s", change.Path, changeValueStr(change.Old), changeValueStr(change.New)))
+	}
+}
+
+func changeValueStr(value interface{}) string {
+	valueStr, err := libjson.MarshalJSONStr(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return valueStr
+}
+
+func didAnyDiffResultsError(results []schema.DiffResult) bool {
+	for _, result := range results {
+		if result.Error != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func didAnyDiffResultsChange(results []schema.DiffResult) bool {
+	for _, result := range results {
+		if result.Action == schema.DiffActionCreate || result.Action == schema.DiffActionUpdate {
+			return true
+		}
+	}
+	return false
+}
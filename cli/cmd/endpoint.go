@@ -0,0 +1,226 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/cortexlabs/cortex/cli/cluster"
+	"github.com/cortexlabs/cortex/pkg/consts"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/exit"
+	"github.com/cortexlabs/cortex/pkg/lib/telemetry"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+var (
+	_flagEndpointTestEnv     string
+	_flagEndpointTestData    string
+	_flagEndpointTestHeaders []string
+	_flagEndpointTestTimeout time.Duration
+)
+
+func endpointInit() {
+	_endpointTestCmd.Flags().SortFlags = false
+	_endpointTestCmd.Flags().StringVarP(&_flagEndpointTestEnv, "env", "e", "", "environment to use")
+	_endpointTestCmd.Flags().StringVarP(&_flagEndpointTestData, "data", "d", "", "request body; for grpc apis, the json encoding of the request message (default: {})")
+	_endpointTestCmd.Flags().StringArrayVarP(&_flagEndpointTestHeaders, "header", "H", nil, "header (or grpc metadata) to include in the request, e.g. \"X-Api-Key: <key>\" (can be specified multiple times)")
+	_endpointTestCmd.Flags().DurationVar(&_flagEndpointTestTimeout, "timeout", time.Minute, "maximum amount of time to wait for the response")
+	_endpointCmd.AddCommand(_endpointTestCmd)
+}
+
+var _endpointCmd = &cobra.Command{
+	Use:   "endpoint",
+	Short: "send requests to apis (contains subcommands)",
+}
+
+var _endpointTestCmd = &cobra.Command{
+	Use:   "test API_NAME [GRPC_METHOD]",
+	Short: "send a request to a realtime api; for grpc apis, list the services via server reflection, or call GRPC_METHOD (e.g. package.Service/Method)",
+	Args:  cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		envName, err := getEnvFromFlag(_flagEndpointTestEnv)
+		if err != nil {
+			telemetry.Event("cli.endpoint.test")
+			exit.Error(err)
+		}
+
+		env, err := ReadOrConfigureEnv(envName)
+		if err != nil {
+			telemetry.Event("cli.endpoint.test")
+			exit.Error(err)
+		}
+		telemetry.Event("cli.endpoint.test", map[string]interface{}{"env_name": env.Name, "grpc_method": len(args) == 2})
+
+		err = printEnvIfNotSpecified(env.Name, cmd)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		apiName := args[0]
+		apisRes, err := cluster.GetAPI(MustGetOperatorConfig(env.Name), apiName)
+		if err != nil {
+			exit.Error(err)
+		}
+		apiRes := apisRes[0]
+		if apiRes.Metadata.Kind != userconfig.RealtimeAPIKind {
+			exit.Error(ErrorEndpointTestNotSupportedForKind(apiName, apiRes.Metadata.Kind))
+		}
+		if apiRes.Endpoint == nil {
+			exit.Error(errors.ErrorUnexpected("missing endpoint from operator response"))
+		}
+
+		headers, err := parseEndpointTestHeaders()
+		if err != nil {
+			exit.Error(err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), _flagEndpointTestTimeout)
+		defer cancel()
+
+		if apiRes.Spec != nil && apiRes.Spec.Pod != nil && apiRes.Spec.Pod.Protocol == userconfig.ProtocolGRPC {
+			var grpcMethod string
+			if len(args) == 2 {
+				grpcMethod = args[1]
+			}
+			err = testGRPCEndpoint(ctx, apiRes, grpcMethod, headers)
+		} else {
+			if len(args) == 2 {
+				exit.Error(ErrorGRPCMethodRequiresGRPCAPI(apiName))
+			}
+			err = testHTTPEndpoint(ctx, apiRes, headers)
+		}
+		if err != nil {
+			exit.Error(err)
+		}
+	},
+}
+
+func parseEndpointTestHeaders() (map[string]string, error) {
+	headers := map[string]string{}
+	for _, header := range _flagEndpointTestHeaders {
+		split := strings.SplitN(header, ":", 2)
+		if len(split) != 2 || strings.TrimSpace(split[0]) == "" {
+			return nil, ErrorInvalidHeaderFlag(header)
+		}
+		headers[strings.TrimSpace(split[0])] = strings.TrimSpace(split[1])
+	}
+	return headers, nil
+}
+
+func testHTTPEndpoint(ctx context.Context, apiRes schema.APIResponse, headers map[string]string) error {
+	method := http.MethodGet
+	var body io.Reader
+	if _flagEndpointTestData != "" {
+		method = http.MethodPost
+		body = strings.NewReader(_flagEndpointTestData)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, *apiRes.Endpoint, body)
+	if err != nil {
+		return errors.Wrap(err, *apiRes.Endpoint)
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return errors.Wrap(err, *apiRes.Endpoint)
+	}
+	defer res.Body.Close()
+
+	resBody, err := io.ReadAll(res.Body)
+	if err != nil {
+		return errors.Wrap(err, *apiRes.Endpoint)
+	}
+
+	fmt.Printf("%s %s\n", res.Proto, res.Status)
+	fmt.Println(strings.TrimSpace(string(resBody)))
+	return nil
+}
+
+func testGRPCEndpoint(ctx context.Context, apiRes schema.APIResponse, grpcMethod string, headers map[string]string) error {
+	conn, err := dialGRPCEndpoint(*apiRes.Endpoint)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	// grpc requests are routed to the api by its name in their metadata
+	md := metadata.Pairs(strings.ToLower(consts.CortexAPINameHeader), apiRes.Spec.Name)
+	for key, value := range headers {
+		md.Set(key, value)
+	}
+	ctx = metadata.NewOutgoingContext(ctx, md)
+
+	reflectionClient, err := newGRPCReflectionClient(ctx, conn)
+	if err != nil {
+		return err
+	}
+	defer reflectionClient.Close()
+
+	if grpcMethod == "" {
+		serviceNames, err := reflectionClient.ListServices()
+		if err != nil {
+			return err
+		}
+		for _, serviceName := range serviceNames {
+			serviceDescriptor, err := reflectionClient.ServiceDescriptor(serviceName)
+			if err != nil {
+				return err
+			}
+			fmt.Println(serviceName)
+			methods := serviceDescriptor.Methods()
+			for i := 0; i < methods.Len(); i++ {
+				fmt.Println("  " + grpcMethodStr(methods.Get(i)))
+			}
+		}
+		return nil
+	}
+
+	serviceName, methodName, err := splitGRPCMethod(grpcMethod)
+	if err != nil {
+		return err
+	}
+
+	serviceDescriptor, err := reflectionClient.ServiceDescriptor(serviceName)
+	if err != nil {
+		return err
+	}
+
+	methodDescriptor := serviceDescriptor.Methods().ByName(protoreflect.Name(methodName))
+	if methodDescriptor == nil {
+		return ErrorGRPCMethodNotFound(methodName, serviceName)
+	}
+
+	data := _flagEndpointTestData
+	if data == "" {
+		data = "{}"
+	}
+
+	return invokeGRPCMethod(ctx, conn, methodDescriptor, data)
+}
@@ -22,20 +22,26 @@ import (
 
 	"github.com/cortexlabs/cortex/cli/types/cliconfig"
 	"github.com/cortexlabs/cortex/cli/types/flags"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
 	"github.com/cortexlabs/cortex/pkg/lib/exit"
 	libjson "github.com/cortexlabs/cortex/pkg/lib/json"
 	"github.com/cortexlabs/cortex/pkg/lib/print"
 	"github.com/cortexlabs/cortex/pkg/lib/telemetry"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
 	"github.com/spf13/cobra"
 )
 
 var (
 	_flagEnvOperatorEndpoint string
+	_flagEnvProject          string
+	_flagEnvAWSProfile       string
 )
 
 func envInit() {
 	_envConfigureCmd.Flags().SortFlags = false
 	_envConfigureCmd.Flags().StringVarP(&_flagEnvOperatorEndpoint, "operator-endpoint", "o", "", "set the operator endpoint without prompting")
+	_envConfigureCmd.Flags().StringVarP(&_flagEnvProject, "project", "p", "", "set the project which apis are deployed to and listed from (an empty value clears it)")
+	_envConfigureCmd.Flags().StringVar(&_flagEnvAWSProfile, "profile", "", "set the aws profile which is used to authenticate with the operator (an empty value clears it)")
 	_envCmd.AddCommand(_envConfigureCmd)
 
 	_envListCmd.Flags().SortFlags = false
@@ -78,7 +84,25 @@ var _envConfigureCmd = &cobra.Command{
 			fieldsToSkipPrompt.OperatorEndpoint = operatorEndpoint
 		}
 
-		if _, err := configureEnv(envName, fieldsToSkipPrompt); err != nil {
+		var project *string
+		if wasFlagProvided(cmd, "project") {
+			if err := userconfig.ValidateProject(_flagEnvProject); err != nil {
+				exit.Error(errors.Wrap(err, "--project"))
+			}
+			project = &_flagEnvProject
+		}
+
+		var awsProfile *string
+		if wasFlagProvided(cmd, "profile") {
+			if _flagEnvAWSProfile != "" {
+				if err := validateAWSProfile(_flagEnvAWSProfile); err != nil {
+					exit.Error(errors.Wrap(err, "--profile"))
+				}
+			}
+			awsProfile = &_flagEnvAWSProfile
+		}
+
+		if _, err := configureEnv(envName, fieldsToSkipPrompt, project, awsProfile); err != nil {
 			exit.Error(err)
 		}
 	},
@@ -20,12 +20,16 @@ import (
 	"fmt"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/cortexlabs/cortex/pkg/consts"
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
 	s "github.com/cortexlabs/cortex/pkg/lib/strings"
 	"github.com/cortexlabs/cortex/pkg/lib/urls"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
 	"github.com/cortexlabs/cortex/pkg/types/clusterconfig"
+	"github.com/cortexlabs/cortex/pkg/types/status"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
 )
 
 const (
@@ -53,8 +57,18 @@ const (
 	ErrCredentialsInClusterConfig          = "cli.credentials_in_cluster_config"
 	ErrClusterUp                           = "cli.cluster_up"
 	ErrClusterConfigure                    = "cli.cluster_configure"
+	ErrClusterScale                        = "cli.cluster_scale"
+	ErrClusterPause                        = "cli.cluster_pause"
+	ErrClusterResume                       = "cli.cluster_resume"
+	ErrClusterUpgrade                      = "cli.cluster_upgrade"
+	ErrClusterNewerThanCLI                 = "cli.cluster_newer_than_cli"
+	ErrUnsupportedUpgradeVersionSkew       = "cli.unsupported_upgrade_version_skew"
+	ErrJobsInProgressDuringUpgrade         = "cli.jobs_in_progress_during_upgrade"
+	ErrIncompatibleAPISpec                 = "cli.incompatible_api_spec"
+	ErrInvalidClusterExport                = "cli.invalid_cluster_export"
 	ErrClusterDebug                        = "cli.cluster_debug"
 	ErrClusterRefresh                      = "cli.cluster_refresh"
+	ErrClusterRender                       = "cli.cluster_render"
 	ErrClusterDown                         = "cli.cluster_down"
 	ErrSpecifyAtLeastOneFlag               = "cli.specify_at_least_one_flag"
 	ErrMinInstancesLowerThan               = "cli.min_instances_lower_than"
@@ -69,6 +83,42 @@ const (
 	ErrAPINameMustBeProvided               = "cli.api_name_must_be_provided"
 	ErrAPINotFoundInConfig                 = "cli.api_not_found_in_config"
 	ErrClusterUIDsLimitInBucket            = "cli.cluster_uids_limit_in_bucket"
+	ErrDeleteTargetRequired                = "cli.delete_target_required"
+	ErrDeleteTargetConflict                = "cli.delete_target_conflict"
+	ErrSelectorWithAPIName                 = "cli.selector_with_api_name"
+	ErrFailedToDeleteAPIs                  = "cli.failed_to_delete_apis"
+	ErrInvalidCostLookback                 = "cli.invalid_cost_lookback"
+	ErrMissingIAMPermissions               = "cli.missing_iam_permissions"
+	ErrInvalidJobWaitTimeout               = "cli.invalid_job_wait_timeout"
+	ErrJobWaitNotSupportedForKind          = "cli.job_wait_not_supported_for_kind"
+	ErrJobWaitInterrupted                  = "cli.job_wait_interrupted"
+	ErrJobWaitTimeout                      = "cli.job_wait_timeout"
+	ErrJobDidNotSucceed                    = "cli.job_did_not_succeed"
+	ErrRerunRequiresOnlyFailed             = "cli.rerun_requires_only_failed"
+	ErrEndpointTestNotSupportedForKind     = "cli.endpoint_test_not_supported_for_kind"
+	ErrGRPCMethodRequiresGRPCAPI           = "cli.grpc_method_requires_grpc_api"
+	ErrInvalidHeaderFlag                   = "cli.invalid_header_flag"
+	ErrInvalidGRPCMethod                   = "cli.invalid_grpc_method"
+	ErrGRPCServiceNotFound                 = "cli.grpc_service_not_found"
+	ErrGRPCMethodNotFound                  = "cli.grpc_method_not_found"
+	ErrGRPCClientStreamingNotSupported     = "cli.grpc_client_streaming_not_supported"
+	ErrGRPCReflection                      = "cli.grpc_reflection"
+	ErrFlagRequiresJobID                   = "cli.flag_requires_job_id"
+	ErrInvalidWorkersFlag                  = "cli.invalid_workers_flag"
+	ErrInvalidPortMapping                  = "cli.invalid_port_mapping"
+	ErrLocalAPIRequired                    = "cli.local_api_required"
+	ErrLocalAPINotFound                    = "cli.local_api_not_found"
+	ErrLocalKindNotSupported               = "cli.local_kind_not_supported"
+	ErrLocalContainersFailed               = "cli.local_containers_failed"
+	ErrInvalidConfigVar                    = "cli.invalid_config_var"
+	ErrInvalidConfigVarFileValue           = "cli.invalid_config_var_file_value"
+	ErrInvalidGitReference                 = "cli.invalid_git_reference"
+	ErrGitNotInstalled                     = "cli.git_not_installed"
+	ErrGitCommandFailed                    = "cli.git_command_failed"
+	ErrGitRefNotFound                      = "cli.git_ref_not_found"
+	ErrGitConfigFileNotFound               = "cli.git_config_file_not_found"
+	ErrMFATokenNotProvided                 = "cli.mfa_token_not_provided"
+	ErrNegativeFlagValue                   = "cli.negative_flag_value"
 )
 
 func ErrorInvalidProvider(providerStr, cliConfigPath string) error {
@@ -170,6 +220,73 @@ func ErrorClusterConfigure(out string) error {
 	})
 }
 
+func ErrorClusterScale(out string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrClusterScale,
+		Message: out,
+		NoPrint: true,
+	})
+}
+
+func ErrorClusterPause(out string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrClusterPause,
+		Message: out,
+		NoPrint: true,
+	})
+}
+
+func ErrorClusterResume(out string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrClusterResume,
+		Message: out,
+		NoPrint: true,
+	})
+}
+
+func ErrorClusterUpgrade(out string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrClusterUpgrade,
+		Message: out,
+		NoPrint: true,
+	})
+}
+
+func ErrorClusterNewerThanCLI(clusterVersion string, cliVersion string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrClusterNewerThanCLI,
+		Message: fmt.Sprintf("your cluster is running cortex %s, which is newer than your cli (%s); please upgrade your cli (pip install cortex==%s)", clusterVersion, cliVersion, clusterVersion),
+	})
+}
+
+func ErrorUnsupportedUpgradeVersionSkew(clusterVersion string, cliVersion string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrUnsupportedUpgradeVersionSkew,
+		Message: fmt.Sprintf("your cluster is running cortex %s, and can only be upgraded one minor version at a time (your cli is version %s); please upgrade using a cli whose minor version is one greater than your cluster's minor version first", clusterVersion, cliVersion),
+	})
+}
+
+func ErrorJobsInProgressDuringUpgrade(jobIDs []string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrJobsInProgressDuringUpgrade,
+		Message: fmt.Sprintf("the following %s in progress and would not survive the upgrade: %s; please wait for %s to finish (or stop %s) before upgrading", s.PluralCustom("job is", "jobs are", len(jobIDs)), s.StrsAnd(jobIDs), s.PluralCustom("it", "them", len(jobIDs)), s.PluralCustom("it", "them", len(jobIDs))),
+	})
+}
+
+func ErrorIncompatibleAPISpec(apiName string, apiID string, err error) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrIncompatibleAPISpec,
+		Message: fmt.Sprintf("the spec of api %s (api id %s) is not compatible with cortex %s: %s; please delete the api before upgrading, and re-deploy it afterwards", apiName, apiID, consts.CortexVersion, errors.Message(err)),
+	})
+}
+
+func ErrorInvalidClusterExport(exportPath string, reason string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrInvalidClusterExport,
+		Message: fmt.Sprintf("%s is not a valid cluster export (cluster exports can be created with `cortex cluster export`): %s", exportPath, reason),
+	})
+}
+
 func ErrorClusterDebug(out string) error {
 	return errors.WithStack(&errors.Error{
 		Kind:    ErrClusterDebug,
@@ -178,6 +295,14 @@ func ErrorClusterDebug(out string) error {
 	})
 }
 
+func ErrorClusterRender(out string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrClusterRender,
+		Message: out,
+		NoPrint: true,
+	})
+}
+
 func ErrorClusterRefresh(out string) error {
 	return errors.WithStack(&errors.Error{
 		Kind:    ErrClusterRefresh,
@@ -290,3 +415,262 @@ func ErrorClusterUIDsLimitInBucket(bucket string) error {
 		Message: fmt.Sprintf("detected too many top level folders in %s bucket; please empty your bucket and try again", bucket),
 	})
 }
+
+func ErrorDeleteTargetRequired() error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrDeleteTargetRequired,
+		Message: "specify the name of the api to delete, or use the --all, --kind, or --selector flags to delete multiple apis",
+	})
+}
+
+func ErrorDeleteTargetConflict() error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrDeleteTargetConflict,
+		Message: "an api name cannot be specified when using the --all, --kind, or --selector flags",
+	})
+}
+
+func ErrorSelectorWithAPIName() error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrSelectorWithAPIName,
+		Message: "an api name cannot be specified when using the --selector flag",
+	})
+}
+
+func ErrorFailedToDeleteAPIs(apiNames []string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrFailedToDeleteAPIs,
+		Message: fmt.Sprintf("failed to delete %s %s", s.PluralS("api", len(apiNames)), s.StrsAnd(apiNames)),
+	})
+}
+
+func ErrorInvalidCostLookback(days int, maxDays int) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrInvalidCostLookback,
+		Message: fmt.Sprintf("--days must be between 1 and %d (got %d)", maxDays, days),
+	})
+}
+
+func ErrorMissingIAMPermissions(numMissing int, docsMsg string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrMissingIAMPermissions,
+		Message: fmt.Sprintf("your aws credentials are missing %d %s required to create a cluster; %s", numMissing, s.PluralS("permission", numMissing), docsMsg),
+	})
+}
+
+func ErrorInvalidJobWaitTimeout(timeout time.Duration) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrInvalidJobWaitTimeout,
+		Message: fmt.Sprintf("--timeout must be a positive duration (got %s)", timeout.String()),
+	})
+}
+
+func ErrorJobWaitNotSupportedForKind(apiName string, kind userconfig.Kind) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrJobWaitNotSupportedForKind,
+		Message: fmt.Sprintf("%s is a %s; waiting is only supported for jobs of %s and %s apis", apiName, kind.String(), userconfig.BatchAPIKind.String(), userconfig.TaskAPIKind.String()),
+	})
+}
+
+func ErrorJobWaitInterrupted(apiName string, jobID string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:        ErrJobWaitInterrupted,
+		Message:     fmt.Sprintf("\nstopped waiting for job %s (the job has not been stopped; to stop it, run `cortex delete %s %s`)", jobID, apiName, jobID),
+		NoTelemetry: true,
+	})
+}
+
+func ErrorJobWaitTimeout(jobID string, timeout time.Duration, jobStatus status.JobCode) error {
+	return errors.WithStack(&errors.Error{
+		Kind:        ErrJobWaitTimeout,
+		Message:     fmt.Sprintf("job %s did not complete within %s (its current status is %s)", jobID, timeout.String(), jobStatus.Message()),
+		NoTelemetry: true,
+	})
+}
+
+func ErrorJobDidNotSucceed(jobID string, jobStatus status.JobCode) error {
+	return errors.WithStack(&errors.Error{
+		Kind:        ErrJobDidNotSucceed,
+		Message:     fmt.Sprintf("job %s did not succeed (status: %s)", jobID, jobStatus.Message()),
+		NoTelemetry: true,
+	})
+}
+
+func ErrorRerunRequiresOnlyFailed() error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrRerunRequiresOnlyFailed,
+		Message: "only the failed batches of a job can be re-run (since the original job submission isn't retained); please specify the --only-failed flag",
+	})
+}
+
+func ErrorEndpointTestNotSupportedForKind(apiName string, kind userconfig.Kind) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrEndpointTestNotSupportedForKind,
+		Message: fmt.Sprintf("%s is a %s; testing endpoints is only supported for %ss", apiName, kind.String(), userconfig.RealtimeAPIKind.String()),
+	})
+}
+
+func ErrorGRPCMethodRequiresGRPCAPI(apiName string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrGRPCMethodRequiresGRPCAPI,
+		Message: fmt.Sprintf("a grpc method can only be specified for apis whose %s.%s is %s, but %s's is %s", userconfig.PodKey, userconfig.ProtocolKey, userconfig.ProtocolGRPC, apiName, userconfig.ProtocolHTTP),
+	})
+}
+
+func ErrorInvalidHeaderFlag(header string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrInvalidHeaderFlag,
+		Message: fmt.Sprintf("invalid header \"%s\"; headers must be formatted as \"Name: value\"", header),
+	})
+}
+
+func ErrorInvalidGRPCMethod(method string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrInvalidGRPCMethod,
+		Message: fmt.Sprintf("invalid grpc method \"%s\"; methods must be formatted as package.Service/Method", method),
+	})
+}
+
+func ErrorGRPCServiceNotFound(service string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrGRPCServiceNotFound,
+		Message: fmt.Sprintf("grpc service %s was not found on the server", service),
+	})
+}
+
+func ErrorGRPCMethodNotFound(method string, service string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrGRPCMethodNotFound,
+		Message: fmt.Sprintf("grpc service %s does not have a method named %s", service, method),
+	})
+}
+
+func ErrorGRPCClientStreamingNotSupported(method string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrGRPCClientStreamingNotSupported,
+		Message: fmt.Sprintf("%s is a client streaming method; only unary and server streaming methods can be tested", method),
+	})
+}
+
+func ErrorGRPCReflection(msg string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrGRPCReflection,
+		Message: fmt.Sprintf("unable to describe the api's grpc services via server reflection (the api's grpc server must register the reflection service): %s", msg),
+	})
+}
+
+func ErrorFlagRequiresJobID(flag string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrFlagRequiresJobID,
+		Message: fmt.Sprintf("the %s flag can only be used when a job id is specified (e.g. `cortex logs API_NAME JOB_ID %s`)", flag, flag),
+	})
+}
+
+func ErrorInvalidWorkersFlag(workers string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrInvalidWorkersFlag,
+		Message: fmt.Sprintf("invalid value for --workers (got %s, must be a positive integer or \"%s\")", s.UserStr(workers), schema.AutoWorkers),
+	})
+}
+
+func ErrorInvalidPortMapping(portMapping string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrInvalidPortMapping,
+		Message: fmt.Sprintf("invalid port mapping %s; specify REMOTE_PORT (e.g. 8888) to use the same local port, or LOCAL_PORT:REMOTE_PORT (e.g. 9999:8888), where each port is an integer between 1 and 65535", s.UserStr(portMapping)),
+	})
+}
+
+func ErrorLocalAPIRequired(configPath string, apiNames []string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrLocalAPIRequired,
+		Message: fmt.Sprintf("%s contains multiple apis (%s); specify the api to run locally with --local-api", configPath, s.StrsAnd(apiNames)),
+	})
+}
+
+func ErrorLocalAPINotFound(configPath string, apiName string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrLocalAPINotFound,
+		Message: fmt.Sprintf("api %s was not found in %s", s.UserStr(apiName), configPath),
+	})
+}
+
+func ErrorLocalKindNotSupported(kind userconfig.Kind) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrLocalKindNotSupported,
+		Message: fmt.Sprintf("%s apis can't be run locally (only apis which run containers can be run locally)", kind.String()),
+	})
+}
+
+func ErrorLocalContainersFailed(containers []string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrLocalContainersFailed,
+		Message: fmt.Sprintf("%s failed: %s", s.PluralS("container", len(containers)), strings.Join(containers, ", ")),
+	})
+}
+
+func ErrorInvalidConfigVar(configVar string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrInvalidConfigVar,
+		Message: fmt.Sprintf("invalid value for --var: %s (expected NAME=VALUE)", s.UserStr(configVar)),
+	})
+}
+
+func ErrorInvalidConfigVarFileValue(name string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrInvalidConfigVarFileValue,
+		Message: fmt.Sprintf("%s: value must be a string, number, or boolean", name),
+	})
+}
+
+func ErrorInvalidGitReference(reference string, reason string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrInvalidGitReference,
+		Message: fmt.Sprintf("invalid git reference %s: %s (expected [git::]REPOSITORY[//PATH][?ref=REF], e.g. github.com/org/repo//apis/cortex.yaml?ref=main)", s.UserStr(reference), reason),
+	})
+}
+
+func ErrorGitNotInstalled() error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrGitNotInstalled,
+		Message: "git must be installed to deploy from a git reference (see https://git-scm.com/downloads)",
+	})
+}
+
+func ErrorGitCommandFailed(args []string, output string) error {
+	message := fmt.Sprintf("git %s failed", strings.Join(args, " "))
+	if output != "" {
+		message += ":\n\n" + output
+	}
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrGitCommandFailed,
+		Message: message,
+	})
+}
+
+func ErrorGitRefNotFound(ref string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrGitRefNotFound,
+		Message: fmt.Sprintf("git ref %s was not found (it must be a branch, tag, or commit)", s.UserStr(ref)),
+	})
+}
+
+func ErrorGitConfigFileNotFound(path string, repository string, commit string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrGitConfigFileNotFound,
+		Message: fmt.Sprintf("%s does not exist in %s at commit %s", path, repository, commit),
+	})
+}
+
+func ErrorMFATokenNotProvided(mfaSerial string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrMFATokenNotProvided,
+		Message: fmt.Sprintf("the code of mfa device %s was not provided", mfaSerial),
+	})
+}
+
+func ErrorNegativeFlagValue(flag string, value int) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrNegativeFlagValue,
+		Message: fmt.Sprintf("%s must not be negative (got %d)", flag, value),
+	})
+}
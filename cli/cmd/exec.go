@@ -0,0 +1,101 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/cortexlabs/cortex/cli/cluster"
+	"github.com/cortexlabs/cortex/pkg/lib/exit"
+	"github.com/cortexlabs/cortex/pkg/lib/telemetry"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+var (
+	_flagExecEnv       string
+	_flagExecReplica   string
+	_flagExecContainer string
+)
+
+func execInit() {
+	_execCmd.Flags().SortFlags = false
+	_execCmd.Flags().StringVarP(&_flagExecEnv, "env", "e", "", "environment to use")
+	_execCmd.Flags().StringVar(&_flagExecReplica, "replica", "", "name (or name suffix) of the replica to run the command in (default: the first running replica)")
+	_execCmd.Flags().StringVarP(&_flagExecContainer, "container", "c", "", "name of the container to run the command in (default: the api's first container)")
+}
+
+var _execCmd = &cobra.Command{
+	Use:   "exec API_NAME [JOB_ID] [-- COMMAND [ARGS...]]",
+	Short: "run a command in a running replica (or job worker), or open a shell if no command is specified",
+	Args: func(cmd *cobra.Command, args []string) error {
+		positionalArgs, _ := splitExecArgs(cmd, args)
+		return cobra.RangeArgs(1, 2)(cmd, positionalArgs)
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		envName, err := getEnvFromFlag(_flagExecEnv)
+		if err != nil {
+			telemetry.Event("cli.exec")
+			exit.Error(err)
+		}
+
+		env, err := ReadOrConfigureEnv(envName)
+		if err != nil {
+			telemetry.Event("cli.exec")
+			exit.Error(err)
+		}
+
+		positionalArgs, command := splitExecArgs(cmd, args)
+
+		// a tty is only requested when the command is run interactively, so that the command's output can be piped
+		tty := term.IsTerminal(int(os.Stdin.Fd())) && term.IsTerminal(int(os.Stdout.Fd()))
+		telemetry.Event("cli.exec", map[string]interface{}{"env_name": env.Name, "tty": tty, "job": len(positionalArgs) == 2})
+
+		// the environment is printed to stderr so that it isn't mixed with the command's output
+		envStr, err := envStringIfNotSpecified(env.Name, cmd)
+		if err != nil {
+			exit.Error(err)
+		}
+		fmt.Fprint(os.Stderr, envStr)
+
+		execOptions := cluster.ExecOptions{
+			Replica:   _flagExecReplica,
+			Container: _flagExecContainer,
+			Command:   command,
+			TTY:       tty,
+		}
+		if len(positionalArgs) == 2 {
+			execOptions.JobID = positionalArgs[1]
+		}
+
+		exitCode, err := cluster.Exec(MustGetOperatorConfig(env.Name), positionalArgs[0], execOptions)
+		if err != nil {
+			exit.Error(err)
+		}
+		exit.Code(exitCode)
+	},
+}
+
+// splitExecArgs splits the args into the positional args and the command to run (the args after "--")
+func splitExecArgs(cmd *cobra.Command, args []string) ([]string, []string) {
+	dashIndex := cmd.ArgsLenAtDash()
+	if dashIndex < 0 {
+		return args, nil
+	}
+	return args[:dashIndex], args[dashIndex:]
+}
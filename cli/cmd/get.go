@@ -18,6 +18,7 @@ package cmd
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
@@ -49,14 +50,26 @@ const (
 )
 
 var (
-	_flagGetEnv   string
-	_flagGetWatch bool
+	_flagGetEnv      string
+	_flagGetWatch    bool
+	_flagGetSelector string
 )
 
+// the fields which are displayed in the tables of `cortex get` (the other fields are only retrieved for json and yaml output)
+var _getAPIsTableFields = []string{"metadata", "status", "num_traffic_splitter_targets", "batch_job_statuses", "task_job_statuses"}
+
+func getAPIsFields() []string {
+	if _flagOutput == flags.PrettyOutputType {
+		return _getAPIsTableFields
+	}
+	return nil
+}
+
 func getInit() {
 	_getCmd.Flags().SortFlags = false
 	_getCmd.Flags().StringVarP(&_flagGetEnv, "env", "e", "", "environment to use")
 	_getCmd.Flags().BoolVarP(&_flagGetWatch, "watch", "w", false, "re-run the command every 2 seconds")
+	_getCmd.Flags().StringVarP(&_flagGetSelector, "selector", "l", "", "only list the apis which match the label selector (e.g. team=nlp,env=prod)")
 	_getCmd.Flags().VarP(&_flagOutput, "output", "o", fmt.Sprintf("output format: one of %s", strings.Join(flags.OutputTypeStringsExcluding(flags.YAMLOutputType), "|")))
 	addVerboseFlag(_getCmd)
 }
@@ -66,6 +79,11 @@ var _getCmd = &cobra.Command{
 	Short: "get information about apis or jobs",
 	Args:  cobra.RangeArgs(0, 2),
 	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) > 0 && _flagGetSelector != "" {
+			telemetry.Event("cli.get")
+			exit.Error(ErrorSelectorWithAPIName())
+		}
+
 		var envName string
 		if wasFlagProvided(cmd, "env") {
 			envName = _flagGetEnv
@@ -211,7 +229,7 @@ func getAPIsInAllEnvironments() (string, error) {
 	errorsMap := map[string]error{}
 	// get apis from both environments
 	for _, env := range cliConfig.Environments {
-		apisRes, err := cluster.GetAPIs(MustGetOperatorConfig(env.Name))
+		apisRes, err := cluster.GetAPIs(MustGetOperatorConfig(env.Name), _flagGetSelector, getAPIsFields()...)
 
 		apisOutput := getAPIsOutput{
 			EnvName: env.Name,
@@ -265,7 +283,7 @@ func getAPIsInAllEnvironments() (string, error) {
 		// check if any environments errorred
 		if len(errorsMap) != len(cliConfig.Environments) {
 			if len(errorsMap) == 0 {
-				return console.Bold("no apis are deployed"), nil
+				return console.Bold(noAPIsDeployedMessage()), nil
 			}
 
 			var successfulEnvs []string
@@ -274,7 +292,7 @@ func getAPIsInAllEnvironments() (string, error) {
 					successfulEnvs = append(successfulEnvs, env.Name)
 				}
 			}
-			fmt.Println(console.Bold(fmt.Sprintf("no apis are deployed in %s: %s", s.PluralS("environment", len(successfulEnvs)), s.StrsAnd(successfulEnvs))) + "\n")
+			fmt.Println(console.Bold(fmt.Sprintf("%s in %s: %s", noAPIsDeployedMessage(), s.PluralS("environment", len(successfulEnvs)), s.StrsAnd(successfulEnvs))) + "\n")
 		}
 
 		// Print the first error
@@ -335,7 +353,7 @@ func getAPIsInAllEnvironments() (string, error) {
 }
 
 func getAPIsByEnv(env cliconfig.Environment) (string, error) {
-	apisRes, err := cluster.GetAPIs(MustGetOperatorConfig(env.Name))
+	apisRes, err := cluster.GetAPIs(MustGetOperatorConfig(env.Name), _flagGetSelector, getAPIsFields()...)
 	if err != nil {
 		return "", err
 	}
@@ -375,7 +393,7 @@ func getAPIsByEnv(env cliconfig.Environment) (string, error) {
 	}
 
 	if len(allRealtimeAPIs) == 0 && len(allAsyncAPIs) == 0 && len(allBatchAPIs) == 0 && len(allTaskAPIs) == 0 && len(allTrafficSplitters) == 0 {
-		return console.Bold("no apis are deployed"), nil
+		return console.Bold(noAPIsDeployedMessage()), nil
 	}
 
 	out := ""
@@ -501,9 +519,28 @@ func getAPI(env cliconfig.Environment, apiName string) (string, error) {
 }
 
 func apiHistoryTable(apiVersions []schema.APIVersion) string {
+	hasModelVersions := false
+	hasGitCommits := false
+	hasImageDigests := false
+	for _, apiVersion := range apiVersions {
+		if apiVersion.ModelVersion != "" {
+			hasModelVersions = true
+		}
+		if apiVersion.GitCommit != "" {
+			hasGitCommits = true
+		}
+		if len(apiVersion.ImageDigests) > 0 {
+			hasImageDigests = true
+		}
+	}
+
 	t := table.Table{
 		Headers: []table.Header{
+			{Title: "version"},
 			{Title: "api id"},
+			{Title: "model version", Hidden: !hasModelVersions},
+			{Title: "git commit", Hidden: !hasGitCommits},
+			{Title: "image digests", Hidden: !hasImageDigests},
 			{Title: "last deployed"},
 		},
 	}
@@ -511,12 +548,56 @@ func apiHistoryTable(apiVersions []schema.APIVersion) string {
 	t.Rows = make([][]interface{}, len(apiVersions))
 	for i, apiVersion := range apiVersions {
 		lastUpdated := time.Unix(apiVersion.LastUpdated, 0)
-		t.Rows[i] = []interface{}{apiVersion.APIID, libtime.SinceStr(&lastUpdated)}
+		modelVersion := apiVersion.ModelVersion
+		if modelVersion == "" {
+			modelVersion = "-"
+		}
+		gitCommit := apiVersion.GitCommit
+		if gitCommit == "" {
+			gitCommit = "-"
+		} else if len(gitCommit) > 12 {
+			gitCommit = gitCommit[:12]
+		}
+		t.Rows[i] = []interface{}{apiVersion.Version, apiVersion.APIID, modelVersion, gitCommit, imageDigestsStr(apiVersion.ImageDigests), libtime.SinceStr(&lastUpdated)}
 	}
 
 	return t.MustFormat(&table.Opts{Sort: pointer.Bool(false)})
 }
 
+// imageDigestsStr returns the abbreviated image digests (e.g. sha256:0123456789ab), prefixed with their container names if there are multiple containers
+func imageDigestsStr(imageDigests map[string]string) string {
+	if len(imageDigests) == 0 {
+		return "-"
+	}
+
+	containerNames := make([]string, 0, len(imageDigests))
+	for containerName := range imageDigests {
+		containerNames = append(containerNames, containerName)
+	}
+	sort.Strings(containerNames)
+
+	strs := make([]string, len(containerNames))
+	for i, containerName := range containerNames {
+		digest := imageDigests[containerName]
+		if len(digest) > len("sha256:")+12 {
+			digest = digest[:len("sha256:")+12]
+		}
+		if len(containerNames) > 1 {
+			digest = containerName + ": " + digest
+		}
+		strs[i] = digest
+	}
+
+	return strings.Join(strs, ", ")
+}
+
 func titleStr(title string) string {
 	return "\n" + console.Bold(title) + "\n"
 }
+
+func noAPIsDeployedMessage() string {
+	if _flagGetSelector != "" {
+		return fmt.Sprintf("no apis which match the selector %s are deployed", _flagGetSelector)
+	}
+	return "no apis are deployed"
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cortexlabs/cortex/cli/cluster"
+	"github.com/cortexlabs/cortex/cli/types/flags"
+	"github.com/cortexlabs/cortex/pkg/lib/console"
+	"github.com/cortexlabs/cortex/pkg/lib/exit"
+	libjson "github.com/cortexlabs/cortex/pkg/lib/json"
+	"github.com/cortexlabs/cortex/pkg/lib/pointer"
+	"github.com/cortexlabs/cortex/pkg/lib/table"
+	"github.com/cortexlabs/cortex/pkg/lib/telemetry"
+	libtime "github.com/cortexlabs/cortex/pkg/lib/time"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/spf13/cobra"
+)
+
+var (
+	_flagGitOpsEnv string
+)
+
+func gitOpsInit() {
+	_gitOpsStatusCmd.Flags().SortFlags = false
+	_gitOpsStatusCmd.Flags().StringVarP(&_flagGitOpsEnv, "env", "e", "", "environment to use")
+	_gitOpsStatusCmd.Flags().VarP(&_flagOutput, "output", "o", fmt.Sprintf("output format: one of %s", strings.Join(flags.OutputTypeStringsExcluding(flags.YAMLOutputType), "|")))
+	_gitOpsCmd.AddCommand(_gitOpsStatusCmd)
+}
+
+var _gitOpsCmd = &cobra.Command{
+	Use:   "gitops",
+	Short: "inspect the reconciliation of the cluster's apis with its gitops repository (contains subcommands)",
+}
+
+var _gitOpsStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "show the most recently applied commit of the gitops repository, and the result for each of its apis",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		envName, err := getEnvFromFlag(_flagGitOpsEnv)
+		if err != nil {
+			telemetry.Event("cli.gitops.status")
+			exit.Error(err)
+		}
+
+		env, err := ReadOrConfigureEnv(envName)
+		if err != nil {
+			telemetry.Event("cli.gitops.status")
+			exit.Error(err)
+		}
+		telemetry.Event("cli.gitops.status", map[string]interface{}{"env_name": env.Name})
+
+		err = printEnvIfNotSpecified(env.Name, cmd)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		gitOpsStatus, err := cluster.GetGitOpsStatus(MustGetOperatorConfig(env.Name))
+		if err != nil {
+			exit.Error(err)
+		}
+
+		if _flagOutput == flags.JSONOutputType {
+			bytes, err := libjson.Marshal(gitOpsStatus)
+			if err != nil {
+				exit.Error(err)
+			}
+			fmt.Print(string(bytes))
+			return
+		}
+
+		fmt.Print(gitOpsStatusStr(gitOpsStatus))
+	},
+}
+
+func gitOpsStatusStr(gitOpsStatus schema.GitOpsStatus) string {
+	path := gitOpsStatus.Path
+	if path == "" {
+		path = "/"
+	}
+	commit := gitOpsStatus.Commit
+	if commit == "" {
+		commit = "-"
+	} else if len(commit) > 12 {
+		commit = commit[:12]
+	}
+
+	out := fmt.Sprintf("%s %s\n", console.Bold("repository:"), gitOpsStatus.Repository)
+	out += fmt.Sprintf("%s %s\n", console.Bold("branch:"), gitOpsStatus.Branch)
+	out += fmt.Sprintf("%s %s\n", console.Bold("path:"), path)
+	out += fmt.Sprintf("%s %s\n", console.Bold("commit:"), commit)
+	out += fmt.Sprintf("%s %s\n", console.Bold("last sync:"), libtime.SinceStr(gitOpsStatus.LastSync))
+	out += fmt.Sprintf("%s %s\n", console.Bold("last attempt:"), libtime.SinceStr(gitOpsStatus.LastAttempt))
+
+	if gitOpsStatus.Error != "" {
+		out += fmt.Sprintf("\n%s %s\n", console.Bold("error:"), gitOpsStatus.Error)
+	}
+
+	if gitOpsStatus.Commit == "" && gitOpsStatus.Error == "" {
+		return out + "\nthe repository has not been applied yet\n"
+	}
+
+	if len(gitOpsStatus.APIs) == 0 {
+		return out
+	}
+
+	var hasErrors bool
+	rows := make([][]interface{}, 0, len(gitOpsStatus.APIs))
+	for _, api := range gitOpsStatus.APIs {
+		file := api.File
+		if file == "" {
+			file = "-"
+		}
+		apiErr := api.Error
+		if apiErr != "" {
+			hasErrors = true
+		} else {
+			apiErr = "-"
+		}
+		rows = append(rows, []interface{}{api.Name, api.Kind.String(), file, string(api.Action), apiErr})
+	}
+
+	t := table.Table{
+		Headers: []table.Header{
+			{Title: "api"},
+			{Title: "kind"},
+			{Title: "file"},
+			{Title: "action"},
+			{Title: "error", Hidden: !hasErrors},
+		},
+		Rows: rows,
+	}
+
+	return out + "\n" + t.MustFormat(&table.Opts{Sort: pointer.Bool(false)})
+}
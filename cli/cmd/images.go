@@ -0,0 +1,374 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cortexlabs/cortex/cli/cluster"
+	"github.com/cortexlabs/cortex/pkg/lib/aws"
+	"github.com/cortexlabs/cortex/pkg/lib/docker"
+	"github.com/cortexlabs/cortex/pkg/lib/exit"
+	"github.com/cortexlabs/cortex/pkg/lib/prompt"
+	"github.com/cortexlabs/cortex/pkg/lib/sets/strset"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	"github.com/cortexlabs/cortex/pkg/lib/table"
+	"github.com/cortexlabs/cortex/pkg/lib/telemetry"
+	libtime "github.com/cortexlabs/cortex/pkg/lib/time"
+	"github.com/spf13/cobra"
+)
+
+var (
+	_flagImagesEnv              string
+	_flagImagesRegion           string
+	_flagImagesRepositoryPrefix string
+	_flagImagesTagPrefix        string
+	_flagImagesKeep             int
+	_flagImagesOlderThan        int
+	_flagImagesKeepUntagged     bool
+	_flagImagesDryRun           bool
+	_flagImagesYes              bool
+)
+
+func imagesInit() {
+	_imagesPruneCmd.Flags().SortFlags = false
+	_imagesPruneCmd.Flags().StringVarP(&_flagImagesEnv, "env", "e", "", "environment whose apis' images should be protected (and whose cluster's region is used by default)")
+	_imagesPruneCmd.Flags().StringVarP(&_flagImagesRegion, "region", "r", "", "aws region of the ecr repositories (defaults to the cluster's region)")
+	_imagesPruneCmd.Flags().StringVar(&_flagImagesRepositoryPrefix, "repository-prefix", "", "prune all ecr repositories whose names start with this prefix (defaults to the repositories used by the environment's apis)")
+	_imagesPruneCmd.Flags().StringVar(&_flagImagesTagPrefix, "tag-prefix", "", "only prune tagged images which have a tag that starts with this prefix")
+	_imagesPruneCmd.Flags().IntVar(&_flagImagesKeep, "keep", 10, "number of most recently pushed tagged images to keep in each repository")
+	_imagesPruneCmd.Flags().IntVar(&_flagImagesOlderThan, "older-than", 0, "only prune images which were pushed more than this many days ago")
+	_imagesPruneCmd.Flags().BoolVar(&_flagImagesKeepUntagged, "keep-untagged", false, "don't prune untagged images")
+	_imagesPruneCmd.Flags().BoolVar(&_flagImagesDryRun, "dry-run", false, "list the images which would be pruned without deleting them")
+	_imagesPruneCmd.Flags().BoolVarP(&_flagImagesYes, "yes", "y", false, "skip prompts")
+	_imagesCmd.AddCommand(_imagesPruneCmd)
+}
+
+var _imagesCmd = &cobra.Command{
+	Use:   "images",
+	Short: "manage the container images of your apis (contains subcommands)",
+}
+
+var _imagesPruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "delete old images from ecr to reduce storage costs (images used by the environment's apis are never deleted)",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		envName, err := getEnvFromFlag(_flagImagesEnv)
+		if err != nil {
+			telemetry.Event("cli.images.prune")
+			exit.Error(err)
+		}
+
+		env, err := ReadOrConfigureEnv(envName)
+		if err != nil {
+			telemetry.Event("cli.images.prune")
+			exit.Error(err)
+		}
+		telemetry.Event("cli.images.prune", map[string]interface{}{"env_name": env.Name, "dry_run": _flagImagesDryRun})
+
+		if _flagImagesKeep < 0 {
+			exit.Error(ErrorNegativeFlagValue("--keep", _flagImagesKeep))
+		}
+		if _flagImagesOlderThan < 0 {
+			exit.Error(ErrorNegativeFlagValue("--older-than", _flagImagesOlderThan))
+		}
+
+		err = printEnvIfNotSpecified(env.Name, cmd)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		operatorConfig := MustGetOperatorConfig(env.Name)
+
+		region := _flagImagesRegion
+		if region == "" {
+			infoResponse, err := cluster.Info(operatorConfig)
+			if err != nil {
+				exit.Error(err)
+			}
+			region = infoResponse.ClusterConfig.Region
+		}
+
+		awsClient, err := newAWSClient(region, true)
+		if err != nil {
+			exit.Error(err)
+		}
+		accountID, _, err := awsClient.GetCachedAccountID()
+		if err != nil {
+			exit.Error(err)
+		}
+
+		inUse, err := getImagesInUse(operatorConfig, accountID, region)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		var repositories []string
+		if _flagImagesRepositoryPrefix != "" {
+			repositories, err = awsClient.ListECRRepositories(_flagImagesRepositoryPrefix)
+			if err != nil {
+				exit.Error(err)
+			}
+		} else {
+			repositories = inUse.repositories.SliceSorted()
+		}
+
+		if len(repositories) == 0 {
+			fmt.Println("no ecr repositories to prune were found")
+			return
+		}
+
+		var imagesToPrune []aws.ECRImage
+		for _, repository := range repositories {
+			repositoryImages, err := imagesToPruneInRepository(awsClient, repository, inUse)
+			if err != nil {
+				exit.Error(err)
+			}
+			imagesToPrune = append(imagesToPrune, repositoryImages...)
+		}
+
+		if len(imagesToPrune) == 0 {
+			fmt.Printf("no images need to be pruned in %s %s\n", s.PluralS("repository", len(repositories)), s.StrsAnd(repositories))
+			return
+		}
+
+		t := prunedImagesTable(imagesToPrune)
+		fmt.Print(t.MustFormat())
+		fmt.Println()
+
+		summary := fmt.Sprintf("%d %s (%s)", len(imagesToPrune), s.PluralS("image", len(imagesToPrune)), s.Int64ToBase2Byte(ecrImagesSize(imagesToPrune)))
+		if _flagImagesDryRun {
+			fmt.Println(summary + " would be pruned")
+			return
+		}
+
+		if !_flagImagesYes {
+			prompt.YesOrExit(fmt.Sprintf("%s will be deleted from ecr and can't be recovered, are you sure you want to continue?", summary), "", "")
+		}
+
+		digestsByRepository := map[string][]string{}
+		for _, image := range imagesToPrune {
+			digestsByRepository[image.Repository] = append(digestsByRepository[image.Repository], image.Digest)
+		}
+		for _, repository := range repositories {
+			if err := awsClient.DeleteECRImages(repository, digestsByRepository[repository]); err != nil {
+				exit.Error(err)
+			}
+		}
+
+		fmt.Println("pruned " + summary)
+	},
+}
+
+// imagesInUse holds the images which are referenced by the environment's apis (including their previous versions)
+type imagesInUse struct {
+	repositories strset.Set            // the names of the ecr repositories (in the aws account and region being pruned) which are used by the apis
+	references   map[string]strset.Set // repository name -> tags and digests which are used by the apis
+	digests      strset.Set            // digests which are pinned by the apis, in any repository
+}
+
+func (inUse imagesInUse) contains(image aws.ECRImage) bool {
+	if inUse.digests.Has(image.Digest) {
+		return true
+	}
+
+	references := inUse.references[image.Repository]
+	if references == nil {
+		return false
+	}
+	return references.Has(image.Digest) || references.HasAny(image.Tags...)
+}
+
+func getImagesInUse(operatorConfig cluster.OperatorConfig, accountID string, region string) (imagesInUse, error) {
+	inUse := imagesInUse{
+		repositories: strset.New(),
+		references:   map[string]strset.Set{},
+		digests:      strset.New(),
+	}
+
+	apis, err := cluster.GetAPIs(operatorConfig, "")
+	if err != nil {
+		return imagesInUse{}, err
+	}
+
+	for _, api := range apis {
+		if api.Metadata == nil {
+			continue
+		}
+
+		apiResponses, err := cluster.GetAPI(operatorConfig, api.Metadata.Name)
+		if err != nil {
+			return imagesInUse{}, err
+		}
+
+		for _, apiResponse := range apiResponses {
+			for _, apiVersion := range apiResponse.APIVersions {
+				for _, digest := range apiVersion.ImageDigests {
+					inUse.digests.Add(digest)
+				}
+			}
+
+			if apiResponse.Spec == nil || apiResponse.Spec.Pod == nil {
+				continue
+			}
+
+			for _, container := range apiResponse.Spec.Pod.Containers {
+				if container.ImageDigest != "" {
+					inUse.digests.Add(container.ImageDigest)
+				}
+
+				registry, repository, reference := docker.ParseImageReference(container.Image)
+				if aws.GetAccountIDFromECRURL(registry) != accountID || aws.GetRegionFromECRURL(registry) != region {
+					continue
+				}
+
+				inUse.repositories.Add(repository)
+				if inUse.references[repository] == nil {
+					inUse.references[repository] = strset.New()
+				}
+				inUse.references[repository].Add(reference)
+			}
+		}
+	}
+
+	return inUse, nil
+}
+
+// imagesToPruneInRepository returns the repository's images which should be pruned based on the command's flags
+func imagesToPruneInRepository(awsClient *aws.Client, repository string, inUse imagesInUse) ([]aws.ECRImage, error) {
+	taggedImages, err := awsClient.ListECRImages(repository, "")
+	if err != nil {
+		return nil, err
+	}
+	untaggedImages, err := awsClient.ListUntaggedECRImages(repository)
+	if err != nil {
+		return nil, err
+	}
+
+	protectedDigests, err := protectedImageDigests(awsClient, repository, append(taggedImages, untaggedImages...), inUse)
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []aws.ECRImage
+
+	var prefixedImages []aws.ECRImage
+	for _, image := range taggedImages {
+		if image.HasTagWithPrefix(_flagImagesTagPrefix) {
+			prefixedImages = append(prefixedImages, image)
+		}
+	}
+	if len(prefixedImages) > _flagImagesKeep {
+		candidates = append(candidates, prefixedImages[_flagImagesKeep:]...)
+	}
+
+	if !_flagImagesKeepUntagged {
+		candidates = append(candidates, untaggedImages...)
+	}
+
+	var cutoff time.Time
+	if _flagImagesOlderThan > 0 {
+		cutoff = time.Now().AddDate(0, 0, -_flagImagesOlderThan)
+	}
+
+	var images []aws.ECRImage
+	for _, image := range candidates {
+		if protectedDigests.Has(image.Digest) {
+			continue
+		}
+		if !cutoff.IsZero() && image.PushedAt.After(cutoff) {
+			continue
+		}
+		images = append(images, image)
+	}
+
+	return images, nil
+}
+
+// protectedImageDigests returns the digests of the repository's images which are in use: the images which are referenced by the apis, the platform-specific
+// manifests of the multi-arch images among them (which are stored as untagged images), and the cosign signatures of all of those (which are tagged sha256-<hex>.sig)
+func protectedImageDigests(awsClient *aws.Client, repository string, images []aws.ECRImage, inUse imagesInUse) (strset.Set, error) {
+	protected := strset.New()
+	for _, image := range images {
+		if inUse.contains(image) {
+			protected.Add(image.Digest)
+		}
+	}
+
+	for _, image := range images {
+		if !protected.Has(image.Digest) || !image.IsImageIndex() {
+			continue
+		}
+		manifestDigests, err := awsClient.ListECRImageIndexManifests(repository, image.Digest)
+		if err != nil {
+			return nil, err
+		}
+		protected.Add(manifestDigests...)
+	}
+
+	for _, image := range images {
+		for _, tag := range image.Tags {
+			if signedDigest, ok := docker.CosignSignedDigest(tag); ok && (protected.Has(signedDigest) || inUse.digests.Has(signedDigest)) {
+				protected.Add(image.Digest)
+			}
+		}
+	}
+
+	return protected, nil
+}
+
+func ecrImagesSize(images []aws.ECRImage) int64 {
+	var size int64
+	for _, image := range images {
+		size += image.SizeBytes
+	}
+	return size
+}
+
+func prunedImagesTable(images []aws.ECRImage) table.Table {
+	sort.SliceStable(images, func(i, j int) bool {
+		return images[i].Repository < images[j].Repository
+	})
+
+	rows := make([][]interface{}, 0, len(images))
+	for _, image := range images {
+		tags := "-"
+		if image.IsTagged() {
+			tags = strings.Join(image.Tags, ", ")
+		}
+		age := "-"
+		if !image.PushedAt.IsZero() {
+			age = libtime.SinceStr(&image.PushedAt)
+		}
+		rows = append(rows, []interface{}{image.Repository, tags, image.Digest, age, s.Int64ToBase2Byte(image.SizeBytes)})
+	}
+
+	return table.Table{
+		Headers: []table.Header{
+			{Title: "repository"},
+			{Title: "tags"},
+			{Title: "digest"},
+			{Title: "age"},
+			{Title: "size"},
+		},
+		Rows: rows,
+	}
+}
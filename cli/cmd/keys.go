@@ -0,0 +1,213 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cortexlabs/cortex/cli/cluster"
+	"github.com/cortexlabs/cortex/cli/types/cliconfig"
+	"github.com/cortexlabs/cortex/cli/types/flags"
+	"github.com/cortexlabs/cortex/pkg/lib/console"
+	"github.com/cortexlabs/cortex/pkg/lib/exit"
+	libjson "github.com/cortexlabs/cortex/pkg/lib/json"
+	"github.com/cortexlabs/cortex/pkg/lib/print"
+	"github.com/cortexlabs/cortex/pkg/lib/prompt"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	"github.com/cortexlabs/cortex/pkg/lib/table"
+	"github.com/cortexlabs/cortex/pkg/lib/telemetry"
+	libtime "github.com/cortexlabs/cortex/pkg/lib/time"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/spf13/cobra"
+)
+
+var (
+	_flagKeysEnv         string
+	_flagKeysCreateAPIs  []string
+	_flagKeysRevokeForce bool
+)
+
+func keysInit() {
+	_keysCreateCmd.Flags().SortFlags = false
+	_keysCreateCmd.Flags().StringVarP(&_flagKeysEnv, "env", "e", "", "environment to use")
+	_keysCreateCmd.Flags().StringSliceVarP(&_flagKeysCreateAPIs, "api", "a", nil, "api which the key grants access to (can be repeated; default: all apis)")
+	_keysCreateCmd.Flags().VarP(&_flagOutput, "output", "o", fmt.Sprintf("output format: one of %s", strings.Join(flags.OutputTypeStringsExcluding(flags.YAMLOutputType), "|")))
+	_keysCmd.AddCommand(_keysCreateCmd)
+
+	_keysListCmd.Flags().SortFlags = false
+	_keysListCmd.Flags().StringVarP(&_flagKeysEnv, "env", "e", "", "environment to use")
+	_keysListCmd.Flags().VarP(&_flagOutput, "output", "o", fmt.Sprintf("output format: one of %s", strings.Join(flags.OutputTypeStringsExcluding(flags.YAMLOutputType), "|")))
+	_keysCmd.AddCommand(_keysListCmd)
+
+	_keysRevokeCmd.Flags().SortFlags = false
+	_keysRevokeCmd.Flags().StringVarP(&_flagKeysEnv, "env", "e", "", "environment to use")
+	_keysRevokeCmd.Flags().BoolVarP(&_flagKeysRevokeForce, "force", "f", false, "revoke the key without confirmation")
+	_keysRevokeCmd.Flags().VarP(&_flagOutput, "output", "o", fmt.Sprintf("output format: one of %s", strings.Join(flags.OutputTypeStringsExcluding(flags.YAMLOutputType), "|")))
+	_keysCmd.AddCommand(_keysRevokeCmd)
+}
+
+var _keysCmd = &cobra.Command{
+	Use:   "keys",
+	Short: "manage api keys for apis with api key authentication (contains subcommands)",
+}
+
+var _keysCreateCmd = &cobra.Command{
+	Use:   "create KEY_NAME",
+	Short: "create an api key",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		env := keysEnvOrExit("cli.keys.create")
+
+		err := printEnvIfNotSpecified(env.Name, cmd)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		createResponse, err := cluster.CreateAPIKey(MustGetOperatorConfig(env.Name), args[0], _flagKeysCreateAPIs)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		if _flagOutput == flags.JSONOutputType {
+			bytes, err := libjson.Marshal(createResponse)
+			if err != nil {
+				exit.Error(err)
+			}
+			fmt.Print(string(bytes))
+			return
+		}
+
+		apis := "all apis"
+		if len(createResponse.APIKey.APIs) > 0 {
+			apis = s.StrsAnd(createResponse.APIKey.APIs)
+		}
+		print.BoldFirstLine(fmt.Sprintf("created api key %s, which grants access to %s:", createResponse.APIKey.Name, apis))
+		fmt.Println()
+		fmt.Println(console.Bold(createResponse.Key))
+		fmt.Println()
+		fmt.Println("this key will not be shown again, so store it somewhere safe; send it in the X-Api-Key header of requests to apis with `authentication: api_key` (it may take up to 2 minutes for running apis to start accepting it)")
+	},
+}
+
+var _keysListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "list the api keys",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		env := keysEnvOrExit("cli.keys.list")
+
+		err := printEnvIfNotSpecified(env.Name, cmd)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		keys, err := cluster.ListAPIKeys(MustGetOperatorConfig(env.Name))
+		if err != nil {
+			exit.Error(err)
+		}
+
+		if _flagOutput == flags.JSONOutputType {
+			bytes, err := libjson.Marshal(keys)
+			if err != nil {
+				exit.Error(err)
+			}
+			fmt.Print(string(bytes))
+			return
+		}
+
+		if len(keys) == 0 {
+			fmt.Println("no api keys have been created (create one with `cortex keys create KEY_NAME`)")
+			return
+		}
+
+		t := apiKeysTable(keys)
+		fmt.Print(t.MustFormat())
+	},
+}
+
+var _keysRevokeCmd = &cobra.Command{
+	Use:   "revoke KEY_NAME",
+	Short: "revoke an api key",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		env := keysEnvOrExit("cli.keys.revoke")
+
+		err := printEnvIfNotSpecified(env.Name, cmd)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		if !_flagKeysRevokeForce {
+			prompt.YesOrExit(fmt.Sprintf("are you sure you want to revoke api key %s? requests which use it will be rejected", args[0]), "", "")
+		}
+
+		revokeResponse, err := cluster.RevokeAPIKey(MustGetOperatorConfig(env.Name), args[0])
+		if err != nil {
+			exit.Error(err)
+		}
+
+		if _flagOutput == flags.JSONOutputType {
+			bytes, err := libjson.Marshal(revokeResponse)
+			if err != nil {
+				exit.Error(err)
+			}
+			fmt.Print(string(bytes))
+			return
+		}
+
+		print.BoldFirstLine(revokeResponse.Message)
+	},
+}
+
+func keysEnvOrExit(eventName string) cliconfig.Environment {
+	envName, err := getEnvFromFlag(_flagKeysEnv)
+	if err != nil {
+		telemetry.Event(eventName)
+		exit.Error(err)
+	}
+
+	env, err := ReadOrConfigureEnv(envName)
+	if err != nil {
+		telemetry.Event(eventName)
+		exit.Error(err)
+	}
+	telemetry.Event(eventName, map[string]interface{}{"env_name": env.Name})
+
+	return env
+}
+
+func apiKeysTable(keys []schema.APIKey) table.Table {
+	rows := make([][]interface{}, 0, len(keys))
+	for _, key := range keys {
+		apis := "all"
+		if len(key.APIs) > 0 {
+			apis = strings.Join(key.APIs, ", ")
+		}
+		createdAt := key.CreatedAt
+		rows = append(rows, []interface{}{key.Name, apis, libtime.SinceStr(&createdAt)})
+	}
+
+	return table.Table{
+		Headers: []table.Header{
+			{Title: "name"},
+			{Title: "apis"},
+			{Title: "created"},
+		},
+		Rows: rows,
+	}
+}
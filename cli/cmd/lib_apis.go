@@ -17,7 +17,15 @@ limitations under the License.
 package cmd
 
 import (
+	"fmt"
+	"strings"
+	"time"
+
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
 	"github.com/cortexlabs/cortex/pkg/lib/table"
+	libtime "github.com/cortexlabs/cortex/pkg/lib/time"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
 	"github.com/cortexlabs/cortex/pkg/types/status"
 )
 
@@ -57,3 +65,58 @@ func replicaCountTable(counts *status.ReplicaCounts) table.Table {
 		Rows: rows,
 	}
 }
+
+func eventsTable(events []schema.Event) table.Table {
+	rows := make([][]interface{}, 0, len(events))
+	for _, event := range events {
+		lastSeen := time.Unix(event.Timestamp, 0)
+		lastSeenStr := libtime.SinceStr(&lastSeen)
+		if event.Count > 1 {
+			lastSeenStr += fmt.Sprintf(" (x%d)", event.Count)
+		}
+		rows = append(rows, []interface{}{
+			lastSeenStr,
+			event.Type,
+			event.Reason,
+			event.Object,
+			event.Message,
+		})
+	}
+
+	return table.Table{
+		Headers: []table.Header{
+			{Title: _titleEventLastSeen},
+			{Title: _titleEventType},
+			{Title: _titleEventReason},
+			{Title: _titleEventObject},
+			{Title: _titleEventMessage, MaxWidth: 100},
+		},
+		Rows: rows,
+	}
+}
+
+func imagePrepullStr(imagePrepull *schema.ImagePrepullResponse) string {
+	return fmt.Sprintf("images pulled on %d/%d %s", imagePrepull.NumPulled, imagePrepull.NumNodes, s.PluralS("node", imagePrepull.NumNodes))
+}
+
+func jobDependenciesStr(dependencies []spec.JobKey) string {
+	dependencyStrs := make([]string, len(dependencies))
+	for i, dependency := range dependencies {
+		dependencyStrs[i] = dependency.UserString()
+	}
+	return strings.Join(dependencyStrs, ", ")
+}
+
+func jobTimeoutStr(timeout *int) string {
+	if timeout == nil {
+		return "-"
+	}
+	return (time.Duration(*timeout) * time.Second).String()
+}
+
+func jobMaxRetriesStr(maxRetries *int) string {
+	if maxRetries == nil {
+		return "0"
+	}
+	return s.Int(*maxRetries)
+}
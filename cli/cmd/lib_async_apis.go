@@ -24,6 +24,7 @@ import (
 	"github.com/cortexlabs/cortex/cli/types/cliconfig"
 	"github.com/cortexlabs/cortex/pkg/lib/console"
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
 	"github.com/cortexlabs/cortex/pkg/lib/table"
 	libtime "github.com/cortexlabs/cortex/pkg/lib/time"
 	"github.com/cortexlabs/cortex/pkg/operator/schema"
@@ -44,6 +45,14 @@ func asyncAPITable(asyncAPI schema.APIResponse, env cliconfig.Environment) (stri
 		out += "\n" + console.Bold("endpoint: ") + *asyncAPI.Endpoint + "\n"
 	}
 
+	if asyncAPI.DeadLetterQueueLength != nil {
+		out += "\n" + console.Bold("dead-letter queue: ") + s.Int(*asyncAPI.DeadLetterQueueLength) + " " + s.PluralS("workload", *asyncAPI.DeadLetterQueueLength)
+		if *asyncAPI.DeadLetterQueueLength > 0 && asyncAPI.Metadata != nil {
+			out += fmt.Sprintf(" (run `cortex async redrive %s` to resubmit)", asyncAPI.Metadata.Name)
+		}
+		out += "\n"
+	}
+
 	out += "\n" + apiHistoryTable(asyncAPI.APIVersions)
 
 	if !_flagVerbose {
@@ -75,9 +84,18 @@ func asyncDescribeAPITable(asyncAPI schema.APIResponse, env cliconfig.Environmen
 		out += "\n" + console.Bold("endpoint: ") + *asyncAPI.Endpoint + "\n"
 	}
 
+	if asyncAPI.ImagePrepull != nil {
+		out += "\n" + console.Bold("image pre-pull: ") + imagePrepullStr(asyncAPI.ImagePrepull) + "\n"
+	}
+
 	t = replicaCountTable(asyncAPI.Status.ReplicaCounts)
 	out += "\n" + t.MustFormat()
 
+	if len(asyncAPI.Events) > 0 {
+		t = eventsTable(asyncAPI.Events)
+		out += "\n" + t.MustFormat()
+	}
+
 	return out, nil
 }
 
@@ -18,6 +18,8 @@ package cmd
 
 import (
 	"fmt"
+	"os"
+	"strings"
 
 	"github.com/cortexlabs/cortex/pkg/consts"
 	"github.com/cortexlabs/cortex/pkg/lib/aws"
@@ -26,12 +28,27 @@ import (
 	"github.com/cortexlabs/cortex/pkg/types/clusterconfig"
 )
 
+const (
+	_awsRoleARNEnvVar    = "CORTEX_AWS_ROLE_ARN"
+	_awsExternalIDEnvVar = "CORTEX_AWS_EXTERNAL_ID"
+	_awsRoleSessionName  = "cortex-cli"
+)
+
+// newAWSClient uses the default credential chain, or if CORTEX_AWS_ROLE_ARN is set, assumes that role (with CORTEX_AWS_EXTERNAL_ID, if set) using the default credential chain
 func newAWSClient(region string, printToStdout bool) (*aws.Client, error) {
 	if err := clusterconfig.ValidateRegion(region); err != nil {
 		return nil, err
 	}
 
-	awsClient, err := aws.NewForRegion(region)
+	roleARN := os.Getenv(_awsRoleARNEnvVar)
+
+	var awsClient *aws.Client
+	var err error
+	if roleARN != "" {
+		awsClient, err = aws.NewForRole(region, roleARN, os.Getenv(_awsExternalIDEnvVar), _awsRoleSessionName)
+	} else {
+		awsClient, err = aws.NewForRegion(region)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -41,12 +58,47 @@ func newAWSClient(region string, printToStdout bool) (*aws.Client, error) {
 	}
 
 	if printToStdout {
-		fmt.Println("using aws credentials with access key " + *awsClient.AccessKeyID() + "\n")
+		if roleARN != "" {
+			fmt.Println("using aws role " + roleARN + "\n")
+		} else {
+			fmt.Println("using aws credentials from " + awsClient.CredentialsSource() + " with access key " + *awsClient.AccessKeyID() + "\n")
+		}
 	}
 
 	return awsClient, nil
 }
 
+// promptMFAToken prompts for the code of the mfa device when an aws profile requires mfa (the resulting session is cached, so this is only necessary when it expires)
+func promptMFAToken(mfaSerial string) (string, error) {
+	tokenCode := prompt.Prompt(&prompt.Options{
+		Prompt: "mfa code for " + mfaSerial,
+	})
+	fmt.Println()
+
+	tokenCode = strings.TrimSpace(tokenCode)
+	if tokenCode == "" {
+		return "", ErrorMFATokenNotProvided(mfaSerial)
+	}
+
+	return tokenCode, nil
+}
+
+// validateAWSProfile checks that credentials can be resolved from the aws profile, and prints where they were resolved from
+func validateAWSProfile(profile string) error {
+	awsClient, err := aws.NewForProfile("", profile)
+	if err != nil {
+		return err
+	}
+
+	if _, _, err := awsClient.CheckCredentials(); err != nil {
+		return err
+	}
+
+	fmt.Printf("aws profile %s uses credentials from %s (with access key %s)\n\n", profile, awsClient.CredentialsSource(), *awsClient.AccessKeyID())
+
+	return nil
+}
+
 func promptIfNotAdmin(awsClient *aws.Client, disallowPrompt bool) {
 	accessKeyMsg := ""
 	if accessKey := awsClient.AccessKeyID(); accessKey != nil {
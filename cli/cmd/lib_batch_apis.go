@@ -18,6 +18,7 @@ package cmd
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/PEAT-AI/yaml"
@@ -26,18 +27,23 @@ import (
 	"github.com/cortexlabs/cortex/cli/types/flags"
 	"github.com/cortexlabs/cortex/pkg/lib/console"
 	libjson "github.com/cortexlabs/cortex/pkg/lib/json"
+	libmath "github.com/cortexlabs/cortex/pkg/lib/math"
 	"github.com/cortexlabs/cortex/pkg/lib/pointer"
 	s "github.com/cortexlabs/cortex/pkg/lib/strings"
 	"github.com/cortexlabs/cortex/pkg/lib/table"
 	libtime "github.com/cortexlabs/cortex/pkg/lib/time"
 	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types/metrics"
 	"github.com/cortexlabs/cortex/pkg/types/status"
 )
 
 const (
-	_titleBatchAPI    = "batch api"
-	_titleJobCount    = "running jobs"
-	_titleLatestJobID = "latest job id"
+	_titleBatchAPI       = "batch api"
+	_titleJobCount       = "running jobs"
+	_titleQueuedJobCount = "queued jobs"
+	_titleLatestJobID    = "latest job id"
+
+	_maxFailedBatchIndicesToDisplay = 20
 )
 
 func batchAPIsTable(batchAPIs []schema.APIResponse, envNames []string) table.Table {
@@ -67,6 +73,7 @@ func batchAPIsTable(batchAPIs []schema.APIResponse, envNames []string) table.Tab
 			envNames[i],
 			batchAPI.Metadata.Name,
 			runningJobs,
+			batchAPI.QueuedJobCount,
 			latestJobID,
 			libtime.SinceStr(&lastAPIUpdated),
 		})
@@ -77,6 +84,7 @@ func batchAPIsTable(batchAPIs []schema.APIResponse, envNames []string) table.Tab
 			{Title: _titleEnvironment},
 			{Title: _titleBatchAPI},
 			{Title: _titleJobCount},
+			{Title: _titleQueuedJobCount},
 			{Title: _titleLatestJobID},
 			{Title: _titleLastUpdated},
 		},
@@ -168,6 +176,10 @@ func getBatchJob(env cliconfig.Environment, apiName string, jobID string) (strin
 	jobIntroTable := table.KeyValuePairs{}
 	jobIntroTable.Add("job id", job.ID)
 	jobIntroTable.Add("status", job.Status.Message())
+	if len(job.Dependencies) > 0 {
+		jobIntroTable.Add("depends on", jobDependenciesStr(job.Dependencies))
+	}
+	jobIntroTable.Add("max retries", jobMaxRetriesStr(job.MaxRetries))
 	out += jobIntroTable.String(&table.KeyValuePairOpts{BoldKeys: pointer.Bool(true)})
 
 	jobTimingTable := table.KeyValuePairs{}
@@ -182,6 +194,7 @@ func getBatchJob(env cliconfig.Environment, apiName string, jobID string) (strin
 	}
 	duration := jobEndTime.Sub(job.StartTime).Truncate(time.Second).String()
 	jobTimingTable.Add("duration", duration)
+	jobTimingTable.Add("timeout", jobTimeoutStr(job.Timeout))
 
 	out += "\n" + jobTimingTable.String(&table.KeyValuePairOpts{BoldKeys: pointer.Bool(true)})
 
@@ -218,13 +231,26 @@ func getBatchJob(env cliconfig.Environment, apiName string, jobID string) (strin
 
 	out += titleStr("batch stats") + t.MustFormat(&table.Opts{BoldHeader: pointer.Bool(false)})
 
-	if job.Status == status.JobEnqueuing {
+	if job.BatchProgress != nil {
+		out += "\n" + batchProgressStr(job, resp.Metrics)
+	}
+
+	if job.Status == status.JobPendingDependencies {
+		out += "\n" + "waiting for the jobs which this job depends on to succeed, workers have not been allocated for this job yet\n"
+	} else if job.Status == status.JobQueued {
+		out += "\n" + "queued because the api is running its maximum number of concurrent jobs, workers have not been allocated for this job yet (run `cortex queue " + job.APIName + "` to see its position)\n"
+	} else if job.Status == status.JobEnqueuing {
 		out += "\n" + "still enqueuing, workers have not been allocated for this job yet\n"
 	} else if job.Status.IsCompleted() {
 		out += "\n" + "worker stats are not available because this job is not currently running\n"
 	} else {
 		out += titleStr("worker stats")
 		if job.WorkerCounts != nil {
+			var requestedWorkers interface{} = job.Workers
+			if job.AutoWorkers {
+				requestedWorkers = fmt.Sprintf("%d (auto)", job.Workers)
+			}
+
 			t := table.Table{
 				Headers: []table.Header{
 					{Title: "Requested"},
@@ -243,7 +269,7 @@ func getBatchJob(env cliconfig.Environment, apiName string, jobID string) (strin
 				},
 				Rows: [][]interface{}{
 					{
-						job.Workers,
+						requestedWorkers,
 						job.WorkerCounts.Pending,
 						job.WorkerCounts.Creating,
 						job.WorkerCounts.Ready,
@@ -276,3 +302,37 @@ func getBatchJob(env cliconfig.Environment, apiName string, jobID string) (strin
 
 	return out, nil
 }
+
+func batchProgressStr(job status.BatchJobStatus, jobMetrics *metrics.BatchMetrics) string {
+	progress := job.BatchProgress
+
+	barWidth := 40
+	filled := int(progress.CompletionPercentage / 100 * float64(barWidth))
+	out := fmt.Sprintf("progress: [%s%s] %.1f%%", strings.Repeat("#", filled), strings.Repeat("-", barWidth-filled), progress.CompletionPercentage)
+
+	var pendingIndices []string
+	var failedIndices []string
+	for i, batchState := range progress.BatchStates {
+		if batchState == status.BatchPending {
+			pendingIndices = append(pendingIndices, s.Int(i))
+		} else if batchState == status.BatchFailed {
+			failedIndices = append(failedIndices, s.Int(i))
+		}
+	}
+
+	if job.Status == status.JobRunning && len(pendingIndices) > 0 && jobMetrics != nil && jobMetrics.AverageTimePerBatch != nil {
+		workers := libmath.MaxInt(job.Workers, 1)
+		eta := time.Duration(*jobMetrics.AverageTimePerBatch*float64(len(pendingIndices))/float64(workers)) * time.Second
+		out += fmt.Sprintf(" (eta %s)", eta.Truncate(time.Second).String())
+	}
+	out += "\n"
+
+	if len(failedIndices) > 0 {
+		if len(failedIndices) > _maxFailedBatchIndicesToDisplay {
+			failedIndices = append(failedIndices[:_maxFailedBatchIndicesToDisplay], fmt.Sprintf("... (%d total)", len(failedIndices)))
+		}
+		out += "failed batches: " + strings.Join(failedIndices, ", ") + "\n"
+	}
+
+	return out
+}
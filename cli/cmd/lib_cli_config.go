@@ -37,6 +37,7 @@ import (
 	s "github.com/cortexlabs/cortex/pkg/lib/strings"
 	"github.com/cortexlabs/cortex/pkg/lib/urls"
 	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
 )
 
 var _cliConfigValidation = &cr.StructValidation{
@@ -91,6 +92,50 @@ var _cliConfigValidation = &cr.StructValidation{
 								Validator: cliconfig.CortexEndpointValidator,
 							},
 						},
+						{
+							StructField: "Project",
+							StringValidation: &cr.StringValidation{
+								AllowEmpty: true,
+								Validator: func(project string) (string, error) {
+									return project, userconfig.ValidateProject(project)
+								},
+							},
+						},
+						{
+							StructField: "AWSProfile",
+							StringValidation: &cr.StringValidation{
+								AllowEmpty: true,
+							},
+						},
+						{
+							StructField: "OIDCLogin",
+							StructValidation: &cr.StructValidation{
+								AllowExplicitNull: true,
+								DefaultNil:        true,
+								StructFieldValidations: []*cr.StructFieldValidation{
+									{
+										StructField:      "IssuerURL",
+										StringValidation: &cr.StringValidation{Required: true},
+									},
+									{
+										StructField:      "ClientID",
+										StringValidation: &cr.StringValidation{Required: true},
+									},
+									{
+										StructField:      "IDToken",
+										StringValidation: &cr.StringValidation{Required: true},
+									},
+									{
+										StructField:      "RefreshToken",
+										StringValidation: &cr.StringValidation{AllowEmpty: true},
+									},
+									{
+										StructField:     "Expiry",
+										Int64Validation: &cr.Int64Validation{Required: true},
+									},
+								},
+							},
+						},
 					},
 				},
 			},
@@ -386,7 +431,7 @@ func ReadOrConfigureEnv(envName string) (cliconfig.Environment, error) {
 	noMsg := "you can create a cluster by running the `cortex cluster up` command"
 	prompt.YesOrExit(promptStr, yesMsg, noMsg)
 
-	env, err := configureEnv(envName, cliconfig.Environment{})
+	env, err := configureEnv(envName, cliconfig.Environment{}, nil, nil)
 	if err != nil {
 		return cliconfig.Environment{}, err
 	}
@@ -409,8 +454,8 @@ func getEnvConfigDefaults(envName string) cliconfig.Environment {
 	return defaults
 }
 
-// If envName is "", this will prompt for the environment name to configure
-func configureEnv(envName string, fieldsToSkipPrompt cliconfig.Environment) (cliconfig.Environment, error) {
+// If envName is "", this will prompt for the environment name to configure; if project or awsProfile is nil, the environment's previous value (if any) is kept
+func configureEnv(envName string, fieldsToSkipPrompt cliconfig.Environment, project *string, awsProfile *string) (cliconfig.Environment, error) {
 	env := cliconfig.Environment{
 		Name:             envName,
 		OperatorEndpoint: fieldsToSkipPrompt.OperatorEndpoint,
@@ -423,6 +468,28 @@ func configureEnv(envName string, fieldsToSkipPrompt cliconfig.Environment) (cli
 		return cliconfig.Environment{}, err
 	}
 
+	prevEnv, err := readEnv(env.Name)
+	if err != nil {
+		return cliconfig.Environment{}, err
+	}
+
+	if project != nil {
+		env.Project = *project
+	} else if prevEnv != nil {
+		env.Project = prevEnv.Project
+	}
+
+	if awsProfile != nil {
+		env.AWSProfile = *awsProfile
+	} else if prevEnv != nil {
+		env.AWSProfile = prevEnv.AWSProfile
+	}
+
+	// logins are kept as long as the environment points to the same operator
+	if prevEnv != nil && prevEnv.OperatorEndpoint == env.OperatorEndpoint {
+		env.OIDCLogin = prevEnv.OIDCLogin
+	}
+
 	if err := env.Validate(); err != nil {
 		return cliconfig.Environment{}, err
 	}
@@ -448,9 +515,11 @@ func MustGetOperatorConfig(envName string) cluster.OperatorConfig {
 	}
 
 	operatorConfig := cluster.OperatorConfig{
-		Telemetry: isTelemetryEnabled(),
-		ClientID:  clientID,
-		EnvName:   env.Name,
+		Telemetry:  isTelemetryEnabled(),
+		ClientID:   clientID,
+		EnvName:    env.Name,
+		Project:    env.Project,
+		AWSProfile: env.AWSProfile,
 	}
 
 	if env.OperatorEndpoint == "" {
@@ -458,6 +527,14 @@ func MustGetOperatorConfig(envName string) cluster.OperatorConfig {
 	}
 	operatorConfig.OperatorEndpoint = env.OperatorEndpoint
 
+	if env.OIDCLogin != nil {
+		idToken, err := getOrRefreshIDToken(env)
+		if err != nil {
+			exit.Error(err)
+		}
+		operatorConfig.IDToken = idToken
+	}
+
 	return operatorConfig
 }
 
@@ -634,5 +711,15 @@ func writeCLIConfig(cliConfig cliconfig.CLIConfig) error {
 		return err
 	}
 
+	// the file contains credentials once an environment is logged in
+	for _, env := range cliConfig.Environments {
+		if env.OIDCLogin != nil {
+			if err := os.Chmod(_cliConfigPath, 0600); err != nil {
+				return errors.WithStack(err)
+			}
+			break
+		}
+	}
+
 	return nil
 }
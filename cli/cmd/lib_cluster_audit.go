@@ -0,0 +1,301 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"net"
+	"sort"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	awslib "github.com/cortexlabs/cortex/pkg/lib/aws"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/pointer"
+	"github.com/cortexlabs/cortex/pkg/lib/sets/strset"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	"github.com/cortexlabs/cortex/pkg/lib/table"
+	"github.com/cortexlabs/cortex/pkg/types/clusterconfig"
+)
+
+// ports which are expected to be reachable from the load balancer cidr white lists (80/443 on load balancers, and node ports for nlb targets)
+var _expectedPublicPortRanges = [][2]int64{{80, 80}, {443, 443}, {30000, 32767}}
+
+type clusterAuditFinding struct {
+	Resource string `json:"resource"`
+	Issue    string `json:"issue"`
+}
+
+func getClusterAuditFindings(awsClient *awslib.Client, clusterConfig clusterconfig.Config) ([]clusterAuditFinding, error) {
+	eksCluster, err := awsClient.EKSClusterOrNil(clusterConfig.ClusterName)
+	if err != nil {
+		return nil, err
+	}
+	if eksCluster == nil || eksCluster.ResourcesVpcConfig == nil || eksCluster.ResourcesVpcConfig.VpcId == nil {
+		return nil, errors.ErrorUnexpected("unable to find the vpc of eks cluster", clusterConfig.ClusterName)
+	}
+	vpcID := *eksCluster.ResourcesVpcConfig.VpcId
+
+	vpcs, err := awsClient.DescribeVpcs()
+	if err != nil {
+		return nil, err
+	}
+	var vpcCIDRs []string
+	for _, vpc := range vpcs {
+		if aws.StringValue(vpc.VpcId) != vpcID {
+			continue
+		}
+		for _, association := range vpc.CidrBlockAssociationSet {
+			if association != nil && association.CidrBlock != nil {
+				vpcCIDRs = append(vpcCIDRs, *association.CidrBlock)
+			}
+		}
+	}
+	if len(vpcCIDRs) == 0 {
+		return []clusterAuditFinding{{Resource: vpcID, Issue: "the cluster's vpc no longer exists"}}, nil
+	}
+
+	subnets, err := awsClient.DescribeSubnets()
+	if err != nil {
+		return nil, err
+	}
+
+	natGateways, err := awsClient.DescribeNATGateways()
+	if err != nil {
+		return nil, err
+	}
+
+	securityGroups, err := awsClient.DescribeSecurityGroups()
+	if err != nil {
+		return nil, err
+	}
+
+	findings := []clusterAuditFinding{}
+	findings = append(findings, auditSubnets(clusterConfig, aws.StringValueSlice(eksCluster.ResourcesVpcConfig.SubnetIds), subnets)...)
+	findings = append(findings, auditNATGateways(clusterConfig, vpcID, subnets, natGateways)...)
+	findings = append(findings, auditSecurityGroups(clusterConfig, vpcID, vpcCIDRs, securityGroups)...)
+
+	return findings, nil
+}
+
+func auditSubnets(clusterConfig clusterconfig.Config, eksSubnetIDs []string, subnets []ec2.Subnet) []clusterAuditFinding {
+	existingSubnetIDs := strset.New()
+	for _, subnet := range subnets {
+		existingSubnetIDs.Add(aws.StringValue(subnet.SubnetId))
+	}
+
+	expectedSubnetIDs := strset.New(eksSubnetIDs...)
+	for _, subnetConfig := range clusterConfig.Subnets {
+		expectedSubnetIDs.Add(subnetConfig.SubnetID)
+	}
+
+	var findings []clusterAuditFinding
+	for _, subnetID := range expectedSubnetIDs.SliceSorted() {
+		if !existingSubnetIDs.Has(subnetID) {
+			findings = append(findings, clusterAuditFinding{Resource: subnetID, Issue: "subnet was deleted"})
+		}
+	}
+	return findings
+}
+
+func auditNATGateways(clusterConfig clusterconfig.Config, vpcID string, subnets []ec2.Subnet, natGateways []ec2.NatGateway) []clusterAuditFinding {
+	// nat gateways are not managed by cortex when the cluster uses existing subnets
+	if len(clusterConfig.Subnets) > 0 {
+		return nil
+	}
+
+	zones := strset.New(clusterConfig.AvailabilityZones...)
+	if len(zones) == 0 {
+		for _, subnet := range subnets {
+			if aws.StringValue(subnet.VpcId) == vpcID {
+				zones.Add(aws.StringValue(subnet.AvailabilityZone))
+			}
+		}
+	}
+
+	var expected int
+	switch clusterConfig.NATGateway {
+	case clusterconfig.SingleNATGateway:
+		expected = 1
+	case clusterconfig.HighlyAvailableNATGateway:
+		expected = len(zones)
+	}
+
+	var actual int
+	var findings []clusterAuditFinding
+	for _, natGateway := range natGateways {
+		if aws.StringValue(natGateway.VpcId) != vpcID {
+			continue
+		}
+		switch aws.StringValue(natGateway.State) {
+		case ec2.NatGatewayStateAvailable, ec2.NatGatewayStatePending:
+			actual++
+		case ec2.NatGatewayStateFailed:
+			findings = append(findings, clusterAuditFinding{Resource: aws.StringValue(natGateway.NatGatewayId), Issue: "nat gateway is in a failed state"})
+		}
+	}
+
+	if actual < expected {
+		issue := fmt.Sprintf("expected %d nat %s (%s: %s), but found %d", expected, s.PluralS("gateway", expected), clusterconfig.NATGatewayKey, clusterConfig.NATGateway, actual)
+		if clusterConfig.SubnetVisibility == clusterconfig.PrivateSubnetVisibility {
+			issue += "; nodes in private subnets may be unable to reach the internet"
+		}
+		findings = append(findings, clusterAuditFinding{Resource: vpcID, Issue: issue})
+	} else if actual > expected {
+		findings = append(findings, clusterAuditFinding{Resource: vpcID, Issue: fmt.Sprintf("expected %d nat %s (%s: %s), but found %d", expected, s.PluralS("gateway", expected), clusterconfig.NATGatewayKey, clusterConfig.NATGateway, actual)})
+	}
+
+	return findings
+}
+
+func auditSecurityGroups(clusterConfig clusterconfig.Config, vpcID string, vpcCIDRs []string, securityGroups []ec2.SecurityGroup) []clusterAuditFinding {
+	whiteList := append(append([]string{}, clusterConfig.APILoadBalancerCIDRWhiteList...), clusterConfig.OperatorLoadBalancerCIDRWhiteList...)
+
+	var findings []clusterAuditFinding
+	for _, sg := range securityGroups {
+		if aws.StringValue(sg.VpcId) != vpcID || !isClusterSecurityGroup(sg, clusterConfig.ClusterName) {
+			continue
+		}
+
+		for _, permission := range sg.IpPermissions {
+			if permission == nil {
+				continue
+			}
+
+			var cidrs []string
+			for _, ipRange := range permission.IpRanges {
+				if ipRange != nil && ipRange.CidrIp != nil {
+					cidrs = append(cidrs, *ipRange.CidrIp)
+				}
+			}
+			for _, ipRange := range permission.Ipv6Ranges {
+				if ipRange != nil && ipRange.CidrIpv6 != nil {
+					cidrs = append(cidrs, *ipRange.CidrIpv6)
+				}
+			}
+
+			for _, cidr := range cidrs {
+				if cidrsContain(vpcCIDRs, cidr) {
+					continue
+				}
+				if cidrsContain(whiteList, cidr) && isExpectedPublicPortRange(permission) {
+					continue
+				}
+				findings = append(findings, clusterAuditFinding{
+					Resource: fmt.Sprintf("%s (%s)", aws.StringValue(sg.GroupId), aws.StringValue(sg.GroupName)),
+					Issue:    fmt.Sprintf("%s open to %s", ipPermissionPortsStr(permission), cidr),
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i].Resource < findings[j].Resource
+	})
+
+	return findings
+}
+
+func isClusterSecurityGroup(sg ec2.SecurityGroup, clusterName string) bool {
+	for _, tag := range sg.Tags {
+		if tag == nil || tag.Key == nil {
+			continue
+		}
+		switch *tag.Key {
+		case clusterconfig.ClusterNameTag, "alpha.eksctl.io/cluster-name", "aws:eks:cluster-name":
+			if aws.StringValue(tag.Value) == clusterName {
+				return true
+			}
+		case "kubernetes.io/cluster/" + clusterName:
+			return true
+		}
+	}
+	return false
+}
+
+func isExpectedPublicPortRange(permission *ec2.IpPermission) bool {
+	if aws.StringValue(permission.IpProtocol) != "tcp" {
+		return false
+	}
+
+	fromPort := aws.Int64Value(permission.FromPort)
+	toPort := aws.Int64Value(permission.ToPort)
+	for _, portRange := range _expectedPublicPortRanges {
+		if fromPort >= portRange[0] && toPort <= portRange[1] {
+			return true
+		}
+	}
+	return false
+}
+
+func ipPermissionPortsStr(permission *ec2.IpPermission) string {
+	protocol := aws.StringValue(permission.IpProtocol)
+	if protocol == "-1" {
+		return "all traffic"
+	}
+
+	fromPort := aws.Int64Value(permission.FromPort)
+	toPort := aws.Int64Value(permission.ToPort)
+	if fromPort == toPort {
+		return fmt.Sprintf("%s port %d", protocol, fromPort)
+	}
+	return fmt.Sprintf("%s ports %d-%d", protocol, fromPort, toPort)
+}
+
+// returns true if any of the networks in cidrs contains the network of cidr
+func cidrsContain(cidrs []string, cidr string) bool {
+	_, network, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return false
+	}
+	maskSize, _ := network.Mask.Size()
+
+	for _, outerCIDR := range cidrs {
+		_, outerNetwork, err := net.ParseCIDR(outerCIDR)
+		if err != nil {
+			continue
+		}
+		outerMaskSize, _ := outerNetwork.Mask.Size()
+		if outerNetwork.Contains(network.IP) && outerMaskSize <= maskSize {
+			return true
+		}
+	}
+	return false
+}
+
+func printClusterAuditFindings(clusterName string, findings []clusterAuditFinding) {
+	if len(findings) == 0 {
+		fmt.Printf("no drift detected in the network configuration of the %s cluster\n", clusterName)
+		return
+	}
+
+	rows := make([][]interface{}, len(findings))
+	for i, finding := range findings {
+		rows[i] = []interface{}{finding.Resource, finding.Issue}
+	}
+
+	t := table.Table{
+		Headers: []table.Header{
+			{Title: "resource"},
+			{Title: "issue"},
+		},
+		Rows: rows,
+	}
+	t.MustPrint(&table.Opts{Sort: pointer.Bool(false)})
+
+	fmt.Printf("\nthe %s cluster's network configuration has drifted from what cortex expects (%d %s found); resources which were modified outside of cortex may affect the cluster's availability or security\n", clusterName, len(findings), s.PluralS("issue", len(findings)))
+}
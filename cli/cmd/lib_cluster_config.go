@@ -21,6 +21,8 @@ import (
 	"path"
 	"path/filepath"
 	"regexp"
+	"sort"
+	"time"
 
 	"github.com/cortexlabs/cortex/pkg/consts"
 	"github.com/cortexlabs/cortex/pkg/lib/aws"
@@ -32,6 +34,8 @@ import (
 	libmath "github.com/cortexlabs/cortex/pkg/lib/math"
 	"github.com/cortexlabs/cortex/pkg/lib/pointer"
 	"github.com/cortexlabs/cortex/pkg/lib/prompt"
+	"github.com/cortexlabs/cortex/pkg/lib/sets/strset"
+	"github.com/cortexlabs/cortex/pkg/lib/slices"
 	s "github.com/cortexlabs/cortex/pkg/lib/strings"
 	"github.com/cortexlabs/cortex/pkg/lib/table"
 	"github.com/cortexlabs/cortex/pkg/types/clusterconfig"
@@ -40,6 +44,12 @@ import (
 
 var _cachedClusterConfigRegex = regexp.MustCompile(`^cluster_\S+\.yaml$`)
 
+const (
+	_lowSpotPlacementScore         = 3 // out of 10
+	_maxSuggestedSpotRegionsToShow = 3
+	_spotPriceLookback             = 7 * 24 * time.Hour
+)
+
 func getCachedClusterConfigPath(clusterName string, region string) string {
 	return filepath.Join(_localDir, fmt.Sprintf("cluster_%s_%s.yaml", clusterName, region))
 }
@@ -86,6 +96,8 @@ func getNewClusterAccessConfig(clusterConfigFile string) (*clusterconfig.AccessC
 		return nil, errors.Append(errors.FirstError(errs...), fmt.Sprintf("\n\ncluster configuration schema can be found at https://docs.cortexlabs.com/v/%s/", consts.CortexVersionMinor))
 	}
 
+	accessConfig.ImageManager = clusterconfig.MirrorImage(accessConfig.ImageManager, accessConfig.RegistryMirror)
+
 	return accessConfig, nil
 }
 
@@ -119,10 +131,21 @@ func getClusterAccessConfigWithCache(hasClusterFlags bool) (*clusterconfig.Acces
 	if accessConfig.ClusterName == "" || accessConfig.Region == "" {
 		return nil, ErrorClusterAccessConfigRequired(hasClusterFlags)
 	}
+
+	// the manager image must be pulled from the cluster's registry mirror (if it has one)
+	if accessConfig.RegistryMirror == nil {
+		cachedClusterConfigPath := getCachedClusterConfigPath(accessConfig.ClusterName, accessConfig.Region)
+		if files.IsFile(cachedClusterConfigPath) {
+			cachedAccessConfig := &clusterconfig.AccessConfig{}
+			cr.ParseYAMLFile(cachedAccessConfig, clusterconfig.AccessValidation, cachedClusterConfigPath)
+			accessConfig.RegistryMirror = cachedAccessConfig.RegistryMirror
+		}
+	}
+	accessConfig.ImageManager = clusterconfig.MirrorImage(accessConfig.ImageManager, accessConfig.RegistryMirror)
 	return accessConfig, nil
 }
 
-func getInstallClusterConfig(awsClient *aws.Client, clusterConfigFile string) (*clusterconfig.Config, error) {
+func getInstallClusterConfig(awsClient *aws.Client, clusterConfigFile string, offline bool) (*clusterconfig.Config, error) {
 	clusterConfig := &clusterconfig.Config{}
 
 	err := readUserClusterConfigFile(clusterConfig, clusterConfigFile)
@@ -130,7 +153,10 @@ func getInstallClusterConfig(awsClient *aws.Client, clusterConfigFile string) (*
 		return nil, err
 	}
 
-	clusterConfig.Telemetry = isTelemetryEnabled()
+	clusterConfig.Offline = offline
+	clusterConfig.Telemetry = isTelemetryEnabled() && !offline
+
+	fmt.Print("verifying your configuration ...\n\n")
 
 	err = clusterConfig.ValidateOnInstall(awsClient)
 	if err != nil {
@@ -149,7 +175,7 @@ func getConfigureClusterConfig(awsClient *aws.Client, k8sClient *k8s.Client, sta
 		return nil, clusterconfig.ConfigureChanges{}, err
 	}
 
-	newUserClusterConfig.Telemetry = isTelemetryEnabled()
+	newUserClusterConfig.Telemetry = isTelemetryEnabled() && !cachedClusterConfig.Offline
 	cachedClusterConfig.Telemetry = newUserClusterConfig.Telemetry
 
 	configureChanges, err := newUserClusterConfig.ValidateOnConfigure(awsClient, k8sClient, cachedClusterConfig, stacks.NodeGroupsStacks)
@@ -161,10 +187,61 @@ func getConfigureClusterConfig(awsClient *aws.Client, k8sClient *k8s.Client, sta
 	return newUserClusterConfig, configureChanges, nil
 }
 
+func readNodeGroupConfigFile(filePath string) (*clusterconfig.NodeGroup, error) {
+	nodeGroup := &clusterconfig.NodeGroup{}
+
+	errs := cr.ParseYAMLFile(nodeGroup, clusterconfig.NodeGroupValidation, filePath)
+	if errors.HasError(errs) {
+		return nil, errors.Append(errors.FirstError(errs...), fmt.Sprintf("\n\nnodegroup configuration schema can be found at https://docs.cortexlabs.com/v/%s/", consts.CortexVersionMinor))
+	}
+
+	return nodeGroup, nil
+}
+
+// builds the new cluster config from the cached cluster config, with its nodegroups modified by updateNodeGroups
+func getNodeGroupsClusterConfig(awsClient *aws.Client, k8sClient *k8s.Client, stacks clusterstate.ClusterStacks, cachedClusterConfig clusterconfig.Config, updateNodeGroups func(nodeGroups []*clusterconfig.NodeGroup) ([]*clusterconfig.NodeGroup, error)) (*clusterconfig.Config, clusterconfig.ConfigureChanges, error) {
+	cachedClusterConfigCopy, err := cachedClusterConfig.DeepCopy()
+	if err != nil {
+		return nil, clusterconfig.ConfigureChanges{}, err
+	}
+
+	// only the user-facing fields are kept, since the managed fields are set during validation
+	newUserClusterConfig := &clusterconfig.Config{
+		CoreConfig: cachedClusterConfigCopy.CoreConfig,
+	}
+
+	newUserClusterConfig.NodeGroups, err = updateNodeGroups(newUserClusterConfig.NodeGroups)
+	if err != nil {
+		return nil, clusterconfig.ConfigureChanges{}, err
+	}
+
+	newUserClusterConfig.Telemetry = isTelemetryEnabled() && !cachedClusterConfig.Offline
+	cachedClusterConfig.Telemetry = newUserClusterConfig.Telemetry
+
+	configureChanges, err := newUserClusterConfig.ValidateOnConfigure(awsClient, k8sClient, cachedClusterConfig, stacks.NodeGroupsStacks)
+	if err != nil {
+		return nil, clusterconfig.ConfigureChanges{}, err
+	}
+
+	return newUserClusterConfig, configureChanges, nil
+}
+
+// returns 0 if the price is unknown
+// onDemandInstancePrice returns the hourly price of the instance type; if it can't be determined, the instance type is added to unknownPriceInstanceTypes and 0 is returned
+func onDemandInstancePrice(awsClient *aws.Client, instanceType string, unknownPriceInstanceTypes strset.Set) (float64, bool) {
+	price, err := awsClient.OnDemandInstancePrice(instanceType)
+	if err != nil {
+		unknownPriceInstanceTypes.Add(instanceType)
+		return 0, false
+	}
+	return price, true
+}
+
 func confirmInstallClusterConfig(clusterConfig *clusterconfig.Config, awsClient *aws.Client, disallowPrompt bool) {
 	eksPrice := aws.EKSPrices[clusterConfig.Region]
-	operatorInstancePrice := aws.InstanceMetadatas[clusterConfig.Region]["t3.medium"].Price
-	prometheusInstancePrice := aws.InstanceMetadatas[clusterConfig.Region][clusterConfig.PrometheusInstanceType].Price
+	unknownPriceInstanceTypes := strset.New()
+	operatorInstancePrice, operatorPriceKnown := onDemandInstancePrice(awsClient, clusterConfig.OperatorNodeGroupInstanceType(), unknownPriceInstanceTypes)
+	prometheusInstancePrice, prometheusPriceKnown := onDemandInstancePrice(awsClient, clusterConfig.PrometheusInstanceType, unknownPriceInstanceTypes)
 	operatorEBSPrice := aws.EBSMetadatas[clusterConfig.Region]["gp3"].PriceGB * 20 / 30 / 24
 	prometheusEBSPrice := aws.EBSMetadatas[clusterConfig.Region]["gp3"].PriceGB * 20 / 30 / 24
 	metricsEBSPrice := aws.EBSMetadatas[clusterConfig.Region]["gp2"].PriceGB * (40 + 2) / 30 / 24
@@ -200,7 +277,7 @@ func confirmInstallClusterConfig(clusterConfig *clusterconfig.Config, awsClient
 	totalMinPrice := fixedPrice
 	totalMaxPrice := fixedPrice
 	for _, ng := range clusterConfig.NodeGroups {
-		apiInstancePrice := aws.InstanceMetadatas[clusterConfig.Region][ng.InstanceType].Price
+		apiInstancePrice, apiPriceKnown := onDemandInstancePrice(awsClient, ng.InstanceType, unknownPriceInstanceTypes)
 		apiEBSPrice := aws.EBSMetadatas[clusterConfig.Region][ng.InstanceVolumeType.String()].PriceGB * float64(ng.InstanceVolumeSize) / 30 / 24
 		if ng.InstanceVolumeType == clusterconfig.IO1VolumeType && ng.InstanceVolumeIOPS != nil {
 			apiEBSPrice += aws.EBSMetadatas[clusterConfig.Region][ng.InstanceVolumeType.String()].PriceIOPS * float64(*ng.InstanceVolumeIOPS) / 30 / 24
@@ -218,12 +295,20 @@ func confirmInstallClusterConfig(clusterConfig *clusterconfig.Config, awsClient
 		}
 
 		workerPriceStr := s.DollarsAndTenthsOfCents(apiInstancePrice+apiEBSPrice) + " each"
-		if ng.Spot {
+		if !apiPriceKnown {
+			workerPriceStr = "unknown"
+			if ng.Spot {
+				ngNameToSpotInstancesUsed[ng.Name]++
+			}
+			totalMinPrice += float64(ng.MinInstances) * apiEBSPrice
+		} else if ng.Spot {
 			ngNameToSpotInstancesUsed[ng.Name]++
-			spotPrice, err := awsClient.SpotInstancePrice(ng.InstanceType)
+			// estimate with the median spot price over the lookback period, since the current price may be unusually low or high
+			spotPriceHistory, err := awsClient.SpotInstancePriceHistory(ng.InstanceType, _spotPriceLookback)
 			workerPriceStr += " (spot pricing unavailable)"
-			if err == nil && spotPrice != 0 {
-				workerPriceStr = fmt.Sprintf("%s - %s each (varies based on spot price)", s.DollarsAndTenthsOfCents(spotPrice+apiEBSPrice), s.DollarsAndTenthsOfCents(apiInstancePrice+apiEBSPrice))
+			if err == nil && spotPriceHistory.Stats.P50 != 0 {
+				spotPrice := spotPriceHistory.Stats.P50
+				workerPriceStr = fmt.Sprintf("%s - %s each (varies based on spot price; %s at the 90th percentile of the past week)", s.DollarsAndTenthsOfCents(spotPrice+apiEBSPrice), s.DollarsAndTenthsOfCents(apiInstancePrice+apiEBSPrice), s.DollarsAndTenthsOfCents(spotPriceHistory.Stats.P90+apiEBSPrice))
 				if ng.MinInstances > *ng.SpotConfig.OnDemandBaseCapacity {
 					totalMinPrice += float64(ng.MinInstances-*ng.SpotConfig.OnDemandBaseCapacity)*(spotPrice+apiEBSPrice)*float64(100-*ng.SpotConfig.OnDemandPercentageAboveBaseCapacity)/100 +
 						float64(ng.MinInstances-*ng.SpotConfig.OnDemandBaseCapacity)*(apiInstancePrice+apiEBSPrice)*float64(*ng.SpotConfig.OnDemandPercentageAboveBaseCapacity)/100 +
@@ -243,8 +328,16 @@ func confirmInstallClusterConfig(clusterConfig *clusterconfig.Config, awsClient
 
 	operatorNodeGroupPrice := 2 * (operatorInstancePrice + operatorEBSPrice)
 	prometheusNodeGroupPrice := prometheusInstancePrice + prometheusEBSPrice + metricsEBSPrice
-	rows = append(rows, []interface{}{"2 t3.medium instances (cortex system)", s.DollarsAndTenthsOfCents(operatorNodeGroupPrice) + " total"})
-	rows = append(rows, []interface{}{fmt.Sprintf("1 %s instance (prometheus)", clusterConfig.PrometheusInstanceType), s.DollarsAndTenthsOfCents(prometheusNodeGroupPrice)})
+	operatorNodeGroupPriceStr := s.DollarsAndTenthsOfCents(operatorNodeGroupPrice) + " total"
+	if !operatorPriceKnown {
+		operatorNodeGroupPriceStr = "unknown"
+	}
+	prometheusNodeGroupPriceStr := s.DollarsAndTenthsOfCents(prometheusNodeGroupPrice)
+	if !prometheusPriceKnown {
+		prometheusNodeGroupPriceStr = "unknown"
+	}
+	rows = append(rows, []interface{}{fmt.Sprintf("2 %s instances (cortex system)", clusterConfig.OperatorNodeGroupInstanceType()), operatorNodeGroupPriceStr})
+	rows = append(rows, []interface{}{fmt.Sprintf("1 %s instance (prometheus)", clusterConfig.PrometheusInstanceType), prometheusNodeGroupPriceStr})
 	if usesELBForAPILoadBalancer {
 		rows = append(rows, []interface{}{"1 network load balancer", s.DollarsMaxPrecision(nlbPrice)})
 		rows = append(rows, []interface{}{"1 classic load balancer", s.DollarsMaxPrecision(elbPrice)})
@@ -279,6 +372,11 @@ func confirmInstallClusterConfig(clusterConfig *clusterconfig.Config, awsClient
 
 	fmt.Printf("your cluster will cost %s per hour%s\n\n", priceStr, suffix)
 
+	if len(unknownPriceInstanceTypes) > 0 {
+		instanceTypes := unknownPriceInstanceTypes.SliceSorted()
+		fmt.Printf("warning: the on-demand price of %s %s could not be retrieved, so %s not included in the estimate above\n\n", s.PluralS("instance type", len(instanceTypes)), s.StrsAnd(instanceTypes), s.PluralCustom("it is", "they are", len(instanceTypes)))
+	}
+
 	privateSubnetMsg := ""
 	if clusterConfig.SubnetVisibility == clusterconfig.PrivateSubnetVisibility {
 		privateSubnetMsg = ", and will use private subnets for all EC2 instances"
@@ -297,12 +395,74 @@ func confirmInstallClusterConfig(clusterConfig *clusterconfig.Config, awsClient
 		fmt.Printf("warning: you've enabled spot instances for %s %s; spot instances are not guaranteed to be available so please take that into account for production clusters; see https://docs.cortexlabs.com/v/%s/ for more information\n\n", s.PluralS("nodegroup", len(ngNameToSpotInstancesUsed)), s.StrsAnd(maps.StrMapKeysInt(ngNameToSpotInstancesUsed)), consts.CortexVersionMinor)
 	}
 
+	printSpotPlacementWarnings(clusterConfig, awsClient)
+
 	if !disallowPrompt {
 		exitMessage := fmt.Sprintf("cluster configuration can be modified via the cluster config file; see https://docs.cortexlabs.com/v/%s/ for more information", consts.CortexVersionMinor)
 		prompt.YesOrExit("would you like to continue?", "", exitMessage)
 	}
 }
 
+// warns about spot nodegroups which are unlikely to get capacity in the cluster's availability zones
+func printSpotPlacementWarnings(clusterConfig *clusterconfig.Config, awsClient *aws.Client) {
+	zones := clusterConfig.AvailabilityZones
+	for _, subnet := range clusterConfig.Subnets {
+		zones = append(zones, subnet.AvailabilityZone)
+	}
+
+	for _, ng := range clusterConfig.NodeGroups {
+		if !ng.Spot {
+			continue
+		}
+
+		instanceTypes := slices.UniqueStrings(append([]string{ng.InstanceType}, ng.SpotConfig.InstanceDistribution...))
+		targetCapacity := libmath.MaxInt64(ng.MaxInstances, 1)
+
+		scores, err := awsClient.SpotPlacementScores(instanceTypes, int(targetCapacity))
+		if err != nil {
+			// placement scores are only advisory (e.g. the caller might not have the ec2:GetSpotPlacementScores permission)
+			continue
+		}
+
+		var bestZoneScore int64
+		for _, zone := range zones {
+			bestZoneScore = libmath.MaxInt64(bestZoneScore, scores.AvailabilityZones[zone])
+		}
+		if bestZoneScore == 0 {
+			bestZoneScore = scores.Regions[clusterConfig.Region]
+		}
+		if bestZoneScore == 0 || bestZoneScore > _lowSpotPlacementScore {
+			continue
+		}
+
+		var betterRegions []string
+		for region, score := range scores.Regions {
+			if score > bestZoneScore {
+				betterRegions = append(betterRegions, region)
+			}
+		}
+		sort.Slice(betterRegions, func(i, j int) bool {
+			if scores.Regions[betterRegions[i]] != scores.Regions[betterRegions[j]] {
+				return scores.Regions[betterRegions[i]] > scores.Regions[betterRegions[j]]
+			}
+			return betterRegions[i] < betterRegions[j]
+		})
+		if len(betterRegions) > _maxSuggestedSpotRegionsToShow {
+			betterRegions = betterRegions[:_maxSuggestedSpotRegionsToShow]
+		}
+
+		fmt.Printf("warning: nodegroup %s is unlikely to get spot capacity for %d %s in %s (spot placement score: %d out of 10)", ng.Name, targetCapacity, s.PluralS("instance", targetCapacity), s.StrsAnd(zones), bestZoneScore)
+		if len(betterRegions) > 0 {
+			regionStrs := make([]string, len(betterRegions))
+			for i, region := range betterRegions {
+				regionStrs[i] = fmt.Sprintf("%s (%d)", region, scores.Regions[region])
+			}
+			fmt.Printf("; regions with a higher score: %s", s.StrsAnd(regionStrs))
+		}
+		fmt.Print("; consider adding instance types to spot_config.instance_distribution or adding an on-demand backup nodegroup\n\n")
+	}
+}
+
 func confirmConfigureClusterConfig(configureChanges clusterconfig.ConfigureChanges, oldCc, newCc clusterconfig.Config, disallowPrompt bool) {
 	fmt.Printf("your %s cluster in region %s will be updated as follows:\n\n", newCc.ClusterName, newCc.Region)
 
@@ -336,3 +496,20 @@ func confirmConfigureClusterConfig(configureChanges clusterconfig.ConfigureChang
 		prompt.YesOrExit(fmt.Sprintf("your cluster named \"%s\" in %s will be updated according to the configuration above, are you sure you want to continue?", newCc.ClusterName, newCc.Region), "", exitMessage)
 	}
 }
+
+func confirmScaleNodeGroup(oldNg, newNg *clusterconfig.NodeGroup, cc clusterconfig.Config, disallowPrompt bool) {
+	fmt.Printf("your %s cluster in region %s will be updated as follows:\n\n", cc.ClusterName, cc.Region)
+	fmt.Printf("￮ %s\n\n", newNg.UpdatePlan(oldNg))
+
+	for _, schedule := range cc.Schedules {
+		if schedule.NodeGroup == newNg.Name {
+			fmt.Printf("note: nodegroup %s has %s configured, so its min/max instances will be overridden the next time one of them fires\n\n", newNg.Name, clusterconfig.SchedulesKey)
+			break
+		}
+	}
+
+	if !disallowPrompt {
+		exitMessage := fmt.Sprintf("nodegroups can also be scaled via the cluster config file; see https://docs.cortexlabs.com/v/%s/ for more information", consts.CortexVersionMinor)
+		prompt.YesOrExit(fmt.Sprintf("your cluster named \"%s\" in %s will be updated according to the configuration above, are you sure you want to continue?", cc.ClusterName, cc.Region), "", exitMessage)
+	}
+}
@@ -0,0 +1,201 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/cortexlabs/cortex/pkg/consts"
+	"github.com/cortexlabs/cortex/pkg/lib/aws"
+	cr "github.com/cortexlabs/cortex/pkg/lib/configreader"
+	"github.com/cortexlabs/cortex/pkg/lib/files"
+	libmath "github.com/cortexlabs/cortex/pkg/lib/math"
+	"github.com/cortexlabs/cortex/pkg/lib/pointer"
+	"github.com/cortexlabs/cortex/pkg/lib/prompt"
+	"github.com/cortexlabs/cortex/pkg/lib/sets/strset"
+	"github.com/cortexlabs/cortex/pkg/lib/slices"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	"github.com/cortexlabs/cortex/pkg/types/clusterconfig"
+)
+
+const (
+	_defaultInteractiveClusterConfigPath = "cluster.yaml"
+	_defaultInteractiveRegion            = "us-east-1"
+	_defaultInteractiveInstanceType      = "m5.large"
+)
+
+type interactiveClusterConfig struct {
+	ClusterName  string
+	Region       string
+	InstanceType string
+	Spot         bool
+	MinInstances int64
+	MaxInstances int64
+}
+
+// prompts for the basic cluster configuration fields and writes the resulting cluster config file to clusterConfigPath
+func promptAndWriteClusterConfig(clusterConfigPath string) error {
+	if files.IsFile(clusterConfigPath) {
+		prompt.YesOrExit(fmt.Sprintf("%s already exists; would you like to overwrite it?", clusterConfigPath), "", "you can specify a different path for the cluster configuration file (e.g. `cortex cluster up my-cluster.yaml --interactive`)")
+		fmt.Println()
+	}
+
+	// DescribeRegions can be called from any enabled region
+	awsClient, err := newAWSClient(_defaultInteractiveRegion, false)
+	if err != nil {
+		return err
+	}
+
+	enabledRegions, err := awsClient.ListEnabledRegions()
+	if err != nil {
+		return err
+	}
+	regions := strset.Intersection(enabledRegions, aws.EKSSupportedRegions).SliceSorted()
+
+	fmt.Printf("the following regions are enabled for your account and supported by cortex: %s\n\n", s.StrsAnd(regions))
+
+	defaultRegion := ""
+	if slices.HasString(regions, _defaultInteractiveRegion) {
+		defaultRegion = _defaultInteractiveRegion
+	}
+
+	config := &interactiveClusterConfig{}
+
+	err = cr.ReadPrompt(config, &cr.PromptValidation{
+		PromptItemValidations: []*cr.PromptItemValidation{
+			{
+				StructField: "ClusterName",
+				PromptOpts: &prompt.Options{
+					Prompt: "cluster name",
+				},
+				StringValidation: &cr.StringValidation{
+					Default:   "cortex",
+					Validator: clusterconfig.ClusterNameValidator,
+				},
+			},
+			{
+				StructField: "Region",
+				PromptOpts: &prompt.Options{
+					Prompt: "aws region",
+				},
+				StringValidation: &cr.StringValidation{
+					Required:      defaultRegion == "",
+					Default:       defaultRegion,
+					AllowedValues: regions,
+				},
+			},
+			{
+				StructField: "InstanceType",
+				PromptOpts: &prompt.Options{
+					Prompt: "instance type for your apis' node group",
+				},
+				StringValidation: &cr.StringValidation{
+					Default:   _defaultInteractiveInstanceType,
+					Validator: validateInteractiveInstanceType,
+				},
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	config.Spot = prompt.YesOrNo("would you like to use spot instances? (spot instances are cheaper, but can be interrupted)", "", "")
+	fmt.Println()
+
+	err = cr.ReadPrompt(config, &cr.PromptValidation{
+		PromptItemValidations: []*cr.PromptItemValidation{
+			{
+				StructField: "MinInstances",
+				PromptOpts: &prompt.Options{
+					Prompt: "minimum number of instances",
+				},
+				Int64Validation: &cr.Int64Validation{
+					Default:              1,
+					GreaterThanOrEqualTo: pointer.Int64(0),
+				},
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	err = cr.ReadPrompt(config, &cr.PromptValidation{
+		PromptItemValidations: []*cr.PromptItemValidation{
+			{
+				StructField: "MaxInstances",
+				PromptOpts: &prompt.Options{
+					Prompt: "maximum number of instances",
+				},
+				Int64Validation: &cr.Int64Validation{
+					Default:              libmath.MaxInt64(5, config.MinInstances),
+					GreaterThan:          pointer.Int64(0),
+					GreaterThanOrEqualTo: pointer.Int64(config.MinInstances),
+				},
+			},
+		},
+		PrintNewLineIfPrompted: true,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := files.WriteFile([]byte(config.yamlStr()), clusterConfigPath); err != nil {
+		return err
+	}
+
+	fmt.Printf("your cluster configuration has been saved to %s (you can edit this file to configure additional fields; see https://docs.cortexlabs.com/v/%s/ for more information)\n\n", clusterConfigPath, consts.CortexVersionMinor)
+
+	return nil
+}
+
+func validateInteractiveInstanceType(instanceType string) (string, error) {
+	if _, err := aws.ParseInstanceType(instanceType); err != nil {
+		return "", err
+	}
+	return instanceType, nil
+}
+
+func (config *interactiveClusterConfig) nodeGroupName() string {
+	if isGPU, _ := aws.IsGPUInstance(config.InstanceType); isGPU {
+		return "ng-gpu"
+	}
+	if isInf, _ := aws.IsInferentiaInstance(config.InstanceType); isInf {
+		return "ng-inf"
+	}
+	return "ng-cpu"
+}
+
+func (config *interactiveClusterConfig) yamlStr() string {
+	return fmt.Sprintf(`# cluster name
+cluster_name: %s
+
+# AWS region
+region: %s
+
+# list of cluster node groups
+node_groups:
+  - name: %s # name of the node group
+    instance_type: %s # instance type
+    min_instances: %d # minimum number of instances
+    max_instances: %d # maximum number of instances
+    spot: %s # whether to use spot instances
+
+# the full cluster configuration schema can be found at https://docs.cortexlabs.com/v/%s/
+`, config.ClusterName, config.Region, config.nodeGroupName(), config.InstanceType, config.MinInstances, config.MaxInstances, s.Bool(config.Spot), consts.CortexVersionMinor)
+}
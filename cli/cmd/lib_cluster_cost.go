@@ -0,0 +1,209 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	awslib "github.com/cortexlabs/cortex/pkg/lib/aws"
+	"github.com/cortexlabs/cortex/pkg/lib/console"
+	"github.com/cortexlabs/cortex/pkg/lib/pointer"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	"github.com/cortexlabs/cortex/pkg/lib/table"
+	"github.com/cortexlabs/cortex/pkg/types/clusterconfig"
+)
+
+const (
+	_maxClusterCostDays       = 365
+	_nodeGroupNameTagKey      = "alpha.eksctl.io/nodegroup-name"
+	_sharedCostNodeGroupName  = "shared"
+	_costExplorerDateFormat   = "2006-01-02"
+	_operatorNodeGroupName    = "cx-operator"
+	_prometheusNodeGroupName  = "cx-prometheus"
+	_onDemandNodeGroupPrefix  = "cx-wd-"
+	_spotNodeGroupPrefix      = "cx-ws-"
+	_cortexSystemNameModifier = " (cortex system)"
+)
+
+type clusterCost struct {
+	ClusterName string          `json:"cluster_name"`
+	Region      string          `json:"region"`
+	StartDate   string          `json:"start_date"`
+	EndDate     string          `json:"end_date"`
+	NodeGroups  []nodeGroupCost `json:"nodegroups"`
+	Total       costBreakdown   `json:"total"`
+}
+
+type nodeGroupCost struct {
+	Name string `json:"name"`
+	costBreakdown
+}
+
+type costBreakdown struct {
+	EC2        float64 `json:"ec2"`
+	EBS        float64 `json:"ebs"`
+	NATGateway float64 `json:"nat_gateway"`
+	ELB        float64 `json:"elb"`
+	Other      float64 `json:"other"`
+	Total      float64 `json:"total"`
+}
+
+func (cb *costBreakdown) add(category string, amount float64) {
+	switch category {
+	case awslib.CostCategoryEC2:
+		cb.EC2 += amount
+	case awslib.CostCategoryEBS:
+		cb.EBS += amount
+	case awslib.CostCategoryNATGateway:
+		cb.NATGateway += amount
+	case awslib.CostCategoryELB:
+		cb.ELB += amount
+	default:
+		cb.Other += amount
+	}
+	cb.Total += amount
+}
+
+func getClusterCost(awsClient *awslib.Client, accessConfig *clusterconfig.AccessConfig, days int) (*clusterCost, error) {
+	// cost explorer's end date is exclusive, so end tomorrow in order to include today's (partial) spend
+	now := time.Now().UTC()
+	end := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, 1)
+	start := end.AddDate(0, 0, -days)
+
+	costGroups, err := awsClient.CostByTag(start, end, clusterconfig.ClusterNameTag, accessConfig.ClusterName, _nodeGroupNameTagKey)
+	if err != nil {
+		return nil, err
+	}
+
+	clusterCost := &clusterCost{
+		ClusterName: accessConfig.ClusterName,
+		Region:      accessConfig.Region,
+		StartDate:   start.Format(_costExplorerDateFormat),
+		EndDate:     end.AddDate(0, 0, -1).Format(_costExplorerDateFormat),
+	}
+
+	nodeGroupCosts := map[string]*nodeGroupCost{}
+	for _, costGroup := range costGroups {
+		name := costNodeGroupName(costGroup.TagValue)
+		if _, ok := nodeGroupCosts[name]; !ok {
+			nodeGroupCosts[name] = &nodeGroupCost{Name: name}
+		}
+
+		category := awslib.UsageTypeCostCategory(costGroup.UsageType)
+		nodeGroupCosts[name].add(category, costGroup.Amount)
+		clusterCost.Total.add(category, costGroup.Amount)
+	}
+
+	for _, ngCost := range nodeGroupCosts {
+		clusterCost.NodeGroups = append(clusterCost.NodeGroups, *ngCost)
+	}
+	sort.Slice(clusterCost.NodeGroups, func(i, j int) bool {
+		return costNodeGroupLess(clusterCost.NodeGroups[i].Name, clusterCost.NodeGroups[j].Name)
+	})
+
+	return clusterCost, nil
+}
+
+// maps the value of the eksctl nodegroup tag to the name of the nodegroup in the cluster config
+func costNodeGroupName(tagValue string) string {
+	switch {
+	case tagValue == "":
+		// resources which aren't part of a nodegroup (e.g. nat gateways, load balancers, the eks control plane)
+		return _sharedCostNodeGroupName
+	case tagValue == _operatorNodeGroupName:
+		return "operator" + _cortexSystemNameModifier
+	case tagValue == _prometheusNodeGroupName:
+		return "prometheus" + _cortexSystemNameModifier
+	case strings.HasPrefix(tagValue, _onDemandNodeGroupPrefix):
+		return strings.TrimPrefix(tagValue, _onDemandNodeGroupPrefix)
+	case strings.HasPrefix(tagValue, _spotNodeGroupPrefix):
+		return strings.TrimPrefix(tagValue, _spotNodeGroupPrefix)
+	default:
+		return tagValue
+	}
+}
+
+// workload nodegroups first, then the cortex system nodegroups, then shared resources
+func costNodeGroupLess(a string, b string) bool {
+	rank := func(name string) int {
+		switch {
+		case name == _sharedCostNodeGroupName:
+			return 2
+		case strings.HasSuffix(name, _cortexSystemNameModifier):
+			return 1
+		default:
+			return 0
+		}
+	}
+
+	if rank(a) != rank(b) {
+		return rank(a) < rank(b)
+	}
+	return a < b
+}
+
+func printClusterCost(clusterCost *clusterCost) {
+	if len(clusterCost.NodeGroups) == 0 {
+		fmt.Printf("no spend was found for cluster %s between %s and %s\n\n", clusterCost.ClusterName, clusterCost.StartDate, clusterCost.EndDate)
+		printClusterCostNote()
+		return
+	}
+
+	fmt.Printf(console.Bold("cluster %s spent %s between %s and %s\n\n"), clusterCost.ClusterName, s.DollarsAndCents(clusterCost.Total.Total), clusterCost.StartDate, clusterCost.EndDate)
+
+	rows := make([][]interface{}, 0, len(clusterCost.NodeGroups)+1)
+	for _, ngCost := range clusterCost.NodeGroups {
+		rows = append(rows, costBreakdownRow(ngCost.Name, ngCost.costBreakdown))
+	}
+	rows = append(rows, costBreakdownRow("total", clusterCost.Total))
+
+	t := table.Table{
+		Headers: []table.Header{
+			{Title: "nodegroup"},
+			{Title: awslib.CostCategoryEC2},
+			{Title: awslib.CostCategoryEBS},
+			{Title: awslib.CostCategoryNATGateway},
+			{Title: awslib.CostCategoryELB},
+			{Title: awslib.CostCategoryOther},
+			{Title: "total"},
+		},
+		Rows: rows,
+	}
+	t.MustPrint(&table.Opts{Sort: pointer.Bool(false)})
+
+	fmt.Println()
+	printClusterCostNote()
+}
+
+func costBreakdownRow(name string, cb costBreakdown) []interface{} {
+	return []interface{}{
+		name,
+		s.DollarsAndCents(cb.EC2),
+		s.DollarsAndCents(cb.EBS),
+		s.DollarsAndCents(cb.NATGateway),
+		s.DollarsAndCents(cb.ELB),
+		s.DollarsAndCents(cb.Other),
+		s.DollarsAndCents(cb.Total),
+	}
+}
+
+func printClusterCostNote() {
+	fmt.Printf("note: cost explorer data can lag by up to 24 hours, and only includes spend from resources tagged after the %s and %s cost allocation tags were activated in the aws billing console\n", clusterconfig.ClusterNameTag, _nodeGroupNameTagKey)
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/PEAT-AI/yaml"
+	"github.com/cortexlabs/cortex/pkg/consts"
+	"github.com/cortexlabs/cortex/pkg/lib/archive"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+)
+
+const (
+	_clusterExportMetadataFileName = "metadata.yaml"
+	_clusterExportConfigFileName   = "cluster.yaml"
+	_clusterExportAPIsDir          = "apis"
+)
+
+type clusterExportMetadata struct {
+	CortexVersion      string   `json:"cortex_version" yaml:"cortex_version"`
+	ClusterName        string   `json:"cluster_name" yaml:"cluster_name"`
+	Region             string   `json:"region" yaml:"region"`
+	ExportedAt         string   `json:"exported_at" yaml:"exported_at"`
+	Environments       []string `json:"environments" yaml:"environments"`
+	DefaultEnvironment *string  `json:"default_environment" yaml:"default_environment"`
+}
+
+type clusterExport struct {
+	Metadata      clusterExportMetadata
+	ClusterConfig []byte            // the cluster configuration, which can be used to spin up a new cluster
+	APIConfigs    map[string][]byte // api name -> submitted api configuration
+}
+
+func clusterExportFileName(clusterName string, region string) string {
+	return fmt.Sprintf("export-%s-%s.tgz", region, clusterName)
+}
+
+func newClusterExportMetadata(clusterName string, region string, envNames []string, defaultEnv *string) clusterExportMetadata {
+	metadata := clusterExportMetadata{
+		CortexVersion: consts.CortexVersion,
+		ClusterName:   clusterName,
+		Region:        region,
+		ExportedAt:    time.Now().UTC().Format(time.RFC3339),
+		Environments:  envNames,
+	}
+	for _, envName := range envNames {
+		if defaultEnv != nil && *defaultEnv == envName {
+			metadata.DefaultEnvironment = defaultEnv
+		}
+	}
+	return metadata
+}
+
+func (export clusterExport) sortedAPINames() []string {
+	apiNames := make([]string, 0, len(export.APIConfigs))
+	for apiName := range export.APIConfigs {
+		apiNames = append(apiNames, apiName)
+	}
+	sort.Strings(apiNames)
+	return apiNames
+}
+
+func writeClusterExport(export clusterExport, exportPath string) error {
+	metadataBytes, err := yaml.Marshal(export.Metadata)
+	if err != nil {
+		return err
+	}
+
+	archiveInput := &archive.Input{
+		Bytes: []archive.BytesInput{
+			{Content: metadataBytes, Dest: _clusterExportMetadataFileName},
+			{Content: export.ClusterConfig, Dest: _clusterExportConfigFileName},
+		},
+	}
+	for _, apiName := range export.sortedAPINames() {
+		archiveInput.Bytes = append(archiveInput.Bytes, archive.BytesInput{
+			Content: export.APIConfigs[apiName],
+			Dest:    path.Join(_clusterExportAPIsDir, apiName+".yaml"),
+		})
+	}
+
+	_, err = archive.TgzToFile(archiveInput, exportPath)
+	return err
+}
+
+func readClusterExport(exportPath string) (*clusterExport, error) {
+	fileMap, err := archive.UntgzFileToMem(exportPath)
+	if err != nil {
+		return nil, ErrorInvalidClusterExport(exportPath, errors.Message(err))
+	}
+
+	metadataBytes, ok := fileMap[_clusterExportMetadataFileName]
+	if !ok {
+		return nil, ErrorInvalidClusterExport(exportPath, fmt.Sprintf("%s was not found in the archive", _clusterExportMetadataFileName))
+	}
+
+	export := clusterExport{
+		ClusterConfig: fileMap[_clusterExportConfigFileName],
+		APIConfigs:    map[string][]byte{},
+	}
+	if err := yaml.Unmarshal(metadataBytes, &export.Metadata); err != nil {
+		return nil, ErrorInvalidClusterExport(exportPath, errors.Message(err, _clusterExportMetadataFileName))
+	}
+
+	for filePath, fileBytes := range fileMap {
+		if path.Dir(filePath) != _clusterExportAPIsDir || path.Ext(filePath) != ".yaml" {
+			continue
+		}
+		export.APIConfigs[strings.TrimSuffix(path.Base(filePath), ".yaml")] = fileBytes
+	}
+
+	return &export, nil
+}
@@ -0,0 +1,432 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/PEAT-AI/yaml"
+	"github.com/cortexlabs/cortex/pkg/lib/aws"
+	"github.com/cortexlabs/cortex/pkg/types/clusterconfig"
+)
+
+type cfnMap = yaml.MapSlice
+
+var _cfnLogicalIDSeparators = regexp.MustCompile(`[^a-zA-Z0-9]+`)
+
+// converts a name to a cloudformation logical id (e.g. cx-wd-ng-cpu -> CxWdNgCpu)
+func cfnLogicalID(parts ...string) string {
+	var logicalID strings.Builder
+	for _, part := range parts {
+		for _, word := range _cfnLogicalIDSeparators.Split(part, -1) {
+			if word == "" {
+				continue
+			}
+			logicalID.WriteString(strings.ToUpper(word[:1]) + word[1:])
+		}
+	}
+	return logicalID.String()
+}
+
+func cfnRef(logicalID string) cfnMap {
+	return cfnMap{{Key: "Ref", Value: logicalID}}
+}
+
+func cfnGetAtt(logicalID string, attribute string) cfnMap {
+	return cfnMap{{Key: "Fn::GetAtt", Value: []string{logicalID, attribute}}}
+}
+
+func cfnTags(tags map[string]string) []cfnMap {
+	cfnTags := make([]cfnMap, 0, len(tags))
+	for _, key := range sortedKeys(tags) {
+		cfnTags = append(cfnTags, cfnMap{{Key: "Key", Value: key}, {Key: "Value", Value: tags[key]}})
+	}
+	return cfnTags
+}
+
+func cfnResource(resourceType string, properties cfnMap, extra ...yaml.MapItem) cfnMap {
+	resource := cfnMap{{Key: "Type", Value: resourceType}}
+	resource = append(resource, extra...)
+	return append(resource, yaml.MapItem{Key: "Properties", Value: properties})
+}
+
+// resources which already exist when the cluster is exported, and can be brought under management with a resource import
+var _cfnRetain = yaml.MapItem{Key: "DeletionPolicy", Value: "Retain"}
+
+// renders the cluster's infrastructure as a cloudformation template
+func iacCloudFormation(cluster *iacCluster) (string, error) {
+	var parameters cfnMap
+	for _, amiType := range cluster.amiTypes() {
+		parameters = append(parameters, yaml.MapItem{Key: cfnLogicalID("ami", amiType), Value: cfnMap{
+			{Key: "Type", Value: "AWS::SSM::Parameter::Value<AWS::EC2::Image::Id>"},
+			{Key: "Default", Value: aws.EKSAMIParameterName(cluster.K8sVersion, amiType)},
+		}})
+	}
+
+	publicSubnets, privateSubnets, resources := iacCloudFormationNetwork(cluster)
+
+	iamResources, err := iacCloudFormationIAM(cluster)
+	if err != nil {
+		return "", err
+	}
+	resources = append(resources, iamResources...)
+
+	var clusterSubnets []interface{}
+	for _, zone := range cluster.zones() {
+		clusterSubnets = append(clusterSubnets, publicSubnets[zone]...)
+		clusterSubnets = append(clusterSubnets, privateSubnets[zone]...)
+	}
+
+	resources = append(resources,
+		yaml.MapItem{Key: "Bucket", Value: cfnResource("AWS::S3::Bucket", cfnMap{
+			{Key: "BucketName", Value: cluster.Bucket},
+		}, _cfnRetain)},
+		yaml.MapItem{Key: "LogGroup", Value: cfnResource("AWS::Logs::LogGroup", cfnMap{
+			{Key: "LogGroupName", Value: cluster.LogGroup},
+		}, _cfnRetain)},
+		yaml.MapItem{Key: "Cluster", Value: cfnResource("AWS::EKS::Cluster", cfnMap{
+			{Key: "Name", Value: cluster.Name},
+			{Key: "Version", Value: cluster.K8sVersion},
+			{Key: "RoleArn", Value: cfnGetAtt("ClusterRole", "Arn")},
+			{Key: "ResourcesVpcConfig", Value: cfnMap{
+				{Key: "SubnetIds", Value: uniqueCFNValues(clusterSubnets)},
+			}},
+		}, _cfnRetain)},
+	)
+
+	nodeSubnets := publicSubnets
+	if cluster.SubnetVisibility == clusterconfig.PrivateSubnetVisibility {
+		nodeSubnets = privateSubnets
+	}
+	for _, ng := range cluster.NodeGroups {
+		resources = append(resources, iacCloudFormationNodeGroup(cluster, ng, nodeSubnets)...)
+	}
+
+	template := cfnMap{
+		{Key: "AWSTemplateFormatVersion", Value: "2010-09-09"},
+		{Key: "Description", Value: fmt.Sprintf("infrastructure of the cortex cluster %s", cluster.Name)},
+		{Key: "Parameters", Value: parameters},
+		{Key: "Resources", Value: resources},
+	}
+
+	templateBytes, err := yaml.Marshal(template)
+	if err != nil {
+		return "", err
+	}
+
+	return iacHeader(cluster, "#") + "\n" + string(templateBytes), nil
+}
+
+// returns the subnets in each availability zone, and the resources of the vpc (if the cluster doesn't use existing subnets)
+func iacCloudFormationNetwork(cluster *iacCluster) (map[string][]interface{}, map[string][]interface{}, cfnMap) {
+	publicSubnets := map[string][]interface{}{}
+	privateSubnets := map[string][]interface{}{}
+
+	if cluster.VPC == nil {
+		for _, subnet := range cluster.ExistingSubnets {
+			// existing subnets are all public or all private, depending on the cluster's subnet visibility
+			if cluster.SubnetVisibility == clusterconfig.PrivateSubnetVisibility {
+				privateSubnets[subnet.AvailabilityZone] = append(privateSubnets[subnet.AvailabilityZone], subnet.SubnetID)
+			} else {
+				publicSubnets[subnet.AvailabilityZone] = append(publicSubnets[subnet.AvailabilityZone], subnet.SubnetID)
+			}
+		}
+		return publicSubnets, privateSubnets, nil
+	}
+
+	clusterOwnershipTag := "kubernetes.io/cluster/" + cluster.Name
+
+	resources := cfnMap{
+		{Key: "VPC", Value: cfnResource("AWS::EC2::VPC", cfnMap{
+			{Key: "CidrBlock", Value: cluster.VPC.CIDR},
+			{Key: "EnableDnsHostnames", Value: true},
+			{Key: "EnableDnsSupport", Value: true},
+			{Key: "Tags", Value: cfnTags(map[string]string{"Name": cluster.Name})},
+		})},
+		{Key: "InternetGateway", Value: cfnResource("AWS::EC2::InternetGateway", cfnMap{})},
+		{Key: "VPCGatewayAttachment", Value: cfnResource("AWS::EC2::VPCGatewayAttachment", cfnMap{
+			{Key: "VpcId", Value: cfnRef("VPC")},
+			{Key: "InternetGatewayId", Value: cfnRef("InternetGateway")},
+		})},
+		{Key: "PublicRouteTable", Value: cfnResource("AWS::EC2::RouteTable", cfnMap{
+			{Key: "VpcId", Value: cfnRef("VPC")},
+		})},
+		{Key: "PublicRoute", Value: cfnResource("AWS::EC2::Route", cfnMap{
+			{Key: "RouteTableId", Value: cfnRef("PublicRouteTable")},
+			{Key: "DestinationCidrBlock", Value: "0.0.0.0/0"},
+			{Key: "GatewayId", Value: cfnRef("InternetGateway")},
+		}, yaml.MapItem{Key: "DependsOn", Value: "VPCGatewayAttachment"})},
+	}
+
+	for _, zone := range cluster.VPC.Zones {
+		publicSubnetID := cfnLogicalID("public", "subnet", zone.AvailabilityZone)
+		privateSubnetID := cfnLogicalID("private", "subnet", zone.AvailabilityZone)
+		publicSubnets[zone.AvailabilityZone] = []interface{}{cfnRef(publicSubnetID)}
+		privateSubnets[zone.AvailabilityZone] = []interface{}{cfnRef(privateSubnetID)}
+
+		resources = append(resources,
+			yaml.MapItem{Key: publicSubnetID, Value: cfnResource("AWS::EC2::Subnet", cfnMap{
+				{Key: "VpcId", Value: cfnRef("VPC")},
+				{Key: "CidrBlock", Value: zone.PublicCIDR},
+				{Key: "AvailabilityZone", Value: zone.AvailabilityZone},
+				{Key: "MapPublicIpOnLaunch", Value: true},
+				{Key: "Tags", Value: cfnTags(map[string]string{
+					"Name":                   fmt.Sprintf("%s-public-%s", cluster.Name, zone.AvailabilityZone),
+					"kubernetes.io/role/elb": "1",
+					clusterOwnershipTag:      "shared",
+				})},
+			})},
+			yaml.MapItem{Key: cfnLogicalID("public", "route", "table", "association", zone.AvailabilityZone), Value: cfnResource("AWS::EC2::SubnetRouteTableAssociation", cfnMap{
+				{Key: "SubnetId", Value: cfnRef(publicSubnetID)},
+				{Key: "RouteTableId", Value: cfnRef("PublicRouteTable")},
+			})},
+			yaml.MapItem{Key: privateSubnetID, Value: cfnResource("AWS::EC2::Subnet", cfnMap{
+				{Key: "VpcId", Value: cfnRef("VPC")},
+				{Key: "CidrBlock", Value: zone.PrivateCIDR},
+				{Key: "AvailabilityZone", Value: zone.AvailabilityZone},
+				{Key: "Tags", Value: cfnTags(map[string]string{
+					"Name":                            fmt.Sprintf("%s-private-%s", cluster.Name, zone.AvailabilityZone),
+					"kubernetes.io/role/internal-elb": "1",
+					clusterOwnershipTag:               "shared",
+				})},
+			})},
+		)
+	}
+
+	for i, zone := range cluster.VPC.Zones {
+		privateRouteTableID := cfnLogicalID("private", "route", "table", zone.AvailabilityZone)
+
+		resources = append(resources,
+			yaml.MapItem{Key: privateRouteTableID, Value: cfnResource("AWS::EC2::RouteTable", cfnMap{
+				{Key: "VpcId", Value: cfnRef("VPC")},
+			})},
+			yaml.MapItem{Key: cfnLogicalID("private", "route", "table", "association", zone.AvailabilityZone), Value: cfnResource("AWS::EC2::SubnetRouteTableAssociation", cfnMap{
+				{Key: "SubnetId", Value: cfnRef(cfnLogicalID("private", "subnet", zone.AvailabilityZone))},
+				{Key: "RouteTableId", Value: cfnRef(privateRouteTableID)},
+			})},
+		)
+
+		natID := ""
+		switch cluster.VPC.NATGateway {
+		case clusterconfig.SingleNATGateway:
+			natID = "NATGateway"
+		case clusterconfig.HighlyAvailableNATGateway:
+			natID = cfnLogicalID("NATGateway", zone.AvailabilityZone)
+		}
+		if natID == "" {
+			continue
+		}
+
+		if cluster.VPC.NATGateway == clusterconfig.HighlyAvailableNATGateway || i == 0 {
+			resources = append(resources,
+				yaml.MapItem{Key: natID + "EIP", Value: cfnResource("AWS::EC2::EIP", cfnMap{
+					{Key: "Domain", Value: "vpc"},
+				}, yaml.MapItem{Key: "DependsOn", Value: "VPCGatewayAttachment"})},
+				yaml.MapItem{Key: natID, Value: cfnResource("AWS::EC2::NatGateway", cfnMap{
+					{Key: "AllocationId", Value: cfnGetAtt(natID+"EIP", "AllocationId")},
+					{Key: "SubnetId", Value: cfnRef(cfnLogicalID("public", "subnet", zone.AvailabilityZone))},
+				})},
+			)
+		}
+		resources = append(resources, yaml.MapItem{Key: cfnLogicalID("private", "route", zone.AvailabilityZone), Value: cfnResource("AWS::EC2::Route", cfnMap{
+			{Key: "RouteTableId", Value: cfnRef(privateRouteTableID)},
+			{Key: "DestinationCidrBlock", Value: "0.0.0.0/0"},
+			{Key: "NatGatewayId", Value: cfnRef(natID)},
+		})})
+	}
+
+	return publicSubnets, privateSubnets, resources
+}
+
+func iacCloudFormationIAM(cluster *iacCluster) (cfnMap, error) {
+	var policyDocument interface{}
+	if err := json.Unmarshal([]byte(cluster.PolicyDocument), &policyDocument); err != nil {
+		return nil, err
+	}
+
+	assumeRolePolicy := func(service string) cfnMap {
+		return cfnMap{
+			{Key: "Version", Value: "2012-10-17"},
+			{Key: "Statement", Value: []cfnMap{{
+				{Key: "Effect", Value: "Allow"},
+				{Key: "Principal", Value: cfnMap{{Key: "Service", Value: service}}},
+				{Key: "Action", Value: "sts:AssumeRole"},
+			}}},
+		}
+	}
+
+	nodePolicyARNs := []interface{}{}
+	for _, policyARN := range cluster.NodePolicyARNs {
+		nodePolicyARNs = append(nodePolicyARNs, policyARN)
+	}
+	nodePolicyARNs = append(nodePolicyARNs, cfnRef("CortexPolicy"))
+
+	return cfnMap{
+		{Key: "CortexPolicy", Value: cfnResource("AWS::IAM::ManagedPolicy", cfnMap{
+			{Key: "ManagedPolicyName", Value: cluster.PolicyName},
+			{Key: "PolicyDocument", Value: policyDocument},
+		}, _cfnRetain)},
+		{Key: "ClusterRole", Value: cfnResource("AWS::IAM::Role", cfnMap{
+			{Key: "AssumeRolePolicyDocument", Value: assumeRolePolicy("eks.amazonaws.com")},
+			{Key: "ManagedPolicyArns", Value: []string{
+				fmt.Sprintf("arn:%s:iam::aws:policy/AmazonEKSClusterPolicy", cluster.Partition),
+				fmt.Sprintf("arn:%s:iam::aws:policy/AmazonEKSVPCResourceController", cluster.Partition),
+			}},
+		})},
+		{Key: "NodeRole", Value: cfnResource("AWS::IAM::Role", cfnMap{
+			{Key: "AssumeRolePolicyDocument", Value: assumeRolePolicy("ec2.amazonaws.com")},
+			{Key: "ManagedPolicyArns", Value: nodePolicyARNs},
+			{Key: "Policies", Value: []cfnMap{{
+				{Key: "PolicyName", Value: "autoscaler"},
+				{Key: "PolicyDocument", Value: cfnMap{
+					{Key: "Version", Value: "2012-10-17"},
+					{Key: "Statement", Value: []cfnMap{{
+						{Key: "Effect", Value: "Allow"},
+						{Key: "Action", Value: _iacAutoscalerActions},
+						{Key: "Resource", Value: "*"},
+					}}},
+				}},
+			}}},
+		})},
+		{Key: "NodeInstanceProfile", Value: cfnResource("AWS::IAM::InstanceProfile", cfnMap{
+			{Key: "Roles", Value: []interface{}{cfnRef("NodeRole")}},
+		})},
+	}, nil
+}
+
+func iacCloudFormationNodeGroup(cluster *iacCluster, ng iacNodeGroup, nodeSubnets map[string][]interface{}) cfnMap {
+	launchTemplateID := cfnLogicalID(ng.Name, "launch", "template")
+
+	ebs := cfnMap{
+		{Key: "VolumeSize", Value: ng.VolumeSize},
+		{Key: "VolumeType", Value: ng.VolumeType},
+	}
+	if ng.VolumeIOPS != nil {
+		ebs = append(ebs, yaml.MapItem{Key: "Iops", Value: *ng.VolumeIOPS})
+	}
+	if ng.VolumeThroughput != nil {
+		ebs = append(ebs, yaml.MapItem{Key: "Throughput", Value: *ng.VolumeThroughput})
+	}
+	ebs = append(ebs, yaml.MapItem{Key: "DeleteOnTermination", Value: true})
+
+	launchTemplateData := cfnMap{
+		{Key: "ImageId", Value: cfnRef(cfnLogicalID("ami", ng.AMIType))},
+	}
+	if ng.SpotConfig == nil {
+		launchTemplateData = append(launchTemplateData, yaml.MapItem{Key: "InstanceType", Value: ng.InstanceType})
+	}
+	launchTemplateData = append(launchTemplateData,
+		yaml.MapItem{Key: "SecurityGroupIds", Value: []interface{}{cfnGetAtt("Cluster", "ClusterSecurityGroupId")}},
+		yaml.MapItem{Key: "IamInstanceProfile", Value: cfnMap{{Key: "Arn", Value: cfnGetAtt("NodeInstanceProfile", "Arn")}}},
+		yaml.MapItem{Key: "BlockDeviceMappings", Value: []cfnMap{{
+			{Key: "DeviceName", Value: "/dev/xvda"},
+			{Key: "Ebs", Value: ebs},
+		}}},
+		yaml.MapItem{Key: "UserData", Value: cfnMap{{Key: "Fn::Base64", Value: ng.userData(cluster)}}},
+	)
+	if ng.CapacityReservationID != "" {
+		launchTemplateData = append(launchTemplateData, yaml.MapItem{Key: "CapacityReservationSpecification", Value: cfnMap{
+			{Key: "CapacityReservationTarget", Value: cfnMap{{Key: "CapacityReservationId", Value: ng.CapacityReservationID}}},
+		}})
+	}
+
+	var subnets []interface{}
+	for _, zone := range cluster.nodeGroupZones(ng) {
+		subnets = append(subnets, nodeSubnets[zone]...)
+	}
+
+	launchTemplateSpec := cfnMap{
+		{Key: "LaunchTemplateId", Value: cfnRef(launchTemplateID)},
+		{Key: "Version", Value: cfnGetAtt(launchTemplateID, "LatestVersionNumber")},
+	}
+
+	// the desired capacity is omitted, since it is managed by the cluster autoscaler
+	asgProperties := cfnMap{
+		{Key: "AutoScalingGroupName", Value: fmt.Sprintf("%s-%s", cluster.Name, ng.Name)},
+		{Key: "MinSize", Value: fmt.Sprint(ng.MinSize)},
+		{Key: "MaxSize", Value: fmt.Sprint(ng.MaxSize)},
+		{Key: "VPCZoneIdentifier", Value: uniqueCFNValues(subnets)},
+	}
+
+	if ng.SpotConfig == nil {
+		asgProperties = append(asgProperties, yaml.MapItem{Key: "LaunchTemplate", Value: launchTemplateSpec})
+	} else {
+		distribution := cfnMap{}
+		if ng.SpotConfig.OnDemandBaseCapacity != nil {
+			distribution = append(distribution, yaml.MapItem{Key: "OnDemandBaseCapacity", Value: *ng.SpotConfig.OnDemandBaseCapacity})
+		}
+		if ng.SpotConfig.OnDemandPercentageAboveBaseCapacity != nil {
+			distribution = append(distribution, yaml.MapItem{Key: "OnDemandPercentageAboveBaseCapacity", Value: *ng.SpotConfig.OnDemandPercentageAboveBaseCapacity})
+		}
+		if ng.SpotConfig.InstancePools != nil {
+			distribution = append(distribution, yaml.MapItem{Key: "SpotInstancePools", Value: *ng.SpotConfig.InstancePools})
+		}
+		if ng.SpotConfig.MaxPrice != nil {
+			distribution = append(distribution, yaml.MapItem{Key: "SpotMaxPrice", Value: fmt.Sprint(*ng.SpotConfig.MaxPrice)})
+		}
+
+		var overrides []cfnMap
+		for _, instanceType := range ng.instanceTypes() {
+			overrides = append(overrides, cfnMap{{Key: "InstanceType", Value: instanceType}})
+		}
+
+		asgProperties = append(asgProperties, yaml.MapItem{Key: "MixedInstancesPolicy", Value: cfnMap{
+			{Key: "InstancesDistribution", Value: distribution},
+			{Key: "LaunchTemplate", Value: cfnMap{
+				{Key: "LaunchTemplateSpecification", Value: launchTemplateSpec},
+				{Key: "Overrides", Value: overrides},
+			}},
+		}})
+	}
+
+	// cloudformation doesn't support suspending autoscaling processes, so ng.SuspendedProcesses must be suspended separately (eksctl does this after creating the group)
+
+	tags := ng.asgTags(cluster)
+	var asgTags []cfnMap
+	for _, key := range sortedKeys(tags) {
+		asgTags = append(asgTags, cfnMap{
+			{Key: "Key", Value: key},
+			{Key: "Value", Value: tags[key]},
+			{Key: "PropagateAtLaunch", Value: true},
+		})
+	}
+	asgProperties = append(asgProperties, yaml.MapItem{Key: "Tags", Value: asgTags})
+
+	return cfnMap{
+		{Key: launchTemplateID, Value: cfnResource("AWS::EC2::LaunchTemplate", cfnMap{
+			{Key: "LaunchTemplateName", Value: fmt.Sprintf("%s-%s", cluster.Name, ng.Name)},
+			{Key: "LaunchTemplateData", Value: launchTemplateData},
+		})},
+		{Key: cfnLogicalID(ng.Name, "autoscaling", "group"), Value: cfnResource("AWS::AutoScaling::AutoScalingGroup", asgProperties)},
+	}
+}
+
+func uniqueCFNValues(values []interface{}) []interface{} {
+	unique := []interface{}{}
+	seen := map[string]bool{}
+	for _, value := range values {
+		key := fmt.Sprint(value)
+		if !seen[key] {
+			seen[key] = true
+			unique = append(unique, value)
+		}
+	}
+	return unique
+}
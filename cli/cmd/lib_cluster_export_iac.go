@@ -0,0 +1,503 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/bits"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/cortexlabs/cortex/cli/types/flags"
+	"github.com/cortexlabs/cortex/pkg/consts"
+	"github.com/cortexlabs/cortex/pkg/lib/aws"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/pointer"
+	"github.com/cortexlabs/cortex/pkg/lib/slices"
+	"github.com/cortexlabs/cortex/pkg/types/clusterconfig"
+)
+
+// the infrastructure which `cortex cluster up` provisions for a cluster (mostly via eksctl), which is exported as terraform or cloudformation;
+// the node groups mirror those generated by manager/generate_eks.py, and should be kept in sync with it
+type iacCluster struct {
+	Name             string
+	Region           string
+	Partition        string
+	K8sVersion       string
+	Tags             map[string]string
+	Bucket           string
+	LogGroup         string
+	PolicyName       string
+	PolicyARN        string
+	PolicyDocument   string
+	NodePolicyARNs   []string // the managed policies which are attached to the node role, other than the cortex policy
+	SubnetVisibility clusterconfig.SubnetVisibility
+	VPC              *iacVPC // nil if the cluster uses existing subnets
+	ExistingSubnets  []*clusterconfig.Subnet
+	NodeGroups       []iacNodeGroup
+}
+
+type iacVPC struct {
+	CIDR       string
+	NATGateway clusterconfig.NATGateway
+	Zones      []iacZone
+}
+
+// each availability zone has a public and a private subnet (as created by eksctl)
+type iacZone struct {
+	AvailabilityZone string
+	PublicCIDR       string
+	PrivateCIDR      string
+}
+
+type iacNodeGroup struct {
+	Name                  string // the eksctl nodegroup name (e.g. cx-wd-ng-cpu)
+	AMIType               string // one of the aws.EKSAMIType* constants
+	InstanceType          string
+	SpotConfig            *clusterconfig.SpotConfig // nil for on-demand node groups
+	MinSize               int64
+	MaxSize               int64
+	DesiredCapacity       int64
+	VolumeSize            int64
+	VolumeType            string
+	VolumeIOPS            *int64
+	VolumeThroughput      *int64
+	Labels                map[string]string
+	Taints                []iacTaint
+	Tags                  map[string]string // autoscaling group tags, which are propagated to the instances
+	SuspendedProcesses    []string
+	AvailabilityZone      string // if set, the node group is restricted to this zone (for capacity reservations)
+	CapacityReservationID string
+}
+
+type iacTaint struct {
+	Key    string
+	Value  string
+	Effect string
+}
+
+var _iacBootstrapCommands = []string{
+	"yum install -y ipvsadm",
+	"modprobe ip_vs",
+	"modprobe ip_vs_rr",
+	"modprobe ip_vs_lc",
+	"modprobe ip_vs_wrr",
+	"modprobe ip_vs_sh",
+	"modprobe nf_conntrack_ipv4",
+}
+
+// the permissions of eksctl's autoScaler addon policy, which the cluster autoscaler requires
+var _iacAutoscalerActions = []string{
+	"autoscaling:DescribeAutoScalingGroups",
+	"autoscaling:DescribeAutoScalingInstances",
+	"autoscaling:DescribeLaunchConfigurations",
+	"autoscaling:DescribeTags",
+	"autoscaling:SetDesiredCapacity",
+	"autoscaling:TerminateInstanceInAutoScalingGroup",
+	"ec2:DescribeInstanceTypes",
+	"ec2:DescribeLaunchTemplateVersions",
+}
+
+func newIACCluster(clusterConfig clusterconfig.Config) (*iacCluster, error) {
+	partition := aws.PartitionFromRegion(clusterConfig.Region)
+
+	policyDocument, err := clusterconfig.RenderDefaultPolicy(clusterconfig.CortexPolicyTemplateArgs{
+		ClusterName:               clusterConfig.ClusterName,
+		LogGroup:                  clusterConfig.ClusterName,
+		Bucket:                    clusterConfig.Bucket,
+		Region:                    clusterConfig.Region,
+		AccountID:                 clusterConfig.AccountID,
+		CustomDomainHostedZoneIDs: clusterConfig.CustomDomainHostedZoneIDs,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	cluster := &iacCluster{
+		Name:           clusterConfig.ClusterName,
+		Region:         clusterConfig.Region,
+		Partition:      partition,
+		K8sVersion:     consts.EKSVersion,
+		Tags:           clusterConfig.Tags,
+		Bucket:         clusterConfig.Bucket,
+		LogGroup:       clusterConfig.ClusterName,
+		PolicyName:     clusterconfig.DefaultPolicyName(clusterConfig.ClusterName, clusterConfig.Region),
+		PolicyARN:      clusterConfig.CortexPolicyARN,
+		PolicyDocument: policyDocument,
+		NodePolicyARNs: append([]string{
+			fmt.Sprintf("arn:%s:iam::aws:policy/AmazonEKSWorkerNodePolicy", partition),
+			fmt.Sprintf("arn:%s:iam::aws:policy/AmazonEKS_CNI_Policy", partition),
+			fmt.Sprintf("arn:%s:iam::aws:policy/AmazonEC2ContainerRegistryReadOnly", partition),
+			fmt.Sprintf("arn:%s:iam::aws:policy/ElasticLoadBalancingFullAccess", partition),
+		}, clusterConfig.IAMPolicyARNs...),
+		SubnetVisibility: clusterConfig.SubnetVisibility,
+		ExistingSubnets:  clusterConfig.Subnets,
+	}
+
+	if len(clusterConfig.Subnets) == 0 {
+		if len(clusterConfig.AvailabilityZones) == 0 {
+			return nil, errors.ErrorUnexpected("the cluster configuration does not specify the cluster's availability zones")
+		}
+		vpcCIDR := "192.168.0.0/16"
+		if clusterConfig.VPCCIDR != nil {
+			vpcCIDR = *clusterConfig.VPCCIDR
+		}
+		vpc, err := newIACVPC(vpcCIDR, clusterConfig.AvailabilityZones, clusterConfig.NATGateway)
+		if err != nil {
+			return nil, err
+		}
+		cluster.VPC = vpc
+	}
+
+	systemInstanceType := "t3.medium"
+	if clusterConfig.Arch == clusterconfig.ARM64Arch {
+		systemInstanceType = "t4g.medium"
+	}
+
+	operatorNodeGroup, err := newIACSystemNodeGroup("cx-operator", systemInstanceType, 2, 25, map[string]string{"operator": "true"}, nil)
+	if err != nil {
+		return nil, err
+	}
+	prometheusNodeGroup, err := newIACSystemNodeGroup("cx-prometheus", clusterConfig.PrometheusInstanceType, 1, 1, map[string]string{"prometheus": "true"}, []iacTaint{{Key: "prometheus", Value: "true", Effect: "NoSchedule"}})
+	if err != nil {
+		return nil, err
+	}
+	cluster.NodeGroups = append(cluster.NodeGroups, *operatorNodeGroup, *prometheusNodeGroup)
+
+	for _, nodeGroup := range clusterConfig.NodeGroups {
+		workerNodeGroup, err := newIACWorkerNodeGroup(nodeGroup)
+		if err != nil {
+			return nil, errors.Wrap(err, clusterconfig.NodeGroupsKey, nodeGroup.Name)
+		}
+		cluster.NodeGroups = append(cluster.NodeGroups, *workerNodeGroup)
+	}
+
+	return cluster, nil
+}
+
+// splits the vpc's cidr into equally sized blocks (at least 8, like eksctl), and assigns the first blocks to the public subnets and the following blocks to the private subnets
+func newIACVPC(vpcCIDR string, availabilityZones []string, natGateway clusterconfig.NATGateway) (*iacVPC, error) {
+	_, ipNet, err := net.ParseCIDR(vpcCIDR)
+	if err != nil || ipNet.IP.To4() == nil {
+		return nil, errors.ErrorUnexpected("invalid vpc cidr", vpcCIDR)
+	}
+	prefixLength, _ := ipNet.Mask.Size()
+
+	zones := append([]string{}, availabilityZones...)
+	sort.Strings(zones)
+
+	blockBits := bits.Len(uint(2*len(zones) - 1))
+	if blockBits < 3 {
+		blockBits = 3
+	}
+	subnetPrefixLength := prefixLength + blockBits
+	if subnetPrefixLength > 28 {
+		return nil, errors.ErrorUnexpected(fmt.Sprintf("vpc cidr %s is too small to be split into %d subnets", vpcCIDR, 2*len(zones)))
+	}
+
+	baseIP := binary.BigEndian.Uint32(ipNet.IP.To4())
+	subnetCIDR := func(block int) string {
+		ip := make(net.IP, 4)
+		binary.BigEndian.PutUint32(ip, baseIP+uint32(block)<<(32-subnetPrefixLength))
+		return fmt.Sprintf("%s/%d", ip.String(), subnetPrefixLength)
+	}
+
+	vpc := &iacVPC{
+		CIDR:       vpcCIDR,
+		NATGateway: natGateway,
+	}
+	for i, zone := range zones {
+		vpc.Zones = append(vpc.Zones, iacZone{
+			AvailabilityZone: zone,
+			PublicCIDR:       subnetCIDR(i),
+			PrivateCIDR:      subnetCIDR(len(zones) + i),
+		})
+	}
+
+	return vpc, nil
+}
+
+func newIACSystemNodeGroup(name string, instanceType string, minSize int64, maxSize int64, labels map[string]string, taints []iacTaint) (*iacNodeGroup, error) {
+	amiType, err := aws.EKSAMIType(instanceType)
+	if err != nil {
+		return nil, err
+	}
+
+	labels["alpha.eksctl.io/nodegroup-name"] = name
+
+	return &iacNodeGroup{
+		Name:             name,
+		AMIType:          amiType,
+		InstanceType:     instanceType,
+		MinSize:          minSize,
+		MaxSize:          maxSize,
+		DesiredCapacity:  minSize,
+		VolumeSize:       20,
+		VolumeType:       clusterconfig.GP3VolumeType.String(),
+		VolumeIOPS:       pointer.Int64(3000),
+		VolumeThroughput: pointer.Int64(125),
+		Labels:           labels,
+		Taints:           taints,
+		Tags:             map[string]string{},
+	}, nil
+}
+
+func newIACWorkerNodeGroup(nodeGroup *clusterconfig.NodeGroup) (*iacNodeGroup, error) {
+	amiType, err := aws.EKSAMIType(nodeGroup.InstanceType)
+	if err != nil {
+		return nil, err
+	}
+
+	name := "cx-wd-" + nodeGroup.Name
+	if nodeGroup.Spot {
+		name = "cx-ws-" + nodeGroup.Name
+	}
+
+	desiredCapacity := nodeGroup.MinInstances
+	if desiredCapacity == 0 {
+		desiredCapacity = 1
+	}
+
+	workerNodeGroup := &iacNodeGroup{
+		Name:            name,
+		AMIType:         amiType,
+		InstanceType:    nodeGroup.InstanceType,
+		MinSize:         nodeGroup.MinInstances,
+		MaxSize:         nodeGroup.MaxInstances,
+		DesiredCapacity: desiredCapacity,
+		VolumeSize:      nodeGroup.InstanceVolumeSize,
+		VolumeType:      nodeGroup.InstanceVolumeType.String(),
+		Labels: map[string]string{
+			"alpha.eksctl.io/nodegroup-name": name,
+			"workload":                       "true",
+		},
+		Taints: []iacTaint{{Key: "workload", Value: "true", Effect: "NoSchedule"}},
+		Tags: map[string]string{
+			"k8s.io/cluster-autoscaler/enabled":                      "true",
+			"k8s.io/cluster-autoscaler/node-template/label/workload": "true",
+		},
+		SuspendedProcesses: []string{"AZRebalance"},
+	}
+
+	if nodeGroup.InstanceVolumeType == clusterconfig.IO1VolumeType || nodeGroup.InstanceVolumeType == clusterconfig.GP3VolumeType {
+		workerNodeGroup.VolumeIOPS = nodeGroup.InstanceVolumeIOPS
+	}
+	if nodeGroup.InstanceVolumeType == clusterconfig.GP3VolumeType {
+		workerNodeGroup.VolumeThroughput = nodeGroup.InstanceVolumeThroughput
+	}
+
+	if nodeGroup.Spot {
+		workerNodeGroup.SpotConfig = nodeGroup.SpotConfig
+		workerNodeGroup.Labels["lifecycle"] = "Ec2Spot"
+	}
+
+	if nodeGroup.CapacityReservationID != nil {
+		workerNodeGroup.CapacityReservationID = *nodeGroup.CapacityReservationID
+		workerNodeGroup.AvailabilityZone = nodeGroup.CapacityReservationAvailabilityZone
+	}
+
+	isNvidiaGPU, err := aws.IsNvidiaGPUInstance(nodeGroup.InstanceType)
+	if err != nil {
+		return nil, err
+	}
+	if isNvidiaGPU {
+		workerNodeGroup.Labels["nvidia.com/gpu"] = "true"
+		workerNodeGroup.Labels["k8s.amazonaws.com/accelerator"] = "true"
+		workerNodeGroup.Taints = append(workerNodeGroup.Taints, iacTaint{Key: "nvidia.com/gpu", Value: "true", Effect: "NoSchedule"})
+		workerNodeGroup.Tags["k8s.io/cluster-autoscaler/node-template/label/nvidia.com/gpu"] = "true"
+		workerNodeGroup.Tags["k8s.io/cluster-autoscaler/node-template/taint/dedicated"] = "nvidia.com/gpu=true"
+		workerNodeGroup.Tags["k8s.io/cluster-autoscaler/node-template/label/k8s.amazonaws.com/accelerator"] = "true"
+	}
+
+	isInferentia, err := aws.IsInferentiaInstance(nodeGroup.InstanceType)
+	if err != nil {
+		return nil, err
+	}
+	if isInferentia {
+		numChips := iacInferentiaChips(nodeGroup.InstanceType)
+		workerNodeGroup.Labels["aws.amazon.com/neuron"] = "true"
+		workerNodeGroup.Taints = append(workerNodeGroup.Taints, iacTaint{Key: "aws.amazon.com/neuron", Value: "true", Effect: "NoSchedule"})
+		workerNodeGroup.Tags["k8s.io/cluster-autoscaler/node-template/label/aws.amazon.com/neuron"] = "true"
+		workerNodeGroup.Tags["k8s.io/cluster-autoscaler/node-template/taint/dedicated"] = "aws.amazon.com/neuron=true"
+		workerNodeGroup.Tags["k8s.io/cluster-autoscaler/node-template/resources/aws.amazon.com/neuron"] = fmt.Sprint(numChips)
+		workerNodeGroup.Tags["k8s.io/cluster-autoscaler/node-template/resources/aws.amazon.com/neuroncore"] = fmt.Sprint(numChips * 4)
+		workerNodeGroup.Tags["k8s.io/cluster-autoscaler/node-template/resources/hugepages-2Mi"] = fmt.Sprintf("%dMi", 128*numChips)
+	}
+
+	return workerNodeGroup, nil
+}
+
+// see get_inf_resources() in manager/generate_eks.py
+func iacInferentiaChips(instanceType string) int {
+	switch instanceType {
+	case "inf1.xlarge", "inf1.2xlarge":
+		return 1
+	case "inf1.6xlarge":
+		return 4
+	case "inf1.24xlarge":
+		return 16
+	}
+	return 0
+}
+
+// the instance types of the node group (more than one for spot node groups with an instance distribution)
+func (ng iacNodeGroup) instanceTypes() []string {
+	if ng.SpotConfig != nil && len(ng.SpotConfig.InstanceDistribution) > 0 {
+		return ng.SpotConfig.InstanceDistribution
+	}
+	return []string{ng.InstanceType}
+}
+
+// tags which are applied to each node group's autoscaling group (and propagated to its instances), in addition to the cluster's tags
+func (ng iacNodeGroup) asgTags(cluster *iacCluster) map[string]string {
+	tags := map[string]string{
+		"Name":                                      fmt.Sprintf("%s-%s-Node", cluster.Name, ng.Name),
+		"kubernetes.io/cluster/" + cluster.Name:     "owned",
+		"alpha.eksctl.io/cluster-name":              cluster.Name,
+		"alpha.eksctl.io/nodegroup-name":            ng.Name,
+		"k8s.io/cluster-autoscaler/" + cluster.Name: "owned",
+	}
+	for key, value := range ng.Tags {
+		tags[key] = value
+	}
+	return tags
+}
+
+// the node's user data, which joins the node to the cluster with the node group's labels and taints (see default_nodegroup() in manager/generate_eks.py)
+func (ng iacNodeGroup) userData(cluster *iacCluster) string {
+	labelKeys := make([]string, 0, len(ng.Labels))
+	for key := range ng.Labels {
+		labelKeys = append(labelKeys, key)
+	}
+	sort.Strings(labelKeys)
+	labels := make([]string, 0, len(labelKeys))
+	for _, key := range labelKeys {
+		labels = append(labels, key+"="+ng.Labels[key])
+	}
+
+	taints := make([]string, 0, len(ng.Taints))
+	for _, taint := range ng.Taints {
+		taints = append(taints, fmt.Sprintf("%s=%s:%s", taint.Key, taint.Value, taint.Effect))
+	}
+
+	kubeletArgs := []string{
+		"--node-labels=" + strings.Join(labels, ","),
+		"--kube-reserved=cpu=150m,memory=300Mi,ephemeral-storage=1Gi",
+		"--system-reserved=cpu=150m,memory=300Mi,ephemeral-storage=1Gi",
+		"--eviction-hard=memory.available<200Mi,nodefs.available<5%",
+		"--registry-qps=10",
+	}
+	if len(taints) > 0 {
+		kubeletArgs = append(kubeletArgs, "--register-with-taints="+strings.Join(taints, ","))
+	}
+
+	lines := []string{"#!/bin/bash", "set -o errexit"}
+	lines = append(lines, _iacBootstrapCommands...)
+	lines = append(lines, fmt.Sprintf("/etc/eks/bootstrap.sh %s --container-runtime containerd --kubelet-extra-args '%s'", cluster.Name, strings.Join(kubeletArgs, " ")))
+
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// the availability zones which contain each node group's subnets
+func iacExportFileName(clusterName string, region string, format flags.ExportFormat) string {
+	if format == flags.CloudFormationExportFormat {
+		return fmt.Sprintf("export-%s-%s.cloudformation.yaml", region, clusterName)
+	}
+	return fmt.Sprintf("export-%s-%s.tf", region, clusterName)
+}
+
+// renders the cluster's infrastructure in the given format (terraform or cloudformation)
+func renderIACExport(clusterConfig clusterconfig.Config, format flags.ExportFormat) (string, error) {
+	cluster, err := newIACCluster(clusterConfig)
+	if err != nil {
+		return "", err
+	}
+	if format == flags.CloudFormationExportFormat {
+		return iacCloudFormation(cluster)
+	}
+	return iacTerraform(cluster)
+}
+
+// returns the cluster's availability zones, in order
+func (cluster *iacCluster) zones() []string {
+	var zones []string
+	if cluster.VPC != nil {
+		for _, zone := range cluster.VPC.Zones {
+			zones = append(zones, zone.AvailabilityZone)
+		}
+	} else {
+		for _, subnet := range cluster.ExistingSubnets {
+			if !slices.HasString(zones, subnet.AvailabilityZone) {
+				zones = append(zones, subnet.AvailabilityZone)
+			}
+		}
+	}
+	return zones
+}
+
+func (cluster *iacCluster) nodeGroupZones(ng iacNodeGroup) []string {
+	if ng.AvailabilityZone != "" {
+		return []string{ng.AvailabilityZone}
+	}
+	return cluster.zones()
+}
+
+// the comment which is rendered at the top of the exported configuration
+func iacHeader(cluster *iacCluster, commentPrefix string) string {
+	lines := []string{
+		fmt.Sprintf("infrastructure of the cortex cluster %s in %s", cluster.Name, cluster.Region),
+		"",
+		"the following are not included, and are managed by cortex on the cluster itself:",
+		"  - the kubernetes components which cortex installs (operator, gateways, autoscaler, monitoring, etc.)",
+		"  - the mapping of the node role in the aws-auth config map",
+		"  - eks addons",
+		"  - load balancers (they are created by kubernetes)",
+	}
+
+	var buf strings.Builder
+	for _, line := range lines {
+		if line == "" {
+			buf.WriteString(commentPrefix + "\n")
+		} else {
+			buf.WriteString(commentPrefix + " " + line + "\n")
+		}
+	}
+	return buf.String()
+}
+
+// the ami types which are used by the cluster's node groups
+func (cluster *iacCluster) amiTypes() []string {
+	amiTypes := []string{}
+	for _, ng := range cluster.NodeGroups {
+		if !slices.HasString(amiTypes, ng.AMIType) {
+			amiTypes = append(amiTypes, ng.AMIType)
+		}
+	}
+	sort.Strings(amiTypes)
+	return amiTypes
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
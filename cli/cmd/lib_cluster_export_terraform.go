@@ -0,0 +1,632 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/cortexlabs/cortex/pkg/lib/aws"
+	"github.com/cortexlabs/cortex/pkg/types/clusterconfig"
+)
+
+// a minimal writer for the subset of HCL which is used by the terraform export
+
+type hclBlock struct {
+	Type   string
+	Labels []string
+	Body   []interface{} // hclAttr or hclBlock
+}
+
+type hclAttr struct {
+	Name  string
+	Value interface{} // string, int64, bool, []string, []hclExpr, map[string]string, hclObject, hclExpr, or hclHeredoc
+}
+
+// hclExpr is rendered verbatim (e.g. references to other resources)
+type hclExpr string
+
+// hclObject is an object whose keys are rendered unquoted, in order
+type hclObject []hclAttr
+
+// hclHeredoc is rendered as an indented heredoc string, optionally wrapped in a function call (e.g. base64encode)
+type hclHeredoc struct {
+	Content string
+	Func    string
+}
+
+var _hclIdentifierInvalidChars = regexp.MustCompile(`[^a-z0-9_]+`)
+
+func hclIdentifier(name string) string {
+	identifier := _hclIdentifierInvalidChars.ReplaceAllString(strings.ToLower(name), "_")
+	if identifier == "" || (identifier[0] >= '0' && identifier[0] <= '9') {
+		identifier = "_" + identifier
+	}
+	return identifier
+}
+
+func hclEscapeTemplate(str string) string {
+	str = strings.ReplaceAll(str, "${", "$${")
+	return strings.ReplaceAll(str, "%{", "%%{")
+}
+
+func hclString(str string) string {
+	quoted := strconv.Quote(str)
+	return hclEscapeTemplate(quoted)
+}
+
+func (block hclBlock) write(buf *bytes.Buffer, indent string) {
+	buf.WriteString(indent + block.Type)
+	for _, label := range block.Labels {
+		buf.WriteString(" " + strconv.Quote(label))
+	}
+	buf.WriteString(" {\n")
+	writeHCLBody(buf, block.Body, indent+"  ")
+	buf.WriteString(indent + "}\n")
+}
+
+func writeHCLBody(buf *bytes.Buffer, body []interface{}, indent string) {
+	for i := 0; i < len(body); i++ {
+		switch item := body[i].(type) {
+		case hclBlock:
+			if i > 0 {
+				buf.WriteString("\n")
+			}
+			item.write(buf, indent)
+			if i < len(body)-1 {
+				if _, nextIsAttr := body[i+1].(hclAttr); nextIsAttr {
+					buf.WriteString("\n")
+				}
+			}
+		case hclAttr:
+			// consecutive single-line attributes are aligned on their equals signs (like terraform fmt)
+			if value := hclValue(item.Value, indent); strings.Contains(value, "\n") {
+				buf.WriteString(fmt.Sprintf("%s%s = %s\n", indent, item.Name, value))
+				continue
+			}
+			var names, values []string
+			for ; i < len(body); i++ {
+				attr, ok := body[i].(hclAttr)
+				if !ok {
+					break
+				}
+				value := hclValue(attr.Value, indent)
+				if strings.Contains(value, "\n") {
+					break
+				}
+				names = append(names, attr.Name)
+				values = append(values, value)
+			}
+			i--
+			width := 0
+			for _, name := range names {
+				if len(name) > width {
+					width = len(name)
+				}
+			}
+			for j := range names {
+				buf.WriteString(fmt.Sprintf("%s%-*s = %s\n", indent, width, names[j], values[j]))
+			}
+		}
+	}
+}
+
+func hclValue(value interface{}, indent string) string {
+	switch v := value.(type) {
+	case string:
+		return hclString(v)
+	case hclExpr:
+		return string(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case int:
+		return strconv.Itoa(v)
+	case bool:
+		return strconv.FormatBool(v)
+	case []string:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = hclString(item)
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case []hclExpr:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = string(item)
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case map[string]string:
+		if len(v) == 0 {
+			return "{}"
+		}
+		attrs := make([]interface{}, 0, len(v))
+		for _, key := range sortedKeys(v) {
+			attrs = append(attrs, hclAttr{Name: hclString(key), Value: v[key]})
+		}
+		buf := &bytes.Buffer{}
+		buf.WriteString("{\n")
+		writeHCLBody(buf, attrs, indent+"  ")
+		buf.WriteString(indent + "}")
+		return buf.String()
+	case hclObject:
+		attrs := make([]interface{}, len(v))
+		for i, attr := range v {
+			attrs[i] = attr
+		}
+		buf := &bytes.Buffer{}
+		buf.WriteString("{\n")
+		writeHCLBody(buf, attrs, indent+"  ")
+		buf.WriteString(indent + "}")
+		return buf.String()
+	case hclHeredoc:
+		buf := &bytes.Buffer{}
+		if v.Func != "" {
+			buf.WriteString(v.Func + "(")
+		}
+		buf.WriteString("<<-EOT\n")
+		for _, line := range strings.Split(strings.TrimSuffix(v.Content, "\n"), "\n") {
+			buf.WriteString(indent + "  " + hclEscapeTemplate(line) + "\n")
+		}
+		buf.WriteString(indent + "EOT")
+		if v.Func != "" {
+			buf.WriteString("\n" + indent + ")")
+		}
+		return buf.String()
+	}
+	return fmt.Sprint(value) // unexpected
+}
+
+// renders the cluster's infrastructure as a terraform configuration
+func iacTerraform(cluster *iacCluster) (string, error) {
+	var blocks []hclBlock
+
+	blocks = append(blocks,
+		hclBlock{Type: "terraform", Body: []interface{}{
+			hclAttr{"required_version", ">= 1.5.0"},
+			hclBlock{Type: "required_providers", Body: []interface{}{
+				hclAttr{"aws", hclObject{{"source", "hashicorp/aws"}, {"version", ">= 5.0"}}},
+			}},
+		}},
+		hclBlock{Type: "provider", Labels: []string{"aws"}, Body: []interface{}{
+			hclAttr{"region", cluster.Region},
+			hclBlock{Type: "default_tags", Body: []interface{}{
+				hclAttr{"tags", cluster.Tags},
+			}},
+		}},
+	)
+
+	publicSubnets, privateSubnets, vpcBlocks := iacTerraformNetwork(cluster)
+	blocks = append(blocks, vpcBlocks...)
+
+	iamBlocks, err := iacTerraformIAM(cluster)
+	if err != nil {
+		return "", err
+	}
+	blocks = append(blocks, iamBlocks...)
+
+	blocks = append(blocks,
+		hclBlock{Type: "resource", Labels: []string{"aws_s3_bucket", "cortex"}, Body: []interface{}{
+			hclAttr{"bucket", cluster.Bucket},
+		}},
+		hclBlock{Type: "import", Body: []interface{}{
+			hclAttr{"to", hclExpr("aws_s3_bucket.cortex")},
+			hclAttr{"id", cluster.Bucket},
+		}},
+		hclBlock{Type: "resource", Labels: []string{"aws_cloudwatch_log_group", "cortex"}, Body: []interface{}{
+			hclAttr{"name", cluster.LogGroup},
+		}},
+		hclBlock{Type: "import", Body: []interface{}{
+			hclAttr{"to", hclExpr("aws_cloudwatch_log_group.cortex")},
+			hclAttr{"id", cluster.LogGroup},
+		}},
+	)
+
+	var clusterSubnets []hclExpr
+	for _, zone := range cluster.zones() {
+		clusterSubnets = append(clusterSubnets, publicSubnets[zone]...)
+		clusterSubnets = append(clusterSubnets, privateSubnets[zone]...)
+	}
+
+	blocks = append(blocks,
+		hclBlock{Type: "resource", Labels: []string{"aws_eks_cluster", "cluster"}, Body: []interface{}{
+			hclAttr{"name", cluster.Name},
+			hclAttr{"version", cluster.K8sVersion},
+			hclAttr{"role_arn", hclExpr("aws_iam_role.cluster.arn")},
+			hclBlock{Type: "vpc_config", Body: []interface{}{
+				hclAttr{"subnet_ids", uniqueHCLExprs(clusterSubnets)},
+			}},
+			hclAttr{"depends_on", []hclExpr{"aws_iam_role_policy_attachment.cluster"}},
+		}},
+		hclBlock{Type: "import", Body: []interface{}{
+			hclAttr{"to", hclExpr("aws_eks_cluster.cluster")},
+			hclAttr{"id", cluster.Name},
+		}},
+	)
+
+	for _, amiType := range cluster.amiTypes() {
+		blocks = append(blocks, hclBlock{Type: "data", Labels: []string{"aws_ssm_parameter", "ami_" + amiType}, Body: []interface{}{
+			hclAttr{"name", aws.EKSAMIParameterName(cluster.K8sVersion, amiType)},
+		}})
+	}
+
+	nodeSubnets := publicSubnets
+	if cluster.SubnetVisibility == clusterconfig.PrivateSubnetVisibility {
+		nodeSubnets = privateSubnets
+	}
+	for _, ng := range cluster.NodeGroups {
+		blocks = append(blocks, iacTerraformNodeGroup(cluster, ng, nodeSubnets)...)
+	}
+
+	buf := &bytes.Buffer{}
+	buf.WriteString(iacHeader(cluster, "#"))
+	for _, block := range blocks {
+		buf.WriteString("\n")
+		block.write(buf, "")
+	}
+
+	return buf.String(), nil
+}
+
+// returns the subnets in each availability zone, and the resources of the vpc (if the cluster doesn't use existing subnets)
+func iacTerraformNetwork(cluster *iacCluster) (map[string][]hclExpr, map[string][]hclExpr, []hclBlock) {
+	publicSubnets := map[string][]hclExpr{}
+	privateSubnets := map[string][]hclExpr{}
+
+	if cluster.VPC == nil {
+		for _, subnet := range cluster.ExistingSubnets {
+			// existing subnets are all public or all private, depending on the cluster's subnet visibility
+			expr := hclExpr(strconv.Quote(subnet.SubnetID))
+			if cluster.SubnetVisibility == clusterconfig.PrivateSubnetVisibility {
+				privateSubnets[subnet.AvailabilityZone] = append(privateSubnets[subnet.AvailabilityZone], expr)
+			} else {
+				publicSubnets[subnet.AvailabilityZone] = append(publicSubnets[subnet.AvailabilityZone], expr)
+			}
+		}
+		return publicSubnets, privateSubnets, nil
+	}
+
+	clusterOwnershipTag := "kubernetes.io/cluster/" + cluster.Name
+
+	blocks := []hclBlock{
+		{Type: "resource", Labels: []string{"aws_vpc", "cluster"}, Body: []interface{}{
+			hclAttr{"cidr_block", cluster.VPC.CIDR},
+			hclAttr{"enable_dns_hostnames", true},
+			hclAttr{"enable_dns_support", true},
+			hclAttr{"tags", map[string]string{"Name": cluster.Name}},
+		}},
+		{Type: "resource", Labels: []string{"aws_internet_gateway", "cluster"}, Body: []interface{}{
+			hclAttr{"vpc_id", hclExpr("aws_vpc.cluster.id")},
+		}},
+		{Type: "resource", Labels: []string{"aws_route_table", "public"}, Body: []interface{}{
+			hclAttr{"vpc_id", hclExpr("aws_vpc.cluster.id")},
+			hclBlock{Type: "route", Body: []interface{}{
+				hclAttr{"cidr_block", "0.0.0.0/0"},
+				hclAttr{"gateway_id", hclExpr("aws_internet_gateway.cluster.id")},
+			}},
+		}},
+	}
+
+	for _, zone := range cluster.VPC.Zones {
+		zoneID := hclIdentifier(zone.AvailabilityZone)
+		publicSubnets[zone.AvailabilityZone] = []hclExpr{hclExpr(fmt.Sprintf("aws_subnet.public_%s.id", zoneID))}
+		privateSubnets[zone.AvailabilityZone] = []hclExpr{hclExpr(fmt.Sprintf("aws_subnet.private_%s.id", zoneID))}
+
+		blocks = append(blocks,
+			hclBlock{Type: "resource", Labels: []string{"aws_subnet", "public_" + zoneID}, Body: []interface{}{
+				hclAttr{"vpc_id", hclExpr("aws_vpc.cluster.id")},
+				hclAttr{"cidr_block", zone.PublicCIDR},
+				hclAttr{"availability_zone", zone.AvailabilityZone},
+				hclAttr{"map_public_ip_on_launch", true},
+				hclAttr{"tags", map[string]string{
+					"Name":                   fmt.Sprintf("%s-public-%s", cluster.Name, zone.AvailabilityZone),
+					"kubernetes.io/role/elb": "1",
+					clusterOwnershipTag:      "shared",
+				}},
+			}},
+			hclBlock{Type: "resource", Labels: []string{"aws_route_table_association", "public_" + zoneID}, Body: []interface{}{
+				hclAttr{"subnet_id", hclExpr(fmt.Sprintf("aws_subnet.public_%s.id", zoneID))},
+				hclAttr{"route_table_id", hclExpr("aws_route_table.public.id")},
+			}},
+			hclBlock{Type: "resource", Labels: []string{"aws_subnet", "private_" + zoneID}, Body: []interface{}{
+				hclAttr{"vpc_id", hclExpr("aws_vpc.cluster.id")},
+				hclAttr{"cidr_block", zone.PrivateCIDR},
+				hclAttr{"availability_zone", zone.AvailabilityZone},
+				hclAttr{"tags", map[string]string{
+					"Name":                            fmt.Sprintf("%s-private-%s", cluster.Name, zone.AvailabilityZone),
+					"kubernetes.io/role/internal-elb": "1",
+					clusterOwnershipTag:               "shared",
+				}},
+			}},
+		)
+	}
+
+	for i, zone := range cluster.VPC.Zones {
+		zoneID := hclIdentifier(zone.AvailabilityZone)
+
+		privateRouteTable := hclBlock{Type: "resource", Labels: []string{"aws_route_table", "private_" + zoneID}, Body: []interface{}{
+			hclAttr{"vpc_id", hclExpr("aws_vpc.cluster.id")},
+		}}
+
+		natID := ""
+		switch cluster.VPC.NATGateway {
+		case clusterconfig.SingleNATGateway:
+			natID = "nat"
+		case clusterconfig.HighlyAvailableNATGateway:
+			natID = "nat_" + zoneID
+		}
+
+		if natID != "" {
+			if cluster.VPC.NATGateway == clusterconfig.HighlyAvailableNATGateway || i == 0 {
+				blocks = append(blocks,
+					hclBlock{Type: "resource", Labels: []string{"aws_eip", natID}, Body: []interface{}{
+						hclAttr{"domain", "vpc"},
+					}},
+					hclBlock{Type: "resource", Labels: []string{"aws_nat_gateway", natID}, Body: []interface{}{
+						hclAttr{"allocation_id", hclExpr(fmt.Sprintf("aws_eip.%s.id", natID))},
+						hclAttr{"subnet_id", hclExpr(fmt.Sprintf("aws_subnet.public_%s.id", zoneID))},
+						hclAttr{"depends_on", []hclExpr{"aws_internet_gateway.cluster"}},
+					}},
+				)
+			}
+			privateRouteTable.Body = append(privateRouteTable.Body, hclBlock{Type: "route", Body: []interface{}{
+				hclAttr{"cidr_block", "0.0.0.0/0"},
+				hclAttr{"nat_gateway_id", hclExpr(fmt.Sprintf("aws_nat_gateway.%s.id", natID))},
+			}})
+		}
+
+		blocks = append(blocks,
+			privateRouteTable,
+			hclBlock{Type: "resource", Labels: []string{"aws_route_table_association", "private_" + zoneID}, Body: []interface{}{
+				hclAttr{"subnet_id", hclExpr(fmt.Sprintf("aws_subnet.private_%s.id", zoneID))},
+				hclAttr{"route_table_id", hclExpr(fmt.Sprintf("aws_route_table.private_%s.id", zoneID))},
+			}},
+		)
+	}
+
+	return publicSubnets, privateSubnets, blocks
+}
+
+func iacTerraformIAM(cluster *iacCluster) ([]hclBlock, error) {
+	policyDocument, err := iacIndentJSON(cluster.PolicyDocument)
+	if err != nil {
+		return nil, err
+	}
+	clusterAssumeRolePolicy, err := iacAssumeRolePolicy("eks.amazonaws.com")
+	if err != nil {
+		return nil, err
+	}
+	nodeAssumeRolePolicy, err := iacAssumeRolePolicy("ec2.amazonaws.com")
+	if err != nil {
+		return nil, err
+	}
+	autoscalerPolicy, err := iacAutoscalerPolicy()
+	if err != nil {
+		return nil, err
+	}
+
+	return []hclBlock{
+		{Type: "resource", Labels: []string{"aws_iam_policy", "cortex"}, Body: []interface{}{
+			hclAttr{"name", cluster.PolicyName},
+			hclAttr{"policy", hclHeredoc{Content: policyDocument}},
+		}},
+		{Type: "import", Body: []interface{}{
+			hclAttr{"to", hclExpr("aws_iam_policy.cortex")},
+			hclAttr{"id", cluster.PolicyARN},
+		}},
+		{Type: "resource", Labels: []string{"aws_iam_role", "cluster"}, Body: []interface{}{
+			hclAttr{"name_prefix", cluster.Name + "-cluster-"},
+			hclAttr{"assume_role_policy", hclHeredoc{Content: clusterAssumeRolePolicy}},
+		}},
+		{Type: "resource", Labels: []string{"aws_iam_role_policy_attachment", "cluster"}, Body: []interface{}{
+			hclAttr{"for_each", hclExpr(fmt.Sprintf("toset([%q, %q])",
+				fmt.Sprintf("arn:%s:iam::aws:policy/AmazonEKSClusterPolicy", cluster.Partition),
+				fmt.Sprintf("arn:%s:iam::aws:policy/AmazonEKSVPCResourceController", cluster.Partition)))},
+			hclAttr{"role", hclExpr("aws_iam_role.cluster.name")},
+			hclAttr{"policy_arn", hclExpr("each.value")},
+		}},
+		{Type: "resource", Labels: []string{"aws_iam_role", "node"}, Body: []interface{}{
+			hclAttr{"name_prefix", cluster.Name + "-node-"},
+			hclAttr{"assume_role_policy", hclHeredoc{Content: nodeAssumeRolePolicy}},
+		}},
+		{Type: "resource", Labels: []string{"aws_iam_role_policy_attachment", "node"}, Body: []interface{}{
+			hclAttr{"for_each", hclExpr("toset(" + hclValue(cluster.NodePolicyARNs, "") + ")")},
+			hclAttr{"role", hclExpr("aws_iam_role.node.name")},
+			hclAttr{"policy_arn", hclExpr("each.value")},
+		}},
+		{Type: "resource", Labels: []string{"aws_iam_role_policy_attachment", "node_cortex"}, Body: []interface{}{
+			hclAttr{"role", hclExpr("aws_iam_role.node.name")},
+			hclAttr{"policy_arn", hclExpr("aws_iam_policy.cortex.arn")},
+		}},
+		{Type: "resource", Labels: []string{"aws_iam_role_policy", "node_autoscaler"}, Body: []interface{}{
+			hclAttr{"name", "autoscaler"},
+			hclAttr{"role", hclExpr("aws_iam_role.node.id")},
+			hclAttr{"policy", hclHeredoc{Content: autoscalerPolicy}},
+		}},
+		{Type: "resource", Labels: []string{"aws_iam_instance_profile", "node"}, Body: []interface{}{
+			hclAttr{"name_prefix", cluster.Name + "-node-"},
+			hclAttr{"role", hclExpr("aws_iam_role.node.name")},
+		}},
+	}, nil
+}
+
+func iacTerraformNodeGroup(cluster *iacCluster, ng iacNodeGroup, nodeSubnets map[string][]hclExpr) []hclBlock {
+	id := hclIdentifier(ng.Name)
+	launchTemplate := hclExpr("aws_launch_template." + id)
+
+	ebs := []interface{}{
+		hclAttr{"volume_size", ng.VolumeSize},
+		hclAttr{"volume_type", ng.VolumeType},
+	}
+	if ng.VolumeIOPS != nil {
+		ebs = append(ebs, hclAttr{"iops", *ng.VolumeIOPS})
+	}
+	if ng.VolumeThroughput != nil {
+		ebs = append(ebs, hclAttr{"throughput", *ng.VolumeThroughput})
+	}
+	ebs = append(ebs, hclAttr{"delete_on_termination", true})
+
+	launchTemplateBody := []interface{}{
+		hclAttr{"name", fmt.Sprintf("%s-%s", cluster.Name, ng.Name)},
+		hclAttr{"image_id", hclExpr(fmt.Sprintf("data.aws_ssm_parameter.ami_%s.value", ng.AMIType))},
+	}
+	if ng.SpotConfig == nil {
+		launchTemplateBody = append(launchTemplateBody, hclAttr{"instance_type", ng.InstanceType})
+	}
+	launchTemplateBody = append(launchTemplateBody,
+		hclAttr{"vpc_security_group_ids", []hclExpr{"aws_eks_cluster.cluster.vpc_config[0].cluster_security_group_id"}},
+		hclAttr{"user_data", hclHeredoc{Content: ng.userData(cluster), Func: "base64encode"}},
+		hclBlock{Type: "iam_instance_profile", Body: []interface{}{
+			hclAttr{"arn", hclExpr("aws_iam_instance_profile.node.arn")},
+		}},
+		hclBlock{Type: "block_device_mappings", Body: []interface{}{
+			hclAttr{"device_name", "/dev/xvda"},
+			hclBlock{Type: "ebs", Body: ebs},
+		}},
+	)
+	if ng.CapacityReservationID != "" {
+		launchTemplateBody = append(launchTemplateBody, hclBlock{Type: "capacity_reservation_specification", Body: []interface{}{
+			hclBlock{Type: "capacity_reservation_target", Body: []interface{}{
+				hclAttr{"capacity_reservation_id", ng.CapacityReservationID},
+			}},
+		}})
+	}
+
+	var subnets []hclExpr
+	for _, zone := range cluster.nodeGroupZones(ng) {
+		subnets = append(subnets, nodeSubnets[zone]...)
+	}
+
+	asgBody := []interface{}{
+		hclAttr{"name", fmt.Sprintf("%s-%s", cluster.Name, ng.Name)},
+		hclAttr{"min_size", ng.MinSize},
+		hclAttr{"max_size", ng.MaxSize},
+		hclAttr{"desired_capacity", ng.DesiredCapacity},
+		hclAttr{"vpc_zone_identifier", uniqueHCLExprs(subnets)},
+	}
+	if len(ng.SuspendedProcesses) > 0 {
+		asgBody = append(asgBody, hclAttr{"suspended_processes", ng.SuspendedProcesses})
+	}
+
+	launchTemplateSpec := []interface{}{
+		hclAttr{"id", launchTemplate + ".id"},
+		hclAttr{"version", launchTemplate + ".latest_version"},
+	}
+	if ng.SpotConfig == nil {
+		asgBody = append(asgBody, hclBlock{Type: "launch_template", Body: launchTemplateSpec})
+	} else {
+		distribution := []interface{}{}
+		if ng.SpotConfig.OnDemandBaseCapacity != nil {
+			distribution = append(distribution, hclAttr{"on_demand_base_capacity", *ng.SpotConfig.OnDemandBaseCapacity})
+		}
+		if ng.SpotConfig.OnDemandPercentageAboveBaseCapacity != nil {
+			distribution = append(distribution, hclAttr{"on_demand_percentage_above_base_capacity", *ng.SpotConfig.OnDemandPercentageAboveBaseCapacity})
+		}
+		if ng.SpotConfig.InstancePools != nil {
+			distribution = append(distribution, hclAttr{"spot_instance_pools", *ng.SpotConfig.InstancePools})
+		}
+		if ng.SpotConfig.MaxPrice != nil {
+			distribution = append(distribution, hclAttr{"spot_max_price", strconv.FormatFloat(*ng.SpotConfig.MaxPrice, 'f', -1, 64)})
+		}
+
+		launchTemplateOverrides := []interface{}{
+			hclBlock{Type: "launch_template_specification", Body: []interface{}{
+				hclAttr{"launch_template_id", launchTemplate + ".id"},
+				hclAttr{"version", launchTemplate + ".latest_version"},
+			}},
+		}
+		for _, instanceType := range ng.instanceTypes() {
+			launchTemplateOverrides = append(launchTemplateOverrides, hclBlock{Type: "override", Body: []interface{}{
+				hclAttr{"instance_type", instanceType},
+			}})
+		}
+
+		asgBody = append(asgBody, hclBlock{Type: "mixed_instances_policy", Body: []interface{}{
+			hclBlock{Type: "instances_distribution", Body: distribution},
+			hclBlock{Type: "launch_template", Body: launchTemplateOverrides},
+		}})
+	}
+
+	tags := ng.asgTags(cluster)
+	for _, key := range sortedKeys(tags) {
+		asgBody = append(asgBody, hclBlock{Type: "tag", Body: []interface{}{
+			hclAttr{"key", key},
+			hclAttr{"value", tags[key]},
+			hclAttr{"propagate_at_launch", true},
+		}})
+	}
+
+	// the cluster autoscaler manages the desired capacity
+	asgBody = append(asgBody, hclBlock{Type: "lifecycle", Body: []interface{}{
+		hclAttr{"ignore_changes", []hclExpr{"desired_capacity"}},
+	}})
+
+	return []hclBlock{
+		{Type: "resource", Labels: []string{"aws_launch_template", id}, Body: launchTemplateBody},
+		{Type: "resource", Labels: []string{"aws_autoscaling_group", id}, Body: asgBody},
+	}
+}
+
+func uniqueHCLExprs(exprs []hclExpr) []hclExpr {
+	unique := []hclExpr{}
+	seen := map[hclExpr]bool{}
+	for _, expr := range exprs {
+		if !seen[expr] {
+			seen[expr] = true
+			unique = append(unique, expr)
+		}
+	}
+	return unique
+}
+
+func iacIndentJSON(jsonStr string) (string, error) {
+	buf := &bytes.Buffer{}
+	if err := json.Indent(buf, []byte(jsonStr), "", "  "); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func iacAssumeRolePolicy(service string) (string, error) {
+	return iacPolicyJSON(map[string]interface{}{
+		"Effect":    "Allow",
+		"Principal": map[string]interface{}{"Service": service},
+		"Action":    "sts:AssumeRole",
+	})
+}
+
+func iacAutoscalerPolicy() (string, error) {
+	return iacPolicyJSON(map[string]interface{}{
+		"Effect":   "Allow",
+		"Action":   _iacAutoscalerActions,
+		"Resource": "*",
+	})
+}
+
+func iacPolicyJSON(statement map[string]interface{}) (string, error) {
+	policyBytes, err := json.MarshalIndent(map[string]interface{}{
+		"Version":   "2012-10-17",
+		"Statement": []interface{}{statement},
+	}, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(policyBytes), nil
+}
@@ -0,0 +1,229 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/cortexlabs/cortex/pkg/lib/aws"
+	"github.com/cortexlabs/cortex/pkg/lib/docker"
+	"github.com/cortexlabs/cortex/pkg/lib/k8s"
+	"github.com/cortexlabs/cortex/pkg/lib/sets/strset"
+	"github.com/cortexlabs/cortex/pkg/types/clusterconfig"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
+	kcore "k8s.io/api/core/v1"
+)
+
+type apiSpecMigration struct {
+	APIName   string
+	APIID     string
+	FromKey   string
+	ToKey     string
+	SpecBytes []byte
+}
+
+// returns the cortex version which the cluster is running, based on the tag of its operator image (e.g. 0.42.0 or manifest-0.42.0-arm64)
+func clusterCortexVersion(clusterConfig clusterconfig.Config) string {
+	tag := strings.TrimPrefix(docker.ExtractImageTag(clusterConfig.ImageOperator), "manifest-")
+	tag = strings.TrimSuffix(tag, "-"+clusterconfig.AMD64Arch.String())
+	tag = strings.TrimSuffix(tag, "-"+clusterconfig.ARM64Arch.String())
+	return tag
+}
+
+// parses a release version (e.g. 0.42.1); ok is false for development versions (e.g. master)
+func parseCortexVersion(version string) (versionParts [3]int64, ok bool) {
+	parts := strings.Split(version, ".")
+	if len(parts) != 3 {
+		return versionParts, false
+	}
+	for i, part := range parts {
+		n, err := strconv.ParseInt(part, 10, 64)
+		if err != nil {
+			return versionParts, false
+		}
+		versionParts[i] = n
+	}
+	return versionParts, true
+}
+
+// clusters can be upgraded to a newer patch version, or to the next minor version
+func validateUpgradeVersionSkew(clusterVersion string, cliVersion string) error {
+	clusterVersionParts, ok := parseCortexVersion(clusterVersion)
+	if !ok {
+		return nil // development versions are not checked
+	}
+	cliVersionParts, ok := parseCortexVersion(cliVersion)
+	if !ok {
+		return nil
+	}
+
+	for i := range clusterVersionParts {
+		if clusterVersionParts[i] > cliVersionParts[i] {
+			return ErrorClusterNewerThanCLI(clusterVersion, cliVersion)
+		}
+		if clusterVersionParts[i] < cliVersionParts[i] {
+			break
+		}
+	}
+
+	if clusterVersionParts[0] != cliVersionParts[0] || cliVersionParts[1] > clusterVersionParts[1]+1 {
+		return ErrorUnsupportedUpgradeVersionSkew(clusterVersion, cliVersion)
+	}
+
+	return nil
+}
+
+// returns a copy of the cluster config in which the images of fromVersion are replaced with the images of toVersion;
+// the keys of images which were not replaced (i.e. custom images) are also returned
+func upgradeClusterConfigImages(clusterConfig clusterconfig.Config, fromVersion string, toVersion string) (clusterconfig.Config, []string, error) {
+	upgradedClusterConfig, err := clusterConfig.DeepCopy()
+	if err != nil {
+		return clusterconfig.Config{}, nil, err
+	}
+
+	var customImageKeys []string
+	coreConfig := reflect.ValueOf(&upgradedClusterConfig.CoreConfig).Elem()
+	for i := 0; i < coreConfig.NumField(); i++ {
+		field := coreConfig.Type().Field(i)
+		if !strings.HasPrefix(field.Name, "Image") || field.Type.Kind() != reflect.String {
+			continue
+		}
+
+		image, ok := upgradeImage(coreConfig.Field(i).String(), fromVersion, toVersion)
+		if !ok {
+			customImageKeys = append(customImageKeys, strings.Split(field.Tag.Get("yaml"), ",")[0])
+			continue
+		}
+		coreConfig.Field(i).SetString(image)
+	}
+
+	return upgradedClusterConfig, customImageKeys, nil
+}
+
+func upgradeImage(image string, fromVersion string, toVersion string) (string, bool) {
+	tag := docker.ExtractImageTag(image)
+	repository := strings.TrimSuffix(image, ":"+tag)
+
+	if tag == fromVersion {
+		return repository + ":" + toVersion, true
+	}
+
+	// arch-specific tags of multi-arch images are formatted as manifest-<version>-<arch>
+	if strings.HasPrefix(tag, "manifest-"+fromVersion+"-") {
+		return repository + ":manifest-" + toVersion + strings.TrimPrefix(tag, "manifest-"+fromVersion), true
+	}
+
+	return image, false
+}
+
+// returns the IDs of the batch and task jobs which have running or pending pods
+func jobsInProgress(k8sClient *k8s.Client) ([]string, error) {
+	pods, err := k8sClient.ListPodsWithLabelKeys("jobID")
+	if err != nil {
+		return nil, err
+	}
+
+	jobIDs := strset.New()
+	for _, pod := range pods {
+		if pod.Status.Phase == kcore.PodRunning || pod.Status.Phase == kcore.PodPending {
+			jobIDs.Add(pod.Labels["apiName"] + "/" + pod.Labels["jobID"])
+		}
+	}
+
+	return jobIDs.SliceSorted(), nil
+}
+
+// finds the API specs which were written by fromVersion and verifies that they can be read by this version of cortex;
+// the most recent spec of each API must be compatible, while incompatible older specs are skipped (they won't be available for rollbacks)
+func getAPISpecMigrations(awsClient *aws.Client, clusterConfig clusterconfig.Config, fromVersion string) ([]apiSpecMigration, int, error) {
+	prefix := filepath.Join(clusterConfig.ClusterUID, "apis") + "/"
+	suffix := "/" + fromVersion + "-spec.json"
+
+	objects, err := awsClient.ListS3Prefix(clusterConfig.Bucket, prefix, false, nil, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	specKeysByAPI := map[string][]string{} // api name -> spec keys
+	for _, object := range objects {
+		if object.Key == nil || !strings.HasSuffix(*object.Key, suffix) {
+			continue
+		}
+		// <cluster uid>/apis/<api name>/api/<api id>/<version>-spec.json
+		parts := strings.Split(strings.TrimPrefix(*object.Key, prefix), "/")
+		if len(parts) != 4 || parts[1] != "api" {
+			continue
+		}
+		specKeysByAPI[parts[0]] = append(specKeysByAPI[parts[0]], *object.Key)
+	}
+
+	var migrations []apiSpecMigration
+	var numSkipped int
+	for apiName, keys := range specKeysByAPI {
+		// api IDs start with a monotonically decreasing ID, so the most recent spec is first
+		sort.Strings(keys)
+
+		for i, key := range keys {
+			apiID := strings.Split(strings.TrimPrefix(key, prefix), "/")[2]
+
+			specBytes, err := awsClient.ReadBytesFromS3(clusterConfig.Bucket, key)
+			if err != nil {
+				return nil, 0, err
+			}
+
+			decoder := json.NewDecoder(bytes.NewReader(specBytes))
+			decoder.DisallowUnknownFields()
+			var api spec.API
+			if err := decoder.Decode(&api); err != nil {
+				if i == 0 {
+					return nil, 0, ErrorIncompatibleAPISpec(apiName, apiID, err)
+				}
+				numSkipped++
+				continue
+			}
+
+			migrations = append(migrations, apiSpecMigration{
+				APIName:   apiName,
+				APIID:     apiID,
+				FromKey:   key,
+				ToKey:     spec.Key(apiName, apiID, clusterConfig.ClusterUID),
+				SpecBytes: specBytes,
+			})
+		}
+	}
+
+	return migrations, numSkipped, nil
+}
+
+// copies the API specs to the keys which are read by this version of cortex (the original specs are left in place)
+func migrateAPISpecs(awsClient *aws.Client, bucket string, migrations []apiSpecMigration) error {
+	for _, migration := range migrations {
+		if migration.FromKey == migration.ToKey {
+			continue
+		}
+		if err := awsClient.UploadBytesToS3(migration.SpecBytes, bucket, migration.ToKey); err != nil {
+			return err
+		}
+	}
+	return nil
+}
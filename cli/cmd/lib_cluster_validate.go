@@ -0,0 +1,95 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/cortexlabs/cortex/pkg/consts"
+	cr "github.com/cortexlabs/cortex/pkg/lib/configreader"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/print"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	"github.com/cortexlabs/cortex/pkg/types/clusterconfig"
+)
+
+type clusterValidationError struct {
+	Kind    string `json:"kind"`
+	Message string `json:"message"`
+}
+
+type clusterValidationResult struct {
+	Valid  bool                     `json:"valid"`
+	Errors []clusterValidationError `json:"errors"`
+}
+
+// runs the same validations as `cortex cluster up` (including the checks against your aws account) without creating any resources
+func validateClusterConfigFile(clusterConfigFile string, printToStdout bool) clusterValidationResult {
+	result := clusterValidationResult{
+		Errors: []clusterValidationError{},
+	}
+
+	addErr := func(err error) {
+		result.Errors = append(result.Errors, clusterValidationError{
+			Kind:    errors.GetKind(err),
+			Message: errors.Message(err),
+		})
+	}
+
+	clusterConfig := &clusterconfig.Config{}
+	errs := cr.ParseYAMLFile(clusterConfig, clusterconfig.FullConfigValidation, clusterConfigFile)
+	if errors.HasError(errs) {
+		for _, err := range errs {
+			if err != nil {
+				addErr(err)
+			}
+		}
+		return result
+	}
+
+	awsClient, err := newAWSClient(clusterConfig.Region, printToStdout)
+	if err != nil {
+		addErr(err)
+		return result
+	}
+
+	if printToStdout {
+		fmt.Print("verifying your configuration ...\n\n")
+	}
+
+	if err := clusterConfig.ValidateOnInstall(awsClient); err != nil {
+		addErr(errors.Wrap(err, clusterConfigFile))
+		return result
+	}
+
+	result.Valid = true
+	return result
+}
+
+func printClusterValidationResult(clusterConfigFile string, result clusterValidationResult) {
+	if result.Valid {
+		fmt.Printf("%s is valid\n", clusterConfigFile)
+		return
+	}
+
+	for _, validationErr := range result.Errors {
+		print.BoldFirstLine("error: " + validationErr.Message)
+		fmt.Println()
+	}
+
+	fmt.Printf("%s is invalid (%d %s found); cluster configuration schema can be found at https://docs.cortexlabs.com/v/%s/\n", clusterConfigFile, len(result.Errors), s.PluralS("error", len(result.Errors)), consts.CortexVersionMinor)
+}
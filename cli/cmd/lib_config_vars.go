@@ -0,0 +1,100 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	cr "github.com/cortexlabs/cortex/pkg/lib/configreader"
+	"github.com/cortexlabs/cortex/pkg/lib/envsubst"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/files"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	"github.com/spf13/cobra"
+)
+
+func addConfigVarFlags(cmd *cobra.Command) {
+	cmd.Flags().StringArrayVar(&_flagConfigVars, "var", nil, "value for a ${NAME} reference in the config file, e.g. \"STAGE=prod\" (can be specified multiple times)")
+	cmd.Flags().StringVar(&_flagConfigVarFile, "var-file", "", "path to a yaml file which maps variable names to values for the ${NAME} references in the config file")
+}
+
+// readConfigBytes reads the config file and substitutes its ${NAME} references with the values from --var, --var-file, and the environment (in that order of precedence)
+func readConfigBytes(configPath string) ([]byte, error) {
+	configBytes, err := files.ReadFileBytes(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	configVars, err := getConfigVars()
+	if err != nil {
+		return nil, err
+	}
+
+	substituted, err := envsubst.Substitute(string(configBytes), func(name string) (string, bool) {
+		if value, ok := configVars[name]; ok {
+			return value, true
+		}
+		return os.LookupEnv(name)
+	})
+	if err != nil {
+		if errors.GetKind(err) == envsubst.ErrUndefinedVariables {
+			err = errors.Append(err, " (set variables with --var, --var-file, or environment variables, or specify a default value with ${NAME:-default})")
+		}
+		return nil, errors.Wrap(err, filepath.Base(configPath))
+	}
+
+	return []byte(substituted), nil
+}
+
+func getConfigVars() (map[string]string, error) {
+	configVars := map[string]string{}
+
+	if _flagConfigVarFile != "" {
+		varFilePath := files.RelToAbsPath(_flagConfigVarFile, _cwd)
+		if err := files.CheckFile(varFilePath); err != nil {
+			return nil, err
+		}
+
+		varFile, err := cr.ReadYAMLFileStrMap(varFilePath)
+		if err != nil {
+			return nil, errors.Wrap(err, _flagConfigVarFile)
+		}
+
+		for name, value := range varFile {
+			switch value.(type) {
+			case map[interface{}]interface{}, map[string]interface{}, []interface{}:
+				return nil, errors.Wrap(ErrorInvalidConfigVarFileValue(name), _flagConfigVarFile)
+			case nil:
+				configVars[name] = ""
+			default:
+				configVars[name] = s.ObjFlatNoQuotes(value)
+			}
+		}
+	}
+
+	for _, configVar := range _flagConfigVars {
+		split := strings.SplitN(configVar, "=", 2)
+		if len(split) != 2 || split[0] == "" {
+			return nil, ErrorInvalidConfigVar(configVar)
+		}
+		configVars[split[0]] = split[1]
+	}
+
+	return configVars, nil
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/files"
+	"github.com/cortexlabs/cortex/pkg/lib/hash"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+)
+
+// repositories on these hosts can be referenced without the git:: prefix, e.g. github.com/org/repo//path?ref=sha
+var _gitHostPrefixes = []string{"github.com/", "gitlab.com/", "bitbucket.org/", "git@"}
+
+// parseGitReference parses references of the form [git::]REPOSITORY[//PATH][?ref=REF];
+// PATH is the config file (or a directory which contains cortex.yaml) within the repository
+func parseGitReference(str string) (*userconfig.GitSource, bool, error) {
+	explicit := strings.HasPrefix(str, "git::")
+	str = strings.TrimPrefix(str, "git::")
+
+	if !explicit {
+		isGitHost := false
+		for _, prefix := range _gitHostPrefixes {
+			if strings.HasPrefix(str, prefix) {
+				isGitHost = true
+				break
+			}
+		}
+		if !isGitHost {
+			return nil, false, nil
+		}
+	}
+
+	gitSource := userconfig.GitSource{}
+
+	if i := strings.Index(str, "?"); i >= 0 {
+		query, err := url.ParseQuery(str[i+1:])
+		if err != nil {
+			return nil, true, ErrorInvalidGitReference(str, err.Error())
+		}
+		gitSource.Ref = query.Get("ref")
+		str = str[:i]
+	}
+
+	scheme := ""
+	if i := strings.Index(str, "://"); i >= 0 {
+		scheme = str[:i+3]
+		str = str[i+3:]
+	}
+
+	configPath := ""
+	if i := strings.Index(str, "//"); i >= 0 {
+		configPath = str[i+2:]
+		str = str[:i]
+	}
+
+	if str == "" {
+		return nil, true, ErrorInvalidGitReference(scheme+str, "the repository is missing")
+	}
+
+	gitSource.Repository = scheme + str
+	if scheme == "" && !strings.HasPrefix(str, "git@") {
+		gitSource.Repository = "https://" + str
+	}
+
+	configPath = path.Clean("/" + configPath)[1:]
+	if !files.IsFilePathYAML(configPath) {
+		configPath = path.Join(configPath, "cortex.yaml")
+	}
+	gitSource.Path = configPath
+
+	return &gitSource, true, nil
+}
+
+// checkoutGitSource checks out the reference into a clone of the repository (which is kept in the cli's config directory so that it can be
+// fetched incrementally), sets the commit of the git source, and returns the absolute path of the config file
+func checkoutGitSource(gitSource *userconfig.GitSource) (string, error) {
+	repoDir := filepath.Join(_localDir, "git", hash.String(gitSource.Repository)[:16])
+
+	if files.IsDir(repoDir) {
+		if _, err := runGit(repoDir, "fetch", "--quiet", "--tags", "--force", "origin"); err != nil {
+			return "", err
+		}
+	} else {
+		fmt.Fprintf(os.Stderr, "￮ cloning %s\n", gitSource.Repository)
+		if err := os.MkdirAll(filepath.Dir(repoDir), os.ModePerm); err != nil {
+			return "", errors.WithStack(err)
+		}
+		if _, err := runGit("", "clone", "--quiet", "--no-checkout", gitSource.Repository, repoDir); err != nil {
+			_ = os.RemoveAll(repoDir)
+			return "", err
+		}
+	}
+
+	commit, err := resolveGitRef(repoDir, gitSource.Ref)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := runGit(repoDir, "checkout", "--quiet", "--force", "--detach", commit); err != nil {
+		return "", err
+	}
+	gitSource.Commit = commit
+
+	configPath := filepath.Join(repoDir, filepath.FromSlash(gitSource.Path))
+	if !files.IsFile(configPath) {
+		return "", ErrorGitConfigFileNotFound(gitSource.Path, gitSource.Repository, commit)
+	}
+
+	return configPath, nil
+}
+
+// resolveGitRef returns the commit of a branch, tag, or commit (branches are resolved from the remote, since the local clone doesn't track them)
+func resolveGitRef(repoDir string, ref string) (string, error) {
+	if ref == "" {
+		ref = "HEAD"
+	}
+
+	for _, candidate := range []string{"origin/" + ref, ref} {
+		commit, err := runGit(repoDir, "rev-parse", "--verify", "--quiet", candidate+"^{commit}")
+		if err == nil {
+			return commit, nil
+		}
+	}
+
+	return "", ErrorGitRefNotFound(ref)
+}
+
+func runGit(dir string, args ...string) (string, error) {
+	if _, err := exec.LookPath("git"); err != nil {
+		return "", ErrorGitNotInstalled()
+	}
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", ErrorGitCommandFailed(args, strings.TrimSpace(string(output)))
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
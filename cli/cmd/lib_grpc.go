@@ -0,0 +1,286 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	rpb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// dialGRPCEndpoint connects to the load balancer which serves an api endpoint (the path of the endpoint is ignored, since grpc requests are routed by their metadata)
+func dialGRPCEndpoint(endpoint string) (*grpc.ClientConn, error) {
+	endpointURL, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, errors.Wrap(err, endpoint)
+	}
+
+	port := endpointURL.Port()
+	creds := insecure.NewCredentials()
+	if endpointURL.Scheme == "https" {
+		creds = credentials.NewTLS(&tls.Config{ServerName: endpointURL.Hostname()})
+		if port == "" {
+			port = "443"
+		}
+	} else if port == "" {
+		port = "80"
+	}
+
+	return grpc.Dial(net.JoinHostPort(endpointURL.Hostname(), port), grpc.WithTransportCredentials(creds))
+}
+
+// splitGRPCMethod splits a method name of the form package.Service/Method (or package.Service.Method) into the service's and the method's names
+func splitGRPCMethod(method string) (string, string, error) {
+	trimmed := strings.TrimPrefix(method, "/")
+
+	separator := strings.LastIndex(trimmed, "/")
+	if separator == -1 {
+		separator = strings.LastIndex(trimmed, ".")
+	}
+	if separator <= 0 || separator == len(trimmed)-1 {
+		return "", "", ErrorInvalidGRPCMethod(method)
+	}
+
+	return trimmed[:separator], trimmed[separator+1:], nil
+}
+
+// grpcReflectionClient resolves the descriptors of a grpc server's services with the server reflection protocol
+type grpcReflectionClient struct {
+	stream          rpb.ServerReflection_ServerReflectionInfoClient
+	fileDescriptors map[string]*descriptorpb.FileDescriptorProto
+}
+
+func newGRPCReflectionClient(ctx context.Context, conn *grpc.ClientConn) (*grpcReflectionClient, error) {
+	stream, err := rpb.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+	if err != nil {
+		return nil, ErrorGRPCReflection(err.Error())
+	}
+
+	return &grpcReflectionClient{
+		stream:          stream,
+		fileDescriptors: map[string]*descriptorpb.FileDescriptorProto{},
+	}, nil
+}
+
+func (c *grpcReflectionClient) Close() error {
+	return c.stream.CloseSend()
+}
+
+func (c *grpcReflectionClient) request(req *rpb.ServerReflectionRequest) (*rpb.ServerReflectionResponse, error) {
+	if err := c.stream.Send(req); err != nil {
+		return nil, ErrorGRPCReflection(err.Error())
+	}
+
+	res, err := c.stream.Recv()
+	if err != nil {
+		return nil, ErrorGRPCReflection(err.Error())
+	}
+
+	if errRes := res.GetErrorResponse(); errRes != nil {
+		if symbol := req.GetFileContainingSymbol(); symbol != "" && errRes.GetErrorCode() == int32(codes.NotFound) {
+			return nil, ErrorGRPCServiceNotFound(symbol)
+		}
+		return nil, ErrorGRPCReflection(errRes.GetErrorMessage())
+	}
+
+	return res, nil
+}
+
+// ListServices returns the names of the services which are served by the server, excluding the reflection service
+func (c *grpcReflectionClient) ListServices() ([]string, error) {
+	res, err := c.request(&rpb.ServerReflectionRequest{
+		MessageRequest: &rpb.ServerReflectionRequest_ListServices{ListServices: "*"},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var serviceNames []string
+	for _, service := range res.GetListServicesResponse().GetService() {
+		if strings.HasPrefix(service.GetName(), "grpc.reflection.") {
+			continue
+		}
+		serviceNames = append(serviceNames, service.GetName())
+	}
+	sort.Strings(serviceNames)
+
+	return serviceNames, nil
+}
+
+func (c *grpcReflectionClient) ServiceDescriptor(serviceName string) (protoreflect.ServiceDescriptor, error) {
+	res, err := c.request(&rpb.ServerReflectionRequest{
+		MessageRequest: &rpb.ServerReflectionRequest_FileContainingSymbol{FileContainingSymbol: serviceName},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.addFileDescriptors(res); err != nil {
+		return nil, err
+	}
+
+	if err := c.resolveDependencies(); err != nil {
+		return nil, err
+	}
+
+	fileDescriptorSet := &descriptorpb.FileDescriptorSet{}
+	for _, fileDescriptor := range c.fileDescriptors {
+		fileDescriptorSet.File = append(fileDescriptorSet.File, fileDescriptor)
+	}
+
+	files, err := protodesc.NewFiles(fileDescriptorSet)
+	if err != nil {
+		return nil, ErrorGRPCReflection(err.Error())
+	}
+
+	descriptor, err := files.FindDescriptorByName(protoreflect.FullName(serviceName))
+	if err != nil {
+		return nil, ErrorGRPCServiceNotFound(serviceName)
+	}
+
+	serviceDescriptor, ok := descriptor.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil, ErrorGRPCServiceNotFound(serviceName)
+	}
+
+	return serviceDescriptor, nil
+}
+
+func (c *grpcReflectionClient) addFileDescriptors(res *rpb.ServerReflectionResponse) error {
+	for _, fileDescriptorBytes := range res.GetFileDescriptorResponse().GetFileDescriptorProto() {
+		fileDescriptor := &descriptorpb.FileDescriptorProto{}
+		if err := proto.Unmarshal(fileDescriptorBytes, fileDescriptor); err != nil {
+			return ErrorGRPCReflection(err.Error())
+		}
+		c.fileDescriptors[fileDescriptor.GetName()] = fileDescriptor
+	}
+	return nil
+}
+
+// resolveDependencies fetches the files which are imported by the fetched files, since servers aren't required to include them in their responses
+func (c *grpcReflectionClient) resolveDependencies() error {
+	for {
+		missingFile := ""
+		for _, fileDescriptor := range c.fileDescriptors {
+			for _, dependency := range fileDescriptor.GetDependency() {
+				if _, ok := c.fileDescriptors[dependency]; !ok {
+					missingFile = dependency
+					break
+				}
+			}
+			if missingFile != "" {
+				break
+			}
+		}
+
+		if missingFile == "" {
+			return nil
+		}
+
+		res, err := c.request(&rpb.ServerReflectionRequest{
+			MessageRequest: &rpb.ServerReflectionRequest_FileByFilename{FileByFilename: missingFile},
+		})
+		if err != nil {
+			return err
+		}
+
+		numFiles := len(c.fileDescriptors)
+		if err := c.addFileDescriptors(res); err != nil {
+			return err
+		}
+		if len(c.fileDescriptors) == numFiles {
+			return ErrorGRPCReflection(fmt.Sprintf("the server did not return the descriptor of %s", missingFile))
+		}
+	}
+}
+
+func grpcMethodStr(method protoreflect.MethodDescriptor) string {
+	input := string(method.Input().FullName())
+	if method.IsStreamingClient() {
+		input = "stream " + input
+	}
+
+	output := string(method.Output().FullName())
+	if method.IsStreamingServer() {
+		output = "stream " + output
+	}
+
+	return fmt.Sprintf("%s(%s) returns (%s)", method.Name(), input, output)
+}
+
+// invokeGRPCMethod sends the json-encoded request to a unary or server streaming method, and prints the json-encoded responses
+func invokeGRPCMethod(ctx context.Context, conn *grpc.ClientConn, method protoreflect.MethodDescriptor, data string) error {
+	fullMethod := fmt.Sprintf("/%s/%s", method.Parent().FullName(), method.Name())
+	if method.IsStreamingClient() {
+		return ErrorGRPCClientStreamingNotSupported(fullMethod)
+	}
+
+	req := dynamicpb.NewMessage(method.Input())
+	if err := protojson.Unmarshal([]byte(data), req); err != nil {
+		return errors.Wrap(err, "--data")
+	}
+
+	streamDesc := &grpc.StreamDesc{
+		StreamName:    string(method.Name()),
+		ServerStreams: method.IsStreamingServer(),
+	}
+	stream, err := conn.NewStream(ctx, streamDesc, fullMethod)
+	if err != nil {
+		return err
+	}
+	if err := stream.SendMsg(req); err != nil {
+		return err
+	}
+	if err := stream.CloseSend(); err != nil {
+		return err
+	}
+
+	for {
+		res := dynamicpb.NewMessage(method.Output())
+		err := stream.RecvMsg(res)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		resBytes, err := protojson.MarshalOptions{Multiline: true}.Marshal(res)
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(resBytes))
+	}
+}
@@ -0,0 +1,175 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/cortexlabs/cortex/pkg/consts"
+	"github.com/cortexlabs/cortex/pkg/lib/aws"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/exit"
+	"github.com/cortexlabs/cortex/pkg/lib/pointer"
+	"github.com/cortexlabs/cortex/pkg/lib/prompt"
+	"github.com/cortexlabs/cortex/pkg/lib/table"
+	"github.com/cortexlabs/cortex/pkg/types/clusterconfig"
+)
+
+type iamPermissionCheck struct {
+	Actions   []string
+	Resources []string
+}
+
+// the permissions that are exercised by `cortex cluster up` (directly, and via eksctl and cloudformation in the manager container)
+func clusterUpPermissionChecks(clusterConfig *clusterconfig.Config) []iamPermissionCheck {
+	partition := aws.PartitionFromRegion(clusterConfig.Region)
+	iamARNPrefix := fmt.Sprintf("arn:%s:iam::%s:", partition, clusterConfig.AccountID)
+
+	return []iamPermissionCheck{
+		{
+			Actions: []string{
+				"sts:GetCallerIdentity",
+				"ecr:GetAuthorizationToken",
+				"ecr:BatchGetImage",
+				"iam:GetPolicy",
+				"servicequotas:ListServiceQuotas",
+				"cloudformation:CreateStack",
+				"cloudformation:DescribeStacks",
+				"cloudformation:DescribeStackEvents",
+				"cloudformation:ListStacks",
+				"ec2:CreateVpc",
+				"ec2:CreateSubnet",
+				"ec2:CreateSecurityGroup",
+				"ec2:AuthorizeSecurityGroupIngress",
+				"ec2:CreateLaunchTemplate",
+				"ec2:RunInstances",
+				"ec2:CreateTags",
+				"ec2:DescribeSubnets",
+				"ec2:DescribeAvailabilityZones",
+				"eks:CreateCluster",
+				"eks:DescribeCluster",
+				"eks:CreateAddon",
+				"autoscaling:CreateAutoScalingGroup",
+				"autoscaling:UpdateAutoScalingGroup",
+				"autoscaling:DescribeAutoScalingGroups",
+				"elasticloadbalancing:DescribeLoadBalancers",
+				"cloudwatch:PutDashboard",
+				"kms:DescribeKey",
+				"kms:CreateGrant",
+				"logs:PutRetentionPolicy",
+			},
+			Resources: []string{"*"},
+		},
+		{
+			Actions: []string{
+				"iam:CreateRole",
+				"iam:GetRole",
+				"iam:TagRole",
+				"iam:AttachRolePolicy",
+				"iam:PutRolePolicy",
+				"iam:PassRole",
+			},
+			Resources: []string{iamARNPrefix + "role/eksctl-" + clusterConfig.ClusterName + "-cluster-ServiceRole"},
+		},
+		{
+			Actions: []string{
+				"iam:CreateInstanceProfile",
+				"iam:AddRoleToInstanceProfile",
+			},
+			Resources: []string{iamARNPrefix + "instance-profile/eksctl-" + clusterConfig.ClusterName + "-nodegroup"},
+		},
+		{
+			Actions: []string{
+				"iam:CreateOpenIDConnectProvider",
+				"iam:GetOpenIDConnectProvider",
+			},
+			Resources: []string{iamARNPrefix + "oidc-provider/*"},
+		},
+		{
+			Actions: []string{
+				"iam:CreatePolicy",
+				"iam:CreatePolicyVersion",
+			},
+			Resources: []string{clusterConfig.CortexPolicyARN},
+		},
+		{
+			Actions: []string{
+				"logs:CreateLogGroup",
+				"logs:TagLogGroup",
+			},
+			Resources: []string{fmt.Sprintf("arn:%s:logs:%s:%s:log-group:%s", partition, clusterConfig.Region, clusterConfig.AccountID, clusterConfig.ClusterName)},
+		},
+		{
+			Actions: []string{
+				"s3:CreateBucket",
+				"s3:PutBucketTagging",
+				"s3:PutLifecycleConfiguration",
+			},
+			Resources: []string{fmt.Sprintf("arn:%s:s3:::%s", partition, clusterConfig.Bucket)},
+		},
+	}
+}
+
+// simulates the permissions required for `cortex cluster up`, and prints a table of the ones which are missing;
+// if the simulation itself can't be run (e.g. for the root user, or if iam:SimulatePrincipalPolicy is not allowed), the check is skipped
+func confirmClusterUpPermissions(clusterConfig *clusterconfig.Config, awsClient *aws.Client, disallowPrompt bool) {
+	principalARN, err := awsClient.CallerPrincipalARN()
+	if err != nil || principalARN == "" {
+		return
+	}
+
+	var denied []aws.PolicySimulationResult
+	for _, check := range clusterUpPermissionChecks(clusterConfig) {
+		results, err := awsClient.SimulatePrincipalPolicy(principalARN, check.Actions, check.Resources...)
+		if err != nil {
+			fmt.Printf("note: unable to verify your IAM permissions before creating the cluster (%s)\n\n", errors.Message(err))
+			return
+		}
+		for _, result := range results {
+			if !result.IsAllowed() {
+				denied = append(denied, result)
+			}
+		}
+	}
+
+	if len(denied) == 0 {
+		return
+	}
+
+	rows := make([][]interface{}, len(denied))
+	for i, result := range denied {
+		rows[i] = []interface{}{result.Action, result.Resource, result.Decision}
+	}
+
+	fmt.Printf("%s is missing the following permissions, which are required to create a cluster:\n\n", principalARN)
+	t := table.Table{
+		Headers: []table.Header{
+			{Title: "action"},
+			{Title: "resource"},
+			{Title: "decision"},
+		},
+		Rows: rows,
+	}
+	t.MustPrint(&table.Opts{Sort: pointer.Bool(false)})
+	fmt.Println()
+
+	docsMsg := fmt.Sprintf("see https://docs.cortexlabs.com/v/%s/ for the minimum IAM policy required to run `cortex cluster` commands", consts.CortexVersionMinor)
+	if disallowPrompt {
+		exit.Error(ErrorMissingIAMPermissions(len(denied), docsMsg))
+	}
+	prompt.YesOrExit("the permissions were evaluated with the IAM policy simulator, which may not account for conditions in your policies; would you like to continue anyway?", "", docsMsg)
+}
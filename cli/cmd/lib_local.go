@@ -0,0 +1,402 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/cortexlabs/cortex/cli/lib/routines"
+	"github.com/cortexlabs/cortex/pkg/consts"
+	"github.com/cortexlabs/cortex/pkg/lib/aws"
+	"github.com/cortexlabs/cortex/pkg/lib/docker"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/exit"
+	"github.com/cortexlabs/cortex/pkg/lib/files"
+	"github.com/cortexlabs/cortex/pkg/lib/pointer"
+	"github.com/cortexlabs/cortex/pkg/lib/regex"
+	"github.com/cortexlabs/cortex/pkg/lib/slices"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	"github.com/cortexlabs/cortex/pkg/modelcache"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+	"github.com/cortexlabs/cortex/pkg/workloads"
+	dockertypes "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/docker/go-connections/nat"
+	kcore "k8s.io/api/core/v1"
+)
+
+type localContainer struct {
+	name string
+	id   string
+}
+
+// runLocal runs the containers of one of the apis in the config file with the local docker daemon,
+// with the same environment variables and mounts (including the cached model) that the api's pods have in the cluster;
+// the api is served on hostPort (or on the api's pod port if hostPort is 0)
+func runLocal(configPath string, configBytes []byte, apiName string, hostPort int32) error {
+	apiConfig, err := getLocalAPIConfig(configPath, configBytes, apiName)
+	if err != nil {
+		return err
+	}
+
+	api := spec.API{API: apiConfig}
+
+	if api.Kind != userconfig.TaskAPIKind && api.Pod.Port == nil {
+		api.Pod.Port = pointer.Int32(consts.DefaultUserPodPortInt32)
+	}
+	if hostPort == 0 && api.Pod.Port != nil {
+		hostPort = *api.Pod.Port
+	}
+
+	if api.ModelWatch != nil {
+		awsClient, err := aws.NewFromS3Path(api.ModelWatch.Path)
+		if err != nil {
+			return err
+		}
+		api.ModelVersion, err = spec.LatestModelVersion(api.ModelWatch.Path, awsClient)
+		if err != nil {
+			return err
+		}
+	}
+
+	var modelCacheDir string
+	if api.ModelCache != nil {
+		modelCacheDir, err = downloadLocalModel(workloads.ModelCachePath(api))
+		if err != nil {
+			return err
+		}
+	}
+
+	containers, volumes := workloads.UserPodContainers(api)
+
+	dockerClient, err := docker.GetDockerClient()
+	if err != nil {
+		return err
+	}
+
+	for _, kContainer := range containers {
+		if err := pullLocalImage(kContainer.Image); err != nil {
+			return err
+		}
+	}
+
+	volumesByName := map[string]kcore.Volume{}
+	for _, volume := range volumes {
+		volumesByName[volume.Name] = volume
+	}
+
+	var localContainers []localContainer
+	var dockerVolumes []string
+	var cleanupOnce sync.Once
+
+	cleanup := func() {
+		cleanupOnce.Do(func() {
+			for _, localContainer := range localContainers {
+				_ = dockerClient.ContainerRemove(context.Background(), localContainer.id, dockertypes.ContainerRemoveOptions{
+					RemoveVolumes: true,
+					Force:         true,
+				})
+			}
+			for _, dockerVolume := range dockerVolumes {
+				_ = dockerClient.VolumeRemove(context.Background(), dockerVolume, true)
+			}
+		})
+	}
+
+	// Make sure to remove the containers immediately on ctrl+c
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+
+	routines.RunWithPanicHandler(func() {
+		<-c
+		fmt.Println()
+		cleanup()
+		exit.Ok()
+	}, false)
+
+	for i, kContainer := range containers {
+		containerConfig := &container.Config{
+			Image:      kContainer.Image,
+			Entrypoint: kContainer.Command,
+			Cmd:        kContainer.Args,
+			Env:        localEnvVars(kContainer.Env),
+		}
+
+		hostConfig := &container.HostConfig{}
+
+		for _, volumeMount := range kContainer.VolumeMounts {
+			volume := volumesByName[volumeMount.Name]
+			switch {
+			case volume.EmptyDir != nil && volume.EmptyDir.Medium == kcore.StorageMediumMemory:
+				// shared memory is configured with the container's shm size
+				continue
+			case volume.EmptyDir != nil:
+				dockerVolume := fmt.Sprintf("cortex-local-%s-%s", api.Name, volume.Name)
+				if !slices.HasString(dockerVolumes, dockerVolume) {
+					dockerVolumes = append(dockerVolumes, dockerVolume)
+				}
+				hostConfig.Mounts = append(hostConfig.Mounts, mount.Mount{
+					Type:   mount.TypeVolume,
+					Source: dockerVolume,
+					Target: volumeMount.MountPath,
+				})
+			case volume.HostPath != nil && modelCacheDir != "":
+				hostConfig.Mounts = append(hostConfig.Mounts, mount.Mount{
+					Type:     mount.TypeBind,
+					Source:   modelCacheDir,
+					Target:   volumeMount.MountPath,
+					ReadOnly: true,
+				})
+			}
+		}
+
+		userContainer := api.Pod.Containers[i]
+		if userContainer.Compute != nil {
+			if userContainer.Compute.Shm != nil {
+				hostConfig.ShmSize = userContainer.Compute.Shm.Value()
+			}
+			if userContainer.Compute.GPU > 0 {
+				hostConfig.DeviceRequests = []container.DeviceRequest{
+					{
+						Count:        int(userContainer.Compute.GPU),
+						Capabilities: [][]string{{"gpu"}},
+					},
+				}
+			}
+		}
+
+		if i == 0 {
+			hostConfig.IpcMode = "shareable"
+			if api.Pod.Port != nil {
+				containerPort := nat.Port(fmt.Sprintf("%d/tcp", *api.Pod.Port))
+				containerConfig.ExposedPorts = nat.PortSet{containerPort: struct{}{}}
+				hostConfig.PortBindings = nat.PortMap{
+					containerPort: []nat.PortBinding{{HostIP: "127.0.0.1", HostPort: s.Int32(hostPort)}},
+				}
+			}
+		} else {
+			// the containers of a pod share its network and ipc namespaces
+			hostConfig.NetworkMode = container.NetworkMode("container:" + localContainers[0].id)
+			hostConfig.IpcMode = container.IpcMode("container:" + localContainers[0].id)
+		}
+
+		containerInfo, err := dockerClient.ContainerCreate(context.Background(), containerConfig, hostConfig, nil, fmt.Sprintf("cortex-local-%s-%s", api.Name, kContainer.Name))
+		if err != nil {
+			cleanup()
+			return docker.WrapDockerError(err)
+		}
+		localContainers = append(localContainers, localContainer{name: kContainer.Name, id: containerInfo.ID})
+
+		err = dockerClient.ContainerStart(context.Background(), containerInfo.ID, dockertypes.ContainerStartOptions{})
+		if err != nil {
+			cleanup()
+			return docker.WrapDockerError(err)
+		}
+	}
+
+	if api.Pod.Port != nil {
+		fmt.Printf("%s is running locally at http://localhost:%d (press ctrl+c to stop)\n\n", api.Name, hostPort)
+	} else {
+		fmt.Printf("%s is running locally (press ctrl+c to stop)\n\n", api.Name)
+	}
+
+	var wg sync.WaitGroup
+	for _, localContainer := range localContainers {
+		localContainer := localContainer
+		wg.Add(1)
+		routines.RunWithPanicHandler(func() {
+			defer wg.Done()
+			streamLocalContainerLogs(localContainer, len(localContainers) > 1)
+		}, false)
+	}
+	wg.Wait()
+
+	var failedContainers []string
+	for _, localContainer := range localContainers {
+		info, err := dockerClient.ContainerInspect(context.Background(), localContainer.id)
+		if err == nil && info.State.ExitCode != 0 {
+			failedContainers = append(failedContainers, fmt.Sprintf("%s (exit code %d)", localContainer.name, info.State.ExitCode))
+		}
+	}
+
+	cleanup()
+
+	if len(failedContainers) > 0 {
+		return ErrorLocalContainersFailed(failedContainers)
+	}
+
+	return nil
+}
+
+func getLocalAPIConfig(configPath string, configBytes []byte, apiName string) (*userconfig.API, error) {
+	apiConfigs, err := spec.ExtractAPIConfigs(configBytes, filepath.Base(configPath))
+	if err != nil {
+		return nil, err
+	}
+
+	if apiName == "" {
+		if len(apiConfigs) > 1 {
+			apiNames := make([]string, len(apiConfigs))
+			for i := range apiConfigs {
+				apiNames[i] = apiConfigs[i].Name
+			}
+			return nil, ErrorLocalAPIRequired(configPath, apiNames)
+		}
+		apiName = apiConfigs[0].Name
+	}
+
+	for i := range apiConfigs {
+		if apiConfigs[i].Name != apiName {
+			continue
+		}
+		if apiConfigs[i].Kind == userconfig.TrafficSplitterKind {
+			return nil, ErrorLocalKindNotSupported(apiConfigs[i].Kind)
+		}
+		return &apiConfigs[i], nil
+	}
+
+	return nil, ErrorLocalAPINotFound(configPath, apiName)
+}
+
+// downloadLocalModel downloads the model into the same cache directory layout that is used on the cluster's nodes,
+// so that it is only downloaded once
+func downloadLocalModel(s3Path string) (string, error) {
+	modelDir := filepath.Join(_localDir, "local", "model-cache", modelcache.Key(s3Path))
+	if files.IsDir(modelDir) {
+		return modelDir, nil
+	}
+
+	awsClient, err := aws.NewFromS3Path(s3Path)
+	if err != nil {
+		return "", err
+	}
+
+	bucket, key, err := aws.SplitS3Path(s3Path)
+	if err != nil {
+		return "", err
+	}
+
+	fmt.Printf("￮ downloading model %s\n", s3Path)
+
+	tmpDir := modelDir + ".tmp"
+	if err := os.RemoveAll(tmpDir); err != nil {
+		return "", errors.WithStack(err)
+	}
+	if err := awsClient.DownloadDirFromS3(bucket, key, tmpDir, true, nil); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmpDir, modelDir); err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	return modelDir, nil
+}
+
+func pullLocalImage(image string) error {
+	authConfig := docker.NoAuth
+	if regex.IsValidECRURL(image) {
+		awsClient, err := aws.NewForRegion(aws.GetRegionFromECRURL(image))
+		if err != nil {
+			return err
+		}
+		authConfig, err = docker.AWSAuthConfig(awsClient)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err := docker.PullImage(image, authConfig, docker.PrintDots)
+	if err != nil {
+		if strings.Contains(err.Error(), "auth") {
+			err = errors.Append(err, fmt.Sprintf("\n\nif your image is stored in a private repository: run `docker login` (if you haven't already), download your image with `docker pull %s`, and try this command again)", image))
+		}
+		return err
+	}
+
+	return nil
+}
+
+// localEnvVars converts the container's env vars to docker's format; env vars which are populated by the cluster (e.g. from the pod's status) are skipped
+func localEnvVars(envVars []kcore.EnvVar) []string {
+	var dockerEnvVars []string
+	for _, envVar := range envVars {
+		if envVar.ValueFrom != nil {
+			continue
+		}
+		dockerEnvVars = append(dockerEnvVars, envVar.Name+"="+envVar.Value)
+	}
+	return dockerEnvVars
+}
+
+func streamLocalContainerLogs(localContainer localContainer, prefixLogs bool) {
+	dockerClient, err := docker.GetDockerClient()
+	if err != nil {
+		return
+	}
+
+	logsOutput, err := dockerClient.ContainerLogs(context.Background(), localContainer.id, dockertypes.ContainerLogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Follow:     true,
+	})
+	if err != nil {
+		return
+	}
+	defer logsOutput.Close()
+
+	var stdout, stderr io.Writer = os.Stdout, os.Stderr
+	if prefixLogs {
+		stdout = &linePrefixWriter{prefix: "[" + localContainer.name + "] ", writer: os.Stdout}
+		stderr = &linePrefixWriter{prefix: "[" + localContainer.name + "] ", writer: os.Stderr}
+	}
+
+	_, _ = stdcopy.StdCopy(stdout, stderr, logsOutput)
+}
+
+// linePrefixWriter prefixes each complete line which is written to it
+type linePrefixWriter struct {
+	prefix string
+	writer io.Writer
+	buffer bytes.Buffer
+}
+
+func (w *linePrefixWriter) Write(p []byte) (int, error) {
+	w.buffer.Write(p)
+	for {
+		i := bytes.IndexByte(w.buffer.Bytes(), '\n')
+		if i < 0 {
+			break
+		}
+		line := w.buffer.Next(i + 1)
+		if _, err := io.WriteString(w.writer, w.prefix+string(line)); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
@@ -52,7 +52,7 @@ type dockerCopyToPath struct {
 	containerPath string
 }
 
-func runManager(containerConfig *container.Config, addNewLineAfterPull bool, copyToPaths []dockerCopyToPath, copyFromPaths []dockerCopyFromPath) (string, *int, error) {
+func runManager(containerConfig *container.Config, awsClient *aws.Client, addNewLineAfterPull bool, copyToPaths []dockerCopyToPath, copyFromPaths []dockerCopyFromPath) (string, *int, error) {
 	containerConfig.Env = append(containerConfig.Env, "CORTEX_CLI_VERSION="+consts.CortexVersion)
 
 	// Add a slight delay before running the command to ensure logs don't start until after the container is attached
@@ -113,6 +113,9 @@ func runManager(containerConfig *container.Config, addNewLineAfterPull bool, cop
 		return "", nil, docker.WrapDockerError(err)
 	}
 
+	stopRefreshingCredentials := refreshManagerAWSCredentials(containerInfo.ID, awsClient)
+	defer stopRefreshingCredentials()
+
 	// Use ContainerAttach() since that allows logs to be streamed even if they don't end in new lines
 	logsOutput, err := dockerClient.ContainerAttach(context.Background(), containerInfo.ID, dockertypes.ContainerAttachOptions{
 		Stream: true,
@@ -184,16 +187,26 @@ func runManagerWithClusterConfig(entrypoint string, clusterConfig *clusterconfig
 		containerPath: "/",
 	})
 
+	telemetryDisable := os.Getenv("CORTEX_TELEMETRY_DISABLE")
+	if clusterConfig.Offline {
+		telemetryDisable = "true"
+	}
+
+	credentialsCopyPath, credentialsEnvs, err := managerAWSCredentials(awsClient)
+	if err != nil {
+		return "", nil, err
+	}
+	copyToPaths = append(copyToPaths, credentialsCopyPath)
+
 	envs := []string{
-		"AWS_ACCESS_KEY_ID=" + *awsClient.AccessKeyID(),
-		"AWS_SECRET_ACCESS_KEY=" + *awsClient.SecretAccessKey(),
-		"CORTEX_TELEMETRY_DISABLE=" + os.Getenv("CORTEX_TELEMETRY_DISABLE"),
+		"CORTEX_TELEMETRY_DISABLE=" + telemetryDisable,
 		"CORTEX_TELEMETRY_SENTRY_DSN=" + os.Getenv("CORTEX_TELEMETRY_SENTRY_DSN"),
 		"CORTEX_TELEMETRY_SEGMENT_WRITE_KEY=" + os.Getenv("CORTEX_TELEMETRY_SEGMENT_WRITE_KEY"),
 		"CORTEX_DEV_DEFAULT_IMAGE_REGISTRY=" + os.Getenv("CORTEX_DEV_DEFAULT_IMAGE_REGISTRY"),
 		"CORTEX_DEV_ADD_CONTROL_PLANE_DASHBOARD=" + os.Getenv("CORTEX_DEV_ADD_CONTROL_PLANE_DASHBOARD"),
 		"CORTEX_CLUSTER_CONFIG_FILE=" + containerClusterConfigPath,
 	}
+	envs = append(envs, credentialsEnvs...)
 	envs = append(envs, extraEnvs...)
 	containerConfig := &container.Config{
 		Image:        clusterConfig.ImageManager,
@@ -205,11 +218,7 @@ func runManagerWithClusterConfig(entrypoint string, clusterConfig *clusterconfig
 		Env:          envs,
 	}
 
-	if sessionToken := awsClient.SessionToken(); sessionToken != nil {
-		containerConfig.Env = append(containerConfig.Env, "AWS_SESSION_TOKEN="+*sessionToken)
-	}
-
-	output, exitCode, err := runManager(containerConfig, false, copyToPaths, copyFromPaths)
+	output, exitCode, err := runManager(containerConfig, awsClient, false, copyToPaths, copyFromPaths)
 	if err != nil {
 		return "", nil, err
 	}
@@ -217,7 +226,22 @@ func runManagerWithClusterConfig(entrypoint string, clusterConfig *clusterconfig
 	return output, exitCode, nil
 }
 
+// runs the manager with the cluster config, passing along which nodegroups the manager must resize, add, and remove
+func runManagerWithNodeGroupChanges(entrypoint string, clusterConfig *clusterconfig.Config, awsClient *aws.Client, configureChanges clusterconfig.ConfigureChanges) (string, *int, error) {
+	return runManagerWithClusterConfig(entrypoint, clusterConfig, awsClient, nil, nil, []string{
+		"CORTEX_NODEGROUP_NAMES_TO_UPDATE=" + strings.Join(configureChanges.NodeGroupsToUpdate, " "),        // NodeGroupsToUpdate contain the cluster config node-group names
+		"CORTEX_NODEGROUP_NAMES_TO_ADD=" + strings.Join(configureChanges.NodeGroupsToAdd, " "),              // NodeGroupsToAdd contain the cluster config node-group names
+		"CORTEX_EKS_NODEGROUP_NAMES_TO_REMOVE=" + strings.Join(configureChanges.EKSNodeGroupsToRemove, " "), // EKSNodeGroupsToRemove contain the EKS node-group names
+	})
+}
+
 func runManagerAccessCommand(entrypoint string, accessConfig clusterconfig.AccessConfig, awsClient *aws.Client, copyToPaths []dockerCopyToPath, copyFromPaths []dockerCopyFromPath) (string, *int, error) {
+	credentialsCopyPath, credentialsEnvs, err := managerAWSCredentials(awsClient)
+	if err != nil {
+		return "", nil, err
+	}
+	copyToPaths = append(copyToPaths, credentialsCopyPath)
+
 	containerConfig := &container.Config{
 		Image:        accessConfig.ImageManager,
 		Entrypoint:   []string{"/bin/bash", "-c"},
@@ -225,25 +249,110 @@ func runManagerAccessCommand(entrypoint string, accessConfig clusterconfig.Acces
 		Tty:          true,
 		AttachStdout: true,
 		AttachStderr: true,
-		Env: []string{
-			"AWS_ACCESS_KEY_ID=" + *awsClient.AccessKeyID(),
-			"AWS_SECRET_ACCESS_KEY=" + *awsClient.SecretAccessKey(),
+		Env: append([]string{
 			"CORTEX_CLUSTER_NAME=" + accessConfig.ClusterName,
 			"CORTEX_REGION=" + accessConfig.Region,
 			"CORTEX_TELEMETRY_DISABLE=" + os.Getenv("CORTEX_TELEMETRY_DISABLE"),
 			"CORTEX_TELEMETRY_SENTRY_DSN=" + os.Getenv("CORTEX_TELEMETRY_SENTRY_DSN"),
 			"CORTEX_TELEMETRY_SEGMENT_WRITE_KEY=" + os.Getenv("CORTEX_TELEMETRY_SEGMENT_WRITE_KEY"),
-		},
-	}
-
-	if sessionToken := awsClient.SessionToken(); sessionToken != nil {
-		containerConfig.Env = append(containerConfig.Env, "AWS_SESSION_TOKEN="+*sessionToken)
+		}, credentialsEnvs...),
 	}
 
-	output, exitCode, err := runManager(containerConfig, true, copyToPaths, copyFromPaths)
+	output, exitCode, err := runManager(containerConfig, awsClient, true, copyToPaths, copyFromPaths)
 	if err != nil {
 		return "", nil, err
 	}
 
 	return output, exitCode, nil
 }
+
+const (
+	_managerAWSConfigPath      = "/root/.aws/config"
+	_managerAWSCredentialsPath = "/root/.aws/cortex-credentials.json"
+
+	// temporary credentials are replaced this long before they expire (the aws cli refreshes credentials which expire within 15 minutes)
+	_managerCredentialsRefreshWindow = 20 * time.Minute
+)
+
+// managerAWSCredentials returns the files which provide the aws credentials to the manager container, and the environment variables which point to them.
+// The credentials are copied into the container and read via credential_process (rather than being set as environment variables, which are visible via `docker inspect`)
+func managerAWSCredentials(awsClient *aws.Client) (dockerCopyToPath, []string, error) {
+	credentialsJSON, err := awsClient.CredentialProcessJSON()
+	if err != nil {
+		return dockerCopyToPath{}, nil, err
+	}
+
+	awsConfig := fmt.Sprintf("[default]\ncredential_process = cat %s\n", _managerAWSCredentialsPath)
+
+	copyPath := dockerCopyToPath{
+		input: &archive.Input{
+			Bytes: []archive.BytesInput{
+				{
+					Content: []byte(awsConfig),
+					Dest:    _managerAWSConfigPath,
+				},
+				{
+					Content: credentialsJSON,
+					Dest:    _managerAWSCredentialsPath,
+				},
+			},
+		},
+		containerPath: "/",
+	}
+
+	envs := []string{
+		"AWS_CONFIG_FILE=" + _managerAWSConfigPath,
+		"AWS_SDK_LOAD_CONFIG=1", // required for tools which use older versions of the aws go sdk to read credential_process from the config file
+	}
+
+	return copyPath, envs, nil
+}
+
+// refreshManagerAWSCredentials replaces the manager container's credentials before they expire (if they are temporary, e.g. from an assumed role,
+// an sso session, or an mfa session), so that long-running commands don't fail mid-way; it stops when the returned function is called
+func refreshManagerAWSCredentials(containerID string, awsClient *aws.Client) func() {
+	done := make(chan struct{})
+
+	routines.RunWithPanicHandler(func() {
+		for {
+			expiration := awsClient.CredentialsExpiration()
+			if expiration == nil {
+				return
+			}
+
+			select {
+			case <-done:
+				return
+			case <-time.After(time.Until(expiration.Add(-_managerCredentialsRefreshWindow))):
+			}
+
+			// mfa codes are only prompted for on the main goroutine, since the manager's output is being streamed to the terminal
+			if err := awsClient.RefreshCredentialsWithoutPrompt(); err != nil {
+				if errors.GetKind(err) == aws.ErrMFATokenRequired {
+					fmt.Printf("warning: the aws credentials of the cluster manager can't be refreshed without an mfa code, and expire at %s\n", expiration.Local().Format(time.RFC1123))
+				} else {
+					errors.PrintError(err, "unable to refresh the aws credentials of the cluster manager")
+				}
+				return
+			}
+
+			credentialsCopyPath, _, err := managerAWSCredentials(awsClient)
+			if err == nil {
+				err = docker.CopyToContainer(containerID, credentialsCopyPath.input, credentialsCopyPath.containerPath)
+			}
+			if err != nil {
+				errors.PrintError(err, "unable to refresh the aws credentials of the cluster manager")
+				return
+			}
+
+			// stop if the credentials can't be renewed for longer (e.g. if the sso session expires soon)
+			if newExpiration := awsClient.CredentialsExpiration(); newExpiration == nil || time.Until(*newExpiration) <= _managerCredentialsRefreshWindow {
+				return
+			}
+		}
+	}, false)
+
+	return func() {
+		close(done)
+	}
+}
@@ -22,11 +22,14 @@ import (
 	"time"
 
 	"github.com/cortexlabs/cortex/cli/types/cliconfig"
+	"github.com/cortexlabs/cortex/pkg/consts"
 	"github.com/cortexlabs/cortex/pkg/lib/console"
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
 	"github.com/cortexlabs/cortex/pkg/lib/table"
 	libtime "github.com/cortexlabs/cortex/pkg/lib/time"
 	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
 )
 
 func realtimeAPITable(realtimeAPI schema.APIResponse, env cliconfig.Environment) (string, error) {
@@ -41,6 +44,13 @@ func realtimeAPITable(realtimeAPI schema.APIResponse, env cliconfig.Environment)
 
 	if realtimeAPI.Endpoint != nil {
 		out += "\n" + console.Bold("endpoint: ") + *realtimeAPI.Endpoint + "\n"
+		if realtimeAPI.Spec != nil && realtimeAPI.Spec.Pod != nil && realtimeAPI.Spec.Pod.Protocol == userconfig.ProtocolGRPC {
+			out += fmt.Sprintf("grpc requests are routed to this api by the \"%s: %s\" metadata (the endpoint's path is not used); run `cortex endpoint test %s` to list its services\n", strings.ToLower(consts.CortexAPINameHeader), realtimeAPI.Spec.Name, realtimeAPI.Spec.Name)
+		}
+	}
+
+	if realtimeAPI.Canary != nil {
+		out += "\n" + console.Bold("canary: ") + canaryStr(realtimeAPI.Canary) + "\n"
 	}
 
 	out += "\n" + apiHistoryTable(realtimeAPI.APIVersions)
@@ -74,12 +84,29 @@ func realtimeDescribeAPITable(realtimeAPI schema.APIResponse, env cliconfig.Envi
 		out += "\n" + console.Bold("endpoint: ") + *realtimeAPI.Endpoint + "\n"
 	}
 
+	if realtimeAPI.ImagePrepull != nil {
+		out += "\n" + console.Bold("image pre-pull: ") + imagePrepullStr(realtimeAPI.ImagePrepull) + "\n"
+	}
+
 	t = replicaCountTable(realtimeAPI.Status.ReplicaCounts)
 	out += "\n" + t.MustFormat()
 
+	if len(realtimeAPI.Events) > 0 {
+		t = eventsTable(realtimeAPI.Events)
+		out += "\n" + t.MustFormat()
+	}
+
 	return out, nil
 }
 
+func canaryStr(canary *schema.CanaryResponse) string {
+	replicasStr := fmt.Sprintf("%d/%d %s ready", canary.Status.Ready, canary.Status.Requested, s.PluralS("replica", canary.Status.Requested))
+	if canary.Status.Ready == 0 && canary.Weight == 0 {
+		return fmt.Sprintf("%s (traffic will be routed to the canary once it's ready); api id: %s", replicasStr, canary.APIID)
+	}
+	return fmt.Sprintf("%s, receiving %d%% of traffic; api id: %s", replicasStr, canary.Weight, canary.APIID)
+}
+
 func realtimeAPIsTable(realtimeAPIs []schema.APIResponse, envNames []string) table.Table {
 	rows := make([][]interface{}, 0, len(realtimeAPIs))
 
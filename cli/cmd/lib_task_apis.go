@@ -18,6 +18,7 @@ package cmd
 
 import (
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/PEAT-AI/yaml"
@@ -26,10 +27,12 @@ import (
 	"github.com/cortexlabs/cortex/cli/types/flags"
 	"github.com/cortexlabs/cortex/pkg/lib/console"
 	libjson "github.com/cortexlabs/cortex/pkg/lib/json"
+	"github.com/cortexlabs/cortex/pkg/lib/maps"
 	"github.com/cortexlabs/cortex/pkg/lib/pointer"
 	"github.com/cortexlabs/cortex/pkg/lib/table"
 	libtime "github.com/cortexlabs/cortex/pkg/lib/time"
 	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types/status"
 )
 
 const (
@@ -65,6 +68,7 @@ func taskAPIsTable(taskAPIs []schema.APIResponse, envNames []string) table.Table
 			envNames[i],
 			taskAPI.Metadata.Name,
 			runningJobs,
+			taskAPI.QueuedJobCount,
 			latestJobID,
 			libtime.SinceStr(&lastAPIUpdated),
 		})
@@ -75,6 +79,7 @@ func taskAPIsTable(taskAPIs []schema.APIResponse, envNames []string) table.Table
 			{Title: _titleEnvironment},
 			{Title: _titleTaskAPI},
 			{Title: _titleTaskJobCount},
+			{Title: _titleQueuedJobCount},
 			{Title: _titleLatestTaskJobID},
 			{Title: _titleLastUpdated},
 		},
@@ -163,6 +168,10 @@ func getTaskJob(env cliconfig.Environment, apiName string, jobID string) (string
 	jobIntroTable := table.KeyValuePairs{}
 	jobIntroTable.Add("job id", job.ID)
 	jobIntroTable.Add("status", job.Status.Message())
+	if len(job.Dependencies) > 0 {
+		jobIntroTable.Add("depends on", jobDependenciesStr(job.Dependencies))
+	}
+	jobIntroTable.Add("max retries", jobMaxRetriesStr(job.MaxRetries))
 	out += jobIntroTable.String(&table.KeyValuePairOpts{BoldKeys: pointer.Bool(true)})
 
 	jobTimingTable := table.KeyValuePairs{}
@@ -177,10 +186,47 @@ func getTaskJob(env cliconfig.Environment, apiName string, jobID string) (string
 	}
 	duration := jobEndTime.Sub(job.StartTime).Truncate(time.Second).String()
 	jobTimingTable.Add("duration", duration)
+	jobTimingTable.Add("timeout", jobTimeoutStr(job.Timeout))
 
 	out += "\n" + jobTimingTable.String(&table.KeyValuePairOpts{BoldKeys: pointer.Bool(true)})
 
-	if job.Status.IsCompleted() {
+	if len(job.Params) > 0 {
+		out += titleStr("params")
+		paramsTable := table.KeyValuePairs{}
+		paramNames := maps.StrMapKeysString(job.Params)
+		sort.Strings(paramNames)
+		for _, name := range paramNames {
+			paramsTable.Add(name, job.Params[name])
+		}
+		out += paramsTable.String()
+	}
+
+	if job.Checkpoint != nil {
+		out += titleStr("checkpoint")
+		checkpointTable := table.KeyValuePairs{}
+		checkpointTable.Add("checkpoint dir", job.Checkpoint.JobDir(job.ID))
+		if job.Checkpoint.Interval != nil {
+			checkpointTable.Add("interval", fmt.Sprintf("%ds", *job.Checkpoint.Interval))
+		}
+		if job.CheckpointRestore == nil {
+			checkpointTable.Add("restarts", 0)
+		} else {
+			checkpointTable.Add("restarts", job.CheckpointRestore.Restarts)
+			checkpointTable.Add("last restart", job.CheckpointRestore.LastRestartTime.Format(_timeFormat))
+			if job.CheckpointRestore.RestoredFrom != "" {
+				checkpointTable.Add("restored from checkpoint", job.CheckpointRestore.RestoredFrom)
+			} else {
+				checkpointTable.Add("restored from checkpoint", "- (no checkpoint had been written)")
+			}
+		}
+		out += checkpointTable.String()
+	}
+
+	if job.Status == status.JobPendingDependencies {
+		out += "\n" + "waiting for the jobs which this job depends on to succeed, workers have not been allocated for this job yet\n"
+	} else if job.Status == status.JobQueued {
+		out += "\n" + "queued because the api is running its maximum number of concurrent jobs, workers have not been allocated for this job yet (run `cortex queue " + job.APIName + "` to see its position)\n"
+	} else if job.Status.IsCompleted() {
 		out += "\n" + "worker stats are not available because this job is not currently running\n"
 	} else {
 		out += titleStr("worker stats")
@@ -0,0 +1,88 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/cortexlabs/cortex/cli/types/flags"
+	"github.com/cortexlabs/cortex/pkg/lib/console"
+	"github.com/cortexlabs/cortex/pkg/lib/exit"
+	libjson "github.com/cortexlabs/cortex/pkg/lib/json"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	"github.com/cortexlabs/cortex/pkg/lib/telemetry"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
+	"github.com/spf13/cobra"
+)
+
+func lintInit() {
+	_lintCmd.Flags().SortFlags = false
+	addConfigVarFlags(_lintCmd)
+	_lintCmd.Flags().VarP(&_flagOutput, "output", "o", fmt.Sprintf("output format: one of %s", strings.Join(flags.OutputTypeStringsExcluding(flags.YAMLOutputType), "|")))
+}
+
+var _lintCmd = &cobra.Command{
+	Use:   "lint [CONFIG_FILE | GIT_REFERENCE]",
+	Short: "validate an api configuration without deploying it (no cluster is required)",
+	Args:  cobra.RangeArgs(0, 1),
+	Run: func(cmd *cobra.Command, args []string) {
+		telemetry.Event("cli.lint")
+
+		configPath, _ := getConfigSource(args)
+
+		configBytes, err := readConfigBytes(configPath)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		issues := spec.LintAPIConfigs(configBytes, filepath.Base(configPath))
+
+		switch _flagOutput {
+		case flags.JSONOutputType:
+			if issues == nil {
+				issues = []spec.LintIssue{}
+			}
+			bytes, err := libjson.Marshal(issues)
+			if err != nil {
+				exit.Error(err)
+			}
+			fmt.Print(string(bytes))
+		case flags.PrettyOutputType:
+			fmt.Print(lintIssuesStr(filepath.Base(configPath), issues))
+		}
+
+		if len(issues) > 0 {
+			exit.Error(nil)
+		}
+	},
+}
+
+func lintIssuesStr(configFileName string, issues []spec.LintIssue) string {
+	if len(issues) == 0 {
+		return fmt.Sprintf("%s %s is valid\n", console.Green("✓"), configFileName)
+	}
+
+	var out string
+	for _, issue := range issues {
+		out += fmt.Sprintf("%s %s\n%s\n\n", console.Red("x"), console.Bold(issue.Code), s.Indent(issue.Message, "  "))
+	}
+	out += fmt.Sprintf("%d %s found in %s\n", len(issues), s.PluralS("issue", len(issues)), configFileName)
+
+	return out
+}
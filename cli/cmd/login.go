@@ -0,0 +1,188 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cortexlabs/cortex/cli/cluster"
+	"github.com/cortexlabs/cortex/cli/types/cliconfig"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/exit"
+	"github.com/cortexlabs/cortex/pkg/lib/oidc"
+	"github.com/cortexlabs/cortex/pkg/lib/print"
+	"github.com/cortexlabs/cortex/pkg/lib/telemetry"
+	"github.com/spf13/cobra"
+)
+
+// id tokens are refreshed shortly before they expire, so that they don't expire in flight
+const _idTokenRefreshLeeway = 1 * time.Minute
+
+var (
+	_flagLoginEnv string
+)
+
+func loginInit() {
+	_loginCmd.Flags().SortFlags = false
+	_loginCmd.Flags().StringVarP(&_flagLoginEnv, "env", "e", "", "environment to use")
+
+	_logoutCmd.Flags().SortFlags = false
+	_logoutCmd.Flags().StringVarP(&_flagLoginEnv, "env", "e", "", "environment to use")
+}
+
+var _loginCmd = &cobra.Command{
+	Use:   "login",
+	Short: "log in to an environment with your organization's identity provider",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		env := loginEnvOrExit("cli.login")
+
+		err := printEnvIfNotSpecified(env.Name, cmd)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		oidcConfig, err := cluster.GetOIDCConfig(cluster.OperatorConfig{
+			Telemetry:        isTelemetryEnabled(),
+			ClientID:         clientID(),
+			EnvName:          env.Name,
+			OperatorEndpoint: env.OperatorEndpoint,
+		})
+		if err != nil {
+			exit.Error(err)
+		}
+
+		metadata, err := oidc.Discover(oidcConfig.IssuerURL)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		deviceAuth, err := oidc.StartDeviceAuthorization(metadata, oidcConfig.ClientID, oidcConfig.Scopes)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		if deviceAuth.VerificationURIComplete != "" {
+			fmt.Printf("to log in, visit %s and confirm that the code is %s\n\n", deviceAuth.VerificationURIComplete, deviceAuth.UserCode)
+		} else {
+			fmt.Printf("to log in, visit %s and enter the code %s\n\n", deviceAuth.VerificationURI, deviceAuth.UserCode)
+		}
+		fmt.Println("waiting for the login to be approved ...")
+
+		token, err := oidc.PollDeviceToken(metadata, oidcConfig.ClientID, deviceAuth)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		env.OIDCLogin = &cliconfig.OIDCLogin{
+			IssuerURL:    oidcConfig.IssuerURL,
+			ClientID:     oidcConfig.ClientID,
+			IDToken:      token.IDToken,
+			RefreshToken: token.RefreshToken,
+			Expiry:       token.Expiry.Unix(),
+		}
+		if err := addEnvToCLIConfig(env, false); err != nil {
+			exit.Error(err)
+		}
+
+		whoAmIResponse, err := cluster.WhoAmI(MustGetOperatorConfig(env.Name))
+		if err != nil {
+			exit.Error(err)
+		}
+
+		print.BoldFirstLine(fmt.Sprintf("logged in to the %s environment as %s", env.Name, whoAmIResponse.Caller))
+	},
+}
+
+var _logoutCmd = &cobra.Command{
+	Use:   "logout",
+	Short: "log out of an environment (the cli will use your aws credentials again)",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		env := loginEnvOrExit("cli.logout")
+
+		err := printEnvIfNotSpecified(env.Name, cmd)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		if env.OIDCLogin == nil {
+			fmt.Printf("the %s environment is not logged in\n", env.Name)
+			return
+		}
+
+		env.OIDCLogin = nil
+		if err := addEnvToCLIConfig(env, false); err != nil {
+			exit.Error(err)
+		}
+
+		print.BoldFirstLine(fmt.Sprintf("logged out of the %s environment", env.Name))
+	},
+}
+
+func loginEnvOrExit(eventName string) cliconfig.Environment {
+	envName, err := getEnvFromFlag(_flagLoginEnv)
+	if err != nil {
+		telemetry.Event(eventName)
+		exit.Error(err)
+	}
+
+	env, err := ReadOrConfigureEnv(envName)
+	if err != nil {
+		telemetry.Event(eventName)
+		exit.Error(err)
+	}
+	telemetry.Event(eventName, map[string]interface{}{"env_name": env.Name})
+
+	return env
+}
+
+// getOrRefreshIDToken returns the environment's id token; if it has expired (or is about to), it's refreshed and the environment is updated
+func getOrRefreshIDToken(env *cliconfig.Environment) (string, error) {
+	login := env.OIDCLogin
+	if time.Now().Add(_idTokenRefreshLeeway).Before(time.Unix(login.Expiry, 0)) {
+		return login.IDToken, nil
+	}
+
+	if login.RefreshToken == "" {
+		return "", oidc.ErrorTokenExpired()
+	}
+
+	metadata, err := oidc.Discover(login.IssuerURL)
+	if err != nil {
+		return "", err
+	}
+
+	token, err := oidc.Refresh(metadata, login.ClientID, login.RefreshToken)
+	if err != nil {
+		return "", errors.Append(err, "\n\nrun `cortex login` to log in again")
+	}
+
+	env.OIDCLogin = &cliconfig.OIDCLogin{
+		IssuerURL:    login.IssuerURL,
+		ClientID:     login.ClientID,
+		IDToken:      token.IDToken,
+		RefreshToken: token.RefreshToken,
+		Expiry:       token.Expiry.Unix(),
+	}
+	if err := addEnvToCLIConfig(*env, false); err != nil {
+		return "", err
+	}
+
+	return token.IDToken, nil
+}
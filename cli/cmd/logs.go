@@ -29,6 +29,11 @@ var (
 	_flagLogsEnv            string
 	_flagLogsDisallowPrompt bool
 	_flagRandomPod          bool
+	_flagLogsFollow         bool
+	_flagLogsSince          string
+	_flagLogsReplica        string
+	_flagLogsFilter         string
+	_flagLogsAllWorkers     bool
 	_logsOutput             = `Navigate to the link below and click "Run Query":
 
 %s
@@ -42,6 +47,11 @@ func logsInit() {
 	_logsCmd.Flags().StringVarP(&_flagLogsEnv, "env", "e", "", "environment to use")
 	_logsCmd.Flags().BoolVarP(&_flagLogsDisallowPrompt, "yes", "y", false, "skip prompts")
 	_logsCmd.Flags().BoolVarP(&_flagRandomPod, "random-pod", "", false, "stream logs from a random pod")
+	_logsCmd.Flags().BoolVarP(&_flagLogsFollow, "follow", "f", false, "stream logs from all replicas until interrupted")
+	_logsCmd.Flags().StringVar(&_flagLogsSince, "since", "", "only show logs newer than a relative duration (e.g. 30s, 5m, or 1h)")
+	_logsCmd.Flags().StringVar(&_flagLogsReplica, "replica", "", "only show logs from the replica with this name (or name suffix)")
+	_logsCmd.Flags().StringVar(&_flagLogsFilter, "filter", "", "only show log lines which match this regular expression")
+	_logsCmd.Flags().BoolVar(&_flagLogsAllWorkers, "all-workers", false, "show the logs of all of a job's workers, including completed workers (can be combined with --filter)")
 }
 
 var _logsCmd = &cobra.Command{
@@ -60,7 +70,23 @@ var _logsCmd = &cobra.Command{
 			telemetry.Event("cli.logs")
 			exit.Error(err)
 		}
-		telemetry.Event("cli.logs", map[string]interface{}{"env_name": env.Name, "random_pod": _flagRandomPod})
+		streamReplicas := _flagLogsFollow || _flagLogsSince != "" || _flagLogsReplica != "" || _flagLogsFilter != ""
+		telemetry.Event("cli.logs", map[string]interface{}{"env_name": env.Name, "random_pod": _flagRandomPod, "stream_replicas": streamReplicas})
+
+		if _flagRandomPod && streamReplicas {
+			exit.Error(ErrorMutuallyExclusiveFlags("--random-pod", logStreamFlag()))
+		}
+		if _flagLogsAllWorkers {
+			if len(args) == 1 {
+				exit.Error(ErrorFlagRequiresJobID("--all-workers"))
+			}
+			if _flagRandomPod {
+				exit.Error(ErrorMutuallyExclusiveFlags("--all-workers", "--random-pod"))
+			}
+			if _flagLogsFollow || _flagLogsSince != "" || _flagLogsReplica != "" {
+				exit.Error(ErrorMutuallyExclusiveFlags("--all-workers", logStreamFlag()))
+			}
+		}
 
 		err = printEnvIfNotSpecified(env.Name, cmd)
 		if err != nil {
@@ -70,7 +96,22 @@ var _logsCmd = &cobra.Command{
 		operatorConfig := MustGetOperatorConfig(env.Name)
 		apiName := args[0]
 
+		logStreamOptions := cluster.LogStreamOptions{
+			Follow:  _flagLogsFollow,
+			Since:   _flagLogsSince,
+			Replica: _flagLogsReplica,
+			Filter:  _flagLogsFilter,
+		}
+
 		if len(args) == 1 {
+			if streamReplicas {
+				err := cluster.StreamReplicaLogs(operatorConfig, apiName, logStreamOptions)
+				if err != nil {
+					exit.Error(err)
+				}
+				return
+			}
+
 			if _flagRandomPod {
 				err := cluster.StreamLogs(operatorConfig, apiName)
 				if err != nil {
@@ -88,6 +129,22 @@ var _logsCmd = &cobra.Command{
 		}
 
 		jobID := args[1]
+		if _flagLogsAllWorkers {
+			err := cluster.StreamAggregatedJobLogs(operatorConfig, apiName, jobID, _flagLogsFilter)
+			if err != nil {
+				exit.Error(err)
+			}
+			return
+		}
+
+		if streamReplicas {
+			err := cluster.StreamJobReplicaLogs(operatorConfig, apiName, jobID, logStreamOptions)
+			if err != nil {
+				exit.Error(err)
+			}
+			return
+		}
+
 		if _flagRandomPod {
 			err := cluster.StreamJobLogs(operatorConfig, apiName, jobID)
 			if err != nil {
@@ -103,3 +160,17 @@ var _logsCmd = &cobra.Command{
 		fmt.Printf(_logsOutput, logResponse.LogURL)
 	},
 }
+
+// logStreamFlag returns the first log streaming flag which was specified
+func logStreamFlag() string {
+	switch {
+	case _flagLogsFollow:
+		return "--follow"
+	case _flagLogsSince != "":
+		return "--since"
+	case _flagLogsReplica != "":
+		return "--replica"
+	default:
+		return "--filter"
+	}
+}
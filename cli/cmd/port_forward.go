@@ -0,0 +1,141 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+
+	"github.com/cortexlabs/cortex/cli/cluster"
+	"github.com/cortexlabs/cortex/cli/lib/routines"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/exit"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	"github.com/cortexlabs/cortex/pkg/lib/telemetry"
+	"github.com/spf13/cobra"
+)
+
+var (
+	_flagPortForwardEnv     string
+	_flagPortForwardReplica string
+	_flagPortForwardAddress string
+)
+
+func portForwardInit() {
+	_portForwardCmd.Flags().SortFlags = false
+	_portForwardCmd.Flags().StringVarP(&_flagPortForwardEnv, "env", "e", "", "environment to use")
+	_portForwardCmd.Flags().StringVar(&_flagPortForwardReplica, "replica", "", "name (or name suffix) of the replica to forward to (default: the first running replica)")
+	_portForwardCmd.Flags().StringVar(&_flagPortForwardAddress, "address", "localhost", "local address to listen on")
+}
+
+var _portForwardCmd = &cobra.Command{
+	Use:   "port-forward API_NAME [JOB_ID] [LOCAL_PORT:]REMOTE_PORT",
+	Short: "forward a local port to a port of a running replica (or job worker)",
+	Args:  cobra.RangeArgs(2, 3),
+	Run: func(cmd *cobra.Command, args []string) {
+		envName, err := getEnvFromFlag(_flagPortForwardEnv)
+		if err != nil {
+			telemetry.Event("cli.port-forward")
+			exit.Error(err)
+		}
+
+		env, err := ReadOrConfigureEnv(envName)
+		if err != nil {
+			telemetry.Event("cli.port-forward")
+			exit.Error(err)
+		}
+		telemetry.Event("cli.port-forward", map[string]interface{}{"env_name": env.Name, "job": len(args) == 3})
+
+		err = printEnvIfNotSpecified(env.Name, cmd)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		apiName := args[0]
+		portMapping := args[len(args)-1]
+		localPort, remotePort, err := parsePortMapping(portMapping)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		options := cluster.PortForwardOptions{
+			Replica: _flagPortForwardReplica,
+			Port:    remotePort,
+		}
+		if len(args) == 3 {
+			options.JobID = args[1]
+		}
+
+		listener, err := net.Listen("tcp", net.JoinHostPort(_flagPortForwardAddress, s.Int32(localPort)))
+		if err != nil {
+			exit.Error(errors.WithStack(err))
+		}
+		defer listener.Close()
+
+		fmt.Printf("forwarding %s to port %d of %s (press ctrl+c to stop)\n", listener.Addr().String(), remotePort, apiName)
+
+		operatorConfig := MustGetOperatorConfig(env.Name)
+
+		// once a connection has been forwarded, subsequent connections are forwarded to the same replica
+		var replicaMux sync.Mutex
+		onConnect := func(replica string) {
+			replicaMux.Lock()
+			defer replicaMux.Unlock()
+			if options.Replica == "" && replica != "" {
+				options.Replica = replica
+				fmt.Printf("forwarding connections to replica %s\n", replica)
+			}
+		}
+
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				exit.Error(errors.WithStack(err))
+			}
+
+			replicaMux.Lock()
+			connOptions := options
+			replicaMux.Unlock()
+
+			routines.RunWithPanicHandler(func() {
+				if err := cluster.PortForward(operatorConfig, apiName, connOptions, conn, onConnect); err != nil {
+					errors.PrintError(err)
+				}
+			}, false)
+		}
+	},
+}
+
+// parsePortMapping parses REMOTE_PORT or LOCAL_PORT:REMOTE_PORT
+func parsePortMapping(portMapping string) (int32, int32, error) {
+	localPortStr, remotePortStr := portMapping, portMapping
+	if split := strings.Split(portMapping, ":"); len(split) == 2 {
+		localPortStr, remotePortStr = split[0], split[1]
+	}
+
+	localPort, ok := s.ParseInt32(localPortStr)
+	if !ok || localPort < 1 || localPort > 65535 {
+		return 0, 0, ErrorInvalidPortMapping(portMapping)
+	}
+	remotePort, ok := s.ParseInt32(remotePortStr)
+	if !ok || remotePort < 1 || remotePort > 65535 {
+		return 0, 0, ErrorInvalidPortMapping(portMapping)
+	}
+	return localPort, remotePort, nil
+}
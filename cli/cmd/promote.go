@@ -0,0 +1,89 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cortexlabs/cortex/cli/cluster"
+	"github.com/cortexlabs/cortex/cli/types/flags"
+	"github.com/cortexlabs/cortex/pkg/lib/exit"
+	libjson "github.com/cortexlabs/cortex/pkg/lib/json"
+	"github.com/cortexlabs/cortex/pkg/lib/pointer"
+	"github.com/cortexlabs/cortex/pkg/lib/print"
+	"github.com/cortexlabs/cortex/pkg/lib/telemetry"
+	"github.com/spf13/cobra"
+)
+
+var (
+	_flagPromoteEnv    string
+	_flagPromoteWeight int32
+)
+
+func promoteInit() {
+	_promoteCmd.Flags().SortFlags = false
+	_promoteCmd.Flags().StringVarP(&_flagPromoteEnv, "env", "e", "", "environment to use")
+	_promoteCmd.Flags().Int32VarP(&_flagPromoteWeight, "weight", "w", 0, "shift the specified percentage of traffic to the canary instead of rolling it out (0-100)")
+	_promoteCmd.Flags().VarP(&_flagOutput, "output", "o", fmt.Sprintf("output format: one of %s", strings.Join(flags.OutputTypeStringsExcluding(flags.YAMLOutputType), "|")))
+}
+
+var _promoteCmd = &cobra.Command{
+	Use:   "promote API_NAME",
+	Short: "roll out an api's canary, or shift traffic to it",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		envName, err := getEnvFromFlag(_flagPromoteEnv)
+		if err != nil {
+			telemetry.Event("cli.promote")
+			exit.Error(err)
+		}
+
+		env, err := ReadOrConfigureEnv(envName)
+		if err != nil {
+			telemetry.Event("cli.promote")
+			exit.Error(err)
+		}
+		telemetry.Event("cli.promote", map[string]interface{}{"env_name": env.Name})
+
+		err = printEnvIfNotSpecified(env.Name, cmd)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		var weight *int32
+		if cmd.Flags().Changed("weight") {
+			weight = pointer.Int32(_flagPromoteWeight)
+		}
+
+		promoteResponse, err := cluster.Promote(MustGetOperatorConfig(env.Name), args[0], weight)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		if _flagOutput == flags.JSONOutputType {
+			bytes, err := libjson.Marshal(promoteResponse)
+			if err != nil {
+				exit.Error(err)
+			}
+			fmt.Print(string(bytes))
+			return
+		}
+
+		print.BoldFirstLine(promoteResponse.Message)
+	},
+}
@@ -0,0 +1,180 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cortexlabs/cortex/cli/cluster"
+	"github.com/cortexlabs/cortex/cli/types/cliconfig"
+	"github.com/cortexlabs/cortex/cli/types/flags"
+	"github.com/cortexlabs/cortex/pkg/lib/exit"
+	libjson "github.com/cortexlabs/cortex/pkg/lib/json"
+	"github.com/cortexlabs/cortex/pkg/lib/print"
+	"github.com/cortexlabs/cortex/pkg/lib/table"
+	"github.com/cortexlabs/cortex/pkg/lib/telemetry"
+	libtime "github.com/cortexlabs/cortex/pkg/lib/time"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/spf13/cobra"
+)
+
+var (
+	_flagQueueEnv         string
+	_flagQueueMoveToFront bool
+)
+
+func queueInit() {
+	_queueCmd.Flags().SortFlags = false
+	_queueCmd.Flags().StringVarP(&_flagQueueEnv, "env", "e", "", "environment to use")
+	_queueCmd.Flags().VarP(&_flagOutput, "output", "o", fmt.Sprintf("output format: one of %s", strings.Join(flags.OutputTypeStringsExcluding(flags.YAMLOutputType), "|")))
+
+	_queueMoveCmd.Flags().SortFlags = false
+	_queueMoveCmd.Flags().StringVarP(&_flagQueueEnv, "env", "e", "", "environment to use")
+	_queueMoveCmd.Flags().BoolVar(&_flagQueueMoveToFront, "to-front", false, "move the job ahead of all of the api's other queued jobs")
+	_queueMoveCmd.Flags().VarP(&_flagOutput, "output", "o", fmt.Sprintf("output format: one of %s", strings.Join(flags.OutputTypeStringsExcluding(flags.YAMLOutputType), "|")))
+	_queueCmd.AddCommand(_queueMoveCmd)
+}
+
+var _queueCmd = &cobra.Command{
+	Use:   "queue API_NAME",
+	Short: "list the jobs of a batch or task api which have been submitted but not yet started",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		env := queueEnvOrExit("cli.queue")
+
+		err := printEnvIfNotSpecified(env.Name, cmd)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		queuedJobs, err := cluster.GetJobQueue(MustGetOperatorConfig(env.Name), args[0])
+		if err != nil {
+			exit.Error(err)
+		}
+
+		printJobQueueOrExit(queuedJobs, args[0])
+	},
+}
+
+var _queueMoveCmd = &cobra.Command{
+	Use:   "move API_NAME JOB_ID",
+	Short: "change the position of a queued job",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		env := queueEnvOrExit("cli.queue.move")
+
+		err := printEnvIfNotSpecified(env.Name, cmd)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		if !_flagQueueMoveToFront {
+			exit.Error(ErrorSpecifyAtLeastOneFlag("--to-front"))
+		}
+
+		queuedJobs, err := cluster.MoveJobToFrontOfQueue(MustGetOperatorConfig(env.Name), args[0], args[1])
+		if err != nil {
+			exit.Error(err)
+		}
+
+		if _flagOutput != flags.JSONOutputType {
+			print.BoldFirstLine(fmt.Sprintf("moved job %s to the front of the queue\n", args[1]))
+		}
+		printJobQueueOrExit(queuedJobs, args[0])
+	},
+}
+
+func queueEnvOrExit(eventName string) cliconfig.Environment {
+	envName, err := getEnvFromFlag(_flagQueueEnv)
+	if err != nil {
+		telemetry.Event(eventName)
+		exit.Error(err)
+	}
+
+	env, err := ReadOrConfigureEnv(envName)
+	if err != nil {
+		telemetry.Event(eventName)
+		exit.Error(err)
+	}
+	telemetry.Event(eventName, map[string]interface{}{"env_name": env.Name})
+
+	return env
+}
+
+func printJobQueueOrExit(queuedJobs []schema.QueuedJob, apiName string) {
+	if _flagOutput == flags.JSONOutputType {
+		bytes, err := libjson.Marshal(queuedJobs)
+		if err != nil {
+			exit.Error(err)
+		}
+		fmt.Print(string(bytes))
+		return
+	}
+
+	if len(queuedJobs) == 0 {
+		fmt.Printf("%s has no queued jobs\n", apiName)
+		return
+	}
+
+	t := jobQueueTable(queuedJobs)
+	fmt.Print(t.MustFormat())
+}
+
+func jobQueueTable(queuedJobs []schema.QueuedJob) table.Table {
+	rows := make([][]interface{}, 0, len(queuedJobs))
+	for _, queuedJob := range queuedJobs {
+		dependencies := "-"
+		if len(queuedJob.Dependencies) > 0 {
+			dependencies = jobDependenciesStr(queuedJob.Dependencies)
+		}
+
+		submittedTime := queuedJob.SubmittedTime
+		rows = append(rows, []interface{}{
+			queuedJob.Position,
+			queuedJob.ID,
+			queuedJob.Status.Message(),
+			libtime.SinceStr(&submittedTime),
+			dependencies,
+			estimatedStartStr(queuedJob.EstimatedStartTime),
+		})
+	}
+
+	return table.Table{
+		Headers: []table.Header{
+			{Title: "position"},
+			{Title: "job id"},
+			{Title: "status"},
+			{Title: "submitted"},
+			{Title: "depends on"},
+			{Title: "estimated start"},
+		},
+		Rows: rows,
+	}
+}
+
+func estimatedStartStr(estimatedStartTime *time.Time) string {
+	if estimatedStartTime == nil {
+		return "-"
+	}
+	now := time.Now()
+	if !estimatedStartTime.After(now) {
+		return "now"
+	}
+	return "in " + libtime.DifferenceStr(&now, estimatedStartTime)
+}
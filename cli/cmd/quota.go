@@ -0,0 +1,128 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cortexlabs/cortex/cli/cluster"
+	"github.com/cortexlabs/cortex/cli/types/flags"
+	"github.com/cortexlabs/cortex/pkg/lib/exit"
+	libjson "github.com/cortexlabs/cortex/pkg/lib/json"
+	"github.com/cortexlabs/cortex/pkg/lib/pointer"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	"github.com/cortexlabs/cortex/pkg/lib/table"
+	"github.com/cortexlabs/cortex/pkg/lib/telemetry"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types/clusterconfig"
+	"github.com/spf13/cobra"
+)
+
+var (
+	_flagQuotaEnv string
+)
+
+func quotaInit() {
+	_quotaCmd.Flags().SortFlags = false
+	_quotaCmd.Flags().StringVarP(&_flagQuotaEnv, "env", "e", "", "environment to use")
+	_quotaCmd.Flags().VarP(&_flagOutput, "output", "o", fmt.Sprintf("output format: one of %s", strings.Join(flags.OutputTypeStringsExcluding(flags.YAMLOutputType), "|")))
+}
+
+var _quotaCmd = &cobra.Command{
+	Use:   "quota",
+	Short: "show the current usage of the cluster's quotas",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		envName, err := getEnvFromFlag(_flagQuotaEnv)
+		if err != nil {
+			telemetry.Event("cli.quota")
+			exit.Error(err)
+		}
+
+		env, err := ReadOrConfigureEnv(envName)
+		if err != nil {
+			telemetry.Event("cli.quota")
+			exit.Error(err)
+		}
+		telemetry.Event("cli.quota", map[string]interface{}{"env_name": env.Name})
+
+		err = printEnvIfNotSpecified(env.Name, cmd)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		quotaUsages, err := cluster.GetQuotas(MustGetOperatorConfig(env.Name))
+		if err != nil {
+			exit.Error(err)
+		}
+
+		if _flagOutput == flags.JSONOutputType {
+			bytes, err := libjson.Marshal(quotaUsages)
+			if err != nil {
+				exit.Error(err)
+			}
+			fmt.Print(string(bytes))
+			return
+		}
+
+		if len(quotaUsages) == 0 {
+			fmt.Printf("no quotas are configured (quotas can be added to the %s section of your cluster configuration file)\n", clusterconfig.QuotasKey)
+			return
+		}
+
+		fmt.Print(quotaUsagesStr(quotaUsages))
+	},
+}
+
+func quotaUsagesStr(quotaUsages []schema.QuotaUsage) string {
+	var rows [][]interface{}
+	for _, quotaUsage := range quotaUsages {
+		selector := quotaUsage.Selector
+		if selector == "" {
+			selector = "*"
+		}
+
+		appendRow := func(resource string, usage int64, limit *int64) {
+			if limit == nil {
+				return
+			}
+			usageStr := s.Int64(usage)
+			if usage > *limit {
+				usageStr += " (exceeded)"
+			}
+			rows = append(rows, []interface{}{quotaUsage.Name, selector, resource, usageStr, *limit})
+		}
+
+		appendRow(clusterconfig.MaxReplicasKey, quotaUsage.Replicas, quotaUsage.MaxReplicas)
+		appendRow(clusterconfig.MaxGPUsKey, quotaUsage.GPUs, quotaUsage.MaxGPUs)
+		appendRow(clusterconfig.MaxConcurrentJobsKey, quotaUsage.ConcurrentJobs, quotaUsage.MaxConcurrentJobs)
+	}
+
+	t := table.Table{
+		Headers: []table.Header{
+			{Title: "quota"},
+			{Title: "selector"},
+			{Title: "limit type"},
+			{Title: "usage"},
+			{Title: "limit"},
+		},
+		Rows: rows,
+	}
+
+	return t.MustFormat(&table.Opts{Sort: pointer.Bool(false)})
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cortexlabs/cortex/cli/cluster"
+	"github.com/cortexlabs/cortex/cli/types/flags"
+	"github.com/cortexlabs/cortex/pkg/lib/exit"
+	libjson "github.com/cortexlabs/cortex/pkg/lib/json"
+	"github.com/cortexlabs/cortex/pkg/lib/telemetry"
+	"github.com/spf13/cobra"
+)
+
+var (
+	_flagRerunEnv        string
+	_flagRerunOnlyFailed bool
+)
+
+func rerunInit() {
+	_rerunCmd.Flags().SortFlags = false
+	_rerunCmd.Flags().StringVarP(&_flagRerunEnv, "env", "e", "", "environment to use")
+	_rerunCmd.Flags().BoolVar(&_flagRerunOnlyFailed, "only-failed", false, "only re-submit the batches which failed in the job (required)")
+	_rerunCmd.Flags().VarP(&_flagOutput, "output", "o", fmt.Sprintf("output format: one of %s", strings.Join(flags.OutputTypeStringsExcluding(flags.YAMLOutputType), "|")))
+}
+
+var _rerunCmd = &cobra.Command{
+	Use:   "rerun API_NAME JOB_ID",
+	Short: "re-submit the failed batches of a batch job",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		envName, err := getEnvFromFlag(_flagRerunEnv)
+		if err != nil {
+			telemetry.Event("cli.rerun")
+			exit.Error(err)
+		}
+
+		env, err := ReadOrConfigureEnv(envName)
+		if err != nil {
+			telemetry.Event("cli.rerun")
+			exit.Error(err)
+		}
+		telemetry.Event("cli.rerun", map[string]interface{}{"env_name": env.Name})
+
+		// the original job submission isn't retained once it has been enqueued, so only failed batches can be re-run
+		if !_flagRerunOnlyFailed {
+			exit.Error(ErrorRerunRequiresOnlyFailed())
+		}
+
+		err = printEnvIfNotSpecified(env.Name, cmd)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		apiName := args[0]
+		jobID := args[1]
+
+		batchJob, err := cluster.RerunFailedBatches(MustGetOperatorConfig(env.Name), apiName, jobID)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		if _flagOutput == flags.JSONOutputType {
+			bytes, err := libjson.Marshal(batchJob)
+			if err != nil {
+				exit.Error(err)
+			}
+			fmt.Print(string(bytes))
+			return
+		}
+
+		fmt.Printf("submitted job %s to re-run the failed batches of job %s\n\nrun `cortex get %s %s` to check its status\n", batchJob.ID, jobID, apiName, batchJob.ID)
+	},
+}
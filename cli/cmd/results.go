@@ -0,0 +1,181 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/cortexlabs/cortex/cli/cluster"
+	"github.com/cortexlabs/cortex/cli/types/flags"
+	"github.com/cortexlabs/cortex/pkg/lib/aws"
+	"github.com/cortexlabs/cortex/pkg/lib/console"
+	"github.com/cortexlabs/cortex/pkg/lib/exit"
+	"github.com/cortexlabs/cortex/pkg/lib/files"
+	libjson "github.com/cortexlabs/cortex/pkg/lib/json"
+	libmath "github.com/cortexlabs/cortex/pkg/lib/math"
+	"github.com/cortexlabs/cortex/pkg/lib/parallel"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	"github.com/cortexlabs/cortex/pkg/lib/table"
+	"github.com/cortexlabs/cortex/pkg/lib/telemetry"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/spf13/cobra"
+)
+
+const (
+	_resultsDownloadConcurrency = 8
+)
+
+var (
+	_flagResultsEnv      string
+	_flagResultsDownload string
+)
+
+func resultsInit() {
+	_resultsCmd.Flags().SortFlags = false
+	_resultsCmd.Flags().StringVarP(&_flagResultsEnv, "env", "e", "", "environment to use")
+	_resultsCmd.Flags().StringVar(&_flagResultsDownload, "download", "", "local directory to which the job's results are downloaded (using your local aws credentials)")
+	_resultsCmd.Flags().VarP(&_flagOutput, "output", "o", fmt.Sprintf("output format: one of %s", strings.Join(flags.OutputTypeStringsExcluding(flags.YAMLOutputType), "|")))
+}
+
+var _resultsCmd = &cobra.Command{
+	Use:   "results API_NAME JOB_ID",
+	Short: "list (and optionally download) the results which a batch job wrote to its output path",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		envName, err := getEnvFromFlag(_flagResultsEnv)
+		if err != nil {
+			telemetry.Event("cli.results")
+			exit.Error(err)
+		}
+
+		env, err := ReadOrConfigureEnv(envName)
+		if err != nil {
+			telemetry.Event("cli.results")
+			exit.Error(err)
+		}
+		telemetry.Event("cli.results", map[string]interface{}{"env_name": env.Name})
+
+		err = printEnvIfNotSpecified(env.Name, cmd)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		apiName := args[0]
+		jobID := args[1]
+
+		resultsRes, err := cluster.GetBatchJobResults(MustGetOperatorConfig(env.Name), apiName, jobID)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		if _flagResultsDownload != "" {
+			if err := downloadJobResults(resultsRes, _flagResultsDownload); err != nil {
+				exit.Error(err)
+			}
+			fmt.Printf("downloaded %d %s to %s\n", len(resultsRes.Objects), s.PluralS("result", len(resultsRes.Objects)), _flagResultsDownload)
+			return
+		}
+
+		if _flagOutput == flags.JSONOutputType {
+			bytes, err := libjson.Marshal(resultsRes)
+			if err != nil {
+				exit.Error(err)
+			}
+			fmt.Print(string(bytes))
+			return
+		}
+
+		fmt.Print(jobResultsStr(resultsRes))
+	},
+}
+
+func jobResultsStr(resultsRes schema.BatchJobResultsResponse) string {
+	if len(resultsRes.Objects) == 0 {
+		return fmt.Sprintf("no results have been written to %s\n", resultsRes.OutputPrefix)
+	}
+
+	rows := make([][]interface{}, 0, len(resultsRes.Objects))
+	for _, object := range resultsRes.Objects {
+		rows = append(rows, []interface{}{
+			object.Key,
+			s.Int64(object.Size),
+			object.LastModified.Format(_timeFormat),
+		})
+	}
+
+	t := table.Table{
+		Headers: []table.Header{
+			{Title: "key"},
+			{Title: "size (bytes)"},
+			{Title: "last modified"},
+		},
+		Rows: rows,
+	}
+
+	out := console.Bold("output prefix: ") + resultsRes.OutputPrefix + "\n\n"
+	out += t.MustFormat()
+	return out
+}
+
+// downloadJobResults downloads the job's results with several concurrent s3 downloaders
+func downloadJobResults(resultsRes schema.BatchJobResultsResponse, localDir string) error {
+	bucket, prefix, err := aws.SplitS3Path(resultsRes.OutputPrefix)
+	if err != nil {
+		return err
+	}
+
+	region, err := aws.GetBucketRegion(bucket)
+	if err != nil {
+		return err
+	}
+
+	awsClient, err := newAWSClient(region, false)
+	if err != nil {
+		return err
+	}
+
+	if _, err := files.CreateDirIfMissing(localDir); err != nil {
+		return err
+	}
+
+	objects := make(chan schema.BatchJobResult, len(resultsRes.Objects))
+	for _, object := range resultsRes.Objects {
+		objects <- object
+	}
+	close(objects)
+
+	numWorkers := libmath.MaxInt(libmath.MinInt(_resultsDownloadConcurrency, len(resultsRes.Objects)), 1)
+	fns := make([]func() error, numWorkers)
+	for i := range fns {
+		fns[i] = func() error {
+			for object := range objects {
+				localPath := filepath.Join(localDir, filepath.FromSlash(object.Key))
+				if _, err := files.CreateDirIfMissing(filepath.Dir(localPath)); err != nil {
+					return err
+				}
+				if err := awsClient.DownloadFileFromS3(bucket, prefix+object.Key, localPath); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}
+
+	return parallel.RunFirstErr(fns[0], fns[1:]...)
+}
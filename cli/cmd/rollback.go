@@ -0,0 +1,85 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cortexlabs/cortex/cli/cluster"
+	"github.com/cortexlabs/cortex/cli/types/flags"
+	"github.com/cortexlabs/cortex/pkg/lib/exit"
+	libjson "github.com/cortexlabs/cortex/pkg/lib/json"
+	"github.com/cortexlabs/cortex/pkg/lib/print"
+	"github.com/cortexlabs/cortex/pkg/lib/telemetry"
+	"github.com/spf13/cobra"
+)
+
+var (
+	_flagRollbackEnv       string
+	_flagRollbackToVersion string
+	_flagRollbackForce     bool
+)
+
+func rollbackInit() {
+	_rollbackCmd.Flags().SortFlags = false
+	_rollbackCmd.Flags().StringVarP(&_flagRollbackEnv, "env", "e", "", "environment to use")
+	_rollbackCmd.Flags().StringVar(&_flagRollbackToVersion, "to-version", "", "version number (as listed by cortex get API_NAME) or api id to redeploy (default: the most recent version with a different configuration)")
+	_rollbackCmd.Flags().BoolVarP(&_flagRollbackForce, "force", "f", false, "override the in-progress api update")
+	_rollbackCmd.Flags().VarP(&_flagOutput, "output", "o", fmt.Sprintf("output format: one of %s", strings.Join(flags.OutputTypeStringsExcluding(flags.YAMLOutputType), "|")))
+}
+
+var _rollbackCmd = &cobra.Command{
+	Use:   "rollback API_NAME",
+	Short: "remove an api's canary, or redeploy a previous version of an api",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		envName, err := getEnvFromFlag(_flagRollbackEnv)
+		if err != nil {
+			telemetry.Event("cli.rollback")
+			exit.Error(err)
+		}
+
+		env, err := ReadOrConfigureEnv(envName)
+		if err != nil {
+			telemetry.Event("cli.rollback")
+			exit.Error(err)
+		}
+		telemetry.Event("cli.rollback", map[string]interface{}{"env_name": env.Name})
+
+		err = printEnvIfNotSpecified(env.Name, cmd)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		rollbackResponse, err := cluster.Rollback(MustGetOperatorConfig(env.Name), args[0], _flagRollbackToVersion, _flagRollbackForce)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		if _flagOutput == flags.JSONOutputType {
+			bytes, err := libjson.Marshal(rollbackResponse)
+			if err != nil {
+				exit.Error(err)
+			}
+			fmt.Print(string(bytes))
+			return
+		}
+
+		print.BoldFirstLine(rollbackResponse.Message)
+	},
+}
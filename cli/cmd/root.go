@@ -23,6 +23,7 @@ import (
 	"strings"
 
 	"github.com/cortexlabs/cortex/cli/types/flags"
+	awslib "github.com/cortexlabs/cortex/pkg/lib/aws"
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
 	"github.com/cortexlabs/cortex/pkg/lib/exit"
 	"github.com/cortexlabs/cortex/pkg/lib/files"
@@ -86,6 +87,13 @@ func init() {
 		exit.Error(err)
 	}
 
+	// ~/.cortex/cache/availability-zones/
+	awslib.AvailabilityZonesCacheDir = filepath.Join(_localDir, "cache", "availability-zones")
+
+	// ~/.cortex/cache/mfa-sessions/
+	awslib.MFASessionCacheDir = filepath.Join(_localDir, "cache", "mfa-sessions")
+	awslib.MFATokenProvider = promptMFAToken
+
 	_cliConfigPath = filepath.Join(_localDir, "cli.yaml")
 	_clientIDPath = filepath.Join(_localDir, "client-id.txt")
 	_emailPath = filepath.Join(_localDir, "email.txt")
@@ -109,16 +117,38 @@ func init() {
 		initTelemetry()
 	}
 
+	asyncInit()
 	clusterInit()
 	completionInit()
 	deleteInit()
 	describeInit()
 	deployInit()
+	diffInit()
+	endpointInit()
 	envInit()
+	execInit()
 	getInit()
+	gitOpsInit()
+	lintInit()
 	logsInit()
+	portForwardInit()
+	promoteInit()
+	queueInit()
+	quotaInit()
 	refreshInit()
+	rerunInit()
+	resultsInit()
+	rollbackInit()
+	scheduleInit()
+	submitInit()
+	topInit()
+	keysInit()
+	secretsInit()
+	imagesInit()
+	authInit()
+	loginInit()
 	versionInit()
+	waitInit()
 }
 
 func initTelemetry() {
@@ -154,15 +184,38 @@ func Execute() {
 	cobra.EnableCommandSorting = false
 
 	_rootCmd.AddCommand(_deployCmd)
+	_rootCmd.AddCommand(_diffCmd)
+	_rootCmd.AddCommand(_lintCmd)
 	_rootCmd.AddCommand(_getCmd)
 	_rootCmd.AddCommand(_describeCmd)
 	_rootCmd.AddCommand(_logsCmd)
+	_rootCmd.AddCommand(_execCmd)
+	_rootCmd.AddCommand(_portForwardCmd)
 	_rootCmd.AddCommand(_refreshCmd)
+	_rootCmd.AddCommand(_promoteCmd)
+	_rootCmd.AddCommand(_rollbackCmd)
+	_rootCmd.AddCommand(_asyncCmd)
+	_rootCmd.AddCommand(_submitCmd)
+	_rootCmd.AddCommand(_rerunCmd)
+	_rootCmd.AddCommand(_resultsCmd)
+	_rootCmd.AddCommand(_scheduleCmd)
+	_rootCmd.AddCommand(_queueCmd)
+	_rootCmd.AddCommand(_waitCmd)
 	_rootCmd.AddCommand(_deleteCmd)
+	_rootCmd.AddCommand(_quotaCmd)
+	_rootCmd.AddCommand(_gitOpsCmd)
+	_rootCmd.AddCommand(_topCmd)
+	_rootCmd.AddCommand(_keysCmd)
+	_rootCmd.AddCommand(_secretsCmd)
+	_rootCmd.AddCommand(_imagesCmd)
+	_rootCmd.AddCommand(_authCmd)
+	_rootCmd.AddCommand(_endpointCmd)
 
 	_rootCmd.AddCommand(_clusterCmd)
 
 	_rootCmd.AddCommand(_envCmd)
+	_rootCmd.AddCommand(_loginCmd)
+	_rootCmd.AddCommand(_logoutCmd)
 	_rootCmd.AddCommand(_versionCmd)
 	_rootCmd.AddCommand(_completionCmd)
 
@@ -0,0 +1,247 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cortexlabs/cortex/cli/cluster"
+	"github.com/cortexlabs/cortex/cli/types/cliconfig"
+	"github.com/cortexlabs/cortex/cli/types/flags"
+	"github.com/cortexlabs/cortex/pkg/lib/exit"
+	libjson "github.com/cortexlabs/cortex/pkg/lib/json"
+	"github.com/cortexlabs/cortex/pkg/lib/print"
+	"github.com/cortexlabs/cortex/pkg/lib/prompt"
+	"github.com/cortexlabs/cortex/pkg/lib/table"
+	"github.com/cortexlabs/cortex/pkg/lib/telemetry"
+	libtime "github.com/cortexlabs/cortex/pkg/lib/time"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
+	"github.com/spf13/cobra"
+)
+
+var (
+	_flagScheduleEnv         string
+	_flagScheduleDeleteForce bool
+)
+
+func scheduleInit() {
+	_scheduleListCmd.Flags().SortFlags = false
+	_scheduleListCmd.Flags().StringVarP(&_flagScheduleEnv, "env", "e", "", "environment to use")
+	_scheduleListCmd.Flags().VarP(&_flagOutput, "output", "o", fmt.Sprintf("output format: one of %s", strings.Join(flags.OutputTypeStringsExcluding(flags.YAMLOutputType), "|")))
+	_scheduleCmd.AddCommand(_scheduleListCmd)
+
+	_schedulePauseCmd.Flags().SortFlags = false
+	_schedulePauseCmd.Flags().StringVarP(&_flagScheduleEnv, "env", "e", "", "environment to use")
+	_schedulePauseCmd.Flags().VarP(&_flagOutput, "output", "o", fmt.Sprintf("output format: one of %s", strings.Join(flags.OutputTypeStringsExcluding(flags.YAMLOutputType), "|")))
+	_scheduleCmd.AddCommand(_schedulePauseCmd)
+
+	_scheduleResumeCmd.Flags().SortFlags = false
+	_scheduleResumeCmd.Flags().StringVarP(&_flagScheduleEnv, "env", "e", "", "environment to use")
+	_scheduleResumeCmd.Flags().VarP(&_flagOutput, "output", "o", fmt.Sprintf("output format: one of %s", strings.Join(flags.OutputTypeStringsExcluding(flags.YAMLOutputType), "|")))
+	_scheduleCmd.AddCommand(_scheduleResumeCmd)
+
+	_scheduleDeleteCmd.Flags().SortFlags = false
+	_scheduleDeleteCmd.Flags().StringVarP(&_flagScheduleEnv, "env", "e", "", "environment to use")
+	_scheduleDeleteCmd.Flags().BoolVarP(&_flagScheduleDeleteForce, "force", "f", false, "delete the schedule without confirmation")
+	_scheduleDeleteCmd.Flags().VarP(&_flagOutput, "output", "o", fmt.Sprintf("output format: one of %s", strings.Join(flags.OutputTypeStringsExcluding(flags.YAMLOutputType), "|")))
+	_scheduleCmd.AddCommand(_scheduleDeleteCmd)
+}
+
+var _scheduleCmd = &cobra.Command{
+	Use:   "schedule",
+	Short: "manage the job schedules of batch and task apis (contains subcommands)",
+}
+
+var _scheduleListCmd = &cobra.Command{
+	Use:   "list [API_NAME]",
+	Short: "list the job schedules of an api, or of all apis",
+	Args:  cobra.RangeArgs(0, 1),
+	Run: func(cmd *cobra.Command, args []string) {
+		env := scheduleEnvOrExit("cli.schedule.list")
+
+		err := printEnvIfNotSpecified(env.Name, cmd)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		var apiName string
+		if len(args) == 1 {
+			apiName = args[0]
+		}
+
+		jobSchedules, err := cluster.ListJobSchedules(MustGetOperatorConfig(env.Name), apiName)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		if _flagOutput == flags.JSONOutputType {
+			bytes, err := libjson.Marshal(jobSchedules)
+			if err != nil {
+				exit.Error(err)
+			}
+			fmt.Print(string(bytes))
+			return
+		}
+
+		if len(jobSchedules) == 0 {
+			fmt.Println("no job schedules have been created (create one with `cortex submit API_NAME --schedule CRON_SCHEDULE`, or by including the schedule field in a job submission)")
+			return
+		}
+
+		t := jobSchedulesTable(jobSchedules)
+		fmt.Print(t.MustFormat())
+	},
+}
+
+var _schedulePauseCmd = &cobra.Command{
+	Use:   "pause API_NAME SCHEDULE_ID",
+	Short: "stop submitting jobs for a job schedule",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		env := scheduleEnvOrExit("cli.schedule.pause")
+
+		err := printEnvIfNotSpecified(env.Name, cmd)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		jobSchedule, err := cluster.PauseJobSchedule(MustGetOperatorConfig(env.Name), args[0], args[1])
+		if err != nil {
+			exit.Error(err)
+		}
+
+		printJobScheduleOrExit(jobSchedule, fmt.Sprintf("paused job schedule %s (%s api)", jobSchedule.ID, jobSchedule.APIName))
+	},
+}
+
+var _scheduleResumeCmd = &cobra.Command{
+	Use:   "resume API_NAME SCHEDULE_ID",
+	Short: "resume submitting jobs for a paused job schedule (scheduled times which were missed while paused are skipped)",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		env := scheduleEnvOrExit("cli.schedule.resume")
+
+		err := printEnvIfNotSpecified(env.Name, cmd)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		jobSchedule, err := cluster.ResumeJobSchedule(MustGetOperatorConfig(env.Name), args[0], args[1])
+		if err != nil {
+			exit.Error(err)
+		}
+
+		printJobScheduleOrExit(jobSchedule, fmt.Sprintf("resumed job schedule %s (%s api)", jobSchedule.ID, jobSchedule.APIName))
+	},
+}
+
+var _scheduleDeleteCmd = &cobra.Command{
+	Use:   "delete API_NAME SCHEDULE_ID",
+	Short: "delete a job schedule (jobs which it already submitted are not affected)",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		env := scheduleEnvOrExit("cli.schedule.delete")
+
+		err := printEnvIfNotSpecified(env.Name, cmd)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		if !_flagScheduleDeleteForce {
+			prompt.YesOrExit(fmt.Sprintf("are you sure you want to delete job schedule %s of %s?", args[1], args[0]), "", "")
+		}
+
+		deleteResponse, err := cluster.DeleteJobSchedule(MustGetOperatorConfig(env.Name), args[0], args[1])
+		if err != nil {
+			exit.Error(err)
+		}
+
+		if _flagOutput == flags.JSONOutputType {
+			bytes, err := libjson.Marshal(deleteResponse)
+			if err != nil {
+				exit.Error(err)
+			}
+			fmt.Print(string(bytes))
+			return
+		}
+
+		print.BoldFirstLine(deleteResponse.Message)
+	},
+}
+
+func scheduleEnvOrExit(eventName string) cliconfig.Environment {
+	envName, err := getEnvFromFlag(_flagScheduleEnv)
+	if err != nil {
+		telemetry.Event(eventName)
+		exit.Error(err)
+	}
+
+	env, err := ReadOrConfigureEnv(envName)
+	if err != nil {
+		telemetry.Event(eventName)
+		exit.Error(err)
+	}
+	telemetry.Event(eventName, map[string]interface{}{"env_name": env.Name})
+
+	return env
+}
+
+func printJobScheduleOrExit(jobSchedule spec.JobSchedule, message string) {
+	if _flagOutput == flags.JSONOutputType {
+		bytes, err := libjson.Marshal(jobSchedule)
+		if err != nil {
+			exit.Error(err)
+		}
+		fmt.Print(string(bytes))
+		return
+	}
+
+	print.BoldFirstLine(message)
+}
+
+func jobSchedulesTable(jobSchedules []spec.JobSchedule) table.Table {
+	rows := make([][]interface{}, 0, len(jobSchedules))
+	for _, jobSchedule := range jobSchedules {
+		status := "active"
+		if jobSchedule.Paused {
+			status = "paused"
+		}
+
+		lastJob := "-"
+		if jobSchedule.LastJobID != "" {
+			lastJob = jobSchedule.LastJobID
+		} else if jobSchedule.LastError != "" {
+			lastJob = "failed to submit"
+		}
+
+		createdTime := jobSchedule.CreatedTime
+		rows = append(rows, []interface{}{jobSchedule.APIName, jobSchedule.ID, jobSchedule.Schedule, status, lastJob, libtime.SinceStr(&createdTime)})
+	}
+
+	return table.Table{
+		Headers: []table.Header{
+			{Title: "api"},
+			{Title: "schedule id"},
+			{Title: "schedule (utc)"},
+			{Title: "status"},
+			{Title: "last job"},
+			{Title: "created"},
+		},
+		Rows: rows,
+	}
+}
@@ -0,0 +1,247 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/cortexlabs/cortex/cli/cluster"
+	"github.com/cortexlabs/cortex/cli/types/cliconfig"
+	"github.com/cortexlabs/cortex/cli/types/flags"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/exit"
+	libjson "github.com/cortexlabs/cortex/pkg/lib/json"
+	"github.com/cortexlabs/cortex/pkg/lib/print"
+	"github.com/cortexlabs/cortex/pkg/lib/prompt"
+	"github.com/cortexlabs/cortex/pkg/lib/table"
+	"github.com/cortexlabs/cortex/pkg/lib/telemetry"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+var (
+	_flagSecretsEnv         string
+	_flagSecretsDeleteForce bool
+)
+
+func secretsInit() {
+	_secretsSetCmd.Flags().SortFlags = false
+	_secretsSetCmd.Flags().StringVarP(&_flagSecretsEnv, "env", "e", "", "environment to use")
+	_secretsSetCmd.Flags().VarP(&_flagOutput, "output", "o", fmt.Sprintf("output format: one of %s", strings.Join(flags.OutputTypeStringsExcluding(flags.YAMLOutputType), "|")))
+	_secretsCmd.AddCommand(_secretsSetCmd)
+
+	_secretsGetCmd.Flags().SortFlags = false
+	_secretsGetCmd.Flags().StringVarP(&_flagSecretsEnv, "env", "e", "", "environment to use")
+	_secretsGetCmd.Flags().VarP(&_flagOutput, "output", "o", fmt.Sprintf("output format: one of %s", strings.Join(flags.OutputTypeStringsExcluding(flags.YAMLOutputType), "|")))
+	_secretsCmd.AddCommand(_secretsGetCmd)
+
+	_secretsDeleteCmd.Flags().SortFlags = false
+	_secretsDeleteCmd.Flags().StringVarP(&_flagSecretsEnv, "env", "e", "", "environment to use")
+	_secretsDeleteCmd.Flags().BoolVarP(&_flagSecretsDeleteForce, "force", "f", false, "delete the secret without confirmation")
+	_secretsDeleteCmd.Flags().VarP(&_flagOutput, "output", "o", fmt.Sprintf("output format: one of %s", strings.Join(flags.OutputTypeStringsExcluding(flags.YAMLOutputType), "|")))
+	_secretsCmd.AddCommand(_secretsDeleteCmd)
+}
+
+var _secretsCmd = &cobra.Command{
+	Use:   "secrets",
+	Short: "manage secrets which apis can read as environment variables via env_from_secrets (contains subcommands)",
+}
+
+var _secretsSetCmd = &cobra.Command{
+	Use:   "set SECRET_NAME [VALUE]",
+	Short: "create or update a secret (the value is read from stdin if it isn't provided)",
+	Args:  cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		env := secretsEnvOrExit("cli.secrets.set")
+
+		err := printEnvIfNotSpecified(env.Name, cmd)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		var value string
+		if len(args) == 2 {
+			value = args[1]
+		} else {
+			value, err = readSecretValue()
+			if err != nil {
+				exit.Error(err)
+			}
+		}
+
+		setResponse, err := cluster.SetSecret(MustGetOperatorConfig(env.Name), args[0], value)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		if _flagOutput == flags.JSONOutputType {
+			bytes, err := libjson.Marshal(setResponse)
+			if err != nil {
+				exit.Error(err)
+			}
+			fmt.Print(string(bytes))
+			return
+		}
+
+		print.BoldFirstLine(setResponse.Message)
+	},
+}
+
+var _secretsGetCmd = &cobra.Command{
+	Use:   "get [SECRET_NAME]",
+	Short: "get the value of a secret, or list the secrets if no name is provided",
+	Args:  cobra.RangeArgs(0, 1),
+	Run: func(cmd *cobra.Command, args []string) {
+		env := secretsEnvOrExit("cli.secrets.get")
+
+		err := printEnvIfNotSpecified(env.Name, cmd)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		if len(args) == 1 {
+			getResponse, err := cluster.GetSecret(MustGetOperatorConfig(env.Name), args[0])
+			if err != nil {
+				exit.Error(err)
+			}
+
+			if _flagOutput == flags.JSONOutputType {
+				bytes, err := libjson.Marshal(getResponse)
+				if err != nil {
+					exit.Error(err)
+				}
+				fmt.Print(string(bytes))
+				return
+			}
+
+			fmt.Println(getResponse.Value)
+			return
+		}
+
+		secrets, err := cluster.ListSecrets(MustGetOperatorConfig(env.Name))
+		if err != nil {
+			exit.Error(err)
+		}
+
+		if _flagOutput == flags.JSONOutputType {
+			bytes, err := libjson.Marshal(secrets)
+			if err != nil {
+				exit.Error(err)
+			}
+			fmt.Print(string(bytes))
+			return
+		}
+
+		if len(secrets) == 0 {
+			fmt.Println("no secrets have been created (create one with `cortex secrets set SECRET_NAME`)")
+			return
+		}
+
+		t := secretsTable(secrets)
+		fmt.Print(t.MustFormat())
+	},
+}
+
+var _secretsDeleteCmd = &cobra.Command{
+	Use:   "delete SECRET_NAME",
+	Short: "delete a secret",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		env := secretsEnvOrExit("cli.secrets.delete")
+
+		err := printEnvIfNotSpecified(env.Name, cmd)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		if !_flagSecretsDeleteForce {
+			prompt.YesOrExit(fmt.Sprintf("are you sure you want to delete secret %s? it can't be recovered", args[0]), "", "")
+		}
+
+		deleteResponse, err := cluster.DeleteSecret(MustGetOperatorConfig(env.Name), args[0])
+		if err != nil {
+			exit.Error(err)
+		}
+
+		if _flagOutput == flags.JSONOutputType {
+			bytes, err := libjson.Marshal(deleteResponse)
+			if err != nil {
+				exit.Error(err)
+			}
+			fmt.Print(string(bytes))
+			return
+		}
+
+		print.BoldFirstLine(deleteResponse.Message)
+	},
+}
+
+// readSecretValue prompts for the value (without echoing it) when stdin is a terminal, and otherwise reads all of stdin (without its trailing newline)
+func readSecretValue() (string, error) {
+	if term.IsTerminal(int(os.Stdin.Fd())) {
+		return prompt.Prompt(&prompt.Options{
+			Prompt:     "value",
+			HideTyping: true,
+		}), nil
+	}
+
+	valueBytes, err := ioutil.ReadAll(os.Stdin)
+	if err != nil {
+		return "", errors.Wrap(err, "stdin")
+	}
+	return strings.TrimSuffix(strings.TrimSuffix(string(valueBytes), "\n"), "\r"), nil
+}
+
+func secretsEnvOrExit(eventName string) cliconfig.Environment {
+	envName, err := getEnvFromFlag(_flagSecretsEnv)
+	if err != nil {
+		telemetry.Event(eventName)
+		exit.Error(err)
+	}
+
+	env, err := ReadOrConfigureEnv(envName)
+	if err != nil {
+		telemetry.Event(eventName)
+		exit.Error(err)
+	}
+	telemetry.Event(eventName, map[string]interface{}{"env_name": env.Name})
+
+	return env
+}
+
+func secretsTable(secrets []schema.Secret) table.Table {
+	rows := make([][]interface{}, 0, len(secrets))
+	for _, secret := range secrets {
+		apis := "-"
+		if len(secret.APIs) > 0 {
+			apis = strings.Join(secret.APIs, ", ")
+		}
+		rows = append(rows, []interface{}{secret.Name, apis})
+	}
+
+	return table.Table{
+		Headers: []table.Header{
+			{Title: "name"},
+			{Title: "used by"},
+		},
+		Rows: rows,
+	}
+}
@@ -0,0 +1,184 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cortexlabs/cortex/cli/cluster"
+	"github.com/cortexlabs/cortex/cli/types/flags"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/exit"
+	"github.com/cortexlabs/cortex/pkg/lib/files"
+	libjson "github.com/cortexlabs/cortex/pkg/lib/json"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	"github.com/cortexlabs/cortex/pkg/lib/telemetry"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/spf13/cobra"
+)
+
+var (
+	_flagSubmitEnv        string
+	_flagSubmitManifest   string
+	_flagSubmitSubmission string
+	_flagSubmitBatchSize  int
+	_flagSubmitWorkers    string
+	_flagSubmitDryRun     bool
+	_flagSubmitSchedule   string
+	_flagSubmitDependsOn  []string
+)
+
+func submitInit() {
+	_submitCmd.Flags().SortFlags = false
+	_submitCmd.Flags().StringVarP(&_flagSubmitEnv, "env", "e", "", "environment to use")
+	_submitCmd.Flags().StringVarP(&_flagSubmitManifest, "manifest", "m", "", "s3 path of a manifest which lists the files to process (a text file with one s3 path per line, or the manifest.json of an s3 inventory report)")
+	_submitCmd.Flags().StringVarP(&_flagSubmitSubmission, "submission", "s", "", "path to a json file containing the job submission request")
+	_submitCmd.Flags().IntVarP(&_flagSubmitBatchSize, "batch-size", "b", 1, "the number of files per batch (when using --manifest)")
+	_submitCmd.Flags().StringVar(&_flagSubmitWorkers, "workers", "", fmt.Sprintf("the number of workers to allocate for this job, or \"%s\" to scale the workers with the job's remaining batches (overrides the value in --submission; default: 1)", schema.AutoWorkers))
+	_submitCmd.Flags().BoolVar(&_flagSubmitDryRun, "dry-run", false, "validate the job submission and list the files which would be processed without submitting the job")
+	_submitCmd.Flags().StringSliceVar(&_flagSubmitDependsOn, "depends-on", nil, "id of a job which must succeed before this job starts, or API_NAME/JOB_ID for a job of another api (can be repeated; overrides the value in --submission)")
+	_submitCmd.Flags().StringVar(&_flagSubmitSchedule, "schedule", "", "cron schedule (in utc) on which to submit the job, e.g. \"0 3 * * *\" (overrides the value in --submission)")
+	_submitCmd.Flags().VarP(&_flagOutput, "output", "o", fmt.Sprintf("output format: one of %s", strings.Join(flags.OutputTypeStringsExcluding(flags.YAMLOutputType), "|")))
+}
+
+var _submitCmd = &cobra.Command{
+	Use:   "submit API_NAME",
+	Short: "submit a batch job",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		envName, err := getEnvFromFlag(_flagSubmitEnv)
+		if err != nil {
+			telemetry.Event("cli.submit")
+			exit.Error(err)
+		}
+
+		env, err := ReadOrConfigureEnv(envName)
+		if err != nil {
+			telemetry.Event("cli.submit")
+			exit.Error(err)
+		}
+		telemetry.Event("cli.submit", map[string]interface{}{"env_name": env.Name, "manifest": _flagSubmitManifest != "", "schedule": _flagSubmitSchedule != ""})
+
+		err = printEnvIfNotSpecified(env.Name, cmd)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		submission, err := getBatchJobSubmission()
+		if err != nil {
+			exit.Error(err)
+		}
+
+		apiName := args[0]
+		operatorConfig := MustGetOperatorConfig(env.Name)
+
+		if _flagSubmitDryRun {
+			dryRunOutput, err := cluster.DryRunBatchJob(operatorConfig, apiName, submission)
+			if err != nil {
+				exit.Error(err)
+			}
+			fmt.Println(strings.TrimSpace(dryRunOutput))
+			return
+		}
+
+		if submission.Schedule != nil {
+			jobSchedule, err := cluster.ScheduleBatchJob(operatorConfig, apiName, submission)
+			if err != nil {
+				exit.Error(err)
+			}
+
+			if _flagOutput == flags.JSONOutputType {
+				bytes, err := libjson.Marshal(jobSchedule)
+				if err != nil {
+					exit.Error(err)
+				}
+				fmt.Print(string(bytes))
+				return
+			}
+
+			fmt.Printf("created job schedule %s (%s)\n\nrun `cortex schedule list %s` to see the jobs which it has submitted\n", jobSchedule.ID, jobSchedule.Schedule, apiName)
+			return
+		}
+
+		batchJob, err := cluster.SubmitBatchJob(operatorConfig, apiName, submission)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		if _flagOutput == flags.JSONOutputType {
+			bytes, err := libjson.Marshal(batchJob)
+			if err != nil {
+				exit.Error(err)
+			}
+			fmt.Print(string(bytes))
+			return
+		}
+
+		fmt.Printf("submitted job %s\n\nrun `cortex get %s %s` to check its status\n", batchJob.ID, apiName, batchJob.ID)
+	},
+}
+
+func getBatchJobSubmission() (schema.BatchJobSubmission, error) {
+	if _flagSubmitManifest == "" && _flagSubmitSubmission == "" {
+		return schema.BatchJobSubmission{}, ErrorSpecifyAtLeastOneFlag("--manifest", "--submission")
+	}
+
+	var submission schema.BatchJobSubmission
+	if _flagSubmitSubmission != "" {
+		submissionPath := files.UserRelToAbsPath(_flagSubmitSubmission)
+		submissionBytes, err := files.ReadFileBytes(submissionPath)
+		if err != nil {
+			return schema.BatchJobSubmission{}, err
+		}
+		if err := libjson.Unmarshal(submissionBytes, &submission); err != nil {
+			return schema.BatchJobSubmission{}, errors.Wrap(err, submissionPath)
+		}
+	}
+
+	if _flagSubmitManifest != "" {
+		submission.FileManifest = &schema.FileManifest{
+			S3Path:    _flagSubmitManifest,
+			BatchSize: _flagSubmitBatchSize,
+		}
+	}
+
+	if len(_flagSubmitDependsOn) > 0 {
+		submission.DependsOn = _flagSubmitDependsOn
+	}
+
+	if _flagSubmitSchedule != "" {
+		submission.Schedule = &_flagSubmitSchedule
+	}
+
+	if _flagSubmitWorkers == schema.AutoWorkers {
+		submission.Workers = 0
+		submission.AutoWorkers = true
+	} else if _flagSubmitWorkers != "" {
+		workers, ok := s.ParseInt(_flagSubmitWorkers)
+		if !ok || workers <= 0 {
+			return schema.BatchJobSubmission{}, ErrorInvalidWorkersFlag(_flagSubmitWorkers)
+		}
+		submission.Workers = workers
+		submission.AutoWorkers = false
+		submission.MaxWorkers = nil
+	} else if submission.Workers == 0 && !submission.AutoWorkers {
+		submission.Workers = 1
+	}
+
+	return submission, nil
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cortexlabs/cortex/cli/cluster"
+	"github.com/cortexlabs/cortex/cli/types/flags"
+	"github.com/cortexlabs/cortex/pkg/lib/exit"
+	libjson "github.com/cortexlabs/cortex/pkg/lib/json"
+	"github.com/cortexlabs/cortex/pkg/lib/pointer"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	"github.com/cortexlabs/cortex/pkg/lib/table"
+	"github.com/cortexlabs/cortex/pkg/lib/telemetry"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/spf13/cobra"
+)
+
+var (
+	_flagTopEnv   string
+	_flagTopWatch bool
+)
+
+func topInit() {
+	_topCmd.Flags().SortFlags = false
+	_topCmd.Flags().StringVarP(&_flagTopEnv, "env", "e", "", "environment to use")
+	_topCmd.Flags().BoolVarP(&_flagTopWatch, "watch", "w", true, "re-run the command every 2 seconds (use --watch=false to print once)")
+	_topCmd.Flags().VarP(&_flagOutput, "output", "o", fmt.Sprintf("output format: one of %s", strings.Join(flags.OutputTypeStringsExcluding(flags.YAMLOutputType), "|")))
+}
+
+var _topCmd = &cobra.Command{
+	Use:   "top [API_NAME]",
+	Short: "show the cpu, memory, and gpu usage of each api replica",
+	Args:  cobra.RangeArgs(0, 1),
+	Run: func(cmd *cobra.Command, args []string) {
+		var apiName string
+		if len(args) == 1 {
+			apiName = args[0]
+		}
+
+		envName, err := getEnvFromFlag(_flagTopEnv)
+		if err != nil {
+			telemetry.Event("cli.top")
+			exit.Error(err)
+		}
+
+		env, err := ReadOrConfigureEnv(envName)
+		if err != nil {
+			telemetry.Event("cli.top")
+			exit.Error(err)
+		}
+		telemetry.Event("cli.top", map[string]interface{}{"env_name": env.Name})
+
+		if _flagOutput == flags.JSONOutputType {
+			replicas, err := cluster.Top(MustGetOperatorConfig(env.Name), apiName)
+			if err != nil {
+				exit.Error(err)
+			}
+			bytes, err := libjson.Marshal(replicas)
+			if err != nil {
+				exit.Error(err)
+			}
+			fmt.Print(string(bytes))
+			return
+		}
+
+		rerun(_flagTopWatch, func() (string, error) {
+			out, err := envStringIfNotSpecified(envName, cmd)
+			if err != nil {
+				return "", err
+			}
+
+			replicas, err := cluster.Top(MustGetOperatorConfig(env.Name), apiName)
+			if err != nil {
+				return "", err
+			}
+
+			if len(replicas) == 0 {
+				if apiName != "" {
+					return out + fmt.Sprintf("%s has no running replicas\n", apiName), nil
+				}
+				return out + "no replicas are running\n", nil
+			}
+
+			return out + replicaUtilizationsStr(replicas), nil
+		})
+	},
+}
+
+// replicas are expected to be sorted by api name and then by nodegroup, so that they can be grouped
+func replicaUtilizationsStr(replicas []schema.ReplicaUtilization) string {
+	hasGPUs := false
+	for _, replica := range replicas {
+		if replica.GPUs > 0 {
+			hasGPUs = true
+			break
+		}
+	}
+
+	var rows [][]interface{}
+	for i, replica := range replicas {
+		apiName := replica.APIName
+		nodeGroupName := replica.NodeGroupName
+		if i > 0 && replicas[i-1].APIName == replica.APIName {
+			apiName = ""
+			if replicas[i-1].NodeGroupName == replica.NodeGroupName {
+				nodeGroupName = ""
+			}
+		}
+
+		cpu, mem := "-", "-"
+		if replica.CPU != nil {
+			cpu = replica.CPU.MilliString()
+		}
+		if replica.Mem != nil {
+			mem = replica.Mem.ToMiRoundedStr()
+		}
+		cpu += " / " + replica.CPURequested.MilliString()
+		mem += " / " + replica.MemRequested.ToMiRoundedStr()
+
+		gpu, gpuMem := "-", "-"
+		if replica.GPUs > 0 {
+			if replica.GPUUtilization != nil {
+				gpu = s.Round(*replica.GPUUtilization, 0, 0) + "%"
+			}
+			if replica.GPUMemUsed != nil {
+				gpuMem = s.Round(*replica.GPUMemUsed, 0, 0) + "Mi"
+			}
+			gpu += fmt.Sprintf(" (%d %s)", replica.GPUs, s.PluralS("gpu", replica.GPUs))
+		}
+
+		rows = append(rows, []interface{}{apiName, nodeGroupName, replica.PodName, cpu, mem, gpu, gpuMem})
+	}
+
+	t := table.Table{
+		Headers: []table.Header{
+			{Title: "api"},
+			{Title: "nodegroup"},
+			{Title: "replica"},
+			{Title: "cpu (used / requested)"},
+			{Title: "memory (used / requested)"},
+			{Title: "gpu utilization", Hidden: !hasGPUs},
+			{Title: "gpu memory", Hidden: !hasGPUs},
+		},
+		Rows: rows,
+	}
+
+	return t.MustFormat(&table.Opts{Sort: pointer.Bool(false)})
+}
@@ -0,0 +1,185 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/cortexlabs/cortex/cli/cluster"
+	"github.com/cortexlabs/cortex/cli/lib/routines"
+	"github.com/cortexlabs/cortex/pkg/lib/exit"
+	"github.com/cortexlabs/cortex/pkg/lib/telemetry"
+	"github.com/cortexlabs/cortex/pkg/types/status"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+	"github.com/spf13/cobra"
+)
+
+const (
+	_jobWaitPollInterval    = 5 * time.Second
+	_jobWaitLogsGracePeriod = 10 * time.Second
+	_jobWaitMaxPollFailures = 5
+)
+
+var (
+	_flagWaitEnv     string
+	_flagWaitTimeout time.Duration
+	_flagWaitLogs    bool
+)
+
+func waitInit() {
+	_waitCmd.Flags().SortFlags = false
+	_waitCmd.Flags().StringVarP(&_flagWaitEnv, "env", "e", "", "environment to use")
+	_waitCmd.Flags().DurationVar(&_flagWaitTimeout, "timeout", 0, "maximum amount of time to wait for the job to complete (e.g. 30m or 2h; default: no timeout)")
+	_waitCmd.Flags().BoolVar(&_flagWaitLogs, "logs", false, "stream the logs of the job's workers while waiting")
+}
+
+var _waitCmd = &cobra.Command{
+	Use:   "wait API_NAME JOB_ID",
+	Short: "wait for a job to complete",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		envName, err := getEnvFromFlag(_flagWaitEnv)
+		if err != nil {
+			telemetry.Event("cli.wait")
+			exit.Error(err)
+		}
+
+		env, err := ReadOrConfigureEnv(envName)
+		if err != nil {
+			telemetry.Event("cli.wait")
+			exit.Error(err)
+		}
+		telemetry.Event("cli.wait", map[string]interface{}{"env_name": env.Name, "timeout": _flagWaitTimeout != 0, "logs": _flagWaitLogs})
+
+		err = printEnvIfNotSpecified(env.Name, cmd)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		operatorConfig := MustGetOperatorConfig(env.Name)
+		apiName := args[0]
+		jobID := args[1]
+
+		if _flagWaitTimeout < 0 {
+			exit.Error(ErrorInvalidJobWaitTimeout(_flagWaitTimeout))
+		}
+
+		apisRes, err := cluster.GetAPI(operatorConfig, apiName)
+		if err != nil {
+			exit.Error(err)
+		}
+		kind := apisRes[0].Metadata.Kind
+		if kind != userconfig.BatchAPIKind && kind != userconfig.TaskAPIKind {
+			exit.Error(ErrorJobWaitNotSupportedForKind(apiName, kind))
+		}
+
+		interrupt := make(chan os.Signal, 1)
+		signal.Notify(interrupt, os.Interrupt)
+		routines.RunWithPanicHandler(func() {
+			<-interrupt
+			exit.Error(ErrorJobWaitInterrupted(apiName, jobID))
+		}, false)
+
+		jobStatus, err := waitForJob(operatorConfig, kind, apiName, jobID)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		if jobStatus != status.JobSucceeded {
+			exit.Error(ErrorJobDidNotSucceed(jobID, jobStatus))
+		}
+
+		fmt.Printf("job %s %s\n", jobID, jobStatus.Message())
+	},
+}
+
+// waitForJob polls the job's status until it has completed, and returns its final status
+func waitForJob(operatorConfig cluster.OperatorConfig, kind userconfig.Kind, apiName string, jobID string) (status.JobCode, error) {
+	var deadline <-chan time.Time
+	if _flagWaitTimeout > 0 {
+		deadline = time.After(_flagWaitTimeout)
+	}
+
+	var logsDone chan struct{}
+	lastStatus := status.JobUnknown
+	numPollFailures := 0
+
+	for {
+		jobStatus, err := getJobStatusCode(operatorConfig, kind, apiName, jobID)
+		if err != nil {
+			// tolerate intermittent failures (e.g. network issues), since jobs can run for a long time
+			numPollFailures++
+			if numPollFailures >= _jobWaitMaxPollFailures {
+				return status.JobUnknown, err
+			}
+		} else {
+			numPollFailures = 0
+
+			if jobStatus != lastStatus {
+				fmt.Printf("job %s is %s\n", jobID, jobStatus.Message())
+				lastStatus = jobStatus
+			}
+
+			if _flagWaitLogs && logsDone == nil && jobStatus == status.JobRunning {
+				logsDone = make(chan struct{})
+				routines.RunWithPanicHandler(func() {
+					defer close(logsDone)
+					err := cluster.StreamJobReplicaLogs(operatorConfig, apiName, jobID, cluster.LogStreamOptions{Follow: true})
+					if err != nil {
+						exit.Error(err)
+					}
+				}, false)
+			}
+
+			if jobStatus.IsCompleted() {
+				if logsDone != nil {
+					// allow the final log lines to be written
+					select {
+					case <-logsDone:
+					case <-time.After(_jobWaitLogsGracePeriod):
+					}
+				}
+				return jobStatus, nil
+			}
+		}
+
+		select {
+		case <-deadline:
+			return lastStatus, ErrorJobWaitTimeout(jobID, _flagWaitTimeout, lastStatus)
+		case <-time.After(_jobWaitPollInterval):
+		}
+	}
+}
+
+func getJobStatusCode(operatorConfig cluster.OperatorConfig, kind userconfig.Kind, apiName string, jobID string) (status.JobCode, error) {
+	if kind == userconfig.BatchAPIKind {
+		jobRes, err := cluster.GetBatchJob(operatorConfig, apiName, jobID)
+		if err != nil {
+			return status.JobUnknown, err
+		}
+		return jobRes.JobStatus.Status, nil
+	}
+
+	jobRes, err := cluster.GetTaskJob(operatorConfig, apiName, jobID)
+	if err != nil {
+		return status.JobUnknown, err
+	}
+	return jobRes.JobStatus.Status, nil
+}
@@ -21,4 +21,7 @@ const (
 	DefaultEnvironmentKey = "default_environment"
 	NameKey               = "name"
 	OperatorEndpointKey   = "operator_endpoint"
+	ProjectKey            = "project"
+	AWSProfileKey         = "aws_profile"
+	OIDCLoginKey          = "oidc_login"
 )
@@ -24,11 +24,24 @@ import (
 	"github.com/cortexlabs/cortex/pkg/lib/console"
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
 	"github.com/cortexlabs/cortex/pkg/lib/urls"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
 )
 
 type Environment struct {
-	Name             string `json:"name" yaml:"name"`
-	OperatorEndpoint string `json:"operator_endpoint" yaml:"operator_endpoint"`
+	Name             string     `json:"name" yaml:"name"`
+	OperatorEndpoint string     `json:"operator_endpoint" yaml:"operator_endpoint"`
+	Project          string     `json:"project,omitempty" yaml:"project,omitempty"`
+	AWSProfile       string     `json:"aws_profile,omitempty" yaml:"aws_profile,omitempty"`
+	OIDCLogin        *OIDCLogin `json:"oidc_login,omitempty" yaml:"oidc_login,omitempty"`
+}
+
+// OIDCLogin holds the tokens which were obtained with `cortex login`
+type OIDCLogin struct {
+	IssuerURL    string `json:"issuer_url" yaml:"issuer_url"`
+	ClientID     string `json:"client_id" yaml:"client_id"`
+	IDToken      string `json:"id_token" yaml:"id_token"`
+	RefreshToken string `json:"refresh_token,omitempty" yaml:"refresh_token,omitempty"`
+	Expiry       int64  `json:"expiry" yaml:"expiry"` // unix time at which the id token expires
 }
 
 func (env Environment) String(isDefault bool) string {
@@ -41,6 +54,15 @@ func (env Environment) String(isDefault bool) string {
 	}
 
 	envStr += fmt.Sprintf("\ncortex operator endpoint: %s\n", env.OperatorEndpoint)
+	if env.Project != "" {
+		envStr += fmt.Sprintf("project: %s\n", env.Project)
+	}
+	if env.AWSProfile != "" {
+		envStr += fmt.Sprintf("aws profile: %s\n", env.AWSProfile)
+	}
+	if env.OIDCLogin != nil {
+		envStr += fmt.Sprintf("logged in with: %s\n", env.OIDCLogin.IssuerURL)
+	}
 
 	return envStr
 }
@@ -72,5 +94,10 @@ func (env *Environment) Validate() error {
 	}
 
 	env.OperatorEndpoint = validOperatorURL
+
+	if err := userconfig.ValidateProject(env.Project); err != nil {
+		return errors.Wrap(err, ProjectKey)
+	}
+
 	return nil
 }
@@ -24,7 +24,8 @@ import (
 )
 
 const (
-	ErrInvalidOutputType = "flags.invalid_output_type"
+	ErrInvalidOutputType   = "flags.invalid_output_type"
+	ErrInvalidExportFormat = "flags.invalid_export_format"
 )
 
 func ErrorInvalidOutputType(invalidOutputType string) error {
@@ -33,3 +34,10 @@ func ErrorInvalidOutputType(invalidOutputType string) error {
 		Message: fmt.Sprintf("invalid value \"%s\" specified for -o/--output; valid values are %s", invalidOutputType, s.StrsAnd(OutputTypeStrings())),
 	})
 }
+
+func ErrorInvalidExportFormat(invalidExportFormat string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrInvalidExportFormat,
+		Message: fmt.Sprintf("invalid value \"%s\" specified for --format; valid values are %s", invalidExportFormat, s.StrsAnd(ExportFormatStrings())),
+	})
+}
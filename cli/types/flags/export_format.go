@@ -0,0 +1,74 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package flags
+
+type ExportFormat int
+
+const (
+	UnknownExportFormat ExportFormat = iota
+	ArchiveExportFormat
+	TerraformExportFormat
+	CloudFormationExportFormat
+)
+
+var _exportFormats = []string{
+	"unknown",
+	"archive",
+	"terraform",
+	"cloudformation",
+}
+
+func ExportFormatFromString(s string) ExportFormat {
+	for i := 0; i < len(_exportFormats); i++ {
+		if s == _exportFormats[i] {
+			return ExportFormat(i)
+		}
+	}
+	return UnknownExportFormat
+}
+
+func ExportFormatStrings() []string {
+	return _exportFormats[1:]
+}
+
+func (t ExportFormat) String() string {
+	return _exportFormats[t]
+}
+
+// MarshalText satisfies TextMarshaler
+func (t ExportFormat) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// UnmarshalText satisfies TextUnmarshaler
+func (t *ExportFormat) UnmarshalText(text []byte) error {
+	*t = ExportFormatFromString(string(text))
+	return nil
+}
+
+func (t *ExportFormat) Set(value string) error {
+	format := ExportFormatFromString(value)
+	if format == UnknownExportFormat {
+		return ErrorInvalidExportFormat(value)
+	}
+	*t = format
+	return nil
+}
+
+func (t ExportFormat) Type() string {
+	return "string"
+}
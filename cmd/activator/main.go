@@ -29,6 +29,7 @@ import (
 
 	"github.com/cortexlabs/cortex/pkg/activator"
 	"github.com/cortexlabs/cortex/pkg/autoscaler"
+	"github.com/cortexlabs/cortex/pkg/consts"
 	"github.com/cortexlabs/cortex/pkg/lib/aws"
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
 	"github.com/cortexlabs/cortex/pkg/lib/k8s"
@@ -48,17 +49,12 @@ func main() {
 		adminPort     int
 		inCluster     bool
 		autoscalerURL string
-		namespace     string
 	)
 
 	flag.IntVar(&port, "port", 8000, "port where the activator server will be exposed")
 	flag.IntVar(&adminPort, "admin-port", 15000, "port where the admin server will be exposed")
 	flag.BoolVar(&inCluster, "in-cluster", false, "use when autoscaler runs in-cluster")
 	flag.StringVar(&autoscalerURL, "autoscaler-url", "", "the URL for the cortex autoscaler endpoint")
-	flag.StringVar(&namespace, "namespace", os.Getenv("CORTEX_NAMESPACE"),
-		"kubernetes namespace where the cortex APIs are deployed "+
-			"(can be set through the CORTEX_NAMESPACE env variable)",
-	)
 	flag.Parse()
 
 	log := logging.GetLogger()
@@ -66,11 +62,8 @@ func main() {
 		_ = log.Sync()
 	}()
 
-	switch {
-	case autoscalerURL == "":
+	if autoscalerURL == "" {
 		log.Fatal("--autoscaler-url is a required option")
-	case namespace == "":
-		log.Fatal("--namespace is a required option")
 	}
 
 	awsClient, err := aws.New()
@@ -101,7 +94,7 @@ func main() {
 	}
 	defer telemetry.Close()
 
-	k8sClient, err := k8s.New(namespace, inCluster, nil, runtime.NewScheme())
+	k8sClient, err := k8s.New(consts.DefaultNamespace, inCluster, nil, runtime.NewScheme())
 	if err != nil {
 		exit(log, err, "failed to initialize kubernetes client")
 	}
@@ -114,15 +107,14 @@ func main() {
 
 	istioInformerFactory := istioinformers.NewSharedInformerFactoryWithOptions(
 		istioClient, 10*time.Second, // TODO: check how much makes sense
-		istioinformers.WithNamespace(namespace),
 		istioinformers.WithTweakListOptions(informerFilter),
 	)
 	virtualServiceInformer := istioInformerFactory.Networking().V1beta1().VirtualServices().Informer()
-	virtualServiceClient := istioClient.NetworkingV1beta1().VirtualServices(namespace)
+	// apis are deployed to the namespace of their project, so the activator watches all namespaces
+	virtualServiceClient := istioClient.NetworkingV1beta1().VirtualServices(kmeta.NamespaceAll)
 
 	kubeInformerFactory := kinformers.NewSharedInformerFactoryWithOptions(
 		kubeClient, 2*time.Second, // TODO: check how much makes sense
-		kinformers.WithNamespace(namespace),
 		kinformers.WithTweakListOptions(informerFilter),
 	)
 	deploymentInformer := kubeInformerFactory.Apps().V1().Deployments().Informer()
@@ -18,11 +18,15 @@ package main
 
 import (
 	"flag"
+	"net"
 	"net/http"
 	"os"
 	"strings"
+	"time"
 
 	gateway "github.com/cortexlabs/cortex/pkg/async-gateway"
+	"github.com/cortexlabs/cortex/pkg/async-gateway/pb"
+	"github.com/cortexlabs/cortex/pkg/consts"
 	"github.com/cortexlabs/cortex/pkg/lib/aws"
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
 	"github.com/cortexlabs/cortex/pkg/lib/logging"
@@ -31,13 +35,15 @@ import (
 	"github.com/gorilla/handlers"
 	"github.com/gorilla/mux"
 	"go.uber.org/zap"
+	"google.golang.org/grpc"
 )
 
 const (
-	_defaultPort = "8080"
+	_defaultPort            = "8080"
+	_defaultCleanupInterval = 10 * time.Minute
 )
 
-// usage: ./gateway -bucket <bucket> -region <region> -port <port>
+// usage: ./gateway -bucket <bucket> -region <region> -port <port> -grpc-port <grpc-port>
 func main() {
 	log := logging.GetLogger()
 	defer func() {
@@ -45,9 +51,11 @@ func main() {
 	}()
 
 	var (
-		bucket     = flag.String("bucket", "", "bucket")
-		clusterUID = flag.String("cluster-uid", "", "cluster uid")
-		port       = flag.String("port", _defaultPort, "port on which the gateway server runs on")
+		bucket          = flag.String("bucket", "", "bucket")
+		clusterUID      = flag.String("cluster-uid", "", "cluster uid")
+		port            = flag.String("port", _defaultPort, "port on which the gateway server runs on")
+		grpcPort        = flag.String("grpc-port", consts.AsyncGatewayGRPCPortStr, "port on which the gateway grpc server runs on")
+		cleanupInterval = flag.Duration("cleanup-interval", _defaultCleanupInterval, "interval at which the payloads and results of expired workloads are deleted")
 	)
 	flag.Parse()
 
@@ -91,6 +99,24 @@ func main() {
 	svc := gateway.NewService(*clusterUID, s3Storage, log, *sess)
 	ep := gateway.NewEndpoint(svc, log)
 
+	janitorStopCh := make(chan struct{})
+	defer close(janitorStopCh)
+	go gateway.NewJanitor(*clusterUID, s3Storage, *cleanupInterval, log).Run(janitorStopCh)
+
+	grpcListener, err := net.Listen("tcp", ":"+*grpcPort)
+	if err != nil {
+		exit(log, err)
+	}
+	grpcServer := grpc.NewServer()
+	pb.RegisterAsyncGatewayServer(grpcServer, gateway.NewGRPCServer(svc, log))
+	defer grpcServer.Stop()
+	go func() {
+		log.Info("Running grpc server on port " + *grpcPort)
+		if err := grpcServer.Serve(grpcListener); err != nil {
+			exit(log, err)
+		}
+	}()
+
 	router := mux.NewRouter()
 	router.HandleFunc("/", ep.CreateWorkload).Methods("POST")
 	router.HandleFunc(
@@ -107,7 +133,7 @@ func main() {
 	corsOptions := []handlers.CORSOption{
 		handlers.AllowedOrigins([]string{"*"}),
 		// custom headers are not supported currently, since "*" is not supported in AllowedHeaders(); here are some common ones:
-		handlers.AllowedHeaders([]string{"Content-Type", "X-Requested-With", "User-Agent", "Accept", "Accept-Language", "Content-Language", "Origin"}),
+		handlers.AllowedHeaders([]string{"Content-Type", "X-Requested-With", "User-Agent", "Accept", "Accept-Language", "Content-Language", "Origin", consts.IdempotencyKeyHeader}),
 		handlers.AllowedMethods([]string{"GET", "HEAD", "POST", "PUT", "OPTIONS"}),
 		handlers.ExposedHeaders([]string{"Content-Length", "Content-Range"}),
 		handlers.AllowCredentials(),
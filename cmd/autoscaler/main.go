@@ -29,6 +29,7 @@ import (
 	"time"
 
 	"github.com/cortexlabs/cortex/pkg/autoscaler"
+	"github.com/cortexlabs/cortex/pkg/consts"
 	"github.com/cortexlabs/cortex/pkg/lib/aws"
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
 	"github.com/cortexlabs/cortex/pkg/lib/k8s"
@@ -53,7 +54,6 @@ func main() {
 		port          int
 		inCluster     bool
 		prometheusURL string
-		namespace     string
 	)
 
 	flag.IntVar(&port, "port", 8000, "port where the autoscaler server will be exposed")
@@ -61,10 +61,6 @@ func main() {
 	flag.StringVar(&prometheusURL, "prometheus-url", os.Getenv("CORTEX_PROMETHEUS_URL"),
 		"prometheus url (can be set through the CORTEX_PROMETHEUS_URL env variable)",
 	)
-	flag.StringVar(&namespace, "namespace", os.Getenv("CORTEX_NAMESPACE"),
-		"kubernetes namespace where the cortex APIs are deployed "+
-			"(can be set through the CORTEX_NAMESPACE env variable)",
-	)
 	flag.Parse()
 
 	log := logging.GetLogger()
@@ -72,11 +68,8 @@ func main() {
 		_ = log.Sync()
 	}()
 
-	switch {
-	case prometheusURL == "":
+	if prometheusURL == "" {
 		log.Fatal("--prometheus-url is a required option")
-	case namespace == "":
-		log.Fatal("--namespace is a required option")
 	}
 
 	awsClient, err := aws.New()
@@ -112,7 +105,8 @@ func main() {
 		exit(log, err, "failed to add k8s client-go-scheme to scheme")
 	}
 
-	k8sClient, err := k8s.New(namespace, inCluster, nil, scheme)
+	// apis are deployed to the namespace of their project, so the scalers look up each api's namespace
+	k8sClient, err := k8s.New(consts.DefaultNamespace, inCluster, nil, scheme)
 	if err != nil {
 		exit(log, err, "failed to initialize kubernetes client")
 	}
@@ -134,8 +128,9 @@ func main() {
 
 	promAPIClient := promv1.NewAPI(promClient)
 
-	realtimeScaler := autoscaler.NewRealtimeScaler(k8sClient, promAPIClient, log)
-	asyncScaler := autoscaler.NewAsyncScaler(k8sClient, promAPIClient)
+	apiNamespaces := autoscaler.NewAPINamespaces()
+	realtimeScaler := autoscaler.NewRealtimeScaler(k8sClient, apiNamespaces, promAPIClient, log)
+	asyncScaler := autoscaler.NewAsyncScaler(k8sClient, apiNamespaces, promAPIClient)
 
 	autoScaler := autoscaler.New(log)
 	autoScaler.AddScaler(realtimeScaler, userconfig.RealtimeAPIKind)
@@ -144,7 +139,6 @@ func main() {
 
 	istioInformerFactory := istioinformers.NewSharedInformerFactoryWithOptions(
 		istioClient, 10*time.Second, // TODO: check how much makes sense
-		istioinformers.WithNamespace(namespace),
 		istioinformers.WithTweakListOptions(informerFilter),
 	)
 	virtualServiceInformer := istioInformerFactory.Networking().V1beta1().VirtualServices().Informer()
@@ -158,17 +152,13 @@ func main() {
 					return
 				}
 
-				if resource.GetNamespace() != namespace {
-					// filter out virtual services that are not in the cortex namespace
-					return
-				}
-
 				api, err := apiResourceFromLabels(resource.GetLabels())
 				if err != nil {
 					// filter out non-cortex apis
 					return
 				}
 
+				apiNamespaces.Set(api.Name, resource.GetNamespace())
 				if err := autoScaler.AddAPI(api); err != nil {
 					log.Errorw("failed to add API to autoscaler",
 						zap.Error(err),
@@ -185,11 +175,6 @@ func main() {
 					log.Errorw("failed to access resource metadata", zap.Error(err))
 				}
 
-				if resource.GetNamespace() != namespace {
-					// filter out virtual services that are not in the cortex namespace
-					return
-				}
-
 				api, err := apiResourceFromLabels(resource.GetLabels())
 				if err != nil {
 					// filter out non-cortex apis
@@ -197,6 +182,7 @@ func main() {
 				}
 
 				autoScaler.RemoveAPI(api)
+				apiNamespaces.Delete(api.Name)
 			},
 		},
 	)
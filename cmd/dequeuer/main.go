@@ -17,6 +17,7 @@ limitations under the License.
 package main
 
 import (
+	"encoding/json"
 	"flag"
 	"fmt"
 	"net/http"
@@ -33,9 +34,11 @@ import (
 	"github.com/cortexlabs/cortex/pkg/lib/files"
 	"github.com/cortexlabs/cortex/pkg/lib/logging"
 	"github.com/cortexlabs/cortex/pkg/lib/telemetry"
+	"github.com/cortexlabs/cortex/pkg/predictionmetrics"
 	"github.com/cortexlabs/cortex/pkg/probe"
 	"github.com/cortexlabs/cortex/pkg/types/clusterconfig"
 	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
 )
 
@@ -52,6 +55,11 @@ func main() {
 		apiKind           string
 		adminPort         int
 		workers           int
+		outputPath        string
+		maxReceiveCount   int
+		maxRetries        int
+		autoWorkers       bool
+		predictionMetrics string
 	)
 	flag.StringVar(&clusterConfigPath, "cluster-config", "", "cluster config path")
 	flag.StringVar(&clusterUID, "cluster-uid", "", "cluster unique identifier")
@@ -64,7 +72,12 @@ func main() {
 	flag.IntVar(&userContainerPort, "user-port", 8080, "target port to which the dequeued messages will be sent to")
 	flag.IntVar(&adminPort, "admin-port", 0, "port where the admin server (for the probes) will be exposed")
 	flag.IntVar(&workers, "workers", 1, "number of workers pulling from the queue")
+	flag.StringVar(&outputPath, "output-path", "", "s3 path to which the results of async workloads are also written (optional)")
+	flag.IntVar(&maxReceiveCount, "max-receive-count", 0, "number of attempts after which a failed async workload is moved to the dead-letter queue (0 if there is no dead-letter queue)")
+	flag.IntVar(&maxRetries, "max-retries", 0, "number of times a failed batch is retried before it is considered failed (batch only)")
+	flag.BoolVar(&autoWorkers, "auto-workers", false, "whether the job's workers are scaled with its remaining batches, in which case the dequeuer exits as soon as there are no batches left to receive (batch only)")
 
+	flag.StringVar(&predictionMetrics, "prediction-metrics", "", "json-encoded list of prediction metrics which the user container can report to the admin server (async only)")
 	flag.Parse()
 
 	version := os.Getenv("CORTEX_VERSION")
@@ -152,11 +165,14 @@ func main() {
 		}
 
 		config := dequeuer.BatchMessageHandlerConfig{
-			Region:    clusterConfig.Region,
-			APIName:   apiName,
-			JobID:     jobID,
-			QueueURL:  queueURL,
-			TargetURL: targetURL,
+			Region:     clusterConfig.Region,
+			APIName:    apiName,
+			JobID:      jobID,
+			QueueURL:   queueURL,
+			TargetURL:  targetURL,
+			ClusterUID: clusterUID,
+			Bucket:     clusterConfig.Bucket,
+			MaxRetries: maxRetries,
 		}
 
 		metricsClient, err := statsd.New(statsdAddress)
@@ -166,10 +182,11 @@ func main() {
 
 		messageHandler = dequeuer.NewBatchMessageHandler(config, awsClient, metricsClient, log)
 		dequeuerConfig = dequeuer.SQSDequeuerConfig{
-			Region:           clusterConfig.Region,
-			QueueURL:         queueURL,
-			StopIfNoMessages: true,
-			Workers:          workers,
+			Region:                  clusterConfig.Region,
+			QueueURL:                queueURL,
+			StopIfNoMessages:        true,
+			StopIfNoVisibleMessages: autoWorkers,
+			Workers:                 workers,
 		}
 
 	case userconfig.AsyncAPIKind.String():
@@ -178,10 +195,12 @@ func main() {
 		}
 
 		config := dequeuer.AsyncMessageHandlerConfig{
-			ClusterUID: clusterUID,
-			Bucket:     clusterConfig.Bucket,
-			APIName:    apiName,
-			TargetURL:  targetURL,
+			ClusterUID:      clusterUID,
+			Bucket:          clusterConfig.Bucket,
+			APIName:         apiName,
+			TargetURL:       targetURL,
+			OutputPath:      outputPath,
+			MaxReceiveCount: maxReceiveCount,
 		}
 
 		asyncStatsReporter := dequeuer.NewAsyncPrometheusStatsReporter()
@@ -195,6 +214,18 @@ func main() {
 
 		// report prometheus metrics for async api kinds
 		adminHandler.Handle("/metrics", asyncStatsReporter)
+
+		if predictionMetrics != "" {
+			var metrics []*userconfig.PredictionMetric
+			if err := json.Unmarshal([]byte(predictionMetrics), &metrics); err != nil {
+				exit(log, err, "--prediction-metrics")
+			}
+			predictionMetricsReporter, err := predictionmetrics.NewReporter(prometheus.DefaultRegisterer, metrics)
+			if err != nil {
+				exit(log, err, "--prediction-metrics")
+			}
+			adminHandler.Handle(predictionmetrics.Path, predictionMetricsReporter)
+		}
 	default:
 		exit(log, err, fmt.Sprintf("kind %s is not supported", apiKind))
 	}
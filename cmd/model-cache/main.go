@@ -0,0 +1,160 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"flag"
+	"os"
+	"time"
+
+	"github.com/DataDog/datadog-go/statsd"
+	awslib "github.com/cortexlabs/cortex/pkg/lib/aws"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/logging"
+	"github.com/cortexlabs/cortex/pkg/modelcache"
+	"go.uber.org/zap"
+)
+
+func main() {
+	var (
+		cacheDir            string
+		s3Path              string
+		apiName             string
+		region              string
+		statsdAddress       string
+		minFreeDiskRatio    float64
+		evictionGracePeriod time.Duration
+		remove              bool
+	)
+	flag.StringVar(&cacheDir, "cache-dir", "", "directory on the node in which models are cached")
+	flag.StringVar(&s3Path, "s3-path", "", "s3 path of the model directory")
+	flag.StringVar(&apiName, "api-name", "", "api name")
+	flag.StringVar(&region, "region", os.Getenv("CORTEX_REGION"), "cluster region (can be set through the CORTEX_REGION env variable)")
+	flag.StringVar(&statsdAddress, "statsd-address", "", "address to push statsd metrics (optional)")
+	flag.Float64Var(&minFreeDiskRatio, "min-free-disk-ratio", 0.2, "fraction of the node's disk which least recently used models are evicted to keep free")
+	flag.DurationVar(&evictionGracePeriod, "eviction-grace-period", time.Hour, "duration after a model was last fetched during which it won't be evicted")
+	flag.BoolVar(&remove, "remove", false, "remove the model from the node's cache instead of fetching it")
+	flag.Parse()
+
+	log := logging.GetLogger()
+	defer func() {
+		_ = log.Sync()
+	}()
+
+	switch {
+	case cacheDir == "":
+		log.Fatal("--cache-dir is a required option")
+	case s3Path == "":
+		log.Fatal("--s3-path is a required option")
+	}
+
+	if remove {
+		removeModel(log, cacheDir, s3Path)
+		return
+	}
+
+	switch {
+	case apiName == "":
+		log.Fatal("--api-name is a required option")
+	case region == "":
+		log.Fatal("--region is a required option")
+	}
+
+	awsClient, err := awslib.NewForRegion(region)
+	if err != nil {
+		exit(log, err, "failed to create aws client")
+	}
+
+	cache := modelcache.New(modelcache.Config{
+		Dir:                 cacheDir,
+		MinFreeDiskRatio:    minFreeDiskRatio,
+		EvictionGracePeriod: evictionGracePeriod,
+	}, modelcache.NewS3Downloader(awsClient), log)
+
+	result, err := cache.Fetch(s3Path)
+	if err != nil {
+		exit(log, err, s3Path)
+	}
+
+	if result.Hit {
+		log.Infow("model found in the node's cache", "s3_path", s3Path, "path", result.Path)
+	} else {
+		log.Infow("downloaded model into the node's cache", "s3_path", s3Path, "path", result.Path, "bytes", result.DownloadedBytes, "duration", result.DownloadDuration.String(), "evicted", result.NumEvicted)
+	}
+
+	if statsdAddress != "" {
+		if err := reportMetrics(statsdAddress, apiName, result); err != nil {
+			log.Warnw("failed to report model cache metrics", "error", err)
+		}
+	}
+}
+
+func removeModel(log *zap.SugaredLogger, cacheDir string, s3Path string) {
+	cache := modelcache.New(modelcache.Config{Dir: cacheDir}, nil, log)
+
+	removed, err := cache.Remove(s3Path)
+	if err != nil {
+		exit(log, err, s3Path)
+	}
+
+	if removed {
+		log.Infow("removed model from the node's cache", "s3_path", s3Path)
+	} else {
+		log.Infow("model not found in the node's cache", "s3_path", s3Path)
+	}
+}
+
+func reportMetrics(statsdAddress string, apiName string, result *modelcache.FetchResult) error {
+	metricsClient, err := statsd.New(statsdAddress)
+	if err != nil {
+		return err
+	}
+	defer metricsClient.Close()
+
+	tags := []string{
+		"api_name:" + apiName,
+		"node:" + os.Getenv("NODE_NAME"),
+	}
+
+	if result.Hit {
+		return metricsClient.Incr("cortex_model_cache_hit", tags, 1.0)
+	}
+
+	if err := metricsClient.Incr("cortex_model_cache_miss", tags, 1.0); err != nil {
+		return err
+	}
+	if err := metricsClient.Histogram("cortex_model_cache_download_duration", result.DownloadDuration.Seconds(), tags, 1.0); err != nil {
+		return err
+	}
+	return metricsClient.Count("cortex_model_cache_evicted", int64(result.NumEvicted), tags, 1.0)
+}
+
+func exit(log *zap.SugaredLogger, err error, wrapStrs ...string) {
+	if err == nil {
+		os.Exit(0)
+	}
+
+	for _, str := range wrapStrs {
+		err = errors.Wrap(err, str)
+	}
+
+	if !errors.IsNoPrint(err) {
+		log.Fatal(err)
+	}
+
+	os.Exit(1)
+}
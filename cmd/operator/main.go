@@ -21,6 +21,7 @@ import (
 	"time"
 
 	"github.com/cortexlabs/cortex/pkg/config"
+	"github.com/cortexlabs/cortex/pkg/lib/aws"
 	"github.com/cortexlabs/cortex/pkg/lib/cron"
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
 	"github.com/cortexlabs/cortex/pkg/lib/logging"
@@ -28,8 +29,11 @@ import (
 	"github.com/cortexlabs/cortex/pkg/operator/endpoints"
 	"github.com/cortexlabs/cortex/pkg/operator/lib/exit"
 	"github.com/cortexlabs/cortex/pkg/operator/operator"
+	"github.com/cortexlabs/cortex/pkg/operator/resources"
 	"github.com/cortexlabs/cortex/pkg/operator/resources/asyncapi"
 	"github.com/cortexlabs/cortex/pkg/operator/resources/job/taskapi"
+	"github.com/cortexlabs/cortex/pkg/operator/resources/realtimeapi"
+	"github.com/cortexlabs/cortex/pkg/rbac"
 	"github.com/cortexlabs/cortex/pkg/types/userconfig"
 	"github.com/gorilla/handlers"
 	"github.com/gorilla/mux"
@@ -38,9 +42,22 @@ import (
 
 var operatorLogger = logging.GetLogger()
 
-const _operatorPortStr = "8888"
+const (
+	_operatorPortStr = "8888"
+	_metricsPortStr  = "8889" // only reachable from inside the cluster (the operator's load balancer routes to _operatorPortStr)
+)
 
 func main() {
+	aws.OnThrottle = func(event aws.ThrottleEvent) {
+		operatorLogger.Warnw("aws request throttled",
+			"service", event.Service,
+			"operation", event.Operation,
+			"error_code", event.ErrorCode,
+			"attempt", event.Attempt,
+			"retry_in", event.Delay.String(),
+		)
+	}
+
 	if err := config.Init(); err != nil {
 		exit.ErrorNoTelemetry(errors.Wrap(err, "init"))
 	}
@@ -50,6 +67,12 @@ func main() {
 	cron.Run(operator.DeleteEvictedPods, operator.ErrorHandler("delete evicted pods"), time.Hour)
 	cron.Run(operator.ClusterTelemetry, operator.ErrorHandler("instance telemetry"), 1*time.Hour)
 	cron.Run(operator.CostBreakdown, operator.ErrorHandler("cost breakdown metrics"), 5*time.Minute)
+	cron.Run(operator.HandleSpotInterruptions, operator.ErrorHandler("handle spot interruptions"), operator.SpotInterruptionsCronPeriod)
+	cron.Run(operator.RefreshECRRegistryCredentials, operator.ErrorHandler("refresh ecr registry credentials"), operator.ECRRegistryCredentialsCronPeriod)
+	cron.Run(operator.ApplyNodeGroupSchedules, operator.ErrorHandler("apply nodegroup schedules"), operator.NodeGroupSchedulesCronPeriod)
+	cron.Run(operator.ReconcileCustomDomains, operator.ErrorHandler("reconcile custom domains"), operator.CustomDomainsCronPeriod)
+	cron.Run(operator.RefreshNetworkPolicies, operator.ErrorHandler("refresh egress network policies"), operator.NetworkPoliciesCronPeriod)
+	cron.Run(operator.SyncProjectNamespaces, operator.ErrorHandler("sync project namespaces"), operator.ProjectNamespacesCronPeriod)
 
 	_, err := operator.UpdateMemoryCapacityConfigMap()
 	if err != nil {
@@ -57,8 +80,18 @@ func main() {
 	}
 
 	cron.Run(taskapi.ManageJobResources, operator.ErrorHandler("manage task jobs"), taskapi.ManageJobResourcesCronPeriod)
+	cron.Run(realtimeapi.RouteReadyCanaries, operator.ErrorHandler("route traffic to ready canaries"), realtimeapi.RouteReadyCanariesCronPeriod)
+	cron.Run(realtimeapi.RemovePromotedWarmPods, operator.ErrorHandler("remove promoted warm pool replicas"), realtimeapi.RemovePromotedWarmPodsCronPeriod)
+	cron.Run(operator.UpdateAPIMetrics, operator.ErrorHandler("api metrics"), operator.APIMetricsCronPeriod)
+	cron.Run(resources.WatchModels, operator.ErrorHandler("watch models"), resources.ModelWatchCronPeriod)
+	cron.Run(resources.RunJobSchedules, operator.ErrorHandler("run job schedules"), resources.JobSchedulesCronPeriod)
+	cron.Run(resources.ManagePendingDependenciesJobs, operator.ErrorHandler("manage jobs with pending dependencies"), resources.JobDependenciesCronPeriod)
+	if config.ClusterConfig.GitOps != nil {
+		cron.Run(resources.ReconcileGitOps, operator.ErrorHandler("reconcile gitops repository"), resources.GitOpsCronPeriod)
+	}
+	cron.Run(resources.ReconcileCortexAPIs, operator.ErrorHandler("reconcile CortexAPI resources"), resources.CortexAPICronPeriod)
 
-	deployments, err := config.K8s.ListDeploymentsWithLabelKeys("apiName")
+	deployments, err := config.K8sAllNamspaces.ListDeploymentsWithLabelKeys("apiName")
 	if err != nil {
 		exit.Error(errors.Wrap(err, "init"))
 	}
@@ -79,6 +112,7 @@ func main() {
 	routerWithoutAuth := router.NewRoute().Subrouter()
 	routerWithoutAuth.Use(endpoints.PanicMiddleware)
 	routerWithoutAuth.HandleFunc("/verifycortex", endpoints.VerifyCortex).Methods("GET")
+	routerWithoutAuth.HandleFunc("/auth/oidc", endpoints.GetOIDCConfig).Methods("GET")
 
 	routerWithoutAuth.HandleFunc("/batch/{apiName}", endpoints.SubmitBatchJob).Methods("POST")
 	routerWithoutAuth.HandleFunc("/batch/{apiName}", endpoints.GetBatchJob).Methods("GET")
@@ -87,26 +121,61 @@ func main() {
 	routerWithoutAuth.HandleFunc("/tasks/{apiName}", endpoints.GetTaskJob).Methods("GET")
 	routerWithoutAuth.HandleFunc("/tasks/{apiName}", endpoints.StopTaskJob).Methods("DELETE")
 
-	// prometheus metrics
-	routerWithoutAuth.Handle("/metrics", promhttp.Handler()).Methods("GET")
-
 	routerWithAuth := router.NewRoute().Subrouter()
 
 	routerWithAuth.Use(endpoints.PanicMiddleware)
 	routerWithAuth.Use(endpoints.APIVersionCheckMiddleware)
-	routerWithAuth.Use(endpoints.AWSAuthMiddleware)
+	routerWithAuth.Use(endpoints.AuthMiddleware)
 	routerWithAuth.Use(endpoints.ClientIDMiddleware)
 
-	routerWithAuth.HandleFunc("/info", endpoints.Info).Methods("GET")
-	routerWithAuth.HandleFunc("/deploy", endpoints.Deploy).Methods("POST")
-	routerWithAuth.HandleFunc("/refresh/{apiName}", endpoints.Refresh).Methods("POST")
-	routerWithAuth.HandleFunc("/delete/{apiName}", endpoints.Delete).Methods("DELETE")
-	routerWithAuth.HandleFunc("/get", endpoints.GetAPIs).Methods("GET")
-	routerWithAuth.HandleFunc("/get/{apiName}", endpoints.GetAPI).Methods("GET")
-	routerWithAuth.HandleFunc("/get/{apiName}/{apiID}", endpoints.GetAPIByID).Methods("GET")
-	routerWithAuth.HandleFunc("/describe/{apiName}", endpoints.DescribeAPI).Methods("GET")
-	routerWithAuth.HandleFunc("/streamlogs/{apiName}", endpoints.ReadLogs)
-	routerWithAuth.HandleFunc("/logs/{apiName}", endpoints.GetLogURL).Methods("GET")
+	routerWithAuth.HandleFunc("/info", endpoints.RequireRole(rbac.RoleViewer, endpoints.Info)).Methods("GET")
+	routerWithAuth.HandleFunc("/deploy", endpoints.RequireRole(rbac.RoleDeployer, endpoints.Deploy)).Methods("POST")
+	routerWithAuth.HandleFunc("/diff", endpoints.RequireRole(rbac.RoleViewer, endpoints.Diff)).Methods("POST")
+	routerWithAuth.HandleFunc("/refresh/{apiName}", endpoints.RequireRole(rbac.RoleDeployer, endpoints.Refresh)).Methods("POST")
+	routerWithAuth.HandleFunc("/promote/{apiName}", endpoints.RequireRole(rbac.RoleDeployer, endpoints.Promote)).Methods("POST")
+	routerWithAuth.HandleFunc("/rollback/{apiName}", endpoints.RequireRole(rbac.RoleDeployer, endpoints.Rollback)).Methods("POST")
+	routerWithAuth.HandleFunc("/redrive/{apiName}", endpoints.RequireRole(rbac.RoleDeployer, endpoints.Redrive)).Methods("POST")
+	routerWithAuth.HandleFunc("/rerun/{apiName}", endpoints.RequireRole(rbac.RoleDeployer, endpoints.RerunBatchJob)).Methods("POST")
+	routerWithAuth.HandleFunc("/results/{apiName}", endpoints.RequireRole(rbac.RoleViewer, endpoints.GetBatchJobResults)).Methods("GET")
+	routerWithAuth.HandleFunc("/delete", endpoints.RequireRole(rbac.RoleDeployer, endpoints.DeleteAPIs)).Methods("DELETE")
+	routerWithAuth.HandleFunc("/delete/{apiName}", endpoints.RequireRole(rbac.RoleDeployer, endpoints.Delete)).Methods("DELETE")
+	routerWithAuth.HandleFunc("/get", endpoints.RequireRole(rbac.RoleViewer, endpoints.GetAPIs)).Methods("GET")
+	routerWithAuth.HandleFunc("/get/{apiName}", endpoints.RequireRole(rbac.RoleViewer, endpoints.GetAPI)).Methods("GET")
+	routerWithAuth.HandleFunc("/get/{apiName}/{apiID}", endpoints.RequireRole(rbac.RoleViewer, endpoints.GetAPIByID)).Methods("GET")
+	routerWithAuth.HandleFunc("/describe/{apiName}", endpoints.RequireRole(rbac.RoleViewer, endpoints.DescribeAPI)).Methods("GET")
+	routerWithAuth.HandleFunc("/streamlogs/{apiName}", endpoints.RequireRole(rbac.RoleViewer, endpoints.ReadLogs))
+	routerWithAuth.HandleFunc("/joblogs/{apiName}", endpoints.RequireRole(rbac.RoleViewer, endpoints.ReadAggregatedJobLogs))
+	routerWithAuth.HandleFunc("/exec/{apiName}", endpoints.RequireRole(rbac.RoleDeployer, endpoints.Exec))
+	routerWithAuth.HandleFunc("/portforward/{apiName}", endpoints.RequireRole(rbac.RoleDeployer, endpoints.PortForward))
+	routerWithAuth.HandleFunc("/logs/{apiName}", endpoints.RequireRole(rbac.RoleViewer, endpoints.GetLogURL)).Methods("GET")
+	routerWithAuth.HandleFunc("/quotas", endpoints.RequireRole(rbac.RoleViewer, endpoints.GetQuotas)).Methods("GET")
+	routerWithAuth.HandleFunc("/gitops", endpoints.RequireRole(rbac.RoleViewer, endpoints.GetGitOpsStatus)).Methods("GET")
+	routerWithAuth.HandleFunc("/top", endpoints.RequireRole(rbac.RoleViewer, endpoints.Top)).Methods("GET")
+	routerWithAuth.HandleFunc("/top/{apiName}", endpoints.RequireRole(rbac.RoleViewer, endpoints.Top)).Methods("GET")
+	routerWithAuth.HandleFunc("/keys", endpoints.RequireRole(rbac.RoleAdmin, endpoints.ListAPIKeys)).Methods("GET")
+	routerWithAuth.HandleFunc("/keys/{keyName}", endpoints.RequireRole(rbac.RoleAdmin, endpoints.CreateAPIKey)).Methods("POST")
+	routerWithAuth.HandleFunc("/keys/{keyName}", endpoints.RequireRole(rbac.RoleAdmin, endpoints.RevokeAPIKey)).Methods("DELETE")
+	routerWithAuth.HandleFunc("/secrets", endpoints.RequireRole(rbac.RoleDeployer, endpoints.ListSecrets)).Methods("GET")
+	routerWithAuth.HandleFunc("/secrets/{secretName}", endpoints.RequireRole(rbac.RoleDeployer, endpoints.GetSecret)).Methods("GET")
+	routerWithAuth.HandleFunc("/secrets/{secretName}", endpoints.RequireRole(rbac.RoleDeployer, endpoints.SetSecret)).Methods("POST")
+	routerWithAuth.HandleFunc("/secrets/{secretName}", endpoints.RequireRole(rbac.RoleDeployer, endpoints.DeleteSecret)).Methods("DELETE")
+	routerWithAuth.HandleFunc("/schedules", endpoints.RequireRole(rbac.RoleViewer, endpoints.ListJobSchedules)).Methods("GET")
+	routerWithAuth.HandleFunc("/schedules/{apiName}/{scheduleID}/pause", endpoints.RequireRole(rbac.RoleDeployer, endpoints.PauseJobSchedule)).Methods("POST")
+	routerWithAuth.HandleFunc("/schedules/{apiName}/{scheduleID}/resume", endpoints.RequireRole(rbac.RoleDeployer, endpoints.ResumeJobSchedule)).Methods("POST")
+	routerWithAuth.HandleFunc("/schedules/{apiName}/{scheduleID}", endpoints.RequireRole(rbac.RoleDeployer, endpoints.DeleteJobSchedule)).Methods("DELETE")
+	routerWithAuth.HandleFunc("/queue/{apiName}", endpoints.RequireRole(rbac.RoleViewer, endpoints.GetJobQueue)).Methods("GET")
+	routerWithAuth.HandleFunc("/queue/{apiName}/{jobID}/front", endpoints.RequireRole(rbac.RoleDeployer, endpoints.MoveJobToFrontOfQueue)).Methods("POST")
+	routerWithAuth.HandleFunc("/auth/whoami", endpoints.WhoAmI).Methods("GET")
+	routerWithAuth.HandleFunc("/auth/bindings", endpoints.RequireRole(rbac.RoleAdmin, endpoints.ListRoleBindings)).Methods("GET")
+	routerWithAuth.HandleFunc("/auth/bindings/{bindingName}", endpoints.RequireRole(rbac.RoleAdmin, endpoints.CreateRoleBinding)).Methods("POST")
+	routerWithAuth.HandleFunc("/auth/bindings/{bindingName}", endpoints.RequireRole(rbac.RoleAdmin, endpoints.DeleteRoleBinding)).Methods("DELETE")
+
+	// prometheus metrics (which include per-api traffic) are served on a separate port, which isn't exposed by the operator's load balancer
+	metricsRouter := mux.NewRouter()
+	metricsRouter.Handle("/metrics", promhttp.Handler()).Methods("GET")
+	go func() {
+		operatorLogger.Fatal(http.ListenAndServe(":"+_metricsPortStr, metricsRouter))
+	}()
 
 	operatorLogger.Info("Running on port " + _operatorPortStr)
 
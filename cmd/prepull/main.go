@@ -0,0 +1,72 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// prepull is a static binary which keeps the images of an api's containers on each node: an init container copies it
+// into a shared volume (via --copy-to), and then it runs (and sleeps until it's terminated) in a container for each image.
+// It only uses the standard library, since it runs inside of the user's images.
+package main
+
+import (
+	"flag"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+)
+
+func main() {
+	var copyTo string
+	flag.StringVar(&copyTo, "copy-to", "", "copy the prepull binary into this directory and exit")
+	flag.Parse()
+
+	if copyTo != "" {
+		if err := copySelf(copyTo); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	sigint := make(chan os.Signal, 1)
+	signal.Notify(sigint, os.Interrupt, syscall.SIGTERM)
+	<-sigint
+}
+
+func copySelf(dir string) error {
+	executable, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	src, err := os.Open(executable)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	// the binary must be executable by the (possibly non-root) user of each image
+	dst, err := os.OpenFile(filepath.Join(dir, "prepull"), os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0755)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		return err
+	}
+	return dst.Close()
+}
@@ -18,24 +18,31 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"net"
 	"net/http"
+	"net/http/httputil"
 	"os"
 	"os/signal"
 	"strconv"
 	"syscall"
 	"time"
 
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/cortexlabs/cortex/pkg/consts"
 	"github.com/cortexlabs/cortex/pkg/lib/aws"
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
 	"github.com/cortexlabs/cortex/pkg/lib/logging"
 	"github.com/cortexlabs/cortex/pkg/lib/telemetry"
+	"github.com/cortexlabs/cortex/pkg/predictionmetrics"
 	"github.com/cortexlabs/cortex/pkg/proxy"
 	"github.com/cortexlabs/cortex/pkg/types/clusterconfig"
 	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
+	"golang.org/x/net/netutil"
 )
 
 const (
@@ -48,19 +55,43 @@ func main() {
 		port              int
 		adminPort         int
 		userContainerPort int
+		protocol          string
 		maxConcurrency    int
 		maxQueueLength    int
+		maxRequestBody    int64
+		requestTimeout    time.Duration
+		maxConnections    int
 		hasTCPProbe       bool
 		clusterConfigPath string
+		warmupConfig      string
+		apiName           string
+		requestLogConfig  string
+		predictionMetrics string
+		rateLimitConfig   string
+		apiKeysDir        string
+		awsIAMPrincipals  string
+		webSocketConfig   string
 	)
 
 	flag.IntVar(&port, "port", 8000, "port where the proxy server will be exposed")
 	flag.IntVar(&adminPort, "admin-port", 15000, "port where the admin server (for metrics and probes) will be exposed")
 	flag.IntVar(&userContainerPort, "user-port", 8080, "port where the proxy will redirect to the traffic to")
+	flag.StringVar(&protocol, "protocol", userconfig.ProtocolHTTP, "protocol of the user container (http or grpc)")
 	flag.IntVar(&maxConcurrency, "max-concurrency", 0, "max concurrency allowed for user container")
 	flag.IntVar(&maxQueueLength, "max-queue-length", 0, "max request queue length for user container")
+	flag.Int64Var(&maxRequestBody, "max-request-body-size", 0, "max size of request bodies in bytes (0 means no limit)")
+	flag.DurationVar(&requestTimeout, "request-timeout", 0, "max time to wait for the user container to respond to a request, excluding the time spent in the queue (0 means no timeout)")
+	flag.IntVar(&maxConnections, "max-connections", 0, "max number of concurrent client connections which the proxy server accepts (0 means no limit)")
 	flag.BoolVar(&hasTCPProbe, "has-tcp-probe", false, "tcp probe to the user-provided container port")
 	flag.StringVar(&clusterConfigPath, "cluster-config", "", "cluster config path")
+	flag.StringVar(&warmupConfig, "warmup", "", "json-encoded warmup configuration (requests to send to the user container before reporting readiness)")
+	flag.StringVar(&apiName, "api-name", "", "api name (required when request logging is configured)")
+	flag.StringVar(&requestLogConfig, "request-logging", "", "json-encoded request logging configuration (where to write sampled request/response pairs)")
+	flag.StringVar(&predictionMetrics, "prediction-metrics", "", "json-encoded list of prediction metrics which the user container can report to the admin server")
+	flag.StringVar(&rateLimitConfig, "rate-limit", "", "json-encoded rate limit configuration (requests per second per client)")
+	flag.StringVar(&apiKeysDir, "api-keys-dir", "", "directory containing the cluster's hashed api keys; if set, requests must include an api key which grants access to the api")
+	flag.StringVar(&awsIAMPrincipals, "aws-iam-principals", "", "json-encoded list of aws iam principals; if set, requests must include a signed sts identity request from one of the principals")
+	flag.StringVar(&webSocketConfig, "websocket", "", "json-encoded websocket configuration (idle timeout and max connections); if set, websocket connections bypass the max concurrency limit")
 	flag.Parse()
 
 	log := logging.GetLogger()
@@ -75,6 +106,76 @@ func main() {
 		log.Fatal("--max-queue-length flag is required")
 	case clusterConfigPath == "":
 		log.Fatal("--cluster-config flag is required")
+	case protocol != userconfig.ProtocolHTTP && protocol != userconfig.ProtocolGRPC:
+		log.Fatal("--protocol flag must be http or grpc")
+	}
+
+	var warmup *userconfig.Warmup
+	if warmupConfig != "" {
+		warmup = &userconfig.Warmup{}
+		if err := json.Unmarshal([]byte(warmupConfig), warmup); err != nil {
+			exit(log, err, "--warmup")
+		}
+	}
+
+	var requestLogging *userconfig.RequestLogging
+	if requestLogConfig != "" {
+		if apiName == "" {
+			log.Fatal("--api-name flag is required when --request-logging is set")
+		}
+		requestLogging = &userconfig.RequestLogging{}
+		if err := json.Unmarshal([]byte(requestLogConfig), requestLogging); err != nil {
+			exit(log, err, "--request-logging")
+		}
+	}
+
+	if apiKeysDir != "" && apiName == "" {
+		log.Fatal("--api-name flag is required when --api-keys-dir is set")
+	}
+
+	var awsIAMAuthenticator *proxy.AWSIAMAuthenticator
+	if awsIAMPrincipals != "" {
+		if apiName == "" {
+			log.Fatal("--api-name flag is required when --aws-iam-principals is set")
+		}
+		var principals []string
+		if err := json.Unmarshal([]byte(awsIAMPrincipals), &principals); err != nil {
+			exit(log, err, "--aws-iam-principals")
+		}
+		// identity requests must be signed for this api, so that they can't be replayed by the recipients of requests to other apis (or the operator)
+		awsIAMAuthenticator = proxy.NewAWSIAMAuthenticator(principals, func(identityRequestHeader string) (*sts.GetCallerIdentityOutput, error) {
+			return aws.GetCallerIdentityFromHeaderForAudience(identityRequestHeader, consts.AWSIAMAudienceHeader, apiName)
+		})
+	}
+
+	var rateLimiter *proxy.RateLimiter
+	if rateLimitConfig != "" {
+		var rateLimit userconfig.RateLimit
+		if err := json.Unmarshal([]byte(rateLimitConfig), &rateLimit); err != nil {
+			exit(log, err, "--rate-limit")
+		}
+		rateLimiter = proxy.NewRateLimiter(rateLimit)
+	}
+
+	var webSocket *userconfig.WebSocket
+	if webSocketConfig != "" {
+		webSocket = &userconfig.WebSocket{}
+		if err := json.Unmarshal([]byte(webSocketConfig), webSocket); err != nil {
+			exit(log, err, "--websocket")
+		}
+	}
+
+	var predictionMetricsReporter *predictionmetrics.Reporter
+	if predictionMetrics != "" {
+		var metrics []*userconfig.PredictionMetric
+		if err := json.Unmarshal([]byte(predictionMetrics), &metrics); err != nil {
+			exit(log, err, "--prediction-metrics")
+		}
+		var err error
+		predictionMetricsReporter, err = predictionmetrics.NewReporter(prometheus.DefaultRegisterer, metrics)
+		if err != nil {
+			exit(log, err, "--prediction-metrics")
+		}
 	}
 
 	clusterConfig, err := clusterconfig.NewForFile(clusterConfigPath)
@@ -109,7 +210,24 @@ func main() {
 	defer telemetry.Close()
 
 	target := "http://127.0.0.1:" + strconv.Itoa(userContainerPort)
-	httpProxy := proxy.NewReverseProxy(target, maxQueueLength, maxQueueLength)
+
+	var httpProxy *httputil.ReverseProxy
+	var grpcHealthChecker *proxy.GRPCHealthChecker
+	if protocol == userconfig.ProtocolGRPC {
+		httpProxy = proxy.NewGRPCReverseProxy(target)
+		grpcHealthChecker, err = proxy.NewGRPCHealthChecker(target)
+		if err != nil {
+			exit(log, err)
+		}
+		defer grpcHealthChecker.Close()
+	} else {
+		httpProxy = proxy.NewReverseProxy(target, maxQueueLength, maxQueueLength)
+	}
+
+	var webSocketProxy *proxy.WebSocketProxy
+	if webSocket != nil {
+		webSocketProxy = proxy.NewWebSocketProxy(target, *webSocket)
+	}
 
 	requestCounterStats := &proxy.RequestStats{}
 	breaker := proxy.NewBreaker(
@@ -122,6 +240,44 @@ func main() {
 
 	promStats := proxy.NewPrometheusStatsReporter()
 
+	warmer := proxy.NewWarmer(target, warmup, log)
+	warmupCtx, cancelWarmup := context.WithCancel(context.Background())
+	defer cancelWarmup()
+	go func() {
+		if err := warmer.Run(warmupCtx); err != nil && err != context.Canceled {
+			log.Warnw("warmup failed", zap.Error(err))
+		}
+	}()
+
+	var apiKeyAuthenticator *proxy.APIKeyAuthenticator
+	apiKeysCtx, stopAPIKeys := context.WithCancel(context.Background())
+	defer stopAPIKeys()
+	if apiKeysDir != "" {
+		apiKeyAuthenticator, err = proxy.NewAPIKeyAuthenticator(apiName, apiKeysDir, log)
+		if err != nil {
+			exit(log, err, "--api-keys-dir")
+		}
+		go apiKeyAuthenticator.Run(apiKeysCtx)
+	}
+
+	var requestLogger *proxy.RequestLogger
+	requestLoggerCtx, stopRequestLogger := context.WithCancel(context.Background())
+	defer stopRequestLogger()
+	requestLoggerDone := make(chan struct{})
+	if requestLogging != nil {
+		sink, err := proxy.NewRequestLogSink(awsClient, *requestLogging)
+		if err != nil {
+			exit(log, err, "--request-logging")
+		}
+		requestLogger = proxy.NewRequestLogger(apiName, *requestLogging, sink, log)
+		go func() {
+			requestLogger.Run(requestLoggerCtx)
+			close(requestLoggerDone)
+		}()
+	} else {
+		close(requestLoggerDone)
+	}
+
 	go func() {
 		reportTicker := time.NewTicker(_reportInterval)
 		defer reportTicker.Stop()
@@ -138,7 +294,8 @@ func main() {
 				}()
 			case <-requestSamplingTicker.C:
 				go func() {
-					requestCounterStats.Append(breaker.InFlight())
+					// open websocket connections count as in-flight requests, so that they are taken into account by the autoscaler
+					requestCounterStats.Append(breaker.InFlight() + webSocketProxy.Connections())
 				}()
 			}
 		}
@@ -146,12 +303,29 @@ func main() {
 
 	adminHandler := http.NewServeMux()
 	adminHandler.Handle("/metrics", promStats)
-	adminHandler.Handle("/healthz", readinessTCPHandler(userContainerPort, hasTCPProbe, log))
+	adminHandler.Handle("/healthz", readinessTCPHandler(userContainerPort, hasTCPProbe, grpcHealthChecker, warmer, log))
+	if predictionMetricsReporter != nil {
+		adminHandler.Handle(predictionmetrics.Path, predictionMetricsReporter)
+	}
+
+	// handlers are listed from the innermost to the outermost (rate limiting is applied first);
+	// websocket connections bypass the breaker and request logging, since they are long-lived
+	var proxyHandler http.Handler = proxy.RequestTimeoutHandler(requestTimeout, httpProxy)
+	proxyHandler = proxy.Handler(breaker, proxyHandler)
+	proxyHandler = proxy.RequestLoggingHandler(requestLogger, proxyHandler)
+	proxyHandler = proxy.WebSocketHandler(webSocketProxy, proxyHandler)
+	proxyHandler = proxy.MaxRequestBodySizeHandler(maxRequestBody, proxyHandler)
+	proxyHandler = proxy.AWSIAMAuthHandler(awsIAMAuthenticator, proxyHandler)
+	proxyHandler = proxy.APIKeyAuthHandler(apiKeyAuthenticator, proxyHandler)
+	proxyHandler = proxy.RateLimitHandler(rateLimiter, proxyHandler)
+	if protocol == userconfig.ProtocolGRPC {
+		proxyHandler = proxy.GRPCHandler(proxyHandler)
+	}
 
 	servers := map[string]*http.Server{
 		"proxy": {
 			Addr:    ":" + strconv.Itoa(port),
-			Handler: proxy.Handler(breaker, httpProxy),
+			Handler: proxyHandler,
 		},
 		"admin": {
 			Addr:    ":" + strconv.Itoa(adminPort),
@@ -163,7 +337,16 @@ func main() {
 	for name, server := range servers {
 		go func(name string, server *http.Server) {
 			log.Infof("Starting %s server on %s", name, server.Addr)
-			errCh <- server.ListenAndServe()
+			listener, err := net.Listen("tcp", server.Addr)
+			if err != nil {
+				errCh <- err
+				return
+			}
+			// additional client connections wait to be accepted until one of the open connections is closed
+			if name == "proxy" && maxConnections > 0 {
+				listener = netutil.LimitListener(listener, maxConnections)
+			}
+			errCh <- server.Serve(listener)
 		}(name, server)
 	}
 
@@ -184,6 +367,10 @@ func main() {
 				telemetry.Error(errors.Wrap(err, "HTTP server Shutdown Error"))
 			}
 		}
+
+		// flush the remaining request logs
+		stopRequestLogger()
+		<-requestLoggerDone
 		log.Info("Shutdown complete, exiting...")
 	}
 }
@@ -204,9 +391,23 @@ func exit(log *zap.SugaredLogger, err error, wrapStrs ...string) {
 	os.Exit(1)
 }
 
-func readinessTCPHandler(port int, enableTCPProbe bool, logger *zap.SugaredLogger) http.HandlerFunc {
+// if grpcHealthChecker is not nil, the user container is probed with the grpc health checking protocol instead of with a tcp connection
+func readinessTCPHandler(port int, enableTCPProbe bool, grpcHealthChecker *proxy.GRPCHealthChecker, warmer *proxy.Warmer, logger *zap.SugaredLogger) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		if enableTCPProbe {
+		if !warmer.Done() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte("warming up"))
+			return
+		}
+
+		if enableTCPProbe && grpcHealthChecker != nil {
+			if err := grpcHealthChecker.Check(r.Context()); err != nil {
+				logger.Warn(errors.Wrap(err, "gRPC health check of user-provided container failed"))
+				w.WriteHeader(http.StatusInternalServerError)
+				_, _ = w.Write([]byte("unhealthy"))
+				return
+			}
+		} else if enableTCPProbe {
 			ctx := r.Context()
 			address := net.JoinHostPort("localhost", fmt.Sprintf("%d", port))
 
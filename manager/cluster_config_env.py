@@ -25,6 +25,11 @@ def export(base_key, value):
             value, "CORTEX_OPERATOR_LOAD_BALANCER_TAGS", {"cortex.dev/load-balancer": "operator"}
         )
         exportTags(value, "CORTEX_API_LOAD_BALANCER_TAGS", {"cortex.dev/load-balancer": "api"})
+        exportTags(
+            value,
+            "CORTEX_API_INTERNAL_LOAD_BALANCER_TAGS",
+            {"cortex.dev/load-balancer": "api-internal"},
+        )
         return
 
     if value is None:
@@ -160,6 +160,20 @@ def apply_spot_settings(nodegroup, config):
     return merge_override(nodegroup, spot_settings)
 
 
+def apply_capacity_reservation_settings(nodegroup, config):
+    # instances which target a capacity reservation can only be launched in the reservation's availability zone
+    capacity_reservation_settings = {
+        "availabilityZones": [config["capacity_reservation_availability_zone"]],
+        "capacityReservation": {
+            "capacityReservationTarget": {
+                "capacityReservationID": config["capacity_reservation_id"],
+            },
+        },
+    }
+
+    return merge_override(nodegroup, capacity_reservation_settings)
+
+
 def apply_gpu_settings(nodegroup):
     gpu_settings = {
         "tags": {
@@ -199,6 +213,9 @@ def apply_inf_settings(nodegroup, config):
             "k8s.io/cluster-autoscaler/node-template/resources/aws.amazon.com/neuron": str(
                 num_chips
             ),
+            "k8s.io/cluster-autoscaler/node-template/resources/aws.amazon.com/neuroncore": str(
+                num_chips * 4
+            ),
             "k8s.io/cluster-autoscaler/node-template/resources/hugepages-2Mi": num_hugepages,
         },
         "labels": {"aws.amazon.com/neuron": "true"},
@@ -259,6 +276,9 @@ def get_worker_nodegroup(ami_map: dict, nodegroup_config: dict, cluster_config:
     if nodegroup_config["spot"]:
         apply_spot_settings(worker_nodegroup, nodegroup_config)
 
+    if nodegroup_config.get("capacity_reservation_id"):
+        apply_capacity_reservation_settings(worker_nodegroup, nodegroup_config)
+
     if is_gpu(nodegroup_config["instance_type"]):
         apply_gpu_settings(worker_nodegroup)
 
@@ -343,12 +363,15 @@ def generate_eks(
         click.echo(yaml.dump(eks, Dumper=IgnoreAliases, default_flow_style=False, default_style=""))
         return
 
+    # graviton-only clusters also run the cortex system components on arm64 instances
+    operator_instance_type = "t4g.medium" if cluster_config.get("arch") == "arm64" else "t3.medium"
+
     operator_nodegroup = default_nodegroup(cluster_config)
     operator_settings = {
-        "ami": get_ami(ami_map, "t3.medium"),
+        "ami": get_ami(ami_map, operator_instance_type),
         "amiFamily": AMI_FAMILY,
         "name": "cx-operator",
-        "instanceType": "t3.medium",
+        "instanceType": operator_instance_type,
         "minSize": 2,
         "maxSize": 25,
         "desiredCapacity": 2,
@@ -403,12 +426,7 @@ def generate_eks(
         },
         "vpc": {"nat": {"gateway": nat_gateway}},
         "nodeGroups": [operator_nodegroup, prometheus_nodegroup] + worker_nodegroups,
-        "addons": [
-            {
-                "name": "vpc-cni",
-                "version": "1.12.6",
-            },
-        ],
+        "addons": [vpc_cni_addon(cluster_config)],
     }
 
     if (
@@ -429,12 +447,27 @@ def generate_eks(
         else:
             eks["vpc"]["subnets"] = {"public": eks_subnet_configs}
 
+    if cluster_config.get("vpc_id", "") != "":
+        eks["vpc"]["id"] = cluster_config["vpc_id"]
+
     if cluster_config.get("vpc_cidr", "") != "":
         eks["vpc"]["cidr"] = cluster_config["vpc_cidr"]
 
     click.echo(yaml.dump(eks, Dumper=IgnoreAliases, default_flow_style=False, default_style=""))
 
 
+def vpc_cni_addon(cluster_config):
+    if not cluster_config.get("network_policies", False):
+        return {"name": "vpc-cni", "version": "1.12.6"}
+
+    # network policy enforcement is supported by the vpc cni starting with version 1.14
+    return {
+        "name": "vpc-cni",
+        "version": "1.14.1",
+        "configurationValues": json.dumps({"enableNetworkPolicy": "true"}),
+    }
+
+
 class IgnoreAliases(yaml.Dumper):
     """By default, yaml dumper tries to compress yaml by annotating collections (lists and maps)
     and replacing subsequent identical collections with aliases. This class overrides the default
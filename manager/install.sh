@@ -27,6 +27,16 @@ arg1="$1"
 function main() {
   if [ "$arg1" = "--configure" ]; then
     cluster_configure
+  elif [ "$arg1" = "--scale" ]; then
+    cluster_scale
+  elif [ "$arg1" = "--nodegroups" ]; then
+    cluster_update_nodegroups
+  elif [ "$arg1" = "--pause" ]; then
+    cluster_pause
+  elif [ "$arg1" = "--resume" ]; then
+    cluster_resume
+  elif [ "$arg1" = "--upgrade" ]; then
+    cluster_upgrade
   else
     cluster_up
   fi
@@ -51,45 +61,48 @@ function cluster_up() {
   python render_template.py $CORTEX_CLUSTER_CONFIG_FILE manifests/apis.yaml.j2 | kubectl apply -f - >/dev/null
   echo "✓"
 
-  echo -n "￮ configuring autoscaling "
-  python render_template.py $CORTEX_CLUSTER_CONFIG_FILE manifests/autoscaler.yaml.j2 | kubectl apply -f - >/dev/null
-  python render_template.py $CORTEX_CLUSTER_CONFIG_FILE manifests/activator.yaml.j2 | kubectl apply -f - >/dev/null
-  python render_template.py $CORTEX_CLUSTER_CONFIG_FILE manifests/cluster-autoscaler.yaml.j2 | kubectl apply -f - >/dev/null
-  echo "✓"
+  setup_cortex_components
 
-  echo -n "￮ configuring async gateway "
-  python render_template.py $CORTEX_CLUSTER_CONFIG_FILE manifests/async-gateway.yaml.j2 | kubectl apply -f - >/dev/null
-  echo "✓"
+  restart_operator
+  start_controller_manager
 
-  echo -n "￮ configuring logging "
-  python render_template.py $CORTEX_CLUSTER_CONFIG_FILE manifests/fluent-bit.yaml.j2 | kubectl apply -f - >/dev/null
-  envsubst < manifests/event-exporter.yaml | kubectl apply -f - >/dev/null
-  echo "✓"
+  validate_cortex
 
-  echo -n "￮ configuring metrics "
-  envsubst < manifests/metrics-server.yaml | kubectl apply -f - >/dev/null
-  setup_prometheus
-  setup_grafana
-  echo "✓"
+  echo -e "\ncortex is ready!"
+  if [ "$CORTEX_OPERATOR_LOAD_BALANCER_SCHEME" == "internal" ]; then
+    echo -e "\nnote: you will need to configure VPC Peering to connect to your cluster: https://docs.cortexlabs.com/v/${CORTEX_VERSION_MINOR}/"
+  fi
 
-  echo -n "￮ configuring gpu support (for nodegroups that may require it) "
-  envsubst < manifests/nvidia.yaml | kubectl apply -f - >/dev/null
-  NVIDIA_COM_GPU_VALUE=true envsubst < manifests/prometheus-dcgm-exporter.yaml | kubectl apply -f - >/dev/null
+  print_endpoints
+}
+
+function cluster_upgrade() {
+  check_eks
+
+  python generate_eks.py $CORTEX_CLUSTER_CONFIG_FILE manifests/ami.json > /workspace/eks.yaml
+
+  upgrade_eks
+
+  echo -n "￮ updating cluster configuration "
+  setup_configmap
   echo "✓"
 
-  echo -n "￮ configuring inf support (for nodegroups that may require it) "
-  envsubst < manifests/inferentia.yaml | kubectl apply -f - >/dev/null
+  echo -n "￮ updating networking "
+  python render_template.py $CORTEX_CLUSTER_CONFIG_FILE manifests/apis.yaml.j2 | kubectl apply -f - >/dev/null
   echo "✓"
 
+  setup_cortex_components
+
+  replace_outdated_nodegroups
+
+  rm /workspace/eks.yaml
+
   restart_operator
-  start_controller_manager
+  upgrade_controller_manager
 
   validate_cortex
 
   echo -e "\ncortex is ready!"
-  if [ "$CORTEX_OPERATOR_LOAD_BALANCER_SCHEME" == "internal" ]; then
-    echo -e "\nnote: you will need to configure VPC Peering to connect to your cluster: https://docs.cortexlabs.com/v/${CORTEX_VERSION_MINOR}/"
-  fi
 
   print_endpoints
 }
@@ -123,6 +136,203 @@ function cluster_configure() {
   print_endpoints
 }
 
+function cluster_scale() {
+  check_eks
+
+  resize_nodegroups
+
+  echo -n "￮ updating cluster configuration "
+  setup_configmap
+  echo "✓"
+
+  # this is necessary since max_instances may have been updated
+  echo -n "￮ configuring autoscaling "
+  python render_template.py $CORTEX_CLUSTER_CONFIG_FILE manifests/cluster-autoscaler.yaml.j2 | kubectl apply -f - >/dev/null
+  echo "✓"
+
+  restart_operator
+
+  echo -e "\nnodegroup $CORTEX_NODEGROUP_NAMES_TO_UPDATE has been scaled"
+}
+
+function cluster_update_nodegroups() {
+  check_eks
+
+  add_nodegroups
+  remove_nodegroups
+
+  echo -n "￮ updating cluster configuration "
+  setup_configmap
+  echo "✓"
+
+  echo -n "￮ configuring autoscaling "
+  python render_template.py $CORTEX_CLUSTER_CONFIG_FILE manifests/cluster-autoscaler.yaml.j2 | kubectl apply -f - >/dev/null
+  echo "✓"
+
+  restart_controller_manager
+
+  restart_operator
+
+  validate_cortex
+
+  echo -e "\ncortex is ready!"
+}
+
+function cluster_pause() {
+  check_eks
+
+  if kubectl -n=default get configmap cluster-paused >/dev/null 2>&1; then
+    echo "error: your cluster is already paused; run \`cortex cluster resume\` to resume it"
+    exit 1
+  fi
+
+  echo -n "￮ suspending operator "
+  kubectl -n=default scale deployment operator --replicas=0 >/dev/null
+  kubectl -n=default scale deployment operator-controller-manager --replicas=0 >/dev/null
+  echo "✓"
+
+  # the current nodegroup sizes are stored in the cluster so that they can be restored on resume
+  eksctl get nodegroup --cluster=$CORTEX_CLUSTER_NAME --region=$CORTEX_REGION --verbose=0 -o json > nodegroups.json
+  kubectl -n=default create configmap 'cluster-paused' \
+    --from-file='nodegroups.json'=nodegroups.json \
+    -o yaml --dry-run=client | kubectl apply -f - >/dev/null
+
+  eks_ng_len=$(cat nodegroups.json | jq -r length)
+  for eks_idx in $(seq 0 $(($eks_ng_len-1))); do
+    stack_ng=$(cat nodegroups.json | jq -r .[$eks_idx].Name)
+    echo "￮ nodegroup $stack_ng: scaling to 0 instances"
+    eksctl scale nodegroup --cluster=$CORTEX_CLUSTER_NAME --region=$CORTEX_REGION $stack_ng --nodes 0 --nodes-min 0 --nodes-max 0 --timeout "60m"
+    echo
+  done
+
+  rm nodegroups.json
+
+  echo "your cluster has been paused; run \`cortex cluster resume\` to resume it"
+}
+
+function cluster_resume() {
+  check_eks
+
+  if ! kubectl -n=default get configmap cluster-paused >/dev/null 2>&1; then
+    echo "error: your cluster is not paused"
+    exit 1
+  fi
+
+  kubectl -n=default get configmap cluster-paused -o json | jq -r '.data."nodegroups.json"' > nodegroups.json
+
+  eks_ng_len=$(cat nodegroups.json | jq -r length)
+  for eks_idx in $(seq 0 $(($eks_ng_len-1))); do
+    stack_ng=$(cat nodegroups.json | jq -r .[$eks_idx].Name)
+    desired=$(cat nodegroups.json | jq -r .[$eks_idx].DesiredCapacity)
+    min=$(cat nodegroups.json | jq -r .[$eks_idx].MinSize)
+    max=$(cat nodegroups.json | jq -r .[$eks_idx].MaxSize)
+    echo "￮ nodegroup $stack_ng: restoring min instances to $min and max instances to $max"
+    eksctl scale nodegroup --cluster=$CORTEX_CLUSTER_NAME --region=$CORTEX_REGION $stack_ng --nodes $desired --nodes-min $min --nodes-max $max --timeout "60m"
+    echo
+  done
+
+  rm nodegroups.json
+
+  echo -n "￮ resuming controller manager "
+  kubectl -n=default scale deployment operator-controller-manager --replicas=1 >/dev/null
+  echo "✓"
+
+  restart_operator
+
+  kubectl -n=default delete configmap cluster-paused >/dev/null
+
+  validate_cortex
+
+  echo -e "\ncortex is ready!"
+
+  print_endpoints
+}
+
+# applies the manifests of cortex's system components (safe to re-apply on an existing cluster)
+function setup_cortex_components() {
+  echo -n "￮ configuring autoscaling "
+  python render_template.py $CORTEX_CLUSTER_CONFIG_FILE manifests/autoscaler.yaml.j2 | kubectl apply -f - >/dev/null
+  python render_template.py $CORTEX_CLUSTER_CONFIG_FILE manifests/activator.yaml.j2 | kubectl apply -f - >/dev/null
+  python render_template.py $CORTEX_CLUSTER_CONFIG_FILE manifests/cluster-autoscaler.yaml.j2 | kubectl apply -f - >/dev/null
+  echo "✓"
+
+  echo -n "￮ configuring async gateway "
+  python render_template.py $CORTEX_CLUSTER_CONFIG_FILE manifests/async-gateway.yaml.j2 | kubectl apply -f - >/dev/null
+  echo "✓"
+
+  echo -n "￮ configuring logging "
+  python render_template.py $CORTEX_CLUSTER_CONFIG_FILE manifests/fluent-bit.yaml.j2 | kubectl apply -f - >/dev/null
+  envsubst < manifests/event-exporter.yaml | kubectl apply -f - >/dev/null
+  echo "✓"
+
+  echo -n "￮ configuring metrics "
+  envsubst < manifests/metrics-server.yaml | kubectl apply -f - >/dev/null
+  setup_prometheus
+  setup_grafana
+  echo "✓"
+
+  echo -n "￮ configuring gpu support (for nodegroups that may require it) "
+  envsubst < manifests/nvidia.yaml | kubectl apply -f - >/dev/null
+  NVIDIA_COM_GPU_VALUE=true envsubst < manifests/prometheus-dcgm-exporter.yaml | kubectl apply -f - >/dev/null
+  echo "✓"
+
+  echo -n "￮ configuring inf support (for nodegroups that may require it) "
+  envsubst < manifests/inferentia.yaml | kubectl apply -f - >/dev/null
+  echo "✓"
+}
+
+# upgrades the eks control plane (one minor version at a time) to the kubernetes version of this manager
+function upgrade_eks() {
+  target_version=$(cat /workspace/eks.yaml | yq -r .metadata.version)
+  current_version=$(eksctl get cluster --name=$CORTEX_CLUSTER_NAME --region=$CORTEX_REGION -o json | jq -r 'first | .Version')
+  upgraded="false"
+
+  # loop until current_version >= target_version
+  until [ "$(printf '%s\n' "$target_version" "$current_version" | sort -V | head -n1)" = "$target_version" ]; do
+    echo "￮ upgrading the kubernetes control plane from $current_version (this will take a while) "
+    eksctl upgrade cluster --name=$CORTEX_CLUSTER_NAME --region=$CORTEX_REGION --approve --timeout=$EKSCTL_CLUSTER_TIMEOUT
+    new_version=$(eksctl get cluster --name=$CORTEX_CLUSTER_NAME --region=$CORTEX_REGION -o json | jq -r 'first | .Version')
+    if [ "$new_version" = "$current_version" ]; then
+      echo "error: unable to upgrade the kubernetes control plane from version $current_version"
+      exit 1
+    fi
+    current_version=$new_version
+    upgraded="true"
+    echo
+  done
+
+  if [ "$upgraded" = "true" ]; then
+    echo "￮ updating kubernetes add-ons "
+    eksctl utils update-kube-proxy --cluster=$CORTEX_CLUSTER_NAME --region=$CORTEX_REGION --approve
+    eksctl utils update-aws-node --cluster=$CORTEX_CLUSTER_NAME --region=$CORTEX_REGION --approve
+    eksctl utils update-coredns --cluster=$CORTEX_CLUSTER_NAME --region=$CORTEX_REGION --approve
+    echo
+  fi
+}
+
+# replaces the nodegroups whose instances don't use the ami of this manager (one nodegroup at a time)
+function replace_outdated_nodegroups() {
+  eksctl get nodegroup --cluster=$CORTEX_CLUSTER_NAME --region=$CORTEX_REGION --verbose=0 -o json > nodegroups.json
+  eks_ng_len=$(cat nodegroups.json | jq -r length)
+
+  for eks_idx in $(seq 0 $(($eks_ng_len-1))); do
+    stack_ng=$(cat nodegroups.json | jq -r .[$eks_idx].Name)
+    current_ami=$(cat nodegroups.json | jq -r .[$eks_idx].ImageID)
+    desired_ami=$(cat /workspace/eks.yaml | yq -r ".nodeGroups[] | select(.name == \"$stack_ng\") | .ami")
+
+    if [ -z "$desired_ami" ] || [ "$desired_ami" = "null" ] || [ "$current_ami" = "$desired_ami" ]; then
+      continue
+    fi
+
+    echo "￮ nodegroup $stack_ng: replacing instances to use $desired_ami"
+    eksctl delete nodegroup --cluster=$CORTEX_CLUSTER_NAME --region=$CORTEX_REGION --name=$stack_ng --timeout=$EKSCTL_NODEGROUP_TIMEOUT --wait --approve
+    eksctl create nodegroup --timeout=$EKSCTL_NODEGROUP_TIMEOUT --install-neuron-plugin=false --install-nvidia-plugin=false --skip-outdated-addons-check --include=$stack_ng -f /workspace/eks.yaml
+    echo
+  done
+
+  rm nodegroups.json
+}
+
 # creates the eks cluster and configures kubectl
 function create_eks() {
   set +e
@@ -287,6 +497,17 @@ function start_controller_manager() {
   echo "✓"
 }
 
+function upgrade_controller_manager() {
+  echo -n "￮ upgrading controller manager "
+
+  cd config/manager \
+    && kustomize edit set image controller=${CORTEX_IMAGE_CONTROLLER_MANAGER} \
+    && cd ../.. > /dev/null
+
+  kustomize build config/default | kubectl apply -f - >/dev/null
+  echo "✓"
+}
+
 function restart_controller_manager() {
   echo -n "￮ restarting controller manager "
 
@@ -469,13 +690,18 @@ function update_networking() {
   if [ "$new_ssl_certificate_arn" != "$prev_ssl_certificate_arn" ] ; then
       # there is a bug where changing the certificate annotation will not cause the HTTPS listener in the NLB to update
       # the current workaround is to delete the HTTPS listener and have it recreated with istioctl
-      if [ "$prev_ssl_certificate_arn" != "" ] ; then
-        kubectl patch svc ingressgateway-apis -n=istio-system --type=json -p="[{'op': 'remove', 'path': '/metadata/annotations/service.beta.kubernetes.io~1aws-load-balancer-ssl-cert'}]" >/dev/null
-      fi
-      https_index=$(kubectl get svc ingressgateway-apis -n=istio-system -o json  | jq '.spec.ports | map(.name == "https") | index(true)')
-      if [ "$https_index" != "null" ] ; then
-        kubectl patch svc ingressgateway-apis -n=istio-system --type=json -p="[{'op': 'remove', 'path': '/spec/ports/$https_index'}]" >/dev/null
-      fi
+      for service in ingressgateway-apis ingressgateway-apis-internal; do
+        if ! kubectl get svc $service -n=istio-system >/dev/null 2>&1; then
+          continue
+        fi
+        if [ "$prev_ssl_certificate_arn" != "" ] ; then
+          kubectl patch svc $service -n=istio-system --type=json -p="[{'op': 'remove', 'path': '/metadata/annotations/service.beta.kubernetes.io~1aws-load-balancer-ssl-cert'}]" >/dev/null
+        fi
+        https_index=$(kubectl get svc $service -n=istio-system -o json  | jq '.spec.ports | map(.name == "https") | index(true)')
+        if [ "$https_index" != "null" ] ; then
+          kubectl patch svc $service -n=istio-system --type=json -p="[{'op': 'remove', 'path': '/spec/ports/$https_index'}]" >/dev/null
+        fi
+      done
   fi
 
   python render_template.py $CORTEX_CLUSTER_CONFIG_FILE manifests/istio.yaml.j2 > /workspace/istio.yaml
@@ -0,0 +1,26 @@
+#!/bin/bash
+
+# Copyright 2022 Cortex Labs, Inc.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+set -e
+
+# renders the operator, gateway, and ingress manifests that `cortex cluster up` would apply, without applying them
+
+render_dir="$1"
+mkdir -p "$render_dir"
+
+python render_template.py $CORTEX_CLUSTER_CONFIG_FILE manifests/operator.yaml.j2 > "$render_dir/operator.yaml"
+python render_template.py $CORTEX_CLUSTER_CONFIG_FILE manifests/istio.yaml.j2 > "$render_dir/gateway.yaml"
+python render_template.py $CORTEX_CLUSTER_CONFIG_FILE manifests/apis.yaml.j2 > "$render_dir/ingress.yaml"
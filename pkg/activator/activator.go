@@ -28,6 +28,7 @@ import (
 	"github.com/cortexlabs/cortex/pkg/types/userconfig"
 	"github.com/cortexlabs/cortex/pkg/workloads"
 	"go.uber.org/zap"
+	istioclientnetworking "istio.io/client-go/pkg/apis/networking/v1beta1"
 	istionetworkingclient "istio.io/client-go/pkg/clientset/versioned/typed/networking/v1beta1"
 	kapps "k8s.io/api/apps/v1"
 	kmeta "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -128,17 +129,38 @@ func (a *activator) getOrCreateAPIActivator(ctx context.Context, apiName string)
 		return act, nil
 	}
 
-	vs, err := a.istioClient.Get(ctx, workloads.K8sName(apiName), kmeta.GetOptions{})
+	// the api's virtual service is in the namespace of the api's project, so it's looked up by label across all namespaces
+	vsList, err := a.istioClient.List(ctx, kmeta.ListOptions{
+		LabelSelector: kmeta.FormatLabelSelector(&kmeta.LabelSelector{
+			MatchLabels: map[string]string{"apiName": apiName},
+		}),
+	})
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
 
+	var vs *istioclientnetworking.VirtualService
+	for i := range vsList.Items {
+		if vsList.Items[i].Name == workloads.K8sName(apiName) {
+			vs = vsList.Items[i]
+			break
+		}
+	}
+	if vs == nil {
+		return nil, errors.ErrorUnexpected("unable to find virtual service", apiName)
+	}
+
 	maxQueueLength, maxConcurrency, err := userconfig.ConcurrencyFromAnnotations(vs)
 	if err != nil {
 		return nil, err
 	}
 
-	apiAct := newAPIActivator(maxQueueLength, maxConcurrency)
+	maxQueueWait, err := maxQueueWaitFromAnnotations(vs)
+	if err != nil {
+		return nil, err
+	}
+
+	apiAct := newAPIActivator(maxQueueLength, maxConcurrency, maxQueueWait)
 
 	a.apiActivators[apiName] = apiAct
 
@@ -176,7 +198,7 @@ func (a *activator) addAPI(obj interface{}) {
 	a.activatorsMux.Lock()
 	if a.apiActivators[apiName] == nil {
 		a.logger.Debugw("adding new api activator", zap.String("apiName", apiName))
-		a.apiActivators[apiName] = newAPIActivator(apiMetadata.maxQueueLength, apiMetadata.maxConcurrency)
+		a.apiActivators[apiName] = newAPIActivator(apiMetadata.maxQueueLength, apiMetadata.maxConcurrency, apiMetadata.maxQueueWait)
 	}
 	a.activatorsMux.Unlock()
 
@@ -204,11 +226,13 @@ func (a *activator) updateAPI(oldObj interface{}, newObj interface{}) {
 		return
 	}
 
-	if oldAPIMetatada.maxConcurrency != apiMetadata.maxConcurrency || oldAPIMetatada.maxQueueLength != apiMetadata.maxQueueLength {
+	if oldAPIMetatada.maxConcurrency != apiMetadata.maxConcurrency ||
+		oldAPIMetatada.maxQueueLength != apiMetadata.maxQueueLength ||
+		oldAPIMetatada.maxQueueWait != apiMetadata.maxQueueWait {
 		a.logger.Debugw("updating api activator", zap.String("apiName", apiName))
 
 		a.activatorsMux.Lock()
-		a.apiActivators[apiName].updateQueueParams(apiMetadata.maxQueueLength, apiMetadata.maxConcurrency)
+		a.apiActivators[apiName].updateQueueParams(apiMetadata.maxQueueLength, apiMetadata.maxConcurrency, apiMetadata.maxQueueWait)
 		a.activatorsMux.Unlock()
 	}
 }
@@ -24,6 +24,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/cortexlabs/cortex/pkg/consts"
 	"github.com/cortexlabs/cortex/pkg/proxy"
 	"github.com/cortexlabs/cortex/pkg/types/userconfig"
 	"github.com/stretchr/testify/require"
@@ -62,7 +63,7 @@ func TestActivator_Try(t *testing.T) {
 	act := &activator{
 		autoscalerClient: autoscalerClientMock{},
 		apiActivators: map[string]*apiActivator{
-			apiName: newAPIActivator(1, 1),
+			apiName: newAPIActivator(1, 1, consts.WaitForReadyReplicasTimeout),
 		},
 		readinessTrackers: map[string]*readinessTracker{
 			apiName: {ready: true},
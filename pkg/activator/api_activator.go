@@ -21,34 +21,36 @@ package activator
 import (
 	"context"
 	"sync"
+	"sync/atomic"
+	"time"
 
-	"github.com/cortexlabs/cortex/pkg/consts"
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
 	"github.com/cortexlabs/cortex/pkg/proxy"
 	kapps "k8s.io/api/apps/v1"
 )
 
 type apiActivator struct {
-	breaker *proxy.Breaker
+	breaker      *proxy.Breaker
+	maxQueueWait int64 // time.Duration, accessed atomically
 }
 
-func newAPIActivator(maxQueueLength, maxConcurrency int) *apiActivator {
+func newAPIActivator(maxQueueLength, maxConcurrency int, maxQueueWait time.Duration) *apiActivator {
 	breaker := proxy.NewBreaker(proxy.BreakerParams{
 		QueueDepth:      maxQueueLength,
 		MaxConcurrency:  maxConcurrency,
 		InitialCapacity: maxConcurrency,
 	})
 
-	return &apiActivator{breaker: breaker}
+	return &apiActivator{breaker: breaker, maxQueueWait: int64(maxQueueWait)}
 }
 
 // try waits for the readinessTracker to be ready and then attempts to execute the passed callback.
-// If the readinessTracker does not reach a ready state, it will timeout.
+// If the readinessTracker does not reach a ready state within the api's max queue wait, it will timeout.
 func (a *apiActivator) try(ctx context.Context, fn func() error, tracker *readinessTracker) error {
 	var execErr error
 
 	if err := a.breaker.Maybe(ctx, func() {
-		ctx, cancel := context.WithTimeout(ctx, consts.WaitForReadyReplicasTimeout)
+		ctx, cancel := context.WithTimeout(ctx, time.Duration(atomic.LoadInt64(&a.maxQueueWait)))
 		defer cancel()
 
 		if !tracker.IsReady() {
@@ -72,9 +74,10 @@ func (a *apiActivator) try(ctx context.Context, fn func() error, tracker *readin
 }
 
 // updateQueueParams updates the breaker queue parameters (not thread safe)
-func (a *apiActivator) updateQueueParams(maxQueueLength, maxConcurrency int) {
+func (a *apiActivator) updateQueueParams(maxQueueLength, maxConcurrency int, maxQueueWait time.Duration) {
 	a.breaker.UpdateConcurrency(maxConcurrency)
 	a.breaker.UpdateQueueLength(maxQueueLength)
+	atomic.StoreInt64(&a.maxQueueWait, int64(maxQueueWait))
 }
 
 // inFlight returns the amount of in-flight requests of the breaker
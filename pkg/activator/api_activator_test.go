@@ -21,16 +21,19 @@ package activator
 import (
 	"context"
 	"testing"
+	"time"
 
+	"github.com/cortexlabs/cortex/pkg/consts"
 	"github.com/cortexlabs/cortex/pkg/proxy"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
+	kapps "k8s.io/api/apps/v1"
 )
 
 func TestApiActivator_Try(t *testing.T) {
 	t.Parallel()
 
-	act := newAPIActivator(1, 1)
+	act := newAPIActivator(1, 1, consts.WaitForReadyReplicasTimeout)
 
 	errCh := make(chan error)
 	waitCh := make(chan struct{})
@@ -54,3 +57,29 @@ func TestApiActivator_Try(t *testing.T) {
 		require.NoError(t, <-errCh)
 	}
 }
+
+func TestApiActivator_TryMaxQueueWait(t *testing.T) {
+	t.Parallel()
+
+	act := newAPIActivator(1, 1, 50*time.Millisecond)
+
+	err := act.try(context.Background(), func() error {
+		return nil
+	}, newReadinessTracker())
+	require.Error(t, err)
+	require.True(t, errors.Is(err, context.DeadlineExceeded))
+
+	act.updateQueueParams(1, 1, consts.WaitForReadyReplicasTimeout)
+
+	tracker := newReadinessTracker()
+	errCh := make(chan error)
+	go func() {
+		errCh <- act.try(context.Background(), func() error {
+			return nil
+		}, tracker)
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+	tracker.Update(&kapps.Deployment{Status: kapps.DeploymentStatus{ReadyReplicas: 1}})
+	require.NoError(t, <-errCh)
+}
@@ -36,7 +36,7 @@ func TestActivatorHandler_ServeHTTP(t *testing.T) {
 	act := &activator{
 		autoscalerClient: autoscalerClientMock{},
 		apiActivators: map[string]*apiActivator{
-			apiName: newAPIActivator(1, 1),
+			apiName: newAPIActivator(1, 1, consts.WaitForReadyReplicasTimeout),
 		},
 		readinessTrackers: map[string]*readinessTracker{
 			apiName: {ready: true},
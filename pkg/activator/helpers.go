@@ -19,9 +19,13 @@ Modifications Copyright 2022 Cortex Labs, Inc.
 package activator
 
 import (
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/consts"
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
 	"github.com/cortexlabs/cortex/pkg/types/userconfig"
 	"k8s.io/apimachinery/pkg/api/meta"
+	kmeta "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 type apiMeta struct {
@@ -31,6 +35,7 @@ type apiMeta struct {
 	annotations    map[string]string
 	maxConcurrency int
 	maxQueueLength int
+	maxQueueWait   time.Duration
 }
 
 func getAPIMeta(obj interface{}) (apiMeta, error) {
@@ -55,6 +60,11 @@ func getAPIMeta(obj interface{}) (apiMeta, error) {
 		return apiMeta{}, err
 	}
 
+	maxQueueWait, err := maxQueueWaitFromAnnotations(resource)
+	if err != nil {
+		return apiMeta{}, err
+	}
+
 	return apiMeta{
 		apiName:        apiName,
 		apiKind:        userconfig.KindFromString(apiKind),
@@ -62,5 +72,18 @@ func getAPIMeta(obj interface{}) (apiMeta, error) {
 		annotations:    resource.GetAnnotations(),
 		maxConcurrency: maxConcurrency,
 		maxQueueLength: maxQueueLength,
+		maxQueueWait:   maxQueueWait,
 	}, nil
 }
+
+// maxQueueWaitFromAnnotations returns the api's max_queue_wait, or the default wait for ready replicas if unset
+func maxQueueWaitFromAnnotations(obj kmeta.Object) (time.Duration, error) {
+	maxQueueWait, err := userconfig.MaxQueueWaitFromAnnotations(obj)
+	if err != nil {
+		return 0, err
+	}
+	if maxQueueWait == nil {
+		return consts.WaitForReadyReplicasTimeout, nil
+	}
+	return *maxQueueWait, nil
+}
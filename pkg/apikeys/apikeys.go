@@ -0,0 +1,66 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apikeys
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/slices"
+)
+
+const (
+	// SecretName is the kubernetes secret in which api keys are stored (one entry per key, keyed by the key's name)
+	SecretName = "api-keys"
+
+	// Header is the request header in which clients send their api key
+	Header = "X-Api-Key"
+
+	_keyPrefix   = "ck_"
+	_keyNumBytes = 32
+)
+
+// Key is an api key as it is stored in the cluster; only the hash of the key itself is stored
+type Key struct {
+	Name      string    `json:"name"`
+	Hash      string    `json:"hash"`
+	APIs      []string  `json:"apis"` // if empty, the key grants access to all apis
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Generate returns a new random api key
+func Generate() (string, error) {
+	keyBytes := make([]byte, _keyNumBytes)
+	if _, err := rand.Read(keyBytes); err != nil {
+		return "", errors.WithStack(err)
+	}
+	return _keyPrefix + hex.EncodeToString(keyBytes), nil
+}
+
+// Hash returns the hex-encoded sha256 hash of an api key (keys are randomly generated with 256 bits of entropy, so a slow hash is not necessary)
+func Hash(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// CanAccess returns whether the key grants access to the api
+func (k *Key) CanAccess(apiName string) bool {
+	return len(k.APIs) == 0 || slices.HasString(k.APIs, apiName)
+}
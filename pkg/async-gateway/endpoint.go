@@ -20,6 +20,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/cortexlabs/cortex/pkg/consts"
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
@@ -65,6 +66,12 @@ func (e *Endpoint) CreateWorkload(w http.ResponseWriter, r *http.Request) {
 	}
 	r.Header.Del(consts.CortexQueueURLHeader)
 
+	options, err := parseWorkloadOptions(r.Header)
+	if err != nil {
+		respondPlainText(w, http.StatusBadRequest, fmt.Sprintf("error: %v", err))
+		return
+	}
+
 	body := r.Body
 	defer func() {
 		_ = r.Body.Close()
@@ -72,7 +79,7 @@ func (e *Endpoint) CreateWorkload(w http.ResponseWriter, r *http.Request) {
 
 	log := e.logger.With(zap.String("id", requestID), zap.String("apiName", apiName))
 
-	id, err := e.service.CreateWorkload(requestID, apiName, queueURL, body, r.Header)
+	id, err := e.service.CreateWorkload(requestID, apiName, queueURL, options, body, r.Header)
 	if err != nil {
 		respondPlainText(w, http.StatusInternalServerError, fmt.Sprintf("error: %v", err))
 		logErrorWithTelemetry(log, errors.Wrap(err, "failed to create workload"))
@@ -115,12 +122,44 @@ func (e *Endpoint) GetWorkload(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if res.Status == async.StatusExpired {
+		respondPlainText(w, http.StatusGone, fmt.Sprintf("error: the result of id %s has expired", res.ID))
+		return
+	}
+
 	if err = respondJSON(w, http.StatusOK, res); err != nil {
 		logErrorWithTelemetry(log, errors.Wrap(err, "failed to encode json response"))
 		return
 	}
 }
 
+// parseWorkloadOptions reads the workload options from the headers which are set by the api's virtual service, and removes them from the headers
+func parseWorkloadOptions(headers http.Header) (WorkloadOptions, error) {
+	options := WorkloadOptions{
+		IdempotencyKey: headers.Get(consts.IdempotencyKeyHeader),
+	}
+
+	if resultTTLStr := headers.Get(consts.CortexResultTTLHeader); resultTTLStr != "" {
+		resultTTL, err := time.ParseDuration(resultTTLStr)
+		if err != nil {
+			return WorkloadOptions{}, fmt.Errorf("invalid %s header value: %s", consts.CortexResultTTLHeader, resultTTLStr)
+		}
+		options.ResultTTL = &resultTTL
+	}
+	headers.Del(consts.CortexResultTTLHeader)
+
+	if idempotencyWindowStr := headers.Get(consts.CortexIdempotencyWindowHeader); idempotencyWindowStr != "" {
+		idempotencyWindow, err := time.ParseDuration(idempotencyWindowStr)
+		if err != nil {
+			return WorkloadOptions{}, fmt.Errorf("invalid %s header value: %s", consts.CortexIdempotencyWindowHeader, idempotencyWindowStr)
+		}
+		options.IdempotencyWindow = idempotencyWindow
+	}
+	headers.Del(consts.CortexIdempotencyWindowHeader)
+
+	return options, nil
+}
+
 func respondPlainText(w http.ResponseWriter, statusCode int, message string) {
 	w.Header().Set("Content-Type", "text/plain")
 	w.WriteHeader(statusCode)
@@ -0,0 +1,239 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+//go:generate protoc --proto_path=pb --go_out=pb --go_opt=paths=source_relative --go-grpc_out=pb --go-grpc_opt=paths=source_relative async_gateway.proto
+
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/async-gateway/pb"
+	"github.com/cortexlabs/cortex/pkg/consts"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/sets/strset"
+	"github.com/cortexlabs/cortex/pkg/types/async"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	_defaultContentType = "application/json"
+	_resultPollInterval = time.Second
+)
+
+// metadata keys which are specific to the grpc transport, and therefore aren't forwarded to the api's containers
+var _grpcMetadataKeys = strset.New("content-type", "te", "user-agent")
+
+// GRPCServer wraps an async-gateway Service with gRPC logic
+type GRPCServer struct {
+	pb.UnimplementedAsyncGatewayServer
+	service Service
+	logger  *zap.SugaredLogger
+}
+
+// NewGRPCServer creates and initializes a new GRPCServer struct
+func NewGRPCServer(svc Service, logger *zap.SugaredLogger) *GRPCServer {
+	return &GRPCServer{
+		service: svc,
+		logger:  logger,
+	}
+}
+
+// Submit is a handler for the async-gateway service workload creation rpc
+func (s *GRPCServer) Submit(ctx context.Context, req *pb.SubmitRequest) (*pb.SubmitResponse, error) {
+	headers := incomingHeaders(ctx)
+
+	requestID := headers.Get("x-request-id")
+	if requestID == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing x-request-id key in request metadata")
+	}
+
+	apiName, err := popRequiredHeader(headers, consts.CortexAPINameHeader)
+	if err != nil {
+		return nil, err
+	}
+
+	queueURL, err := popRequiredHeader(headers, consts.CortexQueueURLHeader)
+	if err != nil {
+		return nil, err
+	}
+
+	options, err := parseWorkloadOptions(headers)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if req.IdempotencyKey != "" {
+		options.IdempotencyKey = req.IdempotencyKey
+	}
+
+	contentType := req.ContentType
+	if contentType == "" {
+		contentType = _defaultContentType
+	}
+	headers.Set("Content-Type", contentType)
+
+	log := s.logger.With(zap.String("id", requestID), zap.String("apiName", apiName))
+
+	id, err := s.service.CreateWorkload(requestID, apiName, queueURL, options, bytes.NewReader(req.Payload), headers)
+	if err != nil {
+		logErrorWithTelemetry(log, errors.Wrap(err, "failed to create workload"))
+		return nil, status.Errorf(codes.Internal, "%v", err)
+	}
+
+	return &pb.SubmitResponse{Id: id}, nil
+}
+
+// GetStatus is a handler for the async-gateway service workload status rpc
+func (s *GRPCServer) GetStatus(ctx context.Context, req *pb.GetStatusRequest) (*pb.GetStatusResponse, error) {
+	apiName, err := popRequiredHeader(incomingHeaders(ctx), consts.CortexAPINameHeader)
+	if err != nil {
+		return nil, err
+	}
+
+	log := s.logger.With(zap.String("id", req.Id), zap.String("apiName", apiName))
+
+	st, err := s.service.GetStatus(req.Id, apiName)
+	if err != nil {
+		logErrorWithTelemetry(log, errors.Wrap(err, "failed to get workload status"))
+		return nil, status.Errorf(codes.Internal, "%v", err)
+	}
+	if err = workloadStatusError(req.Id, st); err != nil {
+		return nil, err
+	}
+
+	return &pb.GetStatusResponse{Id: req.Id, Status: string(st)}, nil
+}
+
+// GetResult is a handler for the async-gateway service workload result rpc; it streams the workload's status
+// whenever it changes, and returns once the workload has completed (including its result) or failed
+func (s *GRPCServer) GetResult(req *pb.GetResultRequest, stream pb.AsyncGateway_GetResultServer) error {
+	ctx := stream.Context()
+
+	apiName, err := popRequiredHeader(incomingHeaders(ctx), consts.CortexAPINameHeader)
+	if err != nil {
+		return err
+	}
+
+	log := s.logger.With(zap.String("id", req.Id), zap.String("apiName", apiName))
+
+	ticker := time.NewTicker(_resultPollInterval)
+	defer ticker.Stop()
+
+	var lastStatus async.Status
+	for {
+		res, err := s.service.GetWorkload(req.Id, apiName)
+		if err != nil {
+			logErrorWithTelemetry(log, errors.Wrap(err, "failed to get workload"))
+			return status.Errorf(codes.Internal, "%v", err)
+		}
+		if err = workloadStatusError(req.Id, res.Status); err != nil {
+			return err
+		}
+
+		if res.Status != lastStatus {
+			msg, err := getResultResponse(res)
+			if err != nil {
+				logErrorWithTelemetry(log, errors.Wrap(err, "failed to encode workload result"))
+				return status.Errorf(codes.Internal, "%v", err)
+			}
+			if err = stream.Send(msg); err != nil {
+				return err
+			}
+			lastStatus = res.Status
+		}
+
+		if res.Status == async.StatusCompleted || res.Status == async.StatusFailed {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func getResultResponse(res GetWorkloadResponse) (*pb.GetResultResponse, error) {
+	msg := &pb.GetResultResponse{
+		Id:     res.ID,
+		Status: string(res.Status),
+	}
+
+	if res.Result != nil {
+		result, err := structpb.NewStruct(*res.Result)
+		if err != nil {
+			return nil, err
+		}
+		msg.Result = result
+	}
+
+	if res.Timestamp != nil {
+		msg.Timestamp = timestamppb.New(*res.Timestamp)
+	}
+
+	return msg, nil
+}
+
+func workloadStatusError(id string, st async.Status) error {
+	switch st {
+	case async.StatusNotFound:
+		return status.Errorf(codes.NotFound, "id %s not found", id)
+	case async.StatusExpired:
+		return status.Errorf(codes.NotFound, "the result of id %s has expired", id)
+	}
+	return nil
+}
+
+// incomingHeaders converts the request's metadata (which includes the headers that are set by the api's virtual service) to http headers
+func incomingHeaders(ctx context.Context) http.Header {
+	headers := http.Header{}
+
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return headers
+	}
+
+	for key, values := range md {
+		if strings.HasPrefix(key, ":") || strings.HasPrefix(key, "grpc-") || strings.HasSuffix(key, "-bin") || _grpcMetadataKeys.Has(key) {
+			continue
+		}
+		for _, value := range values {
+			headers.Add(key, value)
+		}
+	}
+
+	return headers
+}
+
+func popRequiredHeader(headers http.Header, key string) (string, error) {
+	value := headers.Get(key)
+	if value == "" {
+		return "", status.Error(codes.InvalidArgument, fmt.Sprintf("missing %s key in request metadata", strings.ToLower(key)))
+	}
+	headers.Del(key)
+	return value, nil
+}
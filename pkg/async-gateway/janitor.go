@@ -0,0 +1,160 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gateway
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/types/async"
+	"go.uber.org/zap"
+)
+
+// Janitor periodically deletes the payloads and results of workloads whose result ttl has elapsed
+type Janitor struct {
+	clusterUID string
+	storage    Storage
+	interval   time.Duration
+	logger     *zap.SugaredLogger
+}
+
+type workloadObjects struct {
+	apiName    string
+	id         string
+	keys       []string
+	resultTTL  *time.Duration
+	finishedAt *time.Time
+	expired    bool
+}
+
+// NewJanitor creates a new async-gateway janitor
+func NewJanitor(clusterUID string, storage Storage, interval time.Duration, logger *zap.SugaredLogger) *Janitor {
+	return &Janitor{
+		clusterUID: clusterUID,
+		storage:    storage,
+		interval:   interval,
+		logger:     logger,
+	}
+}
+
+// Run cleans up expired workloads every interval until the stop channel is closed
+func (j *Janitor) Run(stopCh <-chan struct{}) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+			if err := j.cleanup(time.Now()); err != nil {
+				logErrorWithTelemetry(j.logger, errors.Wrap(err, "failed to clean up expired workloads"))
+			}
+		}
+	}
+}
+
+func (j *Janitor) cleanup(now time.Time) error {
+	workloadsPrefix := fmt.Sprintf("%s/workloads", j.clusterUID)
+
+	objects, err := j.storage.ListObjects(workloadsPrefix)
+	if err != nil {
+		return err
+	}
+
+	for _, workload := range groupWorkloadObjects(workloadsPrefix, objects) {
+		if workload.expired || workload.resultTTL == nil || workload.finishedAt == nil {
+			continue
+		}
+		if now.Before(workload.finishedAt.Add(*workload.resultTTL)) {
+			continue
+		}
+
+		log := j.logger.With(zap.String("id", workload.id), zap.String("apiName", workload.apiName))
+		if err := j.expireWorkload(workload); err != nil {
+			logErrorWithTelemetry(log, errors.Wrap(err, "failed to delete expired workload"))
+			continue
+		}
+		log.Debug("deleted expired workload")
+	}
+
+	return nil
+}
+
+func (j *Janitor) expireWorkload(workload *workloadObjects) error {
+	prefix := async.StoragePath(j.clusterUID, workload.apiName)
+
+	// the marker is kept so that requests for the workload respond with 410 instead of 404
+	expiredPath := async.StatusPath(prefix, workload.id, async.StatusExpired)
+	if err := j.storage.Upload(expiredPath, strings.NewReader(""), "text/plain"); err != nil {
+		return errors.Wrap(err, "failed to upload workload status")
+	}
+
+	return j.storage.Delete(workload.keys)
+}
+
+// groupWorkloadObjects groups the objects under <cluster_uid>/workloads by api name and workload id
+func groupWorkloadObjects(workloadsPrefix string, objects []Object) []*workloadObjects {
+	workloads := map[string]*workloadObjects{}
+	var ordered []*workloadObjects
+
+	for _, obj := range objects {
+		// <api_name>/<id>/<file path>
+		parts := strings.SplitN(strings.TrimPrefix(obj.Key, workloadsPrefix+"/"), "/", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		apiName, id, filePath := parts[0], parts[1], parts[2]
+		if id == async.IdempotencyKeysDir {
+			continue // idempotency keys are deleted by the bucket's lifecycle policy
+		}
+
+		workloadKey := apiName + "/" + id
+		workload, ok := workloads[workloadKey]
+		if !ok {
+			workload = &workloadObjects{apiName: apiName, id: id}
+			workloads[workloadKey] = workload
+			ordered = append(ordered, workload)
+		}
+		workload.keys = append(workload.keys, obj.Key)
+
+		dir, file := path.Split(filePath)
+		switch dir {
+		case "status/":
+			switch async.Status(file) {
+			case async.StatusCompleted, async.StatusFailed:
+				lastModified := obj.LastModified
+				workload.finishedAt = &lastModified
+			case async.StatusExpired:
+				workload.expired = true
+			}
+		case "result_ttl/":
+			seconds, err := strconv.ParseInt(file, 10, 64)
+			if err != nil {
+				continue
+			}
+			resultTTL := time.Duration(seconds) * time.Second
+			workload.resultTTL = &resultTTL
+		}
+	}
+
+	return ordered
+}
@@ -0,0 +1,211 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             v3.21.12
+// source: async_gateway.proto
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// AsyncGatewayClient is the client API for AsyncGateway service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type AsyncGatewayClient interface {
+	// Submit enqueues a workload and responds with its ID
+	Submit(ctx context.Context, in *SubmitRequest, opts ...grpc.CallOption) (*SubmitResponse, error)
+	// GetStatus responds with the status of a workload
+	GetStatus(ctx context.Context, in *GetStatusRequest, opts ...grpc.CallOption) (*GetStatusResponse, error)
+	// GetResult streams the status of a workload whenever it changes, until the workload has completed or failed
+	GetResult(ctx context.Context, in *GetResultRequest, opts ...grpc.CallOption) (AsyncGateway_GetResultClient, error)
+}
+
+type asyncGatewayClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAsyncGatewayClient(cc grpc.ClientConnInterface) AsyncGatewayClient {
+	return &asyncGatewayClient{cc}
+}
+
+func (c *asyncGatewayClient) Submit(ctx context.Context, in *SubmitRequest, opts ...grpc.CallOption) (*SubmitResponse, error) {
+	out := new(SubmitResponse)
+	err := c.cc.Invoke(ctx, "/asyncgateway.AsyncGateway/Submit", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *asyncGatewayClient) GetStatus(ctx context.Context, in *GetStatusRequest, opts ...grpc.CallOption) (*GetStatusResponse, error) {
+	out := new(GetStatusResponse)
+	err := c.cc.Invoke(ctx, "/asyncgateway.AsyncGateway/GetStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *asyncGatewayClient) GetResult(ctx context.Context, in *GetResultRequest, opts ...grpc.CallOption) (AsyncGateway_GetResultClient, error) {
+	stream, err := c.cc.NewStream(ctx, &AsyncGateway_ServiceDesc.Streams[0], "/asyncgateway.AsyncGateway/GetResult", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &asyncGatewayGetResultClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type AsyncGateway_GetResultClient interface {
+	Recv() (*GetResultResponse, error)
+	grpc.ClientStream
+}
+
+type asyncGatewayGetResultClient struct {
+	grpc.ClientStream
+}
+
+func (x *asyncGatewayGetResultClient) Recv() (*GetResultResponse, error) {
+	m := new(GetResultResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// AsyncGatewayServer is the server API for AsyncGateway service.
+// All implementations must embed UnimplementedAsyncGatewayServer
+// for forward compatibility
+type AsyncGatewayServer interface {
+	// Submit enqueues a workload and responds with its ID
+	Submit(context.Context, *SubmitRequest) (*SubmitResponse, error)
+	// GetStatus responds with the status of a workload
+	GetStatus(context.Context, *GetStatusRequest) (*GetStatusResponse, error)
+	// GetResult streams the status of a workload whenever it changes, until the workload has completed or failed
+	GetResult(*GetResultRequest, AsyncGateway_GetResultServer) error
+	mustEmbedUnimplementedAsyncGatewayServer()
+}
+
+// UnimplementedAsyncGatewayServer must be embedded to have forward compatible implementations.
+type UnimplementedAsyncGatewayServer struct {
+}
+
+func (UnimplementedAsyncGatewayServer) Submit(context.Context, *SubmitRequest) (*SubmitResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Submit not implemented")
+}
+func (UnimplementedAsyncGatewayServer) GetStatus(context.Context, *GetStatusRequest) (*GetStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStatus not implemented")
+}
+func (UnimplementedAsyncGatewayServer) GetResult(*GetResultRequest, AsyncGateway_GetResultServer) error {
+	return status.Errorf(codes.Unimplemented, "method GetResult not implemented")
+}
+func (UnimplementedAsyncGatewayServer) mustEmbedUnimplementedAsyncGatewayServer() {}
+
+// UnsafeAsyncGatewayServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AsyncGatewayServer will
+// result in compilation errors.
+type UnsafeAsyncGatewayServer interface {
+	mustEmbedUnimplementedAsyncGatewayServer()
+}
+
+func RegisterAsyncGatewayServer(s grpc.ServiceRegistrar, srv AsyncGatewayServer) {
+	s.RegisterService(&AsyncGateway_ServiceDesc, srv)
+}
+
+func _AsyncGateway_Submit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SubmitRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AsyncGatewayServer).Submit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/asyncgateway.AsyncGateway/Submit",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AsyncGatewayServer).Submit(ctx, req.(*SubmitRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AsyncGateway_GetStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AsyncGatewayServer).GetStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/asyncgateway.AsyncGateway/GetStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AsyncGatewayServer).GetStatus(ctx, req.(*GetStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AsyncGateway_GetResult_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetResultRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AsyncGatewayServer).GetResult(m, &asyncGatewayGetResultServer{stream})
+}
+
+type AsyncGateway_GetResultServer interface {
+	Send(*GetResultResponse) error
+	grpc.ServerStream
+}
+
+type asyncGatewayGetResultServer struct {
+	grpc.ServerStream
+}
+
+func (x *asyncGatewayGetResultServer) Send(m *GetResultResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// AsyncGateway_ServiceDesc is the grpc.ServiceDesc for AsyncGateway service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AsyncGateway_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "asyncgateway.AsyncGateway",
+	HandlerType: (*AsyncGatewayServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Submit",
+			Handler:    _AsyncGateway_Submit_Handler,
+		},
+		{
+			MethodName: "GetStatus",
+			Handler:    _AsyncGateway_GetStatus_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "GetResult",
+			Handler:       _AsyncGateway_GetResult_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "async_gateway.proto",
+}
@@ -23,19 +23,25 @@ import (
 	"io"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws/session"
+	awslib "github.com/cortexlabs/cortex/pkg/lib/aws"
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/hash"
 	"github.com/cortexlabs/cortex/pkg/types/async"
 	"go.uber.org/zap"
 )
 
 // Service provides an interface to the async-gateway business logic
 type Service interface {
-	CreateWorkload(id string, apiName string, queueURL string, payload io.Reader, headers http.Header) (string, error)
+	CreateWorkload(id string, apiName string, queueURL string, options WorkloadOptions, payload io.Reader, headers http.Header) (string, error)
 	GetWorkload(id string, apiName string) (GetWorkloadResponse, error)
+	GetStatus(id string, apiName string) (async.Status, error)
 }
 
+const _maxIdempotencyKeyClaimAttempts = 3
+
 type service struct {
 	logger     *zap.SugaredLogger
 	storage    Storage
@@ -53,11 +59,39 @@ func NewService(clusterUID string, storage Storage, logger *zap.SugaredLogger, s
 	}
 }
 
-// CreateWorkload enqueues an async workload request and uploads the request payload to S3
-func (s *service) CreateWorkload(id string, apiName string, queueURL string, payload io.Reader, headers http.Header) (string, error) {
+// CreateWorkload enqueues an async workload request and uploads the request payload to S3;
+// if a workload with the same idempotency key was submitted within the idempotency window, its ID is returned instead
+func (s *service) CreateWorkload(id string, apiName string, queueURL string, options WorkloadOptions, payload io.Reader, headers http.Header) (string, error) {
 	prefix := async.StoragePath(s.clusterUID, apiName)
 	log := s.logger.With(zap.String("id", id), zap.String("apiName", apiName))
 
+	if options.IdempotencyKey == "" || options.IdempotencyWindow == 0 {
+		return s.createWorkload(prefix, id, queueURL, options, payload, headers, log)
+	}
+
+	idempotencyKeyPath := async.IdempotencyKeyPath(prefix, hash.String(options.IdempotencyKey))
+	existingID, err := s.claimIdempotencyKey(idempotencyKeyPath, id, options.IdempotencyWindow)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to claim idempotency key")
+	}
+	if existingID != "" {
+		log.Debugw("found workload with the same idempotency key", zap.String("existingID", existingID))
+		return existingID, nil
+	}
+
+	workloadID, err := s.createWorkload(prefix, id, queueURL, options, payload, headers, log)
+	if err != nil {
+		// release the key so that the request can be retried
+		if deleteErr := s.storage.Delete([]string{idempotencyKeyPath}); deleteErr != nil {
+			log.Errorw("failed to release idempotency key", zap.Error(deleteErr))
+		}
+		return "", err
+	}
+
+	return workloadID, nil
+}
+
+func (s *service) createWorkload(prefix string, id string, queueURL string, options WorkloadOptions, payload io.Reader, headers http.Header, log *zap.SugaredLogger) (string, error) {
 	buf := &bytes.Buffer{}
 	if err := json.NewEncoder(buf).Encode(headers); err != nil {
 		return "", errors.Wrap(err, "failed to dump headers")
@@ -76,6 +110,14 @@ func (s *service) CreateWorkload(id string, apiName string, queueURL string, pay
 		return "", errors.Wrap(err, "failed to upload payload")
 	}
 
+	if options.ResultTTL != nil {
+		resultTTLPath := async.ResultTTLPath(prefix, id, *options.ResultTTL)
+		log.Debugw("uploading result ttl", zap.String("path", resultTTLPath))
+		if err := s.storage.Upload(resultTTLPath, strings.NewReader(""), "text/plain"); err != nil {
+			return "", errors.Wrap(err, "failed to upload result ttl")
+		}
+	}
+
 	log.Debug("sending message to queue")
 	queue := NewSQS(queueURL, &s.session)
 	if err := queue.SendMessage(id, id); err != nil {
@@ -91,11 +133,47 @@ func (s *service) CreateWorkload(id string, apiName string, queueURL string, pay
 	return id, nil
 }
 
+// claimIdempotencyKey stores the workload ID at the idempotency key's path, unless a workload with the same key
+// was submitted within the window, in which case that workload's ID is returned
+func (s *service) claimIdempotencyKey(idempotencyKeyPath string, id string, window time.Duration) (string, error) {
+	for i := 0; i < _maxIdempotencyKeyClaimAttempts; i++ {
+		created, err := s.storage.UploadIfNoneMatch(idempotencyKeyPath, []byte(id), "text/plain")
+		if err != nil {
+			return "", err
+		}
+		if created {
+			return "", nil
+		}
+
+		existingID, obj, err := s.storage.GetObject(idempotencyKeyPath)
+		if err != nil {
+			if awslib.IsNoSuchKeyErr(err) {
+				continue // the key was released in the meantime
+			}
+			return "", err
+		}
+		if time.Since(obj.LastModified) < window {
+			return string(existingID), nil
+		}
+
+		// the key is outside of the window, so it can be reused (unless another request has already replaced it)
+		replaced, err := s.storage.UploadIfMatch(idempotencyKeyPath, obj.ETag, []byte(id), "text/plain")
+		if err != nil {
+			return "", err
+		}
+		if replaced {
+			return "", nil
+		}
+	}
+
+	return "", errors.ErrorUnexpected("unable to claim idempotency key due to concurrent requests")
+}
+
 // GetWorkload retrieves the status and result, if available, of a given workload
 func (s *service) GetWorkload(id string, apiName string) (GetWorkloadResponse, error) {
 	log := s.logger.With(zap.String("id", id), zap.String("apiName", apiName))
 
-	st, err := s.getStatus(id, apiName)
+	st, err := s.GetStatus(id, apiName)
 	if err != nil {
 		return GetWorkloadResponse{}, err
 	}
@@ -135,7 +213,8 @@ func (s *service) GetWorkload(id string, apiName string) (GetWorkloadResponse, e
 	}, nil
 }
 
-func (s *service) getStatus(id string, apiName string) (async.Status, error) {
+// GetStatus retrieves the status of a given workload
+func (s *service) GetStatus(id string, apiName string) (async.Status, error) {
 	prefix := async.StoragePath(s.clusterUID, apiName)
 	log := s.logger.With(zap.String("id", id))
 
@@ -150,6 +229,13 @@ func (s *service) getStatus(id string, apiName string) (async.Status, error) {
 		return async.StatusNotFound, nil
 	}
 
+	// the expired marker is uploaded before the workload's other files are deleted, so it takes precedence
+	for _, file := range files {
+		if async.Status(file) == async.StatusExpired {
+			return async.StatusExpired, nil
+		}
+	}
+
 	// determine request status
 	st := async.StatusInQueue
 	for _, file := range files {
@@ -17,15 +17,19 @@ limitations under the License.
 package gateway
 
 import (
+	"bytes"
 	"io"
+	"io/ioutil"
 	"path"
 	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/aws/session"
 	awss3 "github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	awslib "github.com/cortexlabs/cortex/pkg/lib/aws"
 )
 
 // Storage is an interface that abstracts cloud storage uploading
@@ -34,8 +38,23 @@ type Storage interface {
 	Download(key string) ([]byte, error)
 	List(key string) ([]string, error)
 	GetLastModified(key string) (time.Time, error)
+	ListObjects(prefix string) ([]Object, error)
+	Delete(keys []string) error
+	GetObject(key string) ([]byte, Object, error)
+	UploadIfNoneMatch(key string, content []byte, contentType string) (bool, error)
+	UploadIfMatch(key string, etag string, content []byte, contentType string) (bool, error)
 }
 
+// Object describes a stored object
+type Object struct {
+	Key          string
+	LastModified time.Time
+	ETag         string
+}
+
+// S3 allows up to 1000 keys per DeleteObjects request
+const _maxKeysPerDelete = 1000
+
 type s3 struct {
 	uploader   *s3manager.Uploader
 	downloader *s3manager.Downloader
@@ -120,3 +139,118 @@ func (s *s3) GetLastModified(key string) (time.Time, error) {
 
 	return *obj.LastModified, nil
 }
+
+// ListObjects lists all of the objects (recursively) under a given S3 path
+func (s *s3) ListObjects(prefix string) ([]Object, error) {
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+
+	var objects []Object
+	err := s.client.ListObjectsV2Pages(
+		&awss3.ListObjectsV2Input{
+			Prefix: aws.String(prefix),
+			Bucket: aws.String(s.bucket),
+		},
+		func(page *awss3.ListObjectsV2Output, lastPage bool) bool {
+			for _, obj := range page.Contents {
+				objects = append(objects, Object{
+					Key:          *obj.Key,
+					LastModified: *obj.LastModified,
+				})
+			}
+			return true
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return objects, nil
+}
+
+// Delete deletes a set of objects from S3
+func (s *s3) Delete(keys []string) error {
+	for start := 0; start < len(keys); start += _maxKeysPerDelete {
+		end := start + _maxKeysPerDelete
+		if end > len(keys) {
+			end = len(keys)
+		}
+
+		objects := make([]*awss3.ObjectIdentifier, 0, end-start)
+		for _, key := range keys[start:end] {
+			objects = append(objects, &awss3.ObjectIdentifier{Key: aws.String(key)})
+		}
+
+		_, err := s.client.DeleteObjects(&awss3.DeleteObjectsInput{
+			Bucket: aws.String(s.bucket),
+			Delete: &awss3.Delete{
+				Objects: objects,
+				Quiet:   aws.Bool(true),
+			},
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetObject downloads an S3 object into memory, along with its metadata
+func (s *s3) GetObject(key string) ([]byte, Object, error) {
+	obj, err := s.client.GetObject(&awss3.GetObjectInput{
+		Key:    aws.String(key),
+		Bucket: aws.String(s.bucket),
+	})
+	if err != nil {
+		return nil, Object{}, err
+	}
+	defer func() {
+		_ = obj.Body.Close()
+	}()
+
+	content, err := ioutil.ReadAll(obj.Body)
+	if err != nil {
+		return nil, Object{}, err
+	}
+
+	return content, Object{
+		Key:          key,
+		LastModified: aws.TimeValue(obj.LastModified),
+		ETag:         aws.StringValue(obj.ETag),
+	}, nil
+}
+
+// UploadIfNoneMatch uploads binary data to S3 only if the object doesn't already exist;
+// returns false if the object already exists
+func (s *s3) UploadIfNoneMatch(key string, content []byte, contentType string) (bool, error) {
+	return s.conditionalUpload(key, content, contentType, map[string]string{"If-None-Match": "*"})
+}
+
+// UploadIfMatch overwrites an S3 object only if its ETag matches the provided etag;
+// returns false if the object has been modified
+func (s *s3) UploadIfMatch(key string, etag string, content []byte, contentType string) (bool, error) {
+	return s.conditionalUpload(key, content, contentType, map[string]string{"If-Match": etag})
+}
+
+func (s *s3) conditionalUpload(key string, content []byte, contentType string, conditionHeaders map[string]string) (bool, error) {
+	_, err := s.client.PutObjectWithContext(
+		aws.BackgroundContext(),
+		&awss3.PutObjectInput{
+			Key:         aws.String(key),
+			Bucket:      aws.String(s.bucket),
+			ContentType: aws.String(contentType),
+			Body:        bytes.NewReader(content),
+		},
+		request.WithSetRequestHeaders(conditionHeaders),
+	)
+	if err != nil {
+		// ConditionalRequestConflict is returned when a conflicting write is in progress
+		if awslib.IsErrCode(err, "PreconditionFailed") || awslib.IsErrCode(err, "ConditionalRequestConflict") || awslib.IsNoSuchKeyErr(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
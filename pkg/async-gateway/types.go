@@ -37,3 +37,10 @@ type GetWorkloadResponse struct {
 	Result    *UserResponse `json:"result,omitempty"`
 	Timestamp *time.Time    `json:"timestamp,omitempty"`
 }
+
+// WorkloadOptions represents the api-level configuration which applies to a workload
+type WorkloadOptions struct {
+	ResultTTL         *time.Duration
+	IdempotencyKey    string
+	IdempotencyWindow time.Duration
+}
@@ -32,18 +32,26 @@ import (
 
 type AsyncScaler struct {
 	k8s        *k8s.Client
+	namespaces *APINamespaces
 	prometheus promv1.API
 }
 
-func NewAsyncScaler(k8sClient *k8s.Client, promClient promv1.API) *AsyncScaler {
+func NewAsyncScaler(k8sClient *k8s.Client, namespaces *APINamespaces, promClient promv1.API) *AsyncScaler {
 	return &AsyncScaler{
 		k8s:        k8sClient,
+		namespaces: namespaces,
 		prometheus: promClient,
 	}
 }
 
+func (s *AsyncScaler) k8sClient(apiName string) *k8s.Client {
+	return s.k8s.WithNamespace(s.namespaces.Get(apiName))
+}
+
 func (s *AsyncScaler) Scale(apiName string, request int32) error {
-	deployment, err := s.k8s.GetDeployment(workloads.K8sName(apiName))
+	k8sClient := s.k8sClient(apiName)
+
+	deployment, err := k8sClient.GetDeployment(workloads.K8sName(apiName))
 	if err != nil {
 		return err
 	}
@@ -58,7 +66,7 @@ func (s *AsyncScaler) Scale(apiName string, request int32) error {
 
 	deployment.Spec.Replicas = pointer.Int32(request)
 
-	if _, err = s.k8s.UpdateDeployment(deployment); err != nil {
+	if _, err = k8sClient.UpdateDeployment(deployment); err != nil {
 		return err
 	}
 
@@ -97,8 +105,18 @@ func (s *AsyncScaler) GetInFlightRequests(apiName string, window time.Duration)
 	return nil, nil
 }
 
+// GetGPUUtilization is not supported for async apis (the autoscaling spec validation prevents it from being configured)
+func (s *AsyncScaler) GetGPUUtilization(apiName string, window time.Duration) (*float64, error) {
+	return nil, errors.ErrorUnexpected("scaling on gpu utilization is not supported for " + userconfig.AsyncAPIKind.String())
+}
+
+// QueryCustomMetric is not supported for async apis (the autoscaling spec validation prevents it from being configured)
+func (s *AsyncScaler) QueryCustomMetric(query string) (*float64, error) {
+	return nil, errors.ErrorUnexpected("scaling on custom metrics is not supported for " + userconfig.AsyncAPIKind.String())
+}
+
 func (s *AsyncScaler) GetAutoscalingSpec(apiName string) (*userconfig.Autoscaling, error) {
-	deployment, err := s.k8s.GetDeployment(workloads.K8sName(apiName))
+	deployment, err := s.k8sClient(apiName).GetDeployment(workloads.K8sName(apiName))
 	if err != nil {
 		return nil, err
 	}
@@ -116,7 +134,7 @@ func (s *AsyncScaler) GetAutoscalingSpec(apiName string) (*userconfig.Autoscalin
 }
 
 func (s *AsyncScaler) CurrentRequestedReplicas(apiName string) (int32, error) {
-	deployment, err := s.k8s.GetDeployment(workloads.K8sName(apiName))
+	deployment, err := s.k8sClient(apiName).GetDeployment(workloads.K8sName(apiName))
 	if err != nil {
 		return 0, err
 	}
@@ -39,6 +39,8 @@ const (
 type Scaler interface {
 	Scale(apiName string, request int32) error
 	GetInFlightRequests(apiName string, window time.Duration) (*float64, error)
+	GetGPUUtilization(apiName string, window time.Duration) (*float64, error)
+	QueryCustomMetric(query string) (*float64, error)
 	GetAutoscalingSpec(apiName string) (*userconfig.Autoscaling, error)
 	CurrentRequestedReplicas(apiName string) (int32, error)
 }
@@ -175,16 +177,16 @@ func (a *Autoscaler) autoscaleFn(api userconfig.Resource) (func() error, error)
 			startTime = time.Now()
 		}
 
-		avgInFlight, err := scaler.GetInFlightRequests(api.Name, autoscalingSpec.Window)
+		metricName, metricValue, metricTarget, err := getScalingMetric(scaler, api.Name, autoscalingSpec)
 		if err != nil {
-			return errors.Wrap(err, "failed to get in-flight requests")
+			return err
 		}
-		if avgInFlight == nil {
+		if metricValue == nil {
 			log.Debug("autoscaler tick: metrics not available yet")
 			return nil
 		}
 
-		rawRecommendation := *avgInFlight / *autoscalingSpec.TargetInFlight
+		rawRecommendation := *metricValue / metricTarget
 		recommendation := int32(math.Ceil(rawRecommendation))
 
 		if rawRecommendation < float64(currentRequestedReplicas) && rawRecommendation > float64(currentRequestedReplicas)*(1-autoscalingSpec.DownscaleTolerance) {
@@ -253,8 +255,9 @@ func (a *Autoscaler) autoscaleFn(api userconfig.Resource) (func() error, error)
 
 		log.Debugw("autoscaler tick",
 			"autoscaling", map[string]interface{}{
-				"avg_in_flight":                  *avgInFlight,
-				"target_in_flight":               *autoscalingSpec.TargetInFlight,
+				"metric":                         metricName,
+				"metric_value":                   *metricValue,
+				"metric_target":                  metricTarget,
 				"raw_recommendation":             rawRecommendation,
 				"current_replicas":               currentRequestedReplicas,
 				"downscale_tolerance":            autoscalingSpec.DownscaleTolerance,
@@ -284,3 +287,28 @@ func (a *Autoscaler) autoscaleFn(api userconfig.Resource) (func() error, error)
 		return nil
 	}, nil
 }
+
+// getScalingMetric returns the metric which the api is scaled on: its value is the total load of the api
+// (across all replicas), and its target is the desired load per replica
+func getScalingMetric(scaler Scaler, apiName string, autoscalingSpec *userconfig.Autoscaling) (string, *float64, float64, error) {
+	switch {
+	case autoscalingSpec.CustomMetric != nil:
+		value, err := scaler.QueryCustomMetric(autoscalingSpec.CustomMetric.Query)
+		if err != nil {
+			return "", nil, 0, errors.Wrap(err, "failed to query custom metric")
+		}
+		return userconfig.CustomMetricKey, value, autoscalingSpec.CustomMetric.Target, nil
+	case autoscalingSpec.TargetGPUUtilization != nil:
+		gpuUtilization, err := scaler.GetGPUUtilization(apiName, autoscalingSpec.Window)
+		if err != nil {
+			return "", nil, 0, errors.Wrap(err, "failed to get gpu utilization")
+		}
+		return "gpu_utilization", gpuUtilization, *autoscalingSpec.TargetGPUUtilization, nil
+	default:
+		avgInFlight, err := scaler.GetInFlightRequests(apiName, autoscalingSpec.Window)
+		if err != nil {
+			return "", nil, 0, errors.Wrap(err, "failed to get in-flight requests")
+		}
+		return "in_flight", avgInFlight, *autoscalingSpec.TargetInFlight, nil
+	}
+}
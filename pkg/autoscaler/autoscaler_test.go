@@ -461,3 +461,72 @@ func TestAutoscaler_MaxReplicas(t *testing.T) {
 		return latestRequest > maxReplicas
 	}, 3*time.Second, time.Second)
 }
+
+func TestGetScalingMetric(t *testing.T) {
+	t.Parallel()
+
+	scalerMock := &ScalerFunc{
+		GetInFlightRequestsFunc: func(apiName string, window time.Duration) (*float64, error) {
+			return pointer.Float64(10), nil
+		},
+		GetGPUUtilizationFunc: func(apiName string, window time.Duration) (*float64, error) {
+			return pointer.Float64(150), nil
+		},
+		QueryCustomMetricFunc: func(query string) (*float64, error) {
+			require.Equal(t, "sum(queue_length)", query)
+			return pointer.Float64(42), nil
+		},
+	}
+
+	cases := []struct {
+		name            string
+		autoscalingSpec userconfig.Autoscaling
+		expectedName    string
+		expectedValue   float64
+		expectedTarget  float64
+	}{
+		{
+			name: "in-flight requests by default",
+			autoscalingSpec: userconfig.Autoscaling{
+				TargetInFlight: pointer.Float64(2),
+			},
+			expectedName:   "in_flight",
+			expectedValue:  10,
+			expectedTarget: 2,
+		},
+		{
+			name: "gpu utilization",
+			autoscalingSpec: userconfig.Autoscaling{
+				TargetInFlight:       pointer.Float64(2),
+				TargetGPUUtilization: pointer.Float64(75),
+			},
+			expectedName:   "gpu_utilization",
+			expectedValue:  150,
+			expectedTarget: 75,
+		},
+		{
+			name: "custom metric",
+			autoscalingSpec: userconfig.Autoscaling{
+				TargetInFlight: pointer.Float64(2),
+				CustomMetric: &userconfig.CustomMetric{
+					Query:  "sum(queue_length)",
+					Target: 7,
+				},
+			},
+			expectedName:   "custom_metric",
+			expectedValue:  42,
+			expectedTarget: 7,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			name, value, target, err := getScalingMetric(scalerMock, "test", &c.autoscalingSpec)
+			require.NoError(t, err)
+			require.Equal(t, c.expectedName, name)
+			require.NotNil(t, value)
+			require.Equal(t, c.expectedValue, *value)
+			require.Equal(t, c.expectedTarget, target)
+		})
+	}
+}
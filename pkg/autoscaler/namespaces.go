@@ -0,0 +1,57 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package autoscaler
+
+import (
+	"sync"
+
+	"github.com/cortexlabs/cortex/pkg/consts"
+)
+
+// APINamespaces keeps track of the namespace which each api is deployed to (the namespace of the api's project)
+type APINamespaces struct {
+	sync.RWMutex
+	namespaces map[string]string
+}
+
+func NewAPINamespaces() *APINamespaces {
+	return &APINamespaces{
+		namespaces: make(map[string]string),
+	}
+}
+
+func (n *APINamespaces) Set(apiName string, namespace string) {
+	n.Lock()
+	defer n.Unlock()
+	n.namespaces[apiName] = namespace
+}
+
+func (n *APINamespaces) Delete(apiName string) {
+	n.Lock()
+	defer n.Unlock()
+	delete(n.namespaces, apiName)
+}
+
+// Get returns the api's namespace, or the default namespace if the api hasn't been added
+func (n *APINamespaces) Get(apiName string) string {
+	n.RLock()
+	defer n.RUnlock()
+	if namespace, ok := n.namespaces[apiName]; ok {
+		return namespace
+	}
+	return consts.DefaultNamespace
+}
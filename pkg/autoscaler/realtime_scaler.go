@@ -38,13 +38,15 @@ import (
 
 type RealtimeScaler struct {
 	k8s        *k8s.Client
+	namespaces *APINamespaces
 	prometheus promv1.API
 	logger     *zap.SugaredLogger
 }
 
-func NewRealtimeScaler(k8sClient *k8s.Client, promClient promv1.API, logger *zap.SugaredLogger) *RealtimeScaler {
+func NewRealtimeScaler(k8sClient *k8s.Client, namespaces *APINamespaces, promClient promv1.API, logger *zap.SugaredLogger) *RealtimeScaler {
 	return &RealtimeScaler{
 		k8s:        k8sClient,
+		namespaces: namespaces,
 		prometheus: promClient,
 		logger:     logger,
 	}
@@ -56,7 +58,7 @@ func (s *RealtimeScaler) Scale(apiName string, request int32) error {
 	// we use the controller-runtime client to make use of the cache mechanism
 	var deployment kapps.Deployment
 	err := s.k8s.Get(ctx, ctrlclient.ObjectKey{
-		Namespace: s.k8s.Namespace,
+		Namespace: s.namespaces.Get(apiName),
 		Name:      workloads.K8sName(apiName),
 	}, &deployment)
 	if err != nil {
@@ -72,6 +74,17 @@ func (s *RealtimeScaler) Scale(apiName string, request int32) error {
 		return nil
 	}
 
+	var numPromoted int32
+	if request > current {
+		// ready replicas of the api's warm pool start serving traffic immediately, while the deployment's new replicas are scheduled
+		if numPromoted, err = s.promoteWarmPods(&deployment, request-current); err != nil {
+			s.logger.Errorw("failed to promote warm pool replicas",
+				zap.Error(err), zap.String("apiName", apiName),
+			)
+			telemetry.Error(err)
+		}
+	}
+
 	if request == 0 {
 		if err = s.routeToActivator(&deployment); err != nil {
 			return errors.Wrap(err, "failed to re-route traffic to activator")
@@ -86,7 +99,8 @@ func (s *RealtimeScaler) Scale(apiName string, request int32) error {
 
 	if current == 0 && request > 0 {
 		go func() {
-			if err := s.routeToService(&deployment); err != nil {
+			// promoted warm pool replicas are already ready, so there's no need to wait for the deployment's replicas
+			if err := s.routeToService(&deployment, numPromoted == 0); err != nil {
 				s.logger.Errorw("failed to re-route traffic to API",
 					zap.Error(err), zap.String("apiName", apiName),
 				)
@@ -99,6 +113,50 @@ func (s *RealtimeScaler) Scale(apiName string, request int32) error {
 	return nil
 }
 
+// promoteWarmPods labels up to n ready replicas of the api's warm pool with the apiName label, which adds them to the api's
+// service and releases them from the warm pool's deployment (which then replaces them); promoted replicas are removed by
+// the operator once the api's deployment has all of its requested replicas ready
+func (s *RealtimeScaler) promoteWarmPods(deployment *kapps.Deployment, n int32) (int32, error) {
+	apiName := deployment.Labels["apiName"]
+
+	k8sClient := s.k8s.WithNamespace(deployment.Namespace)
+
+	pods, err := k8sClient.ListPodsByLabels(map[string]string{
+		"warmPoolOf": apiName,
+		"podID":      deployment.Labels["podID"],
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	var numPromoted int32
+	for i := range pods {
+		if numPromoted >= n {
+			break
+		}
+
+		pod := &pods[i]
+		if pod.DeletionTimestamp != nil || !k8s.IsPodReady(pod) {
+			continue
+		}
+
+		delete(pod.Labels, "warmPoolOf")
+		pod.Labels["apiName"] = apiName
+		pod.Labels["warmPoolPromoted"] = "true"
+
+		if _, err := k8sClient.UpdatePod(pod); err != nil {
+			return numPromoted, err
+		}
+		numPromoted++
+	}
+
+	if numPromoted > 0 {
+		s.logger.Infow("promoted warm pool replicas", zap.String("apiName", apiName), zap.Int32("count", numPromoted))
+	}
+
+	return numPromoted, nil
+}
+
 func (s *RealtimeScaler) GetInFlightRequests(apiName string, window time.Duration) (*float64, error) {
 	windowSeconds := int64(window.Seconds())
 
@@ -135,8 +193,56 @@ func (s *RealtimeScaler) GetInFlightRequests(apiName string, window time.Duratio
 	return &avgInflightRequests, nil
 }
 
+// GetGPUUtilization returns the sum of the average gpu utilization (0-100) of each of the api's replicas
+func (s *RealtimeScaler) GetGPUUtilization(apiName string, window time.Duration) (*float64, error) {
+	windowSeconds := int64(window.Seconds())
+
+	// the pods of the api's deployment are named <deployment>-<replicaset hash>-<suffix>; the regex excludes
+	// the pods of canaries and of apis whose name starts with "<apiName>-"
+	//
+	// PromQL query:
+	// 	sum(avg by (exported_pod) (avg_over_time(DCGM_FI_DEV_GPU_UTIL{exported_pod=~"api-<apiName>-[a-z0-9]+-[a-z0-9]+"}[60s])))
+	query := fmt.Sprintf(
+		"sum(avg by (exported_pod) (avg_over_time(DCGM_FI_DEV_GPU_UTIL{exported_pod=~\"%s-[a-z0-9]+-[a-z0-9]+\"}[%ds])))",
+		workloads.K8sName(apiName), windowSeconds,
+	)
+
+	return s.queryScalar(query)
+}
+
+// QueryCustomMetric runs a user-provided query, which is expected to return a single value
+func (s *RealtimeScaler) QueryCustomMetric(query string) (*float64, error) {
+	return s.queryScalar(query)
+}
+
+func (s *RealtimeScaler) queryScalar(query string) (*float64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), _prometheusQueryTimeoutSeconds*time.Second)
+	defer cancel()
+
+	valuesQuery, _, err := s.prometheus.Query(ctx, query, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	switch values := valuesQuery.(type) {
+	case *model.Scalar:
+		return pointer.Float64(float64(values.Value)), nil
+	case model.Vector:
+		// no values available
+		if values.Len() == 0 {
+			return nil, nil
+		}
+		if values.Len() > 1 {
+			return nil, errors.ErrorUnexpected(fmt.Sprintf("query returned %d series (expected a single value; consider aggregating the query with sum() or max())", values.Len()), query)
+		}
+		return pointer.Float64(float64(values[0].Value)), nil
+	default:
+		return nil, errors.ErrorUnexpected("failed to convert prometheus metric to vector or scalar")
+	}
+}
+
 func (s *RealtimeScaler) GetAutoscalingSpec(apiName string) (*userconfig.Autoscaling, error) {
-	deployment, err := s.k8s.GetDeployment(workloads.K8sName(apiName))
+	deployment, err := s.k8s.WithNamespace(s.namespaces.Get(apiName)).GetDeployment(workloads.K8sName(apiName))
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get deployment")
 	}
@@ -159,7 +265,7 @@ func (s *RealtimeScaler) CurrentRequestedReplicas(apiName string) (int32, error)
 	// we use the controller-runtime client to make use of the cache mechanism
 	var deployment kapps.Deployment
 	err := s.k8s.Get(ctx, ctrlclient.ObjectKey{
-		Namespace: s.k8s.Namespace,
+		Namespace: s.namespaces.Get(apiName),
 		Name:      workloads.K8sName(apiName),
 	}, &deployment)
 	if err != nil {
@@ -173,9 +279,9 @@ func (s *RealtimeScaler) CurrentRequestedReplicas(apiName string) (int32, error)
 	return *deployment.Spec.Replicas, nil
 }
 
-func (s *RealtimeScaler) routeToService(deployment *kapps.Deployment) error {
+func (s *RealtimeScaler) routeToService(deployment *kapps.Deployment, waitForReplicas bool) error {
 	ctx := context.Background()
-	vs, err := s.k8s.GetVirtualService(deployment.Name)
+	vs, err := s.k8s.WithNamespace(deployment.Namespace).GetVirtualService(deployment.Name)
 	if err != nil {
 		return errors.Wrap(err, "failed to get virtual service")
 	}
@@ -184,8 +290,10 @@ func (s *RealtimeScaler) routeToService(deployment *kapps.Deployment) error {
 		return errors.ErrorUnexpected("virtual service does not have any http entries")
 	}
 
-	if err = s.waitForReadyReplicas(ctx, deployment); err != nil {
-		return errors.Wrap(err, "no ready replicas available")
+	if waitForReplicas {
+		if err = s.waitForReadyReplicas(ctx, deployment); err != nil {
+			return errors.Wrap(err, "no ready replicas available")
+		}
 	}
 
 	for i := range vs.Spec.Http {
@@ -197,7 +305,7 @@ func (s *RealtimeScaler) routeToService(deployment *kapps.Deployment) error {
 		vs.Spec.Http[i].Route[1].Weight = 0   // activator traffic
 	}
 
-	vsClient := s.k8s.IstioClientSet().NetworkingV1beta1().VirtualServices(s.k8s.Namespace)
+	vsClient := s.k8s.IstioClientSet().NetworkingV1beta1().VirtualServices(deployment.Namespace)
 	if _, err = vsClient.Update(ctx, vs, kmeta.UpdateOptions{}); err != nil {
 		return errors.Wrap(err, "failed to update virtual service")
 	}
@@ -207,7 +315,7 @@ func (s *RealtimeScaler) routeToService(deployment *kapps.Deployment) error {
 
 func (s *RealtimeScaler) routeToActivator(deployment *kapps.Deployment) error {
 	ctx := context.Background()
-	vs, err := s.k8s.GetVirtualService(deployment.Name)
+	vs, err := s.k8s.WithNamespace(deployment.Namespace).GetVirtualService(deployment.Name)
 	if err != nil {
 		return errors.Wrap(err, "failed to get virtual service")
 	}
@@ -225,7 +333,7 @@ func (s *RealtimeScaler) routeToActivator(deployment *kapps.Deployment) error {
 		vs.Spec.Http[i].Route[1].Weight = 100 // activator traffic
 	}
 
-	vsClient := s.k8s.IstioClientSet().NetworkingV1beta1().VirtualServices(s.k8s.Namespace)
+	vsClient := s.k8s.IstioClientSet().NetworkingV1beta1().VirtualServices(deployment.Namespace)
 	if _, err = vsClient.Update(ctx, vs, kmeta.UpdateOptions{}); err != nil {
 		return errors.Wrap(err, "failed to update virtual service")
 	}
@@ -234,7 +342,7 @@ func (s *RealtimeScaler) routeToActivator(deployment *kapps.Deployment) error {
 }
 
 func (s *RealtimeScaler) waitForReadyReplicas(ctx context.Context, deployment *kapps.Deployment) error {
-	watcher, err := s.k8s.ClientSet().AppsV1().Deployments(s.k8s.Namespace).Watch(
+	watcher, err := s.k8s.ClientSet().AppsV1().Deployments(deployment.Namespace).Watch(
 		ctx,
 		kmeta.ListOptions{
 			FieldSelector: fmt.Sprintf("metadata.name=%s", deployment.Name),
@@ -25,6 +25,8 @@ import (
 type ScalerFunc struct {
 	ScaleFunc                    func(apiName string, request int32) error
 	GetInFlightRequestsFunc      func(apiName string, window time.Duration) (*float64, error)
+	GetGPUUtilizationFunc        func(apiName string, window time.Duration) (*float64, error)
+	QueryCustomMetricFunc        func(query string) (*float64, error)
 	GetAutoscalingSpecFunc       func(apiName string) (*userconfig.Autoscaling, error)
 	CurrentRequestedReplicasFunc func(apiName string) (int32, error)
 }
@@ -45,6 +47,22 @@ func (s *ScalerFunc) GetInFlightRequests(apiName string, window time.Duration) (
 	return s.GetInFlightRequestsFunc(apiName, window)
 }
 
+func (s *ScalerFunc) GetGPUUtilization(apiName string, window time.Duration) (*float64, error) {
+	if s.GetGPUUtilizationFunc == nil {
+		return nil, nil
+	}
+
+	return s.GetGPUUtilizationFunc(apiName, window)
+}
+
+func (s *ScalerFunc) QueryCustomMetric(query string) (*float64, error) {
+	if s.QueryCustomMetricFunc == nil {
+		return nil, nil
+	}
+
+	return s.QueryCustomMetricFunc(query)
+}
+
 func (s *ScalerFunc) GetAutoscalingSpec(apiName string) (*userconfig.Autoscaling, error) {
 	if s.GetAutoscalingSpecFunc == nil {
 		return nil, nil
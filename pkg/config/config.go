@@ -24,11 +24,13 @@ import (
 	"github.com/DataDog/datadog-go/statsd"
 	"github.com/cortexlabs/cortex/pkg/consts"
 	batch "github.com/cortexlabs/cortex/pkg/crds/apis/batch/v1alpha1"
+	serving "github.com/cortexlabs/cortex/pkg/crds/apis/serving/v1alpha1"
 	"github.com/cortexlabs/cortex/pkg/lib/aws"
 	cr "github.com/cortexlabs/cortex/pkg/lib/configreader"
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
 	"github.com/cortexlabs/cortex/pkg/lib/hash"
 	"github.com/cortexlabs/cortex/pkg/lib/k8s"
+	"github.com/cortexlabs/cortex/pkg/lib/oidc"
 	"github.com/cortexlabs/cortex/pkg/lib/telemetry"
 	"github.com/cortexlabs/cortex/pkg/types/clusterconfig"
 	promapi "github.com/prometheus/client_golang/api"
@@ -49,12 +51,14 @@ var (
 	K8sAllNamspaces *k8s.Client
 	MetricsClient   *statsd.Client
 	Prometheus      promv1.API
+	OIDCVerifier    *oidc.Verifier // nil unless oidc is configured
 	scheme          = runtime.NewScheme()
 )
 
 func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 	utilruntime.Must(batch.AddToScheme(scheme))
+	utilruntime.Must(serving.AddToScheme(scheme))
 }
 
 func InitConfigs(clusterConfig *clusterconfig.Config, operatorMetadata *clusterconfig.OperatorMetadata) {
@@ -91,6 +95,10 @@ func Init() error {
 
 	ClusterConfig = clusterConfig
 
+	if clusterConfig.OIDC != nil {
+		OIDCVerifier = oidc.NewVerifier(clusterConfig.OIDC.IssuerURL, clusterConfig.OIDC.ClientID)
+	}
+
 	AWS, err = aws.NewForRegion(clusterConfig.Region)
 	if err != nil {
 		return err
@@ -27,6 +27,8 @@ var (
 	CortexVersion      = "master" // CORTEX_VERSION
 	CortexVersionMinor = "master" // CORTEX_VERSION_MINOR
 
+	EKSVersion = "1.26" // must match K8S_VERSION in manager/generate_eks.py
+
 	DefaultNamespace    = "default"
 	KubeSystemNamespace = "kube-system"
 	IstioNamespace      = "istio-system"
@@ -38,9 +40,15 @@ var (
 
 	DefaultUserPodPortInt32 = int32(8080)
 
+	DefaultDistributedPortInt32 = int32(29500) // matches torch.distributed's default master port
+
 	ProxyPortStr   = "8888"
 	ProxyPortInt32 = int32(8888)
 
+	AsyncGatewayGRPCPortStr    = "8889"
+	AsyncGatewayGRPCPortInt32  = int32(8889)
+	AsyncGatewayGRPCPathPrefix = "/asyncgateway.AsyncGateway/" // must match the service defined in pkg/async-gateway/pb/async_gateway.proto
+
 	ActivatorName      = "activator"
 	ActivatorPortInt32 = int32(8000)
 
@@ -49,6 +57,8 @@ var (
 	AdminPortInt32 = int32(15000)
 
 	AuthHeader = "X-Cortex-Authorization"
+	// AWSIAMAudienceHeader must be included in the signature of the identity requests which are sent to aws_iam apis, and must be set to the name of the api
+	AWSIAMAudienceHeader = "X-Cortex-API-Name"
 
 	CortexProxyCPU    = kresource.MustParse("100m")
 	CortexProxyMem    = kresource.MustParse("100Mi")
@@ -97,18 +107,23 @@ var (
 	UserAgentKey             = "User-Agent"
 	KubeProbeUserAgentPrefix = "kube-probe/"
 
-	CortexAPINameHeader       = "X-Cortex-API-Name"
-	CortexTargetServiceHeader = "X-Cortex-Target-Service"
-	CortexProbeHeader         = "X-Cortex-Probe"
-	CortexOriginHeader        = "X-Cortex-Origin"
-	CortexQueueURLHeader      = "X-Cortex-Queue-URL"
+	CortexAPINameHeader           = "X-Cortex-API-Name"
+	CortexTargetServiceHeader     = "X-Cortex-Target-Service"
+	CortexProbeHeader             = "X-Cortex-Probe"
+	CortexOriginHeader            = "X-Cortex-Origin"
+	CortexQueueURLHeader          = "X-Cortex-Queue-URL"
+	CortexResultTTLHeader         = "X-Cortex-Result-TTL"
+	CortexIdempotencyWindowHeader = "X-Cortex-Idempotency-Window"
+	IdempotencyKeyHeader          = "Idempotency-Key"
 
 	WaitForReadyReplicasTimeout = 20 * time.Minute
 )
 
+const ReleaseRegistry = "quay.io/cortexlabs"
+
 func DefaultRegistry() string {
 	if registryOverride := os.Getenv("CORTEX_DEV_DEFAULT_IMAGE_REGISTRY"); registryOverride != "" {
 		return registryOverride
 	}
-	return "quay.io/cortexlabs"
+	return ReleaseRegistry
 }
@@ -37,6 +37,15 @@ type BatchJobSpec struct {
 	// Number of workers for the batch job
 	Workers int32 `json:"workers,omitempty"`
 
+	// +kubebuilder:validation:Optional
+	// Scale the number of workers with the number of remaining batches (Workers is ignored)
+	AutoWorkers bool `json:"auto_workers,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// Maximum number of workers when AutoWorkers is set (defaults to the total number of batches)
+	MaxWorkers *int32 `json:"max_workers,omitempty"`
+
 	// +kubebuilder:validation:Optional
 	// YAML content of the user config
 	Config *string `json:"config,omitempty"`
@@ -45,6 +54,14 @@ type BatchJobSpec struct {
 	// Duration until a batch job times out
 	Timeout *kmeta.Duration `json:"timeout,omitempty"`
 
+	// +kubebuilder:validation:Optional
+	// Number of times a failed batch is retried
+	MaxRetries *int32 `json:"max_retries,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// S3 path under which the job writes its results
+	OutputPath string `json:"output_path,omitempty"`
+
 	// +kubebuilder:validation:Optional
 	// Configuration for the dead letter queue
 	DeadLetterQueue *DeadLetterQueueSpec `json:"dead_letter_queue,omitempty"`
@@ -103,6 +120,9 @@ type BatchJobStatus struct {
 
 	// Detailed worker counts with respective status
 	WorkerCounts *status.WorkerCounts `json:"worker_counts,omitempty"`
+
+	// Current number of workers, which changes over time when AutoWorkers is set
+	Workers int32 `json:"workers,omitempty"`
 }
 
 // EnqueuingStatus is an enum for the different possible enqueuing status
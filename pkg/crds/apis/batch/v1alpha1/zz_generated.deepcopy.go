@@ -90,6 +90,11 @@ func (in *BatchJobList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BatchJobSpec) DeepCopyInto(out *BatchJobSpec) {
 	*out = *in
+	if in.MaxWorkers != nil {
+		in, out := &in.MaxWorkers, &out.MaxWorkers
+		*out = new(int32)
+		**out = **in
+	}
 	if in.Config != nil {
 		in, out := &in.Config, &out.Config
 		*out = new(string)
@@ -100,6 +105,11 @@ func (in *BatchJobSpec) DeepCopyInto(out *BatchJobSpec) {
 		*out = new(v1.Duration)
 		**out = **in
 	}
+	if in.MaxRetries != nil {
+		in, out := &in.MaxRetries, &out.MaxRetries
+		*out = new(int32)
+		**out = **in
+	}
 	if in.DeadLetterQueue != nil {
 		in, out := &in.DeadLetterQueue, &out.DeadLetterQueue
 		*out = new(DeadLetterQueueSpec)
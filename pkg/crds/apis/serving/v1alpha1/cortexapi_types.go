@@ -0,0 +1,102 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	kmeta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// CortexAPIState is an enum for the state of the reconciliation of a CortexAPI
+type CortexAPIState string
+
+// Possible CortexAPIState states
+const (
+	CortexAPIStateSynced CortexAPIState = "synced"
+	CortexAPIStateError  CortexAPIState = "error"
+)
+
+// CortexAPIStatus defines the observed state of CortexAPI
+type CortexAPIStatus struct {
+	// +kubebuilder:validation:Type=string
+	// State of the reconciliation of the api with the resource's spec
+	State CortexAPIState `json:"state,omitempty"`
+
+	// Error from the most recent attempt to deploy the api
+	Error string `json:"error,omitempty"`
+
+	// Kind of the deployed api
+	Kind string `json:"kind,omitempty"`
+
+	// ID of the deployed api
+	APIID string `json:"api_id,omitempty"`
+
+	// Endpoint of the api
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// Number of ready replicas (realtime and async apis only)
+	Ready int32 `json:"ready,omitempty"`
+
+	// Number of requested replicas (realtime and async apis only)
+	Requested int32 `json:"requested,omitempty"`
+
+	// Number of up-to-date replicas (realtime and async apis only)
+	UpToDate int32 `json:"up_to_date,omitempty"`
+
+	// Generation of the resource which was most recently deployed
+	ObservedGeneration int64 `json:"observed_generation,omitempty"`
+
+	// Time of the most recent attempt to deploy the api
+	LastAttemptTime *kmeta.Time `json:"last_attempt_time,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=capi
+// +kubebuilder:printcolumn:JSONPath=".status.kind",name="Kind",type="string"
+// +kubebuilder:printcolumn:JSONPath=".status.state",name="State",type="string"
+// +kubebuilder:printcolumn:JSONPath=".status.ready",name="Ready",type="integer"
+// +kubebuilder:printcolumn:JSONPath=".status.requested",name="Requested",type="integer"
+// +kubebuilder:printcolumn:JSONPath=".status.endpoint",name="Endpoint",type="string"
+// +kubebuilder:printcolumn:JSONPath=".metadata.creationTimestamp",name="Age",type="date"
+
+// CortexAPI is the Schema for the cortexapis API
+type CortexAPI struct {
+	kmeta.TypeMeta   `json:",inline"`
+	kmeta.ObjectMeta `json:"metadata,omitempty"`
+
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Type=object
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// The api configuration, with the same fields as an api in an api configuration file (the name of the api is the name of the resource)
+	Spec runtime.RawExtension `json:"spec"`
+
+	Status CortexAPIStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// CortexAPIList contains a list of CortexAPI
+type CortexAPIList struct {
+	kmeta.TypeMeta `json:",inline"`
+	kmeta.ListMeta `json:"metadata,omitempty"`
+	Items          []CortexAPI `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&CortexAPI{}, &CortexAPIList{})
+}
@@ -263,6 +263,16 @@ func (r *BatchJobReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 				log.Error(err, "failed to create worker job")
 				return ctrl.Result{}, err
 			}
+		} else if batchJob.Spec.AutoWorkers && !batchJob.Status.Status.IsCompleted() {
+			log.V(1).Info("scaling workers")
+			if err = r.scaleAutoWorkers(ctx, batchJob, workerJob, queueURL); err != nil {
+				if controllers.IsOptimisticLockError(err) {
+					log.Info("conflict during worker job update, retrying")
+					return ctrl.Result{Requeue: true}, nil
+				}
+				log.Error(err, "failed to scale workers")
+				return ctrl.Result{}, err
+			}
 		}
 	}
 
@@ -280,6 +290,11 @@ func (r *BatchJobReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 		return ctrl.Result{RequeueAfter: batchJob.Spec.TTL.Duration}, nil
 	}
 
+	// the remaining batches and available capacity change without triggering a reconciliation
+	if batchJob.Spec.AutoWorkers && workerJobExists && !batchJob.Status.Status.IsCompleted() {
+		return ctrl.Result{RequeueAfter: _autoWorkersSyncPeriod}, nil
+	}
+
 	return ctrl.Result{}, nil
 }
 
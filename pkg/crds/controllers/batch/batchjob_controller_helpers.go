@@ -33,6 +33,8 @@ import (
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
 	libjson "github.com/cortexlabs/cortex/pkg/lib/json"
 	"github.com/cortexlabs/cortex/pkg/lib/k8s"
+	"github.com/cortexlabs/cortex/pkg/lib/maps"
+	libmath "github.com/cortexlabs/cortex/pkg/lib/math"
 	"github.com/cortexlabs/cortex/pkg/lib/parallel"
 	"github.com/cortexlabs/cortex/pkg/lib/pointer"
 	s "github.com/cortexlabs/cortex/pkg/lib/strings"
@@ -55,6 +57,8 @@ const (
 	_enqueuerContainerName  = "enqueuer"
 	_deadlineExceededReason = "DeadlineExceeded"
 	_cacheDuration          = 60 * time.Second
+	_autoWorkersSyncPeriod  = 30 * time.Second
+	_minAutoWorkersScaleUp  = 4
 )
 
 var totalBatchCountCache, apiSpecCache *cache.Cache
@@ -252,6 +256,15 @@ func (r *BatchJobReconciler) createWorkerJob(ctx context.Context, batchJob batch
 		return errors.Wrap(err, "failed to get desired worker job")
 	}
 
+	if batchJob.Spec.AutoWorkers {
+		totalBatchCount, err := r.Config.GetTotalBatchCount(r, batchJob)
+		if err != nil {
+			return errors.Wrap(err, "failed to get total batch count")
+		}
+		workers := desiredAutoWorkers(0, batchJob.Spec.MaxWorkers, totalBatchCount, 0)
+		workerJob.Spec.Parallelism = &workers
+	}
+
 	if err = r.Create(ctx, workerJob); err != nil {
 		return err
 	}
@@ -259,6 +272,70 @@ func (r *BatchJobReconciler) createWorkerJob(ctx context.Context, batchJob batch
 	return nil
 }
 
+// scaleAutoWorkers adds workers to a job with auto workers as they are needed and as capacity becomes available
+func (r *BatchJobReconciler) scaleAutoWorkers(ctx context.Context, batchJob batch.BatchJob, workerJob *kbatch.Job, queueURL string) error {
+	if workerJob.Status.Succeeded > 0 || workerJob.Spec.Parallelism == nil {
+		// a worker only succeeds once there are no batches left to process, after which kubernetes doesn't start new workers
+		return nil
+	}
+
+	remainingBatchCount, err := r.getRemainingBatchCount(queueURL)
+	if err != nil {
+		return errors.Wrap(err, "failed to get remaining batch count")
+	}
+
+	workerJobPods, err := r.getWorkerJobPods(ctx, batchJob)
+	if err != nil {
+		return errors.Wrap(err, "failed to retrieve worker pods")
+	}
+	workerCounts := getReplicaCounts(workerJobPods)
+
+	currentWorkers := *workerJob.Spec.Parallelism
+	workers := desiredAutoWorkers(currentWorkers, batchJob.Spec.MaxWorkers, remainingBatchCount, workerCounts.Pending+workerCounts.Stalled)
+	if workers == currentWorkers {
+		return nil
+	}
+
+	workerJob.Spec.Parallelism = &workers
+	return r.Update(ctx, workerJob)
+}
+
+// desiredAutoWorkers targets one worker per remaining batch (up to maxWorkers). Workers are only added while all of the
+// current workers have been scheduled, and at most doubled at a time, so that the job grows as capacity (e.g. spot
+// instances) becomes available. Workers are never removed: each worker exits once there are no batches left for it to
+// process, which releases its node while the last batches are still being processed by other workers.
+func desiredAutoWorkers(currentWorkers int32, maxWorkers *int32, remainingBatchCount int, unscheduledWorkers int32) int32 {
+	targetWorkers := int32(libmath.MaxInt(remainingBatchCount, 1))
+	if maxWorkers != nil {
+		targetWorkers = libmath.MinInt32(targetWorkers, *maxWorkers)
+	}
+
+	if targetWorkers <= currentWorkers || unscheduledWorkers > 0 {
+		return currentWorkers
+	}
+
+	return libmath.MinInt32(targetWorkers, libmath.MaxInt32(2*currentWorkers, currentWorkers+_minAutoWorkersScaleUp))
+}
+
+// getRemainingBatchCount returns the number of batches in the job's queue, including the ones being processed
+func (r *BatchJobReconciler) getRemainingBatchCount(queueURL string) (int, error) {
+	output, err := r.AWS.SQS().GetQueueAttributes(&sqs.GetQueueAttributesInput{
+		QueueUrl: aws.String(queueURL),
+		AttributeNames: aws.StringSlice([]string{
+			sqs.QueueAttributeNameApproximateNumberOfMessages,
+			sqs.QueueAttributeNameApproximateNumberOfMessagesNotVisible,
+		}),
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	visibleCount, _ := s.ParseInt(aws.StringValue(output.Attributes[sqs.QueueAttributeNameApproximateNumberOfMessages]))
+	notVisibleCount, _ := s.ParseInt(aws.StringValue(output.Attributes[sqs.QueueAttributeNameApproximateNumberOfMessagesNotVisible]))
+
+	return visibleCount + notVisibleCount, nil
+}
+
 func (r *BatchJobReconciler) desiredEnqueuerJob(batchJob batch.BatchJob, queueURL string) (*kbatch.Job, error) {
 	job := k8s.Job(
 		&k8s.JobSpec{
@@ -350,10 +427,10 @@ func (r *BatchJobReconciler) desiredWorkerJob(batchJob batch.BatchJob, apiSpec s
 					"cortex.dev/api":   "true",
 					"cortex.dev/batch": "worker",
 				},
-				Annotations: map[string]string{
+				Annotations: maps.MergeStrMapsString(map[string]string{
 					"traffic.sidecar.istio.io/excludeOutboundIPRanges": "0.0.0.0/0",
 					"cluster-autoscaler.kubernetes.io/safe-to-evict":   "false",
-				},
+				}, workloads.IAMRolePodAnnotations(apiSpec.Pod)),
 				K8sPodSpec: kcore.PodSpec{
 					InitContainers: []kcore.Container{
 						workloads.KubexitInitContainer(),
@@ -364,7 +441,8 @@ func (r *BatchJobReconciler) desiredWorkerJob(batchJob batch.BatchJob, apiSpec s
 					NodeSelector:       workloads.NodeSelectors(),
 					Affinity:           workloads.GenerateNodeAffinities(batchJob.Spec.NodeGroups),
 					Tolerations:        workloads.GenerateResourceTolerations(),
-					ServiceAccountName: workloads.ServiceAccountName,
+					ServiceAccountName: workloads.PodServiceAccountName(apiSpec.Name, apiSpec.Pod),
+					ImagePullSecrets:   workloads.ImagePullSecrets(apiSpec.Name, apiSpec.Pod),
 				},
 			},
 		},
@@ -445,7 +523,7 @@ func (r *BatchJobReconciler) getWorkerJob(ctx context.Context, batchJob batch.Ba
 func (r *BatchJobReconciler) getWorkerJobPods(ctx context.Context, batchJob batch.BatchJob) ([]kcore.Pod, error) {
 	workerJobPods := kcore.PodList{}
 	if err := r.List(ctx, &workerJobPods,
-		client.InNamespace(consts.DefaultNamespace),
+		client.InNamespace(batchJob.Namespace),
 		client.MatchingLabels{
 			"jobID":            batchJob.Name,
 			"apiName":          batchJob.Spec.APIName,
@@ -498,7 +576,16 @@ func (r *BatchJobReconciler) updateStatus(ctx context.Context, batchJob *batch.B
 			}
 		}
 
-		if worker.Status.Failed == batchJob.Spec.Workers {
+		// auto workers are only added, so the worker job's parallelism is the number of workers that were started
+		workers := batchJob.Spec.Workers
+		if worker.Spec.Parallelism != nil {
+			batchJob.Status.Workers = *worker.Spec.Parallelism
+			if batchJob.Spec.AutoWorkers {
+				workers = *worker.Spec.Parallelism
+			}
+		}
+
+		if worker.Status.Failed == workers {
 			batchJobStatus := status.JobWorkerError
 			for _, condition := range worker.Status.Conditions {
 				if condition.Reason == _deadlineExceededReason {
@@ -515,7 +602,7 @@ func (r *BatchJobReconciler) updateStatus(ctx context.Context, batchJob *batch.B
 			}
 
 			batchJob.Status.Status = batchJobStatus
-		} else if worker.Status.Succeeded == batchJob.Spec.Workers {
+		} else if worker.Status.Succeeded == workers {
 			batchJob.Status.Status = status.JobSucceeded
 
 			jobMetrics, err := r.Config.GetMetrics(r, *batchJob)
@@ -591,6 +678,21 @@ func (r *BatchJobReconciler) ConvertControllerBatchToJobSpec(batchJob batch.Batc
 		timeout = pointer.Int(int(batchJob.Spec.Timeout.Seconds()))
 	}
 
+	var maxRetries *int
+	if batchJob.Spec.MaxRetries != nil {
+		maxRetries = pointer.Int(int(*batchJob.Spec.MaxRetries))
+	}
+
+	workers := int(batchJob.Spec.Workers)
+	if batchJob.Spec.AutoWorkers {
+		workers = int(batchJob.Status.Workers)
+	}
+
+	var maxWorkers *int
+	if batchJob.Spec.MaxWorkers != nil {
+		maxWorkers = pointer.Int(int(*batchJob.Spec.MaxWorkers))
+	}
+
 	totalBatchCount, err := r.Config.GetTotalBatchCount(r, batchJob)
 	if err != nil {
 		return spec.BatchJob{}, errors.Wrap(err, "failed to get total batch count")
@@ -603,10 +705,14 @@ func (r *BatchJobReconciler) ConvertControllerBatchToJobSpec(batchJob batch.Batc
 			Kind:    userconfig.BatchAPIKind,
 		},
 		RuntimeBatchJobConfig: spec.RuntimeBatchJobConfig{
-			Workers:            int(batchJob.Spec.Workers),
+			Workers:            workers,
+			AutoWorkers:        batchJob.Spec.AutoWorkers,
+			MaxWorkers:         maxWorkers,
 			SQSDeadLetterQueue: deadLetterQueue,
 			Config:             config,
 			Timeout:            timeout,
+			MaxRetries:         maxRetries,
+			OutputPath:         batchJob.Spec.OutputPath,
 		},
 		APIID:           api.ID,
 		SQSUrl:          queueURL,
@@ -21,6 +21,8 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"path"
+	"strconv"
 	"strings"
 	"time"
 
@@ -53,6 +55,9 @@ type AsyncMessageHandlerConfig struct {
 	Bucket     string
 	APIName    string
 	TargetURL  string
+	OutputPath string // s3 path to which results are also written (optional)
+	// the number of attempts after which a failed workload is moved to the dead-letter queue (0 if the api doesn't have a dead-letter queue)
+	MaxReceiveCount int
 }
 
 func NewAsyncMessageHandler(config AsyncMessageHandlerConfig, awsClient *awslib.Client, eventHandler RequestEventHandler, logger *zap.SugaredLogger) *AsyncMessageHandler {
@@ -76,14 +81,30 @@ func (h *AsyncMessageHandler) Handle(message *sqs.Message) error {
 	}
 
 	requestID := *message.Body
-	err := h.handleMessage(requestID)
+	err := h.handleMessage(requestID, h.isFinalAttempt(message))
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func (h *AsyncMessageHandler) handleMessage(requestID string) error {
+// isFinalAttempt returns false if the workload will be retried in case of failure
+func (h *AsyncMessageHandler) isFinalAttempt(message *sqs.Message) bool {
+	if h.config.MaxReceiveCount == 0 {
+		return true
+	}
+
+	receiveCount, err := strconv.Atoi(aws.StringValue(message.Attributes[sqs.MessageSystemAttributeNameApproximateReceiveCount]))
+	if err != nil {
+		return true
+	}
+
+	return receiveCount >= h.config.MaxReceiveCount
+}
+
+// handleMessage processes a workload; if it's not the final attempt, the workload's status is not set to failed in case of failure,
+// since the workload will be retried
+func (h *AsyncMessageHandler) handleMessage(requestID string, finalAttempt bool) error {
 	h.log.Infow("processing workload", "id", requestID)
 
 	err := h.updateStatus(requestID, async.StatusInProgress)
@@ -93,29 +114,32 @@ func (h *AsyncMessageHandler) handleMessage(requestID string) error {
 
 	payload, err := h.getPayload(requestID)
 	if err != nil {
-		updateStatusErr := h.updateStatus(requestID, async.StatusFailed)
-		if updateStatusErr != nil {
-			h.log.Errorw("failed to update status after failure to get payload", "id", requestID, "error", updateStatusErr)
-		}
+		h.updateStatusOnFailure(requestID, finalAttempt, "failed to update status after failure to get payload")
 		return errors.Wrap(err, "failed to get payload")
 	}
+	succeeded := false
 	defer func() {
-		h.deletePayload(requestID)
+		// when the api has a dead-letter queue, the payload is kept until the workload succeeds so that it can be retried or redriven
+		if succeeded || h.config.MaxReceiveCount == 0 {
+			h.deletePayload(requestID)
+		}
 		_ = payload.Close()
 	}()
 
 	headers, err := h.getHeaders(requestID)
 	if err != nil {
-		updateStatusErr := h.updateStatus(requestID, async.StatusFailed)
-		if updateStatusErr != nil {
-			h.log.Errorw("failed to update status after failure to get headers", "id", requestID, "error", updateStatusErr)
-		}
+		h.updateStatusOnFailure(requestID, finalAttempt, "failed to update status after failure to get headers")
 		return errors.Wrap(err, "failed to get payload")
 	}
 
 	result, err := h.submitRequest(payload, headers, requestID)
 	if err != nil {
 		h.log.Errorw("failed to submit request to user container", "id", requestID, "error", err)
+		if h.config.MaxReceiveCount > 0 {
+			// the error is returned so that the workload is retried, or moved to the dead-letter queue after the final attempt
+			h.updateStatusOnFailure(requestID, finalAttempt, "failed to update status after failure to submit request to user container")
+			return errors.Wrap(err, "failed to submit request to user container")
+		}
 		updateStatusErr := h.updateStatus(requestID, async.StatusFailed)
 		if updateStatusErr != nil {
 			return errors.Wrap(updateStatusErr, fmt.Sprintf("failed to update status to %s", async.StatusFailed))
@@ -124,16 +148,21 @@ func (h *AsyncMessageHandler) handleMessage(requestID string) error {
 	}
 
 	if err = h.uploadResult(requestID, result); err != nil {
-		updateStatusErr := h.updateStatus(requestID, async.StatusFailed)
-		if updateStatusErr != nil {
-			h.log.Errorw("failed to update status after failure to upload result", "id", requestID, "error", updateStatusErr)
-		}
+		h.updateStatusOnFailure(requestID, finalAttempt, "failed to update status after failure to upload result")
 		return errors.Wrap(err, "failed to upload result to storage")
 	}
 
+	if h.config.OutputPath != "" {
+		if err = h.uploadResultToOutputPath(requestID, result); err != nil {
+			h.updateStatusOnFailure(requestID, finalAttempt, "failed to update status after failure to upload result to output path")
+			return errors.Wrap(err, "failed to upload result to output path", h.config.OutputPath)
+		}
+	}
+
 	if err = h.updateStatus(requestID, async.StatusCompleted); err != nil {
 		return errors.Wrap(err, fmt.Sprintf("failed to update status to %s", async.StatusCompleted))
 	}
+	succeeded = true
 
 	h.log.Infow("workload processing complete", "id", requestID)
 
@@ -145,6 +174,16 @@ func (h *AsyncMessageHandler) updateStatus(requestID string, status async.Status
 	return h.aws.UploadStringToS3("", h.config.Bucket, key)
 }
 
+// updateStatusOnFailure sets the workload's status to failed, unless the workload will be retried
+func (h *AsyncMessageHandler) updateStatusOnFailure(requestID string, finalAttempt bool, errMsg string) {
+	if !finalAttempt {
+		return
+	}
+	if err := h.updateStatus(requestID, async.StatusFailed); err != nil {
+		h.log.Errorw(errMsg, "id", requestID, "error", err)
+	}
+}
+
 func (h *AsyncMessageHandler) getPayload(requestID string) (io.ReadCloser, error) {
 	key := async.PayloadPath(h.storagePath, requestID)
 	output, err := h.aws.S3().GetObject(
@@ -215,6 +254,15 @@ func (h *AsyncMessageHandler) uploadResult(requestID string, result interface{})
 	return h.aws.UploadJSONToS3(result, h.config.Bucket, key)
 }
 
+// uploadResultToOutputPath writes the result to the user-specified output path, using the workload ID as the key
+func (h *AsyncMessageHandler) uploadResultToOutputPath(requestID string, result interface{}) error {
+	bucket, prefix, err := awslib.SplitS3Path(h.config.OutputPath)
+	if err != nil {
+		return err
+	}
+	return h.aws.UploadJSONToS3(result, bucket, path.Join(prefix, requestID))
+}
+
 func (h *AsyncMessageHandler) getHeaders(requestID string) (http.Header, error) {
 	key := async.HeadersPath(h.storagePath, requestID)
 
@@ -88,6 +88,120 @@ func TestAsyncMessageHandler_Handle(t *testing.T) {
 	require.Equal(t, 1, requestEventsCount)
 }
 
+func TestAsyncMessageHandler_Handle_OutputPath(t *testing.T) {
+	t.Parallel()
+
+	log := newLogger(t)
+	defer func() { _ = log.Sync() }()
+
+	awsClient := testAWSClient(t)
+
+	requestID := random.String(8)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"label": "cat"}`))
+	}))
+
+	outputBucket := "test-output"
+	asyncHandler := NewAsyncMessageHandler(AsyncMessageHandlerConfig{
+		ClusterUID: "cortex-test",
+		Bucket:     _testBucket,
+		APIName:    "async-test-output",
+		TargetURL:  server.URL,
+		OutputPath: "s3://" + outputBucket + "/results",
+	}, awsClient, NewRequestEventHandlerFunc(func(event RequestEvent) {}), log)
+
+	for _, bucket := range []string{_testBucket, outputBucket} {
+		_, err := awsClient.S3().CreateBucket(&s3.CreateBucketInput{
+			Bucket: aws.String(bucket),
+		})
+		require.NoError(t, err)
+	}
+
+	err := awsClient.UploadStringToS3("{}", asyncHandler.config.Bucket, async.PayloadPath(asyncHandler.storagePath, requestID))
+	require.NoError(t, err)
+
+	err = awsClient.UploadStringToS3("{}", asyncHandler.config.Bucket, async.HeadersPath(asyncHandler.storagePath, requestID))
+	require.NoError(t, err)
+
+	err = asyncHandler.Handle(&sqs.Message{
+		Body:      aws.String(requestID),
+		MessageId: aws.String(requestID),
+	})
+	require.NoError(t, err)
+
+	result, err := awsClient.ReadStringFromS3(outputBucket, "results/"+requestID)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"label": "cat"}`, result)
+}
+
+func TestAsyncMessageHandler_Handle_Retry(t *testing.T) {
+	t.Parallel()
+
+	log := newLogger(t)
+	defer func() { _ = log.Sync() }()
+
+	awsClient := testAWSClient(t)
+
+	requestID := random.String(8)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+
+	eventHandler := NewRequestEventHandlerFunc(func(event RequestEvent) {})
+
+	asyncHandler := NewAsyncMessageHandler(AsyncMessageHandlerConfig{
+		ClusterUID:      "cortex-test",
+		Bucket:          _testBucket,
+		APIName:         "async-test-retry",
+		TargetURL:       server.URL,
+		MaxReceiveCount: 2,
+	}, awsClient, eventHandler, log)
+
+	_, err := awsClient.S3().CreateBucket(&s3.CreateBucketInput{
+		Bucket: aws.String(_testBucket),
+	})
+	require.NoError(t, err)
+
+	err = awsClient.UploadStringToS3("{}", asyncHandler.config.Bucket, async.PayloadPath(asyncHandler.storagePath, requestID))
+	require.NoError(t, err)
+
+	err = awsClient.UploadStringToS3("{}", asyncHandler.config.Bucket, async.HeadersPath(asyncHandler.storagePath, requestID))
+	require.NoError(t, err)
+
+	failedStatusPath := fmt.Sprintf("%s/%s/status/%s", asyncHandler.storagePath, requestID, async.StatusFailed)
+
+	// first attempt: the workload is retried, so it isn't marked as failed
+	err = asyncHandler.Handle(&sqs.Message{
+		Body:      aws.String(requestID),
+		MessageId: aws.String(requestID),
+		Attributes: map[string]*string{
+			sqs.MessageSystemAttributeNameApproximateReceiveCount: aws.String("1"),
+		},
+	})
+	require.Error(t, err)
+
+	_, err = awsClient.ReadStringFromS3(_testBucket, failedStatusPath)
+	require.Error(t, err)
+
+	// final attempt: the workload is marked as failed and its payload is kept for redrive
+	err = asyncHandler.Handle(&sqs.Message{
+		Body:      aws.String(requestID),
+		MessageId: aws.String(requestID),
+		Attributes: map[string]*string{
+			sqs.MessageSystemAttributeNameApproximateReceiveCount: aws.String("2"),
+		},
+	})
+	require.Error(t, err)
+
+	_, err = awsClient.ReadStringFromS3(_testBucket, failedStatusPath)
+	require.NoError(t, err)
+
+	_, err = awsClient.ReadStringFromS3(_testBucket, async.PayloadPath(asyncHandler.storagePath, requestID))
+	require.NoError(t, err)
+}
+
 func TestAsyncMessageHandler_Handle_Errors(t *testing.T) {
 	t.Parallel()
 
@@ -19,6 +19,7 @@ package dequeuer
 import (
 	"bytes"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/DataDog/datadog-go/statsd"
@@ -27,6 +28,9 @@ import (
 	awslib "github.com/cortexlabs/cortex/pkg/lib/aws"
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
 	"github.com/cortexlabs/cortex/pkg/lib/urls"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
+	"github.com/cortexlabs/cortex/pkg/types/status"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
 	"github.com/xtgo/uuid"
 	"go.uber.org/zap"
 )
@@ -48,11 +52,14 @@ type BatchMessageHandler struct {
 }
 
 type BatchMessageHandlerConfig struct {
-	APIName   string
-	JobID     string
-	QueueURL  string
-	Region    string
-	TargetURL string
+	APIName    string
+	JobID      string
+	QueueURL   string
+	Region     string
+	TargetURL  string
+	ClusterUID string
+	Bucket     string // if set, failed batches are stored in the bucket so that they can be re-run
+	MaxRetries int    // number of times a failed batch is put back on the queue before it is considered failed
 }
 
 func NewBatchMessageHandler(config BatchMessageHandlerConfig, awsClient *awslib.Client, statsdClient statsd.ClientInterface, log *zap.SugaredLogger) *BatchMessageHandler {
@@ -151,15 +158,38 @@ func (h *BatchMessageHandler) handleBatch(message *sqs.Message) error {
 	err := h.submitRequest(*message.Body, false)
 	if err != nil {
 		h.log.Errorw("failed to process batch", "id", *message.MessageId, "error", err)
+		if receiveCount, finalAttempt := h.isFinalAttempt(message); !finalAttempt {
+			return ErrorBatchRetry(*message.MessageId, receiveCount)
+		}
 		recordFailureErr := h.recordFailure()
 		if recordFailureErr != nil {
 			return errors.Wrap(recordFailureErr, "failed to record failure metric")
 		}
+		if storeErr := h.storeFailedBatch(message); storeErr != nil {
+			return errors.Wrap(storeErr, "failed to store failed batch")
+		}
+		if storeErr := h.storeBatchState(message, status.BatchFailed); storeErr != nil {
+			return errors.Wrap(storeErr, "failed to store batch state")
+		}
 		return nil
 	}
 
 	endTime := time.Since(startTime)
 
+	// the batch may have failed in a previous attempt (if a dead letter queue or max retries is configured)
+	if receiveCount, ok := message.Attributes[sqs.MessageSystemAttributeNameApproximateReceiveCount]; ok && receiveCount != nil && *receiveCount != "1" {
+		if err := h.deleteFailedBatch(message); err != nil {
+			return errors.Wrap(err, "failed to delete failed batch")
+		}
+		if err := h.deleteBatchState(message, status.BatchFailed); err != nil {
+			return errors.Wrap(err, "failed to delete batch state")
+		}
+	}
+
+	if err := h.storeBatchState(message, status.BatchSucceeded); err != nil {
+		return errors.Wrap(err, "failed to store batch state")
+	}
+
 	err = h.recordSuccess()
 	if err != nil {
 		return errors.Wrap(err, "failed to record success metric")
@@ -172,6 +202,50 @@ func (h *BatchMessageHandler) handleBatch(message *sqs.Message) error {
 	return nil
 }
 
+// isFinalAttempt returns false if the batch will be retried in case of failure
+func (h *BatchMessageHandler) isFinalAttempt(message *sqs.Message) (int, bool) {
+	receiveCount, err := strconv.Atoi(aws.StringValue(message.Attributes[sqs.MessageSystemAttributeNameApproximateReceiveCount]))
+	if err != nil {
+		return 1, true
+	}
+
+	return receiveCount, receiveCount > h.config.MaxRetries
+}
+
+func (h *BatchMessageHandler) storeFailedBatch(message *sqs.Message) error {
+	if h.config.Bucket == "" {
+		return nil
+	}
+	key := spec.JobFailedBatchKey(h.config.ClusterUID, userconfig.BatchAPIKind, h.config.APIName, h.config.JobID, *message.MessageId)
+	return h.aws.UploadStringToS3(*message.Body, h.config.Bucket, key)
+}
+
+func (h *BatchMessageHandler) deleteFailedBatch(message *sqs.Message) error {
+	if h.config.Bucket == "" {
+		return nil
+	}
+	key := spec.JobFailedBatchKey(h.config.ClusterUID, userconfig.BatchAPIKind, h.config.APIName, h.config.JobID, *message.MessageId)
+	return h.aws.DeleteS3File(h.config.Bucket, key)
+}
+
+func (h *BatchMessageHandler) storeBatchState(message *sqs.Message, state status.BatchCode) error {
+	batchIndex, ok := getBatchIndex(message)
+	if h.config.Bucket == "" || !ok {
+		return nil
+	}
+	key := spec.JobBatchStateKey(h.config.ClusterUID, userconfig.BatchAPIKind, h.config.APIName, h.config.JobID, batchIndex, state.String())
+	return h.aws.UploadStringToS3("", h.config.Bucket, key)
+}
+
+func (h *BatchMessageHandler) deleteBatchState(message *sqs.Message, state status.BatchCode) error {
+	batchIndex, ok := getBatchIndex(message)
+	if h.config.Bucket == "" || !ok {
+		return nil
+	}
+	key := spec.JobBatchStateKey(h.config.ClusterUID, userconfig.BatchAPIKind, h.config.APIName, h.config.JobID, batchIndex, state.String())
+	return h.aws.DeleteS3File(h.config.Bucket, key)
+}
+
 func (h *BatchMessageHandler) onJobComplete(message *sqs.Message) error {
 	shouldRunOnJobComplete := false
 	h.log.Info("received job_complete message")
@@ -225,6 +299,19 @@ func (h *BatchMessageHandler) onJobComplete(message *sqs.Message) error {
 	}
 }
 
+// getBatchIndex returns the index of the batch, which is set by the enqueuer
+func getBatchIndex(message *sqs.Message) (int, bool) {
+	attribute, found := message.MessageAttributes["batch_index"]
+	if !found || attribute == nil {
+		return 0, false
+	}
+	batchIndex, err := strconv.Atoi(aws.StringValue(attribute.StringValue))
+	if err != nil {
+		return 0, false
+	}
+	return batchIndex, true
+}
+
 func isOnJobCompleteMessage(message *sqs.Message) bool {
 	_, found := message.MessageAttributes["job_complete"]
 	return found
@@ -23,7 +23,11 @@ import (
 
 	"github.com/DataDog/datadog-go/statsd"
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
 	"github.com/stretchr/testify/require"
 )
 
@@ -110,3 +114,125 @@ func TestBatchMessageHandler_Handle_OnJobComplete(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, callCount, 1)
 }
+
+func TestBatchMessageHandler_Handle_StoresFailedBatch(t *testing.T) {
+	t.Parallel()
+	awsClient := testAWSClient(t)
+
+	server := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}),
+	)
+
+	logger := newLogger(t)
+	defer func() { _ = logger.Sync() }()
+
+	bucket := "test-failed-batches"
+	_, err := awsClient.S3().CreateBucket(&s3.CreateBucketInput{
+		Bucket: aws.String(bucket),
+	})
+	require.NoError(t, err)
+
+	batchHandler := NewBatchMessageHandler(BatchMessageHandlerConfig{
+		APIName:    "test",
+		JobID:      "12345",
+		Region:     _localStackDefaultRegion,
+		TargetURL:  server.URL,
+		ClusterUID: "cortex-test",
+		Bucket:     bucket,
+	}, awsClient, &statsd.NoOpClient{}, logger)
+
+	err = batchHandler.Handle(&sqs.Message{
+		Body:      aws.String(`[{"id": 1}, {"id": 2}]`),
+		MessageId: aws.String("1"),
+	})
+	require.NoError(t, err)
+
+	failedBatchKey := spec.JobFailedBatchKey("cortex-test", userconfig.BatchAPIKind, "test", "12345", "1")
+	failedBatch, err := awsClient.ReadStringFromS3(bucket, failedBatchKey)
+	require.NoError(t, err)
+	require.Equal(t, `[{"id": 1}, {"id": 2}]`, failedBatch)
+
+	// the batch succeeds when it is retried
+	successServer := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}),
+	)
+	batchHandler.config.TargetURL = successServer.URL
+
+	err = batchHandler.Handle(&sqs.Message{
+		Body:      aws.String(`[{"id": 1}, {"id": 2}]`),
+		MessageId: aws.String("1"),
+		Attributes: map[string]*string{
+			sqs.MessageSystemAttributeNameApproximateReceiveCount: aws.String("2"),
+		},
+	})
+	require.NoError(t, err)
+
+	exists, err := awsClient.IsS3File(bucket, failedBatchKey)
+	require.NoError(t, err)
+	require.False(t, exists)
+}
+
+func TestBatchMessageHandler_Handle_RetriesFailedBatch(t *testing.T) {
+	t.Parallel()
+	awsClient := testAWSClient(t)
+
+	server := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}),
+	)
+
+	logger := newLogger(t)
+	defer func() { _ = logger.Sync() }()
+
+	batchHandler := NewBatchMessageHandler(BatchMessageHandlerConfig{
+		APIName:    "test",
+		JobID:      "12345",
+		Region:     _localStackDefaultRegion,
+		TargetURL:  server.URL,
+		ClusterUID: "cortex-test",
+		MaxRetries: 1,
+	}, awsClient, &statsd.NoOpClient{}, logger)
+
+	err := batchHandler.Handle(&sqs.Message{
+		Body:      aws.String(`[{"id": 1}, {"id": 2}]`),
+		MessageId: aws.String("1"),
+		Attributes: map[string]*string{
+			sqs.MessageSystemAttributeNameApproximateReceiveCount: aws.String("1"),
+		},
+	})
+	require.Error(t, err)
+	require.Equal(t, ErrBatchRetry, errors.GetKind(err))
+
+	// the batch is considered failed once the retries have been exhausted
+	err = batchHandler.Handle(&sqs.Message{
+		Body:      aws.String(`[{"id": 1}, {"id": 2}]`),
+		MessageId: aws.String("1"),
+		Attributes: map[string]*string{
+			sqs.MessageSystemAttributeNameApproximateReceiveCount: aws.String("2"),
+		},
+	})
+	require.NoError(t, err)
+}
+
+func TestGetBatchIndex(t *testing.T) {
+	t.Parallel()
+
+	batchIndex, ok := getBatchIndex(&sqs.Message{
+		MessageAttributes: map[string]*sqs.MessageAttributeValue{
+			"batch_index": {
+				DataType:    aws.String("Number"),
+				StringValue: aws.String("12"),
+			},
+		},
+	})
+	require.True(t, ok)
+	require.Equal(t, 12, batchIndex)
+
+	_, ok = getBatchIndex(&sqs.Message{})
+	require.False(t, ok)
+}
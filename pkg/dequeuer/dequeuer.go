@@ -41,7 +41,10 @@ type SQSDequeuerConfig struct {
 	Region           string
 	QueueURL         string
 	StopIfNoMessages bool
-	Workers          int
+	// stop as soon as there are no messages left to receive, even if other consumers are still processing messages
+	// (only applies if StopIfNoMessages is set)
+	StopIfNoVisibleMessages bool
+	Workers                 int
 }
 
 type SQSDequeuer struct {
@@ -82,6 +85,7 @@ func (d *SQSDequeuer) ReceiveMessage() (*sqs.Message, error) {
 		QueueUrl:              aws.String(d.config.QueueURL),
 		MaxNumberOfMessages:   aws.Int64(1),
 		MessageAttributeNames: aws.StringSlice(_messageAttributes),
+		AttributeNames:        aws.StringSlice([]string{sqs.MessageSystemAttributeNameApproximateReceiveCount}),
 		VisibilityTimeout:     d.visibilityTimeout,
 		WaitTimeSeconds:       d.waitTimeSeconds,
 	})
@@ -148,7 +152,12 @@ loop:
 					return err
 				}
 
-				if queueAttributes.TotalMessages() == 0 {
+				noMessagesLeft := queueAttributes.TotalMessages() == 0
+				if d.config.StopIfNoVisibleMessages {
+					noMessagesLeft = queueAttributes.VisibleMessages == 0
+				}
+
+				if noMessagesLeft {
 					if noMessagesInPreviousIteration && d.config.StopIfNoMessages {
 						d.log.Info("no messages found in queue, exiting ...")
 						return nil
@@ -183,9 +192,9 @@ func (d *SQSDequeuer) handleMessage(message *sqs.Message, messageHandler Message
 	done <- struct{}{}
 	isOnJobComplete := isOnJobCompleteMessage(message)
 
-	if messageErr != nil && d.hasDeadLetterQueue && !isOnJobComplete {
-		// expire messages when dead letter queue is configured to facilitate redrive policy.
-		// always delete onJobComplete messages regardless of redrive policy because a new one will
+	if messageErr != nil && (d.hasDeadLetterQueue || errors.GetKind(messageErr) == ErrBatchRetry) && !isOnJobComplete {
+		// expire messages when dead letter queue is configured to facilitate redrive policy,
+		// or when the batch will be retried. always delete onJobComplete messages regardless of redrive policy because a new one will
 		// be added if an onJobComplete message has been consumed prematurely
 		_, err := d.aws.SQS().ChangeMessageVisibility(
 			&sqs.ChangeMessageVisibilityInput{
@@ -292,6 +292,68 @@ func TestSQSDequeuerTerminationOnEmptyQueue(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestSQSDequeuerTerminationWhenNoVisibleMessages(t *testing.T) {
+	t.Parallel()
+
+	awsClient := testAWSClient(t)
+	queueURL := createQueue(t, awsClient)
+
+	logger := newLogger(t)
+	defer func() { _ = logger.Sync() }()
+
+	dq, err := NewSQSDequeuer(
+		SQSDequeuerConfig{
+			Region:                  _localStackDefaultRegion,
+			QueueURL:                queueURL,
+			StopIfNoMessages:        true,
+			StopIfNoVisibleMessages: true,
+			Workers:                 1,
+		}, awsClient, logger,
+	)
+	require.NoError(t, err)
+
+	dq.notFoundSleepTime = 0
+	dq.waitTimeSeconds = aws.Int64(0)
+
+	messageID := "12345"
+	messageBody := "blah"
+	_, err = awsClient.SQS().SendMessage(&sqs.SendMessageInput{
+		MessageBody:            aws.String(messageBody),
+		MessageDeduplicationId: aws.String(messageID),
+		MessageGroupId:         aws.String(messageID),
+		QueueUrl:               aws.String(queueURL),
+	})
+	require.NoError(t, err)
+
+	// the message is being processed by another consumer
+	message, err := dq.ReceiveMessage()
+	require.NoError(t, err)
+	require.NotNil(t, message)
+
+	done := dq.StartMessageRenewer(*message.ReceiptHandle)
+	defer func() {
+		done <- struct{}{}
+	}()
+
+	msgHandler := &messageHandlerFunc{
+		HandleFunc: func(msg *sqs.Message) error {
+			return nil
+		},
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- dq.Start(msgHandler, func() bool {
+			return true
+		})
+	}()
+
+	time.AfterFunc(10*time.Second, func() { errCh <- errors.New("timeout: dequeuer did not finish") })
+
+	err = <-errCh
+	require.NoError(t, err)
+}
+
 func TestSQSDequeuer_Shutdown(t *testing.T) {
 	t.Parallel()
 
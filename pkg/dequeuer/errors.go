@@ -27,6 +27,7 @@ const (
 	ErrUserContainerResponseMissingJSONHeader = "dequeuer.user_container_response_missing_json_header"
 	ErrUserContainerResponseNotJSONDecodable  = "dequeuer.user_container_response_not_json_decodable"
 	ErrUserContainerNotReachable              = "dequeuer.user_container_not_reachable"
+	ErrBatchRetry                             = "dequeuer.batch_retry"
 )
 
 func ErrorUserContainerResponseStatusCode(statusCode int) error {
@@ -60,3 +61,11 @@ func ErrorUserContainerNotReachable(err error) error {
 		NoTelemetry: true,
 	}
 }
+
+func ErrorBatchRetry(messageID string, receiveCount int) error {
+	return &errors.Error{
+		Kind:        ErrBatchRetry,
+		Message:     fmt.Sprintf("batch %s failed on attempt %d and will be retried", messageID, receiveCount),
+		NoTelemetry: true,
+	}
+}
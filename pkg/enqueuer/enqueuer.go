@@ -72,10 +72,16 @@ type DelimitedFiles struct {
 	BatchSize int `json:"batch_size"`
 }
 
+type FileManifest struct {
+	S3Path    string `json:"s3_path"`
+	BatchSize int    `json:"batch_size"`
+}
+
 type JobSubmission struct {
 	ItemList       *ItemList       `json:"item_list"`
 	FilePathLister *FilePathLister `json:"file_path_lister"`
 	DelimitedFiles *DelimitedFiles `json:"delimited_files"`
+	FileManifest   *FileManifest   `json:"file_manifest"`
 }
 
 type onJobCompleteRequestBody struct {
@@ -129,6 +135,11 @@ func (e *Enqueuer) Enqueue() (int, error) {
 		if err != nil {
 			return 0, err
 		}
+	} else if submission.FileManifest != nil {
+		totalBatches, err = e.enqueueManifestS3Paths(submission.FileManifest)
+		if err != nil {
+			return 0, err
+		}
 	}
 
 	onJobCompleteBodyBytes, err := json.Marshal(onJobCompleteRequestBody{
@@ -295,6 +306,52 @@ func (e *Enqueuer) enqueueS3Paths(s3PathsLister *FilePathLister) (int, error) {
 	return uploader.TotalBatches, nil
 }
 
+func (e *Enqueuer) enqueueManifestS3Paths(fileManifest *FileManifest) (int, error) {
+	log := e.logger
+
+	awsClientForBucket, err := awslib.NewFromClientS3Path(fileManifest.S3Path, e.aws)
+	if err != nil {
+		return 0, err
+	}
+
+	var s3PathList []string
+	uploader := newSQSBatchUploader(e.envConfig.APIName, e.envConfig.JobID, e.queueURL, e.aws.SQS())
+
+	err = awsClientForBucket.S3ManifestIterator(fileManifest.S3Path, func(bucket string, key string) (bool, error) {
+		s3PathList = append(s3PathList, awslib.S3Path(bucket, key))
+		if len(s3PathList) == fileManifest.BatchSize {
+			err := addS3PathsToQueue(uploader, s3PathList)
+			if err != nil {
+				return false, err
+			}
+			s3PathList = nil
+
+			if uploader.TotalBatches%100 == 0 {
+				log.Info("enqueued batches", zap.Int("numBatches", uploader.TotalBatches))
+			}
+		}
+
+		return true, nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if len(s3PathList) > 0 {
+		err := addS3PathsToQueue(uploader, s3PathList)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	err = uploader.Flush()
+	if err != nil {
+		return 0, err
+	}
+
+	return uploader.TotalBatches, nil
+}
+
 func (e *Enqueuer) enqueueS3FileContents(delimitedFiles *DelimitedFiles) (int, error) {
 	log := e.logger
 
@@ -18,6 +18,7 @@ package enqueuer
 
 import (
 	"fmt"
+	"strconv"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/sqs"
@@ -66,8 +67,18 @@ func (uploader *sqsBatchUploader) AddToBatch(id string, body *string) error {
 		return ErrorMessageExceedsMaxSize(len(*body), _messageSizeLimit)
 	}
 
+	messageAttributes := make(map[string]*sqs.MessageAttributeValue, len(uploader.messageAttributes)+1)
+	for key, value := range uploader.messageAttributes {
+		messageAttributes[key] = value
+	}
+	// used by the dequeuer to report the state of each batch
+	messageAttributes["batch_index"] = &sqs.MessageAttributeValue{
+		DataType:    aws.String("Number"),
+		StringValue: aws.String(strconv.Itoa(uploader.TotalBatches)),
+	}
+
 	message := &sqs.SendMessageBatchRequestEntry{
-		MessageAttributes:      uploader.messageAttributes,
+		MessageAttributes:      messageAttributes,
 		Id:                     aws.String(id),
 		MessageBody:            body,
 		MessageDeduplicationId: aws.String(id), // prevent content based deduping
@@ -36,3 +36,96 @@ func (c *Client) DoesCertificateExist(sslCertificateARN string) (bool, error) {
 
 	return true, nil
 }
+
+// RequestCertificate requests a DNS-validated certificate for the domain, and returns the certificate's ARN
+func (c *Client) RequestCertificate(domain string, tags map[string]string) (string, error) {
+	var acmTags []*acm.Tag
+	for key, value := range tags {
+		acmTags = append(acmTags, &acm.Tag{
+			Key:   aws.String(key),
+			Value: aws.String(value),
+		})
+	}
+
+	output, err := c.ACM().RequestCertificate(&acm.RequestCertificateInput{
+		DomainName:       aws.String(domain),
+		ValidationMethod: aws.String(acm.ValidationMethodDns),
+		Tags:             acmTags,
+	})
+	if err != nil {
+		return "", errors.Wrap(err, domain)
+	}
+
+	return *output.CertificateArn, nil
+}
+
+// ListCertificatesWithTags returns the details of all certificates which have all of the specified tags
+func (c *Client) ListCertificatesWithTags(tags map[string]string) ([]*acm.CertificateDetail, error) {
+	var certificateARNs []string
+	err := c.ACM().ListCertificatesPages(&acm.ListCertificatesInput{},
+		func(page *acm.ListCertificatesOutput, lastPage bool) bool {
+			for _, summary := range page.CertificateSummaryList {
+				if summary.CertificateArn != nil {
+					certificateARNs = append(certificateARNs, *summary.CertificateArn)
+				}
+			}
+			return true
+		})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	var certificates []*acm.CertificateDetail
+	for _, certificateARN := range certificateARNs {
+		tagsOutput, err := c.ACM().ListTagsForCertificate(&acm.ListTagsForCertificateInput{
+			CertificateArn: aws.String(certificateARN),
+		})
+		if err != nil {
+			if IsErrCode(err, acm.ErrCodeResourceNotFoundException) {
+				continue
+			}
+			return nil, errors.Wrap(err, certificateARN)
+		}
+
+		certificateTags := make(map[string]string, len(tagsOutput.Tags))
+		for _, tag := range tagsOutput.Tags {
+			if tag.Key != nil && tag.Value != nil {
+				certificateTags[*tag.Key] = *tag.Value
+			}
+		}
+
+		missingTag := false
+		for key, value := range tags {
+			if certificateTags[key] != value {
+				missingTag = true
+				break
+			}
+		}
+		if missingTag {
+			continue
+		}
+
+		output, err := c.ACM().DescribeCertificate(&acm.DescribeCertificateInput{
+			CertificateArn: aws.String(certificateARN),
+		})
+		if err != nil {
+			if IsErrCode(err, acm.ErrCodeResourceNotFoundException) {
+				continue
+			}
+			return nil, errors.Wrap(err, certificateARN)
+		}
+		certificates = append(certificates, output.Certificate)
+	}
+
+	return certificates, nil
+}
+
+func (c *Client) DeleteCertificate(certificateARN string) error {
+	_, err := c.ACM().DeleteCertificate(&acm.DeleteCertificateInput{
+		CertificateArn: aws.String(certificateARN),
+	})
+	if err != nil && !IsErrCode(err, acm.ErrCodeResourceNotFoundException) {
+		return errors.Wrap(err, certificateARN)
+	}
+	return nil
+}
@@ -80,3 +80,57 @@ func (c *Client) MostRecentASGActivity(asgName string) (*autoscaling.Activity, e
 
 	return resp.Activities[0], nil
 }
+
+// Returns a map of instance ID -> autoscaling group name (instances which do not belong to an autoscaling group are omitted)
+func (c *Client) AutoscalingGroupNamesForInstances(instanceIDs ...string) (map[string]string, error) {
+	asgNames := map[string]string{}
+	if len(instanceIDs) == 0 {
+		return asgNames, nil
+	}
+
+	err := c.Autoscaling().DescribeAutoScalingInstancesPages(&autoscaling.DescribeAutoScalingInstancesInput{
+		InstanceIds: aws.StringSlice(instanceIDs),
+	}, func(page *autoscaling.DescribeAutoScalingInstancesOutput, lastPage bool) bool {
+		for _, instance := range page.AutoScalingInstances {
+			if instance == nil || instance.InstanceId == nil || instance.AutoScalingGroupName == nil {
+				continue
+			}
+			asgNames[*instance.InstanceId] = *instance.AutoScalingGroupName
+		}
+		return true
+	})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return asgNames, nil
+}
+
+// Detaches the instance from its autoscaling group without decrementing the desired capacity, so that the group immediately launches a replacement
+// (the detached instance keeps running until it is terminated)
+func (c *Client) DetachInstanceForReplacement(asgName string, instanceID string) error {
+	_, err := c.Autoscaling().DetachInstances(&autoscaling.DetachInstancesInput{
+		AutoScalingGroupName:           aws.String(asgName),
+		InstanceIds:                    []*string{aws.String(instanceID)},
+		ShouldDecrementDesiredCapacity: aws.Bool(false),
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	return nil
+}
+
+// Sets the min and max size of the autoscaling group (the desired capacity is adjusted by AWS if it falls outside of the new bounds)
+func (c *Client) UpdateAutoscalingGroupSize(asgName string, minSize int64, maxSize int64) error {
+	_, err := c.Autoscaling().UpdateAutoScalingGroup(&autoscaling.UpdateAutoScalingGroupInput{
+		AutoScalingGroupName: aws.String(asgName),
+		MinSize:              aws.Int64(minSize),
+		MaxSize:              aws.Int64(maxSize),
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	libjson "github.com/cortexlabs/cortex/pkg/lib/json"
+)
+
+// AvailabilityZonesCacheDir is the directory in which the availability zones supported by each instance type are cached
+// (one file per region); caching is disabled if it is empty
+var AvailabilityZonesCacheDir string
+
+// AvailabilityZonesCacheTTL is how long cached availability zones are considered valid
+var AvailabilityZonesCacheTTL = 24 * time.Hour
+
+var _availabilityZonesCacheLock sync.Mutex
+
+type availabilityZonesCacheEntry struct {
+	Zones     []string  `json:"zones"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// instance type -> cache entry
+type availabilityZonesCache map[string]availabilityZonesCacheEntry
+
+func availabilityZonesCachePath(dir string, region string) string {
+	return filepath.Join(dir, region+".json")
+}
+
+// returns the cached zones for the instance type, or nil if they are not cached or the cache entry has expired
+func (cache availabilityZonesCache) get(instanceType string, ttl time.Duration, now time.Time) []string {
+	entry, ok := cache[instanceType]
+	if !ok || len(entry.Zones) == 0 {
+		return nil
+	}
+	if now.Sub(entry.Timestamp) > ttl || entry.Timestamp.After(now) {
+		return nil
+	}
+	return entry.Zones
+}
+
+// a missing or unreadable cache file is treated as an empty cache
+func readAvailabilityZonesCache(path string) availabilityZonesCache {
+	cache := availabilityZonesCache{}
+
+	cacheBytes, err := os.ReadFile(path)
+	if err != nil {
+		return cache
+	}
+	if err := libjson.Unmarshal(cacheBytes, &cache); err != nil {
+		return availabilityZonesCache{}
+	}
+
+	return cache
+}
+
+func writeAvailabilityZonesCache(path string, cache availabilityZonesCache) error {
+	cacheBytes, err := libjson.Marshal(cache)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return errors.WithStack(err)
+	}
+
+	// write to a temporary file and rename it, so that concurrent cortex commands never read a partially written file
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, cacheBytes, 0644); err != nil {
+		return errors.WithStack(err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return errors.WithStack(err)
+	}
+
+	return nil
+}
+
+// returns the cached zones for each instance type which has a valid cache entry (instance type -> zones)
+func (c *Client) cachedSupportedAvailabilityZones(instanceTypes []string) map[string][]string {
+	cachedZones := map[string][]string{}
+	if AvailabilityZonesCacheDir == "" {
+		return cachedZones
+	}
+
+	_availabilityZonesCacheLock.Lock()
+	defer _availabilityZonesCacheLock.Unlock()
+
+	cache := readAvailabilityZonesCache(availabilityZonesCachePath(AvailabilityZonesCacheDir, c.Region))
+	now := time.Now()
+	for _, instanceType := range instanceTypes {
+		if zones := cache.get(instanceType, AvailabilityZonesCacheTTL, now); zones != nil {
+			cachedZones[instanceType] = zones
+		}
+	}
+
+	return cachedZones
+}
+
+// the cache is best-effort, so errors are ignored
+func (c *Client) cacheSupportedAvailabilityZones(zonesByInstanceType map[string][]string) {
+	if AvailabilityZonesCacheDir == "" || len(zonesByInstanceType) == 0 {
+		return
+	}
+
+	_availabilityZonesCacheLock.Lock()
+	defer _availabilityZonesCacheLock.Unlock()
+
+	path := availabilityZonesCachePath(AvailabilityZonesCacheDir, c.Region)
+	cache := readAvailabilityZonesCache(path)
+	now := time.Now()
+	for instanceType, zones := range zonesByInstanceType {
+		if len(zones) == 0 {
+			continue
+		}
+		cache[instanceType] = availabilityZonesCacheEntry{
+			Zones:     zones,
+			Timestamp: now,
+		}
+	}
+
+	_ = writeAvailabilityZonesCache(path, cache)
+}
@@ -0,0 +1,51 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAvailabilityZonesCache(t *testing.T) {
+	path := availabilityZonesCachePath(t.TempDir(), "us-west-2")
+	now := time.Now()
+
+	// a missing file is an empty cache
+	cache := readAvailabilityZonesCache(path)
+	require.Empty(t, cache)
+
+	cache["g4dn.xlarge"] = availabilityZonesCacheEntry{
+		Zones:     []string{"us-west-2a", "us-west-2b"},
+		Timestamp: now.Add(-time.Hour),
+	}
+	cache["p3.2xlarge"] = availabilityZonesCacheEntry{
+		Zones:     []string{"us-west-2c"},
+		Timestamp: now.Add(-48 * time.Hour),
+	}
+	require.NoError(t, writeAvailabilityZonesCache(path, cache))
+	require.Equal(t, "us-west-2.json", filepath.Base(path))
+
+	cache = readAvailabilityZonesCache(path)
+	require.Equal(t, []string{"us-west-2a", "us-west-2b"}, cache.get("g4dn.xlarge", 24*time.Hour, now))
+	require.Nil(t, cache.get("g4dn.xlarge", 30*time.Minute, now))
+	require.Nil(t, cache.get("p3.2xlarge", 24*time.Hour, now))
+	require.Nil(t, cache.get("t3.medium", 24*time.Hour, now))
+}
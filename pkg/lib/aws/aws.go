@@ -20,8 +20,11 @@ limitations under the License.
 package aws
 
 import (
+	"time"
+
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
 )
@@ -33,6 +36,7 @@ type Client struct {
 	clients         clients
 	accountID       *string
 	hashedAccountID *string
+	mfaTokens       *mfaTokenSource // nil if the client's profile doesn't require mfa
 }
 
 func NewForSession(sess *session.Session) (*Client, error) {
@@ -42,7 +46,7 @@ func NewForSession(sess *session.Session) (*Client, error) {
 
 	return &Client{
 		Region: *sess.Config.Region,
-		sess:   sess,
+		sess:   withRetryer(sess),
 	}, nil
 }
 
@@ -76,65 +80,130 @@ func NewFromS3Bucket(bucket string) (*Client, error) {
 }
 
 func NewForRegion(region string) (*Client, error) {
-	sess, err := session.NewSessionWithOptions(session.Options{
-		Config: aws.Config{
-			Region: aws.String(region),
-		},
+	return NewForProfile(region, "")
+}
+
+// NewForProfile returns a client which uses the credentials of the profile in the shared aws config files (or of the default credential chain if profile is empty),
+// which may be static keys, an sso session, a credential_process, or the ecs/ec2 instance metadata; if region is empty, the profile's region is used
+func NewForProfile(region string, profile string) (*Client, error) {
+	sessOptions := session.Options{
+		Profile:           profile,
 		SharedConfigState: session.SharedConfigEnable,
-	})
+	}
+	if region != "" {
+		sessOptions.Config.Region = aws.String(region)
+	}
+
+	sharedConfig, err := loadSharedConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	// the sdk doesn't resolve profiles which reference an [sso-session] section
+	ssoProfile, err := getSSOSessionProfile(sharedConfig, resolvedProfileName(profile))
+	if err != nil {
+		return nil, err
+	}
+	if ssoProfile != nil {
+		sessOptions.Config.Credentials, err = newSSOSessionCredentials(*ssoProfile)
+		if err != nil {
+			return nil, err
+		}
+	}
 
+	var mfaTokens *mfaTokenSource
+	if mfaProfile := getMFAProfile(sharedConfig, resolvedProfileName(profile)); mfaProfile != nil {
+		mfaTokens = &mfaTokenSource{profile: *mfaProfile}
+	}
+	if mfaTokens != nil && mfaTokens.profile.AssumesRole {
+		// the sdk prompts for the code when it assumes (or re-assumes) the role
+		sessOptions.AssumeRoleDuration = time.Hour
+		if MFATokenProvider != nil {
+			sessOptions.AssumeRoleTokenProvider = mfaTokens.tokenCode
+		}
+	}
+
+	sess, err := session.NewSessionWithOptions(sessOptions)
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
 
+	if mfaTokens != nil && !mfaTokens.profile.AssumesRole {
+		sess = sess.Copy(&aws.Config{
+			Credentials: newMFASessionCredentials(sess, mfaTokens),
+		})
+	}
+
+	if sess.Config.Region == nil || *sess.Config.Region == "" {
+		return nil, ErrorRegionNotConfigured()
+	}
+
 	if sess.Config.Credentials == nil {
 		return nil, ErrorUnableToFindCredentials()
 	}
 
 	creds, err := sess.Config.Credentials.Get()
 	if err != nil {
+		if isSSOLoginRequiredErr(err) {
+			return nil, ErrorSSOLoginRequired(resolvedProfileName(profile))
+		}
+		if errors.GetKind(err) == ErrMFASession || errors.GetKind(err) == ErrMFATokenRequired {
+			return nil, err
+		}
 		return nil, ErrorUnableToFindCredentials()
 	}
 
+	// make sure that credential exists
 	if creds.AccessKeyID == "" || creds.SecretAccessKey == "" {
 		return nil, ErrorUnexpectedMissingCredentials(creds.AccessKeyID, creds.SecretAccessKey)
 	}
 
 	return &Client{
-		sess:   sess,
-		Region: region,
+		sess:      withRetryer(sess),
+		Region:    *sess.Config.Region,
+		mfaTokens: mfaTokens,
 	}, nil
 }
 
-func New() (*Client, error) {
-	sess := session.Must(session.NewSessionWithOptions(session.Options{
-		SharedConfigState: session.SharedConfigEnable,
-	}))
-
-	if sess.Config.Region == nil {
-		return nil, ErrorRegionNotConfigured()
+// NewForRole returns a client for the region which is authenticated as the role, which is assumed with the credentials of the default credential chain
+// (and with the external id, if not empty); the role's temporary credentials are refreshed before they expire
+func NewForRole(region string, roleARN string, externalID string, sessionName string) (*Client, error) {
+	baseClient, err := NewForRegion(region)
+	if err != nil {
+		return nil, err
 	}
 
-	if sess.Config.Credentials == nil {
-		return nil, ErrorUnableToFindCredentials()
-	}
+	creds := stscreds.NewCredentials(baseClient.sess, roleARN, func(provider *stscreds.AssumeRoleProvider) {
+		provider.RoleSessionName = sessionName
+		provider.Duration = time.Hour // the maximum for roles which don't raise their max session duration
+		provider.ExpiryWindow = time.Minute
+		if externalID != "" {
+			provider.ExternalID = aws.String(externalID)
+		}
+	})
 
-	creds, err := sess.Config.Credentials.Get()
-	if err != nil {
-		return nil, ErrorUnableToFindCredentials()
+	if _, err := creds.Get(); err != nil {
+		return nil, ErrorAssumeRole(roleARN, err)
 	}
 
-	// make sure that credential exists
-	if creds.AccessKeyID == "" || creds.SecretAccessKey == "" {
-		return nil, ErrorUnexpectedMissingCredentials(creds.AccessKeyID, creds.SecretAccessKey)
+	sess, err := session.NewSession(&aws.Config{
+		Region:      aws.String(region),
+		Credentials: creds,
+	})
+	if err != nil {
+		return nil, errors.WithStack(err)
 	}
 
 	return &Client{
-		sess:   sess,
-		Region: *sess.Config.Region,
+		sess:   withRetryer(sess),
+		Region: region,
 	}, nil
 }
 
+func New() (*Client, error) {
+	return NewForProfile("", "")
+}
+
 func NewAnonymousClientWithRegion(region string) (*Client, error) {
 	sess, err := session.NewSession(&aws.Config{
 		Credentials: credentials.AnonymousCredentials,
@@ -144,7 +213,7 @@ func NewAnonymousClientWithRegion(region string) (*Client, error) {
 		return nil, err
 	}
 	return &Client{
-		sess:        sess,
+		sess:        withRetryer(sess),
 		Region:      region,
 		IsAnonymous: true,
 	}, nil
@@ -0,0 +1,115 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+)
+
+const _capacityReservationPlatform = "Linux/UNIX"
+
+// CreateCapacityReservation creates a "targeted" On-Demand Capacity Reservation (i.e. only instances which explicitly target the reservation will use it),
+// which remains active until it is released
+func (c *Client) CreateCapacityReservation(instanceType string, availabilityZone string, instanceCount int64, tags map[string]string) (*ec2.CapacityReservation, error) {
+	input := &ec2.CreateCapacityReservationInput{
+		InstanceType:          aws.String(instanceType),
+		InstancePlatform:      aws.String(_capacityReservationPlatform),
+		AvailabilityZone:      aws.String(availabilityZone),
+		InstanceCount:         aws.Int64(instanceCount),
+		InstanceMatchCriteria: aws.String(ec2.InstanceMatchCriteriaTargeted),
+		EndDateType:           aws.String(ec2.EndDateTypeUnlimited),
+	}
+
+	if len(tags) > 0 {
+		ec2Tags := make([]*ec2.Tag, 0, len(tags))
+		for key, value := range tags {
+			ec2Tags = append(ec2Tags, &ec2.Tag{Key: aws.String(key), Value: aws.String(value)})
+		}
+		input.TagSpecifications = []*ec2.TagSpecification{
+			{
+				ResourceType: aws.String(ec2.ResourceTypeCapacityReservation),
+				Tags:         ec2Tags,
+			},
+		}
+	}
+
+	output, err := c.EC2().CreateCapacityReservation(input)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return output.CapacityReservation, nil
+}
+
+// GetCapacityReservationOrNil returns nil if the capacity reservation does not exist
+func (c *Client) GetCapacityReservationOrNil(capacityReservationID string) (*ec2.CapacityReservation, error) {
+	output, err := c.EC2().DescribeCapacityReservations(&ec2.DescribeCapacityReservationsInput{
+		CapacityReservationIds: []*string{aws.String(capacityReservationID)},
+	})
+	if err != nil {
+		if IsErrCode(err, "InvalidCapacityReservationId.NotFound") || IsErrCode(err, "InvalidCapacityReservationId.Malformed") {
+			return nil, nil
+		}
+		return nil, errors.WithStack(err)
+	}
+
+	for _, capacityReservation := range output.CapacityReservations {
+		if capacityReservation != nil && aws.StringValue(capacityReservation.CapacityReservationId) == capacityReservationID {
+			return capacityReservation, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (c *Client) ListCapacityReservations(tags ...ec2.Tag) ([]ec2.CapacityReservation, error) {
+	var capacityReservations []ec2.CapacityReservation
+	err := c.EC2().DescribeCapacityReservationsPages(&ec2.DescribeCapacityReservationsInput{}, func(output *ec2.DescribeCapacityReservationsOutput, lastPage bool) bool {
+		if output == nil {
+			return false
+		}
+		for _, capacityReservation := range output.CapacityReservations {
+			if capacityReservation == nil {
+				continue
+			}
+			if hasAllEC2Tags(tags, capacityReservation.Tags) {
+				capacityReservations = append(capacityReservations, *capacityReservation)
+			}
+		}
+
+		return true
+	})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return capacityReservations, nil
+}
+
+// ReleaseCapacityReservation cancels the capacity reservation; running instances which were using the reservation are not affected (they will be billed at on-demand rates)
+func (c *Client) ReleaseCapacityReservation(capacityReservationID string) error {
+	_, err := c.EC2().CancelCapacityReservation(&ec2.CancelCapacityReservationInput{
+		CapacityReservationId: aws.String(capacityReservationID),
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	return nil
+}
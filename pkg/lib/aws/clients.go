@@ -17,22 +17,29 @@ limitations under the License.
 package aws
 
 import (
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/acm"
 	"github.com/aws/aws-sdk-go/service/apigatewayv2"
 	"github.com/aws/aws-sdk-go/service/autoscaling"
 	"github.com/aws/aws-sdk-go/service/cloudformation"
 	"github.com/aws/aws-sdk-go/service/cloudwatch"
 	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go/service/costexplorer"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/ecr"
 	"github.com/aws/aws-sdk-go/service/eks"
 	"github.com/aws/aws-sdk-go/service/elb"
 	"github.com/aws/aws-sdk-go/service/elbv2"
 	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/kinesis"
+	"github.com/aws/aws-sdk-go/service/pricing"
+	"github.com/aws/aws-sdk-go/service/route53"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
 	"github.com/aws/aws-sdk-go/service/servicequotas"
 	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/aws/aws-sdk-go/service/ssm"
 	"github.com/aws/aws-sdk-go/service/sts"
 )
 
@@ -48,6 +55,7 @@ type clients struct {
 	eks            *eks.EKS
 	ecr            *ecr.ECR
 	acm            *acm.ACM
+	route53        *route53.Route53
 	autoscaling    *autoscaling.AutoScaling
 	cloudWatchLogs *cloudwatchlogs.CloudWatchLogs
 	cloudWatch     *cloudwatch.CloudWatch
@@ -55,6 +63,11 @@ type clients struct {
 	serviceQuotas  *servicequotas.ServiceQuotas
 	cloudFormation *cloudformation.CloudFormation
 	iam            *iam.IAM
+	kinesis        *kinesis.Kinesis
+	secretsManager *secretsmanager.SecretsManager
+	ssm            *ssm.SSM
+	pricing        *pricing.Pricing
+	costExplorer   *costexplorer.CostExplorer
 }
 
 func (c *Client) S3() *s3.S3 {
@@ -148,6 +161,13 @@ func (c *Client) ACM() *acm.ACM {
 	return c.clients.acm
 }
 
+func (c *Client) Route53() *route53.Route53 {
+	if c.clients.route53 == nil {
+		c.clients.route53 = route53.New(c.sess)
+	}
+	return c.clients.route53
+}
+
 func (c *Client) CloudWatchLogs() *cloudwatchlogs.CloudWatchLogs {
 	if c.clients.cloudWatchLogs == nil {
 		c.clients.cloudWatchLogs = cloudwatchlogs.New(c.sess)
@@ -182,3 +202,40 @@ func (c *Client) IAM() *iam.IAM {
 	}
 	return c.clients.iam
 }
+
+func (c *Client) Kinesis() *kinesis.Kinesis {
+	if c.clients.kinesis == nil {
+		c.clients.kinesis = kinesis.New(c.sess)
+	}
+	return c.clients.kinesis
+}
+
+func (c *Client) SecretsManager() *secretsmanager.SecretsManager {
+	if c.clients.secretsManager == nil {
+		c.clients.secretsManager = secretsmanager.New(c.sess)
+	}
+	return c.clients.secretsManager
+}
+
+func (c *Client) SSM() *ssm.SSM {
+	if c.clients.ssm == nil {
+		c.clients.ssm = ssm.New(c.sess)
+	}
+	return c.clients.ssm
+}
+
+func (c *Client) Pricing() *pricing.Pricing {
+	if c.clients.pricing == nil {
+		// the pricing API is only served from a few regions, and us-east-1 returns prices for all regions
+		c.clients.pricing = pricing.New(c.sess, aws.NewConfig().WithRegion(_pricingAPIRegion))
+	}
+	return c.clients.pricing
+}
+
+func (c *Client) CostExplorer() *costexplorer.CostExplorer {
+	if c.clients.costExplorer == nil {
+		// cost explorer is a global service which is only served from us-east-1
+		c.clients.costExplorer = costexplorer.New(c.sess, aws.NewConfig().WithRegion(_costExplorerRegion))
+	}
+	return c.clients.costExplorer
+}
@@ -18,6 +18,7 @@ package aws
 
 import (
 	"encoding/json"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/cloudwatch"
@@ -135,6 +136,51 @@ func (c *Client) TagLogGroup(logGroup string, tagMap map[string]string) error {
 	return nil
 }
 
+// RunLogsInsightsQuery runs a CloudWatch Logs Insights query and waits for it to complete; each result maps the query's fields to their values
+func (c *Client) RunLogsInsightsQuery(logGroup string, query string, startTime time.Time, endTime time.Time, limit int64, timeout time.Duration) ([]map[string]string, error) {
+	startQueryOutput, err := c.CloudWatchLogs().StartQuery(&cloudwatchlogs.StartQueryInput{
+		LogGroupName: aws.String(logGroup),
+		QueryString:  aws.String(query),
+		StartTime:    aws.Int64(startTime.Unix()),
+		EndTime:      aws.Int64(endTime.Unix()),
+		Limit:        aws.Int64(limit),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "log group "+logGroup)
+	}
+
+	queryStartTime := time.Now()
+	for {
+		resultsOutput, err := c.CloudWatchLogs().GetQueryResults(&cloudwatchlogs.GetQueryResultsInput{
+			QueryId: startQueryOutput.QueryId,
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "log group "+logGroup)
+		}
+
+		switch aws.StringValue(resultsOutput.Status) {
+		case cloudwatchlogs.QueryStatusComplete:
+			results := make([]map[string]string, 0, len(resultsOutput.Results))
+			for _, fields := range resultsOutput.Results {
+				result := make(map[string]string, len(fields))
+				for _, field := range fields {
+					result[aws.StringValue(field.Field)] = aws.StringValue(field.Value)
+				}
+				results = append(results, result)
+			}
+			return results, nil
+		case cloudwatchlogs.QueryStatusScheduled, cloudwatchlogs.QueryStatusRunning:
+			if time.Since(queryStartTime) > timeout {
+				_, _ = c.CloudWatchLogs().StopQuery(&cloudwatchlogs.StopQueryInput{QueryId: startQueryOutput.QueryId})
+				return nil, ErrorLogsInsightsQuery(logGroup, cloudwatchlogs.QueryStatusTimeout)
+			}
+			time.Sleep(time.Second)
+		default:
+			return nil, ErrorLogsInsightsQuery(logGroup, aws.StringValue(resultsOutput.Status))
+		}
+	}
+}
+
 // NewDashboard creates a new dashboard object with title
 func (c *Client) NewDashboard(title string) *CloudWatchDashboard {
 	return &CloudWatchDashboard{
@@ -0,0 +1,152 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/costexplorer"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+)
+
+const (
+	_costExplorerRegion     = "us-east-1"
+	_costExplorerDateFormat = "2006-01-02"
+)
+
+const (
+	CostCategoryEC2        = "ec2"
+	CostCategoryEBS        = "ebs"
+	CostCategoryNATGateway = "nat gateway"
+	CostCategoryELB        = "elb"
+	CostCategoryOther      = "other"
+)
+
+var CostCategories = []string{CostCategoryEC2, CostCategoryEBS, CostCategoryNATGateway, CostCategoryELB, CostCategoryOther}
+
+type CostGroup struct {
+	UsageType string
+	TagValue  string // empty if the resource does not have the group-by tag
+	Amount    float64
+}
+
+// CostByTag returns the unblended cost (in USD) of the resources tagged with filterTagKey=filterTagValue between start (inclusive) and end (exclusive),
+// grouped by usage type and by the value of groupByTagKey; both tags must be activated as cost allocation tags in the billing console
+func (c *Client) CostByTag(start time.Time, end time.Time, filterTagKey string, filterTagValue string, groupByTagKey string) ([]CostGroup, error) {
+	input := &costexplorer.GetCostAndUsageInput{
+		TimePeriod: &costexplorer.DateInterval{
+			Start: aws.String(start.UTC().Format(_costExplorerDateFormat)),
+			End:   aws.String(end.UTC().Format(_costExplorerDateFormat)),
+		},
+		Granularity: aws.String(costexplorer.GranularityMonthly),
+		Metrics:     aws.StringSlice([]string{costexplorer.MetricUnblendedCost}),
+		Filter: &costexplorer.Expression{
+			Tags: &costexplorer.TagValues{
+				Key:          aws.String(filterTagKey),
+				Values:       aws.StringSlice([]string{filterTagValue}),
+				MatchOptions: aws.StringSlice([]string{costexplorer.MatchOptionEquals}),
+			},
+		},
+		GroupBy: []*costexplorer.GroupDefinition{
+			{
+				Type: aws.String(costexplorer.GroupDefinitionTypeDimension),
+				Key:  aws.String(costexplorer.DimensionUsageType),
+			},
+			{
+				Type: aws.String(costexplorer.GroupDefinitionTypeTag),
+				Key:  aws.String(groupByTagKey),
+			},
+		},
+	}
+
+	var results []*costexplorer.ResultByTime
+	for {
+		output, err := c.CostExplorer().GetCostAndUsage(input)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		results = append(results, output.ResultsByTime...)
+
+		if output.NextPageToken == nil || *output.NextPageToken == "" {
+			break
+		}
+		input.NextPageToken = output.NextPageToken
+	}
+
+	return aggregateCostGroups(results, groupByTagKey)
+}
+
+// merges the groups of all time periods, keyed by usage type and tag value
+func aggregateCostGroups(results []*costexplorer.ResultByTime, groupByTagKey string) ([]CostGroup, error) {
+	var costGroups []CostGroup
+	indexes := map[[2]string]int{}
+
+	for _, result := range results {
+		for _, group := range result.Groups {
+			keys := aws.StringValueSlice(group.Keys)
+			if len(keys) != 2 {
+				return nil, errors.ErrorUnexpected("unexpected number of cost explorer group keys", keys)
+			}
+
+			metric, ok := group.Metrics[costexplorer.MetricUnblendedCost]
+			if !ok || metric.Amount == nil {
+				continue
+			}
+			amount, err := strconv.ParseFloat(*metric.Amount, 64)
+			if err != nil {
+				return nil, errors.Wrap(err, "unable to parse cost explorer amount", *metric.Amount)
+			}
+
+			usageType := keys[0]
+			// tag group keys are formatted as "<tag key>$<tag value>", with an empty value for untagged resources
+			tagValue := strings.TrimPrefix(keys[1], groupByTagKey+"$")
+
+			key := [2]string{usageType, tagValue}
+			if i, ok := indexes[key]; ok {
+				costGroups[i].Amount += amount
+				continue
+			}
+			indexes[key] = len(costGroups)
+			costGroups = append(costGroups, CostGroup{
+				UsageType: usageType,
+				TagValue:  tagValue,
+				Amount:    amount,
+			})
+		}
+	}
+
+	return costGroups, nil
+}
+
+// UsageTypeCostCategory maps a cost explorer usage type (e.g. "USW2-BoxUsage:g4dn.xlarge") to one of the CostCategories
+func UsageTypeCostCategory(usageType string) string {
+	switch {
+	case strings.Contains(usageType, "BoxUsage"), strings.Contains(usageType, "SpotUsage"), strings.Contains(usageType, "DedicatedUsage"):
+		return CostCategoryEC2
+	case strings.Contains(usageType, "EBS:"):
+		return CostCategoryEBS
+	case strings.Contains(usageType, "NatGateway"):
+		return CostCategoryNATGateway
+	case strings.Contains(usageType, "LoadBalancerUsage"), strings.Contains(usageType, "LCUUsage"), strings.Contains(usageType, "DataProcessing-Bytes"):
+		return CostCategoryELB
+	default:
+		return CostCategoryOther
+	}
+}
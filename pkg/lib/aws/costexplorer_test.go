@@ -0,0 +1,73 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/costexplorer"
+	"github.com/stretchr/testify/require"
+)
+
+func costGroup(usageType string, tagValue string, amount string) *costexplorer.Group {
+	return &costexplorer.Group{
+		Keys: aws.StringSlice([]string{usageType, "alpha.eksctl.io/nodegroup-name$" + tagValue}),
+		Metrics: map[string]*costexplorer.MetricValue{
+			costexplorer.MetricUnblendedCost: {Amount: aws.String(amount), Unit: aws.String("USD")},
+		},
+	}
+}
+
+func TestAggregateCostGroups(t *testing.T) {
+	results := []*costexplorer.ResultByTime{
+		{
+			Groups: []*costexplorer.Group{
+				costGroup("USW2-BoxUsage:g4dn.xlarge", "cx-wd-gpu", "10.5"),
+				costGroup("USW2-NatGateway-Hours", "", "1.25"),
+			},
+		},
+		{
+			Groups: []*costexplorer.Group{
+				costGroup("USW2-BoxUsage:g4dn.xlarge", "cx-wd-gpu", "2.5"),
+				costGroup("USW2-SpotUsage:g4dn.xlarge", "cx-ws-gpu", "3"),
+			},
+		},
+	}
+
+	costGroups, err := aggregateCostGroups(results, "alpha.eksctl.io/nodegroup-name")
+	require.NoError(t, err)
+	require.Equal(t, []CostGroup{
+		{UsageType: "USW2-BoxUsage:g4dn.xlarge", TagValue: "cx-wd-gpu", Amount: 13},
+		{UsageType: "USW2-NatGateway-Hours", TagValue: "", Amount: 1.25},
+		{UsageType: "USW2-SpotUsage:g4dn.xlarge", TagValue: "cx-ws-gpu", Amount: 3},
+	}, costGroups)
+
+	_, err = aggregateCostGroups([]*costexplorer.ResultByTime{{Groups: []*costexplorer.Group{costGroup("USW2-BoxUsage:t3.medium", "cx-operator", "abc")}}}, "alpha.eksctl.io/nodegroup-name")
+	require.Error(t, err)
+}
+
+func TestUsageTypeCostCategory(t *testing.T) {
+	require.Equal(t, CostCategoryEC2, UsageTypeCostCategory("USW2-BoxUsage:t3.medium"))
+	require.Equal(t, CostCategoryEC2, UsageTypeCostCategory("USW2-SpotUsage:g4dn.xlarge"))
+	require.Equal(t, CostCategoryEC2, UsageTypeCostCategory("BoxUsage:m5.large"))
+	require.Equal(t, CostCategoryEBS, UsageTypeCostCategory("USW2-EBS:VolumeUsage.gp3"))
+	require.Equal(t, CostCategoryNATGateway, UsageTypeCostCategory("USW2-NatGateway-Bytes"))
+	require.Equal(t, CostCategoryELB, UsageTypeCostCategory("USW2-LoadBalancerUsage"))
+	require.Equal(t, CostCategoryELB, UsageTypeCostCategory("USW2-LCUUsage"))
+	require.Equal(t, CostCategoryOther, UsageTypeCostCategory("USW2-AmazonEKS-Hours:perCluster"))
+}
@@ -16,6 +16,13 @@ limitations under the License.
 
 package aws
 
+import (
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	libjson "github.com/cortexlabs/cortex/pkg/lib/json"
+)
+
 // access key ID may be unavailable depending on how the client was instantiated
 func (c *Client) AccessKeyID() *string {
 	if c.sess.Config.Credentials == nil {
@@ -67,3 +74,76 @@ func (c *Client) SessionToken() *string {
 
 	return &sessCreds.SessionToken
 }
+
+// CredentialsExpiration returns when the client's current credentials expire, or nil if they don't expire (or their expiration is unknown)
+func (c *Client) CredentialsExpiration() *time.Time {
+	if c.sess.Config.Credentials == nil {
+		return nil
+	}
+
+	expiration, err := c.sess.Config.Credentials.ExpiresAt()
+	if err != nil || expiration.IsZero() {
+		return nil
+	}
+
+	return &expiration
+}
+
+// RefreshCredentials retrieves new credentials (if the client's credentials are temporary), even if the current credentials haven't expired
+func (c *Client) RefreshCredentials() error {
+	if c.sess.Config.Credentials == nil {
+		return ErrorUnableToFindCredentials()
+	}
+
+	c.sess.Config.Credentials.Expire()
+	if _, err := c.sess.Config.Credentials.Get(); err != nil {
+		return errors.WithStack(err)
+	}
+
+	return nil
+}
+
+// RefreshCredentialsWithoutPrompt is like RefreshCredentials, but returns ErrMFATokenRequired instead of prompting for an mfa code if one is needed,
+// so that it can be called from background goroutines (the credentials are then retrieved, and the code is prompted for, the next time they are used)
+func (c *Client) RefreshCredentialsWithoutPrompt() error {
+	if c.mfaTokens != nil {
+		c.mfaTokens.promptsDisabled.Store(true)
+		defer c.mfaTokens.promptsDisabled.Store(false)
+	}
+	return c.RefreshCredentials()
+}
+
+// the format which credential_process commands print (https://docs.aws.amazon.com/cli/latest/userguide/cli-configure-sourcing-external.html)
+type credentialProcessOutput struct {
+	Version         int        `json:"Version"`
+	AccessKeyID     string     `json:"AccessKeyId"`
+	SecretAccessKey string     `json:"SecretAccessKey"`
+	SessionToken    string     `json:"SessionToken,omitempty"`
+	Expiration      *time.Time `json:"Expiration,omitempty"`
+}
+
+// CredentialProcessJSON returns the client's current credentials in the format which credential_process commands print,
+// so that they can be provided to other processes without setting them in environment variables
+func (c *Client) CredentialProcessJSON() ([]byte, error) {
+	if c.sess.Config.Credentials == nil {
+		return nil, ErrorUnableToFindCredentials()
+	}
+
+	creds, err := c.sess.Config.Credentials.Get()
+	if err != nil {
+		return nil, ErrorUnableToFindCredentials()
+	}
+
+	output := credentialProcessOutput{
+		Version:         1,
+		AccessKeyID:     creds.AccessKeyID,
+		SecretAccessKey: creds.SecretAccessKey,
+		SessionToken:    creds.SessionToken,
+	}
+	if expiration := c.CredentialsExpiration(); expiration != nil {
+		utcExpiration := expiration.UTC()
+		output.Expiration = &utcExpiration
+	}
+
+	return libjson.Marshal(output)
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCredentialProcessJSON(t *testing.T) {
+	sess, err := session.NewSession(&aws.Config{
+		Region:      aws.String("us-west-2"),
+		Credentials: credentials.NewStaticCredentials("AKIAEXAMPLE", "secret", "token"),
+	})
+	require.NoError(t, err)
+	client, err := NewForSession(sess)
+	require.NoError(t, err)
+
+	credsJSON, err := client.CredentialProcessJSON()
+	require.NoError(t, err)
+
+	var output map[string]interface{}
+	require.NoError(t, json.Unmarshal(credsJSON, &output))
+	require.Equal(t, map[string]interface{}{
+		"Version":         float64(1),
+		"AccessKeyId":     "AKIAEXAMPLE",
+		"SecretAccessKey": "secret",
+		"SessionToken":    "token",
+	}, output)
+
+	// static credentials don't expire
+	require.Nil(t, client.CredentialsExpiration())
+}
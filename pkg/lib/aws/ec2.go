@@ -19,6 +19,7 @@ package aws
 import (
 	"math"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
 
@@ -188,6 +189,24 @@ func IsTrainiumInstance(instanceType string) (bool, error) {
 	return parsedType.Family == "trn", nil
 }
 
+// NeuronCoresPerDevice returns the number of neuron cores on each neuron device (Inferentia or Trainium chip) of the instance type,
+// or 0 if the instance type does not have neuron devices
+func NeuronCoresPerDevice(instanceType string) (int64, error) {
+	parsedType, err := ParseInstanceType(instanceType)
+	if err != nil {
+		return 0, err
+	}
+
+	switch {
+	case parsedType.Family == "inf" && parsedType.Generation == 1:
+		return 4, nil
+	case parsedType.Family == "inf" || parsedType.Family == "trn":
+		return 2, nil
+	}
+
+	return 0, nil
+}
+
 func IsMacInstance(instanceType string) (bool, error) {
 	parsedType, err := ParseInstanceType(instanceType)
 	if err != nil {
@@ -278,6 +297,288 @@ func (c *Client) SpotInstancePrice(instanceType string) (float64, error) {
 	return min, nil
 }
 
+type SpotPrice struct {
+	Timestamp time.Time `json:"timestamp"`
+	Price     float64   `json:"price"`
+}
+
+// SpotPriceStats are weighted by the amount of time for which each price was in effect
+type SpotPriceStats struct {
+	P50 float64 `json:"p50"`
+	P90 float64 `json:"p90"`
+	Max float64 `json:"max"`
+}
+
+type SpotPriceHistory struct {
+	InstanceType          string                    `json:"instance_type"`
+	StartTime             time.Time                 `json:"start_time"`
+	EndTime               time.Time                 `json:"end_time"`
+	AvailabilityZones     map[string][]SpotPrice    `json:"availability_zones"` // sorted by timestamp (ascending)
+	AvailabilityZoneStats map[string]SpotPriceStats `json:"availability_zone_stats"`
+	Stats                 SpotPriceStats            `json:"stats"` // across all availability zones
+}
+
+// SpotInstancePriceHistory returns the spot price of the instance type in each availability zone over the lookback period
+func (c *Client) SpotInstancePriceHistory(instanceType string, lookback time.Duration) (*SpotPriceHistory, error) {
+	endTime := time.Now()
+	startTime := endTime.Add(-lookback)
+
+	series := map[string][]SpotPrice{}
+	err := c.EC2().DescribeSpotPriceHistoryPages(&ec2.DescribeSpotPriceHistoryInput{
+		InstanceTypes:       []*string{aws.String(instanceType)},
+		ProductDescriptions: []*string{aws.String("Linux/UNIX")},
+		StartTime:           aws.Time(startTime),
+		EndTime:             aws.Time(endTime),
+	}, func(output *ec2.DescribeSpotPriceHistoryOutput, lastPage bool) bool {
+		for _, spotPrice := range output.SpotPriceHistory {
+			if spotPrice == nil || spotPrice.AvailabilityZone == nil || spotPrice.SpotPrice == nil || spotPrice.Timestamp == nil {
+				continue
+			}
+
+			price, ok := s.ParseFloat64(*spotPrice.SpotPrice)
+			if !ok || price <= 0 {
+				continue
+			}
+
+			series[*spotPrice.AvailabilityZone] = append(series[*spotPrice.AvailabilityZone], SpotPrice{
+				Timestamp: *spotPrice.Timestamp,
+				Price:     price,
+			})
+		}
+		return true
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "checking spot instance price history")
+	}
+
+	if len(series) == 0 {
+		return nil, ErrorNoValidSpotPrices(instanceType, c.Region)
+	}
+
+	return newSpotPriceHistory(instanceType, startTime, endTime, series), nil
+}
+
+func newSpotPriceHistory(instanceType string, startTime time.Time, endTime time.Time, series map[string][]SpotPrice) *SpotPriceHistory {
+	history := SpotPriceHistory{
+		InstanceType:          instanceType,
+		StartTime:             startTime,
+		EndTime:               endTime,
+		AvailabilityZones:     series,
+		AvailabilityZoneStats: make(map[string]SpotPriceStats, len(series)),
+	}
+
+	var allWeightedPrices []weightedSpotPrice
+	for zone, prices := range series {
+		sort.Slice(prices, func(i, j int) bool {
+			return prices[i].Timestamp.Before(prices[j].Timestamp)
+		})
+
+		weightedPrices := weightSpotPrices(prices, startTime, endTime)
+		history.AvailabilityZoneStats[zone] = spotPriceStats(weightedPrices)
+		allWeightedPrices = append(allWeightedPrices, weightedPrices...)
+	}
+	history.Stats = spotPriceStats(allWeightedPrices)
+
+	return &history
+}
+
+type weightedSpotPrice struct {
+	price  float64
+	weight time.Duration
+}
+
+// each price is in effect from its timestamp until the next price's timestamp (the first price may predate startTime);
+// prices which were not in effect during the time range are omitted
+func weightSpotPrices(prices []SpotPrice, startTime time.Time, endTime time.Time) []weightedSpotPrice {
+	weightedPrices := make([]weightedSpotPrice, 0, len(prices))
+
+	for i, price := range prices {
+		from := price.Timestamp
+		if from.Before(startTime) {
+			from = startTime
+		}
+		to := endTime
+		if i+1 < len(prices) && prices[i+1].Timestamp.Before(endTime) {
+			to = prices[i+1].Timestamp
+		}
+
+		if weight := to.Sub(from); weight > 0 {
+			weightedPrices = append(weightedPrices, weightedSpotPrice{price: price.Price, weight: weight})
+		}
+	}
+
+	// fall back to weighting each price equally if the prices don't span any time
+	if len(weightedPrices) == 0 {
+		for _, price := range prices {
+			weightedPrices = append(weightedPrices, weightedSpotPrice{price: price.Price, weight: 1})
+		}
+	}
+
+	return weightedPrices
+}
+
+func spotPriceStats(weightedPrices []weightedSpotPrice) SpotPriceStats {
+	if len(weightedPrices) == 0 {
+		return SpotPriceStats{}
+	}
+
+	sorted := make([]weightedSpotPrice, len(weightedPrices))
+	copy(sorted, weightedPrices)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].price < sorted[j].price
+	})
+
+	var totalWeight time.Duration
+	for _, weightedPrice := range sorted {
+		totalWeight += weightedPrice.weight
+	}
+
+	percentile := func(p float64) float64 {
+		threshold := p * float64(totalWeight)
+		var cumulativeWeight time.Duration
+		for _, weightedPrice := range sorted {
+			cumulativeWeight += weightedPrice.weight
+			if float64(cumulativeWeight) >= threshold {
+				return weightedPrice.price
+			}
+		}
+		return sorted[len(sorted)-1].price
+	}
+
+	return SpotPriceStats{
+		P50: percentile(0.5),
+		P90: percentile(0.9),
+		Max: sorted[len(sorted)-1].price,
+	}
+}
+
+// spot instance request status codes which indicate that an interruption notice has been issued (the instance is interrupted ~2 minutes later)
+// spot instances which are marked for stop aren't included, since detaching them from their autoscaling group would leave them stopped
+// (and billed for their volumes) outside of it; autoscaling groups always terminate their spot instances when they are interrupted
+var _spotInterruptionStatusCodes = []string{"marked-for-termination"}
+
+// SpotInstancesMarkedForInterruption returns the IDs of the running spot instances which have received an interruption notice to be terminated
+func (c *Client) SpotInstancesMarkedForInterruption() ([]string, error) {
+	var instanceIDs []string
+	err := c.EC2().DescribeSpotInstanceRequestsPages(&ec2.DescribeSpotInstanceRequestsInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("status-code"),
+				Values: aws.StringSlice(_spotInterruptionStatusCodes),
+			},
+		},
+	}, func(output *ec2.DescribeSpotInstanceRequestsOutput, lastPage bool) bool {
+		if output == nil {
+			return false
+		}
+		for _, request := range output.SpotInstanceRequests {
+			if request == nil || request.InstanceId == nil {
+				continue
+			}
+			instanceIDs = append(instanceIDs, *request.InstanceId)
+		}
+
+		return true
+	})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return instanceIDs, nil
+}
+
+// scores range from 1 (a spot request is unlikely to succeed) to 10 (a spot request is highly likely to succeed)
+type SpotPlacementScores struct {
+	Regions           map[string]int64 `json:"regions"`            // region -> score, for all regions which returned a score
+	AvailabilityZones map[string]int64 `json:"availability_zones"` // availability zone name -> score, for the client's region
+}
+
+// SpotPlacementScores returns how likely a spot request for targetCapacity instances (of any of the instanceTypes) is to succeed
+// in each region, and in each availability zone of the client's region
+func (c *Client) SpotPlacementScores(instanceTypes []string, targetCapacity int) (*SpotPlacementScores, error) {
+	scores := SpotPlacementScores{
+		Regions:           map[string]int64{},
+		AvailabilityZones: map[string]int64{},
+	}
+
+	regionScores, err := c.getSpotPlacementScores(&ec2.GetSpotPlacementScoresInput{
+		InstanceTypes:  aws.StringSlice(instanceTypes),
+		TargetCapacity: aws.Int64(int64(targetCapacity)),
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, score := range regionScores {
+		if score.Region != nil && score.Score != nil {
+			scores.Regions[*score.Region] = *score.Score
+		}
+	}
+
+	zoneScores, err := c.getSpotPlacementScores(&ec2.GetSpotPlacementScoresInput{
+		InstanceTypes:          aws.StringSlice(instanceTypes),
+		TargetCapacity:         aws.Int64(int64(targetCapacity)),
+		RegionNames:            aws.StringSlice([]string{c.Region}),
+		SingleAvailabilityZone: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(zoneScores) == 0 {
+		return &scores, nil
+	}
+
+	// placement scores identify zones by ID (e.g. use1-az1), which map to different zone names in each account
+	zoneIDsToNames, err := c.availabilityZoneIDsToNames()
+	if err != nil {
+		return nil, err
+	}
+	for _, score := range zoneScores {
+		if score.AvailabilityZoneId == nil || score.Score == nil {
+			continue
+		}
+		if zoneName, ok := zoneIDsToNames[*score.AvailabilityZoneId]; ok {
+			scores.AvailabilityZones[zoneName] = *score.Score
+		}
+	}
+
+	return &scores, nil
+}
+
+func (c *Client) getSpotPlacementScores(input *ec2.GetSpotPlacementScoresInput) ([]*ec2.SpotPlacementScore, error) {
+	var scores []*ec2.SpotPlacementScore
+	err := c.EC2().GetSpotPlacementScoresPages(input, func(output *ec2.GetSpotPlacementScoresOutput, lastPage bool) bool {
+		scores = append(scores, output.SpotPlacementScores...)
+		return true
+	})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return scores, nil
+}
+
+func (c *Client) availabilityZoneIDsToNames() (map[string]string, error) {
+	result, err := c.EC2().DescribeAvailabilityZones(&ec2.DescribeAvailabilityZonesInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("region-name"),
+				Values: []*string{aws.String(c.Region)},
+			},
+		},
+	})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	zoneIDsToNames := map[string]string{}
+	for _, az := range result.AvailabilityZones {
+		if az.ZoneId != nil && az.ZoneName != nil {
+			zoneIDsToNames[*az.ZoneId] = *az.ZoneName
+		}
+	}
+
+	return zoneIDsToNames, nil
+}
+
 func (c *Client) ListAllRegions() (strset.Set, error) {
 	result, err := c.EC2().DescribeRegions(&ec2.DescribeRegionsInput{
 		AllRegions: aws.Bool(true),
@@ -398,26 +699,47 @@ func (c *Client) listSupportedAvailabilityZonesSingle(instanceType string) (strs
 	return zones, nil
 }
 
+// ListSupportedAvailabilityZones returns the availability zones which support all of the instance types
+// (results are cached on disk if AvailabilityZonesCacheDir is set)
 func (c *Client) ListSupportedAvailabilityZones(instanceType string, instanceTypes ...string) (strset.Set, error) {
-	allInstanceTypes := append(instanceTypes, instanceType)
+	allInstanceTypes := strset.New(append(instanceTypes, instanceType)...).SliceSorted()
+	cachedZones := c.cachedSupportedAvailabilityZones(allInstanceTypes)
+
 	zoneSets := make([]strset.Set, len(allInstanceTypes))
-	fns := make([]func() error, len(allInstanceTypes))
+	fetchedZones := make([][]string, len(allInstanceTypes))
+	var fns []func() error
 
 	for i := range allInstanceTypes {
+		if zones, ok := cachedZones[allInstanceTypes[i]]; ok {
+			zoneSets[i] = strset.New(zones...)
+			continue
+		}
+
 		localIdx := i
-		fns[i] = func() error {
+		fns = append(fns, func() error {
 			zones, err := c.listSupportedAvailabilityZonesSingle(allInstanceTypes[localIdx])
 			if err != nil {
 				return err
 			}
 			zoneSets[localIdx] = zones
+			fetchedZones[localIdx] = zones.SliceSorted()
 			return nil
-		}
+		})
 	}
 
-	err := parallel.RunFirstErr(fns[0], fns[1:]...)
-	if err != nil {
-		return nil, err
+	if len(fns) > 0 {
+		err := parallel.RunFirstErr(fns[0], fns[1:]...)
+		if err != nil {
+			return nil, err
+		}
+
+		zonesToCache := map[string][]string{}
+		for i, zones := range fetchedZones {
+			if zones != nil {
+				zonesToCache[allInstanceTypes[i]] = zones
+			}
+		}
+		c.cacheSupportedAvailabilityZones(zonesToCache)
 	}
 
 	return strset.Intersection(zoneSets...), nil
@@ -550,6 +872,69 @@ func (c *Client) DescribeSecurityGroups() ([]ec2.SecurityGroup, error) {
 	return sgs, nil
 }
 
+// ListInstances returns all instances which match all of the provided filters (instances in all states are included, unless filtered by "instance-state-name")
+func (c *Client) ListInstances(filters ...ec2.Filter) ([]ec2.Instance, error) {
+	input := &ec2.DescribeInstancesInput{}
+	for i := range filters {
+		input.Filters = append(input.Filters, &filters[i])
+	}
+
+	var instances []ec2.Instance
+	err := c.EC2().DescribeInstancesPages(input, func(output *ec2.DescribeInstancesOutput, lastPage bool) bool {
+		if output == nil {
+			return false
+		}
+		for _, reservation := range output.Reservations {
+			if reservation == nil {
+				continue
+			}
+			for _, instance := range reservation.Instances {
+				if instance == nil {
+					continue
+				}
+				instances = append(instances, *instance)
+			}
+		}
+
+		return true
+	})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return instances, nil
+}
+
+// ListInstancesByTags returns all instances which have all of the provided tags; an empty tag value matches any value for that key
+func (c *Client) ListInstancesByTags(tags map[string]string) ([]ec2.Instance, error) {
+	return c.ListInstances(tagFilters(tags)...)
+}
+
+func tagFilters(tags map[string]string) []ec2.Filter {
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	filters := make([]ec2.Filter, 0, len(tags))
+	for _, key := range keys {
+		if tags[key] == "" {
+			filters = append(filters, ec2.Filter{
+				Name:   aws.String("tag-key"),
+				Values: []*string{aws.String(key)},
+			})
+			continue
+		}
+		filters = append(filters, ec2.Filter{
+			Name:   aws.String("tag:" + key),
+			Values: []*string{aws.String(tags[key])},
+		})
+	}
+
+	return filters
+}
+
 func (c *Client) ListVolumes(tags ...ec2.Tag) ([]ec2.Volume, error) {
 	var volumes []ec2.Volume
 	err := c.EC2().DescribeVolumesPages(&ec2.DescribeVolumesInput{}, func(output *ec2.DescribeVolumesOutput, lastPage bool) bool {
@@ -19,6 +19,7 @@ package aws
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/cortexlabs/cortex/pkg/lib/sets/strset"
 	"github.com/stretchr/testify/require"
@@ -61,3 +62,65 @@ func TestParseInstanceType(t *testing.T) {
 		require.NoError(t, err)
 	}
 }
+
+func TestNewSpotPriceHistory(t *testing.T) {
+	startTime := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	endTime := startTime.Add(10 * time.Hour)
+
+	history := newSpotPriceHistory("g4dn.xlarge", startTime, endTime, map[string][]SpotPrice{
+		"us-west-2a": {
+			// out of order, and the first price predates the start time
+			{Timestamp: startTime.Add(9 * time.Hour), Price: 0.5},
+			{Timestamp: startTime.Add(-2 * time.Hour), Price: 0.1},
+		},
+		"us-west-2b": {
+			{Timestamp: startTime.Add(-5 * time.Hour), Price: 0.9}, // superseded before the start time
+			{Timestamp: startTime.Add(-1 * time.Hour), Price: 0.2},
+			{Timestamp: startTime.Add(5 * time.Hour), Price: 0.3},
+		},
+	})
+
+	require.Equal(t, startTime.Add(-2*time.Hour), history.AvailabilityZones["us-west-2a"][0].Timestamp)
+	require.Equal(t, SpotPriceStats{P50: 0.1, P90: 0.1, Max: 0.5}, history.AvailabilityZoneStats["us-west-2a"])
+	require.Equal(t, SpotPriceStats{P50: 0.2, P90: 0.3, Max: 0.3}, history.AvailabilityZoneStats["us-west-2b"])
+	require.Equal(t, SpotPriceStats{P50: 0.2, P90: 0.3, Max: 0.5}, history.Stats)
+}
+
+func TestWeightSpotPricesWithoutDuration(t *testing.T) {
+	now := time.Now()
+	weightedPrices := weightSpotPrices([]SpotPrice{{Timestamp: now, Price: 0.4}}, now, now)
+	require.Equal(t, SpotPriceStats{P50: 0.4, P90: 0.4, Max: 0.4}, spotPriceStats(weightedPrices))
+}
+
+func TestTagFilters(t *testing.T) {
+	filters := tagFilters(map[string]string{
+		"cortex.dev/cluster-name":        "cortex",
+		"alpha.eksctl.io/nodegroup-name": "",
+	})
+
+	require.Len(t, filters, 2)
+	require.Equal(t, "tag-key", *filters[0].Name)
+	require.Equal(t, "alpha.eksctl.io/nodegroup-name", *filters[0].Values[0])
+	require.Equal(t, "tag:cortex.dev/cluster-name", *filters[1].Name)
+	require.Equal(t, "cortex", *filters[1].Values[0])
+
+	require.Empty(t, tagFilters(nil))
+}
+
+func TestNeuronCoresPerDevice(t *testing.T) {
+	var testcases = []struct {
+		instanceType string
+		expected     int64
+	}{
+		{"inf1.xlarge", 4},
+		{"inf1.24xlarge", 4},
+		{"g4dn.xlarge", 0},
+		{"m5.large", 0},
+	}
+
+	for _, tc := range testcases {
+		numCores, err := NeuronCoresPerDevice(tc.instanceType)
+		require.NoError(t, err)
+		require.Equal(t, tc.expected, numCores, tc.instanceType)
+	}
+}
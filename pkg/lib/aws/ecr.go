@@ -21,6 +21,7 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ecr"
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
 	"github.com/cortexlabs/cortex/pkg/lib/regex"
@@ -50,7 +51,38 @@ func (c *Client) GetECRAuthConfig() (ECRAuthConfig, error) {
 	if len(tokenOutput.AuthorizationData) == 0 {
 		return ECRAuthConfig{}, ErrorECRExtractingCredentials()
 	}
-	authData := tokenOutput.AuthorizationData[0]
+
+	return parseECRAuthorizationData(tokenOutput.AuthorizationData[0])
+}
+
+// GetECRAuthConfigsForRegistries returns an auth config for each of the registries (i.e. AWS account IDs) in the client's region
+// The registries may belong to other accounts, as long as their repository policies grant access to the client's identity
+func (c *Client) GetECRAuthConfigsForRegistries(registryIDs ...string) ([]ECRAuthConfig, error) {
+	tokenOutput, err := c.ECR().GetAuthorizationToken(&ecr.GetAuthorizationTokenInput{
+		RegistryIds: aws.StringSlice(registryIDs),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to retrieve ECR auth token", strings.Join(registryIDs, ", "))
+	}
+	if len(tokenOutput.AuthorizationData) == 0 {
+		return nil, ErrorECRExtractingCredentials()
+	}
+
+	authConfigs := make([]ECRAuthConfig, len(tokenOutput.AuthorizationData))
+	for i, authData := range tokenOutput.AuthorizationData {
+		authConfigs[i], err = parseECRAuthorizationData(authData)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return authConfigs, nil
+}
+
+func parseECRAuthorizationData(authData *ecr.AuthorizationData) (ECRAuthConfig, error) {
+	if authData == nil || authData.AuthorizationToken == nil || authData.ProxyEndpoint == nil {
+		return ECRAuthConfig{}, ErrorECRExtractingCredentials()
+	}
 
 	credentials, err := base64.URLEncoding.DecodeString(*authData.AuthorizationToken)
 	if err != nil {
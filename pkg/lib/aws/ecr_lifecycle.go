@@ -0,0 +1,224 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecr"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+)
+
+// BatchDeleteImage accepts at most 100 image IDs per request
+const _ecrBatchDeleteMaxImages = 100
+
+// the media types of multi-arch images, whose manifests reference a manifest for each platform
+var _imageIndexMediaTypes = []string{
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.oci.image.index.v1+json",
+}
+
+type ECRImage struct {
+	Repository        string
+	Digest            string
+	Tags              []string
+	PushedAt          time.Time
+	SizeBytes         int64
+	ManifestMediaType string
+}
+
+func (image ECRImage) IsTagged() bool {
+	return len(image.Tags) > 0
+}
+
+// IsImageIndex returns true if the image is a multi-arch image (i.e. a manifest list or an oci image index), whose platform-specific manifests are stored as untagged images
+func (image ECRImage) IsImageIndex() bool {
+	for _, mediaType := range _imageIndexMediaTypes {
+		if image.ManifestMediaType == mediaType {
+			return true
+		}
+	}
+	return false
+}
+
+// HasTagWithPrefix returns true if any of the image's tags starts with the prefix (an empty prefix matches all tagged images)
+func (image ECRImage) HasTagWithPrefix(prefix string) bool {
+	for _, tag := range image.Tags {
+		if strings.HasPrefix(tag, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// ListECRRepositories returns the names of the repositories in the client's registry whose names start with the prefix, in alphabetical order
+func (c *Client) ListECRRepositories(prefix string) ([]string, error) {
+	var repositories []string
+	err := c.ECR().DescribeRepositoriesPages(&ecr.DescribeRepositoriesInput{}, func(output *ecr.DescribeRepositoriesOutput, lastPage bool) bool {
+		for _, repository := range output.Repositories {
+			name := aws.StringValue(repository.RepositoryName)
+			if strings.HasPrefix(name, prefix) {
+				repositories = append(repositories, name)
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list ECR repositories")
+	}
+
+	sort.Strings(repositories)
+	return repositories, nil
+}
+
+// ListECRImages returns the repository's tagged images which have at least one tag that starts with tagPrefix, most recently pushed first
+func (c *Client) ListECRImages(repository string, tagPrefix string) ([]ECRImage, error) {
+	images, err := c.describeECRImages(repository, ecr.TagStatusTagged)
+	if err != nil {
+		return nil, err
+	}
+	return filterECRImagesByTagPrefix(images, tagPrefix), nil
+}
+
+// ListUntaggedECRImages returns the repository's untagged images, most recently pushed first
+func (c *Client) ListUntaggedECRImages(repository string) ([]ECRImage, error) {
+	return c.describeECRImages(repository, ecr.TagStatusUntagged)
+}
+
+func (c *Client) describeECRImages(repository string, tagStatus string) ([]ECRImage, error) {
+	var images []ECRImage
+	err := c.ECR().DescribeImagesPages(&ecr.DescribeImagesInput{
+		RepositoryName: aws.String(repository),
+		Filter:         &ecr.DescribeImagesFilter{TagStatus: aws.String(tagStatus)},
+	}, func(output *ecr.DescribeImagesOutput, lastPage bool) bool {
+		for _, detail := range output.ImageDetails {
+			images = append(images, ecrImageFromDetail(repository, detail))
+		}
+		return true
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list images in ECR repository "+repository)
+	}
+
+	sortECRImagesNewestFirst(images)
+	return images, nil
+}
+
+// ListECRImageIndexManifests returns the digests of the platform-specific manifests which are referenced by the multi-arch image with the provided digest
+func (c *Client) ListECRImageIndexManifests(repository string, digest string) ([]string, error) {
+	output, err := c.ECR().BatchGetImage(&ecr.BatchGetImageInput{
+		RepositoryName:     aws.String(repository),
+		ImageIds:           []*ecr.ImageIdentifier{{ImageDigest: aws.String(digest)}},
+		AcceptedMediaTypes: aws.StringSlice(_imageIndexMediaTypes),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get image "+digest+" from ECR repository "+repository)
+	}
+	if len(output.Images) == 0 {
+		return nil, nil // the image was deleted
+	}
+
+	return parseImageIndexManifestDigests(aws.StringValue(output.Images[0].ImageManifest))
+}
+
+// DeleteECRImages deletes the images with the provided digests from the repository (all of an image's tags are removed along with it)
+func (c *Client) DeleteECRImages(repository string, digests []string) error {
+	for start := 0; start < len(digests); start += _ecrBatchDeleteMaxImages {
+		end := start + _ecrBatchDeleteMaxImages
+		if end > len(digests) {
+			end = len(digests)
+		}
+
+		imageIDs := make([]*ecr.ImageIdentifier, end-start)
+		for i, digest := range digests[start:end] {
+			imageIDs[i] = &ecr.ImageIdentifier{ImageDigest: aws.String(digest)}
+		}
+
+		output, err := c.ECR().BatchDeleteImage(&ecr.BatchDeleteImageInput{
+			RepositoryName: aws.String(repository),
+			ImageIds:       imageIDs,
+		})
+		if err != nil {
+			return errors.Wrap(err, "failed to delete images from ECR repository "+repository)
+		}
+
+		var failures []string
+		for _, failure := range output.Failures {
+			if aws.StringValue(failure.FailureCode) == ecr.ImageFailureCodeImageNotFound {
+				continue // already deleted
+			}
+			failures = append(failures, aws.StringValue(failure.ImageId.ImageDigest)+": "+aws.StringValue(failure.FailureReason))
+		}
+		if len(failures) > 0 {
+			return ErrorECRImageDeletion(repository, failures)
+		}
+	}
+
+	return nil
+}
+
+func ecrImageFromDetail(repository string, detail *ecr.ImageDetail) ECRImage {
+	image := ECRImage{
+		Repository:        repository,
+		Digest:            aws.StringValue(detail.ImageDigest),
+		Tags:              aws.StringValueSlice(detail.ImageTags),
+		SizeBytes:         aws.Int64Value(detail.ImageSizeInBytes),
+		ManifestMediaType: aws.StringValue(detail.ImageManifestMediaType),
+	}
+	if detail.ImagePushedAt != nil {
+		image.PushedAt = *detail.ImagePushedAt
+	}
+	sort.Strings(image.Tags)
+	return image
+}
+
+func parseImageIndexManifestDigests(manifest string) ([]string, error) {
+	var index struct {
+		Manifests []struct {
+			Digest string `json:"digest"`
+		} `json:"manifests"`
+	}
+	if err := json.Unmarshal([]byte(manifest), &index); err != nil {
+		return nil, errors.Wrap(errors.WithStack(err), "failed to parse image index")
+	}
+
+	digests := make([]string, 0, len(index.Manifests))
+	for _, child := range index.Manifests {
+		digests = append(digests, child.Digest)
+	}
+	return digests, nil
+}
+
+func filterECRImagesByTagPrefix(images []ECRImage, tagPrefix string) []ECRImage {
+	filtered := []ECRImage{}
+	for _, image := range images {
+		if image.HasTagWithPrefix(tagPrefix) {
+			filtered = append(filtered, image)
+		}
+	}
+	return filtered
+}
+
+func sortECRImagesNewestFirst(images []ECRImage) {
+	sort.SliceStable(images, func(i, j int) bool {
+		return images[i].PushedAt.After(images[j].PushedAt)
+	})
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestECRImageFromDetail(t *testing.T) {
+	pushedAt := time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC)
+	image := ecrImageFromDetail("cortex/api", &ecr.ImageDetail{
+		ImageDigest:      aws.String("sha256:abc"),
+		ImageTags:        aws.StringSlice([]string{"v2", "latest"}),
+		ImagePushedAt:    &pushedAt,
+		ImageSizeInBytes: aws.Int64(1024),
+	})
+
+	require.Equal(t, ECRImage{
+		Repository: "cortex/api",
+		Digest:     "sha256:abc",
+		Tags:       []string{"latest", "v2"},
+		PushedAt:   pushedAt,
+		SizeBytes:  1024,
+	}, image)
+	require.True(t, image.IsTagged())
+
+	require.False(t, image.IsImageIndex())
+
+	image = ecrImageFromDetail("cortex/api", &ecr.ImageDetail{ImageDigest: aws.String("sha256:def")})
+	require.False(t, image.IsTagged())
+	require.True(t, image.PushedAt.IsZero())
+
+	image = ecrImageFromDetail("cortex/api", &ecr.ImageDetail{
+		ImageDigest:            aws.String("sha256:123"),
+		ImageManifestMediaType: aws.String("application/vnd.oci.image.index.v1+json"),
+	})
+	require.True(t, image.IsImageIndex())
+}
+
+func TestParseImageIndexManifestDigests(t *testing.T) {
+	digests, err := parseImageIndexManifestDigests(`{
+		"schemaVersion": 2,
+		"mediaType": "application/vnd.docker.distribution.manifest.list.v2+json",
+		"manifests": [
+			{"mediaType": "application/vnd.docker.distribution.manifest.v2+json", "digest": "sha256:amd64", "platform": {"architecture": "amd64", "os": "linux"}},
+			{"mediaType": "application/vnd.docker.distribution.manifest.v2+json", "digest": "sha256:arm64", "platform": {"architecture": "arm64", "os": "linux"}}
+		]
+	}`)
+	require.NoError(t, err)
+	require.Equal(t, []string{"sha256:amd64", "sha256:arm64"}, digests)
+
+	_, err = parseImageIndexManifestDigests("not json")
+	require.Error(t, err)
+}
+
+func TestFilterAndSortECRImages(t *testing.T) {
+	day := func(d int) time.Time {
+		return time.Date(2022, 3, d, 0, 0, 0, 0, time.UTC)
+	}
+	images := []ECRImage{
+		{Digest: "a", Tags: []string{"build-1"}, PushedAt: day(1)},
+		{Digest: "b", Tags: []string{"latest", "build-3"}, PushedAt: day(3)},
+		{Digest: "c", Tags: []string{"release-1"}, PushedAt: day(2)},
+		{Digest: "d", PushedAt: day(4)},
+	}
+
+	sortECRImagesNewestFirst(images)
+	require.Equal(t, []string{"d", "b", "c", "a"}, ecrImageDigests(images))
+
+	require.Equal(t, []string{"b", "a"}, ecrImageDigests(filterECRImagesByTagPrefix(images, "build-")))
+	require.Equal(t, []string{"b", "c", "a"}, ecrImageDigests(filterECRImagesByTagPrefix(images, "")))
+	require.Empty(t, filterECRImagesByTagPrefix(images, "nightly-"))
+}
+
+func ecrImageDigests(images []ECRImage) []string {
+	digests := make([]string, len(images))
+	for i, image := range images {
+		digests[i] = image.Digest
+	}
+	return digests
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecr"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+)
+
+// ECR finding severities, from most to least severe
+var ECRFindingSeverities = []string{
+	ecr.FindingSeverityCritical,
+	ecr.FindingSeverityHigh,
+	ecr.FindingSeverityMedium,
+	ecr.FindingSeverityLow,
+	ecr.FindingSeverityInformational,
+	ecr.FindingSeverityUndefined,
+}
+
+type ECRImageScanFindings struct {
+	Status         string           // one of the ecr.ScanStatus* constants, or "" if the image has not been scanned
+	CompletedAt    *time.Time       // nil unless the scan is complete
+	SeverityCounts map[string]int64 // keyed by ECR finding severity (e.g. "CRITICAL")
+}
+
+// CountAtOrAbove returns the number of findings whose severity is at least as severe as the provided severity (case-insensitive)
+func (f ECRImageScanFindings) CountAtOrAbove(severity string) int64 {
+	var count int64
+	for _, s := range ECRFindingSeverities {
+		count += f.SeverityCounts[s]
+		if strings.EqualFold(s, severity) {
+			break
+		}
+	}
+	return count
+}
+
+// IsECRFindingSeverity returns whether the provided severity (case-insensitive) is one of the ECR finding severities
+func IsECRFindingSeverity(severity string) bool {
+	for _, s := range ECRFindingSeverities {
+		if strings.EqualFold(s, severity) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetECRImageScanFindings returns a summary of the scan findings for the image in the client's region
+// reference may be a tag or a digest (e.g. "sha256:..."); if the image has not been scanned, the returned Status will be empty
+func (c *Client) GetECRImageScanFindings(registryID string, repository string, reference string) (ECRImageScanFindings, error) {
+	input := &ecr.DescribeImageScanFindingsInput{
+		RepositoryName: aws.String(repository),
+		ImageId:        ecrImageIdentifier(reference),
+		MaxResults:     aws.Int64(1),
+	}
+	if registryID != "" {
+		input.RegistryId = aws.String(registryID)
+	}
+
+	output, err := c.ECR().DescribeImageScanFindings(input)
+	if err != nil {
+		if IsErrCode(err, ecr.ErrCodeScanNotFoundException) {
+			return ECRImageScanFindings{}, nil
+		}
+		return ECRImageScanFindings{}, errors.Wrap(err, "failed to describe image scan findings", repository+":"+reference)
+	}
+
+	return parseECRImageScanFindings(output), nil
+}
+
+func ecrImageIdentifier(reference string) *ecr.ImageIdentifier {
+	if strings.HasPrefix(reference, "sha256:") {
+		return &ecr.ImageIdentifier{ImageDigest: aws.String(reference)}
+	}
+	return &ecr.ImageIdentifier{ImageTag: aws.String(reference)}
+}
+
+func parseECRImageScanFindings(output *ecr.DescribeImageScanFindingsOutput) ECRImageScanFindings {
+	findings := ECRImageScanFindings{
+		SeverityCounts: map[string]int64{},
+	}
+	if output == nil {
+		return findings
+	}
+
+	if output.ImageScanStatus != nil {
+		findings.Status = aws.StringValue(output.ImageScanStatus.Status)
+	}
+
+	if output.ImageScanFindings != nil {
+		findings.CompletedAt = output.ImageScanFindings.ImageScanCompletedAt
+		for severity, count := range output.ImageScanFindings.FindingSeverityCounts {
+			findings.SeverityCounts[strings.ToUpper(severity)] = aws.Int64Value(count)
+		}
+	}
+
+	return findings
+}
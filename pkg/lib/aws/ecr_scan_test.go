@@ -0,0 +1,61 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseECRImageScanFindings(t *testing.T) {
+	completedAt := time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC)
+	findings := parseECRImageScanFindings(&ecr.DescribeImageScanFindingsOutput{
+		ImageScanStatus: &ecr.ImageScanStatus{Status: aws.String(ecr.ScanStatusComplete)},
+		ImageScanFindings: &ecr.ImageScanFindings{
+			ImageScanCompletedAt: &completedAt,
+			FindingSeverityCounts: map[string]*int64{
+				ecr.FindingSeverityCritical: aws.Int64(1),
+				ecr.FindingSeverityHigh:     aws.Int64(2),
+				ecr.FindingSeverityLow:      aws.Int64(5),
+			},
+		},
+	})
+
+	require.Equal(t, ecr.ScanStatusComplete, findings.Status)
+	require.Equal(t, &completedAt, findings.CompletedAt)
+	require.Equal(t, int64(1), findings.CountAtOrAbove("critical"))
+	require.Equal(t, int64(3), findings.CountAtOrAbove("HIGH"))
+	require.Equal(t, int64(3), findings.CountAtOrAbove("medium"))
+	require.Equal(t, int64(8), findings.CountAtOrAbove("low"))
+
+	findings = parseECRImageScanFindings(nil)
+	require.Equal(t, "", findings.Status)
+	require.Equal(t, int64(0), findings.CountAtOrAbove("low"))
+
+	require.True(t, IsECRFindingSeverity("Critical"))
+	require.False(t, IsECRFindingSeverity("severe"))
+}
+
+func TestECRImageIdentifier(t *testing.T) {
+	require.Equal(t, "latest", *ecrImageIdentifier("latest").ImageTag)
+	require.Nil(t, ecrImageIdentifier("latest").ImageDigest)
+	require.Equal(t, "sha256:abc", *ecrImageIdentifier("sha256:abc").ImageDigest)
+}
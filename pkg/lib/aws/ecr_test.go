@@ -0,0 +1,64 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseECRAuthorizationData(t *testing.T) {
+	authData := &ecr.AuthorizationData{
+		AuthorizationToken: aws.String(base64.URLEncoding.EncodeToString([]byte("AWS:token"))),
+		ProxyEndpoint:      aws.String("https://123456789012.dkr.ecr.us-west-2.amazonaws.com"),
+	}
+
+	authConfig, err := parseECRAuthorizationData(authData)
+	require.NoError(t, err)
+	require.Equal(t, ECRAuthConfig{
+		Username:      "AWS",
+		AccessToken:   "token",
+		ProxyEndpoint: "https://123456789012.dkr.ecr.us-west-2.amazonaws.com",
+	}, authConfig)
+
+	_, err = parseECRAuthorizationData(nil)
+	require.Error(t, err)
+
+	_, err = parseECRAuthorizationData(&ecr.AuthorizationData{
+		AuthorizationToken: aws.String(base64.URLEncoding.EncodeToString([]byte("token"))),
+		ProxyEndpoint:      aws.String("https://123456789012.dkr.ecr.us-west-2.amazonaws.com"),
+	})
+	require.Error(t, err)
+
+	_, err = parseECRAuthorizationData(&ecr.AuthorizationData{
+		AuthorizationToken: aws.String(base64.URLEncoding.EncodeToString([]byte("AWS:token"))),
+	})
+	require.Error(t, err)
+}
+
+func TestECRURLParsing(t *testing.T) {
+	image := "123456789012.dkr.ecr.eu-central-1.amazonaws.com/my-repo:latest"
+	require.Equal(t, "123456789012", GetAccountIDFromECRURL(image))
+	require.Equal(t, "eu-central-1", GetRegionFromECRURL(image))
+
+	require.Equal(t, "", GetAccountIDFromECRURL("quay.io/my-org/my-repo:latest"))
+	require.Equal(t, "", GetRegionFromECRURL("quay.io/my-org/my-repo:latest"))
+}
@@ -17,11 +17,49 @@ limitations under the License.
 package aws
 
 import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/eks"
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
 	"github.com/cortexlabs/cortex/pkg/lib/sets/strset"
 )
 
+// EKS-optimized AMI types (the keys of manager/manifests/ami.json)
+const (
+	EKSAMITypeCPUAMD64         = "cpu_amd64"
+	EKSAMITypeCPUARM64         = "cpu_arm64"
+	EKSAMITypeAcceleratedAMD64 = "accelerated_amd64"
+)
+
+var _eksAMINamePatterns = map[string]string{
+	EKSAMITypeCPUAMD64:         "amazon-eks-node-%s-v*",
+	EKSAMITypeCPUARM64:         "amazon-eks-arm64-node-%s-v*",
+	EKSAMITypeAcceleratedAMD64: "amazon-eks-gpu-node-%s-v*",
+}
+
+// the path of the amazon linux 2 variant of each ami type in the public SSM parameters of the EKS-optimized AMIs
+var _eksAMISSMVariants = map[string]string{
+	EKSAMITypeCPUAMD64:         "amazon-linux-2",
+	EKSAMITypeCPUARM64:         "amazon-linux-2-arm64",
+	EKSAMITypeAcceleratedAMD64: "amazon-linux-2-gpu",
+}
+
+const _defaultEKSAMIOwnerAccount = "602401143452"
+
+// accounts which own the EKS-optimized AMIs in regions which don't use the default account (see build/generate_ami_mapping.go)
+var _eksAMIOwnerAccounts = map[string]string{
+	"ap-east-1":      "800184023465",
+	"me-south-1":     "558608220178",
+	"cn-northwest-1": "961992271922",
+	"cn-north-1":     "918309763551",
+	"af-south-1":     "877085696533",
+	"eu-south-1":     "590381155156",
+	"us-gov-west-1":  "013241004608",
+	"us-gov-east-1":  "151742754352",
+}
+
 var EKSSupportedRegions strset.Set
 
 func init() {
@@ -43,3 +81,65 @@ func (c *Client) EKSClusterOrNil(clusterName string) (*eks.Cluster, error) {
 
 	return clusterInfo.Cluster, nil
 }
+
+// EKSAMIParameterName returns the name of the public SSM parameter which holds the id of the latest EKS-optimized AMI of the ami type
+func EKSAMIParameterName(k8sVersion string, amiType string) string {
+	return fmt.Sprintf("/aws/service/eks/optimized-ami/%s/%s/recommended/image_id", k8sVersion, _eksAMISSMVariants[amiType])
+}
+
+// Returns the type of EKS-optimized AMI which is used for the instance type (see get_ami() in manager/generate_eks.py)
+func EKSAMIType(instanceType string) (string, error) {
+	isGPU, err := IsGPUInstance(instanceType)
+	if err != nil {
+		return "", err
+	}
+	isInf, err := IsInferentiaInstance(instanceType)
+	if err != nil {
+		return "", err
+	}
+	if isGPU || isInf {
+		return EKSAMITypeAcceleratedAMD64, nil
+	}
+
+	isARM, err := IsARMInstance(instanceType)
+	if err != nil {
+		return "", err
+	}
+	if isARM {
+		return EKSAMITypeCPUARM64, nil
+	}
+
+	return EKSAMITypeCPUAMD64, nil
+}
+
+// Returns whether an EKS-optimized AMI of the given type is available for the kubernetes version in the client's region
+func (c *Client) IsEKSOptimizedAMIAvailable(k8sVersion string, amiType string) (bool, error) {
+	namePattern, ok := _eksAMINamePatterns[amiType]
+	if !ok {
+		return false, errors.ErrorUnexpected("unknown eks ami type", amiType)
+	}
+
+	ownerAccount := _defaultEKSAMIOwnerAccount
+	if account, ok := _eksAMIOwnerAccounts[c.Region]; ok {
+		ownerAccount = account
+	}
+
+	result, err := c.EC2().DescribeImages(&ec2.DescribeImagesInput{
+		Owners: []*string{aws.String(ownerAccount)},
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("name"),
+				Values: []*string{aws.String(fmt.Sprintf(namePattern, k8sVersion))},
+			},
+			{
+				Name:   aws.String("state"),
+				Values: []*string{aws.String(ec2.ImageStateAvailable)},
+			},
+		},
+	})
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+
+	return len(result.Images) > 0, nil
+}
@@ -104,6 +104,83 @@ func (c *Client) FindLoadBalancerV2(tags map[string]string) (*elbv2.LoadBalancer
 	return loadBalancer, nil
 }
 
+// FindListener returns the load balancer's listener on the specified port, or nil if there is no such listener
+func (c *Client) FindListener(loadBalancerARN string, port int64) (*elbv2.Listener, error) {
+	var listener *elbv2.Listener
+	err := c.ELBV2().DescribeListenersPages(&elbv2.DescribeListenersInput{
+		LoadBalancerArn: aws.String(loadBalancerARN),
+	},
+		func(page *elbv2.DescribeListenersOutput, lastPage bool) bool {
+			for i := range page.Listeners {
+				if page.Listeners[i].Port != nil && *page.Listeners[i].Port == port {
+					listener = page.Listeners[i]
+					return false
+				}
+			}
+			return true
+		})
+	if err != nil {
+		return nil, errors.Wrap(err, loadBalancerARN)
+	}
+
+	return listener, nil
+}
+
+// GetListenerCertificateARNs returns the ARNs of the listener's certificates (including its default certificate)
+func (c *Client) GetListenerCertificateARNs(listenerARN string) (strset.Set, error) {
+	certificateARNs := strset.New()
+
+	input := &elbv2.DescribeListenerCertificatesInput{
+		ListenerArn: aws.String(listenerARN),
+	}
+	for {
+		output, err := c.ELBV2().DescribeListenerCertificates(input)
+		if err != nil {
+			return nil, errors.Wrap(err, listenerARN)
+		}
+
+		for _, certificate := range output.Certificates {
+			if certificate.CertificateArn != nil {
+				certificateARNs.Add(*certificate.CertificateArn)
+			}
+		}
+
+		if output.NextMarker == nil || *output.NextMarker == "" {
+			break
+		}
+		input.Marker = output.NextMarker
+	}
+
+	return certificateARNs, nil
+}
+
+// AddListenerCertificate adds a certificate to the listener's certificate list, which is used for SNI
+func (c *Client) AddListenerCertificate(listenerARN string, certificateARN string) error {
+	_, err := c.ELBV2().AddListenerCertificates(&elbv2.AddListenerCertificatesInput{
+		ListenerArn: aws.String(listenerARN),
+		Certificates: []*elbv2.Certificate{
+			{CertificateArn: aws.String(certificateARN)},
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, listenerARN, certificateARN)
+	}
+	return nil
+}
+
+func (c *Client) RemoveListenerCertificate(listenerARN string, certificateARN string) error {
+	_, err := c.ELBV2().RemoveListenerCertificates(&elbv2.RemoveListenerCertificatesInput{
+		ListenerArn: aws.String(listenerARN),
+		Certificates: []*elbv2.Certificate{
+			{CertificateArn: aws.String(certificateARN)},
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, listenerARN, certificateARN)
+	}
+	return nil
+}
+
 func IsLoadBalancerV2Healthy(loadBalancer elbv2.LoadBalancer) bool {
 	if loadBalancer.State == nil || loadBalancer.State.Code == nil {
 		return false
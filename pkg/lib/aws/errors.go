@@ -18,11 +18,14 @@ package aws
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/iam"
 	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
 	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/aws/aws-sdk-go/service/ssm"
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
 	s "github.com/cortexlabs/cortex/pkg/lib/strings"
 )
@@ -38,6 +41,7 @@ const (
 	ErrBucketNotFound               = "aws.bucket_not_found"
 	ErrInsufficientInstanceQuota    = "aws.insufficient_instance_quota"
 	ErrNoValidSpotPrices            = "aws.no_valid_spot_prices"
+	ErrNoValidOnDemandPrice         = "aws.no_valid_on_demand_price"
 	ErrECRExtractingCredentials     = "aws.ecr_failed_credentials"
 	ErrDashboardWidthOutOfRange     = "aws.dashboard_width_ouf_of_range"
 	ErrDashboardHeightOutOfRange    = "aws.dashboard_height_out_of_range"
@@ -49,6 +53,20 @@ const (
 	ErrVPCLimitExceeded             = "aws.vpc_limit_exceeded"
 	ErrSecurityGroupRulesExceeded   = "aws.security_group_rules_exceeded"
 	ErrSecurityGroupLimitExceeded   = "aws.security_group_limit_exceeded"
+	ErrInvalidS3Manifest            = "aws.invalid_s3_manifest"
+	ErrAssumeRole                   = "aws.assume_role"
+	ErrInvalidIdentityRequest       = "aws.invalid_identity_request"
+	ErrLogsInsightsQuery            = "aws.logs_insights_query"
+	ErrInvalidSSOProfile            = "aws.invalid_sso_profile"
+	ErrSSOLoginRequired             = "aws.sso_login_required"
+	ErrMFATokenRequired             = "aws.mfa_token_required"
+	ErrMFASession                   = "aws.mfa_session"
+	ErrIAMRoleNotFound              = "aws.iam_role_not_found"
+	ErrIAMRoleInDifferentAccount    = "aws.iam_role_in_different_account"
+	ErrIRSATrustPolicyMismatch      = "aws.irsa_trust_policy_mismatch"
+	ErrEKSClusterOIDCIssuerNotFound = "aws.eks_cluster_oidc_issuer_not_found"
+	ErrECRImageDeletion             = "aws.ecr_image_deletion"
+	ErrIdentityRequestAudience      = "aws.identity_request_audience"
 )
 
 func IsAWSError(err error) bool {
@@ -78,6 +96,14 @@ func IsNonExistentQueueErr(err error) bool {
 	return IsErrCode(err, sqs.ErrCodeQueueDoesNotExist)
 }
 
+func IsSecretNotFoundErr(err error) bool {
+	return IsErrCode(err, secretsmanager.ErrCodeResourceNotFoundException)
+}
+
+func IsParameterNotFoundErr(err error) bool {
+	return IsErrCode(err, ssm.ErrCodeParameterNotFound)
+}
+
 func IsGenericNotFoundErr(err error) bool {
 	return IsNotFoundErr(err) || IsNoSuchKeyErr(err) || IsNoSuchBucketErr(err)
 }
@@ -160,12 +186,20 @@ func ErrorBucketNotFound(bucket string) error {
 	})
 }
 
-func ErrorInsufficientInstanceQuota(instanceTypes []string, lifecycle string, region string, requiredVCPUs int64, vCPUQuota int64, quotaCode string) error {
-	url := fmt.Sprintf("https://%s.console.aws.amazon.com/servicequotas/home?region=%s#!/services/ec2/quotas/%s", region, region, quotaCode)
-	andInstanceTypes := s.StrsAnd(instanceTypes)
+func ErrorInsufficientInstanceQuota(deficits []InstanceQuotaDeficit, region string) error {
+	var lines []string
+	for _, deficit := range deficits {
+		url := fmt.Sprintf("https://%s.console.aws.amazon.com/servicequotas/home?region=%s#!/services/ec2/quotas/%s", region, region, deficit.QuotaCode)
+		quotaName := deficit.QuotaCode
+		if deficit.QuotaName != "" {
+			quotaName = fmt.Sprintf("\"%s\" (%s)", deficit.QuotaName, deficit.QuotaCode)
+		}
+		lines = append(lines, fmt.Sprintf("￮ your cluster may require up to %d vCPU of %s %s instances and %d vCPU of %s instances of the same class are already running, but your quota is %d vCPU; request an increase of %s to at least %d vCPU here: %s", deficit.RequiredVCPUs, deficit.Lifecycle, s.StrsAnd(deficit.InstanceTypes), deficit.InUseVCPUs, deficit.Lifecycle, deficit.QuotaVCPUs, quotaName, deficit.InUseVCPUs+deficit.RequiredVCPUs, url))
+	}
+
 	return errors.WithStack(&errors.Error{
 		Kind:    ErrInsufficientInstanceQuota,
-		Message: fmt.Sprintf("your cluster may require up to %d vCPU %s %s instances, but your AWS quota for %s %s instances in %s is only %d vCPU; please reduce the maximum number of %s %s instances your cluster may use (e.g. by changing max_instances and/or spot_config if applicable), or request a quota increase to at least %d vCPU here: %s (if your request was recently approved, please allow ~30 minutes for AWS to reflect this change)", requiredVCPUs, lifecycle, andInstanceTypes, lifecycle, andInstanceTypes, region, vCPUQuota, lifecycle, andInstanceTypes, requiredVCPUs, url),
+		Message: fmt.Sprintf("your AWS vCPU quotas in %s are insufficient:\n%s\n\nplease request the quota increases above, or reduce the maximum number of instances your cluster may use (e.g. by changing max_instances and/or spot_config if applicable); if your request was recently approved, please allow ~30 minutes for AWS to reflect this change", region, strings.Join(lines, "\n")),
 	})
 }
 
@@ -176,6 +210,13 @@ func ErrorNoValidSpotPrices(instanceType string, region string) error {
 	})
 }
 
+func ErrorNoValidOnDemandPrice(instanceType string, region string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrNoValidOnDemandPrice,
+		Message: fmt.Sprintf("no on-demand price was found for %s instances in %s", instanceType, region),
+	})
+}
+
 func ErrorECRExtractingCredentials() error {
 	return errors.WithStack(&errors.Error{
 		Kind:    ErrECRExtractingCredentials,
@@ -258,3 +299,103 @@ func ErrorSecurityGroupLimitExceeded(currentLimit, additionalQuotaRequired int,
 		Message: fmt.Sprintf("security group limit of %d exceeded in region %s; remove some node groups or increase your quota for security groups by at least %d here: %s (if your request was recently approved, please allow ~30 minutes for AWS to reflect this change)", currentLimit, region, additionalQuotaRequired, url),
 	})
 }
+
+func ErrorInvalidS3Manifest(s3Path string, reason string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrInvalidS3Manifest,
+		Message: fmt.Sprintf("%s is not a valid manifest: %s", s3Path, reason),
+	})
+}
+
+func ErrorAssumeRole(roleARN string, err error) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrAssumeRole,
+		Message: fmt.Sprintf("unable to assume role %s: %s", roleARN, errors.Message(err)),
+		Cause:   err,
+	})
+}
+
+func ErrorInvalidIdentityRequest(target string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrInvalidIdentityRequest,
+		Message: fmt.Sprintf("the identity request must be sent to an AWS STS endpoint over https (got %s)", target),
+	})
+}
+
+func ErrorLogsInsightsQuery(logGroup string, status string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrLogsInsightsQuery,
+		Message: fmt.Sprintf("cloudwatch logs insights query on log group %s did not complete (status: %s)", logGroup, strings.ToLower(status)),
+	})
+}
+
+func ErrorInvalidSSOProfile(profile string, reason string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrInvalidSSOProfile,
+		Message: fmt.Sprintf("aws profile %s is not a valid sso profile: %s", profile, reason),
+	})
+}
+
+func ErrorSSOLoginRequired(profile string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrSSOLoginRequired,
+		Message: fmt.Sprintf("the aws sso session of profile %s has expired or has not been started; please run `aws sso login --profile %s`", profile, profile),
+	})
+}
+
+func ErrorMFATokenRequired(profile string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrMFATokenRequired,
+		Message: fmt.Sprintf("aws profile %s requires mfa, but the mfa code can't be prompted for", profile),
+	})
+}
+
+func ErrorMFASession(profile string, err error) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrMFASession,
+		Message: fmt.Sprintf("unable to start an mfa session for aws profile %s: %s", profile, errors.Message(err)),
+		Cause:   err,
+	})
+}
+
+func ErrorIAMRoleNotFound(roleARN string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrIAMRoleNotFound,
+		Message: fmt.Sprintf("iam role %s does not exist", roleARN),
+	})
+}
+
+func ErrorIAMRoleInDifferentAccount(roleARN string, accountID string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrIAMRoleInDifferentAccount,
+		Message: fmt.Sprintf("iam role %s must be in the cluster's aws account (%s)", roleARN, accountID),
+	})
+}
+
+func ErrorIRSATrustPolicyMismatch(roleARN string, reason string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrIRSATrustPolicyMismatch,
+		Message: fmt.Sprintf("iam role %s can't be assumed by the api: %s; see https://docs.aws.amazon.com/eks/latest/userguide/associate-service-account-role.html for how to configure the role's trust policy", roleARN, reason),
+	})
+}
+
+func ErrorEKSClusterOIDCIssuerNotFound(clusterName string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrEKSClusterOIDCIssuerNotFound,
+		Message: fmt.Sprintf("unable to find the openid connect issuer of eks cluster %s", clusterName),
+	})
+}
+
+func ErrorECRImageDeletion(repository string, failures []string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrECRImageDeletion,
+		Message: fmt.Sprintf("failed to delete %s from ECR repository %s:\n%s", s.PluralS("image", len(failures)), repository, strings.Join(failures, "\n")),
+	})
+}
+
+func ErrorIdentityRequestAudience(audienceHeader string, audience string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrIdentityRequestAudience,
+		Message: fmt.Sprintf("the identity request's signature must include the %s header, set to %s", audienceHeader, audience),
+	})
+}
@@ -21,6 +21,7 @@ import (
 	"strings"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/iam"
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
@@ -226,3 +227,106 @@ func (c *Client) GetPolicyOrNil(policyARN string) (*iam.Policy, error) {
 	}
 	return nil, nil
 }
+
+type PolicySimulationResult struct {
+	Action   string
+	Resource string
+	Decision string
+}
+
+func (r PolicySimulationResult) IsAllowed() bool {
+	return r.Decision == iam.PolicyEvaluationDecisionTypeAllowed
+}
+
+// CallerPrincipalARN returns the ARN of the IAM user or role that the client's credentials belong to
+// (for assumed roles, the role's ARN is returned rather than the session's ARN); for the root user, "" is returned
+func (c *Client) CallerPrincipalARN() (string, error) {
+	identity, err := c.STS().GetCallerIdentity(nil)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	return principalARNFromCallerARN(aws.StringValue(identity.Arn)), nil
+}
+
+func principalARNFromCallerARN(callerARN string) string {
+	if strings.HasSuffix(callerARN, ":root") {
+		return ""
+	}
+
+	// expected to be in form arn:aws:sts::account-id:assumed-role/role-name/role-session-name
+	if strings.Contains(callerARN, ":assumed-role/") {
+		arnSplit := strings.Split(callerARN, "/")
+		if len(arnSplit) < 2 {
+			return callerARN
+		}
+		prefix := strings.Replace(arnSplit[0], ":sts::", ":iam::", 1)
+		prefix = strings.Replace(prefix, ":assumed-role", ":role", 1)
+		return prefix + "/" + arnSplit[1]
+	}
+
+	return callerARN
+}
+
+// PrincipalMatchesCaller returns whether the caller (as returned by GetCallerIdentity) is the principal, which is an account id or an account, role, or user arn;
+// callers which assumed a role are matched against the role by name, since assumed role arns don't include the role's path
+func PrincipalMatchesCaller(principal string, callerARN string) bool {
+	caller, err := arn.Parse(callerARN)
+	if err != nil {
+		return false
+	}
+
+	if !arn.IsARN(principal) {
+		return principal == caller.AccountID
+	}
+
+	parsedPrincipal, err := arn.Parse(principal)
+	if err != nil || parsedPrincipal.Partition != caller.Partition || parsedPrincipal.AccountID != caller.AccountID {
+		return false
+	}
+
+	switch {
+	case parsedPrincipal.Resource == "root":
+		return true
+	case strings.HasPrefix(parsedPrincipal.Resource, "user/"):
+		return caller.Service == "iam" && caller.Resource == parsedPrincipal.Resource
+	case strings.HasPrefix(parsedPrincipal.Resource, "role/"):
+		roleName := parsedPrincipal.Resource[strings.LastIndex(parsedPrincipal.Resource, "/")+1:]
+		return caller.Service == "sts" && strings.HasPrefix(caller.Resource, "assumed-role/"+roleName+"/")
+	}
+	return false
+}
+
+// SimulatePrincipalPolicy evaluates whether the principal is allowed to perform each of the actions on each of the resources
+func (c *Client) SimulatePrincipalPolicy(principalARN string, actions []string, resourceARNs ...string) ([]PolicySimulationResult, error) {
+	input := &iam.SimulatePrincipalPolicyInput{
+		PolicySourceArn: aws.String(principalARN),
+		ActionNames:     aws.StringSlice(actions),
+	}
+	if len(resourceARNs) > 0 {
+		input.ResourceArns = aws.StringSlice(resourceARNs)
+	}
+
+	var results []PolicySimulationResult
+	err := c.IAM().SimulatePrincipalPolicyPages(input, func(output *iam.SimulatePolicyResponse, lastPage bool) bool {
+		if output == nil {
+			return false
+		}
+		for _, evaluation := range output.EvaluationResults {
+			if evaluation == nil {
+				continue
+			}
+			results = append(results, PolicySimulationResult{
+				Action:   aws.StringValue(evaluation.EvalActionName),
+				Resource: aws.StringValue(evaluation.EvalResourceName),
+				Decision: aws.StringValue(evaluation.EvalDecision),
+			})
+		}
+
+		return true
+	})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return results, nil
+}
@@ -0,0 +1,45 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrincipalARNFromCallerARN(t *testing.T) {
+	require.Equal(t, "arn:aws:iam::123456789012:user/alice", principalARNFromCallerARN("arn:aws:iam::123456789012:user/alice"))
+	require.Equal(t, "arn:aws:iam::123456789012:role/admin", principalARNFromCallerARN("arn:aws:sts::123456789012:assumed-role/admin/session"))
+	require.Equal(t, "arn:aws-us-gov:iam::123456789012:role/admin", principalARNFromCallerARN("arn:aws-us-gov:sts::123456789012:assumed-role/admin/session"))
+	require.Equal(t, "", principalARNFromCallerARN("arn:aws:iam::123456789012:root"))
+}
+
+func TestPrincipalMatchesCaller(t *testing.T) {
+	userARN := "arn:aws:iam::123456789012:user/alice"
+	assumedRoleARN := "arn:aws:sts::123456789012:assumed-role/deployer/session"
+
+	require.True(t, PrincipalMatchesCaller("123456789012", userARN))
+	require.False(t, PrincipalMatchesCaller("210987654321", userARN))
+	require.True(t, PrincipalMatchesCaller("arn:aws:iam::123456789012:root", assumedRoleARN))
+	require.True(t, PrincipalMatchesCaller(userARN, userARN))
+	require.False(t, PrincipalMatchesCaller("arn:aws:iam::123456789012:user/bob", userARN))
+	require.True(t, PrincipalMatchesCaller("arn:aws:iam::123456789012:role/ci/deployer", assumedRoleARN))
+	require.False(t, PrincipalMatchesCaller("arn:aws:iam::123456789012:role/admin", assumedRoleARN))
+	require.False(t, PrincipalMatchesCaller("arn:aws-us-gov:iam::123456789012:user/alice", userARN))
+	require.False(t, PrincipalMatchesCaller(userARN, "invalid"))
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	libjson "github.com/cortexlabs/cortex/pkg/lib/json"
+	"github.com/cortexlabs/cortex/pkg/lib/sets/strset"
+)
+
+// IPRangesURL is where AWS publishes the ip address ranges of its services
+const IPRangesURL = "https://ip-ranges.amazonaws.com/ip-ranges.json"
+
+const (
+	_ipRangesRequestTimeout = 30 * time.Second
+	_ipRangesCacheDuration  = 24 * time.Hour
+)
+
+var (
+	_ipRangesCache     []byte
+	_ipRangesFetchedAt time.Time
+	_ipRangesLock      sync.Mutex
+)
+
+type ipRanges struct {
+	Prefixes []struct {
+		IPPrefix string `json:"ip_prefix"`
+		Region   string `json:"region"`
+		Service  string `json:"service"`
+	} `json:"prefixes"`
+}
+
+// ServiceIPRanges returns the (ipv4) cidr blocks which AWS publishes for the service (e.g. "S3") in the region
+func ServiceIPRanges(service string, region string) ([]string, error) {
+	body, err := getIPRanges()
+	if err != nil {
+		return nil, err
+	}
+	return parseServiceIPRanges(body, service, region)
+}
+
+func getIPRanges() ([]byte, error) {
+	_ipRangesLock.Lock()
+	defer _ipRangesLock.Unlock()
+
+	if _ipRangesCache != nil && time.Since(_ipRangesFetchedAt) < _ipRangesCacheDuration {
+		return _ipRangesCache, nil
+	}
+
+	httpClient := http.Client{Timeout: _ipRangesRequestTimeout}
+	resp, err := httpClient.Get(IPRangesURL)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.ErrorUnexpected("unable to download aws ip ranges", IPRangesURL, resp.Status)
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	_ipRangesCache = body
+	_ipRangesFetchedAt = time.Now()
+	return body, nil
+}
+
+func parseServiceIPRanges(body []byte, service string, region string) ([]string, error) {
+	var ranges ipRanges
+	if err := libjson.Unmarshal(body, &ranges); err != nil {
+		return nil, err
+	}
+
+	cidrs := strset.New()
+	for _, prefix := range ranges.Prefixes {
+		if prefix.Service == service && prefix.Region == region {
+			cidrs.Add(prefix.IPPrefix)
+		}
+	}
+
+	cidrList := cidrs.Slice()
+	sort.Strings(cidrList)
+	return cidrList, nil
+}
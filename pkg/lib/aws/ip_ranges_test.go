@@ -0,0 +1,50 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseServiceIPRanges(t *testing.T) {
+	body := []byte(`{
+		"syncToken": "1700000000",
+		"prefixes": [
+			{"ip_prefix": "52.216.0.0/15", "region": "us-east-1", "service": "AMAZON", "network_border_group": "us-east-1"},
+			{"ip_prefix": "52.216.0.0/15", "region": "us-east-1", "service": "S3", "network_border_group": "us-east-1"},
+			{"ip_prefix": "3.5.0.0/19", "region": "us-east-1", "service": "S3", "network_border_group": "us-east-1"},
+			{"ip_prefix": "3.5.0.0/19", "region": "us-east-1", "service": "S3", "network_border_group": "us-east-1"},
+			{"ip_prefix": "52.92.16.0/20", "region": "us-west-2", "service": "S3", "network_border_group": "us-west-2"}
+		],
+		"ipv6_prefixes": [
+			{"ipv6_prefix": "2600:1fa0:80::/41", "region": "us-east-1", "service": "S3", "network_border_group": "us-east-1"}
+		]
+	}`)
+
+	cidrs, err := parseServiceIPRanges(body, "S3", "us-east-1")
+	require.NoError(t, err)
+	require.Equal(t, []string{"3.5.0.0/19", "52.216.0.0/15"}, cidrs)
+
+	cidrs, err = parseServiceIPRanges(body, "S3", "eu-west-1")
+	require.NoError(t, err)
+	require.Empty(t, cidrs)
+
+	_, err = parseServiceIPRanges([]byte(`not json`), "S3", "us-east-1")
+	require.Error(t, err)
+}
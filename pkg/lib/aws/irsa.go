@@ -0,0 +1,246 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/slices"
+)
+
+// the audience of the tokens which eks issues to service accounts for IRSA (IAM roles for service accounts)
+const _irsaAudience = "sts.amazonaws.com"
+
+// IAM policy elements can be either a string or a list of strings
+type policyStrings []string
+
+func (strs *policyStrings) UnmarshalJSON(data []byte) error {
+	var str string
+	if err := json.Unmarshal(data, &str); err == nil {
+		*strs = []string{str}
+		return nil
+	}
+
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*strs = list
+	return nil
+}
+
+type trustPolicyStatement struct {
+	Effect    string                              `json:"Effect"`
+	Principal json.RawMessage                     `json:"Principal"`
+	Action    policyStrings                       `json:"Action"`
+	Condition map[string]map[string]policyStrings `json:"Condition"`
+}
+
+type trustPolicyDocument struct {
+	Statement []trustPolicyStatement `json:"Statement"`
+}
+
+func (doc *trustPolicyDocument) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Statement json.RawMessage `json:"Statement"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	var statement trustPolicyStatement
+	if err := json.Unmarshal(raw.Statement, &statement); err == nil {
+		doc.Statement = []trustPolicyStatement{statement}
+		return nil
+	}
+
+	return json.Unmarshal(raw.Statement, &doc.Statement)
+}
+
+func (statement trustPolicyStatement) federatedPrincipals() []string {
+	var principal struct {
+		Federated policyStrings `json:"Federated"`
+	}
+	if err := json.Unmarshal(statement.Principal, &principal); err != nil {
+		return nil
+	}
+	return principal.Federated
+}
+
+func (statement trustPolicyStatement) allowsWebIdentity() bool {
+	if statement.Effect != "Allow" {
+		return false
+	}
+	for _, action := range statement.Action {
+		if action == "sts:AssumeRoleWithWebIdentity" || action == "sts:*" || action == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// returns the values of the condition key for the StringEquals and StringLike operators, and whether the key is constrained by either of them
+func (statement trustPolicyStatement) conditionValues(key string) ([]string, []string, bool) {
+	var equalsValues, likeValues []string
+	isConstrained := false
+	for operator, conditions := range statement.Condition {
+		for conditionKey, values := range conditions {
+			if !strings.EqualFold(conditionKey, key) {
+				continue
+			}
+			switch operator {
+			case "StringEquals":
+				equalsValues = append(equalsValues, values...)
+				isConstrained = true
+			case "StringLike":
+				likeValues = append(likeValues, values...)
+				isConstrained = true
+			}
+		}
+	}
+	return equalsValues, likeValues, isConstrained
+}
+
+func conditionMatches(equalsValues []string, likeValues []string, value string) bool {
+	if slices.HasString(equalsValues, value) {
+		return true
+	}
+	for _, pattern := range likeValues {
+		if policyWildcardPattern(pattern).MatchString(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// converts an IAM policy StringLike pattern (which supports * and ?) to a regular expression
+func policyWildcardPattern(pattern string) *regexp.Regexp {
+	quoted := regexp.QuoteMeta(pattern)
+	quoted = strings.ReplaceAll(quoted, `\*`, ".*")
+	quoted = strings.ReplaceAll(quoted, `\?`, ".")
+	return regexp.MustCompile("^" + quoted + "$")
+}
+
+// checkIRSATrustPolicy returns why the trust policy doesn't allow the service account (e.g. system:serviceaccount:default:my-api) to assume the role
+// with a token from the oidc provider, or "" if it does
+func checkIRSATrustPolicy(policyDocument string, oidcProviderARN string, serviceAccountSubject string) (string, error) {
+	var doc trustPolicyDocument
+	if err := json.Unmarshal([]byte(policyDocument), &doc); err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	issuerHostPath := oidcProviderARN[strings.Index(oidcProviderARN, ":oidc-provider/")+len(":oidc-provider/"):]
+
+	var subjectPatterns []string
+	for _, statement := range doc.Statement {
+		if !statement.allowsWebIdentity() || !slices.HasString(statement.federatedPrincipals(), oidcProviderARN) {
+			continue
+		}
+
+		audEquals, audLike, audConstrained := statement.conditionValues(issuerHostPath + ":aud")
+		if audConstrained && !conditionMatches(audEquals, audLike, _irsaAudience) {
+			continue
+		}
+
+		subEquals, subLike, subConstrained := statement.conditionValues(issuerHostPath + ":sub")
+		if !subConstrained || conditionMatches(subEquals, subLike, serviceAccountSubject) {
+			return "", nil
+		}
+		subjectPatterns = append(subjectPatterns, append(subEquals, subLike...)...)
+	}
+
+	if len(subjectPatterns) > 0 {
+		return fmt.Sprintf("its trust policy only allows %s to assume it, but the api's service account is %s", strings.Join(subjectPatterns, ", "), serviceAccountSubject), nil
+	}
+
+	return fmt.Sprintf("its trust policy doesn't allow sts:AssumeRoleWithWebIdentity for the cluster's oidc provider (%s)", oidcProviderARN), nil
+}
+
+// GetRoleTrustPolicy returns the trust policy document of the role (which must be in the client's account)
+func (c *Client) GetRoleTrustPolicy(roleARN string) (string, error) {
+	roleName := roleARN[strings.LastIndex(roleARN, "/")+1:]
+
+	output, err := c.IAM().GetRole(&iam.GetRoleInput{
+		RoleName: aws.String(roleName),
+	})
+	if err != nil {
+		if IsNoSuchEntityErr(err) {
+			return "", ErrorIAMRoleNotFound(roleARN)
+		}
+		return "", errors.WithStack(err)
+	}
+
+	policyDocument, err := url.QueryUnescape(aws.StringValue(output.Role.AssumeRolePolicyDocument))
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	return policyDocument, nil
+}
+
+// EKSClusterOIDCIssuer returns the url of the cluster's openid connect issuer (e.g. https://oidc.eks.us-west-2.amazonaws.com/id/EXAMPLED539D4633E53DE1B71EXAMPLE)
+func (c *Client) EKSClusterOIDCIssuer(clusterName string) (string, error) {
+	cluster, err := c.EKSClusterOrNil(clusterName)
+	if err != nil {
+		return "", err
+	}
+	if cluster == nil || cluster.Identity == nil || cluster.Identity.Oidc == nil || aws.StringValue(cluster.Identity.Oidc.Issuer) == "" {
+		return "", ErrorEKSClusterOIDCIssuerNotFound(clusterName)
+	}
+
+	return *cluster.Identity.Oidc.Issuer, nil
+}
+
+// OIDCProviderARN returns the arn of the IAM oidc provider of the issuer in the account
+func OIDCProviderARN(region string, accountID string, oidcIssuer string) string {
+	return fmt.Sprintf("arn:%s:iam::%s:oidc-provider/%s", PartitionFromRegion(region), accountID, strings.TrimPrefix(oidcIssuer, "https://"))
+}
+
+// ValidateIRSARole checks that the role's trust policy allows the service account (e.g. system:serviceaccount:default:my-api)
+// to assume the role via the oidc provider of the eks cluster's issuer; the role must be in the client's account
+func (c *Client) ValidateIRSARole(roleARN string, oidcIssuer string, serviceAccountSubject string) error {
+	accountID, _, err := c.GetCachedAccountID()
+	if err != nil {
+		return err
+	}
+
+	if roleAccountID := strings.Split(roleARN, ":")[4]; roleAccountID != accountID {
+		return ErrorIAMRoleInDifferentAccount(roleARN, accountID)
+	}
+
+	policyDocument, err := c.GetRoleTrustPolicy(roleARN)
+	if err != nil {
+		return err
+	}
+
+	reason, err := checkIRSATrustPolicy(policyDocument, OIDCProviderARN(c.Region, accountID, oidcIssuer), serviceAccountSubject)
+	if err != nil {
+		return err
+	}
+	if reason != "" {
+		return ErrorIRSATrustPolicyMismatch(roleARN, reason)
+	}
+
+	return nil
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	_testOIDCIssuer      = "https://oidc.eks.us-west-2.amazonaws.com/id/EXAMPLED539D4633E53DE1B71EXAMPLE"
+	_testOIDCProviderARN = "arn:aws:iam::123456789012:oidc-provider/oidc.eks.us-west-2.amazonaws.com/id/EXAMPLED539D4633E53DE1B71EXAMPLE"
+	_testSubject         = "system:serviceaccount:default:my-api-iam-role"
+)
+
+func TestOIDCProviderARN(t *testing.T) {
+	require.Equal(t, _testOIDCProviderARN, OIDCProviderARN("us-west-2", "123456789012", _testOIDCIssuer))
+}
+
+func TestCheckIRSATrustPolicy(t *testing.T) {
+	testcases := []struct {
+		name           string
+		policyDocument string
+		valid          bool
+	}{
+		{
+			name: "sub and aud equal",
+			policyDocument: `{
+				"Version": "2012-10-17",
+				"Statement": [{
+					"Effect": "Allow",
+					"Principal": {"Federated": "arn:aws:iam::123456789012:oidc-provider/oidc.eks.us-west-2.amazonaws.com/id/EXAMPLED539D4633E53DE1B71EXAMPLE"},
+					"Action": "sts:AssumeRoleWithWebIdentity",
+					"Condition": {"StringEquals": {
+						"oidc.eks.us-west-2.amazonaws.com/id/EXAMPLED539D4633E53DE1B71EXAMPLE:sub": "system:serviceaccount:default:my-api-iam-role",
+						"oidc.eks.us-west-2.amazonaws.com/id/EXAMPLED539D4633E53DE1B71EXAMPLE:aud": "sts.amazonaws.com"
+					}}
+				}]
+			}`,
+			valid: true,
+		},
+		{
+			name: "single statement with sub wildcard",
+			policyDocument: `{
+				"Statement": {
+					"Effect": "Allow",
+					"Principal": {"Federated": ["arn:aws:iam::123456789012:oidc-provider/oidc.eks.us-west-2.amazonaws.com/id/EXAMPLED539D4633E53DE1B71EXAMPLE"]},
+					"Action": ["sts:AssumeRoleWithWebIdentity"],
+					"Condition": {"StringLike": {"oidc.eks.us-west-2.amazonaws.com/id/EXAMPLED539D4633E53DE1B71EXAMPLE:sub": "system:serviceaccount:default:*"}}
+				}
+			}`,
+			valid: true,
+		},
+		{
+			name: "no conditions",
+			policyDocument: `{
+				"Statement": [{
+					"Effect": "Allow",
+					"Principal": {"Federated": "arn:aws:iam::123456789012:oidc-provider/oidc.eks.us-west-2.amazonaws.com/id/EXAMPLED539D4633E53DE1B71EXAMPLE"},
+					"Action": "sts:AssumeRoleWithWebIdentity"
+				}]
+			}`,
+			valid: true,
+		},
+		{
+			name: "different service account",
+			policyDocument: `{
+				"Statement": [{
+					"Effect": "Allow",
+					"Principal": {"Federated": "arn:aws:iam::123456789012:oidc-provider/oidc.eks.us-west-2.amazonaws.com/id/EXAMPLED539D4633E53DE1B71EXAMPLE"},
+					"Action": "sts:AssumeRoleWithWebIdentity",
+					"Condition": {"StringEquals": {"oidc.eks.us-west-2.amazonaws.com/id/EXAMPLED539D4633E53DE1B71EXAMPLE:sub": "system:serviceaccount:default:other"}}
+				}]
+			}`,
+			valid: false,
+		},
+		{
+			name: "different audience",
+			policyDocument: `{
+				"Statement": [{
+					"Effect": "Allow",
+					"Principal": {"Federated": "arn:aws:iam::123456789012:oidc-provider/oidc.eks.us-west-2.amazonaws.com/id/EXAMPLED539D4633E53DE1B71EXAMPLE"},
+					"Action": "sts:AssumeRoleWithWebIdentity",
+					"Condition": {"StringEquals": {"oidc.eks.us-west-2.amazonaws.com/id/EXAMPLED539D4633E53DE1B71EXAMPLE:aud": "other"}}
+				}]
+			}`,
+			valid: false,
+		},
+		{
+			name: "different oidc provider",
+			policyDocument: `{
+				"Statement": [{
+					"Effect": "Allow",
+					"Principal": {"Federated": "arn:aws:iam::123456789012:oidc-provider/oidc.eks.us-east-1.amazonaws.com/id/OTHER"},
+					"Action": "sts:AssumeRoleWithWebIdentity"
+				}]
+			}`,
+			valid: false,
+		},
+		{
+			name: "service principal",
+			policyDocument: `{
+				"Statement": [{
+					"Effect": "Allow",
+					"Principal": {"Service": "ec2.amazonaws.com"},
+					"Action": "sts:AssumeRole"
+				}]
+			}`,
+			valid: false,
+		},
+		{
+			name: "denied",
+			policyDocument: `{
+				"Statement": [{
+					"Effect": "Deny",
+					"Principal": {"Federated": "arn:aws:iam::123456789012:oidc-provider/oidc.eks.us-west-2.amazonaws.com/id/EXAMPLED539D4633E53DE1B71EXAMPLE"},
+					"Action": "sts:AssumeRoleWithWebIdentity"
+				}]
+			}`,
+			valid: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			reason, err := checkIRSATrustPolicy(tc.policyDocument, _testOIDCProviderARN, _testSubject)
+			require.NoError(t, err)
+			if tc.valid {
+				require.Empty(t, reason)
+			} else {
+				require.NotEmpty(t, reason)
+			}
+		})
+	}
+
+	_, err := checkIRSATrustPolicy("not json", _testOIDCProviderARN, _testSubject)
+	require.Error(t, err)
+}
+
+func TestPolicyWildcardPattern(t *testing.T) {
+	require.True(t, policyWildcardPattern("system:serviceaccount:default:*").MatchString(_testSubject))
+	require.True(t, policyWildcardPattern("system:serviceaccount:defaul?:my-api-iam-role").MatchString(_testSubject))
+	require.False(t, policyWildcardPattern("system:serviceaccount:other:*").MatchString(_testSubject))
+	require.False(t, policyWildcardPattern("system:serviceaccount:default:my-api").MatchString(_testSubject))
+}
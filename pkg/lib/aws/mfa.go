@@ -0,0 +1,213 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	libjson "github.com/cortexlabs/cortex/pkg/lib/json"
+)
+
+// MFATokenProvider returns the current code of the mfa device (identified by its serial number or arn); it is called when a profile which sets mfa_serial
+// has no valid cached mfa session, and profiles which require mfa can't be used if it is nil
+var MFATokenProvider func(mfaSerial string) (string, error)
+
+// MFASessionCacheDir is the directory in which the temporary credentials of mfa sessions are cached (one file per profile); caching is disabled if it is empty
+var MFASessionCacheDir string
+
+// MFASessionDuration is how long the temporary credentials of mfa sessions are valid for
+var MFASessionDuration = 12 * time.Hour
+
+// MFASessionProviderName is the name of the provider of the credentials of mfa sessions
+const MFASessionProviderName = "MFASessionProvider"
+
+// cached mfa sessions which expire sooner than this are renewed, so that commands don't start with credentials which expire while they run
+const _mfaSessionMinRemaining = 30 * time.Minute
+
+// mfa sessions are renewed this long before they expire
+const _mfaSessionExpiryWindow = 5 * time.Minute
+
+type mfaProfile struct {
+	ProfileName string
+	MFASerial   string
+	AssumesRole bool
+}
+
+// mfaTokenSource prompts for the codes of a client's mfa device via MFATokenProvider, unless prompts are disabled (while the client's credentials are refreshed in the background)
+type mfaTokenSource struct {
+	profile         mfaProfile
+	promptsDisabled atomic.Bool
+}
+
+func (s *mfaTokenSource) tokenCode() (string, error) {
+	if MFATokenProvider == nil || s.promptsDisabled.Load() {
+		return "", ErrorMFATokenRequired(s.profile.ProfileName)
+	}
+	return MFATokenProvider(s.profile.MFASerial)
+}
+
+// getMFAProfile returns nil if the profile doesn't set mfa_serial
+func getMFAProfile(sharedConfig map[string]map[string]string, profile string) *mfaProfile {
+	profileSection := getProfileSection(sharedConfig, profile)
+	if profileSection["mfa_serial"] == "" {
+		return nil
+	}
+
+	return &mfaProfile{
+		ProfileName: profile,
+		MFASerial:   profileSection["mfa_serial"],
+		AssumesRole: profileSection["role_arn"] != "",
+	}
+}
+
+func mfaSessionCachePath(dir string, profile mfaProfile, accessKeyID string) string {
+	hash := sha1.Sum([]byte(profile.ProfileName + "|" + profile.MFASerial + "|" + accessKeyID))
+	return filepath.Join(dir, hex.EncodeToString(hash[:])+".json")
+}
+
+// returns nil if the session isn't cached, or if it expires within minRemaining
+func readMFASessionCache(path string, minRemaining time.Duration, now time.Time) *credentialProcessOutput {
+	cacheBytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+
+	var cached credentialProcessOutput
+	if err := libjson.Unmarshal(cacheBytes, &cached); err != nil {
+		return nil
+	}
+
+	if cached.AccessKeyID == "" || cached.Expiration == nil || cached.Expiration.Sub(now) < minRemaining {
+		return nil
+	}
+
+	return &cached
+}
+
+func writeMFASessionCache(path string, cached credentialProcessOutput) error {
+	cacheBytes, err := libjson.Marshal(cached)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return errors.WithStack(err)
+	}
+
+	// write to a uniquely named temporary file in the same directory and rename it, so that concurrent cortex commands never read (or write) a partially written file
+	tmpFile, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer os.Remove(tmpFile.Name()) // no-op once the file has been renamed
+
+	if _, err := tmpFile.Write(cacheBytes); err != nil {
+		tmpFile.Close()
+		return errors.WithStack(err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		return errors.WithStack(err)
+	}
+	if err := os.Rename(tmpFile.Name(), path); err != nil {
+		return errors.WithStack(err)
+	}
+
+	return nil
+}
+
+func mfaSessionValue(mfaSession credentialProcessOutput) credentials.Value {
+	return credentials.Value{
+		AccessKeyID:     mfaSession.AccessKeyID,
+		SecretAccessKey: mfaSession.SecretAccessKey,
+		SessionToken:    mfaSession.SessionToken,
+		ProviderName:    MFASessionProviderName,
+	}
+}
+
+// mfaSessionProvider exchanges the long-term credentials of a profile and the code of its mfa device for temporary credentials (via sts GetSessionToken)
+type mfaSessionProvider struct {
+	credentials.Expiry
+	client    *sts.STS
+	baseCreds *credentials.Credentials
+	profile   mfaProfile
+	tokens    *mfaTokenSource
+}
+
+func newMFASessionCredentials(baseSess *session.Session, tokens *mfaTokenSource) *credentials.Credentials {
+	return credentials.NewCredentials(&mfaSessionProvider{
+		client:    sts.New(baseSess),
+		baseCreds: baseSess.Config.Credentials,
+		profile:   tokens.profile,
+		tokens:    tokens,
+	})
+}
+
+func (p *mfaSessionProvider) Retrieve() (credentials.Value, error) {
+	baseCreds, err := p.baseCreds.Get()
+	if err != nil {
+		return credentials.Value{ProviderName: MFASessionProviderName}, err
+	}
+
+	var cachePath string
+	if MFASessionCacheDir != "" {
+		cachePath = mfaSessionCachePath(MFASessionCacheDir, p.profile, baseCreds.AccessKeyID)
+		if cached := readMFASessionCache(cachePath, _mfaSessionMinRemaining, time.Now()); cached != nil {
+			p.SetExpiration(*cached.Expiration, _mfaSessionExpiryWindow)
+			return mfaSessionValue(*cached), nil
+		}
+	}
+
+	tokenCode, err := p.tokens.tokenCode()
+	if err != nil {
+		return credentials.Value{ProviderName: MFASessionProviderName}, err
+	}
+
+	output, err := p.client.GetSessionToken(&sts.GetSessionTokenInput{
+		DurationSeconds: aws.Int64(int64(MFASessionDuration.Seconds())),
+		SerialNumber:    aws.String(p.profile.MFASerial),
+		TokenCode:       aws.String(tokenCode),
+	})
+	if err != nil {
+		return credentials.Value{ProviderName: MFASessionProviderName}, ErrorMFASession(p.profile.ProfileName, err)
+	}
+
+	mfaSession := credentialProcessOutput{
+		Version:         1,
+		AccessKeyID:     aws.StringValue(output.Credentials.AccessKeyId),
+		SecretAccessKey: aws.StringValue(output.Credentials.SecretAccessKey),
+		SessionToken:    aws.StringValue(output.Credentials.SessionToken),
+		Expiration:      output.Credentials.Expiration,
+	}
+
+	// the cache is best-effort, so errors are ignored
+	if cachePath != "" {
+		_ = writeMFASessionCache(cachePath, mfaSession)
+	}
+
+	p.SetExpiration(*mfaSession.Expiration, _mfaSessionExpiryWindow)
+	return mfaSessionValue(mfaSession), nil
+}
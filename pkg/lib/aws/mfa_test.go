@@ -0,0 +1,109 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetMFAProfile(t *testing.T) {
+	sharedConfig := parseSharedConfig(`
+[default]
+mfa_serial = arn:aws:iam::123456789012:mfa/user
+
+[profile admin]
+role_arn = arn:aws:iam::123456789012:role/admin
+source_profile = default
+mfa_serial = arn:aws:iam::123456789012:mfa/user
+
+[profile dev]
+region = us-west-2
+`)
+
+	require.Equal(t, &mfaProfile{
+		ProfileName: "default",
+		MFASerial:   "arn:aws:iam::123456789012:mfa/user",
+	}, getMFAProfile(sharedConfig, "default"))
+
+	require.Equal(t, &mfaProfile{
+		ProfileName: "admin",
+		MFASerial:   "arn:aws:iam::123456789012:mfa/user",
+		AssumesRole: true,
+	}, getMFAProfile(sharedConfig, "admin"))
+
+	require.Nil(t, getMFAProfile(sharedConfig, "dev"))
+	require.Nil(t, getMFAProfile(sharedConfig, "nonexistent"))
+}
+
+func TestMFASessionCache(t *testing.T) {
+	dir := t.TempDir()
+	profile := mfaProfile{ProfileName: "default", MFASerial: "arn:aws:iam::123456789012:mfa/user"}
+	now := time.Now()
+
+	path := mfaSessionCachePath(dir, profile, "AKIAEXAMPLE")
+	require.Equal(t, dir, filepath.Dir(path))
+	require.NotEqual(t, path, mfaSessionCachePath(dir, profile, "AKIAOTHER"))
+
+	require.Nil(t, readMFASessionCache(path, _mfaSessionMinRemaining, now))
+
+	expiration := now.Add(time.Hour).UTC().Truncate(time.Second)
+	require.NoError(t, writeMFASessionCache(path, credentialProcessOutput{
+		Version:         1,
+		AccessKeyID:     "ASIAEXAMPLE",
+		SecretAccessKey: "secret",
+		SessionToken:    "token",
+		Expiration:      &expiration,
+	}))
+
+	cached := readMFASessionCache(path, _mfaSessionMinRemaining, now)
+	require.NotNil(t, cached)
+	require.Equal(t, "ASIAEXAMPLE", cached.AccessKeyID)
+	require.True(t, expiration.Equal(*cached.Expiration))
+
+	// sessions which expire soon are renewed
+	require.Nil(t, readMFASessionCache(path, _mfaSessionMinRemaining, now.Add(45*time.Minute)))
+
+	// the temporary file is renamed into place
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, filepath.Base(path), entries[0].Name())
+}
+
+func TestMFATokenSource(t *testing.T) {
+	prevProvider := MFATokenProvider
+	defer func() { MFATokenProvider = prevProvider }()
+	MFATokenProvider = func(mfaSerial string) (string, error) {
+		return "123456", nil
+	}
+
+	tokens := &mfaTokenSource{profile: mfaProfile{ProfileName: "default", MFASerial: "arn:aws:iam::123456789012:mfa/user"}}
+
+	tokenCode, err := tokens.tokenCode()
+	require.NoError(t, err)
+	require.Equal(t, "123456", tokenCode)
+
+	tokens.promptsDisabled.Store(true)
+	_, err = tokens.tokenCode()
+	require.Equal(t, ErrMFATokenRequired, errors.GetKind(err))
+}
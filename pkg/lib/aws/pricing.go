@@ -0,0 +1,123 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/pricing"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+)
+
+const _pricingAPIRegion = "us-east-1"
+
+// OnDemandInstancePrice returns the hourly on-demand price (in USD) of a Linux instance in the client's region;
+// if the Pricing API can't be reached (e.g. due to missing permissions), the price is looked up in the built-in table
+func (c *Client) OnDemandInstancePrice(instanceType string) (float64, error) {
+	price, err := c.onDemandInstancePriceFromPricingAPI(instanceType)
+	if err == nil {
+		return price, nil
+	}
+
+	if metadata, ok := InstanceMetadatas[c.Region][instanceType]; ok && metadata.Price > 0 {
+		return metadata.Price, nil
+	}
+
+	return 0, err
+}
+
+func (c *Client) onDemandInstancePriceFromPricingAPI(instanceType string) (float64, error) {
+	filters := map[string]string{
+		"instanceType":    instanceType,
+		"regionCode":      c.Region,
+		"operatingSystem": "Linux",
+		"tenancy":         "Shared",
+		"preInstalledSw":  "NA",
+		"capacitystatus":  "Used",
+	}
+
+	input := &pricing.GetProductsInput{
+		ServiceCode: aws.String("AmazonEC2"),
+		MaxResults:  aws.Int64(10),
+	}
+	for field, value := range filters {
+		input.Filters = append(input.Filters, &pricing.Filter{
+			Type:  aws.String(pricing.FilterTypeTermMatch),
+			Field: aws.String(field),
+			Value: aws.String(value),
+		})
+	}
+
+	result, err := c.Pricing().GetProducts(input)
+	if err != nil {
+		return 0, errors.Wrap(err, "checking on-demand instance price")
+	}
+
+	for _, product := range result.PriceList {
+		if price, ok := parseOnDemandPrice(product); ok {
+			return price, nil
+		}
+	}
+
+	return 0, ErrorNoValidOnDemandPrice(instanceType, c.Region)
+}
+
+// parses the hourly USD price from the on-demand terms of a Pricing API product
+func parseOnDemandPrice(product aws.JSONValue) (float64, bool) {
+	terms, ok := product["terms"].(map[string]interface{})
+	if !ok {
+		return 0, false
+	}
+	onDemandTerms, ok := terms["OnDemand"].(map[string]interface{})
+	if !ok {
+		return 0, false
+	}
+
+	for _, term := range onDemandTerms {
+		termMap, ok := term.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		priceDimensions, ok := termMap["priceDimensions"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		for _, priceDimension := range priceDimensions {
+			priceDimensionMap, ok := priceDimension.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if unit, _ := priceDimensionMap["unit"].(string); unit != "Hrs" {
+				continue
+			}
+			pricePerUnit, ok := priceDimensionMap["pricePerUnit"].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			usdStr, ok := pricePerUnit["USD"].(string)
+			if !ok {
+				continue
+			}
+			if price, ok := s.ParseFloat64(usdStr); ok && price > 0 {
+				return price, true
+			}
+		}
+	}
+
+	return 0, false
+}
@@ -0,0 +1,58 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOnDemandPrice(t *testing.T) {
+	productJSON := `{
+		"product": {"attributes": {"instanceType": "t3.medium", "regionCode": "us-west-2"}},
+		"terms": {
+			"OnDemand": {
+				"ABC.JRTCKXETXF": {
+					"priceDimensions": {
+						"ABC.JRTCKXETXF.6YS6EN2CT7": {
+							"unit": "Hrs",
+							"pricePerUnit": {"USD": "0.0416000000"}
+						}
+					}
+				}
+			}
+		}
+	}`
+
+	var product aws.JSONValue
+	require.NoError(t, json.Unmarshal([]byte(productJSON), &product))
+
+	price, ok := parseOnDemandPrice(product)
+	require.True(t, ok)
+	require.Equal(t, 0.0416, price)
+
+	_, ok = parseOnDemandPrice(aws.JSONValue{"terms": map[string]interface{}{}})
+	require.False(t, ok)
+
+	var zeroPriceProduct aws.JSONValue
+	require.NoError(t, json.Unmarshal([]byte(`{"terms": {"OnDemand": {"a": {"priceDimensions": {"b": {"unit": "Hrs", "pricePerUnit": {"USD": "0.0000000000"}}}}}}}`), &zeroPriceProduct))
+	_, ok = parseOnDemandPrice(zeroPriceProduct)
+	require.False(t, ok)
+}
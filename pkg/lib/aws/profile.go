@@ -0,0 +1,298 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/ec2rolecreds"
+	"github.com/aws/aws-sdk-go/aws/credentials/endpointcreds"
+	"github.com/aws/aws-sdk-go/aws/credentials/processcreds"
+	"github.com/aws/aws-sdk-go/aws/credentials/ssocreds"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sso"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/sets/strset"
+)
+
+// ssoSessionProfile is a profile which references an [sso-session X] section of the shared config file
+// (the sdk only resolves sso profiles which set sso_start_url and sso_region directly)
+type ssoSessionProfile struct {
+	ProfileName string
+	SessionName string
+	StartURL    string
+	Region      string
+	AccountID   string
+	RoleName    string
+}
+
+// resolvedProfileName returns the profile which the sdk uses if profile is empty
+func resolvedProfileName(profile string) string {
+	if profile != "" {
+		return profile
+	}
+	if envProfile := os.Getenv("AWS_PROFILE"); envProfile != "" {
+		return envProfile
+	}
+	if envProfile := os.Getenv("AWS_DEFAULT_PROFILE"); envProfile != "" {
+		return envProfile
+	}
+	return "default"
+}
+
+func sharedConfigFilePath() (string, error) {
+	if path := os.Getenv("AWS_CONFIG_FILE"); path != "" {
+		return path, nil
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	return filepath.Join(homeDir, ".aws", "config"), nil
+}
+
+func ssoTokenCacheFilePath(sessionName string) (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	hash := sha1.Sum([]byte(sessionName))
+	return filepath.Join(homeDir, ".aws", "sso", "cache", hex.EncodeToString(hash[:])+".json"), nil
+}
+
+// parseSharedConfig returns the keys of each section of a shared config file, by section name (e.g. "default", "profile dev", "sso-session my-sso")
+func parseSharedConfig(contents string) map[string]map[string]string {
+	sections := map[string]map[string]string{}
+
+	var section map[string]string
+	scanner := bufio.NewScanner(strings.NewReader(contents))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			name := strings.Join(strings.Fields(line[1:len(line)-1]), " ")
+			if _, ok := sections[name]; !ok {
+				sections[name] = map[string]string{}
+			}
+			section = sections[name]
+			continue
+		}
+
+		if section == nil {
+			continue
+		}
+
+		split := strings.SplitN(line, "=", 2)
+		if len(split) != 2 {
+			continue
+		}
+		section[strings.TrimSpace(split[0])] = strings.TrimSpace(split[1])
+	}
+
+	return sections
+}
+
+// getSSOSessionProfile returns nil if the profile doesn't reference an sso-session section
+func getSSOSessionProfile(sharedConfig map[string]map[string]string, profile string) (*ssoSessionProfile, error) {
+	profileSection := getProfileSection(sharedConfig, profile)
+	if profileSection["sso_session"] == "" {
+		return nil, nil
+	}
+
+	sessionName := profileSection["sso_session"]
+	sessionSection, ok := sharedConfig["sso-session "+sessionName]
+	if !ok {
+		return nil, ErrorInvalidSSOProfile(profile, "the [sso-session "+sessionName+"] section is missing")
+	}
+
+	ssoProfile := ssoSessionProfile{
+		ProfileName: profile,
+		SessionName: sessionName,
+		StartURL:    sessionSection["sso_start_url"],
+		Region:      sessionSection["sso_region"],
+		AccountID:   profileSection["sso_account_id"],
+		RoleName:    profileSection["sso_role_name"],
+	}
+
+	missingKeys := strset.New()
+	if ssoProfile.StartURL == "" {
+		missingKeys.Add("sso_start_url")
+	}
+	if ssoProfile.Region == "" {
+		missingKeys.Add("sso_region")
+	}
+	if ssoProfile.AccountID == "" {
+		missingKeys.Add("sso_account_id")
+	}
+	if ssoProfile.RoleName == "" {
+		missingKeys.Add("sso_role_name")
+	}
+	if len(missingKeys) > 0 {
+		return nil, ErrorInvalidSSOProfile(profile, "missing "+strings.Join(missingKeys.SliceSorted(), ", "))
+	}
+
+	return &ssoProfile, nil
+}
+
+// a missing shared config file is treated as an empty one
+func loadSharedConfig() (map[string]map[string]string, error) {
+	path, err := sharedConfigFilePath()
+	if err != nil {
+		return nil, err
+	}
+
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return map[string]map[string]string{}, nil
+		}
+		return nil, errors.WithStack(err)
+	}
+
+	return parseSharedConfig(string(contents)), nil
+}
+
+// getProfileSection returns nil if the profile isn't in the shared config
+func getProfileSection(sharedConfig map[string]map[string]string, profile string) map[string]string {
+	if profileSection, ok := sharedConfig["profile "+profile]; ok {
+		return profileSection
+	}
+	if profile == "default" {
+		return sharedConfig["default"]
+	}
+	return nil
+}
+
+type ssoToken struct {
+	AccessToken string    `json:"accessToken"`
+	ExpiresAt   time.Time `json:"expiresAt"`
+}
+
+// ssoSessionProvider exchanges the cached token of an sso session (which is created by `aws sso login`) for role credentials
+type ssoSessionProvider struct {
+	credentials.Expiry
+	client  *sso.SSO
+	profile ssoSessionProfile
+}
+
+func newSSOSessionCredentials(profile ssoSessionProfile) (*credentials.Credentials, error) {
+	sess, err := session.NewSession(&aws.Config{
+		Region:      aws.String(profile.Region),
+		Credentials: credentials.AnonymousCredentials,
+	})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return credentials.NewCredentials(&ssoSessionProvider{
+		client:  sso.New(sess),
+		profile: profile,
+	}), nil
+}
+
+func (p *ssoSessionProvider) Retrieve() (credentials.Value, error) {
+	invalidTokenErr := awserr.New(ssocreds.ErrCodeSSOProviderInvalidToken, "the sso session has expired or is invalid", nil)
+
+	path, err := ssoTokenCacheFilePath(p.profile.SessionName)
+	if err != nil {
+		return credentials.Value{ProviderName: ssocreds.ProviderName}, err
+	}
+
+	tokenBytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		return credentials.Value{ProviderName: ssocreds.ProviderName}, invalidTokenErr
+	}
+
+	var token ssoToken
+	if err := json.Unmarshal(tokenBytes, &token); err != nil || token.AccessToken == "" || time.Now().After(token.ExpiresAt) {
+		return credentials.Value{ProviderName: ssocreds.ProviderName}, invalidTokenErr
+	}
+
+	output, err := p.client.GetRoleCredentials(&sso.GetRoleCredentialsInput{
+		AccessToken: aws.String(token.AccessToken),
+		AccountId:   aws.String(p.profile.AccountID),
+		RoleName:    aws.String(p.profile.RoleName),
+	})
+	if err != nil {
+		return credentials.Value{ProviderName: ssocreds.ProviderName}, err
+	}
+
+	p.SetExpiration(time.Unix(0, aws.Int64Value(output.RoleCredentials.Expiration)*int64(time.Millisecond)), time.Minute)
+
+	return credentials.Value{
+		AccessKeyID:     aws.StringValue(output.RoleCredentials.AccessKeyId),
+		SecretAccessKey: aws.StringValue(output.RoleCredentials.SecretAccessKey),
+		SessionToken:    aws.StringValue(output.RoleCredentials.SessionToken),
+		ProviderName:    ssocreds.ProviderName,
+	}, nil
+}
+
+func isSSOLoginRequiredErr(err error) bool {
+	return IsErrCode(err, ssocreds.ErrCodeSSOProviderInvalidToken) || IsErrCode(err, sso.ErrCodeUnauthorizedException)
+}
+
+// CredentialsSource describes where the client's credentials were resolved from
+func (c *Client) CredentialsSource() string {
+	if c.sess.Config.Credentials == nil {
+		return "unknown"
+	}
+
+	creds, err := c.sess.Config.Credentials.Get()
+	if err != nil {
+		return "unknown"
+	}
+
+	switch {
+	case creds.ProviderName == session.EnvProviderName:
+		return "environment variables"
+	case strings.HasPrefix(creds.ProviderName, "SharedConfigCredentials"), creds.ProviderName == credentials.SharedCredsProviderName:
+		return "shared credentials file"
+	case creds.ProviderName == ssocreds.ProviderName:
+		return "aws sso"
+	case creds.ProviderName == MFASessionProviderName:
+		return "mfa session"
+	case creds.ProviderName == processcreds.ProviderName:
+		return "credential_process"
+	case creds.ProviderName == ec2rolecreds.ProviderName:
+		return "ec2 instance metadata"
+	case creds.ProviderName == endpointcreds.ProviderName:
+		return "container credentials endpoint"
+	case creds.ProviderName == stscreds.ProviderName:
+		return "assumed role"
+	case creds.ProviderName == stscreds.WebIdentityProviderName:
+		return "web identity token"
+	case creds.ProviderName == credentials.StaticProviderName:
+		return "static credentials"
+	}
+
+	return creds.ProviderName
+}
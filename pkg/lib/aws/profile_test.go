@@ -0,0 +1,115 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/stretchr/testify/require"
+)
+
+const _testSharedConfig = `
+[default]
+region = us-west-2
+
+# sso-session profile
+[profile dev]
+sso_session = my-sso
+sso_account_id = 123456789012
+sso_role_name = Developer
+region = us-east-1
+
+[profile legacy]
+sso_start_url = https://example.awsapps.com/start
+sso_region = us-east-1
+
+[profile  missing-session]
+sso_session = other
+
+[profile incomplete]
+sso_session = my-sso
+sso_account_id = 123456789012
+
+[sso-session my-sso]
+sso_start_url = https://example.awsapps.com/start
+sso_region = us-east-2
+`
+
+func TestParseSharedConfig(t *testing.T) {
+	sharedConfig := parseSharedConfig(_testSharedConfig)
+
+	require.Equal(t, map[string]string{"region": "us-west-2"}, sharedConfig["default"])
+	require.Equal(t, "my-sso", sharedConfig["profile dev"]["sso_session"])
+	require.Equal(t, "other", sharedConfig["profile missing-session"]["sso_session"])
+	require.Equal(t, "https://example.awsapps.com/start", sharedConfig["sso-session my-sso"]["sso_start_url"])
+}
+
+func TestGetSSOSessionProfile(t *testing.T) {
+	sharedConfig := parseSharedConfig(_testSharedConfig)
+
+	ssoProfile, err := getSSOSessionProfile(sharedConfig, "dev")
+	require.NoError(t, err)
+	require.Equal(t, &ssoSessionProfile{
+		ProfileName: "dev",
+		SessionName: "my-sso",
+		StartURL:    "https://example.awsapps.com/start",
+		Region:      "us-east-2",
+		AccountID:   "123456789012",
+		RoleName:    "Developer",
+	}, ssoProfile)
+
+	// profiles which don't reference an sso session are resolved by the sdk
+	for _, profile := range []string{"default", "legacy", "nonexistent"} {
+		ssoProfile, err = getSSOSessionProfile(sharedConfig, profile)
+		require.NoError(t, err)
+		require.Nil(t, ssoProfile)
+	}
+
+	_, err = getSSOSessionProfile(sharedConfig, "missing-session")
+	require.Equal(t, ErrInvalidSSOProfile, errors.GetKind(err))
+
+	_, err = getSSOSessionProfile(sharedConfig, "incomplete")
+	require.Equal(t, ErrInvalidSSOProfile, errors.GetKind(err))
+	require.Contains(t, errors.Message(err), "sso_role_name")
+}
+
+func TestResolvedProfileName(t *testing.T) {
+	t.Setenv("AWS_PROFILE", "")
+	t.Setenv("AWS_DEFAULT_PROFILE", "")
+	require.Equal(t, "default", resolvedProfileName(""))
+	require.Equal(t, "dev", resolvedProfileName("dev"))
+
+	t.Setenv("AWS_PROFILE", "prod")
+	require.Equal(t, "prod", resolvedProfileName(""))
+	require.Equal(t, "dev", resolvedProfileName("dev"))
+}
+
+func TestCredentialsSource(t *testing.T) {
+	sess, err := session.NewSession(&aws.Config{
+		Region:      aws.String("us-west-2"),
+		Credentials: credentials.NewStaticCredentials("AKIAEXAMPLE", "secret", ""),
+	})
+	require.NoError(t, err)
+	client, err := NewForSession(sess)
+	require.NoError(t, err)
+
+	require.Equal(t, "static credentials", client.CredentialsSource())
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+)
+
+type RetryOptions struct {
+	MaxRetries        int
+	BaseDelay         time.Duration // base delay for retryable errors (e.g. 5xx responses)
+	ThrottleBaseDelay time.Duration // base delay for throttling errors (e.g. RequestLimitExceeded), which should back off more aggressively
+	MaxDelay          time.Duration
+}
+
+// DefaultRetryOptions are applied to all clients created after they are modified
+var DefaultRetryOptions = RetryOptions{
+	MaxRetries:        10,
+	BaseDelay:         100 * time.Millisecond,
+	ThrottleBaseDelay: 500 * time.Millisecond,
+	MaxDelay:          20 * time.Second,
+}
+
+type ThrottleEvent struct {
+	Service   string        `json:"service"`
+	Operation string        `json:"operation"`
+	ErrorCode string        `json:"error_code"`
+	Attempt   int           `json:"attempt"`
+	Delay     time.Duration `json:"delay"`
+}
+
+// OnThrottle is called whenever a request is retried due to throttling; by default, a warning is printed to stderr
+var OnThrottle = func(event ThrottleEvent) {
+	fmt.Fprintf(os.Stderr, "warning: aws request throttled (service=%s operation=%s code=%s attempt=%d retry_in=%s)\n",
+		event.Service, event.Operation, event.ErrorCode, event.Attempt, event.Delay.Round(time.Millisecond))
+}
+
+var (
+	_random     = rand.New(rand.NewSource(time.Now().UnixNano()))
+	_randomLock sync.Mutex
+)
+
+type retryer struct {
+	client.DefaultRetryer
+	options RetryOptions
+}
+
+func newRetryer(options RetryOptions) request.Retryer {
+	return retryer{
+		DefaultRetryer: client.DefaultRetryer{NumMaxRetries: options.MaxRetries},
+		options:        options,
+	}
+}
+
+// withRetryer returns a copy of the session which retries throttled and retryable requests using DefaultRetryOptions
+func withRetryer(sess *session.Session) *session.Session {
+	return sess.Copy(request.WithRetryer(aws.NewConfig(), newRetryer(DefaultRetryOptions)))
+}
+
+func (r retryer) RetryRules(req *request.Request) time.Duration {
+	_randomLock.Lock()
+	random := _random.Float64()
+	_randomLock.Unlock()
+
+	if !req.IsErrorThrottle() {
+		return backoffDelay(r.options.BaseDelay, r.options.MaxDelay, req.RetryCount, random)
+	}
+
+	delay := backoffDelay(r.options.ThrottleBaseDelay, r.options.MaxDelay, req.RetryCount, random)
+
+	if OnThrottle != nil {
+		event := ThrottleEvent{
+			Attempt: req.RetryCount + 1,
+			Delay:   delay,
+		}
+		if req.ClientInfo.ServiceName != "" {
+			event.Service = req.ClientInfo.ServiceName
+		}
+		if req.Operation != nil {
+			event.Operation = req.Operation.Name
+		}
+		if awsErr, ok := req.Error.(awserr.Error); ok {
+			event.ErrorCode = awsErr.Code()
+		}
+		OnThrottle(event)
+	}
+
+	return delay
+}
+
+// backoffDelay returns an exponential backoff delay with "equal jitter" (i.e. between half and all of the capped exponential delay);
+// random must be in [0, 1)
+func backoffDelay(baseDelay time.Duration, maxDelay time.Duration, retryCount int, random float64) time.Duration {
+	delay := maxDelay
+	// avoid overflowing when shifting
+	if retryCount < 32 && baseDelay<<uint(retryCount) < maxDelay && baseDelay<<uint(retryCount) > 0 {
+		delay = baseDelay << uint(retryCount)
+	}
+
+	halfDelay := delay / 2
+	return halfDelay + time.Duration(random*float64(delay-halfDelay))
+}
@@ -0,0 +1,46 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackoffDelay(t *testing.T) {
+	base := 100 * time.Millisecond
+	max := 20 * time.Second
+
+	require.Equal(t, 50*time.Millisecond, backoffDelay(base, max, 0, 0))
+	require.Equal(t, 75*time.Millisecond, backoffDelay(base, max, 0, 0.5))
+	require.Equal(t, 400*time.Millisecond, backoffDelay(base, max, 3, 0))
+	require.Equal(t, 600*time.Millisecond, backoffDelay(base, max, 3, 0.5))
+
+	// capped at the max delay
+	require.Equal(t, 10*time.Second, backoffDelay(base, max, 10, 0))
+	require.Equal(t, 15*time.Second, backoffDelay(base, max, 10, 0.5))
+	require.Equal(t, 10*time.Second, backoffDelay(base, max, 100, 0))
+
+	// never exceeds the max delay
+	for retryCount := 0; retryCount < 70; retryCount++ {
+		delay := backoffDelay(base, max, retryCount, 0.999)
+		require.True(t, delay <= max)
+		require.True(t, delay >= max/2 || retryCount < 8)
+	}
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/sets/strset"
+)
+
+// GetHostedZone returns the hosted zone with the specified id (with or without the "/hostedzone/" prefix), or nil if there is no such hosted zone
+func (c *Client) GetHostedZone(hostedZoneID string) (*route53.HostedZone, error) {
+	output, err := c.Route53().GetHostedZone(&route53.GetHostedZoneInput{
+		Id: aws.String(hostedZoneID),
+	})
+	if err != nil {
+		if IsErrCode(err, route53.ErrCodeNoSuchHostedZone) {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, hostedZoneID)
+	}
+
+	return output.HostedZone, nil
+}
+
+// FindHostedZoneForDomain returns the most specific public hosted zone which the domain belongs to out of the hosted zones with the specified ids, or nil if there is no such hosted zone
+func (c *Client) FindHostedZoneForDomain(domain string, hostedZoneIDs []string) (*route53.HostedZone, error) {
+	domain = strings.TrimSuffix(strings.ToLower(domain), ".")
+
+	allowedHostedZoneIDs := strset.New()
+	for _, hostedZoneID := range hostedZoneIDs {
+		allowedHostedZoneIDs.Add(strings.TrimPrefix(hostedZoneID, "/hostedzone/"))
+	}
+
+	var hostedZone *route53.HostedZone
+	err := c.Route53().ListHostedZonesPages(&route53.ListHostedZonesInput{},
+		func(page *route53.ListHostedZonesOutput, lastPage bool) bool {
+			for _, zone := range page.HostedZones {
+				if zone.Name == nil || zone.Id == nil || (zone.Config != nil && zone.Config.PrivateZone != nil && *zone.Config.PrivateZone) {
+					continue
+				}
+				if !allowedHostedZoneIDs.Has(strings.TrimPrefix(*zone.Id, "/hostedzone/")) {
+					continue
+				}
+
+				zoneName := strings.TrimSuffix(strings.ToLower(*zone.Name), ".")
+				if domain != zoneName && !strings.HasSuffix(domain, "."+zoneName) {
+					continue
+				}
+
+				if hostedZone == nil || len(zoneName) > len(strings.TrimSuffix(*hostedZone.Name, ".")) {
+					hostedZone = zone
+				}
+			}
+			return true
+		})
+	if err != nil {
+		return nil, errors.Wrap(err, domain)
+	}
+
+	return hostedZone, nil
+}
+
+// CreateRecordSets creates the record sets in the hosted zone (the whole change fails if any of them already exist)
+func (c *Client) CreateRecordSets(hostedZoneID string, recordSets ...*route53.ResourceRecordSet) error {
+	changes := make([]*route53.Change, len(recordSets))
+	for i := range recordSets {
+		changes[i] = &route53.Change{
+			Action:            aws.String(route53.ChangeActionCreate),
+			ResourceRecordSet: recordSets[i],
+		}
+	}
+
+	_, err := c.Route53().ChangeResourceRecordSets(&route53.ChangeResourceRecordSetsInput{
+		HostedZoneId: aws.String(hostedZoneID),
+		ChangeBatch: &route53.ChangeBatch{
+			Changes: changes,
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, hostedZoneID)
+	}
+
+	return nil
+}
+
+// GetRecordSet returns the hosted zone's record set with the specified name and type, or nil if there is no such record set
+func (c *Client) GetRecordSet(hostedZoneID string, name string, recordType string) (*route53.ResourceRecordSet, error) {
+	name = strings.TrimSuffix(strings.ToLower(name), ".") + "."
+
+	output, err := c.Route53().ListResourceRecordSets(&route53.ListResourceRecordSetsInput{
+		HostedZoneId:    aws.String(hostedZoneID),
+		StartRecordName: aws.String(name),
+		StartRecordType: aws.String(recordType),
+		MaxItems:        aws.String("1"),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, hostedZoneID, name)
+	}
+
+	for _, recordSet := range output.ResourceRecordSets {
+		if recordSet.Name != nil && strings.ToLower(*recordSet.Name) == name && recordSet.Type != nil && *recordSet.Type == recordType {
+			return recordSet, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// DeleteRecordSet deletes the hosted zone's record set with the specified name and type (no error is returned if it doesn't exist)
+func (c *Client) DeleteRecordSet(hostedZoneID string, name string, recordType string) error {
+	recordSet, err := c.GetRecordSet(hostedZoneID, name, recordType)
+	if err != nil {
+		return err
+	}
+	if recordSet == nil {
+		return nil
+	}
+
+	_, err = c.Route53().ChangeResourceRecordSets(&route53.ChangeResourceRecordSetsInput{
+		HostedZoneId: aws.String(hostedZoneID),
+		ChangeBatch: &route53.ChangeBatch{
+			Changes: []*route53.Change{
+				{
+					Action:            aws.String(route53.ChangeActionDelete),
+					ResourceRecordSet: recordSet,
+				},
+			},
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, hostedZoneID, name)
+	}
+
+	return nil
+}
@@ -729,6 +729,36 @@ func (c *Client) ListS3DirOneLevel(bucket string, s3Dir string, maxResults *int6
 	return allNames.SliceSorted(), nil
 }
 
+// Returns the names of the "directories" directly within s3Dir (files in s3Dir are not included)
+// Only one request is made per 1000 directories, regardless of the number of files within them
+func (c *Client) ListS3SubDirs(bucket string, s3Dir string) ([]string, error) {
+	s3Dir = s.EnsureSuffix(s3Dir, "/")
+
+	allNames := strset.New()
+
+	err := c.S3().ListObjectsV2Pages(
+		&s3.ListObjectsV2Input{
+			Bucket:    aws.String(bucket),
+			Prefix:    aws.String(s3Dir),
+			Delimiter: aws.String("/"),
+		},
+		func(listObjectsOutput *s3.ListObjectsV2Output, lastPage bool) bool {
+			for _, commonPrefix := range listObjectsOutput.CommonPrefixes {
+				name := strings.TrimSuffix(strings.TrimPrefix(*commonPrefix.Prefix, s3Dir), "/")
+				if name != "" {
+					allNames.Add(name)
+				}
+			}
+			return true
+		})
+
+	if err != nil {
+		return nil, errors.Wrap(err, S3Path(bucket, s3Dir))
+	}
+
+	return allNames.SliceSorted(), nil
+}
+
 func (c *Client) ListS3Prefix(bucket string, prefix string, includeDirObjects bool, maxResults *int64, startAfter *string) ([]*s3.Object, error) {
 	var allObjects []*s3.Object
 
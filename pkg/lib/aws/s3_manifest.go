@@ -0,0 +1,211 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/csv"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	libjson "github.com/cortexlabs/cortex/pkg/lib/json"
+)
+
+const _s3InventoryManifestFileName = "manifest.json"
+
+// the manifest.json file of an S3 inventory report
+// https://docs.aws.amazon.com/AmazonS3/latest/userguide/storage-inventory-location.html
+type s3InventoryManifest struct {
+	SourceBucket      string `json:"sourceBucket"`
+	DestinationBucket string `json:"destinationBucket"` // e.g. arn:aws:s3:::bucket-name
+	FileFormat        string `json:"fileFormat"`
+	FileSchema        string `json:"fileSchema"` // e.g. "Bucket, Key, Size, LastModifiedDate"
+	Files             []struct {
+		Key string `json:"key"`
+	} `json:"files"`
+}
+
+// IsS3InventoryManifest returns whether the S3 path points to the manifest.json file of an S3 inventory report
+func IsS3InventoryManifest(s3Path string) bool {
+	return strings.HasSuffix(s3Path, "/"+_s3InventoryManifestFileName)
+}
+
+// S3ManifestIterator calls fn with the bucket and key of each object listed in the manifest, which is either the manifest.json file
+// of a CSV S3 inventory report, or a text file with one S3 path or key per line (keys are relative to the manifest's bucket)
+func (c *Client) S3ManifestIterator(manifestS3Path string, fn func(bucket string, key string) (bool, error)) error {
+	bucket, key, err := SplitS3Path(manifestS3Path)
+	if err != nil {
+		return err
+	}
+
+	if !IsS3InventoryManifest(manifestS3Path) {
+		reader, err := c.ReadReaderFromS3(bucket, key)
+		if err != nil {
+			return err
+		}
+		defer reader.Close()
+
+		_, err = iterateS3ManifestLines(reader, bucket, fn)
+		if err != nil {
+			return errors.Wrap(err, manifestS3Path)
+		}
+		return nil
+	}
+
+	manifestBytes, err := c.ReadBytesFromS3(bucket, key)
+	if err != nil {
+		return err
+	}
+
+	manifest, bucketColumn, keyColumn, err := parseS3InventoryManifest(manifestBytes)
+	if err != nil {
+		return ErrorInvalidS3Manifest(manifestS3Path, errors.Message(err))
+	}
+
+	destinationBucket := strings.TrimPrefix(manifest.DestinationBucket, "arn:aws:s3:::")
+	if destinationBucket == "" {
+		destinationBucket = bucket
+	}
+
+	for _, file := range manifest.Files {
+		shouldContinue, err := c.iterateS3InventoryFile(destinationBucket, file.Key, bucketColumn, keyColumn, fn)
+		if err != nil {
+			return errors.Wrap(err, S3Path(destinationBucket, file.Key))
+		}
+		if !shouldContinue {
+			break
+		}
+	}
+
+	return nil
+}
+
+func (c *Client) iterateS3InventoryFile(bucket string, key string, bucketColumn int, keyColumn int, fn func(bucket string, key string) (bool, error)) (bool, error) {
+	reader, err := c.ReadReaderFromS3(bucket, key)
+	if err != nil {
+		return false, err
+	}
+	defer reader.Close()
+
+	var csvReader io.Reader = reader
+	if strings.HasSuffix(key, ".gz") {
+		gzipReader, err := gzip.NewReader(reader)
+		if err != nil {
+			return false, errors.WithStack(err)
+		}
+		defer gzipReader.Close()
+		csvReader = gzipReader
+	}
+
+	return iterateS3InventoryCSV(csvReader, bucketColumn, keyColumn, fn)
+}
+
+// returns the parsed manifest, and the indices of the bucket and key columns in the inventory's CSV files
+func parseS3InventoryManifest(manifestBytes []byte) (*s3InventoryManifest, int, int, error) {
+	var manifest s3InventoryManifest
+	if err := libjson.Unmarshal(manifestBytes, &manifest); err != nil {
+		return nil, 0, 0, err
+	}
+
+	if !strings.EqualFold(manifest.FileFormat, "CSV") {
+		return nil, 0, 0, errors.ErrorUnexpected("only CSV inventory reports are supported (got " + manifest.FileFormat + ")")
+	}
+
+	bucketColumn, keyColumn := -1, -1
+	for i, column := range strings.Split(manifest.FileSchema, ",") {
+		switch strings.TrimSpace(column) {
+		case "Bucket":
+			bucketColumn = i
+		case "Key":
+			keyColumn = i
+		}
+	}
+	if bucketColumn == -1 || keyColumn == -1 {
+		return nil, 0, 0, errors.ErrorUnexpected("the inventory's file schema must include the Bucket and Key fields")
+	}
+
+	return &manifest, bucketColumn, keyColumn, nil
+}
+
+// S3 inventory reports URL-encode object keys
+func iterateS3InventoryCSV(reader io.Reader, bucketColumn int, keyColumn int, fn func(bucket string, key string) (bool, error)) (bool, error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.FieldsPerRecord = -1
+
+	for {
+		record, err := csvReader.Read()
+		if err == io.EOF {
+			return true, nil
+		}
+		if err != nil {
+			return false, errors.WithStack(err)
+		}
+
+		if bucketColumn >= len(record) || keyColumn >= len(record) {
+			continue
+		}
+
+		key, err := url.QueryUnescape(record[keyColumn])
+		if err != nil {
+			return false, errors.WithStack(err)
+		}
+		if key == "" || strings.HasSuffix(key, "/") {
+			continue // skip directory placeholder objects
+		}
+
+		shouldContinue, err := fn(record[bucketColumn], key)
+		if err != nil || !shouldContinue {
+			return false, err
+		}
+	}
+}
+
+// each line is either a full S3 path (s3://bucket/key) or a key in defaultBucket; empty lines and lines starting with # are ignored
+func iterateS3ManifestLines(reader io.Reader, defaultBucket string, fn func(bucket string, key string) (bool, error)) (bool, error) {
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		bucket, key := defaultBucket, strings.TrimPrefix(line, "/")
+		if strings.HasPrefix(line, "s3://") {
+			var err error
+			bucket, key, err = SplitS3Path(line)
+			if err != nil {
+				return false, err
+			}
+		}
+
+		shouldContinue, err := fn(bucket, key)
+		if err != nil || !shouldContinue {
+			return false, err
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return false, errors.WithStack(err)
+	}
+
+	return true, nil
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type manifestEntry struct {
+	bucket string
+	key    string
+}
+
+func collectManifestEntries(entries *[]manifestEntry) func(bucket string, key string) (bool, error) {
+	return func(bucket string, key string) (bool, error) {
+		*entries = append(*entries, manifestEntry{bucket, key})
+		return true, nil
+	}
+}
+
+func TestIterateS3ManifestLines(t *testing.T) {
+	manifest := `
+# images to process
+images/1.jpg
+s3://other-bucket/images/2.jpg
+
+/images/3.jpg
+`
+	var entries []manifestEntry
+	shouldContinue, err := iterateS3ManifestLines(strings.NewReader(manifest), "my-bucket", collectManifestEntries(&entries))
+	require.NoError(t, err)
+	require.True(t, shouldContinue)
+	require.Equal(t, []manifestEntry{
+		{"my-bucket", "images/1.jpg"},
+		{"other-bucket", "images/2.jpg"},
+		{"my-bucket", "images/3.jpg"},
+	}, entries)
+
+	_, err = iterateS3ManifestLines(strings.NewReader("s3://"), "my-bucket", collectManifestEntries(&entries))
+	require.Error(t, err)
+}
+
+func TestParseS3InventoryManifest(t *testing.T) {
+	manifestJSON := `{
+		"sourceBucket": "my-bucket",
+		"destinationBucket": "arn:aws:s3:::inventory-bucket",
+		"fileFormat": "CSV",
+		"fileSchema": "Bucket, Key, Size, LastModifiedDate",
+		"files": [{"key": "inventory/data/1.csv.gz"}, {"key": "inventory/data/2.csv.gz"}]
+	}`
+
+	manifest, bucketColumn, keyColumn, err := parseS3InventoryManifest([]byte(manifestJSON))
+	require.NoError(t, err)
+	require.Equal(t, 0, bucketColumn)
+	require.Equal(t, 1, keyColumn)
+	require.Len(t, manifest.Files, 2)
+	require.Equal(t, "inventory/data/2.csv.gz", manifest.Files[1].Key)
+
+	_, _, _, err = parseS3InventoryManifest([]byte(`{"fileFormat": "Parquet", "fileSchema": "Bucket, Key"}`))
+	require.Error(t, err)
+
+	_, _, _, err = parseS3InventoryManifest([]byte(`{"fileFormat": "CSV", "fileSchema": "Bucket, Size"}`))
+	require.Error(t, err)
+
+	require.True(t, IsS3InventoryManifest("s3://inventory-bucket/my-bucket/config/2022-01-01T00-00Z/manifest.json"))
+	require.False(t, IsS3InventoryManifest("s3://my-bucket/manifest.txt"))
+}
+
+func TestIterateS3InventoryCSV(t *testing.T) {
+	inventoryCSV := `"my-bucket","images/1.jpg","100"
+"my-bucket","images/","0"
+"my-bucket","images/my+image%282%29.jpg","200"
+`
+	var entries []manifestEntry
+	shouldContinue, err := iterateS3InventoryCSV(strings.NewReader(inventoryCSV), 0, 1, collectManifestEntries(&entries))
+	require.NoError(t, err)
+	require.True(t, shouldContinue)
+	require.Equal(t, []manifestEntry{
+		{"my-bucket", "images/1.jpg"},
+		{"my-bucket", "images/my image(2).jpg"},
+	}, entries)
+}
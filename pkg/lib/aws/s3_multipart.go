@@ -0,0 +1,268 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/files"
+)
+
+const (
+	MinMultipartUploadPartSize int64 = 5 * 1024 * 1024 // the minimum size of each part (except for the last one), enforced by S3
+	maxMultipartUploadParts          = 10000           // enforced by S3
+)
+
+type MultipartUploadOptions struct {
+	PartSize    int64                // size of each part in bytes (default: 8MiB, minimum: 5MiB)
+	Concurrency int                  // number of parts which are uploaded in parallel (default: 4)
+	PartRetries int                  // number of times a failed part is re-uploaded before the upload is aborted (default: 3)
+	OnProgress  func(UploadProgress) // called after each part has been uploaded (optional)
+}
+
+// DefaultMultipartUploadOptions are used for any zero-valued fields of the options passed to the multipart upload functions
+var DefaultMultipartUploadOptions = MultipartUploadOptions{
+	PartSize:    8 * 1024 * 1024,
+	Concurrency: 4,
+	PartRetries: 3,
+}
+
+type UploadProgress struct {
+	UploadedBytes int64
+	TotalBytes    int64 // -1 if the size of the upload is not known in advance
+	UploadedParts int
+}
+
+func (opts MultipartUploadOptions) withDefaults() MultipartUploadOptions {
+	if opts.PartSize <= 0 {
+		opts.PartSize = DefaultMultipartUploadOptions.PartSize
+	}
+	if opts.PartSize < MinMultipartUploadPartSize {
+		opts.PartSize = MinMultipartUploadPartSize
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = DefaultMultipartUploadOptions.Concurrency
+	}
+	if opts.PartRetries <= 0 {
+		opts.PartRetries = DefaultMultipartUploadOptions.PartRetries
+	}
+	return opts
+}
+
+// multipartUploadPartSize increases the part size if necessary, so that an upload of totalBytes fits within S3's part limit
+func multipartUploadPartSize(partSize int64, totalBytes int64) int64 {
+	if totalBytes <= 0 {
+		return partSize
+	}
+	minPartSize := (totalBytes + maxMultipartUploadParts - 1) / maxMultipartUploadParts
+	if minPartSize > partSize {
+		return minPartSize
+	}
+	return partSize
+}
+
+func (c *Client) MultipartUploadFileToS3(path string, bucket string, key string, opts MultipartUploadOptions) error {
+	file, err := files.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	fileInfo, err := file.Stat()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	return c.MultipartUploadReaderToS3(file, fileInfo.Size(), bucket, key, opts)
+}
+
+// MultipartUploadReaderToS3 streams data to S3 in parts, retrying each part individually (totalBytes should be -1 if the size is unknown);
+// roughly opts.Concurrency parts are buffered in memory at a time, and the upload is aborted if a part can't be uploaded
+func (c *Client) MultipartUploadReaderToS3(data io.Reader, totalBytes int64, bucket string, key string, opts MultipartUploadOptions) error {
+	opts = opts.withDefaults()
+	partSize := multipartUploadPartSize(opts.PartSize, totalBytes)
+
+	firstPart, err := readUploadPart(data, partSize)
+	if err != nil {
+		return errors.Wrap(err, S3Path(bucket, key))
+	}
+
+	// a single part doesn't benefit from a multipart upload
+	if int64(len(firstPart)) < partSize {
+		if err := c.UploadBytesToS3(firstPart, bucket, key); err != nil {
+			return err
+		}
+		reportUploadProgress(opts.OnProgress, int64(len(firstPart)), totalBytes, 1)
+		return nil
+	}
+
+	createOutput, err := c.S3().CreateMultipartUpload(&s3.CreateMultipartUploadInput{
+		Bucket:             aws.String(bucket),
+		Key:                aws.String(key),
+		ACL:                aws.String("private"),
+		ContentDisposition: aws.String("attachment"),
+	})
+	if err != nil {
+		return errors.Wrap(err, S3Path(bucket, key))
+	}
+	uploadID := createOutput.UploadId
+
+	completedParts, err := c.uploadParts(data, firstPart, partSize, totalBytes, bucket, key, uploadID, opts)
+	if err != nil {
+		// best-effort cleanup, so that the uploaded parts don't continue to incur storage costs
+		c.S3().AbortMultipartUpload(&s3.AbortMultipartUploadInput{
+			Bucket:   aws.String(bucket),
+			Key:      aws.String(key),
+			UploadId: uploadID,
+		})
+		return errors.Wrap(err, S3Path(bucket, key))
+	}
+
+	_, err = c.S3().CompleteMultipartUpload(&s3.CompleteMultipartUploadInput{
+		Bucket:   aws.String(bucket),
+		Key:      aws.String(key),
+		UploadId: uploadID,
+		MultipartUpload: &s3.CompletedMultipartUpload{
+			Parts: completedParts,
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, S3Path(bucket, key))
+	}
+
+	return nil
+}
+
+func (c *Client) uploadParts(data io.Reader, firstPart []byte, partSize int64, totalBytes int64, bucket string, key string, uploadID *string, opts MultipartUploadOptions) ([]*s3.CompletedPart, error) {
+	var (
+		wg             sync.WaitGroup
+		lock           sync.Mutex
+		completedParts []*s3.CompletedPart
+		firstErr       error
+		uploadedBytes  int64
+		failed         int32
+	)
+
+	semaphore := make(chan struct{}, opts.Concurrency)
+
+	part := firstPart
+	for partNumber := int64(1); len(part) > 0; partNumber++ {
+		if atomic.LoadInt32(&failed) != 0 {
+			break
+		}
+
+		semaphore <- struct{}{}
+		wg.Add(1)
+		go func(partNumber int64, part []byte) {
+			defer func() {
+				<-semaphore
+				wg.Done()
+			}()
+
+			etag, err := c.uploadPartWithRetries(part, partNumber, bucket, key, uploadID, opts.PartRetries)
+
+			lock.Lock()
+			defer lock.Unlock()
+
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				atomic.StoreInt32(&failed, 1)
+				return
+			}
+
+			completedParts = append(completedParts, &s3.CompletedPart{
+				ETag:       etag,
+				PartNumber: aws.Int64(partNumber),
+			})
+			uploadedBytes += int64(len(part))
+			reportUploadProgress(opts.OnProgress, uploadedBytes, totalBytes, len(completedParts))
+		}(partNumber, part)
+
+		var err error
+		part, err = readUploadPart(data, partSize)
+		if err != nil {
+			lock.Lock()
+			if firstErr == nil {
+				firstErr = err
+			}
+			lock.Unlock()
+			break
+		}
+	}
+
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	sort.Slice(completedParts, func(i, j int) bool {
+		return *completedParts[i].PartNumber < *completedParts[j].PartNumber
+	})
+
+	return completedParts, nil
+}
+
+func (c *Client) uploadPartWithRetries(part []byte, partNumber int64, bucket string, key string, uploadID *string, retries int) (*string, error) {
+	var err error
+	for attempt := 0; attempt <= retries; attempt++ {
+		var output *s3.UploadPartOutput
+		output, err = c.S3().UploadPart(&s3.UploadPartInput{
+			Bucket:     aws.String(bucket),
+			Key:        aws.String(key),
+			UploadId:   uploadID,
+			PartNumber: aws.Int64(partNumber),
+			Body:       bytes.NewReader(part),
+		})
+		if err == nil {
+			return output.ETag, nil
+		}
+	}
+
+	return nil, errors.Wrap(err, fmt.Sprintf("part %d", partNumber))
+}
+
+// reads up to partSize bytes; returns an empty slice once the reader has been exhausted
+func readUploadPart(data io.Reader, partSize int64) ([]byte, error) {
+	part := make([]byte, partSize)
+	n, err := io.ReadFull(data, part)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return nil, errors.WithStack(err)
+	}
+	return part[:n], nil
+}
+
+func reportUploadProgress(onProgress func(UploadProgress), uploadedBytes int64, totalBytes int64, uploadedParts int) {
+	if onProgress == nil {
+		return
+	}
+	onProgress(UploadProgress{
+		UploadedBytes: uploadedBytes,
+		TotalBytes:    totalBytes,
+		UploadedParts: uploadedParts,
+	})
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultipartUploadPartSize(t *testing.T) {
+	partSize := DefaultMultipartUploadOptions.PartSize
+
+	require.Equal(t, partSize, multipartUploadPartSize(partSize, -1))
+	require.Equal(t, partSize, multipartUploadPartSize(partSize, 100*1024*1024))
+
+	// 1TiB does not fit in 10000 8MiB parts
+	totalBytes := int64(1024 * 1024 * 1024 * 1024)
+	adjusted := multipartUploadPartSize(partSize, totalBytes)
+	require.Greater(t, adjusted, partSize)
+	require.LessOrEqual(t, (totalBytes+adjusted-1)/adjusted, int64(maxMultipartUploadParts))
+}
+
+func TestMultipartUploadOptionsWithDefaults(t *testing.T) {
+	opts := MultipartUploadOptions{}.withDefaults()
+	require.Equal(t, DefaultMultipartUploadOptions.PartSize, opts.PartSize)
+	require.Equal(t, DefaultMultipartUploadOptions.Concurrency, opts.Concurrency)
+	require.Equal(t, DefaultMultipartUploadOptions.PartRetries, opts.PartRetries)
+
+	opts = MultipartUploadOptions{PartSize: 1024, Concurrency: 16}.withDefaults()
+	require.Equal(t, MinMultipartUploadPartSize, opts.PartSize)
+	require.Equal(t, 16, opts.Concurrency)
+}
+
+func TestReadUploadPart(t *testing.T) {
+	reader := strings.NewReader("abcdefg")
+
+	part, err := readUploadPart(reader, 3)
+	require.NoError(t, err)
+	require.Equal(t, "abc", string(part))
+
+	part, err = readUploadPart(reader, 3)
+	require.NoError(t, err)
+	require.Equal(t, "def", string(part))
+
+	part, err = readUploadPart(reader, 3)
+	require.NoError(t, err)
+	require.Equal(t, "g", string(part))
+
+	part, err = readUploadPart(reader, 3)
+	require.NoError(t, err)
+	require.Empty(t, part)
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+)
+
+// GetSecretString returns the string value of a Secrets Manager secret (secretID can be the secret's name or ARN)
+func (c *Client) GetSecretString(secretID string) (string, error) {
+	result, err := c.SecretsManager().GetSecretValue(&secretsmanager.GetSecretValueInput{
+		SecretId: aws.String(secretID),
+	})
+	if err != nil {
+		return "", errors.Wrap(err, secretID)
+	}
+
+	if result.SecretString != nil {
+		return *result.SecretString, nil
+	}
+
+	// binary secrets are returned base64-decoded by the sdk
+	return string(result.SecretBinary), nil
+}
+
+// PutSecretString stores value as a new version of the secret, creating the secret (with tags) if it doesn't exist
+func (c *Client) PutSecretString(name string, value string, tags map[string]string) error {
+	_, err := c.SecretsManager().PutSecretValue(&secretsmanager.PutSecretValueInput{
+		SecretId:     aws.String(name),
+		SecretString: aws.String(value),
+	})
+	if err == nil {
+		return nil
+	}
+	if !IsSecretNotFoundErr(err) {
+		return errors.Wrap(err, name)
+	}
+
+	var secretTags []*secretsmanager.Tag
+	for key, tagValue := range tags {
+		secretTags = append(secretTags, &secretsmanager.Tag{
+			Key:   aws.String(key),
+			Value: aws.String(tagValue),
+		})
+	}
+
+	_, err = c.SecretsManager().CreateSecret(&secretsmanager.CreateSecretInput{
+		Name:         aws.String(name),
+		SecretString: aws.String(value),
+		Tags:         secretTags,
+	})
+	if err != nil {
+		return errors.Wrap(err, name)
+	}
+	return nil
+}
+
+// DeleteSecret deletes the secret without a recovery window (so that its name can be reused immediately); returns false if the secret doesn't exist
+func (c *Client) DeleteSecret(name string) (bool, error) {
+	_, err := c.SecretsManager().DeleteSecret(&secretsmanager.DeleteSecretInput{
+		SecretId:                   aws.String(name),
+		ForceDeleteWithoutRecovery: aws.Bool(true),
+	})
+	if err != nil {
+		if IsSecretNotFoundErr(err) {
+			return false, nil
+		}
+		return false, errors.Wrap(err, name)
+	}
+	return true, nil
+}
+
+// ListSecretNamesWithPrefix returns the names of the secrets whose names start with prefix
+func (c *Client) ListSecretNamesWithPrefix(prefix string) ([]string, error) {
+	var names []string
+
+	err := c.SecretsManager().ListSecretsPages(&secretsmanager.ListSecretsInput{
+		Filters: []*secretsmanager.Filter{
+			{
+				Key:    aws.String(secretsmanager.FilterNameStringTypeName),
+				Values: aws.StringSlice([]string{prefix}),
+			},
+		},
+	}, func(output *secretsmanager.ListSecretsOutput, lastPage bool) bool {
+		for _, secret := range output.SecretList {
+			// the name filter matches prefixes of any word in the name, so the prefix is checked again
+			if name := aws.StringValue(secret.Name); strings.HasPrefix(name, prefix) {
+				names = append(names, name)
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return names, nil
+}
@@ -20,6 +20,7 @@ import (
 	"strings"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/servicequotas"
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
 	"github.com/cortexlabs/cortex/pkg/lib/pointer"
@@ -53,33 +54,55 @@ type instanceClassRequest struct {
 
 	OnDemandCPUQuota  *int64
 	OnDemandQuotaCode string
+	OnDemandQuotaName string
 
 	SpotCPUQuota  *int64
 	SpotQuotaCode string
+	SpotQuotaName string
 }
 
-func (c *Client) VerifyInstanceQuota(instances []InstanceTypeRequests) error {
+type InstanceQuotaDeficit struct {
+	InstanceTypes []string
+	Lifecycle     string // "on-demand" or "spot"
+	QuotaName     string
+	QuotaCode     string
+	QuotaVCPUs    int64
+	InUseVCPUs    int64
+	RequiredVCPUs int64
+}
+
+// returns the vCPU quota class of the instance type (e.g. "standard" or "g"), and false if the instance type's family isn't recognized
+func instanceClass(instanceType string) (string, bool) {
+	parsedType, err := ParseInstanceType(instanceType)
+	if err != nil {
+		return "", false
+	}
+
+	if !_knownInstanceFamilies.Has(parsedType.Family) {
+		return "", false
+	}
+
+	if _standardInstanceFamilies.Has(parsedType.Family) {
+		return "standard", true
+	}
+	return parsedType.Family, true
+}
+
+// Checks the account's on-demand and spot vCPU quotas (per instance class) against the requested instances, taking the vCPUs of instances which are already running into account.
+// Running instances which have all of ignoredInstanceTags (e.g. the instances of a cluster which is being reconfigured) are not counted towards the current usage
+func (c *Client) VerifyInstanceQuota(instances []InstanceTypeRequests, ignoredInstanceTags map[string]string) error {
 	instanceClassRequests := []instanceClassRequest{}
 	for _, instance := range instances {
 		if instance.RequiredOnDemandInstances == 0 && instance.RequiredSpotInstances == 0 {
 			continue
 		}
 
-		parsedType, err := ParseInstanceType(instance.InstanceType)
-		if err != nil {
-			continue
-		}
-
 		// Allow the instance if we don't recognize the type
-		if !_knownInstanceFamilies.Has(parsedType.Family) {
+		instanceClass, ok := instanceClass(instance.InstanceType)
+		if !ok {
 			continue
 		}
 
-		instanceClass := parsedType.Family
-		if _standardInstanceFamilies.Has(parsedType.Family) {
-			instanceClass = "standard"
-		}
-
 		cpusPerInstance := InstanceMetadatas[c.Region][instance.InstanceType].CPU
 
 		instanceClassFound := false
@@ -104,6 +127,10 @@ func (c *Client) VerifyInstanceQuota(instances []InstanceTypeRequests) error {
 		}
 	}
 
+	if len(instanceClassRequests) == 0 {
+		return nil
+	}
+
 	err := c.ServiceQuotas().ListServiceQuotasPages(
 		&servicequotas.ListServiceQuotasInput{
 			ServiceCode: aws.String("ec2"),
@@ -127,9 +154,11 @@ func (c *Client) VerifyInstanceQuota(instances []InstanceTypeRequests) error {
 					if strings.ToLower(*metricClass) == r.InstanceClass+"/ondemand" {
 						instanceClassRequests[idx].OnDemandCPUQuota = pointer.Int64(int64(*quota.Value))
 						instanceClassRequests[idx].OnDemandQuotaCode = *quota.QuotaCode
+						instanceClassRequests[idx].OnDemandQuotaName = aws.StringValue(quota.QuotaName)
 					} else if strings.ToLower(*metricClass) == r.InstanceClass+"/spot" {
 						instanceClassRequests[idx].SpotCPUQuota = pointer.Int64(int64(*quota.Value))
 						instanceClassRequests[idx].SpotQuotaCode = *quota.QuotaCode
+						instanceClassRequests[idx].SpotQuotaName = aws.StringValue(quota.QuotaName)
 					}
 				}
 			}
@@ -140,18 +169,101 @@ func (c *Client) VerifyInstanceQuota(instances []InstanceTypeRequests) error {
 		return errors.WithStack(err)
 	}
 
+	inUseOnDemandCPUs, inUseSpotCPUs, err := c.runningInstanceVCPUsByClass(ignoredInstanceTags)
+	if err != nil {
+		return err
+	}
+
+	var deficits []InstanceQuotaDeficit
 	for _, r := range instanceClassRequests {
-		if r.OnDemandCPUQuota != nil && *r.OnDemandCPUQuota < r.RequiredOnDemandCPUs {
-			return ErrorInsufficientInstanceQuota(r.InstanceTypes.Slice(), "on-demand", c.Region, r.RequiredOnDemandCPUs, *r.OnDemandCPUQuota, r.OnDemandQuotaCode)
+		if r.OnDemandCPUQuota != nil && r.RequiredOnDemandCPUs > 0 && *r.OnDemandCPUQuota < inUseOnDemandCPUs[r.InstanceClass]+r.RequiredOnDemandCPUs {
+			deficits = append(deficits, InstanceQuotaDeficit{
+				InstanceTypes: r.InstanceTypes.SliceSorted(),
+				Lifecycle:     "on-demand",
+				QuotaName:     r.OnDemandQuotaName,
+				QuotaCode:     r.OnDemandQuotaCode,
+				QuotaVCPUs:    *r.OnDemandCPUQuota,
+				InUseVCPUs:    inUseOnDemandCPUs[r.InstanceClass],
+				RequiredVCPUs: r.RequiredOnDemandCPUs,
+			})
 		}
-		if r.SpotCPUQuota != nil && *r.SpotCPUQuota < r.RequiredSpotCPUs {
-			return ErrorInsufficientInstanceQuota(r.InstanceTypes.Slice(), "spot", c.Region, r.RequiredSpotCPUs, *r.SpotCPUQuota, r.SpotQuotaCode)
+		if r.SpotCPUQuota != nil && r.RequiredSpotCPUs > 0 && *r.SpotCPUQuota < inUseSpotCPUs[r.InstanceClass]+r.RequiredSpotCPUs {
+			deficits = append(deficits, InstanceQuotaDeficit{
+				InstanceTypes: r.InstanceTypes.SliceSorted(),
+				Lifecycle:     "spot",
+				QuotaName:     r.SpotQuotaName,
+				QuotaCode:     r.SpotQuotaCode,
+				QuotaVCPUs:    *r.SpotCPUQuota,
+				InUseVCPUs:    inUseSpotCPUs[r.InstanceClass],
+				RequiredVCPUs: r.RequiredSpotCPUs,
+			})
 		}
 	}
 
+	if len(deficits) > 0 {
+		return ErrorInsufficientInstanceQuota(deficits, c.Region)
+	}
+
 	return nil
 }
 
+// returns the vCPUs of the pending and running on-demand and spot instances in the region, by instance class
+func (c *Client) runningInstanceVCPUsByClass(ignoredInstanceTags map[string]string) (map[string]int64, map[string]int64, error) {
+	instances, err := c.ListInstances(ec2.Filter{
+		Name:   aws.String("instance-state-name"),
+		Values: aws.StringSlice([]string{ec2.InstanceStateNamePending, ec2.InstanceStateNameRunning}),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	onDemandCPUs := map[string]int64{}
+	spotCPUs := map[string]int64{}
+	for _, instance := range instances {
+		if len(ignoredInstanceTags) > 0 && hasAllTags(instance.Tags, ignoredInstanceTags) {
+			continue
+		}
+
+		instanceType := aws.StringValue(instance.InstanceType)
+		instanceClass, ok := instanceClass(instanceType)
+		if !ok {
+			continue
+		}
+
+		var cpus int64
+		if instance.CpuOptions != nil && instance.CpuOptions.CoreCount != nil && instance.CpuOptions.ThreadsPerCore != nil {
+			cpus = *instance.CpuOptions.CoreCount * *instance.CpuOptions.ThreadsPerCore
+		} else {
+			instanceCPU := InstanceMetadatas[c.Region][instanceType].CPU
+			cpus = instanceCPU.Value()
+		}
+
+		if aws.StringValue(instance.InstanceLifecycle) == ec2.InstanceLifecycleTypeSpot {
+			spotCPUs[instanceClass] += cpus
+		} else {
+			onDemandCPUs[instanceClass] += cpus
+		}
+	}
+
+	return onDemandCPUs, spotCPUs, nil
+}
+
+func hasAllTags(tags []*ec2.Tag, desiredTags map[string]string) bool {
+	for key, value := range desiredTags {
+		found := false
+		for _, tag := range tags {
+			if tag != nil && aws.StringValue(tag.Key) == key && aws.StringValue(tag.Value) == value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
 func (c *Client) ListServiceQuotas(quotaCodes []string, serviceCodes []string) (map[string]int, error) {
 	desiredQuotaCodes := strset.New(quotaCodes...)
 	quotaCodeToValueMap := map[string]int{}
@@ -0,0 +1,59 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstanceClass(t *testing.T) {
+	var testcases = []struct {
+		instanceType string
+		class        string
+		ok           bool
+	}{
+		{"t3.small", "standard", true},
+		{"m5.large", "standard", true},
+		{"g4dn.xlarge", "g", true},
+		{"inf1.24xlarge", "inf", true},
+		{"u-9tb1.metal", "", false},
+		{"badtype.large", "", false},
+	}
+
+	for _, testcase := range testcases {
+		class, ok := instanceClass(testcase.instanceType)
+		require.Equal(t, testcase.ok, ok, testcase.instanceType)
+		require.Equal(t, testcase.class, class, testcase.instanceType)
+	}
+}
+
+func TestHasAllTags(t *testing.T) {
+	tags := []*ec2.Tag{
+		{Key: aws.String("cortex.dev/cluster-name"), Value: aws.String("cortex")},
+		{Key: aws.String("team"), Value: aws.String("ml")},
+	}
+
+	require.True(t, hasAllTags(tags, map[string]string{"cortex.dev/cluster-name": "cortex"}))
+	require.True(t, hasAllTags(tags, map[string]string{"cortex.dev/cluster-name": "cortex", "team": "ml"}))
+	require.False(t, hasAllTags(tags, map[string]string{"cortex.dev/cluster-name": "other"}))
+	require.False(t, hasAllTags(tags, map[string]string{"owner": "ml"}))
+	require.False(t, hasAllTags(nil, map[string]string{"team": "ml"}))
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+)
+
+// GetParameterString returns the (decrypted) value of a Parameter Store parameter
+func (c *Client) GetParameterString(name string) (string, error) {
+	result, err := c.SSM().GetParameter(&ssm.GetParameterInput{
+		Name:           aws.String(name),
+		WithDecryption: aws.Bool(true),
+	})
+	if err != nil {
+		return "", errors.Wrap(err, name)
+	}
+	return aws.StringValue(result.Parameter.Value), nil
+}
+
+// PutSecureStringParameter stores value as a SecureString parameter (encrypted with the account's default key), overwriting the parameter if it exists
+func (c *Client) PutSecureStringParameter(name string, value string) error {
+	_, err := c.SSM().PutParameter(&ssm.PutParameterInput{
+		Name:      aws.String(name),
+		Value:     aws.String(value),
+		Type:      aws.String(ssm.ParameterTypeSecureString),
+		Overwrite: aws.Bool(true),
+	})
+	if err != nil {
+		return errors.Wrap(err, name)
+	}
+	return nil
+}
+
+// DeleteParameter returns false if the parameter doesn't exist
+func (c *Client) DeleteParameter(name string) (bool, error) {
+	_, err := c.SSM().DeleteParameter(&ssm.DeleteParameterInput{
+		Name: aws.String(name),
+	})
+	if err != nil {
+		if IsParameterNotFoundErr(err) {
+			return false, nil
+		}
+		return false, errors.Wrap(err, name)
+	}
+	return true, nil
+}
+
+// ListParameterNamesByPath returns the names of the parameters directly under path (e.g. /my/path)
+func (c *Client) ListParameterNamesByPath(path string) ([]string, error) {
+	var names []string
+
+	err := c.SSM().DescribeParametersPages(&ssm.DescribeParametersInput{
+		ParameterFilters: []*ssm.ParameterStringFilter{
+			{
+				Key:    aws.String("Path"),
+				Option: aws.String("OneLevel"),
+				Values: aws.StringSlice([]string{path}),
+			},
+		},
+	}, func(output *ssm.DescribeParametersOutput, lastPage bool) bool {
+		for _, parameter := range output.Parameters {
+			names = append(names, aws.StringValue(parameter.Name))
+		}
+		return true
+	})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return names, nil
+}
@@ -22,10 +22,15 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"regexp"
 	"strings"
+	"time"
 
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/private/protocol/query"
 	"github.com/aws/aws-sdk-go/private/protocol/xml/xmlutil"
 	"github.com/aws/aws-sdk-go/service/sts"
@@ -59,6 +64,42 @@ func (c *Client) GetCachedAccountID() (string, string, error) {
 	return *c.accountID, *c.hashedAccountID, nil
 }
 
+// AssumeRole returns a client for the given region which is authenticated as the assumed role (e.g. to access resources in another account)
+// The temporary credentials are not refreshed, so the returned client should not be retained
+func (c *Client) AssumeRole(roleARN string, sessionName string, region string) (*Client, error) {
+	output, err := c.STS().AssumeRole(&sts.AssumeRoleInput{
+		RoleArn:         aws.String(roleARN),
+		RoleSessionName: aws.String(sessionName),
+	})
+	if err != nil {
+		return nil, ErrorAssumeRole(roleARN, err)
+	}
+	if output.Credentials == nil {
+		return nil, ErrorAssumeRole(roleARN, errors.ErrorUnexpected("response is missing credentials"))
+	}
+
+	sess, err := session.NewSession(&aws.Config{
+		Region: aws.String(region),
+		Credentials: credentials.NewStaticCredentials(
+			*output.Credentials.AccessKeyId,
+			*output.Credentials.SecretAccessKey,
+			*output.Credentials.SessionToken,
+		),
+	})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return NewForSession(sess)
+}
+
+// identity requests may only be sent to STS, otherwise a client could direct them to a server which returns an arbitrary identity
+var _stsHostRegex = regexp.MustCompile(`^sts(-fips)?(\.[a-z0-9-]+)?\.amazonaws\.com(\.cn)?$`)
+
+var _signedHeadersRegex = regexp.MustCompile(`SignedHeaders=([^,\s]+)`)
+
+const _identityRequestTimeout = 10 * time.Second
+
 type awsRequest struct {
 	Header        http.Header
 	URL           string
@@ -69,7 +110,20 @@ type awsRequest struct {
 }
 
 func (c *Client) IdentityRequestAsHeader() (string, error) {
+	return c.identityRequestAsHeader(nil)
+}
+
+// IdentityRequestAsHeaderForAudience is like IdentityRequestAsHeader, but the signature also covers the audience header,
+// so that the identity request is only accepted by the intended recipient (see GetCallerIdentityFromHeaderForAudience)
+func (c *Client) IdentityRequestAsHeaderForAudience(audienceHeader string, audience string) (string, error) {
+	return c.identityRequestAsHeader(map[string]string{audienceHeader: audience})
+}
+
+func (c *Client) identityRequestAsHeader(extraHeaders map[string]string) (string, error) {
 	req, _ := c.STS().GetCallerIdentityRequest(nil)
+	for name, value := range extraHeaders {
+		req.HTTPRequest.Header.Set(name, value)
+	}
 
 	err := req.Sign()
 	if err != nil {
@@ -99,22 +153,92 @@ func (c *Client) IdentityRequestAsHeader() (string, error) {
 
 // ExecuteIdentityRequestFromHeader executes identity request marshalled from header and returns account id if successful
 func ExecuteIdentityRequestFromHeader(indentityRequestheader string) (string, error) {
+	identity, err := GetCallerIdentityFromHeader(indentityRequestheader)
+	if err != nil {
+		return "", err
+	}
+	return *identity.Account, nil
+}
+
+// GetCallerIdentityFromHeaderForAudience is like GetCallerIdentityFromHeader, but first verifies that the identity request's signature
+// covers the audience header and that it is set to the expected audience; this prevents a recipient of the header from replaying it to a different recipient
+func GetCallerIdentityFromHeaderForAudience(indentityRequestheader string, audienceHeader string, audience string) (*sts.GetCallerIdentityOutput, error) {
+	signedRequestArtifacts, err := decodeIdentityRequestHeader(indentityRequestheader)
+	if err != nil {
+		return nil, err
+	}
+
+	if value, ok := signedHeaderValue(signedRequestArtifacts, audienceHeader); !ok || value != audience {
+		return nil, ErrorIdentityRequestAudience(audienceHeader, audience)
+	}
+
+	return getCallerIdentity(signedRequestArtifacts)
+}
+
+// GetCallerIdentityFromHeader executes identity request marshalled from header and returns the caller's identity (including its ARN) if successful
+func GetCallerIdentityFromHeader(indentityRequestheader string) (*sts.GetCallerIdentityOutput, error) {
+	signedRequestArtifacts, err := decodeIdentityRequestHeader(indentityRequestheader)
+	if err != nil {
+		return nil, err
+	}
+	return getCallerIdentity(signedRequestArtifacts)
+}
+
+func decodeIdentityRequestHeader(indentityRequestheader string) (awsRequest, error) {
 	jsonObj, err := base64.RawURLEncoding.DecodeString(indentityRequestheader)
 	if err != nil {
-		return "", errors.WithStack(err)
+		return awsRequest{}, errors.WithStack(err)
 	}
 
 	signedRequestArtifacts := awsRequest{}
 	err = libjson.Unmarshal(jsonObj, &signedRequestArtifacts)
 	if err != nil {
-		return "", err
+		return awsRequest{}, err
+	}
+
+	return signedRequestArtifacts, nil
+}
+
+// signedHeaderValue returns the value of the header if it is included in the request's SigV4 signature (header names are case-insensitive)
+func signedHeaderValue(signedRequestArtifacts awsRequest, headerName string) (string, bool) {
+	var authorization, value []string
+	for name, values := range signedRequestArtifacts.Header {
+		if strings.EqualFold(name, "Authorization") {
+			authorization = append(authorization, values...)
+		}
+		if strings.EqualFold(name, headerName) {
+			value = append(value, values...)
+		}
+	}
+	if len(authorization) != 1 || len(value) != 1 {
+		return "", false
 	}
 
-	httpClient := http.Client{}
+	match := _signedHeadersRegex.FindStringSubmatch(authorization[0])
+	if match == nil {
+		return "", false
+	}
+	for _, signedHeader := range strings.Split(match[1], ";") {
+		if strings.EqualFold(signedHeader, headerName) {
+			return value[0], true
+		}
+	}
+
+	return "", false
+}
+
+func getCallerIdentity(signedRequestArtifacts awsRequest) (*sts.GetCallerIdentityOutput, error) {
+	httpClient := http.Client{Timeout: _identityRequestTimeout}
 
 	url, err := url.Parse(signedRequestArtifacts.URL)
 	if err != nil {
-		return "", errors.WithStack(err)
+		return nil, errors.WithStack(err)
+	}
+	if url.Scheme != "https" || !_stsHostRegex.MatchString(url.Hostname()) || url.Port() != "" {
+		return nil, ErrorInvalidIdentityRequest(signedRequestArtifacts.URL)
+	}
+	if signedRequestArtifacts.Host != "" && signedRequestArtifacts.Host != url.Host {
+		return nil, ErrorInvalidIdentityRequest(signedRequestArtifacts.Host)
 	}
 
 	req := http.Request{
@@ -128,14 +252,14 @@ func ExecuteIdentityRequestFromHeader(indentityRequestheader string) (string, er
 
 	resp, err := httpClient.Do(&req)
 	if err != nil {
-		return "", errors.WithStack(err)
+		return nil, errors.WithStack(err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode >= 400 {
 		awsReq := request.Request{HTTPResponse: resp}
 		query.UnmarshalError(&awsReq)
-		return "", errors.WithStack(awsReq.Error)
+		return nil, errors.WithStack(awsReq.Error)
 	}
 
 	decoder := xml.NewDecoder(resp.Body)
@@ -143,15 +267,15 @@ func ExecuteIdentityRequestFromHeader(indentityRequestheader string) (string, er
 	result := sts.GetCallerIdentityOutput{}
 	err = xmlutil.UnmarshalXML(&result, decoder, "GetCallerIdentityResult")
 	if err != nil {
-		return "", awserr.NewRequestFailure(
+		return nil, awserr.NewRequestFailure(
 			awserr.New(request.ErrCodeSerialization, "failed decoding Query response", err),
 			resp.StatusCode,
 			resp.Header.Get("X-Amzn-Requestid"),
 		)
 	}
-	if result.Account == nil {
-		return "", errors.ErrorUnexpected("GetCallerIdentityResult xml parsing failed")
+	if result.Account == nil || result.Arn == nil {
+		return nil, errors.ErrorUnexpected("GetCallerIdentityResult xml parsing failed")
 	}
 
-	return *result.Account, nil
+	return &result, nil
 }
@@ -0,0 +1,84 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdentityRequestAsHeaderForAudience(t *testing.T) {
+	sess, err := session.NewSession(&aws.Config{
+		Region:      aws.String("us-west-2"),
+		Credentials: credentials.NewStaticCredentials("AKIDEXAMPLE", "secret", ""),
+	})
+	require.NoError(t, err)
+	client, err := NewForSession(sess)
+	require.NoError(t, err)
+
+	header, err := client.IdentityRequestAsHeaderForAudience("X-Cortex-API-Name", "my-api")
+	require.NoError(t, err)
+	signedRequestArtifacts, err := decodeIdentityRequestHeader(header)
+	require.NoError(t, err)
+	value, ok := signedHeaderValue(signedRequestArtifacts, "x-cortex-api-name")
+	require.True(t, ok)
+	require.Equal(t, "my-api", value)
+
+	header, err = client.IdentityRequestAsHeader()
+	require.NoError(t, err)
+	signedRequestArtifacts, err = decodeIdentityRequestHeader(header)
+	require.NoError(t, err)
+	_, ok = signedHeaderValue(signedRequestArtifacts, "X-Cortex-API-Name")
+	require.False(t, ok)
+}
+
+func TestSignedHeaderValue(t *testing.T) {
+	authorization := "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20220301/us-west-2/sts/aws4_request, SignedHeaders=content-type;host;x-amz-date;x-cortex-api-name, Signature=abc"
+
+	value, ok := signedHeaderValue(awsRequest{Header: http.Header{
+		"Authorization":     {authorization},
+		"x-cortex-api-name": {"my-api"},
+	}}, "X-Cortex-API-Name")
+	require.True(t, ok)
+	require.Equal(t, "my-api", value)
+
+	// the header is present but not signed
+	_, ok = signedHeaderValue(awsRequest{Header: http.Header{
+		"Authorization":     {"AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20220301/us-west-2/sts/aws4_request, SignedHeaders=content-type;host;x-amz-date, Signature=abc"},
+		"X-Cortex-Api-Name": {"my-api"},
+	}}, "X-Cortex-API-Name")
+	require.False(t, ok)
+
+	// the header is signed but has multiple values
+	_, ok = signedHeaderValue(awsRequest{Header: http.Header{
+		"Authorization":     {authorization},
+		"X-Cortex-Api-Name": {"my-api"},
+		"x-cortex-api-name": {"other-api"},
+	}}, "X-Cortex-API-Name")
+	require.False(t, ok)
+
+	// the request is not signed
+	_, ok = signedHeaderValue(awsRequest{Header: http.Header{
+		"X-Cortex-Api-Name": {"my-api"},
+	}}, "X-Cortex-API-Name")
+	require.False(t, ok)
+}
@@ -0,0 +1,155 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+)
+
+const _defaultRouteCIDR = "0.0.0.0/0"
+
+// DescribeSubnetsByIDs returns the subnets which exist; subnet IDs which don't exist are omitted rather than causing an error
+func (c *Client) DescribeSubnetsByIDs(subnetIDs ...string) ([]ec2.Subnet, error) {
+	if len(subnetIDs) == 0 {
+		return nil, nil
+	}
+
+	var subnets []ec2.Subnet
+	err := c.EC2().DescribeSubnetsPages(&ec2.DescribeSubnetsInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("subnet-id"),
+				Values: aws.StringSlice(subnetIDs),
+			},
+		},
+	}, func(output *ec2.DescribeSubnetsOutput, lastPage bool) bool {
+		if output == nil {
+			return false
+		}
+		for _, subnet := range output.Subnets {
+			if subnet == nil {
+				continue
+			}
+			subnets = append(subnets, *subnet)
+		}
+
+		return true
+	})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return subnets, nil
+}
+
+// SubnetRouteTables returns the route table which is in effect for each of the provided subnets (keyed by subnet ID);
+// subnets without an explicit route table association use the VPC's main route table
+func (c *Client) SubnetRouteTables(vpcID string, subnetIDs ...string) (map[string]ec2.RouteTable, error) {
+	var routeTables []ec2.RouteTable
+	err := c.EC2().DescribeRouteTablesPages(&ec2.DescribeRouteTablesInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("vpc-id"),
+				Values: []*string{aws.String(vpcID)},
+			},
+		},
+	}, func(output *ec2.DescribeRouteTablesOutput, lastPage bool) bool {
+		if output == nil {
+			return false
+		}
+		for _, routeTable := range output.RouteTables {
+			if routeTable == nil {
+				continue
+			}
+			routeTables = append(routeTables, *routeTable)
+		}
+
+		return true
+	})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return subnetRouteTables(routeTables, subnetIDs), nil
+}
+
+func subnetRouteTables(routeTables []ec2.RouteTable, subnetIDs []string) map[string]ec2.RouteTable {
+	var mainRouteTable *ec2.RouteTable
+	explicitRouteTables := map[string]ec2.RouteTable{}
+
+	for i := range routeTables {
+		for _, association := range routeTables[i].Associations {
+			if association == nil {
+				continue
+			}
+			if aws.BoolValue(association.Main) {
+				mainRouteTable = &routeTables[i]
+			}
+			if association.SubnetId != nil {
+				explicitRouteTables[*association.SubnetId] = routeTables[i]
+			}
+		}
+	}
+
+	subnetRouteTables := map[string]ec2.RouteTable{}
+	for _, subnetID := range subnetIDs {
+		if routeTable, ok := explicitRouteTables[subnetID]; ok {
+			subnetRouteTables[subnetID] = routeTable
+		} else if mainRouteTable != nil {
+			subnetRouteTables[subnetID] = *mainRouteTable
+		}
+	}
+
+	return subnetRouteTables
+}
+
+// DefaultRouteTarget returns the ID of the target of the route table's active 0.0.0.0/0 route (e.g. "igw-...", "nat-...", "tgw-..."), or "" if there is none
+func DefaultRouteTarget(routeTable ec2.RouteTable) string {
+	for _, route := range routeTable.Routes {
+		if route == nil || aws.StringValue(route.DestinationCidrBlock) != _defaultRouteCIDR {
+			continue
+		}
+		if aws.StringValue(route.State) == ec2.RouteStateBlackhole {
+			continue
+		}
+
+		for _, target := range []*string{
+			route.GatewayId,
+			route.NatGatewayId,
+			route.TransitGatewayId,
+			route.VpcPeeringConnectionId,
+			route.NetworkInterfaceId,
+			route.InstanceId,
+			route.LocalGatewayId,
+			route.CarrierGatewayId,
+		} {
+			if aws.StringValue(target) != "" {
+				return *target
+			}
+		}
+	}
+
+	return ""
+}
+
+func IsInternetGatewayID(id string) bool {
+	return strings.HasPrefix(id, "igw-")
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubnetRouteTables(t *testing.T) {
+	mainRouteTable := ec2.RouteTable{
+		RouteTableId: aws.String("rtb-main"),
+		Associations: []*ec2.RouteTableAssociation{{Main: aws.Bool(true)}},
+	}
+	explicitRouteTable := ec2.RouteTable{
+		RouteTableId: aws.String("rtb-explicit"),
+		Associations: []*ec2.RouteTableAssociation{{SubnetId: aws.String("subnet-a")}},
+	}
+
+	routeTables := subnetRouteTables([]ec2.RouteTable{mainRouteTable, explicitRouteTable}, []string{"subnet-a", "subnet-b"})
+	require.Equal(t, "rtb-explicit", *routeTables["subnet-a"].RouteTableId)
+	require.Equal(t, "rtb-main", *routeTables["subnet-b"].RouteTableId)
+
+	routeTables = subnetRouteTables([]ec2.RouteTable{explicitRouteTable}, []string{"subnet-a", "subnet-b"})
+	require.Len(t, routeTables, 1)
+}
+
+func TestDefaultRouteTarget(t *testing.T) {
+	localRoute := &ec2.Route{DestinationCidrBlock: aws.String("10.0.0.0/16"), GatewayId: aws.String("local")}
+
+	require.Equal(t, "", DefaultRouteTarget(ec2.RouteTable{Routes: []*ec2.Route{localRoute}}))
+
+	require.Equal(t, "igw-123", DefaultRouteTarget(ec2.RouteTable{Routes: []*ec2.Route{
+		localRoute,
+		{DestinationCidrBlock: aws.String("0.0.0.0/0"), GatewayId: aws.String("igw-123"), State: aws.String(ec2.RouteStateActive)},
+	}}))
+
+	require.Equal(t, "nat-123", DefaultRouteTarget(ec2.RouteTable{Routes: []*ec2.Route{
+		{DestinationCidrBlock: aws.String("0.0.0.0/0"), NatGatewayId: aws.String("nat-123")},
+	}}))
+
+	require.Equal(t, "", DefaultRouteTarget(ec2.RouteTable{Routes: []*ec2.Route{
+		{DestinationCidrBlock: aws.String("0.0.0.0/0"), NatGatewayId: aws.String("nat-123"), State: aws.String(ec2.RouteStateBlackhole)},
+	}}))
+
+	require.True(t, IsInternetGatewayID("igw-123"))
+	require.False(t, IsInternetGatewayID("nat-123"))
+}
@@ -132,7 +132,8 @@ func ValidateImageVersion(image, cortexVersion string) (string, error) {
 		tag = "latest"
 	}
 
-	if !strings.HasPrefix(tag, cortexVersion) {
+	// arch-specific tags of multi-arch images are formatted as manifest-<version>-<arch>
+	if !strings.HasPrefix(tag, cortexVersion) && !strings.HasPrefix(tag, "manifest-"+cortexVersion) {
 		return "", ErrorImageVersionMismatch(image, tag, cortexVersion)
 	}
 
@@ -17,6 +17,8 @@ limitations under the License.
 package console
 
 import (
+	"fmt"
+
 	"github.com/fatih/color"
 )
 
@@ -34,3 +36,18 @@ func BoolColor(b bool) string {
 	}
 	return color.RedString("%t", b)
 }
+
+// Green returns a string formatted in green
+func Green(a ...interface{}) string {
+	return color.GreenString("%s", fmt.Sprint(a...))
+}
+
+// Red returns a string formatted in red
+func Red(a ...interface{}) string {
+	return color.RedString("%s", fmt.Sprint(a...))
+}
+
+// Yellow returns a string formatted in yellow
+func Yellow(a ...interface{}) string {
+	return color.YellowString("%s", fmt.Sprint(a...))
+}
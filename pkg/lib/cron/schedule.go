@@ -0,0 +1,195 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule is a parsed standard cron expression (minute hour day-of-month month day-of-week);
+// each field supports *, single values, ranges (a-b), lists (a,b) and steps (*/n or a-b/n)
+type Schedule struct {
+	minutes     uint64
+	hours       uint64
+	daysOfMonth uint64
+	months      uint64
+	daysOfWeek  uint64
+
+	// when both day fields are restricted, a day matches if either of them matches
+	daysOfMonthRestricted bool
+	daysOfWeekRestricted  bool
+}
+
+type fieldBounds struct {
+	name string
+	min  int
+	max  int
+}
+
+var (
+	_minuteBounds     = fieldBounds{"minute", 0, 59}
+	_hourBounds       = fieldBounds{"hour", 0, 23}
+	_dayOfMonthBounds = fieldBounds{"day-of-month", 1, 31}
+	_monthBounds      = fieldBounds{"month", 1, 12}
+	_dayOfWeekBounds  = fieldBounds{"day-of-week", 0, 7} // 0 and 7 are both sunday
+)
+
+// how far back Prev() searches for a matching time
+const _maxPrevLookback = 5 * 366 * 24 * time.Hour
+
+func ParseSchedule(schedule string) (*Schedule, error) {
+	fields := strings.Fields(schedule)
+	if len(fields) != 5 {
+		return nil, ErrorInvalidSchedule(schedule, fmt.Sprintf("found %d fields", len(fields)))
+	}
+
+	var s Schedule
+	var err error
+
+	if s.minutes, err = parseField(fields[0], _minuteBounds); err != nil {
+		return nil, ErrorInvalidSchedule(schedule, err.Error())
+	}
+	if s.hours, err = parseField(fields[1], _hourBounds); err != nil {
+		return nil, ErrorInvalidSchedule(schedule, err.Error())
+	}
+	if s.daysOfMonth, err = parseField(fields[2], _dayOfMonthBounds); err != nil {
+		return nil, ErrorInvalidSchedule(schedule, err.Error())
+	}
+	if s.months, err = parseField(fields[3], _monthBounds); err != nil {
+		return nil, ErrorInvalidSchedule(schedule, err.Error())
+	}
+	if s.daysOfWeek, err = parseField(fields[4], _dayOfWeekBounds); err != nil {
+		return nil, ErrorInvalidSchedule(schedule, err.Error())
+	}
+
+	if s.daysOfWeek&(1<<7) != 0 {
+		s.daysOfWeek |= 1
+	}
+
+	s.daysOfMonthRestricted = fields[2] != "*"
+	s.daysOfWeekRestricted = fields[4] != "*"
+
+	return &s, nil
+}
+
+func parseField(field string, bounds fieldBounds) (uint64, error) {
+	var bits uint64
+
+	for _, part := range strings.Split(field, ",") {
+		rangeStr := part
+		step := 1
+
+		if slashIndex := strings.Index(part, "/"); slashIndex != -1 {
+			rangeStr = part[:slashIndex]
+			var err error
+			step, err = strconv.Atoi(part[slashIndex+1:])
+			if err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step in %s field (%s)", bounds.name, part)
+			}
+		}
+
+		var start, end int
+		if rangeStr == "*" {
+			start, end = bounds.min, bounds.max
+		} else if dashIndex := strings.Index(rangeStr, "-"); dashIndex != -1 {
+			var err error
+			if start, err = parseFieldValue(rangeStr[:dashIndex], bounds); err != nil {
+				return 0, err
+			}
+			if end, err = parseFieldValue(rangeStr[dashIndex+1:], bounds); err != nil {
+				return 0, err
+			}
+			if start > end {
+				return 0, fmt.Errorf("invalid range in %s field (%s)", bounds.name, rangeStr)
+			}
+		} else {
+			value, err := parseFieldValue(rangeStr, bounds)
+			if err != nil {
+				return 0, err
+			}
+			start = value
+			end = value
+			if step != 1 {
+				end = bounds.max
+			}
+		}
+
+		for i := start; i <= end; i += step {
+			bits |= 1 << uint(i)
+		}
+	}
+
+	return bits, nil
+}
+
+func parseFieldValue(str string, bounds fieldBounds) (int, error) {
+	value, err := strconv.Atoi(str)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value in %s field (%s)", bounds.name, str)
+	}
+	if value < bounds.min || value > bounds.max {
+		return 0, fmt.Errorf("%s field must be between %d and %d (got %d)", bounds.name, bounds.min, bounds.max, value)
+	}
+	return value, nil
+}
+
+// Matches returns whether the schedule fires during the minute of t
+func (s *Schedule) Matches(t time.Time) bool {
+	return s.dayMatches(t) && s.hours&(1<<uint(t.Hour())) != 0 && s.minutes&(1<<uint(t.Minute())) != 0
+}
+
+func (s *Schedule) dayMatches(t time.Time) bool {
+	if s.months&(1<<uint(t.Month())) == 0 {
+		return false
+	}
+
+	domMatches := s.daysOfMonth&(1<<uint(t.Day())) != 0
+	dowMatches := s.daysOfWeek&(1<<uint(t.Weekday())) != 0
+
+	if s.daysOfMonthRestricted && s.daysOfWeekRestricted {
+		return domMatches || dowMatches
+	}
+	return domMatches && dowMatches
+}
+
+// Prev returns the most recent minute at or before t (in t's location) at which the schedule fired;
+// false is returned if the schedule has not fired within the last five years
+func (s *Schedule) Prev(t time.Time) (time.Time, bool) {
+	t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, t.Location())
+	limit := t.Add(-_maxPrevLookback)
+
+	for t.After(limit) {
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location()).Add(-time.Minute)
+			continue
+		}
+		if s.hours&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, t.Location()).Add(-time.Minute)
+			continue
+		}
+		if s.minutes&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(-time.Minute)
+			continue
+		}
+		return t, true
+	}
+
+	return time.Time{}, false
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSchedule(t *testing.T) {
+	for _, schedule := range []string{
+		"* * * * *",
+		"0 19 * * 1-5",
+		"*/15 8-18 * * *",
+		"0 0 1,15 * *",
+		"30 2 * 1-12/3 0,7",
+	} {
+		_, err := ParseSchedule(schedule)
+		require.NoError(t, err, schedule)
+	}
+
+	for _, schedule := range []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"0 0 * * mon",
+	} {
+		_, err := ParseSchedule(schedule)
+		require.Error(t, err, schedule)
+	}
+}
+
+func TestMatches(t *testing.T) {
+	schedule, err := ParseSchedule("0 19 * * 1-5")
+	require.NoError(t, err)
+
+	require.True(t, schedule.Matches(time.Date(2022, 3, 7, 19, 0, 30, 0, time.UTC)))  // monday
+	require.False(t, schedule.Matches(time.Date(2022, 3, 7, 19, 1, 0, 0, time.UTC)))  // monday
+	require.False(t, schedule.Matches(time.Date(2022, 3, 6, 19, 0, 0, 0, time.UTC)))  // sunday
+	require.False(t, schedule.Matches(time.Date(2022, 3, 12, 19, 0, 0, 0, time.UTC))) // saturday
+
+	// 7 is also sunday
+	schedule, err = ParseSchedule("0 0 * * 7")
+	require.NoError(t, err)
+	require.True(t, schedule.Matches(time.Date(2022, 3, 6, 0, 0, 0, 0, time.UTC)))
+
+	// either day field matches when both are restricted
+	schedule, err = ParseSchedule("0 0 1 * 1")
+	require.NoError(t, err)
+	require.True(t, schedule.Matches(time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC)))  // tuesday the 1st
+	require.True(t, schedule.Matches(time.Date(2022, 3, 7, 0, 0, 0, 0, time.UTC)))  // monday the 7th
+	require.False(t, schedule.Matches(time.Date(2022, 3, 8, 0, 0, 0, 0, time.UTC))) // tuesday the 8th
+}
+
+func TestPrev(t *testing.T) {
+	schedule, err := ParseSchedule("0 19 * * 1-5")
+	require.NoError(t, err)
+
+	// sunday -> previous friday
+	prev, ok := schedule.Prev(time.Date(2022, 3, 6, 12, 0, 0, 0, time.UTC))
+	require.True(t, ok)
+	require.Equal(t, time.Date(2022, 3, 4, 19, 0, 0, 0, time.UTC), prev)
+
+	// at the exact minute
+	prev, ok = schedule.Prev(time.Date(2022, 3, 7, 19, 0, 45, 0, time.UTC))
+	require.True(t, ok)
+	require.Equal(t, time.Date(2022, 3, 7, 19, 0, 0, 0, time.UTC), prev)
+
+	schedule, err = ParseSchedule("*/15 8-18 * * *")
+	require.NoError(t, err)
+	prev, ok = schedule.Prev(time.Date(2022, 3, 7, 12, 44, 0, 0, time.UTC))
+	require.True(t, ok)
+	require.Equal(t, time.Date(2022, 3, 7, 12, 30, 0, 0, time.UTC), prev)
+	prev, ok = schedule.Prev(time.Date(2022, 3, 7, 7, 0, 0, 0, time.UTC))
+	require.True(t, ok)
+	require.Equal(t, time.Date(2022, 3, 6, 18, 45, 0, 0, time.UTC), prev)
+
+	// february 30th never happens
+	schedule, err = ParseSchedule("0 0 30 2 *")
+	require.NoError(t, err)
+	_, ok = schedule.Prev(time.Date(2022, 3, 7, 0, 0, 0, 0, time.UTC))
+	require.False(t, ok)
+}
@@ -0,0 +1,207 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+)
+
+const (
+	_cosignSignatureAnnotation = "dev.cosignproject.cosign/signature"
+	_maxCosignPayloadBytes     = 1024 * 1024
+)
+
+type cosignSignatureManifest struct {
+	Layers []struct {
+		Digest      string            `json:"digest"`
+		Annotations map[string]string `json:"annotations"`
+	} `json:"layers"`
+}
+
+type cosignPayload struct {
+	Critical struct {
+		Identity struct {
+			DockerReference string `json:"docker-reference"`
+		} `json:"identity"`
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+	} `json:"critical"`
+}
+
+// ParseCosignPublicKey parses a PEM-encoded ECDSA public key (e.g. the cosign.pub file which is created by `cosign generate-key-pair`)
+func ParseCosignPublicKey(pemStr string) (*ecdsa.PublicKey, error) {
+	block, _ := pem.Decode([]byte(strings.TrimSpace(pemStr)))
+	if block == nil {
+		return nil, ErrorInvalidCosignPublicKey("expected a PEM-encoded public key")
+	}
+
+	publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, ErrorInvalidCosignPublicKey(errors.Message(err))
+	}
+
+	ecdsaPublicKey, ok := publicKey.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, ErrorInvalidCosignPublicKey("only ECDSA public keys are supported")
+	}
+
+	return ecdsaPublicKey, nil
+}
+
+// CosignSignatureTag returns the tag at which cosign stores the signatures of the manifest with the provided digest (e.g. sha256-<hex>.sig)
+func CosignSignatureTag(digest string) string {
+	return strings.Replace(digest, ":", "-", 1) + ".sig"
+}
+
+// CosignSignedDigest returns the digest of the manifest whose signatures are stored at the tag, and whether the tag is a cosign signature tag (the inverse of CosignSignatureTag)
+func CosignSignedDigest(tag string) (string, bool) {
+	if !strings.HasPrefix(tag, "sha256-") || !strings.HasSuffix(tag, ".sig") {
+		return "", false
+	}
+	return strings.Replace(strings.TrimSuffix(tag, ".sig"), "-", ":", 1), true
+}
+
+// VerifyCosignSignature verifies that the image's manifest (identified by its digest) has a cosign signature which was created with one of the public keys
+// The signatures are fetched from the image's registry using the provided credentials
+func VerifyCosignSignature(image string, digest string, username string, password string, publicKeys []*ecdsa.PublicKey) error {
+	return verifyCosignSignature(_registryHTTPClient, repositoryURL(image), image, digest, username, password, publicKeys)
+}
+
+func verifyCosignSignature(client *http.Client, repositoryURL string, image string, digest string, username string, password string, publicKeys []*ecdsa.PublicKey) error {
+	_, repository, _ := ParseImageReference(image)
+	signatureTag := CosignSignatureTag(digest)
+
+	response, err := registryRequest(client, http.MethodGet, fmt.Sprintf("%s/manifests/%s", repositoryURL, signatureTag), _manifestMediaTypes, repository, username, password)
+	if err != nil {
+		return ErrorImageInaccessible(image, err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusNotFound {
+		return ErrorImageNotSigned(image, signatureTag)
+	}
+	if err := checkRegistryResponse(response, "signature"); err != nil {
+		return ErrorImageInaccessible(image, err)
+	}
+
+	var manifest cosignSignatureManifest
+	if err := json.NewDecoder(response.Body).Decode(&manifest); err != nil {
+		return ErrorImageSignatureInvalid(image, "unable to parse the signature manifest")
+	}
+
+	reason := "no signatures were found in the signature manifest"
+	for _, layer := range manifest.Layers {
+		signature, ok := layer.Annotations[_cosignSignatureAnnotation]
+		if !ok {
+			continue
+		}
+
+		payload, err := fetchBlob(client, fmt.Sprintf("%s/blobs/%s", repositoryURL, layer.Digest), layer.Digest, repository, username, password)
+		if err != nil {
+			return ErrorImageInaccessible(image, err)
+		}
+
+		if err := verifyCosignPayload(payload, signature, image, digest, publicKeys); err != nil {
+			reason = errors.Message(err)
+			continue
+		}
+
+		return nil
+	}
+
+	return ErrorImageSignatureInvalid(image, reason)
+}
+
+func fetchBlob(client *http.Client, blobURL string, blobDigest string, repository string, username string, password string) ([]byte, error) {
+	response, err := registryRequest(client, http.MethodGet, blobURL, nil, repository, username, password)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if err := checkRegistryResponse(response, "blob"); err != nil {
+		return nil, err
+	}
+
+	blob, err := ioutil.ReadAll(io.LimitReader(response.Body, _maxCosignPayloadBytes))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	hash := sha256.Sum256(blob)
+	if "sha256:"+hex.EncodeToString(hash[:]) != blobDigest {
+		return nil, ErrorRegistryRequestFailed(fmt.Sprintf("the content of blob %s does not match its digest", blobDigest))
+	}
+
+	return blob, nil
+}
+
+// verifyCosignPayload verifies the payload's signature with any of the public keys, and that the payload refers to the image's repository and manifest digest
+// (so that a signature which was created for another repository can't be copied to the image's repository)
+func verifyCosignPayload(payload []byte, signature string, image string, digest string, publicKeys []*ecdsa.PublicKey) error {
+	signatureBytes, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return ErrorSignatureVerificationFailed("the signature is not base64-encoded")
+	}
+
+	hash := sha256.Sum256(payload)
+
+	verified := false
+	for _, publicKey := range publicKeys {
+		if ecdsa.VerifyASN1(publicKey, hash[:], signatureBytes) {
+			verified = true
+			break
+		}
+	}
+	if !verified {
+		return ErrorSignatureVerificationFailed("the signature was not created with any of the configured public keys")
+	}
+
+	var parsedPayload cosignPayload
+	if err := json.Unmarshal(payload, &parsedPayload); err != nil {
+		return ErrorSignatureVerificationFailed("unable to parse the signature payload")
+	}
+	if parsedPayload.Critical.Image.DockerManifestDigest != digest {
+		return ErrorSignatureVerificationFailed(fmt.Sprintf("the signature is for a different image (%s)", parsedPayload.Critical.Image.DockerManifestDigest))
+	}
+
+	signedReference := parsedPayload.Critical.Identity.DockerReference
+	if signedReference == "" {
+		return ErrorSignatureVerificationFailed("the signature payload does not specify the image's repository (docker-reference)")
+	}
+	signedRegistry, signedRepository, _ := ParseImageReference(signedReference)
+	registry, repository, _ := ParseImageReference(image)
+	if signedRegistry != registry || signedRepository != repository {
+		return ErrorSignatureVerificationFailed(fmt.Sprintf("the signature is for a different repository (%s)", signedReference))
+	}
+
+	return nil
+}
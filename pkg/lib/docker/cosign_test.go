@@ -0,0 +1,123 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCosignPublicKey(t *testing.T) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	require.NoError(t, err)
+
+	publicKey, err := ParseCosignPublicKey(string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})))
+	require.NoError(t, err)
+	require.True(t, publicKey.Equal(&privateKey.PublicKey))
+
+	_, err = ParseCosignPublicKey("not a key")
+	require.Error(t, err)
+}
+
+func TestCosignSignatureTag(t *testing.T) {
+	digest := "sha256:" + hex.EncodeToString(make([]byte, 32))
+	tag := CosignSignatureTag(digest)
+	require.Equal(t, "sha256-"+hex.EncodeToString(make([]byte, 32))+".sig", tag)
+
+	signedDigest, ok := CosignSignedDigest(tag)
+	require.True(t, ok)
+	require.Equal(t, digest, signedDigest)
+
+	_, ok = CosignSignedDigest("latest")
+	require.False(t, ok)
+	_, ok = CosignSignedDigest("sha256-abc.att")
+	require.False(t, ok)
+}
+
+func TestVerifyCosignSignature(t *testing.T) {
+	digest := "sha256:" + hex.EncodeToString(make([]byte, 32))
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	payload := []byte(`{"critical":{"identity":{"docker-reference":"quay.io/org/image"},"image":{"docker-manifest-digest":"` + digest + `"},"type":"cosign container image signature"},"optional":null}`)
+	payloadHash := sha256.Sum256(payload)
+	payloadDigest := "sha256:" + hex.EncodeToString(payloadHash[:])
+	signature, err := ecdsa.SignASN1(rand.Reader, privateKey, payloadHash[:])
+	require.NoError(t, err)
+
+	manifest, err := json.Marshal(map[string]interface{}{
+		"layers": []map[string]interface{}{
+			{
+				"digest":      payloadDigest,
+				"annotations": map[string]string{_cosignSignatureAnnotation: base64.StdEncoding.EncodeToString(signature)},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/org/image/manifests/" + CosignSignatureTag(digest):
+			w.Write(manifest)
+		case "/v2/org/image/blobs/" + payloadDigest:
+			w.Write(payload)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	repositoryURL := server.URL + "/v2/org/image"
+
+	require.NoError(t, verifyCosignSignature(server.Client(), repositoryURL, "quay.io/org/image", digest, "", "", []*ecdsa.PublicKey{&otherKey.PublicKey, &privateKey.PublicKey}))
+
+	err = verifyCosignSignature(server.Client(), repositoryURL, "quay.io/org/image", digest, "", "", []*ecdsa.PublicKey{&otherKey.PublicKey})
+	require.Error(t, err)
+	require.Equal(t, ErrImageSignatureInvalid, errors.GetKind(err))
+
+	// the signature was created for quay.io/org/image, so it isn't valid for an image in another repository
+	err = verifyCosignSignature(server.Client(), repositoryURL, "quay.io/other/image", digest, "", "", []*ecdsa.PublicKey{&privateKey.PublicKey})
+	require.Error(t, err)
+	require.Equal(t, ErrImageSignatureInvalid, errors.GetKind(err))
+
+	err = verifyCosignPayload(payload, base64.StdEncoding.EncodeToString(signature), "quay.io/other/image", digest, []*ecdsa.PublicKey{&privateKey.PublicKey})
+	require.Equal(t, ErrSignatureVerificationFailed, errors.GetKind(err))
+	require.NoError(t, verifyCosignPayload(payload, base64.StdEncoding.EncodeToString(signature), "quay.io/org/image:latest", digest, []*ecdsa.PublicKey{&privateKey.PublicKey}))
+
+	otherDigest := "sha256:" + hex.EncodeToString(make([]byte, 31)) + "01"
+	err = verifyCosignSignature(server.Client(), repositoryURL, "quay.io/org/image", otherDigest, "", "", []*ecdsa.PublicKey{&privateKey.PublicKey})
+	require.Error(t, err)
+	require.Equal(t, ErrImageNotSigned, errors.GetKind(err))
+}
@@ -26,10 +26,16 @@ import (
 )
 
 const (
-	ErrConnectToDockerDaemon   = "docker.connect_to_docker_daemon"
-	ErrDockerPermissions       = "docker.docker_permissions"
-	ErrImageDoesntExistLocally = "docker.image_doesnt_exist_locally"
-	ErrImageInaccessible       = "docker.image_inaccessible"
+	ErrConnectToDockerDaemon       = "docker.connect_to_docker_daemon"
+	ErrDockerPermissions           = "docker.docker_permissions"
+	ErrImageDoesntExistLocally     = "docker.image_doesnt_exist_locally"
+	ErrImageInaccessible           = "docker.image_inaccessible"
+	ErrInvalidRegistryCredentials  = "docker.invalid_registry_credentials"
+	ErrRegistryRequestFailed       = "docker.registry_request_failed"
+	ErrInvalidCosignPublicKey      = "docker.invalid_cosign_public_key"
+	ErrImageNotSigned              = "docker.image_not_signed"
+	ErrImageSignatureInvalid       = "docker.image_signature_invalid"
+	ErrSignatureVerificationFailed = "docker.signature_verification_failed"
 )
 
 func ErrorConnectToDockerDaemon() error {
@@ -72,7 +78,7 @@ func ErrorImageInaccessible(image string, cause error) error {
 		message += "\n" + errors.Message(cause) // add \n because docker client errors are verbose but useful
 	}
 
-	if strings.Contains(cause.Error(), "auth") {
+	if cause != nil && strings.Contains(cause.Error(), "auth") {
 		message += fmt.Sprintf("\n\nif you would like to use a private docker registry, see https://docs.cortexlabs.com/v/%s/", consts.CortexVersionMinor)
 	}
 
@@ -82,3 +88,45 @@ func ErrorImageInaccessible(image string, cause error) error {
 		Cause:   cause,
 	})
 }
+
+func ErrorInvalidRegistryCredentials(reason string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrInvalidRegistryCredentials,
+		Message: fmt.Sprintf("invalid docker registry credentials: %s", reason),
+	})
+}
+
+func ErrorRegistryRequestFailed(reason string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrRegistryRequestFailed,
+		Message: reason,
+	})
+}
+
+func ErrorInvalidCosignPublicKey(reason string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrInvalidCosignPublicKey,
+		Message: fmt.Sprintf("invalid cosign public key: %s", reason),
+	})
+}
+
+func ErrorImageNotSigned(image string, signatureTag string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrImageNotSigned,
+		Message: fmt.Sprintf("%s is not signed (no cosign signature was found at the %s tag); sign it with `cosign sign --key <key> %s`", image, signatureTag, image),
+	})
+}
+
+func ErrorImageSignatureInvalid(image string, reason string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrImageSignatureInvalid,
+		Message: fmt.Sprintf("the signature of %s could not be verified: %s", image, reason),
+	})
+}
+
+func ErrorSignatureVerificationFailed(reason string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrSignatureVerificationFailed,
+		Message: reason,
+	})
+}
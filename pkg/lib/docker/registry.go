@@ -0,0 +1,404 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+)
+
+const (
+	_dockerHubRegistry     = "docker.io"
+	_dockerHubRegistryHost = "registry-1.docker.io"
+)
+
+var _registryHTTPClient = &http.Client{
+	Timeout: 15 * time.Second,
+}
+
+var _manifestMediaTypes = []string{
+	"application/vnd.docker.distribution.manifest.v2+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.oci.image.index.v1+json",
+}
+
+var _authParamRegex = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+// RegistryConfig holds docker registry credentials, in the format of a kubernetes.io/dockerconfigjson secret
+type RegistryConfig struct {
+	Auths map[string]RegistryAuth `json:"auths"`
+}
+
+type RegistryAuth struct {
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	Auth     string `json:"auth,omitempty"`
+}
+
+// ParseRegistrySecret accepts either a docker config (i.e. {"auths": {...}}), or a single set of credentials in the form {"registry": "...", "username": "...", "password": "..."}
+func ParseRegistrySecret(data []byte) (*RegistryConfig, error) {
+	var registryConfig RegistryConfig
+	if err := json.Unmarshal(data, &registryConfig); err != nil {
+		return nil, ErrorInvalidRegistryCredentials("expected valid json")
+	}
+
+	if registryConfig.Auths == nil {
+		var creds struct {
+			Registry string `json:"registry"`
+			Username string `json:"username"`
+			Password string `json:"password"`
+		}
+		if err := json.Unmarshal(data, &creds); err != nil {
+			return nil, ErrorInvalidRegistryCredentials("expected valid json")
+		}
+		if creds.Registry == "" || creds.Username == "" || creds.Password == "" {
+			return nil, ErrorInvalidRegistryCredentials(`expected either an "auths" key, or "registry", "username", and "password" keys`)
+		}
+		registryConfig.Auths = map[string]RegistryAuth{
+			creds.Registry: {Username: creds.Username, Password: creds.Password},
+		}
+	}
+
+	if len(registryConfig.Auths) == 0 {
+		return nil, ErrorInvalidRegistryCredentials("no credentials were found")
+	}
+
+	for registryAddress, auth := range registryConfig.Auths {
+		if _, _, err := auth.credentials(); err != nil {
+			return nil, errors.Wrap(err, registryAddress)
+		}
+		if auth.Auth == "" {
+			auth.Auth = base64.StdEncoding.EncodeToString([]byte(auth.Username + ":" + auth.Password))
+			registryConfig.Auths[registryAddress] = auth
+		}
+	}
+
+	return &registryConfig, nil
+}
+
+func (auth RegistryAuth) credentials() (string, string, error) {
+	if auth.Username != "" && auth.Password != "" {
+		return auth.Username, auth.Password, nil
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(auth.Auth)
+	if err != nil {
+		return "", "", ErrorInvalidRegistryCredentials(`"auth" must be base64-encoded`)
+	}
+	split := strings.SplitN(string(decoded), ":", 2)
+	if len(split) != 2 || split[0] == "" || split[1] == "" {
+		return "", "", ErrorInvalidRegistryCredentials(`missing username or password`)
+	}
+
+	return split[0], split[1], nil
+}
+
+// Bytes returns the json-encoded docker config, which can be used as the ".dockerconfigjson" key of a kubernetes secret
+func (registryConfig *RegistryConfig) Bytes() ([]byte, error) {
+	data, err := json.Marshal(registryConfig)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return data, nil
+}
+
+// CredentialsForImage returns the username and password for the image's registry, if present
+func (registryConfig *RegistryConfig) CredentialsForImage(image string) (string, string, bool) {
+	registry, _, _ := ParseImageReference(image)
+
+	for registryAddress, auth := range registryConfig.Auths {
+		if normalizeRegistryAddress(registryAddress) != registry {
+			continue
+		}
+		username, password, err := auth.credentials()
+		if err != nil {
+			return "", "", false
+		}
+		return username, password, true
+	}
+
+	return "", "", false
+}
+
+func normalizeRegistryAddress(registryAddress string) string {
+	registryAddress = strings.TrimPrefix(registryAddress, "https://")
+	registryAddress = strings.TrimPrefix(registryAddress, "http://")
+	if slashIndex := strings.Index(registryAddress, "/"); slashIndex != -1 {
+		registryAddress = registryAddress[:slashIndex]
+	}
+
+	switch registryAddress {
+	case "index.docker.io", _dockerHubRegistryHost:
+		return _dockerHubRegistry
+	}
+
+	return registryAddress
+}
+
+// ParseImageReference splits an image into its registry, repository, and reference (tag or digest)
+func ParseImageReference(image string) (string, string, string) {
+	registry := _dockerHubRegistry
+	remainder := image
+
+	if slashIndex := strings.Index(image, "/"); slashIndex != -1 {
+		firstComponent := image[:slashIndex]
+		if strings.ContainsAny(firstComponent, ".:") || firstComponent == "localhost" {
+			registry = normalizeRegistryAddress(firstComponent)
+			remainder = image[slashIndex+1:]
+		}
+	}
+
+	reference := "latest"
+	if atIndex := strings.Index(remainder, "@"); atIndex != -1 {
+		reference = remainder[atIndex+1:]
+		remainder = remainder[:atIndex]
+	} else if colonIndex := strings.LastIndex(remainder, ":"); colonIndex != -1 && !strings.Contains(remainder[colonIndex:], "/") {
+		reference = remainder[colonIndex+1:]
+		remainder = remainder[:colonIndex]
+	}
+
+	if registry == _dockerHubRegistry && !strings.Contains(remainder, "/") {
+		remainder = "library/" + remainder
+	}
+
+	return registry, remainder, reference
+}
+
+// CheckImagePullable verifies that the image's manifest can be fetched from its registry using the provided credentials (via the registry's v2 api, so that a docker daemon is not required)
+func CheckImagePullable(image string, username string, password string) error {
+	_, repository, reference := ParseImageReference(image)
+
+	if err := checkManifest(_registryHTTPClient, registryURL(image, "manifests", reference), repository, username, password); err != nil {
+		return ErrorImageInaccessible(image, err)
+	}
+
+	return nil
+}
+
+// ResolveImageDigest returns the digest of the image's manifest (e.g. "sha256:..."), which is fetched from its registry using the provided credentials
+// If the image is referenced by digest, the digest is returned without contacting the registry
+func ResolveImageDigest(image string, username string, password string) (string, error) {
+	_, repository, reference := ParseImageReference(image)
+	if strings.HasPrefix(reference, "sha256:") {
+		return reference, nil
+	}
+
+	header, err := headManifest(_registryHTTPClient, registryURL(image, "manifests", reference), repository, username, password)
+	if err != nil {
+		return "", ErrorImageInaccessible(image, err)
+	}
+
+	digest := header.Get("Docker-Content-Digest")
+	if digest == "" {
+		return "", ErrorImageInaccessible(image, ErrorRegistryRequestFailed("registry did not return the manifest's digest"))
+	}
+
+	return digest, nil
+}
+
+// ImageWithDigest returns the image, referenced by the provided digest instead of its tag (e.g. quay.io/org/image@sha256:...)
+func ImageWithDigest(image string, digest string) string {
+	name := image
+	if atIndex := strings.Index(name, "@"); atIndex != -1 {
+		name = name[:atIndex]
+	} else if colonIndex := strings.LastIndex(name, ":"); colonIndex != -1 && !strings.Contains(name[colonIndex:], "/") {
+		name = name[:colonIndex]
+	}
+	return name + "@" + digest
+}
+
+// registryURL returns the url of the image's repository's manifest or blob (resourceType is "manifests" or "blobs")
+func registryURL(image string, resourceType string, reference string) string {
+	return fmt.Sprintf("%s/%s/%s", repositoryURL(image), resourceType, reference)
+}
+
+func repositoryURL(image string) string {
+	registry, repository, _ := ParseImageReference(image)
+
+	registryHost := registry
+	if registry == _dockerHubRegistry {
+		registryHost = _dockerHubRegistryHost
+	}
+
+	return fmt.Sprintf("https://%s/v2/%s", registryHost, repository)
+}
+
+func checkManifest(client *http.Client, manifestURL string, repository string, username string, password string) error {
+	_, err := headManifest(client, manifestURL, repository, username, password)
+	return err
+}
+
+// headManifest returns the headers of the registry's response to a HEAD request for the manifest
+func headManifest(client *http.Client, manifestURL string, repository string, username string, password string) (http.Header, error) {
+	response, err := registryRequest(client, http.MethodHead, manifestURL, _manifestMediaTypes, repository, username, password)
+	if err != nil {
+		return nil, err
+	}
+	response.Body.Close()
+
+	if err := checkRegistryResponse(response, "image"); err != nil {
+		return nil, err
+	}
+
+	return response.Header, nil
+}
+
+// registryRequest sends the request, and if the registry responds with an authentication challenge, re-sends it with the provided credentials
+// The caller is responsible for closing the response's body
+func registryRequest(client *http.Client, method string, url string, accept []string, repository string, username string, password string) (*http.Response, error) {
+	response, err := sendRegistryRequest(client, method, url, accept, "")
+	if err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode == http.StatusUnauthorized {
+		response.Body.Close()
+
+		authHeader, err := registryAuthorizationHeader(client, response.Header.Get("WWW-Authenticate"), repository, username, password)
+		if err != nil {
+			return nil, err
+		}
+
+		response, err = sendRegistryRequest(client, method, url, accept, authHeader)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return response, nil
+}
+
+func sendRegistryRequest(client *http.Client, method string, url string, accept []string, authHeader string) (*http.Response, error) {
+	request, err := http.NewRequest(method, url, nil)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if len(accept) > 0 {
+		request.Header.Set("Accept", strings.Join(accept, ", "))
+	}
+	if authHeader != "" {
+		request.Header.Set("Authorization", authHeader)
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return response, nil
+}
+
+// checkRegistryResponse returns an error if the registry didn't respond with 200 (resourceName describes the requested resource, e.g. "image")
+func checkRegistryResponse(response *http.Response, resourceName string) error {
+	switch response.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return ErrorRegistryRequestFailed(fmt.Sprintf("registry returned %d: auth failed (the provided credentials do not grant pull access)", response.StatusCode))
+	case http.StatusNotFound:
+		return ErrorRegistryRequestFailed(fmt.Sprintf("registry returned 404: %s not found", resourceName))
+	default:
+		return ErrorRegistryRequestFailed(fmt.Sprintf("registry returned unexpected status code %d", response.StatusCode))
+	}
+}
+
+// registryAuthorizationHeader implements the docker registry token authentication flow (https://docs.docker.com/registry/spec/auth/token)
+func registryAuthorizationHeader(client *http.Client, challenge string, repository string, username string, password string) (string, error) {
+	basicAuth := "Basic " + base64.StdEncoding.EncodeToString([]byte(username+":"+password))
+
+	if strings.HasPrefix(strings.ToLower(challenge), "basic") {
+		return basicAuth, nil
+	}
+	if !strings.HasPrefix(strings.ToLower(challenge), "bearer") {
+		return "", ErrorRegistryRequestFailed(fmt.Sprintf("registry requested an unsupported authentication scheme (%s)", challenge))
+	}
+
+	params := map[string]string{}
+	for _, match := range _authParamRegex.FindAllStringSubmatch(challenge, -1) {
+		params[strings.ToLower(match[1])] = match[2]
+	}
+	if params["realm"] == "" {
+		return "", ErrorRegistryRequestFailed("registry authentication challenge is missing the token realm")
+	}
+
+	tokenURL, err := url.Parse(params["realm"])
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	query := tokenURL.Query()
+	if params["service"] != "" {
+		query.Set("service", params["service"])
+	}
+	scope := params["scope"]
+	if scope == "" {
+		scope = fmt.Sprintf("repository:%s:pull", repository)
+	}
+	query.Set("scope", scope)
+	tokenURL.RawQuery = query.Encode()
+
+	request, err := http.NewRequest(http.MethodGet, tokenURL.String(), nil)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	// anonymous tokens (e.g. for public images) are requested without credentials
+	if username != "" || password != "" {
+		request.Header.Set("Authorization", basicAuth)
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return "", ErrorRegistryRequestFailed(fmt.Sprintf("registry token endpoint returned %d: auth failed (the provided credentials may be invalid)", response.StatusCode))
+	}
+
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	var tokenResponse struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.Unmarshal(body, &tokenResponse); err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	token := tokenResponse.Token
+	if token == "" {
+		token = tokenResponse.AccessToken
+	}
+	if token == "" {
+		return "", ErrorRegistryRequestFailed("registry token endpoint did not return a token")
+	}
+
+	return "Bearer " + token, nil
+}
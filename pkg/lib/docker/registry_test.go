@@ -0,0 +1,118 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseImageReference(t *testing.T) {
+	var testcases = []struct {
+		image      string
+		registry   string
+		repository string
+		reference  string
+	}{
+		{"python", "docker.io", "library/python", "latest"},
+		{"python:3.9", "docker.io", "library/python", "3.9"},
+		{"cortexlabs/proxy:0.42.0", "docker.io", "cortexlabs/proxy", "0.42.0"},
+		{"index.docker.io/cortexlabs/proxy", "docker.io", "cortexlabs/proxy", "latest"},
+		{"registry.gitlab.com/group/project/image:v1", "registry.gitlab.com", "group/project/image", "v1"},
+		{"localhost:5000/image", "localhost:5000", "image", "latest"},
+		{"localhost:5000/image:v2", "localhost:5000", "image", "v2"},
+		{"company.jfrog.io/repo/image@sha256:abc123", "company.jfrog.io", "repo/image", "sha256:abc123"},
+	}
+
+	for _, tc := range testcases {
+		registry, repository, reference := ParseImageReference(tc.image)
+		require.Equal(t, tc.registry, registry, tc.image)
+		require.Equal(t, tc.repository, repository, tc.image)
+		require.Equal(t, tc.reference, reference, tc.image)
+	}
+}
+
+func TestParseRegistrySecret(t *testing.T) {
+	registryConfig, err := ParseRegistrySecret([]byte(`{"auths": {"https://registry.gitlab.com": {"auth": "dXNlcjpwYXNz"}}}`))
+	require.NoError(t, err)
+	username, password, ok := registryConfig.CredentialsForImage("registry.gitlab.com/group/image:v1")
+	require.True(t, ok)
+	require.Equal(t, "user", username)
+	require.Equal(t, "pass", password)
+
+	_, _, ok = registryConfig.CredentialsForImage("quay.io/group/image:v1")
+	require.False(t, ok)
+
+	registryConfig, err = ParseRegistrySecret([]byte(`{"registry": "company.jfrog.io", "username": "user", "password": "pass"}`))
+	require.NoError(t, err)
+	require.Equal(t, "dXNlcjpwYXNz", registryConfig.Auths["company.jfrog.io"].Auth)
+
+	registryConfig, err = ParseRegistrySecret([]byte(`{"registry": "index.docker.io", "username": "user", "password": "pass"}`))
+	require.NoError(t, err)
+	_, _, ok = registryConfig.CredentialsForImage("cortexlabs/private")
+	require.True(t, ok)
+
+	_, err = ParseRegistrySecret([]byte(`{"username": "user"}`))
+	require.Error(t, err)
+
+	_, err = ParseRegistrySecret([]byte(`{"auths": {"registry.gitlab.com": {"auth": "dXNlcg=="}}}`))
+	require.Error(t, err)
+
+	_, err = ParseRegistrySecret([]byte(`not json`))
+	require.Error(t, err)
+}
+
+func TestCheckManifest(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/token":
+			username, password, _ := r.BasicAuth()
+			if username != "user" || password != "pass" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			require.Equal(t, "repository:group/image:pull", r.URL.Query().Get("scope"))
+			w.Write([]byte(`{"token": "abc"}`))
+		case "/v2/group/image/manifests/v1":
+			if r.Header.Get("Authorization") != "Bearer abc" {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="`+server.URL+`/token",service="registry"`)
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	require.NoError(t, checkManifest(server.Client(), server.URL+"/v2/group/image/manifests/v1", "group/image", "user", "pass"))
+	require.Error(t, checkManifest(server.Client(), server.URL+"/v2/group/image/manifests/v1", "group/image", "user", "wrong"))
+	require.Error(t, checkManifest(server.Client(), server.URL+"/v2/group/image/manifests/v2", "group/image", "user", "pass"))
+}
+
+func TestImageWithDigest(t *testing.T) {
+	require.Equal(t, "python@sha256:abc", ImageWithDigest("python", "sha256:abc"))
+	require.Equal(t, "python@sha256:abc", ImageWithDigest("python:3.9", "sha256:abc"))
+	require.Equal(t, "localhost:5000/image@sha256:abc", ImageWithDigest("localhost:5000/image", "sha256:abc"))
+	require.Equal(t, "localhost:5000/image@sha256:abc", ImageWithDigest("localhost:5000/image:v2", "sha256:abc"))
+	require.Equal(t, "company.jfrog.io/repo/image@sha256:abc", ImageWithDigest("company.jfrog.io/repo/image@sha256:def", "sha256:abc"))
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package envsubst
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/cortexlabs/cortex/pkg/lib/slices"
+)
+
+var _variableNameRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Substitute replaces each ${NAME} in str with the value of the variable, or with the default value for ${NAME:-default} if the variable is unset or empty.
+// $${ is replaced with a literal ${. All of the variables which are undefined (and have no default) are reported in the returned error.
+func Substitute(str string, lookup func(name string) (string, bool)) (string, error) {
+	var out strings.Builder
+	var undefined []string
+
+	for i := 0; i < len(str); {
+		if strings.HasPrefix(str[i:], "$${") {
+			out.WriteString("${")
+			i += 3
+			continue
+		}
+
+		if !strings.HasPrefix(str[i:], "${") {
+			out.WriteByte(str[i])
+			i++
+			continue
+		}
+
+		end := strings.IndexByte(str[i+2:], '}')
+		if end < 0 {
+			return "", ErrorUnterminatedReference(firstLine(str[i:]))
+		}
+		reference := str[i+2 : i+2+end]
+		i += end + 3
+
+		name := reference
+		defaultValue := ""
+		hasDefault := false
+		if j := strings.Index(reference, ":-"); j >= 0 {
+			name = reference[:j]
+			defaultValue = reference[j+2:]
+			hasDefault = true
+		}
+
+		if !_variableNameRegex.MatchString(name) {
+			return "", ErrorInvalidVariableName(name)
+		}
+
+		value, ok := lookup(name)
+		if hasDefault && value == "" {
+			value, ok = defaultValue, true
+		}
+		if !ok {
+			if !slices.HasString(undefined, name) {
+				undefined = append(undefined, name)
+			}
+			continue
+		}
+
+		out.WriteString(value)
+	}
+
+	if len(undefined) > 0 {
+		return "", ErrorUndefinedVariables(undefined)
+	}
+
+	return out.String(), nil
+}
+
+func firstLine(str string) string {
+	if i := strings.IndexByte(str, '\n'); i >= 0 {
+		return str[:i]
+	}
+	return str
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package envsubst
+
+import (
+	"testing"
+
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubstitute(t *testing.T) {
+	vars := map[string]string{
+		"STAGE":    "prod",
+		"REPLICAS": "3",
+		"EMPTY":    "",
+	}
+	lookup := func(name string) (string, bool) {
+		value, ok := vars[name]
+		return value, ok
+	}
+
+	for _, tc := range []struct {
+		name        string
+		input       string
+		expected    string
+		expectedErr string
+	}{
+		{name: "no references", input: "name: api\n", expected: "name: api\n"},
+		{name: "reference", input: "name: api-${STAGE}", expected: "name: api-prod"},
+		{name: "multiple references", input: "${STAGE}: ${REPLICAS}${STAGE}", expected: "prod: 3prod"},
+		{name: "empty value", input: "a${EMPTY}b", expected: "ab"},
+		{name: "default for unset", input: "${MISSING:-1}", expected: "1"},
+		{name: "default for empty", input: "${EMPTY:-x}", expected: "x"},
+		{name: "default ignored", input: "${STAGE:-dev}", expected: "prod"},
+		{name: "empty default", input: "a${MISSING:-}b", expected: "ab"},
+		{name: "escaped", input: "$${STAGE} ${STAGE}", expected: "${STAGE} prod"},
+		{name: "lone dollar", input: "cost: $5 $STAGE", expected: "cost: $5 $STAGE"},
+		{name: "undefined", input: "${A} ${B} ${A}", expectedErr: ErrUndefinedVariables},
+		{name: "invalid name", input: "${1A}", expectedErr: ErrInvalidVariableName},
+		{name: "unterminated", input: "image: ${STAGE\nname: api", expectedErr: ErrUnterminatedReference},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := Substitute(tc.input, lookup)
+			if tc.expectedErr != "" {
+				require.Error(t, err)
+				require.Equal(t, tc.expectedErr, errors.GetKind(err))
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, out)
+		})
+	}
+}
+
+func TestSubstituteReportsAllUndefinedVariables(t *testing.T) {
+	_, err := Substitute("${A} ${B} ${A}", func(string) (string, bool) { return "", false })
+	require.Error(t, err)
+	require.Equal(t, `variables A and B are not defined`, errors.Message(err))
+}
@@ -0,0 +1,51 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package envsubst
+
+import (
+	"fmt"
+
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+)
+
+const (
+	ErrUndefinedVariables    = "envsubst.undefined_variables"
+	ErrInvalidVariableName   = "envsubst.invalid_variable_name"
+	ErrUnterminatedReference = "envsubst.unterminated_reference"
+)
+
+func ErrorUndefinedVariables(names []string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrUndefinedVariables,
+		Message: fmt.Sprintf("%s %s %s not defined", s.PluralS("variable", len(names)), s.StrsAnd(names), s.PluralIs(len(names))),
+	})
+}
+
+func ErrorInvalidVariableName(name string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrInvalidVariableName,
+		Message: fmt.Sprintf("%s is not a valid variable name (variable names may only contain letters, numbers, and underscores, and may not start with a number; use $${ for a literal ${)", s.UserStr(name)),
+	})
+}
+
+func ErrorUnterminatedReference(reference string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrUnterminatedReference,
+		Message: fmt.Sprintf("%s: variable reference is missing its closing }", s.UserStr(reference)),
+	})
+}
@@ -40,6 +40,11 @@ func (cortexError *Error) Error() string {
 	return cortexError.Message
 }
 
+// Unwrap returns the underlying error (if any), so that errors.Is and errors.As can inspect wrapped errors
+func (cortexError *Error) Unwrap() error {
+	return cortexError.Cause
+}
+
 func (cortexError *Error) StackTrace() pkgerrors.StackTrace {
 	stackTrace := make([]pkgerrors.Frame, len(*cortexError.stack))
 	for i := 0; i < len(stackTrace); i++ {
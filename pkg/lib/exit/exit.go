@@ -28,6 +28,12 @@ func Ok() {
 	os.Exit(0)
 }
 
+// Code exits with the given exit code, e.g. to propagate the exit code of a command which was run remotely
+func Code(code int) {
+	telemetry.Close()
+	os.Exit(code)
+}
+
 func Error(err error, wrapStrs ...string) {
 	for _, str := range wrapStrs {
 		err = errors.Wrap(err, str)
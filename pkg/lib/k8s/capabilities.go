@@ -0,0 +1,38 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8s
+
+import (
+	"strings"
+
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/regex"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+)
+
+// NormalizeCapabilities validates linux capability names, and converts them to the form which kubernetes expects (e.g. "cap_net_raw" -> "NET_RAW")
+func NormalizeCapabilities(capabilities []string) ([]string, error) {
+	normalized := make([]string, len(capabilities))
+	for i, capability := range capabilities {
+		normalizedCapability := strings.TrimPrefix(strings.ToUpper(capability), "CAP_")
+		if !regex.IsValidLinuxCapability(normalizedCapability) {
+			return nil, errors.Wrap(ErrorInvalidCapability(capability), s.Index(i))
+		}
+		normalized[i] = normalizedCapability
+	}
+	return normalized, nil
+}
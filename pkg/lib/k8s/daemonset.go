@@ -0,0 +1,156 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8s
+
+import (
+	"context"
+
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	kapps "k8s.io/api/apps/v1"
+	kcore "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	kmeta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	klabels "k8s.io/apimachinery/pkg/labels"
+)
+
+var _daemonSetTypeMeta = kmeta.TypeMeta{
+	APIVersion: "apps/v1",
+	Kind:       "DaemonSet",
+}
+
+type DaemonSetSpec struct {
+	Name        string
+	PodSpec     PodSpec
+	Selector    map[string]string
+	Labels      map[string]string
+	Annotations map[string]string
+}
+
+func DaemonSet(spec *DaemonSetSpec) *kapps.DaemonSet {
+	if spec.PodSpec.Name == "" {
+		spec.PodSpec.Name = spec.Name
+	}
+	if spec.Selector == nil {
+		spec.Selector = spec.PodSpec.Labels
+	}
+
+	daemonSet := &kapps.DaemonSet{
+		TypeMeta: _daemonSetTypeMeta,
+		ObjectMeta: kmeta.ObjectMeta{
+			Name:        spec.Name,
+			Labels:      spec.Labels,
+			Annotations: spec.Annotations,
+		},
+		Spec: kapps.DaemonSetSpec{
+			UpdateStrategy: kapps.DaemonSetUpdateStrategy{
+				Type: kapps.RollingUpdateDaemonSetStrategyType,
+			},
+			Template: kcore.PodTemplateSpec{
+				ObjectMeta: kmeta.ObjectMeta{
+					Name:        spec.PodSpec.Name,
+					Labels:      spec.PodSpec.Labels,
+					Annotations: spec.PodSpec.Annotations,
+				},
+				Spec: spec.PodSpec.K8sPodSpec,
+			},
+			Selector: &kmeta.LabelSelector{
+				MatchLabels: spec.Selector,
+			},
+		},
+	}
+	return daemonSet
+}
+
+func (c *Client) CreateDaemonSet(daemonSet *kapps.DaemonSet) (*kapps.DaemonSet, error) {
+	daemonSet.TypeMeta = _daemonSetTypeMeta
+	daemonSet, err := c.daemonSetClient.Create(context.Background(), daemonSet, kmeta.CreateOptions{})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return daemonSet, nil
+}
+
+func (c *Client) UpdateDaemonSet(daemonSet *kapps.DaemonSet) (*kapps.DaemonSet, error) {
+	daemonSet.TypeMeta = _daemonSetTypeMeta
+	daemonSet, err := c.daemonSetClient.Update(context.Background(), daemonSet, kmeta.UpdateOptions{})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return daemonSet, nil
+}
+
+func (c *Client) ApplyDaemonSet(daemonSet *kapps.DaemonSet) (*kapps.DaemonSet, error) {
+	existing, err := c.GetDaemonSet(daemonSet.Name)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return c.CreateDaemonSet(daemonSet)
+	}
+	return c.UpdateDaemonSet(daemonSet)
+}
+
+func (c *Client) GetDaemonSet(name string) (*kapps.DaemonSet, error) {
+	daemonSet, err := c.daemonSetClient.Get(context.Background(), name, kmeta.GetOptions{})
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.WithStack(err)
+	}
+	daemonSet.TypeMeta = _daemonSetTypeMeta
+	return daemonSet, nil
+}
+
+func (c *Client) DeleteDaemonSet(name string) (bool, error) {
+	err := c.daemonSetClient.Delete(context.Background(), name, _deleteOpts)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, errors.WithStack(err)
+	}
+	return true, nil
+}
+
+func (c *Client) ListDaemonSets(opts *kmeta.ListOptions) ([]kapps.DaemonSet, error) {
+	if opts == nil {
+		opts = &kmeta.ListOptions{}
+	}
+	daemonSetList, err := c.daemonSetClient.List(context.Background(), *opts)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	for i := range daemonSetList.Items {
+		daemonSetList.Items[i].TypeMeta = _daemonSetTypeMeta
+	}
+	return daemonSetList.Items, nil
+}
+
+func (c *Client) ListDaemonSetsByLabels(labels map[string]string) ([]kapps.DaemonSet, error) {
+	opts := &kmeta.ListOptions{
+		LabelSelector: klabels.SelectorFromSet(labels).String(),
+	}
+	return c.ListDaemonSets(opts)
+}
+
+func (c *Client) ListDaemonSetsWithLabelKeys(labelKeys ...string) ([]kapps.DaemonSet, error) {
+	opts := &kmeta.ListOptions{
+		LabelSelector: LabelExistsSelector(labelKeys...),
+	}
+	return c.ListDaemonSets(opts)
+}
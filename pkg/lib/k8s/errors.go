@@ -32,6 +32,8 @@ const (
 	ErrParseQuantity      = "k8s.parse_quantity"
 	ErrMissingMetrics     = "k8s.missing_metrics"
 	ErrServiceNotFound    = "k8s.service_not_found"
+	ErrPortForward        = "k8s.port_forward"
+	ErrInvalidCapability  = "k8s.invalid_capability"
 )
 
 func ErrorLabelNotFound(labelName string) error {
@@ -82,3 +84,17 @@ func ErrorServiceNotFound(serviceName string) error {
 		Message: fmt.Sprintf("service %s couldn't be found", serviceName),
 	})
 }
+
+func ErrorPortForward(podName string, port int32, message string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrPortForward,
+		Message: fmt.Sprintf("unable to forward to port %d of pod %s: %s", port, podName, message),
+	})
+}
+
+func ErrorInvalidCapability(capability string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrInvalidCapability,
+		Message: fmt.Sprintf("%s is not a valid linux capability (e.g. NET_RAW, or ALL to drop all capabilities)", capability),
+	})
+}
@@ -0,0 +1,58 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8s
+
+import (
+	"context"
+
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	kcore "k8s.io/api/core/v1"
+	kmeta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	EventTypeNormal  = kcore.EventTypeNormal
+	EventTypeWarning = kcore.EventTypeWarning
+)
+
+func (c *Client) ListEvents(opts *kmeta.ListOptions) ([]kcore.Event, error) {
+	if opts == nil {
+		opts = &kmeta.ListOptions{}
+	}
+	eventList, err := c.eventClient.List(context.Background(), *opts)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return eventList.Items, nil
+}
+
+// GetEventTime returns the most recent time at which the event was observed
+func GetEventTime(event *kcore.Event) kmeta.Time {
+	if !event.LastTimestamp.IsZero() {
+		return event.LastTimestamp
+	}
+	if event.Series != nil && !event.Series.LastObservedTime.IsZero() {
+		return kmeta.NewTime(event.Series.LastObservedTime.Time)
+	}
+	if !event.EventTime.IsZero() {
+		return kmeta.NewTime(event.EventTime.Time)
+	}
+	if !event.FirstTimestamp.IsZero() {
+		return event.FirstTimestamp
+	}
+	return event.CreationTimestamp
+}
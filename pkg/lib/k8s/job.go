@@ -37,9 +37,14 @@ type JobSpec struct {
 	Namespace    string
 	PodSpec      PodSpec
 	Parallelism  int32
+	Completions  *int32
+	Indexed      bool // each pod is assigned a unique completion index (see kbatch.JobCompletionIndexAnnotation)
 	BackoffLimit int32
 	Labels       map[string]string
 	Annotations  map[string]string
+
+	// if set, the job is deleted this long after it finishes
+	TTLSecondsAfterFinished *int32
 }
 
 func Job(spec *JobSpec) *kbatch.Job {
@@ -56,8 +61,10 @@ func Job(spec *JobSpec) *kbatch.Job {
 			Annotations: spec.Annotations,
 		},
 		Spec: kbatch.JobSpec{
-			BackoffLimit: &spec.BackoffLimit,
-			Parallelism:  &spec.Parallelism,
+			BackoffLimit:            &spec.BackoffLimit,
+			Parallelism:             &spec.Parallelism,
+			Completions:             spec.Completions,
+			TTLSecondsAfterFinished: spec.TTLSecondsAfterFinished,
 			Template: kcore.PodTemplateSpec{
 				ObjectMeta: kmeta.ObjectMeta{
 					Name:        spec.PodSpec.Name,
@@ -68,6 +75,12 @@ func Job(spec *JobSpec) *kbatch.Job {
 			},
 		},
 	}
+
+	if spec.Indexed {
+		completionMode := kbatch.IndexedCompletion
+		job.Spec.CompletionMode = &completionMode
+	}
+
 	return job
 }
 
@@ -35,6 +35,7 @@ import (
 	kclientbatch "k8s.io/client-go/kubernetes/typed/batch/v1"
 	kclientcore "k8s.io/client-go/kubernetes/typed/core/v1"
 	kclientextensions "k8s.io/client-go/kubernetes/typed/extensions/v1beta1"
+	kclientnetworking "k8s.io/client-go/kubernetes/typed/networking/v1"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
 	kclientrest "k8s.io/client-go/rest"
 	kclientcmd "k8s.io/client-go/tools/clientcmd"
@@ -57,13 +58,18 @@ type Client struct {
 	istioClientSet       *istioclient.Clientset
 	dynamicClient        kclientdynamic.Interface
 	podClient            kclientcore.PodInterface
+	namespaceClient      kclientcore.NamespaceInterface
 	nodeClient           kclientcore.NodeInterface
 	serviceClient        kclientcore.ServiceInterface
 	configMapClient      kclientcore.ConfigMapInterface
 	secretClient         kclientcore.SecretInterface
+	serviceAccountClient kclientcore.ServiceAccountInterface
+	eventClient          kclientcore.EventInterface
 	deploymentClient     kclientapps.DeploymentInterface
+	daemonSetClient      kclientapps.DaemonSetInterface
 	jobClient            kclientbatch.JobInterface
 	ingressClient        kclientextensions.IngressInterface
+	networkPolicyClient  kclientnetworking.NetworkPolicyInterface
 	hpaClient            kclientautoscaling.HorizontalPodAutoscalerInterface
 	virtualServiceClient istionetworkingclient.VirtualServiceInterface
 	Namespace            string
@@ -71,9 +77,7 @@ type Client struct {
 
 func New(namespace string, inCluster bool, restConfig *kclientrest.Config, scheme *runtime.Scheme) (*Client, error) {
 	var err error
-	client := &Client{
-		Namespace: namespace,
-	}
+	client := &Client{}
 	if restConfig != nil {
 		client.RestConfig = restConfig
 	} else if inCluster {
@@ -106,20 +110,38 @@ func New(namespace string, inCluster bool, restConfig *kclientrest.Config, schem
 	if err != nil {
 		return nil, errors.Wrap(err, "kubeconfig")
 	}
-	client.virtualServiceClient = client.istioClientSet.NetworkingV1beta1().VirtualServices(namespace)
 
-	client.podClient = client.clientSet.CoreV1().Pods(namespace)
+	client.namespaceClient = client.clientSet.CoreV1().Namespaces()
 	client.nodeClient = client.clientSet.CoreV1().Nodes()
-	client.serviceClient = client.clientSet.CoreV1().Services(namespace)
-	client.configMapClient = client.clientSet.CoreV1().ConfigMaps(namespace)
-	client.secretClient = client.clientSet.CoreV1().Secrets(namespace)
-	client.deploymentClient = client.clientSet.AppsV1().Deployments(namespace)
-	client.jobClient = client.clientSet.BatchV1().Jobs(namespace)
-	client.ingressClient = client.clientSet.ExtensionsV1beta1().Ingresses(namespace)
-	client.hpaClient = client.clientSet.AutoscalingV2().HorizontalPodAutoscalers(namespace)
+	client.setNamespace(namespace)
 	return client, nil
 }
 
+// WithNamespace returns a copy of the client (which shares its connections) whose operations are performed in namespace;
+// if namespace is kmeta.NamespaceAll, the copy's list operations span all namespaces
+func (c *Client) WithNamespace(namespace string) *Client {
+	client := *c
+	client.setNamespace(namespace)
+	return &client
+}
+
+func (c *Client) setNamespace(namespace string) {
+	c.Namespace = namespace
+	c.virtualServiceClient = c.istioClientSet.NetworkingV1beta1().VirtualServices(namespace)
+	c.podClient = c.clientSet.CoreV1().Pods(namespace)
+	c.serviceClient = c.clientSet.CoreV1().Services(namespace)
+	c.configMapClient = c.clientSet.CoreV1().ConfigMaps(namespace)
+	c.secretClient = c.clientSet.CoreV1().Secrets(namespace)
+	c.serviceAccountClient = c.clientSet.CoreV1().ServiceAccounts(namespace)
+	c.eventClient = c.clientSet.CoreV1().Events(namespace)
+	c.deploymentClient = c.clientSet.AppsV1().Deployments(namespace)
+	c.daemonSetClient = c.clientSet.AppsV1().DaemonSets(namespace)
+	c.jobClient = c.clientSet.BatchV1().Jobs(namespace)
+	c.ingressClient = c.clientSet.ExtensionsV1beta1().Ingresses(namespace)
+	c.networkPolicyClient = c.clientSet.NetworkingV1().NetworkPolicies(namespace)
+	c.hpaClient = c.clientSet.AutoscalingV2().HorizontalPodAutoscalers(namespace)
+}
+
 func (c *Client) ClientSet() *kclientset.Clientset {
 	return c.clientSet
 }
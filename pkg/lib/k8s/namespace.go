@@ -0,0 +1,123 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8s
+
+import (
+	"context"
+
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	kcore "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	kmeta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	klabels "k8s.io/apimachinery/pkg/labels"
+)
+
+var _namespaceTypeMeta = kmeta.TypeMeta{
+	APIVersion: "v1",
+	Kind:       "Namespace",
+}
+
+type NamespaceSpec struct {
+	Name        string
+	Labels      map[string]string
+	Annotations map[string]string
+}
+
+func Namespace(spec *NamespaceSpec) *kcore.Namespace {
+	namespace := &kcore.Namespace{
+		TypeMeta: _namespaceTypeMeta,
+		ObjectMeta: kmeta.ObjectMeta{
+			Name:        spec.Name,
+			Labels:      spec.Labels,
+			Annotations: spec.Annotations,
+		},
+	}
+	return namespace
+}
+
+func (c *Client) CreateNamespace(namespace *kcore.Namespace) (*kcore.Namespace, error) {
+	namespace.TypeMeta = _namespaceTypeMeta
+	namespace, err := c.namespaceClient.Create(context.Background(), namespace, kmeta.CreateOptions{})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return namespace, nil
+}
+
+func (c *Client) UpdateNamespace(namespace *kcore.Namespace) (*kcore.Namespace, error) {
+	namespace.TypeMeta = _namespaceTypeMeta
+	namespace, err := c.namespaceClient.Update(context.Background(), namespace, kmeta.UpdateOptions{})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return namespace, nil
+}
+
+func (c *Client) GetNamespace(name string) (*kcore.Namespace, error) {
+	namespace, err := c.namespaceClient.Get(context.Background(), name, kmeta.GetOptions{})
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.WithStack(err)
+	}
+	namespace.TypeMeta = _namespaceTypeMeta
+	return namespace, nil
+}
+
+func (c *Client) DeleteNamespace(name string) (bool, error) {
+	err := c.namespaceClient.Delete(context.Background(), name, _deleteOpts)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, errors.WithStack(err)
+	}
+	return true, nil
+}
+
+func (c *Client) ListNamespaces(opts *kmeta.ListOptions) ([]kcore.Namespace, error) {
+	if opts == nil {
+		opts = &kmeta.ListOptions{}
+	}
+	namespaceList, err := c.namespaceClient.List(context.Background(), *opts)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	for i := range namespaceList.Items {
+		namespaceList.Items[i].TypeMeta = _namespaceTypeMeta
+	}
+	return namespaceList.Items, nil
+}
+
+func (c *Client) ListNamespacesByLabels(labels map[string]string) ([]kcore.Namespace, error) {
+	opts := &kmeta.ListOptions{
+		LabelSelector: klabels.SelectorFromSet(labels).String(),
+	}
+	return c.ListNamespaces(opts)
+}
+
+func (c *Client) ListNamespacesByLabel(labelKey string, labelValue string) ([]kcore.Namespace, error) {
+	return c.ListNamespacesByLabels(map[string]string{labelKey: labelValue})
+}
+
+func (c *Client) ListNamespacesWithLabelKeys(labelKeys ...string) ([]kcore.Namespace, error) {
+	opts := &kmeta.ListOptions{
+		LabelSelector: LabelExistsSelector(labelKeys...),
+	}
+	return c.ListNamespaces(opts)
+}
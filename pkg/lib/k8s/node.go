@@ -99,3 +99,17 @@ func HowManyPodsFitOnNode(podSpec kcore.PodSpec, node kcore.Node, cpuReserved re
 
 	return maxPodsInt64
 }
+
+// CordonNode marks the node as unschedulable (no-op if the node is already cordoned)
+func (c *Client) CordonNode(node *kcore.Node) error {
+	if node.Spec.Unschedulable {
+		return nil
+	}
+	node = node.DeepCopy()
+	node.Spec.Unschedulable = true
+	_, err := c.nodeClient.Update(context.Background(), node, kmeta.UpdateOptions{})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
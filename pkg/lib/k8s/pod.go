@@ -19,18 +19,24 @@ package k8s
 import (
 	"bytes"
 	"context"
+	"io"
+	"net/http"
 	"regexp"
 	"time"
 
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
 	"github.com/cortexlabs/cortex/pkg/lib/pointer"
 	"github.com/cortexlabs/cortex/pkg/lib/sets/strset"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
 	kcore "k8s.io/api/core/v1"
+	kpolicy "k8s.io/api/policy/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	kmeta "k8s.io/apimachinery/pkg/apis/meta/v1"
 	klabels "k8s.io/apimachinery/pkg/labels"
 	kscheme "k8s.io/client-go/kubernetes/scheme"
+	kportforward "k8s.io/client-go/tools/portforward"
 	kremotecommand "k8s.io/client-go/tools/remotecommand"
+	kspdy "k8s.io/client-go/transport/spdy"
 )
 
 var _podTypeMeta = kmeta.TypeMeta{
@@ -379,6 +385,24 @@ func (c *Client) DeletePod(name string) (bool, error) {
 	return true, nil
 }
 
+// EvictPod evicts the pod through the eviction API, so that pod disruption budgets and termination grace periods are respected
+// (returns false if the pod no longer exists)
+func (c *Client) EvictPod(pod *kcore.Pod) (bool, error) {
+	err := c.clientSet.CoreV1().Pods(pod.Namespace).EvictV1(context.Background(), &kpolicy.Eviction{
+		ObjectMeta: kmeta.ObjectMeta{
+			Name:      pod.Name,
+			Namespace: pod.Namespace,
+		},
+	})
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, errors.WithStack(err)
+	}
+	return true, nil
+}
+
 func (c *Client) ListPods(opts *kmeta.ListOptions) ([]kcore.Pod, error) {
 	if opts == nil {
 		opts = &kmeta.ListOptions{}
@@ -485,3 +509,87 @@ func (c *Client) Exec(podName string, containerName string, command []string) (s
 
 	return buf.String(), nil
 }
+
+// ExecStream runs a command in a container of the pod, connecting it to the provided streams until the command exits or ctx is cancelled
+func (c *Client) ExecStream(ctx context.Context, podName string, containerName string, command []string, streamOptions kremotecommand.StreamOptions) error {
+	options := &kcore.PodExecOptions{
+		Container: containerName,
+		Command:   command,
+		Stdin:     streamOptions.Stdin != nil,
+		Stdout:    streamOptions.Stdout != nil,
+		Stderr:    streamOptions.Stderr != nil,
+		TTY:       streamOptions.Tty,
+	}
+
+	req := c.clientSet.CoreV1().RESTClient().Post().Namespace(c.Namespace).Resource("pods").Name(podName).SubResource("exec")
+	req.VersionedParams(options, kscheme.ParameterCodec)
+
+	exec, err := kremotecommand.NewSPDYExecutor(c.RestConfig, "POST", req.URL())
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	return exec.StreamWithContext(ctx, streamOptions)
+}
+
+// PortForward forwards a single connection to a port of the pod: the data read from src is sent to the port until src returns EOF,
+// and the data received from the port is written to dst until the pod closes the connection
+func (c *Client) PortForward(podName string, port int32, src io.Reader, dst io.Writer) error {
+	transport, upgrader, err := kspdy.RoundTripperFor(c.RestConfig)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	req := c.clientSet.CoreV1().RESTClient().Post().Namespace(c.Namespace).Resource("pods").Name(podName).SubResource("portforward")
+	dialer := kspdy.NewDialer(upgrader, &http.Client{Transport: transport}, "POST", req.URL())
+
+	streamConn, _, err := dialer.Dial(kportforward.PortForwardProtocolV1Name)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer streamConn.Close()
+
+	headers := http.Header{}
+	headers.Set(kcore.StreamType, kcore.StreamTypeError)
+	headers.Set(kcore.PortHeader, s.Int32(port))
+	headers.Set(kcore.PortForwardRequestIDHeader, "0")
+	errorStream, err := streamConn.CreateStream(headers)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	// the error stream is only read from
+	errorStream.Close()
+
+	errorChan := make(chan error, 1)
+	go func() {
+		message, err := io.ReadAll(errorStream)
+		if err != nil {
+			errorChan <- errors.WithStack(err)
+		} else if len(message) > 0 {
+			errorChan <- ErrorPortForward(podName, port, string(message))
+		}
+		close(errorChan)
+	}()
+
+	headers.Set(kcore.StreamType, kcore.StreamTypeData)
+	dataStream, err := streamConn.CreateStream(headers)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	go func() {
+		io.Copy(dataStream, src)
+		// half-closes the stream, so that the pod sees the end of the connection's input
+		dataStream.Close()
+	}()
+
+	_, copyErr := io.Copy(dst, dataStream)
+
+	if err := <-errorChan; err != nil {
+		return err
+	}
+	if copyErr != nil {
+		return errors.WithStack(copyErr)
+	}
+	return nil
+}
@@ -33,6 +33,7 @@ var _secretTypeMeta = kmeta.TypeMeta{
 
 type SecretSpec struct {
 	Name        string
+	Type        kcore.SecretType
 	Data        map[string][]byte
 	Labels      map[string]string
 	Annotations map[string]string
@@ -46,6 +47,7 @@ func Secret(spec *SecretSpec) *kcore.Secret {
 			Labels:      spec.Labels,
 			Annotations: spec.Annotations,
 		},
+		Type: spec.Type,
 		Data: spec.Data,
 	}
 	return secret
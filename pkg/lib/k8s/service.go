@@ -39,6 +39,7 @@ type ServiceSpec struct {
 	Port        int32
 	TargetPort  int32
 	ServiceType kcore.ServiceType
+	ClusterIP   string // set to kcore.ClusterIPNone for a headless service
 	Selector    map[string]string
 	Labels      map[string]string
 	Annotations map[string]string
@@ -53,8 +54,9 @@ func Service(spec *ServiceSpec) *kcore.Service {
 			Annotations: spec.Annotations,
 		},
 		Spec: kcore.ServiceSpec{
-			Selector: spec.Selector,
-			Type:     spec.ServiceType,
+			Selector:  spec.Selector,
+			Type:      spec.ServiceType,
+			ClusterIP: spec.ClusterIP,
 			Ports: []kcore.ServicePort{
 				{
 					Protocol: kcore.ProtocolTCP,
@@ -18,6 +18,7 @@ package k8s
 
 import (
 	"context"
+	"strings"
 
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
 	"github.com/cortexlabs/cortex/pkg/lib/sets/strset"
@@ -46,6 +47,14 @@ type VirtualServiceSpec struct {
 	Annotations  map[string]string
 	Headers      *istionetworking.Headers
 	Retries      *int32
+	HeaderRoutes []HeaderRoute // take precedence over the path routes
+}
+
+// HeaderRoute routes requests based on a path prefix and request headers, e.g. for grpc requests (whose paths are determined by the grpc service)
+type HeaderRoute struct {
+	PrefixPath   string
+	Headers      map[string]string // exact matches (keys must be lowercase)
+	Destinations []Destination
 }
 
 type Destination struct {
@@ -57,37 +66,41 @@ type Destination struct {
 }
 
 func VirtualService(spec *VirtualServiceSpec) *istioclientnetworking.VirtualService {
-	destinations := []*istionetworking.HTTPRouteDestination{}
-	var mirror *istionetworking.Destination
-	var mirrorWeight *istionetworking.Percent
+	destinations, mirror, mirrorWeight := httpRouteDestinations(spec.Destinations)
 
-	for _, destination := range spec.Destinations {
-		if destination.Shadow {
-			mirror = &istionetworking.Destination{
-				Host: destination.ServiceName,
-				Port: &istionetworking.PortSelector{
-					Number: destination.Port,
+	var httpRoutes []*istionetworking.HTTPRoute
+
+	for _, headerRoute := range spec.HeaderRoutes {
+		headerMatches := map[string]*istionetworking.StringMatch{}
+		for key, value := range headerRoute.Headers {
+			headerMatches[key] = &istionetworking.StringMatch{
+				MatchType: &istionetworking.StringMatch_Exact{
+					Exact: value,
 				},
 			}
-			mirrorWeight = &istionetworking.Percent{Value: float64(destination.Weight)}
-		} else {
-			destinations = append(destinations, &istionetworking.HTTPRouteDestination{
-				Destination: &istionetworking.Destination{
-					Host: destination.ServiceName,
-					Port: &istionetworking.PortSelector{
-						Number: destination.Port,
+		}
+
+		headerRouteDestinations, headerRouteMirror, headerRouteMirrorWeight := httpRouteDestinations(headerRoute.Destinations)
+		httpRoutes = append(httpRoutes, &istionetworking.HTTPRoute{
+			Match: []*istionetworking.HTTPMatchRequest{
+				{
+					Uri: &istionetworking.StringMatch{
+						MatchType: &istionetworking.StringMatch_Prefix{
+							Prefix: headerRoute.PrefixPath,
+						},
 					},
+					Headers: headerMatches,
 				},
-				Weight:  destination.Weight,
-				Headers: destination.Headers,
-			})
-		}
+			},
+			Route:            headerRouteDestinations,
+			Mirror:           headerRouteMirror,
+			MirrorPercentage: headerRouteMirrorWeight,
+			Headers:          spec.Headers,
+		})
 	}
 
-	var httpRoutes []*istionetworking.HTTPRoute
-
 	if spec.ExactPath != nil {
-		httpRoutes = append(httpRoutes, &istionetworking.HTTPRoute{
+		exactRoute := &istionetworking.HTTPRoute{
 			Match: []*istionetworking.HTTPMatchRequest{
 				{
 					Uri: &istionetworking.StringMatch{
@@ -101,13 +114,15 @@ func VirtualService(spec *VirtualServiceSpec) *istioclientnetworking.VirtualServ
 			Mirror:           mirror,
 			MirrorPercentage: mirrorWeight,
 			Headers:          spec.Headers,
-		})
+		}
 
 		if spec.Rewrite != nil {
-			httpRoutes[0].Rewrite = &istionetworking.HTTPRewrite{
+			exactRoute.Rewrite = &istionetworking.HTTPRewrite{
 				Uri: urls.CanonicalizeEndpoint(*spec.Rewrite),
 			}
 		}
+
+		httpRoutes = append(httpRoutes, exactRoute)
 	} else {
 		exactMatch := &istionetworking.HTTPRoute{
 			Match: []*istionetworking.HTTPMatchRequest{
@@ -179,6 +194,37 @@ func VirtualService(spec *VirtualServiceSpec) *istioclientnetworking.VirtualServ
 	return virtualService
 }
 
+func httpRouteDestinations(destinations []Destination) ([]*istionetworking.HTTPRouteDestination, *istionetworking.Destination, *istionetworking.Percent) {
+	httpDestinations := []*istionetworking.HTTPRouteDestination{}
+	var mirror *istionetworking.Destination
+	var mirrorWeight *istionetworking.Percent
+
+	for _, destination := range destinations {
+		if destination.Shadow {
+			mirror = &istionetworking.Destination{
+				Host: destination.ServiceName,
+				Port: &istionetworking.PortSelector{
+					Number: destination.Port,
+				},
+			}
+			mirrorWeight = &istionetworking.Percent{Value: float64(destination.Weight)}
+		} else {
+			httpDestinations = append(httpDestinations, &istionetworking.HTTPRouteDestination{
+				Destination: &istionetworking.Destination{
+					Host: destination.ServiceName,
+					Port: &istionetworking.PortSelector{
+						Number: destination.Port,
+					},
+				},
+				Weight:  destination.Weight,
+				Headers: destination.Headers,
+			})
+		}
+	}
+
+	return httpDestinations, mirror, mirrorWeight
+}
+
 func (c *Client) VirtualServiceClient() istionetworkingclient.VirtualServiceInterface {
 	return c.virtualServiceClient
 }
@@ -269,8 +315,13 @@ func (c *Client) ListVirtualServicesWithLabelKeys(labelKeys ...string) ([]*istio
 	return c.ListVirtualServices(opts)
 }
 
+// ExtractVirtualServiceGateways returns the names of the virtual service's gateways (without the namespace which qualifies them, if any)
 func ExtractVirtualServiceGateways(virtualService *istioclientnetworking.VirtualService) strset.Set {
-	return strset.FromSlice(virtualService.Spec.Gateways)
+	gateways := strset.New()
+	for _, gateway := range virtualService.Spec.Gateways {
+		gateways.Add(gateway[strings.LastIndex(gateway, "/")+1:])
+	}
+	return gateways
 }
 
 func ExtractVirtualServiceEndpoints(virtualService *istioclientnetworking.VirtualService) strset.Set {
@@ -0,0 +1,94 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oidc
+
+import (
+	"fmt"
+
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+)
+
+const (
+	ErrDiscovery              = "oidc.discovery"
+	ErrDeviceFlowNotSupported = "oidc.device_flow_not_supported"
+	ErrTokenRequest           = "oidc.token_request"
+	ErrAccessDenied           = "oidc.access_denied"
+	ErrDeviceCodeExpired      = "oidc.device_code_expired"
+	ErrNoIDToken              = "oidc.no_id_token"
+	ErrInvalidToken           = "oidc.invalid_token"
+	ErrTokenExpired           = "oidc.token_expired"
+)
+
+func ErrorDiscovery(issuerURL string, err error) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrDiscovery,
+		Message: fmt.Sprintf("unable to retrieve the openid connect configuration of %s: %s", issuerURL, errors.Message(err)),
+	})
+}
+
+func ErrorDeviceFlowNotSupported(issuerURL string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrDeviceFlowNotSupported,
+		Message: fmt.Sprintf("the identity provider %s does not support the device authorization flow", issuerURL),
+	})
+}
+
+func ErrorTokenRequest(errorCode string, description string) error {
+	message := fmt.Sprintf("the identity provider rejected the request: %s", errorCode)
+	if description != "" {
+		message += fmt.Sprintf(" (%s)", description)
+	}
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrTokenRequest,
+		Message: message,
+	})
+}
+
+func ErrorAccessDenied() error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrAccessDenied,
+		Message: "the login request was denied",
+	})
+}
+
+func ErrorDeviceCodeExpired() error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrDeviceCodeExpired,
+		Message: "the login request expired before it was approved; please try again",
+	})
+}
+
+func ErrorNoIDToken() error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrNoIDToken,
+		Message: "the identity provider did not return an id token (the openid scope may be missing)",
+	})
+}
+
+func ErrorInvalidToken(reason string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrInvalidToken,
+		Message: fmt.Sprintf("invalid id token: %s", reason),
+	})
+}
+
+func ErrorTokenExpired() error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrTokenExpired,
+		Message: "your login has expired; run `cortex login` to log in again",
+	})
+}
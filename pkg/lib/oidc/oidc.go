@@ -0,0 +1,239 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oidc
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+)
+
+const _requestTimeout = 15 * time.Second
+
+var _httpClient = &http.Client{Timeout: _requestTimeout}
+
+// ProviderMetadata is the subset of an identity provider's discovery document which is used by cortex
+type ProviderMetadata struct {
+	Issuer                      string `json:"issuer"`
+	DeviceAuthorizationEndpoint string `json:"device_authorization_endpoint"`
+	TokenEndpoint               string `json:"token_endpoint"`
+	JWKSURI                     string `json:"jwks_uri"`
+}
+
+// Token holds the tokens which are stored by the cli after logging in
+type Token struct {
+	IDToken      string    `json:"id_token" yaml:"id_token"`
+	RefreshToken string    `json:"refresh_token,omitempty" yaml:"refresh_token,omitempty"`
+	Expiry       time.Time `json:"expiry" yaml:"expiry"` // expiry of the id token
+}
+
+// Expired returns whether the id token has expired, or will expire within the leeway
+func (t *Token) Expired(leeway time.Duration) bool {
+	return time.Now().Add(leeway).After(t.Expiry)
+}
+
+type DeviceAuthorization struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete"`
+	ExpiresIn               int64  `json:"expires_in"`
+	Interval                int64  `json:"interval"`
+}
+
+type tokenResponse struct {
+	IDToken          string `json:"id_token"`
+	RefreshToken     string `json:"refresh_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// Discover retrieves the identity provider's discovery document from <issuer>/.well-known/openid-configuration
+func Discover(issuerURL string) (*ProviderMetadata, error) {
+	discoveryURL := strings.TrimSuffix(issuerURL, "/") + "/.well-known/openid-configuration"
+
+	var metadata ProviderMetadata
+	if err := getJSON(discoveryURL, &metadata); err != nil {
+		return nil, ErrorDiscovery(issuerURL, err)
+	}
+
+	if strings.TrimSuffix(metadata.Issuer, "/") != strings.TrimSuffix(issuerURL, "/") {
+		return nil, ErrorDiscovery(issuerURL, errors.ErrorUnexpected("the discovery document's issuer does not match", metadata.Issuer))
+	}
+	if metadata.JWKSURI == "" || metadata.TokenEndpoint == "" {
+		return nil, ErrorDiscovery(issuerURL, errors.ErrorUnexpected("the discovery document does not include a jwks_uri and token_endpoint"))
+	}
+
+	return &metadata, nil
+}
+
+// StartDeviceAuthorization starts the device authorization flow (RFC 8628); the user must then visit the verification uri and enter the user code
+func StartDeviceAuthorization(metadata *ProviderMetadata, clientID string, scopes []string) (*DeviceAuthorization, error) {
+	if metadata.DeviceAuthorizationEndpoint == "" {
+		return nil, ErrorDeviceFlowNotSupported(metadata.Issuer)
+	}
+
+	var deviceAuthRes struct {
+		DeviceAuthorization
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	err := postForm(metadata.DeviceAuthorizationEndpoint, url.Values{
+		"client_id": {clientID},
+		"scope":     {strings.Join(scopes, " ")},
+	}, &deviceAuthRes)
+	if err != nil {
+		return nil, err
+	}
+	if deviceAuthRes.Error != "" {
+		return nil, ErrorTokenRequest(deviceAuthRes.Error, deviceAuthRes.ErrorDescription)
+	}
+
+	deviceAuth := deviceAuthRes.DeviceAuthorization
+	if deviceAuth.DeviceCode == "" || deviceAuth.UserCode == "" || deviceAuth.VerificationURI == "" {
+		return nil, errors.ErrorUnexpected("the identity provider's device authorization response is incomplete")
+	}
+	if deviceAuth.Interval <= 0 {
+		deviceAuth.Interval = 5
+	}
+
+	return &deviceAuth, nil
+}
+
+// PollDeviceToken polls the token endpoint until the user has approved (or denied) the device authorization, or it expires
+func PollDeviceToken(metadata *ProviderMetadata, clientID string, deviceAuth *DeviceAuthorization) (*Token, error) {
+	interval := time.Duration(deviceAuth.Interval) * time.Second
+	deadline := time.Now().Add(time.Duration(deviceAuth.ExpiresIn) * time.Second)
+
+	for {
+		time.Sleep(interval)
+
+		var tokenRes tokenResponse
+		err := postForm(metadata.TokenEndpoint, url.Values{
+			"grant_type":  {"urn:ietf:params:oauth:grant-type:device_code"},
+			"device_code": {deviceAuth.DeviceCode},
+			"client_id":   {clientID},
+		}, &tokenRes)
+		if err != nil {
+			return nil, err
+		}
+
+		switch tokenRes.Error {
+		case "":
+			return newToken(tokenRes)
+		case "authorization_pending":
+		case "slow_down":
+			interval += 5 * time.Second
+		case "access_denied":
+			return nil, ErrorAccessDenied()
+		case "expired_token":
+			return nil, ErrorDeviceCodeExpired()
+		default:
+			return nil, ErrorTokenRequest(tokenRes.Error, tokenRes.ErrorDescription)
+		}
+
+		if deviceAuth.ExpiresIn > 0 && time.Now().After(deadline) {
+			return nil, ErrorDeviceCodeExpired()
+		}
+	}
+}
+
+// Refresh exchanges a refresh token for a new id token
+func Refresh(metadata *ProviderMetadata, clientID string, refreshToken string) (*Token, error) {
+	var tokenRes tokenResponse
+	err := postForm(metadata.TokenEndpoint, url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {refreshToken},
+		"client_id":     {clientID},
+	}, &tokenRes)
+	if err != nil {
+		return nil, err
+	}
+	if tokenRes.Error != "" {
+		return nil, ErrorTokenRequest(tokenRes.Error, tokenRes.ErrorDescription)
+	}
+
+	token, err := newToken(tokenRes)
+	if err != nil {
+		return nil, err
+	}
+	if token.RefreshToken == "" {
+		// refresh tokens are not always rotated
+		token.RefreshToken = refreshToken
+	}
+	return token, nil
+}
+
+func newToken(tokenRes tokenResponse) (*Token, error) {
+	if tokenRes.IDToken == "" {
+		return nil, ErrorNoIDToken()
+	}
+
+	// the id token is verified by the operator; here it's only parsed to determine when it must be refreshed
+	_, claims, _, err := parseJWT(tokenRes.IDToken)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Token{
+		IDToken:      tokenRes.IDToken,
+		RefreshToken: tokenRes.RefreshToken,
+		Expiry:       claims.expiry(),
+	}, nil
+}
+
+func getJSON(url string, response interface{}) error {
+	res, err := _httpClient.Get(url)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer res.Body.Close()
+
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if res.StatusCode != http.StatusOK {
+		return errors.ErrorUnexpected("request failed", url, res.StatusCode, string(body))
+	}
+
+	return errors.WithStack(json.Unmarshal(body, response))
+}
+
+// postForm decodes the response body regardless of the status code, since oauth errors are returned with status code 400
+func postForm(url string, values url.Values, response interface{}) error {
+	res, err := _httpClient.PostForm(url, values)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer res.Body.Close()
+
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if err := json.Unmarshal(body, response); err != nil {
+		return errors.ErrorUnexpected("unable to parse the identity provider's response", url, res.StatusCode, string(body))
+	}
+
+	return nil
+}
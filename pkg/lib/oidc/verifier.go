@@ -0,0 +1,294 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oidc
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/slices"
+)
+
+const (
+	_clockSkewLeeway = 1 * time.Minute
+
+	// the jwks is re-fetched when a token is signed with an unknown key (e.g. after the identity provider rotates its keys), at most once per interval
+	_minJWKSRefreshInterval = 1 * time.Minute
+)
+
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+// Claims are the claims of an id token
+type Claims map[string]interface{}
+
+// String returns the value of a string claim, or "" if the claim is not set or is not a string
+func (c Claims) String(name string) string {
+	str, _ := c[name].(string)
+	return str
+}
+
+func (c Claims) time(name string) (time.Time, bool) {
+	seconds, ok := c[name].(float64)
+	if !ok {
+		return time.Time{}, false
+	}
+	return time.Unix(int64(seconds), 0), true
+}
+
+func (c Claims) expiry() time.Time {
+	expiry, _ := c.time("exp")
+	return expiry
+}
+
+func (c Claims) audiences() []string {
+	switch aud := c["aud"].(type) {
+	case string:
+		return []string{aud}
+	case []interface{}:
+		audiences := make([]string, 0, len(aud))
+		for _, audience := range aud {
+			if audienceStr, ok := audience.(string); ok {
+				audiences = append(audiences, audienceStr)
+			}
+		}
+		return audiences
+	}
+	return nil
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// Verifier verifies id tokens which were issued by an identity provider to a client (RS256 and ES256 signatures are supported)
+type Verifier struct {
+	issuerURL string
+	clientID  string
+
+	mux           sync.Mutex
+	keys          map[string]crypto.PublicKey // keyed by kid
+	lastJWKSFetch time.Time
+}
+
+// NewVerifier returns a verifier which retrieves the identity provider's discovery document and signing keys when the first token is verified
+func NewVerifier(issuerURL string, clientID string) *Verifier {
+	return &Verifier{
+		issuerURL: issuerURL,
+		clientID:  clientID,
+	}
+}
+
+// Verify checks the id token's signature, issuer, audience, and expiry, and returns its claims
+func (v *Verifier) Verify(rawIDToken string) (Claims, error) {
+	header, claims, signedContent, err := parseJWT(rawIDToken)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := v.getKey(header.Kid)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(rawIDToken[strings.LastIndex(rawIDToken, ".")+1:])
+	if err != nil {
+		return nil, ErrorInvalidToken("malformed signature")
+	}
+	if err := verifySignature(header.Alg, key, signedContent, signature); err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSuffix(claims.String("iss"), "/") != strings.TrimSuffix(v.issuerURL, "/") {
+		return nil, ErrorInvalidToken("the token was issued by " + claims.String("iss"))
+	}
+	if !slices.HasString(claims.audiences(), v.clientID) {
+		return nil, ErrorInvalidToken("the token was not issued for client " + v.clientID)
+	}
+
+	now := time.Now()
+	expiry, ok := claims.time("exp")
+	if !ok {
+		return nil, ErrorInvalidToken("the token does not have an expiry")
+	}
+	if now.Add(-_clockSkewLeeway).After(expiry) {
+		return nil, ErrorTokenExpired()
+	}
+	if notBefore, ok := claims.time("nbf"); ok && now.Add(_clockSkewLeeway).Before(notBefore) {
+		return nil, ErrorInvalidToken("the token is not valid yet")
+	}
+
+	return claims, nil
+}
+
+func (v *Verifier) getKey(kid string) (crypto.PublicKey, error) {
+	v.mux.Lock()
+	defer v.mux.Unlock()
+
+	if key, ok := v.keys[kid]; ok {
+		return key, nil
+	}
+
+	if time.Since(v.lastJWKSFetch) < _minJWKSRefreshInterval {
+		return nil, ErrorInvalidToken("the token was signed with an unknown key")
+	}
+	v.lastJWKSFetch = time.Now()
+
+	metadata, err := Discover(v.issuerURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var jwks struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := getJSON(metadata.JWKSURI, &jwks); err != nil {
+		return nil, ErrorDiscovery(v.issuerURL, err)
+	}
+
+	keys := map[string]crypto.PublicKey{}
+	for _, key := range jwks.Keys {
+		if key.Use != "" && key.Use != "sig" {
+			continue
+		}
+		if publicKey, err := key.publicKey(); err == nil {
+			keys[key.Kid] = publicKey
+		}
+	}
+	v.keys = keys
+
+	if key, ok := v.keys[kid]; ok {
+		return key, nil
+	}
+	return nil, ErrorInvalidToken("the token was signed with an unknown key")
+}
+
+func (k *jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		if k.Crv != "P-256" {
+			return nil, errors.ErrorUnexpected("unsupported curve", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}, nil
+	}
+	return nil, errors.ErrorUnexpected("unsupported key type", k.Kty)
+}
+
+func decodeBigInt(str string) (*big.Int, error) {
+	bytes, err := base64.RawURLEncoding.DecodeString(str)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return new(big.Int).SetBytes(bytes), nil
+}
+
+func verifySignature(alg string, key crypto.PublicKey, signedContent string, signature []byte) error {
+	digest := sha256.Sum256([]byte(signedContent))
+
+	switch alg {
+	case "RS256":
+		rsaKey, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return ErrorInvalidToken("the token's algorithm does not match its key")
+		}
+		if rsa.VerifyPKCS1v15(rsaKey, crypto.SHA256, digest[:], signature) != nil {
+			return ErrorInvalidToken("invalid signature")
+		}
+		return nil
+	case "ES256":
+		ecKey, ok := key.(*ecdsa.PublicKey)
+		if !ok || len(signature) != 64 {
+			return ErrorInvalidToken("the token's algorithm does not match its key")
+		}
+		r := new(big.Int).SetBytes(signature[:32])
+		s := new(big.Int).SetBytes(signature[32:])
+		if !ecdsa.Verify(ecKey, digest[:], r, s) {
+			return ErrorInvalidToken("invalid signature")
+		}
+		return nil
+	}
+
+	return ErrorInvalidToken("unsupported signing algorithm " + alg)
+}
+
+// parseJWT decodes the token's header and claims without verifying it, and returns the content which is signed
+func parseJWT(rawToken string) (jwtHeader, Claims, string, error) {
+	parts := strings.Split(rawToken, ".")
+	if len(parts) != 3 {
+		return jwtHeader{}, nil, "", ErrorInvalidToken("malformed token")
+	}
+
+	var header jwtHeader
+	if err := decodeJWTPart(parts[0], &header); err != nil {
+		return jwtHeader{}, nil, "", err
+	}
+
+	var claims Claims
+	if err := decodeJWTPart(parts[1], &claims); err != nil {
+		return jwtHeader{}, nil, "", err
+	}
+
+	return header, claims, parts[0] + "." + parts[1], nil
+}
+
+func decodeJWTPart(part string, v interface{}) error {
+	bytes, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return ErrorInvalidToken("malformed token")
+	}
+	if err := json.Unmarshal(bytes, v); err != nil {
+		return ErrorInvalidToken("malformed token")
+	}
+	return nil
+}
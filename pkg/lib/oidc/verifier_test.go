@@ -0,0 +1,127 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oidc
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestProvider(t *testing.T, key *rsa.PrivateKey) *httptest.Server {
+	t.Helper()
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/.well-known/openid-configuration":
+			json.NewEncoder(w).Encode(ProviderMetadata{
+				Issuer:        server.URL,
+				TokenEndpoint: server.URL + "/token",
+				JWKSURI:       server.URL + "/jwks",
+			})
+		case "/jwks":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"keys": []jwk{{
+					Kty: "RSA",
+					Kid: "key-1",
+					Use: "sig",
+					N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+					E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+				}},
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func signTestToken(t *testing.T, key *rsa.PrivateKey, kid string, claims map[string]interface{}) string {
+	t.Helper()
+
+	headerBytes, err := json.Marshal(jwtHeader{Alg: "RS256", Kid: kid})
+	require.NoError(t, err)
+	claimsBytes, err := json.Marshal(claims)
+	require.NoError(t, err)
+
+	signedContent := base64.RawURLEncoding.EncodeToString(headerBytes) + "." + base64.RawURLEncoding.EncodeToString(claimsBytes)
+	digest := sha256.Sum256([]byte(signedContent))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	require.NoError(t, err)
+
+	return signedContent + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func TestVerify(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	server := newTestProvider(t, key)
+	verifier := NewVerifier(server.URL, "cortex")
+
+	validClaims := func() map[string]interface{} {
+		return map[string]interface{}{
+			"iss":   server.URL,
+			"aud":   []string{"other", "cortex"},
+			"exp":   time.Now().Add(time.Hour).Unix(),
+			"email": "alice@example.com",
+		}
+	}
+
+	claims, err := verifier.Verify(signTestToken(t, key, "key-1", validClaims()))
+	require.NoError(t, err)
+	require.Equal(t, "alice@example.com", claims.String("email"))
+
+	wrongAudience := validClaims()
+	wrongAudience["aud"] = "other"
+	_, err = verifier.Verify(signTestToken(t, key, "key-1", wrongAudience))
+	require.Error(t, err)
+
+	wrongIssuer := validClaims()
+	wrongIssuer["iss"] = "https://example.com"
+	_, err = verifier.Verify(signTestToken(t, key, "key-1", wrongIssuer))
+	require.Error(t, err)
+
+	expired := validClaims()
+	expired["exp"] = time.Now().Add(-time.Hour).Unix()
+	_, err = verifier.Verify(signTestToken(t, key, "key-1", expired))
+	require.Error(t, err)
+
+	_, err = verifier.Verify(signTestToken(t, otherKey, "key-1", validClaims()))
+	require.Error(t, err)
+
+	_, err = verifier.Verify(signTestToken(t, key, "key-2", validClaims()))
+	require.Error(t, err)
+
+	_, err = verifier.Verify("not-a-token")
+	require.Error(t, err)
+}
@@ -73,6 +73,12 @@ func IsAlphaNumericDash(s string) bool {
 	return _alphaNumericDashRegex.MatchString(s)
 }
 
+var _envVarNameRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+func IsValidEnvVarName(s string) bool {
+	return _envVarNameRegex.MatchString(s)
+}
+
 // used the evaluated form of
 // https://github.com/docker/distribution/blob/3150937b9f2b1b5b096b2634d0e7c44d4a0f89fb/reference/regexp.go#L68-L70
 var _dockerValidImage = regexp.MustCompile(
@@ -96,3 +102,28 @@ var _ecrPattern = regexp.MustCompile(
 func IsValidECRURL(s string) bool {
 	return _ecrPattern.MatchString(s)
 }
+
+var _awsIAMPrincipalPattern = regexp.MustCompile(
+	`^([0-9]{12}|arn:aws(-cn|-us-gov)?:iam::[0-9]{12}:(root|(role|user)(/[a-zA-Z0-9+=,.@_\-]+)+))$`,
+)
+
+// IsValidAWSIAMPrincipal returns whether s is an aws account id, or the arn of an account (root), iam role, or iam user
+func IsValidAWSIAMPrincipal(s string) bool {
+	return _awsIAMPrincipalPattern.MatchString(s)
+}
+
+var _iamRoleARNPattern = regexp.MustCompile(
+	`^arn:aws(-cn|-us-gov)?:iam::[0-9]{12}:role(/[a-zA-Z0-9+=,.@_\-]+)+$`,
+)
+
+// IsValidIAMRoleARN returns whether s is the arn of an iam role (which may include a path)
+func IsValidIAMRoleARN(s string) bool {
+	return _iamRoleARNPattern.MatchString(s)
+}
+
+var _linuxCapabilityPattern = regexp.MustCompile(`^[A-Z][A-Z0-9_]*$`)
+
+// IsValidLinuxCapability returns whether s has the form of a linux capability name without the CAP_ prefix (e.g. NET_RAW), or is ALL
+func IsValidLinuxCapability(s string) bool {
+	return _linuxCapabilityPattern.MatchString(s)
+}
@@ -293,6 +293,50 @@ func TestAlphaNumericDashUnderscoreRegex(t *testing.T) {
 	}
 }
 
+func TestEnvVarNameRegex(t *testing.T) {
+	testcases := []regexpMatch{
+		{
+			input: "DATASET_PATH",
+			match: true,
+		},
+		{
+			input: "_private",
+			match: true,
+		},
+		{
+			input: "learningRate2",
+			match: true,
+		},
+		{
+			input: "2FAST",
+			match: false,
+		},
+		{
+			input: "dataset-path",
+			match: false,
+		},
+		{
+			input: "dataset.path",
+			match: false,
+		},
+		{
+			input: "KEY=VALUE",
+			match: false,
+		},
+		{
+			input: "",
+			match: false,
+		},
+	}
+
+	for i := range testcases {
+		match := _envVarNameRegex.MatchString(testcases[i].input)
+		if match != testcases[i].match {
+			t.Errorf("No match for %q", testcases[i].input)
+		}
+	}
+}
+
 func TestValidDockerImage(t *testing.T) {
 	testcases := []regexpMatch{
 		{
@@ -657,3 +701,127 @@ func TestValidECR(t *testing.T) {
 		}
 	}
 }
+
+func TestValidAWSIAMPrincipal(t *testing.T) {
+	testcases := []regexpMatch{
+		{
+			input: "",
+			match: false,
+		},
+		{
+			input: "123456789012",
+			match: true,
+		},
+		{
+			input: "12345678901",
+			match: false,
+		},
+		{
+			input: "arn:aws:iam::123456789012:root",
+			match: true,
+		},
+		{
+			input: "arn:aws:iam::123456789012:role/my-role",
+			match: true,
+		},
+		{
+			input: "arn:aws:iam::123456789012:role/service-role/my-role",
+			match: true,
+		},
+		{
+			input: "arn:aws-cn:iam::123456789012:user/my.user@example.com",
+			match: true,
+		},
+		{
+			input: "arn:aws:iam::123456789012:role/",
+			match: false,
+		},
+		{
+			input: "arn:aws:sts::123456789012:assumed-role/my-role/session",
+			match: false,
+		},
+		{
+			input: "arn:aws:iam::123456789012:group/my-group",
+			match: false,
+		},
+	}
+
+	for i := range testcases {
+		match := _awsIAMPrincipalPattern.MatchString(testcases[i].input)
+		if match != testcases[i].match {
+			t.Errorf("No match for %q", testcases[i].input)
+		}
+	}
+}
+
+func TestValidIAMRoleARN(t *testing.T) {
+	testcases := []regexpMatch{
+		{
+			input: "",
+			match: false,
+		},
+		{
+			input: "arn:aws:iam::123456789012:role/my-role",
+			match: true,
+		},
+		{
+			input: "arn:aws-cn:iam::123456789012:role/service-role/my-role",
+			match: true,
+		},
+		{
+			input: "arn:aws:iam::123456789012:role/",
+			match: false,
+		},
+		{
+			input: "arn:aws:iam::123456789012:user/my-user",
+			match: false,
+		},
+		{
+			input: "arn:aws:sts::123456789012:assumed-role/my-role/session",
+			match: false,
+		},
+	}
+
+	for i := range testcases {
+		match := _iamRoleARNPattern.MatchString(testcases[i].input)
+		if match != testcases[i].match {
+			t.Errorf("No match for %q", testcases[i].input)
+		}
+	}
+}
+
+func TestValidLinuxCapability(t *testing.T) {
+	testcases := []regexpMatch{
+		{
+			input: "",
+			match: false,
+		},
+		{
+			input: "NET_RAW",
+			match: true,
+		},
+		{
+			input: "ALL",
+			match: true,
+		},
+		{
+			input: "net_raw",
+			match: false,
+		},
+		{
+			input: "NET RAW",
+			match: false,
+		},
+		{
+			input: "_NET_RAW",
+			match: false,
+		},
+	}
+
+	for i := range testcases {
+		match := _linuxCapabilityPattern.MatchString(testcases[i].input)
+		if match != testcases[i].match {
+			t.Errorf("No match for %q", testcases[i].input)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package structs
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+type ChangeType string
+
+const (
+	ChangeAdded    ChangeType = "added"
+	ChangeRemoved  ChangeType = "removed"
+	ChangeModified ChangeType = "modified"
+)
+
+// Change describes a single difference between two objects; Path is in the form "pod.containers[0].image"
+type Change struct {
+	Path string      `json:"path" yaml:"path"`
+	Type ChangeType  `json:"type" yaml:"type"`
+	Old  interface{} `json:"old,omitempty" yaml:"old,omitempty"`
+	New  interface{} `json:"new,omitempty" yaml:"new,omitempty"`
+}
+
+// Diff returns the differences between the JSON representations of oldObj and newObj, sorted by path
+func Diff(oldObj, newObj interface{}) ([]Change, error) {
+	oldVal, err := toJSONValue(oldObj)
+	if err != nil {
+		return nil, err
+	}
+	newVal, err := toJSONValue(newObj)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []Change
+	diffValues("", oldVal, newVal, &changes)
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+
+	return changes, nil
+}
+
+func toJSONValue(obj interface{}) (interface{}, error) {
+	jsonBytes, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	var val interface{}
+	if err := json.Unmarshal(jsonBytes, &val); err != nil {
+		return nil, err
+	}
+	return val, nil
+}
+
+func diffValues(path string, oldVal, newVal interface{}, changes *[]Change) {
+	if reflect.DeepEqual(oldVal, newVal) || (isEmpty(oldVal) && isEmpty(newVal)) {
+		return
+	}
+
+	if isEmpty(oldVal) {
+		*changes = append(*changes, Change{Path: path, Type: ChangeAdded, New: newVal})
+		return
+	}
+	if isEmpty(newVal) {
+		*changes = append(*changes, Change{Path: path, Type: ChangeRemoved, Old: oldVal})
+		return
+	}
+
+	switch oldTyped := oldVal.(type) {
+	case map[string]interface{}:
+		if newTyped, ok := newVal.(map[string]interface{}); ok {
+			keys := map[string]bool{}
+			for key := range oldTyped {
+				keys[key] = true
+			}
+			for key := range newTyped {
+				keys[key] = true
+			}
+			for key := range keys {
+				diffValues(joinPath(path, key), oldTyped[key], newTyped[key], changes)
+			}
+			return
+		}
+	case []interface{}:
+		if newTyped, ok := newVal.([]interface{}); ok {
+			for i := 0; i < len(oldTyped) || i < len(newTyped); i++ {
+				var oldElem, newElem interface{}
+				if i < len(oldTyped) {
+					oldElem = oldTyped[i]
+				}
+				if i < len(newTyped) {
+					newElem = newTyped[i]
+				}
+				diffValues(fmt.Sprintf("%s[%d]", path, i), oldElem, newElem, changes)
+			}
+			return
+		}
+	}
+
+	*changes = append(*changes, Change{Path: path, Type: ChangeModified, Old: oldVal, New: newVal})
+}
+
+// null, empty maps and empty lists are considered to be unset
+func isEmpty(val interface{}) bool {
+	switch typed := val.(type) {
+	case nil:
+		return true
+	case map[string]interface{}:
+		return len(typed) == 0
+	case []interface{}:
+		return len(typed) == 0
+	}
+	return false
+}
+
+func joinPath(path string, key string) string {
+	if strings.ContainsAny(key, ".[]") {
+		return fmt.Sprintf("%s[%q]", path, key)
+	}
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package structs
+
+import (
+	"testing"
+
+	"github.com/cortexlabs/cortex/pkg/lib/pointer"
+	"github.com/stretchr/testify/require"
+)
+
+type diffContainer struct {
+	Name  string            `json:"name"`
+	Image string            `json:"image"`
+	Env   map[string]string `json:"env"`
+}
+
+type diffSpec struct {
+	Name       string           `json:"name"`
+	Replicas   *int32           `json:"replicas"`
+	Containers []*diffContainer `json:"containers"`
+}
+
+func TestDiff(t *testing.T) {
+	t.Parallel()
+
+	oldSpec := diffSpec{
+		Name:     "my-api",
+		Replicas: pointer.Int32(1),
+		Containers: []*diffContainer{
+			{Name: "api", Image: "app:v1", Env: map[string]string{"A": "1", "B": "2"}},
+		},
+	}
+
+	changes, err := Diff(oldSpec, oldSpec)
+	require.NoError(t, err)
+	require.Empty(t, changes)
+
+	newSpec := diffSpec{
+		Name: "my-api",
+		Containers: []*diffContainer{
+			{Name: "api", Image: "app:v2", Env: map[string]string{"A": "1", "C": "3"}},
+			{Name: "sidecar", Image: "proxy:v1"},
+		},
+	}
+
+	changes, err = Diff(oldSpec, newSpec)
+	require.NoError(t, err)
+	require.Equal(t, []Change{
+		{Path: "containers[0].env.B", Type: ChangeRemoved, Old: "2"},
+		{Path: "containers[0].env.C", Type: ChangeAdded, New: "3"},
+		{Path: "containers[0].image", Type: ChangeModified, Old: "app:v1", New: "app:v2"},
+		{Path: "containers[1]", Type: ChangeAdded, New: map[string]interface{}{"name": "sidecar", "image": "proxy:v1", "env": nil}},
+		{Path: "replicas", Type: ChangeRemoved, Old: float64(1)},
+	}, changes)
+}
+
+func TestDiffEmptyValues(t *testing.T) {
+	t.Parallel()
+
+	changes, err := Diff(map[string]interface{}{"env": nil}, map[string]interface{}{"env": map[string]string{}})
+	require.NoError(t, err)
+	require.Empty(t, changes)
+
+	changes, err = Diff(map[string]interface{}{"a.b": 1}, map[string]interface{}{"a.b": 2})
+	require.NoError(t, err)
+	require.Equal(t, []Change{{Path: `["a.b"]`, Type: ChangeModified, Old: float64(1), New: float64(2)}}, changes)
+}
@@ -30,6 +30,7 @@ const (
 	ErrEndpoint            = "urls.endpoint"
 	ErrEndpointEmptyPath   = "urls.endpoint_empty_path"
 	ErrEndpointDoubleSlash = "urls.endpoint_double_slash"
+	ErrDomainName          = "urls.domain_name"
 )
 
 func ErrorInvalidURL(provided string) error {
@@ -73,3 +74,10 @@ func ErrorEndpointDoubleSlash(provided string) error {
 		Message: fmt.Sprintf("%s cannot contain adjacent slashes", s.UserStr(provided)),
 	})
 }
+
+func ErrorDomainName(provided string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrDomainName,
+		Message: fmt.Sprintf("%s is not a valid domain name (e.g. api.example.com)", s.UserStr(provided)),
+	})
+}
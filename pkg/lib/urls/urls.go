@@ -29,6 +29,7 @@ var (
 	_dns1123Regex   = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
 	_endpointRegex  = regexp.MustCompile(`^[a-zA-Z0-9_\-\./]*$`)
 	_urlQParamRegex = regexp.MustCompile(`(https?://.*)\?[^:\s]*`)
+	_domainRegex    = regexp.MustCompile(`^([a-z0-9]([-a-z0-9]*[a-z0-9])?\.)+[a-z]([-a-z0-9]*[a-z0-9])?$`)
 )
 
 func Parse(rawurl string) (*url.URL, error) {
@@ -62,6 +63,22 @@ func CheckDNS1123(str string) error {
 	return nil
 }
 
+// ValidateDomainName lower-cases the provided domain name (trimming a trailing dot), and checks that it is a fully qualified domain name
+func ValidateDomainName(str string) (string, error) {
+	domain := strings.TrimSuffix(strings.ToLower(str), ".")
+	if len(domain) > 253 || !_domainRegex.MatchString(domain) {
+		return "", ErrorDomainName(str)
+	}
+
+	for _, label := range strings.Split(domain, ".") {
+		if len(label) > 63 {
+			return "", ErrorDomainName(str)
+		}
+	}
+
+	return domain, nil
+}
+
 func ValidateEndpointAllowEmptyPath(str string) (string, error) {
 	if !_endpointRegex.MatchString(str) {
 		return "", ErrorEndpoint(str)
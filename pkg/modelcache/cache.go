@@ -0,0 +1,337 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package modelcache
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/hash"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	"go.uber.org/zap"
+)
+
+const (
+	_evictionLockFileName = ".eviction.lock"
+	_lockFileSuffix       = ".lock"
+	_tmpDirPrefix         = ".tmp-"
+)
+
+// Downloader fetches the contents of an S3 directory
+type Downloader interface {
+	// Size returns the total size of the files in the S3 directory, in bytes
+	Size(s3Path string) (int64, error)
+	// Download downloads the files in the S3 directory into localDir (which must not exist yet)
+	Download(s3Path string, localDir string) error
+}
+
+type Config struct {
+	// Dir is the directory on the node which holds the cache
+	Dir string
+	// MinFreeDiskRatio is the fraction of the node's disk which least recently used models are evicted to keep free when a model is added
+	MinFreeDiskRatio float64
+	// EvictionGracePeriod is how long a model is protected from eviction after it was last fetched
+	EvictionGracePeriod time.Duration
+	// DiskUsage returns the total and available bytes of the filesystem which contains dir (defaults to statfs)
+	DiskUsage func(dir string) (total uint64, available uint64, err error)
+}
+
+type FetchResult struct {
+	// Path is the directory in which the model is cached
+	Path             string
+	Hit              bool
+	DownloadDuration time.Duration
+	DownloadedBytes  int64
+	NumEvicted       int
+}
+
+// Cache stores S3 directories on the node's disk, so that they are downloaded once per node and shared across the pods which run on it.
+// Concurrent fetches (including from different pods) are coordinated with file locks in the cache directory.
+type Cache struct {
+	config     Config
+	downloader Downloader
+	logger     *zap.SugaredLogger
+}
+
+func New(config Config, downloader Downloader, logger *zap.SugaredLogger) *Cache {
+	if config.DiskUsage == nil {
+		config.DiskUsage = statfsDiskUsage
+	}
+
+	return &Cache{
+		config:     config,
+		downloader: downloader,
+		logger:     logger,
+	}
+}
+
+// Key returns the name of the directory (within the cache directory) in which the S3 directory is cached
+func Key(s3Path string) string {
+	return hash.String(s.EnsureSuffix(s3Path, "/"))[:32]
+}
+
+// Fetch returns the local directory which contains the S3 directory, downloading it if it isn't already cached on the node
+func (c *Cache) Fetch(s3Path string) (*FetchResult, error) {
+	if err := os.MkdirAll(c.config.Dir, 0755); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	key := Key(s3Path)
+	entryPath := filepath.Join(c.config.Dir, key)
+
+	// replicas which start at the same time wait for the first one to download the model
+	unlock, err := lockFile(filepath.Join(c.config.Dir, "."+key+_lockFileSuffix), true)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	if _, err := os.Stat(entryPath); err == nil {
+		if err := touch(entryPath); err != nil {
+			return nil, err
+		}
+		return &FetchResult{Path: entryPath, Hit: true}, nil
+	}
+
+	size, err := c.downloader.Size(s3Path)
+	if err != nil {
+		return nil, err
+	}
+
+	numEvicted, err := c.evict(size, key)
+	if err != nil {
+		return nil, err
+	}
+
+	// models are downloaded into a temporary directory and then renamed, so that partial downloads are never used
+	tmpPath := filepath.Join(c.config.Dir, _tmpDirPrefix+key)
+	if err := os.RemoveAll(tmpPath); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	start := time.Now()
+	if err := c.downloader.Download(s3Path, tmpPath); err != nil {
+		_ = os.RemoveAll(tmpPath)
+		return nil, err
+	}
+	downloadDuration := time.Since(start)
+
+	if err := os.Rename(tmpPath, entryPath); err != nil {
+		_ = os.RemoveAll(tmpPath)
+		return nil, errors.WithStack(err)
+	}
+	if err := touch(entryPath); err != nil {
+		return nil, err
+	}
+
+	return &FetchResult{
+		Path:             entryPath,
+		Hit:              false,
+		DownloadDuration: downloadDuration,
+		DownloadedBytes:  size,
+		NumEvicted:       numEvicted,
+	}, nil
+}
+
+// Remove deletes the S3 directory from the node's cache (waiting for in-progress fetches of it to finish), and returns whether it was cached
+func (c *Cache) Remove(s3Path string) (bool, error) {
+	key := Key(s3Path)
+	entryPath := filepath.Join(c.config.Dir, key)
+
+	if _, err := os.Stat(entryPath); err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, errors.WithStack(err)
+	}
+
+	unlock, err := lockFile(filepath.Join(c.config.Dir, "."+key+_lockFileSuffix), true)
+	if err != nil {
+		return false, err
+	}
+	defer unlock()
+
+	if err := os.RemoveAll(entryPath); err != nil {
+		return false, errors.WithStack(err)
+	}
+
+	return true, nil
+}
+
+type entry struct {
+	key      string
+	path     string
+	lastUsed time.Time
+}
+
+// evict removes the least recently used models until there is room for a new model of the given size (while keeping MinFreeDiskRatio of the disk free);
+// models which were fetched within EvictionGracePeriod, and the model which is being fetched, are not evicted
+func (c *Cache) evict(size int64, fetchingKey string) (int, error) {
+	unlock, err := lockFile(filepath.Join(c.config.Dir, _evictionLockFileName), true)
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+
+	c.removeAbandonedDownloads(fetchingKey)
+
+	total, available, err := c.config.DiskUsage(c.config.Dir)
+	if err != nil {
+		return 0, err
+	}
+
+	required := uint64(size) + uint64(c.config.MinFreeDiskRatio*float64(total))
+	if available >= required {
+		return 0, nil
+	}
+
+	entries, err := c.entries()
+	if err != nil {
+		return 0, err
+	}
+
+	numEvicted := 0
+	for _, entry := range entries {
+		if available >= required {
+			break
+		}
+		if entry.key == fetchingKey || time.Since(entry.lastUsed) < c.config.EvictionGracePeriod {
+			continue
+		}
+
+		if err := os.RemoveAll(entry.path); err != nil {
+			return numEvicted, errors.WithStack(err)
+		}
+		numEvicted++
+		c.logger.Infow("evicted model from the node's model cache", "key", entry.key, "last_used", entry.lastUsed)
+
+		_, available, err = c.config.DiskUsage(c.config.Dir)
+		if err != nil {
+			return numEvicted, err
+		}
+	}
+
+	if available < uint64(size) {
+		return numEvicted, ErrorInsufficientDiskSpace(size, available)
+	}
+	if available < required {
+		c.logger.Warnw("unable to evict enough models from the node's model cache to keep the minimum amount of free disk space", "available_bytes", available, "required_bytes", required)
+	}
+
+	return numEvicted, nil
+}
+
+// entries returns the cached models, ordered from least to most recently used
+func (c *Cache) entries() ([]entry, error) {
+	dirEntries, err := os.ReadDir(c.config.Dir)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	var entries []entry
+	for _, dirEntry := range dirEntries {
+		if !dirEntry.IsDir() || strings.HasPrefix(dirEntry.Name(), ".") {
+			continue
+		}
+		info, err := dirEntry.Info()
+		if err != nil {
+			continue // the entry was removed concurrently
+		}
+		entries = append(entries, entry{
+			key:      dirEntry.Name(),
+			path:     filepath.Join(c.config.Dir, dirEntry.Name()),
+			lastUsed: info.ModTime(),
+		})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].lastUsed.Before(entries[j].lastUsed)
+	})
+
+	return entries, nil
+}
+
+// removeAbandonedDownloads deletes temporary directories left behind by pods which were terminated during a download
+func (c *Cache) removeAbandonedDownloads(fetchingKey string) {
+	dirEntries, err := os.ReadDir(c.config.Dir)
+	if err != nil {
+		return
+	}
+
+	for _, dirEntry := range dirEntries {
+		if !strings.HasPrefix(dirEntry.Name(), _tmpDirPrefix) {
+			continue
+		}
+		key := strings.TrimPrefix(dirEntry.Name(), _tmpDirPrefix)
+		if key == fetchingKey {
+			continue
+		}
+
+		// if the model's lock can be acquired, no pod is downloading it
+		unlock, err := lockFile(filepath.Join(c.config.Dir, "."+key+_lockFileSuffix), false)
+		if err != nil {
+			continue
+		}
+		_ = os.RemoveAll(filepath.Join(c.config.Dir, dirEntry.Name()))
+		unlock()
+	}
+}
+
+// lockFile acquires an exclusive lock on the file (creating it if necessary); if wait is false, an error is returned if the lock is held elsewhere
+func lockFile(path string, wait bool) (func(), error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	how := syscall.LOCK_EX
+	if !wait {
+		how |= syscall.LOCK_NB
+	}
+
+	if err := syscall.Flock(int(file.Fd()), how); err != nil {
+		file.Close()
+		return nil, errors.WithStack(err)
+	}
+
+	return func() {
+		_ = syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+		file.Close()
+	}, nil
+}
+
+// the modification time of a cached model's directory is used to track when it was last used
+func touch(path string) error {
+	now := time.Now()
+	if err := os.Chtimes(path, now, now); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+func statfsDiskUsage(dir string) (uint64, uint64, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(dir, &stat); err != nil {
+		return 0, 0, errors.WithStack(err)
+	}
+	return stat.Blocks * uint64(stat.Bsize), stat.Bavail * uint64(stat.Bsize), nil
+}
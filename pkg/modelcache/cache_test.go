@@ -0,0 +1,234 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package modelcache_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/modelcache"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const _diskSize = 1000
+
+type fakeDownloader struct {
+	sizes       map[string]int64
+	numDownload int32
+	fail        bool
+}
+
+func (d *fakeDownloader) Size(s3Path string) (int64, error) {
+	return d.sizes[s3Path], nil
+}
+
+func (d *fakeDownloader) Download(s3Path string, localDir string) error {
+	atomic.AddInt32(&d.numDownload, 1)
+	if err := os.MkdirAll(localDir, 0755); err != nil {
+		return err
+	}
+	if d.fail {
+		_ = os.WriteFile(filepath.Join(localDir, "partial"), []byte("x"), 0644)
+		return errors.ErrorUnexpected("download failed")
+	}
+	time.Sleep(10 * time.Millisecond)
+	return os.WriteFile(filepath.Join(localDir, "model"), []byte(strings.Repeat("x", int(d.sizes[s3Path]))), 0644)
+}
+
+// fakeDiskUsage simulates a disk of _diskSize bytes which only contains the cache
+func fakeDiskUsage(dir string) (uint64, uint64, error) {
+	var used uint64
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			used += uint64(info.Size())
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	return _diskSize, _diskSize - used, nil
+}
+
+func newCache(t *testing.T, downloader *fakeDownloader, evictionGracePeriod time.Duration) (*modelcache.Cache, string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	cache := modelcache.New(modelcache.Config{
+		Dir:                 dir,
+		MinFreeDiskRatio:    0,
+		EvictionGracePeriod: evictionGracePeriod,
+		DiskUsage:           fakeDiskUsage,
+	}, downloader, zap.NewNop().Sugar())
+
+	return cache, dir
+}
+
+func TestFetch_DownloadsOnce(t *testing.T) {
+	t.Parallel()
+
+	downloader := &fakeDownloader{sizes: map[string]int64{"s3://bucket/model/1": 100}}
+	cache, dir := newCache(t, downloader, 0)
+
+	result, err := cache.Fetch("s3://bucket/model/1")
+	require.NoError(t, err)
+	require.False(t, result.Hit)
+	require.Equal(t, int64(100), result.DownloadedBytes)
+	require.Equal(t, filepath.Join(dir, modelcache.Key("s3://bucket/model/1")), result.Path)
+	require.FileExists(t, filepath.Join(result.Path, "model"))
+
+	result, err = cache.Fetch("s3://bucket/model/1/")
+	require.NoError(t, err)
+	require.True(t, result.Hit)
+	require.Equal(t, int32(1), atomic.LoadInt32(&downloader.numDownload))
+}
+
+func TestFetch_ConcurrentFetchesDownloadOnce(t *testing.T) {
+	t.Parallel()
+
+	downloader := &fakeDownloader{sizes: map[string]int64{"s3://bucket/model/1": 100}}
+	cache, _ := newCache(t, downloader, 0)
+
+	var wg sync.WaitGroup
+	var numHits int32
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result, err := cache.Fetch("s3://bucket/model/1")
+			require.NoError(t, err)
+			if result.Hit {
+				atomic.AddInt32(&numHits, 1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	require.Equal(t, int32(1), atomic.LoadInt32(&downloader.numDownload))
+	require.Equal(t, int32(4), atomic.LoadInt32(&numHits))
+}
+
+func TestFetch_EvictsLeastRecentlyUsed(t *testing.T) {
+	t.Parallel()
+
+	downloader := &fakeDownloader{sizes: map[string]int64{
+		"s3://bucket/a": 400,
+		"s3://bucket/b": 400,
+		"s3://bucket/c": 400,
+	}}
+	cache, _ := newCache(t, downloader, time.Minute)
+
+	resultA, err := cache.Fetch("s3://bucket/a")
+	require.NoError(t, err)
+	resultB, err := cache.Fetch("s3://bucket/b")
+	require.NoError(t, err)
+
+	// a was used before b, and neither was used within the eviction grace period
+	require.NoError(t, os.Chtimes(resultA.Path, time.Now().Add(-2*time.Hour), time.Now().Add(-2*time.Hour)))
+	require.NoError(t, os.Chtimes(resultB.Path, time.Now().Add(-time.Hour), time.Now().Add(-time.Hour)))
+
+	resultC, err := cache.Fetch("s3://bucket/c")
+	require.NoError(t, err)
+	require.Equal(t, 1, resultC.NumEvicted)
+
+	require.NoDirExists(t, resultA.Path)
+	require.DirExists(t, resultB.Path)
+	require.DirExists(t, resultC.Path)
+}
+
+func TestFetch_RecentlyUsedModelsAreNotEvicted(t *testing.T) {
+	t.Parallel()
+
+	downloader := &fakeDownloader{sizes: map[string]int64{
+		"s3://bucket/a": 400,
+		"s3://bucket/b": 400,
+		"s3://bucket/c": 400,
+	}}
+	cache, _ := newCache(t, downloader, time.Hour)
+
+	resultA, err := cache.Fetch("s3://bucket/a")
+	require.NoError(t, err)
+	resultB, err := cache.Fetch("s3://bucket/b")
+	require.NoError(t, err)
+
+	_, err = cache.Fetch("s3://bucket/c")
+	require.Error(t, err)
+	require.Equal(t, modelcache.ErrInsufficientDiskSpace, errors.GetKind(err))
+
+	require.DirExists(t, resultA.Path)
+	require.DirExists(t, resultB.Path)
+}
+
+func TestFetch_FailedDownloadIsNotCached(t *testing.T) {
+	t.Parallel()
+
+	downloader := &fakeDownloader{sizes: map[string]int64{"s3://bucket/model/1": 100}, fail: true}
+	cache, dir := newCache(t, downloader, 0)
+
+	_, err := cache.Fetch("s3://bucket/model/1")
+	require.Error(t, err)
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	for _, entry := range entries {
+		require.False(t, entry.IsDir(), entry.Name())
+	}
+
+	downloader.fail = false
+	result, err := cache.Fetch("s3://bucket/model/1")
+	require.NoError(t, err)
+	require.False(t, result.Hit)
+	require.Equal(t, int32(2), atomic.LoadInt32(&downloader.numDownload))
+}
+
+func TestRemove(t *testing.T) {
+	t.Parallel()
+
+	downloader := &fakeDownloader{sizes: map[string]int64{"s3://bucket/model/1": 100, "s3://bucket/model/2": 100}}
+	cache, _ := newCache(t, downloader, time.Hour)
+
+	result1, err := cache.Fetch("s3://bucket/model/1")
+	require.NoError(t, err)
+	result2, err := cache.Fetch("s3://bucket/model/2")
+	require.NoError(t, err)
+
+	removed, err := cache.Remove("s3://bucket/model/1/")
+	require.NoError(t, err)
+	require.True(t, removed)
+	require.NoDirExists(t, result1.Path)
+	require.DirExists(t, result2.Path)
+
+	removed, err = cache.Remove("s3://bucket/model/1")
+	require.NoError(t, err)
+	require.False(t, removed)
+
+	// removed models are downloaded again when they are next fetched
+	result1, err = cache.Fetch("s3://bucket/model/1")
+	require.NoError(t, err)
+	require.False(t, result1.Hit)
+	require.Equal(t, int32(3), atomic.LoadInt32(&downloader.numDownload))
+}
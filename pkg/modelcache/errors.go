@@ -0,0 +1,46 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package modelcache
+
+import (
+	"fmt"
+
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+)
+
+const (
+	ErrModelNotFound         = "modelcache.model_not_found"
+	ErrInsufficientDiskSpace = "modelcache.insufficient_disk_space"
+)
+
+func ErrorModelNotFound(s3Path string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrModelNotFound,
+		Message: fmt.Sprintf("no files were found in %s", s3Path),
+	})
+}
+
+func ErrorInsufficientDiskSpace(requiredBytes int64, availableBytes uint64) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrInsufficientDiskSpace,
+		Message: fmt.Sprintf("the model requires %s of disk space, but only %s is available on the node (after evicting the models which can be evicted)", mibStr(uint64(requiredBytes)), mibStr(availableBytes)),
+	})
+}
+
+func mibStr(bytes uint64) string {
+	return fmt.Sprintf("%d MiB", bytes>>20)
+}
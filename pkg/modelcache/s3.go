@@ -0,0 +1,58 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package modelcache
+
+import (
+	"github.com/cortexlabs/cortex/pkg/lib/aws"
+)
+
+// S3Downloader downloads models using the node's AWS credentials
+type S3Downloader struct {
+	awsClient *aws.Client
+}
+
+func NewS3Downloader(awsClient *aws.Client) *S3Downloader {
+	return &S3Downloader{
+		awsClient: awsClient,
+	}
+}
+
+func (d *S3Downloader) Size(s3Path string) (int64, error) {
+	objects, err := d.awsClient.ListS3PathDir(s3Path, false, nil, nil)
+	if err != nil {
+		return 0, err
+	}
+	if len(objects) == 0 {
+		return 0, ErrorModelNotFound(s3Path)
+	}
+
+	var size int64
+	for _, object := range objects {
+		if object.Size != nil {
+			size += *object.Size
+		}
+	}
+	return size, nil
+}
+
+func (d *S3Downloader) Download(s3Path string, localDir string) error {
+	bucket, key, err := aws.SplitS3Path(s3Path)
+	if err != nil {
+		return err
+	}
+	return d.awsClient.DownloadDirFromS3(bucket, key, localDir, true, nil)
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package endpoints
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/cortexlabs/cortex/pkg/operator/resources"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/gorilla/mux"
+)
+
+func CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	keyName := mux.Vars(r)["keyName"]
+
+	var apiNames []string
+	if apisStr := getOptionalQParam("apis", r); apisStr != "" {
+		apiNames = strings.Split(apisStr, ",")
+	}
+
+	response, err := resources.CreateAPIKey(keyName, apiNames)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	respondJSON(w, r, response)
+}
+
+func ListAPIKeys(w http.ResponseWriter, r *http.Request) {
+	response, err := resources.ListAPIKeys()
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	respondJSON(w, r, response)
+}
+
+func RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	keyName := mux.Vars(r)["keyName"]
+
+	msg, err := resources.RevokeAPIKey(keyName)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	respondJSON(w, r, schema.RevokeAPIKeyResponse{
+		Message: msg,
+	})
+}
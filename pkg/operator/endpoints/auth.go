@@ -0,0 +1,104 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package endpoints
+
+import (
+	"net/http"
+
+	"github.com/cortexlabs/cortex/pkg/config"
+	"github.com/cortexlabs/cortex/pkg/operator/resources"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/rbac"
+	"github.com/gorilla/mux"
+)
+
+// GetOIDCConfig is not authenticated, since it's used by `cortex login` to determine which identity provider to log in with
+func GetOIDCConfig(w http.ResponseWriter, r *http.Request) {
+	if config.ClusterConfig.OIDC == nil {
+		respondError(w, r, ErrorOIDCNotConfigured())
+		return
+	}
+
+	respondJSON(w, r, schema.OIDCConfigResponse{
+		IssuerURL: config.ClusterConfig.OIDC.IssuerURL,
+		ClientID:  config.ClusterConfig.OIDC.ClientID,
+		Scopes:    config.ClusterConfig.OIDC.Scopes,
+	})
+}
+
+func WhoAmI(w http.ResponseWriter, r *http.Request) {
+	response, err := resources.WhoAmI(getCaller(r))
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	respondJSON(w, r, response)
+}
+
+func CreateRoleBinding(w http.ResponseWriter, r *http.Request) {
+	bindingName := mux.Vars(r)["bindingName"]
+
+	roleStr, err := getRequiredQueryParam("role", r)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+	role, err := rbac.ParseRole(roleStr)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	subject, err := getRequiredQueryParam("subject", r)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	response, err := resources.CreateRoleBinding(bindingName, role, subject, getCaller(r))
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	respondJSON(w, r, response)
+}
+
+func ListRoleBindings(w http.ResponseWriter, r *http.Request) {
+	response, err := resources.ListRoleBindings()
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	respondJSON(w, r, response)
+}
+
+func DeleteRoleBinding(w http.ResponseWriter, r *http.Request) {
+	bindingName := mux.Vars(r)["bindingName"]
+
+	msg, err := resources.DeleteRoleBinding(bindingName, getCaller(r))
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	respondJSON(w, r, schema.DeleteRoleBindingResponse{
+		Message: msg,
+	})
+}
@@ -18,16 +18,63 @@ package endpoints
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/cortexlabs/cortex/pkg/operator/resources"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
 	"github.com/gorilla/mux"
 )
 
 func Delete(w http.ResponseWriter, r *http.Request) {
 	apiName := mux.Vars(r)["apiName"]
 	keepCache := getOptionalBoolQParam("keepCache", false, r)
+	keepVolumes := getOptionalBoolQParam("keepVolumes", true, r)
 
-	response, err := resources.DeleteAPI(apiName, keepCache)
+	response, err := resources.DeleteAPI(apiName, keepCache, keepVolumes)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+	respondJSON(w, r, response)
+}
+
+func DeleteAPIs(w http.ResponseWriter, r *http.Request) {
+	all := getOptionalBoolQParam("all", false, r)
+	kindStr := getOptionalQParam("kind", r)
+	selector := getOptionalQParam("selector", r)
+	keepCache := getOptionalBoolQParam("keepCache", false, r)
+	keepVolumes := getOptionalBoolQParam("keepVolumes", true, r)
+	dryRun := getOptionalBoolQParam("dryRun", false, r)
+
+	// restricts the deletion to the apis which the user confirmed (apis which match the filters but aren't listed are not deleted)
+	var apiNames []string
+	if namesStr := getOptionalQParam("names", r); namesStr != "" {
+		apiNames = strings.Split(namesStr, ",")
+	}
+
+	// require the filters to be explicit to avoid accidentally deleting all apis
+	if !all && kindStr == "" && selector == "" {
+		respondError(w, r, ErrorAnyQueryParamRequired("all", "kind", "selector"))
+		return
+	}
+
+	// the project only narrows down the other filters, so it doesn't count as one of them
+	project, err := getProjectQParam(r)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	kind := userconfig.UnknownKind
+	if kindStr != "" {
+		kind = userconfig.KindFromString(kindStr)
+		if kind == userconfig.UnknownKind {
+			respondError(w, r, ErrorQueryParamInvalid("kind", kindStr, userconfig.KindStrings()...))
+			return
+		}
+	}
+
+	response, err := resources.DeleteAPIs(project, kind, selector, apiNames, keepCache, keepVolumes, dryRun)
 	if err != nil {
 		respondError(w, r, err)
 		return
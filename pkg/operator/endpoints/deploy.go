@@ -22,10 +22,13 @@ import (
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
 	"github.com/cortexlabs/cortex/pkg/lib/files"
 	"github.com/cortexlabs/cortex/pkg/operator/resources"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
 )
 
 func Deploy(w http.ResponseWriter, r *http.Request) {
 	force := getOptionalBoolQParam("force", false, r)
+	skipScan := getOptionalBoolQParam("skipScan", false, r)
+	project := getOptionalQParam("project", r)
 
 	configFileName, err := getRequiredQueryParam("configFileName", r)
 	if err != nil {
@@ -42,7 +45,17 @@ func Deploy(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	response, err := resources.Deploy(configFileName, configBytes, force)
+	var gitSource *userconfig.GitSource
+	if gitCommit := getOptionalQParam("gitCommit", r); gitCommit != "" {
+		gitSource = &userconfig.GitSource{
+			Repository: getOptionalQParam("gitRepository", r),
+			Path:       getOptionalQParam("gitPath", r),
+			Ref:        getOptionalQParam("gitRef", r),
+			Commit:     gitCommit,
+		}
+	}
+
+	response, err := resources.Deploy(configFileName, configBytes, project, force, skipScan, gitSource)
 	if err != nil {
 		respondError(w, r, err)
 		return
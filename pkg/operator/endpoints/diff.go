@@ -0,0 +1,50 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package endpoints
+
+import (
+	"net/http"
+
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/files"
+	"github.com/cortexlabs/cortex/pkg/operator/resources"
+)
+
+func Diff(w http.ResponseWriter, r *http.Request) {
+	configFileName, err := getRequiredQueryParam("configFileName", r)
+	if err != nil {
+		respondError(w, r, errors.WithStack(err))
+		return
+	}
+
+	configBytes, err := files.ReadReqFile(r, "config")
+	if err != nil {
+		respondError(w, r, errors.WithStack(err))
+		return
+	} else if len(configBytes) == 0 {
+		respondError(w, r, ErrorFormFileMustBeProvided("config"))
+		return
+	}
+
+	response, err := resources.Diff(configFileName, configBytes, getOptionalQParam("project", r))
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	respondJSON(w, r, response)
+}
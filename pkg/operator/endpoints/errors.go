@@ -22,6 +22,8 @@ import (
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
 	s "github.com/cortexlabs/cortex/pkg/lib/strings"
 	"github.com/cortexlabs/cortex/pkg/operator/operator"
+	"github.com/cortexlabs/cortex/pkg/rbac"
+	"github.com/cortexlabs/cortex/pkg/types/clusterconfig"
 )
 
 const (
@@ -32,11 +34,20 @@ const (
 	ErrFormFileMustBeProvided = "endpoints.form_file_must_be_provided"
 	ErrAuthInvalid            = "endpoints.auth_invalid"
 	ErrAuthOtherAccount       = "endpoints.auth_other_account"
+	ErrAuthInvalidAPIKey      = "endpoints.auth_invalid_api_key"
+	ErrForbidden              = "endpoints.forbidden"
+	ErrOIDCNotConfigured      = "endpoints.oidc_not_configured"
+	ErrOIDCClaimMissing       = "endpoints.oidc_claim_missing"
 	ErrQueryParamRequired     = "endpoints.query_param_required"
+	ErrQueryParamInvalid      = "endpoints.query_param_invalid"
+	ErrQueryParamMalformed    = "endpoints.query_param_malformed"
 	ErrPathParamRequired      = "endpoints.path_param_required"
 	ErrAnyQueryParamRequired  = "endpoints.any_query_param_required"
 	ErrAnyPathParamRequired   = "endpoints.any_path_param_required"
 	ErrLogsJobIDRequired      = "endpoints.logs_job_id_required"
+	ErrReplicaJobIDRequired   = "endpoints.replica_job_id_required"
+	ErrNoRunningReplicas      = "endpoints.no_running_replicas"
+	ErrContainerNotFound      = "endpoints.container_not_found"
 )
 
 func ErrorAPIVersionMismatch(operatorVersion string, clientVersion string) error {
@@ -92,6 +103,38 @@ func ErrorAuthOtherAccount() error {
 	})
 }
 
+func ErrorAuthInvalidAPIKey() error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrAuthInvalidAPIKey,
+		Message: "invalid api key; run `cortex keys list` to see the existing api keys",
+	})
+}
+
+func ErrorForbidden(caller string, role rbac.Role, requiredRole rbac.Role) error {
+	roleStr := "no role"
+	if role != "" {
+		roleStr = fmt.Sprintf("the %s role", role)
+	}
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrForbidden,
+		Message: fmt.Sprintf("%s has %s, but the %s role is required for this operation; an admin can grant it with `cortex auth bind`", caller, roleStr, requiredRole),
+	})
+}
+
+func ErrorOIDCNotConfigured() error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrOIDCNotConfigured,
+		Message: fmt.Sprintf("oidc login is not configured for this cluster (it can be enabled by adding the %s section to your cluster configuration file)", clusterconfig.OIDCKey),
+	})
+}
+
+func ErrorOIDCClaimMissing(claim string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrOIDCClaimMissing,
+		Message: fmt.Sprintf("the id token does not include the %s claim, which is used as the caller's username", s.UserStr(claim)),
+	})
+}
+
 func ErrorFormFileMustBeProvided(fileName string) error {
 	return errors.WithStack(&errors.Error{
 		Kind:    ErrFormFileMustBeProvided,
@@ -105,6 +148,20 @@ func ErrorQueryParamRequired(param string) error {
 	})
 }
 
+func ErrorQueryParamInvalid(param string, value string, validValues ...string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrQueryParamInvalid,
+		Message: fmt.Sprintf("invalid value for query param %s: %s (valid values: %s)", param, s.UserStr(value), s.UserStrsOr(validValues)),
+	})
+}
+
+func ErrorQueryParamMalformed(param string, value string, reason string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrQueryParamMalformed,
+		Message: fmt.Sprintf("invalid value for query param %s: %s (%s)", param, s.UserStr(value), reason),
+	})
+}
+
 func ErrorPathParamRequired(param string) error {
 	return errors.WithStack(&errors.Error{
 		Kind:    ErrPathParamRequired,
@@ -134,3 +191,31 @@ func ErrorLogsJobIDRequired(resource operator.DeployedResource) error {
 		Message: fmt.Sprintf("job id is required for %s; you can get a list of latest job ids with `cortex get %s` and use `cortex logs %s JOB_ID` to get the logs", resource.UserString(), resource.Name, resource.Name),
 	})
 }
+
+// cliCommand is the cortex command which connects to a replica, e.g. "exec"
+func ErrorReplicaJobIDRequired(resource operator.DeployedResource, cliCommand string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrReplicaJobIDRequired,
+		Message: fmt.Sprintf("job id is required for %s; you can get a list of latest job ids with `cortex get %s` and use `cortex %s %s JOB_ID ...` to connect to one of the job's workers", resource.UserString(), resource.Name, cliCommand, resource.Name),
+	})
+}
+
+func ErrorNoRunningReplicas(resource operator.DeployedResource, replica string) error {
+	if replica != "" {
+		return errors.WithStack(&errors.Error{
+			Kind:    ErrNoRunningReplicas,
+			Message: fmt.Sprintf("replica %s of %s is not currently running (run `cortex get %s` to see its replicas)", replica, resource.UserString(), resource.Name),
+		})
+	}
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrNoRunningReplicas,
+		Message: fmt.Sprintf("there are currently no running replicas of %s", resource.UserString()),
+	})
+}
+
+func ErrorContainerNotFound(container string, replica string, availableContainers []string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrContainerNotFound,
+		Message: fmt.Sprintf("container %s was not found in replica %s; available containers: %s", s.UserStr(container), replica, s.StrsAnd(availableContainers)),
+	})
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package endpoints
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/cortexlabs/cortex/pkg/config"
+	"github.com/cortexlabs/cortex/pkg/lib/sets/strset"
+	"github.com/cortexlabs/cortex/pkg/operator/operator"
+	"github.com/cortexlabs/cortex/pkg/operator/resources"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+	"github.com/cortexlabs/cortex/pkg/workloads"
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+	kcore "k8s.io/api/core/v1"
+)
+
+var _defaultExecCommand = []string{"/bin/sh"}
+
+// containers added by cortex, which are skipped when choosing the default container to exec into
+var _cortexContainerNames = strset.New(workloads.ProxyContainerName, workloads.DequeuerContainerName, workloads.GatewayContainerName)
+
+func Exec(w http.ResponseWriter, r *http.Request) {
+	apiName := mux.Vars(r)["apiName"]
+	jobID := getOptionalQParam("jobID", r)
+	replica := getOptionalQParam("replica", r)
+	containerName := getOptionalQParam("container", r)
+	tty := getOptionalBoolQParam("tty", false, r)
+
+	command := _defaultExecCommand
+	if commandStr := getOptionalQParam("command", r); commandStr != "" {
+		var userCommand []string
+		if err := json.Unmarshal([]byte(commandStr), &userCommand); err != nil || len(userCommand) == 0 {
+			respondError(w, r, ErrorQueryParamMalformed("command", commandStr, "must be a non-empty json array of strings"))
+			return
+		}
+		command = userCommand
+	}
+
+	pod, err := getRunningReplica(apiName, jobID, replica, "exec")
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	if containerName == "" {
+		containerName = defaultExecContainer(pod)
+	}
+	if !podHasContainer(pod, containerName) {
+		respondError(w, r, ErrorContainerNotFound(containerName, pod.Name, podContainerNames(pod)))
+		return
+	}
+
+	upgrader := websocket.Upgrader{}
+	socket, err := upgrader.Upgrade(w, r, http.Header{schema.ReplicaHeader: []string{pod.Name}})
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+	defer socket.Close()
+
+	operator.ExecInReplica(pod.Name, pod.Namespace, containerName, command, tty, socket)
+}
+
+// getRunningReplica returns the running replica of the api (or of the job's workers) with the given name (or name suffix),
+// or the first running replica if no replica is specified; cliCommand is the cortex command which connects to the replica
+func getRunningReplica(apiName string, jobID string, replica string, cliCommand string) (*kcore.Pod, error) {
+	deployedResource, err := resources.GetDeployedResourceByName(apiName)
+	if err != nil {
+		return nil, err
+	}
+
+	var labels map[string]string
+	switch deployedResource.Kind {
+	case userconfig.RealtimeAPIKind, userconfig.AsyncAPIKind:
+		labels = map[string]string{
+			"apiName":      apiName,
+			"deploymentID": deployedResource.VirtualService.Labels["deploymentID"],
+			"podID":        deployedResource.VirtualService.Labels["podID"],
+		}
+	case userconfig.BatchAPIKind, userconfig.TaskAPIKind:
+		if jobID == "" {
+			return nil, ErrorReplicaJobIDRequired(*deployedResource, cliCommand)
+		}
+		labels = map[string]string{"apiName": apiName, "jobID": jobID}
+		if deployedResource.Kind == userconfig.BatchAPIKind {
+			labels["cortex.dev/batch"] = "worker"
+		}
+	default:
+		return nil, resources.ErrorOperationIsOnlySupportedForKind(*deployedResource, userconfig.RealtimeAPIKind, userconfig.AsyncAPIKind, userconfig.BatchAPIKind, userconfig.TaskAPIKind)
+	}
+
+	pods, err := config.K8s.WithNamespace(deployedResource.Namespace()).ListPodsByLabels(labels)
+	if err != nil {
+		return nil, err
+	}
+
+	pod := findRunningReplica(pods, replica)
+	if pod == nil {
+		return nil, ErrorNoRunningReplicas(*deployedResource, replica)
+	}
+	return pod, nil
+}
+
+func findRunningReplica(pods []kcore.Pod, replica string) *kcore.Pod {
+	sort.Slice(pods, func(i, j int) bool {
+		return pods[i].Name < pods[j].Name
+	})
+
+	for i := range pods {
+		if pods[i].Status.Phase != kcore.PodRunning || pods[i].DeletionTimestamp != nil {
+			continue
+		}
+		if replica == "" || pods[i].Name == replica || strings.HasSuffix(pods[i].Name, "-"+replica) {
+			return &pods[i]
+		}
+	}
+	return nil
+}
+
+func defaultExecContainer(pod *kcore.Pod) string {
+	for _, container := range pod.Spec.Containers {
+		if !_cortexContainerNames.Has(container.Name) {
+			return container.Name
+		}
+	}
+	return pod.Spec.Containers[0].Name
+}
+
+func podHasContainer(pod *kcore.Pod, containerName string) bool {
+	for _, container := range pod.Spec.Containers {
+		if container.Name == containerName {
+			return true
+		}
+	}
+	return false
+}
+
+func podContainerNames(pod *kcore.Pod) []string {
+	names := make([]string, 0, len(pod.Spec.Containers))
+	for _, container := range pod.Spec.Containers {
+		names = append(names, container.Name)
+	}
+	return names
+}
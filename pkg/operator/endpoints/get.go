@@ -18,19 +18,121 @@ package endpoints
 
 import (
 	"net/http"
+	"strings"
 
+	"github.com/cortexlabs/cortex/pkg/lib/sets/strset"
+	"github.com/cortexlabs/cortex/pkg/lib/slices"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
 	"github.com/cortexlabs/cortex/pkg/operator/resources"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
 	"github.com/gorilla/mux"
 )
 
 func GetAPIs(w http.ResponseWriter, r *http.Request) {
-	response, err := resources.GetAPIs()
+	offset, limit, err := getPaginationQParams(r)
 	if err != nil {
 		respondError(w, r, err)
 		return
 	}
 
-	respondJSON(w, r, response)
+	fields, err := getFieldsQParam(r)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	project, err := getProjectQParam(r)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	response, total, err := resources.GetAPIs(project, getOptionalQParam("selector", r), offset, limit)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	if fields != nil {
+		for i := range response {
+			response[i] = response[i].SelectFields(fields)
+		}
+	}
+
+	// the response is only paginated if requested, to remain compatible with clients which expect a list of apis
+	if limit == 0 {
+		respondJSON(w, r, response)
+		return
+	}
+
+	page := schema.GetAPIsPage{
+		APIs:  response,
+		Total: total,
+	}
+	if nextOffset := offset + len(response); nextOffset < total {
+		page.NextOffset = &nextOffset
+	}
+
+	respondJSON(w, r, page)
+}
+
+// returns the project query param, which is empty if the request isn't scoped to a project
+func getProjectQParam(r *http.Request) (string, error) {
+	project := getOptionalQParam("project", r)
+	if project == "" {
+		return "", nil
+	}
+	if err := userconfig.ValidateProject(project); err != nil {
+		return "", err
+	}
+	return project, nil
+}
+
+// limit is 0 if it was not specified
+func getPaginationQParams(r *http.Request) (int, int, error) {
+	var offset, limit int
+
+	if offsetStr := getOptionalQParam("offset", r); offsetStr != "" {
+		var ok bool
+		offset, ok = s.ParseInt(offsetStr)
+		if !ok || offset < 0 {
+			return 0, 0, ErrorQueryParamMalformed("offset", offsetStr, "must be a non-negative integer")
+		}
+	}
+
+	if limitStr := getOptionalQParam("limit", r); limitStr != "" {
+		var ok bool
+		limit, ok = s.ParseInt(limitStr)
+		if !ok || limit <= 0 {
+			return 0, 0, ErrorQueryParamMalformed("limit", limitStr, "must be a positive integer")
+		}
+	}
+
+	if offset > 0 && limit == 0 {
+		return 0, 0, ErrorQueryParamRequired("limit")
+	}
+
+	return offset, limit, nil
+}
+
+// returns nil if the fields query param was not specified (in which case all fields are returned)
+func getFieldsQParam(r *http.Request) (strset.Set, error) {
+	fieldsStr := getOptionalQParam("fields", r)
+	if fieldsStr == "" {
+		return nil, nil
+	}
+
+	fields := strset.New()
+	for _, field := range strings.Split(fieldsStr, ",") {
+		field = strings.TrimSpace(field)
+		if !slices.HasString(schema.APIResponseFields, field) {
+			return nil, ErrorQueryParamInvalid("fields", field, schema.APIResponseFields...)
+		}
+		fields.Add(field)
+	}
+
+	return fields, nil
 }
 
 func GetAPI(w http.ResponseWriter, r *http.Request) {
@@ -23,6 +23,7 @@ import (
 	"github.com/cortexlabs/cortex/pkg/config"
 	"github.com/cortexlabs/cortex/pkg/lib/aws"
 	"github.com/cortexlabs/cortex/pkg/lib/k8s"
+	"github.com/cortexlabs/cortex/pkg/operator/operator"
 	"github.com/cortexlabs/cortex/pkg/operator/schema"
 	"github.com/cortexlabs/cortex/pkg/types/clusterconfig"
 	"github.com/cortexlabs/cortex/pkg/types/userconfig"
@@ -42,6 +43,12 @@ func Info(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	spotInterruptions, err := operator.GetSpotInterruptionCounts()
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
 	fullClusterConfig := clusterconfig.InternalConfig{
 		Config:           *config.ClusterConfig,
 		OperatorMetadata: *config.OperatorMetadata,
@@ -52,6 +59,7 @@ func Info(w http.ResponseWriter, r *http.Request) {
 		WorkerNodeInfos:    workerNodeInfos,
 		OperatorNodeInfos:  operatorNodeInfos,
 		NumPendingReplicas: numPendingReplicas,
+		SpotInterruptions:  spotInterruptions,
 	}
 	respondJSON(w, r, response)
 }
@@ -0,0 +1,73 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package endpoints
+
+import (
+	"net/http"
+
+	"github.com/cortexlabs/cortex/pkg/operator/resources"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/gorilla/mux"
+)
+
+func ListJobSchedules(w http.ResponseWriter, r *http.Request) {
+	response, err := resources.ListJobSchedules(getOptionalQParam("apiName", r))
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	respondJSON(w, r, response)
+}
+
+func PauseJobSchedule(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	response, err := resources.PauseJobSchedule(vars["apiName"], vars["scheduleID"])
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	respondJSON(w, r, response)
+}
+
+func ResumeJobSchedule(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	response, err := resources.ResumeJobSchedule(vars["apiName"], vars["scheduleID"])
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	respondJSON(w, r, response)
+}
+
+func DeleteJobSchedule(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	msg, err := resources.DeleteJobSchedule(vars["apiName"], vars["scheduleID"])
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	respondJSON(w, r, schema.DeleteJobScheduleResponse{
+		Message: msg,
+	})
+}
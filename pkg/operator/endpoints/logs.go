@@ -18,6 +18,8 @@ package endpoints
 
 import (
 	"net/http"
+	"regexp"
+	"time"
 
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
 	"github.com/cortexlabs/cortex/pkg/operator/operator"
@@ -56,6 +58,12 @@ func ReadLogs(w http.ResponseWriter, r *http.Request) {
 	deploymentID := deployedResource.VirtualService.Labels["deploymentID"]
 	podID := deployedResource.VirtualService.Labels["podID"]
 
+	logStreamOptions, err := getLogStreamOptions(r)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
 	upgrader := websocket.Upgrader{}
 	socket, err := upgrader.Upgrade(w, r, nil)
 	if err != nil {
@@ -66,9 +74,45 @@ func ReadLogs(w http.ResponseWriter, r *http.Request) {
 
 	labels := map[string]string{"apiName": apiName, "deploymentID": deploymentID, "podID": podID}
 
+	if logStreamOptions != nil {
+		operator.StreamLogsFromPods(labels, *logStreamOptions, socket)
+		return
+	}
+
 	operator.StreamLogsFromRandomPod(labels, socket)
 }
 
+// getLogStreamOptions returns nil if none of the log streaming options were specified, in which case the logs of a random replica are streamed
+func getLogStreamOptions(r *http.Request) (*operator.LogStreamOptions, error) {
+	query := r.URL.Query()
+	if !query.Has("follow") && !query.Has("since") && !query.Has("replica") && !query.Has("filter") {
+		return nil, nil
+	}
+
+	options := operator.LogStreamOptions{
+		Follow:  getOptionalBoolQParam("follow", false, r),
+		Replica: getOptionalQParam("replica", r),
+	}
+
+	if sinceStr := getOptionalQParam("since", r); sinceStr != "" {
+		since, err := time.ParseDuration(sinceStr)
+		if err != nil || since <= 0 {
+			return nil, ErrorQueryParamMalformed("since", sinceStr, "must be a positive duration, e.g. 30s, 5m, or 1h")
+		}
+		options.Since = &since
+	}
+
+	if filterStr := getOptionalQParam("filter", r); filterStr != "" {
+		filter, err := regexp.Compile(filterStr)
+		if err != nil {
+			return nil, ErrorQueryParamMalformed("filter", filterStr, "must be a valid regular expression")
+		}
+		options.Filter = filter
+	}
+
+	return &options, nil
+}
+
 func GetLogURL(w http.ResponseWriter, r *http.Request) {
 	apiName := mux.Vars(r)["apiName"]
 	jobID := getOptionalQParam("jobID", r)
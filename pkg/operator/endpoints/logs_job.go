@@ -18,6 +18,8 @@ package endpoints
 
 import (
 	"net/http"
+	"regexp"
+	"time"
 
 	"github.com/cortexlabs/cortex/pkg/operator/operator"
 	"github.com/cortexlabs/cortex/pkg/operator/resources"
@@ -48,6 +50,12 @@ func ReadJobLogs(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	logStreamOptions, err := getLogStreamOptions(r)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
 	upgrader := websocket.Upgrader{}
 	socket, err := upgrader.Upgrade(w, r, nil)
 	if err != nil {
@@ -62,6 +70,11 @@ func ReadJobLogs(w http.ResponseWriter, r *http.Request) {
 		labels["cortex.dev/batch"] = "worker"
 	}
 
+	if logStreamOptions != nil {
+		operator.StreamLogsFromPods(labels, *logStreamOptions, socket)
+		return
+	}
+
 	operator.StreamLogsFromRandomPod(labels, socket)
 }
 
@@ -120,3 +133,67 @@ func GetJobLogURL(w http.ResponseWriter, r *http.Request) {
 		respondError(w, r, resources.ErrorOperationIsOnlySupportedForKind(*deployedResource, userconfig.BatchAPIKind, userconfig.TaskAPIKind))
 	}
 }
+
+func ReadAggregatedJobLogs(w http.ResponseWriter, r *http.Request) {
+	apiName := mux.Vars(r)["apiName"]
+	jobID, err := getRequiredQueryParam("jobID", r)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	var filter *regexp.Regexp
+	if filterStr := getOptionalQParam("filter", r); filterStr != "" {
+		filter, err = regexp.Compile(filterStr)
+		if err != nil {
+			respondError(w, r, ErrorQueryParamMalformed("filter", filterStr, "must be a valid regular expression"))
+			return
+		}
+	}
+
+	deployedResource, err := resources.GetDeployedResourceByName(apiName)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	jobKey := spec.JobKey{
+		ID:      jobID,
+		APIName: apiName,
+		Kind:    deployedResource.Kind,
+	}
+
+	var startTime time.Time
+	var endTime *time.Time
+	switch deployedResource.Kind {
+	case userconfig.BatchAPIKind:
+		jobResponse, err := batchapi.GetJob(jobKey)
+		if err != nil {
+			respondError(w, r, err)
+			return
+		}
+		startTime = jobResponse.JobStatus.StartTime
+		endTime = jobResponse.JobStatus.EndTime
+	case userconfig.TaskAPIKind:
+		jobStatus, err := taskapi.GetJobStatus(jobKey)
+		if err != nil {
+			respondError(w, r, err)
+			return
+		}
+		startTime = jobStatus.StartTime
+		endTime = jobStatus.EndTime
+	default:
+		respondError(w, r, resources.ErrorOperationIsOnlySupportedForKind(*deployedResource, userconfig.BatchAPIKind, userconfig.TaskAPIKind))
+		return
+	}
+
+	upgrader := websocket.Upgrader{}
+	socket, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+	defer socket.Close()
+
+	operator.StreamAggregatedJobLogs(jobKey, startTime, endTime, filter, socket)
+}
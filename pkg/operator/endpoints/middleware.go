@@ -19,12 +19,16 @@ package endpoints
 import (
 	"context"
 	"net/http"
+	"strings"
 
+	"github.com/cortexlabs/cortex/pkg/apikeys"
 	"github.com/cortexlabs/cortex/pkg/config"
 	"github.com/cortexlabs/cortex/pkg/consts"
 	"github.com/cortexlabs/cortex/pkg/lib/aws"
 	"github.com/cortexlabs/cortex/pkg/lib/sets/strset"
 	"github.com/cortexlabs/cortex/pkg/lib/telemetry"
+	"github.com/cortexlabs/cortex/pkg/operator/resources"
+	"github.com/cortexlabs/cortex/pkg/rbac"
 )
 
 var _cachedClientIDs = strset.New()
@@ -34,6 +38,7 @@ type ctxKey int
 const (
 	ctxKeyUnknown ctxKey = iota
 	ctxKeyClient
+	ctxKeyCaller
 )
 
 func PanicMiddleware(next http.Handler) http.Handler {
@@ -59,34 +64,112 @@ func ClientIDMiddleware(next http.Handler) http.Handler {
 	})
 }
 
-func AWSAuthMiddleware(next http.Handler) http.Handler {
+// AuthMiddleware authenticates the caller with a signed aws identity request, an api key, or an oidc id token (obtained with `cortex login`);
+// api keys only grant access to endpoints once they are bound to a role (see RequireRole)
+func AuthMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		authHeader := r.Header.Get(consts.AuthHeader)
-
-		if authHeader == "" {
-			respondError(w, r, ErrorAuthHeaderMissing(consts.AuthHeader, r.Host, r.RequestURI))
+		caller, statusCode, err := authenticate(r)
+		if err != nil {
+			respondErrorCode(w, r, statusCode, err)
 			return
 		}
 
-		accountID, err := aws.ExecuteIdentityRequestFromHeader(authHeader)
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), ctxKeyCaller, caller)))
+	})
+}
+
+// authenticate returns the caller, or the status code and error to respond with
+func authenticate(r *http.Request) (rbac.Caller, int, error) {
+	authHeader := r.Header.Get(consts.AuthHeader)
+
+	if authHeader == "" {
+		if apiKey := r.Header.Get(apikeys.Header); apiKey != "" {
+			return authenticateAPIKey(apiKey)
+		}
+		if idToken := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "); idToken != r.Header.Get("Authorization") {
+			return authenticateIDToken(idToken)
+		}
+		return rbac.Caller{}, http.StatusBadRequest, ErrorAuthHeaderMissing(consts.AuthHeader, r.Host, r.RequestURI)
+	}
+
+	identity, err := aws.GetCallerIdentityFromHeader(authHeader)
+	if err != nil {
+		return rbac.Caller{}, http.StatusBadRequest, err
+	}
+
+	operatorAccountID, _, err := config.AWS.GetCachedAccountID()
+	if err != nil {
+		return rbac.Caller{}, http.StatusBadRequest, ErrorAuthAPIError()
+	}
+
+	if *identity.Account != operatorAccountID {
+		return rbac.Caller{}, http.StatusForbidden, ErrorAuthOtherAccount()
+	}
+
+	return rbac.Caller{Kind: rbac.SubjectKindIAM, ID: *identity.Arn}, 0, nil
+}
+
+func authenticateAPIKey(apiKey string) (rbac.Caller, int, error) {
+	keyName, err := resources.AuthenticateAPIKey(apiKey)
+	if err != nil {
+		return rbac.Caller{}, http.StatusBadRequest, err
+	}
+	if keyName == "" {
+		return rbac.Caller{}, http.StatusUnauthorized, ErrorAuthInvalidAPIKey()
+	}
+
+	return rbac.Caller{Kind: rbac.SubjectKindAPIKey, ID: keyName}, 0, nil
+}
+
+func authenticateIDToken(idToken string) (rbac.Caller, int, error) {
+	if config.OIDCVerifier == nil {
+		return rbac.Caller{}, http.StatusUnauthorized, ErrorOIDCNotConfigured()
+	}
+
+	claims, err := config.OIDCVerifier.Verify(idToken)
+	if err != nil {
+		return rbac.Caller{}, http.StatusUnauthorized, err
+	}
+
+	username := claims.String(config.ClusterConfig.OIDC.UsernameClaim)
+	if username == "" {
+		return rbac.Caller{}, http.StatusUnauthorized, ErrorOIDCClaimMissing(config.ClusterConfig.OIDC.UsernameClaim)
+	}
+
+	return rbac.Caller{Kind: rbac.SubjectKindOIDC, ID: username}, 0, nil
+}
+
+// RequireRole responds with 403 to callers whose role doesn't include the required role; while no role bindings exist (i.e. rbac is disabled),
+// all callers which are authenticated with aws credentials are allowed, and callers which are authenticated with api keys or id tokens are not
+func RequireRole(requiredRole rbac.Role, handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		caller := getCaller(r)
+
+		role, rbacEnabled, err := resources.CallerRole(caller)
 		if err != nil {
 			respondError(w, r, err)
 			return
 		}
 
-		operatorAccountID, _, err := config.AWS.GetCachedAccountID()
-		if err != nil {
-			respondError(w, r, ErrorAuthAPIError())
+		if !rbacEnabled && caller.Kind == rbac.SubjectKindIAM {
+			handler(w, r)
 			return
 		}
 
-		if accountID != operatorAccountID {
-			respondErrorCode(w, r, http.StatusForbidden, ErrorAuthOtherAccount())
+		if !role.Includes(requiredRole) {
+			respondErrorCode(w, r, http.StatusForbidden, ErrorForbidden(caller.String(), role, requiredRole))
 			return
 		}
 
-		next.ServeHTTP(w, r)
-	})
+		handler(w, r)
+	}
+}
+
+func getCaller(r *http.Request) rbac.Caller {
+	if caller, ok := r.Context().Value(ctxKeyCaller).(rbac.Caller); ok {
+		return caller
+	}
+	return rbac.Caller{}
 }
 
 func APIVersionCheckMiddleware(next http.Handler) http.Handler {
@@ -0,0 +1,60 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package endpoints
+
+import (
+	"net/http"
+
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	"github.com/cortexlabs/cortex/pkg/operator/operator"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+)
+
+func PortForward(w http.ResponseWriter, r *http.Request) {
+	apiName := mux.Vars(r)["apiName"]
+	jobID := getOptionalQParam("jobID", r)
+	replica := getOptionalQParam("replica", r)
+
+	portStr, err := getRequiredQueryParam("port", r)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+	port, ok := s.ParseInt32(portStr)
+	if !ok || port < 1 || port > 65535 {
+		respondError(w, r, ErrorQueryParamMalformed("port", portStr, "must be an integer between 1 and 65535"))
+		return
+	}
+
+	pod, err := getRunningReplica(apiName, jobID, replica, "port-forward")
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	upgrader := websocket.Upgrader{}
+	socket, err := upgrader.Upgrade(w, r, http.Header{schema.ReplicaHeader: []string{pod.Name}})
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+	defer socket.Close()
+
+	operator.PortForwardToReplica(pod.Name, pod.Namespace, port, socket)
+}
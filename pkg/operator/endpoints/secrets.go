@@ -0,0 +1,93 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package endpoints
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/operator/resources"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/gorilla/mux"
+)
+
+func SetSecret(w http.ResponseWriter, r *http.Request) {
+	secretName := mux.Vars(r)["secretName"]
+
+	// secrets manager's max secret size
+	rw := http.MaxBytesReader(w, r.Body, 64<<10)
+
+	bodyBytes, err := ioutil.ReadAll(rw)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	var request schema.SetSecretRequest
+	if err := json.Unmarshal(bodyBytes, &request); err != nil {
+		respondError(w, r, errors.Wrap(err, "request body"))
+		return
+	}
+
+	msg, err := resources.SetSecret(secretName, request.Value)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	respondJSON(w, r, schema.SetSecretResponse{
+		Message: msg,
+	})
+}
+
+func GetSecret(w http.ResponseWriter, r *http.Request) {
+	secretName := mux.Vars(r)["secretName"]
+
+	response, err := resources.GetSecret(secretName)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	respondJSON(w, r, response)
+}
+
+func ListSecrets(w http.ResponseWriter, r *http.Request) {
+	response, err := resources.ListSecrets()
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	respondJSON(w, r, response)
+}
+
+func DeleteSecret(w http.ResponseWriter, r *http.Request) {
+	secretName := mux.Vars(r)["secretName"]
+
+	msg, err := resources.DeleteSecret(secretName)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	respondJSON(w, r, schema.DeleteSecretResponse{
+		Message: msg,
+	})
+}
@@ -88,6 +88,16 @@ func SubmitBatchJob(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if submission.Schedule != nil {
+		jobSchedule, err := resources.CreateBatchJobSchedule(apiName, &submission)
+		if err != nil {
+			respondError(w, r, err)
+			return
+		}
+		respondJSON(w, r, jobSchedule)
+		return
+	}
+
 	jobSpec, err := batchapi.SubmitJob(apiName, &submission)
 	if err != nil {
 		respondError(w, r, err)
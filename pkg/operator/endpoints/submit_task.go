@@ -68,6 +68,16 @@ func SubmitTaskJob(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if submission.Schedule != nil {
+		jobSchedule, err := resources.CreateTaskJobSchedule(apiName, &submission)
+		if err != nil {
+			respondError(w, r, err)
+			return
+		}
+		respondJSON(w, r, jobSchedule)
+		return
+	}
+
 	jobSpec, err := taskapi.SubmitJob(apiName, &submission)
 	if err != nil {
 		respondError(w, r, err)
@@ -0,0 +1,216 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operator
+
+import (
+	"context"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/config"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+	"github.com/cortexlabs/cortex/pkg/workloads"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/common/model"
+)
+
+const (
+	APIMetricsCronPeriod            = 30 * time.Second
+	_prometheusQueryTimeoutSeconds  = 10
+	_apiMetricsRateWindow           = "1m"
+	_realtimeDestinationServiceExpr = `destination_service=~"api-.+"`
+)
+
+var _latencyQuantiles = []string{"0.5", "0.9", "0.99"}
+
+var apiRequestRateGauge = promauto.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "cortex_api_requests_per_second",
+		Help: "The request rate of an API, averaged over the past minute",
+	}, []string{"api_name", "api_kind", "status_code"},
+)
+
+var apiLatencyGauge = promauto.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "cortex_api_latency_seconds",
+		Help: "The request latency quantiles of an API, computed over the past minute",
+	}, []string{"api_name", "api_kind", "quantile"},
+)
+
+var apiRequestedReplicasGauge = promauto.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "cortex_api_requested_replicas",
+		Help: "The number of replicas of an API requested by the autoscaler",
+	}, []string{"api_name", "api_kind"},
+)
+
+var apiReadyReplicasGauge = promauto.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "cortex_api_ready_replicas",
+		Help: "The number of replicas of an API which are ready to serve traffic",
+	}, []string{"api_name", "api_kind"},
+)
+
+var apiMinReplicasGauge = promauto.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "cortex_api_min_replicas",
+		Help: "The lower bound of the autoscaler for an API",
+	}, []string{"api_name", "api_kind"},
+)
+
+var apiMaxReplicasGauge = promauto.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "cortex_api_max_replicas",
+		Help: "The upper bound of the autoscaler for an API",
+	}, []string{"api_name", "api_kind"},
+)
+
+// UpdateAPIMetrics exports per-API request rates, latencies, and autoscaling decisions on the operator's /metrics endpoint
+func UpdateAPIMetrics() error {
+	deployments, err := config.K8sAllNamspaces.ListDeploymentsWithLabelKeys("apiName", "apiKind")
+	if err != nil {
+		return err
+	}
+
+	apiKinds := map[string]string{} // api name -> api kind
+
+	apiRequestedReplicasGauge.Reset()
+	apiReadyReplicasGauge.Reset()
+	apiMinReplicasGauge.Reset()
+	apiMaxReplicasGauge.Reset()
+	for i := range deployments {
+		deployment := &deployments[i]
+		apiName := deployment.Labels["apiName"]
+		apiKind := deployment.Labels["apiKind"]
+		if apiKind != userconfig.RealtimeAPIKind.String() && apiKind != userconfig.AsyncAPIKind.String() {
+			continue
+		}
+		if _, ok := deployment.Labels["canaryOf"]; ok {
+			continue
+		}
+		apiKinds[apiName] = apiKind
+
+		if deployment.Spec.Replicas != nil {
+			apiRequestedReplicasGauge.WithLabelValues(apiName, apiKind).Set(float64(*deployment.Spec.Replicas))
+		}
+		apiReadyReplicasGauge.WithLabelValues(apiName, apiKind).Set(float64(deployment.Status.ReadyReplicas))
+
+		autoscaling, err := userconfig.AutoscalingFromAnnotations(deployment)
+		if err != nil {
+			continue
+		}
+		apiMinReplicasGauge.WithLabelValues(apiName, apiKind).Set(float64(autoscaling.MinReplicas))
+		apiMaxReplicasGauge.WithLabelValues(apiName, apiKind).Set(float64(autoscaling.MaxReplicas))
+	}
+
+	realtimeRequestRates, err := QueryPrometheusVec(
+		"sum by (destination_service, response_code) (rate(istio_requests_total{" + _realtimeDestinationServiceExpr + "}[" + _apiMetricsRateWindow + "]))",
+	)
+	if err != nil {
+		return err
+	}
+	asyncRequestRates, err := QueryPrometheusVec(
+		"sum by (api_name, status_code) (rate(cortex_async_request_count[" + _apiMetricsRateWindow + "]))",
+	)
+	if err != nil {
+		return err
+	}
+
+	apiRequestRateGauge.Reset()
+	for _, sample := range realtimeRequestRates {
+		apiName := apiNameFromDestinationService(string(sample.Metric["destination_service"]))
+		if apiKinds[apiName] != userconfig.RealtimeAPIKind.String() {
+			continue
+		}
+		setSample(apiRequestRateGauge, sample.Value, apiName, apiKinds[apiName], string(sample.Metric["response_code"]))
+	}
+	for _, sample := range asyncRequestRates {
+		apiName := string(sample.Metric["api_name"])
+		if apiKinds[apiName] != userconfig.AsyncAPIKind.String() {
+			continue
+		}
+		setSample(apiRequestRateGauge, sample.Value, apiName, apiKinds[apiName], string(sample.Metric["status_code"]))
+	}
+
+	apiLatencyGauge.Reset()
+	for _, quantile := range _latencyQuantiles {
+		realtimeLatencies, err := QueryPrometheusVec(
+			"histogram_quantile(" + quantile + ", sum by (destination_service, le) (rate(istio_request_duration_milliseconds_bucket{" + _realtimeDestinationServiceExpr + "}[" + _apiMetricsRateWindow + "]))) / 1000",
+		)
+		if err != nil {
+			return err
+		}
+		asyncLatencies, err := QueryPrometheusVec(
+			"histogram_quantile(" + quantile + ", sum by (api_name, le) (rate(cortex_async_latency_bucket[" + _apiMetricsRateWindow + "])))",
+		)
+		if err != nil {
+			return err
+		}
+
+		for _, sample := range realtimeLatencies {
+			apiName := apiNameFromDestinationService(string(sample.Metric["destination_service"]))
+			if apiKinds[apiName] != userconfig.RealtimeAPIKind.String() {
+				continue
+			}
+			setSample(apiLatencyGauge, sample.Value, apiName, apiKinds[apiName], quantile)
+		}
+		for _, sample := range asyncLatencies {
+			apiName := string(sample.Metric["api_name"])
+			if apiKinds[apiName] != userconfig.AsyncAPIKind.String() {
+				continue
+			}
+			setSample(apiLatencyGauge, sample.Value, apiName, apiKinds[apiName], quantile)
+		}
+	}
+
+	return nil
+}
+
+// QueryPrometheusVec runs an instant query against the in-cluster prometheus
+func QueryPrometheusVec(query string) (model.Vector, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), _prometheusQueryTimeoutSeconds*time.Second)
+	defer cancel()
+
+	valuesQuery, _, err := config.Prometheus.Query(ctx, query, time.Now())
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	values, ok := valuesQuery.(model.Vector)
+	if !ok {
+		return nil, errors.ErrorUnexpected("failed to convert prometheus metric to vector")
+	}
+
+	return values, nil
+}
+
+// the istio destination service of a realtime api looks like api-<api_name>.<namespace>.svc.cluster.local
+func apiNameFromDestinationService(destinationService string) string {
+	k8sName := strings.SplitN(destinationService, ".", 2)[0]
+	return strings.TrimPrefix(k8sName, workloads.K8sName(""))
+}
+
+// quantiles of apis without traffic in the window are NaN, which are skipped
+func setSample(gauge *prometheus.GaugeVec, value model.SampleValue, labelValues ...string) {
+	if math.IsNaN(float64(value)) {
+		return
+	}
+	gauge.WithLabelValues(labelValues...).Set(float64(value))
+}
@@ -40,7 +40,7 @@ var operatorLogger = logging.GetLogger()
 var previousListOfEvictedPods = strset.New()
 
 func DeleteEvictedPods() error {
-	failedPods, err := config.K8s.ListPods(&kmeta.ListOptions{
+	failedPods, err := config.K8sAllNamspaces.ListPods(&kmeta.ListOptions{
 		FieldSelector: "status.phase=Failed",
 	})
 	if err != nil {
@@ -53,14 +53,15 @@ func DeleteEvictedPods() error {
 		if pod.Status.Reason != k8s.ReasonEvicted {
 			continue
 		}
-		if previousListOfEvictedPods.Has(pod.Name) {
-			_, err := config.K8s.DeletePod(pod.Name)
+		podKey := pod.Namespace + "/" + pod.Name
+		if previousListOfEvictedPods.Has(podKey) {
+			_, err := config.K8s.WithNamespace(pod.Namespace).DeletePod(pod.Name)
 			if err != nil {
 				errs = append(errs, err)
 			}
 			continue
 		}
-		currentEvictedPods.Add(pod.Name)
+		currentEvictedPods.Add(podKey)
 	}
 	previousListOfEvictedPods = currentEvictedPods
 
@@ -240,7 +241,7 @@ func cortexSystemPrice(numOperatorInstances, numPrometheusInstances int) float64
 		natTotalPrice = natUnitPrice * float64(len(config.ClusterConfig.AvailabilityZones))
 	}
 
-	operatorInstancePrice := aws.InstanceMetadatas[config.ClusterConfig.Region]["t3.medium"].Price
+	operatorInstancePrice := aws.InstanceMetadatas[config.ClusterConfig.Region][config.ClusterConfig.OperatorNodeGroupInstanceType()].Price
 	operatorEBSPrice := aws.EBSMetadatas[config.ClusterConfig.Region]["gp3"].PriceGB * 20 / 30 / 24
 
 	prometheusInstancePrice := aws.InstanceMetadatas[config.ClusterConfig.Region][config.ClusterConfig.PrometheusInstanceType].Price
@@ -272,7 +273,6 @@ func CostBreakdown() error {
 
 	var podList v1.PodList
 	err = config.K8s.List(ctx, &podList,
-		client.InNamespace(consts.DefaultNamespace),
 		client.HasLabels{"apiName", "apiKind"},
 	)
 	if err != nil {
@@ -0,0 +1,347 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operator
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/acm"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/cortexlabs/cortex/pkg/config"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/sets/strset"
+	"github.com/cortexlabs/cortex/pkg/types/clusterconfig"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+)
+
+// The apis' virtual services match any host (see k8s.VirtualService), so requests sent to a custom domain are routed by their path like any others;
+// this is why an api's endpoint must be unique in the cluster even if it has a custom domain.
+
+// ACM certificates are validated asynchronously (usually within a few minutes of their DNS validation records being created),
+// so custom domains are provisioned by a cron rather than while the api is being deployed
+const CustomDomainsCronPeriod = time.Minute
+
+const (
+	_customDomainTag     = "cortex.dev/custom-domain"
+	_validationRecordTTL = 300
+)
+
+// AreCustomDomainsSupported returns whether the api load balancer has a TLS listener, to which the custom domains' certificates can be added,
+// and whether any hosted zones have been configured in which the operator is allowed to create the custom domains' records
+func AreCustomDomainsSupported() bool {
+	return config.ClusterConfig.APILoadBalancerType == clusterconfig.NLBLoadBalancerType && config.ClusterConfig.SSLCertificateARN != nil &&
+		len(config.ClusterConfig.CustomDomainHostedZoneIDs) > 0
+}
+
+// ValidateCustomDomain checks that one of the cluster's configured hosted zones contains the api's custom domain,
+// and that the hosted zone doesn't already have records for the domain other than the alias to the api load balancer which cortex creates
+func ValidateCustomDomain(api *userconfig.API) error {
+	if api.Networking.CustomDomain == nil {
+		return nil
+	}
+	domain := *api.Networking.CustomDomain
+
+	hostedZone, err := config.AWS.FindHostedZoneForDomain(domain, config.ClusterConfig.CustomDomainHostedZoneIDs)
+	if err != nil {
+		return err
+	}
+	if hostedZone == nil {
+		return ErrorNoHostedZoneForCustomDomain(domain)
+	}
+
+	cnameRecordSet, err := config.AWS.GetRecordSet(*hostedZone.Id, domain, route53.RRTypeCname)
+	if err != nil {
+		return err
+	}
+	if cnameRecordSet != nil {
+		return ErrorCustomDomainRecordExists(domain, route53.RRTypeCname, *hostedZone.Name)
+	}
+
+	aliasRecordSet, err := config.AWS.GetRecordSet(*hostedZone.Id, domain, route53.RRTypeA)
+	if err != nil {
+		return err
+	}
+	if aliasRecordSet == nil {
+		return nil
+	}
+
+	loadBalancer, err := getAPILoadBalancer()
+	if err != nil {
+		return err
+	}
+	if !isAliasToLoadBalancer(aliasRecordSet, loadBalancer) {
+		return ErrorCustomDomainRecordExists(domain, route53.RRTypeA, *hostedZone.Name)
+	}
+
+	return nil
+}
+
+// ReconcileCustomDomains provisions an ACM certificate and Route 53 records for each custom domain which is used by a deployed api,
+// and adds each certificate to the api load balancer's https listener once it has been issued.
+// The certificates and records of custom domains which are no longer used by any api are deleted.
+func ReconcileCustomDomains() error {
+	if !AreCustomDomainsSupported() {
+		return nil
+	}
+
+	virtualServices, err := config.K8sAllNamspaces.ListVirtualServicesWithLabelKeys("apiName", "apiKind")
+	if err != nil {
+		return err
+	}
+
+	domains := strset.New()
+	for _, virtualService := range virtualServices {
+		if virtualService.Labels["apiKind"] != userconfig.RealtimeAPIKind.String() && virtualService.Labels["apiKind"] != userconfig.AsyncAPIKind.String() {
+			continue
+		}
+		if domain, ok := virtualService.Annotations[userconfig.CustomDomainAnnotationKey]; ok {
+			domains.Add(domain)
+		}
+	}
+
+	certificates, err := config.AWS.ListCertificatesWithTags(customDomainTags())
+	if err != nil {
+		return err
+	}
+
+	if len(domains) == 0 && len(certificates) == 0 {
+		return nil
+	}
+
+	loadBalancer, err := getAPILoadBalancer()
+	if err != nil {
+		return err
+	}
+
+	listener, err := config.AWS.FindListener(*loadBalancer.LoadBalancerArn, 443)
+	if err != nil {
+		return err
+	}
+	if listener == nil || listener.ListenerArn == nil {
+		return ErrorLoadBalancerInitializing()
+	}
+
+	listenerCertificateARNs, err := config.AWS.GetListenerCertificateARNs(*listener.ListenerArn)
+	if err != nil {
+		return err
+	}
+
+	certificatesByDomain := map[string]*acm.CertificateDetail{}
+	for _, certificate := range certificates {
+		if certificate.DomainName != nil && certificate.CertificateArn != nil {
+			certificatesByDomain[*certificate.DomainName] = certificate
+		}
+	}
+
+	var errs []error
+
+	for _, domain := range domains.SliceSorted() {
+		err := reconcileCustomDomain(domain, certificatesByDomain[domain], loadBalancer, *listener.ListenerArn, listenerCertificateARNs)
+		errs, _ = errors.AddError(errs, err, domain)
+	}
+
+	unusedDomains := make([]string, 0, len(certificatesByDomain))
+	for domain := range certificatesByDomain {
+		if !domains.Has(domain) {
+			unusedDomains = append(unusedDomains, domain)
+		}
+	}
+	sort.Strings(unusedDomains)
+
+	for _, domain := range unusedDomains {
+		err := deleteCustomDomain(domain, certificatesByDomain[domain], loadBalancer, *listener.ListenerArn, listenerCertificateARNs)
+		errs, _ = errors.AddError(errs, err, domain)
+	}
+
+	return errors.FirstError(errs...)
+}
+
+func reconcileCustomDomain(domain string, certificate *acm.CertificateDetail, loadBalancer *elbv2.LoadBalancer, listenerARN string, listenerCertificateARNs strset.Set) error {
+	hostedZone, err := config.AWS.FindHostedZoneForDomain(domain, config.ClusterConfig.CustomDomainHostedZoneIDs)
+	if err != nil {
+		return err
+	}
+	if hostedZone == nil {
+		return ErrorNoHostedZoneForCustomDomain(domain)
+	}
+
+	aliasRecordSet, err := config.AWS.GetRecordSet(*hostedZone.Id, domain, route53.RRTypeA)
+	if err != nil {
+		return err
+	}
+	// records which already exist are never overwritten, since they may serve something other than this cluster (apis whose
+	// custom domain has such a record are rejected during validation, but the record may have been created after the api was deployed)
+	if aliasRecordSet != nil && !isAliasToLoadBalancer(aliasRecordSet, loadBalancer) {
+		return ErrorCustomDomainRecordExists(domain, route53.RRTypeA, *hostedZone.Name)
+	}
+	if aliasRecordSet == nil {
+		err := config.AWS.CreateRecordSets(*hostedZone.Id, &route53.ResourceRecordSet{
+			Name: aws.String(domain),
+			Type: aws.String(route53.RRTypeA),
+			AliasTarget: &route53.AliasTarget{
+				DNSName:              loadBalancer.DNSName,
+				HostedZoneId:         loadBalancer.CanonicalHostedZoneId,
+				EvaluateTargetHealth: aws.Bool(false),
+			},
+		})
+		if err != nil {
+			return err
+		}
+		operatorLogger.Infof("created dns record for custom domain %s", domain)
+	}
+
+	if certificate == nil {
+		if _, err := config.AWS.RequestCertificate(domain, customDomainTags()); err != nil {
+			return err
+		}
+		operatorLogger.Infof("requested certificate for custom domain %s", domain)
+		// the certificate's dns validation records are populated asynchronously, so they are created during the next run
+		return nil
+	}
+
+	switch *certificate.Status {
+	case acm.CertificateStatusPendingValidation:
+		var missingRecordSets []*route53.ResourceRecordSet
+		for _, recordSet := range validationRecordSets(certificate) {
+			existingRecordSet, err := config.AWS.GetRecordSet(*hostedZone.Id, *recordSet.Name, *recordSet.Type)
+			if err != nil {
+				return err
+			}
+			if existingRecordSet == nil {
+				missingRecordSets = append(missingRecordSets, recordSet)
+			}
+		}
+		if len(missingRecordSets) == 0 {
+			return nil
+		}
+		return config.AWS.CreateRecordSets(*hostedZone.Id, missingRecordSets...)
+	case acm.CertificateStatusIssued:
+		if listenerCertificateARNs.Has(*certificate.CertificateArn) {
+			return nil
+		}
+		if err := config.AWS.AddListenerCertificate(listenerARN, *certificate.CertificateArn); err != nil {
+			return err
+		}
+		operatorLogger.Infof("added certificate for custom domain %s to the api load balancer", domain)
+		return nil
+	case acm.CertificateStatusValidationTimedOut, acm.CertificateStatusExpired, acm.CertificateStatusRevoked:
+		// the certificate can't be used anymore; a new one will be requested during the next run
+		if listenerCertificateARNs.Has(*certificate.CertificateArn) {
+			if err := config.AWS.RemoveListenerCertificate(listenerARN, *certificate.CertificateArn); err != nil {
+				return err
+			}
+		}
+		return config.AWS.DeleteCertificate(*certificate.CertificateArn)
+	default:
+		return ErrorCustomDomainCertificateFailed(domain, *certificate.Status, aws.StringValue(certificate.FailureReason))
+	}
+}
+
+func deleteCustomDomain(domain string, certificate *acm.CertificateDetail, loadBalancer *elbv2.LoadBalancer, listenerARN string, listenerCertificateARNs strset.Set) error {
+	if listenerCertificateARNs.Has(*certificate.CertificateArn) {
+		if err := config.AWS.RemoveListenerCertificate(listenerARN, *certificate.CertificateArn); err != nil {
+			return err
+		}
+	}
+
+	hostedZone, err := config.AWS.FindHostedZoneForDomain(domain, config.ClusterConfig.CustomDomainHostedZoneIDs)
+	if err != nil {
+		return err
+	}
+
+	if hostedZone != nil {
+		aliasRecordSet, err := config.AWS.GetRecordSet(*hostedZone.Id, domain, route53.RRTypeA)
+		if err != nil {
+			return err
+		}
+		// don't delete the record if it has since been pointed elsewhere
+		if isAliasToLoadBalancer(aliasRecordSet, loadBalancer) {
+			if err := config.AWS.DeleteRecordSet(*hostedZone.Id, domain, route53.RRTypeA); err != nil {
+				return err
+			}
+		}
+
+		for _, recordSet := range validationRecordSets(certificate) {
+			if err := config.AWS.DeleteRecordSet(*hostedZone.Id, *recordSet.Name, *recordSet.Type); err != nil {
+				return err
+			}
+		}
+	}
+
+	// this fails while the load balancer is still releasing the certificate, in which case it is retried during the next run
+	if err := config.AWS.DeleteCertificate(*certificate.CertificateArn); err != nil {
+		return err
+	}
+
+	operatorLogger.Infof("deleted certificate and dns records for custom domain %s", domain)
+	return nil
+}
+
+func getAPILoadBalancer() (*elbv2.LoadBalancer, error) {
+	loadBalancer, err := config.AWS.FindLoadBalancerV2(map[string]string{
+		clusterconfig.ClusterNameTag: config.ClusterConfig.ClusterName,
+		"cortex.dev/load-balancer":   "api",
+	})
+	if err != nil {
+		return nil, err
+	}
+	if loadBalancer == nil || loadBalancer.LoadBalancerArn == nil {
+		return nil, ErrorLoadBalancerInitializing()
+	}
+	return loadBalancer, nil
+}
+
+func customDomainTags() map[string]string {
+	return map[string]string{
+		clusterconfig.ClusterNameTag: config.ClusterConfig.ClusterName,
+		_customDomainTag:             "true",
+	}
+}
+
+func validationRecordSets(certificate *acm.CertificateDetail) []*route53.ResourceRecordSet {
+	var recordSets []*route53.ResourceRecordSet
+	for _, option := range certificate.DomainValidationOptions {
+		if option.ResourceRecord == nil || option.ResourceRecord.Name == nil || option.ResourceRecord.Value == nil {
+			continue
+		}
+		recordSets = append(recordSets, &route53.ResourceRecordSet{
+			Name: option.ResourceRecord.Name,
+			Type: option.ResourceRecord.Type,
+			TTL:  aws.Int64(_validationRecordTTL),
+			ResourceRecords: []*route53.ResourceRecord{
+				{Value: option.ResourceRecord.Value},
+			},
+		})
+	}
+	return recordSets
+}
+
+func isAliasToLoadBalancer(recordSet *route53.ResourceRecordSet, loadBalancer *elbv2.LoadBalancer) bool {
+	if recordSet == nil || recordSet.AliasTarget == nil || recordSet.AliasTarget.DNSName == nil || loadBalancer.DNSName == nil {
+		return false
+	}
+
+	normalize := func(dnsName string) string {
+		return strings.TrimPrefix(strings.TrimSuffix(strings.ToLower(dnsName), "."), "dualstack.")
+	}
+
+	return normalize(*recordSet.AliasTarget.DNSName) == normalize(*loadBalancer.DNSName)
+}
@@ -17,7 +17,9 @@ limitations under the License.
 package operator
 
 import (
+	"github.com/cortexlabs/cortex/pkg/config"
 	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+	"github.com/cortexlabs/cortex/pkg/workloads"
 	istioclientnetworking "istio.io/client-go/pkg/apis/networking/v1beta1"
 )
 
@@ -29,3 +31,23 @@ type DeployedResource struct {
 func (deployedResourced *DeployedResource) ID() string {
 	return deployedResourced.VirtualService.Labels["apiID"]
 }
+
+// Namespace returns the namespace of the resource's project
+func (deployedResourced *DeployedResource) Namespace() string {
+	return deployedResourced.VirtualService.Namespace
+}
+
+// GetAPIVirtualService returns the virtual service of the api from the namespace of whichever project it's in
+// (api names are unique across projects), or nil if the api isn't deployed
+func GetAPIVirtualService(apiName string) (*istioclientnetworking.VirtualService, error) {
+	virtualServices, err := config.K8sAllNamspaces.ListVirtualServicesByLabel("apiName", apiName)
+	if err != nil {
+		return nil, err
+	}
+	for _, virtualService := range virtualServices {
+		if virtualService.Name == workloads.K8sName(apiName) {
+			return virtualService, nil
+		}
+	}
+	return nil, nil
+}
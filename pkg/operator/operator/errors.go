@@ -18,15 +18,27 @@ package operator
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
 	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	"github.com/cortexlabs/cortex/pkg/types/clusterconfig"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
 )
 
 const (
-	ErrCortexInstallationBroken = "operator.cortex_installation_broken"
-	ErrLoadBalancerInitializing = "operator.load_balancer_initializing"
-	ErrInvalidOperatorLogLevel  = "operator.invalid_operator_log_level"
+	ErrCortexInstallationBroken       = "operator.cortex_installation_broken"
+	ErrLoadBalancerInitializing       = "operator.load_balancer_initializing"
+	ErrInvalidOperatorLogLevel        = "operator.invalid_operator_log_level"
+	ErrCustomDomainCertificateFailed  = "operator.custom_domain_certificate_failed"
+	ErrNoHostedZoneForCustomDomain    = "operator.no_hosted_zone_for_custom_domain"
+	ErrCustomDomainRecordExists       = "operator.custom_domain_record_exists"
+	ErrSecretNotFound                 = "operator.secret_not_found"
+	ErrNetworkPoliciesDisabled        = "operator.network_policies_disabled"
+	ErrEgressInstanceMetadataRequired = "operator.egress_instance_metadata_required"
+	ErrEgressDomainNotResolvable      = "operator.egress_domain_not_resolvable"
+	ErrPodSecurityPolicyViolation     = "operator.pod_security_policy_violation"
+	ErrImageScanPolicyViolation       = "operator.image_scan_policy_violation"
 )
 
 func ErrorCortexInstallationBroken() error {
@@ -49,3 +61,72 @@ func ErrorInvalidOperatorLogLevel(provided string, loglevels []string) error {
 		Message: fmt.Sprintf("invalid operator log level %s; must be one of %s", provided, s.StrsOr(loglevels)),
 	})
 }
+
+func ErrorCustomDomainCertificateFailed(domain string, status string, reason string) error {
+	message := fmt.Sprintf("the certificate for custom domain %s has status %s", domain, status)
+	if reason != "" {
+		message += fmt.Sprintf(" (%s)", reason)
+	}
+	message += "; remove the custom domain from your api configuration, and delete the certificate in the acm console to try again"
+
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrCustomDomainCertificateFailed,
+		Message: message,
+	})
+}
+
+func ErrorNoHostedZoneForCustomDomain(domain string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrNoHostedZoneForCustomDomain,
+		Message: fmt.Sprintf("none of the public route 53 hosted zones in your cluster configuration's %s contain %s; please add the id of a hosted zone for %s (or one of its parent domains) to %s (this requires creating a new cluster, since the operator's iam policy only allows it to change the records of those hosted zones)", clusterconfig.CustomDomainHostedZoneIDsKey, domain, domain, clusterconfig.CustomDomainHostedZoneIDsKey),
+	})
+}
+
+func ErrorCustomDomainRecordExists(domain string, recordType string, hostedZoneName string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrCustomDomainRecordExists,
+		Message: fmt.Sprintf("hosted zone %s already has a %s record for %s which doesn't point to the api load balancer; cortex only creates records which don't exist yet, so please delete the record or use a different domain", strings.TrimSuffix(hostedZoneName, "."), recordType, domain),
+	})
+}
+
+func ErrorSecretNotFound(name string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrSecretNotFound,
+		Message: fmt.Sprintf("secret %s does not exist; you can create it with `cortex secrets set %s`", name, name),
+	})
+}
+
+func ErrorNetworkPoliciesDisabled() error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrNetworkPoliciesDisabled,
+		Message: fmt.Sprintf("egress can only be restricted in clusters which enforce network policies (i.e. which were created with %s: true in the cluster configuration)", clusterconfig.NetworkPoliciesKey),
+	})
+}
+
+func ErrorEgressDomainNotResolvable(domain string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrEgressDomainNotResolvable,
+		Message: fmt.Sprintf("unable to resolve %s to an ipv4 address", domain),
+	})
+}
+
+func ErrorEgressInstanceMetadataRequired(awsServices []string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrEgressInstanceMetadataRequired,
+		Message: fmt.Sprintf("cortex's containers need aws credentials to reach %s for this api, which they get from the instance metadata service; add %s/32 to %s, or set %s.%s to give the api its own iam role", s.StrsAnd(awsServices), _instanceMetadataIP, userconfig.AllowedCIDRsKey, userconfig.PodKey, userconfig.IAMRoleARNKey),
+	})
+}
+
+func ErrorPodSecurityPolicyViolation(message string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrPodSecurityPolicyViolation,
+		Message: fmt.Sprintf("%s (this is enforced by the cluster's %s configuration)", message, clusterconfig.PodSecurityKey),
+	})
+}
+
+func ErrorImageScanPolicyViolation(image string, message string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrImageScanPolicyViolation,
+		Message: fmt.Sprintf("%s: %s (this is enforced by the cluster's %s configuration; use `cortex deploy --skip-scan` to deploy anyway)", image, message, clusterconfig.ImageScanKey),
+	})
+}
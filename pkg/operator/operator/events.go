@@ -0,0 +1,137 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operator
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/cortexlabs/cortex/pkg/config"
+	"github.com/cortexlabs/cortex/pkg/lib/k8s"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	kapps "k8s.io/api/apps/v1"
+	kcore "k8s.io/api/core/v1"
+)
+
+const _maxAPIEvents = 20
+
+// replica set names are <deployment>-<hash>, and pod names are <deployment>-<hash>-<suffix>
+var _alphanumericRegex = regexp.MustCompile(`^[a-z0-9]+$`)
+
+// GetAPIEvents returns the most recent events for the deployment, its replica sets and its pods
+// (including OOM kills, which are only recorded in the pods' container statuses), sorted from oldest to newest
+func GetAPIEvents(deployment *kapps.Deployment, pods []kcore.Pod) ([]schema.Event, error) {
+	k8sEvents, err := config.K8s.WithNamespace(deployment.Namespace).ListEvents(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var relevantEvents []kcore.Event
+	for _, event := range k8sEvents {
+		if isEventForDeployment(&event, deployment.Name) {
+			relevantEvents = append(relevantEvents, event)
+		}
+	}
+
+	events := make([]schema.Event, 0, len(relevantEvents))
+	for i := range relevantEvents {
+		event := &relevantEvents[i]
+		count := event.Count
+		if event.Series != nil {
+			count = event.Series.Count
+		}
+		if count == 0 {
+			count = 1
+		}
+		events = append(events, schema.Event{
+			Type:      event.Type,
+			Reason:    event.Reason,
+			Object:    strings.ToLower(event.InvolvedObject.Kind) + "/" + event.InvolvedObject.Name,
+			Message:   strings.TrimSpace(event.Message),
+			Count:     count,
+			Timestamp: k8s.GetEventTime(event).Unix(),
+		})
+	}
+
+	events = append(events, oomKillEvents(pods)...)
+	sortEvents(events)
+
+	if len(events) > _maxAPIEvents {
+		events = events[len(events)-_maxAPIEvents:]
+	}
+
+	return events, nil
+}
+
+func isEventForDeployment(event *kcore.Event, deploymentName string) bool {
+	name := event.InvolvedObject.Name
+
+	switch event.InvolvedObject.Kind {
+	case "Deployment":
+		return name == deploymentName
+	case "ReplicaSet", "Pod":
+		if !strings.HasPrefix(name, deploymentName+"-") {
+			return false
+		}
+		// the number of name segments is checked to avoid matching the workloads of other APIs whose names share this prefix
+		segments := strings.Split(strings.TrimPrefix(name, deploymentName+"-"), "-")
+		if (event.InvolvedObject.Kind == "ReplicaSet" && len(segments) != 1) || (event.InvolvedObject.Kind == "Pod" && len(segments) != 2) {
+			return false
+		}
+		for _, segment := range segments {
+			if !_alphanumericRegex.MatchString(segment) {
+				return false
+			}
+		}
+		return true
+	}
+
+	return false
+}
+
+// Kubernetes does not emit an event when a container is OOM killed (only when the node itself runs out of memory)
+func oomKillEvents(pods []kcore.Pod) []schema.Event {
+	var events []schema.Event
+	for i := range pods {
+		pod := &pods[i]
+		for _, containerStatus := range pod.Status.ContainerStatuses {
+			terminated := containerStatus.LastTerminationState.Terminated
+			if terminated == nil {
+				terminated = containerStatus.State.Terminated
+			}
+			if terminated == nil || terminated.Reason != k8s.ReasonOOMKilled {
+				continue
+			}
+			events = append(events, schema.Event{
+				Type:      k8s.EventTypeWarning,
+				Reason:    k8s.ReasonOOMKilled,
+				Object:    "pod/" + pod.Name,
+				Message:   "container " + containerStatus.Name + " was killed because it exceeded its memory limit",
+				Count:     1,
+				Timestamp: terminated.FinishedAt.Unix(),
+			})
+		}
+	}
+	return events
+}
+
+func sortEvents(events []schema.Event) {
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Timestamp < events[j].Timestamp
+	})
+}
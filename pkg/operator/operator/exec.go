@@ -0,0 +1,171 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/cortexlabs/cortex/pkg/config"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	"github.com/cortexlabs/cortex/pkg/operator/lib/routines"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/gorilla/websocket"
+	kremotecommand "k8s.io/client-go/tools/remotecommand"
+	kexec "k8s.io/client-go/util/exec"
+)
+
+const (
+	_execSocketMaxMessageSize = 1 << 20
+)
+
+// ExecInReplica runs a command in a container of the replica, tunnelling its streams through the socket until the command exits or the client disconnects
+func ExecInReplica(podName string, namespace string, containerName string, command []string, tty bool, socket *websocket.Conn) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	writer := &execSocketWriter{socket: socket}
+	stdinReader, stdinWriter := io.Pipe()
+	sizeQueue := &execTerminalSizeQueue{
+		sizes: make(chan kremotecommand.TerminalSize, 1),
+		done:  ctx.Done(),
+	}
+
+	routines.RunWithPanicHandler(func() {
+		// the client disconnecting terminates the command
+		defer cancel()
+		defer stdinWriter.Close()
+		pumpExecInput(socket, stdinWriter, sizeQueue)
+	})
+
+	streamOptions := kremotecommand.StreamOptions{
+		Stdin:  stdinReader,
+		Stdout: writer.stream(schema.ExecStdoutStream),
+		Tty:    tty,
+	}
+	if tty {
+		streamOptions.TerminalSizeQueue = sizeQueue
+	} else {
+		streamOptions.Stderr = writer.stream(schema.ExecStderrStream)
+	}
+
+	exitCode := 0
+	err := config.K8s.WithNamespace(namespace).ExecStream(ctx, podName, containerName, command, streamOptions)
+	if err != nil {
+		if exitErr, ok := err.(kexec.ExitError); ok {
+			exitCode = exitErr.ExitStatus()
+		} else {
+			exitCode = 1
+			writer.write(schema.ExecStderrStream, []byte(fmt.Sprintf("error: unable to run %s in replica %s: %s\n", s.UserStr(strings.Join(command, " ")), podName, err.Error())))
+		}
+	}
+
+	writer.write(schema.ExecExitCodeStream, []byte(s.Int(exitCode)))
+	closeSocket(socket)
+}
+
+func pumpExecInput(socket *websocket.Conn, stdin *io.PipeWriter, sizeQueue *execTerminalSizeQueue) {
+	socket.SetReadLimit(_execSocketMaxMessageSize)
+	for {
+		_, message, err := socket.ReadMessage()
+		if err != nil {
+			return
+		}
+		if len(message) == 0 {
+			continue
+		}
+
+		switch message[0] {
+		case schema.ExecStdinStream:
+			if len(message) == 1 {
+				stdin.Close()
+				continue
+			}
+			if _, err := stdin.Write(message[1:]); err != nil {
+				// the command is no longer reading its stdin; keep reading the socket to detect the client disconnecting
+				continue
+			}
+		case schema.ExecResizeStream:
+			var size schema.ExecTerminalSize
+			if err := json.Unmarshal(message[1:], &size); err != nil {
+				continue
+			}
+			sizeQueue.push(kremotecommand.TerminalSize{Width: size.Width, Height: size.Height})
+		}
+	}
+}
+
+// execSocketWriter serializes the writes of the command's output streams to the socket
+type execSocketWriter struct {
+	socket *websocket.Conn
+	mux    sync.Mutex
+}
+
+func (w *execSocketWriter) write(stream byte, data []byte) error {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	return w.socket.WriteMessage(websocket.BinaryMessage, append([]byte{stream}, data...))
+}
+
+func (w *execSocketWriter) stream(stream byte) io.Writer {
+	return execStreamWriter{writer: w, stream: stream}
+}
+
+type execStreamWriter struct {
+	writer *execSocketWriter
+	stream byte
+}
+
+func (w execStreamWriter) Write(p []byte) (int, error) {
+	if err := w.writer.write(w.stream, p); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// execTerminalSizeQueue only holds the most recent terminal size which has not been applied yet
+type execTerminalSizeQueue struct {
+	sizes chan kremotecommand.TerminalSize
+	done  <-chan struct{}
+}
+
+func (q *execTerminalSizeQueue) push(size kremotecommand.TerminalSize) {
+	for {
+		select {
+		case q.sizes <- size:
+			return
+		default:
+			select {
+			case <-q.sizes:
+			default:
+			}
+		}
+	}
+}
+
+func (q *execTerminalSizeQueue) Next() *kremotecommand.TerminalSize {
+	select {
+	case size := <-q.sizes:
+		return &size
+	case <-q.done:
+		return nil
+	}
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operator
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/cortexlabs/cortex/pkg/config"
+	"github.com/cortexlabs/cortex/pkg/lib/k8s"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+	"github.com/cortexlabs/cortex/pkg/workloads"
+)
+
+const _iamRoleARNAnnotation = "eks.amazonaws.com/role-arn"
+
+// the cluster's oidc issuer doesn't change, so it's only looked up once
+var (
+	_clusterOIDCIssuer     string
+	_clusterOIDCIssuerLock sync.Mutex
+)
+
+func clusterOIDCIssuer() (string, error) {
+	_clusterOIDCIssuerLock.Lock()
+	defer _clusterOIDCIssuerLock.Unlock()
+
+	if _clusterOIDCIssuer != "" {
+		return _clusterOIDCIssuer, nil
+	}
+
+	issuer, err := config.AWS.EKSClusterOIDCIssuer(config.ClusterConfig.ClusterName)
+	if err != nil {
+		return "", err
+	}
+
+	_clusterOIDCIssuer = issuer
+	return issuer, nil
+}
+
+func iamRoleServiceAccountSubject(api *userconfig.API) string {
+	return fmt.Sprintf("system:serviceaccount:%s:%s", api.Namespace(), workloads.IAMRoleServiceAccountName(api.Name))
+}
+
+// ValidateIAMRole checks that the trust policy of the api's iam role allows the api's service account to assume it via the cluster's oidc provider
+func ValidateIAMRole(api *userconfig.API) error {
+	if api.Pod == nil || api.Pod.IAMRoleARN == nil {
+		return nil
+	}
+
+	issuer, err := clusterOIDCIssuer()
+	if err != nil {
+		return err
+	}
+
+	return config.AWS.ValidateIRSARole(*api.Pod.IAMRoleARN, issuer, iamRoleServiceAccountSubject(api))
+}
+
+// ApplyIAMRoleServiceAccount creates or updates the service account which is annotated with the api's iam role (the eks pod identity webhook provides
+// the role's credentials to the containers of pods which use it). If the api doesn't specify an iam role, any previously created service account is deleted.
+func ApplyIAMRoleServiceAccount(api *userconfig.API) error {
+	if api.Pod == nil || api.Pod.IAMRoleARN == nil {
+		return DeleteIAMRoleServiceAccount(api.Name, api.Namespace())
+	}
+
+	_, err := config.K8s.WithNamespace(api.Namespace()).ApplyServiceAccount(k8s.ServiceAccount(&k8s.ServiceAccountSpec{
+		Name: workloads.IAMRoleServiceAccountName(api.Name),
+		Labels: map[string]string{
+			"apiName": api.Name,
+			"apiKind": api.Kind.String(),
+		},
+		Annotations: map[string]string{
+			_iamRoleARNAnnotation:                      *api.Pod.IAMRoleARN,
+			"eks.amazonaws.com/sts-regional-endpoints": "true",
+		},
+	}))
+	return err
+}
+
+func DeleteIAMRoleServiceAccount(apiName string, namespace string) error {
+	_, err := config.K8s.WithNamespace(namespace).DeleteServiceAccount(workloads.IAMRoleServiceAccountName(apiName))
+	return err
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operator
+
+import (
+	"crypto/ecdsa"
+
+	"github.com/cortexlabs/cortex/pkg/config"
+	"github.com/cortexlabs/cortex/pkg/lib/aws"
+	"github.com/cortexlabs/cortex/pkg/lib/docker"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/regex"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+)
+
+// ResolveImageDigests sets the digest of each of the api's container images, so that the containers run the exact images which were referenced when the api was deployed.
+// Digests which are already set (e.g. when rolling back to a previously deployed version of the api) are kept.
+func ResolveImageDigests(api *userconfig.API) error {
+	if api.Pod == nil {
+		return nil
+	}
+
+	credentials, err := newImageCredentialsResolver(api)
+	if err != nil {
+		return err
+	}
+
+	for i, container := range api.Pod.Containers {
+		if container.ImageDigest != "" {
+			continue
+		}
+
+		username, password, err := credentials.forImage(container.Image)
+		if err != nil {
+			return errors.Wrap(err, userconfig.PodKey, userconfig.ContainersKey, s.Index(i), userconfig.ImageKey)
+		}
+
+		digest, err := docker.ResolveImageDigest(container.Image, username, password)
+		if err != nil {
+			return errors.Wrap(err, userconfig.PodKey, userconfig.ContainersKey, s.Index(i), userconfig.ImageKey)
+		}
+		container.ImageDigest = digest
+	}
+
+	return nil
+}
+
+// VerifyImageSignatures verifies that each of the api's container images (by digest) was signed with one of the public keys in the cluster's image signatures configuration
+func VerifyImageSignatures(api *userconfig.API) error {
+	imageSignatures := config.ClusterConfig.ImageSignatures
+	if imageSignatures == nil || api.Pod == nil {
+		return nil
+	}
+
+	publicKeys := make([]*ecdsa.PublicKey, len(imageSignatures.PublicKeys))
+	for i, publicKeyStr := range imageSignatures.PublicKeys {
+		publicKey, err := docker.ParseCosignPublicKey(publicKeyStr)
+		if err != nil {
+			return err
+		}
+		publicKeys[i] = publicKey
+	}
+
+	credentials, err := newImageCredentialsResolver(api)
+	if err != nil {
+		return err
+	}
+
+	for i, container := range api.Pod.Containers {
+		username, password, err := credentials.forImage(container.Image)
+		if err != nil {
+			return errors.Wrap(err, userconfig.PodKey, userconfig.ContainersKey, s.Index(i), userconfig.ImageKey)
+		}
+
+		if err := docker.VerifyCosignSignature(container.Image, container.ImageDigest, username, password, publicKeys); err != nil {
+			return errors.Wrap(err, userconfig.PodKey, userconfig.ContainersKey, s.Index(i), userconfig.ImageKey)
+		}
+	}
+
+	return nil
+}
+
+// imageCredentialsResolver returns the credentials which the operator uses to access an api's images: the api's registry credentials (if configured),
+// otherwise an ECR auth token for images which are hosted on ECR, otherwise no credentials
+type imageCredentialsResolver struct {
+	registryConfig *docker.RegistryConfig
+	ecrAuthConfigs map[string]aws.ECRAuthConfig // keyed by registry (i.e. account ID and region)
+}
+
+func newImageCredentialsResolver(api *userconfig.API) (*imageCredentialsResolver, error) {
+	resolver := &imageCredentialsResolver{
+		ecrAuthConfigs: map[string]aws.ECRAuthConfig{},
+	}
+
+	if api.Pod.RegistryCredentials != nil {
+		registryConfig, err := GetRegistryDockerConfig(api.Pod.RegistryCredentials, api.Pod.Containers, api.Namespace())
+		if err != nil {
+			return nil, errors.Wrap(err, userconfig.PodKey, userconfig.RegistryCredentialsKey)
+		}
+		resolver.registryConfig = registryConfig
+	}
+
+	return resolver, nil
+}
+
+func (r *imageCredentialsResolver) forImage(image string) (string, string, error) {
+	if r.registryConfig != nil {
+		if username, password, ok := r.registryConfig.CredentialsForImage(image); ok {
+			return username, password, nil
+		}
+	}
+
+	if !regex.IsValidECRURL(image) {
+		return "", "", nil
+	}
+
+	accountID := aws.GetAccountIDFromECRURL(image)
+	region := aws.GetRegionFromECRURL(image)
+	registry := accountID + "/" + region
+
+	if authConfig, ok := r.ecrAuthConfigs[registry]; ok {
+		return authConfig.Username, authConfig.AccessToken, nil
+	}
+
+	awsClient, err := ecrAWSClient(region, nil)
+	if err != nil {
+		return "", "", err
+	}
+
+	authConfigs, err := awsClient.GetECRAuthConfigsForRegistries(accountID)
+	if err != nil {
+		return "", "", err
+	}
+
+	r.ecrAuthConfigs[registry] = authConfigs[0]
+	return authConfigs[0].Username, authConfigs[0].AccessToken, nil
+}
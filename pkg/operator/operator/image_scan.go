@@ -0,0 +1,152 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/service/ecr"
+	"github.com/cortexlabs/cortex/pkg/config"
+	"github.com/cortexlabs/cortex/pkg/lib/aws"
+	"github.com/cortexlabs/cortex/pkg/lib/docker"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/regex"
+	"github.com/cortexlabs/cortex/pkg/lib/sets/strset"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types/clusterconfig"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+)
+
+const (
+	_ecrRoleSessionName        = "cortex-operator-ecr"
+	_imageScanStatusNotScanned = "not_scanned"
+)
+
+// ScanAPIImages checks the ECR scan findings of the api's container images against the cluster's image scan configuration. The images are scanned by
+// their digests if they have been resolved (by ResolveImageDigests), so that the scanned images are the ones which are deployed.
+// A summary is returned for each image; if the configured action is to block, an error is returned for the first image which violates the configuration.
+func ScanAPIImages(api *userconfig.API) ([]schema.ImageScanSummary, error) {
+	imageScan := config.ClusterConfig.ImageScan
+	if imageScan == nil || api.Pod == nil {
+		return nil, nil
+	}
+
+	var roleARN *string
+	if api.Pod.RegistryCredentials != nil {
+		roleARN = api.Pod.RegistryCredentials.ECRRoleARN
+	}
+
+	images := strset.New()
+	for _, container := range api.Pod.Containers {
+		if container.ImageDigest != "" {
+			images.Add(docker.ImageWithDigest(container.Image, container.ImageDigest))
+		} else {
+			images.Add(container.Image)
+		}
+	}
+
+	summaries := make([]schema.ImageScanSummary, 0, len(images))
+	for _, image := range images.SliceSorted() {
+		summary, violation, err := scanImage(image, imageScan, roleARN)
+		if err != nil {
+			return summaries, errors.Wrap(err, image)
+		}
+
+		if violation != "" {
+			if imageScan.Action == clusterconfig.BlockImageScanAction {
+				summaries = append(summaries, summary)
+				return summaries, ErrorImageScanPolicyViolation(image, violation)
+			}
+			summary.Warning = violation
+		}
+
+		summaries = append(summaries, summary)
+	}
+
+	return summaries, nil
+}
+
+// scanImage returns the image's scan summary, and a description of how it violates the image scan configuration (or "" if it doesn't)
+func scanImage(image string, imageScan *clusterconfig.ImageScan, roleARN *string) (schema.ImageScanSummary, string, error) {
+	summary := schema.ImageScanSummary{
+		Image:  image,
+		Status: _imageScanStatusNotScanned,
+	}
+
+	if !regex.IsValidECRURL(image) {
+		if imageScan.BlockUnscanned {
+			return summary, "only images which are hosted on ECR can be scanned", nil
+		}
+		return summary, "", nil
+	}
+
+	awsClient, err := ecrAWSClient(aws.GetRegionFromECRURL(image), roleARN)
+	if err != nil {
+		return summary, "", err
+	}
+
+	_, repository, reference := docker.ParseImageReference(image)
+	findings, err := awsClient.GetECRImageScanFindings(aws.GetAccountIDFromECRURL(image), repository, reference)
+	if err != nil {
+		return summary, "", err
+	}
+
+	if findings.Status != ecr.ScanStatusComplete {
+		if findings.Status != "" {
+			summary.Status = strings.ToLower(findings.Status)
+		}
+		if imageScan.BlockUnscanned {
+			return summary, "the image's scan has not completed (the image must be scanned by ECR before it can be deployed)", nil
+		}
+		return summary, "", nil
+	}
+
+	summary.Status = strings.ToLower(findings.Status)
+	summary.SeverityCounts = map[string]int64{}
+	for severity, count := range findings.SeverityCounts {
+		summary.SeverityCounts[strings.ToLower(severity)] = count
+	}
+
+	if count := findings.CountAtOrAbove(imageScan.SeverityThreshold); count > 0 {
+		return summary, fmt.Sprintf("found %d %s with severity %s or higher (%s)", count, s.PluralCustom("vulnerability", "vulnerabilities", count), imageScan.SeverityThreshold, severityCountsStr(summary.SeverityCounts)), nil
+	}
+
+	return summary, "", nil
+}
+
+// images which are pulled by assuming an ECR role are accessed with the same role, since they may belong to another account
+func ecrAWSClient(region string, roleARN *string) (*aws.Client, error) {
+	if roleARN != nil {
+		return config.AWS.AssumeRole(*roleARN, _ecrRoleSessionName, region)
+	}
+	if region == config.AWS.Region {
+		return config.AWS, nil
+	}
+	return aws.NewForRegion(region)
+}
+
+func severityCountsStr(severityCounts map[string]int64) string {
+	var strs []string
+	for _, severity := range aws.ECRFindingSeverities {
+		if count := severityCounts[strings.ToLower(severity)]; count > 0 {
+			strs = append(strs, fmt.Sprintf("%s: %d", strings.ToLower(severity), count))
+		}
+	}
+	return strings.Join(strs, ", ")
+}
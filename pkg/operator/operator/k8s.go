@@ -20,9 +20,11 @@ import (
 	"strings"
 
 	"github.com/cortexlabs/cortex/pkg/config"
+	"github.com/cortexlabs/cortex/pkg/lib/k8s"
 	"github.com/cortexlabs/cortex/pkg/lib/urls"
 	"github.com/cortexlabs/cortex/pkg/types/spec"
 	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+	"github.com/cortexlabs/cortex/pkg/workloads"
 )
 
 // APILoadBalancerURL returns the http endpoint of the ingress load balancer for deployed APIs
@@ -30,6 +32,11 @@ func APILoadBalancerURL() (string, error) {
 	return getLoadBalancerURL("ingressgateway-apis")
 }
 
+// InternalAPILoadBalancerURL returns the http endpoint of the internal ingress load balancer for deployed APIs whose endpoint visibility is internal
+func InternalAPILoadBalancerURL() (string, error) {
+	return getLoadBalancerURL("ingressgateway-apis-internal")
+}
+
 // LoadBalancerURL returns the http endpoint of the ingress load balancer for the operator
 func LoadBalancerURL() (string, error) {
 	return getLoadBalancerURL("ingressgateway-operator")
@@ -53,14 +60,14 @@ func getLoadBalancerURL(name string) (string, error) {
 }
 
 func APIEndpoint(api *spec.API) (string, error) {
-	var err error
-	baseAPIEndpoint := ""
+	if api.Networking.CustomDomain != nil {
+		return urls.Join("https://"+*api.Networking.CustomDomain, *api.Networking.Endpoint), nil
+	}
 
-	baseAPIEndpoint, err = APILoadBalancerURL()
+	baseAPIEndpoint, err := apiLoadBalancerURLForGateway(workloads.APIGateway(api.Networking))
 	if err != nil {
 		return "", err
 	}
-	baseAPIEndpoint = strings.Replace(baseAPIEndpoint, "https://", "http://", 1)
 
 	return urls.Join(baseAPIEndpoint, *api.Networking.Endpoint), nil
 }
@@ -71,13 +78,35 @@ func APIEndpointFromResource(deployedResource *DeployedResource) (string, error)
 		return "", err
 	}
 
-	baseAPIEndpoint := ""
+	if customDomain, ok := deployedResource.VirtualService.Annotations[userconfig.CustomDomainAnnotationKey]; ok {
+		return urls.Join("https://"+customDomain, apiEndpoint), nil
+	}
+
+	gateway := workloads.APIsGateway
+	if k8s.ExtractVirtualServiceGateways(deployedResource.VirtualService).Has(workloads.InternalAPIsGateway) {
+		gateway = workloads.InternalAPIsGateway
+	}
 
-	baseAPIEndpoint, err = APILoadBalancerURL()
+	baseAPIEndpoint, err := apiLoadBalancerURLForGateway(gateway)
 	if err != nil {
 		return "", err
 	}
-	baseAPIEndpoint = strings.Replace(baseAPIEndpoint, "https://", "http://", 1)
 
 	return urls.Join(baseAPIEndpoint, apiEndpoint), nil
 }
+
+func apiLoadBalancerURLForGateway(gateway string) (string, error) {
+	var baseAPIEndpoint string
+	var err error
+
+	if gateway == workloads.InternalAPIsGateway {
+		baseAPIEndpoint, err = InternalAPILoadBalancerURL()
+	} else {
+		baseAPIEndpoint, err = APILoadBalancerURL()
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return strings.Replace(baseAPIEndpoint, "https://", "http://", 1), nil
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operator
+
+import (
+	"github.com/cortexlabs/cortex/pkg/config"
+	"github.com/cortexlabs/cortex/pkg/lib/hash"
+	"github.com/cortexlabs/cortex/pkg/lib/k8s"
+	"github.com/cortexlabs/cortex/pkg/lib/pointer"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+	"github.com/cortexlabs/cortex/pkg/workloads"
+	kbatch "k8s.io/api/batch/v1"
+	kcore "k8s.io/api/core/v1"
+)
+
+// finished removal jobs are garbage collected by kubernetes after this long
+const _modelCacheRemovalTTLSeconds = 10 * 60
+
+func modelCacheRemovalK8sName(api *spec.API, nodeName string) string {
+	return workloads.K8sName(api.Name) + "-rm-model-" + hash.String(api.ID + nodeName)[:8]
+}
+
+func modelCacheRemovalJobSpec(api *spec.API, nodeName string) *kbatch.Job {
+	return k8s.Job(&k8s.JobSpec{
+		Name:                    modelCacheRemovalK8sName(api, nodeName),
+		Parallelism:             1,
+		Completions:             pointer.Int32(1),
+		BackoffLimit:            2,
+		TTLSecondsAfterFinished: pointer.Int32(_modelCacheRemovalTTLSeconds),
+		Labels: map[string]string{
+			"modelCacheRemovalOf": api.Name,
+		},
+		PodSpec: k8s.PodSpec{
+			Labels: map[string]string{
+				"modelCacheRemovalOf": api.Name,
+			},
+			K8sPodSpec: kcore.PodSpec{
+				NodeName:                      nodeName,
+				RestartPolicy:                 kcore.RestartPolicyNever,
+				TerminationGracePeriodSeconds: pointer.Int64(0),
+				Containers:                    []kcore.Container{workloads.ModelCacheRemovalContainer(*api)},
+				Tolerations:                   workloads.GenerateResourceTolerations(),
+				Volumes:                       []kcore.Volume{workloads.ModelCacheVolume()},
+			},
+		},
+	})
+}
+
+// RemoveCachedModel removes the api's model from the model cache of each of the cluster's workload nodes,
+// unless another api caches the same model
+func RemoveCachedModel(api *spec.API) error {
+	if api.ModelCache == nil {
+		return nil
+	}
+
+	isShared, err := isCachedModelShared(api)
+	if err != nil {
+		return err
+	}
+	if isShared {
+		return nil
+	}
+
+	nodes, err := config.K8s.ListNodesByLabels(workloads.NodeSelectors())
+	if err != nil {
+		return err
+	}
+
+	for i := range nodes {
+		job := modelCacheRemovalJobSpec(api, nodes[i].Name)
+		existingJob, err := config.K8s.GetJob(job.Name)
+		if err != nil {
+			return err
+		}
+		if existingJob != nil {
+			continue
+		}
+		if _, err := config.K8s.CreateJob(job); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// returns true if another realtime or async api caches the same model as the api
+func isCachedModelShared(api *spec.API) (bool, error) {
+	virtualServices, err := config.K8sAllNamspaces.ListVirtualServicesWithLabelKeys("apiName")
+	if err != nil {
+		return false, err
+	}
+
+	var apiNames, apiIDs []string
+	for _, vs := range virtualServices {
+		apiName := vs.Labels["apiName"]
+		apiKind := userconfig.KindFromString(vs.Labels["apiKind"])
+		if apiName == api.Name || (apiKind != userconfig.RealtimeAPIKind && apiKind != userconfig.AsyncAPIKind) {
+			continue
+		}
+		apiNames = append(apiNames, apiName)
+		apiIDs = append(apiIDs, vs.Labels["apiID"])
+	}
+
+	apis, err := DownloadAPISpecs(apiNames, apiIDs)
+	if err != nil {
+		return false, err
+	}
+
+	s3Path := workloads.ModelCachePath(*api)
+	for i := range apis {
+		if apis[i].ModelCache != nil && workloads.ModelCachePath(apis[i]) == s3Path {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
@@ -0,0 +1,38 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operator
+
+import (
+	"github.com/cortexlabs/cortex/pkg/config"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+)
+
+// LatestModelVersion returns the most recent version of the api's model, or an empty string if model_watch is not configured
+func LatestModelVersion(apiConfig *userconfig.API) (string, error) {
+	if apiConfig.ModelWatch == nil {
+		return "", nil
+	}
+
+	modelVersion, err := spec.LatestModelVersion(apiConfig.ModelWatch.Path, config.AWS)
+	if err != nil {
+		return "", errors.Wrap(err, apiConfig.Resource.UserString(), userconfig.ModelWatchKey)
+	}
+
+	return modelVersion, nil
+}
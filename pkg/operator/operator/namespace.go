@@ -0,0 +1,176 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operator
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/apikeys"
+	"github.com/cortexlabs/cortex/pkg/config"
+	"github.com/cortexlabs/cortex/pkg/consts"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/k8s"
+	"github.com/cortexlabs/cortex/pkg/lib/maps"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+)
+
+const ProjectNamespacesCronPeriod = time.Minute
+
+// the configmaps and secrets in the default namespace which are mounted by the apis' pods, and are therefore copied into each project's namespace
+var (
+	_projectConfigMaps = []string{"env-vars", "cluster-config", "client-config"}
+	_projectSecrets    = []string{apikeys.SecretName}
+)
+
+// ApplyProjectNamespace creates the namespace of the project (if it doesn't already exist) and copies the cluster's shared configmaps and secrets into it
+func ApplyProjectNamespace(project string) error {
+	namespace := userconfig.ProjectNamespace(project)
+	if namespace == consts.DefaultNamespace {
+		return nil
+	}
+
+	existing, err := config.K8s.GetNamespace(namespace)
+	if err != nil {
+		return err
+	}
+
+	if existing == nil {
+		_, err = config.K8s.CreateNamespace(k8s.Namespace(&k8s.NamespaceSpec{
+			Name: namespace,
+			Labels: map[string]string{
+				userconfig.ProjectLabelKey: project,
+			},
+		}))
+		if err != nil {
+			return err
+		}
+	} else if existing.Labels[userconfig.ProjectLabelKey] != project {
+		if existing.Labels == nil {
+			existing.Labels = map[string]string{}
+		}
+		existing.Labels[userconfig.ProjectLabelKey] = project
+		if _, err := config.K8s.UpdateNamespace(existing); err != nil {
+			return err
+		}
+	}
+
+	return syncProjectNamespace(namespace)
+}
+
+// ListAPINamespaces returns the namespaces which apis may be deployed to (the default namespace and the namespace of each project)
+func ListAPINamespaces() ([]string, error) {
+	projectNamespaces, err := config.K8s.ListNamespacesWithLabelKeys(userconfig.ProjectLabelKey)
+	if err != nil {
+		return nil, err
+	}
+
+	namespaces := []string{consts.DefaultNamespace}
+	for _, namespace := range projectNamespaces {
+		if namespace.Name != consts.DefaultNamespace {
+			namespaces = append(namespaces, namespace.Name)
+		}
+	}
+	return namespaces, nil
+}
+
+// SyncProjectNamespaces copies the cluster's shared configmaps and secrets into the namespace of each project
+// (e.g. so that api keys which are created or revoked, or a configuration which is updated by `cortex cluster configure`, apply to all projects);
+// copies which are already up to date are not written
+func SyncProjectNamespaces() error {
+	namespaces, err := ListAPINamespaces()
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, namespace := range namespaces {
+		if namespace == consts.DefaultNamespace {
+			continue
+		}
+		if err := syncProjectNamespace(namespace); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.FirstError(errs...)
+}
+
+func syncProjectNamespace(namespace string) error {
+	k8sClient := config.K8s.WithNamespace(namespace)
+
+	for _, name := range _projectConfigMaps {
+		configMap, err := config.K8s.GetConfigMap(name)
+		if err != nil {
+			return err
+		}
+		if configMap == nil {
+			continue
+		}
+		existing, err := k8sClient.GetConfigMap(name)
+		if err != nil {
+			return err
+		}
+		if existing != nil && reflect.DeepEqual(existing.Data, configMap.Data) && reflect.DeepEqual(existing.BinaryData, configMap.BinaryData) &&
+			maps.StrMapsEqualString(existing.Labels, configMap.Labels) {
+			continue
+		}
+		_, err = k8sClient.ApplyConfigMap(k8s.ConfigMap(&k8s.ConfigMapSpec{
+			Name:       name,
+			Data:       configMap.Data,
+			BinaryData: configMap.BinaryData,
+			Labels:     configMap.Labels,
+		}))
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, name := range _projectSecrets {
+		secret, err := config.K8s.GetSecret(name)
+		if err != nil {
+			return err
+		}
+		existing, err := k8sClient.GetSecret(name)
+		if err != nil {
+			return err
+		}
+		if secret == nil {
+			if existing != nil {
+				if _, err := k8sClient.DeleteSecret(name); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if existing != nil && existing.Type == secret.Type && reflect.DeepEqual(existing.Data, secret.Data) &&
+			maps.StrMapsEqualString(existing.Labels, secret.Labels) {
+			continue
+		}
+		_, err = k8sClient.ApplySecret(k8s.Secret(&k8s.SecretSpec{
+			Name:   name,
+			Type:   secret.Type,
+			Data:   secret.Data,
+			Labels: secret.Labels,
+		}))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,363 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operator
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/config"
+	"github.com/cortexlabs/cortex/pkg/consts"
+	"github.com/cortexlabs/cortex/pkg/lib/aws"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	libjson "github.com/cortexlabs/cortex/pkg/lib/json"
+	"github.com/cortexlabs/cortex/pkg/lib/k8s"
+	"github.com/cortexlabs/cortex/pkg/lib/sets/strset"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+	"github.com/cortexlabs/cortex/pkg/workloads"
+	kcore "k8s.io/api/core/v1"
+	knetworking "k8s.io/api/networking/v1"
+	kmeta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const NetworkPoliciesCronPeriod = time.Minute
+
+const (
+	_egressCIDRsAnnotation             = "networking.cortex.dev/egress-cidrs"
+	_egressDomainsAnnotation           = "networking.cortex.dev/egress-domains"
+	_egressAWSServicesAnnotation       = "networking.cortex.dev/egress-aws-services"
+	_egressResolvedAddressesAnnotation = "networking.cortex.dev/egress-resolved-addresses"
+
+	_instanceMetadataIP = "169.254.169.254"
+
+	// addresses which a domain no longer resolves to are kept in the policy for this long, since
+	// many domains (including aws service endpoints) rotate through a set of addresses
+	_resolvedAddressRetention = time.Hour
+	_domainLookupTimeout      = 5 * time.Second
+)
+
+// the aws services which cortex's containers (e.g. the async dequeuer) may need to reach
+const (
+	_awsServiceS3      = "s3"
+	_awsServiceSQS     = "sqs"
+	_awsServiceSTS     = "sts"
+	_awsServiceKinesis = "kinesis"
+)
+
+// domain -> address -> unix time at which the domain last resolved to the address
+type resolvedAddresses map[string]map[string]int64
+
+func networkPolicyName(apiName string) string {
+	return workloads.K8sName(apiName) + "-egress"
+}
+
+// ValidateNetworkPolicy checks that the cluster enforces network policies if the api restricts its egress, that the allowed domains can be resolved,
+// and that cortex's containers can get aws credentials (from the instance metadata service, unless the api has its own iam role) if they need to reach aws services
+func ValidateNetworkPolicy(api *userconfig.API) error {
+	if api.Networking == nil || api.Networking.Egress == nil {
+		return nil
+	}
+
+	if !config.ClusterConfig.NetworkPolicies {
+		return ErrorNetworkPoliciesDisabled()
+	}
+
+	awsServices := egressAWSServices(api)
+	if len(awsServices) > 0 && (api.Pod == nil || api.Pod.IAMRoleARN == nil) && !allowsInstanceMetadata(api.Networking.Egress.AllowedCIDRs) {
+		return errors.Wrap(ErrorEgressInstanceMetadataRequired(awsServices), userconfig.AllowedCIDRsKey)
+	}
+
+	for i, domain := range api.Networking.Egress.AllowedDomains {
+		addresses, err := lookupIPv4(domain)
+		if err != nil || len(addresses) == 0 {
+			return errors.Wrap(ErrorEgressDomainNotResolvable(domain), userconfig.AllowedDomainsKey, s.Index(i))
+		}
+	}
+
+	return nil
+}
+
+// ApplyNetworkPolicy creates or updates the network policy which restricts the egress of the api's pods (if the api doesn't restrict its egress, any
+// previously created network policy is deleted). Traffic to the cluster's dns and the aws services which cortex's containers use for the api is always
+// allowed; the instance metadata service is only allowed if it is in the api's allowed cidrs.
+func ApplyNetworkPolicy(api *userconfig.API) error {
+	if api.Networking == nil || api.Networking.Egress == nil {
+		return DeleteNetworkPolicy(api.Name, api.Namespace())
+	}
+
+	annotations := map[string]string{
+		_egressCIDRsAnnotation:       strings.Join(api.Networking.Egress.AllowedCIDRs, ","),
+		_egressDomainsAnnotation:     strings.Join(api.Networking.Egress.AllowedDomains, ","),
+		_egressAWSServicesAnnotation: strings.Join(egressAWSServices(api), ","),
+	}
+
+	k8sClient := config.K8s.WithNamespace(api.Namespace())
+
+	existing, err := k8sClient.GetNetworkPolicy(networkPolicyName(api.Name))
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		annotations[_egressResolvedAddressesAnnotation] = existing.Annotations[_egressResolvedAddressesAnnotation]
+	}
+
+	networkPolicy, err := networkPolicySpec(api.Name, api.Kind.String(), annotations)
+	if err != nil {
+		return err
+	}
+
+	_, err = k8sClient.ApplyNetworkPolicy(networkPolicy)
+	return err
+}
+
+func DeleteNetworkPolicy(apiName string, namespace string) error {
+	_, err := config.K8s.WithNamespace(namespace).DeleteNetworkPolicy(networkPolicyName(apiName))
+	return err
+}
+
+// RefreshNetworkPolicies re-resolves the allowed domains of each api's network policy, and updates the policies whose addresses have changed
+func RefreshNetworkPolicies() error {
+	if !config.ClusterConfig.NetworkPolicies {
+		return nil
+	}
+
+	networkPolicies, err := config.K8sAllNamspaces.ListNetworkPoliciesWithLabelKeys("apiName", "apiKind")
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for i := range networkPolicies {
+		existing := &networkPolicies[i]
+
+		networkPolicy, err := networkPolicySpec(existing.Labels["apiName"], existing.Labels["apiKind"], existing.Annotations)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		if networkPolicy.Annotations[_egressResolvedAddressesAnnotation] == existing.Annotations[_egressResolvedAddressesAnnotation] {
+			continue
+		}
+
+		if _, err := config.K8s.WithNamespace(existing.Namespace).ApplyNetworkPolicy(networkPolicy); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.FirstError(errs...)
+}
+
+func egressAWSServices(api *userconfig.API) []string {
+	services := strset.New()
+
+	switch api.Kind {
+	case userconfig.AsyncAPIKind, userconfig.BatchAPIKind:
+		services.Add(_awsServiceS3, _awsServiceSQS)
+	}
+	if api.ModelCache != nil || api.ModelWatch != nil {
+		services.Add(_awsServiceS3)
+	}
+	if api.RequestLogging != nil {
+		if api.RequestLogging.S3Path != nil {
+			services.Add(_awsServiceS3)
+		}
+		if api.RequestLogging.KinesisStream != nil {
+			services.Add(_awsServiceKinesis)
+		}
+	}
+	if api.Pod != nil && api.Pod.IAMRoleARN != nil {
+		services.Add(_awsServiceSTS)
+	}
+
+	return services.SliceSorted()
+}
+
+// networkPolicySpec renders the network policy from the egress configuration which is stored in its annotations
+func networkPolicySpec(apiName string, apiKind string, annotations map[string]string) (*knetworking.NetworkPolicy, error) {
+	cidrs := strset.New(splitAnnotation(annotations[_egressCIDRsAnnotation])...)
+
+	domains := splitAnnotation(annotations[_egressDomainsAnnotation])
+	for _, service := range splitAnnotation(annotations[_egressAWSServicesAnnotation]) {
+		switch service {
+		case _awsServiceS3:
+			// s3 rotates through too many addresses to be resolved reliably, but its ranges are published
+			s3CIDRs, err := aws.ServiceIPRanges("S3", config.ClusterConfig.Region)
+			if err != nil {
+				return nil, err
+			}
+			cidrs.Add(s3CIDRs...)
+		case _awsServiceSQS, _awsServiceSTS, _awsServiceKinesis:
+			domains = append(domains, fmt.Sprintf("%s.%s.amazonaws.com", service, config.ClusterConfig.Region))
+		}
+	}
+
+	var prevResolved resolvedAddresses
+	if annotations[_egressResolvedAddressesAnnotation] != "" {
+		// if the annotation can't be parsed, the domains will be resolved from scratch
+		_ = libjson.Unmarshal([]byte(annotations[_egressResolvedAddressesAnnotation]), &prevResolved)
+	}
+	resolved := resolveDomains(domains, prevResolved, time.Now())
+	for _, addresses := range resolved {
+		for address := range addresses {
+			cidrs.Add(address + "/32")
+		}
+	}
+
+	resolvedBytes, err := libjson.Marshal(resolved)
+	if err != nil {
+		return nil, err
+	}
+
+	var ipBlockPeers []knetworking.NetworkPolicyPeer
+	for _, cidr := range cidrs.SliceSorted() {
+		ipBlockPeers = append(ipBlockPeers, knetworking.NetworkPolicyPeer{
+			IPBlock: &knetworking.IPBlock{CIDR: cidr},
+		})
+	}
+
+	return k8s.NetworkPolicy(&k8s.NetworkPolicySpec{
+		Name: networkPolicyName(apiName),
+		PodSelector: map[string]string{
+			"apiName": apiName,
+		},
+		Egress: []knetworking.NetworkPolicyEgressRule{
+			dnsEgressRule(),
+			statsDEgressRule(),
+			{To: ipBlockPeers},
+		},
+		Labels: map[string]string{
+			"apiName": apiName,
+			"apiKind": apiKind,
+		},
+		Annotations: map[string]string{
+			_egressCIDRsAnnotation:             annotations[_egressCIDRsAnnotation],
+			_egressDomainsAnnotation:           annotations[_egressDomainsAnnotation],
+			_egressAWSServicesAnnotation:       annotations[_egressAWSServicesAnnotation],
+			_egressResolvedAddressesAnnotation: string(resolvedBytes),
+		},
+	}), nil
+}
+
+func allowsInstanceMetadata(cidrs []string) bool {
+	instanceMetadataIP := net.ParseIP(_instanceMetadataIP)
+	for _, cidr := range cidrs {
+		if _, ipNet, err := net.ParseCIDR(cidr); err == nil && ipNet.Contains(instanceMetadataIP) {
+			return true
+		}
+	}
+	return false
+}
+
+func dnsEgressRule() knetworking.NetworkPolicyEgressRule {
+	dnsPort := intstr.FromInt(53)
+	return knetworking.NetworkPolicyEgressRule{
+		To: []knetworking.NetworkPolicyPeer{
+			{
+				NamespaceSelector: &kmeta.LabelSelector{
+					MatchLabels: map[string]string{"kubernetes.io/metadata.name": "kube-system"},
+				},
+				PodSelector: &kmeta.LabelSelector{
+					MatchLabels: map[string]string{"k8s-app": "kube-dns"},
+				},
+			},
+		},
+		Ports: []knetworking.NetworkPolicyPort{
+			{Protocol: protocolPtr(kcore.ProtocolUDP), Port: &dnsPort},
+			{Protocol: protocolPtr(kcore.ProtocolTCP), Port: &dnsPort},
+		},
+	}
+}
+
+// cortex's containers send metrics to the statsd exporter
+func statsDEgressRule() knetworking.NetworkPolicyEgressRule {
+	return knetworking.NetworkPolicyEgressRule{
+		To: []knetworking.NetworkPolicyPeer{
+			{
+				NamespaceSelector: &kmeta.LabelSelector{
+					MatchLabels: map[string]string{"kubernetes.io/metadata.name": consts.PrometheusNamespace},
+				},
+				PodSelector: &kmeta.LabelSelector{
+					MatchLabels: map[string]string{"name": "prometheus-statsd-exporter"},
+				},
+			},
+		},
+	}
+}
+
+// resolveDomains adds the domains' current addresses to the previously resolved addresses, and drops the addresses which haven't been seen within the retention period
+// (a domain keeps its previous addresses if it can't currently be resolved)
+func resolveDomains(domains []string, prev resolvedAddresses, now time.Time) resolvedAddresses {
+	resolved := resolvedAddresses{}
+
+	for _, domain := range strset.New(domains...).SliceSorted() {
+		addresses := map[string]int64{}
+		for address, lastSeen := range prev[domain] {
+			if now.Sub(time.Unix(lastSeen, 0)) < _resolvedAddressRetention {
+				addresses[address] = lastSeen
+			}
+		}
+
+		currentAddresses, err := lookupIPv4(domain)
+		if err != nil {
+			operatorLogger.Warnf("unable to resolve %s for egress network policies: %s", domain, errors.Message(err))
+		}
+		for _, address := range currentAddresses {
+			addresses[address] = now.Unix()
+		}
+
+		if len(addresses) > 0 {
+			resolved[domain] = addresses
+		}
+	}
+
+	return resolved
+}
+
+func lookupIPv4(domain string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), _domainLookupTimeout)
+	defer cancel()
+
+	ips, err := net.DefaultResolver.LookupIP(ctx, "ip4", domain)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	addresses := make([]string, 0, len(ips))
+	for _, ip := range ips {
+		addresses = append(addresses, ip.String())
+	}
+	sort.Strings(addresses)
+	return addresses, nil
+}
+
+func splitAnnotation(value string) []string {
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, ",")
+}
+
+func protocolPtr(protocol kcore.Protocol) *kcore.Protocol {
+	return &protocol
+}
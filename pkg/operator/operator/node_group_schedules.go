@@ -0,0 +1,115 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operator
+
+import (
+	"strings"
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/config"
+	"github.com/cortexlabs/cortex/pkg/lib/cron"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/types/clusterconfig"
+)
+
+const NodeGroupSchedulesCronPeriod = time.Minute
+
+// ApplyNodeGroupSchedules sets the min/max instances of each scheduled nodegroup's autoscaling group
+// to the values of the nodegroup's most recently fired schedule
+func ApplyNodeGroupSchedules() error {
+	activeSchedules := activeNodeGroupSchedules(config.ClusterConfig.Schedules, time.Now().UTC())
+	if len(activeSchedules) == 0 {
+		return nil
+	}
+
+	asgs, err := config.AWS.AutoscalingGroups(map[string]string{clusterconfig.ClusterNameTag: config.ClusterConfig.ClusterName})
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, asg := range asgs {
+		if asg.AutoScalingGroupName == nil || asg.MinSize == nil || asg.MaxSize == nil {
+			continue
+		}
+
+		var eksNodeGroupName string
+		for _, tag := range asg.Tags {
+			if tag.Key != nil && tag.Value != nil && *tag.Key == "alpha.eksctl.io/nodegroup-name" {
+				eksNodeGroupName = *tag.Value
+				break
+			}
+		}
+
+		// e.g. cx-wd-ng-gpu -> ng-gpu
+		if !strings.HasPrefix(eksNodeGroupName, "cx-wd-") && !strings.HasPrefix(eksNodeGroupName, "cx-ws-") {
+			continue
+		}
+		nodeGroupName := eksNodeGroupName[len("cx-wd-"):]
+
+		schedule, ok := activeSchedules[nodeGroupName]
+		if !ok {
+			continue
+		}
+
+		if *asg.MinSize == schedule.MinInstances && *asg.MaxSize == schedule.MaxInstances {
+			continue
+		}
+
+		operatorLogger.Infow("applying nodegroup schedule",
+			"nodegroup", nodeGroupName,
+			"cron", schedule.Cron,
+			"min_instances", schedule.MinInstances,
+			"max_instances", schedule.MaxInstances,
+		)
+
+		if err := config.AWS.UpdateAutoscalingGroupSize(*asg.AutoScalingGroupName, schedule.MinInstances, schedule.MaxInstances); err != nil {
+			errs = append(errs, errors.Wrap(err, nodeGroupName))
+		}
+	}
+
+	if errors.HasError(errs) {
+		return errors.FirstError(errs...)
+	}
+	return nil
+}
+
+// returns nodegroup name -> the schedule which fired most recently (ties go to the schedule listed last)
+func activeNodeGroupSchedules(schedules []*clusterconfig.Schedule, now time.Time) map[string]*clusterconfig.Schedule {
+	activeSchedules := map[string]*clusterconfig.Schedule{}
+	lastFired := map[string]time.Time{}
+
+	for _, schedule := range schedules {
+		cronSchedule, err := cron.ParseSchedule(schedule.Cron)
+		if err != nil {
+			continue // the cron expression has already been validated
+		}
+
+		prev, ok := cronSchedule.Prev(now)
+		if !ok {
+			continue
+		}
+
+		if fired, ok := lastFired[schedule.NodeGroup]; ok && prev.Before(fired) {
+			continue
+		}
+		activeSchedules[schedule.NodeGroup] = schedule
+		lastFired[schedule.NodeGroup] = prev
+	}
+
+	return activeSchedules
+}
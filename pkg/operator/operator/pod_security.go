@@ -0,0 +1,91 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operator
+
+import (
+	"fmt"
+
+	"github.com/cortexlabs/cortex/pkg/config"
+	"github.com/cortexlabs/cortex/pkg/lib/slices"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+)
+
+// EnforcePodSecurityPolicy applies the cluster's pod security policy to the api's pod security configuration. Settings which the policy
+// requires are added to the api's configuration, and an error is returned if the api explicitly requests something which the policy disallows.
+func EnforcePodSecurityPolicy(api *userconfig.API) error {
+	policy := config.ClusterConfig.PodSecurity
+	if policy == nil || api.Pod == nil {
+		return nil
+	}
+
+	if api.Pod.Security == nil {
+		api.Pod.Security = &userconfig.PodSecurity{
+			DropCapabilities: []string{},
+			SeccompProfile:   userconfig.SeccompProfileRuntimeDefault,
+		}
+	}
+
+	security := api.Pod.Security
+
+	if security.Privileged && !policy.AllowPrivileged {
+		return ErrorPodSecurityPolicyViolation(fmt.Sprintf("%s can't be true", userconfig.PrivilegedKey))
+	}
+
+	if security.SeccompProfile == userconfig.SeccompProfileUnconfined && !policy.AllowUnconfinedSeccomp {
+		return ErrorPodSecurityPolicyViolation(fmt.Sprintf("%s can't be %s", userconfig.SeccompProfileKey, userconfig.SeccompProfileUnconfined))
+	}
+
+	if policy.RequireReadOnlyRootFS {
+		security.ReadOnlyRootFS = true
+	}
+
+	if policy.RequireRunAsNonRoot {
+		if security.RunAsUser != nil && *security.RunAsUser == 0 {
+			return ErrorPodSecurityPolicyViolation(fmt.Sprintf("%s can't be 0 (root)", userconfig.RunAsUserKey))
+		}
+		security.RunAsNonRoot = true
+	}
+
+	for _, capability := range policy.DropCapabilities {
+		if !slices.HasString(security.DropCapabilities, capability) {
+			security.DropCapabilities = append(security.DropCapabilities, capability)
+		}
+	}
+
+	// the containers which use inferentia are given the capabilities which the neuron runtime requires, which would undo the policy's drops
+	if usesNeuron(api) {
+		for _, capability := range policy.DropCapabilities {
+			if slices.HasString(_neuronCapabilities, capability) || capability == "ALL" {
+				return ErrorPodSecurityPolicyViolation(fmt.Sprintf("apis which request %s or %s can't be deployed, since the neuron runtime requires the %s capability, which is dropped", userconfig.InfKey, userconfig.NeuronCoresKey, capability))
+			}
+		}
+	}
+
+	return nil
+}
+
+// the capabilities which are added to the containers which use inferentia
+var _neuronCapabilities = []string{"SYS_ADMIN", "IPC_LOCK"}
+
+func usesNeuron(api *userconfig.API) bool {
+	for _, container := range api.Pod.Containers {
+		if container.Compute != nil && (container.Compute.Inf > 0 || container.Compute.NeuronCores > 0) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operator
+
+import (
+	"io"
+
+	"github.com/cortexlabs/cortex/pkg/config"
+	"github.com/gorilla/websocket"
+)
+
+// the close reason of a websocket close frame can't exceed 123 bytes
+const _maxCloseReasonLength = 123
+
+// PortForwardToReplica forwards the connection which is tunnelled through the socket to a port of the replica
+func PortForwardToReplica(podName string, namespace string, port int32, socket *websocket.Conn) {
+	socket.SetReadLimit(_execSocketMaxMessageSize)
+
+	err := config.K8s.WithNamespace(namespace).PortForward(podName, port, &portForwardSocketReader{socket: socket}, &portForwardSocketWriter{socket: socket})
+	if err != nil {
+		reason := err.Error()
+		if len(reason) > _maxCloseReasonLength {
+			reason = reason[:_maxCloseReasonLength]
+		}
+		socket.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseInternalServerErr, reason))
+		return
+	}
+
+	closeSocket(socket)
+}
+
+// portForwardSocketReader returns EOF once the client has sent all of its data (or has disconnected)
+type portForwardSocketReader struct {
+	socket *websocket.Conn
+	buf    []byte
+	eof    bool
+}
+
+func (r *portForwardSocketReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		if r.eof {
+			return 0, io.EOF
+		}
+		_, message, err := r.socket.ReadMessage()
+		if err != nil || len(message) == 0 {
+			r.eof = true
+			continue
+		}
+		r.buf = message
+	}
+
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+type portForwardSocketWriter struct {
+	socket *websocket.Conn
+}
+
+func (w *portForwardSocketWriter) Write(p []byte) (int, error) {
+	if err := w.socket.WriteMessage(websocket.BinaryMessage, p); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operator
+
+import (
+	"github.com/cortexlabs/cortex/pkg/config"
+	"github.com/cortexlabs/cortex/pkg/lib/k8s"
+	"github.com/cortexlabs/cortex/pkg/lib/math"
+	"github.com/cortexlabs/cortex/pkg/lib/pointer"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
+	"github.com/cortexlabs/cortex/pkg/workloads"
+	kapps "k8s.io/api/apps/v1"
+	kcore "k8s.io/api/core/v1"
+)
+
+func prepullK8sName(apiName string) string {
+	return workloads.K8sName(apiName) + "-prepull"
+}
+
+func prepullDaemonSetSpec(api *spec.API) *kapps.DaemonSet {
+	initContainers, containers, volumes := workloads.PrepullContainers(*api)
+
+	return k8s.DaemonSet(&k8s.DaemonSetSpec{
+		Name: prepullK8sName(api.Name),
+		Labels: map[string]string{
+			"prepullOf": api.Name,
+			"apiKind":   api.Kind.String(),
+			"podID":     api.PodID,
+		},
+		Selector: map[string]string{
+			"prepullOf": api.Name,
+			"apiKind":   api.Kind.String(),
+		},
+		PodSpec: k8s.PodSpec{
+			Labels: map[string]string{
+				"prepullOf": api.Name,
+				"apiKind":   api.Kind.String(),
+				"podID":     api.PodID,
+			},
+			K8sPodSpec: kcore.PodSpec{
+				TerminationGracePeriodSeconds: pointer.Int64(0),
+				InitContainers:                initContainers,
+				Containers:                    containers,
+				NodeSelector:                  workloads.NodeSelectors(),
+				Tolerations:                   workloads.GenerateResourceTolerations(),
+				Affinity:                      workloads.GenerateNodeAffinities(api.NodeGroups),
+				Volumes:                       volumes,
+				ServiceAccountName:            workloads.ServiceAccountName,
+				ImagePullSecrets:              workloads.ImagePullSecrets(api.Name, api.Pod),
+			},
+		},
+	})
+}
+
+// ApplyImagePrepull creates or updates the daemonset which pre-pulls the api's images onto the nodes of its node groups,
+// or deletes it if prepull_images is disabled
+func ApplyImagePrepull(api *spec.API) error {
+	if !api.PrepullImages {
+		return DeleteImagePrepull(api.Name, api.Namespace())
+	}
+
+	_, err := config.K8s.WithNamespace(api.Namespace()).ApplyDaemonSet(prepullDaemonSetSpec(api))
+	return err
+}
+
+func DeleteImagePrepull(apiName string, namespace string) error {
+	_, err := config.K8s.WithNamespace(namespace).DeleteDaemonSet(prepullK8sName(apiName))
+	return err
+}
+
+// GetImagePrepullStatus returns nil if the api's images aren't pre-pulled
+func GetImagePrepullStatus(apiName string, namespace string) (*schema.ImagePrepullResponse, error) {
+	daemonSet, err := config.K8s.WithNamespace(namespace).GetDaemonSet(prepullK8sName(apiName))
+	if err != nil {
+		return nil, err
+	}
+	if daemonSet == nil {
+		return nil, nil
+	}
+
+	// pods are ready once all of their images have been pulled; pods which are running the previous images are excluded
+	return &schema.ImagePrepullResponse{
+		NumNodes:  daemonSet.Status.DesiredNumberScheduled,
+		NumPulled: math.MinInt32(daemonSet.Status.NumberReady, daemonSet.Status.UpdatedNumberScheduled),
+	}, nil
+}
@@ -0,0 +1,235 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operator
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/config"
+	"github.com/cortexlabs/cortex/pkg/lib/docker"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/k8s"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+	"github.com/cortexlabs/cortex/pkg/workloads"
+	kcore "k8s.io/api/core/v1"
+)
+
+// ECR auth tokens expire after 12 hours, so the image pull secrets which were created by assuming a role are refreshed periodically
+const ECRRegistryCredentialsCronPeriod = time.Hour
+
+const (
+	_ecrRoleARNAnnotation   = "ecrRoleARN"
+	_ecrImagesAnnotation    = "ecrImages"
+	_cortexSecretAnnotation = "cortexSecret"
+)
+
+// ApplyRegistryCredentials copies the api's registry credentials from Secrets Manager or the cluster's secrets backend (or obtains them by assuming the ECR role) into an image pull secret.
+// If the api doesn't reference a Secrets Manager secret, a cortex secret, or an ECR role, any previously created image pull secret is deleted.
+func ApplyRegistryCredentials(api *userconfig.API) error {
+	if api.Pod == nil || api.Pod.RegistryCredentials == nil || api.Pod.RegistryCredentials.Secret != nil {
+		return DeleteRegistryCredentials(api.Name, api.Namespace())
+	}
+
+	registryConfig, err := GetRegistryDockerConfig(api.Pod.RegistryCredentials, api.Pod.Containers, api.Namespace())
+	if err != nil {
+		return err
+	}
+
+	var annotations map[string]string
+	if api.Pod.RegistryCredentials.ECRRoleARN != nil {
+		annotations = map[string]string{
+			_ecrRoleARNAnnotation: *api.Pod.RegistryCredentials.ECRRoleARN,
+			_ecrImagesAnnotation:  strings.Join(spec.ECRImages(api.Pod.Containers), ","),
+		}
+	}
+	if api.Pod.RegistryCredentials.RegistryAuth != nil {
+		annotations = map[string]string{
+			_cortexSecretAnnotation: *api.Pod.RegistryCredentials.RegistryAuth,
+		}
+	}
+
+	return applyRegistryCredentialsSecret(api.Name, api.Kind.String(), api.Namespace(), registryConfig, annotations)
+}
+
+// GetRegistryDockerConfig resolves the api's registry credentials into a docker config; credentials which reference a secret that was created with `cortex secrets set` are read from the cluster's secrets backend,
+// and credentials which reference a kubernetes secret are read from the namespace of the api's project
+func GetRegistryDockerConfig(registryCredentials *userconfig.RegistryCredentials, containers []*userconfig.Container, namespace string) (*docker.RegistryConfig, error) {
+	if registryCredentials.RegistryAuth == nil {
+		return spec.GetRegistryDockerConfig(registryCredentials, containers, config.AWS, config.K8s.WithNamespace(namespace))
+	}
+
+	value, err := GetSecretValue(*registryCredentials.RegistryAuth)
+	if err != nil {
+		return nil, errors.Wrap(err, userconfig.RegistryAuthKey)
+	}
+
+	registryConfig, err := docker.ParseRegistrySecret([]byte(value))
+	if err != nil {
+		return nil, errors.Wrap(err, userconfig.RegistryAuthKey)
+	}
+
+	return registryConfig, nil
+}
+
+// ValidateRegistryCredentials verifies that the api's images can be pulled with the credentials which are stored in the cluster's secrets backend (the other types of registry credentials are validated with the api's spec)
+func ValidateRegistryCredentials(api *userconfig.API) error {
+	if api.Pod == nil || api.Pod.RegistryCredentials == nil || api.Pod.RegistryCredentials.RegistryAuth == nil {
+		return nil
+	}
+
+	registryConfig, err := GetRegistryDockerConfig(api.Pod.RegistryCredentials, api.Pod.Containers, api.Namespace())
+	if err != nil {
+		return errors.Wrap(err, userconfig.RegistryCredentialsKey)
+	}
+
+	for i, container := range api.Pod.Containers {
+		username, password, ok := registryConfig.CredentialsForImage(container.Image)
+		if !ok {
+			continue
+		}
+
+		if err := docker.CheckImagePullable(container.Image, username, password); err != nil {
+			return errors.Wrap(err, userconfig.ContainersKey, s.Index(i), userconfig.ImageKey)
+		}
+	}
+
+	return nil
+}
+
+// ValidateRegistryCredentialsUpdate checks that the new value of the cortex secret can be parsed as registry credentials, if the registry credentials of any api reference it,
+// so that an invalid value can be rejected before it is stored
+func ValidateRegistryCredentialsUpdate(name string, value string) error {
+	_, _, err := parseRegistryCredentialsUpdate(name, value)
+	return err
+}
+
+// UpdateRegistryCredentials updates the image pull secrets of the apis whose registry credentials reference the cortex secret, and returns the names of those apis;
+// the new value is parsed before any of the image pull secrets are updated
+func UpdateRegistryCredentials(name string, value string) ([]string, error) {
+	secrets, registryConfig, err := parseRegistryCredentialsUpdate(name, value)
+	if err != nil {
+		return nil, err
+	}
+
+	var apiNames []string
+	for _, secret := range secrets {
+		if err := applyRegistryCredentialsSecret(secret.Labels["apiName"], secret.Labels["apiKind"], secret.Namespace, registryConfig, secret.Annotations); err != nil {
+			return nil, errors.Wrap(err, secret.Labels["apiName"])
+		}
+		apiNames = append(apiNames, secret.Labels["apiName"])
+	}
+	sort.Strings(apiNames)
+
+	return apiNames, nil
+}
+
+// returns the image pull secrets which were created from the cortex secret, and the registry config which is parsed from its new value (nil if there are no such image pull secrets)
+func parseRegistryCredentialsUpdate(name string, value string) ([]kcore.Secret, *docker.RegistryConfig, error) {
+	registrySecrets, err := listCortexSecretRegistryCredentials()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var secrets []kcore.Secret
+	for _, secret := range registrySecrets {
+		if secret.Annotations[_cortexSecretAnnotation] == name {
+			secrets = append(secrets, secret)
+		}
+	}
+	if len(secrets) == 0 {
+		return nil, nil, nil
+	}
+
+	registryConfig, err := docker.ParseRegistrySecret([]byte(value))
+	if err != nil {
+		return nil, nil, errors.Wrap(err, secrets[0].Labels["apiName"], userconfig.PodKey, userconfig.RegistryCredentialsKey, userconfig.RegistryAuthKey)
+	}
+
+	return secrets, registryConfig, nil
+}
+
+func listCortexSecretRegistryCredentials() ([]kcore.Secret, error) {
+	secrets, err := config.K8sAllNamspaces.ListSecretsWithLabelKeys("apiName", "apiKind")
+	if err != nil {
+		return nil, err
+	}
+
+	var registrySecrets []kcore.Secret
+	for _, secret := range secrets {
+		if _, ok := secret.Annotations[_cortexSecretAnnotation]; ok && secret.Name == workloads.RegistryCredentialsSecretName(secret.Labels["apiName"]) {
+			registrySecrets = append(registrySecrets, secret)
+		}
+	}
+	return registrySecrets, nil
+}
+
+// RefreshECRRegistryCredentials re-creates the image pull secrets which were obtained by assuming an ECR role, before their auth tokens expire
+func RefreshECRRegistryCredentials() error {
+	secrets, err := config.K8sAllNamspaces.ListSecretsWithLabelKeys("apiName", "apiKind")
+	if err != nil {
+		return err
+	}
+
+	for _, secret := range secrets {
+		roleARN, ok := secret.Annotations[_ecrRoleARNAnnotation]
+		if !ok || secret.Name != workloads.RegistryCredentialsSecretName(secret.Labels["apiName"]) {
+			continue
+		}
+		images := strings.Split(secret.Annotations[_ecrImagesAnnotation], ",")
+
+		registryConfig, err := spec.GetECRRegistryDockerConfig(roleARN, images, config.AWS)
+		if err != nil {
+			return errors.Wrap(err, secret.Labels["apiName"])
+		}
+
+		if err := applyRegistryCredentialsSecret(secret.Labels["apiName"], secret.Labels["apiKind"], secret.Namespace, registryConfig, secret.Annotations); err != nil {
+			return errors.Wrap(err, secret.Labels["apiName"])
+		}
+	}
+
+	return nil
+}
+
+func applyRegistryCredentialsSecret(apiName string, apiKind string, namespace string, registryConfig *docker.RegistryConfig, annotations map[string]string) error {
+	dockerConfigJSON, err := registryConfig.Bytes()
+	if err != nil {
+		return err
+	}
+
+	_, err = config.K8s.WithNamespace(namespace).ApplySecret(k8s.Secret(&k8s.SecretSpec{
+		Name: workloads.RegistryCredentialsSecretName(apiName),
+		Type: kcore.SecretTypeDockerConfigJson,
+		Data: map[string][]byte{
+			kcore.DockerConfigJsonKey: dockerConfigJSON,
+		},
+		Labels: map[string]string{
+			"apiName": apiName,
+			"apiKind": apiKind,
+		},
+		Annotations: annotations,
+	}))
+	return err
+}
+
+func DeleteRegistryCredentials(apiName string, namespace string) error {
+	_, err := config.K8s.WithNamespace(namespace).DeleteSecret(workloads.RegistryCredentialsSecretName(apiName))
+	return err
+}
@@ -0,0 +1,214 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operator
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/cortexlabs/cortex/pkg/config"
+	"github.com/cortexlabs/cortex/pkg/lib/aws"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/k8s"
+	"github.com/cortexlabs/cortex/pkg/lib/sets/strset"
+	"github.com/cortexlabs/cortex/pkg/types/clusterconfig"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+	"github.com/cortexlabs/cortex/pkg/workloads"
+	kcore "k8s.io/api/core/v1"
+)
+
+// GetSecretValue returns the value of a secret which was created with `cortex secrets set` from the cluster's secrets backend
+func GetSecretValue(name string) (string, error) {
+	backendName := config.ClusterConfig.SecretName(name)
+
+	var value string
+	var err error
+	if config.ClusterConfig.SecretsBackend == clusterconfig.ParameterStoreSecretsBackend {
+		value, err = config.AWS.GetParameterString(backendName)
+	} else {
+		value, err = config.AWS.GetSecretString(backendName)
+	}
+	if err != nil {
+		if aws.IsParameterNotFoundErr(err) || aws.IsSecretNotFoundErr(err) {
+			return "", ErrorSecretNotFound(name)
+		}
+		return "", err
+	}
+
+	return value, nil
+}
+
+// PutSecretValue creates or updates a secret in the cluster's secrets backend
+func PutSecretValue(name string, value string) error {
+	backendName := config.ClusterConfig.SecretName(name)
+
+	if config.ClusterConfig.SecretsBackend == clusterconfig.ParameterStoreSecretsBackend {
+		return config.AWS.PutSecureStringParameter(backendName, value)
+	}
+	return config.AWS.PutSecretString(backendName, value, map[string]string{
+		clusterconfig.ClusterNameTag: config.ClusterConfig.ClusterName,
+	})
+}
+
+// DeleteSecretValue returns false if the secret doesn't exist in the cluster's secrets backend
+func DeleteSecretValue(name string) (bool, error) {
+	backendName := config.ClusterConfig.SecretName(name)
+
+	if config.ClusterConfig.SecretsBackend == clusterconfig.ParameterStoreSecretsBackend {
+		return config.AWS.DeleteParameter(backendName)
+	}
+	return config.AWS.DeleteSecret(backendName)
+}
+
+// ListSecretNames returns the (sorted) names of the secrets which were created with `cortex secrets set`
+func ListSecretNames() ([]string, error) {
+	prefix := config.ClusterConfig.SecretNamePrefix()
+
+	var backendNames []string
+	var err error
+	if config.ClusterConfig.SecretsBackend == clusterconfig.ParameterStoreSecretsBackend {
+		backendNames, err = config.AWS.ListParameterNamesByPath(strings.TrimSuffix(prefix, "/"))
+	} else {
+		backendNames, err = config.AWS.ListSecretNamesWithPrefix(prefix)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(backendNames))
+	for _, backendName := range backendNames {
+		names = append(names, strings.TrimPrefix(backendName, prefix))
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
+
+// ApplySecretEnv copies the values of the secrets which are referenced by the api's env_from_secrets from the secrets backend into a kubernetes secret (which the api's containers reference).
+// If the api doesn't reference any secrets, any previously created kubernetes secret is deleted.
+func ApplySecretEnv(api *userconfig.API) error {
+	if api.Pod == nil {
+		return DeleteSecretEnv(api.Name, api.Namespace())
+	}
+
+	secretNames := userconfig.SecretNames(api.Pod.Containers)
+	if len(secretNames) == 0 {
+		return DeleteSecretEnv(api.Name, api.Namespace())
+	}
+
+	data := make(map[string][]byte, len(secretNames))
+	for _, secretName := range secretNames {
+		value, err := GetSecretValue(secretName)
+		if err != nil {
+			return errors.Wrap(err, userconfig.EnvFromSecretsKey)
+		}
+		data[secretName] = []byte(value)
+	}
+
+	_, err := config.K8s.WithNamespace(api.Namespace()).ApplySecret(k8s.Secret(&k8s.SecretSpec{
+		Name: workloads.SecretEnvSecretName(api.Name),
+		Type: kcore.SecretTypeOpaque,
+		Data: data,
+		Labels: map[string]string{
+			"apiName": api.Name,
+			"apiKind": api.Kind.String(),
+		},
+	}))
+	return err
+}
+
+// UpdateSecretEnvs updates the value of a secret in the kubernetes secrets of the apis which reference it, and returns the names of those apis
+// (the apis' pods only receive the new value once they are restarted)
+func UpdateSecretEnvs(name string, value string) ([]string, error) {
+	secrets, err := listSecretEnvSecrets()
+	if err != nil {
+		return nil, err
+	}
+
+	var apiNames []string
+	for i := range secrets {
+		secret := secrets[i]
+		if _, ok := secret.Data[name]; !ok {
+			continue
+		}
+		secret.Data[name] = []byte(value)
+		if _, err := config.K8s.WithNamespace(secret.Namespace).UpdateSecret(&secret); err != nil {
+			return nil, errors.Wrap(err, secret.Labels["apiName"])
+		}
+		apiNames = append(apiNames, secret.Labels["apiName"])
+	}
+	sort.Strings(apiNames)
+
+	return apiNames, nil
+}
+
+// APIsUsingSecrets maps the name of each secret which is referenced by the env_from_secrets or registry credentials of deployed apis to the (sorted) names of those apis
+func APIsUsingSecrets() (map[string][]string, error) {
+	secrets, err := listSecretEnvSecrets()
+	if err != nil {
+		return nil, err
+	}
+
+	registrySecrets, err := listCortexSecretRegistryCredentials()
+	if err != nil {
+		return nil, err
+	}
+
+	apiNameSets := map[string]strset.Set{}
+	addAPIName := func(name string, apiName string) {
+		if _, ok := apiNameSets[name]; !ok {
+			apiNameSets[name] = strset.New()
+		}
+		apiNameSets[name].Add(apiName)
+	}
+
+	for _, secret := range secrets {
+		for name := range secret.Data {
+			addAPIName(name, secret.Labels["apiName"])
+		}
+	}
+	for _, secret := range registrySecrets {
+		addAPIName(secret.Annotations[_cortexSecretAnnotation], secret.Labels["apiName"])
+	}
+
+	apiNames := make(map[string][]string, len(apiNameSets))
+	for name, apiNameSet := range apiNameSets {
+		apiNames[name] = apiNameSet.SliceSorted()
+	}
+
+	return apiNames, nil
+}
+
+func listSecretEnvSecrets() ([]kcore.Secret, error) {
+	secrets, err := config.K8sAllNamspaces.ListSecretsWithLabelKeys("apiName", "apiKind")
+	if err != nil {
+		return nil, err
+	}
+
+	var secretEnvSecrets []kcore.Secret
+	for _, secret := range secrets {
+		if secret.Name == workloads.SecretEnvSecretName(secret.Labels["apiName"]) {
+			secretEnvSecrets = append(secretEnvSecrets, secret)
+		}
+	}
+	return secretEnvSecrets, nil
+}
+
+func DeleteSecretEnv(apiName string, namespace string) error {
+	_, err := config.K8s.WithNamespace(namespace).DeleteSecret(workloads.SecretEnvSecretName(apiName))
+	return err
+}
@@ -0,0 +1,220 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operator
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/config"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/k8s"
+	"github.com/cortexlabs/cortex/pkg/lib/sets/strset"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	kcore "k8s.io/api/core/v1"
+	kmeta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	SpotInterruptionsCronPeriod     = 15 * time.Second
+	_spotInterruptionsConfigMapName = "cortex-spot-interruptions"
+)
+
+// instance IDs of the interrupted instances which have already been drained
+var _handledSpotInterruptions = strset.New()
+
+var spotInterruptionsCounter = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "cortex_spot_interruptions_total",
+		Help: "The number of spot instance interruption notices handled by the operator",
+	}, []string{"nodegroup"},
+)
+
+// HandleSpotInterruptions cordons and drains the workload nodes which have received a spot interruption notice,
+// and detaches them from their autoscaling groups so that replacement instances are requested right away
+func HandleSpotInterruptions() error {
+	instanceIDs, err := config.AWS.SpotInstancesMarkedForInterruption()
+	if err != nil {
+		return err
+	}
+
+	// forget about instances which are no longer marked (i.e. they have been terminated)
+	_handledSpotInterruptions = strset.Intersection(_handledSpotInterruptions, strset.New(instanceIDs...))
+
+	newInstanceIDs := strset.Difference(strset.New(instanceIDs...), _handledSpotInterruptions)
+	if len(newInstanceIDs) == 0 {
+		return nil
+	}
+
+	nodes, err := config.K8sAllNamspaces.ListNodesByLabel("workload", "true")
+	if err != nil {
+		return err
+	}
+
+	interruptedNodes := map[string]kcore.Node{} // instance ID -> node
+	for _, node := range nodes {
+		instanceID := instanceIDFromProviderID(node.Spec.ProviderID)
+		if newInstanceIDs.Has(instanceID) {
+			interruptedNodes[instanceID] = node
+		}
+	}
+	if len(interruptedNodes) == 0 {
+		return nil
+	}
+
+	interruptedInstanceIDs := make([]string, 0, len(interruptedNodes))
+	for instanceID := range interruptedNodes {
+		interruptedInstanceIDs = append(interruptedInstanceIDs, instanceID)
+	}
+
+	asgNames, err := config.AWS.AutoscalingGroupNamesForInstances(interruptedInstanceIDs...)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	counts := map[string]int64{} // nodegroup name -> number of interruptions
+	for instanceID := range interruptedNodes {
+		node := interruptedNodes[instanceID]
+		nodeGroupName := node.Labels["alpha.eksctl.io/nodegroup-name"]
+		operatorLogger.Warnw("spot instance interruption notice received; draining node",
+			"node", node.Name,
+			"instance_id", instanceID,
+			"nodegroup", nodeGroupName,
+		)
+
+		if err := drainNode(&node); err != nil {
+			errs = append(errs, errors.Wrap(err, node.Name))
+			continue
+		}
+
+		if asgName, ok := asgNames[instanceID]; ok {
+			if err := config.AWS.DetachInstanceForReplacement(asgName, instanceID); err != nil {
+				errs = append(errs, errors.Wrap(err, node.Name))
+			}
+		}
+
+		_handledSpotInterruptions.Add(instanceID)
+		spotInterruptionsCounter.WithLabelValues(nodeGroupName).Inc()
+		counts[nodeGroupName]++
+	}
+
+	if len(counts) > 0 {
+		if err := incrementSpotInterruptionCounts(counts); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if errors.HasError(errs) {
+		return errors.FirstError(errs...)
+	}
+	return nil
+}
+
+func drainNode(node *kcore.Node) error {
+	if err := config.K8sAllNamspaces.CordonNode(node); err != nil {
+		return err
+	}
+
+	pods, err := config.K8sAllNamspaces.ListPods(&kmeta.ListOptions{
+		FieldSelector: "spec.nodeName=" + node.Name,
+	})
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for i := range pods {
+		pod := pods[i]
+		if pod.Status.Phase == kcore.PodSucceeded || pod.Status.Phase == kcore.PodFailed || isDaemonSetPod(&pod) {
+			continue
+		}
+		if _, err := config.K8sAllNamspaces.EvictPod(&pod); err != nil {
+			errs = append(errs, errors.Wrap(err, pod.Namespace+"/"+pod.Name))
+		}
+	}
+
+	if errors.HasError(errs) {
+		return errors.FirstError(errs...)
+	}
+	return nil
+}
+
+func isDaemonSetPod(pod *kcore.Pod) bool {
+	for _, ownerRef := range pod.OwnerReferences {
+		if ownerRef.Kind == "DaemonSet" {
+			return true
+		}
+	}
+	return false
+}
+
+// e.g. aws:///us-east-1a/i-0123456789abcdef0 -> i-0123456789abcdef0
+func instanceIDFromProviderID(providerID string) string {
+	if !strings.HasPrefix(providerID, "aws://") {
+		return ""
+	}
+	return providerID[strings.LastIndex(providerID, "/")+1:]
+}
+
+// GetSpotInterruptionCounts returns the number of spot interruptions which have been handled for each nodegroup
+func GetSpotInterruptionCounts() (map[string]int64, error) {
+	configMapData, _, err := config.K8s.GetConfigMapData(_spotInterruptionsConfigMapName)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := map[string]int64{}
+	for nodeGroupName, countStr := range configMapData {
+		count, err := strconv.ParseInt(countStr, 10, 64)
+		if err != nil {
+			continue
+		}
+		counts[nodeGroupName] = count
+	}
+
+	return counts, nil
+}
+
+func incrementSpotInterruptionCounts(increments map[string]int64) error {
+	counts, err := GetSpotInterruptionCounts()
+	if err != nil {
+		return err
+	}
+
+	configMapData := map[string]string{}
+	for nodeGroupName, count := range counts {
+		configMapData[nodeGroupName] = strconv.FormatInt(count, 10)
+	}
+	for nodeGroupName, increment := range increments {
+		configMapData[nodeGroupName] = strconv.FormatInt(counts[nodeGroupName]+increment, 10)
+	}
+
+	configMap := k8s.ConfigMap(&k8s.ConfigMapSpec{
+		Name: _spotInterruptionsConfigMapName,
+		Data: configMapData,
+	})
+
+	_, err = config.K8s.ApplyConfigMap(configMap)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -23,7 +23,10 @@ import (
 	"fmt"
 	"io"
 	"os/exec"
+	"regexp"
+	"sort"
 	"strings"
+	"sync"
 	"text/template"
 	"time"
 
@@ -31,11 +34,14 @@ import (
 	awslib "github.com/cortexlabs/cortex/pkg/lib/aws"
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
 	"github.com/cortexlabs/cortex/pkg/lib/k8s"
+	"github.com/cortexlabs/cortex/pkg/lib/sets/strset"
 	"github.com/cortexlabs/cortex/pkg/lib/telemetry"
 	"github.com/cortexlabs/cortex/pkg/operator/lib/routines"
 	"github.com/cortexlabs/cortex/pkg/types/spec"
 	"github.com/cortexlabs/cortex/pkg/types/status"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
 	"github.com/gorilla/websocket"
+	kcore "k8s.io/api/core/v1"
 )
 
 const (
@@ -46,6 +52,9 @@ const (
 
 	_pendingPodCheckInterval = 1 * time.Second
 	_pollPeriod              = 250 * time.Millisecond
+
+	_aggregatedJobLogsLimit        = 10000 // the maximum number of results of a cloudwatch logs insights query
+	_aggregatedJobLogsQueryTimeout = 2 * time.Minute
 )
 
 func timeString(t time.Time) string {
@@ -192,7 +201,7 @@ func TaskJobLogURL(apiName string, jobStatus status.TaskJobStatus) (string, erro
 	})
 }
 
-func waitForPodToBeNotPending(podName string, cancelListener chan struct{}, socket *websocket.Conn) bool {
+func waitForPodToBeNotPending(podName string, namespace string, cancelListener chan struct{}, socket *websocket.Conn) bool {
 	wrotePending := false
 	timer := time.NewTimer(0)
 
@@ -201,7 +210,7 @@ func waitForPodToBeNotPending(podName string, cancelListener chan struct{}, sock
 		case <-cancelListener:
 			return false
 		case <-timer.C:
-			pod, err := config.K8s.GetPod(podName)
+			pod, err := config.K8s.WithNamespace(namespace).GetPod(podName)
 			if err != nil {
 				writeAndCloseSocket(socket, fmt.Sprintf("error encountered while attempting to stream logs from pod %s\n%s", podName, err.Error()))
 				return false
@@ -230,13 +239,13 @@ type jsonMessage struct {
 	ExcInfo string `json:"exc_info"`
 }
 
-func startKubectlProcess(podName string, cancelListener chan struct{}, socket *websocket.Conn) {
-	shouldContinue := waitForPodToBeNotPending(podName, cancelListener, socket)
+func startKubectlProcess(podName string, namespace string, cancelListener chan struct{}, socket *websocket.Conn) {
+	shouldContinue := waitForPodToBeNotPending(podName, namespace, cancelListener, socket)
 	if !shouldContinue {
 		return
 	}
 
-	cmd := exec.Command("/usr/local/bin/kubectl", "-n="+config.K8s.Namespace, "logs", "--all-containers", podName, "--follow")
+	cmd := exec.Command("/usr/local/bin/kubectl", "-n="+namespace, "logs", "--all-containers", podName, "--follow")
 
 	cleanup := func() {
 		// trigger a wait on the child process and while the process is being waited on,
@@ -265,6 +274,15 @@ func startKubectlProcess(podName string, cancelListener chan struct{}, socket *w
 }
 
 func pumpStdout(socket *websocket.Conn, reader io.Reader) {
+	scanLogMessages(reader, func(message string) {
+		writeString(socket, message)
+	})
+
+	closeSocket(socket)
+}
+
+// scanLogMessages calls fn for each log message (including the exception info if present); each message ends with a newline
+func scanLogMessages(reader io.Reader, fn func(message string)) {
 	// it seems like if the buffer is maxed out with no ending token, the scanner just exits.
 	// increase the buffer used by the scanner to accommodate larger log lines (a common issue when printing progress)
 	p := make([]byte, 1024*1024)
@@ -275,20 +293,17 @@ func pumpStdout(socket *websocket.Conn, reader io.Reader) {
 		var message jsonMessage
 		err := json.Unmarshal(logBytes, &message)
 		if err != nil {
-			writeString(socket, string(logBytes)+"\n")
+			fn(string(logBytes) + "\n")
+		} else if message.ExcInfo != "" {
+			fn(message.Message + "\n" + message.ExcInfo + "\n")
 		} else {
-			writeString(socket, message.Message+"\n")
-			if message.ExcInfo != "" {
-				writeString(socket, message.ExcInfo+"\n")
-			}
+			fn(message.Message + "\n")
 		}
 	}
-
-	closeSocket(socket)
 }
 
 func StreamLogsFromRandomPod(podSearchLabels map[string]string, socket *websocket.Conn) {
-	pods, err := config.K8s.ListPodsByLabels(podSearchLabels)
+	pods, err := config.K8sAllNamspaces.ListPodsByLabels(podSearchLabels)
 	if err != nil {
 		writeAndCloseSocket(socket, err.Error())
 		return
@@ -301,12 +316,252 @@ func StreamLogsFromRandomPod(podSearchLabels map[string]string, socket *websocke
 	cancelListener := make(chan struct{})
 	defer close(cancelListener)
 	routines.RunWithPanicHandler(func() {
-		startKubectlProcess(pods[0].Name, cancelListener, socket)
+		startKubectlProcess(pods[0].Name, pods[0].Namespace, cancelListener, socket)
 	})
 	pumpStdin(socket)
 	cancelListener <- struct{}{}
 }
 
+// LogStreamOptions configures streaming logs from all of a workload's replicas
+type LogStreamOptions struct {
+	Follow  bool
+	Since   *time.Duration
+	Replica string // name (or name suffix) of the replica to stream logs from; if empty, logs from all replicas are streamed
+	Filter  *regexp.Regexp
+}
+
+// replicaLogWriter merges the log streams of multiple replicas into the socket
+type replicaLogWriter struct {
+	mux       sync.Mutex
+	socket    *websocket.Conn
+	filter    *regexp.Regexp
+	addPrefix bool
+}
+
+func (w *replicaLogWriter) write(podName string, message string) {
+	if w.filter != nil && !w.filter.MatchString(message) {
+		return
+	}
+
+	if w.addPrefix {
+		prefix := fmt.Sprintf("[%s] ", podName)
+		message = prefix + strings.Replace(strings.TrimSuffix(message, "\n"), "\n", "\n"+prefix, -1) + "\n"
+	}
+
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	writeString(w.socket, message)
+}
+
+func StreamLogsFromPods(podSearchLabels map[string]string, options LogStreamOptions, socket *websocket.Conn) {
+	allPods, err := config.K8sAllNamspaces.ListPodsByLabels(podSearchLabels)
+	if err != nil {
+		writeAndCloseSocket(socket, err.Error())
+		return
+	}
+
+	var pods []kcore.Pod
+	for _, pod := range allPods {
+		if options.Replica == "" || pod.Name == options.Replica || strings.HasSuffix(pod.Name, "-"+options.Replica) {
+			pods = append(pods, pod)
+		}
+	}
+	if len(pods) == 0 {
+		if options.Replica != "" {
+			writeAndCloseSocket(socket, fmt.Sprintf("replica %s is not currently running for this workload\n", options.Replica))
+			return
+		}
+		writeAndCloseSocket(socket, "there are currently no pods running for this workload; please visit your logging dashboard for historical logs\n")
+		return
+	}
+	sort.Slice(pods, func(i, j int) bool {
+		return pods[i].Name < pods[j].Name
+	})
+
+	writer := &replicaLogWriter{
+		socket:    socket,
+		filter:    options.Filter,
+		addPrefix: options.Replica == "",
+	}
+
+	cancelListener := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := range pods {
+		podName := pods[i].Name
+		namespace := pods[i].Namespace
+		wg.Add(1)
+		routines.RunWithPanicHandler(func() {
+			defer wg.Done()
+			streamReplicaLogs(podName, namespace, options, cancelListener, writer)
+		})
+	}
+
+	streamsDone := make(chan struct{})
+	routines.RunWithPanicHandler(func() {
+		wg.Wait()
+		close(streamsDone)
+	})
+
+	clientDisconnected := make(chan struct{})
+	routines.RunWithPanicHandler(func() {
+		pumpStdin(socket)
+		close(clientDisconnected)
+	})
+
+	select {
+	case <-streamsDone:
+		// all of the replicas' logs have been written (or all of the replicas have terminated when following)
+		closeSocket(socket)
+	case <-clientDisconnected:
+		close(cancelListener)
+		wg.Wait()
+	}
+}
+
+func streamReplicaLogs(podName string, namespace string, options LogStreamOptions, cancelListener chan struct{}, writer *replicaLogWriter) {
+	if !waitForReplicaToStart(podName, namespace, options.Follow, cancelListener, writer) {
+		return
+	}
+
+	cmd := exec.Command("/usr/local/bin/kubectl", kubectlLogsArgs(podName, namespace, options)...)
+
+	logStream, err := cmd.StdoutPipe()
+	if err != nil {
+		telemetry.Error(errors.ErrorUnexpected(err.Error()))
+		operatorLogger.Error(err)
+		return
+	}
+
+	if err := cmd.Start(); err != nil {
+		writer.write(podName, fmt.Sprintf("error encountered while attempting to stream logs: %s\n", err.Error()))
+		return
+	}
+
+	streamEnded := make(chan struct{})
+	routines.RunWithPanicHandler(func() {
+		defer close(streamEnded)
+		scanLogMessages(logStream, func(message string) {
+			writer.write(podName, message)
+		})
+	})
+
+	select {
+	case <-streamEnded:
+	case <-cancelListener:
+		cmd.Process.Kill()
+	}
+	cmd.Wait()
+}
+
+func kubectlLogsArgs(podName string, namespace string, options LogStreamOptions) []string {
+	args := []string{"-n=" + namespace, "logs", "--all-containers", podName}
+	if options.Follow {
+		args = append(args, "--follow")
+	}
+	if options.Since != nil {
+		args = append(args, "--since="+options.Since.String())
+	}
+	return args
+}
+
+// waitForReplicaToStart returns whether logs can be streamed from the replica; pending replicas are only waited on when following the logs
+func waitForReplicaToStart(podName string, namespace string, follow bool, cancelListener chan struct{}, writer *replicaLogWriter) bool {
+	wrotePending := false
+	timer := time.NewTimer(0)
+
+	for {
+		select {
+		case <-cancelListener:
+			return false
+		case <-timer.C:
+			pod, err := config.K8s.WithNamespace(namespace).GetPod(podName)
+			if err != nil {
+				writer.write(podName, fmt.Sprintf("error encountered while attempting to stream logs: %s\n", err.Error()))
+				return false
+			}
+			if pod == nil {
+				return false
+			}
+			if k8s.GetPodStatus(pod) != k8s.PodStatusPending {
+				return true
+			}
+			if !follow {
+				writer.write(podName, "pod is still initializing\n")
+				return false
+			}
+			if !wrotePending {
+				writer.write(podName, "waiting for pod to initialize ...\n")
+				wrotePending = true
+			}
+			timer.Reset(_pendingPodCheckInterval)
+		}
+	}
+}
+
+// StreamAggregatedJobLogs writes the logs of all of the job's workers (queried from cloudwatch logs insights, so that the logs of completed jobs are included),
+// prefixing each line with the index of the worker which wrote it
+func StreamAggregatedJobLogs(jobKey spec.JobKey, startTime time.Time, endTime *time.Time, filter *regexp.Regexp, socket *websocket.Conn) {
+	queryEndTime := time.Now()
+	if endTime != nil {
+		queryEndTime = endTime.Add(60 * time.Second)
+	}
+
+	query := fmt.Sprintf("fields @timestamp, @logStream, message\n| filter cortex.labels.apiName=\"%s\" and cortex.labels.jobID=\"%s\"", jobKey.APIName, jobKey.ID)
+	if jobKey.Kind == userconfig.BatchAPIKind {
+		query += "\n| filter `cortex.labels.cortex.dev/batch`=\"worker\""
+	}
+	query += fmt.Sprintf("\n| sort @timestamp asc\n| limit %d", _aggregatedJobLogsLimit)
+
+	results, err := config.AWS.RunLogsInsightsQuery(config.ClusterConfig.ClusterName, query, startTime, queryEndTime, _aggregatedJobLogsLimit, _aggregatedJobLogsQueryTimeout)
+	if err != nil {
+		writeAndCloseSocket(socket, errors.Message(err)+"\n")
+		return
+	}
+
+	if len(results) == 0 {
+		writeAndCloseSocket(socket, fmt.Sprintf("no logs were found for job %s; there may be 1-2 minutes of delay for logs to become available\n", jobKey.UserString()))
+		return
+	}
+
+	// workers are numbered by the names of their pods
+	podNames := strset.New()
+	for _, result := range results {
+		podNames.Add(podNameFromLogStream(result["@logStream"]))
+	}
+	workerIndices := map[string]int{}
+	for i, podName := range podNames.SliceSorted() {
+		workerIndices[podName] = i
+	}
+
+	writer := &replicaLogWriter{
+		socket:    socket,
+		filter:    filter,
+		addPrefix: true,
+	}
+	for _, result := range results {
+		workerName := fmt.Sprintf("worker-%d", workerIndices[podNameFromLogStream(result["@logStream"])])
+		writer.write(workerName, strings.TrimSuffix(result["message"], "\n")+"\n")
+	}
+
+	if len(results) == _aggregatedJobLogsLimit {
+		writeString(socket, fmt.Sprintf("only the first %d log lines of the job are shown\n", _aggregatedJobLogsLimit))
+	}
+	if endTime == nil {
+		writeString(socket, "the job is still running; there may be 1-2 minutes of delay for its latest logs to become available\n")
+	}
+
+	closeSocket(socket)
+}
+
+// log streams are named kube.k8s_container.<namespace>.<pod name>.<container name>
+func podNameFromLogStream(logStream string) string {
+	split := strings.Split(logStream, ".")
+	if len(split) < 5 {
+		return logStream
+	}
+	return split[3]
+}
+
 func pumpStdin(socket *websocket.Conn) {
 	socket.SetReadLimit(_socketMaxMessageSize)
 	for {
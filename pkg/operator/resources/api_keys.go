@@ -0,0 +1,171 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/apikeys"
+	"github.com/cortexlabs/cortex/pkg/config"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	libjson "github.com/cortexlabs/cortex/pkg/lib/json"
+	"github.com/cortexlabs/cortex/pkg/lib/k8s"
+	"github.com/cortexlabs/cortex/pkg/lib/telemetry"
+	"github.com/cortexlabs/cortex/pkg/lib/urls"
+	"github.com/cortexlabs/cortex/pkg/operator/operator"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	kcore "k8s.io/api/core/v1"
+)
+
+// CreateAPIKey generates a new api key and stores its hash in the api keys secret (which is mounted by the proxies of apis with api key authentication);
+// the key itself is only returned once and is not stored
+func CreateAPIKey(name string, apiNames []string) (*schema.CreateAPIKeyResponse, error) {
+	if err := urls.CheckDNS1123(name); err != nil {
+		return nil, err
+	}
+
+	secret, err := config.K8s.GetSecret(apikeys.SecretName)
+	if err != nil {
+		return nil, err
+	}
+	if secret != nil {
+		if _, ok := secret.Data[name]; ok {
+			return nil, ErrorAPIKeyAlreadyExists(name)
+		}
+	}
+
+	key, err := apikeys.Generate()
+	if err != nil {
+		return nil, err
+	}
+
+	storedKey := apikeys.Key{
+		Name:      name,
+		Hash:      apikeys.Hash(key),
+		APIs:      apiNames,
+		CreatedAt: time.Now().UTC(),
+	}
+	storedKeyBytes, err := libjson.Marshal(storedKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if secret == nil {
+		_, err = config.K8s.CreateSecret(k8s.Secret(&k8s.SecretSpec{
+			Name: apikeys.SecretName,
+			Type: kcore.SecretTypeOpaque,
+			Data: map[string][]byte{name: storedKeyBytes},
+		}))
+	} else {
+		if secret.Data == nil {
+			secret.Data = map[string][]byte{}
+		}
+		secret.Data[name] = storedKeyBytes
+		_, err = config.K8s.UpdateSecret(secret) // fails if the secret was modified concurrently
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// the apis of other projects mount their namespace's copy of the secret (which is otherwise synced by a cron)
+	if err := operator.SyncProjectNamespaces(); err != nil {
+		telemetry.Error(err)
+	}
+
+	return &schema.CreateAPIKeyResponse{
+		APIKey: apiKeyInfo(storedKey),
+		Key:    key,
+	}, nil
+}
+
+func ListAPIKeys() ([]schema.APIKey, error) {
+	secretData, err := config.K8s.GetSecretData(apikeys.SecretName)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]schema.APIKey, 0, len(secretData))
+	for name, storedKeyBytes := range secretData {
+		var storedKey apikeys.Key
+		if err := libjson.Unmarshal(storedKeyBytes, &storedKey); err != nil {
+			return nil, errors.Wrap(err, apikeys.SecretName, name)
+		}
+		keys = append(keys, apiKeyInfo(storedKey))
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].Name < keys[j].Name
+	})
+
+	return keys, nil
+}
+
+func RevokeAPIKey(name string) (string, error) {
+	secret, err := config.K8s.GetSecret(apikeys.SecretName)
+	if err != nil {
+		return "", err
+	}
+	if secret == nil {
+		return "", ErrorAPIKeyNotFound(name)
+	}
+	if _, ok := secret.Data[name]; !ok {
+		return "", ErrorAPIKeyNotFound(name)
+	}
+
+	delete(secret.Data, name)
+	if _, err := config.K8s.UpdateSecret(secret); err != nil {
+		return "", err
+	}
+
+	if err := operator.SyncProjectNamespaces(); err != nil {
+		telemetry.Error(err)
+	}
+
+	return fmt.Sprintf("revoked api key %s (it may take up to 2 minutes for running apis to stop accepting it)", name), nil
+}
+
+// AuthenticateAPIKey returns the name of the api key, or "" if the key does not exist (e.g. because it was revoked)
+func AuthenticateAPIKey(key string) (string, error) {
+	secretData, err := config.K8s.GetSecretData(apikeys.SecretName)
+	if err != nil {
+		return "", err
+	}
+
+	keyHash := apikeys.Hash(key)
+	for name, storedKeyBytes := range secretData {
+		var storedKey apikeys.Key
+		if err := libjson.Unmarshal(storedKeyBytes, &storedKey); err != nil {
+			return "", errors.Wrap(err, apikeys.SecretName, name)
+		}
+		if subtle.ConstantTimeCompare([]byte(storedKey.Hash), []byte(keyHash)) == 1 {
+			return storedKey.Name, nil
+		}
+	}
+
+	return "", nil
+}
+
+func apiKeyInfo(storedKey apikeys.Key) schema.APIKey {
+	return schema.APIKey{
+		Name:      storedKey.Name,
+		APIs:      storedKey.APIs,
+		CreatedAt: storedKey.CreatedAt,
+	}
+}
@@ -60,7 +60,12 @@ func generateDeploymentID() string {
 }
 
 func UpdateAPI(apiConfig userconfig.API, force bool) (*spec.API, string, error) {
-	prevK8sResources, err := getK8sResources(apiConfig.Name)
+	prevK8sResources, err := getK8sResources(apiConfig.Name, apiConfig.Namespace())
+	if err != nil {
+		return nil, "", err
+	}
+
+	modelVersion, err := operator.LatestModelVersion(&apiConfig)
 	if err != nil {
 		return nil, "", err
 	}
@@ -74,9 +79,13 @@ func UpdateAPI(apiConfig userconfig.API, force bool) (*spec.API, string, error)
 			return nil, "", err
 		}
 		deploymentID = prevK8sResources.apiVirtualService.Labels["deploymentID"]
+		if modelVersion != prevK8sResources.apiVirtualService.Annotations[userconfig.ModelVersionAnnotationKey] {
+			deploymentID = generateDeploymentID()
+		}
 	}
 
 	api := spec.GetAPISpec(&apiConfig, initialDeploymentTime, deploymentID, config.ClusterConfig.ClusterUID)
+	api.ModelVersion = modelVersion
 
 	// resource creation
 	if prevK8sResources.apiVirtualService == nil {
@@ -93,6 +102,13 @@ func UpdateAPI(apiConfig userconfig.API, force bool) (*spec.API, string, error)
 			return nil, "", err
 		}
 
+		if err = applyRedrivePolicy(*api, queueURL); err != nil {
+			routines.RunWithPanicHandler(func() {
+				_ = deleteQueueByURL(queueURL)
+			})
+			return nil, "", err
+		}
+
 		if err = applyK8sResources(*api, prevK8sResources, queueURL); err != nil {
 			routines.RunWithPanicHandler(func() {
 				_ = parallel.RunFirstErr(
@@ -100,7 +116,7 @@ func UpdateAPI(apiConfig userconfig.API, force bool) (*spec.API, string, error)
 						return deleteQueueByURL(queueURL)
 					},
 					func() error {
-						return deleteK8sResources(api.Name)
+						return deleteK8sResources(api.Name, api.Namespace())
 					},
 				)
 			})
@@ -111,7 +127,7 @@ func UpdateAPI(apiConfig userconfig.API, force bool) (*spec.API, string, error)
 	}
 
 	// resource update
-	if prevK8sResources.apiVirtualService.Labels["specID"] != api.SpecID {
+	if prevK8sResources.apiVirtualService.Labels["specID"] != api.SpecID || prevK8sResources.apiVirtualService.Labels["deploymentID"] != api.DeploymentID {
 		isUpdating, err := isAPIUpdating(prevK8sResources.apiDeployment)
 		if err != nil {
 			return nil, "", err
@@ -134,6 +150,10 @@ func UpdateAPI(apiConfig userconfig.API, force bool) (*spec.API, string, error)
 			return nil, "", err
 		}
 
+		if err = applyRedrivePolicy(*api, queueURL); err != nil {
+			return nil, "", err
+		}
+
 		if err = applyK8sResources(*api, prevK8sResources, queueURL); err != nil {
 			return nil, "", err
 		}
@@ -152,8 +172,18 @@ func UpdateAPI(apiConfig userconfig.API, force bool) (*spec.API, string, error)
 	return api, fmt.Sprintf("%s is up to date", api.Resource.UserString()), nil
 }
 
-func RefreshAPI(apiName string, force bool) (string, error) {
-	prevK8sResources, err := getK8sResources(apiName)
+func RefreshAPI(apiName string, namespace string, force bool) (string, error) {
+	return refreshAPI(apiName, namespace, nil, force)
+}
+
+// UpdateModelVersion performs a rolling update of the api to the provided version of its model
+func UpdateModelVersion(apiName string, namespace string, modelVersion string) (string, error) {
+	return refreshAPI(apiName, namespace, &modelVersion, false)
+}
+
+// if modelVersion is nil, the currently deployed model version is kept
+func refreshAPI(apiName string, namespace string, modelVersion *string, force bool) (string, error) {
+	prevK8sResources, err := getK8sResources(apiName, namespace)
 	if err != nil {
 		return "", err
 	} else if prevK8sResources.apiVirtualService == nil || prevK8sResources.apiDeployment == nil {
@@ -184,7 +214,12 @@ func RefreshAPI(apiName string, force bool) (string, error) {
 		return "", err
 	}
 
+	prevModelVersion := api.ModelVersion
 	api = spec.GetAPISpec(api.API, initialDeploymentTime, generateDeploymentID(), config.ClusterConfig.ClusterUID)
+	api.ModelVersion = prevModelVersion
+	if modelVersion != nil {
+		api.ModelVersion = *modelVersion
+	}
 
 	if err := config.AWS.UploadJSONToS3(api, config.ClusterConfig.Bucket, api.Key); err != nil {
 		return "", errors.Wrap(err, "upload api spec")
@@ -202,10 +237,10 @@ func RefreshAPI(apiName string, force bool) (string, error) {
 	return fmt.Sprintf("updating %s", api.Resource.UserString()), nil
 }
 
-func DeleteAPI(apiName string, keepCache bool) error {
+func DeleteAPI(apiName string, namespace string, keepCache bool) error {
 	err := parallel.RunFirstErr(
 		func() error {
-			vs, err := config.K8s.GetVirtualService(workloads.K8sName(apiName))
+			vs, err := config.K8s.WithNamespace(namespace).GetVirtualService(workloads.K8sName(apiName))
 			if err != nil {
 				return err
 			}
@@ -220,11 +255,18 @@ func DeleteAPI(apiName string, keepCache bool) error {
 				}
 				// best effort deletion
 				_ = deleteQueueByURL(queueURL)
+
+				deadLetterQueueURL, err := getDeadLetterQueueURL(apiName, initialDeploymentTime)
+				if err != nil {
+					return err
+				}
+				// best effort deletion (the dead-letter queue only exists if async.max_receive_count was specified)
+				_ = deleteQueueByURL(deadLetterQueueURL)
 			}
 			return nil
 		},
 		func() error {
-			return deleteK8sResources(apiName)
+			return deleteK8sResources(apiName, namespace)
 		},
 		func() error {
 			if keepCache {
@@ -271,7 +313,7 @@ func GetAllAPIs(deployments []kapps.Deployment) ([]schema.APIResponse, error) {
 }
 
 func GetAPIByName(deployedResource *operator.DeployedResource) ([]schema.APIResponse, error) {
-	apiDeployment, err := config.K8s.GetDeployment(workloads.K8sName(deployedResource.Name))
+	apiDeployment, err := config.K8s.WithNamespace(deployedResource.Namespace()).GetDeployment(workloads.K8sName(deployedResource.Name))
 	if err != nil {
 		return nil, err
 	}
@@ -298,21 +340,37 @@ func GetAPIByName(deployedResource *operator.DeployedResource) ([]schema.APIResp
 
 	dashboardURL := pointer.String(getDashboardURL(api.Name))
 
+	var deadLetterQueueLength *int
+	if api.Async != nil && api.Async.MaxReceiveCount != nil {
+		deadLetterQueueURL, err := getDeadLetterQueueURL(api.Name, api.InitialDeploymentTime)
+		if err != nil {
+			return nil, err
+		}
+		queueLength, err := getQueueLength(deadLetterQueueURL)
+		if err != nil {
+			return nil, err
+		}
+		deadLetterQueueLength = &queueLength
+	}
+
 	return []schema.APIResponse{
 		{
-			Spec:         api,
-			Metadata:     apiMetadata,
-			Status:       apiStatus,
-			Endpoint:     &apiEndpoint,
-			DashboardURL: dashboardURL,
+			Spec:                  api,
+			Metadata:              apiMetadata,
+			Status:                apiStatus,
+			Endpoint:              &apiEndpoint,
+			DashboardURL:          dashboardURL,
+			DeadLetterQueueLength: deadLetterQueueLength,
 		},
 	}, nil
 }
 
 func DescribeAPIByName(deployedResource *operator.DeployedResource) ([]schema.APIResponse, error) {
+	k8sClient := config.K8s.WithNamespace(deployedResource.Namespace())
+
 	var apiDeployment *kapps.Deployment
 
-	apiDeployment, err := config.K8s.GetDeployment(workloads.K8sName(deployedResource.Name))
+	apiDeployment, err := k8sClient.GetDeployment(workloads.K8sName(deployedResource.Name))
 	if err != nil {
 		return nil, err
 	}
@@ -327,7 +385,7 @@ func DescribeAPIByName(deployedResource *operator.DeployedResource) ([]schema.AP
 		return nil, errors.ErrorUnexpected("unable to obtain metadata", deployedResource.Name)
 	}
 
-	apiPods, err := config.K8s.ListPodsByLabels(map[string]string{
+	apiPods, err := k8sClient.ListPodsByLabels(map[string]string{
 		"apiName": apiDeployment.Labels["apiName"],
 	})
 	if err != nil {
@@ -340,6 +398,16 @@ func DescribeAPIByName(deployedResource *operator.DeployedResource) ([]schema.AP
 		return nil, err
 	}
 
+	events, err := operator.GetAPIEvents(apiDeployment, apiPods)
+	if err != nil {
+		return nil, err
+	}
+
+	imagePrepull, err := operator.GetImagePrepullStatus(deployedResource.Name, deployedResource.Namespace())
+	if err != nil {
+		return nil, err
+	}
+
 	dashboardURL := pointer.String(getDashboardURL(deployedResource.Name))
 
 	return []schema.APIResponse{
@@ -348,6 +416,8 @@ func DescribeAPIByName(deployedResource *operator.DeployedResource) ([]schema.AP
 			Status:       apiStatus,
 			Endpoint:     &apiEndpoint,
 			DashboardURL: dashboardURL,
+			Events:       events,
+			ImagePrepull: imagePrepull,
 		},
 	}, nil
 }
@@ -369,14 +439,21 @@ func UpdateAPIMetricsCron(apiDeployment *kapps.Deployment) error {
 		return err
 	}
 
-	metricsCron := updateQueueLengthMetricsFn(apiName, queueURL)
+	deadLetterQueueURL, err := getDeadLetterQueueURL(apiName, initialDeploymentTime)
+	if err != nil {
+		return err
+	}
+
+	metricsCron := updateQueueLengthMetricsFn(apiName, queueURL, deadLetterQueueURL)
 
 	_metricsCrons[apiName] = cron.Run(metricsCron, operator.ErrorHandler(apiName+" metrics"), _tickPeriodMetrics)
 
 	return nil
 }
 
-func getK8sResources(apiName string) (resources, error) {
+func getK8sResources(apiName string, namespace string) (resources, error) {
+	k8sClient := config.K8s.WithNamespace(namespace)
+
 	var deployment *kapps.Deployment
 	var apiConfigMap *kcore.ConfigMap
 	var apiVirtualService *istioclientnetworking.VirtualService
@@ -386,17 +463,17 @@ func getK8sResources(apiName string) (resources, error) {
 	err := parallel.RunFirstErr(
 		func() error {
 			var err error
-			deployment, err = config.K8s.GetDeployment(apiK8sName)
+			deployment, err = k8sClient.GetDeployment(apiK8sName)
 			return err
 		},
 		func() error {
 			var err error
-			apiConfigMap, err = config.K8s.GetConfigMap(apiK8sName)
+			apiConfigMap, err = k8sClient.GetConfigMap(apiK8sName)
 			return err
 		},
 		func() error {
 			var err error
-			apiVirtualService, err = config.K8s.GetVirtualService(apiK8sName)
+			apiVirtualService, err = k8sClient.GetVirtualService(apiK8sName)
 			return err
 		},
 	)
@@ -417,13 +494,15 @@ func applyK8sResources(api spec.API, prevK8sResources resources, queueURL string
 
 	apiVirtualService := apiVirtualServiceSpec(api, queueURL)
 
+	k8sClient := config.K8s.WithNamespace(api.Namespace())
+
 	return parallel.RunFirstErr(
 		func() error {
-			if err := applyK8sConfigMap(prevK8sResources.apiConfigMap, &apiConfigMap); err != nil {
+			if err := applyK8sConfigMap(k8sClient, prevK8sResources.apiConfigMap, &apiConfigMap); err != nil {
 				return err
 			}
 
-			if err := applyK8sDeployment(prevK8sResources.apiDeployment, &apiDeployment); err != nil {
+			if err := applyK8sDeployment(k8sClient, prevK8sResources.apiDeployment, &apiDeployment); err != nil {
 				return err
 			}
 
@@ -434,19 +513,22 @@ func applyK8sResources(api spec.API, prevK8sResources resources, queueURL string
 			return nil
 		},
 		func() error {
-			return applyK8sVirtualService(prevK8sResources.apiVirtualService, &apiVirtualService)
+			return applyK8sVirtualService(k8sClient, prevK8sResources.apiVirtualService, &apiVirtualService)
+		},
+		func() error {
+			return operator.ApplyImagePrepull(&api)
 		},
 	)
 }
 
-func applyK8sConfigMap(prevConfigMap *kcore.ConfigMap, newConfigMap *kcore.ConfigMap) error {
+func applyK8sConfigMap(k8sClient *k8s.Client, prevConfigMap *kcore.ConfigMap, newConfigMap *kcore.ConfigMap) error {
 	if prevConfigMap == nil {
-		_, err := config.K8s.CreateConfigMap(newConfigMap)
+		_, err := k8sClient.CreateConfigMap(newConfigMap)
 		if err != nil {
 			return err
 		}
 	} else {
-		_, err := config.K8s.UpdateConfigMap(newConfigMap)
+		_, err := k8sClient.UpdateConfigMap(newConfigMap)
 		if err != nil {
 			return err
 		}
@@ -454,21 +536,21 @@ func applyK8sConfigMap(prevConfigMap *kcore.ConfigMap, newConfigMap *kcore.Confi
 	return nil
 }
 
-func applyK8sDeployment(prevDeployment *kapps.Deployment, newDeployment *kapps.Deployment) error {
+func applyK8sDeployment(k8sClient *k8s.Client, prevDeployment *kapps.Deployment, newDeployment *kapps.Deployment) error {
 	if prevDeployment == nil {
-		_, err := config.K8s.CreateDeployment(newDeployment)
+		_, err := k8sClient.CreateDeployment(newDeployment)
 		if err != nil {
 			return err
 		}
 	} else if prevDeployment.Status.ReadyReplicas == 0 {
 		// Delete deployment if it never became ready
-		_, _ = config.K8s.DeleteDeployment(prevDeployment.Name)
-		_, err := config.K8s.CreateDeployment(newDeployment)
+		_, _ = k8sClient.DeleteDeployment(prevDeployment.Name)
+		_, err := k8sClient.CreateDeployment(newDeployment)
 		if err != nil {
 			return err
 		}
 	} else {
-		_, err := config.K8s.UpdateDeployment(newDeployment)
+		_, err := k8sClient.UpdateDeployment(newDeployment)
 		if err != nil {
 			return err
 		}
@@ -476,13 +558,13 @@ func applyK8sDeployment(prevDeployment *kapps.Deployment, newDeployment *kapps.D
 	return nil
 }
 
-func applyK8sVirtualService(prevVirtualService *istioclientnetworking.VirtualService, newVirtualService *istioclientnetworking.VirtualService) error {
+func applyK8sVirtualService(k8sClient *k8s.Client, prevVirtualService *istioclientnetworking.VirtualService, newVirtualService *istioclientnetworking.VirtualService) error {
 	if prevVirtualService == nil {
-		_, err := config.K8s.CreateVirtualService(newVirtualService)
+		_, err := k8sClient.CreateVirtualService(newVirtualService)
 		return err
 	}
 
-	_, err := config.K8s.UpdateVirtualService(prevVirtualService, newVirtualService)
+	_, err := k8sClient.UpdateVirtualService(prevVirtualService, newVirtualService)
 	return err
 }
 
@@ -491,7 +573,8 @@ func deleteBucketResources(apiName string) error {
 	return config.AWS.DeleteS3Dir(config.ClusterConfig.Bucket, prefix, true)
 }
 
-func deleteK8sResources(apiName string) error {
+func deleteK8sResources(apiName string, namespace string) error {
+	k8sClient := config.K8s.WithNamespace(namespace)
 	apiK8sName := workloads.K8sName(apiName)
 
 	err := parallel.RunFirstErr(
@@ -501,17 +584,20 @@ func deleteK8sResources(apiName string) error {
 				delete(_metricsCrons, apiName)
 			}
 
-			_, err := config.K8s.DeleteDeployment(apiK8sName)
+			_, err := k8sClient.DeleteDeployment(apiK8sName)
 			return err
 		},
 		func() error {
-			_, err := config.K8s.DeleteConfigMap(apiK8sName)
+			_, err := k8sClient.DeleteConfigMap(apiK8sName)
 			return err
 		},
 		func() error {
-			_, err := config.K8s.DeleteVirtualService(apiK8sName)
+			_, err := k8sClient.DeleteVirtualService(apiK8sName)
 			return err
 		},
+		func() error {
+			return operator.DeleteImagePrepull(apiName, namespace)
+		},
 	)
 
 	return err
@@ -519,7 +605,7 @@ func deleteK8sResources(apiName string) error {
 
 // returns true if min_replicas are not ready and no updated replicas have errored
 func isAPIUpdating(deployment *kapps.Deployment) (bool, error) {
-	pods, err := config.K8s.ListPodsByLabel("apiName", deployment.Labels["apiName"])
+	pods, err := config.K8s.WithNamespace(deployment.Namespace).ListPodsByLabel("apiName", deployment.Labels["apiName"])
 	if err != nil {
 		return false, err
 	}
@@ -23,7 +23,8 @@ import (
 )
 
 const (
-	ErrAPIUpdating = "asyncapi.api_updating"
+	ErrAPIUpdating                  = "asyncapi.api_updating"
+	ErrDeadLetterQueueNotConfigured = "asyncapi.dead_letter_queue_not_configured"
 )
 
 func ErrorAPIUpdating(apiName string) error {
@@ -32,3 +33,10 @@ func ErrorAPIUpdating(apiName string) error {
 		Message: fmt.Sprintf("%s is updating (override with --force)", apiName),
 	})
 }
+
+func ErrorDeadLetterQueueNotConfigured(apiName string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrDeadLetterQueueNotConfigured,
+		Message: fmt.Sprintf("%s does not have a dead-letter queue (specify async.max_receive_count in the api's configuration to enable it)", apiName),
+	})
+}
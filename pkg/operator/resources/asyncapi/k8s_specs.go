@@ -17,8 +17,11 @@ limitations under the License.
 package asyncapi
 
 import (
+	"strings"
+
 	"github.com/cortexlabs/cortex/pkg/consts"
 	"github.com/cortexlabs/cortex/pkg/lib/k8s"
+	"github.com/cortexlabs/cortex/pkg/lib/maps"
 	"github.com/cortexlabs/cortex/pkg/lib/pointer"
 	s "github.com/cortexlabs/cortex/pkg/lib/strings"
 	"github.com/cortexlabs/cortex/pkg/types/spec"
@@ -32,20 +35,46 @@ import (
 var _terminationGracePeriodSeconds int64 = 60 // seconds
 
 func apiVirtualServiceSpec(api spec.API, queueURL string) v1beta1.VirtualService {
+	requestHeaders := map[string]string{
+		consts.CortexAPINameHeader:  api.Name,
+		consts.CortexQueueURLHeader: queueURL,
+	}
+	if api.Async != nil {
+		if api.Async.ResultTTL != nil {
+			requestHeaders[consts.CortexResultTTLHeader] = api.Async.ResultTTL.String()
+		}
+		requestHeaders[consts.CortexIdempotencyWindowHeader] = api.Async.IdempotencyWindow.String()
+	}
+	headers := &istionetworking.Headers{
+		Request: &istionetworking.Headers_HeaderOperations{
+			Set: requestHeaders,
+		},
+	}
+
 	return *k8s.VirtualService(&k8s.VirtualServiceSpec{
 		Name:     workloads.K8sName(api.Name),
-		Gateways: []string{"apis-gateway"},
+		Gateways: []string{workloads.APIGatewayRef(api.Networking)},
 		Destinations: []k8s.Destination{
 			{
-				ServiceName: "async-gateway",
+				ServiceName: workloads.ClusterServiceHost("async-gateway"),
 				Weight:      100,
 				Port:        uint32(consts.ProxyPortInt32),
-				Headers: &istionetworking.Headers{
-					Request: &istionetworking.Headers_HeaderOperations{
-						Set: map[string]string{
-							consts.CortexAPINameHeader:  api.Name,
-							consts.CortexQueueURLHeader: queueURL,
-						},
+				Headers:     headers,
+			},
+		},
+		// grpc paths are determined by the grpc service, so grpc requests are routed by the api name in their metadata
+		HeaderRoutes: []k8s.HeaderRoute{
+			{
+				PrefixPath: consts.AsyncGatewayGRPCPathPrefix,
+				Headers: map[string]string{
+					strings.ToLower(consts.CortexAPINameHeader): api.Name,
+				},
+				Destinations: []k8s.Destination{
+					{
+						ServiceName: workloads.ClusterServiceHost("async-gateway"),
+						Weight:      100,
+						Port:        uint32(consts.AsyncGatewayGRPCPortInt32),
+						Headers:     headers,
 					},
 				},
 			},
@@ -53,7 +82,7 @@ func apiVirtualServiceSpec(api spec.API, queueURL string) v1beta1.VirtualService
 		PrefixPath:  api.Networking.Endpoint,
 		Rewrite:     pointer.String("/"),
 		Annotations: api.ToK8sAnnotations(),
-		Labels: map[string]string{
+		Labels: maps.MergeStrMapsString(api.Labels, map[string]string{
 			"apiName":               api.Name,
 			"apiKind":               api.Kind.String(),
 			"apiID":                 api.ID,
@@ -62,7 +91,8 @@ func apiVirtualServiceSpec(api spec.API, queueURL string) v1beta1.VirtualService
 			"deploymentID":          api.DeploymentID,
 			"podID":                 api.PodID,
 			"cortex.dev/api":        "true",
-		},
+			"cortex.dev/project":    api.Project,
+		}),
 	})
 }
 
@@ -109,6 +139,7 @@ func deploymentSpec(api spec.API, prevDeployment *kapps.Deployment, queueURL str
 			"deploymentID":          api.DeploymentID,
 			"podID":                 api.PodID,
 			"cortex.dev/api":        "true",
+			"cortex.dev/project":    api.Project,
 		},
 		Annotations: api.ToK8sAnnotations(),
 		Selector: map[string]string{
@@ -125,15 +156,18 @@ func deploymentSpec(api spec.API, prevDeployment *kapps.Deployment, queueURL str
 				"podID":                 api.PodID,
 				"cortex.dev/api":        "true",
 			},
+			Annotations: workloads.IAMRolePodAnnotations(api.Pod),
 			K8sPodSpec: kcore.PodSpec{
 				RestartPolicy:                 "Always",
 				TerminationGracePeriodSeconds: pointer.Int64(_terminationGracePeriodSeconds),
+				InitContainers:                workloads.InitContainers(api),
 				Containers:                    containers,
 				NodeSelector:                  workloads.NodeSelectors(),
 				Tolerations:                   workloads.GenerateResourceTolerations(),
 				Affinity:                      workloads.GenerateNodeAffinities(api.NodeGroups),
 				Volumes:                       volumes,
-				ServiceAccountName:            workloads.ServiceAccountName,
+				ServiceAccountName:            workloads.PodServiceAccountName(api.Name, api.Pod),
+				ImagePullSecrets:              workloads.ImagePullSecrets(api.Name, api.Pod),
 			},
 		},
 	})
@@ -18,26 +18,38 @@ package asyncapi
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/sqs"
 	"github.com/cortexlabs/cortex/pkg/config"
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	libjson "github.com/cortexlabs/cortex/pkg/lib/json"
 	s "github.com/cortexlabs/cortex/pkg/lib/strings"
 	"github.com/cortexlabs/cortex/pkg/types/clusterconfig"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
 )
 
+// the maximum message retention period allowed by sqs, so that failed workloads can be redriven for as long as possible
+const _deadLetterQueueRetentionSeconds = int64(14 * 24 * 60 * 60)
+
 func createFIFOQueue(apiName string, initialDeploymentTime int64, tags map[string]string) (string, error) {
+	return createFIFOQueueWithName(apiQueueName(apiName, initialDeploymentTime), tags, nil)
+}
+
+func createFIFOQueueWithName(queueName string, tags map[string]string, extraAttributes map[string]string) (string, error) {
 	for key, value := range config.ClusterConfig.Tags {
 		tags[key] = value
 	}
 
-	queueName := apiQueueName(apiName, initialDeploymentTime)
-
 	attributes := map[string]string{
 		sqs.QueueAttributeNameFifoQueue:         "true",
 		sqs.QueueAttributeNameVisibilityTimeout: "60",
 	}
+	for key, value := range extraAttributes {
+		attributes[key] = value
+	}
 
 	output, err := config.AWS.SQS().CreateQueue(
 		&sqs.CreateQueueInput{
@@ -53,6 +65,60 @@ func createFIFOQueue(apiName string, initialDeploymentTime int64, tags map[strin
 	return *output.QueueUrl, nil
 }
 
+// applyRedrivePolicy creates the api's dead-letter queue and points the api's queue at it if async.max_receive_count is specified;
+// otherwise the redrive policy is removed (the dead-letter queue is kept until the api is deleted, since it may contain workloads)
+func applyRedrivePolicy(api spec.API, queueURL string) error {
+	if api.Async == nil || api.Async.MaxReceiveCount == nil {
+		_, err := config.AWS.SQS().SetQueueAttributes(&sqs.SetQueueAttributesInput{
+			QueueUrl: aws.String(queueURL),
+			Attributes: aws.StringMap(map[string]string{
+				sqs.QueueAttributeNameRedrivePolicy: "",
+			}),
+		})
+		if err != nil {
+			return errors.Wrap(err, "failed to remove redrive policy from sqs queue", queueURL)
+		}
+		return nil
+	}
+
+	deadLetterQueueURL, err := createFIFOQueueWithName(
+		apiDeadLetterQueueName(api.Name, api.InitialDeploymentTime),
+		map[string]string{"apiName": api.Name},
+		map[string]string{sqs.QueueAttributeNameMessageRetentionPeriod: s.Int64(_deadLetterQueueRetentionSeconds)},
+	)
+	if err != nil {
+		return err
+	}
+
+	deadLetterQueueAttributes, err := config.AWS.SQS().GetQueueAttributes(&sqs.GetQueueAttributesInput{
+		QueueUrl:       aws.String(deadLetterQueueURL),
+		AttributeNames: aws.StringSlice([]string{sqs.QueueAttributeNameQueueArn}),
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to get sqs queue arn", deadLetterQueueURL)
+	}
+
+	redrivePolicyJSONBytes, err := libjson.Marshal(map[string]string{
+		"deadLetterTargetArn": aws.StringValue(deadLetterQueueAttributes.Attributes[sqs.QueueAttributeNameQueueArn]),
+		"maxReceiveCount":     s.Int64(*api.Async.MaxReceiveCount),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = config.AWS.SQS().SetQueueAttributes(&sqs.SetQueueAttributesInput{
+		QueueUrl: aws.String(queueURL),
+		Attributes: aws.StringMap(map[string]string{
+			sqs.QueueAttributeNameRedrivePolicy: string(redrivePolicyJSONBytes),
+		}),
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to set redrive policy on sqs queue", queueURL)
+	}
+
+	return nil
+}
+
 func apiQueueName(apiName string, initialDeploymentTime int64) string {
 	// initialDeploymentTime is incorporated so that the queue name changes when doing a deploy after a delete
 	// (if the queue name doesn't change, the user would have to wait 60 seconds before recreating the queue)
@@ -61,6 +127,10 @@ func apiQueueName(apiName string, initialDeploymentTime int64) string {
 	return config.ClusterConfig.SQSNamePrefix() + apiName + clusterconfig.SQSQueueDelimiter + initialDeploymentTimeID + ".fifo"
 }
 
+func apiDeadLetterQueueName(apiName string, initialDeploymentTime int64) string {
+	return strings.TrimSuffix(apiQueueName(apiName, initialDeploymentTime), ".fifo") + clusterconfig.SQSQueueDelimiter + "dlq.fifo"
+}
+
 func deleteQueueByURL(queueURL string) error {
 	_, err := config.AWS.SQS().DeleteQueue(&sqs.DeleteQueueInput{
 		QueueUrl: aws.String(queueURL),
@@ -83,3 +153,32 @@ func getQueueURL(apiName string, initialDeploymentTime int64) (string, error) {
 		config.AWS.Region, operatorAccountID, apiQueueName(apiName, initialDeploymentTime),
 	), nil
 }
+
+func getDeadLetterQueueURL(apiName string, initialDeploymentTime int64) (string, error) {
+	operatorAccountID, _, err := config.AWS.GetCachedAccountID()
+	if err != nil {
+		return "", errors.Wrap(err, "failed to construct queue url", "unable to get account id")
+	}
+
+	return fmt.Sprintf(
+		"https://sqs.%s.amazonaws.com/%s/%s",
+		config.AWS.Region, operatorAccountID, apiDeadLetterQueueName(apiName, initialDeploymentTime),
+	), nil
+}
+
+func getQueueLength(queueURL string) (int, error) {
+	output, err := config.AWS.SQS().GetQueueAttributes(&sqs.GetQueueAttributesInput{
+		QueueUrl:       aws.String(queueURL),
+		AttributeNames: aws.StringSlice([]string{sqs.QueueAttributeNameApproximateNumberOfMessages}),
+	})
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get sqs queue attributes", queueURL)
+	}
+
+	queueLength, err := strconv.Atoi(aws.StringValue(output.Attributes[sqs.QueueAttributeNameApproximateNumberOfMessages]))
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+
+	return queueLength, nil
+}
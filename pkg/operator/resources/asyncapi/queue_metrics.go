@@ -24,6 +24,7 @@ import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/sqs"
 	"github.com/cortexlabs/cortex/pkg/config"
+	awslib "github.com/cortexlabs/cortex/pkg/lib/aws"
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
 	"github.com/cortexlabs/cortex/pkg/types/userconfig"
 	"github.com/prometheus/client_golang/prometheus"
@@ -58,7 +59,15 @@ var inFlightGauge = promauto.NewGaugeVec(
 	}, []string{"api_name"},
 )
 
-func updateQueueLengthMetricsFn(apiName, queueURL string) func() error {
+var deadLetterQueuedGauge = promauto.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name:        "cortex_async_dead_letter_queued",
+		Help:        "The number of workloads in an AsyncAPI's dead-letter queue",
+		ConstLabels: map[string]string{"api_kind": userconfig.AsyncAPIKind.String()},
+	}, []string{"api_name"},
+)
+
+func updateQueueLengthMetricsFn(apiName, queueURL, deadLetterQueueURL string) func() error {
 	return func() error {
 		sqsClient := config.AWS.SQS()
 
@@ -95,6 +104,17 @@ func updateQueueLengthMetricsFn(apiName, queueURL string) func() error {
 		queuedGauge.WithLabelValues(apiName).Set(visibleMessages)
 		inFlightGauge.WithLabelValues(apiName).Set(invisibleMessages + visibleMessages)
 
+		deadLetterQueueLength, err := getQueueLength(deadLetterQueueURL)
+		if err != nil {
+			if awslib.IsNonExistentQueueErr(err) {
+				// the dead-letter queue only exists if async.max_receive_count is specified
+				deadLetterQueuedGauge.DeleteLabelValues(apiName)
+				return nil
+			}
+			return err
+		}
+		deadLetterQueuedGauge.WithLabelValues(apiName).Set(float64(deadLetterQueueLength))
+
 		return nil
 	}
 }
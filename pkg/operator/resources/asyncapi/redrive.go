@@ -0,0 +1,125 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package asyncapi
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/cortexlabs/cortex/pkg/config"
+	awslib "github.com/cortexlabs/cortex/pkg/lib/aws"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/k8s"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	"github.com/cortexlabs/cortex/pkg/types/async"
+	"github.com/cortexlabs/cortex/pkg/workloads"
+)
+
+const _redriveVisibilityTimeoutSeconds = 60
+
+// RedriveDeadLetterQueue moves the workloads in the api's dead-letter queue back onto the api's queue
+func RedriveDeadLetterQueue(apiName string, namespace string) (string, error) {
+	virtualService, err := config.K8s.WithNamespace(namespace).GetVirtualService(workloads.K8sName(apiName))
+	if err != nil {
+		return "", err
+	}
+	if virtualService == nil {
+		return "", errors.ErrorUnexpected("unable to find virtual service", apiName)
+	}
+
+	initialDeploymentTime, err := k8s.ParseInt64Label(virtualService, "initialDeploymentTime")
+	if err != nil {
+		return "", err
+	}
+
+	queueURL, err := getQueueURL(apiName, initialDeploymentTime)
+	if err != nil {
+		return "", err
+	}
+
+	deadLetterQueueURL, err := getDeadLetterQueueURL(apiName, initialDeploymentTime)
+	if err != nil {
+		return "", err
+	}
+
+	storagePath := async.StoragePath(config.ClusterConfig.ClusterUID, apiName)
+
+	numRedriven := 0
+	for {
+		output, err := config.AWS.SQS().ReceiveMessage(&sqs.ReceiveMessageInput{
+			QueueUrl:            aws.String(deadLetterQueueURL),
+			MaxNumberOfMessages: aws.Int64(10),
+			VisibilityTimeout:   aws.Int64(_redriveVisibilityTimeoutSeconds),
+		})
+		if err != nil {
+			if awslib.IsNonExistentQueueErr(err) {
+				return "", ErrorDeadLetterQueueNotConfigured(apiName)
+			}
+			return "", errors.Wrap(err, "failed to receive messages from dead-letter queue", deadLetterQueueURL)
+		}
+
+		if len(output.Messages) == 0 {
+			break
+		}
+
+		for _, message := range output.Messages {
+			if err := redriveMessage(message, storagePath, queueURL, deadLetterQueueURL); err != nil {
+				return "", errors.Wrap(err, fmt.Sprintf("resubmitted %d %s before the failure", numRedriven, s.PluralS("workload", numRedriven)))
+			}
+			numRedriven++
+		}
+	}
+
+	if numRedriven == 0 {
+		return fmt.Sprintf("the dead-letter queue of %s is empty", apiName), nil
+	}
+	return fmt.Sprintf("resubmitted %d %s from the dead-letter queue of %s", numRedriven, s.PluralS("workload", numRedriven), apiName), nil
+}
+
+func redriveMessage(message *sqs.Message, storagePath string, queueURL string, deadLetterQueueURL string) error {
+	workloadID := aws.StringValue(message.Body)
+
+	// reset the workload's status to in_queue (the in_queue status file is never deleted)
+	for _, status := range []async.Status{async.StatusFailed, async.StatusInProgress} {
+		if err := config.AWS.DeleteS3File(config.ClusterConfig.Bucket, async.StatusPath(storagePath, workloadID, status)); err != nil {
+			return errors.Wrap(err, "failed to reset status of workload", workloadID)
+		}
+	}
+
+	_, err := config.AWS.SQS().SendMessage(&sqs.SendMessageInput{
+		QueueUrl:       aws.String(queueURL),
+		MessageBody:    aws.String(workloadID),
+		MessageGroupId: aws.String(workloadID),
+		// the workload's previous deduplication ids may still be within sqs's 5 minute deduplication interval
+		MessageDeduplicationId: aws.String(fmt.Sprintf("%s-%d", workloadID, time.Now().UnixNano())),
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to resubmit workload", workloadID)
+	}
+
+	_, err = config.AWS.SQS().DeleteMessage(&sqs.DeleteMessageInput{
+		QueueUrl:      aws.String(deadLetterQueueURL),
+		ReceiptHandle: message.ReceiptHandle,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to delete workload from dead-letter queue", workloadID)
+	}
+
+	return nil
+}
@@ -0,0 +1,297 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/config"
+	serving "github.com/cortexlabs/cortex/pkg/crds/apis/serving/v1alpha1"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/maps"
+	"github.com/cortexlabs/cortex/pkg/lib/slices"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+	kmeta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const CortexAPICronPeriod = 10 * time.Second
+
+// how long to wait before retrying to deploy a CortexAPI whose spec hasn't changed since the previous attempt failed
+const _cortexAPIRetryPeriod = time.Minute
+
+const _cortexAPIFinalizer = "api.finalizers.serving.cortex.dev"
+
+// ReconcileCortexAPIs deploys the api of each CortexAPI resource whenever its spec changes or the api drifts from it (e.g. it was deleted or
+// redeployed with `cortex deploy`), deletes the api when the resource is deleted, and mirrors the api's status (as shown by `cortex get`) in the resource's status
+func ReconcileCortexAPIs() error {
+	ctx := context.Background()
+
+	var cortexAPIList serving.CortexAPIList
+	if err := config.K8s.List(ctx, &cortexAPIList, client.InNamespace(config.K8s.Namespace)); err != nil {
+		return err
+	}
+	if len(cortexAPIList.Items) == 0 {
+		return nil
+	}
+
+	deployedAPIIDs, err := getDeployedAPIIDs()
+	if err != nil {
+		return err
+	}
+
+	ownerUIDs, err := getCortexAPIOwnerUIDs()
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	var deletedAPIs []*serving.CortexAPI
+	var apisToDeploy []*serving.CortexAPI
+	var apis []*serving.CortexAPI
+	for i := range cortexAPIList.Items {
+		cortexAPI := &cortexAPIList.Items[i]
+
+		if cortexAPI.DeletionTimestamp != nil {
+			if slices.HasString(cortexAPI.Finalizers, _cortexAPIFinalizer) {
+				deletedAPIs = append(deletedAPIs, cortexAPI)
+			}
+			continue
+		}
+
+		if !slices.HasString(cortexAPI.Finalizers, _cortexAPIFinalizer) {
+			cortexAPI.Finalizers = append(cortexAPI.Finalizers, _cortexAPIFinalizer)
+			if err := config.K8s.Update(ctx, cortexAPI); err != nil {
+				errs = append(errs, errors.Wrap(err, "CortexAPI", cortexAPI.Name))
+				continue
+			}
+		}
+
+		apis = append(apis, cortexAPI)
+		if isCortexAPIOutOfSync(cortexAPI, deployedAPIIDs) {
+			apisToDeploy = append(apisToDeploy, cortexAPI)
+		}
+	}
+
+	// traffic splitters are deleted first so that the apis which they reference can be deleted, and deployed last so that the apis which they reference exist
+	sort.SliceStable(deletedAPIs, func(i, j int) bool {
+		return deletedAPIs[i].Status.Kind == userconfig.TrafficSplitterKind.String() && deletedAPIs[j].Status.Kind != userconfig.TrafficSplitterKind.String()
+	})
+	sort.SliceStable(apisToDeploy, func(i, j int) bool {
+		return cortexAPIKind(apisToDeploy[i]) != userconfig.TrafficSplitterKind && cortexAPIKind(apisToDeploy[j]) == userconfig.TrafficSplitterKind
+	})
+
+	for _, cortexAPI := range deletedAPIs {
+		if err := deleteCortexAPI(ctx, cortexAPI, ownerUIDs); err != nil {
+			errs = append(errs, errors.Wrap(err, "CortexAPI", cortexAPI.Name))
+		}
+	}
+
+	for _, cortexAPI := range apisToDeploy {
+		deployCortexAPI(cortexAPI, ownerUIDs)
+	}
+
+	apiResponses, _, err := GetAPIs("", "", 0, 0)
+	if err != nil {
+		return errors.FirstError(append(errs, err)...)
+	}
+	apiResponsesByName := map[string]schema.APIResponse{}
+	for _, apiResponse := range apiResponses {
+		if apiResponse.Metadata != nil {
+			apiResponsesByName[apiResponse.Metadata.Name] = apiResponse
+		}
+	}
+
+	for _, cortexAPI := range apis {
+		prevStatus := cortexAPI.Status.DeepCopy()
+
+		apiResponse, isDeployed := apiResponsesByName[cortexAPI.Name]
+		updateCortexAPIStatus(cortexAPI, apiResponse, isDeployed && isCortexAPIOwner(cortexAPI, ownerUIDs))
+
+		if reflect.DeepEqual(*prevStatus, cortexAPI.Status) {
+			continue
+		}
+		if err := config.K8s.Status().Update(ctx, cortexAPI); err != nil {
+			errs = append(errs, errors.Wrap(err, "CortexAPI", cortexAPI.Name))
+		}
+	}
+
+	return errors.FirstError(errs...)
+}
+
+// checks whether the resource has changed since it was last deployed, the api has drifted from it, or it should be retried after a failed attempt
+func isCortexAPIOutOfSync(cortexAPI *serving.CortexAPI, deployedAPIIDs map[string]string) bool {
+	status := cortexAPI.Status
+
+	if status.ObservedGeneration != cortexAPI.Generation {
+		return true
+	}
+
+	if status.Error != "" {
+		return status.LastAttemptTime == nil || time.Since(status.LastAttemptTime.Time) >= _cortexAPIRetryPeriod
+	}
+
+	deployedID, isDeployed := deployedAPIIDs[cortexAPI.Name]
+	return !isDeployed || deployedID != status.APIID
+}
+
+// returns the uid of the CortexAPI resource which deployed each api (or an empty string for apis which were not deployed by a CortexAPI resource), keyed by api name
+func getCortexAPIOwnerUIDs() (map[string]string, error) {
+	virtualServices, err := config.K8sAllNamspaces.ListVirtualServicesWithLabelKeys("apiName")
+	if err != nil {
+		return nil, err
+	}
+
+	ownerUIDs := map[string]string{}
+	for _, virtualService := range virtualServices {
+		ownerUIDs[virtualService.Labels["apiName"]] = virtualService.Labels[userconfig.CortexAPIUIDLabelKey]
+	}
+	return ownerUIDs, nil
+}
+
+// checks whether the resource's api is deployed and was deployed by the resource
+func isCortexAPIOwner(cortexAPI *serving.CortexAPI, ownerUIDs map[string]string) bool {
+	ownerUID, isDeployed := ownerUIDs[cortexAPI.Name]
+	return isDeployed && ownerUID == string(cortexAPI.UID)
+}
+
+// deploys the resource's api, and records the result in the resource's status (which is written by the caller);
+// an api with the same name which was not deployed by the resource (e.g. with `cortex deploy`) is not overwritten
+func deployCortexAPI(cortexAPI *serving.CortexAPI, ownerUIDs map[string]string) {
+	now := kmeta.Now()
+	cortexAPI.Status.ObservedGeneration = cortexAPI.Generation
+	cortexAPI.Status.LastAttemptTime = &now
+
+	setError := func(err error) {
+		cortexAPI.Status.State = serving.CortexAPIStateError
+		cortexAPI.Status.Error = errors.ErrorStr(err)
+	}
+
+	if _, isDeployed := ownerUIDs[cortexAPI.Name]; isDeployed && !isCortexAPIOwner(cortexAPI, ownerUIDs) {
+		setError(ErrorCortexAPINotOwner(cortexAPI.Name))
+		return
+	}
+
+	apiConfig, err := cortexAPIConfig(cortexAPI)
+	if err != nil {
+		setError(err)
+		return
+	}
+	apiConfig.Labels = maps.MergeStrMapsString(apiConfig.Labels, map[string]string{
+		userconfig.CortexAPIUIDLabelKey: string(cortexAPI.UID),
+	})
+
+	results, err := deployAPIConfigs([]userconfig.API{*apiConfig}, false, false)
+	if err != nil {
+		setError(err)
+		return
+	}
+
+	result := results[0]
+	if result.Error != "" {
+		cortexAPI.Status.State = serving.CortexAPIStateError
+		cortexAPI.Status.Error = result.Error
+		return
+	}
+
+	cortexAPI.Status.State = serving.CortexAPIStateSynced
+	cortexAPI.Status.Error = ""
+	if result.API != nil && result.API.Spec != nil {
+		cortexAPI.Status.APIID = result.API.Spec.ID
+	}
+}
+
+// deletes the resource's api (unless it was not deployed by the resource), and then removes the finalizer so that the resource can be deleted
+func deleteCortexAPI(ctx context.Context, cortexAPI *serving.CortexAPI, ownerUIDs map[string]string) error {
+	if isCortexAPIOwner(cortexAPI, ownerUIDs) {
+		if _, err := DeleteAPI(cortexAPI.Name, false, true); err != nil {
+			return err
+		}
+	}
+
+	cortexAPI.Finalizers = slices.RemoveString(cortexAPI.Finalizers, _cortexAPIFinalizer)
+	return config.K8s.Update(ctx, cortexAPI)
+}
+
+func updateCortexAPIStatus(cortexAPI *serving.CortexAPI, apiResponse schema.APIResponse, isDeployed bool) {
+	status := &cortexAPI.Status
+
+	status.Kind = ""
+	status.Endpoint = ""
+	status.Ready = 0
+	status.Requested = 0
+	status.UpToDate = 0
+
+	if !isDeployed {
+		return
+	}
+
+	status.Kind = apiResponse.Metadata.Kind.String()
+	if apiResponse.Endpoint != nil {
+		status.Endpoint = *apiResponse.Endpoint
+	}
+	if apiResponse.Status != nil {
+		status.Ready = apiResponse.Status.Ready
+		status.Requested = apiResponse.Status.Requested
+		status.UpToDate = apiResponse.Status.UpToDate
+	}
+}
+
+// converts the resource's spec into an api configuration; the name of the api is the name of the resource
+func cortexAPIConfig(cortexAPI *serving.CortexAPI) (*userconfig.API, error) {
+	var data map[string]interface{}
+	if err := json.Unmarshal(cortexAPI.Spec.Raw, &data); err != nil || data == nil {
+		return nil, ErrorMalformedCortexAPISpec()
+	}
+
+	if name, ok := data[userconfig.NameKey]; ok {
+		if nameStr, _ := name.(string); nameStr != cortexAPI.Name {
+			return nil, ErrorCortexAPINameMismatch(cortexAPI.Name, s.ObjFlatNoQuotes(name))
+		}
+	}
+	data[userconfig.NameKey] = cortexAPI.Name
+
+	// json is valid yaml, so the spec is parsed as a config file which contains a single api
+	configBytes, err := json.Marshal([]interface{}{data})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	apiConfigs, err := spec.ExtractAPIConfigs(configBytes, "CortexAPI resource")
+	if err != nil {
+		return nil, err
+	}
+
+	return &apiConfigs[0], nil
+}
+
+// returns the kind in the resource's spec (or unknown if the spec is malformed)
+func cortexAPIKind(cortexAPI *serving.CortexAPI) userconfig.Kind {
+	var data map[string]interface{}
+	if err := json.Unmarshal(cortexAPI.Spec.Raw, &data); err != nil {
+		return userconfig.UnknownKind
+	}
+	kind, _ := data[userconfig.KindKey].(string)
+	return userconfig.KindFromString(kind)
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"fmt"
+
+	"github.com/cortexlabs/cortex/pkg/config"
+	"github.com/cortexlabs/cortex/pkg/consts"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/sets/strset"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	"github.com/cortexlabs/cortex/pkg/lib/structs"
+	"github.com/cortexlabs/cortex/pkg/operator/operator"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+	"github.com/cortexlabs/cortex/pkg/workloads"
+)
+
+// Diff validates the api configurations and compares them to the currently deployed apis, without applying any changes
+func Diff(configFileName string, configBytes []byte, project string) ([]schema.DiffResult, error) {
+	apiConfigs, err := spec.ExtractAPIConfigs(configBytes, configFileName)
+	if err != nil {
+		return nil, err
+	}
+
+	setDefaultProject(apiConfigs, project)
+
+	err = ValidateClusterAPIs(apiConfigs)
+	if err != nil {
+		err = errors.Append(err, fmt.Sprintf("\n\napi configuration schema can be found at https://docs.cortexlabs.com/v/%s/", consts.CortexVersionMinor))
+		return nil, err
+	}
+
+	// match the order in which apis are deployed
+	apiConfigs = append(ExclusiveFilterAPIsByKind(apiConfigs, userconfig.TrafficSplitterKind), InclusiveFilterAPIsByKind(apiConfigs, userconfig.TrafficSplitterKind)...)
+
+	results := make([]schema.DiffResult, 0, len(apiConfigs))
+	for i := range apiConfigs {
+		result, err := diffAPI(&apiConfigs[i])
+		if err != nil {
+			result = schema.DiffResult{
+				Name:  apiConfigs[i].Name,
+				Kind:  apiConfigs[i].Kind,
+				Error: errors.ErrorStr(err),
+			}
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+func diffAPI(apiConfig *userconfig.API) (schema.DiffResult, error) {
+	result := schema.DiffResult{
+		Name: apiConfig.Name,
+		Kind: apiConfig.Kind,
+	}
+
+	deployedResource, err := GetDeployedResourceByNameOrNil(apiConfig.Name)
+	if err != nil {
+		return result, err
+	}
+
+	if deployedResource == nil {
+		result.Action = schema.DiffActionCreate
+		return result, nil
+	}
+
+	if deployedResource.Kind != apiConfig.Kind {
+		return result, ErrorCannotChangeKindOfDeployedAPI(apiConfig.Name, apiConfig.Kind, deployedResource.Kind)
+	}
+
+	prevAPI, err := operator.DownloadAPISpec(deployedResource.Name, deployedResource.ID())
+	if err != nil {
+		return result, err
+	}
+
+	// the images' digests are compared, so that tags which point to new images are shown as changes
+	if apiConfig.Kind != userconfig.TrafficSplitterKind {
+		if err := operator.ResolveImageDigests(apiConfig); err != nil {
+			return result, err
+		}
+	}
+
+	api := spec.GetAPISpec(apiConfig, prevAPI.InitialDeploymentTime, prevAPI.DeploymentID, config.ClusterConfig.ClusterUID)
+
+	result.Changes, err = structs.Diff(comparableAPIConfig(prevAPI.API), comparableAPIConfig(api.API))
+	if err != nil {
+		return result, err
+	}
+
+	if api.SpecID == prevAPI.SpecID || len(result.Changes) == 0 {
+		result.Action = schema.DiffActionNone
+		result.Changes = nil
+		return result, nil
+	}
+
+	result.Action = schema.DiffActionUpdate
+	result.Impact, err = updateImpact(prevAPI, api)
+	if err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+// the file name, index and submitted spec don't affect the deployment
+func comparableAPIConfig(apiConfig *userconfig.API) userconfig.API {
+	comparable := *apiConfig
+	comparable.Index = 0
+	comparable.FileName = ""
+	comparable.SubmittedAPISpec = nil
+	return comparable
+}
+
+func updateImpact(prevAPI *spec.API, api *spec.API) (string, error) {
+	switch api.Kind {
+	case userconfig.RealtimeAPIKind, userconfig.AsyncAPIKind:
+		if api.PodID == prevAPI.PodID && strset.New(api.NodeGroups...).IsEqual(strset.New(prevAPI.NodeGroups...)) {
+			return "running replicas will not be restarted", nil
+		}
+
+		if api.Canary != nil {
+			return fmt.Sprintf("the new version will be deployed as a canary, which will receive %d%% of traffic once it's ready", api.Canary.Weight), nil
+		}
+
+		deployment, err := config.K8s.WithNamespace(api.Namespace()).GetDeployment(workloads.K8sName(api.Name))
+		if err != nil {
+			return "", err
+		}
+		var numReplicas int32
+		if deployment != nil {
+			numReplicas = deployment.Status.Replicas
+		}
+
+		return fmt.Sprintf("rolling update: %d running %s will be replaced (max_surge: %s, max_unavailable: %s)",
+			numReplicas, s.PluralS("replica", numReplicas), api.UpdateStrategy.MaxSurge, api.UpdateStrategy.MaxUnavailable), nil
+	case userconfig.BatchAPIKind, userconfig.TaskAPIKind:
+		return "the changes will apply to jobs submitted after the update; in-progress jobs will not be affected", nil
+	case userconfig.TrafficSplitterKind:
+		return "traffic will be re-routed according to the updated weights", nil
+	}
+
+	return "", nil
+}
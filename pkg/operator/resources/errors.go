@@ -29,22 +29,47 @@ import (
 	s "github.com/cortexlabs/cortex/pkg/lib/strings"
 	"github.com/cortexlabs/cortex/pkg/lib/table"
 	"github.com/cortexlabs/cortex/pkg/operator/operator"
+	"github.com/cortexlabs/cortex/pkg/types/clusterconfig"
 	"github.com/cortexlabs/cortex/pkg/types/userconfig"
 	"github.com/cortexlabs/cortex/pkg/workloads"
 	kresource "k8s.io/apimachinery/pkg/api/resource"
 )
 
 const (
-	ErrOperationIsOnlySupportedForKind  = "resources.operation_is_only_supported_for_kind"
-	ErrAPINotDeployed                   = "resources.api_not_deployed"
-	ErrAPIIDNotFound                    = "resources.api_id_not_found"
-	ErrCannotChangeTypeOfDeployedAPI    = "resources.cannot_change_kind_of_deployed_api"
-	ErrNoAvailableNodeComputeLimit      = "resources.no_available_node_compute_limit"
-	ErrJobIDRequired                    = "resources.job_id_required"
-	ErrRealtimeAPIUsedByTrafficSplitter = "resources.realtime_api_used_by_traffic_splitter"
-	ErrAPIsNotDeployed                  = "resources.apis_not_deployed"
-	ErrInvalidNodeGroupSelector         = "resources.invalid_node_group_selector"
-	ErrNoNodeGroups                     = "resources.no_node_groups"
+	ErrOperationIsOnlySupportedForKind                  = "resources.operation_is_only_supported_for_kind"
+	ErrAPINotDeployed                                   = "resources.api_not_deployed"
+	ErrAPIIDNotFound                                    = "resources.api_id_not_found"
+	ErrCannotChangeTypeOfDeployedAPI                    = "resources.cannot_change_kind_of_deployed_api"
+	ErrCannotChangeProjectOfDeployedAPI                 = "resources.cannot_change_project_of_deployed_api"
+	ErrNoAvailableNodeComputeLimit                      = "resources.no_available_node_compute_limit"
+	ErrJobIDRequired                                    = "resources.job_id_required"
+	ErrRealtimeAPIUsedByTrafficSplitter                 = "resources.realtime_api_used_by_traffic_splitter"
+	ErrAPIsNotDeployed                                  = "resources.apis_not_deployed"
+	ErrInvalidNodeGroupSelector                         = "resources.invalid_node_group_selector"
+	ErrNoNodeGroups                                     = "resources.no_node_groups"
+	ErrInvalidLabelSelector                             = "resources.invalid_label_selector"
+	ErrNoPreviousAPIVersion                             = "resources.no_previous_api_version"
+	ErrAPIVersionNotFound                               = "resources.api_version_not_found"
+	ErrCannotRollbackToVersionWithCanary                = "resources.cannot_rollback_to_version_with_canary"
+	ErrAPIKeyAlreadyExists                              = "resources.api_key_already_exists"
+	ErrAPIKeyNotFound                                   = "resources.api_key_not_found"
+	ErrCustomDomainRequiresTLSLoadBalancer              = "resources.custom_domain_requires_tls_load_balancer"
+	ErrPublicEndpointRequiresInternetFacingLoadBalancer = "resources.public_endpoint_requires_internet_facing_load_balancer"
+	ErrCustomDomainNotSupportedForInternalEndpoint      = "resources.custom_domain_not_supported_for_internal_endpoint"
+	ErrTrafficSplitterGRPCAPI                           = "resources.traffic_splitter_grpc_api"
+	ErrTrafficSplitterAPIInOtherProject                 = "resources.traffic_splitter_api_in_other_project"
+	ErrJobScheduleNotFound                              = "resources.job_schedule_not_found"
+	ErrGitOpsNotConfigured                              = "resources.gitops_not_configured"
+	ErrGitOpsCommandFailed                              = "resources.gitops_command_failed"
+	ErrGitOpsNoConfigFiles                              = "resources.gitops_no_config_files"
+	ErrMalformedCortexAPISpec                           = "resources.malformed_cortex_api_spec"
+	ErrCortexAPINameMismatch                            = "resources.cortex_api_name_mismatch"
+	ErrCortexAPINotOwner                                = "resources.cortex_api_not_owner"
+	ErrProjectNotFound                                  = "resources.project_not_found"
+	ErrRoleBindingAlreadyExists                         = "resources.role_binding_already_exists"
+	ErrRoleBindingNotFound                              = "resources.role_binding_not_found"
+	ErrRoleBindingLockout                               = "resources.role_binding_lockout"
+	ErrSecretInUse                                      = "resources.secret_in_use"
 )
 
 func ErrorOperationIsOnlySupportedForKind(resource operator.DeployedResource, supportedKind userconfig.Kind, supportedKinds ...userconfig.Kind) error {
@@ -82,6 +107,14 @@ func ErrorCannotChangeKindOfDeployedAPI(name string, newKind, prevKind userconfi
 	})
 }
 
+// the apis of each project are deployed to the project's namespace, so an api can't be moved to another project in place
+func ErrorCannotChangeProjectOfDeployedAPI(name string, newProject string, prevProject string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrCannotChangeProjectOfDeployedAPI,
+		Message: fmt.Sprintf("cannot change the project of %s to %s because it has already been deployed in project %s; please delete it with `cortex delete %s` and redeploy it in project %s", name, newProject, prevProject, name, newProject),
+	})
+}
+
 func ErrorNoAvailableNodeComputeLimit(api *userconfig.API, compute userconfig.Compute, maxMemMap map[string]kresource.Quantity) error {
 	msg := "no instance types in your cluster are large enough to satisfy the requested resources for your pod\n\n"
 	msg += console.Bold("requested pod resources\n")
@@ -150,6 +183,9 @@ func podResourceRequestsTable(api *userconfig.API, compute userconfig.Compute) s
 	if compute.Inf > 0 {
 		items.Add("Inf", compute.Inf)
 	}
+	if compute.NeuronCores > 0 {
+		items.Add("neuron cores", compute.NeuronCores)
+	}
 
 	return items.String()
 }
@@ -163,7 +199,7 @@ func nodeGroupResourcesTable(api *userconfig.API, compute userconfig.Compute, ma
 	if compute.GPU > 0 {
 		showGPU = true
 	}
-	if compute.Inf > 0 {
+	if compute.Inf > 0 || compute.NeuronCores > 0 {
 		showInf = true
 	}
 
@@ -179,7 +215,7 @@ func nodeGroupResourcesTable(api *userconfig.API, compute userconfig.Compute, ma
 		if api.NodeGroups != nil && !slices.HasString(api.NodeGroups, ng.Name) {
 			skippedNodeGroups = append(skippedNodeGroups, ng.Name)
 		} else {
-			nodeGroupResourceRows = append(nodeGroupResourceRows, []interface{}{ng.Name, ng.InstanceType, nodeCPU, k8s.ToMiFloorStr(nodeMem), nodeGPU, nodeInf})
+			nodeGroupResourceRows = append(nodeGroupResourceRows, []interface{}{ng.Name, ng.InstanceType, nodeCPU, k8s.ToMiFloorStr(nodeMem), nodeGPU, nodeInf, getNodeNeuronCores(ng.InstanceType, nodeInf)})
 		}
 	}
 
@@ -191,6 +227,7 @@ func nodeGroupResourcesTable(api *userconfig.API, compute userconfig.Compute, ma
 			{Title: "memory"},
 			{Title: "GPU", Hidden: !showGPU},
 			{Title: "Inf", Hidden: !showInf},
+			{Title: "neuron cores", Hidden: !showInf},
 		},
 		Rows: nodeGroupResourceRows,
 	}
@@ -202,3 +239,168 @@ func nodeGroupResourcesTable(api *userconfig.API, compute userconfig.Compute, ma
 
 	return out
 }
+
+func ErrorInvalidLabelSelector(selector string, err error) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrInvalidLabelSelector,
+		Message: fmt.Sprintf("invalid label selector \"%s\": %s", selector, errors.Message(err)),
+		Cause:   err,
+	})
+}
+
+func ErrorNoPreviousAPIVersion(apiName string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrNoPreviousAPIVersion,
+		Message: fmt.Sprintf("%s does not have a previous version with a different configuration to roll back to", apiName),
+	})
+}
+
+func ErrorAPIVersionNotFound(apiName string, version int, numVersions int) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrAPIVersionNotFound,
+		Message: fmt.Sprintf("version %d of %s was not found (%s has %d stored %s; run `cortex get %s` to see its version history, or specify an api id instead)", version, apiName, apiName, numVersions, s.PluralS("version", numVersions), apiName),
+	})
+}
+
+func ErrorCannotRollbackToVersionWithCanary(apiName string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrCannotRollbackToVersionWithCanary,
+		Message: fmt.Sprintf("%s has a canary deployed; run `cortex rollback %s` (without --to-version) to remove the canary first", apiName, apiName),
+	})
+}
+
+func ErrorAPIKeyAlreadyExists(name string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrAPIKeyAlreadyExists,
+		Message: fmt.Sprintf("an api key named %s already exists; revoke it with `cortex keys revoke %s` or choose a different name", name, name),
+	})
+}
+
+func ErrorAPIKeyNotFound(name string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrAPIKeyNotFound,
+		Message: fmt.Sprintf("api key %s was not found (run `cortex keys list` to see the existing keys)", name),
+	})
+}
+
+func ErrorCustomDomainRequiresTLSLoadBalancer() error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrCustomDomainRequiresTLSLoadBalancer,
+		Message: fmt.Sprintf("custom domains can only be used if the api load balancer is a network load balancer which terminates tls, and the cluster's hosted zones for custom domains are configured; please set %s to %s and specify %s and %s in your cluster configuration (see https://docs.cortexlabs.com/v/%s/clusters/networking/custom-domain for instructions)", clusterconfig.APILoadBalancerTypeKey, clusterconfig.NLBLoadBalancerType.String(), clusterconfig.SSLCertificateARNKey, clusterconfig.CustomDomainHostedZoneIDsKey, consts.CortexVersionMinor),
+	})
+}
+
+func ErrorPublicEndpointRequiresInternetFacingLoadBalancer() error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrPublicEndpointRequiresInternetFacingLoadBalancer,
+		Message: fmt.Sprintf("%s: %s can only be used if %s is %s in your cluster configuration", userconfig.EndpointVisibilityKey, userconfig.EndpointVisibilityPublic, clusterconfig.APILoadBalancerSchemeKey, clusterconfig.InternetFacingLoadBalancerScheme.String()),
+	})
+}
+
+func ErrorCustomDomainNotSupportedForInternalEndpoint() error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrCustomDomainNotSupportedForInternalEndpoint,
+		Message: fmt.Sprintf("%s can't be specified for apis whose %s is %s", userconfig.CustomDomainKey, userconfig.EndpointVisibilityKey, userconfig.EndpointVisibilityInternal),
+	})
+}
+
+func ErrorTrafficSplitterGRPCAPI(apiName string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrTrafficSplitterGRPCAPI,
+		Message: fmt.Sprintf("api %s can't be used in a %s because its %s.%s is %s", apiName, userconfig.TrafficSplitterKind.String(), userconfig.PodKey, userconfig.ProtocolKey, userconfig.ProtocolGRPC),
+	})
+}
+
+func ErrorTrafficSplitterAPIInOtherProject(apiName string, apiProject string, trafficSplitterProject string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrTrafficSplitterAPIInOtherProject,
+		Message: fmt.Sprintf("api %s can't be used in a %s in project %s because it is in project %s (a %s can only route traffic to apis in its own project)", apiName, userconfig.TrafficSplitterKind.String(), trafficSplitterProject, apiProject, userconfig.TrafficSplitterKind.String()),
+	})
+}
+
+func ErrorJobScheduleNotFound(apiName string, scheduleID string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrJobScheduleNotFound,
+		Message: fmt.Sprintf("job schedule %s was not found for %s (run `cortex schedule list %s` to see the api's job schedules)", scheduleID, apiName, apiName),
+	})
+}
+
+func ErrorGitOpsNotConfigured() error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrGitOpsNotConfigured,
+		Message: fmt.Sprintf("gitops is not configured for this cluster (it can be enabled by adding the %s section to your cluster configuration file)", clusterconfig.GitOpsKey),
+	})
+}
+
+func ErrorGitOpsCommandFailed(command string, output string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrGitOpsCommandFailed,
+		Message: fmt.Sprintf("`git %s` failed: %s", command, output),
+	})
+}
+
+func ErrorGitOpsNoConfigFiles(branch string, path string) error {
+	if path == "" {
+		path = "the root of the repository"
+	}
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrGitOpsNoConfigFiles,
+		Message: fmt.Sprintf("no api configuration files (*.yaml or *.yml) were found in %s on branch %s", path, branch),
+	})
+}
+
+func ErrorMalformedCortexAPISpec() error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrMalformedCortexAPISpec,
+		Message: "the spec of a CortexAPI resource must be an api configuration (a map of api configuration fields)",
+	})
+}
+
+func ErrorCortexAPINameMismatch(resourceName string, apiName string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrCortexAPINameMismatch,
+		Message: fmt.Sprintf("the %s in the spec (%s) must match the name of the CortexAPI resource (%s); the %s field can be omitted", userconfig.NameKey, apiName, resourceName, userconfig.NameKey),
+	})
+}
+
+func ErrorProjectNotFound(project string, availableProjects []string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrProjectNotFound,
+		Message: fmt.Sprintf("project \"%s\" is not declared in the %s section of the cluster configuration; specify one of the declared projects (%s), or %s", project, clusterconfig.ProjectsKey, s.StrsOr(availableProjects), userconfig.DefaultProject),
+	})
+}
+
+func ErrorRoleBindingAlreadyExists(name string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrRoleBindingAlreadyExists,
+		Message: fmt.Sprintf("a role binding named %s already exists; delete it with `cortex auth unbind %s` or choose a different name", name, name),
+	})
+}
+
+func ErrorRoleBindingNotFound(name string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrRoleBindingNotFound,
+		Message: fmt.Sprintf("role binding %s was not found (run `cortex auth list` to see the existing role bindings)", name),
+	})
+}
+
+func ErrorRoleBindingLockout(caller string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrRoleBindingLockout,
+		Message: fmt.Sprintf("this change would remove the admin role from the caller (%s), who would then be unable to manage role bindings; bind the admin role to another subject which matches the caller first", caller),
+	})
+}
+
+func ErrorSecretInUse(name string, apiNames []string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrSecretInUse,
+		Message: fmt.Sprintf("secret %s can't be deleted because it is referenced by %s %s; remove it from the %s of %s and redeploy first", name, s.PluralS("api", len(apiNames)), s.StrsAnd(apiNames), userconfig.EnvFromSecretsKey, s.PluralCustom("that api", "those apis", len(apiNames))),
+	})
+}
+
+func ErrorCortexAPINotOwner(apiName string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrCortexAPINotOwner,
+		Message: fmt.Sprintf("api %s already exists and was not deployed by this CortexAPI resource; delete it with `cortex delete %s` so that it can be deployed by this resource", apiName, apiName),
+	})
+}
@@ -0,0 +1,392 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"encoding/base64"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/config"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/files"
+	"github.com/cortexlabs/cortex/pkg/lib/sets/strset"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types/clusterconfig"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+)
+
+const GitOpsCronPeriod = time.Minute
+
+const _gitOpsRepoDir = "/tmp/gitops"
+
+var (
+	_gitOpsStatus      *schema.GitOpsStatus // nil until it has been loaded from s3
+	_gitOpsStatusMutex = sync.Mutex{}
+)
+
+// GetGitOpsStatus returns the state of the reconciliation of the cluster's apis with the gitops repository
+func GetGitOpsStatus() (*schema.GitOpsStatus, error) {
+	if config.ClusterConfig.GitOps == nil {
+		return nil, ErrorGitOpsNotConfigured()
+	}
+
+	_gitOpsStatusMutex.Lock()
+	defer _gitOpsStatusMutex.Unlock()
+
+	if err := loadGitOpsStatus(); err != nil {
+		return nil, err
+	}
+
+	status := *_gitOpsStatus
+	status.APIs = append([]schema.GitOpsAPIStatus{}, _gitOpsStatus.APIs...)
+	return &status, nil
+}
+
+// ReconcileGitOps applies the api configurations in the gitops repository whenever a new commit is pushed to its branch,
+// the previous attempt failed, or any of the apis it manages has drifted from the repository (e.g. it was deleted or redeployed with `cortex deploy`).
+// If prune is enabled, apis which were previously deployed from the repository but have since been removed from it are deleted.
+func ReconcileGitOps() error {
+	gitOps := config.ClusterConfig.GitOps
+	if gitOps == nil {
+		return nil
+	}
+
+	prevStatus, err := GetGitOpsStatus()
+	if err != nil {
+		return err
+	}
+
+	status, err := reconcileGitOps(gitOps, prevStatus)
+	if status == nil {
+		return err
+	}
+
+	_gitOpsStatusMutex.Lock()
+	_gitOpsStatus = status
+	_gitOpsStatusMutex.Unlock()
+
+	return errors.FirstError(err, config.AWS.UploadJSONToS3(status, config.ClusterConfig.Bucket, gitOpsStatusKey()))
+}
+
+// returns a nil status if the cluster is already in sync with the repository
+func reconcileGitOps(gitOps *clusterconfig.GitOps, prevStatus *schema.GitOpsStatus) (*schema.GitOpsStatus, error) {
+	now := time.Now()
+	status := &schema.GitOpsStatus{
+		Repository:  gitOps.Repository,
+		Branch:      gitOps.Branch,
+		Path:        gitOps.Path,
+		Commit:      prevStatus.Commit,
+		LastSync:    prevStatus.LastSync,
+		LastAttempt: &now,
+		APIs:        prevStatus.APIs,
+	}
+
+	commit, err := fetchGitOpsRepo(gitOps)
+	if err != nil {
+		status.Error = errors.ErrorStr(err)
+		return status, err
+	}
+
+	deployedAPIIDs, err := getDeployedAPIIDs()
+	if err != nil {
+		return nil, err
+	}
+
+	if commit == prevStatus.Commit && isGitOpsInSync(prevStatus, deployedAPIIDs) {
+		return nil, nil
+	}
+
+	status.Commit = commit
+
+	apiConfigs, err := readGitOpsAPIConfigs(gitOps, commit)
+	if err != nil {
+		status.Error = errors.ErrorStr(err)
+		return status, err
+	}
+
+	// match the order in which apis are deployed, so that the results line up with the configs
+	apiConfigs = append(ExclusiveFilterAPIsByKind(apiConfigs, userconfig.TrafficSplitterKind), InclusiveFilterAPIsByKind(apiConfigs, userconfig.TrafficSplitterKind)...)
+
+	results, err := deployAPIConfigs(apiConfigs, false, false)
+	if err != nil {
+		status.Error = errors.ErrorStr(err)
+		return status, err
+	}
+
+	apiStatuses := make([]schema.GitOpsAPIStatus, 0, len(apiConfigs))
+	apiNames := strset.New()
+	for i, result := range results {
+		apiConfig := apiConfigs[i]
+		apiNames.Add(apiConfig.Name)
+
+		apiStatus := schema.GitOpsAPIStatus{
+			Name:   apiConfig.Name,
+			Kind:   apiConfig.Kind,
+			File:   apiConfig.GitSource.Path,
+			Action: schema.GitOpsActionUpdate,
+			Error:  result.Error,
+		}
+
+		prevID, isDeployed := deployedAPIIDs[apiConfig.Name]
+		if !isDeployed {
+			apiStatus.Action = schema.GitOpsActionCreate
+		}
+		if result.API != nil && result.API.Spec != nil {
+			apiStatus.ID = result.API.Spec.ID
+			if isDeployed && apiStatus.ID == prevID {
+				apiStatus.Action = schema.GitOpsActionNone
+			}
+		}
+
+		apiStatuses = append(apiStatuses, apiStatus)
+	}
+
+	if gitOps.Prune {
+		apiStatuses = append(apiStatuses, pruneGitOpsAPIs(prevStatus.APIs, apiNames, deployedAPIIDs)...)
+	}
+
+	status.APIs = apiStatuses
+	if isGitOpsInSync(status, nil) {
+		status.LastSync = &now
+	}
+
+	return status, nil
+}
+
+// deletes the apis which were previously deployed from the repository (or could not be deleted on a previous attempt) and are no longer in it;
+// traffic splitters are deleted first so that the apis which they reference can be deleted
+func pruneGitOpsAPIs(prevAPIs []schema.GitOpsAPIStatus, apiNames strset.Set, deployedAPIIDs map[string]string) []schema.GitOpsAPIStatus {
+	var trafficSplitters []schema.GitOpsAPIStatus
+	var otherAPIs []schema.GitOpsAPIStatus
+	for _, prevAPI := range prevAPIs {
+		if prevAPI.Action == schema.GitOpsActionDelete && prevAPI.Error == "" {
+			continue
+		}
+		if apiNames.Has(prevAPI.Name) {
+			continue
+		}
+		if _, isDeployed := deployedAPIIDs[prevAPI.Name]; !isDeployed {
+			continue
+		}
+		if prevAPI.Kind == userconfig.TrafficSplitterKind {
+			trafficSplitters = append(trafficSplitters, prevAPI)
+		} else {
+			otherAPIs = append(otherAPIs, prevAPI)
+		}
+	}
+
+	apiStatuses := make([]schema.GitOpsAPIStatus, 0, len(trafficSplitters)+len(otherAPIs))
+	for _, prevAPI := range append(trafficSplitters, otherAPIs...) {
+		apiStatus := schema.GitOpsAPIStatus{
+			Name:   prevAPI.Name,
+			Kind:   prevAPI.Kind,
+			File:   prevAPI.File,
+			Action: schema.GitOpsActionDelete,
+		}
+		if _, err := DeleteAPI(prevAPI.Name, false, true); err != nil {
+			apiStatus.Error = errors.ErrorStr(err)
+		}
+		apiStatuses = append(apiStatuses, apiStatus)
+	}
+
+	return apiStatuses
+}
+
+// checks whether the most recent attempt succeeded, and (if deployedAPIIDs is not nil) that none of the apis have drifted since
+func isGitOpsInSync(status *schema.GitOpsStatus, deployedAPIIDs map[string]string) bool {
+	if status.Commit == "" || status.Error != "" {
+		return false
+	}
+
+	for _, api := range status.APIs {
+		if api.Error != "" {
+			return false
+		}
+		if deployedAPIIDs == nil || api.Action == schema.GitOpsActionDelete {
+			continue
+		}
+		if deployedID, isDeployed := deployedAPIIDs[api.Name]; !isDeployed || deployedID != api.ID {
+			return false
+		}
+	}
+
+	return true
+}
+
+// fetches the latest commit of the branch and checks it out, and returns its sha
+func fetchGitOpsRepo(gitOps *clusterconfig.GitOps) (string, error) {
+	authEnv, err := gitOpsAuthEnv(gitOps)
+	if err != nil {
+		return "", err
+	}
+
+	if !files.IsDir(filepath.Join(_gitOpsRepoDir, ".git")) {
+		if err := os.RemoveAll(_gitOpsRepoDir); err != nil {
+			return "", errors.WithStack(err)
+		}
+		if _, err := runGitOpsCommand(nil, "init", "--quiet", _gitOpsRepoDir); err != nil {
+			return "", err
+		}
+	}
+
+	remoteRef := "refs/remotes/origin/" + gitOps.Branch
+	if _, err := runGitOpsCommand(authEnv, "-C", _gitOpsRepoDir, "fetch", "--quiet", "--depth=1", "--no-tags", gitOps.Repository, "+refs/heads/"+gitOps.Branch+":"+remoteRef); err != nil {
+		return "", err
+	}
+
+	commit, err := runGitOpsCommand(nil, "-C", _gitOpsRepoDir, "rev-parse", remoteRef)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := runGitOpsCommand(nil, "-C", _gitOpsRepoDir, "checkout", "--quiet", "--force", "--detach", commit); err != nil {
+		return "", err
+	}
+
+	return commit, nil
+}
+
+// the credentials from secrets manager (if configured) are passed to git as an http header via the environment,
+// so that they aren't included in the command's arguments or output, or stored in the repository's config
+func gitOpsAuthEnv(gitOps *clusterconfig.GitOps) ([]string, error) {
+	if gitOps.SecretsManagerARN == nil {
+		return nil, nil
+	}
+
+	secret, err := config.AWS.GetSecretString(*gitOps.SecretsManagerARN)
+	if err != nil {
+		return nil, errors.Wrap(err, clusterconfig.GitOpsKey, userconfig.SecretsManagerARNKey)
+	}
+	secret = strings.TrimSpace(secret)
+
+	// the secret is either an access token, or a username and password separated by a colon
+	if !strings.Contains(secret, ":") {
+		secret = "x-access-token:" + secret
+	}
+
+	return []string{
+		"GIT_CONFIG_COUNT=1",
+		"GIT_CONFIG_KEY_0=http.extraHeader",
+		"GIT_CONFIG_VALUE_0=Authorization: Basic " + base64.StdEncoding.EncodeToString([]byte(secret)),
+	}, nil
+}
+
+func runGitOpsCommand(env []string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Env = append(append(os.Environ(), "GIT_TERMINAL_PROMPT=0"), env...)
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		message := strings.TrimSpace(string(output))
+		if message == "" {
+			message = err.Error()
+		}
+		return "", ErrorGitOpsCommandFailed(strings.Join(args, " "), message)
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+// reads all of the api configuration files in the gitops path (including subdirectories, but not hidden files or directories)
+func readGitOpsAPIConfigs(gitOps *clusterconfig.GitOps, commit string) ([]userconfig.API, error) {
+	configDir := filepath.Join(_gitOpsRepoDir, gitOps.Path)
+	if !files.IsDir(configDir) {
+		return nil, ErrorGitOpsNoConfigFiles(gitOps.Branch, gitOps.Path)
+	}
+
+	configPaths, err := files.ListDirRecursive(configDir, false, files.IgnoreHiddenFolders, files.IgnoreHiddenFiles, files.IgnoreNonYAML)
+	if err != nil {
+		return nil, err
+	}
+	if len(configPaths) == 0 {
+		return nil, ErrorGitOpsNoConfigFiles(gitOps.Branch, gitOps.Path)
+	}
+
+	var apiConfigs []userconfig.API
+	for _, configPath := range configPaths {
+		relPath := filepath.ToSlash(strings.TrimPrefix(configPath, _gitOpsRepoDir+"/"))
+
+		configBytes, err := files.ReadFileBytesErrPath(configPath, relPath)
+		if err != nil {
+			return nil, err
+		}
+
+		fileAPIConfigs, err := spec.ExtractAPIConfigs(configBytes, relPath)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range fileAPIConfigs {
+			fileAPIConfigs[i].GitSource = &userconfig.GitSource{
+				Repository: gitOps.Repository,
+				Path:       relPath,
+				Ref:        gitOps.Branch,
+				Commit:     commit,
+			}
+		}
+
+		apiConfigs = append(apiConfigs, fileAPIConfigs...)
+	}
+
+	return apiConfigs, nil
+}
+
+// must be called while holding _gitOpsStatusMutex
+func loadGitOpsStatus() error {
+	if _gitOpsStatus != nil {
+		return nil
+	}
+
+	status := schema.GitOpsStatus{}
+
+	exists, err := config.AWS.IsS3File(config.ClusterConfig.Bucket, gitOpsStatusKey())
+	if err != nil {
+		return err
+	}
+	if exists {
+		if err := config.AWS.ReadJSONFromS3(&status, config.ClusterConfig.Bucket, gitOpsStatusKey()); err != nil {
+			return err
+		}
+	}
+
+	// if the repository, branch, or path was changed, the new one is applied on the next run (the previously deployed apis are kept so that they can be pruned)
+	gitOps := config.ClusterConfig.GitOps
+	if status.Repository != gitOps.Repository || status.Branch != gitOps.Branch || status.Path != gitOps.Path {
+		status.Repository = gitOps.Repository
+		status.Branch = gitOps.Branch
+		status.Path = gitOps.Path
+		status.Commit = ""
+	}
+
+	if status.APIs == nil {
+		status.APIs = []schema.GitOpsAPIStatus{}
+	}
+
+	_gitOpsStatus = &status
+	return nil
+}
+
+// e.g. <cluster UID>/gitops/status.json
+func gitOpsStatusKey() string {
+	return filepath.Join(config.ClusterConfig.ClusterUID, "gitops", "status.json")
+}
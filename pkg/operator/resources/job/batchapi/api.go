@@ -44,7 +44,7 @@ import (
 const _batchDashboardUID = "batchapi"
 
 func UpdateAPI(apiConfig *userconfig.API) (*spec.API, string, error) {
-	prevVirtualService, err := config.K8s.GetVirtualService(workloads.K8sName(apiConfig.Name))
+	prevVirtualService, err := config.K8s.WithNamespace(apiConfig.Namespace()).GetVirtualService(workloads.K8sName(apiConfig.Name))
 	if err != nil {
 		return nil, "", err
 	}
@@ -68,7 +68,7 @@ func UpdateAPI(apiConfig *userconfig.API) (*spec.API, string, error) {
 		err = applyK8sResources(api, prevVirtualService)
 		if err != nil {
 			routines.RunWithPanicHandler(func() {
-				_ = deleteK8sResources(api.Name)
+				_ = deleteK8sResources(api.Name, api.Namespace())
 			})
 			return nil, "", err
 		}
@@ -92,11 +92,11 @@ func UpdateAPI(apiConfig *userconfig.API) (*spec.API, string, error) {
 	return api, fmt.Sprintf("%s is up to date", api.Resource.UserString()), nil
 }
 
-func DeleteAPI(apiName string, keepCache bool) error {
+func DeleteAPI(apiName string, namespace string, keepCache bool) error {
 	// best effort deletion, so don't handle error yet
 	err := parallel.RunFirstErr(
 		func() error {
-			return deleteK8sResources(apiName)
+			return deleteK8sResources(apiName, namespace)
 		},
 		func() error {
 			if keepCache {
@@ -114,6 +114,7 @@ func DeleteAPI(apiName string, keepCache bool) error {
 }
 
 func deleteS3Resources(apiName string) error {
+	_ = job.DeleteAllPendingDependenciesFilesByAPI(userconfig.BatchAPIKind, apiName)
 	return parallel.RunFirstErr(
 		func() error {
 			prefix := filepath.Join(config.ClusterConfig.ClusterUID, "apis", apiName)
@@ -182,6 +183,16 @@ func GetAllAPIs(virtualServices []*istioclientnetworking.VirtualService, batchJo
 		}
 	}
 
+	queuedJobKeys, err := job.ListAllPendingDependenciesJobKeys(userconfig.BatchAPIKind)
+	if err != nil {
+		return nil, err
+	}
+	for _, jobKey := range queuedJobKeys {
+		if batchAPI, ok := batchAPIsMap[jobKey.APIName]; ok {
+			batchAPI.QueuedJobCount++
+		}
+	}
+
 	batchAPIList := make([]schema.APIResponse, 0, len(batchAPIsMap))
 
 	for _, batchAPI := range batchAPIsMap {
@@ -206,7 +217,7 @@ func GetAPIByName(deployedResource *operator.DeployedResource) ([]schema.APIResp
 	batchJobList := batch.BatchJobList{}
 	if err = config.K8s.List(
 		ctx, &batchJobList,
-		client.InNamespace(config.K8s.Namespace),
+		client.InNamespace(deployedResource.Namespace()),
 		client.MatchingLabels{"apiName": deployedResource.Name},
 	); err != nil {
 		return nil, err
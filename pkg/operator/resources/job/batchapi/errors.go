@@ -20,11 +20,17 @@ import (
 	"fmt"
 
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
 )
 
 const (
 	ErrNoS3FilesFound            = "batchapi.no_s3_files_found"
 	ErrBatchItemSizeExceedsLimit = "batchapi.item_size_exceeds_limit"
+	ErrJobIsNotCompleted         = "batchapi.job_is_not_completed"
+	ErrNoFailedBatches           = "batchapi.no_failed_batches"
+	ErrJobHasNoOutputPath        = "batchapi.job_has_no_output_path"
+	ErrMaxWorkersRequiresAuto    = "batchapi.max_workers_requires_auto"
 )
 
 func ErrorNoS3FilesFound() error {
@@ -40,3 +46,31 @@ func ErrorItemSizeExceedsLimit(index int, size int, limit int) error {
 		Message: fmt.Sprintf("item %d has size %d bytes which exceeds the limit (%d bytes)", index, size, limit),
 	})
 }
+
+func ErrorJobIsNotCompleted(jobKey spec.JobKey) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrJobIsNotCompleted,
+		Message: fmt.Sprintf("job %s has not completed yet; its failed batches can only be re-run once it has completed", jobKey.UserString()),
+	})
+}
+
+func ErrorNoFailedBatches(jobKey spec.JobKey) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrNoFailedBatches,
+		Message: fmt.Sprintf("there are no failed batches to re-run for job %s", jobKey.UserString()),
+	})
+}
+
+func ErrorJobHasNoOutputPath(jobKey spec.JobKey) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrJobHasNoOutputPath,
+		Message: fmt.Sprintf("job %s was not submitted with an output_path, so its results can't be listed", jobKey.UserString()),
+	})
+}
+
+func ErrorMaxWorkersRequiresAuto() error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrMaxWorkersRequiresAuto,
+		Message: fmt.Sprintf("%s can only be specified when %s is \"%s\"", schema.MaxWorkersKey, schema.WorkersKey, schema.AutoWorkers),
+	})
+}
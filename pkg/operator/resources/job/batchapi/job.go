@@ -26,8 +26,11 @@ import (
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
 	"github.com/cortexlabs/cortex/pkg/lib/pointer"
 	"github.com/cortexlabs/cortex/pkg/operator/operator"
+	"github.com/cortexlabs/cortex/pkg/operator/resources/job"
+	"github.com/cortexlabs/cortex/pkg/operator/resources/quota"
 	"github.com/cortexlabs/cortex/pkg/operator/schema"
 	"github.com/cortexlabs/cortex/pkg/types/spec"
+	"github.com/cortexlabs/cortex/pkg/types/status"
 	"github.com/cortexlabs/cortex/pkg/types/userconfig"
 	"github.com/cortexlabs/cortex/pkg/workloads"
 	kmeta "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -59,19 +62,37 @@ func DryRun(submission *schema.BatchJobSubmission) ([]string, error) {
 		return s3Files, nil
 	}
 
+	if submission.FileManifest != nil {
+		s3Files, err := listManifestFilesDryRun(submission.FileManifest)
+		if err != nil {
+			return nil, errors.Wrap(err, schema.FileManifestKey)
+		}
+
+		return s3Files, nil
+	}
+
 	return nil, nil
 }
 
 func SubmitJob(apiName string, submission *schema.BatchJobSubmission) (*spec.BatchJob, error) {
-	err := validateJobSubmission(submission)
+	var err error
+	if len(submission.DependsOn) > 0 {
+		// the s3 inputs of a job with dependencies may be created by the jobs which it depends on
+		err = ValidateScheduledJobSubmission(submission)
+	} else {
+		err = validateJobSubmission(submission)
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	virtualService, err := config.K8s.GetVirtualService(workloads.K8sName(apiName))
+	virtualService, err := operator.GetAPIVirtualService(apiName)
 	if err != nil {
 		return nil, err
 	}
+	if virtualService == nil {
+		return nil, errors.ErrorUnexpected("unable to find virtual service", apiName)
+	}
 
 	apiID := virtualService.Labels["apiID"]
 	jobID := spec.MonotonicallyDecreasingID()
@@ -81,6 +102,19 @@ func SubmitJob(apiName string, submission *schema.BatchJobSubmission) (*spec.Bat
 		return nil, err
 	}
 
+	workers := int64(submission.Workers)
+	if submission.AutoWorkers && submission.MaxWorkers != nil {
+		workers = int64(*submission.MaxWorkers)
+	}
+	if err := quota.ValidateJobSubmission(apiSpec.API, workers); err != nil {
+		return nil, err
+	}
+
+	dependencies, err := job.ResolveDependencies(apiName, userconfig.BatchAPIKind, submission.DependsOn)
+	if err != nil {
+		return nil, errors.Wrap(err, schema.DependsOnKey)
+	}
+
 	jobSpec := spec.BatchJob{
 		RuntimeBatchJobConfig: submission.RuntimeBatchJobConfig,
 		JobKey: spec.JobKey{
@@ -88,8 +122,9 @@ func SubmitJob(apiName string, submission *schema.BatchJobSubmission) (*spec.Bat
 			ID:      jobID,
 			Kind:    userconfig.BatchAPIKind,
 		},
-		APIID:     apiSpec.ID,
-		StartTime: time.Now(),
+		APIID:        apiSpec.ID,
+		StartTime:    time.Now(),
+		Dependencies: dependencies,
 	}
 
 	err = uploadJobSpec(&jobSpec)
@@ -103,11 +138,62 @@ func SubmitJob(apiName string, submission *schema.BatchJobSubmission) (*spec.Bat
 		return nil, err
 	}
 
+	if len(dependencies) > 0 {
+		if err := job.SetPendingDependenciesStatus(jobSpec.JobKey); err != nil {
+			return nil, err
+		}
+		return &jobSpec, nil
+	}
+
+	started, err := job.StartIfBelowConcurrentJobLimit(userconfig.BatchAPIKind, apiName, apiSpec.MaxConcurrentJobs, func() error {
+		return createBatchJob(apiSpec, &jobSpec, submission)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !started {
+		if err := job.SetQueuedStatus(jobSpec.JobKey); err != nil {
+			return nil, err
+		}
+	}
+
+	return &jobSpec, nil
+}
+
+// StartPendingJob starts a job which was waiting for its dependencies to succeed or was queued, unless its api is running
+// maxConcurrentJobs jobs; it returns whether the job was started
+func StartPendingJob(jobKey spec.JobKey, maxConcurrentJobs *int64) (bool, error) {
+	return job.StartIfBelowConcurrentJobLimit(jobKey.Kind, jobKey.APIName, maxConcurrentJobs, func() error {
+		jobSpec, err := operator.DownloadBatchJobSpec(jobKey)
+		if err != nil {
+			return err
+		}
+
+		apiSpec, err := operator.DownloadAPISpec(jobSpec.APIName, jobSpec.APIID)
+		if err != nil {
+			return err
+		}
+
+		submission := schema.BatchJobSubmission{}
+		payloadKey := spec.JobPayloadKey(config.ClusterConfig.ClusterUID, userconfig.BatchAPIKind, jobKey.APIName, jobKey.ID)
+		if err := config.AWS.ReadJSONFromS3(&submission, config.ClusterConfig.Bucket, payloadKey); err != nil {
+			return err
+		}
+
+		if err := createBatchJob(apiSpec, jobSpec, &submission); err != nil {
+			return err
+		}
+
+		return job.DeletePendingDependenciesFile(jobKey)
+	})
+}
+
+func createBatchJob(apiSpec *spec.API, jobSpec *spec.BatchJob, submission *schema.BatchJobSubmission) error {
 	var jobConfig *string
 	if submission.Config != nil {
 		jobConfigBytes, err := yaml.Marshal(submission.Config)
 		if err != nil {
-			return nil, err
+			return err
 		}
 		jobConfig = pointer.String(string(jobConfigBytes))
 	}
@@ -117,6 +203,16 @@ func SubmitJob(apiName string, submission *schema.BatchJobSubmission) (*spec.Bat
 		timeout = &kmeta.Duration{Duration: time.Duration(*submission.Timeout) * time.Second}
 	}
 
+	var maxRetries *int32
+	if submission.MaxRetries != nil {
+		maxRetries = pointer.Int32(int32(*submission.MaxRetries))
+	}
+
+	var maxWorkers *int32
+	if submission.MaxWorkers != nil {
+		maxWorkers = pointer.Int32(int32(*submission.MaxWorkers))
+	}
+
 	var deadLetterQueue *batch.DeadLetterQueueSpec
 	if submission.SQSDeadLetterQueue != nil {
 		deadLetterQueue = &batch.DeadLetterQueueSpec{
@@ -127,22 +223,26 @@ func SubmitJob(apiName string, submission *schema.BatchJobSubmission) (*spec.Bat
 
 	batchJob := batch.BatchJob{
 		ObjectMeta: kmeta.ObjectMeta{
-			Name:      jobID,
-			Namespace: config.K8s.Namespace,
+			Name:      jobSpec.ID,
+			Namespace: apiSpec.Namespace(),
 			Labels: map[string]string{
-				"apiName":        apiName,
-				"apiID":          apiID,
-				"specID":         virtualService.Labels["specID"],
+				"apiName":        jobSpec.APIName,
+				"apiID":          apiSpec.ID,
+				"specID":         apiSpec.SpecID,
 				"apiKind":        userconfig.BatchAPIKind.String(),
 				"cortex.dev/api": "true",
 			},
 		},
 		Spec: batch.BatchJobSpec{
-			APIName:         apiName,
-			APIID:           apiID,
+			APIName:         jobSpec.APIName,
+			APIID:           apiSpec.ID,
 			Workers:         int32(submission.Workers),
+			AutoWorkers:     submission.AutoWorkers,
+			MaxWorkers:      maxWorkers,
 			Config:          jobConfig,
 			Timeout:         timeout,
+			MaxRetries:      maxRetries,
+			OutputPath:      submission.OutputPath,
 			DeadLetterQueue: deadLetterQueue,
 			TTL:             &kmeta.Duration{Duration: _batchJobTTL},
 			NodeGroups:      apiSpec.NodeGroups,
@@ -151,16 +251,22 @@ func SubmitJob(apiName string, submission *schema.BatchJobSubmission) (*spec.Bat
 	}
 
 	ctx := context.Background()
-	if err = config.K8s.Create(ctx, &batchJob); err != nil {
-		return nil, err
-	}
-
-	return &jobSpec, nil
+	return config.K8s.Create(ctx, &batchJob)
 }
 
 func StopJob(jobKey spec.JobKey) error {
+	jobState, err := job.GetJobState(jobKey)
+	if err == nil && (jobState.Status == status.JobPendingDependencies || jobState.Status == status.JobQueued) {
+		return job.SetNotStartedJobStoppedStatus(jobKey)
+	}
+
+	namespace, err := job.APINamespace(jobKey.APIName)
+	if err != nil {
+		return err
+	}
+
 	return config.K8s.Delete(context.Background(), &batch.BatchJob{
-		ObjectMeta: kmeta.ObjectMeta{Name: jobKey.ID, Namespace: config.K8s.Namespace},
+		ObjectMeta: kmeta.ObjectMeta{Name: jobKey.ID, Namespace: namespace},
 	})
 }
 
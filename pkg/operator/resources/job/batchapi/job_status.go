@@ -19,6 +19,9 @@ package batchapi
 import (
 	"context"
 	"net/url"
+	"path"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/PEAT-AI/yaml"
@@ -39,10 +42,15 @@ import (
 )
 
 func GetJob(jobKey spec.JobKey) (*schema.BatchJobResponse, error) {
+	namespace, err := job.APINamespace(jobKey.APIName)
+	if err != nil {
+		return nil, err
+	}
+
 	ctx := context.Background()
 	var batchJob batch.BatchJob
 
-	err := config.K8s.Get(ctx, client.ObjectKey{Name: jobKey.ID, Namespace: config.K8s.Namespace}, &batchJob)
+	err = config.K8s.Get(ctx, client.ObjectKey{Name: jobKey.ID, Namespace: namespace}, &batchJob)
 	if err != nil && !kerrors.IsNotFound(err) {
 		return nil, err
 	}
@@ -81,6 +89,11 @@ func getJobFromS3(jobKey spec.JobKey) (*schema.BatchJobResponse, error) {
 		}
 	}
 
+	jobStatus.BatchProgress, err = getBatchProgress(jobStatus)
+	if err != nil {
+		telemetry.Error(err)
+	}
+
 	apiSpec, err := operator.DownloadAPISpec(jobStatus.APIName, jobStatus.APIID)
 	if err != nil {
 		return nil, err
@@ -110,6 +123,11 @@ func getJobFromCluster(batchJob batch.BatchJob) (*schema.BatchJobResponse, error
 		telemetry.Error(err)
 	}
 
+	jobStatus.BatchProgress, err = getBatchProgress(jobStatus)
+	if err != nil {
+		telemetry.Error(err)
+	}
+
 	apiSpec, err := operator.DownloadAPISpec(jobStatus.APIName, jobStatus.APIID)
 	if err != nil {
 		return nil, err
@@ -155,14 +173,33 @@ func getJobStatusFromBatchJob(batchJob batch.BatchJob) (*status.BatchJobStatus,
 		timeout = pointer.Int(int(batchJob.Spec.Timeout.Seconds()))
 	}
 
+	var maxRetries *int
+	if batchJob.Spec.MaxRetries != nil {
+		maxRetries = pointer.Int(int(*batchJob.Spec.MaxRetries))
+	}
+
+	workers := int(batchJob.Spec.Workers)
+	if batchJob.Spec.AutoWorkers {
+		workers = int(batchJob.Status.Workers)
+	}
+
+	var maxWorkers *int
+	if batchJob.Spec.MaxWorkers != nil {
+		maxWorkers = pointer.Int(int(*batchJob.Spec.MaxWorkers))
+	}
+
 	jobStatus := status.BatchJobStatus{
 		BatchJob: spec.BatchJob{
 			JobKey: jobKey,
 			RuntimeBatchJobConfig: spec.RuntimeBatchJobConfig{
-				Workers:            int(batchJob.Spec.Workers),
+				Workers:            workers,
+				AutoWorkers:        batchJob.Spec.AutoWorkers,
+				MaxWorkers:         maxWorkers,
 				SQSDeadLetterQueue: deadLetterQueue,
 				Config:             jobConfig,
 				Timeout:            timeout,
+				MaxRetries:         maxRetries,
+				OutputPath:         batchJob.Spec.OutputPath,
 			},
 			APIID:           batchJob.Spec.APIID,
 			StartTime:       batchJob.CreationTimestamp.Time,
@@ -213,6 +250,48 @@ func getJobStatusFromJobState(jobState *job.State) (*status.BatchJobStatus, erro
 	return &jobStatus, nil
 }
 
+// getBatchProgress reads the state of each batch, which is reported by the dequeuer
+func getBatchProgress(jobStatus *status.BatchJobStatus) (*status.BatchProgress, error) {
+	if jobStatus.TotalBatchCount <= 0 {
+		return nil, nil
+	}
+
+	prefix := spec.JobBatchStatesPrefix(config.ClusterConfig.ClusterUID, userconfig.BatchAPIKind, jobStatus.APIName, jobStatus.ID)
+	objects, err := config.AWS.ListS3Prefix(config.ClusterConfig.Bucket, prefix, false, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	batchStates := make([]status.BatchCode, jobStatus.TotalBatchCount)
+	for _, object := range objects {
+		split := strings.SplitN(path.Base(*object.Key), ".", 2)
+		if len(split) != 2 {
+			continue
+		}
+		batchIndex, err := strconv.Atoi(split[0])
+		if err != nil || batchIndex < 0 || batchIndex >= len(batchStates) {
+			continue
+		}
+		batchState := status.BatchCodeFromString(split[1])
+		if batchStates[batchIndex] == status.BatchSucceeded {
+			continue // a batch which succeeded on a retry may still have a stale failed state
+		}
+		batchStates[batchIndex] = batchState
+	}
+
+	completedBatches := 0
+	for _, batchState := range batchStates {
+		if batchState.IsCompleted() {
+			completedBatches++
+		}
+	}
+
+	return &status.BatchProgress{
+		CompletionPercentage: float64(completedBatches) * 100 / float64(len(batchStates)),
+		BatchStates:          batchStates,
+	}, nil
+}
+
 func readMetricsFromS3(jobKey spec.JobKey) (*metrics.BatchMetrics, error) {
 	s3Key := spec.JobMetricsKey(config.ClusterConfig.ClusterUID, userconfig.BatchAPIKind, jobKey.APIName, jobKey.ID)
 	batchMetrics := metrics.BatchMetrics{}
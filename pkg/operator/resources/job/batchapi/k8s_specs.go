@@ -24,6 +24,7 @@ import (
 	"github.com/cortexlabs/cortex/pkg/consts"
 	batch "github.com/cortexlabs/cortex/pkg/crds/apis/batch/v1alpha1"
 	"github.com/cortexlabs/cortex/pkg/lib/k8s"
+	"github.com/cortexlabs/cortex/pkg/lib/maps"
 	"github.com/cortexlabs/cortex/pkg/lib/parallel"
 	"github.com/cortexlabs/cortex/pkg/lib/pointer"
 	s "github.com/cortexlabs/cortex/pkg/lib/strings"
@@ -38,7 +39,7 @@ const _operatorService = "operator"
 func virtualServiceSpec(api *spec.API) *istioclientnetworking.VirtualService {
 	return k8s.VirtualService(&k8s.VirtualServiceSpec{
 		Name:     workloads.K8sName(api.Name),
-		Gateways: []string{"apis-gateway"},
+		Gateways: []string{workloads.APIGatewayRef(api.Networking)},
 		Destinations: []k8s.Destination{{
 			ServiceName: _operatorService,
 			Weight:      100,
@@ -47,7 +48,7 @@ func virtualServiceSpec(api *spec.API) *istioclientnetworking.VirtualService {
 		PrefixPath:  api.Networking.Endpoint,
 		Rewrite:     pointer.String(path.Join("batch", api.Name)),
 		Annotations: api.ToK8sAnnotations(),
-		Labels: map[string]string{
+		Labels: maps.MergeStrMapsString(api.Labels, map[string]string{
 			"apiName":               api.Name,
 			"apiID":                 api.ID,
 			"specID":                api.SpecID,
@@ -55,35 +56,37 @@ func virtualServiceSpec(api *spec.API) *istioclientnetworking.VirtualService {
 			"initialDeploymentTime": s.Int64(api.InitialDeploymentTime),
 			"apiKind":               api.Kind.String(),
 			"cortex.dev/api":        "true",
-		},
+			"cortex.dev/project":    api.Project,
+		}),
 	})
 }
 
 func applyK8sResources(api *spec.API, prevVirtualService *istioclientnetworking.VirtualService) error {
+	k8sClient := config.K8s.WithNamespace(api.Namespace())
 	newVirtualService := virtualServiceSpec(api)
 
 	if prevVirtualService == nil {
-		_, err := config.K8s.CreateVirtualService(newVirtualService)
+		_, err := k8sClient.CreateVirtualService(newVirtualService)
 		return err
 	}
 
-	_, err := config.K8s.UpdateVirtualService(prevVirtualService, newVirtualService)
+	_, err := k8sClient.UpdateVirtualService(prevVirtualService, newVirtualService)
 	return err
 }
 
-func deleteK8sResources(apiName string) error {
+func deleteK8sResources(apiName string, namespace string) error {
 	return parallel.RunFirstErr(
 		func() error {
 			err := config.K8s.DeleteAllOf(
 				context.Background(),
 				&batch.BatchJob{},
-				client.InNamespace(config.K8s.Namespace),
+				client.InNamespace(namespace),
 				client.MatchingLabels{"apiName": apiName},
 			)
 			return client.IgnoreNotFound(err)
 		},
 		func() error {
-			_, err := config.K8s.DeleteVirtualService(workloads.K8sName(apiName))
+			_, err := config.K8s.WithNamespace(namespace).DeleteVirtualService(workloads.K8sName(apiName))
 			return err
 		},
 	)
@@ -0,0 +1,88 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package batchapi
+
+import (
+	"encoding/json"
+
+	"github.com/cortexlabs/cortex/pkg/config"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
+)
+
+// RerunFailedBatches submits a new job which only processes the batches that failed in a previous job (using the same job configuration)
+func RerunFailedBatches(jobKey spec.JobKey) (*spec.BatchJob, error) {
+	jobResponse, err := GetJob(jobKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if !jobResponse.JobStatus.Status.IsCompleted() {
+		return nil, ErrorJobIsNotCompleted(jobKey)
+	}
+
+	items, batchSize, err := getFailedBatchItems(jobKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(items) == 0 {
+		return nil, ErrorNoFailedBatches(jobKey)
+	}
+
+	submission := &schema.BatchJobSubmission{
+		RuntimeBatchJobConfig: jobResponse.JobStatus.RuntimeBatchJobConfig,
+		ItemList: &schema.ItemList{
+			Items:     items,
+			BatchSize: batchSize,
+		},
+	}
+
+	return SubmitJob(jobKey.APIName, submission)
+}
+
+// getFailedBatchItems returns the items of all of the job's failed batches, and the size of the largest failed batch (which is used as the batch size when re-running)
+func getFailedBatchItems(jobKey spec.JobKey) ([]json.RawMessage, int, error) {
+	prefix := spec.JobFailedBatchesPrefix(config.ClusterConfig.ClusterUID, jobKey.Kind, jobKey.APIName, jobKey.ID)
+
+	objects, err := config.AWS.ListS3Prefix(config.ClusterConfig.Bucket, prefix, false, nil, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var items []json.RawMessage
+	batchSize := 1
+	for _, object := range objects {
+		batchBytes, err := config.AWS.ReadBytesFromS3(config.ClusterConfig.Bucket, *object.Key)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		var batch []json.RawMessage
+		if err := json.Unmarshal(batchBytes, &batch); err != nil {
+			return nil, 0, errors.Wrap(errors.WithStack(err), *object.Key)
+		}
+
+		items = append(items, batch...)
+		if len(batch) > batchSize {
+			batchSize = len(batch)
+		}
+	}
+
+	return items, batchSize, nil
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package batchapi
+
+import (
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/cortexlabs/cortex/pkg/config"
+	awslib "github.com/cortexlabs/cortex/pkg/lib/aws"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
+)
+
+// GetJobResults lists the objects which the job wrote under its output prefix
+func GetJobResults(jobKey spec.JobKey) (*schema.BatchJobResultsResponse, error) {
+	jobResponse, err := GetJob(jobKey)
+	if err != nil {
+		return nil, err
+	}
+
+	outputPrefix := jobResponse.JobStatus.OutputPrefix()
+	if outputPrefix == "" {
+		return nil, ErrorJobHasNoOutputPath(jobKey)
+	}
+
+	bucket, prefix, err := awslib.SplitS3Path(outputPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	objects, err := config.AWS.ListS3Prefix(bucket, prefix, false, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]schema.BatchJobResult, 0, len(objects))
+	for _, object := range objects {
+		results = append(results, schema.BatchJobResult{
+			Key:          strings.TrimPrefix(*object.Key, prefix),
+			Size:         aws.Int64Value(object.Size),
+			LastModified: aws.TimeValue(object.LastModified),
+		})
+	}
+
+	return &schema.BatchJobResultsResponse{
+		OutputPrefix: outputPrefix,
+		Objects:      results,
+	}, nil
+}
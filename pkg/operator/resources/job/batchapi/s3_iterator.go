@@ -102,3 +102,21 @@ func s3IteratorFromLister(s3Lister schema.S3Lister, fn func(string, *s3.Object)
 
 	return numResults, nil
 }
+
+func s3IteratorFromManifest(manifestS3Path string, fn func(bucket string, key string) (bool, error)) (int64, error) {
+	awsClientForBucket, err := aws.NewFromClientS3Path(manifestS3Path, config.AWS)
+	if err != nil {
+		return 0, err
+	}
+
+	var numResults int64
+	err = awsClientForBucket.S3ManifestIterator(manifestS3Path, func(bucket string, key string) (bool, error) {
+		numResults++
+		return fn(bucket, key)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return numResults, nil
+}
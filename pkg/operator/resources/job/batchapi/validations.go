@@ -45,9 +45,12 @@ func validateJobSubmissionSchema(submission *schema.BatchJobSubmission) error {
 	if submission.DelimitedFiles != nil {
 		providedKeys = append(providedKeys, schema.DelimitedFilesKey)
 	}
+	if submission.FileManifest != nil {
+		providedKeys = append(providedKeys, schema.FileManifestKey)
+	}
 
 	if len(providedKeys) == 0 {
-		return job.ErrorSpecifyExactlyOneKey(schema.ItemListKey, schema.FilePathListerKey, schema.DelimitedFilesKey)
+		return job.ErrorSpecifyExactlyOneKey(schema.ItemListKey, schema.FilePathListerKey, schema.DelimitedFilesKey, schema.FileManifestKey)
 	}
 
 	if len(providedKeys) > 1 {
@@ -82,14 +85,41 @@ func validateJobSubmissionSchema(submission *schema.BatchJobSubmission) error {
 		}
 	}
 
-	if submission.Workers <= 0 {
-		return errors.Wrap(cr.ErrorMustBeGreaterThanOrEqualTo(submission.Workers, 1), schema.WorkersKey)
+	if submission.FileManifest != nil {
+		if submission.FileManifest.BatchSize < 1 {
+			return errors.Wrap(cr.ErrorMustBeGreaterThanOrEqualTo(submission.FileManifest.BatchSize, 1), schema.FileManifestKey, schema.BatchSizeKey)
+		}
+	}
+
+	if submission.AutoWorkers {
+		if submission.MaxWorkers != nil && *submission.MaxWorkers <= 0 {
+			return errors.Wrap(cr.ErrorMustBeGreaterThanOrEqualTo(*submission.MaxWorkers, 1), schema.MaxWorkersKey)
+		}
+	} else {
+		if submission.Workers <= 0 {
+			return errors.Wrap(cr.ErrorMustBeGreaterThanOrEqualTo(submission.Workers, 1), schema.WorkersKey)
+		}
+		if submission.MaxWorkers != nil {
+			return ErrorMaxWorkersRequiresAuto()
+		}
 	}
 
 	if submission.Timeout != nil && *submission.Timeout <= 0 {
 		return errors.Wrap(cr.ErrorMustBeGreaterThanOrEqualTo(submission.Timeout, 1), schema.TimeoutKey)
 	}
 
+	if submission.MaxRetries != nil && *submission.MaxRetries < 0 {
+		return errors.Wrap(cr.ErrorMustBeGreaterThanOrEqualTo(*submission.MaxRetries, 0), schema.MaxRetriesKey)
+	}
+
+	if submission.OutputPath != "" && !awslib.IsValidS3Path(submission.OutputPath) {
+		return errors.Wrap(awslib.ErrorInvalidS3Path(submission.OutputPath), schema.OutputPathKey)
+	}
+
+	if submission.MaxRetries != nil && submission.SQSDeadLetterQueue != nil {
+		return job.ErrorConflictingFields(schema.SQSDeadLetterQueueKey, schema.MaxRetriesKey)
+	}
+
 	if submission.SQSDeadLetterQueue != nil {
 		if len(submission.SQSDeadLetterQueue.ARN) == 0 {
 			return errors.Wrap(cr.ErrorCannotBeEmpty(), schema.SQSDeadLetterQueueKey, schema.ARNKey)
@@ -122,6 +152,42 @@ func validateJobSubmission(submission *schema.BatchJobSubmission) error {
 		}
 	}
 
+	if submission.FileManifest != nil {
+		err := validateFileManifest(submission.FileManifest)
+		if err != nil {
+			return errors.Wrap(err, schema.FileManifestKey)
+		}
+	}
+
+	return nil
+}
+
+// ValidateScheduledJobSubmission validates a job submission which will be submitted on a schedule;
+// the s3 inputs are validated when each job is submitted, since they may not exist yet
+func ValidateScheduledJobSubmission(submission *schema.BatchJobSubmission) error {
+	err := validateJobSubmissionSchema(submission)
+	if err != nil {
+		return errors.Append(err, fmt.Sprintf("\n\njob submission schema can be found at https://docs.cortexlabs.com/v/%s/", consts.CortexVersionMinor))
+	}
+	return nil
+}
+
+func validateFileManifest(fileManifest *schema.FileManifest) error {
+	if !awslib.IsValidS3Path(fileManifest.S3Path) {
+		return errors.Wrap(awslib.ErrorInvalidS3Path(fileManifest.S3Path), schema.S3PathKey)
+	}
+
+	numResults, err := s3IteratorFromManifest(fileManifest.S3Path, func(bucket string, key string) (bool, error) {
+		return false, nil
+	})
+	if err != nil {
+		return errors.Wrap(err, schema.S3PathKey)
+	}
+
+	if numResults == 0 {
+		return ErrorNoS3FilesFound()
+	}
+
 	return nil
 }
 
@@ -170,6 +236,23 @@ func validateS3Lister(s3Lister *schema.S3Lister) error {
 	return nil
 }
 
+func listManifestFilesDryRun(fileManifest *schema.FileManifest) ([]string, error) {
+	var s3Files []string
+	_, err := s3IteratorFromManifest(fileManifest.S3Path, func(bucket string, key string) (bool, error) {
+		s3Files = append(s3Files, awslib.S3Path(bucket, key))
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(s3Files) == 0 {
+		return nil, ErrorNoS3FilesFound()
+	}
+
+	return s3Files, nil
+}
+
 func listFilesDryRun(s3Lister *schema.S3Lister) ([]string, error) {
 	var s3Files []string
 	for _, s3Path := range s3Lister.S3Paths {
@@ -49,6 +49,37 @@ func DeleteAllInProgressFilesByAPI(kind userconfig.Kind, apiName string) error {
 	return nil
 }
 
+// ListAllPendingDependenciesJobKeys returns the keys of the jobs which are waiting for their dependencies to succeed, in queue order
+func ListAllPendingDependenciesJobKeys(kind userconfig.Kind) ([]spec.JobKey, error) {
+	_, ok := _jobKinds[kind]
+	if !ok {
+		return nil, ErrorInvalidJobKind(kind)
+	}
+
+	s3Objects, err := config.AWS.ListS3Dir(config.ClusterConfig.Bucket, allPendingDependenciesKey(kind), false, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return queuedJobKeysFromObjects(s3Objects), nil
+}
+
+func DeletePendingDependenciesFile(jobKey spec.JobKey) error {
+	err := config.AWS.DeleteS3File(config.ClusterConfig.Bucket, pendingDependenciesKey(jobKey))
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func DeleteAllPendingDependenciesFilesByAPI(kind userconfig.Kind, apiName string) error {
+	err := config.AWS.DeleteS3Prefix(config.ClusterConfig.Bucket, path.Join(allPendingDependenciesKey(kind), apiName), true)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
 func listAllInProgressJobKeysByAPI(kind userconfig.Kind, apiName *string) ([]spec.JobKey, error) {
 	_, ok := _jobKinds[kind]
 	if !ok {
@@ -84,6 +115,26 @@ func uploadInProgressFile(jobKey spec.JobKey) error {
 	return nil
 }
 
+func uploadPendingDependenciesFile(jobKey spec.JobKey) error {
+	err := config.AWS.UploadStringToS3("", config.ClusterConfig.Bucket, pendingDependenciesKey(jobKey))
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// e.g. <cluster_uid>/jobs/<job_api_kind>/pending_dependencies
+func allPendingDependenciesKey(kind userconfig.Kind) string {
+	return path.Join(
+		config.ClusterConfig.ClusterUID, _jobsPrefix, kind.String(), _pendingDependenciesFilePrefix,
+	)
+}
+
+// e.g. <cluster_uid>/jobs/<job_api_kind>/pending_dependencies/<api_name>/<job_id>
+func pendingDependenciesKey(jobKey spec.JobKey) string {
+	return path.Join(allPendingDependenciesKey(jobKey.Kind), jobKey.APIName, jobKey.ID)
+}
+
 // e.g. <cluster_uid>/jobs/<job_api_kind>/in_progress
 func allInProgressKey(kind userconfig.Kind) string {
 	return path.Join(
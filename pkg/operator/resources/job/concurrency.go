@@ -0,0 +1,77 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"context"
+	"sync"
+
+	"github.com/cortexlabs/cortex/pkg/config"
+	batch "github.com/cortexlabs/cortex/pkg/crds/apis/batch/v1alpha1"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// serializes counting an api's running jobs with starting a job, so that concurrent submissions can't exceed max_concurrent_jobs
+var _concurrentJobsMutex sync.Mutex
+
+// ListRunningJobKeysByAPI returns the keys of the api's jobs which have been started and have not yet completed
+func ListRunningJobKeysByAPI(kind userconfig.Kind, apiName string) ([]spec.JobKey, error) {
+	switch kind {
+	case userconfig.BatchAPIKind:
+		var batchJobList batch.BatchJobList
+		err := config.K8s.List(context.Background(), &batchJobList, client.MatchingLabels{"apiName": apiName})
+		if err != nil {
+			return nil, err
+		}
+
+		var jobKeys []spec.JobKey
+		for _, batchJob := range batchJobList.Items {
+			if batchJob.Status.Status.IsCompleted() {
+				continue
+			}
+			jobKeys = append(jobKeys, spec.JobKey{APIName: apiName, ID: batchJob.Name, Kind: kind})
+		}
+		return jobKeys, nil
+	case userconfig.TaskAPIKind:
+		return ListAllInProgressJobKeysByAPI(kind, apiName)
+	}
+
+	return nil, ErrorInvalidJobKind(kind)
+}
+
+// StartIfBelowConcurrentJobLimit calls startJob if the api is running fewer than maxConcurrentJobs jobs (or if maxConcurrentJobs is nil),
+// and returns whether the job was started
+func StartIfBelowConcurrentJobLimit(kind userconfig.Kind, apiName string, maxConcurrentJobs *int64, startJob func() error) (bool, error) {
+	if maxConcurrentJobs == nil {
+		return true, startJob()
+	}
+
+	_concurrentJobsMutex.Lock()
+	defer _concurrentJobsMutex.Unlock()
+
+	runningJobKeys, err := ListRunningJobKeysByAPI(kind, apiName)
+	if err != nil {
+		return false, err
+	}
+	if int64(len(runningJobKeys)) >= *maxConcurrentJobs {
+		return false, nil
+	}
+
+	return true, startJob()
+}
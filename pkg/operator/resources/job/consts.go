@@ -19,9 +19,10 @@ package job
 import "github.com/cortexlabs/cortex/pkg/types/userconfig"
 
 const (
-	_jobsPrefix            = "jobs"
-	_inProgressFilePrefix  = "in_progress"
-	_enqueuingLivenessFile = "enqueuing_liveness"
+	_jobsPrefix                    = "jobs"
+	_inProgressFilePrefix          = "in_progress"
+	_pendingDependenciesFilePrefix = "pending_dependencies"
+	_enqueuingLivenessFile         = "enqueuing_liveness"
 )
 
 var _jobKinds = map[userconfig.Kind]bool{
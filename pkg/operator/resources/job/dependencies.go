@@ -0,0 +1,117 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"strings"
+
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/operator/operator"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
+	"github.com/cortexlabs/cortex/pkg/types/status"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+)
+
+// ResolveDependencies converts the depends_on field of a job submission into job keys;
+// each dependency is either the ID of a job of the same api, or <api_name>/<job_id> for a job of another batch or task api
+func ResolveDependencies(apiName string, kind userconfig.Kind, dependsOn []string) ([]spec.JobKey, error) {
+	var jobKeys []spec.JobKey
+	seen := map[spec.JobKey]bool{}
+
+	for _, dependency := range dependsOn {
+		jobKey, err := resolveDependency(apiName, kind, dependency)
+		if err != nil {
+			return nil, err
+		}
+		if seen[jobKey] {
+			continue
+		}
+		seen[jobKey] = true
+
+		jobState, err := GetJobState(jobKey)
+		if err != nil {
+			return nil, errors.Wrap(err, dependency)
+		}
+		if jobState.Status.IsCompleted() && jobState.Status != status.JobSucceeded {
+			return nil, ErrorDependencyFailed(jobKey, jobState.Status)
+		}
+
+		jobKeys = append(jobKeys, jobKey)
+	}
+
+	return jobKeys, nil
+}
+
+func resolveDependency(apiName string, kind userconfig.Kind, dependency string) (spec.JobKey, error) {
+	if !strings.Contains(dependency, "/") {
+		if dependency == "" {
+			return spec.JobKey{}, ErrorInvalidDependency(dependency)
+		}
+		return spec.JobKey{APIName: apiName, ID: dependency, Kind: kind}, nil
+	}
+
+	split := strings.Split(dependency, "/")
+	if len(split) != 2 || split[0] == "" || split[1] == "" {
+		return spec.JobKey{}, ErrorInvalidDependency(dependency)
+	}
+	dependencyAPIName, dependencyJobID := split[0], split[1]
+
+	if dependencyAPIName == apiName {
+		return spec.JobKey{APIName: apiName, ID: dependencyJobID, Kind: kind}, nil
+	}
+
+	virtualService, err := operator.GetAPIVirtualService(dependencyAPIName)
+	if err != nil {
+		return spec.JobKey{}, err
+	}
+	if virtualService == nil {
+		return spec.JobKey{}, ErrorDependencyAPINotFound(dependencyAPIName)
+	}
+
+	dependencyKind := userconfig.KindFromString(virtualService.Labels["apiKind"])
+	if !_jobKinds[dependencyKind] {
+		return spec.JobKey{}, ErrorDependencyAPINotFound(dependencyAPIName)
+	}
+
+	return spec.JobKey{APIName: dependencyAPIName, ID: dependencyJobID, Kind: dependencyKind}, nil
+}
+
+// CheckDependencies returns whether all of the dependencies have succeeded;
+// if any dependency has completed without succeeding (or no longer exists), ErrorDependencyFailed is returned
+func CheckDependencies(dependencies []spec.JobKey) (bool, error) {
+	allSucceeded := true
+
+	for _, dependency := range dependencies {
+		jobState, err := GetJobState(dependency)
+		if err != nil {
+			if errors.GetKind(err) == ErrJobNotFound {
+				return false, ErrorDependencyFailed(dependency, status.JobUnknown)
+			}
+			return false, err
+		}
+
+		if jobState.Status == status.JobSucceeded {
+			continue
+		}
+		if jobState.Status.IsCompleted() {
+			return false, ErrorDependencyFailed(dependency, jobState.Status)
+		}
+		allSucceeded = false
+	}
+
+	return allSucceeded, nil
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
 	s "github.com/cortexlabs/cortex/pkg/lib/strings"
 	"github.com/cortexlabs/cortex/pkg/types/spec"
+	"github.com/cortexlabs/cortex/pkg/types/status"
 	"github.com/cortexlabs/cortex/pkg/types/userconfig"
 )
 
@@ -32,6 +33,11 @@ const (
 	ErrJobHasAlreadyBeenStopped = "job.job_has_already_been_stopped"
 	ErrConflictingFields        = "job.conflicting_fields"
 	ErrSpecifyExactlyOneKey     = "job.specify_exactly_one_key"
+	ErrInvalidDependency        = "job.invalid_dependency"
+	ErrDependencyAPINotFound    = "job.dependency_api_not_found"
+	ErrDependencyFailed         = "job.dependency_failed"
+	ErrInvalidParamName         = "job.invalid_param_name"
+	ErrJobIsNotQueued           = "job.job_is_not_queued"
 )
 
 func ErrorInvalidJobKind(kind userconfig.Kind) error {
@@ -78,3 +84,38 @@ func ErrorSpecifyExactlyOneKey(key string, keys ...string) error {
 		Message: fmt.Sprintf("specify exactly one of the following keys: %s", s.StrsOr(allKeys)),
 	})
 }
+
+func ErrorInvalidDependency(dependency string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrInvalidDependency,
+		Message: fmt.Sprintf("invalid dependency \"%s\"; specify either the id of a job of the same api, or <api_name>/<job_id> for a job of another batch or task api", dependency),
+	})
+}
+
+func ErrorDependencyAPINotFound(apiName string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrDependencyAPINotFound,
+		Message: fmt.Sprintf("%s is not a deployed %s or %s", apiName, userconfig.BatchAPIKind.String(), userconfig.TaskAPIKind.String()),
+	})
+}
+
+func ErrorDependencyFailed(jobKey spec.JobKey, jobStatus status.JobCode) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrDependencyFailed,
+		Message: fmt.Sprintf("dependency %s did not succeed (status: %s)", jobKey.UserString(), jobStatus.Message()),
+	})
+}
+
+func ErrorJobIsNotQueued(jobKey spec.JobKey) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrJobIsNotQueued,
+		Message: fmt.Sprintf("job %s is not queued; only jobs which have been submitted but have not started yet can be moved within the queue", jobKey.UserString()),
+	})
+}
+
+func ErrorInvalidParamName(name string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrInvalidParamName,
+		Message: fmt.Sprintf("\"%s\" is not a valid param name; param names are exposed as environment variables, so they may only contain letters, numbers, and underscores, and must not start with a number", name),
+	})
+}
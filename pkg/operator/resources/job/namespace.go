@@ -0,0 +1,35 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"github.com/cortexlabs/cortex/pkg/consts"
+	"github.com/cortexlabs/cortex/pkg/operator/operator"
+)
+
+// APINamespace returns the namespace of the api's project, which is where the kubernetes resources of the api's jobs are;
+// since job keys don't include the project, it's read from the api's virtual service (the default namespace is returned if the api isn't deployed)
+func APINamespace(apiName string) (string, error) {
+	virtualService, err := operator.GetAPIVirtualService(apiName)
+	if err != nil {
+		return "", err
+	}
+	if virtualService == nil {
+		return consts.DefaultNamespace, nil
+	}
+	return virtualService.Namespace, nil
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"path"
+	"sort"
+
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/cortexlabs/cortex/pkg/config"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+)
+
+// the contents of a job's pending file once it has been moved to the front of its api's queue (the file is empty otherwise)
+const _movedToFrontFileContents = "front"
+
+// ListQueuedJobKeysByAPI returns the keys of the api's jobs which have been submitted but not yet started, in the order in which they will be started
+func ListQueuedJobKeysByAPI(kind userconfig.Kind, apiName string) ([]spec.JobKey, error) {
+	_, ok := _jobKinds[kind]
+	if !ok {
+		return nil, ErrorInvalidJobKind(kind)
+	}
+
+	s3Objects, err := config.AWS.ListS3Dir(config.ClusterConfig.Bucket, path.Join(allPendingDependenciesKey(kind), apiName), false, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return queuedJobKeysFromObjects(s3Objects), nil
+}
+
+// MoveJobToFrontOfQueue moves a job which has not yet started ahead of all of the other queued jobs of its api
+func MoveJobToFrontOfQueue(jobKey spec.JobKey) error {
+	isQueued, err := config.AWS.IsS3File(config.ClusterConfig.Bucket, pendingDependenciesKey(jobKey))
+	if err != nil {
+		return err
+	}
+	if !isQueued {
+		return ErrorJobIsNotQueued(jobKey)
+	}
+
+	// the file's last modified timestamp orders the jobs which have been moved to the front
+	return config.AWS.UploadStringToS3(_movedToFrontFileContents, config.ClusterConfig.Bucket, pendingDependenciesKey(jobKey))
+}
+
+// jobs which were moved to the front come first (most recently moved first), followed by the remaining jobs in the order in which they were submitted
+func queuedJobKeysFromObjects(s3Objects []*s3.Object) []spec.JobKey {
+	var queuedObjects []*s3.Object
+	for _, obj := range s3Objects {
+		if obj != nil {
+			queuedObjects = append(queuedObjects, obj)
+		}
+	}
+
+	sort.SliceStable(queuedObjects, func(i, j int) bool {
+		iMoved := *queuedObjects[i].Size > 0
+		jMoved := *queuedObjects[j].Size > 0
+		if iMoved != jMoved {
+			return iMoved
+		}
+		if iMoved && !queuedObjects[i].LastModified.Equal(*queuedObjects[j].LastModified) {
+			return queuedObjects[i].LastModified.After(*queuedObjects[j].LastModified)
+		}
+		// job ids decrease monotonically, so older jobs have larger ids
+		return path.Base(*queuedObjects[i].Key) > path.Base(*queuedObjects[j].Key)
+	})
+
+	jobKeys := make([]spec.JobKey, 0, len(queuedObjects))
+	for _, obj := range queuedObjects {
+		jobKeys = append(jobKeys, jobKeyFromInProgressKey(*obj.Key))
+	}
+	return jobKeys
+}
@@ -91,6 +91,10 @@ func GetTaskStatusCode(lastUpdatedMap map[string]time.Time) status.JobCode {
 		return status.JobUnexpectedError
 	}
 
+	if _, ok := lastUpdatedMap[status.JobDependencyFailed.String()]; ok {
+		return status.JobDependencyFailed
+	}
+
 	if _, ok := lastUpdatedMap[status.JobCompletedWithFailures.String()]; ok {
 		return status.JobCompletedWithFailures
 	}
@@ -107,6 +111,14 @@ func GetTaskStatusCode(lastUpdatedMap map[string]time.Time) status.JobCode {
 		return status.JobEnqueuing
 	}
 
+	if _, ok := lastUpdatedMap[status.JobQueued.String()]; ok {
+		return status.JobQueued
+	}
+
+	if _, ok := lastUpdatedMap[status.JobPendingDependencies.String()]; ok {
+		return status.JobPendingDependencies
+	}
+
 	if _, ok := lastUpdatedMap[status.JobPending.String()]; ok {
 		return status.JobPending
 	}
@@ -147,6 +159,10 @@ func GetBatchStatusCode(lastUpdatedMap map[string]time.Time) status.JobCode {
 		return status.JobStopped
 	}
 
+	if _, ok := lastUpdatedMap[status.JobDependencyFailed.String()]; ok {
+		return status.JobDependencyFailed
+	}
+
 	if _, ok := lastUpdatedMap[status.JobRunning.String()]; ok {
 		return status.JobRunning
 	}
@@ -155,6 +171,14 @@ func GetBatchStatusCode(lastUpdatedMap map[string]time.Time) status.JobCode {
 		return status.JobEnqueuing
 	}
 
+	if _, ok := lastUpdatedMap[status.JobQueued.String()]; ok {
+		return status.JobQueued
+	}
+
+	if _, ok := lastUpdatedMap[status.JobPendingDependencies.String()]; ok {
+		return status.JobPendingDependencies
+	}
+
 	if _, ok := lastUpdatedMap[status.JobPending.String()]; ok {
 		return status.JobPending
 	}
@@ -459,3 +483,66 @@ func SetTimedOutStatus(jobKey spec.JobKey) error {
 
 	return nil
 }
+
+// SetPendingDependenciesStatus marks a job as waiting for the jobs which it depends on to succeed
+func SetPendingDependenciesStatus(jobKey spec.JobKey) error {
+	err := config.AWS.UploadStringToS3("", config.ClusterConfig.Bucket, path.Join(jobKey.Prefix(config.ClusterConfig.ClusterUID), status.JobPendingDependencies.String()))
+	if err != nil {
+		return err
+	}
+
+	err = uploadPendingDependenciesFile(jobKey)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SetQueuedStatus marks a job as waiting for one of its api's running jobs to complete, since the api is running max_concurrent_jobs jobs
+func SetQueuedStatus(jobKey spec.JobKey) error {
+	err := config.AWS.UploadStringToS3("", config.ClusterConfig.Bucket, path.Join(jobKey.Prefix(config.ClusterConfig.ClusterUID), status.JobQueued.String()))
+	if err != nil {
+		return err
+	}
+
+	// a job which was waiting for its dependencies is already in the queue, and keeps its position
+	isQueued, err := config.AWS.IsS3File(config.ClusterConfig.Bucket, pendingDependenciesKey(jobKey))
+	if err != nil {
+		return err
+	}
+	if !isQueued {
+		return uploadPendingDependenciesFile(jobKey)
+	}
+
+	return nil
+}
+
+func SetDependencyFailedStatus(jobKey spec.JobKey) error {
+	err := config.AWS.UploadStringToS3("", config.ClusterConfig.Bucket, path.Join(jobKey.Prefix(config.ClusterConfig.ClusterUID), status.JobDependencyFailed.String()))
+	if err != nil {
+		return err
+	}
+
+	err = DeletePendingDependenciesFile(jobKey)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SetNotStartedJobStoppedStatus stops a job which is waiting for its dependencies or is queued (and therefore has no runtime resources)
+func SetNotStartedJobStoppedStatus(jobKey spec.JobKey) error {
+	err := config.AWS.UploadStringToS3("", config.ClusterConfig.Bucket, path.Join(jobKey.Prefix(config.ClusterConfig.ClusterUID), status.JobStopped.String()))
+	if err != nil {
+		return err
+	}
+
+	err = DeletePendingDependenciesFile(jobKey)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -44,7 +44,7 @@ const _taskDashboardUID = "taskapi"
 
 // UpdateAPI deploys or update a task api without triggering any task
 func UpdateAPI(apiConfig *userconfig.API) (*spec.API, string, error) {
-	prevVirtualService, err := config.K8s.GetVirtualService(workloads.K8sName(apiConfig.Name))
+	prevVirtualService, err := config.K8s.WithNamespace(apiConfig.Namespace()).GetVirtualService(workloads.K8sName(apiConfig.Name))
 	if err != nil {
 		return nil, "", err
 	}
@@ -68,7 +68,7 @@ func UpdateAPI(apiConfig *userconfig.API) (*spec.API, string, error) {
 		err = applyK8sResources(api, prevVirtualService)
 		if err != nil {
 			routines.RunWithPanicHandler(func() {
-				deleteK8sResources(api.Name)
+				deleteK8sResources(api.Name, api.Namespace())
 			})
 			return nil, "", err
 		}
@@ -93,10 +93,10 @@ func UpdateAPI(apiConfig *userconfig.API) (*spec.API, string, error) {
 }
 
 // DeleteAPI deletes a task api
-func DeleteAPI(apiName string, keepCache bool) error {
+func DeleteAPI(apiName string, namespace string, keepCache bool) error {
 	err := parallel.RunFirstErr(
 		func() error {
-			return deleteK8sResources(apiName)
+			return deleteK8sResources(apiName, namespace)
 		},
 		func() error {
 			if keepCache {
@@ -115,6 +115,7 @@ func DeleteAPI(apiName string, keepCache bool) error {
 
 func deleteS3Resources(apiName string) error {
 	_ = job.DeleteAllInProgressFilesByAPI(userconfig.TaskAPIKind, apiName) // not useful xml error is thrown, swallow the error
+	_ = job.DeleteAllPendingDependenciesFilesByAPI(userconfig.TaskAPIKind, apiName)
 	return parallel.RunFirstErr(
 		func() error {
 			prefix := filepath.Join(config.ClusterConfig.ClusterUID, "apis", apiName)
@@ -200,6 +201,16 @@ func GetAllAPIs(virtualServices []*istioclientnetworking.VirtualService, k8sJobs
 		}
 	}
 
+	queuedJobKeys, err := job.ListAllPendingDependenciesJobKeys(userconfig.TaskAPIKind)
+	if err != nil {
+		return nil, err
+	}
+	for _, jobKey := range queuedJobKeys {
+		if taskAPI, ok := taskAPIsMap[jobKey.APIName]; ok {
+			taskAPI.QueuedJobCount++
+		}
+	}
+
 	taskAPIList := make([]schema.APIResponse, 0, len(taskAPIsMap))
 
 	for _, taskAPI := range taskAPIsMap {
@@ -221,7 +232,9 @@ func GetAPIByName(deployedResource *operator.DeployedResource) ([]schema.APIResp
 		return nil, err
 	}
 
-	k8sJobs, err := config.K8s.ListJobsByLabel("apiName", deployedResource.Name)
+	k8sClient := config.K8s.WithNamespace(deployedResource.Namespace())
+
+	k8sJobs, err := k8sClient.ListJobsByLabel("apiName", deployedResource.Name)
 	if err != nil {
 		return nil, err
 	}
@@ -236,7 +249,7 @@ func GetAPIByName(deployedResource *operator.DeployedResource) ([]schema.APIResp
 		return nil, err
 	}
 
-	pods, err := config.K8s.ListPodsByLabel("apiName", deployedResource.Name)
+	pods, err := k8sClient.ListPodsByLabel("apiName", deployedResource.Name)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,136 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package taskapi
+
+import (
+	"strings"
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/config"
+	awslib "github.com/cortexlabs/cortex/pkg/lib/aws"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	"github.com/cortexlabs/cortex/pkg/operator/operator"
+	"github.com/cortexlabs/cortex/pkg/operator/resources/job"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
+)
+
+func getCheckpointRestore(jobKey spec.JobKey) (*spec.CheckpointRestore, error) {
+	key := jobKey.CheckpointRestoreFilePath(config.ClusterConfig.ClusterUID)
+
+	exists, err := config.AWS.IsS3File(config.ClusterConfig.Bucket, key)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, nil
+	}
+
+	checkpointRestore := spec.CheckpointRestore{}
+	if err := config.AWS.ReadJSONFromS3(&checkpointRestore, config.ClusterConfig.Bucket, key); err != nil {
+		return nil, err
+	}
+	return &checkpointRestore, nil
+}
+
+func uploadCheckpointRestore(jobKey spec.JobKey, checkpointRestore *spec.CheckpointRestore) error {
+	return config.AWS.UploadJSONToS3(checkpointRestore, config.ClusterConfig.Bucket, jobKey.CheckpointRestoreFilePath(config.ClusterConfig.ClusterUID))
+}
+
+// each checkpoint is a file or a directory directly within the job's checkpoint directory; the latest checkpoint is
+// the one which was most recently written to (an empty string is returned if no checkpoints have been written)
+func getLatestCheckpoint(jobSpec *spec.TaskJob) (string, error) {
+	bucket, key, err := awslib.SplitS3Path(jobSpec.Checkpoint.JobDir(jobSpec.ID))
+	if err != nil {
+		return "", err
+	}
+	prefix := s.EnsureSuffix(key, "/")
+
+	objects, err := config.AWS.ListS3Prefix(bucket, prefix, false, nil, nil)
+	if err != nil {
+		return "", err
+	}
+
+	var latestCheckpoint string
+	var latestModified time.Time
+	for _, object := range objects {
+		if object.Key == nil || object.LastModified == nil {
+			continue
+		}
+		name := strings.Split(strings.TrimPrefix(*object.Key, prefix), "/")[0]
+		if name == "" {
+			continue
+		}
+		if object.LastModified.After(latestModified) {
+			latestCheckpoint = name
+			latestModified = *object.LastModified
+		}
+	}
+
+	if latestCheckpoint == "" {
+		return "", nil
+	}
+	return awslib.S3Path(bucket, prefix+latestCheckpoint), nil
+}
+
+// recreates the job's kubernetes resources, pointing the new workers to the job's latest checkpoint
+func restartJobFromCheckpoint(jobSpec *spec.TaskJob, checkpointRestore *spec.CheckpointRestore) error {
+	jobKey := jobSpec.JobKey
+
+	apiSpec, err := operator.DownloadAPISpec(jobSpec.APIName, jobSpec.APIID)
+	if err != nil {
+		return err
+	}
+
+	latestCheckpoint, err := getLatestCheckpoint(jobSpec)
+	if err != nil {
+		return errors.Wrap(err, "unable to find the latest checkpoint")
+	}
+
+	if checkpointRestore == nil {
+		checkpointRestore = &spec.CheckpointRestore{}
+	}
+	checkpointRestore.Restarts++
+	checkpointRestore.RestoredFrom = latestCheckpoint
+	checkpointRestore.LastRestartTime = time.Now()
+
+	jobLogger, err := operator.GetJobLogger(jobKey)
+	if err == nil {
+		if latestCheckpoint == "" {
+			jobLogger.Warnf("a worker failed; restarting job from the beginning since no checkpoints have been written (restart %d)", checkpointRestore.Restarts)
+		} else {
+			jobLogger.Warnf("a worker failed; restarting job from checkpoint %s (restart %d)", latestCheckpoint, checkpointRestore.Restarts)
+		}
+	}
+
+	if err := uploadCheckpointRestore(jobKey, checkpointRestore); err != nil {
+		return err
+	}
+
+	if err := errors.FirstError(deleteK8sJob(jobKey, apiSpec.Namespace()), deleteK8sJobService(jobKey, apiSpec.Namespace())); err != nil {
+		return err
+	}
+
+	restartedJobSpec := *jobSpec
+	restartedJobSpec.CheckpointRestore = checkpointRestore
+	if err := createK8sJob(apiSpec, &restartedJobSpec); err != nil {
+		return err
+	}
+
+	// refreshes the running status so that the recreated kubernetes job is given a grace period to appear
+	return job.SetRunningStatus(jobKey)
+}
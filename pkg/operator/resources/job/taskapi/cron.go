@@ -61,7 +61,7 @@ func ManageJobResources() error {
 		}
 	}
 
-	jobs, err := config.K8s.ListJobs(
+	jobs, err := config.K8sAllNamspaces.ListJobs(
 		&kmeta.ListOptions{
 			LabelSelector: klabels.SelectorFromSet(
 				map[string]string{"apiKind": userconfig.TaskAPIKind.String()},
@@ -164,7 +164,7 @@ func ManageJobResources() error {
 		}
 
 		if jobState.Status == status.JobRunning {
-			err = checkIfJobCompleted(jobKey, jobSpec.StartTime, k8sJob)
+			err = checkIfJobCompleted(jobSpec, k8sJob)
 			if err != nil {
 				telemetry.Error(err)
 				operatorLogger.Error(err)
@@ -204,10 +204,36 @@ func reconcileInProgressJob(jobState *job.State, jobFound bool) (status.JobCode,
 	return jobState.Status, ""
 }
 
-func checkIfJobCompleted(jobKey spec.JobKey, jobStartTime time.Time, k8sJob kbatch.Job) error {
-	pods, _ := config.K8s.ListPodsByLabel("jobID", jobKey.ID)
+func checkIfJobCompleted(jobSpec *spec.TaskJob, k8sJob kbatch.Job) error {
+	jobKey := jobSpec.JobKey
+
+	maxRetries := 0
+	if jobSpec.MaxRetries != nil {
+		maxRetries = *jobSpec.MaxRetries
+	}
+
+	// failed pods are restarted by kubernetes until the job's backoff limit (max_retries) is reached
+	retriesExhausted := int(k8sJob.Status.Failed) > maxRetries
+
+	// checkpointed jobs are restarted from their latest checkpoint by the operator until max_retries restarts have been made
+	if jobSpec.Checkpoint != nil && k8sJob.Status.Failed > 0 {
+		checkpointRestore, err := getCheckpointRestore(jobKey)
+		if err != nil {
+			return err
+		}
+		restarts := 0
+		if checkpointRestore != nil {
+			restarts = checkpointRestore.Restarts
+		}
+		if restarts < maxRetries {
+			return restartJobFromCheckpoint(jobSpec, checkpointRestore)
+		}
+		retriesExhausted = true
+	}
+
+	pods, _ := config.K8s.WithNamespace(k8sJob.Namespace).ListPodsByLabel("jobID", jobKey.ID)
 	for i := range pods {
-		if k8s.WasPodOOMKilled(&pods[i]) {
+		if k8s.WasPodOOMKilled(&pods[i]) && (maxRetries == 0 || retriesExhausted) {
 			return errors.FirstError(
 				job.SetWorkerOOMStatus(jobKey),
 				deleteJobRuntimeResources(jobKey),
@@ -216,18 +242,26 @@ func checkIfJobCompleted(jobKey spec.JobKey, jobStartTime time.Time, k8sJob kbat
 		}
 	}
 
-	if int(k8sJob.Status.Failed) == 1 {
+	if retriesExhausted {
 		return errors.FirstError(
 			job.SetWorkerErrorStatus(jobKey),
 			deleteJobRuntimeResources(jobKey),
 			recordFailure(jobKey),
 		)
-	} else if int(k8sJob.Status.Succeeded) == 1 && len(pods) > 0 {
+	}
+
+	// distributed jobs complete once every worker has succeeded
+	completions := int32(1)
+	if k8sJob.Spec.Completions != nil {
+		completions = *k8sJob.Spec.Completions
+	}
+
+	if k8sJob.Status.Succeeded >= completions && len(pods) > 0 {
 		return errors.FirstError(
 			job.SetSucceededStatus(jobKey),
 			deleteJobRuntimeResources(jobKey),
 			recordSuccess(jobKey),
-			recordTimePerTask(jobKey, time.Since(jobStartTime)),
+			recordTimePerTask(jobKey, time.Since(jobSpec.StartTime)),
 		)
 	}
 
@@ -25,8 +25,10 @@ import (
 	"github.com/cortexlabs/cortex/pkg/operator/lib/routines"
 	"github.com/cortexlabs/cortex/pkg/operator/operator"
 	"github.com/cortexlabs/cortex/pkg/operator/resources/job"
+	"github.com/cortexlabs/cortex/pkg/operator/resources/quota"
 	"github.com/cortexlabs/cortex/pkg/operator/schema"
 	"github.com/cortexlabs/cortex/pkg/types/spec"
+	"github.com/cortexlabs/cortex/pkg/types/status"
 	"github.com/cortexlabs/cortex/pkg/workloads"
 )
 
@@ -36,10 +38,13 @@ func SubmitJob(apiName string, submission *schema.TaskJobSubmission) (*spec.Task
 		return nil, err
 	}
 
-	virtualService, err := config.K8s.GetVirtualService(workloads.K8sName(apiName))
+	virtualService, err := operator.GetAPIVirtualService(apiName)
 	if err != nil {
 		return nil, err
 	}
+	if virtualService == nil {
+		return nil, errors.ErrorUnexpected("unable to find virtual service", apiName)
+	}
 
 	apiID := virtualService.Labels["apiID"]
 
@@ -48,6 +53,19 @@ func SubmitJob(apiName string, submission *schema.TaskJobSubmission) (*spec.Task
 		return nil, err
 	}
 
+	workers := int64(1)
+	if apiSpec.Distributed != nil {
+		workers = int64(apiSpec.Distributed.Workers)
+	}
+	if err := quota.ValidateJobSubmission(apiSpec.API, workers); err != nil {
+		return nil, err
+	}
+
+	dependencies, err := job.ResolveDependencies(apiName, apiSpec.Kind, submission.DependsOn)
+	if err != nil {
+		return nil, errors.Wrap(err, schema.DependsOnKey)
+	}
+
 	jobID := spec.MonotonicallyDecreasingID()
 
 	jobKey := spec.JobKey{
@@ -63,17 +81,64 @@ func SubmitJob(apiName string, submission *schema.TaskJobSubmission) (*spec.Task
 		SpecID:               apiSpec.SpecID,
 		PodID:                apiSpec.PodID,
 		StartTime:            time.Now(),
+		Dependencies:         dependencies,
+	}
+
+	if apiSpec.Distributed != nil {
+		jobSpec.Workers = int(apiSpec.Distributed.Workers)
 	}
 
 	if err := uploadJobSpec(&jobSpec); err != nil {
 		return nil, err
 	}
 
-	deployJob(apiSpec, &jobSpec)
+	if len(dependencies) > 0 {
+		if err := job.SetPendingDependenciesStatus(jobKey); err != nil {
+			return nil, err
+		}
+		return &jobSpec, nil
+	}
+
+	started, err := job.StartIfBelowConcurrentJobLimit(jobKey.Kind, jobKey.APIName, apiSpec.MaxConcurrentJobs, func() error {
+		deployJob(apiSpec, &jobSpec)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !started {
+		if err := job.SetQueuedStatus(jobKey); err != nil {
+			return nil, err
+		}
+	}
 
 	return &jobSpec, nil
 }
 
+// StartPendingJob starts a job which was waiting for its dependencies to succeed or was queued, unless its api is running
+// maxConcurrentJobs jobs; it returns whether the job was started
+func StartPendingJob(jobKey spec.JobKey, maxConcurrentJobs *int64) (bool, error) {
+	return job.StartIfBelowConcurrentJobLimit(jobKey.Kind, jobKey.APIName, maxConcurrentJobs, func() error {
+		jobSpec, err := operator.DownloadTaskJobSpec(jobKey)
+		if err != nil {
+			return err
+		}
+
+		apiSpec, err := operator.DownloadAPISpec(jobSpec.APIName, jobSpec.APIID)
+		if err != nil {
+			return err
+		}
+
+		if err := job.DeletePendingDependenciesFile(jobKey); err != nil {
+			return err
+		}
+
+		deployJob(apiSpec, jobSpec)
+
+		return nil
+	})
+}
+
 func uploadJobSpec(jobSpec *spec.TaskJob) error {
 	if err := config.AWS.UploadJSONToS3(
 		jobSpec, config.ClusterConfig.Bucket, jobSpec.SpecFilePath(config.ClusterConfig.ClusterUID),
@@ -111,7 +176,7 @@ func createJobConfigMap(apiSpec spec.API, jobSpec spec.TaskJob) error {
 		return err
 	}
 
-	return createK8sConfigMap(k8sConfigMap(apiSpec, jobSpec, configMapData))
+	return createK8sConfigMap(apiSpec.Namespace(), k8sConfigMap(apiSpec, jobSpec, configMapData))
 }
 
 func handleJobSubmissionError(jobKey spec.JobKey, jobErr error) {
@@ -133,9 +198,15 @@ func handleJobSubmissionError(jobKey spec.JobKey, jobErr error) {
 }
 
 func deleteJobRuntimeResources(jobKey spec.JobKey) error {
+	namespace, err := job.APINamespace(jobKey.APIName)
+	if err != nil {
+		return err
+	}
+
 	return errors.FirstError(
-		deleteK8sJob(jobKey),
-		deleteK8sConfigMap(jobKey),
+		deleteK8sJob(jobKey, namespace),
+		deleteK8sConfigMap(jobKey, namespace),
+		deleteK8sJobService(jobKey, namespace),
 	)
 }
 
@@ -148,6 +219,10 @@ func StopJob(jobKey spec.JobKey) error {
 		return err
 	}
 
+	if jobState.Status == status.JobPendingDependencies || jobState.Status == status.JobQueued {
+		return job.SetNotStartedJobStoppedStatus(jobKey)
+	}
+
 	if !jobState.Status.IsInProgress() {
 		routines.RunWithPanicHandler(func() {
 			deleteJobRuntimeResources(jobKey)
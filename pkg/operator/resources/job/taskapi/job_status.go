@@ -32,12 +32,18 @@ func GetJobStatus(jobKey spec.JobKey) (*status.TaskJobStatus, error) {
 		return nil, err
 	}
 
-	k8sJob, err := config.K8s.GetJob(jobKey.K8sName())
+	namespace, err := job.APINamespace(jobKey.APIName)
 	if err != nil {
 		return nil, err
 	}
+	k8sClient := config.K8s.WithNamespace(namespace)
 
-	pods, err := config.K8s.ListPodsByLabels(map[string]string{"apiName": jobKey.APIName, "jobID": jobKey.ID})
+	k8sJob, err := k8sClient.GetJob(jobKey.K8sName())
+	if err != nil {
+		return nil, err
+	}
+
+	pods, err := k8sClient.ListPodsByLabels(map[string]string{"apiName": jobKey.APIName, "jobID": jobKey.ID})
 	if err != nil {
 		return nil, err
 	}
@@ -68,6 +74,14 @@ func getJobStatusFromJobState(jobState *job.State, k8sJob *kbatch.Job, pods []kc
 		Status:  jobState.Status,
 	}
 
+	if jobSpec.Checkpoint != nil {
+		checkpointRestore, err := getCheckpointRestore(jobKey)
+		if err != nil {
+			return nil, err
+		}
+		jobStatus.CheckpointRestore = checkpointRestore
+	}
+
 	if jobState.Status.IsInProgress() && k8sJob != nil {
 		workerCounts := job.GetWorkerCountsForJob(*k8sJob, pods)
 		jobStatus.WorkerCounts = &workerCounts
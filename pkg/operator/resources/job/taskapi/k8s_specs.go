@@ -22,6 +22,7 @@ import (
 	"github.com/cortexlabs/cortex/pkg/config"
 	"github.com/cortexlabs/cortex/pkg/consts"
 	"github.com/cortexlabs/cortex/pkg/lib/k8s"
+	"github.com/cortexlabs/cortex/pkg/lib/maps"
 	"github.com/cortexlabs/cortex/pkg/lib/parallel"
 	"github.com/cortexlabs/cortex/pkg/lib/pointer"
 	s "github.com/cortexlabs/cortex/pkg/lib/strings"
@@ -40,7 +41,7 @@ const _operatorService = "operator"
 func virtualServiceSpec(api *spec.API) *istioclientnetworking.VirtualService {
 	return k8s.VirtualService(&k8s.VirtualServiceSpec{
 		Name:     workloads.K8sName(api.Name),
-		Gateways: []string{"apis-gateway"},
+		Gateways: []string{workloads.APIGatewayRef(api.Networking)},
 		Destinations: []k8s.Destination{{
 			ServiceName: _operatorService,
 			Weight:      100,
@@ -49,7 +50,7 @@ func virtualServiceSpec(api *spec.API) *istioclientnetworking.VirtualService {
 		PrefixPath:  api.Networking.Endpoint,
 		Rewrite:     pointer.String(path.Join("tasks", api.Name)),
 		Annotations: api.ToK8sAnnotations(),
-		Labels: map[string]string{
+		Labels: maps.MergeStrMapsString(api.Labels, map[string]string{
 			"apiName":               api.Name,
 			"apiID":                 api.ID,
 			"specID":                api.SpecID,
@@ -57,16 +58,36 @@ func virtualServiceSpec(api *spec.API) *istioclientnetworking.VirtualService {
 			"initialDeploymentTime": s.Int64(api.InitialDeploymentTime),
 			"apiKind":               api.Kind.String(),
 			"cortex.dev/api":        "true",
-		},
+			"cortex.dev/project":    api.Project,
+		}),
 	})
 }
 
 func k8sJobSpec(api *spec.API, job *spec.TaskJob) *kbatch.Job {
-	containers, volumes := workloads.TaskContainers(*api, &job.JobKey)
+	containers, volumes := workloads.TaskContainers(*api, job)
+
+	// checkpointed jobs are restarted by the operator (from their latest checkpoint) rather than by kubernetes
+	var backoffLimit int32
+	if job.MaxRetries != nil && job.Checkpoint == nil {
+		backoffLimit = int32(*job.MaxRetries)
+	}
+
+	parallelism := int32(job.Workers)
+	var completions *int32
+	var subdomain string
+	if api.Distributed != nil {
+		// one pod per worker, each with a stable hostname (<job name>-<rank>) under the job's headless service
+		parallelism = api.Distributed.Workers
+		completions = pointer.Int32(api.Distributed.Workers)
+		subdomain = job.JobKey.K8sName()
+	}
 
 	return k8s.Job(&k8s.JobSpec{
-		Name:        job.JobKey.K8sName(),
-		Parallelism: int32(job.Workers),
+		Name:         job.JobKey.K8sName(),
+		Parallelism:  parallelism,
+		Completions:  completions,
+		Indexed:      api.Distributed != nil,
+		BackoffLimit: backoffLimit,
 		Labels: map[string]string{
 			"apiName":        api.Name,
 			"apiID":          api.ID,
@@ -84,12 +105,13 @@ func k8sJobSpec(api *spec.API, job *spec.TaskJob) *kbatch.Job {
 				"apiKind":        api.Kind.String(),
 				"cortex.dev/api": "true",
 			},
-			Annotations: map[string]string{
+			Annotations: maps.MergeStrMapsString(map[string]string{
 				"traffic.sidecar.istio.io/excludeOutboundIPRanges": "0.0.0.0/0",
 				"cluster-autoscaler.kubernetes.io/safe-to-evict":   "false",
-			},
+			}, workloads.IAMRolePodAnnotations(api.Pod)),
 			K8sPodSpec: kcore.PodSpec{
 				RestartPolicy: "Never",
+				Subdomain:     subdomain,
 				InitContainers: []kcore.Container{
 					workloads.KubexitInitContainer(),
 				},
@@ -98,12 +120,36 @@ func k8sJobSpec(api *spec.API, job *spec.TaskJob) *kbatch.Job {
 				Tolerations:        workloads.GenerateResourceTolerations(),
 				Affinity:           workloads.GenerateNodeAffinities(api.NodeGroups),
 				Volumes:            volumes,
-				ServiceAccountName: workloads.ServiceAccountName,
+				ServiceAccountName: workloads.PodServiceAccountName(api.Name, api.Pod),
+				ImagePullSecrets:   workloads.ImagePullSecrets(api.Name, api.Pod),
 			},
 		},
 	})
 }
 
+func k8sHeadlessService(api *spec.API, job *spec.TaskJob) *kcore.Service {
+	service := k8s.Service(&k8s.ServiceSpec{
+		Name:       job.JobKey.K8sName(),
+		PortName:   "rendezvous",
+		Port:       api.Distributed.Port,
+		TargetPort: api.Distributed.Port,
+		ClusterIP:  kcore.ClusterIPNone,
+		Selector: map[string]string{
+			"apiName": api.Name,
+			"jobID":   job.ID,
+		},
+		Labels: map[string]string{
+			"apiName":        api.Name,
+			"jobID":          job.ID,
+			"apiKind":        api.Kind.String(),
+			"cortex.dev/api": "true",
+		},
+	})
+	// workers must be able to resolve each other before all of them are running
+	service.Spec.PublishNotReadyAddresses = true
+	return service
+}
+
 func k8sConfigMap(api spec.API, job spec.TaskJob, configMapData map[string]string) kcore.ConfigMap {
 	return *k8s.ConfigMap(&k8s.ConfigMapSpec{
 		Name: job.JobKey.K8sName(),
@@ -117,21 +163,24 @@ func k8sConfigMap(api spec.API, job spec.TaskJob, configMapData map[string]strin
 }
 
 func applyK8sResources(api *spec.API, prevVirtualService *istioclientnetworking.VirtualService) error {
+	k8sClient := config.K8s.WithNamespace(api.Namespace())
 	newVirtualService := virtualServiceSpec(api)
 
 	if prevVirtualService == nil {
-		_, err := config.K8s.CreateVirtualService(newVirtualService)
+		_, err := k8sClient.CreateVirtualService(newVirtualService)
 		return err
 	}
 
-	_, err := config.K8s.UpdateVirtualService(prevVirtualService, newVirtualService)
+	_, err := k8sClient.UpdateVirtualService(prevVirtualService, newVirtualService)
 	return err
 }
 
-func deleteK8sResources(apiName string) error {
+func deleteK8sResources(apiName string, namespace string) error {
+	k8sClient := config.K8s.WithNamespace(namespace)
+
 	return parallel.RunFirstErr(
 		func() error {
-			_, err := config.K8s.DeleteJobs(&kmeta.ListOptions{
+			_, err := k8sClient.DeleteJobs(&kmeta.ListOptions{
 				LabelSelector: klabels.SelectorFromSet(
 					map[string]string{
 						"apiName": apiName,
@@ -141,14 +190,20 @@ func deleteK8sResources(apiName string) error {
 			return err
 		},
 		func() error {
-			_, err := config.K8s.DeleteVirtualService(workloads.K8sName(apiName))
+			_, err := k8sClient.DeleteVirtualService(workloads.K8sName(apiName))
 			return err
 		},
+		func() error {
+			return deleteK8sServices(k8sClient, map[string]string{
+				"apiName": apiName,
+				"apiKind": userconfig.TaskAPIKind.String(),
+			})
+		},
 	)
 }
 
-func deleteK8sJob(jobKey spec.JobKey) error {
-	_, err := config.K8s.DeleteJobs(&kmeta.ListOptions{
+func deleteK8sJob(jobKey spec.JobKey, namespace string) error {
+	_, err := config.K8s.WithNamespace(namespace).DeleteJobs(&kmeta.ListOptions{
 		LabelSelector: klabels.SelectorFromSet(
 			map[string]string{
 				"apiName": jobKey.APIName,
@@ -159,10 +214,44 @@ func deleteK8sJob(jobKey spec.JobKey) error {
 	return err
 }
 
+func deleteK8sJobService(jobKey spec.JobKey, namespace string) error {
+	return deleteK8sServices(config.K8s.WithNamespace(namespace), map[string]string{
+		"apiName": jobKey.APIName,
+		"apiKind": userconfig.TaskAPIKind.String(),
+		"jobID":   jobKey.ID,
+	})
+}
+
+// deletes the headless services of distributed jobs
+func deleteK8sServices(k8sClient *k8s.Client, labels map[string]string) error {
+	services, err := k8sClient.ListServices(&kmeta.ListOptions{
+		LabelSelector: klabels.SelectorFromSet(labels).String(),
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, service := range services {
+		if _, err := k8sClient.DeleteService(service.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func createK8sJob(apiSpec *spec.API, jobSpec *spec.TaskJob) error {
+	k8sClient := config.K8s.WithNamespace(apiSpec.Namespace())
+
+	if apiSpec.Distributed != nil {
+		_, err := k8sClient.CreateService(k8sHeadlessService(apiSpec, jobSpec))
+		if err != nil {
+			return err
+		}
+	}
+
 	k8sJob := k8sJobSpec(apiSpec, jobSpec)
 
-	_, err := config.K8s.CreateJob(k8sJob)
+	_, err := k8sClient.CreateJob(k8sJob)
 	if err != nil {
 		return err
 	}
@@ -170,12 +259,12 @@ func createK8sJob(apiSpec *spec.API, jobSpec *spec.TaskJob) error {
 	return nil
 }
 
-func deleteK8sConfigMap(jobKey spec.JobKey) error {
-	_, err := config.K8s.DeleteConfigMap(jobKey.K8sName())
+func deleteK8sConfigMap(jobKey spec.JobKey, namespace string) error {
+	_, err := config.K8s.WithNamespace(namespace).DeleteConfigMap(jobKey.K8sName())
 	return err
 }
 
-func createK8sConfigMap(configMap kcore.ConfigMap) error {
-	_, err := config.K8s.CreateConfigMap(&configMap)
+func createK8sConfigMap(namespace string, configMap kcore.ConfigMap) error {
+	_, err := config.K8s.WithNamespace(namespace).CreateConfigMap(&configMap)
 	return err
 }
@@ -17,15 +17,52 @@ limitations under the License.
 package taskapi
 
 import (
+	"strings"
+
+	awslib "github.com/cortexlabs/cortex/pkg/lib/aws"
 	cr "github.com/cortexlabs/cortex/pkg/lib/configreader"
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/regex"
+	"github.com/cortexlabs/cortex/pkg/operator/resources/job"
 	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
 )
 
+// ValidateScheduledJobSubmission validates a job submission which will be submitted on a schedule
+func ValidateScheduledJobSubmission(submission *schema.TaskJobSubmission) error {
+	return validateJobSubmission(submission)
+}
+
 func validateJobSubmission(submission *schema.TaskJobSubmission) error {
 	if submission.Workers != 1 {
 		return errors.Wrap(cr.ErrorInvalidInt(submission.Workers, 1), schema.WorkersKey)
 	}
 
+	if submission.Timeout != nil && *submission.Timeout <= 0 {
+		return errors.Wrap(cr.ErrorMustBeGreaterThanOrEqualTo(*submission.Timeout, 1), schema.TimeoutKey)
+	}
+
+	if submission.MaxRetries != nil && *submission.MaxRetries < 0 {
+		return errors.Wrap(cr.ErrorMustBeGreaterThanOrEqualTo(*submission.MaxRetries, 0), schema.MaxRetriesKey)
+	}
+
+	if submission.Checkpoint != nil {
+		if !awslib.IsValidS3Path(submission.Checkpoint.S3Path) {
+			return errors.Wrap(awslib.ErrorInvalidS3Path(submission.Checkpoint.S3Path), schema.CheckpointKey, schema.S3PathKey)
+		}
+		if submission.Checkpoint.Interval != nil && *submission.Checkpoint.Interval <= 0 {
+			return errors.Wrap(cr.ErrorMustBeGreaterThanOrEqualTo(*submission.Checkpoint.Interval, 1), schema.CheckpointKey, schema.IntervalKey)
+		}
+	}
+
+	for name := range submission.Params {
+		if !regex.IsValidEnvVarName(name) {
+			return errors.Wrap(job.ErrorInvalidParamName(name), schema.ParamsKey)
+		}
+		if strings.HasPrefix(name, "CORTEX_") || strings.HasPrefix(name, "KUBEXIT_") {
+			return errors.Wrap(spec.ErrorCortexPrefixedEnvVarNotAllowed("CORTEX_", "KUBEXIT_"), schema.ParamsKey, name)
+		}
+	}
+
 	return nil
 }
@@ -0,0 +1,126 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/operator/operator"
+	"github.com/cortexlabs/cortex/pkg/operator/resources/job"
+	"github.com/cortexlabs/cortex/pkg/operator/resources/job/batchapi"
+	"github.com/cortexlabs/cortex/pkg/operator/resources/job/taskapi"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
+	"github.com/cortexlabs/cortex/pkg/types/status"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+)
+
+const JobDependenciesCronPeriod = 10 * time.Second
+
+// ManagePendingDependenciesJobs starts the jobs whose dependencies have all succeeded (or queues them if their api is running
+// max_concurrent_jobs jobs), starts queued jobs once their api is running fewer jobs, and fails the jobs which depend on a job that did not succeed
+func ManagePendingDependenciesJobs() error {
+	var errs []error
+	apiSpecs := map[string]*spec.API{} // api name -> spec of the deployed version of the api
+
+	for _, kind := range []userconfig.Kind{userconfig.BatchAPIKind, userconfig.TaskAPIKind} {
+		jobKeys, err := job.ListAllPendingDependenciesJobKeys(kind)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		for _, jobKey := range jobKeys {
+			if err := managePendingDependenciesJob(jobKey, apiSpecs); err != nil {
+				errs, _ = errors.AddError(errs, err, jobKey.UserString())
+			}
+		}
+	}
+
+	return errors.FirstError(errs...)
+}
+
+func managePendingDependenciesJob(jobKey spec.JobKey, apiSpecs map[string]*spec.API) error {
+	jobState, err := job.GetJobState(jobKey)
+	if err != nil {
+		if errors.GetKind(err) == job.ErrJobNotFound {
+			return job.DeletePendingDependenciesFile(jobKey)
+		}
+		return err
+	}
+	if jobState.Status != status.JobPendingDependencies && jobState.Status != status.JobQueued {
+		// e.g. the job was stopped
+		return job.DeletePendingDependenciesFile(jobKey)
+	}
+
+	virtualService, err := operator.GetAPIVirtualService(jobKey.APIName)
+	if err != nil {
+		return err
+	}
+	if virtualService == nil || virtualService.Labels["apiKind"] != jobKey.Kind.String() {
+		// the api was deleted while the job was waiting
+		return job.SetNotStartedJobStoppedStatus(jobKey)
+	}
+
+	if jobState.Status == status.JobPendingDependencies {
+		_, dependencies, err := downloadJobDependencies(jobKey)
+		if err != nil {
+			return err
+		}
+
+		allSucceeded, err := job.CheckDependencies(dependencies)
+		if err != nil {
+			if errors.GetKind(err) != job.ErrDependencyFailed {
+				return err
+			}
+			if jobLogger, logErr := operator.GetJobLogger(jobKey); logErr == nil {
+				jobLogger.Error(errors.Message(err))
+			}
+			return job.SetDependencyFailedStatus(jobKey)
+		}
+		if !allSucceeded {
+			return nil
+		}
+	}
+
+	// the limit of the currently deployed version of the api applies
+	apiSpec, ok := apiSpecs[jobKey.APIName]
+	if !ok {
+		apiSpec, err = operator.DownloadAPISpec(jobKey.APIName, virtualService.Labels["apiID"])
+		if err != nil {
+			return err
+		}
+		apiSpecs[jobKey.APIName] = apiSpec
+	}
+
+	var started bool
+	switch jobKey.Kind {
+	case userconfig.BatchAPIKind:
+		started, err = batchapi.StartPendingJob(jobKey, apiSpec.MaxConcurrentJobs)
+	case userconfig.TaskAPIKind:
+		started, err = taskapi.StartPendingJob(jobKey, apiSpec.MaxConcurrentJobs)
+	}
+	if err != nil {
+		return err
+	}
+
+	if !started && jobState.Status != status.JobQueued {
+		return job.SetQueuedStatus(jobKey)
+	}
+
+	return nil
+}
@@ -0,0 +1,315 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"sort"
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/operator/operator"
+	"github.com/cortexlabs/cortex/pkg/operator/resources/job"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
+	"github.com/cortexlabs/cortex/pkg/types/status"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+)
+
+// the number of an api's most recently submitted jobs which are used to estimate how long its jobs run for
+const _jobRunTimeSampleSize = 10
+
+// GetJobQueue returns the jobs of a batch or task api which have been submitted but not yet started, in the order in which they will be started
+func GetJobQueue(apiName string) ([]schema.QueuedJob, error) {
+	deployedResource, err := GetDeployedResourceByName(apiName)
+	if err != nil {
+		return nil, err
+	}
+	if deployedResource.Kind != userconfig.BatchAPIKind && deployedResource.Kind != userconfig.TaskAPIKind {
+		return nil, ErrorOperationIsOnlySupportedForKind(*deployedResource, userconfig.BatchAPIKind, userconfig.TaskAPIKind)
+	}
+
+	jobKeys, err := job.ListQueuedJobKeysByAPI(deployedResource.Kind, apiName)
+	if err != nil {
+		return nil, err
+	}
+
+	apiSpec, err := operator.DownloadAPISpec(apiName, deployedResource.VirtualService.Labels["apiID"])
+	if err != nil {
+		return nil, err
+	}
+
+	estimator := newJobStartEstimator()
+
+	// the times at which the api's running jobs are expected to complete, if its number of concurrent jobs is limited
+	var slotFreeTimes []*time.Time
+	if apiSpec.MaxConcurrentJobs != nil {
+		runningJobKeys, err := job.ListRunningJobKeysByAPI(deployedResource.Kind, apiName)
+		if err != nil {
+			return nil, err
+		}
+		slotFreeTimes = estimator.slotFreeTimes(runningJobKeys, *apiSpec.MaxConcurrentJobs)
+	}
+
+	queuedJobs := make([]schema.QueuedJob, 0, len(jobKeys))
+	for _, jobKey := range jobKeys {
+		jobState, err := job.GetJobState(jobKey)
+		if err != nil {
+			if errors.GetKind(err) == job.ErrJobNotFound {
+				continue
+			}
+			return nil, err
+		}
+		if !jobState.Status.IsNotStarted() {
+			// the job was started or stopped since the queue was listed
+			continue
+		}
+
+		submittedTime, dependencies, err := downloadJobDependencies(jobKey)
+		if err != nil {
+			return nil, err
+		}
+
+		estimatedStartTime := estimator.startTime(dependencies)
+		if slotFreeTimes != nil {
+			estimatedStartTime = estimator.claimSlot(slotFreeTimes, jobKey, estimatedStartTime)
+		}
+
+		queuedJobs = append(queuedJobs, schema.QueuedJob{
+			JobKey:             jobKey,
+			Position:           len(queuedJobs) + 1,
+			Status:             jobState.Status,
+			SubmittedTime:      submittedTime,
+			Dependencies:       dependencies,
+			EstimatedStartTime: estimatedStartTime,
+		})
+	}
+
+	return queuedJobs, nil
+}
+
+// MoveJobToFrontOfQueue moves a queued job ahead of the api's other queued jobs, and returns the updated queue
+func MoveJobToFrontOfQueue(apiName string, jobID string) ([]schema.QueuedJob, error) {
+	deployedResource, err := GetDeployedResourceByName(apiName)
+	if err != nil {
+		return nil, err
+	}
+	if deployedResource.Kind != userconfig.BatchAPIKind && deployedResource.Kind != userconfig.TaskAPIKind {
+		return nil, ErrorOperationIsOnlySupportedForKind(*deployedResource, userconfig.BatchAPIKind, userconfig.TaskAPIKind)
+	}
+
+	err = job.MoveJobToFrontOfQueue(spec.JobKey{
+		APIName: apiName,
+		ID:      jobID,
+		Kind:    deployedResource.Kind,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return GetJobQueue(apiName)
+}
+
+func downloadJobDependencies(jobKey spec.JobKey) (time.Time, []spec.JobKey, error) {
+	switch jobKey.Kind {
+	case userconfig.BatchAPIKind:
+		jobSpec, err := operator.DownloadBatchJobSpec(jobKey)
+		if err != nil {
+			return time.Time{}, nil, err
+		}
+		return jobSpec.StartTime, jobSpec.Dependencies, nil
+	case userconfig.TaskAPIKind:
+		jobSpec, err := operator.DownloadTaskJobSpec(jobKey)
+		if err != nil {
+			return time.Time{}, nil, err
+		}
+		return jobSpec.StartTime, jobSpec.Dependencies, nil
+	}
+
+	return time.Time{}, nil, job.ErrorInvalidJobKind(jobKey.Kind)
+}
+
+// jobStartEstimator estimates when queued jobs will start based on how long the recent jobs of the relevant apis ran for;
+// estimates are cached, since the jobs in a queue often share dependencies
+type jobStartEstimator struct {
+	now      time.Time
+	endTimes map[spec.JobKey]*time.Time
+	runTimes map[spec.JobKey]*time.Duration // keyed by api (the job id is empty)
+}
+
+func newJobStartEstimator() *jobStartEstimator {
+	return &jobStartEstimator{
+		now:      time.Now(),
+		endTimes: map[spec.JobKey]*time.Time{},
+		runTimes: map[spec.JobKey]*time.Duration{},
+	}
+}
+
+// returns nil if the start time can't be estimated
+func (e *jobStartEstimator) startTime(dependencies []spec.JobKey) *time.Time {
+	// queued jobs are started by the dependencies cron once all of their dependencies have succeeded
+	startTime := e.now.Add(JobDependenciesCronPeriod)
+
+	for _, dependency := range dependencies {
+		endTime := e.endTime(dependency)
+		if endTime == nil {
+			return nil
+		}
+		if endTime.After(startTime) {
+			startTime = *endTime
+		}
+	}
+
+	return &startTime
+}
+
+func (e *jobStartEstimator) endTime(jobKey spec.JobKey) *time.Time {
+	if endTime, ok := e.endTimes[jobKey]; ok {
+		return endTime
+	}
+
+	endTime := e.estimateEndTime(jobKey)
+	e.endTimes[jobKey] = endTime
+	return endTime
+}
+
+func (e *jobStartEstimator) estimateEndTime(jobKey spec.JobKey) *time.Time {
+	jobState, err := job.GetJobState(jobKey)
+	if err != nil {
+		return nil
+	}
+
+	if jobState.Status == status.JobSucceeded {
+		return &e.now
+	}
+	if jobState.Status.IsCompleted() {
+		// the jobs which depend on this job will not start
+		return nil
+	}
+	if jobState.Status == status.JobQueued {
+		// the job's start time depends on the queue of its api
+		return nil
+	}
+
+	runTime := e.runTime(jobKey.Kind, jobKey.APIName)
+	if runTime == nil {
+		return nil
+	}
+
+	var startTime time.Time
+	if jobState.Status == status.JobPendingDependencies {
+		_, dependencies, err := downloadJobDependencies(jobKey)
+		if err != nil {
+			return nil
+		}
+		estimatedStartTime := e.startTime(dependencies)
+		if estimatedStartTime == nil {
+			return nil
+		}
+		startTime = *estimatedStartTime
+	} else if runningTime, ok := jobState.LastUpdatedMap[status.JobRunning.String()]; ok {
+		startTime = runningTime
+	} else {
+		startTime = jobState.GetFirstCreated()
+	}
+
+	endTime := startTime.Add(*runTime)
+	if endTime.Before(e.now) {
+		// the job is taking longer than usual
+		endTime = e.now
+	}
+	return &endTime
+}
+
+// returns the time at which each of an api's max_concurrent_jobs slots is expected to become free, in ascending order (unknown times last)
+func (e *jobStartEstimator) slotFreeTimes(runningJobKeys []spec.JobKey, maxConcurrentJobs int64) []*time.Time {
+	freeTimes := make([]*time.Time, 0, len(runningJobKeys))
+	for _, jobKey := range runningJobKeys {
+		freeTimes = append(freeTimes, e.endTime(jobKey))
+	}
+	sortTimes(freeTimes)
+
+	// if more jobs are running than the limit allows (e.g. it was lowered), the earliest ones must complete before a slot becomes free
+	if excess := int64(len(freeTimes)) - maxConcurrentJobs; excess > 0 {
+		freeTimes = freeTimes[excess:]
+	}
+	for int64(len(freeTimes)) < maxConcurrentJobs {
+		freeTimes = append([]*time.Time{&e.now}, freeTimes...)
+	}
+
+	return freeTimes
+}
+
+// assigns a queued job to the slot which becomes free first, and returns the job's estimated start time
+func (e *jobStartEstimator) claimSlot(slotFreeTimes []*time.Time, jobKey spec.JobKey, readyTime *time.Time) *time.Time {
+	var startTime, endTime *time.Time
+	if readyTime != nil && slotFreeTimes[0] != nil {
+		start := *readyTime
+		if slotFreeTimes[0].After(start) {
+			start = *slotFreeTimes[0]
+		}
+		startTime = &start
+
+		if runTime := e.runTime(jobKey.Kind, jobKey.APIName); runTime != nil {
+			end := start.Add(*runTime)
+			endTime = &end
+		}
+	}
+
+	slotFreeTimes[0] = endTime
+	sortTimes(slotFreeTimes)
+
+	return startTime
+}
+
+func sortTimes(times []*time.Time) {
+	sort.SliceStable(times, func(i, j int) bool {
+		if times[i] == nil || times[j] == nil {
+			return times[j] == nil && times[i] != nil
+		}
+		return times[i].Before(*times[j])
+	})
+}
+
+// returns the average run time of the api's recently succeeded jobs, or nil if none of them have succeeded
+func (e *jobStartEstimator) runTime(kind userconfig.Kind, apiName string) *time.Duration {
+	apiKey := spec.JobKey{APIName: apiName, Kind: kind}
+	if runTime, ok := e.runTimes[apiKey]; ok {
+		return runTime
+	}
+
+	var runTime *time.Duration
+	jobStates, err := job.GetMostRecentlySubmittedJobStates(apiName, _jobRunTimeSampleSize, kind)
+	if err == nil {
+		var total time.Duration
+		var count int64
+		for _, jobState := range jobStates {
+			runningTime, ok := jobState.LastUpdatedMap[status.JobRunning.String()]
+			if jobState.Status != status.JobSucceeded || jobState.EndTime == nil || !ok {
+				continue
+			}
+			total += jobState.EndTime.Sub(runningTime)
+			count++
+		}
+		if count > 0 {
+			averageRunTime := total / time.Duration(count)
+			runTime = &averageRunTime
+		}
+	}
+
+	e.runTimes[apiKey] = runTime
+	return runTime
+}
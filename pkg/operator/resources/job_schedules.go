@@ -0,0 +1,301 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/config"
+	"github.com/cortexlabs/cortex/pkg/lib/cron"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/operator/resources/job/batchapi"
+	"github.com/cortexlabs/cortex/pkg/operator/resources/job/taskapi"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+)
+
+const JobSchedulesCronPeriod = 15 * time.Second
+
+// job schedules are modified by both RunJobSchedules and the schedule endpoints
+var _jobSchedulesMutex sync.Mutex
+
+func CreateBatchJobSchedule(apiName string, submission *schema.BatchJobSubmission) (*spec.JobSchedule, error) {
+	schedule := *submission.Schedule
+	if _, err := cron.ParseSchedule(schedule); err != nil {
+		return nil, errors.Wrap(err, schema.ScheduleKey)
+	}
+
+	scheduledSubmission := *submission
+	scheduledSubmission.Schedule = nil
+	if err := batchapi.ValidateScheduledJobSubmission(&scheduledSubmission); err != nil {
+		return nil, err
+	}
+
+	submissionBytes, err := json.Marshal(scheduledSubmission)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return createJobSchedule(apiName, userconfig.BatchAPIKind, schedule, submissionBytes)
+}
+
+func CreateTaskJobSchedule(apiName string, submission *schema.TaskJobSubmission) (*spec.JobSchedule, error) {
+	schedule := *submission.Schedule
+	if _, err := cron.ParseSchedule(schedule); err != nil {
+		return nil, errors.Wrap(err, schema.ScheduleKey)
+	}
+
+	scheduledSubmission := *submission
+	scheduledSubmission.Schedule = nil
+	if err := taskapi.ValidateScheduledJobSubmission(&scheduledSubmission); err != nil {
+		return nil, err
+	}
+
+	submissionBytes, err := json.Marshal(scheduledSubmission)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return createJobSchedule(apiName, userconfig.TaskAPIKind, schedule, submissionBytes)
+}
+
+func createJobSchedule(apiName string, kind userconfig.Kind, schedule string, submission json.RawMessage) (*spec.JobSchedule, error) {
+	_jobSchedulesMutex.Lock()
+	defer _jobSchedulesMutex.Unlock()
+
+	now := time.Now().UTC()
+	jobSchedule := spec.JobSchedule{
+		ID:                spec.MonotonicallyDecreasingID(),
+		APIName:           apiName,
+		Kind:              kind,
+		Schedule:          schedule,
+		Submission:        submission,
+		CreatedTime:       now,
+		LastScheduledTime: now, // the first job is submitted at the next scheduled time
+	}
+
+	if err := uploadJobSchedule(&jobSchedule); err != nil {
+		return nil, err
+	}
+
+	return &jobSchedule, nil
+}
+
+// ListJobSchedules returns the job schedules of the specified api, or of all apis if apiName is empty
+func ListJobSchedules(apiName string) ([]spec.JobSchedule, error) {
+	prefix := spec.JobSchedulesPrefix(config.ClusterConfig.ClusterUID)
+	if apiName != "" {
+		prefix = spec.JobSchedulesAPIPrefix(config.ClusterConfig.ClusterUID, apiName)
+	}
+
+	jobSchedules, err := downloadJobSchedules(prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(jobSchedules, func(i, j int) bool {
+		if jobSchedules[i].APIName != jobSchedules[j].APIName {
+			return jobSchedules[i].APIName < jobSchedules[j].APIName
+		}
+		return jobSchedules[i].CreatedTime.Before(jobSchedules[j].CreatedTime)
+	})
+
+	return jobSchedules, nil
+}
+
+func PauseJobSchedule(apiName string, scheduleID string) (*spec.JobSchedule, error) {
+	return updateJobSchedule(apiName, scheduleID, func(jobSchedule *spec.JobSchedule) {
+		jobSchedule.Paused = true
+	})
+}
+
+// ResumeJobSchedule resumes a paused job schedule; runs which were missed while the schedule was paused are skipped
+func ResumeJobSchedule(apiName string, scheduleID string) (*spec.JobSchedule, error) {
+	return updateJobSchedule(apiName, scheduleID, func(jobSchedule *spec.JobSchedule) {
+		if jobSchedule.Paused {
+			jobSchedule.Paused = false
+			jobSchedule.LastScheduledTime = time.Now().UTC()
+		}
+	})
+}
+
+func DeleteJobSchedule(apiName string, scheduleID string) (string, error) {
+	_jobSchedulesMutex.Lock()
+	defer _jobSchedulesMutex.Unlock()
+
+	key := spec.JobScheduleKey(config.ClusterConfig.ClusterUID, apiName, scheduleID)
+	if err := checkJobScheduleExists(key, apiName, scheduleID); err != nil {
+		return "", err
+	}
+
+	if err := config.AWS.DeleteS3File(config.ClusterConfig.Bucket, key); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("deleted job schedule %s (%s api)", scheduleID, apiName), nil
+}
+
+func deleteJobSchedules(apiName string) error {
+	_jobSchedulesMutex.Lock()
+	defer _jobSchedulesMutex.Unlock()
+
+	prefix := spec.JobSchedulesAPIPrefix(config.ClusterConfig.ClusterUID, apiName)
+	return config.AWS.DeleteS3Dir(config.ClusterConfig.Bucket, prefix, true)
+}
+
+func updateJobSchedule(apiName string, scheduleID string, update func(*spec.JobSchedule)) (*spec.JobSchedule, error) {
+	_jobSchedulesMutex.Lock()
+	defer _jobSchedulesMutex.Unlock()
+
+	key := spec.JobScheduleKey(config.ClusterConfig.ClusterUID, apiName, scheduleID)
+	if err := checkJobScheduleExists(key, apiName, scheduleID); err != nil {
+		return nil, err
+	}
+
+	var jobSchedule spec.JobSchedule
+	if err := config.AWS.ReadJSONFromS3(&jobSchedule, config.ClusterConfig.Bucket, key); err != nil {
+		return nil, err
+	}
+
+	update(&jobSchedule)
+
+	if err := uploadJobSchedule(&jobSchedule); err != nil {
+		return nil, err
+	}
+
+	return &jobSchedule, nil
+}
+
+// RunJobSchedules submits a job for each job schedule which has fired since it last submitted a job;
+// if the operator was down for multiple scheduled times, only one job is submitted
+func RunJobSchedules() error {
+	_jobSchedulesMutex.Lock()
+	defer _jobSchedulesMutex.Unlock()
+
+	jobSchedules, err := downloadJobSchedules(spec.JobSchedulesPrefix(config.ClusterConfig.ClusterUID))
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	var errs []error
+
+	for i := range jobSchedules {
+		jobSchedule := &jobSchedules[i]
+		if jobSchedule.Paused {
+			continue
+		}
+
+		cronSchedule, err := cron.ParseSchedule(jobSchedule.Schedule)
+		if err != nil {
+			errs, _ = errors.AddError(errs, err, jobSchedule.APIName, jobSchedule.ID)
+			continue
+		}
+
+		prev, ok := cronSchedule.Prev(now)
+		if !ok || !prev.After(jobSchedule.LastScheduledTime) {
+			continue
+		}
+
+		jobID, err := submitScheduledJob(jobSchedule)
+		jobSchedule.LastScheduledTime = prev
+		jobSchedule.LastJobID = jobID
+		jobSchedule.LastError = ""
+		if err != nil {
+			jobSchedule.LastError = errors.Message(err)
+			errs, _ = errors.AddError(errs, err, jobSchedule.APIName, jobSchedule.ID)
+		}
+
+		if err := uploadJobSchedule(jobSchedule); err != nil {
+			errs, _ = errors.AddError(errs, err, jobSchedule.APIName, jobSchedule.ID)
+		}
+	}
+
+	return errors.FirstError(errs...)
+}
+
+func submitScheduledJob(jobSchedule *spec.JobSchedule) (string, error) {
+	switch jobSchedule.Kind {
+	case userconfig.BatchAPIKind:
+		var submission schema.BatchJobSubmission
+		if err := json.Unmarshal(jobSchedule.Submission, &submission); err != nil {
+			return "", errors.WithStack(err)
+		}
+		jobSpec, err := batchapi.SubmitJob(jobSchedule.APIName, &submission)
+		if err != nil {
+			return "", err
+		}
+		return jobSpec.ID, nil
+	case userconfig.TaskAPIKind:
+		var submission schema.TaskJobSubmission
+		if err := json.Unmarshal(jobSchedule.Submission, &submission); err != nil {
+			return "", errors.WithStack(err)
+		}
+		jobSpec, err := taskapi.SubmitJob(jobSchedule.APIName, &submission)
+		if err != nil {
+			return "", err
+		}
+		return jobSpec.ID, nil
+	}
+
+	return "", errors.ErrorUnexpected("job schedules are not supported for kind", jobSchedule.Kind.String())
+}
+
+func checkJobScheduleExists(key string, apiName string, scheduleID string) error {
+	exists, err := config.AWS.IsS3File(config.ClusterConfig.Bucket, key)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrorJobScheduleNotFound(apiName, scheduleID)
+	}
+	return nil
+}
+
+func uploadJobSchedule(jobSchedule *spec.JobSchedule) error {
+	key := spec.JobScheduleKey(config.ClusterConfig.ClusterUID, jobSchedule.APIName, jobSchedule.ID)
+	return config.AWS.UploadJSONToS3(jobSchedule, config.ClusterConfig.Bucket, key)
+}
+
+func downloadJobSchedules(prefix string) ([]spec.JobSchedule, error) {
+	objects, err := config.AWS.ListS3Dir(config.ClusterConfig.Bucket, prefix, false, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	jobSchedules := make([]spec.JobSchedule, 0, len(objects))
+	for _, object := range objects {
+		if object.Key == nil || !strings.HasSuffix(*object.Key, ".json") {
+			continue
+		}
+
+		var jobSchedule spec.JobSchedule
+		if err := config.AWS.ReadJSONFromS3(&jobSchedule, config.ClusterConfig.Bucket, *object.Key); err != nil {
+			return nil, errors.Wrap(err, path.Base(*object.Key))
+		}
+		jobSchedules = append(jobSchedules, jobSchedule)
+	}
+
+	return jobSchedules, nil
+}
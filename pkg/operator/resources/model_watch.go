@@ -0,0 +1,128 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/config"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/sets/strset"
+	"github.com/cortexlabs/cortex/pkg/operator/operator"
+	"github.com/cortexlabs/cortex/pkg/operator/resources/asyncapi"
+	"github.com/cortexlabs/cortex/pkg/operator/resources/realtimeapi"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+)
+
+const ModelWatchCronPeriod = 15 * time.Second
+
+type watchedModel struct {
+	apiID       string
+	modelWatch  userconfig.ModelWatch
+	lastChecked time.Time
+}
+
+// keyed by api name; only accessed by WatchModels, which never runs concurrently with itself
+var _watchedModels = map[string]*watchedModel{}
+
+// WatchModels polls the model path of each api which has model_watch configured, and performs a rolling update of the api when a new model version is found
+func WatchModels() error {
+	virtualServices, err := config.K8sAllNamspaces.ListVirtualServicesWithLabelKeys("apiName")
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	watchedAPINames := strset.New()
+
+	for _, virtualService := range virtualServices {
+		apiKind := userconfig.KindFromString(virtualService.Labels["apiKind"])
+		if apiKind != userconfig.RealtimeAPIKind && apiKind != userconfig.AsyncAPIKind {
+			continue
+		}
+
+		deployedModelVersion, ok := virtualService.Annotations[userconfig.ModelVersionAnnotationKey]
+		if !ok {
+			continue
+		}
+
+		apiName := virtualService.Labels["apiName"]
+		apiID := virtualService.Labels["apiID"]
+		watchedAPINames.Add(apiName)
+
+		watched := _watchedModels[apiName]
+		if watched == nil || watched.apiID != apiID {
+			api, err := operator.DownloadAPISpec(apiName, apiID)
+			if err != nil {
+				errs, _ = errors.AddError(errs, err, apiName)
+				continue
+			}
+			if api.ModelWatch == nil {
+				continue
+			}
+			watched = &watchedModel{
+				apiID:       apiID,
+				modelWatch:  *api.ModelWatch,
+				lastChecked: time.Now(), // the model version was resolved when this api id was deployed
+			}
+			_watchedModels[apiName] = watched
+		}
+
+		if time.Since(watched.lastChecked) < watched.modelWatch.PollInterval {
+			continue
+		}
+		watched.lastChecked = time.Now()
+
+		latestModelVersion, err := spec.LatestModelVersion(watched.modelWatch.Path, config.AWS)
+		if err != nil {
+			errs, _ = errors.AddError(errs, err, apiName, userconfig.ModelWatchKey)
+			continue
+		}
+		if latestModelVersion == deployedModelVersion {
+			continue
+		}
+
+		var msg string
+		switch apiKind {
+		case userconfig.RealtimeAPIKind:
+			msg, err = realtimeapi.UpdateModelVersion(apiName, virtualService.Namespace, latestModelVersion)
+		case userconfig.AsyncAPIKind:
+			msg, err = asyncapi.UpdateModelVersion(apiName, virtualService.Namespace, latestModelVersion)
+		}
+		if err != nil {
+			switch errors.GetKind(err) {
+			case realtimeapi.ErrAPIUpdating, realtimeapi.ErrCanaryInProgress, asyncapi.ErrAPIUpdating:
+				// try again once the api is done updating
+				watched.lastChecked = time.Time{}
+			default:
+				errs, _ = errors.AddError(errs, err, apiName)
+			}
+			continue
+		}
+
+		operatorLogger.Infof("%s to model version %s", msg, latestModelVersion)
+	}
+
+	for apiName := range _watchedModels {
+		if !watchedAPINames.Has(apiName) {
+			delete(_watchedModels, apiName)
+		}
+	}
+
+	return errors.FirstError(errs...)
+}
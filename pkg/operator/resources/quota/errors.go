@@ -0,0 +1,51 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quota
+
+import (
+	"fmt"
+
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	"github.com/cortexlabs/cortex/pkg/types/clusterconfig"
+)
+
+const (
+	ErrQuotaExceeded = "quota.quota_exceeded"
+)
+
+func ErrorQuotaExceeded(quota *clusterconfig.Quota, limitKey string, currentUsage int64, requestedUsage int64) error {
+	var limit int64
+	switch limitKey {
+	case clusterconfig.MaxReplicasKey:
+		limit = *quota.MaxReplicas
+	case clusterconfig.MaxGPUsKey:
+		limit = *quota.MaxGPUs
+	case clusterconfig.MaxConcurrentJobsKey:
+		limit = *quota.MaxConcurrentJobs
+	}
+
+	selectorStr := "all apis"
+	if quota.Selector != "" {
+		selectorStr = "selector: " + quota.Selector
+	}
+
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrQuotaExceeded,
+		Message: fmt.Sprintf("quota %s (%s) would be exceeded: %s is %d, but this request would bring usage to %d (current usage: %d); run `cortex quota` to view the usage of each quota", s.UserStr(quota.Name), selectorStr, limitKey, limit, requestedUsage, currentUsage),
+	})
+}
@@ -0,0 +1,293 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quota
+
+import (
+	"context"
+	"sort"
+
+	"github.com/cortexlabs/cortex/pkg/config"
+	batch "github.com/cortexlabs/cortex/pkg/crds/apis/batch/v1alpha1"
+	"github.com/cortexlabs/cortex/pkg/lib/k8s"
+	"github.com/cortexlabs/cortex/pkg/lib/maps"
+	"github.com/cortexlabs/cortex/pkg/operator/operator"
+	"github.com/cortexlabs/cortex/pkg/operator/resources/job"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types/clusterconfig"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+	kcore "k8s.io/api/core/v1"
+	kmeta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	klabels "k8s.io/apimachinery/pkg/labels"
+)
+
+type apiUsage struct {
+	name     string
+	kind     userconfig.Kind
+	labels   klabels.Set
+	replicas int64
+	gpus     int64
+	jobs     int64
+}
+
+// GetUsage returns the current usage of each quota defined in the cluster configuration (including the quotas of projects)
+func GetUsage() ([]schema.QuotaUsage, error) {
+	quotas := config.ClusterConfig.AllQuotas()
+	if len(quotas) == 0 {
+		return []schema.QuotaUsage{}, nil
+	}
+
+	usages, err := listAPIUsages()
+	if err != nil {
+		return nil, err
+	}
+
+	quotaUsages := make([]schema.QuotaUsage, len(quotas))
+	for i, quota := range quotas {
+		selector, err := klabels.Parse(quota.Selector)
+		if err != nil {
+			return nil, err
+		}
+
+		quotaUsage := schema.QuotaUsage{
+			Name:              quota.Name,
+			Selector:          quota.Selector,
+			APIs:              []string{},
+			MaxReplicas:       quota.MaxReplicas,
+			MaxGPUs:           quota.MaxGPUs,
+			MaxConcurrentJobs: quota.MaxConcurrentJobs,
+		}
+		for _, usage := range usages {
+			if !selector.Matches(usage.labels) {
+				continue
+			}
+			quotaUsage.APIs = append(quotaUsage.APIs, usage.name)
+			quotaUsage.Replicas += usage.replicas
+			quotaUsage.GPUs += usage.gpus
+			quotaUsage.ConcurrentJobs += usage.jobs
+		}
+		sort.Strings(quotaUsage.APIs)
+
+		quotaUsages[i] = quotaUsage
+	}
+
+	return quotaUsages, nil
+}
+
+// ValidateAPI returns an error if deploying (or updating) the API would exceed the replica or GPU limit of a quota which selects it
+func ValidateAPI(api *userconfig.API) error {
+	quotas := config.ClusterConfig.AllQuotas()
+	if len(quotas) == 0 || api.Autoscaling == nil {
+		return nil
+	}
+
+	usages, err := listAPIUsages()
+	if err != nil {
+		return err
+	}
+
+	newUsage := apiUsage{
+		name:     api.Name,
+		kind:     api.Kind,
+		labels:   apiLabels(api.Name, api.Kind, api.Project, api.Labels),
+		replicas: int64(api.Autoscaling.MaxReplicas),
+		gpus:     int64(api.Autoscaling.MaxReplicas) * userconfig.GetPodComputeRequest(api).GPU,
+	}
+
+	for _, quota := range quotas {
+		selector, err := klabels.Parse(quota.Selector)
+		if err != nil {
+			return err
+		}
+		if !selector.Matches(newUsage.labels) {
+			continue
+		}
+
+		var replicas, gpus, prevReplicas, prevGPUs int64
+		for _, usage := range usages {
+			if usage.name == api.Name {
+				if selector.Matches(usage.labels) {
+					prevReplicas, prevGPUs = usage.replicas, usage.gpus
+				}
+				continue
+			}
+			if selector.Matches(usage.labels) {
+				replicas += usage.replicas
+				gpus += usage.gpus
+			}
+		}
+
+		// only reject changes which increase usage, so that APIs can still be scaled down if a quota is already exceeded
+		if quota.MaxReplicas != nil && newUsage.replicas > prevReplicas && replicas+newUsage.replicas > *quota.MaxReplicas {
+			return ErrorQuotaExceeded(quota, clusterconfig.MaxReplicasKey, replicas+prevReplicas, replicas+newUsage.replicas)
+		}
+		if quota.MaxGPUs != nil && newUsage.gpus > prevGPUs && gpus+newUsage.gpus > *quota.MaxGPUs {
+			return ErrorQuotaExceeded(quota, clusterconfig.MaxGPUsKey, gpus+prevGPUs, gpus+newUsage.gpus)
+		}
+	}
+
+	return nil
+}
+
+// ValidateJobSubmission returns an error if submitting a job with the given number of workers for the API would exceed the concurrent job
+// or GPU limit of a quota which selects it
+func ValidateJobSubmission(api *userconfig.API, workers int64) error {
+	quotas := config.ClusterConfig.AllQuotas()
+	if len(quotas) == 0 {
+		return nil
+	}
+
+	usages, err := listAPIUsages()
+	if err != nil {
+		return err
+	}
+
+	labels := apiLabels(api.Name, api.Kind, api.Project, api.Labels)
+	jobGPUs := workers * userconfig.GetPodComputeRequest(api).GPU
+
+	for _, quota := range quotas {
+		if quota.MaxConcurrentJobs == nil && (quota.MaxGPUs == nil || jobGPUs == 0) {
+			continue
+		}
+
+		selector, err := klabels.Parse(quota.Selector)
+		if err != nil {
+			return err
+		}
+		if !selector.Matches(labels) {
+			continue
+		}
+
+		var jobs, gpus int64
+		for _, usage := range usages {
+			if selector.Matches(usage.labels) {
+				jobs += usage.jobs
+				gpus += usage.gpus
+			}
+		}
+
+		if quota.MaxConcurrentJobs != nil && jobs+1 > *quota.MaxConcurrentJobs {
+			return ErrorQuotaExceeded(quota, clusterconfig.MaxConcurrentJobsKey, jobs, jobs+1)
+		}
+		if quota.MaxGPUs != nil && jobGPUs > 0 && gpus+jobGPUs > *quota.MaxGPUs {
+			return ErrorQuotaExceeded(quota, clusterconfig.MaxGPUsKey, gpus, gpus+jobGPUs)
+		}
+	}
+
+	return nil
+}
+
+func listAPIUsages() ([]apiUsage, error) {
+	virtualServices, err := config.K8sAllNamspaces.ListVirtualServicesWithLabelKeys("apiName")
+	if err != nil {
+		return nil, err
+	}
+
+	var apiNames, apiIDs []string
+	usages := make(map[string]*apiUsage, len(virtualServices))
+	for _, vs := range virtualServices {
+		usage := &apiUsage{
+			name:   vs.Labels["apiName"],
+			kind:   userconfig.KindFromString(vs.Labels["apiKind"]),
+			labels: klabels.Set(userconfig.LabelsWithProject(vs.Labels)),
+		}
+		usages[usage.name] = usage
+
+		if usage.kind == userconfig.RealtimeAPIKind || usage.kind == userconfig.AsyncAPIKind {
+			apiNames = append(apiNames, usage.name)
+			apiIDs = append(apiIDs, vs.Labels["apiID"])
+		}
+	}
+
+	apis, err := operator.DownloadAPISpecs(apiNames, apiIDs)
+	if err != nil {
+		return nil, err
+	}
+	for i := range apis {
+		api := apis[i]
+		if api.Autoscaling == nil {
+			continue
+		}
+		usage := usages[api.Name]
+		usage.replicas = int64(api.Autoscaling.MaxReplicas)
+		usage.gpus = usage.replicas * userconfig.GetPodComputeRequest(api.API).GPU
+	}
+
+	// the workers of batch and task jobs
+	jobPods, err := config.K8sAllNamspaces.ListPods(&kmeta.ListOptions{
+		LabelSelector: k8s.LabelExistsSelector("apiName", "jobID"),
+		FieldSelector: k8s.FieldSelectorNotIn("status.phase", []string{string(kcore.PodSucceeded), string(kcore.PodFailed)}),
+	})
+	if err != nil {
+		return nil, err
+	}
+	addJobPodUsages(usages, jobPods)
+
+	var batchJobList batch.BatchJobList
+	if err := config.K8s.List(context.Background(), &batchJobList); err != nil {
+		return nil, err
+	}
+	for _, batchJob := range batchJobList.Items {
+		if batchJob.Status.Status.IsCompleted() {
+			continue
+		}
+		if usage, ok := usages[batchJob.Spec.APIName]; ok {
+			usage.jobs++
+		}
+	}
+
+	taskJobKeys, err := job.ListAllInProgressJobKeys(userconfig.TaskAPIKind)
+	if err != nil {
+		return nil, err
+	}
+	for _, jobKey := range taskJobKeys {
+		if usage, ok := usages[jobKey.APIName]; ok {
+			usage.jobs++
+		}
+	}
+
+	apiUsages := make([]apiUsage, 0, len(usages))
+	for _, usage := range usages {
+		apiUsages = append(apiUsages, *usage)
+	}
+
+	return apiUsages, nil
+}
+
+// adds the GPUs which are requested by the (pending or running) pods of jobs to their APIs' usage
+func addJobPodUsages(usages map[string]*apiUsage, jobPods []kcore.Pod) {
+	for i := range jobPods {
+		usage, ok := usages[jobPods[i].Labels["apiName"]]
+		if !ok {
+			continue
+		}
+		_, _, gpus, _ := k8s.TotalPodCompute(&jobPods[i].Spec)
+		usage.gpus += gpus
+	}
+}
+
+// the labels which will be set on the API's virtual service
+func apiLabels(apiName string, kind userconfig.Kind, project string, labels map[string]string) klabels.Set {
+	if project == "" {
+		project = userconfig.DefaultProject // apis which were deployed before projects were introduced
+	}
+	return klabels.Set(maps.MergeStrMapsString(labels, map[string]string{
+		"apiName":            apiName,
+		"apiKind":            kind.String(),
+		"cortex.dev/api":     "true",
+		"cortex.dev/project": project,
+	}))
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quota
+
+import (
+	"testing"
+
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+	"github.com/stretchr/testify/require"
+	kcore "k8s.io/api/core/v1"
+	kresource "k8s.io/apimachinery/pkg/api/resource"
+	kmeta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func jobPod(apiName string, jobID string, gpusPerContainer ...int64) kcore.Pod {
+	pod := kcore.Pod{
+		ObjectMeta: kmeta.ObjectMeta{
+			Labels: map[string]string{
+				"apiName": apiName,
+				"jobID":   jobID,
+			},
+		},
+	}
+	for _, gpus := range gpusPerContainer {
+		pod.Spec.Containers = append(pod.Spec.Containers, kcore.Container{
+			Resources: kcore.ResourceRequirements{
+				Requests: kcore.ResourceList{
+					"nvidia.com/gpu": *kresource.NewQuantity(gpus, kresource.DecimalSI),
+				},
+			},
+		})
+	}
+	return pod
+}
+
+func TestAddJobPodUsages(t *testing.T) {
+	t.Parallel()
+
+	usages := map[string]*apiUsage{
+		"batch-api":    {name: "batch-api", kind: userconfig.BatchAPIKind},
+		"task-api":     {name: "task-api", kind: userconfig.TaskAPIKind},
+		"realtime-api": {name: "realtime-api", kind: userconfig.RealtimeAPIKind, replicas: 2, gpus: 2},
+	}
+
+	addJobPodUsages(usages, []kcore.Pod{
+		jobPod("batch-api", "job-a", 1),
+		jobPod("batch-api", "job-a", 1),
+		jobPod("batch-api", "job-b", 2, 1),
+		jobPod("task-api", "job-c", 4),
+		jobPod("task-api", "job-d"),
+		jobPod("deleted-api", "job-e", 8),
+	})
+
+	require.Equal(t, int64(5), usages["batch-api"].gpus)
+	require.Equal(t, int64(4), usages["task-api"].gpus)
+	require.Equal(t, int64(2), usages["realtime-api"].gpus)
+	require.Len(t, usages, 3)
+}
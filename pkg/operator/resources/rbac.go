@@ -0,0 +1,206 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/config"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	libjson "github.com/cortexlabs/cortex/pkg/lib/json"
+	"github.com/cortexlabs/cortex/pkg/lib/k8s"
+	"github.com/cortexlabs/cortex/pkg/lib/urls"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/rbac"
+)
+
+// CallerRole returns the role which the role bindings grant to the caller, and whether rbac is enabled (i.e. at least one role binding exists);
+// if rbac is not enabled, all callers which are authenticated with aws credentials have full access (and all other callers have no access)
+func CallerRole(caller rbac.Caller) (rbac.Role, bool, error) {
+	bindings, err := getRoleBindings()
+	if err != nil {
+		return "", false, err
+	}
+	if len(bindings) == 0 {
+		return "", false, nil
+	}
+	return rbac.RoleOf(bindings, caller), true, nil
+}
+
+func WhoAmI(caller rbac.Caller) (*schema.WhoAmIResponse, error) {
+	role, rbacEnabled, err := CallerRole(caller)
+	if err != nil {
+		return nil, err
+	}
+	if !rbacEnabled && caller.Kind == rbac.SubjectKindIAM {
+		role = rbac.RoleAdmin
+	}
+
+	return &schema.WhoAmIResponse{
+		Caller:      caller.String(),
+		Role:        role,
+		RBACEnabled: rbacEnabled,
+	}, nil
+}
+
+func CreateRoleBinding(name string, role rbac.Role, subject string, caller rbac.Caller) (*schema.RoleBinding, error) {
+	if err := urls.CheckDNS1123(name); err != nil {
+		return nil, err
+	}
+	if err := rbac.ValidateSubject(subject); err != nil {
+		return nil, err
+	}
+
+	configMap, err := config.K8s.GetConfigMap(rbac.ConfigMapName)
+	if err != nil {
+		return nil, err
+	}
+	if configMap != nil {
+		if _, ok := configMap.Data[name]; ok {
+			return nil, ErrorRoleBindingAlreadyExists(name)
+		}
+	}
+
+	binding := rbac.Binding{
+		Name:      name,
+		Subject:   subject,
+		Role:      role,
+		CreatedAt: time.Now().UTC(),
+	}
+	bindingBytes, err := libjson.Marshal(binding)
+	if err != nil {
+		return nil, err
+	}
+
+	if configMap == nil {
+		if err := validateCallerKeepsAdmin([]rbac.Binding{binding}, caller); err != nil {
+			return nil, err
+		}
+		_, err = config.K8s.CreateConfigMap(k8s.ConfigMap(&k8s.ConfigMapSpec{
+			Name: rbac.ConfigMapName,
+			Data: map[string]string{name: string(bindingBytes)},
+		}))
+	} else {
+		if configMap.Data == nil {
+			configMap.Data = map[string]string{}
+		}
+		configMap.Data[name] = string(bindingBytes)
+		var bindings []rbac.Binding
+		bindings, err = roleBindingsFromData(configMap.Data)
+		if err != nil {
+			return nil, err
+		}
+		if err := validateCallerKeepsAdmin(bindings, caller); err != nil {
+			return nil, err
+		}
+		_, err = config.K8s.UpdateConfigMap(configMap) // fails if the config map was modified concurrently
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	bindingInfo := roleBindingInfo(binding)
+	return &bindingInfo, nil
+}
+
+func ListRoleBindings() ([]schema.RoleBinding, error) {
+	bindings, err := getRoleBindings()
+	if err != nil {
+		return nil, err
+	}
+
+	bindingInfos := make([]schema.RoleBinding, len(bindings))
+	for i := range bindings {
+		bindingInfos[i] = roleBindingInfo(bindings[i])
+	}
+	return bindingInfos, nil
+}
+
+func DeleteRoleBinding(name string, caller rbac.Caller) (string, error) {
+	configMap, err := config.K8s.GetConfigMap(rbac.ConfigMapName)
+	if err != nil {
+		return "", err
+	}
+	if configMap == nil {
+		return "", ErrorRoleBindingNotFound(name)
+	}
+	if _, ok := configMap.Data[name]; !ok {
+		return "", ErrorRoleBindingNotFound(name)
+	}
+
+	delete(configMap.Data, name)
+	bindings, err := roleBindingsFromData(configMap.Data)
+	if err != nil {
+		return "", err
+	}
+	if err := validateCallerKeepsAdmin(bindings, caller); err != nil {
+		return "", err
+	}
+
+	if _, err := config.K8s.UpdateConfigMap(configMap); err != nil {
+		return "", err
+	}
+
+	if len(bindings) == 0 {
+		return fmt.Sprintf("deleted role binding %s; since no role bindings remain, rbac is disabled and all callers with aws credentials in the cluster's account have full access (api keys and users who are logged in with `cortex login` have no access until they are bound to a role)", name), nil
+	}
+	return fmt.Sprintf("deleted role binding %s", name), nil
+}
+
+// validateCallerKeepsAdmin prevents callers from locking themselves out (unless all role bindings are removed, which disables rbac)
+func validateCallerKeepsAdmin(bindings []rbac.Binding, caller rbac.Caller) error {
+	if len(bindings) > 0 && !rbac.RoleOf(bindings, caller).Includes(rbac.RoleAdmin) {
+		return ErrorRoleBindingLockout(caller.String())
+	}
+	return nil
+}
+
+func getRoleBindings() ([]rbac.Binding, error) {
+	configMapData, _, err := config.K8s.GetConfigMapData(rbac.ConfigMapName)
+	if err != nil {
+		return nil, err
+	}
+	return roleBindingsFromData(configMapData)
+}
+
+func roleBindingsFromData(configMapData map[string]string) ([]rbac.Binding, error) {
+	bindings := make([]rbac.Binding, 0, len(configMapData))
+	for name, bindingStr := range configMapData {
+		var binding rbac.Binding
+		if err := libjson.Unmarshal([]byte(bindingStr), &binding); err != nil {
+			return nil, errors.Wrap(err, rbac.ConfigMapName, name)
+		}
+		bindings = append(bindings, binding)
+	}
+
+	sort.Slice(bindings, func(i, j int) bool {
+		return bindings[i].Name < bindings[j].Name
+	})
+
+	return bindings, nil
+}
+
+func roleBindingInfo(binding rbac.Binding) schema.RoleBinding {
+	return schema.RoleBinding{
+		Name:      binding.Name,
+		Subject:   binding.Subject,
+		Role:      binding.Role,
+		CreatedAt: binding.CreatedAt,
+	}
+}
@@ -46,7 +46,12 @@ func generateDeploymentID() string {
 }
 
 func UpdateAPI(apiConfig *userconfig.API, force bool) (*spec.API, string, error) {
-	prevDeployment, prevService, prevVirtualService, err := getK8sResources(apiConfig.Name)
+	prevDeployment, prevService, prevVirtualService, err := getK8sResources(apiConfig.Name, apiConfig.Namespace())
+	if err != nil {
+		return nil, "", err
+	}
+
+	modelVersion, err := operator.LatestModelVersion(apiConfig)
 	if err != nil {
 		return nil, "", err
 	}
@@ -60,9 +65,13 @@ func UpdateAPI(apiConfig *userconfig.API, force bool) (*spec.API, string, error)
 			return nil, "", err
 		}
 		deploymentID = prevVirtualService.Labels["deploymentID"]
+		if modelVersion != prevVirtualService.Annotations[userconfig.ModelVersionAnnotationKey] {
+			deploymentID = generateDeploymentID()
+		}
 	}
 
 	api := spec.GetAPISpec(apiConfig, initialDeploymentTime, deploymentID, config.ClusterConfig.ClusterUID)
+	api.ModelVersion = modelVersion
 
 	if prevDeployment == nil {
 		if err := config.AWS.UploadJSONToS3(api, config.ClusterConfig.Bucket, api.Key); err != nil {
@@ -71,7 +80,7 @@ func UpdateAPI(apiConfig *userconfig.API, force bool) (*spec.API, string, error)
 
 		if err := applyK8sResources(api, prevDeployment, prevService, prevVirtualService); err != nil {
 			routines.RunWithPanicHandler(func() {
-				_ = deleteK8sResources(api.Name)
+				_ = deleteK8sResources(api.Name, api.Namespace())
 			})
 			return nil, "", err
 		}
@@ -79,6 +88,18 @@ func UpdateAPI(apiConfig *userconfig.API, force bool) (*spec.API, string, error)
 		return api, fmt.Sprintf("creating %s", api.Resource.UserString()), nil
 	}
 
+	prevCanaryDeployment, err := config.K8s.WithNamespace(api.Namespace()).GetDeployment(canaryK8sName(api.Name))
+	if err != nil {
+		return nil, "", err
+	}
+
+	if prevCanaryDeployment != nil || (apiConfig.Canary != nil && prevVirtualService.Labels["podID"] != api.PodID) {
+		if apiConfig.Canary == nil {
+			return nil, "", ErrorCanaryInProgress(api.Name)
+		}
+		return applyCanary(api, prevCanaryDeployment)
+	}
+
 	if prevVirtualService.Labels["specID"] != api.SpecID || prevVirtualService.Labels["deploymentID"] != api.DeploymentID {
 		isUpdating, err := isAPIUpdating(prevDeployment)
 		if err != nil {
@@ -109,8 +130,18 @@ func UpdateAPI(apiConfig *userconfig.API, force bool) (*spec.API, string, error)
 	return api, fmt.Sprintf("%s is up to date", api.Resource.UserString()), nil
 }
 
-func RefreshAPI(apiName string, force bool) (string, error) {
-	prevDeployment, prevService, prevVirtualService, err := getK8sResources(apiName)
+func RefreshAPI(apiName string, namespace string, force bool) (string, error) {
+	return refreshAPI(apiName, namespace, nil, force)
+}
+
+// UpdateModelVersion performs a rolling update of the api to the provided version of its model
+func UpdateModelVersion(apiName string, namespace string, modelVersion string) (string, error) {
+	return refreshAPI(apiName, namespace, &modelVersion, false)
+}
+
+// if modelVersion is nil, the currently deployed model version is kept
+func refreshAPI(apiName string, namespace string, modelVersion *string, force bool) (string, error) {
+	prevDeployment, prevService, prevVirtualService, err := getK8sResources(apiName, namespace)
 	if err != nil {
 		return "", err
 	} else if prevDeployment == nil || prevVirtualService == nil {
@@ -126,6 +157,14 @@ func RefreshAPI(apiName string, force bool) (string, error) {
 		return "", ErrorAPIUpdating(apiName)
 	}
 
+	canaryDeployment, err := config.K8s.WithNamespace(namespace).GetDeployment(canaryK8sName(apiName))
+	if err != nil {
+		return "", err
+	}
+	if canaryDeployment != nil {
+		return "", ErrorCanaryInProgress(apiName)
+	}
+
 	apiID, err := k8s.GetLabel(prevDeployment, "apiID")
 	if err != nil {
 		return "", err
@@ -141,7 +180,12 @@ func RefreshAPI(apiName string, force bool) (string, error) {
 		return "", err
 	}
 
+	prevModelVersion := api.ModelVersion
 	api = spec.GetAPISpec(api.API, initialDeploymentTime, generateDeploymentID(), config.ClusterConfig.ClusterUID)
+	api.ModelVersion = prevModelVersion
+	if modelVersion != nil {
+		api.ModelVersion = *modelVersion
+	}
 
 	if err := config.AWS.UploadJSONToS3(api, config.ClusterConfig.Bucket, api.Key); err != nil {
 		return "", errors.Wrap(err, "upload api spec")
@@ -154,10 +198,10 @@ func RefreshAPI(apiName string, force bool) (string, error) {
 	return fmt.Sprintf("updating %s", api.Resource.UserString()), nil
 }
 
-func DeleteAPI(apiName string, keepCache bool) error {
+func DeleteAPI(apiName string, namespace string, keepCache bool) error {
 	err := parallel.RunFirstErr(
 		func() error {
-			return deleteK8sResources(apiName)
+			return deleteK8sResources(apiName, namespace)
 		},
 		func() error {
 			if keepCache {
@@ -204,7 +248,7 @@ func GetAllAPIs(deployments []kapps.Deployment) ([]schema.APIResponse, error) {
 }
 
 func GetAPIByName(deployedResource *operator.DeployedResource) ([]schema.APIResponse, error) {
-	deployment, err := config.K8s.GetDeployment(workloads.K8sName(deployedResource.Name))
+	deployment, err := config.K8s.WithNamespace(deployedResource.Namespace()).GetDeployment(workloads.K8sName(deployedResource.Name))
 	if err != nil {
 		return nil, err
 	}
@@ -229,6 +273,11 @@ func GetAPIByName(deployedResource *operator.DeployedResource) ([]schema.APIResp
 		return nil, err
 	}
 
+	canary, err := getCanaryResponse(api.Name, deployedResource.VirtualService)
+	if err != nil {
+		return nil, err
+	}
+
 	dashboardURL := pointer.String(getDashboardURL(api.Name))
 
 	return []schema.APIResponse{
@@ -238,12 +287,15 @@ func GetAPIByName(deployedResource *operator.DeployedResource) ([]schema.APIResp
 			Status:       apiStatus,
 			Endpoint:     &apiEndpoint,
 			DashboardURL: dashboardURL,
+			Canary:       canary,
 		},
 	}, nil
 }
 
 func DescribeAPIByName(deployedResource *operator.DeployedResource) ([]schema.APIResponse, error) {
-	deployment, err := config.K8s.GetDeployment(workloads.K8sName(deployedResource.Name))
+	k8sClient := config.K8s.WithNamespace(deployedResource.Namespace())
+
+	deployment, err := k8sClient.GetDeployment(workloads.K8sName(deployedResource.Name))
 	if err != nil {
 		return nil, err
 	}
@@ -258,7 +310,7 @@ func DescribeAPIByName(deployedResource *operator.DeployedResource) ([]schema.AP
 		return nil, errors.ErrorUnexpected("unable to obtain metadata", deployedResource.Name)
 	}
 
-	pods, err := config.K8s.ListPodsByLabel("apiName", deployment.Labels["apiName"])
+	pods, err := k8sClient.ListPodsByLabel("apiName", deployment.Labels["apiName"])
 	if err != nil {
 		return nil, err
 	}
@@ -269,6 +321,16 @@ func DescribeAPIByName(deployedResource *operator.DeployedResource) ([]schema.AP
 		return nil, err
 	}
 
+	events, err := operator.GetAPIEvents(deployment, pods)
+	if err != nil {
+		return nil, err
+	}
+
+	imagePrepull, err := operator.GetImagePrepullStatus(deployedResource.Name, deployedResource.Namespace())
+	if err != nil {
+		return nil, err
+	}
+
 	dashboardURL := pointer.String(getDashboardURL(deployedResource.Name))
 
 	return []schema.APIResponse{
@@ -277,11 +339,15 @@ func DescribeAPIByName(deployedResource *operator.DeployedResource) ([]schema.AP
 			Status:       apiStatus,
 			Endpoint:     &apiEndpoint,
 			DashboardURL: dashboardURL,
+			Events:       events,
+			ImagePrepull: imagePrepull,
 		},
 	}, nil
 }
 
-func getK8sResources(apiName string) (*kapps.Deployment, *kcore.Service, *istioclientnetworking.VirtualService, error) {
+func getK8sResources(apiName string, namespace string) (*kapps.Deployment, *kcore.Service, *istioclientnetworking.VirtualService, error) {
+	k8sClient := config.K8s.WithNamespace(namespace)
+
 	var deployment *kapps.Deployment
 	var service *kcore.Service
 	var virtualService *istioclientnetworking.VirtualService
@@ -289,17 +355,17 @@ func getK8sResources(apiName string) (*kapps.Deployment, *kcore.Service, *istioc
 	err := parallel.RunFirstErr(
 		func() error {
 			var err error
-			deployment, err = config.K8s.GetDeployment(workloads.K8sName(apiName))
+			deployment, err = k8sClient.GetDeployment(workloads.K8sName(apiName))
 			return err
 		},
 		func() error {
 			var err error
-			service, err = config.K8s.GetService(workloads.K8sName(apiName))
+			service, err = k8sClient.GetService(workloads.K8sName(apiName))
 			return err
 		},
 		func() error {
 			var err error
-			virtualService, err = config.K8s.GetVirtualService(workloads.K8sName(apiName))
+			virtualService, err = k8sClient.GetVirtualService(workloads.K8sName(apiName))
 			return err
 		},
 	)
@@ -316,28 +382,35 @@ func applyK8sResources(api *spec.API, prevDeployment *kapps.Deployment, prevServ
 			return applyK8sService(api, prevService)
 		},
 		func() error {
-			return applyK8sVirtualService(api, prevVirtualService)
+			return applyK8sVirtualService(api, prevVirtualService, nil)
+		},
+		func() error {
+			return applyWarmPool(api)
+		},
+		func() error {
+			return operator.ApplyImagePrepull(api)
 		},
 	)
 }
 
 func applyK8sDeployment(api *spec.API, prevDeployment *kapps.Deployment) error {
+	k8sClient := config.K8s.WithNamespace(api.Namespace())
 	newDeployment := deploymentSpec(api, prevDeployment)
 
 	if prevDeployment == nil {
-		_, err := config.K8s.CreateDeployment(newDeployment)
+		_, err := k8sClient.CreateDeployment(newDeployment)
 		if err != nil {
 			return err
 		}
 	} else if prevDeployment.Status.ReadyReplicas == 0 {
 		// Delete deployment if it never became ready
-		_, _ = config.K8s.DeleteDeployment(workloads.K8sName(api.Name))
-		_, err := config.K8s.CreateDeployment(newDeployment)
+		_, _ = k8sClient.DeleteDeployment(workloads.K8sName(api.Name))
+		_, err := k8sClient.CreateDeployment(newDeployment)
 		if err != nil {
 			return err
 		}
 	} else {
-		_, err := config.K8s.UpdateDeployment(newDeployment)
+		_, err := k8sClient.UpdateDeployment(newDeployment)
 		if err != nil {
 			return err
 		}
@@ -347,43 +420,56 @@ func applyK8sDeployment(api *spec.API, prevDeployment *kapps.Deployment) error {
 }
 
 func applyK8sService(api *spec.API, prevService *kcore.Service) error {
+	k8sClient := config.K8s.WithNamespace(api.Namespace())
 	newService := serviceSpec(api)
 
 	if prevService == nil {
-		_, err := config.K8s.CreateService(newService)
+		_, err := k8sClient.CreateService(newService)
 		return err
 	}
 
-	_, err := config.K8s.UpdateService(prevService, newService)
+	_, err := k8sClient.UpdateService(prevService, newService)
 	return err
 }
 
-func applyK8sVirtualService(api *spec.API, prevVirtualService *istioclientnetworking.VirtualService) error {
-	newVirtualService := virtualServiceSpec(api)
+func applyK8sVirtualService(api *spec.API, prevVirtualService *istioclientnetworking.VirtualService, canary *canaryRoute) error {
+	k8sClient := config.K8s.WithNamespace(api.Namespace())
+	newVirtualService := virtualServiceSpec(api, canary)
 
 	if prevVirtualService == nil {
-		_, err := config.K8s.CreateVirtualService(newVirtualService)
+		_, err := k8sClient.CreateVirtualService(newVirtualService)
 		return err
 	}
 
-	_, err := config.K8s.UpdateVirtualService(prevVirtualService, newVirtualService)
+	_, err := k8sClient.UpdateVirtualService(prevVirtualService, newVirtualService)
 	return err
 }
 
-func deleteK8sResources(apiName string) error {
+func deleteK8sResources(apiName string, namespace string) error {
+	k8sClient := config.K8s.WithNamespace(namespace)
+
 	return parallel.RunFirstErr(
 		func() error {
-			_, err := config.K8s.DeleteDeployment(workloads.K8sName(apiName))
+			_, err := k8sClient.DeleteDeployment(workloads.K8sName(apiName))
 			return err
 		},
 		func() error {
-			_, err := config.K8s.DeleteService(workloads.K8sName(apiName))
+			_, err := k8sClient.DeleteService(workloads.K8sName(apiName))
 			return err
 		},
 		func() error {
-			_, err := config.K8s.DeleteVirtualService(workloads.K8sName(apiName))
+			_, err := k8sClient.DeleteVirtualService(workloads.K8sName(apiName))
 			return err
 		},
+		func() error {
+			return deleteCanaryK8sResources(apiName, namespace)
+		},
+		func() error {
+			return deleteWarmPoolK8sResources(apiName, namespace)
+		},
+		func() error {
+			return operator.DeleteImagePrepull(apiName, namespace)
+		},
 	)
 }
 
@@ -394,7 +480,7 @@ func deleteBucketResources(apiName string) error {
 
 // returns true if min_replicas are not ready and no updated replicas have errored
 func isAPIUpdating(deployment *kapps.Deployment) (bool, error) {
-	pods, err := config.K8s.ListPodsByLabel("apiName", deployment.Labels["apiName"])
+	pods, err := config.K8s.WithNamespace(deployment.Namespace).ListPodsByLabel("apiName", deployment.Labels["apiName"])
 	if err != nil {
 		return false, err
 	}
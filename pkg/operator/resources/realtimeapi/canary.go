@@ -0,0 +1,268 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package realtimeapi
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/config"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/k8s"
+	"github.com/cortexlabs/cortex/pkg/lib/parallel"
+	"github.com/cortexlabs/cortex/pkg/operator/operator"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
+	"github.com/cortexlabs/cortex/pkg/types/status"
+	"github.com/cortexlabs/cortex/pkg/workloads"
+	istioclientnetworking "istio.io/client-go/pkg/apis/networking/v1beta1"
+	kapps "k8s.io/api/apps/v1"
+)
+
+const RouteReadyCanariesCronPeriod = 10 * time.Second
+
+type canaryRoute struct {
+	APIID  string
+	Weight int32
+}
+
+func canaryK8sName(apiName string) string {
+	return workloads.K8sName(apiName) + "-canary"
+}
+
+// applyCanary deploys canaryAPI alongside the api's current version; traffic is routed to it by RouteReadyCanaries once it's ready
+func applyCanary(canaryAPI *spec.API, prevCanaryDeployment *kapps.Deployment) (*spec.API, string, error) {
+	if prevCanaryDeployment != nil && prevCanaryDeployment.Labels["specID"] == canaryAPI.SpecID {
+		return canaryAPI, fmt.Sprintf("the canary of %s is up to date", canaryAPI.Resource.UserString()), nil
+	}
+
+	k8sClient := config.K8s.WithNamespace(canaryAPI.Namespace())
+
+	prevCanaryService, err := k8sClient.GetService(canaryK8sName(canaryAPI.Name))
+	if err != nil {
+		return nil, "", err
+	}
+
+	if err := config.AWS.UploadJSONToS3(canaryAPI, config.ClusterConfig.Bucket, canaryAPI.Key); err != nil {
+		return nil, "", errors.Wrap(err, "upload api spec")
+	}
+
+	err = parallel.RunFirstErr(
+		func() error {
+			newDeployment := canaryDeploymentSpec(canaryAPI)
+			if prevCanaryDeployment == nil {
+				_, err := k8sClient.CreateDeployment(newDeployment)
+				return err
+			}
+			_, err := k8sClient.UpdateDeployment(newDeployment)
+			return err
+		},
+		func() error {
+			newService := canaryServiceSpec(canaryAPI)
+			if prevCanaryService == nil {
+				_, err := k8sClient.CreateService(newService)
+				return err
+			}
+			_, err := k8sClient.UpdateService(prevCanaryService, newService)
+			return err
+		},
+	)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return canaryAPI, fmt.Sprintf("deploying a canary for %s, which will receive %d%% of traffic once it's ready (run `cortex promote %s` to roll it out, or `cortex rollback %s` to remove it)",
+		canaryAPI.Resource.UserString(), canaryAPI.Canary.Weight, canaryAPI.Name, canaryAPI.Name), nil
+}
+
+// RouteReadyCanaries starts routing traffic to canaries once they have a ready replica
+func RouteReadyCanaries() error {
+	canaryDeployments, err := config.K8sAllNamspaces.ListDeploymentsWithLabelKeys("canaryOf")
+	if err != nil {
+		return err
+	}
+
+	for i := range canaryDeployments {
+		canaryDeployment := &canaryDeployments[i]
+		if canaryDeployment.Status.ReadyReplicas == 0 {
+			continue
+		}
+
+		apiName := canaryDeployment.Labels["canaryOf"]
+		virtualService, err := config.K8s.WithNamespace(canaryDeployment.Namespace).GetVirtualService(workloads.K8sName(apiName))
+		if err != nil {
+			return err
+		}
+		if virtualService == nil || virtualService.Labels["canaryAPIID"] == canaryDeployment.Labels["apiID"] {
+			continue
+		}
+
+		canaryAPI, err := operator.DownloadAPISpec(apiName, canaryDeployment.Labels["apiID"])
+		if err != nil {
+			return err
+		}
+		if canaryAPI.Canary == nil {
+			continue
+		}
+
+		if err := applyCanaryRoute(virtualService, &canaryRoute{APIID: canaryAPI.ID, Weight: canaryAPI.Canary.Weight}); err != nil {
+			return errors.Wrap(err, apiName)
+		}
+	}
+
+	return nil
+}
+
+// canary is nil to route all traffic to the api's current version
+func applyCanaryRoute(prevVirtualService *istioclientnetworking.VirtualService, canary *canaryRoute) error {
+	api, err := operator.DownloadAPISpec(prevVirtualService.Labels["apiName"], prevVirtualService.Labels["apiID"])
+	if err != nil {
+		return err
+	}
+	return applyK8sVirtualService(api, prevVirtualService, canary)
+}
+
+// PromoteCanary rolls the api out to its canary's version, or if weight is provided, adjusts the percentage of traffic which is routed to the canary
+func PromoteCanary(apiName string, namespace string, weight *int32) (string, error) {
+	prevDeployment, prevService, prevVirtualService, err := getK8sResources(apiName, namespace)
+	if err != nil {
+		return "", err
+	} else if prevDeployment == nil || prevVirtualService == nil {
+		return "", errors.ErrorUnexpected("unable to find deployment", apiName)
+	}
+
+	canaryDeployment, err := config.K8s.WithNamespace(namespace).GetDeployment(canaryK8sName(apiName))
+	if err != nil {
+		return "", err
+	}
+	if canaryDeployment == nil {
+		return "", ErrorNoCanary(apiName)
+	}
+
+	canaryAPIID, err := k8s.GetLabel(canaryDeployment, "apiID")
+	if err != nil {
+		return "", err
+	}
+
+	if weight != nil {
+		if *weight < 0 || *weight > 100 {
+			return "", ErrorInvalidCanaryWeight(*weight)
+		}
+		if canaryDeployment.Status.ReadyReplicas == 0 {
+			return "", ErrorCanaryNotReady(apiName)
+		}
+		if err := applyCanaryRoute(prevVirtualService, &canaryRoute{APIID: canaryAPIID, Weight: *weight}); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%d%% of the traffic to %s is now routed to its canary", *weight, apiName), nil
+	}
+
+	canaryAPI, err := operator.DownloadAPISpec(apiName, canaryAPIID)
+	if err != nil {
+		return "", err
+	}
+
+	// the api's deployment is updated to the canary's version, and all traffic is routed to it while it rolls out
+	if err := applyK8sResources(canaryAPI, prevDeployment, prevService, prevVirtualService); err != nil {
+		return "", err
+	}
+
+	if err := deleteCanaryK8sResources(apiName, namespace); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("promoting the canary of %s; %s is updating", apiName, apiName), nil
+}
+
+// RollbackCanary routes all traffic back to the api's current version and deletes the canary
+func RollbackCanary(apiName string, namespace string) (string, error) {
+	k8sClient := config.K8s.WithNamespace(namespace)
+
+	prevVirtualService, err := k8sClient.GetVirtualService(workloads.K8sName(apiName))
+	if err != nil {
+		return "", err
+	} else if prevVirtualService == nil {
+		return "", errors.ErrorUnexpected("unable to find virtual service", apiName)
+	}
+
+	canaryDeployment, err := k8sClient.GetDeployment(canaryK8sName(apiName))
+	if err != nil {
+		return "", err
+	}
+	if canaryDeployment == nil {
+		return "", ErrorNoCanary(apiName)
+	}
+
+	if prevVirtualService.Labels["canaryAPIID"] != "" {
+		if err := applyCanaryRoute(prevVirtualService, nil); err != nil {
+			return "", err
+		}
+	}
+
+	if err := deleteCanaryK8sResources(apiName, namespace); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("removed the canary of %s; all traffic is routed to the api's current version", apiName), nil
+}
+
+func HasCanary(apiName string, namespace string) (bool, error) {
+	canaryDeployment, err := config.K8s.WithNamespace(namespace).GetDeployment(canaryK8sName(apiName))
+	if err != nil {
+		return false, err
+	}
+	return canaryDeployment != nil, nil
+}
+
+func getCanaryResponse(apiName string, virtualService *istioclientnetworking.VirtualService) (*schema.CanaryResponse, error) {
+	canaryDeployment, err := config.K8s.WithNamespace(virtualService.Namespace).GetDeployment(canaryK8sName(apiName))
+	if err != nil {
+		return nil, err
+	}
+	if canaryDeployment == nil {
+		return nil, nil
+	}
+
+	var weight int32
+	if virtualService != nil && virtualService.Labels["canaryAPIID"] != "" && virtualService.Labels["canaryAPIID"] == canaryDeployment.Labels["apiID"] {
+		weight, err = k8s.ParseInt32Label(virtualService, "canaryWeight")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &schema.CanaryResponse{
+		APIID:  canaryDeployment.Labels["apiID"],
+		Weight: weight,
+		Status: status.FromDeployment(canaryDeployment),
+	}, nil
+}
+
+func deleteCanaryK8sResources(apiName string, namespace string) error {
+	k8sClient := config.K8s.WithNamespace(namespace)
+
+	return parallel.RunFirstErr(
+		func() error {
+			_, err := k8sClient.DeleteDeployment(canaryK8sName(apiName))
+			return err
+		},
+		func() error {
+			_, err := k8sClient.DeleteService(canaryK8sName(apiName))
+			return err
+		},
+	)
+}
@@ -23,7 +23,11 @@ import (
 )
 
 const (
-	ErrAPIUpdating = "realtimeapi.api_updating"
+	ErrAPIUpdating         = "realtimeapi.api_updating"
+	ErrCanaryInProgress    = "realtimeapi.canary_in_progress"
+	ErrNoCanary            = "realtimeapi.no_canary"
+	ErrCanaryNotReady      = "realtimeapi.canary_not_ready"
+	ErrInvalidCanaryWeight = "realtimeapi.invalid_canary_weight"
 )
 
 func ErrorAPIUpdating(apiName string) error {
@@ -32,3 +36,31 @@ func ErrorAPIUpdating(apiName string) error {
 		Message: fmt.Sprintf("%s is updating (override with --force)", apiName),
 	})
 }
+
+func ErrorCanaryInProgress(apiName string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrCanaryInProgress,
+		Message: fmt.Sprintf("%s has a canary deployed; run `cortex promote %s` to roll it out or `cortex rollback %s` to remove it before updating the api without a canary", apiName, apiName, apiName),
+	})
+}
+
+func ErrorNoCanary(apiName string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrNoCanary,
+		Message: fmt.Sprintf("%s does not have a canary deployed (a canary is deployed when an api which specifies the canary field is updated)", apiName),
+	})
+}
+
+func ErrorCanaryNotReady(apiName string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrCanaryNotReady,
+		Message: fmt.Sprintf("the canary of %s does not have any ready replicas yet", apiName),
+	})
+}
+
+func ErrorInvalidCanaryWeight(weight int32) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrInvalidCanaryWeight,
+		Message: fmt.Sprintf("invalid canary weight %d: the weight must be between 0 and 100 (inclusive)", weight),
+	})
+}
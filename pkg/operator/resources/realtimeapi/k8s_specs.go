@@ -18,12 +18,15 @@ package realtimeapi
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/cortexlabs/cortex/pkg/consts"
 	"github.com/cortexlabs/cortex/pkg/lib/k8s"
+	"github.com/cortexlabs/cortex/pkg/lib/maps"
 	"github.com/cortexlabs/cortex/pkg/lib/pointer"
 	s "github.com/cortexlabs/cortex/pkg/lib/strings"
 	"github.com/cortexlabs/cortex/pkg/types/spec"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
 	"github.com/cortexlabs/cortex/pkg/workloads"
 	istionetworking "istio.io/api/networking/v1beta1"
 	istioclientnetworking "istio.io/client-go/pkg/apis/networking/v1beta1"
@@ -50,6 +53,7 @@ func deploymentSpec(api *spec.API, prevDeployment *kapps.Deployment) *kapps.Depl
 			"deploymentID":          api.DeploymentID,
 			"podID":                 api.PodID,
 			"cortex.dev/api":        "true",
+			"cortex.dev/project":    api.Project,
 		},
 		Annotations: api.ToK8sAnnotations(),
 		Selector: map[string]string{
@@ -65,27 +69,35 @@ func deploymentSpec(api *spec.API, prevDeployment *kapps.Deployment) *kapps.Depl
 				"podID":                 api.PodID,
 				"cortex.dev/api":        "true",
 			},
-			Annotations: map[string]string{
+			Annotations: maps.MergeStrMapsString(map[string]string{
 				"traffic.sidecar.istio.io/excludeOutboundIPRanges": "0.0.0.0/0",
-			},
+			}, workloads.IAMRolePodAnnotations(api.Pod)),
 			K8sPodSpec: kcore.PodSpec{
 				RestartPolicy:                 "Always",
 				TerminationGracePeriodSeconds: pointer.Int64(_terminationGracePeriodSeconds),
+				InitContainers:                workloads.InitContainers(*api),
 				Containers:                    containers,
 				NodeSelector:                  workloads.NodeSelectors(),
 				Tolerations:                   workloads.GenerateResourceTolerations(),
 				Affinity:                      workloads.GenerateNodeAffinities(api.NodeGroups),
 				Volumes:                       volumes,
-				ServiceAccountName:            workloads.ServiceAccountName,
+				ServiceAccountName:            workloads.PodServiceAccountName(api.Name, api.Pod),
+				ImagePullSecrets:              workloads.ImagePullSecrets(api.Name, api.Pod),
 			},
 		},
 	})
 }
 
 func serviceSpec(api *spec.API) *kcore.Service {
+	// istio determines the protocol with which to reach the service from its port name
+	portName := "http"
+	if api.Pod.Protocol == userconfig.ProtocolGRPC {
+		portName = "grpc"
+	}
+
 	return k8s.Service(&k8s.ServiceSpec{
 		Name:        workloads.K8sName(api.Name),
-		PortName:    "http",
+		PortName:    portName,
 		Port:        consts.ProxyPortInt32,
 		TargetPort:  consts.ProxyPortInt32,
 		Annotations: api.ToK8sAnnotations(),
@@ -101,69 +113,145 @@ func serviceSpec(api *spec.API) *kcore.Service {
 	})
 }
 
-func virtualServiceSpec(api *spec.API) *istioclientnetworking.VirtualService {
+// canary is nil if the api doesn't have a canary deployed
+func virtualServiceSpec(api *spec.API, canary *canaryRoute) *istioclientnetworking.VirtualService {
 	var activatorWeight int32
 	if api.Autoscaling.InitReplicas == 0 {
 		activatorWeight = 100
 	}
 
-	return k8s.VirtualService(&k8s.VirtualServiceSpec{
-		Name:     workloads.K8sName(api.Name),
-		Gateways: []string{"apis-gateway"},
-		Destinations: []k8s.Destination{
-			{
-				ServiceName: workloads.K8sName(api.Name),
-				Weight:      100 - activatorWeight,
-				Port:        uint32(consts.ProxyPortInt32),
-				Headers: &istionetworking.Headers{
-					Response: &istionetworking.Headers_HeaderOperations{
-						Set: map[string]string{
-							consts.CortexOriginHeader: "api",
-						},
+	var canaryWeight int32
+	if canary != nil {
+		canaryWeight = canary.Weight
+	}
+
+	virtualServiceLabels := map[string]string{
+		"apiName":               api.Name,
+		"apiKind":               api.Kind.String(),
+		"apiID":                 api.ID,
+		"specID":                api.SpecID,
+		"initialDeploymentTime": s.Int64(api.InitialDeploymentTime),
+		"deploymentID":          api.DeploymentID,
+		"podID":                 api.PodID,
+		"cortex.dev/api":        "true",
+		"cortex.dev/project":    api.Project,
+	}
+
+	destinations := []k8s.Destination{
+		{
+			ServiceName: workloads.K8sName(api.Name),
+			Weight:      100 - activatorWeight - canaryWeight,
+			Port:        uint32(consts.ProxyPortInt32),
+			Headers: &istionetworking.Headers{
+				Response: &istionetworking.Headers_HeaderOperations{
+					Set: map[string]string{
+						consts.CortexOriginHeader: "api",
 					},
 				},
 			},
-			{
-				ServiceName: consts.ActivatorName,
-				Weight:      activatorWeight,
-				Port:        uint32(consts.ActivatorPortInt32),
-				Headers: &istionetworking.Headers{
-					Request: &istionetworking.Headers_HeaderOperations{
-						Set: map[string]string{
-							consts.CortexAPINameHeader: api.Name,
-							consts.CortexTargetServiceHeader: fmt.Sprintf(
-								"http://%s.%s:%d",
-								workloads.K8sName(api.Name),
-								consts.DefaultNamespace,
-								consts.ProxyPortInt32,
-							),
-						},
+		},
+		{
+			ServiceName: workloads.ClusterServiceHost(consts.ActivatorName),
+			Weight:      activatorWeight,
+			Port:        uint32(consts.ActivatorPortInt32),
+			Headers: &istionetworking.Headers{
+				Request: &istionetworking.Headers_HeaderOperations{
+					Set: map[string]string{
+						consts.CortexAPINameHeader: api.Name,
+						consts.CortexTargetServiceHeader: fmt.Sprintf(
+							"http://%s.%s:%d",
+							workloads.K8sName(api.Name),
+							api.Namespace(),
+							consts.ProxyPortInt32,
+						),
 					},
-					Response: &istionetworking.Headers_HeaderOperations{
-						Set: map[string]string{
-							consts.CortexOriginHeader: consts.ActivatorName,
-						},
+				},
+				Response: &istionetworking.Headers_HeaderOperations{
+					Set: map[string]string{
+						consts.CortexOriginHeader: consts.ActivatorName,
 					},
 				},
 			},
 		},
-		PrefixPath:  api.Networking.Endpoint,
-		Rewrite:     pointer.String("/"),
-		Retries:     pointer.Int32(0),
-		Annotations: api.ToK8sAnnotations(),
-		Labels: map[string]string{
-			"apiName":               api.Name,
-			"apiKind":               api.Kind.String(),
-			"apiID":                 api.ID,
-			"specID":                api.SpecID,
-			"initialDeploymentTime": s.Int64(api.InitialDeploymentTime),
-			"deploymentID":          api.DeploymentID,
-			"podID":                 api.PodID,
-			"cortex.dev/api":        "true",
-		},
+	}
+
+	if canary != nil {
+		destinations = append(destinations, k8s.Destination{
+			ServiceName: canaryK8sName(api.Name),
+			Weight:      canaryWeight,
+			Port:        uint32(consts.ProxyPortInt32),
+			Headers: &istionetworking.Headers{
+				Response: &istionetworking.Headers_HeaderOperations{
+					Set: map[string]string{
+						consts.CortexOriginHeader: "canary",
+					},
+				},
+			},
+		})
+		virtualServiceLabels["canaryAPIID"] = canary.APIID
+		virtualServiceLabels["canaryWeight"] = s.Int32(canary.Weight)
+	}
+
+	// grpc paths are determined by the grpc service, so grpc requests are routed by the api name in their metadata
+	var headerRoutes []k8s.HeaderRoute
+	if api.Pod.Protocol == userconfig.ProtocolGRPC {
+		headerRoutes = append(headerRoutes, k8s.HeaderRoute{
+			PrefixPath: "/",
+			Headers: map[string]string{
+				strings.ToLower(consts.CortexAPINameHeader): api.Name,
+			},
+			Destinations: destinations,
+		})
+	}
+
+	return k8s.VirtualService(&k8s.VirtualServiceSpec{
+		Name:         workloads.K8sName(api.Name),
+		Gateways:     []string{workloads.APIGatewayRef(api.Networking)},
+		Destinations: destinations,
+		HeaderRoutes: headerRoutes,
+		PrefixPath:   api.Networking.Endpoint,
+		Rewrite:      pointer.String("/"),
+		Retries:      pointer.Int32(0),
+		Annotations:  api.ToK8sAnnotations(),
+		Labels:       maps.MergeStrMapsString(api.Labels, virtualServiceLabels),
 	})
 }
 
+// the canary's deployment and service are selected by the "canaryOf" label (rather than "apiName"),
+// since the selectors of the api's deployment and service would otherwise also match the canary's pods
+func canaryDeploymentSpec(canaryAPI *spec.API) *kapps.Deployment {
+	deployment := deploymentSpec(canaryAPI, nil)
+
+	canaryLabels := func(labels map[string]string) map[string]string {
+		delete(labels, "apiName")
+		labels["canaryOf"] = canaryAPI.Name
+		return labels
+	}
+
+	deployment.Name = canaryK8sName(canaryAPI.Name)
+	deployment.Labels = canaryLabels(deployment.Labels)
+	deployment.Spec.Selector.MatchLabels = canaryLabels(deployment.Spec.Selector.MatchLabels)
+	deployment.Spec.Template.Labels = canaryLabels(deployment.Spec.Template.Labels)
+	// the canary isn't autoscaled
+	deployment.Spec.Replicas = pointer.Int32(canaryAPI.Autoscaling.MinReplicas)
+
+	return deployment
+}
+
+func canaryServiceSpec(canaryAPI *spec.API) *kcore.Service {
+	service := serviceSpec(canaryAPI)
+
+	service.Name = canaryK8sName(canaryAPI.Name)
+	delete(service.Labels, "apiName")
+	service.Labels["canaryOf"] = canaryAPI.Name
+	service.Spec.Selector = map[string]string{
+		"canaryOf": canaryAPI.Name,
+		"apiKind":  canaryAPI.Kind.String(),
+	}
+
+	return service
+}
+
 func getRequestedReplicasFromDeployment(api spec.API, deployment *kapps.Deployment) int32 {
 	requestedReplicas := api.Autoscaling.InitReplicas
 
@@ -0,0 +1,127 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package realtimeapi
+
+import (
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/config"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/pointer"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
+	"github.com/cortexlabs/cortex/pkg/workloads"
+	kapps "k8s.io/api/apps/v1"
+)
+
+const RemovePromotedWarmPodsCronPeriod = 10 * time.Second
+
+func warmPoolK8sName(apiName string) string {
+	return workloads.K8sName(apiName) + "-warm"
+}
+
+// warmPoolDeploymentSpec runs the api's pod without the apiName label, so that its replicas aren't selected by the api's service;
+// the autoscaler promotes ready warm replicas (by labeling them with the apiName label) when the api scales up
+func warmPoolDeploymentSpec(api *spec.API) *kapps.Deployment {
+	deployment := deploymentSpec(api, nil)
+
+	warmPoolLabels := func(labels map[string]string) map[string]string {
+		delete(labels, "apiName")
+		labels["warmPoolOf"] = api.Name
+		return labels
+	}
+
+	deployment.Name = warmPoolK8sName(api.Name)
+	deployment.Labels = warmPoolLabels(deployment.Labels)
+	deployment.Spec.Selector.MatchLabels = warmPoolLabels(deployment.Spec.Selector.MatchLabels)
+	deployment.Spec.Template.Labels = warmPoolLabels(deployment.Spec.Template.Labels)
+	// the warm pool isn't autoscaled
+	deployment.Spec.Replicas = pointer.Int32(api.WarmPool.Replicas)
+
+	return deployment
+}
+
+func applyWarmPool(api *spec.API) error {
+	k8sClient := config.K8s.WithNamespace(api.Namespace())
+
+	prevWarmPoolDeployment, err := k8sClient.GetDeployment(warmPoolK8sName(api.Name))
+	if err != nil {
+		return err
+	}
+
+	if api.WarmPool == nil {
+		if prevWarmPoolDeployment != nil {
+			return deleteWarmPoolK8sResources(api.Name, api.Namespace())
+		}
+		return nil
+	}
+
+	newDeployment := warmPoolDeploymentSpec(api)
+	if prevWarmPoolDeployment == nil {
+		_, err = k8sClient.CreateDeployment(newDeployment)
+		return err
+	}
+	_, err = k8sClient.UpdateDeployment(newDeployment)
+	return err
+}
+
+// RemovePromotedWarmPods deletes the warm replicas which were promoted to serve traffic once the api's deployment
+// has rolled out and all of its requested replicas are ready (the warm pool replaces promoted replicas on its own)
+func RemovePromotedWarmPods() error {
+	pods, err := config.K8sAllNamspaces.ListPodsWithLabelKeys("warmPoolPromoted")
+	if err != nil {
+		return err
+	}
+
+	deployments := map[string]*kapps.Deployment{}
+
+	var errs []error
+	for i := range pods {
+		pod := &pods[i]
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+
+		apiName := pod.Labels["apiName"]
+		deployment, ok := deployments[apiName]
+		if !ok {
+			deployment, err = config.K8s.WithNamespace(pod.Namespace).GetDeployment(workloads.K8sName(apiName))
+			if err != nil {
+				errs, _ = errors.AddError(errs, err, apiName)
+				continue
+			}
+			deployments[apiName] = deployment
+		}
+
+		if deployment != nil && deployment.Spec.Replicas != nil {
+			requested := *deployment.Spec.Replicas
+			if deployment.Status.UpdatedReplicas < requested || deployment.Status.ReadyReplicas < requested {
+				continue
+			}
+		}
+
+		if _, err := config.K8s.WithNamespace(pod.Namespace).DeletePod(pod.Name); err != nil {
+			errs, _ = errors.AddError(errs, err, apiName)
+		}
+	}
+
+	return errors.FirstError(errs...)
+}
+
+func deleteWarmPoolK8sResources(apiName string, namespace string) error {
+	_, err := config.K8s.WithNamespace(namespace).DeleteDeployment(warmPoolK8sName(apiName))
+	return err
+}
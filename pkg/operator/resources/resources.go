@@ -19,33 +19,40 @@ package resources
 import (
 	"context"
 	"fmt"
+	"sort"
 
 	"github.com/cortexlabs/cortex/pkg/config"
 	"github.com/cortexlabs/cortex/pkg/consts"
 	batch "github.com/cortexlabs/cortex/pkg/crds/apis/batch/v1alpha1"
 	"github.com/cortexlabs/cortex/pkg/lib/aws"
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/k8s"
 	"github.com/cortexlabs/cortex/pkg/lib/logging"
+	libmath "github.com/cortexlabs/cortex/pkg/lib/math"
 	"github.com/cortexlabs/cortex/pkg/lib/parallel"
 	"github.com/cortexlabs/cortex/pkg/lib/pointer"
+	"github.com/cortexlabs/cortex/pkg/lib/sets/strset"
+	"github.com/cortexlabs/cortex/pkg/lib/slices"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
 	"github.com/cortexlabs/cortex/pkg/lib/telemetry"
 	"github.com/cortexlabs/cortex/pkg/operator/lib/routines"
 	"github.com/cortexlabs/cortex/pkg/operator/operator"
 	"github.com/cortexlabs/cortex/pkg/operator/resources/asyncapi"
 	"github.com/cortexlabs/cortex/pkg/operator/resources/job/batchapi"
 	"github.com/cortexlabs/cortex/pkg/operator/resources/job/taskapi"
+	"github.com/cortexlabs/cortex/pkg/operator/resources/quota"
 	"github.com/cortexlabs/cortex/pkg/operator/resources/realtimeapi"
 	"github.com/cortexlabs/cortex/pkg/operator/resources/trafficsplitter"
 	"github.com/cortexlabs/cortex/pkg/operator/schema"
 	"github.com/cortexlabs/cortex/pkg/types/spec"
 	"github.com/cortexlabs/cortex/pkg/types/userconfig"
-	"github.com/cortexlabs/cortex/pkg/workloads"
 	istioclientnetworking "istio.io/client-go/pkg/apis/networking/v1beta1"
 	kapps "k8s.io/api/apps/v1"
 	kbatch "k8s.io/api/batch/v1"
 	kcore "k8s.io/api/core/v1"
 	kmeta "k8s.io/apimachinery/pkg/apis/meta/v1"
 	klabels "k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 var operatorLogger = logging.GetLogger()
@@ -65,7 +72,7 @@ func GetDeployedResourceByName(resourceName string) (*operator.DeployedResource,
 }
 
 func GetDeployedResourceByNameOrNil(resourceName string) (*operator.DeployedResource, error) {
-	virtualService, err := config.K8s.GetVirtualService(workloads.K8sName(resourceName))
+	virtualService, err := operator.GetAPIVirtualService(resourceName)
 	if err != nil {
 		return nil, err
 	}
@@ -83,13 +90,40 @@ func GetDeployedResourceByNameOrNil(resourceName string) (*operator.DeployedReso
 	}, nil
 }
 
-func Deploy(configFileName string, configBytes []byte, force bool) ([]schema.DeployResult, error) {
+// returns the id of each deployed api, keyed by api name
+func getDeployedAPIIDs() (map[string]string, error) {
+	virtualServices, err := config.K8sAllNamspaces.ListVirtualServicesWithLabelKeys("apiName")
+	if err != nil {
+		return nil, err
+	}
+
+	deployedAPIIDs := map[string]string{}
+	for _, virtualService := range virtualServices {
+		deployedAPIIDs[virtualService.Labels["apiName"]] = virtualService.Labels["apiID"]
+	}
+	return deployedAPIIDs, nil
+}
+
+// Deploy creates or updates the apis in the config file; the apis which don't specify a project are deployed to project (or to the default project if project is empty),
+// gitSource is set if the config file was read from a git reference, and skipScan disables the image scan check
+func Deploy(configFileName string, configBytes []byte, project string, force bool, skipScan bool, gitSource *userconfig.GitSource) ([]schema.DeployResult, error) {
 	apiConfigs, err := spec.ExtractAPIConfigs(configBytes, configFileName)
 	if err != nil {
 		return nil, err
 	}
 
-	err = ValidateClusterAPIs(apiConfigs)
+	setDefaultProject(apiConfigs, project)
+	for i := range apiConfigs {
+		apiConfigs[i].GitSource = gitSource
+	}
+
+	return deployAPIConfigs(apiConfigs, force, skipScan)
+}
+
+func deployAPIConfigs(apiConfigs []userconfig.API, force bool, skipScan bool) ([]schema.DeployResult, error) {
+	setDefaultProject(apiConfigs, "")
+
+	err := ValidateClusterAPIs(apiConfigs)
 	if err != nil {
 		err = errors.Append(err, fmt.Sprintf("\n\napi configuration schema can be found at https://docs.cortexlabs.com/v/%s/", consts.CortexVersionMinor))
 		return nil, err
@@ -102,11 +136,28 @@ func Deploy(configFileName string, configBytes []byte, force bool) ([]schema.Dep
 	for i := range apiConfigs {
 		apiConfig := apiConfigs[i]
 
+		var imageScans []schema.ImageScanSummary
+		if !skipScan && apiConfig.Kind != userconfig.TrafficSplitterKind {
+			// the digests are resolved before the images are scanned, and are then kept by UpdateAPI, so that the images which are deployed are the ones which were scanned
+			err = operator.ResolveImageDigests(&apiConfig)
+			if err == nil {
+				imageScans, err = operator.ScanAPIImages(&apiConfig)
+			}
+			if err != nil {
+				results = append(results, schema.DeployResult{
+					Error:      errors.ErrorStr(errors.Wrap(err, apiConfig.Identify())),
+					ImageScans: imageScans,
+				})
+				continue
+			}
+		}
+
 		api, msg, err := UpdateAPI(&apiConfig, force)
 
 		result := schema.DeployResult{
-			Message: msg,
-			API:     api,
+			Message:    msg,
+			API:        api,
+			ImageScans: imageScans,
 		}
 
 		if err != nil {
@@ -119,6 +170,18 @@ func Deploy(configFileName string, configBytes []byte, force bool) ([]schema.Dep
 	return results, nil
 }
 
+// sets the project of the apis which don't specify one (to the default project if project is empty)
+func setDefaultProject(apiConfigs []userconfig.API, project string) {
+	if project == "" {
+		project = userconfig.DefaultProject
+	}
+	for i := range apiConfigs {
+		if apiConfigs[i].Project == "" {
+			apiConfigs[i].Project = project
+		}
+	}
+}
+
 func UpdateAPI(apiConfig *userconfig.API, force bool) (*schema.APIResponse, string, error) {
 	deployedResource, err := GetDeployedResourceByNameOrNil(apiConfig.Name)
 	if err != nil {
@@ -129,8 +192,45 @@ func UpdateAPI(apiConfig *userconfig.API, force bool) (*schema.APIResponse, stri
 		return nil, "", ErrorCannotChangeKindOfDeployedAPI(apiConfig.Name, apiConfig.Kind, deployedResource.Kind)
 	}
 
+	if deployedResource != nil && deployedResource.Namespace() != apiConfig.Namespace() {
+		return nil, "", ErrorCannotChangeProjectOfDeployedAPI(apiConfig.Name, apiConfig.Project, userconfig.ProjectFromLabels(deployedResource.VirtualService.Labels))
+	}
+
 	telemetry.Event("operator.deploy", apiConfig.TelemetryEvent())
 
+	if err := quota.ValidateAPI(apiConfig); err != nil {
+		return nil, "", err
+	}
+
+	if err := operator.ApplyProjectNamespace(apiConfig.Project); err != nil {
+		return nil, "", err
+	}
+
+	if apiConfig.Kind != userconfig.TrafficSplitterKind {
+		// the policy is (re-)applied here so that it also applies to stored specs which are redeployed (e.g. by a rollback)
+		if err := operator.EnforcePodSecurityPolicy(apiConfig); err != nil {
+			return nil, "", errors.Wrap(err, apiConfig.Identify(), userconfig.PodKey, userconfig.SecurityKey)
+		}
+		if err := operator.ResolveImageDigests(apiConfig); err != nil {
+			return nil, "", err
+		}
+		if err := operator.VerifyImageSignatures(apiConfig); err != nil {
+			return nil, "", err
+		}
+		if err := operator.ApplyRegistryCredentials(apiConfig); err != nil {
+			return nil, "", err
+		}
+		if err := operator.ApplySecretEnv(apiConfig); err != nil {
+			return nil, "", err
+		}
+		if err := operator.ApplyIAMRoleServiceAccount(apiConfig); err != nil {
+			return nil, "", err
+		}
+		if err := operator.ApplyNetworkPolicy(apiConfig); err != nil {
+			return nil, "", err
+		}
+	}
+
 	var api *spec.API
 	var msg string
 	switch apiConfig.Kind {
@@ -174,73 +274,205 @@ func RefreshAPI(apiName string, force bool) (string, error) {
 
 	switch deployedResource.Kind {
 	case userconfig.RealtimeAPIKind:
-		return realtimeapi.RefreshAPI(apiName, force)
+		return realtimeapi.RefreshAPI(apiName, deployedResource.Namespace(), force)
 	case userconfig.AsyncAPIKind:
-		return asyncapi.RefreshAPI(apiName, force)
+		return asyncapi.RefreshAPI(apiName, deployedResource.Namespace(), force)
 	default:
 		return "", ErrorOperationIsOnlySupportedForKind(*deployedResource, userconfig.RealtimeAPIKind, userconfig.AsyncAPIKind)
 	}
 }
 
-func DeleteAPI(apiName string, keepCache bool) (*schema.DeleteResponse, error) {
+func PromoteAPI(apiName string, weight *int32) (string, error) {
+	deployedResource, err := GetDeployedResourceByName(apiName)
+	if err != nil {
+		return "", err
+	}
+
+	switch deployedResource.Kind {
+	case userconfig.RealtimeAPIKind:
+		return realtimeapi.PromoteCanary(apiName, deployedResource.Namespace(), weight)
+	default:
+		return "", ErrorOperationIsOnlySupportedForKind(*deployedResource, userconfig.RealtimeAPIKind)
+	}
+}
+
+func RedriveAPI(apiName string) (string, error) {
+	deployedResource, err := GetDeployedResourceByName(apiName)
+	if err != nil {
+		return "", err
+	}
+
+	switch deployedResource.Kind {
+	case userconfig.AsyncAPIKind:
+		return asyncapi.RedriveDeadLetterQueue(apiName, deployedResource.Namespace())
+	default:
+		return "", ErrorOperationIsOnlySupportedForKind(*deployedResource, userconfig.AsyncAPIKind)
+	}
+}
+
+// RollbackAPI removes the api's canary if one is deployed and toVersion is empty; otherwise it redeploys a previously stored spec of the api.
+// toVersion may be a version number (as shown in the api's version history) or an api id; if empty, the most recent version with a different configuration is used
+func RollbackAPI(apiName string, toVersion string, force bool) (string, error) {
+	deployedResource, err := GetDeployedResourceByName(apiName)
+	if err != nil {
+		return "", err
+	}
+
+	if deployedResource.Kind == userconfig.RealtimeAPIKind {
+		hasCanary, err := realtimeapi.HasCanary(apiName, deployedResource.Namespace())
+		if err != nil {
+			return "", err
+		}
+		if hasCanary {
+			if toVersion != "" {
+				return "", ErrorCannotRollbackToVersionWithCanary(apiName)
+			}
+			return realtimeapi.RollbackCanary(apiName, deployedResource.Namespace())
+		}
+	}
+
+	targetAPIID, err := getRollbackTargetAPIID(deployedResource, toVersion)
+	if err != nil {
+		return "", err
+	}
+
+	apiSpec, err := operator.DownloadAPISpec(apiName, targetAPIID)
+	if err != nil {
+		if aws.IsGenericNotFoundErr(err) {
+			return "", ErrorAPIIDNotFound(apiName, targetAPIID)
+		}
+		return "", err
+	}
+
+	// canaries are not restored; the stored spec is redeployed as the api's current version (its images are redeployed by the digests which were resolved when it was deployed)
+	apiConfig := *apiSpec.API
+	apiConfig.Canary = nil
+
+	_, msg, err := UpdateAPI(&apiConfig, force)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("rolling back %s to api id %s; %s", apiName, targetAPIID, msg), nil
+}
+
+func getRollbackTargetAPIID(deployedResource *operator.DeployedResource, toVersion string) (string, error) {
+	apiName := deployedResource.Name
+
+	apiVersions, err := getPastAPIDeploys(apiName)
+	if err != nil {
+		return "", err
+	}
+
+	if toVersion != "" {
+		if version, ok := s.ParseInt(toVersion); ok {
+			if version < 1 || version > len(apiVersions) {
+				return "", ErrorAPIVersionNotFound(apiName, version, len(apiVersions))
+			}
+			return apiVersions[version-1].APIID, nil
+		}
+		return toVersion, nil
+	}
+
+	currentAPIID := deployedResource.ID()
+	currentSpecID := spec.SpecIDFromAPIID(currentAPIID)
+
+	foundCurrent := false
+	for _, apiVersion := range apiVersions {
+		if apiVersion.APIID == currentAPIID {
+			foundCurrent = true
+			continue
+		}
+		if foundCurrent && spec.SpecIDFromAPIID(apiVersion.APIID) != currentSpecID {
+			return apiVersion.APIID, nil
+		}
+	}
+
+	return "", ErrorNoPreviousAPIVersion(apiName)
+}
+
+// DeleteAPI deletes the api; keepCache keeps the api's files in the cluster's bucket, and keepVolumes keeps the api's model in the nodes' model caches
+// (where it's evicted once the space is needed), rather than running a job on each node to remove it
+func DeleteAPI(apiName string, keepCache bool, keepVolumes bool) (*schema.DeleteResponse, error) {
 	deployedResource, err := GetDeployedResourceByNameOrNil(apiName)
 	if err != nil {
 		return nil, err
 	}
 	if deployedResource == nil {
-		// Delete anyways just to be sure everything is deleted
+		// Delete anyways just to be sure everything is deleted (in each namespace, since the api's project is unknown)
 		routines.RunWithPanicHandler(func() {
-			err := parallel.RunFirstErr(
-				func() error {
-					return realtimeapi.DeleteAPI(apiName, keepCache)
-				},
-				func() error {
-					return batchapi.DeleteAPI(apiName, keepCache)
-				},
-				func() error {
-					return trafficsplitter.DeleteAPI(apiName, keepCache)
-				},
-				func() error {
-					return taskapi.DeleteAPI(apiName, keepCache)
-				},
+			namespaces, err := operator.ListAPINamespaces()
+			if err != nil {
+				telemetry.Error(err)
+				return
+			}
+			fns := []func() error{
 				func() error {
-					return asyncapi.DeleteAPI(apiName, keepCache)
+					return deleteJobSchedules(apiName)
 				},
-			)
-			if err != nil {
+			}
+			for _, namespace := range namespaces {
+				namespace := namespace
+				fns = append(fns, func() error {
+					return deleteAPIResources(apiName, namespace, keepCache)
+				})
+			}
+			if err := parallel.RunFirstErr(fns[0], fns[1:]...); err != nil {
 				telemetry.Error(err)
 			}
 		})
 		return nil, ErrorAPINotDeployed(apiName)
 	}
 
-	switch deployedResource.Kind {
-	case userconfig.RealtimeAPIKind:
-		err := checkIfUsedByTrafficSplitter(apiName)
-		if err != nil {
+	if deployedResource.Kind == userconfig.RealtimeAPIKind {
+		if err := checkIfUsedByTrafficSplitter(apiName); err != nil {
 			return nil, err
 		}
-		err = realtimeapi.DeleteAPI(apiName, keepCache)
+	}
+
+	if removesCachedModel(deployedResource.Kind, keepVolumes) {
+		// best effort; the spec is read before the api's bucket resources are deleted
+		apiSpec, err := operator.DownloadAPISpec(apiName, deployedResource.ID())
+		if err != nil {
+			telemetry.Error(err)
+		} else {
+			routines.RunWithPanicHandler(func() {
+				if err := operator.RemoveCachedModel(apiSpec); err != nil {
+					telemetry.Error(err)
+				}
+			})
+		}
+	}
+
+	switch deployedResource.Kind {
+	case userconfig.RealtimeAPIKind:
+		err = realtimeapi.DeleteAPI(apiName, deployedResource.Namespace(), keepCache)
 		if err != nil {
 			return nil, err
 		}
 	case userconfig.TrafficSplitterKind:
-		err := trafficsplitter.DeleteAPI(apiName, keepCache)
+		err := trafficsplitter.DeleteAPI(apiName, deployedResource.Namespace(), keepCache)
 		if err != nil {
 			return nil, err
 		}
 	case userconfig.BatchAPIKind:
-		err := batchapi.DeleteAPI(apiName, keepCache)
+		err := batchapi.DeleteAPI(apiName, deployedResource.Namespace(), keepCache)
 		if err != nil {
 			return nil, err
 		}
+		if err := deleteJobSchedules(apiName); err != nil {
+			return nil, err
+		}
 	case userconfig.TaskAPIKind:
-		err := taskapi.DeleteAPI(apiName, keepCache)
+		err := taskapi.DeleteAPI(apiName, deployedResource.Namespace(), keepCache)
 		if err != nil {
 			return nil, err
 		}
+		if err := deleteJobSchedules(apiName); err != nil {
+			return nil, err
+		}
 	case userconfig.AsyncAPIKind:
-		err = asyncapi.DeleteAPI(apiName, keepCache)
+		err = asyncapi.DeleteAPI(apiName, deployedResource.Namespace(), keepCache)
 		if err != nil {
 			return nil, err
 		}
@@ -248,32 +480,165 @@ func DeleteAPI(apiName string, keepCache bool) (*schema.DeleteResponse, error) {
 		return nil, ErrorOperationIsOnlySupportedForKind(*deployedResource, userconfig.RealtimeAPIKind, userconfig.AsyncAPIKind, userconfig.BatchAPIKind, userconfig.TrafficSplitterKind) // unexpected
 	}
 
+	if err := deleteAPIAccessResources(apiName, deployedResource.Namespace()); err != nil {
+		return nil, err
+	}
+
 	return &schema.DeleteResponse{
 		Message: fmt.Sprintf("deleting %s", apiName),
 	}, nil
 }
 
-func GetAPIs() ([]schema.APIResponse, error) {
+// returns whether deleting an api of the kind runs the jobs which remove its model from the nodes' model caches
+func removesCachedModel(kind userconfig.Kind, keepVolumes bool) bool {
+	return !keepVolumes && (kind == userconfig.RealtimeAPIKind || kind == userconfig.AsyncAPIKind)
+}
+
+// deletes all of the resources which an api of any kind may have in the namespace
+func deleteAPIResources(apiName string, namespace string, keepCache bool) error {
+	return parallel.RunFirstErr(
+		func() error {
+			return realtimeapi.DeleteAPI(apiName, namespace, keepCache)
+		},
+		func() error {
+			return batchapi.DeleteAPI(apiName, namespace, keepCache)
+		},
+		func() error {
+			return trafficsplitter.DeleteAPI(apiName, namespace, keepCache)
+		},
+		func() error {
+			return taskapi.DeleteAPI(apiName, namespace, keepCache)
+		},
+		func() error {
+			return asyncapi.DeleteAPI(apiName, namespace, keepCache)
+		},
+		func() error {
+			return deleteAPIAccessResources(apiName, namespace)
+		},
+	)
+}
+
+// deletes the api's image pull secret, secret env, iam role service account, and network policy
+func deleteAPIAccessResources(apiName string, namespace string) error {
+	return parallel.RunFirstErr(
+		func() error {
+			return operator.DeleteRegistryCredentials(apiName, namespace)
+		},
+		func() error {
+			return operator.DeleteSecretEnv(apiName, namespace)
+		},
+		func() error {
+			return operator.DeleteIAMRoleServiceAccount(apiName, namespace)
+		},
+		func() error {
+			return operator.DeleteNetworkPolicy(apiName, namespace)
+		},
+	)
+}
+
+// DeleteAPIs deletes all apis which are in the project (if not empty) and match the kind (if not unknown) and label selector (if not empty);
+// if apiNames is not empty, only the matching apis which it lists are deleted (e.g. the apis which the user confirmed after a dry run).
+// traffic splitters are deleted first so that the apis which they reference can be deleted in the same request
+func DeleteAPIs(project string, kind userconfig.Kind, selector string, apiNames []string, keepCache bool, keepVolumes bool, dryRun bool) (*schema.BulkDeleteResponse, error) {
+	labelSelector, err := klabels.Parse(selector)
+	if err != nil {
+		return nil, ErrorInvalidLabelSelector(selector, err)
+	}
+
+	virtualServices, err := projectK8sClient(project).ListVirtualServicesWithLabelKeys("apiName")
+	if err != nil {
+		return nil, err
+	}
+
+	var matchedResources []userconfig.Resource
+	for _, virtualService := range virtualServices {
+		resource := userconfig.Resource{
+			Name: virtualService.Labels["apiName"],
+			Kind: userconfig.KindFromString(virtualService.Labels["apiKind"]),
+		}
+		if kind != userconfig.UnknownKind && resource.Kind != kind {
+			continue
+		}
+		if len(apiNames) > 0 && !slices.HasString(apiNames, resource.Name) {
+			continue
+		}
+		if !labelSelector.Matches(klabels.Set(userconfig.LabelsWithProject(virtualService.Labels))) {
+			continue
+		}
+		matchedResources = append(matchedResources, resource)
+	}
+
+	sort.Slice(matchedResources, func(i, j int) bool {
+		iIsTrafficSplitter := matchedResources[i].Kind == userconfig.TrafficSplitterKind
+		jIsTrafficSplitter := matchedResources[j].Kind == userconfig.TrafficSplitterKind
+		if iIsTrafficSplitter != jIsTrafficSplitter {
+			return iIsTrafficSplitter
+		}
+		return matchedResources[i].Name < matchedResources[j].Name
+	})
+
+	response := schema.BulkDeleteResponse{
+		DryRun: dryRun,
+		APIs:   make([]schema.BulkDeleteResult, 0, len(matchedResources)),
+	}
+
+	for _, resource := range matchedResources {
+		result := schema.BulkDeleteResult{
+			Name: resource.Name,
+			Kind: resource.Kind,
+		}
+
+		if dryRun {
+			result.Message = fmt.Sprintf("%s would be deleted", resource.Name)
+		} else {
+			deleteResponse, err := DeleteAPI(resource.Name, keepCache, keepVolumes)
+			if err != nil {
+				result.Error = errors.Message(err)
+			} else {
+				result.Message = deleteResponse.Message
+			}
+		}
+
+		response.APIs = append(response.APIs, result)
+	}
+
+	return &response, nil
+}
+
+// GetAPIs returns the apis which are in the project (if not empty) and match the label selector (if not empty) ordered by kind and name,
+// along with the total number of matching apis; if limit is positive, only the apis in [offset, offset+limit) of that ordering are returned
+func GetAPIs(project string, selector string, offset int, limit int) ([]schema.APIResponse, int, error) {
+	labelSelector, err := klabels.Parse(selector)
+	if err != nil {
+		return nil, 0, ErrorInvalidLabelSelector(selector, err)
+	}
+
+	k8sClient := projectK8sClient(project)
+	var batchJobListOpts []client.ListOption
+	if project != "" {
+		batchJobListOpts = append(batchJobListOpts, client.InNamespace(userconfig.ProjectNamespace(project)))
+	}
+
 	var deployments []kapps.Deployment
 	var k8sTaskJobs []kbatch.Job
 	var taskAPIPods []kcore.Pod
 	var virtualServices []*istioclientnetworking.VirtualService
 	var batchJobList batch.BatchJobList
 
-	err := parallel.RunFirstErr(
+	err = parallel.RunFirstErr(
 		func() error {
 			var err error
-			deployments, err = config.K8s.ListDeploymentsWithLabelKeys("apiName")
+			deployments, err = k8sClient.ListDeploymentsWithLabelKeys("apiName")
 			return err
 		},
 		func() error {
 			var err error
-			taskAPIPods, err = config.K8s.ListPodsByLabel("apiKind", userconfig.TaskAPIKind.String())
+			taskAPIPods, err = k8sClient.ListPodsByLabel("apiKind", userconfig.TaskAPIKind.String())
 			return err
 		},
 		func() error {
 			var err error
-			k8sTaskJobs, err = config.K8s.ListJobs(
+			k8sTaskJobs, err = k8sClient.ListJobs(
 				&kmeta.ListOptions{
 					LabelSelector: klabels.SelectorFromSet(
 						map[string]string{
@@ -286,15 +651,27 @@ func GetAPIs() ([]schema.APIResponse, error) {
 		},
 		func() error {
 			var err error
-			virtualServices, err = config.K8s.ListVirtualServicesWithLabelKeys("apiName")
+			virtualServices, err = k8sClient.ListVirtualServicesWithLabelKeys("apiName")
 			return err
 		},
 		func() error {
-			return config.K8s.List(context.Background(), &batchJobList)
+			return config.K8s.List(context.Background(), &batchJobList, batchJobListOpts...)
 		},
 	)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
+	}
+
+	// every api has a virtual service with the api's labels, so it is used to determine which apis match the selector
+	if !labelSelector.Empty() {
+		var matchedVirtualServices []*istioclientnetworking.VirtualService
+		for _, vs := range virtualServices {
+			if labelSelector.Matches(klabels.Set(userconfig.LabelsWithProject(vs.Labels))) {
+				matchedVirtualServices = append(matchedVirtualServices, vs)
+			}
+		}
+		virtualServices = matchedVirtualServices
+		deployments = filterDeploymentsByAPIName(deployments, strset.FromSlice(virtualServiceAPINames(virtualServices)))
 	}
 
 	var realtimeAPIDeployments []kapps.Deployment
@@ -323,33 +700,56 @@ func GetAPIs() ([]schema.APIResponse, error) {
 		}
 	}
 
+	// the apis are ordered in the same way as the response (by kind, and then by name)
+	var apiNames []string
+	for _, kindAPINames := range [][]string{
+		deploymentAPINames(realtimeAPIDeployments),
+		virtualServiceAPINames(batchAPIVirtualServices),
+		virtualServiceAPINames(taskAPIVirtualServices),
+		deploymentAPINames(asyncAPIDeployments),
+		virtualServiceAPINames(trafficSplitterVirtualServices),
+	} {
+		sort.Strings(kindAPINames)
+		apiNames = append(apiNames, kindAPINames...)
+	}
+	total := len(apiNames)
+
+	if limit > 0 {
+		pageAPINames := strset.FromSlice(apiNames[libmath.MinInt(offset, total):libmath.MinInt(offset+limit, total)])
+		realtimeAPIDeployments = filterDeploymentsByAPIName(realtimeAPIDeployments, pageAPINames)
+		asyncAPIDeployments = filterDeploymentsByAPIName(asyncAPIDeployments, pageAPINames)
+		batchAPIVirtualServices = filterVirtualServicesByAPIName(batchAPIVirtualServices, pageAPINames)
+		taskAPIVirtualServices = filterVirtualServicesByAPIName(taskAPIVirtualServices, pageAPINames)
+		trafficSplitterVirtualServices = filterVirtualServicesByAPIName(trafficSplitterVirtualServices, pageAPINames)
+	}
+
 	realtimeAPIList, err := realtimeapi.GetAllAPIs(realtimeAPIDeployments)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
 	var taskAPIList []schema.APIResponse
 	taskAPIList, err = taskapi.GetAllAPIs(taskAPIVirtualServices, k8sTaskJobs, taskAPIPods)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
 	batchAPIList, err := batchapi.GetAllAPIs(batchAPIVirtualServices, batchJobList.Items)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
 	asyncAPIList, err := asyncapi.GetAllAPIs(asyncAPIDeployments)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
 	trafficSplitterList, err := trafficsplitter.GetAllAPIs(trafficSplitterVirtualServices)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
-	response := make([]schema.APIResponse, 0, len(realtimeAPIList)+len(batchAPIList)+len(trafficSplitterList))
+	response := make([]schema.APIResponse, 0, len(realtimeAPIList)+len(batchAPIList)+len(taskAPIList)+len(asyncAPIList)+len(trafficSplitterList))
 
 	response = append(response, realtimeAPIList...)
 	response = append(response, batchAPIList...)
@@ -357,7 +757,51 @@ func GetAPIs() ([]schema.APIResponse, error) {
 	response = append(response, asyncAPIList...)
 	response = append(response, trafficSplitterList...)
 
-	return response, nil
+	return response, total, nil
+}
+
+// returns a client which is scoped to the project's namespace, or to all namespaces if project is empty
+func projectK8sClient(project string) *k8s.Client {
+	if project == "" {
+		return config.K8sAllNamspaces
+	}
+	return config.K8s.WithNamespace(userconfig.ProjectNamespace(project))
+}
+
+func deploymentAPINames(deployments []kapps.Deployment) []string {
+	apiNames := make([]string, 0, len(deployments))
+	for _, deployment := range deployments {
+		apiNames = append(apiNames, deployment.Labels["apiName"])
+	}
+	return apiNames
+}
+
+func virtualServiceAPINames(virtualServices []*istioclientnetworking.VirtualService) []string {
+	apiNames := make([]string, 0, len(virtualServices))
+	for _, vs := range virtualServices {
+		apiNames = append(apiNames, vs.Labels["apiName"])
+	}
+	return apiNames
+}
+
+func filterDeploymentsByAPIName(deployments []kapps.Deployment, apiNames strset.Set) []kapps.Deployment {
+	var filtered []kapps.Deployment
+	for _, deployment := range deployments {
+		if apiNames.Has(deployment.Labels["apiName"]) {
+			filtered = append(filtered, deployment)
+		}
+	}
+	return filtered
+}
+
+func filterVirtualServicesByAPIName(virtualServices []*istioclientnetworking.VirtualService, apiNames strset.Set) []*istioclientnetworking.VirtualService {
+	var filtered []*istioclientnetworking.VirtualService
+	for _, vs := range virtualServices {
+		if apiNames.Has(vs.Labels["apiName"]) {
+			filtered = append(filtered, vs)
+		}
+	}
+	return filtered
 }
 
 func GetAPI(apiName string) ([]schema.APIResponse, error) {
@@ -409,6 +853,12 @@ func GetAPI(apiName string) ([]schema.APIResponse, error) {
 		if err != nil {
 			return nil, err
 		}
+
+		if apiResponse[0].Spec != nil && (apiResponse[0].Spec.ModelWatch != nil || apiResponse[0].Spec.GitSource != nil || apiResponse[0].Spec.Pod != nil) {
+			if err := addAPIVersionDetails(deployedResource.Name, apiResponse[0].APIVersions); err != nil {
+				return nil, err
+			}
+		}
 	}
 
 	return apiResponse, nil
@@ -451,6 +901,7 @@ func getPastAPIDeploys(apiName string) ([]schema.APIVersion, error) {
 			return nil, err
 		}
 		apiVersions = append(apiVersions, schema.APIVersion{
+			Version:     len(apiVersions) + 1,
 			APIID:       apiID,
 			LastUpdated: lastUpdated.Unix(),
 		})
@@ -459,9 +910,43 @@ func getPastAPIDeploys(apiName string) ([]schema.APIVersion, error) {
 	return apiVersions, nil
 }
 
+// addAPIVersionDetails sets the model version (if model_watch was configured), the git commit (if it was deployed from a git reference), and the image digests of each of the api's past deployments
+func addAPIVersionDetails(apiName string, apiVersions []schema.APIVersion) error {
+	apiNames := make([]string, len(apiVersions))
+	apiIDs := make([]string, len(apiVersions))
+	for i := range apiVersions {
+		apiNames[i] = apiName
+		apiIDs[i] = apiVersions[i].APIID
+	}
+
+	apiSpecs, err := operator.DownloadAPISpecs(apiNames, apiIDs)
+	if err != nil {
+		return err
+	}
+
+	for i := range apiSpecs {
+		apiVersions[i].ModelVersion = apiSpecs[i].ModelVersion
+		if apiSpecs[i].GitSource != nil {
+			apiVersions[i].GitCommit = apiSpecs[i].GitSource.Commit
+		}
+		if apiSpecs[i].Pod != nil {
+			for _, container := range apiSpecs[i].Pod.Containers {
+				if container.ImageDigest != "" {
+					if apiVersions[i].ImageDigests == nil {
+						apiVersions[i].ImageDigests = map[string]string{}
+					}
+					apiVersions[i].ImageDigests[container.Name] = container.ImageDigest
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
 // checkIfUsedByTrafficSplitter checks if api is used by a deployed TrafficSplitter
 func checkIfUsedByTrafficSplitter(apiName string) error {
-	virtualServices, err := config.K8s.ListVirtualServicesByLabel("apiKind", userconfig.TrafficSplitterKind.String())
+	virtualServices, err := config.K8sAllNamspaces.ListVirtualServicesByLabel("apiKind", userconfig.TrafficSplitterKind.String())
 	if err != nil {
 		return err
 	}
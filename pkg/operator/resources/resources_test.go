@@ -0,0 +1,39 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"testing"
+
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemovesCachedModel(t *testing.T) {
+	t.Parallel()
+
+	// deletes keep the cached model by default (keepVolumes defaults to true), so no removal jobs are created
+	for _, kindStr := range userconfig.KindStrings() {
+		require.False(t, removesCachedModel(userconfig.KindFromString(kindStr), true), kindStr)
+	}
+
+	require.True(t, removesCachedModel(userconfig.RealtimeAPIKind, false))
+	require.True(t, removesCachedModel(userconfig.AsyncAPIKind, false))
+	require.False(t, removesCachedModel(userconfig.BatchAPIKind, false))
+	require.False(t, removesCachedModel(userconfig.TaskAPIKind, false))
+	require.False(t, removesCachedModel(userconfig.TrafficSplitterKind, false))
+}
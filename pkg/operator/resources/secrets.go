@@ -0,0 +1,117 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"fmt"
+
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	"github.com/cortexlabs/cortex/pkg/operator/operator"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+)
+
+// SetSecret stores the secret in the cluster's secrets backend, and updates the value which is provided to the apis that already reference it
+func SetSecret(name string, value string) (string, error) {
+	if err := userconfig.ValidateSecretName(name); err != nil {
+		return "", errors.Wrap(err, "secret name")
+	}
+
+	// the value is validated before anything is written, so that an invalid value doesn't leave the secret and the apis which use it out of sync
+	if err := operator.ValidateRegistryCredentialsUpdate(name, value); err != nil {
+		return "", err
+	}
+
+	if err := operator.PutSecretValue(name, value); err != nil {
+		return "", err
+	}
+
+	apiNames, err := operator.UpdateSecretEnvs(name, value)
+	if err != nil {
+		return "", err
+	}
+
+	registryAPINames, err := operator.UpdateRegistryCredentials(name, value)
+	if err != nil {
+		return "", err
+	}
+
+	msg := fmt.Sprintf("set secret %s", name)
+	if len(apiNames) > 0 {
+		msg += fmt.Sprintf("; %s %s will receive the new value once %s restarted (e.g. with `cortex refresh`)", s.PluralS("api", len(apiNames)), s.StrsAnd(apiNames), s.PluralCustom("its pods are", "their pods are", len(apiNames)))
+	}
+	if len(registryAPINames) > 0 {
+		msg += fmt.Sprintf("; the registry credentials of %s %s were updated", s.PluralS("api", len(registryAPINames)), s.StrsAnd(registryAPINames))
+	}
+	return msg, nil
+}
+
+func GetSecret(name string) (*schema.GetSecretResponse, error) {
+	value, err := operator.GetSecretValue(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &schema.GetSecretResponse{
+		Name:  name,
+		Value: value,
+	}, nil
+}
+
+func ListSecrets() ([]schema.Secret, error) {
+	names, err := operator.ListSecretNames()
+	if err != nil {
+		return nil, err
+	}
+
+	apiNames, err := operator.APIsUsingSecrets()
+	if err != nil {
+		return nil, err
+	}
+
+	secrets := make([]schema.Secret, 0, len(names))
+	for _, name := range names {
+		secrets = append(secrets, schema.Secret{
+			Name: name,
+			APIs: apiNames[name],
+		})
+	}
+
+	return secrets, nil
+}
+
+// DeleteSecret deletes the secret from the cluster's secrets backend, unless it is referenced by a deployed api
+func DeleteSecret(name string) (string, error) {
+	apiNames, err := operator.APIsUsingSecrets()
+	if err != nil {
+		return "", err
+	}
+	if len(apiNames[name]) > 0 {
+		return "", ErrorSecretInUse(name, apiNames[name])
+	}
+
+	deleted, err := operator.DeleteSecretValue(name)
+	if err != nil {
+		return "", err
+	}
+	if !deleted {
+		return "", operator.ErrorSecretNotFound(name)
+	}
+
+	return fmt.Sprintf("deleted secret %s", name), nil
+}
@@ -0,0 +1,190 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"context"
+	"sort"
+
+	"github.com/cortexlabs/cortex/pkg/config"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/k8s"
+	"github.com/cortexlabs/cortex/pkg/lib/pointer"
+	"github.com/cortexlabs/cortex/pkg/operator/operator"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+	kcore "k8s.io/api/core/v1"
+	kresource "k8s.io/apimachinery/pkg/api/resource"
+	kmeta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kmetrics "k8s.io/metrics/pkg/client/clientset/versioned"
+)
+
+// GetReplicaUtilizations returns the resource usage of every running replica (of all apis if apiName is empty),
+// as reported by the metrics server (cpu and memory) and the dcgm exporter (gpu)
+func GetReplicaUtilizations(apiName string) ([]schema.ReplicaUtilization, error) {
+	labelSelector := k8s.LabelExistsSelector("apiName", "apiKind")
+	if apiName != "" {
+		if _, err := GetDeployedResourceByName(apiName); err != nil {
+			return nil, err
+		}
+		labelSelector += ",apiName=" + apiName
+	}
+
+	pods, err := config.K8sAllNamspaces.ListPods(&kmeta.ListOptions{
+		LabelSelector: labelSelector,
+		FieldSelector: "status.phase=Running",
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(pods) == 0 {
+		return []schema.ReplicaUtilization{}, nil
+	}
+
+	nodeGroupNames, err := getNodeGroupNames()
+	if err != nil {
+		return nil, err
+	}
+
+	podUsages, err := getPodUsages()
+	if err != nil {
+		return nil, err
+	}
+
+	var gpuUtilizations, gpuMemUsages map[string]float64
+	for i := range pods {
+		if _, _, gpus, _ := k8s.TotalPodCompute(&pods[i].Spec); gpus > 0 {
+			gpuUtilizations, gpuMemUsages, err = getPodGPUUsages()
+			if err != nil {
+				return nil, err
+			}
+			break
+		}
+	}
+
+	replicas := make([]schema.ReplicaUtilization, 0, len(pods))
+	for i := range pods {
+		pod := &pods[i]
+		cpuRequested, memRequested, gpus, _ := k8s.TotalPodCompute(&pod.Spec)
+
+		replica := schema.ReplicaUtilization{
+			APIName:       pod.Labels["apiName"],
+			APIKind:       userconfig.KindFromString(pod.Labels["apiKind"]),
+			JobID:         pod.Labels["jobID"],
+			PodName:       pod.Name,
+			NodeGroupName: nodeGroupNames[pod.Spec.NodeName],
+			CPURequested:  cpuRequested,
+			MemRequested:  memRequested,
+			GPUs:          gpus,
+		}
+
+		if usage, ok := podUsages[pod.Name]; ok {
+			replica.CPU = k8s.WrapQuantity(*usage.Cpu())
+			replica.Mem = k8s.WrapQuantity(*usage.Memory())
+		}
+
+		if gpus > 0 {
+			if gpuUtilization, ok := gpuUtilizations[pod.Name]; ok {
+				replica.GPUUtilization = pointer.Float64(gpuUtilization)
+			}
+			if gpuMemUsed, ok := gpuMemUsages[pod.Name]; ok {
+				replica.GPUMemUsed = pointer.Float64(gpuMemUsed)
+			}
+		}
+
+		replicas = append(replicas, replica)
+	}
+
+	sort.Slice(replicas, func(i, j int) bool {
+		if replicas[i].APIName != replicas[j].APIName {
+			return replicas[i].APIName < replicas[j].APIName
+		}
+		if replicas[i].NodeGroupName != replicas[j].NodeGroupName {
+			return replicas[i].NodeGroupName < replicas[j].NodeGroupName
+		}
+		return replicas[i].PodName < replicas[j].PodName
+	})
+
+	return replicas, nil
+}
+
+// returns node name -> nodegroup name
+func getNodeGroupNames() (map[string]string, error) {
+	nodes, err := config.K8s.ListNodes(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	nodeGroupNames := make(map[string]string, len(nodes))
+	for _, node := range nodes {
+		nodeGroupNames[node.Name] = node.Labels["alpha.eksctl.io/nodegroup-name"]
+	}
+	return nodeGroupNames, nil
+}
+
+// returns pod name -> total cpu and memory usage of the pod's containers
+func getPodUsages() (map[string]kcore.ResourceList, error) {
+	metricsClient, err := kmetrics.NewForConfig(config.K8s.RestConfig)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	podMetricsList, err := metricsClient.MetricsV1beta1().PodMetricses(kmeta.NamespaceAll).List(context.Background(), kmeta.ListOptions{
+		LabelSelector: k8s.LabelExistsSelector("apiName"),
+	})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	podUsages := make(map[string]kcore.ResourceList, len(podMetricsList.Items))
+	for _, podMetrics := range podMetricsList.Items {
+		var cpu, mem kresource.Quantity
+		for _, container := range podMetrics.Containers {
+			cpu.Add(*container.Usage.Cpu())
+			mem.Add(*container.Usage.Memory())
+		}
+		podUsages[podMetrics.Name] = kcore.ResourceList{
+			kcore.ResourceCPU:    cpu,
+			kcore.ResourceMemory: mem,
+		}
+	}
+	return podUsages, nil
+}
+
+// returns pod name -> average gpu utilization, and pod name -> total gpu memory used (in MiB)
+func getPodGPUUsages() (map[string]float64, map[string]float64, error) {
+	gpuUtilizations := map[string]float64{}
+	gpuMemUsages := map[string]float64{}
+
+	utilizationValues, err := operator.QueryPrometheusVec("avg by (exported_pod) (DCGM_FI_DEV_GPU_UTIL)")
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, sample := range utilizationValues {
+		gpuUtilizations[string(sample.Metric["exported_pod"])] = float64(sample.Value)
+	}
+
+	memValues, err := operator.QueryPrometheusVec("sum by (exported_pod) (DCGM_FI_DEV_FB_USED)")
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, sample := range memValues {
+		gpuMemUsages[string(sample.Metric["exported_pod"])] = float64(sample.Value)
+	}
+
+	return gpuUtilizations, gpuMemUsages, nil
+}
@@ -38,7 +38,7 @@ import (
 
 // UpdateAPI creates or updates a traffic splitter API kind
 func UpdateAPI(apiConfig *userconfig.API) (*spec.API, string, error) {
-	prevVirtualService, err := config.K8s.GetVirtualService(workloads.K8sName(apiConfig.Name))
+	prevVirtualService, err := config.K8s.WithNamespace(apiConfig.Namespace()).GetVirtualService(workloads.K8sName(apiConfig.Name))
 	if err != nil {
 		return nil, "", err
 	}
@@ -60,7 +60,7 @@ func UpdateAPI(apiConfig *userconfig.API) (*spec.API, string, error) {
 
 		if err := applyK8sVirtualService(api, prevVirtualService); err != nil {
 			routines.RunWithPanicHandler(func() {
-				_ = deleteK8sResources(api.Name)
+				_ = deleteK8sResources(api.Name, api.Namespace())
 			})
 			return nil, "", err
 		}
@@ -84,10 +84,10 @@ func UpdateAPI(apiConfig *userconfig.API) (*spec.API, string, error) {
 }
 
 // DeleteAPI deletes all the resources related to a given traffic splitter API
-func DeleteAPI(apiName string, keepCache bool) error {
+func DeleteAPI(apiName string, namespace string, keepCache bool) error {
 	err := parallel.RunFirstErr(
 		func() error {
-			return deleteK8sResources(apiName)
+			return deleteK8sResources(apiName, namespace)
 		},
 		func() error {
 			if keepCache {
@@ -107,14 +107,15 @@ func DeleteAPI(apiName string, keepCache bool) error {
 }
 
 func applyK8sVirtualService(trafficSplitter *spec.API, prevVirtualService *istioclientnetworking.VirtualService) error {
+	k8sClient := config.K8s.WithNamespace(trafficSplitter.Namespace())
 	newVirtualService := virtualServiceSpec(trafficSplitter)
 
 	if prevVirtualService == nil {
-		_, err := config.K8s.CreateVirtualService(newVirtualService)
+		_, err := k8sClient.CreateVirtualService(newVirtualService)
 		return err
 	}
 
-	_, err := config.K8s.UpdateVirtualService(prevVirtualService, newVirtualService)
+	_, err := k8sClient.UpdateVirtualService(prevVirtualService, newVirtualService)
 	return err
 }
 
@@ -186,8 +187,8 @@ func GetAPIByName(deployedResource *operator.DeployedResource) ([]schema.APIResp
 	}, nil
 }
 
-func deleteK8sResources(apiName string) error {
-	_, err := config.K8s.DeleteVirtualService(workloads.K8sName(apiName))
+func deleteK8sResources(apiName string, namespace string) error {
+	_, err := config.K8s.WithNamespace(namespace).DeleteVirtualService(workloads.K8sName(apiName))
 	return err
 }
 
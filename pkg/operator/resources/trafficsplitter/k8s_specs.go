@@ -18,6 +18,7 @@ package trafficsplitter
 
 import (
 	"github.com/cortexlabs/cortex/pkg/lib/k8s"
+	"github.com/cortexlabs/cortex/pkg/lib/maps"
 	"github.com/cortexlabs/cortex/pkg/lib/pointer"
 	s "github.com/cortexlabs/cortex/pkg/lib/strings"
 	"github.com/cortexlabs/cortex/pkg/types/spec"
@@ -28,19 +29,20 @@ import (
 func virtualServiceSpec(trafficSplitter *spec.API) *istioclientnetworking.VirtualService {
 	return k8s.VirtualService(&k8s.VirtualServiceSpec{
 		Name:         workloads.K8sName(trafficSplitter.Name),
-		Gateways:     []string{"apis-gateway"},
+		Gateways:     []string{workloads.APIGatewayRef(trafficSplitter.Networking)},
 		Destinations: getTrafficSplitterDestinations(trafficSplitter),
 		ExactPath:    trafficSplitter.Networking.Endpoint,
 		Rewrite:      pointer.String("/"),
 		Retries:      pointer.Int32(0),
 		Annotations:  trafficSplitter.ToK8sAnnotations(),
-		Labels: map[string]string{
+		Labels: maps.MergeStrMapsString(trafficSplitter.Labels, map[string]string{
 			"apiName":               trafficSplitter.Name,
 			"apiKind":               trafficSplitter.Kind.String(),
 			"apiID":                 trafficSplitter.ID,
 			"specID":                trafficSplitter.SpecID,
 			"initialDeploymentTime": s.Int64(trafficSplitter.InitialDeploymentTime),
 			"cortex.dev/api":        "true",
-		},
+			"cortex.dev/project":    trafficSplitter.Project,
+		}),
 	})
 }
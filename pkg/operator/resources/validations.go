@@ -26,8 +26,10 @@ import (
 	"github.com/cortexlabs/cortex/pkg/lib/slices"
 	s "github.com/cortexlabs/cortex/pkg/lib/strings"
 	"github.com/cortexlabs/cortex/pkg/operator/operator"
+	"github.com/cortexlabs/cortex/pkg/types/clusterconfig"
 	"github.com/cortexlabs/cortex/pkg/types/spec"
 	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+	"github.com/cortexlabs/cortex/pkg/workloads"
 	istioclientnetworking "istio.io/client-go/pkg/apis/networking/v1beta1"
 	kresource "k8s.io/apimachinery/pkg/api/resource"
 )
@@ -41,14 +43,15 @@ func ValidateClusterAPIs(apis []userconfig.API) error {
 		return ErrorNoNodeGroups()
 	}
 
-	virtualServices, err := config.K8s.ListVirtualServices(nil)
+	virtualServices, err := config.K8sAllNamspaces.ListVirtualServices(nil)
 	if err != nil {
 		return err
 	}
-	deployedRealtimeAPIs := strset.New()
+	// api name -> project
+	deployedRealtimeAPIs := map[string]string{}
 	for _, virtualService := range virtualServices {
 		if virtualService.Labels["apiKind"] == userconfig.RealtimeAPIKind.String() {
-			deployedRealtimeAPIs.Add(virtualService.Labels["apiName"])
+			deployedRealtimeAPIs[virtualService.Labels["apiName"]] = userconfig.ProjectFromLabels(virtualService.Labels)
 		}
 	}
 
@@ -56,16 +59,40 @@ func ValidateClusterAPIs(apis []userconfig.API) error {
 
 	for i := range apis {
 		api := &apis[i]
+		if err := validateProject(api.Project); err != nil {
+			return errors.Wrap(err, api.Identify(), userconfig.ProjectKey)
+		}
+
 		if api.Kind == userconfig.RealtimeAPIKind || api.Kind == userconfig.BatchAPIKind ||
 			api.Kind == userconfig.TaskAPIKind || api.Kind == userconfig.AsyncAPIKind {
 
-			if err := spec.ValidateAPI(api, config.AWS, config.K8s); err != nil {
+			if err := spec.ValidateAPI(api, config.AWS, config.K8s.WithNamespace(api.Namespace())); err != nil {
 				return errors.Wrap(err, api.Identify())
 			}
 
+			if err := operator.EnforcePodSecurityPolicy(api); err != nil {
+				return errors.Wrap(err, api.Identify(), userconfig.PodKey, userconfig.SecurityKey)
+			}
+
+			if err := operator.ValidateIAMRole(api); err != nil {
+				return errors.Wrap(err, api.Identify(), userconfig.PodKey, userconfig.IAMRoleARNKey)
+			}
+
+			if err := operator.ValidateRegistryCredentials(api); err != nil {
+				return errors.Wrap(err, api.Identify(), userconfig.PodKey)
+			}
+
+			if err := operator.ValidateNetworkPolicy(api); err != nil {
+				return errors.Wrap(err, api.Identify(), userconfig.NetworkingKey, userconfig.EgressKey)
+			}
+
 			if err := validateEndpointCollisions(api, virtualServices); err != nil {
 				return err
 			}
+
+			if err := validateEndpointVisibility(api); err != nil {
+				return errors.Wrap(err, api.Identify(), userconfig.NetworkingKey)
+			}
 		}
 
 		if api.Kind == userconfig.TrafficSplitterKind {
@@ -75,9 +102,18 @@ func ValidateClusterAPIs(apis []userconfig.API) error {
 			if err := checkIfAPIExists(api.APIs, realtimeAPIs, deployedRealtimeAPIs); err != nil {
 				return errors.Wrap(err, api.Identify())
 			}
+			if err := checkTrafficSplitterAPIsProtocol(api.APIs, realtimeAPIs); err != nil {
+				return errors.Wrap(err, api.Identify())
+			}
+			if err := checkTrafficSplitterAPIsProject(api, realtimeAPIs, deployedRealtimeAPIs); err != nil {
+				return errors.Wrap(err, api.Identify())
+			}
 			if err := validateEndpointCollisions(api, virtualServices); err != nil {
 				return errors.Wrap(err, api.Identify())
 			}
+			if err := validateEndpointVisibility(api); err != nil {
+				return errors.Wrap(err, api.Identify(), userconfig.NetworkingKey)
+			}
 		}
 	}
 
@@ -99,7 +135,7 @@ func ValidateClusterAPIs(apis []userconfig.API) error {
 	if len(dups) > 0 {
 		return spec.ErrorDuplicateName(dups)
 	}
-	dups = findDuplicateEndpoints(apis)
+	dups = spec.FindDuplicateEndpoints(apis)
 	if len(dups) > 0 {
 		return spec.ErrorDuplicateEndpointInOneDeploy(dups)
 	}
@@ -107,6 +143,20 @@ func ValidateClusterAPIs(apis []userconfig.API) error {
 	return nil
 }
 
+// when projects are declared in the cluster configuration, apis can only be deployed to those projects (or to the default project)
+func validateProject(project string) error {
+	if err := userconfig.ValidateProject(project); err != nil {
+		return err
+	}
+	if len(config.ClusterConfig.Projects) == 0 || project == userconfig.DefaultProject {
+		return nil
+	}
+	if config.ClusterConfig.GetProjectByName(project) == nil {
+		return ErrorProjectNotFound(project, config.ClusterConfig.GetProjectNames())
+	}
+	return nil
+}
+
 var _nvidiaDevicePluginCPUReserve = kresource.MustParse("100m")
 var _nvidiaDevicePluginMemReserve = kresource.MustParse("100Mi")
 
@@ -132,6 +182,7 @@ func validateK8sCompute(api *userconfig.API, maxMemMap map[string]kresource.Quan
 		}
 
 		nodeCPU, nodeMem, nodeGPU, nodeInf := getNodeCapacity(ng.InstanceType, maxMemMap)
+		nodeNeuronCores := getNodeNeuronCores(ng.InstanceType, nodeInf)
 
 		if compute.CPU != nil && nodeCPU.Cmp(compute.CPU.Quantity) < 0 {
 			continue
@@ -141,6 +192,8 @@ func validateK8sCompute(api *userconfig.API, maxMemMap map[string]kresource.Quan
 			continue
 		} else if compute.Inf > nodeInf {
 			continue
+		} else if compute.NeuronCores > nodeNeuronCores {
+			continue
 		}
 
 		// we found a node group that has capacity
@@ -182,11 +235,24 @@ func getNodeCapacity(instanceType string, maxMemMap map[string]kresource.Quantit
 	return cpu, mem, gpu, inf
 }
 
+func getNodeNeuronCores(instanceType string, numInf int64) int64 {
+	if numInf == 0 {
+		return 0
+	}
+
+	coresPerDevice, err := aws.NeuronCoresPerDevice(instanceType)
+	if err != nil {
+		return 0
+	}
+
+	return numInf * coresPerDevice
+}
+
 func validateEndpointCollisions(api *userconfig.API, virtualServices []*istioclientnetworking.VirtualService) error {
 	for i := range virtualServices {
 		virtualService := virtualServices[i]
 		gateways := k8s.ExtractVirtualServiceGateways(virtualService)
-		if !gateways.Has("apis-gateway") {
+		if !gateways.HasAny(workloads.APIsGateway, workloads.InternalAPIsGateway) {
 			continue
 		}
 
@@ -201,16 +267,23 @@ func validateEndpointCollisions(api *userconfig.API, virtualServices []*istiocli
 	return nil
 }
 
-func findDuplicateEndpoints(apis []userconfig.API) []userconfig.API {
-	endpoints := make(map[string][]userconfig.API)
+func validateEndpointVisibility(api *userconfig.API) error {
+	networking := api.Networking
 
-	for _, api := range apis {
-		endpoints[*api.Networking.Endpoint] = append(endpoints[*api.Networking.Endpoint], api)
+	if networking.EndpointVisibility != nil && *networking.EndpointVisibility == userconfig.EndpointVisibilityPublic &&
+		config.ClusterConfig.APILoadBalancerScheme != clusterconfig.InternetFacingLoadBalancerScheme {
+		return errors.Wrap(ErrorPublicEndpointRequiresInternetFacingLoadBalancer(), userconfig.EndpointVisibilityKey)
 	}
 
-	for endpoint := range endpoints {
-		if len(endpoints[endpoint]) > 1 {
-			return endpoints[endpoint]
+	if networking.CustomDomain != nil {
+		if !operator.AreCustomDomainsSupported() {
+			return errors.Wrap(ErrorCustomDomainRequiresTLSLoadBalancer(), userconfig.CustomDomainKey)
+		}
+		if workloads.APIGateway(networking) == workloads.InternalAPIsGateway {
+			return errors.Wrap(ErrorCustomDomainNotSupportedForInternalEndpoint(), userconfig.CustomDomainKey)
+		}
+		if err := operator.ValidateCustomDomain(api); err != nil {
+			return errors.Wrap(err, userconfig.CustomDomainKey)
 		}
 	}
 
@@ -247,12 +320,12 @@ func ExclusiveFilterAPIsByKind(apis []userconfig.API, kindsToExclude ...userconf
 }
 
 // checkIfAPIExists checks if referenced apis in trafficsplitter are either defined in yaml or already deployed.
-func checkIfAPIExists(trafficSplitterAPIs []*userconfig.TrafficSplit, apis []userconfig.API, deployedRealtimeAPIs strset.Set) error {
+func checkIfAPIExists(trafficSplitterAPIs []*userconfig.TrafficSplit, apis []userconfig.API, deployedRealtimeAPIs map[string]string) error {
 	var missingAPIs []string
 	// check if apis named in trafficsplitter are either defined in same yaml or already deployed
 	for _, trafficSplitAPI := range trafficSplitterAPIs {
 		// check if already deployed
-		deployed := deployedRealtimeAPIs.Has(trafficSplitAPI.Name)
+		_, deployed := deployedRealtimeAPIs[trafficSplitAPI.Name]
 
 		// check defined apis
 		for _, definedAPI := range apis {
@@ -270,3 +343,31 @@ func checkIfAPIExists(trafficSplitterAPIs []*userconfig.TrafficSplit, apis []use
 	return nil
 
 }
+
+// traffic splitters route requests by path, which isn't possible for grpc requests
+func checkTrafficSplitterAPIsProtocol(trafficSplitterAPIs []*userconfig.TrafficSplit, apis []userconfig.API) error {
+	for _, trafficSplitAPI := range trafficSplitterAPIs {
+		for _, definedAPI := range apis {
+			if trafficSplitAPI.Name == definedAPI.Name && definedAPI.Pod != nil && definedAPI.Pod.Protocol == userconfig.ProtocolGRPC {
+				return ErrorTrafficSplitterGRPCAPI(definedAPI.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// traffic splitters route to the services of their apis by name, which are only resolvable within the traffic splitter's namespace
+func checkTrafficSplitterAPIsProject(trafficSplitter *userconfig.API, apis []userconfig.API, deployedRealtimeAPIs map[string]string) error {
+	for _, trafficSplitAPI := range trafficSplitter.APIs {
+		project, ok := deployedRealtimeAPIs[trafficSplitAPI.Name]
+		for _, definedAPI := range apis {
+			if trafficSplitAPI.Name == definedAPI.Name {
+				project, ok = definedAPI.Project, true
+			}
+		}
+		if ok && userconfig.ProjectNamespace(project) != trafficSplitter.Namespace() {
+			return ErrorTrafficSplitterAPIInOtherProject(trafficSplitAPI.Name, project, trafficSplitter.Project)
+		}
+	}
+	return nil
+}
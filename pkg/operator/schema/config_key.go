@@ -23,12 +23,22 @@ const (
 	ItemListKey           = "item_list"
 	FilePathListerKey     = "file_path_lister"
 	DelimitedFilesKey     = "delimited_files"
+	FileManifestKey       = "file_manifest"
 	S3PathsKey            = "s3_paths"
+	S3PathKey             = "s3_path"
 	IncludesKey           = "includes"
 	ExcludesKey           = "excludes"
 	WorkersKey            = "workers"
+	MaxWorkersKey         = "max_workers"
 	TimeoutKey            = "timeout"
+	MaxRetriesKey         = "max_retries"
+	OutputPathKey         = "output_path"
 	MaxReceiveCountKey    = "max_receive_count"
 	ARNKey                = "arn"
 	SQSDeadLetterQueueKey = "sqs_dead_letter_queue"
+	ScheduleKey           = "schedule"
+	ParamsKey             = "params"
+	DependsOnKey          = "depends_on"
+	CheckpointKey         = "checkpoint"
+	IntervalKey           = "interval"
 )
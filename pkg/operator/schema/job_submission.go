@@ -19,9 +19,14 @@ package schema
 import (
 	"encoding/json"
 
+	cr "github.com/cortexlabs/cortex/pkg/lib/configreader"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
 	"github.com/cortexlabs/cortex/pkg/types/spec"
 )
 
+// AutoWorkers can be specified as a batch job submission's workers to scale its workers with the job's remaining batches
+const AutoWorkers = "auto"
+
 type ItemList struct {
 	Items     []json.RawMessage `json:"items"`
 	BatchSize int               `json:"batch_size"`
@@ -44,13 +49,59 @@ type DelimitedFiles struct {
 	BatchSize int `json:"batch_size"`
 }
 
+// FileManifest lists the S3 files to process in a manifest file (either a text file with one S3 path or key per line, or the manifest.json of an S3 inventory report)
+type FileManifest struct {
+	S3Path    string `json:"s3_path"` // s3://<bucket_name>/key
+	BatchSize int    `json:"batch_size"`
+}
+
 type BatchJobSubmission struct {
 	spec.RuntimeBatchJobConfig
 	ItemList       *ItemList       `json:"item_list"`
 	FilePathLister *FilePathLister `json:"file_path_lister"`
 	DelimitedFiles *DelimitedFiles `json:"delimited_files"`
+	FileManifest   *FileManifest   `json:"file_manifest"`
+	Schedule       *string         `json:"schedule"` // cron schedule on which to submit the job (the job is submitted immediately if not set)
+}
+
+// UnmarshalJSON allows workers to be set to "auto" (in addition to a number of workers)
+func (submission *BatchJobSubmission) UnmarshalJSON(data []byte) error {
+	type batchJobSubmission BatchJobSubmission // doesn't inherit this method
+
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	var autoWorkers bool
+	var workers *string
+	if json.Unmarshal(fields[WorkersKey], &workers) == nil && workers != nil {
+		if *workers != AutoWorkers {
+			return errors.Wrap(cr.ErrorInvalidStr(*workers, AutoWorkers), WorkersKey)
+		}
+		autoWorkers = true
+
+		delete(fields, WorkersKey)
+		var err error
+		data, err = json.Marshal(fields)
+		if err != nil {
+			return err
+		}
+	}
+
+	var decoded batchJobSubmission
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	*submission = BatchJobSubmission(decoded)
+
+	if autoWorkers {
+		submission.AutoWorkers = true
+	}
+	return nil
 }
 
 type TaskJobSubmission struct {
 	spec.RuntimeTaskJobConfig
+	Schedule *string `json:"schedule"` // cron schedule on which to submit the job (the job is submitted immediately if not set)
 }
@@ -17,6 +17,12 @@ limitations under the License.
 package schema
 
 import (
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/lib/k8s"
+	"github.com/cortexlabs/cortex/pkg/lib/sets/strset"
+	"github.com/cortexlabs/cortex/pkg/lib/structs"
+	"github.com/cortexlabs/cortex/pkg/rbac"
 	"github.com/cortexlabs/cortex/pkg/types/clusterconfig"
 	"github.com/cortexlabs/cortex/pkg/types/metrics"
 	"github.com/cortexlabs/cortex/pkg/types/spec"
@@ -29,6 +35,7 @@ type InfoResponse struct {
 	WorkerNodeInfos    []WorkerNodeInfo             `json:"worker_node_infos" yaml:"worker_node_infos"`
 	OperatorNodeInfos  []NodeInfo                   `json:"operator_node_infos" yaml:"operator_node_infos"`
 	NumPendingReplicas int                          `json:"num_pending_replicas" yaml:"num_pending_replicas"`
+	SpotInterruptions  map[string]int64             `json:"spot_interruptions" yaml:"spot_interruptions"` // nodegroup name -> number of spot interruptions handled
 }
 
 type WorkerNodeInfo struct {
@@ -49,9 +56,34 @@ type NodeInfo struct {
 }
 
 type DeployResult struct {
-	API     *APIResponse `json:"api" yaml:"api"`
-	Message string       `json:"message" yaml:"message"`
-	Error   string       `json:"error" yaml:"error"`
+	API        *APIResponse       `json:"api" yaml:"api"`
+	Message    string             `json:"message" yaml:"message"`
+	Error      string             `json:"error" yaml:"error"`
+	ImageScans []ImageScanSummary `json:"image_scans,omitempty" yaml:"image_scans,omitempty"`
+}
+
+type ImageScanSummary struct {
+	Image          string           `json:"image" yaml:"image"`
+	Status         string           `json:"status" yaml:"status"`                                       // the ECR scan status (e.g. "complete"), or "not_scanned"
+	SeverityCounts map[string]int64 `json:"severity_counts,omitempty" yaml:"severity_counts,omitempty"` // keyed by lowercase severity (e.g. "critical")
+	Warning        string           `json:"warning,omitempty" yaml:"warning,omitempty"`
+}
+
+type DiffAction string
+
+const (
+	DiffActionCreate DiffAction = "create"
+	DiffActionUpdate DiffAction = "update"
+	DiffActionNone   DiffAction = "none"
+)
+
+type DiffResult struct {
+	Name    string           `json:"name" yaml:"name"`
+	Kind    userconfig.Kind  `json:"kind" yaml:"kind"`
+	Action  DiffAction       `json:"action" yaml:"action"`
+	Changes []structs.Change `json:"changes,omitempty" yaml:"changes,omitempty"`
+	Impact  string           `json:"impact,omitempty" yaml:"impact,omitempty"` // a description of how the running workloads will be affected
+	Error   string           `json:"error,omitempty" yaml:"error,omitempty"`
 }
 
 type APIResponse struct {
@@ -63,7 +95,94 @@ type APIResponse struct {
 	DashboardURL              *string                 `json:"dashboard_url,omitempty"  yaml:"dashboard_url,omitempty"`
 	BatchJobStatuses          []status.BatchJobStatus `json:"batch_job_statuses,omitempty"  yaml:"batch_job_statuses,omitempty"`
 	TaskJobStatuses           []status.TaskJobStatus  `json:"task_job_statuses,omitempty"  yaml:"task_job_statuses,omitempty"`
+	QueuedJobCount            int                     `json:"queued_job_count,omitempty"  yaml:"queued_job_count,omitempty"` // batch and task apis only; jobs which have been submitted but not yet started
 	APIVersions               []APIVersion            `json:"api_versions,omitempty"  yaml:"api_versions,omitempty"`
+	Events                    []Event                 `json:"events,omitempty"  yaml:"events,omitempty"`
+	Canary                    *CanaryResponse         `json:"canary,omitempty"  yaml:"canary,omitempty"`
+	DeadLetterQueueLength     *int                    `json:"dead_letter_queue_length,omitempty"  yaml:"dead_letter_queue_length,omitempty"` // async apis with a dead-letter queue only
+	ImagePrepull              *ImagePrepullResponse   `json:"image_prepull,omitempty"  yaml:"image_prepull,omitempty"`                       // apis with prepull_images enabled only
+}
+
+// GetAPIsPage is the response of /get when the limit query param is specified
+type GetAPIsPage struct {
+	APIs       []APIResponse `json:"apis" yaml:"apis"`
+	Total      int           `json:"total" yaml:"total"`                                 // the total number of apis
+	NextOffset *int          `json:"next_offset,omitempty" yaml:"next_offset,omitempty"` // nil if this is the last page
+}
+
+// APIResponseFields are the top-level fields of APIResponse which can be selected via the fields query param of /get
+var APIResponseFields = []string{
+	"spec",
+	"metadata",
+	"status",
+	"num_traffic_splitter_targets",
+	"endpoint",
+	"dashboard_url",
+	"batch_job_statuses",
+	"task_job_statuses",
+	"api_versions",
+	"events",
+	"canary",
+	"dead_letter_queue_length",
+	"image_prepull",
+}
+
+// SelectFields returns a copy of the API response which only contains the specified top-level fields
+func (res APIResponse) SelectFields(fields strset.Set) APIResponse {
+	var selected APIResponse
+	for field := range fields {
+		switch field {
+		case "spec":
+			selected.Spec = res.Spec
+		case "metadata":
+			selected.Metadata = res.Metadata
+		case "status":
+			selected.Status = res.Status
+		case "num_traffic_splitter_targets":
+			selected.NumTrafficSplitterTargets = res.NumTrafficSplitterTargets
+		case "endpoint":
+			selected.Endpoint = res.Endpoint
+		case "dashboard_url":
+			selected.DashboardURL = res.DashboardURL
+		case "batch_job_statuses":
+			selected.BatchJobStatuses = res.BatchJobStatuses
+		case "task_job_statuses":
+			selected.TaskJobStatuses = res.TaskJobStatuses
+		case "api_versions":
+			selected.APIVersions = res.APIVersions
+		case "events":
+			selected.Events = res.Events
+		case "canary":
+			selected.Canary = res.Canary
+		case "dead_letter_queue_length":
+			selected.DeadLetterQueueLength = res.DeadLetterQueueLength
+		case "image_prepull":
+			selected.ImagePrepull = res.ImagePrepull
+		}
+	}
+	return selected
+}
+
+type CanaryResponse struct {
+	APIID  string         `json:"api_id" yaml:"api_id"`
+	Weight int32          `json:"weight" yaml:"weight"` // percentage of traffic routed to the canary (0 until the canary is ready)
+	Status *status.Status `json:"status" yaml:"status"`
+}
+
+// ImagePrepullResponse is the status of the pre-pulling of an API's images onto the nodes which the API can be scheduled on
+type ImagePrepullResponse struct {
+	NumNodes  int32 `json:"num_nodes" yaml:"num_nodes"`   // the number of nodes which the API's images are pre-pulled onto
+	NumPulled int32 `json:"num_pulled" yaml:"num_pulled"` // the number of nodes on which the API's current images have been pulled
+}
+
+// Event is a recent Kubernetes event (or pod termination) related to an API's workloads
+type Event struct {
+	Type      string `json:"type" yaml:"type"`     // Normal or Warning
+	Reason    string `json:"reason" yaml:"reason"` // e.g. FailedScheduling, Failed, BackOff, OOMKilled
+	Object    string `json:"object" yaml:"object"` // e.g. pod/api-my-api-5c9f8d7b6-x2x4z
+	Message   string `json:"message" yaml:"message"`
+	Count     int32  `json:"count" yaml:"count"`         // number of times the event was observed
+	Timestamp int64  `json:"timestamp" yaml:"timestamp"` // unix timestamp of the last occurrence
 }
 
 type LogResponse struct {
@@ -77,6 +196,17 @@ type BatchJobResponse struct {
 	Endpoint  string                `json:"endpoint" yaml:"endpoint"`
 }
 
+type BatchJobResultsResponse struct {
+	OutputPrefix string           `json:"output_prefix" yaml:"output_prefix"`
+	Objects      []BatchJobResult `json:"objects" yaml:"objects"`
+}
+
+type BatchJobResult struct {
+	Key          string    `json:"key" yaml:"key"` // relative to the output prefix
+	Size         int64     `json:"size" yaml:"size"`
+	LastModified time.Time `json:"last_modified" yaml:"last_modified"`
+}
+
 type TaskJobResponse struct {
 	APISpec   spec.API             `json:"api_spec" yaml:"api_spec"`
 	JobStatus status.TaskJobStatus `json:"job_status" yaml:"job_status"`
@@ -87,22 +217,201 @@ type DeleteResponse struct {
 	Message string `json:"message"`
 }
 
+type BulkDeleteResponse struct {
+	DryRun bool               `json:"dry_run"`
+	APIs   []BulkDeleteResult `json:"apis"`
+}
+
+type BulkDeleteResult struct {
+	Name    string          `json:"name"`
+	Kind    userconfig.Kind `json:"kind"`
+	Message string          `json:"message,omitempty"`
+	Error   string          `json:"error,omitempty"`
+}
+
+type QuotaUsage struct {
+	Name              string   `json:"name"`
+	Selector          string   `json:"selector"`
+	APIs              []string `json:"apis"`
+	Replicas          int64    `json:"replicas"`
+	MaxReplicas       *int64   `json:"max_replicas"`
+	GPUs              int64    `json:"gpus"`
+	MaxGPUs           *int64   `json:"max_gpus"`
+	ConcurrentJobs    int64    `json:"concurrent_jobs"`
+	MaxConcurrentJobs *int64   `json:"max_concurrent_jobs"`
+}
+
+type ReplicaUtilization struct {
+	APIName        string          `json:"api_name"`
+	APIKind        userconfig.Kind `json:"api_kind"`
+	JobID          string          `json:"job_id,omitempty"`
+	PodName        string          `json:"pod_name"`
+	NodeGroupName  string          `json:"nodegroup_name"`
+	CPU            *k8s.Quantity   `json:"cpu"` // nil if the metrics server hasn't collected the replica's usage yet
+	CPURequested   k8s.Quantity    `json:"cpu_requested"`
+	Mem            *k8s.Quantity   `json:"mem"` // nil if the metrics server hasn't collected the replica's usage yet
+	MemRequested   k8s.Quantity    `json:"mem_requested"`
+	GPUs           int64           `json:"gpus"`
+	GPUUtilization *float64        `json:"gpu_utilization"` // average utilization (0-100) across the replica's GPUs
+	GPUMemUsed     *float64        `json:"gpu_mem_used"`    // total GPU memory used by the replica, in MiB
+}
+
+type APIKey struct {
+	Name      string    `json:"name"`
+	APIs      []string  `json:"apis"` // if empty, the key grants access to all apis
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type CreateAPIKeyResponse struct {
+	APIKey APIKey `json:"api_key"`
+	Key    string `json:"key"`
+}
+
+type RevokeAPIKeyResponse struct {
+	Message string `json:"message"`
+}
+
+type RoleBinding struct {
+	Name      string    `json:"name"`
+	Subject   string    `json:"subject"`
+	Role      rbac.Role `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type DeleteRoleBindingResponse struct {
+	Message string `json:"message"`
+}
+
+type OIDCConfigResponse struct {
+	IssuerURL string   `json:"issuer_url"`
+	ClientID  string   `json:"client_id"`
+	Scopes    []string `json:"scopes"`
+}
+
+type WhoAmIResponse struct {
+	Caller      string    `json:"caller"`
+	Role        rbac.Role `json:"role"` // empty if the caller has no role
+	RBACEnabled bool      `json:"rbac_enabled"`
+}
+
+type Secret struct {
+	Name string   `json:"name"`
+	APIs []string `json:"apis"` // the deployed apis which reference the secret in their env_from_secrets
+}
+
+type SetSecretRequest struct {
+	Value string `json:"value"`
+}
+
+type SetSecretResponse struct {
+	Message string `json:"message"`
+}
+
+type GetSecretResponse struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type DeleteSecretResponse struct {
+	Message string `json:"message"`
+}
+
+type QueuedJob struct {
+	spec.JobKey
+	Position           int            `json:"position"` // 1 is the next job to be started
+	Status             status.JobCode `json:"status"`
+	SubmittedTime      time.Time      `json:"submitted_time"`
+	Dependencies       []spec.JobKey  `json:"dependencies,omitempty"`
+	EstimatedStartTime *time.Time     `json:"estimated_start_time,omitempty"` // nil if it can't be estimated (e.g. none of the jobs of a dependency's api have succeeded recently)
+}
+
+type GitOpsAction string
+
+const (
+	GitOpsActionCreate GitOpsAction = "create"
+	GitOpsActionUpdate GitOpsAction = "update"
+	GitOpsActionNone   GitOpsAction = "none"
+	GitOpsActionDelete GitOpsAction = "delete"
+)
+
+// GitOpsStatus is the state of the reconciliation of the cluster's apis with the gitops repository, which is persisted across operator restarts
+type GitOpsStatus struct {
+	Repository  string            `json:"repository"`
+	Branch      string            `json:"branch"`
+	Path        string            `json:"path"`
+	Commit      string            `json:"commit,omitempty"`       // the most recently applied commit
+	LastSync    *time.Time        `json:"last_sync,omitempty"`    // the last time a commit was applied without errors
+	LastAttempt *time.Time        `json:"last_attempt,omitempty"` // the last time a commit was applied
+	Error       string            `json:"error,omitempty"`        // set if the most recent commit could not be applied at all (e.g. it could not be fetched, or its api configurations are invalid)
+	APIs        []GitOpsAPIStatus `json:"apis"`                   // the apis of the most recently applied commit, and the apis which were deleted because they were removed from the repository
+}
+
+type GitOpsAPIStatus struct {
+	Name   string          `json:"name"`
+	Kind   userconfig.Kind `json:"kind"`
+	ID     string          `json:"id,omitempty"`
+	File   string          `json:"file,omitempty"` // relative to the repository root
+	Action GitOpsAction    `json:"action"`
+	Error  string          `json:"error,omitempty"`
+}
+
+type DeleteJobScheduleResponse struct {
+	Message string `json:"message"`
+}
+
 type RefreshResponse struct {
 	Message string `json:"message"`
 }
 
+type PromoteResponse struct {
+	Message string `json:"message"`
+}
+
+type RedriveResponse struct {
+	Message string `json:"message" yaml:"message"`
+}
+
+type RollbackResponse struct {
+	Message string `json:"message"`
+}
+
 type ErrorResponse struct {
 	Kind    string `json:"kind"`
 	Message string `json:"message"`
 }
 
 type APIVersion struct {
+	Version     int    `json:"version" yaml:"version"` // 1 is the most recent deployment
 	APIID       string `json:"api_id" yaml:"api_id"`
 	LastUpdated int64  `json:"last_updated" yaml:"last_updated"`
+
+	ModelVersion string `json:"model_version,omitempty" yaml:"model_version,omitempty"`
+	GitCommit    string `json:"git_commit,omitempty" yaml:"git_commit,omitempty"`
+
+	ImageDigests map[string]string `json:"image_digests,omitempty" yaml:"image_digests,omitempty"` // keyed by container name
 }
 
 type VerifyCortexResponse struct{}
 
+// the first byte of each binary websocket message of an exec session identifies its stream
+const (
+	ExecStdinStream    byte = 0 // client -> operator; an empty message closes the command's stdin
+	ExecStdoutStream   byte = 1 // operator -> client
+	ExecStderrStream   byte = 2 // operator -> client (only used if a tty was not requested)
+	ExecResizeStream   byte = 3 // client -> operator; the message is a json-encoded ExecTerminalSize
+	ExecExitCodeStream byte = 4 // operator -> client; the command's exit code, sent before the socket is closed
+)
+
+// ReplicaHeader is the header of the websocket handshake response which holds the name of the replica that an exec or port-forward session is connected to
+const ReplicaHeader = "Cortex-Replica"
+
+type ExecTerminalSize struct {
+	Width  uint16 `json:"width"`
+	Height uint16 `json:"height"`
+}
+
+// each forwarded connection is tunnelled through its own websocket as binary messages; an empty message signals the end of the sender's data
+
 func (ir InfoResponse) GetNodesWithNodeGroupName(ngName string) []WorkerNodeInfo {
 	nodesInfo := []WorkerNodeInfo{}
 	for _, nodeInfo := range ir.WorkerNodeInfos {
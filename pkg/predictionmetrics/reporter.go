@@ -0,0 +1,120 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package predictionmetrics
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Path is the admin server path on which user containers post prediction metadata
+const Path = "/prediction-metrics"
+
+const _maxBodyBytes = 1 << 20
+
+// Reporter exposes one histogram per declared prediction metric; every observation
+// posted by the user container is recorded into the histogram of the matching metric
+type Reporter struct {
+	histograms map[string]prometheus.Histogram
+}
+
+func NewReporter(registerer prometheus.Registerer, metrics []*userconfig.PredictionMetric) (*Reporter, error) {
+	histograms := make(map[string]prometheus.Histogram, len(metrics))
+	for _, metric := range metrics {
+		histogram := prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:        "cortex_prediction_metric",
+			Help:        "Histogram of the prediction metadata reported by the user container of a cortex API",
+			ConstLabels: prometheus.Labels{"metric": metric.Name},
+			Buckets:     metric.Buckets,
+		})
+		if err := registerer.Register(histogram); err != nil {
+			return nil, err
+		}
+		histograms[metric.Name] = histogram
+	}
+
+	return &Reporter{
+		histograms: histograms,
+	}, nil
+}
+
+// ServeHTTP accepts either a single JSON object mapping metric names to values
+// (e.g. {"confidence": 0.93}) or a JSON list of such objects
+func (r *Reporter) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(req.Body, _maxBodyBytes))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	observations, err := parseObservations(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// validate everything before recording anything so that a bad payload is rejected as a whole
+	for _, observation := range observations {
+		for name := range observation {
+			if _, ok := r.histograms[name]; !ok {
+				http.Error(w, fmt.Sprintf("prediction metric %s is not declared in the api configuration", name), http.StatusBadRequest)
+				return
+			}
+		}
+	}
+
+	for _, observation := range observations {
+		for name, value := range observation {
+			r.histograms[name].Observe(value)
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func parseObservations(body []byte) ([]map[string]float64, error) {
+	body = bytes.TrimSpace(body)
+	if len(body) == 0 {
+		return nil, fmt.Errorf("request body is empty")
+	}
+
+	if body[0] == '[' {
+		var observations []map[string]float64
+		if err := json.Unmarshal(body, &observations); err != nil {
+			return nil, fmt.Errorf("request body must be a json object (or list of objects) mapping metric names to numbers: %s", err.Error())
+		}
+		return observations, nil
+	}
+
+	var observation map[string]float64
+	if err := json.Unmarshal(body, &observation); err != nil {
+		return nil, fmt.Errorf("request body must be a json object (or list of objects) mapping metric names to numbers: %s", err.Error())
+	}
+	return []map[string]float64{observation}, nil
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package predictionmetrics_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cortexlabs/cortex/pkg/predictionmetrics"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func newReporter(t *testing.T) (*predictionmetrics.Reporter, *prometheus.Registry) {
+	t.Helper()
+
+	registry := prometheus.NewRegistry()
+	reporter, err := predictionmetrics.NewReporter(registry, []*userconfig.PredictionMetric{
+		{Name: "confidence", Buckets: []float64{0.5, 0.9}},
+		{Name: "input_length", Buckets: []float64{10, 100, 1000}},
+	})
+	require.NoError(t, err)
+
+	return reporter, registry
+}
+
+func post(reporter http.Handler, body string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodPost, predictionmetrics.Path, strings.NewReader(body))
+	w := httptest.NewRecorder()
+	reporter.ServeHTTP(w, r)
+	return w
+}
+
+func TestReporter_SingleObservation(t *testing.T) {
+	t.Parallel()
+
+	reporter, registry := newReporter(t)
+
+	w := post(reporter, `{"confidence": 0.95, "input_length": 42}`)
+	require.Equal(t, http.StatusNoContent, w.Code)
+
+	count, err := testutil.GatherAndCount(registry, "cortex_prediction_metric")
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+
+	expected := `
+# HELP cortex_prediction_metric Histogram of the prediction metadata reported by the user container of a cortex API
+# TYPE cortex_prediction_metric histogram
+cortex_prediction_metric_bucket{metric="confidence",le="0.5"} 0
+cortex_prediction_metric_bucket{metric="confidence",le="0.9"} 0
+cortex_prediction_metric_bucket{metric="confidence",le="+Inf"} 1
+cortex_prediction_metric_sum{metric="confidence"} 0.95
+cortex_prediction_metric_count{metric="confidence"} 1
+cortex_prediction_metric_bucket{metric="input_length",le="10"} 0
+cortex_prediction_metric_bucket{metric="input_length",le="100"} 1
+cortex_prediction_metric_bucket{metric="input_length",le="1000"} 1
+cortex_prediction_metric_bucket{metric="input_length",le="+Inf"} 1
+cortex_prediction_metric_sum{metric="input_length"} 42
+cortex_prediction_metric_count{metric="input_length"} 1
+`
+	require.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(expected), "cortex_prediction_metric"))
+}
+
+func TestReporter_ListOfObservations(t *testing.T) {
+	t.Parallel()
+
+	reporter, registry := newReporter(t)
+
+	w := post(reporter, `[{"confidence": 0.1}, {"confidence": 0.7}, {"confidence": 0.8}]`)
+	require.Equal(t, http.StatusNoContent, w.Code)
+
+	expected := `
+# HELP cortex_prediction_metric Histogram of the prediction metadata reported by the user container of a cortex API
+# TYPE cortex_prediction_metric histogram
+cortex_prediction_metric_bucket{metric="confidence",le="0.5"} 1
+cortex_prediction_metric_bucket{metric="confidence",le="0.9"} 3
+cortex_prediction_metric_bucket{metric="confidence",le="+Inf"} 3
+cortex_prediction_metric_sum{metric="confidence"} 1.6
+cortex_prediction_metric_count{metric="confidence"} 3
+cortex_prediction_metric_bucket{metric="input_length",le="10"} 0
+cortex_prediction_metric_bucket{metric="input_length",le="100"} 0
+cortex_prediction_metric_bucket{metric="input_length",le="1000"} 0
+cortex_prediction_metric_bucket{metric="input_length",le="+Inf"} 0
+cortex_prediction_metric_sum{metric="input_length"} 0
+cortex_prediction_metric_count{metric="input_length"} 0
+`
+	require.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(expected), "cortex_prediction_metric"))
+}
+
+func TestReporter_RejectsInvalidPayloads(t *testing.T) {
+	t.Parallel()
+
+	reporter, registry := newReporter(t)
+
+	for _, body := range []string{
+		``,
+		`{"confidence": "high"}`,
+		`{"confidence": 0.4, "unknown": 1}`,
+		`[{"confidence": 0.4}, 3]`,
+	} {
+		w := post(reporter, body)
+		require.Equal(t, http.StatusBadRequest, w.Code, body)
+	}
+
+	// rejected payloads must not record partial observations
+	families, err := registry.Gather()
+	require.NoError(t, err)
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			require.Zero(t, metric.GetHistogram().GetSampleCount())
+		}
+	}
+}
+
+func TestReporter_MethodNotAllowed(t *testing.T) {
+	t.Parallel()
+
+	reporter, _ := newReporter(t)
+
+	r := httptest.NewRequest(http.MethodGet, predictionmetrics.Path, nil)
+	w := httptest.NewRecorder()
+	reporter.ServeHTTP(w, r)
+
+	require.Equal(t, http.StatusMethodNotAllowed, w.Code)
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/apikeys"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/telemetry"
+	"go.uber.org/zap"
+)
+
+const _apiKeysReloadInterval = 10 * time.Second
+
+// APIKeyAuthenticator verifies api keys against the hashed keys in a directory (the mounted api keys secret),
+// which is reloaded periodically so that created and revoked keys take effect without restarting the proxy
+type APIKeyAuthenticator struct {
+	apiName string
+	dir     string
+	logger  *zap.SugaredLogger
+	mux     sync.RWMutex
+	keys    map[string]*apikeys.Key // keyed by hash
+}
+
+func NewAPIKeyAuthenticator(apiName string, dir string, logger *zap.SugaredLogger) (*APIKeyAuthenticator, error) {
+	authenticator := &APIKeyAuthenticator{
+		apiName: apiName,
+		dir:     dir,
+		logger:  logger,
+	}
+	if err := authenticator.load(); err != nil {
+		return nil, err
+	}
+	return authenticator, nil
+}
+
+// Run reloads the api keys periodically until the context is cancelled
+func (a *APIKeyAuthenticator) Run(ctx context.Context) {
+	ticker := time.NewTicker(_apiKeysReloadInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := a.load(); err != nil {
+				a.logger.Errorw("failed to reload api keys", zap.Error(err))
+				telemetry.Error(err)
+			}
+		}
+	}
+}
+
+func (a *APIKeyAuthenticator) load() error {
+	entries, err := os.ReadDir(a.dir)
+	if err != nil && !os.IsNotExist(err) {
+		return errors.WithStack(err)
+	}
+
+	keys := map[string]*apikeys.Key{}
+	for _, entry := range entries {
+		// kubernetes secret volumes contain hidden directories and symlinks which are used for atomic updates
+		if strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+
+		keyBytes, err := os.ReadFile(filepath.Join(a.dir, entry.Name()))
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		var key apikeys.Key
+		if err := json.Unmarshal(keyBytes, &key); err != nil {
+			return errors.Wrap(errors.WithStack(err), entry.Name())
+		}
+		if key.CanAccess(a.apiName) {
+			keys[key.Hash] = &key
+		}
+	}
+
+	a.mux.Lock()
+	a.keys = keys
+	a.mux.Unlock()
+
+	return nil
+}
+
+func (a *APIKeyAuthenticator) authenticate(key string) bool {
+	if key == "" {
+		return false
+	}
+
+	a.mux.RLock()
+	defer a.mux.RUnlock()
+	_, ok := a.keys[apikeys.Hash(key)]
+	return ok
+}
+
+// APIKeyAuthHandler responds with 401 to requests which don't include a valid api key; the api key header is not forwarded
+func APIKeyAuthHandler(authenticator *APIKeyAuthenticator, next http.Handler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// kubelet probes are not exempt, since the user agent header can be set by any client
+		if authenticator == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if !authenticator.authenticate(r.Header.Get(apikeys.Header)) {
+			http.Error(w, "missing or invalid api key (expected in the "+apikeys.Header+" header)", http.StatusUnauthorized)
+			return
+		}
+		r.Header.Del(apikeys.Header)
+
+		next.ServeHTTP(w, r)
+	}
+}
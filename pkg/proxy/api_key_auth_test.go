@@ -0,0 +1,91 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proxy_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cortexlabs/cortex/pkg/apikeys"
+	"github.com/cortexlabs/cortex/pkg/proxy"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func writeAPIKey(t *testing.T, dir string, name string, apis []string) string {
+	t.Helper()
+
+	key, err := apikeys.Generate()
+	require.NoError(t, err)
+
+	keyBytes, err := json.Marshal(apikeys.Key{Name: name, Hash: apikeys.Hash(key), APIs: apis})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), keyBytes, 0644))
+
+	return key
+}
+
+func sendAuthenticatedRequest(handler http.Handler, key string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+	if key != "" {
+		r.Header.Set(apikeys.Header, key)
+	}
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	return w
+}
+
+func TestAPIKeyAuthHandler(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	allAPIsKey := writeAPIKey(t, dir, "all-apis", nil)
+	thisAPIKey := writeAPIKey(t, dir, "this-api", []string{"other-api", "my-api"})
+	otherAPIKey := writeAPIKey(t, dir, "other-api", []string{"other-api"})
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "..data"), 0755))
+
+	authenticator, err := proxy.NewAPIKeyAuthenticator("my-api", dir, zap.NewNop().Sugar())
+	require.NoError(t, err)
+
+	var forwardedKey string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		forwardedKey = r.Header.Get(apikeys.Header)
+	})
+	handler := proxy.APIKeyAuthHandler(authenticator, next)
+
+	require.Equal(t, http.StatusOK, sendAuthenticatedRequest(handler, allAPIsKey).Code)
+	require.Equal(t, http.StatusOK, sendAuthenticatedRequest(handler, thisAPIKey).Code)
+	require.Empty(t, forwardedKey)
+
+	require.Equal(t, http.StatusUnauthorized, sendAuthenticatedRequest(handler, otherAPIKey).Code)
+	require.Equal(t, http.StatusUnauthorized, sendAuthenticatedRequest(handler, "ck_invalid").Code)
+	require.Equal(t, http.StatusUnauthorized, sendAuthenticatedRequest(handler, "").Code)
+}
+
+func TestAPIKeyAuthenticatorMissingDir(t *testing.T) {
+	t.Parallel()
+
+	authenticator, err := proxy.NewAPIKeyAuthenticator("my-api", filepath.Join(t.TempDir(), "missing"), zap.NewNop().Sugar())
+	require.NoError(t, err)
+
+	handler := proxy.APIKeyAuthHandler(authenticator, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	require.Equal(t, http.StatusUnauthorized, sendAuthenticatedRequest(handler, "ck_invalid").Code)
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proxy
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/cortexlabs/cortex/pkg/consts"
+	"github.com/cortexlabs/cortex/pkg/lib/aws"
+	"github.com/cortexlabs/cortex/pkg/lib/hash"
+	"github.com/patrickmn/go-cache"
+)
+
+// identities are cached so that STS isn't called for every request; the signed identity requests expire after 15 minutes
+const _awsIAMIdentityCacheDuration = 5 * time.Minute
+
+// AWSIAMAuthenticator verifies the caller's identity by executing the signed STS GetCallerIdentity request
+// which is included in each request (the same mechanism which the CLI uses to authenticate with the operator)
+type AWSIAMAuthenticator struct {
+	principals        []string
+	getCallerIdentity func(identityRequestHeader string) (*sts.GetCallerIdentityOutput, error)
+	identities        *cache.Cache // identity request hash -> caller arn
+}
+
+func NewAWSIAMAuthenticator(principals []string, getCallerIdentity func(string) (*sts.GetCallerIdentityOutput, error)) *AWSIAMAuthenticator {
+	return &AWSIAMAuthenticator{
+		principals:        principals,
+		getCallerIdentity: getCallerIdentity,
+		identities:        cache.New(_awsIAMIdentityCacheDuration, 2*_awsIAMIdentityCacheDuration),
+	}
+}
+
+// callerARN returns the arn of the identity which signed the identity request
+func (a *AWSIAMAuthenticator) callerARN(identityRequestHeader string) (string, error) {
+	key := hash.String(identityRequestHeader)
+	if callerARN, ok := a.identities.Get(key); ok {
+		return callerARN.(string), nil
+	}
+
+	identity, err := a.getCallerIdentity(identityRequestHeader)
+	if err != nil {
+		return "", err
+	}
+
+	a.identities.SetDefault(key, *identity.Arn)
+	return *identity.Arn, nil
+}
+
+func (a *AWSIAMAuthenticator) isAuthorized(callerARN string) bool {
+	for _, principal := range a.principals {
+		if aws.PrincipalMatchesCaller(principal, callerARN) {
+			return true
+		}
+	}
+	return false
+}
+
+// AWSIAMAuthHandler responds with 401 to requests which don't include a valid signed identity request,
+// and with 403 to requests from callers which aren't one of the authorized principals; the identity request header is not forwarded
+func AWSIAMAuthHandler(authenticator *AWSIAMAuthenticator, next http.Handler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if authenticator == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		identityRequestHeader := r.Header.Get(consts.AuthHeader)
+		if identityRequestHeader == "" {
+			http.Error(w, "missing "+consts.AuthHeader+" header", http.StatusUnauthorized)
+			return
+		}
+
+		callerARN, err := authenticator.callerARN(identityRequestHeader)
+		if err != nil {
+			http.Error(w, "unable to verify the caller's aws credentials: "+err.Error(), http.StatusUnauthorized)
+			return
+		}
+
+		if !authenticator.isAuthorized(callerARN) {
+			http.Error(w, callerARN+" is not authorized to access this api", http.StatusForbidden)
+			return
+		}
+		r.Header.Del(consts.AuthHeader)
+
+		next.ServeHTTP(w, r)
+	}
+}
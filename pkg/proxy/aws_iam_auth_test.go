@@ -0,0 +1,104 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proxy_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/cortexlabs/cortex/pkg/consts"
+	"github.com/cortexlabs/cortex/pkg/proxy"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestAWSIAMAuthHandler(principals []string, numIdentityRequests *int) http.Handler {
+	// the fake identity request header is the caller's arn
+	getCallerIdentity := func(identityRequestHeader string) (*sts.GetCallerIdentityOutput, error) {
+		*numIdentityRequests++
+		if identityRequestHeader == "invalid" {
+			return nil, errors.New("invalid identity request")
+		}
+		return &sts.GetCallerIdentityOutput{Arn: aws.String(identityRequestHeader)}, nil
+	}
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(consts.AuthHeader) != "" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	})
+	return proxy.AWSIAMAuthHandler(proxy.NewAWSIAMAuthenticator(principals, getCallerIdentity), next)
+}
+
+func sendAWSIAMAuthRequest(handler http.Handler, identityRequestHeader string) int {
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+	if identityRequestHeader != "" {
+		r.Header.Set(consts.AuthHeader, identityRequestHeader)
+	}
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	return w.Code
+}
+
+func TestAWSIAMAuthHandler(t *testing.T) {
+	t.Parallel()
+
+	principals := []string{
+		"111111111111",
+		"arn:aws:iam::222222222222:role/service-role/my-role",
+		"arn:aws:iam::333333333333:user/my-user",
+	}
+	var numIdentityRequests int
+	handler := newTestAWSIAMAuthHandler(principals, &numIdentityRequests)
+
+	for _, callerARN := range []string{
+		"arn:aws:iam::111111111111:user/anyone",
+		"arn:aws:sts::111111111111:assumed-role/any-role/session",
+		"arn:aws:sts::222222222222:assumed-role/my-role/session",
+		"arn:aws:iam::333333333333:user/my-user",
+	} {
+		require.Equal(t, http.StatusOK, sendAWSIAMAuthRequest(handler, callerARN), callerARN)
+	}
+
+	for _, callerARN := range []string{
+		"arn:aws:sts::222222222222:assumed-role/my-role-2/session",
+		"arn:aws:sts::444444444444:assumed-role/my-role/session",
+		"arn:aws:iam::222222222222:user/my-role",
+		"arn:aws:iam::333333333333:user/other-user",
+		"arn:aws-cn:iam::333333333333:user/my-user",
+	} {
+		require.Equal(t, http.StatusForbidden, sendAWSIAMAuthRequest(handler, callerARN), callerARN)
+	}
+
+	require.Equal(t, http.StatusUnauthorized, sendAWSIAMAuthRequest(handler, "invalid"))
+	require.Equal(t, http.StatusUnauthorized, sendAWSIAMAuthRequest(handler, ""))
+}
+
+func TestAWSIAMAuthHandlerCachesIdentities(t *testing.T) {
+	t.Parallel()
+
+	var numIdentityRequests int
+	handler := newTestAWSIAMAuthHandler([]string{"111111111111"}, &numIdentityRequests)
+
+	for i := 0; i < 3; i++ {
+		require.Equal(t, http.StatusOK, sendAWSIAMAuthRequest(handler, "arn:aws:iam::111111111111:user/my-user"))
+	}
+	require.Equal(t, 1, numIdentityRequests)
+}
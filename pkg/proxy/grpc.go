@@ -0,0 +1,102 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proxy
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// NewGRPCReverseProxy creates a reverse proxy which forwards requests to the target over http/2 without tls (h2c),
+// flushing the responses immediately so that streaming rpcs aren't buffered
+func NewGRPCReverseProxy(target string) *httputil.ReverseProxy {
+	targetURL, err := url.Parse(target)
+	if err != nil {
+		panic(err)
+	}
+
+	httpProxy := httputil.NewSingleHostReverseProxy(targetURL)
+	httpProxy.Transport = &http2.Transport{
+		AllowHTTP: true,
+		DialTLSContext: func(ctx context.Context, network string, addr string, _ *tls.Config) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, network, addr)
+		},
+	}
+	httpProxy.FlushInterval = -1
+	httpProxy.ErrorHandler = reverseProxyErrorHandler
+
+	return httpProxy
+}
+
+// GRPCHandler accepts http/2 requests without tls (h2c), which is how grpc requests are forwarded by the ingress gateway;
+// http/1.1 requests (e.g. from probes) are passed to the handler as is
+func GRPCHandler(next http.Handler) http.Handler {
+	return h2c.NewHandler(next, &http2.Server{})
+}
+
+// GRPCHealthChecker checks the health of a grpc server with the standard health checking protocol (grpc.health.v1.Health)
+type GRPCHealthChecker struct {
+	conn   *grpc.ClientConn
+	client grpc_health_v1.HealthClient
+}
+
+func NewGRPCHealthChecker(target string) (*GRPCHealthChecker, error) {
+	targetURL, err := url.Parse(target)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := grpc.Dial(targetURL.Host, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, err
+	}
+
+	return &GRPCHealthChecker{
+		conn:   conn,
+		client: grpc_health_v1.NewHealthClient(conn),
+	}, nil
+}
+
+// Check returns an error unless the server reports that it is serving (the server's overall health is checked, i.e. the service name is empty)
+func (c *GRPCHealthChecker) Check(ctx context.Context) error {
+	res, err := c.client.Check(ctx, &grpc_health_v1.HealthCheckRequest{})
+	if err != nil {
+		return err
+	}
+
+	if res.Status != grpc_health_v1.HealthCheckResponse_SERVING {
+		return errors.ErrorUnexpected("grpc health check status is " + res.Status.String())
+	}
+
+	return nil
+}
+
+func (c *GRPCHealthChecker) Close() error {
+	return c.conn.Close()
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proxy_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/proxy"
+	"github.com/stretchr/testify/require"
+)
+
+func newLimitsTestServer(t *testing.T, handler func(http.Handler) http.Handler) *httptest.Server {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			select {
+			case <-time.After(5 * time.Second):
+			case <-r.Context().Done():
+				return
+			}
+		}
+		body, _ := io.ReadAll(r.Body)
+		_, _ = w.Write(body)
+	}))
+	t.Cleanup(backend.Close)
+
+	server := httptest.NewServer(handler(proxy.NewReverseProxy(backend.URL, 10, 10)))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestMaxRequestBodySizeHandler(t *testing.T) {
+	t.Parallel()
+
+	server := newLimitsTestServer(t, func(next http.Handler) http.Handler {
+		return proxy.MaxRequestBodySizeHandler(10, next)
+	})
+
+	res, err := http.Post(server.URL, "text/plain", strings.NewReader("0123456789"))
+	require.NoError(t, err)
+	body, _ := io.ReadAll(res.Body)
+	res.Body.Close()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	require.Equal(t, "0123456789", string(body))
+
+	res, err = http.Post(server.URL, "text/plain", strings.NewReader("0123456789a"))
+	require.NoError(t, err)
+	res.Body.Close()
+	require.Equal(t, http.StatusRequestEntityTooLarge, res.StatusCode)
+
+	// the content length is unknown, so the limit is enforced while the body is read
+	res, err = http.Post(server.URL, "text/plain", io.MultiReader(strings.NewReader("0123456789"), strings.NewReader("a")))
+	require.NoError(t, err)
+	res.Body.Close()
+	require.Equal(t, http.StatusRequestEntityTooLarge, res.StatusCode)
+}
+
+func TestRequestTimeoutHandler(t *testing.T) {
+	t.Parallel()
+
+	server := newLimitsTestServer(t, func(next http.Handler) http.Handler {
+		return proxy.RequestTimeoutHandler(100*time.Millisecond, next)
+	})
+
+	res, err := http.Get(server.URL + "/fast")
+	require.NoError(t, err)
+	res.Body.Close()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+
+	start := time.Now()
+	res, err = http.Get(server.URL + "/slow")
+	require.NoError(t, err)
+	res.Body.Close()
+	require.Equal(t, http.StatusGatewayTimeout, res.StatusCode)
+	require.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestLimitHandlersDisabled(t *testing.T) {
+	t.Parallel()
+
+	server := newLimitsTestServer(t, func(next http.Handler) http.Handler {
+		return proxy.RequestTimeoutHandler(0, proxy.MaxRequestBodySizeHandler(0, next))
+	})
+
+	res, err := http.Post(server.URL, "text/plain", strings.NewReader(strings.Repeat("a", 1<<20)))
+	require.NoError(t, err)
+	body, _ := io.ReadAll(res.Body)
+	res.Body.Close()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	require.Len(t, body, 1<<20)
+}
@@ -33,6 +33,7 @@ func NewReverseProxy(target string, maxIdle, maxIdlePerHost int) *httputil.Rever
 
 	httpProxy := httputil.NewSingleHostReverseProxy(targetURL)
 	httpProxy.Transport = buildHTTPTransport(maxIdle, maxIdlePerHost)
+	httpProxy.ErrorHandler = reverseProxyErrorHandler
 
 	return httpProxy
 }
@@ -0,0 +1,134 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proxy
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/probe"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+	"golang.org/x/time/rate"
+)
+
+const (
+	_rateLimiterCleanupInterval = time.Minute
+	_rateLimiterIdleTimeout     = 10 * time.Minute
+
+	// set by the istio ingress gateway to the address of the client which sent the request
+	_externalAddressHeader = "X-Envoy-External-Address"
+)
+
+// RateLimiter limits the rate of requests per client, where clients are identified by their IP address
+// or by the value of a configurable request header (e.g. an API key)
+type RateLimiter struct {
+	config      userconfig.RateLimit
+	mux         sync.Mutex
+	clients     map[string]*clientLimiter
+	lastCleanup time.Time
+}
+
+type clientLimiter struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+func NewRateLimiter(config userconfig.RateLimit) *RateLimiter {
+	return &RateLimiter{
+		config:      config,
+		clients:     map[string]*clientLimiter{},
+		lastCleanup: time.Now(),
+	}
+}
+
+// reserve consumes a token from the client's bucket if one is available; otherwise, it returns
+// the amount of time after which the client's next request will be allowed
+func (l *RateLimiter) reserve(clientKey string) (bool, time.Duration) {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+
+	now := time.Now()
+	l.cleanup(now)
+
+	client, ok := l.clients[clientKey]
+	if !ok {
+		client = &clientLimiter{
+			limiter: rate.NewLimiter(rate.Limit(l.config.RequestsPerSecond), int(l.config.Burst)),
+		}
+		l.clients[clientKey] = client
+	}
+	client.lastSeen = now
+
+	reservation := client.limiter.ReserveN(now, 1)
+	if delay := reservation.DelayFrom(now); delay > 0 {
+		reservation.CancelAt(now)
+		return false, delay
+	}
+	return true, 0
+}
+
+// cleanup forgets clients which haven't sent a request recently, so that memory usage is bounded (not thread safe)
+func (l *RateLimiter) cleanup(now time.Time) {
+	if now.Sub(l.lastCleanup) < _rateLimiterCleanupInterval {
+		return
+	}
+	for clientKey, client := range l.clients {
+		if now.Sub(client.lastSeen) > _rateLimiterIdleTimeout {
+			delete(l.clients, clientKey)
+		}
+	}
+	l.lastCleanup = now
+}
+
+func (l *RateLimiter) clientKey(r *http.Request) string {
+	if l.config.KeyHeader != nil {
+		if key := r.Header.Get(*l.config.KeyHeader); key != "" {
+			return "key:" + key
+		}
+	}
+
+	if addr := r.Header.Get(_externalAddressHeader); addr != "" {
+		return "ip:" + addr
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return "ip:" + r.RemoteAddr
+	}
+	return "ip:" + host
+}
+
+// RateLimitHandler responds with 429 (and a Retry-After header) to requests from clients which have exceeded the rate limit
+func RateLimitHandler(rateLimiter *RateLimiter, next http.Handler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if rateLimiter == nil || probe.IsRequestKubeletProbe(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if ok, retryAfter := rateLimiter.reserve(rateLimiter.clientKey(r)); !ok {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			http.Error(w, "rate limit exceeded", http.StatusTooManyRequests)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	}
+}
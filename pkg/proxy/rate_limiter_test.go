@@ -0,0 +1,103 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proxy_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cortexlabs/cortex/pkg/consts"
+	"github.com/cortexlabs/cortex/pkg/lib/pointer"
+	"github.com/cortexlabs/cortex/pkg/proxy"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+	"github.com/stretchr/testify/require"
+)
+
+func sendRateLimitedRequest(handler http.Handler, remoteAddr string, headers map[string]string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+	r.RemoteAddr = remoteAddr
+	for key, value := range headers {
+		r.Header.Set(key, value)
+	}
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	return w
+}
+
+func TestRateLimitHandlerPerClientIP(t *testing.T) {
+	t.Parallel()
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	handler := proxy.RateLimitHandler(proxy.NewRateLimiter(userconfig.RateLimit{RequestsPerSecond: 0.5, Burst: 2}), next)
+
+	for i := 0; i < 2; i++ {
+		require.Equal(t, http.StatusOK, sendRateLimitedRequest(handler, "10.0.0.1:1234", nil).Code)
+	}
+
+	w := sendRateLimitedRequest(handler, "10.0.0.1:5678", nil)
+	require.Equal(t, http.StatusTooManyRequests, w.Code)
+	require.Equal(t, "2", w.Header().Get("Retry-After"))
+
+	// other clients are not affected
+	require.Equal(t, http.StatusOK, sendRateLimitedRequest(handler, "10.0.0.2:1234", nil).Code)
+
+	// the client address set by the ingress gateway takes precedence over the remote address
+	headers := map[string]string{"X-Envoy-External-Address": "10.0.0.1"}
+	require.Equal(t, http.StatusTooManyRequests, sendRateLimitedRequest(handler, "10.0.0.3:1234", headers).Code)
+}
+
+func TestRateLimitHandlerPerKeyHeader(t *testing.T) {
+	t.Parallel()
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	rateLimit := userconfig.RateLimit{RequestsPerSecond: 0.5, Burst: 1, KeyHeader: pointer.String("X-Api-Key")}
+	handler := proxy.RateLimitHandler(proxy.NewRateLimiter(rateLimit), next)
+
+	require.Equal(t, http.StatusOK, sendRateLimitedRequest(handler, "10.0.0.1:1234", map[string]string{"X-Api-Key": "a"}).Code)
+	require.Equal(t, http.StatusTooManyRequests, sendRateLimitedRequest(handler, "10.0.0.2:1234", map[string]string{"X-Api-Key": "a"}).Code)
+
+	// requests with a different key (or without a key, which fall back to the client's IP) are counted separately
+	require.Equal(t, http.StatusOK, sendRateLimitedRequest(handler, "10.0.0.1:1234", map[string]string{"X-Api-Key": "b"}).Code)
+	require.Equal(t, http.StatusOK, sendRateLimitedRequest(handler, "10.0.0.1:1234", nil).Code)
+}
+
+func TestRateLimitHandlerIgnoresKubeletProbes(t *testing.T) {
+	t.Parallel()
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	handler := proxy.RateLimitHandler(proxy.NewRateLimiter(userconfig.RateLimit{RequestsPerSecond: 0.5, Burst: 1}), next)
+
+	probeHeaders := map[string]string{consts.UserAgentKey: consts.KubeProbeUserAgentPrefix + "1.21"}
+	for i := 0; i < 3; i++ {
+		require.Equal(t, http.StatusOK, sendRateLimitedRequest(handler, "10.0.0.1:1234", probeHeaders).Code)
+	}
+
+	// probes don't consume the client's tokens
+	require.Equal(t, http.StatusOK, sendRateLimitedRequest(handler, "10.0.0.1:1234", nil).Code)
+}
+
+func TestRateLimitHandlerNilRateLimiter(t *testing.T) {
+	t.Parallel()
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	handler := proxy.RateLimitHandler(nil, next)
+
+	for i := 0; i < 10; i++ {
+		require.Equal(t, http.StatusOK, sendRateLimitedRequest(handler, "10.0.0.1:1234", nil).Code)
+	}
+}
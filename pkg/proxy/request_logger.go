@@ -0,0 +1,447 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"os"
+	"path"
+	"time"
+	"unicode/utf8"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/kinesis"
+	awslib "github.com/cortexlabs/cortex/pkg/lib/aws"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/sets/strset"
+	"github.com/cortexlabs/cortex/pkg/lib/telemetry"
+	"github.com/cortexlabs/cortex/pkg/probe"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+	"go.uber.org/atomic"
+	"go.uber.org/zap"
+)
+
+const (
+	_requestLogBufferSize    = 1000
+	_requestLogMaxBatchSize  = 500 // the maximum number of records per kinesis PutRecords request
+	_requestLogFlushInterval = 10 * time.Second
+	_maxLoggedBodyBytes      = 256 * 1024
+
+	// the maximum size of a kinesis PutRecords request
+	_kinesisMaxBatchBytes        = 5 * 1024 * 1024
+	_kinesisMaxPutRecordsRetries = 3
+
+	_redactedValue = "[REDACTED]"
+)
+
+// headers which are always redacted from request logs
+var _defaultRedactedHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie", "X-Cortex-Authorization", "X-Api-Key"}
+
+// RequestLogRecord is a sampled request/response pair
+type RequestLogRecord struct {
+	Timestamp time.Time         `json:"timestamp"`
+	APIName   string            `json:"api_name"`
+	RequestID string            `json:"request_id,omitempty"`
+	Method    string            `json:"method"`
+	Path      string            `json:"path"`
+	Query     string            `json:"query,omitempty"`
+	LatencyMS float64           `json:"latency_ms"`
+	Request   RequestLogMessage `json:"request"`
+	Response  RequestLogMessage `json:"response"`
+}
+
+// RequestLogMessage is the request or the response of a RequestLogRecord; JSON bodies are stored as JSON,
+// other UTF-8 bodies are stored as strings, and binary bodies are stored as base64-encoded strings
+type RequestLogMessage struct {
+	StatusCode    int         `json:"status_code,omitempty"`
+	Headers       http.Header `json:"headers"`
+	Body          interface{} `json:"body,omitempty"`
+	BodyEncoding  string      `json:"body_encoding,omitempty"`
+	BodyTruncated bool        `json:"body_truncated,omitempty"`
+}
+
+// Redactor removes sensitive information (e.g. PII) from a record before it's written
+type Redactor func(record *RequestLogRecord)
+
+// RequestLogSink writes batches of JSON-encoded records
+type RequestLogSink interface {
+	Write(records [][]byte) error
+}
+
+// RequestLogger samples requests and writes request/response pairs to a RequestLogSink
+type RequestLogger struct {
+	apiName          string
+	samplePercentage float64
+	redactors        []Redactor
+	sink             RequestLogSink
+	records          chan []byte
+	dropped          *atomic.Int64
+	flushInterval    time.Duration
+	logger           *zap.SugaredLogger
+}
+
+// NewRequestLogger creates a RequestLogger which redacts the default headers, along with the headers and JSON fields
+// in the request logging configuration; additional redactors are applied after those
+func NewRequestLogger(apiName string, config userconfig.RequestLogging, sink RequestLogSink, logger *zap.SugaredLogger, redactors ...Redactor) *RequestLogger {
+	return &RequestLogger{
+		apiName:          apiName,
+		samplePercentage: config.SamplePercentage,
+		redactors: append([]Redactor{
+			HeaderRedactor(append(_defaultRedactedHeaders, config.RedactHeaders...)...),
+			JSONFieldRedactor(config.RedactFields...),
+		}, redactors...),
+		sink:          sink,
+		records:       make(chan []byte, _requestLogBufferSize),
+		dropped:       atomic.NewInt64(0),
+		flushInterval: _requestLogFlushInterval,
+		logger:        logger,
+	}
+}
+
+// NewRequestLogSink creates a RequestLogSink for the destination in the request logging configuration
+func NewRequestLogSink(awsClient *awslib.Client, config userconfig.RequestLogging) (RequestLogSink, error) {
+	if config.KinesisStream != nil {
+		return &kinesisRequestLogSink{awsClient: awsClient, stream: *config.KinesisStream}, nil
+	}
+	if config.S3Path != nil {
+		bucket, prefix, err := awslib.SplitS3Path(*config.S3Path)
+		if err != nil {
+			return nil, err
+		}
+		hostname, _ := os.Hostname()
+		return &s3RequestLogSink{awsClient: awsClient, bucket: bucket, prefix: prefix, hostname: hostname}, nil
+	}
+	return nil, errors.ErrorUnexpected("request logging destination is not configured")
+}
+
+// Run flushes the buffered records periodically until the context is cancelled, and then flushes the remaining records
+func (l *RequestLogger) Run(ctx context.Context) {
+	ticker := time.NewTicker(l.flushInterval)
+	defer ticker.Stop()
+
+	var batch [][]byte
+	flush := func() {
+		if dropped := l.dropped.Swap(0); dropped > 0 {
+			l.logger.Warnf("dropped %d request log record(s) because the buffer was full", dropped)
+		}
+		if len(batch) == 0 {
+			return
+		}
+		if err := l.sink.Write(batch); err != nil {
+			err = errors.Wrap(err, "failed to write request logs")
+			telemetry.Error(err)
+			l.logger.Error(err)
+		}
+		batch = nil
+	}
+
+	for {
+		select {
+		case record := <-l.records:
+			batch = append(batch, record)
+			if len(batch) >= _requestLogMaxBatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-ctx.Done():
+			for {
+				select {
+				case record := <-l.records:
+					batch = append(batch, record)
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
+
+func (l *RequestLogger) sample() bool {
+	return rand.Float64()*100 < l.samplePercentage
+}
+
+func (l *RequestLogger) log(record *RequestLogRecord) {
+	for _, redact := range l.redactors {
+		redact(record)
+	}
+
+	recordBytes, err := json.Marshal(record)
+	if err != nil {
+		l.logger.Errorw("failed to encode request log record", zap.Error(err))
+		return
+	}
+
+	select {
+	case l.records <- recordBytes:
+	default:
+		l.dropped.Inc()
+	}
+}
+
+// RequestLoggingHandler logs the sampled requests which are handled by next
+func RequestLoggingHandler(requestLogger *RequestLogger, next http.Handler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if requestLogger == nil || probe.IsRequestKubeletProbe(r) || !requestLogger.sample() {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		start := time.Now()
+		requestHeaders := r.Header.Clone()
+
+		requestBody := &limitedBuffer{limit: _maxLoggedBodyBytes}
+		if r.Body != nil {
+			r.Body = &teeReadCloser{Reader: io.TeeReader(r.Body, requestBody), Closer: r.Body}
+		}
+		rw := &recordingResponseWriter{
+			ResponseWriter: w,
+			body:           &limitedBuffer{limit: _maxLoggedBodyBytes},
+			statusCode:     http.StatusOK,
+		}
+
+		next.ServeHTTP(rw, r)
+
+		requestLogger.log(&RequestLogRecord{
+			Timestamp: start.UTC(),
+			APIName:   requestLogger.apiName,
+			RequestID: r.Header.Get("X-Request-Id"),
+			Method:    r.Method,
+			Path:      r.URL.Path,
+			Query:     r.URL.RawQuery,
+			LatencyMS: float64(time.Since(start)) / float64(time.Millisecond),
+			Request:   newRequestLogMessage(0, requestHeaders, requestBody),
+			Response:  newRequestLogMessage(rw.statusCode, rw.Header().Clone(), rw.body),
+		})
+	}
+}
+
+func newRequestLogMessage(statusCode int, headers http.Header, body *limitedBuffer) RequestLogMessage {
+	msg := RequestLogMessage{
+		StatusCode:    statusCode,
+		Headers:       headers,
+		BodyTruncated: body.truncated,
+	}
+
+	bodyBytes := body.Bytes()
+	if len(bodyBytes) == 0 {
+		return msg
+	}
+
+	if !body.truncated {
+		decoder := json.NewDecoder(bytes.NewReader(bodyBytes))
+		decoder.UseNumber()
+		var jsonBody interface{}
+		if err := decoder.Decode(&jsonBody); err == nil && !decoder.More() {
+			msg.Body = jsonBody
+			return msg
+		}
+	}
+
+	if utf8.Valid(bodyBytes) {
+		msg.Body = string(bodyBytes)
+	} else {
+		msg.Body = base64.StdEncoding.EncodeToString(bodyBytes)
+		msg.BodyEncoding = "base64"
+	}
+
+	return msg
+}
+
+// HeaderRedactor redacts the values of the given request and response headers (case-insensitive)
+func HeaderRedactor(headers ...string) Redactor {
+	canonicalHeaders := strset.New()
+	for _, header := range headers {
+		canonicalHeaders.Add(http.CanonicalHeaderKey(header))
+	}
+
+	return func(record *RequestLogRecord) {
+		for _, msgHeaders := range []http.Header{record.Request.Headers, record.Response.Headers} {
+			for key := range msgHeaders {
+				if canonicalHeaders.Has(http.CanonicalHeaderKey(key)) {
+					msgHeaders[key] = []string{_redactedValue}
+				}
+			}
+		}
+	}
+}
+
+// JSONFieldRedactor redacts the values of the given fields (at any depth) in JSON request and response bodies
+func JSONFieldRedactor(fields ...string) Redactor {
+	fieldSet := strset.New(fields...)
+
+	var redact func(val interface{})
+	redact = func(val interface{}) {
+		switch typedVal := val.(type) {
+		case map[string]interface{}:
+			for key, nestedVal := range typedVal {
+				if fieldSet.Has(key) {
+					typedVal[key] = _redactedValue
+				} else {
+					redact(nestedVal)
+				}
+			}
+		case []interface{}:
+			for _, nestedVal := range typedVal {
+				redact(nestedVal)
+			}
+		}
+	}
+
+	return func(record *RequestLogRecord) {
+		if len(fieldSet) == 0 {
+			return
+		}
+		redact(record.Request.Body)
+		redact(record.Response.Body)
+	}
+}
+
+type s3RequestLogSink struct {
+	awsClient *awslib.Client
+	bucket    string
+	prefix    string
+	hostname  string
+}
+
+// Write uploads the records as a JSON lines file, partitioned by the hour in which the batch was written
+func (s *s3RequestLogSink) Write(records [][]byte) error {
+	now := time.Now().UTC()
+	key := path.Join(s.prefix, now.Format("2006/01/02/15"), fmt.Sprintf("%d-%s.jsonl", now.UnixNano(), s.hostname))
+
+	var buf bytes.Buffer
+	for _, record := range records {
+		buf.Write(record)
+		buf.WriteByte('\n')
+	}
+
+	return s.awsClient.UploadBytesToS3(buf.Bytes(), s.bucket, key)
+}
+
+type kinesisRequestLogSink struct {
+	awsClient *awslib.Client
+	stream    string
+}
+
+// Write puts the records to the stream in batches which respect the kinesis PutRecords limits
+func (s *kinesisRequestLogSink) Write(records [][]byte) error {
+	var batch []*kinesis.PutRecordsRequestEntry
+	batchBytes := 0
+
+	for _, record := range records {
+		if len(batch) == _requestLogMaxBatchSize || (len(batch) > 0 && batchBytes+len(record) > _kinesisMaxBatchBytes) {
+			if err := s.putRecords(batch); err != nil {
+				return err
+			}
+			batch = nil
+			batchBytes = 0
+		}
+		batch = append(batch, &kinesis.PutRecordsRequestEntry{
+			Data:         record,
+			PartitionKey: aws.String(fmt.Sprintf("%d", rand.Int63())),
+		})
+		batchBytes += len(record)
+	}
+
+	if len(batch) > 0 {
+		return s.putRecords(batch)
+	}
+	return nil
+}
+
+// putRecords retries the records which fail (e.g. due to throttling)
+func (s *kinesisRequestLogSink) putRecords(entries []*kinesis.PutRecordsRequestEntry) error {
+	for i := 0; i < _kinesisMaxPutRecordsRetries; i++ {
+		output, err := s.awsClient.Kinesis().PutRecords(&kinesis.PutRecordsInput{
+			StreamName: aws.String(s.stream),
+			Records:    entries,
+		})
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if aws.Int64Value(output.FailedRecordCount) == 0 {
+			return nil
+		}
+
+		var failedEntries []*kinesis.PutRecordsRequestEntry
+		for j, result := range output.Records {
+			if result.ErrorCode != nil {
+				failedEntries = append(failedEntries, entries[j])
+			}
+		}
+		entries = failedEntries
+		time.Sleep(time.Duration(i+1) * 100 * time.Millisecond)
+	}
+
+	return errors.ErrorUnexpected(fmt.Sprintf("failed to put %d record(s) to kinesis stream %s", len(entries), s.stream))
+}
+
+// limitedBuffer stores up to limit bytes, and discards the rest
+type limitedBuffer struct {
+	bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	remaining := b.limit - b.Len()
+	if len(p) > remaining {
+		b.truncated = true
+		b.Buffer.Write(p[:remaining])
+		return len(p), nil
+	}
+	return b.Buffer.Write(p)
+}
+
+type teeReadCloser struct {
+	io.Reader
+	io.Closer
+}
+
+type recordingResponseWriter struct {
+	http.ResponseWriter
+	body        *limitedBuffer
+	statusCode  int
+	wroteHeader bool
+}
+
+func (w *recordingResponseWriter) WriteHeader(statusCode int) {
+	if !w.wroteHeader {
+		w.statusCode = statusCode
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *recordingResponseWriter) Write(p []byte) (int, error) {
+	w.wroteHeader = true
+	_, _ = w.body.Write(p)
+	return w.ResponseWriter.Write(p)
+}
+
+// Unwrap allows http.ResponseController to access the underlying response writer (e.g. to flush streaming responses)
+func (w *recordingResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,156 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proxy_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/cortexlabs/cortex/pkg/lib/pointer"
+	"github.com/cortexlabs/cortex/pkg/proxy"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type fakeRequestLogSink struct {
+	mu      sync.Mutex
+	records []proxy.RequestLogRecord
+}
+
+func (s *fakeRequestLogSink) Write(records [][]byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, recordBytes := range records {
+		var record proxy.RequestLogRecord
+		if err := json.Unmarshal(recordBytes, &record); err != nil {
+			return err
+		}
+		s.records = append(s.records, record)
+	}
+	return nil
+}
+
+// sendRequests sends the requests through a RequestLoggingHandler, and returns the records which were written to the sink
+func sendRequests(t *testing.T, config userconfig.RequestLogging, next http.Handler, requests ...*http.Request) []proxy.RequestLogRecord {
+	t.Helper()
+
+	sink := &fakeRequestLogSink{}
+	requestLogger := proxy.NewRequestLogger("my-api", config, sink, zap.NewNop().Sugar())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		requestLogger.Run(ctx)
+		close(done)
+	}()
+
+	handler := proxy.RequestLoggingHandler(requestLogger, next)
+	for _, req := range requests {
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	cancel()
+	<-done
+
+	return sink.records
+}
+
+func TestRequestLoggingHandlerLogsAndRedacts(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.Equal(t, `{"user": {"ssn": "123-45-6789", "age": 42}}`, string(body))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"prediction": 1, "ssn": "123-45-6789"}`))
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/predict?x=1", strings.NewReader(`{"user": {"ssn": "123-45-6789", "age": 42}}`))
+	req.Header.Set("Authorization", "Bearer secret")
+	req.Header.Set("X-Api-Key", "secret")
+	req.Header.Set("X-Request-Id", "abc")
+
+	records := sendRequests(t, userconfig.RequestLogging{
+		SamplePercentage: 100,
+		S3Path:           pointer.String("s3://bucket/logs"),
+		RedactHeaders:    []string{"x-api-key"},
+		RedactFields:     []string{"ssn"},
+	}, next, req)
+
+	require.Len(t, records, 1)
+	record := records[0]
+	require.Equal(t, "my-api", record.APIName)
+	require.Equal(t, "abc", record.RequestID)
+	require.Equal(t, http.MethodPost, record.Method)
+	require.Equal(t, "/predict", record.Path)
+	require.Equal(t, "x=1", record.Query)
+
+	require.Equal(t, "[REDACTED]", record.Request.Headers.Get("Authorization"))
+	require.Equal(t, "[REDACTED]", record.Request.Headers.Get("X-Api-Key"))
+	require.Equal(t, map[string]interface{}{"user": map[string]interface{}{"ssn": "[REDACTED]", "age": float64(42)}}, record.Request.Body)
+
+	require.Equal(t, http.StatusCreated, record.Response.StatusCode)
+	require.Equal(t, "application/json", record.Response.Headers.Get("Content-Type"))
+	require.Equal(t, map[string]interface{}{"prediction": float64(1), "ssn": "[REDACTED]"}, record.Response.Body)
+}
+
+func TestRequestLoggingHandlerEncodesNonJSONBodies(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = ioutil.ReadAll(r.Body)
+		_, _ = w.Write([]byte("plain text"))
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("\xff\xfe"))
+
+	records := sendRequests(t, userconfig.RequestLogging{SamplePercentage: 100}, next, req)
+
+	require.Len(t, records, 1)
+	require.Equal(t, "//4=", records[0].Request.Body)
+	require.Equal(t, "base64", records[0].Request.BodyEncoding)
+	require.Equal(t, "plain text", records[0].Response.Body)
+	require.Empty(t, records[0].Response.BodyEncoding)
+}
+
+func TestRequestLoggingHandlerSkipsUnsampledRequests(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	var requests []*http.Request
+	for i := 0; i < 100; i++ {
+		requests = append(requests, httptest.NewRequest(http.MethodGet, "/", nil))
+	}
+
+	records := sendRequests(t, userconfig.RequestLogging{SamplePercentage: 0.0001}, next, requests...)
+	require.Less(t, len(records), 100)
+}
+
+func TestRequestLoggingHandlerNilLogger(t *testing.T) {
+	var called bool
+	handler := proxy.RequestLoggingHandler(nil, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	require.True(t, called)
+}
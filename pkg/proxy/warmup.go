@@ -0,0 +1,125 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+	"go.uber.org/atomic"
+	"go.uber.org/zap"
+)
+
+// Warmer sends the configured warmup requests to the user container, and reports once all of them have succeeded
+type Warmer struct {
+	target        string
+	warmup        *userconfig.Warmup
+	client        *http.Client
+	retryInterval time.Duration
+	done          *atomic.Bool
+	logger        *zap.SugaredLogger
+}
+
+// NewWarmer creates a new Warmer; a nil warmup configuration results in a Warmer which is immediately done
+func NewWarmer(target string, warmup *userconfig.Warmup, logger *zap.SugaredLogger) *Warmer {
+	w := &Warmer{
+		target:        strings.TrimSuffix(target, "/"),
+		warmup:        warmup,
+		retryInterval: time.Second,
+		done:          atomic.NewBool(warmup == nil),
+		logger:        logger,
+	}
+
+	if warmup != nil {
+		w.client = &http.Client{
+			Timeout: time.Duration(warmup.TimeoutSeconds) * time.Second,
+		}
+	}
+
+	return w
+}
+
+// Done returns true once all warmup requests have succeeded
+func (w *Warmer) Done() bool {
+	return w.done.Load()
+}
+
+// Run sends the warmup requests, retrying failed requests until they succeed or the context is cancelled
+func (w *Warmer) Run(ctx context.Context) error {
+	if w.Done() {
+		return nil
+	}
+
+	start := time.Now()
+	for i := int64(0); i < w.warmup.NumRequests; i++ {
+		for {
+			err := w.sendRequest(ctx)
+			if err == nil {
+				break
+			}
+
+			w.logger.Debugw("warmup request failed, retrying", "request", i+1, "error", err.Error())
+
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(w.retryInterval):
+			}
+		}
+	}
+
+	w.done.Store(true)
+	w.logger.Infof("completed %d warmup request(s) in %s", w.warmup.NumRequests, time.Since(start).Round(time.Millisecond))
+
+	return nil
+}
+
+func (w *Warmer) sendRequest(ctx context.Context) error {
+	var body io.Reader
+	if w.warmup.Payload != nil {
+		body = strings.NewReader(*w.warmup.Payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, w.warmup.Method, w.target+w.warmup.Path, body)
+	if err != nil {
+		return err
+	}
+	for key, value := range w.warmup.Headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_, _ = io.Copy(ioutil.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("received status code %d", resp.StatusCode)
+	}
+
+	return nil
+}
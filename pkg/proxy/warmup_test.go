@@ -0,0 +1,110 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proxy_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/lib/pointer"
+	"github.com/cortexlabs/cortex/pkg/proxy"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+	"go.uber.org/zap"
+)
+
+func TestWarmerNilConfigIsDone(t *testing.T) {
+	warmer := proxy.NewWarmer("http://127.0.0.1:8080", nil, zap.NewNop().Sugar())
+	require.True(t, warmer.Done())
+	require.NoError(t, warmer.Run(context.Background()))
+}
+
+func TestWarmerSendsRequests(t *testing.T) {
+	var count atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, "/predict", r.URL.Path)
+		require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.Equal(t, `{"x": 1}`, string(body))
+
+		count.Inc()
+	}))
+	defer server.Close()
+
+	warmer := proxy.NewWarmer(server.URL, &userconfig.Warmup{
+		Path:           "/predict",
+		Method:         http.MethodPost,
+		Payload:        pointer.String(`{"x": 1}`),
+		Headers:        map[string]string{"Content-Type": "application/json"},
+		NumRequests:    3,
+		TimeoutSeconds: 5,
+	}, zap.NewNop().Sugar())
+
+	require.False(t, warmer.Done())
+	require.NoError(t, warmer.Run(context.Background()))
+	require.True(t, warmer.Done())
+	require.Equal(t, int64(3), count.Load())
+}
+
+func TestWarmerRetriesFailedRequests(t *testing.T) {
+	var count atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if count.Inc() == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	warmer := proxy.NewWarmer(server.URL, &userconfig.Warmup{
+		Path:           "/",
+		Method:         http.MethodGet,
+		NumRequests:    1,
+		TimeoutSeconds: 5,
+	}, zap.NewNop().Sugar())
+
+	require.NoError(t, warmer.Run(context.Background()))
+	require.True(t, warmer.Done())
+	require.Equal(t, int64(2), count.Load())
+}
+
+func TestWarmerStopsWhenCancelled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	warmer := proxy.NewWarmer(server.URL, &userconfig.Warmup{
+		Path:           "/",
+		Method:         http.MethodGet,
+		NumRequests:    1,
+		TimeoutSeconds: 5,
+	}, zap.NewNop().Sugar())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	require.ErrorIs(t, warmer.Run(ctx), context.DeadlineExceeded)
+	require.False(t, warmer.Done())
+}
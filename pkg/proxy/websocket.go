@@ -0,0 +1,154 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proxy
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+)
+
+// WebSocketProxy proxies websocket connections to the user container. Since websocket connections are long-lived,
+// they bypass the breaker (which limits the number of concurrent http requests); the number of open connections is
+// limited separately instead, and connections are closed if no data is sent in either direction for the idle timeout.
+type WebSocketProxy struct {
+	proxy          *httputil.ReverseProxy
+	maxConnections int64
+	connections    int64
+}
+
+func NewWebSocketProxy(target string, config userconfig.WebSocket) *WebSocketProxy {
+	targetURL, err := url.Parse(target)
+	if err != nil {
+		panic(err)
+	}
+
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DisableKeepAlives = true // upgraded connections can't be reused
+	transport.ForceAttemptHTTP2 = false
+	transport.DialContext = func(ctx context.Context, network, address string) (net.Conn, error) {
+		conn, err := dialer.DialContext(ctx, network, address)
+		if err != nil {
+			return nil, err
+		}
+		return newIdleTimeoutConn(conn, config.IdleTimeout), nil
+	}
+
+	httpProxy := httputil.NewSingleHostReverseProxy(targetURL)
+	httpProxy.Transport = transport
+
+	return &WebSocketProxy{
+		proxy:          httpProxy,
+		maxConnections: config.MaxConnections,
+	}
+}
+
+// Connections returns the number of open websocket connections
+func (p *WebSocketProxy) Connections() int64 {
+	if p == nil {
+		return 0
+	}
+	return atomic.LoadInt64(&p.connections)
+}
+
+func (p *WebSocketProxy) acquire() bool {
+	if atomic.AddInt64(&p.connections, 1) > p.maxConnections {
+		atomic.AddInt64(&p.connections, -1)
+		return false
+	}
+	return true
+}
+
+func (p *WebSocketProxy) release() {
+	atomic.AddInt64(&p.connections, -1)
+}
+
+// IsWebSocketRequest returns whether the request asks to upgrade the connection to the websocket protocol
+func IsWebSocketRequest(r *http.Request) bool {
+	if !strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+		return false
+	}
+
+	for _, value := range r.Header.Values("Connection") {
+		for _, token := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(token), "upgrade") {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// WebSocketHandler sends websocket requests to the websocket proxy, and all other requests to the next handler
+func WebSocketHandler(webSocketProxy *WebSocketProxy, next http.Handler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if webSocketProxy == nil || !IsWebSocketRequest(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if !webSocketProxy.acquire() {
+			http.Error(w, "too many websocket connections", http.StatusServiceUnavailable)
+			return
+		}
+		defer webSocketProxy.release()
+
+		// blocks until the connection is closed
+		webSocketProxy.proxy.ServeHTTP(w, r)
+	}
+}
+
+// idleTimeoutConn closes the connection if no data is read from or written to it for the idle timeout
+// (setting the deadline also extends any pending read, so activity in either direction keeps the connection open)
+type idleTimeoutConn struct {
+	net.Conn
+	idleTimeout time.Duration
+}
+
+func newIdleTimeoutConn(conn net.Conn, idleTimeout time.Duration) *idleTimeoutConn {
+	_ = conn.SetDeadline(time.Now().Add(idleTimeout))
+	return &idleTimeoutConn{
+		Conn:        conn,
+		idleTimeout: idleTimeout,
+	}
+}
+
+func (c *idleTimeoutConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	if n > 0 {
+		_ = c.Conn.SetDeadline(time.Now().Add(c.idleTimeout))
+	}
+	return n, err
+}
+
+func (c *idleTimeoutConn) Write(b []byte) (int, error) {
+	_ = c.Conn.SetDeadline(time.Now().Add(c.idleTimeout))
+	return c.Conn.Write(b)
+}
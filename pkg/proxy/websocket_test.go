@@ -0,0 +1,152 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proxy_test
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/proxy"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+	"github.com/stretchr/testify/require"
+)
+
+// newEchoWebSocketServer accepts websocket upgrades, and echoes all of the data which it receives on the upgraded connection
+func newEchoWebSocketServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !proxy.IsWebSocketRequest(r) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		conn, buf, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		_, _ = buf.WriteString("HTTP/1.1 101 Switching Protocols\r\nConnection: Upgrade\r\nUpgrade: websocket\r\n\r\n")
+		_ = buf.Flush()
+		_, _ = io.Copy(conn, buf)
+	}))
+}
+
+func dialWebSocket(t *testing.T, address string) (net.Conn, *bufio.Reader, *http.Response) {
+	conn, err := net.Dial("tcp", address)
+	require.NoError(t, err)
+
+	_, err = conn.Write([]byte("GET / HTTP/1.1\r\nHost: " + address + "\r\nConnection: Upgrade\r\nUpgrade: websocket\r\n\r\n"))
+	require.NoError(t, err)
+
+	reader := bufio.NewReader(conn)
+	response, err := http.ReadResponse(reader, nil)
+	require.NoError(t, err)
+
+	return conn, reader, response
+}
+
+func TestIsWebSocketRequest(t *testing.T) {
+	t.Parallel()
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	require.False(t, proxy.IsWebSocketRequest(r))
+
+	r.Header.Set("Upgrade", "WebSocket")
+	require.False(t, proxy.IsWebSocketRequest(r))
+
+	r.Header.Set("Connection", "keep-alive, Upgrade")
+	require.True(t, proxy.IsWebSocketRequest(r))
+
+	r.Header.Set("Upgrade", "h2c")
+	require.False(t, proxy.IsWebSocketRequest(r))
+}
+
+func TestWebSocketHandler(t *testing.T) {
+	t.Parallel()
+
+	backend := newEchoWebSocketServer()
+	defer backend.Close()
+
+	webSocketProxy := proxy.NewWebSocketProxy(backend.URL, userconfig.WebSocket{IdleTimeout: time.Minute, MaxConnections: 1})
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+	server := httptest.NewServer(proxy.WebSocketHandler(webSocketProxy, next))
+	defer server.Close()
+	address := server.Listener.Addr().String()
+
+	// regular requests are sent to the next handler
+	response, err := http.Get(server.URL)
+	require.NoError(t, err)
+	_ = response.Body.Close()
+	require.Equal(t, http.StatusTeapot, response.StatusCode)
+
+	conn, reader, response := dialWebSocket(t, address)
+	require.Equal(t, http.StatusSwitchingProtocols, response.StatusCode)
+	require.Eventually(t, func() bool { return webSocketProxy.Connections() == 1 }, time.Second, 10*time.Millisecond)
+
+	_, err = conn.Write([]byte("hello"))
+	require.NoError(t, err)
+	echoed := make([]byte, 5)
+	_, err = io.ReadFull(reader, echoed)
+	require.NoError(t, err)
+	require.Equal(t, "hello", string(echoed))
+
+	// the connection limit has been reached
+	secondConn, _, response := dialWebSocket(t, address)
+	_ = secondConn.Close()
+	require.Equal(t, http.StatusServiceUnavailable, response.StatusCode)
+
+	_ = conn.Close()
+	require.Eventually(t, func() bool { return webSocketProxy.Connections() == 0 }, time.Second, 10*time.Millisecond)
+}
+
+func TestWebSocketHandlerIdleTimeout(t *testing.T) {
+	t.Parallel()
+
+	backend := newEchoWebSocketServer()
+	defer backend.Close()
+
+	webSocketProxy := proxy.NewWebSocketProxy(backend.URL, userconfig.WebSocket{IdleTimeout: 200 * time.Millisecond, MaxConnections: 10})
+	server := httptest.NewServer(proxy.WebSocketHandler(webSocketProxy, http.NotFoundHandler()))
+	defer server.Close()
+
+	conn, reader, response := dialWebSocket(t, server.Listener.Addr().String())
+	defer conn.Close()
+	require.Equal(t, http.StatusSwitchingProtocols, response.StatusCode)
+
+	// activity keeps the connection open for longer than the idle timeout
+	for i := 0; i < 4; i++ {
+		time.Sleep(100 * time.Millisecond)
+		_, err := conn.Write([]byte("x"))
+		require.NoError(t, err)
+		b, err := reader.ReadByte()
+		require.NoError(t, err)
+		require.Equal(t, byte('x'), b)
+	}
+
+	// the connection is closed once it has been idle for the idle timeout
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	_, err := reader.ReadByte()
+	require.ErrorIs(t, err, io.EOF)
+	require.Eventually(t, func() bool { return webSocketProxy.Connections() == 0 }, time.Second, 10*time.Millisecond)
+}
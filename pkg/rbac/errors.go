@@ -0,0 +1,43 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rbac
+
+import (
+	"fmt"
+
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+)
+
+const (
+	ErrInvalidRole    = "rbac.invalid_role"
+	ErrInvalidSubject = "rbac.invalid_subject"
+)
+
+func ErrorInvalidRole(role string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrInvalidRole,
+		Message: fmt.Sprintf("invalid role %s; valid roles are %s", s.UserStr(role), s.StrsOr(RoleStrings())),
+	})
+}
+
+func ErrorInvalidSubject(subject string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrInvalidSubject,
+		Message: fmt.Sprintf("invalid subject %s; subjects must be formatted as iam:<aws account id, iam user arn, or iam role arn>, apikey:<api key name>, or oidc:<username>", s.UserStr(subject)),
+	})
+}
@@ -0,0 +1,156 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rbac
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/cortexlabs/cortex/pkg/lib/aws"
+	"github.com/cortexlabs/cortex/pkg/lib/urls"
+)
+
+const (
+	// ConfigMapName is the kubernetes config map in which role bindings are stored (one entry per binding, keyed by the binding's name)
+	ConfigMapName = "rbac-bindings"
+
+	SubjectKindIAM    = "iam"
+	SubjectKindAPIKey = "apikey"
+	SubjectKindOIDC   = "oidc"
+)
+
+var _accountIDRegex = regexp.MustCompile(`^[0-9]{12}$`)
+
+type Role string
+
+const (
+	RoleViewer   Role = "viewer"
+	RoleDeployer Role = "deployer"
+	RoleAdmin    Role = "admin"
+)
+
+// in order of increasing privilege
+var _roles = []Role{RoleViewer, RoleDeployer, RoleAdmin}
+
+func RoleStrings() []string {
+	roleStrs := make([]string, len(_roles))
+	for i, role := range _roles {
+		roleStrs[i] = string(role)
+	}
+	return roleStrs
+}
+
+func ParseRole(roleStr string) (Role, error) {
+	for _, role := range _roles {
+		if string(role) == roleStr {
+			return role, nil
+		}
+	}
+	return "", ErrorInvalidRole(roleStr)
+}
+
+func (r Role) rank() int {
+	for i, role := range _roles {
+		if role == r {
+			return i
+		}
+	}
+	return -1
+}
+
+// Includes returns whether the role grants the permissions of the other role (each role grants the permissions of the roles before it)
+func (r Role) Includes(other Role) bool {
+	return r.rank() >= 0 && r.rank() >= other.rank()
+}
+
+// Caller is an authenticated client of the operator
+type Caller struct {
+	Kind string // SubjectKindIAM, SubjectKindAPIKey, or SubjectKindOIDC
+	ID   string // the caller's arn (as returned by GetCallerIdentity), the name of its api key, or its oidc username
+}
+
+func (c Caller) String() string {
+	return c.Kind + ":" + c.ID
+}
+
+// Binding grants a role to the callers which match its subject
+type Binding struct {
+	Name      string    `json:"name"`
+	Subject   string    `json:"subject"` // <kind>:<id>, e.g. iam:arn:aws:iam::123456789012:role/ci or apikey:ci
+	Role      Role      `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func splitSubject(subject string) (string, string) {
+	split := strings.SplitN(subject, ":", 2)
+	if len(split) != 2 {
+		return "", subject
+	}
+	return split[0], split[1]
+}
+
+// ValidateSubject checks that the subject is iam:<account id or iam user/role arn>, apikey:<api key name>, or oidc:<username>
+func ValidateSubject(subject string) error {
+	kind, id := splitSubject(subject)
+	switch kind {
+	case SubjectKindIAM:
+		if _accountIDRegex.MatchString(id) {
+			return nil
+		}
+		parsedARN, err := arn.Parse(id)
+		if err != nil || parsedARN.Service != "iam" {
+			return ErrorInvalidSubject(subject)
+		}
+		return nil
+	case SubjectKindAPIKey:
+		if urls.CheckDNS1123(id) != nil {
+			return ErrorInvalidSubject(subject)
+		}
+		return nil
+	case SubjectKindOIDC:
+		if id == "" {
+			return ErrorInvalidSubject(subject)
+		}
+		return nil
+	}
+	return ErrorInvalidSubject(subject)
+}
+
+// Matches returns whether the caller is the binding's subject; iam subjects match callers in the same way as the principals of apis with aws_iam authentication
+func (b *Binding) Matches(caller Caller) bool {
+	kind, id := splitSubject(b.Subject)
+	if kind != caller.Kind {
+		return false
+	}
+	if kind == SubjectKindIAM {
+		return aws.PrincipalMatchesCaller(id, caller.ID)
+	}
+	return id == caller.ID
+}
+
+// RoleOf returns the most privileged role which the bindings grant to the caller ("" if none of the bindings match the caller)
+func RoleOf(bindings []Binding, caller Caller) Role {
+	var callerRole Role
+	for _, binding := range bindings {
+		if binding.Matches(caller) && binding.Role.rank() > callerRole.rank() {
+			callerRole = binding.Role
+		}
+	}
+	return callerRole
+}
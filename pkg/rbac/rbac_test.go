@@ -0,0 +1,65 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rbac
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoleIncludes(t *testing.T) {
+	require.True(t, RoleAdmin.Includes(RoleDeployer))
+	require.True(t, RoleDeployer.Includes(RoleDeployer))
+	require.True(t, RoleDeployer.Includes(RoleViewer))
+	require.False(t, RoleViewer.Includes(RoleDeployer))
+	require.False(t, Role("").Includes(RoleViewer))
+
+	_, err := ParseRole("owner")
+	require.Error(t, err)
+}
+
+func TestValidateSubject(t *testing.T) {
+	require.NoError(t, ValidateSubject("iam:123456789012"))
+	require.NoError(t, ValidateSubject("iam:arn:aws:iam::123456789012:role/ci"))
+	require.NoError(t, ValidateSubject("apikey:ci-key"))
+	require.NoError(t, ValidateSubject("oidc:alice@example.com"))
+
+	require.Error(t, ValidateSubject("iam:1234"))
+	require.Error(t, ValidateSubject("iam:arn:aws:s3:::my-bucket"))
+	require.Error(t, ValidateSubject("apikey:CI_KEY"))
+	require.Error(t, ValidateSubject("oidc:"))
+	require.Error(t, ValidateSubject("user:alice"))
+	require.Error(t, ValidateSubject("alice"))
+}
+
+func TestRoleOf(t *testing.T) {
+	bindings := []Binding{
+		{Name: "account", Subject: "iam:123456789012", Role: RoleViewer},
+		{Name: "ci", Subject: "iam:arn:aws:iam::123456789012:role/ci", Role: RoleDeployer},
+		{Name: "ci-key", Subject: "apikey:ci", Role: RoleAdmin},
+		{Name: "alice", Subject: "oidc:alice@example.com", Role: RoleDeployer},
+	}
+
+	require.Equal(t, RoleViewer, RoleOf(bindings, Caller{Kind: SubjectKindIAM, ID: "arn:aws:iam::123456789012:user/alice"}))
+	require.Equal(t, RoleDeployer, RoleOf(bindings, Caller{Kind: SubjectKindIAM, ID: "arn:aws:sts::123456789012:assumed-role/ci/session"}))
+	require.Equal(t, Role(""), RoleOf(bindings, Caller{Kind: SubjectKindIAM, ID: "arn:aws:iam::210987654321:user/alice"}))
+	require.Equal(t, RoleAdmin, RoleOf(bindings, Caller{Kind: SubjectKindAPIKey, ID: "ci"}))
+	require.Equal(t, Role(""), RoleOf(bindings, Caller{Kind: SubjectKindAPIKey, ID: "other"}))
+	require.Equal(t, RoleDeployer, RoleOf(bindings, Caller{Kind: SubjectKindOIDC, ID: "alice@example.com"}))
+	require.Equal(t, Role(""), RoleOf(bindings, Caller{Kind: SubjectKindIAM, ID: "alice@example.com"}))
+}
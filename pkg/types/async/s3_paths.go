@@ -18,6 +18,7 @@ package async
 
 import (
 	"fmt"
+	"time"
 )
 
 func StoragePath(clusterUID, apiName string) string {
@@ -43,3 +44,19 @@ func StatusPrefixPath(storagePath string, requestID string) string {
 func StatusPath(storagePath string, requestID string, status Status) string {
 	return fmt.Sprintf("%s/%s", StatusPrefixPath(storagePath, requestID), status)
 }
+
+// IdempotencyKeysDir is the directory (within an api's storage path) in which idempotency keys are stored
+const IdempotencyKeysDir = "idempotency_keys"
+
+func IdempotencyKeyPath(storagePath string, idempotencyKeyHash string) string {
+	return fmt.Sprintf("%s/%s/%s", storagePath, IdempotencyKeysDir, idempotencyKeyHash)
+}
+
+func ResultTTLPrefixPath(storagePath string, requestID string) string {
+	return fmt.Sprintf("%s/%s/result_ttl", storagePath, requestID)
+}
+
+// ResultTTLPath encodes the result ttl (in seconds) in the object key, so that it can be read by listing the workload's objects
+func ResultTTLPath(storagePath string, requestID string, resultTTL time.Duration) string {
+	return fmt.Sprintf("%s/%d", ResultTTLPrefixPath(storagePath, requestID), int64(resultTTL.Seconds()))
+}
@@ -26,6 +26,7 @@ const (
 	StatusInProgress Status = "in_progress"
 	StatusInQueue    Status = "in_queue"
 	StatusCompleted  Status = "completed"
+	StatusExpired    Status = "expired"
 )
 
 func (status Status) String() string {
@@ -34,7 +35,7 @@ func (status Status) String() string {
 
 func (status Status) Valid() bool {
 	switch status {
-	case StatusNotFound, StatusFailed, StatusInProgress, StatusInQueue, StatusCompleted:
+	case StatusNotFound, StatusFailed, StatusInProgress, StatusInQueue, StatusCompleted, StatusExpired:
 		return true
 	default:
 		return false
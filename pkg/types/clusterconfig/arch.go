@@ -0,0 +1,94 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterconfig
+
+import (
+	"github.com/cortexlabs/cortex/pkg/lib/aws"
+)
+
+type Arch int
+
+const (
+	UnknownArch Arch = iota
+	AMD64Arch
+	ARM64Arch
+)
+
+var _availableArchs = []string{
+	"unknown",
+	"amd64",
+	"arm64",
+}
+
+func ArchFromString(s string) Arch {
+	for i := 0; i < len(_availableArchs); i++ {
+		if s == _availableArchs[i] {
+			return Arch(i)
+		}
+	}
+	return UnknownArch
+}
+
+func ArchStrings() []string {
+	return _availableArchs[1:]
+}
+
+// InstanceTypeArch returns the cpu architecture of an instance type (arm64 for graviton instances, amd64 otherwise)
+func InstanceTypeArch(instanceType string) (Arch, error) {
+	isARM, err := aws.IsARMInstance(instanceType)
+	if err != nil {
+		return UnknownArch, err
+	}
+	if isARM {
+		return ARM64Arch, nil
+	}
+	return AMD64Arch, nil
+}
+
+func (t Arch) String() string {
+	return _availableArchs[t]
+}
+
+// MarshalText satisfies TextMarshaler
+func (t Arch) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// UnmarshalText satisfies TextUnmarshaler
+func (t *Arch) UnmarshalText(text []byte) error {
+	enum := string(text)
+	for i := 0; i < len(_availableArchs); i++ {
+		if enum == _availableArchs[i] {
+			*t = Arch(i)
+			return nil
+		}
+	}
+
+	*t = UnknownArch
+	return nil
+}
+
+// UnmarshalBinary satisfies BinaryUnmarshaler
+// Needed for msgpack
+func (t *Arch) UnmarshalBinary(data []byte) error {
+	return t.UnmarshalText(data)
+}
+
+// MarshalBinary satisfies BinaryMarshaler
+func (t Arch) MarshalBinary() ([]byte, error) {
+	return []byte(t.String()), nil
+}
@@ -90,22 +90,10 @@ func (cc *Config) validateUserAvailabilityZones(awsClient *aws.Client) error {
 		}
 	}
 
-	if len(cc.NodeGroups) > 0 {
-		instanceTypes := strset.New()
-		for _, ng := range cc.NodeGroups {
-			instanceTypes.Add(ng.InstanceType)
-		}
-		instanceTypesSlice := instanceTypes.Slice()
-
-		supportedZones, err := awsClient.ListSupportedAvailabilityZones(instanceTypesSlice[0], instanceTypesSlice[1:]...)
-		if err != nil {
-			// Skip validation instance-based validation
-			supportedZones = strset.Difference(allZones, _azBlacklist)
-		}
-
+	if supportedZones, instanceTypes := cc.supportedAvailabilityZones(awsClient, allZones); supportedZones != nil {
 		for _, userZone := range cc.AvailabilityZones {
 			if !supportedZones.Has(userZone) {
-				return ErrorUnsupportedAvailabilityZone(userZone, instanceTypesSlice[0], instanceTypesSlice[1:]...)
+				return ErrorUnsupportedAvailabilityZone(userZone, instanceTypes[0], instanceTypes[1:]...)
 			}
 		}
 	}
@@ -113,6 +101,28 @@ func (cc *Config) validateUserAvailabilityZones(awsClient *aws.Client) error {
 	return nil
 }
 
+// supportedAvailabilityZones returns the zones which support all of the nodegroups' instance types (and the sorted instance types),
+// or nil if there are no nodegroups
+func (cc *Config) supportedAvailabilityZones(awsClient *aws.Client, allZones strset.Set) (strset.Set, []string) {
+	if len(cc.NodeGroups) == 0 {
+		return nil, nil
+	}
+
+	instanceTypes := strset.New()
+	for _, ng := range cc.NodeGroups {
+		instanceTypes.Add(ng.InstanceType)
+	}
+	instanceTypesSlice := instanceTypes.SliceSorted()
+
+	supportedZones, err := awsClient.ListSupportedAvailabilityZones(instanceTypesSlice[0], instanceTypesSlice[1:]...)
+	if err != nil {
+		// Skip validation instance-based validation
+		supportedZones = strset.Difference(allZones, _azBlacklist)
+	}
+
+	return supportedZones, instanceTypesSlice
+}
+
 func (cc *Config) validateSubnets(awsClient *aws.Client) error {
 	if len(cc.Subnets) == 0 {
 		return nil
@@ -123,12 +133,16 @@ func (cc *Config) validateSubnets(awsClient *aws.Client) error {
 		return nil // Skip validation
 	}
 
+	supportedZones, instanceTypes := cc.supportedAvailabilityZones(awsClient, allZones)
 	userZones := strset.New()
 
 	for i, subnetConfig := range cc.Subnets {
 		if !allZones.Has(subnetConfig.AvailabilityZone) {
 			return errors.Wrap(ErrorInvalidAvailabilityZone(subnetConfig.AvailabilityZone, allZones, cc.Region), s.Index(i), AvailabilityZoneKey)
 		}
+		if supportedZones != nil && !supportedZones.Has(subnetConfig.AvailabilityZone) {
+			return errors.Wrap(ErrorUnsupportedAvailabilityZone(subnetConfig.AvailabilityZone, instanceTypes[0], instanceTypes[1:]...), s.Index(i), AvailabilityZoneKey)
+		}
 		if userZones.Has(subnetConfig.AvailabilityZone) {
 			return ErrorAvailabilityZoneSpecifiedTwice(subnetConfig.AvailabilityZone)
 		}
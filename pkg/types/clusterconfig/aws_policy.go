@@ -45,17 +45,123 @@ var _cortexPolicy = `
 				"sts:GetCallerIdentity",
 				"ecr:GetAuthorizationToken",
 				"ecr:BatchGetImage",
+				"ecr:DescribeImageScanFindings",
+				"ecr:GetDownloadUrlForLayer",
 				"sqs:ListQueues",
-				"ec2:DescribeSpotPriceHistory"
+				"ec2:DescribeSpotPriceHistory",
+				"ec2:DescribeSpotInstanceRequests",
+				"autoscaling:DescribeAutoScalingInstances",
+				"autoscaling:DescribeAutoScalingGroups",
+				"logs:GetQueryResults",
+				"logs:StopQuery",
+				"secretsmanager:ListSecrets",
+				"ssm:DescribeParameters",
+				"iam:GetRole",
+				"eks:DescribeCluster"
 			],
 			"Effect": "Allow",
 			"Resource": "*"
 		},
+		{
+			"Action": [
+				"acm:DescribeCertificate",
+				"acm:ListCertificates",
+				"acm:ListTagsForCertificate",
+				"route53:ListHostedZones",
+				"route53:ListResourceRecordSets",
+				"elasticloadbalancing:DescribeLoadBalancers",
+				"elasticloadbalancing:DescribeTags",
+				"elasticloadbalancing:DescribeListeners",
+				"elasticloadbalancing:DescribeListenerCertificates"
+			],
+			"Effect": "Allow",
+			"Resource": "*"
+		},
+		{
+			"Effect": "Allow",
+			"Action": [
+				"acm:RequestCertificate",
+				"acm:AddTagsToCertificate"
+			],
+			"Resource": "*",
+			"Condition": {
+				"StringEquals": {
+					"aws:RequestTag/cortex.dev/cluster-name": "{{ .ClusterName }}"
+				}
+			}
+		},
+		{
+			"Effect": "Allow",
+			"Action": [
+				"acm:DeleteCertificate",
+				"elasticloadbalancing:AddListenerCertificates",
+				"elasticloadbalancing:RemoveListenerCertificates"
+			],
+			"Resource": "*",
+			"Condition": {
+				"StringEquals": {
+					"aws:ResourceTag/cortex.dev/cluster-name": "{{ .ClusterName }}"
+				}
+			}
+		},{{ if .CustomDomainHostedZoneIDs }}
+		{
+			"Effect": "Allow",
+			"Action": "route53:ChangeResourceRecordSets",
+			"Resource": [{{ range $i, $hostedZoneID := .CustomDomainHostedZoneIDs }}{{ if $i }}, {{ end }}"arn:*:route53:::hostedzone/{{ $hostedZoneID }}"{{ end }}]
+		},{{ end }}
+		{
+			"Effect": "Allow",
+			"Action": "sts:AssumeRole",
+			"Resource": "arn:*:iam::*:role/cortex-*"
+		},
+		{
+			"Effect": "Allow",
+			"Action": [
+				"autoscaling:DetachInstances",
+				"autoscaling:UpdateAutoScalingGroup"
+			],
+			"Resource": "*",
+			"Condition": {
+				"StringEquals": {
+					"autoscaling:ResourceTag/cortex.dev/cluster-name": "{{ .ClusterName }}"
+				}
+			}
+		},
 		{
 			"Effect": "Allow",
 			"Action": "sqs:*",
 			"Resource": "arn:*:sqs:{{ .Region }}:{{ .AccountID }}:cx_*"
 		},
+		{
+			"Effect": "Allow",
+			"Action": "secretsmanager:GetSecretValue",
+			"Resource": "arn:*:secretsmanager:{{ .Region }}:*:secret:*",
+			"Condition": {
+				"StringEquals": {
+					"secretsmanager:ResourceTag/cortex.dev/cluster-name": "{{ .ClusterName }}"
+				}
+			}
+		},
+		{
+			"Effect": "Allow",
+			"Action": [
+				"secretsmanager:GetSecretValue",
+				"secretsmanager:CreateSecret",
+				"secretsmanager:PutSecretValue",
+				"secretsmanager:DeleteSecret",
+				"secretsmanager:TagResource"
+			],
+			"Resource": "arn:*:secretsmanager:{{ .Region }}:{{ .AccountID }}:secret:cortex/{{ .ClusterName }}/*"
+		},
+		{
+			"Effect": "Allow",
+			"Action": [
+				"ssm:GetParameter",
+				"ssm:PutParameter",
+				"ssm:DeleteParameter"
+			],
+			"Resource": "arn:*:ssm:{{ .Region }}:{{ .AccountID }}:parameter/cortex/{{ .ClusterName }}/*"
+		},
 		{
 			"Effect": "Allow",
 			"Action": "s3:*",
@@ -72,7 +178,8 @@ var _cortexPolicy = `
 				"logs:CreateLogStream",
 				"logs:DescribeLogStreams",
 				"logs:PutLogEvents",
-				"logs:CreateLogGroup"
+				"logs:CreateLogGroup",
+				"logs:StartQuery"
 			],
 			"Resource": "arn:*:logs:{{ .Region }}:{{ .AccountID }}:log-group:{{ .LogGroup }}:*"
 		},
@@ -86,40 +193,49 @@ var _cortexPolicy = `
 `
 
 type CortexPolicyTemplateArgs struct {
-	ClusterName string
-	LogGroup    string
-	Region      string
-	Bucket      string
-	AccountID   string
+	ClusterName               string
+	LogGroup                  string
+	Region                    string
+	Bucket                    string
+	AccountID                 string
+	CustomDomainHostedZoneIDs []string
 }
 
-func CreateDefaultPolicy(awsClient *aws.Client, args CortexPolicyTemplateArgs) error {
-	policyName := DefaultPolicyName(args.ClusterName, args.Region)
-	accountID, _, err := awsClient.GetCachedAccountID()
-	if err != nil {
-		return err
-	}
-
-	policyARN := DefaultPolicyARN(accountID, args.ClusterName, args.Region)
+// RenderDefaultPolicy returns the (compacted) document of the policy which CreateDefaultPolicy creates
+func RenderDefaultPolicy(args CortexPolicyTemplateArgs) (string, error) {
 	policyTemplate, err := template.New("policy").Parse(_cortexPolicy)
 	if err != nil {
-		return errors.Wrap(err, "failed to parse aws policy template")
+		return "", errors.Wrap(err, "failed to parse aws policy template")
 	}
 
 	buf := &bytes.Buffer{}
 	err = policyTemplate.Execute(buf, args)
 	if err != nil {
-		return errors.Wrap(err, "failed to execute aws policy template")
+		return "", errors.Wrap(err, "failed to execute aws policy template")
 	}
 
 	compactBuf := &bytes.Buffer{}
 
 	err = json.Compact(compactBuf, buf.Bytes())
 	if err != nil {
-		return errors.Wrap(err, "failed to parse and remove whitespace from aws policy json")
+		return "", errors.Wrap(err, "failed to parse and remove whitespace from aws policy json")
 	}
 
-	policyDocument := compactBuf.String()
+	return compactBuf.String(), nil
+}
+
+func CreateDefaultPolicy(awsClient *aws.Client, args CortexPolicyTemplateArgs) error {
+	policyName := DefaultPolicyName(args.ClusterName, args.Region)
+	accountID, _, err := awsClient.GetCachedAccountID()
+	if err != nil {
+		return err
+	}
+
+	policyARN := DefaultPolicyARN(accountID, args.ClusterName, args.Region)
+	policyDocument, err := RenderDefaultPolicy(args)
+	if err != nil {
+		return err
+	}
 
 	_, err = awsClient.IAM().CreatePolicy(&iam.CreatePolicyInput{
 		PolicyDocument: &policyDocument,
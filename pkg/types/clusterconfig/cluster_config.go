@@ -30,10 +30,13 @@ import (
 	"github.com/PEAT-AI/yaml"
 	"github.com/aws/amazon-vpc-cni-k8s/pkg/awsutils"
 	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/iam"
 	"github.com/cortexlabs/cortex/pkg/consts"
 	"github.com/cortexlabs/cortex/pkg/lib/aws"
 	cr "github.com/cortexlabs/cortex/pkg/lib/configreader"
+	"github.com/cortexlabs/cortex/pkg/lib/cron"
+	"github.com/cortexlabs/cortex/pkg/lib/docker"
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
 	libhash "github.com/cortexlabs/cortex/pkg/lib/hash"
 	"github.com/cortexlabs/cortex/pkg/lib/k8s"
@@ -44,6 +47,8 @@ import (
 	libstr "github.com/cortexlabs/cortex/pkg/lib/strings"
 	s "github.com/cortexlabs/cortex/pkg/lib/strings"
 	"github.com/cortexlabs/cortex/pkg/lib/structs"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+	klabels "k8s.io/apimachinery/pkg/labels"
 )
 
 const (
@@ -70,7 +75,8 @@ const (
 )
 
 var (
-	_operatorNodeGroupInstanceType = "t3.medium"
+	_operatorNodeGroupInstanceType    = "t3.medium"
+	_armOperatorNodeGroupInstanceType = "t4g.medium"
 
 	_maxNodeGroupLengthWithPrefix = 32
 	_maxNodeGroupLength           = _maxNodeGroupLengthWithPrefix - len("cx-wd-") // or cx-ws-
@@ -95,9 +101,10 @@ var (
 )
 
 type CoreConfig struct {
-	ClusterName            string `json:"cluster_name" yaml:"cluster_name"`
-	Region                 string `json:"region" yaml:"region"`
-	PrometheusInstanceType string `json:"prometheus_instance_type" yaml:"prometheus_instance_type"`
+	ClusterName            string  `json:"cluster_name" yaml:"cluster_name"`
+	Region                 string  `json:"region" yaml:"region"`
+	PrometheusInstanceType string  `json:"prometheus_instance_type" yaml:"prometheus_instance_type"`
+	RegistryMirror         *string `json:"registry_mirror,omitempty" yaml:"registry_mirror,omitempty"`
 
 	ImageOperator                   string `json:"image_operator" yaml:"image_operator"`
 	ImageControllerManager          string `json:"image_controller_manager" yaml:"image_controller_manager"`
@@ -109,6 +116,8 @@ type CoreConfig struct {
 	ImageAsyncGateway               string `json:"image_async_gateway" yaml:"image_async_gateway"`
 	ImageEnqueuer                   string `json:"image_enqueuer" yaml:"image_enqueuer"`
 	ImageDequeuer                   string `json:"image_dequeuer" yaml:"image_dequeuer"`
+	ImageModelCache                 string `json:"image_model_cache" yaml:"image_model_cache"`
+	ImagePrepull                    string `json:"image_prepull" yaml:"image_prepull"`
 	ImageClusterAutoscaler          string `json:"image_cluster_autoscaler" yaml:"image_cluster_autoscaler"`
 	ImageMetricsServer              string `json:"image_metrics_server" yaml:"image_metrics_server"`
 	ImageNvidiaDevicePlugin         string `json:"image_nvidia_device_plugin" yaml:"image_nvidia_device_plugin"`
@@ -132,6 +141,7 @@ type CoreConfig struct {
 	Tags                              map[string]string  `json:"tags" yaml:"tags"`
 	AvailabilityZones                 []string           `json:"availability_zones" yaml:"availability_zones"`
 	SSLCertificateARN                 *string            `json:"ssl_certificate_arn,omitempty" yaml:"ssl_certificate_arn,omitempty"`
+	CustomDomainHostedZoneIDs         []string           `json:"custom_domain_hosted_zone_ids,omitempty" yaml:"custom_domain_hosted_zone_ids,omitempty"`
 	IAMPolicyARNs                     []string           `json:"iam_policy_arns" yaml:"iam_policy_arns"`
 	SubnetVisibility                  SubnetVisibility   `json:"subnet_visibility" yaml:"subnet_visibility"`
 	Subnets                           []*Subnet          `json:"subnets,omitempty" yaml:"subnets,omitempty"`
@@ -142,6 +152,17 @@ type CoreConfig struct {
 	APILoadBalancerCIDRWhiteList      []string           `json:"api_load_balancer_cidr_white_list,omitempty" yaml:"api_load_balancer_cidr_white_list,omitempty"`
 	OperatorLoadBalancerCIDRWhiteList []string           `json:"operator_load_balancer_cidr_white_list,omitempty" yaml:"operator_load_balancer_cidr_white_list,omitempty"`
 	VPCCIDR                           *string            `json:"vpc_cidr,omitempty" yaml:"vpc_cidr,omitempty"`
+	VPCID                             *string            `json:"vpc_id,omitempty" yaml:"vpc_id,omitempty"`
+	Quotas                            []*Quota           `json:"quotas" yaml:"quotas"`
+	Projects                          []*Project         `json:"projects" yaml:"projects"`
+	Schedules                         []*Schedule        `json:"schedules" yaml:"schedules"`
+	GitOps                            *GitOps            `json:"gitops,omitempty" yaml:"gitops,omitempty"`
+	OIDC                              *OIDC              `json:"oidc,omitempty" yaml:"oidc,omitempty"`
+	SecretsBackend                    SecretsBackend     `json:"secrets_backend" yaml:"secrets_backend"`
+	NetworkPolicies                   bool               `json:"network_policies" yaml:"network_policies"`
+	PodSecurity                       *PodSecurityPolicy `json:"pod_security,omitempty" yaml:"pod_security,omitempty"`
+	ImageScan                         *ImageScan         `json:"image_scan,omitempty" yaml:"image_scan,omitempty"`
+	ImageSignatures                   *ImageSignatures   `json:"image_signatures,omitempty" yaml:"image_signatures,omitempty"`
 	Telemetry                         bool               `json:"telemetry" yaml:"telemetry"`
 }
 
@@ -151,11 +172,14 @@ type ManagedConfig struct {
 	AccountID       string `json:"account_id" yaml:"account_id"`
 	ClusterUID      string `json:"cluster_uid" yaml:"cluster_uid"`
 	Bucket          string `json:"bucket" yaml:"bucket"`
+	Arch            Arch   `json:"arch" yaml:"arch"`       // the architecture of the cortex system nodes (determined on cluster up)
+	Offline         bool   `json:"offline" yaml:"offline"` // whether the cluster was created with --offline (telemetry is disabled for the lifetime of the cluster)
 }
 
 type NodeGroup struct {
 	Name                     string      `json:"name" yaml:"name"`
 	InstanceType             string      `json:"instance_type" yaml:"instance_type"`
+	Arch                     Arch        `json:"arch" yaml:"arch"`
 	MinInstances             int64       `json:"min_instances" yaml:"min_instances"`
 	MaxInstances             int64       `json:"max_instances" yaml:"max_instances"`
 	Priority                 int64       `json:"priority" yaml:"priority"`
@@ -165,6 +189,10 @@ type NodeGroup struct {
 	InstanceVolumeThroughput *int64      `json:"instance_volume_throughput" yaml:"instance_volume_throughput"`
 	Spot                     bool        `json:"spot" yaml:"spot"`
 	SpotConfig               *SpotConfig `json:"spot_config" yaml:"spot_config"`
+	CapacityReservationID    *string     `json:"capacity_reservation_id" yaml:"capacity_reservation_id"`
+
+	// the availability zone of the capacity reservation (determined during validation), which the nodegroup's instances are restricted to
+	CapacityReservationAvailabilityZone string `json:"capacity_reservation_availability_zone,omitempty" yaml:"capacity_reservation_availability_zone,omitempty"`
 }
 
 // compares the supported updatable fields of a nodegroup
@@ -196,6 +224,71 @@ type SpotConfig struct {
 	InstancePools                       *int64   `json:"instance_pools" yaml:"instance_pools"`
 }
 
+// Quota limits the resources which can be used by the APIs whose labels match Selector (all APIs if Selector is empty)
+type Quota struct {
+	Name              string `json:"name" yaml:"name"`
+	Selector          string `json:"selector" yaml:"selector"`
+	MaxReplicas       *int64 `json:"max_replicas" yaml:"max_replicas"`
+	MaxGPUs           *int64 `json:"max_gpus" yaml:"max_gpus"`
+	MaxConcurrentJobs *int64 `json:"max_concurrent_jobs" yaml:"max_concurrent_jobs"`
+}
+
+// Project is a named group of APIs, which are deployed to the namespace with the project's name; its limits apply to the sum of the resources used by its APIs
+type Project struct {
+	Name              string `json:"name" yaml:"name"`
+	MaxReplicas       *int64 `json:"max_replicas" yaml:"max_replicas"`
+	MaxGPUs           *int64 `json:"max_gpus" yaml:"max_gpus"`
+	MaxConcurrentJobs *int64 `json:"max_concurrent_jobs" yaml:"max_concurrent_jobs"`
+}
+
+// Schedule sets the min/max instances of NodeGroup whenever Cron fires (in UTC); the sizes are kept until another schedule for the same nodegroup fires
+type Schedule struct {
+	NodeGroup    string `json:"node_group" yaml:"node_group"`
+	Cron         string `json:"cron" yaml:"cron"`
+	MinInstances int64  `json:"min_instances" yaml:"min_instances"`
+	MaxInstances int64  `json:"max_instances" yaml:"max_instances"`
+}
+
+// GitOps configures the operator to continuously reconcile the cluster's apis with the api configurations in a branch of a git repository
+type GitOps struct {
+	Repository        string  `json:"repository" yaml:"repository"`
+	Branch            string  `json:"branch" yaml:"branch"`
+	Path              string  `json:"path" yaml:"path"`
+	SecretsManagerARN *string `json:"secrets_manager_arn,omitempty" yaml:"secrets_manager_arn,omitempty"`
+	Prune             bool    `json:"prune" yaml:"prune"`
+}
+
+// OIDC configures the operator to accept id tokens from an openid connect identity provider (which the cli obtains with `cortex login`)
+type OIDC struct {
+	IssuerURL     string   `json:"issuer_url" yaml:"issuer_url"`
+	ClientID      string   `json:"client_id" yaml:"client_id"`
+	UsernameClaim string   `json:"username_claim" yaml:"username_claim"`
+	Scopes        []string `json:"scopes" yaml:"scopes"`
+}
+
+// PodSecurityPolicy is enforced on the user containers of all apis (when they are deployed)
+type PodSecurityPolicy struct {
+	AllowPrivileged        bool     `json:"allow_privileged" yaml:"allow_privileged"`
+	AllowUnconfinedSeccomp bool     `json:"allow_unconfined_seccomp" yaml:"allow_unconfined_seccomp"`
+	RequireReadOnlyRootFS  bool     `json:"require_read_only_root_fs" yaml:"require_read_only_root_fs"`
+	RequireRunAsNonRoot    bool     `json:"require_run_as_non_root" yaml:"require_run_as_non_root"`
+	DropCapabilities       []string `json:"drop_capabilities" yaml:"drop_capabilities"`
+}
+
+// ImageScan configures how the results of ECR image scans are handled when apis are deployed
+type ImageScan struct {
+	Action            ImageScanAction `json:"action" yaml:"action"`
+	SeverityThreshold string          `json:"severity_threshold" yaml:"severity_threshold"`
+	BlockUnscanned    bool            `json:"block_unscanned" yaml:"block_unscanned"`
+}
+
+var ImageScanSeverityThresholds = []string{"critical", "high", "medium", "low"}
+
+// ImageSignatures configures the cosign public keys which the images of all apis must be signed with
+type ImageSignatures struct {
+	PublicKeys []string `json:"public_keys" yaml:"public_keys"`
+}
+
 type Subnet struct {
 	AvailabilityZone string `json:"availability_zone" yaml:"availability_zone"`
 	SubnetID         string `json:"subnet_id" yaml:"subnet_id"`
@@ -222,9 +315,10 @@ type InternalConfig struct {
 
 // The bare minimum to identify a cluster
 type AccessConfig struct {
-	ClusterName  string `json:"cluster_name" yaml:"cluster_name"`
-	Region       string `json:"region" yaml:"region"`
-	ImageManager string `json:"image_manager" yaml:"image_manager"`
+	ClusterName    string  `json:"cluster_name" yaml:"cluster_name"`
+	Region         string  `json:"region" yaml:"region"`
+	RegistryMirror *string `json:"registry_mirror,omitempty" yaml:"registry_mirror,omitempty"`
+	ImageManager   string  `json:"image_manager" yaml:"image_manager"`
 }
 
 type ConfigureChanges struct {
@@ -308,7 +402,7 @@ var CoreConfigStructFieldValidations = []*cr.StructFieldValidation{
 			Default:   "cortex",
 			MaxLength: 54, // leaves room for 8 char uniqueness string (and "-") for bucket name (63 chars max)
 			MinLength: 3,
-			Validator: validateClusterName,
+			Validator: ClusterNameValidator,
 		},
 	},
 	{
@@ -333,6 +427,13 @@ var CoreConfigStructFieldValidations = []*cr.StructFieldValidation{
 			Default: true,
 		},
 	},
+	{
+		StructField: "RegistryMirror",
+		StringPtrValidation: &cr.StringPtrValidation{
+			AllowExplicitNull: true,
+			Validator:         validateRegistryMirror,
+		},
+	},
 	{
 		StructField: "ImageOperator",
 		StringValidation: &cr.StringValidation{
@@ -403,6 +504,20 @@ var CoreConfigStructFieldValidations = []*cr.StructFieldValidation{
 			Validator: validateImageVersion,
 		},
 	},
+	{
+		StructField: "ImageModelCache",
+		StringValidation: &cr.StringValidation{
+			Default:   consts.DefaultRegistry() + "/model-cache:" + consts.CortexVersion,
+			Validator: validateImageVersion,
+		},
+	},
+	{
+		StructField: "ImagePrepull",
+		StringValidation: &cr.StringValidation{
+			Default:   consts.DefaultRegistry() + "/prepull:" + consts.CortexVersion,
+			Validator: validateImageVersion,
+		},
+	},
 	{
 		StructField: "ImageClusterAutoscaler",
 		StringValidation: &cr.StringValidation{
@@ -567,6 +682,20 @@ var CoreConfigStructFieldValidations = []*cr.StructFieldValidation{
 			AllowExplicitNull: true,
 		},
 	},
+	{
+		StructField: "CustomDomainHostedZoneIDs",
+		StringListValidation: &cr.StringListValidation{
+			AllowEmpty:        true,
+			AllowExplicitNull: true,
+			DisallowDups:      true,
+			Validator: func(hostedZoneIDs []string) ([]string, error) {
+				for i := range hostedZoneIDs {
+					hostedZoneIDs[i] = strings.TrimPrefix(hostedZoneIDs[i], "/hostedzone/")
+				}
+				return hostedZoneIDs, nil
+			},
+		},
+	},
 	{
 		StructField: "IAMPolicyARNs",
 		StringListValidation: &cr.StringListValidation{
@@ -699,6 +828,319 @@ var CoreConfigStructFieldValidations = []*cr.StructFieldValidation{
 			Validator: validateVPCCIDR,
 		},
 	},
+	{
+		StructField: "VPCID",
+		StringPtrValidation: &cr.StringPtrValidation{
+			Prefix: "vpc-",
+		},
+	},
+	{
+		StructField: "Quotas",
+		StructListValidation: &cr.StructListValidation{
+			AllowExplicitNull: true,
+			TreatNullAsEmpty:  true,
+			StructValidation: &cr.StructValidation{
+				StructFieldValidations: []*cr.StructFieldValidation{
+					{
+						StructField: "Name",
+						StringValidation: &cr.StringValidation{
+							Required:  true,
+							DNS1123:   true,
+							MaxLength: 63,
+						},
+					},
+					{
+						StructField: "Selector",
+						StringValidation: &cr.StringValidation{
+							Default:    "",
+							AllowEmpty: true,
+							Validator:  validateQuotaSelector,
+						},
+					},
+					{
+						StructField: "MaxReplicas",
+						Int64PtrValidation: &cr.Int64PtrValidation{
+							GreaterThanOrEqualTo: pointer.Int64(0),
+						},
+					},
+					{
+						StructField: "MaxGPUs",
+						Int64PtrValidation: &cr.Int64PtrValidation{
+							GreaterThanOrEqualTo: pointer.Int64(0),
+						},
+					},
+					{
+						StructField: "MaxConcurrentJobs",
+						Int64PtrValidation: &cr.Int64PtrValidation{
+							GreaterThanOrEqualTo: pointer.Int64(0),
+						},
+					},
+				},
+			},
+		},
+	},
+	{
+		StructField: "Projects",
+		StructListValidation: &cr.StructListValidation{
+			AllowExplicitNull: true,
+			TreatNullAsEmpty:  true,
+			StructValidation: &cr.StructValidation{
+				StructFieldValidations: []*cr.StructFieldValidation{
+					{
+						StructField: "Name",
+						StringValidation: &cr.StringValidation{
+							Required:  true,
+							DNS1123:   true,
+							MaxLength: 63,
+						},
+					},
+					{
+						StructField: "MaxReplicas",
+						Int64PtrValidation: &cr.Int64PtrValidation{
+							GreaterThanOrEqualTo: pointer.Int64(0),
+						},
+					},
+					{
+						StructField: "MaxGPUs",
+						Int64PtrValidation: &cr.Int64PtrValidation{
+							GreaterThanOrEqualTo: pointer.Int64(0),
+						},
+					},
+					{
+						StructField: "MaxConcurrentJobs",
+						Int64PtrValidation: &cr.Int64PtrValidation{
+							GreaterThanOrEqualTo: pointer.Int64(0),
+						},
+					},
+				},
+			},
+		},
+	},
+	{
+		StructField: "Schedules",
+		StructListValidation: &cr.StructListValidation{
+			AllowExplicitNull: true,
+			TreatNullAsEmpty:  true,
+			StructValidation: &cr.StructValidation{
+				StructFieldValidations: []*cr.StructFieldValidation{
+					{
+						StructField: "NodeGroup",
+						StringValidation: &cr.StringValidation{
+							Required: true,
+						},
+					},
+					{
+						StructField: "Cron",
+						StringValidation: &cr.StringValidation{
+							Required:  true,
+							Validator: validateScheduleCron,
+						},
+					},
+					{
+						StructField: "MinInstances",
+						Int64Validation: &cr.Int64Validation{
+							Required:             true,
+							GreaterThanOrEqualTo: pointer.Int64(0),
+						},
+					},
+					{
+						StructField: "MaxInstances",
+						Int64Validation: &cr.Int64Validation{
+							Required:             true,
+							GreaterThanOrEqualTo: pointer.Int64(0),
+						},
+					},
+				},
+			},
+		},
+	},
+	{
+		StructField: "GitOps",
+		StructValidation: &cr.StructValidation{
+			AllowExplicitNull: true,
+			DefaultNil:        true,
+			StructFieldValidations: []*cr.StructFieldValidation{
+				{
+					StructField: "Repository",
+					StringValidation: &cr.StringValidation{
+						Required:  true,
+						Validator: validateGitOpsRepository,
+					},
+				},
+				{
+					StructField: "Branch",
+					StringValidation: &cr.StringValidation{
+						Default: "main",
+					},
+				},
+				{
+					StructField: "Path",
+					StringValidation: &cr.StringValidation{
+						Default:    "",
+						AllowEmpty: true,
+						Validator:  validateGitOpsPath,
+					},
+				},
+				{
+					StructField: "SecretsManagerARN",
+					StringPtrValidation: &cr.StringPtrValidation{
+						Prefix: "arn:",
+					},
+				},
+				{
+					StructField: "Prune",
+					BoolValidation: &cr.BoolValidation{
+						Default: false,
+					},
+				},
+			},
+		},
+	},
+	{
+		StructField: "OIDC",
+		StructValidation: &cr.StructValidation{
+			AllowExplicitNull: true,
+			DefaultNil:        true,
+			StructFieldValidations: []*cr.StructFieldValidation{
+				{
+					StructField: "IssuerURL",
+					StringValidation: &cr.StringValidation{
+						Required:  true,
+						Validator: validateOIDCIssuerURL,
+					},
+				},
+				{
+					StructField: "ClientID",
+					StringValidation: &cr.StringValidation{
+						Required: true,
+					},
+				},
+				{
+					StructField: "UsernameClaim",
+					StringValidation: &cr.StringValidation{
+						Default: "email",
+					},
+				},
+				{
+					StructField: "Scopes",
+					StringListValidation: &cr.StringListValidation{
+						Default:      []string{"openid", "email", "offline_access"},
+						DisallowDups: true,
+						Validator:    validateOIDCScopes,
+					},
+				},
+			},
+		},
+	},
+	{
+		StructField: "SecretsBackend",
+		StringValidation: &cr.StringValidation{
+			AllowedValues: SecretsBackendStrings(),
+			Default:       SecretsManagerSecretsBackend.String(),
+		},
+		Parser: func(str string) (interface{}, error) {
+			return SecretsBackendFromString(str), nil
+		},
+	},
+	{
+		StructField: "NetworkPolicies",
+		BoolValidation: &cr.BoolValidation{
+			Default: false,
+		},
+	},
+	{
+		StructField: "PodSecurity",
+		StructValidation: &cr.StructValidation{
+			AllowExplicitNull: true,
+			DefaultNil:        true,
+			StructFieldValidations: []*cr.StructFieldValidation{
+				{
+					StructField: "AllowPrivileged",
+					BoolValidation: &cr.BoolValidation{
+						Default: true,
+					},
+				},
+				{
+					StructField: "AllowUnconfinedSeccomp",
+					BoolValidation: &cr.BoolValidation{
+						Default: true,
+					},
+				},
+				{
+					StructField: "RequireReadOnlyRootFS",
+					BoolValidation: &cr.BoolValidation{
+						Default: false,
+					},
+				},
+				{
+					StructField: "RequireRunAsNonRoot",
+					BoolValidation: &cr.BoolValidation{
+						Default: false,
+					},
+				},
+				{
+					StructField: "DropCapabilities",
+					StringListValidation: &cr.StringListValidation{
+						Default:           []string{},
+						AllowExplicitNull: true,
+						AllowEmpty:        true,
+						DisallowDups:      true,
+						Validator:         k8s.NormalizeCapabilities,
+					},
+				},
+			},
+		},
+	},
+	{
+		StructField: "ImageScan",
+		StructValidation: &cr.StructValidation{
+			AllowExplicitNull: true,
+			DefaultNil:        true,
+			StructFieldValidations: []*cr.StructFieldValidation{
+				{
+					StructField: "Action",
+					StringValidation: &cr.StringValidation{
+						AllowedValues: ImageScanActionStrings(),
+						Default:       WarnImageScanAction.String(),
+					},
+					Parser: func(str string) (interface{}, error) {
+						return ImageScanActionFromString(str), nil
+					},
+				},
+				{
+					StructField: "SeverityThreshold",
+					StringValidation: &cr.StringValidation{
+						Default:       "critical",
+						AllowedValues: ImageScanSeverityThresholds,
+					},
+				},
+				{
+					StructField: "BlockUnscanned",
+					BoolValidation: &cr.BoolValidation{
+						Default: false,
+					},
+				},
+			},
+		},
+	},
+	{
+		StructField: "ImageSignatures",
+		StructValidation: &cr.StructValidation{
+			AllowExplicitNull: true,
+			DefaultNil:        true,
+			StructFieldValidations: []*cr.StructFieldValidation{
+				{
+					StructField: "PublicKeys",
+					StringListValidation: &cr.StringListValidation{
+						Required:     true,
+						MinLength:    1,
+						DisallowDups: true,
+						Validator:    validateCosignPublicKeys,
+					},
+				},
+			},
+		},
+	},
 }
 
 var ManagedConfigStructFieldValidations = []*cr.StructFieldValidation{
@@ -734,6 +1176,22 @@ var ManagedConfigStructFieldValidations = []*cr.StructFieldValidation{
 			TreatNullAsEmpty: true,
 		},
 	},
+	{
+		StructField: "Arch",
+		StringValidation: &cr.StringValidation{
+			AllowedValues:       ArchStrings(),
+			HiddenAllowedValues: []string{""},
+			AllowEmpty:          true,
+			TreatNullAsEmpty:    true,
+		},
+		Parser: func(str string) (interface{}, error) {
+			return ArchFromString(str), nil
+		},
+	},
+	{
+		StructField:    "Offline",
+		BoolValidation: &cr.BoolValidation{},
+	},
 }
 
 var nodeGroupsFieldValidation *cr.StructValidation = &cr.StructValidation{
@@ -755,6 +1213,18 @@ var nodeGroupsFieldValidation *cr.StructValidation = &cr.StructValidation{
 				Validator: validateInstanceType,
 			},
 		},
+		{
+			StructField: "Arch",
+			StringValidation: &cr.StringValidation{
+				AllowedValues:       ArchStrings(),
+				HiddenAllowedValues: []string{""},
+				AllowEmpty:          true,
+				TreatNullAsEmpty:    true,
+			},
+			Parser: func(str string) (interface{}, error) {
+				return ArchFromString(str), nil
+			},
+		},
 		{
 			StructField: "MinInstances",
 			Int64Validation: &cr.Int64Validation{
@@ -815,6 +1285,19 @@ var nodeGroupsFieldValidation *cr.StructValidation = &cr.StructValidation{
 				Default: false,
 			},
 		},
+		{
+			StructField: "CapacityReservationID",
+			StringPtrValidation: &cr.StringPtrValidation{
+				AllowExplicitNull: true,
+				Prefix:            "cr-",
+			},
+		},
+		{
+			StructField: "CapacityReservationAvailabilityZone",
+			StringValidation: &cr.StringValidation{
+				AllowEmpty: true,
+			},
+		},
 		{
 			StructField: "SpotConfig",
 			StructValidation: &cr.StructValidation{
@@ -871,6 +1354,11 @@ var FullConfigValidation = &cr.StructValidation{
 	AllowExtraFields:       false,
 }
 
+var NodeGroupValidation = &cr.StructValidation{
+	Required:               true,
+	StructFieldValidations: nodeGroupsFieldValidation.StructFieldValidations,
+}
+
 var AccessValidation = &cr.StructValidation{
 	AllowExtraFields: true,
 	StructFieldValidations: []*cr.StructFieldValidation{
@@ -880,7 +1368,7 @@ var AccessValidation = &cr.StructValidation{
 				Default:   "cortex",
 				MaxLength: 54, // leaves room for 8 char uniqueness string (and "-") for bucket name (63 chars max)
 				MinLength: 3,
-				Validator: validateClusterName,
+				Validator: ClusterNameValidator,
 			},
 		},
 		{
@@ -891,6 +1379,13 @@ var AccessValidation = &cr.StructValidation{
 				Validator: RegionValidator,
 			},
 		},
+		{
+			StructField: "RegistryMirror",
+			StringPtrValidation: &cr.StringPtrValidation{
+				AllowExplicitNull: true,
+				Validator:         validateRegistryMirror,
+			},
+		},
 		{
 			StructField: "ImageManager",
 			StringValidation: &cr.StringValidation{
@@ -903,9 +1398,10 @@ var AccessValidation = &cr.StructValidation{
 
 func (cc *Config) ToAccessConfig() AccessConfig {
 	return AccessConfig{
-		ClusterName:  cc.ClusterName,
-		Region:       cc.Region,
-		ImageManager: cc.ImageManager,
+		ClusterName:    cc.ClusterName,
+		Region:         cc.Region,
+		RegistryMirror: cc.RegistryMirror,
+		ImageManager:   cc.ImageManager,
 	}
 }
 
@@ -919,6 +1415,19 @@ func (cc *CoreConfig) SQSNamePrefix() string {
 	return SQSNamePrefix(cc.ClusterName)
 }
 
+// SecretName returns the name (in the cluster's secrets backend) of a secret which is managed with `cortex secrets`
+func (cc *CoreConfig) SecretName(name string) string {
+	return cc.SecretNamePrefix() + name
+}
+
+// SecretNamePrefix returns the prefix of the names of the secrets which are managed with `cortex secrets`, e.g. cortex/<cluster_name>/ (or /cortex/<cluster_name>/ for parameter store, whose hierarchies must start with a slash)
+func (cc *CoreConfig) SecretNamePrefix() string {
+	if cc.SecretsBackend == ParameterStoreSecretsBackend {
+		return "/cortex/" + cc.ClusterName + "/"
+	}
+	return "cortex/" + cc.ClusterName + "/"
+}
+
 func (cc *Config) validate(awsClient *aws.Client) error {
 	if cc.APILoadBalancerType == NLBLoadBalancerType {
 		isSupportedByNLB, err := aws.IsInstanceSupportedByNLB(cc.PrometheusInstanceType)
@@ -936,16 +1445,6 @@ func (cc *Config) validate(awsClient *aws.Client) error {
 	}
 
 	ngNames := []string{}
-	instances := []aws.InstanceTypeRequests{
-		{
-			InstanceType:              _operatorNodeGroupInstanceType,
-			RequiredOnDemandInstances: 1,
-		},
-		{
-			InstanceType:              cc.PrometheusInstanceType,
-			RequiredOnDemandInstances: 1,
-		},
-	}
 	for _, nodeGroup := range cc.NodeGroups {
 		if !slices.HasString(ngNames, nodeGroup.Name) {
 			ngNames = append(ngNames, nodeGroup.Name)
@@ -957,18 +1456,48 @@ func (cc *Config) validate(awsClient *aws.Client) error {
 		if err != nil {
 			return errors.Wrap(err, NodeGroupsKey, nodeGroup.Name)
 		}
+	}
 
-		instances = append(instances, aws.InstanceTypeRequests{
-			InstanceType:              nodeGroup.InstanceType,
-			RequiredOnDemandInstances: nodeGroup.MaxPossibleOnDemandInstances(),
-			RequiredSpotInstances:     nodeGroup.MaxPossibleSpotInstances(),
-		})
+	if err := cc.verifyInstanceQuota(awsClient); err != nil {
+		return err
 	}
 
-	if err := awsClient.VerifyInstanceQuota(instances); err != nil {
-		// Skip AWS errors, since some regions (e.g. eu-north-1) do not support this API
-		if !aws.IsAWSError(err) {
-			return errors.Wrap(err, NodeGroupsKey)
+	cc.ImageOperator = archImage(cc.ImageOperator, "operator", cc.Arch)
+	cc.applyRegistryMirror()
+
+	quotaNames := strset.New()
+	for _, quota := range cc.Quotas {
+		if quotaNames.Has(quota.Name) {
+			return errors.Wrap(ErrorDuplicateQuotaName(quota.Name), QuotasKey)
+		}
+		quotaNames.Add(quota.Name)
+
+		if quota.MaxReplicas == nil && quota.MaxGPUs == nil && quota.MaxConcurrentJobs == nil {
+			return errors.Wrap(ErrorSpecifyAtLeastOneField(MaxReplicasKey, MaxGPUsKey, MaxConcurrentJobsKey), QuotasKey, quota.Name)
+		}
+	}
+
+	projectNames := strset.New()
+	for _, project := range cc.Projects {
+		if projectNames.Has(project.Name) {
+			return errors.Wrap(ErrorDuplicateProjectName(project.Name), ProjectsKey)
+		}
+		if userconfig.ReservedProjects.Has(project.Name) {
+			return errors.Wrap(cr.ErrorDisallowedValue(project.Name), ProjectsKey, project.Name)
+		}
+		projectNames.Add(project.Name)
+	}
+
+	for i, schedule := range cc.Schedules {
+		nodeGroup := cc.GetNodeGroupByName(schedule.NodeGroup)
+		if nodeGroup == nil {
+			return errors.Wrap(ErrorNodeGroupNotFound(schedule.NodeGroup, GetNodeGroupNames(cc.NodeGroups)), SchedulesKey, s.Index(i), ScheduleNodeGroupKey)
+		}
+		if schedule.MinInstances > schedule.MaxInstances {
+			return errors.Wrap(ErrorMinInstancesGreaterThanMax(schedule.MinInstances, schedule.MaxInstances), SchedulesKey, s.Index(i))
+		}
+		if schedule.MaxInstances > nodeGroup.MaxInstances {
+			return errors.Wrap(ErrorScheduleMaxInstancesGreaterThanNodeGroupMax(schedule.MaxInstances, nodeGroup.Name, nodeGroup.MaxInstances), SchedulesKey, s.Index(i), MaxInstancesKey)
 		}
 	}
 
@@ -976,6 +1505,14 @@ func (cc *Config) validate(awsClient *aws.Client) error {
 		return ErrorSpecifyOneOrNone(AvailabilityZonesKey, SubnetsKey)
 	}
 
+	if cc.VPCID != nil && len(cc.Subnets) == 0 {
+		return ErrorDependentFieldMustBeSpecified(VPCIDKey, SubnetsKey)
+	}
+
+	if cc.VPCID != nil && cc.VPCCIDR != nil {
+		return ErrorSpecifyOneOrNone(VPCIDKey, VPCCIDRKey)
+	}
+
 	if len(cc.Subnets) > 0 && cc.NATGateway != NoneNATGateway {
 		return ErrorNoNATGatewayWithSubnets()
 	}
@@ -1041,6 +1578,16 @@ func (cc *Config) validate(awsClient *aws.Client) error {
 		}
 	}
 
+	for _, hostedZoneID := range cc.CustomDomainHostedZoneIDs {
+		hostedZone, err := awsClient.GetHostedZone(hostedZoneID)
+		if err != nil {
+			return errors.Wrap(err, CustomDomainHostedZoneIDsKey)
+		}
+		if hostedZone == nil || (hostedZone.Config != nil && hostedZone.Config.PrivateZone != nil && *hostedZone.Config.PrivateZone) {
+			return errors.Wrap(ErrorPublicHostedZoneNotFound(hostedZoneID), CustomDomainHostedZoneIDsKey)
+		}
+	}
+
 	for tagName, tagValue := range cc.Tags {
 		if strings.HasPrefix(tagName, "cortex.dev/") {
 			if tagName != ClusterNameTag {
@@ -1057,12 +1604,64 @@ func (cc *Config) validate(awsClient *aws.Client) error {
 		if err := cc.validateSubnets(awsClient); err != nil {
 			return errors.Wrap(err, SubnetsKey)
 		}
+		if err := cc.validateExistingSubnets(awsClient); err != nil {
+			return errors.Wrap(err, SubnetsKey)
+		}
 	} else {
 		if err := cc.setAvailabilityZones(awsClient); err != nil {
 			return errors.Wrap(err, AvailabilityZonesKey)
 		}
 	}
 
+	if err := cc.validateCapacityReservationZones(); err != nil {
+		return err
+	}
+
+	if err := cc.validateAMIs(awsClient); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// checks that the EKS-optimized AMIs required by the cluster's instance types are available in the cluster's region
+func (cc *Config) validateAMIs(awsClient *aws.Client) error {
+	type instanceTypeField struct {
+		instanceType string
+		keys         []string
+	}
+	instanceTypeFields := []instanceTypeField{
+		{instanceType: cc.PrometheusInstanceType, keys: []string{PrometheusInstanceTypeKey}},
+		{instanceType: cc.OperatorNodeGroupInstanceType()},
+	}
+	for _, ng := range cc.NodeGroups {
+		instanceTypeFields = append(instanceTypeFields, instanceTypeField{instanceType: ng.InstanceType, keys: []string{NodeGroupsKey, ng.Name, InstanceTypeKey}})
+	}
+
+	availableAMITypes := map[string]bool{}
+	for _, field := range instanceTypeFields {
+		amiType, err := aws.EKSAMIType(field.instanceType)
+		if err != nil {
+			return errors.Wrap(err, field.keys...)
+		}
+
+		if _, ok := availableAMITypes[amiType]; !ok {
+			available, err := awsClient.IsEKSOptimizedAMIAvailable(consts.EKSVersion, amiType)
+			if err != nil {
+				// skip AWS errors (e.g. missing ec2:DescribeImages permissions), since eksctl will surface any issue during installation
+				if !aws.IsAWSError(err) {
+					return err
+				}
+				available = true
+			}
+			availableAMITypes[amiType] = available
+		}
+
+		if !availableAMITypes[amiType] {
+			return errors.Wrap(ErrorAMIUnavailable(field.instanceType, amiType, consts.EKSVersion, cc.Region), field.keys...)
+		}
+	}
+
 	return nil
 }
 
@@ -1091,6 +1690,38 @@ func (cc *Config) validateTopLevelSectionDiff(oldConfig Config) ([]string, error
 		fieldsToUpdate = append(fieldsToUpdate, OperatorLoadBalancerCIDRWhiteListKey)
 	}
 
+	if libstr.Obj(newClusterConfigCopy.Quotas) != libstr.Obj(oldClusterConfigCopy.Quotas) {
+		fieldsToUpdate = append(fieldsToUpdate, QuotasKey)
+	}
+
+	if libstr.Obj(newClusterConfigCopy.Projects) != libstr.Obj(oldClusterConfigCopy.Projects) {
+		fieldsToUpdate = append(fieldsToUpdate, ProjectsKey)
+	}
+
+	if libstr.Obj(newClusterConfigCopy.Schedules) != libstr.Obj(oldClusterConfigCopy.Schedules) {
+		fieldsToUpdate = append(fieldsToUpdate, SchedulesKey)
+	}
+
+	if libstr.Obj(newClusterConfigCopy.GitOps) != libstr.Obj(oldClusterConfigCopy.GitOps) {
+		fieldsToUpdate = append(fieldsToUpdate, GitOpsKey)
+	}
+
+	if libstr.Obj(newClusterConfigCopy.OIDC) != libstr.Obj(oldClusterConfigCopy.OIDC) {
+		fieldsToUpdate = append(fieldsToUpdate, OIDCKey)
+	}
+
+	if libstr.Obj(newClusterConfigCopy.PodSecurity) != libstr.Obj(oldClusterConfigCopy.PodSecurity) {
+		fieldsToUpdate = append(fieldsToUpdate, PodSecurityKey)
+	}
+
+	if libstr.Obj(newClusterConfigCopy.ImageScan) != libstr.Obj(oldClusterConfigCopy.ImageScan) {
+		fieldsToUpdate = append(fieldsToUpdate, ImageScanKey)
+	}
+
+	if libstr.Obj(newClusterConfigCopy.ImageSignatures) != libstr.Obj(oldClusterConfigCopy.ImageSignatures) {
+		fieldsToUpdate = append(fieldsToUpdate, ImageSignaturesKey)
+	}
+
 	clearUpdatableFields(&newClusterConfigCopy)
 	clearUpdatableFields(&oldClusterConfigCopy)
 
@@ -1113,6 +1744,14 @@ func clearUpdatableFields(clusterConfig *Config) {
 	clusterConfig.SSLCertificateARN = nil
 	clusterConfig.APILoadBalancerCIDRWhiteList = nil
 	clusterConfig.OperatorLoadBalancerCIDRWhiteList = nil
+	clusterConfig.Quotas = nil
+	clusterConfig.Projects = nil
+	clusterConfig.Schedules = nil
+	clusterConfig.GitOps = nil
+	clusterConfig.OIDC = nil
+	clusterConfig.PodSecurity = nil
+	clusterConfig.ImageScan = nil
+	clusterConfig.ImageSignatures = nil
 	clusterConfig.NodeGroups = []*NodeGroup{}
 }
 
@@ -1134,6 +1773,9 @@ func (cc *Config) validateSharedNodeGroupsDiff(oldConfig Config) error {
 		oldNgCopy.MinInstances = 0
 		oldNgCopy.MaxInstances = 0
 		oldNgCopy.Priority = 0
+		// the arch is derived from the instance types (which are compared), and isn't set in configs from older versions
+		newNgCopy.Arch = UnknownArch
+		oldNgCopy.Arch = UnknownArch
 
 		newHash, err := newNgCopy.Hash()
 		if err != nil {
@@ -1151,6 +1793,36 @@ func (cc *Config) validateSharedNodeGroupsDiff(oldConfig Config) error {
 	return nil
 }
 
+func (cc *Config) verifyInstanceQuota(awsClient *aws.Client) error {
+	instances := []aws.InstanceTypeRequests{
+		{
+			InstanceType:              cc.OperatorNodeGroupInstanceType(),
+			RequiredOnDemandInstances: 1,
+		},
+		{
+			InstanceType:              cc.PrometheusInstanceType,
+			RequiredOnDemandInstances: 1,
+		},
+	}
+	for _, nodeGroup := range cc.NodeGroups {
+		instances = append(instances, aws.InstanceTypeRequests{
+			InstanceType:              nodeGroup.InstanceType,
+			RequiredOnDemandInstances: nodeGroup.MaxPossibleOnDemandInstances(),
+			RequiredSpotInstances:     nodeGroup.MaxPossibleSpotInstances(),
+		})
+	}
+
+	// the cluster's own instances are not counted towards the current usage, since they are already included in the requested instances
+	if err := awsClient.VerifyInstanceQuota(instances, map[string]string{ClusterNameTag: cc.ClusterName}); err != nil {
+		// Skip AWS errors, since some regions (e.g. eu-north-1) do not support this API
+		if !aws.IsAWSError(err) {
+			return errors.Wrap(err, NodeGroupsKey)
+		}
+	}
+
+	return nil
+}
+
 func (cc *Config) validateNodeAdditionRate(k8sClient *k8s.Client) error {
 	workloadNodes, err := k8sClient.ListNodesByLabel("workload", "true")
 	if err != nil {
@@ -1168,7 +1840,14 @@ func (cc *Config) validateNodeAdditionRate(k8sClient *k8s.Client) error {
 
 // this validates the user-provided cluster config
 func (cc *Config) ValidateOnInstall(awsClient *aws.Client) error {
-	fmt.Print("verifying your configuration ...\n\n")
+	if cc.Arch != UnknownArch {
+		return ErrorDisallowedField(ArchKey)
+	}
+	cc.Arch = nodeGroupsArch(cc.NodeGroups)
+
+	if cc.Offline && cc.RegistryMirror == nil {
+		return ErrorOfflineRequiresRegistryMirror()
+	}
 
 	err := cc.validate(awsClient)
 	if err != nil {
@@ -1211,11 +1890,17 @@ func (cc *Config) ValidateOnConfigure(awsClient *aws.Client, k8sClient *k8s.Clie
 	fmt.Print("verifying your configuration ...\n\n")
 
 	cc.ClusterUID = oldConfig.ClusterUID
+	cc.Arch = oldConfig.Arch
+	cc.Offline = oldConfig.Offline
 	err := cc.validate(awsClient)
 	if err != nil {
 		return ConfigureChanges{}, err
 	}
 
+	if err := cc.validateCapacityReservationZones(); err != nil {
+		return ConfigureChanges{}, err
+	}
+
 	fieldsToUpdate, err := cc.validateTopLevelSectionDiff(oldConfig)
 	if err != nil {
 		return ConfigureChanges{}, err
@@ -1261,6 +1946,41 @@ func (cc *Config) ValidateOnConfigure(awsClient *aws.Client, k8sClient *k8s.Clie
 	}, nil
 }
 
+// ValidateOnScale updates the min and max instances of one of the cluster's node groups (nil values are left unchanged) and validates the result; cc must be the cluster's current configuration
+func (cc *Config) ValidateOnScale(awsClient *aws.Client, k8sClient *k8s.Client, nodeGroupName string, minInstances *int64, maxInstances *int64) error {
+	nodeGroup := cc.GetNodeGroupByName(nodeGroupName)
+	if nodeGroup == nil {
+		return ErrorNodeGroupNotFound(nodeGroupName, GetNodeGroupNames(cc.NodeGroups))
+	}
+
+	if minInstances != nil {
+		nodeGroup.MinInstances = *minInstances
+	}
+	if maxInstances != nil {
+		nodeGroup.MaxInstances = *maxInstances
+	}
+
+	if nodeGroup.MinInstances > nodeGroup.MaxInstances {
+		return errors.Wrap(ErrorMinInstancesGreaterThanMax(nodeGroup.MinInstances, nodeGroup.MaxInstances), NodeGroupsKey, nodeGroupName)
+	}
+
+	if nodeGroup.SpotConfig != nil && nodeGroup.SpotConfig.OnDemandBaseCapacity != nil && *nodeGroup.SpotConfig.OnDemandBaseCapacity > nodeGroup.MaxInstances {
+		return errors.Wrap(ErrorOnDemandBaseCapacityGreaterThanMax(*nodeGroup.SpotConfig.OnDemandBaseCapacity, nodeGroup.MaxInstances), NodeGroupsKey, nodeGroupName, SpotConfigKey, OnDemandBaseCapacityKey)
+	}
+
+	for i, schedule := range cc.Schedules {
+		if schedule.NodeGroup == nodeGroupName && schedule.MaxInstances > nodeGroup.MaxInstances {
+			return errors.Wrap(ErrorScheduleMaxInstancesGreaterThanNodeGroupMax(schedule.MaxInstances, nodeGroup.Name, nodeGroup.MaxInstances), SchedulesKey, s.Index(i), MaxInstancesKey)
+		}
+	}
+
+	if err := cc.validateNodeAdditionRate(k8sClient); err != nil {
+		return errors.Wrap(err, NodeGroupsKey)
+	}
+
+	return cc.verifyInstanceQuota(awsClient)
+}
+
 func (ng *NodeGroup) validateNodeGroup(awsClient *aws.Client, region string, loadBalancerType LoadBalancerType) error {
 	if ng.MinInstances > ng.MaxInstances {
 		return ErrorMinInstancesGreaterThanMax(ng.MinInstances, ng.MaxInstances)
@@ -1286,6 +2006,15 @@ func (ng *NodeGroup) validateNodeGroup(awsClient *aws.Client, region string, loa
 		return errors.Wrap(ErrorInstanceTypeNotSupportedByCortex(primaryInstanceType), InstanceTypeKey)
 	}
 
+	primaryArch, err := InstanceTypeArch(primaryInstanceType)
+	if err != nil {
+		return errors.Wrap(err, InstanceTypeKey)
+	}
+	if ng.Arch != UnknownArch && ng.Arch != primaryArch {
+		return errors.Wrap(ErrorInstanceTypeArchMismatch(primaryInstanceType, primaryArch, ng.Arch), InstanceTypeKey)
+	}
+	ng.Arch = primaryArch
+
 	// throw error if IOPS defined for other storage than io1/gp3
 	if ng.InstanceVolumeType != IO1VolumeType && ng.InstanceVolumeType != GP3VolumeType && ng.InstanceVolumeIOPS != nil {
 		return ErrorIOPSNotSupported(ng.InstanceVolumeType)
@@ -1347,6 +2076,14 @@ func (ng *NodeGroup) validateNodeGroup(awsClient *aws.Client, region string, loa
 				return errors.Wrap(ErrorInstanceTypeNotSupportedInRegion(instanceType, region), SpotConfigKey, InstanceDistributionKey)
 			}
 
+			instanceArch, err := InstanceTypeArch(instanceType)
+			if err != nil {
+				return errors.Wrap(err, SpotConfigKey, InstanceDistributionKey)
+			}
+			if instanceArch != ng.Arch {
+				return errors.Wrap(ErrorInstanceTypeArchMismatch(instanceType, instanceArch, ng.Arch), SpotConfigKey, InstanceDistributionKey)
+			}
+
 			if loadBalancerType == NLBLoadBalancerType {
 				isSecondaryInstanceSupportedByNLB, err := aws.IsInstanceSupportedByNLB(primaryInstanceType)
 				if err != nil {
@@ -1361,7 +2098,7 @@ func (ng *NodeGroup) validateNodeGroup(awsClient *aws.Client, region string, loa
 			}
 
 			instanceMetadata := aws.InstanceMetadatas[region][instanceType]
-			err := CheckSpotInstanceCompatibility(primaryInstance, instanceMetadata)
+			err = CheckSpotInstanceCompatibility(primaryInstance, instanceMetadata)
 			if err != nil {
 				return errors.Wrap(err, SpotConfigKey, InstanceDistributionKey)
 			}
@@ -1383,6 +2120,68 @@ func (ng *NodeGroup) validateNodeGroup(awsClient *aws.Client, region string, loa
 		}
 	}
 
+	if ng.CapacityReservationID != nil {
+		if err := ng.validateCapacityReservation(awsClient, region); err != nil {
+			return errors.Wrap(err, CapacityReservationIDKey)
+		}
+	} else {
+		ng.CapacityReservationAvailabilityZone = ""
+	}
+
+	return nil
+}
+
+func (ng *NodeGroup) validateCapacityReservation(awsClient *aws.Client, region string) error {
+	capacityReservationID := *ng.CapacityReservationID
+
+	if ng.Spot {
+		return ErrorFieldConfigurationDependentOnCondition(CapacityReservationIDKey, capacityReservationID, SpotKey, s.UserStr(ng.Spot))
+	}
+
+	capacityReservation, err := awsClient.GetCapacityReservationOrNil(capacityReservationID)
+	if err != nil {
+		return err
+	}
+	if capacityReservation == nil {
+		return ErrorCapacityReservationNotFound(capacityReservationID, region)
+	}
+
+	if state := *capacityReservation.State; state != ec2.CapacityReservationStateActive {
+		return ErrorCapacityReservationNotActive(capacityReservationID, state)
+	}
+
+	if *capacityReservation.InstanceType != ng.InstanceType {
+		return ErrorCapacityReservationInstanceTypeMismatch(capacityReservationID, *capacityReservation.InstanceType, ng.InstanceType)
+	}
+
+	if totalInstances := *capacityReservation.TotalInstanceCount; ng.MaxInstances > totalInstances {
+		return ErrorCapacityReservationTooSmall(capacityReservationID, totalInstances, ng.MaxInstances)
+	}
+
+	ng.CapacityReservationAvailabilityZone = *capacityReservation.AvailabilityZone
+
+	return nil
+}
+
+// nodegroups which use a capacity reservation can only launch instances in the reservation's availability zone, which must be one of the cluster's zones
+func (cc *Config) validateCapacityReservationZones() error {
+	zones := strset.New(cc.AvailabilityZones...)
+	for _, subnet := range cc.Subnets {
+		zones.Add(subnet.AvailabilityZone)
+	}
+	if len(zones) == 0 {
+		return nil
+	}
+
+	for _, ng := range cc.NodeGroups {
+		if ng.CapacityReservationID == nil || ng.CapacityReservationAvailabilityZone == "" {
+			continue
+		}
+		if !zones.Has(ng.CapacityReservationAvailabilityZone) {
+			return errors.Wrap(ErrorCapacityReservationZoneNotInCluster(*ng.CapacityReservationID, ng.CapacityReservationAvailabilityZone, zones.SliceSorted()), NodeGroupsKey, ng.Name, CapacityReservationIDKey)
+		}
+	}
+
 	return nil
 }
 
@@ -1533,6 +2332,60 @@ func (ng *NodeGroup) FillEmptySpotFields(region string) {
 	AutoGenerateSpotConfig(ng.SpotConfig, region, ng.InstanceType)
 }
 
+func validateScheduleCron(schedule string) (string, error) {
+	if _, err := cron.ParseSchedule(schedule); err != nil {
+		return "", err
+	}
+	return schedule, nil
+}
+
+func validateGitOpsRepository(repository string) (string, error) {
+	if !strings.HasPrefix(repository, "https://") {
+		return "", ErrorInvalidGitOpsRepository(repository)
+	}
+	return strings.TrimSuffix(repository, "/"), nil
+}
+
+func validateOIDCIssuerURL(issuerURL string) (string, error) {
+	if !strings.HasPrefix(issuerURL, "https://") {
+		return "", ErrorInvalidOIDCIssuerURL(issuerURL)
+	}
+	return strings.TrimSuffix(issuerURL, "/"), nil
+}
+
+func validateOIDCScopes(scopes []string) ([]string, error) {
+	if !slices.HasString(scopes, "openid") {
+		return nil, ErrorOIDCScopesMissingOpenID()
+	}
+	return scopes, nil
+}
+
+func validateGitOpsPath(path string) (string, error) {
+	path = strings.Trim(path, "/")
+	for _, part := range strings.Split(path, "/") {
+		if part == ".." {
+			return "", ErrorInvalidGitOpsPath(path)
+		}
+	}
+	return path, nil
+}
+
+func validateQuotaSelector(selector string) (string, error) {
+	if _, err := klabels.Parse(selector); err != nil {
+		return "", ErrorInvalidQuotaSelector(selector, err)
+	}
+	return selector, nil
+}
+
+func validateCosignPublicKeys(publicKeys []string) ([]string, error) {
+	for i, publicKey := range publicKeys {
+		if _, err := docker.ParseCosignPublicKey(publicKey); err != nil {
+			return nil, errors.Wrap(err, s.Index(i))
+		}
+	}
+	return publicKeys, nil
+}
+
 func validateCIDR(cidr string) (string, error) {
 	_, _, err := net.ParseCIDR(cidr)
 	if err != nil {
@@ -1662,6 +2515,93 @@ func validateInstanceDistribution(instances []string) ([]string, error) {
 	return instances, nil
 }
 
+// OperatorNodeGroupInstanceType returns the instance type of the nodegroup which runs the cortex system components
+func (cc *Config) OperatorNodeGroupInstanceType() string {
+	return OperatorNodeGroupInstanceType(cc.Arch)
+}
+
+func OperatorNodeGroupInstanceType(arch Arch) string {
+	if arch == ARM64Arch {
+		return _armOperatorNodeGroupInstanceType
+	}
+	return _operatorNodeGroupInstanceType
+}
+
+// a cluster is arm64 (i.e. the cortex system components also run on graviton instances) if all of its nodegroups are arm64 on cluster up
+func nodeGroupsArch(nodeGroups []*NodeGroup) Arch {
+	if len(nodeGroups) == 0 {
+		return AMD64Arch
+	}
+
+	for _, ng := range nodeGroups {
+		arch, err := InstanceTypeArch(ng.InstanceType)
+		if err != nil || arch != ARM64Arch {
+			return AMD64Arch
+		}
+	}
+
+	return ARM64Arch
+}
+
+// selects the arch-specific tag of a default multi-arch cortex image (see build/build-image.sh);
+// custom images, and images from dev registries (which only contain the multi-arch manifests), are left as is
+func archImage(image string, imageName string, arch Arch) string {
+	if arch != ARM64Arch || consts.DefaultRegistry() != consts.ReleaseRegistry {
+		return image
+	}
+
+	if image != consts.DefaultRegistry()+"/"+imageName+":"+consts.CortexVersion {
+		return image
+	}
+
+	return consts.DefaultRegistry() + "/" + imageName + ":manifest-" + consts.CortexVersion + "-" + arch.String()
+}
+
+// MirrorImage replaces the registry of a default cortex image with the registry mirror; custom images are left as is
+func MirrorImage(image string, registryMirror *string) string {
+	if registryMirror == nil || !strings.HasPrefix(image, consts.DefaultRegistry()+"/") {
+		return image
+	}
+	return *registryMirror + "/" + strings.TrimPrefix(image, consts.DefaultRegistry()+"/")
+}
+
+func (cc *CoreConfig) applyRegistryMirror() {
+	for _, image := range []*string{
+		&cc.ImageOperator,
+		&cc.ImageControllerManager,
+		&cc.ImageManager,
+		&cc.ImageKubexit,
+		&cc.ImageProxy,
+		&cc.ImageActivator,
+		&cc.ImageAutoscaler,
+		&cc.ImageAsyncGateway,
+		&cc.ImageEnqueuer,
+		&cc.ImageDequeuer,
+		&cc.ImageModelCache,
+		&cc.ImagePrepull,
+		&cc.ImageClusterAutoscaler,
+		&cc.ImageMetricsServer,
+		&cc.ImageNvidiaDevicePlugin,
+		&cc.ImageNeuronDevicePlugin,
+		&cc.ImageNeuronScheduler,
+		&cc.ImageFluentBit,
+		&cc.ImageIstioProxy,
+		&cc.ImageIstioPilot,
+		&cc.ImagePrometheus,
+		&cc.ImagePrometheusConfigReloader,
+		&cc.ImagePrometheusOperator,
+		&cc.ImagePrometheusStatsDExporter,
+		&cc.ImagePrometheusDCGMExporter,
+		&cc.ImagePrometheusKubeStateMetrics,
+		&cc.ImagePrometheusNodeExporter,
+		&cc.ImageKubeRBACProxy,
+		&cc.ImageGrafana,
+		&cc.ImageEventExporter,
+	} {
+		*image = MirrorImage(*image, cc.RegistryMirror)
+	}
+}
+
 func (ng *NodeGroup) DeepCopy() (NodeGroup, error) {
 	deepCopied := NodeGroup{}
 	err := structs.DeepCopy(&deepCopied, ng)
@@ -1788,6 +2728,10 @@ func (cc *CoreConfig) TelemetryEvent() map[string]interface{} {
 	event["region"] = cc.Region
 	event["prometheus_instance_type"] = cc.PrometheusInstanceType
 
+	if cc.RegistryMirror != nil {
+		event["registry_mirror._is_defined"] = true
+	}
+
 	if !strings.HasPrefix(cc.ImageOperator, "quay.io/cortexlabs/") {
 		event["image_operator._is_custom"] = true
 	}
@@ -1818,6 +2762,12 @@ func (cc *CoreConfig) TelemetryEvent() map[string]interface{} {
 	if !strings.HasPrefix(cc.ImageDequeuer, "quay.io/cortexlabs/") {
 		event["image_dequeuer._is_custom"] = true
 	}
+	if !strings.HasPrefix(cc.ImageModelCache, "quay.io/cortexlabs/") {
+		event["image_model_cache._is_custom"] = true
+	}
+	if !strings.HasPrefix(cc.ImagePrepull, "quay.io/cortexlabs/") {
+		event["image_prepull._is_custom"] = true
+	}
 	if !strings.HasPrefix(cc.ImageClusterAutoscaler, "quay.io/cortexlabs/") {
 		event["image_cluster_autoscaler._is_custom"] = true
 	}
@@ -1890,6 +2840,9 @@ func (cc *CoreConfig) TelemetryEvent() map[string]interface{} {
 	if cc.SSLCertificateARN != nil {
 		event["ssl_certificate_arn._is_defined"] = true
 	}
+	if len(cc.CustomDomainHostedZoneIDs) > 0 {
+		event["custom_domain_hosted_zone_ids._len"] = len(cc.CustomDomainHostedZoneIDs)
+	}
 
 	// CortexPolicyARN should be managed by cortex
 	if !strset.New(_defaultIAMPolicies...).IsEqual(strset.New(cc.IAMPolicyARNs...)) {
@@ -1899,12 +2852,58 @@ func (cc *CoreConfig) TelemetryEvent() map[string]interface{} {
 
 	event["subnet_visibility"] = cc.SubnetVisibility
 	event["nat_gateway"] = cc.NATGateway
+	event["secrets_backend"] = cc.SecretsBackend
+	event["network_policies"] = cc.NetworkPolicies
+	if cc.PodSecurity != nil {
+		event["pod_security._is_defined"] = true
+		event["pod_security.allow_privileged"] = cc.PodSecurity.AllowPrivileged
+		event["pod_security.allow_unconfined_seccomp"] = cc.PodSecurity.AllowUnconfinedSeccomp
+		event["pod_security.require_read_only_root_fs"] = cc.PodSecurity.RequireReadOnlyRootFS
+		event["pod_security.require_run_as_non_root"] = cc.PodSecurity.RequireRunAsNonRoot
+		event["pod_security.drop_capabilities._len"] = len(cc.PodSecurity.DropCapabilities)
+	}
+	if cc.ImageScan != nil {
+		event["image_scan._is_defined"] = true
+		event["image_scan.action"] = cc.ImageScan.Action
+		event["image_scan.severity_threshold"] = cc.ImageScan.SeverityThreshold
+		event["image_scan.block_unscanned"] = cc.ImageScan.BlockUnscanned
+	}
+	if cc.ImageSignatures != nil {
+		event["image_signatures._is_defined"] = true
+		event["image_signatures.public_keys._len"] = len(cc.ImageSignatures.PublicKeys)
+	}
 	event["api_load_balancer_type"] = cc.APILoadBalancerType
 	event["api_load_balancer_scheme"] = cc.APILoadBalancerScheme
 	event["operator_load_balancer_scheme"] = cc.OperatorLoadBalancerScheme
 	if cc.VPCCIDR != nil {
 		event["vpc_cidr._is_defined"] = true
 	}
+	if cc.VPCID != nil {
+		event["vpc_id._is_defined"] = true
+	}
+	if len(cc.Quotas) > 0 {
+		event["quotas._is_defined"] = true
+		event["quotas._len"] = len(cc.Quotas)
+	}
+	if len(cc.Projects) > 0 {
+		event["projects._is_defined"] = true
+		event["projects._len"] = len(cc.Projects)
+	}
+	if len(cc.Schedules) > 0 {
+		event["schedules._is_defined"] = true
+		event["schedules._len"] = len(cc.Schedules)
+	}
+	if cc.GitOps != nil {
+		event["gitops._is_defined"] = true
+		event["gitops.prune"] = cc.GitOps.Prune
+		if cc.GitOps.SecretsManagerARN != nil {
+			event["gitops.secrets_manager_arn._is_defined"] = true
+		}
+	}
+	if cc.OIDC != nil {
+		event["oidc._is_defined"] = true
+		event["oidc.username_claim"] = cc.OIDC.UsernameClaim
+	}
 
 	onDemandInstanceTypes := strset.New()
 	spotInstanceTypes := strset.New()
@@ -1919,6 +2918,10 @@ func (cc *CoreConfig) TelemetryEvent() map[string]interface{} {
 		event[nodeGroupKey("_is_defined")] = true
 		event[nodeGroupKey("name")] = ng.Name
 		event[nodeGroupKey("instance_type")] = ng.InstanceType
+		event[nodeGroupKey("arch")] = ng.Arch
+		if ng.CapacityReservationID != nil {
+			event[nodeGroupKey("capacity_reservation._is_defined")] = true
+		}
 		event[nodeGroupKey("min_instances")] = ng.MinInstances
 		event[nodeGroupKey("max_instances")] = ng.MaxInstances
 		event[nodeGroupKey("priority")] = ng.Priority
@@ -1998,18 +3001,66 @@ func (cc *CoreConfig) GetNodeGroupNames() []string {
 	return allNodeGroupNames
 }
 
+func (cc *CoreConfig) GetProjectByName(name string) *Project {
+	for _, project := range cc.Projects {
+		if project.Name == name {
+			return project
+		}
+	}
+	return nil
+}
+
+func (cc *CoreConfig) GetProjectNames() []string {
+	projectNames := make([]string, len(cc.Projects))
+	for i := range cc.Projects {
+		projectNames[i] = cc.Projects[i].Name
+	}
+	return projectNames
+}
+
+// AllQuotas returns the quotas in the quotas section, followed by a quota for each project which sets a limit
+func (cc *CoreConfig) AllQuotas() []*Quota {
+	quotas := append([]*Quota{}, cc.Quotas...)
+	for _, project := range cc.Projects {
+		if project.MaxReplicas == nil && project.MaxGPUs == nil && project.MaxConcurrentJobs == nil {
+			continue
+		}
+		quotas = append(quotas, &Quota{
+			Name:              ProjectQuotaName(project.Name),
+			Selector:          klabels.SelectorFromSet(klabels.Set{userconfig.ProjectLabelKey: project.Name}).String(),
+			MaxReplicas:       project.MaxReplicas,
+			MaxGPUs:           project.MaxGPUs,
+			MaxConcurrentJobs: project.MaxConcurrentJobs,
+		})
+	}
+	return quotas
+}
+
+// ProjectQuotaName is the name of a project's quota (it can't collide with the names of the quotas in the quotas section, which must be DNS-1123 labels)
+func ProjectQuotaName(projectName string) string {
+	return "project/" + projectName
+}
+
 func BucketName(accountID, clusterName, region string) string {
 	bucketID := libhash.String(accountID + region)[:8] // this is to "guarantee" a globally unique name
 	return clusterName + "-" + bucketID
 }
 
-func validateClusterName(clusterName string) (string, error) {
+func ClusterNameValidator(clusterName string) (string, error) {
 	if !_strictS3BucketRegex.MatchString(clusterName) {
 		return "", errors.Wrap(ErrorDidNotMatchStrictS3Regex(), clusterName)
 	}
 	return clusterName, nil
 }
 
+func validateRegistryMirror(registryMirror string) (string, error) {
+	registryMirror = strings.TrimSuffix(registryMirror, "/")
+	if registryMirror == "" || strings.Contains(registryMirror, "://") || strings.ContainsAny(registryMirror, "@ ") {
+		return "", ErrorInvalidRegistryMirror(registryMirror)
+	}
+	return registryMirror, nil
+}
+
 func validateImageVersion(image string) (string, error) {
 	return cr.ValidateImageVersion(image, consts.CortexVersion)
 }
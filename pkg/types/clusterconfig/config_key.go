@@ -19,10 +19,13 @@ package clusterconfig
 const (
 	BucketKey     = "bucket"
 	ClusterUIDKey = "cluster_uid"
+	ArchKey       = "arch"
+	OfflineKey    = "offline"
 
 	ClusterNameKey                         = "cluster_name"
 	RegionKey                              = "region"
 	PrometheusInstanceTypeKey              = "prometheus_instance_type"
+	RegistryMirrorKey                      = "registry_mirror"
 	NodeGroupsKey                          = "node_groups"
 	InstanceTypeKey                        = "instance_type"
 	AcceleratorTypeKey                     = "accelerator_type"
@@ -32,6 +35,7 @@ const (
 	PriorityKey                            = "priority"
 	SpotKey                                = "spot"
 	SpotConfigKey                          = "spot_config"
+	CapacityReservationIDKey               = "capacity_reservation_id"
 	InstanceDistributionKey                = "instance_distribution"
 	OnDemandBaseCapacityKey                = "on_demand_base_capacity"
 	OnDemandPercentageAboveBaseCapacityKey = "on_demand_percentage_above_base_capacity"
@@ -49,15 +53,39 @@ const (
 	AvailabilityZoneKey                    = "availability_zone"
 	SubnetIDKey                            = "subnet_id"
 	SSLCertificateARNKey                   = "ssl_certificate_arn"
+	CustomDomainHostedZoneIDsKey           = "custom_domain_hosted_zone_ids"
 	CortexPolicyARNKey                     = "cortex_policy_arn"
 	IAMPolicyARNsKey                       = "iam_policy_arns"
 	SubnetVisibilityKey                    = "subnet_visibility"
 	NATGatewayKey                          = "nat_gateway"
 	APILoadBalancerSchemeKey               = "api_load_balancer_scheme"
+	APILoadBalancerTypeKey                 = "api_load_balancer_type"
 	OperatorLoadBalancerSchemeKey          = "operator_load_balancer_scheme"
 	APILoadBalancerCIDRWhiteListKey        = "api_load_balancer_cidr_white_list"
 	OperatorLoadBalancerCIDRWhiteListKey   = "operator_load_balancer_cidr_white_list"
 	VPCCIDRKey                             = "vpc_cidr"
+	VPCIDKey                               = "vpc_id"
+	QuotasKey                              = "quotas"
+	QuotaNameKey                           = "name"
+	SelectorKey                            = "selector"
+	MaxReplicasKey                         = "max_replicas"
+	MaxGPUsKey                             = "max_gpus"
+	MaxConcurrentJobsKey                   = "max_concurrent_jobs"
+	ProjectsKey                            = "projects"
+	SchedulesKey                           = "schedules"
+	ScheduleNodeGroupKey                   = "node_group"
+	CronKey                                = "cron"
+	GitOpsKey                              = "gitops"
+	OIDCKey                                = "oidc"
+	SecretsBackendKey                      = "secrets_backend"
+	NetworkPoliciesKey                     = "network_policies"
+	PodSecurityKey                         = "pod_security"
+	ImageScanKey                           = "image_scan"
+	ImageScanActionKey                     = "action"
+	SeverityThresholdKey                   = "severity_threshold"
+	BlockUnscannedKey                      = "block_unscanned"
+	ImageSignaturesKey                     = "image_signatures"
+	PublicKeysKey                          = "public_keys"
 	AccountIDKey                           = "account_id"
 	TelemetryKey                           = "telemetry"
 )
@@ -28,61 +28,87 @@ import (
 )
 
 const (
-	ErrInvalidProvider                        = "clusterconfig.invalid_provider"
-	ErrInvalidLegacyProvider                  = "clusterconfig.invalid_legacy_provider"
-	ErrDisallowedField                        = "clusterconfig.disallowed_field"
-	ErrInvalidRegion                          = "clusterconfig.invalid_region"
-	ErrNodeGroupMaxInstancesIsZero            = "clusterconfig.node_group_max_instances_is_zero"
-	ErrMaxNumOfNodeGroupsReached              = "clusterconfig.max_num_of_nodegroups_reached"
-	ErrDuplicateNodeGroupName                 = "clusterconfig.duplicate_nodegroup_name"
-	ErrMaxNodesToAddOnClusterUp               = "clusterconfig.max_nodes_to_add_on_cluster_up"
-	ErrMaxNodesToAddOnClusterConfigure        = "clusterconfig.max_nodes_to_add_on_cluster_configure"
-	ErrInstanceTypeTooSmall                   = "clusterconfig.instance_type_too_small"
-	ErrMinInstancesGreaterThanMax             = "clusterconfig.min_instances_greater_than_max"
-	ErrInstanceTypeNotSupportedInRegion       = "clusterconfig.instance_type_not_supported_in_region"
-	ErrIncompatibleSpotInstanceTypeMemory     = "clusterconfig.incompatible_spot_instance_type_memory"
-	ErrIncompatibleSpotInstanceTypeCPU        = "clusterconfig.incompatible_spot_instance_type_cpu"
-	ErrIncompatibleSpotInstanceTypeGPU        = "clusterconfig.incompatible_spot_instance_type_gpu"
-	ErrIncompatibleSpotInstanceTypeInf        = "clusterconfig.incompatible_spot_instance_type_inf"
-	ErrSpotPriceGreaterThanTargetOnDemand     = "clusterconfig.spot_price_greater_than_target_on_demand"
-	ErrSpotPriceGreaterThanMaxPrice           = "clusterconfig.spot_price_greater_than_max_price"
-	ErrInstanceTypeNotSupportedByCortex       = "clusterconfig.instance_type_not_supported_by_cortex"
-	ErrAMDGPUInstancesNotSupported            = "clusterconfig.amd_gpu_instances_not_supported"
-	ErrGPUInstancesNotSupported               = "clusterconfig.gpu_instance_not_supported"
-	ErrInferentiaInstancesNotSupported        = "clusterconfig.inferentia_instances_not_supported"
-	ErrMacInstancesNotSupported               = "clusterconfig.mac_instances_not_supported"
-	ErrFPGAInstancesNotSupported              = "clusterconfig.fpga_instances_not_supported"
-	ErrAlevoInstancesNotSupported             = "clusterconfig.alevo_instances_not_supported"
-	ErrGaudiInstancesNotSupported             = "clusterconfig.gaudi_instances_not_supported"
-	ErrTrainiumInstancesNotSupported          = "clusterconfig.trainium_instances_not_supported"
-	ErrAtLeastOneInstanceDistribution         = "clusterconfig.at_least_one_instance_distribution"
-	ErrNoCompatibleSpotInstanceFound          = "clusterconfig.no_compatible_spot_instance_found"
-	ErrConfiguredWhenSpotIsNotEnabled         = "clusterconfig.configured_when_spot_is_not_enabled"
-	ErrOnDemandBaseCapacityGreaterThanMax     = "clusterconfig.on_demand_base_capacity_greater_than_max"
-	ErrInvalidAvailabilityZone                = "clusterconfig.invalid_availability_zone"
-	ErrAvailabilityZoneSpecifiedTwice         = "clusterconfig.availability_zone_specified_twice"
-	ErrUnsupportedAvailabilityZone            = "clusterconfig.unsupported_availability_zone"
-	ErrNotEnoughValidDefaultAvailibilityZones = "clusterconfig.not_enough_valid_default_availability_zones"
-	ErrNoNATGatewayWithSubnets                = "clusterconfig.no_nat_gateway_with_subnets"
-	ErrSubnetMaskOutOfRange                   = "clusterconfig.subnet_mask_out_of_range"
-	ErrConfigCannotBeChangedOnConfigure       = "clusterconfig.config_cannot_be_changed_on_configure"
-	ErrNodeGroupCanOnlyBeScaled               = "clusterconfig.node_group_can_only_be_scaled"
-	ErrSpecifyOneOrNone                       = "clusterconfig.specify_one_or_none"
-	ErrSpecifyTwoOrNone                       = "clusterconfig.specify_two_or_none"
-	ErrDependentFieldMustBeSpecified          = "clusterconfig.dependent_field_must_be_specified"
-	ErrFieldConfigurationDependentOnCondition = "clusterconfig.field_configuration_dependent_on_condition"
-	ErrDidNotMatchStrictS3Regex               = "clusterconfig.did_not_match_strict_s3_regex"
-	ErrNATRequiredWithPrivateSubnetVisibility = "clusterconfig.nat_required_with_private_subnet_visibility"
-	ErrS3RegionDiffersFromCluster             = "clusterconfig.s3_region_differs_from_cluster"
-	ErrIOPSNotSupported                       = "clusterconfig.iops_not_supported"
-	ErrThroughputNotSupported                 = "clusterconfig.throughput_not_supported"
-	ErrIOPSTooSmall                           = "clusterconfig.iops_too_small"
-	ErrIOPSTooLarge                           = "clusterconfig.iops_too_large"
-	ErrIOPSToVolumeSizeRatio                  = "clusterconfig.iops_to_volume_size_ratio"
-	ErrIOPSToThroughputRatio                  = "clusterconfig.iops_to_throughput_ratio"
-	ErrCantOverrideDefaultTag                 = "clusterconfig.cant_override_default_tag"
-	ErrSSLCertificateARNNotFound              = "clusterconfig.ssl_certificate_arn_not_found"
-	ErrIAMPolicyARNNotFound                   = "clusterconfig.iam_policy_arn_not_found"
+	ErrInvalidProvider                         = "clusterconfig.invalid_provider"
+	ErrInvalidLegacyProvider                   = "clusterconfig.invalid_legacy_provider"
+	ErrDisallowedField                         = "clusterconfig.disallowed_field"
+	ErrInvalidRegion                           = "clusterconfig.invalid_region"
+	ErrNodeGroupMaxInstancesIsZero             = "clusterconfig.node_group_max_instances_is_zero"
+	ErrMaxNumOfNodeGroupsReached               = "clusterconfig.max_num_of_nodegroups_reached"
+	ErrDuplicateNodeGroupName                  = "clusterconfig.duplicate_nodegroup_name"
+	ErrNodeGroupNotFound                       = "clusterconfig.nodegroup_not_found"
+	ErrScheduleMaxInstancesGreaterThanNgMax    = "clusterconfig.schedule_max_instances_greater_than_nodegroup_max"
+	ErrMaxNodesToAddOnClusterUp                = "clusterconfig.max_nodes_to_add_on_cluster_up"
+	ErrMaxNodesToAddOnClusterConfigure         = "clusterconfig.max_nodes_to_add_on_cluster_configure"
+	ErrInstanceTypeTooSmall                    = "clusterconfig.instance_type_too_small"
+	ErrMinInstancesGreaterThanMax              = "clusterconfig.min_instances_greater_than_max"
+	ErrInstanceTypeNotSupportedInRegion        = "clusterconfig.instance_type_not_supported_in_region"
+	ErrIncompatibleSpotInstanceTypeMemory      = "clusterconfig.incompatible_spot_instance_type_memory"
+	ErrIncompatibleSpotInstanceTypeCPU         = "clusterconfig.incompatible_spot_instance_type_cpu"
+	ErrIncompatibleSpotInstanceTypeGPU         = "clusterconfig.incompatible_spot_instance_type_gpu"
+	ErrIncompatibleSpotInstanceTypeInf         = "clusterconfig.incompatible_spot_instance_type_inf"
+	ErrSpotPriceGreaterThanTargetOnDemand      = "clusterconfig.spot_price_greater_than_target_on_demand"
+	ErrSpotPriceGreaterThanMaxPrice            = "clusterconfig.spot_price_greater_than_max_price"
+	ErrInstanceTypeNotSupportedByCortex        = "clusterconfig.instance_type_not_supported_by_cortex"
+	ErrAMDGPUInstancesNotSupported             = "clusterconfig.amd_gpu_instances_not_supported"
+	ErrGPUInstancesNotSupported                = "clusterconfig.gpu_instance_not_supported"
+	ErrInferentiaInstancesNotSupported         = "clusterconfig.inferentia_instances_not_supported"
+	ErrMacInstancesNotSupported                = "clusterconfig.mac_instances_not_supported"
+	ErrFPGAInstancesNotSupported               = "clusterconfig.fpga_instances_not_supported"
+	ErrAlevoInstancesNotSupported              = "clusterconfig.alevo_instances_not_supported"
+	ErrGaudiInstancesNotSupported              = "clusterconfig.gaudi_instances_not_supported"
+	ErrTrainiumInstancesNotSupported           = "clusterconfig.trainium_instances_not_supported"
+	ErrAtLeastOneInstanceDistribution          = "clusterconfig.at_least_one_instance_distribution"
+	ErrNoCompatibleSpotInstanceFound           = "clusterconfig.no_compatible_spot_instance_found"
+	ErrConfiguredWhenSpotIsNotEnabled          = "clusterconfig.configured_when_spot_is_not_enabled"
+	ErrOnDemandBaseCapacityGreaterThanMax      = "clusterconfig.on_demand_base_capacity_greater_than_max"
+	ErrInvalidAvailabilityZone                 = "clusterconfig.invalid_availability_zone"
+	ErrAvailabilityZoneSpecifiedTwice          = "clusterconfig.availability_zone_specified_twice"
+	ErrUnsupportedAvailabilityZone             = "clusterconfig.unsupported_availability_zone"
+	ErrNotEnoughValidDefaultAvailibilityZones  = "clusterconfig.not_enough_valid_default_availability_zones"
+	ErrNoNATGatewayWithSubnets                 = "clusterconfig.no_nat_gateway_with_subnets"
+	ErrSubnetMaskOutOfRange                    = "clusterconfig.subnet_mask_out_of_range"
+	ErrConfigCannotBeChangedOnConfigure        = "clusterconfig.config_cannot_be_changed_on_configure"
+	ErrNodeGroupCanOnlyBeScaled                = "clusterconfig.node_group_can_only_be_scaled"
+	ErrSpecifyOneOrNone                        = "clusterconfig.specify_one_or_none"
+	ErrSpecifyTwoOrNone                        = "clusterconfig.specify_two_or_none"
+	ErrDependentFieldMustBeSpecified           = "clusterconfig.dependent_field_must_be_specified"
+	ErrFieldConfigurationDependentOnCondition  = "clusterconfig.field_configuration_dependent_on_condition"
+	ErrDidNotMatchStrictS3Regex                = "clusterconfig.did_not_match_strict_s3_regex"
+	ErrNATRequiredWithPrivateSubnetVisibility  = "clusterconfig.nat_required_with_private_subnet_visibility"
+	ErrS3RegionDiffersFromCluster              = "clusterconfig.s3_region_differs_from_cluster"
+	ErrIOPSNotSupported                        = "clusterconfig.iops_not_supported"
+	ErrThroughputNotSupported                  = "clusterconfig.throughput_not_supported"
+	ErrIOPSTooSmall                            = "clusterconfig.iops_too_small"
+	ErrIOPSTooLarge                            = "clusterconfig.iops_too_large"
+	ErrIOPSToVolumeSizeRatio                   = "clusterconfig.iops_to_volume_size_ratio"
+	ErrIOPSToThroughputRatio                   = "clusterconfig.iops_to_throughput_ratio"
+	ErrCantOverrideDefaultTag                  = "clusterconfig.cant_override_default_tag"
+	ErrSSLCertificateARNNotFound               = "clusterconfig.ssl_certificate_arn_not_found"
+	ErrPublicHostedZoneNotFound                = "clusterconfig.public_hosted_zone_not_found"
+	ErrIAMPolicyARNNotFound                    = "clusterconfig.iam_policy_arn_not_found"
+	ErrSpecifyAtLeastOneField                  = "clusterconfig.specify_at_least_one_field"
+	ErrDuplicateQuotaName                      = "clusterconfig.duplicate_quota_name"
+	ErrInvalidQuotaSelector                    = "clusterconfig.invalid_quota_selector"
+	ErrDuplicateProjectName                    = "clusterconfig.duplicate_project_name"
+	ErrInstanceTypeArchMismatch                = "clusterconfig.instance_type_arch_mismatch"
+	ErrSubnetsNotFound                         = "clusterconfig.subnets_not_found"
+	ErrSubnetAvailabilityZoneMismatch          = "clusterconfig.subnet_availability_zone_mismatch"
+	ErrSubnetNotInVPC                          = "clusterconfig.subnet_not_in_vpc"
+	ErrSubnetsInMultipleVPCs                   = "clusterconfig.subnets_in_multiple_vpcs"
+	ErrSubnetNotEnoughAvailableIPs             = "clusterconfig.subnet_not_enough_available_ips"
+	ErrSubnetRouteDoesNotMatchVisibility       = "clusterconfig.subnet_route_does_not_match_visibility"
+	ErrCapacityReservationNotFound             = "clusterconfig.capacity_reservation_not_found"
+	ErrCapacityReservationNotActive            = "clusterconfig.capacity_reservation_not_active"
+	ErrCapacityReservationInstanceTypeMismatch = "clusterconfig.capacity_reservation_instance_type_mismatch"
+	ErrCapacityReservationTooSmall             = "clusterconfig.capacity_reservation_too_small"
+	ErrCapacityReservationZoneNotInCluster     = "clusterconfig.capacity_reservation_zone_not_in_cluster"
+	ErrAMIUnavailable                          = "clusterconfig.ami_unavailable"
+	ErrInvalidRegistryMirror                   = "clusterconfig.invalid_registry_mirror"
+	ErrOfflineRequiresRegistryMirror           = "clusterconfig.offline_requires_registry_mirror"
+	ErrInvalidGitOpsRepository                 = "clusterconfig.invalid_gitops_repository"
+	ErrInvalidGitOpsPath                       = "clusterconfig.invalid_gitops_path"
+	ErrInvalidOIDCIssuerURL                    = "clusterconfig.invalid_oidc_issuer_url"
+	ErrOIDCScopesMissingOpenID                 = "clusterconfig.oidc_scopes_missing_openid"
 )
 
 func ErrorInvalidProvider(providerStr string) error {
@@ -134,6 +160,20 @@ func ErrorDuplicateNodeGroupName(duplicateNgName string) error {
 	})
 }
 
+func ErrorNodeGroupNotFound(ngName string, availableNgNames []string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrNodeGroupNotFound,
+		Message: fmt.Sprintf("your cluster does not have a nodegroup named %s (available nodegroups: %s)", ngName, s.StrsAnd(availableNgNames)),
+	})
+}
+
+func ErrorScheduleMaxInstancesGreaterThanNodeGroupMax(scheduleMax int64, ngName string, ngMax int64) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrScheduleMaxInstancesGreaterThanNgMax,
+		Message: fmt.Sprintf("%s cannot be greater than the %s of the %s nodegroup (%d > %d)", MaxInstancesKey, MaxInstancesKey, ngName, scheduleMax, ngMax),
+	})
+}
+
 func ErrorMaxNodesToAddOnClusterUp(requestedNodes, maxNodes int64) error {
 	return errors.WithStack(&errors.Error{
 		Kind:    ErrMaxNodesToAddOnClusterUp,
@@ -352,7 +392,7 @@ func ErrorSubnetMaskOutOfRange(requestedMaskSize, minMaskSize, maxMaskSize int)
 func ErrorConfigCannotBeChangedOnConfigure() error {
 	return errors.WithStack(&errors.Error{
 		Kind:    ErrConfigCannotBeChangedOnConfigure,
-		Message: fmt.Sprintf("in a running cluster, only %s can be modified", s.StrsAnd([]string{NodeGroupsKey, SSLCertificateARNKey, OperatorLoadBalancerCIDRWhiteListKey, APILoadBalancerCIDRWhiteListKey})),
+		Message: fmt.Sprintf("in a running cluster, only %s can be modified", s.StrsAnd([]string{NodeGroupsKey, SSLCertificateARNKey, OperatorLoadBalancerCIDRWhiteListKey, APILoadBalancerCIDRWhiteListKey, QuotasKey, ProjectsKey, SchedulesKey, GitOpsKey, OIDCKey, PodSecurityKey, ImageScanKey, ImageSignaturesKey})),
 	})
 }
 
@@ -377,6 +417,14 @@ func ErrorSpecifyOneOrNone(fieldName1 string, fieldName2 string, fieldNames ...s
 	})
 }
 
+func ErrorSpecifyAtLeastOneField(fieldName1 string, fieldName2 string, fieldNames ...string) error {
+	fieldNames = append([]string{fieldName1, fieldName2}, fieldNames...)
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrSpecifyAtLeastOneField,
+		Message: fmt.Sprintf("specify at least one of the following fields: %s", s.StrsOr(fieldNames)),
+	})
+}
+
 func ErrorSpecifyTwoOrNone(fieldName1 string, fieldName2 string, fieldNames ...string) error {
 	fieldNames = append([]string{fieldName1, fieldName2}, fieldNames...)
 	return errors.WithStack(&errors.Error{
@@ -476,9 +524,180 @@ func ErrorSSLCertificateARNNotFound(sslCertificateARN string, region string) err
 	})
 }
 
+func ErrorPublicHostedZoneNotFound(hostedZoneID string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrPublicHostedZoneNotFound,
+		Message: fmt.Sprintf("unable to find a public route 53 hosted zone with id %s in your aws account", hostedZoneID),
+	})
+}
+
 func ErrorIAMPolicyARNNotFound(policyARN string) error {
 	return errors.WithStack(&errors.Error{
 		Kind:    ErrIAMPolicyARNNotFound,
 		Message: fmt.Sprintf("unable to find iam policy %s", policyARN),
 	})
 }
+
+func ErrorDuplicateQuotaName(quotaName string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrDuplicateQuotaName,
+		Message: fmt.Sprintf("cannot have multiple quotas with the same name (%s)", quotaName),
+	})
+}
+
+func ErrorInvalidQuotaSelector(selector string, err error) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrInvalidQuotaSelector,
+		Message: fmt.Sprintf("invalid label selector %s: %s", s.UserStr(selector), errors.Message(err)),
+	})
+}
+
+func ErrorDuplicateProjectName(projectName string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrDuplicateProjectName,
+		Message: fmt.Sprintf("cannot have multiple projects with the same name (%s)", projectName),
+	})
+}
+
+func ErrorInstanceTypeArchMismatch(instanceType string, instanceArch Arch, nodeGroupArch Arch) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrInstanceTypeArchMismatch,
+		Message: fmt.Sprintf("%s instances use the %s architecture, but the nodegroup's architecture is %s; all instance types in a nodegroup must share the same architecture", instanceType, instanceArch, nodeGroupArch),
+	})
+}
+
+func ErrorSubnetsNotFound(subnetIDs []string, region string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrSubnetsNotFound,
+		Message: fmt.Sprintf("%s %s %s not exist in region %s", s.PluralS("subnet", len(subnetIDs)), s.StrsAnd(subnetIDs), s.PluralCustom("does", "do", len(subnetIDs)), region),
+	})
+}
+
+func ErrorSubnetAvailabilityZoneMismatch(subnetID string, configuredZone string, actualZone string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrSubnetAvailabilityZoneMismatch,
+		Message: fmt.Sprintf("subnet %s is in availability zone %s, but %s is configured as its availability zone", subnetID, actualZone, configuredZone),
+	})
+}
+
+func ErrorSubnetNotInVPC(subnetID string, subnetVPCID string, vpcID string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrSubnetNotInVPC,
+		Message: fmt.Sprintf("subnet %s belongs to vpc %s, but %s is set to %s", subnetID, subnetVPCID, VPCIDKey, vpcID),
+	})
+}
+
+func ErrorSubnetsInMultipleVPCs(vpcIDs []string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrSubnetsInMultipleVPCs,
+		Message: fmt.Sprintf("all subnets must belong to the same vpc, but they belong to %s", s.StrsAnd(vpcIDs)),
+	})
+}
+
+func ErrorSubnetNotEnoughAvailableIPs(subnetID string, availableIPs int64, minAvailableIPs int64) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrSubnetNotEnoughAvailableIPs,
+		Message: fmt.Sprintf("subnet %s only has %d available ip addresses, but at least %d are required (each node and pod is assigned an ip address from its subnet)", subnetID, availableIPs, minAvailableIPs),
+	})
+}
+
+func ErrorSubnetRouteDoesNotMatchVisibility(subnetID string, subnetVisibility SubnetVisibility, defaultRouteTarget string) error {
+	var msg string
+	switch {
+	case subnetVisibility == PublicSubnetVisibility:
+		msg = fmt.Sprintf("subnet %s does not route 0.0.0.0/0 traffic through an internet gateway, which is required when %s is %s", subnetID, SubnetVisibilityKey, s.UserStr(PublicSubnetVisibility))
+	case defaultRouteTarget == "":
+		msg = fmt.Sprintf("subnet %s does not have a route for 0.0.0.0/0 traffic (e.g. through a nat gateway), which is required for nodes in private subnets to reach the eks control plane and container registries", subnetID)
+	default:
+		msg = fmt.Sprintf("subnet %s routes 0.0.0.0/0 traffic through internet gateway %s, but %s is %s; either set %s to %s or use private subnets", subnetID, defaultRouteTarget, SubnetVisibilityKey, s.UserStr(PrivateSubnetVisibility), SubnetVisibilityKey, s.UserStr(PublicSubnetVisibility))
+	}
+
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrSubnetRouteDoesNotMatchVisibility,
+		Message: msg,
+	})
+}
+
+func ErrorCapacityReservationNotFound(capacityReservationID string, region string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrCapacityReservationNotFound,
+		Message: fmt.Sprintf("capacity reservation %s does not exist in region %s", capacityReservationID, region),
+	})
+}
+
+func ErrorCapacityReservationNotActive(capacityReservationID string, state string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrCapacityReservationNotActive,
+		Message: fmt.Sprintf("capacity reservation %s is %s (it must be active); please specify a different capacity reservation, or remove %s from the nodegroup", capacityReservationID, state, CapacityReservationIDKey),
+	})
+}
+
+func ErrorCapacityReservationInstanceTypeMismatch(capacityReservationID string, reservationInstanceType string, instanceType string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrCapacityReservationInstanceTypeMismatch,
+		Message: fmt.Sprintf("capacity reservation %s is for %s instances, but the nodegroup's %s is %s", capacityReservationID, reservationInstanceType, InstanceTypeKey, instanceType),
+	})
+}
+
+func ErrorCapacityReservationTooSmall(capacityReservationID string, totalInstances int64, maxInstances int64) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrCapacityReservationTooSmall,
+		Message: fmt.Sprintf("capacity reservation %s reserves %d %s, but the nodegroup's %s is %d; instances beyond the reservation would fail to launch, so please set %s to at most %d", capacityReservationID, totalInstances, s.PluralS("instance", totalInstances), MaxInstancesKey, maxInstances, MaxInstancesKey, totalInstances),
+	})
+}
+
+func ErrorCapacityReservationZoneNotInCluster(capacityReservationID string, zone string, clusterZones []string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrCapacityReservationZoneNotInCluster,
+		Message: fmt.Sprintf("capacity reservation %s is in availability zone %s, which is not one of the cluster's availability zones (%s)", capacityReservationID, zone, s.StrsAnd(clusterZones)),
+	})
+}
+
+func ErrorAMIUnavailable(instanceType string, amiType string, k8sVersion string, region string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrAMIUnavailable,
+		Message: fmt.Sprintf("the EKS-optimized AMI (%s) for kubernetes %s which is required by instance type %s is not available in %s", amiType, k8sVersion, instanceType, region),
+	})
+}
+
+func ErrorInvalidRegistryMirror(registryMirror string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrInvalidRegistryMirror,
+		Message: fmt.Sprintf("\"%s\" is not a valid registry mirror; it must be a registry host followed by an optional repository path, without a scheme or tag (e.g. 123456789012.dkr.ecr.us-west-2.amazonaws.com/cortexlabs)", registryMirror),
+	})
+}
+
+func ErrorOfflineRequiresRegistryMirror() error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrOfflineRequiresRegistryMirror,
+		Message: fmt.Sprintf("%s must be specified in your cluster configuration when creating an offline cluster, since the default cortex images cannot be downloaded from %s", RegistryMirrorKey, consts.ReleaseRegistry),
+	})
+}
+
+func ErrorInvalidGitOpsRepository(repository string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrInvalidGitOpsRepository,
+		Message: fmt.Sprintf("\"%s\" is not a supported repository url; it must be an https url (e.g. https://github.com/my-org/my-apis.git)", repository),
+	})
+}
+
+func ErrorInvalidGitOpsPath(path string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrInvalidGitOpsPath,
+		Message: fmt.Sprintf("\"%s\" is not a valid path; it must be a directory relative to the root of the repository", path),
+	})
+}
+
+func ErrorInvalidOIDCIssuerURL(issuerURL string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrInvalidOIDCIssuerURL,
+		Message: fmt.Sprintf("\"%s\" is not a valid issuer url; it must be an https url (e.g. https://my-org.okta.com)", issuerURL),
+	})
+}
+
+func ErrorOIDCScopesMissingOpenID() error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrOIDCScopesMissingOpenID,
+		Message: "the openid scope is required",
+	})
+}
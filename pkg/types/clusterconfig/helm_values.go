@@ -0,0 +1,142 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterconfig
+
+import (
+	"strings"
+
+	"github.com/cortexlabs/cortex/pkg/lib/maps"
+)
+
+// HelmValues are the values of the operator, gateway, and ingress charts, derived from the cluster configuration
+// in the same way that the manager's manifest templates (and cluster_config_env.py) derive them
+type HelmValues struct {
+	Operator OperatorHelmValues `json:"operator" yaml:"operator"`
+	Gateway  GatewayHelmValues  `json:"gateway" yaml:"gateway"`
+	Ingress  IngressHelmValues  `json:"ingress" yaml:"ingress"`
+}
+
+type OperatorHelmValues struct {
+	Image HelmImage `json:"image" yaml:"image"`
+}
+
+type GatewayHelmValues struct {
+	Proxy        HelmImage        `json:"proxy" yaml:"proxy"`
+	Pilot        HelmImage        `json:"pilot" yaml:"pilot"`
+	Operator     HelmLoadBalancer `json:"operator" yaml:"operator"`
+	APIs         HelmLoadBalancer `json:"apis" yaml:"apis"`
+	APIsInternal HelmLoadBalancer `json:"apisInternal" yaml:"apisInternal"`
+}
+
+type IngressHelmValues struct {
+	// when an ssl certificate is configured, https is terminated at the api load balancer and the gateway serves plain http on port 443
+	TerminateTLSAtLoadBalancer bool `json:"terminateTLSAtLoadBalancer" yaml:"terminateTLSAtLoadBalancer"`
+	InternalGateway            bool `json:"internalGateway" yaml:"internalGateway"`
+}
+
+type HelmImage struct {
+	Hub   string `json:"hub" yaml:"hub"`
+	Image string `json:"image" yaml:"image"`
+	Tag   string `json:"tag" yaml:"tag"`
+}
+
+type HelmLoadBalancer struct {
+	Enabled           bool              `json:"enabled" yaml:"enabled"`
+	Type              string            `json:"type" yaml:"type"`
+	Scheme            string            `json:"scheme" yaml:"scheme"`
+	SSLCertificateARN string            `json:"sslCertificateARN,omitempty" yaml:"sslCertificateARN,omitempty"`
+	SourceRanges      []string          `json:"sourceRanges" yaml:"sourceRanges"`
+	Tags              map[string]string `json:"tags" yaml:"tags"`
+}
+
+var _defaultLoadBalancerSourceRanges = []string{"0.0.0.0/0"}
+
+func NewHelmValues(cc *Config) HelmValues {
+	sslCertificateARN := ""
+	if cc.SSLCertificateARN != nil {
+		sslCertificateARN = *cc.SSLCertificateARN
+	}
+
+	return HelmValues{
+		Operator: OperatorHelmValues{
+			Image: NewHelmImage(cc.ImageOperator),
+		},
+		Gateway: GatewayHelmValues{
+			Proxy: NewHelmImage(cc.ImageIstioProxy),
+			Pilot: NewHelmImage(cc.ImageIstioPilot),
+			Operator: HelmLoadBalancer{
+				Enabled:      true,
+				Type:         NLBLoadBalancerType.String(),
+				Scheme:       cc.OperatorLoadBalancerScheme.String(),
+				SourceRanges: loadBalancerSourceRanges(cc.OperatorLoadBalancerCIDRWhiteList),
+				Tags:         loadBalancerTags(cc.Tags, "operator"),
+			},
+			APIs: HelmLoadBalancer{
+				Enabled:           true,
+				Type:              cc.APILoadBalancerType.String(),
+				Scheme:            cc.APILoadBalancerScheme.String(),
+				SSLCertificateARN: sslCertificateARN,
+				SourceRanges:      loadBalancerSourceRanges(cc.APILoadBalancerCIDRWhiteList),
+				Tags:              loadBalancerTags(cc.Tags, "api"),
+			},
+			// the internal api load balancer is only needed when the api load balancer is internet-facing
+			APIsInternal: HelmLoadBalancer{
+				Enabled:           cc.APILoadBalancerScheme != InternalLoadBalancerScheme,
+				Type:              cc.APILoadBalancerType.String(),
+				Scheme:            InternalLoadBalancerScheme.String(),
+				SSLCertificateARN: sslCertificateARN,
+				SourceRanges:      loadBalancerSourceRanges(cc.APILoadBalancerCIDRWhiteList),
+				Tags:              loadBalancerTags(cc.Tags, "api-internal"),
+			},
+		},
+		Ingress: IngressHelmValues{
+			TerminateTLSAtLoadBalancer: sslCertificateARN != "",
+			InternalGateway:            cc.APILoadBalancerScheme != InternalLoadBalancerScheme,
+		},
+	}
+}
+
+// splits an image into its hub, name, and tag (the tag defaults to "latest")
+func NewHelmImage(image string) HelmImage {
+	helmImage := HelmImage{Tag: "latest"}
+
+	suffix := image
+	if lastSlash := strings.LastIndex(image, "/"); lastSlash != -1 {
+		helmImage.Hub = image[:lastSlash]
+		suffix = image[lastSlash+1:]
+	}
+
+	if colon := strings.Index(suffix, ":"); colon != -1 {
+		helmImage.Image = suffix[:colon]
+		helmImage.Tag = suffix[colon+1:]
+	} else {
+		helmImage.Image = suffix
+	}
+
+	return helmImage
+}
+
+func loadBalancerSourceRanges(cidrWhiteList []string) []string {
+	if len(cidrWhiteList) == 0 {
+		return _defaultLoadBalancerSourceRanges
+	}
+	return cidrWhiteList
+}
+
+func loadBalancerTags(tags map[string]string, loadBalancer string) map[string]string {
+	return maps.MergeStrMapsString(tags, map[string]string{"cortex.dev/load-balancer": loadBalancer})
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterconfig
+
+type ImageScanAction int
+
+const (
+	UnknownImageScanAction ImageScanAction = iota
+	WarnImageScanAction
+	BlockImageScanAction
+)
+
+var _imageScanActions = []string{
+	"unknown",
+	"warn",
+	"block",
+}
+
+func ImageScanActionFromString(s string) ImageScanAction {
+	for i := 0; i < len(_imageScanActions); i++ {
+		if s == _imageScanActions[i] {
+			return ImageScanAction(i)
+		}
+	}
+	return UnknownImageScanAction
+}
+
+func ImageScanActionStrings() []string {
+	return _imageScanActions[1:]
+}
+
+func (t ImageScanAction) String() string {
+	return _imageScanActions[t]
+}
+
+// MarshalText satisfies TextMarshaler
+func (t ImageScanAction) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// UnmarshalText satisfies TextUnmarshaler
+func (t *ImageScanAction) UnmarshalText(text []byte) error {
+	enum := string(text)
+	for i := 0; i < len(_imageScanActions); i++ {
+		if enum == _imageScanActions[i] {
+			*t = ImageScanAction(i)
+			return nil
+		}
+	}
+
+	*t = UnknownImageScanAction
+	return nil
+}
+
+// UnmarshalBinary satisfies BinaryUnmarshaler
+// Needed for msgpack
+func (t *ImageScanAction) UnmarshalBinary(data []byte) error {
+	return t.UnmarshalText(data)
+}
+
+// MarshalBinary satisfies BinaryMarshaler
+func (t ImageScanAction) MarshalBinary() ([]byte, error) {
+	return []byte(t.String()), nil
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterconfig
+
+type SecretsBackend int
+
+const (
+	UnknownSecretsBackend SecretsBackend = iota
+	SecretsManagerSecretsBackend
+	ParameterStoreSecretsBackend
+)
+
+var _secretsBackends = []string{
+	"unknown",
+	"secrets_manager",
+	"parameter_store",
+}
+
+func SecretsBackendFromString(s string) SecretsBackend {
+	for i := 0; i < len(_secretsBackends); i++ {
+		if s == _secretsBackends[i] {
+			return SecretsBackend(i)
+		}
+	}
+	return UnknownSecretsBackend
+}
+
+func SecretsBackendStrings() []string {
+	return _secretsBackends[1:]
+}
+
+func (t SecretsBackend) String() string {
+	return _secretsBackends[t]
+}
+
+// MarshalText satisfies TextMarshaler
+func (t SecretsBackend) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// UnmarshalText satisfies TextUnmarshaler
+func (t *SecretsBackend) UnmarshalText(text []byte) error {
+	enum := string(text)
+	for i := 0; i < len(_secretsBackends); i++ {
+		if enum == _secretsBackends[i] {
+			*t = SecretsBackend(i)
+			return nil
+		}
+	}
+
+	*t = UnknownSecretsBackend
+	return nil
+}
+
+// UnmarshalBinary satisfies BinaryUnmarshaler
+// Needed for msgpack
+func (t *SecretsBackend) UnmarshalBinary(data []byte) error {
+	return t.UnmarshalText(data)
+}
+
+// MarshalBinary satisfies BinaryMarshaler
+func (t SecretsBackend) MarshalBinary() ([]byte, error) {
+	return []byte(t.String()), nil
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterconfig
+
+import (
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/cortexlabs/cortex/pkg/lib/aws"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/sets/strset"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+)
+
+// each node (and each pod, since the vpc cni assigns pod ips from the node's subnet) consumes an ip address
+const _minSubnetAvailableIPs = 32
+
+// validateExistingSubnets verifies that user-provided subnets exist, belong to a single vpc (which must match vpc_id if specified),
+// are in the configured availability zones, have enough free ip addresses, and are routed according to subnet_visibility
+func (cc *Config) validateExistingSubnets(awsClient *aws.Client) error {
+	subnetIDs := make([]string, len(cc.Subnets))
+	for i, subnetConfig := range cc.Subnets {
+		subnetIDs[i] = subnetConfig.SubnetID
+	}
+
+	subnets, err := awsClient.DescribeSubnetsByIDs(subnetIDs...)
+	if err != nil {
+		return err
+	}
+
+	subnetsByID := map[string]ec2.Subnet{}
+	vpcIDs := strset.New()
+	for _, subnet := range subnets {
+		subnetsByID[*subnet.SubnetId] = subnet
+		vpcIDs.Add(*subnet.VpcId)
+	}
+
+	var missingSubnetIDs []string
+	for _, subnetID := range subnetIDs {
+		if _, ok := subnetsByID[subnetID]; !ok {
+			missingSubnetIDs = append(missingSubnetIDs, subnetID)
+		}
+	}
+	if len(missingSubnetIDs) > 0 {
+		return ErrorSubnetsNotFound(missingSubnetIDs, cc.Region)
+	}
+
+	for i, subnetConfig := range cc.Subnets {
+		subnet := subnetsByID[subnetConfig.SubnetID]
+
+		if cc.VPCID != nil && *subnet.VpcId != *cc.VPCID {
+			return errors.Wrap(ErrorSubnetNotInVPC(subnetConfig.SubnetID, *subnet.VpcId, *cc.VPCID), s.Index(i), SubnetIDKey)
+		}
+
+		if *subnet.AvailabilityZone != subnetConfig.AvailabilityZone {
+			return errors.Wrap(ErrorSubnetAvailabilityZoneMismatch(subnetConfig.SubnetID, subnetConfig.AvailabilityZone, *subnet.AvailabilityZone), s.Index(i), AvailabilityZoneKey)
+		}
+
+		if availableIPs := *subnet.AvailableIpAddressCount; availableIPs < _minSubnetAvailableIPs {
+			return errors.Wrap(ErrorSubnetNotEnoughAvailableIPs(subnetConfig.SubnetID, availableIPs, _minSubnetAvailableIPs), s.Index(i), SubnetIDKey)
+		}
+	}
+
+	if len(vpcIDs) > 1 {
+		return ErrorSubnetsInMultipleVPCs(vpcIDs.SliceSorted())
+	}
+
+	return cc.validateSubnetRoutes(awsClient, vpcIDs.GetOne(), subnetIDs)
+}
+
+func (cc *Config) validateSubnetRoutes(awsClient *aws.Client, vpcID string, subnetIDs []string) error {
+	routeTables, err := awsClient.SubnetRouteTables(vpcID, subnetIDs...)
+	if err != nil {
+		return nil // Skip validation
+	}
+
+	for i, subnetID := range subnetIDs {
+		routeTable, ok := routeTables[subnetID]
+		if !ok {
+			continue
+		}
+
+		defaultRouteTarget := aws.DefaultRouteTarget(routeTable)
+		isPublic := aws.IsInternetGatewayID(defaultRouteTarget)
+
+		if cc.SubnetVisibility == PublicSubnetVisibility && !isPublic {
+			return errors.Wrap(ErrorSubnetRouteDoesNotMatchVisibility(subnetID, cc.SubnetVisibility, defaultRouteTarget), s.Index(i), SubnetIDKey)
+		}
+		if cc.SubnetVisibility == PrivateSubnetVisibility && (isPublic || defaultRouteTarget == "") {
+			return errors.Wrap(ErrorSubnetRouteDoesNotMatchVisibility(subnetID, cc.SubnetVisibility, defaultRouteTarget), s.Index(i), SubnetIDKey)
+		}
+	}
+
+	return nil
+}
@@ -46,10 +46,14 @@ type API struct {
 	InitialDeploymentTime int64  `json:"initial_deployment_time" yaml:"initial_deployment_time"`
 	LastUpdated           int64  `json:"last_updated" yaml:"last_updated"`
 	MetadataRoot          string `json:"metadata_root" yaml:"metadata_root"`
+
+	// ModelVersion is the version of the model which was deployed (only set if model_watch is configured)
+	ModelVersion string `json:"model_version,omitempty" yaml:"model_version,omitempty"`
 }
 
 type Metadata struct {
 	*userconfig.Resource
+	Project      string `json:"project,omitempty" yaml:"project,omitempty"`
 	APIID        string `json:"id" yaml:"id"`
 	DeploymentID string `json:"deployment_id,omitempty" yaml:"deployment_id,omitempty"`
 	LastUpdated  int64  `json:"last_updated" yaml:"last_updated"`
@@ -65,6 +69,7 @@ func MetadataFromDeployment(deployment *kapps.Deployment) (*Metadata, error) {
 			Name: deployment.Labels["apiName"],
 			Kind: userconfig.KindFromString(deployment.Labels["apiKind"]),
 		},
+		Project:      userconfig.ProjectFromLabels(deployment.Labels),
 		APIID:        deployment.Labels["apiID"],
 		DeploymentID: deployment.Labels["deploymentID"],
 		LastUpdated:  lastUpdated.Unix(),
@@ -81,6 +86,7 @@ func MetadataFromVirtualService(vs *istioclientnetworking.VirtualService) (*Meta
 			Name: vs.Labels["apiName"],
 			Kind: userconfig.KindFromString(vs.Labels["apiKind"]),
 		},
+		Project:      userconfig.ProjectFromLabels(vs.Labels),
 		APIID:        vs.Labels["apiID"],
 		DeploymentID: vs.Labels["deploymentID"],
 		LastUpdated:  lastUpdated.Unix(),
@@ -96,10 +102,18 @@ func MetadataFromVirtualService(vs *istioclientnetworking.VirtualService) (*Meta
   - Containers
   - Compute
   - Pod
+  - Sidecar configuration (async, request logging, prediction metrics, rate limit, websocket, authentication)
+  - Model configuration (model watch, model cache)
+  - Distributed configuration
   - Deployment Strategy
+  - Canary
   - Autoscaling
+  - Warm pool
+  - Image pre-pulling
   - Networking
   - APIs
+  - Max concurrent jobs
+  - Project
 
 initialDeploymentTime is Time.UnixNano()
 */
@@ -108,6 +122,19 @@ func GetAPISpec(apiConfig *userconfig.API, initialDeploymentTime int64, deployme
 
 	buf.WriteString(s.Obj(apiConfig.Resource))
 	buf.WriteString(s.Obj(apiConfig.Pod))
+	// these are passed to the proxy or dequeuer sidecars, so they are part of the pod spec
+	buf.WriteString(s.Obj(apiConfig.Async))
+	buf.WriteString(s.Obj(apiConfig.RequestLogging))
+	buf.WriteString(s.Obj(apiConfig.PredictionMetrics))
+	buf.WriteString(s.Obj(apiConfig.RateLimit))
+	buf.WriteString(s.Obj(apiConfig.WebSocket))
+	buf.WriteString(s.Obj(apiConfig.Authentication))
+	buf.WriteString(s.Obj(apiConfig.AWSIAMPrincipals))
+	// these determine the model's env vars, init container, and volume mounts
+	buf.WriteString(s.Obj(apiConfig.ModelWatch))
+	buf.WriteString(s.Obj(apiConfig.ModelCache))
+	// determines the task job's env vars and number of coordinated pods
+	buf.WriteString(s.Obj(apiConfig.Distributed))
 	podID := hash.Bytes(buf.Bytes())
 
 	buf.Reset()
@@ -115,8 +142,14 @@ func GetAPISpec(apiConfig *userconfig.API, initialDeploymentTime int64, deployme
 	buf.WriteString(s.Obj(apiConfig.APIs))
 	buf.WriteString(s.Obj(apiConfig.Networking))
 	buf.WriteString(s.Obj(apiConfig.Autoscaling))
+	buf.WriteString(s.Obj(apiConfig.WarmPool))
+	buf.WriteString(s.Obj(apiConfig.PrepullImages))
 	buf.WriteString(s.Obj(apiConfig.UpdateStrategy))
+	buf.WriteString(s.Obj(apiConfig.Canary))
 	buf.WriteString(s.Obj(apiConfig.NodeGroups))
+	buf.WriteString(s.Obj(apiConfig.Labels))
+	buf.WriteString(s.Obj(apiConfig.Project))
+	buf.WriteString(s.Obj(apiConfig.MaxConcurrentJobs))
 	specID := hash.Bytes(buf.Bytes())[:32]
 
 	apiID := fmt.Sprintf("%s-%s-%s", MonotonicallyDecreasingID(), deploymentID, specID) // should be up to 60 characters long
@@ -134,6 +167,15 @@ func GetAPISpec(apiConfig *userconfig.API, initialDeploymentTime int64, deployme
 	}
 }
 
+// ToK8sAnnotations extends the annotations of the user's api configuration with the deployed model version
+func (api *API) ToK8sAnnotations() map[string]string {
+	annotations := api.API.ToK8sAnnotations()
+	if api.ModelVersion != "" {
+		annotations[userconfig.ModelVersionAnnotationKey] = api.ModelVersion
+	}
+	return annotations
+}
+
 func Key(apiName string, apiID string, clusterUID string) string {
 	return filepath.Join(
 		clusterUID,
@@ -164,6 +206,11 @@ func MetadataRoot(apiName string, clusterUID string) string {
 	)
 }
 
+// Extract the spec ID from an API ID (API IDs are in the form <time id>-<deployment id>-<spec id>)
+func SpecIDFromAPIID(apiID string) string {
+	return apiID[strings.LastIndex(apiID, "-")+1:]
+}
+
 // Extract the timestamp from an API ID
 func TimeFromAPIID(apiID string) (time.Time, error) {
 	timeIDStr := strings.Split(apiID, "-")[0]
@@ -19,12 +19,15 @@ package spec
 import (
 	"fmt"
 	"regexp"
+	"strings"
 
 	"github.com/cortexlabs/cortex/pkg/consts"
+	"github.com/cortexlabs/cortex/pkg/lib/aws"
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
 	"github.com/cortexlabs/cortex/pkg/lib/k8s"
 	"github.com/cortexlabs/cortex/pkg/lib/sets/strset"
 	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	"github.com/cortexlabs/cortex/pkg/types/clusterconfig"
 	"github.com/cortexlabs/cortex/pkg/types/userconfig"
 )
 
@@ -50,15 +53,40 @@ const (
 
 	ErrShmCannotExceedMem = "spec.shm_cannot_exceed_mem"
 
-	ErrFieldMustBeSpecifiedForKind    = "spec.field_must_be_specified_for_kind"
-	ErrFieldIsNotSupportedForKind     = "spec.field_is_not_supported_for_kind"
-	ErrCortexPrefixedEnvVarNotAllowed = "spec.cortex_prefixed_env_var_not_allowed"
-	ErrDisallowedEnvVars              = "spec.disallowed_env_vars"
-	ErrComputeResourceConflict        = "spec.compute_resource_conflict"
-	ErrIncorrectTrafficSplitterWeight = "spec.incorrect_traffic_splitter_weight"
-	ErrTrafficSplitterAPIsNotUnique   = "spec.traffic_splitter_apis_not_unique"
-	ErrOneShadowPerTrafficSplitter    = "spec.one_shadow_per_traffic_splitter"
-	ErrUnexpectedDockerSecretData     = "spec.unexpected_docker_secret_data"
+	ErrFieldMustBeSpecifiedForKind           = "spec.field_must_be_specified_for_kind"
+	ErrFieldIsNotSupportedForKind            = "spec.field_is_not_supported_for_kind"
+	ErrCortexPrefixedEnvVarNotAllowed        = "spec.cortex_prefixed_env_var_not_allowed"
+	ErrDisallowedEnvVars                     = "spec.disallowed_env_vars"
+	ErrComputeResourceConflict               = "spec.compute_resource_conflict"
+	ErrIncorrectTrafficSplitterWeight        = "spec.incorrect_traffic_splitter_weight"
+	ErrTrafficSplitterAPIsNotUnique          = "spec.traffic_splitter_apis_not_unique"
+	ErrOneShadowPerTrafficSplitter           = "spec.one_shadow_per_traffic_splitter"
+	ErrUnexpectedDockerSecretData            = "spec.unexpected_docker_secret_data"
+	ErrRegistrySecretNotFound                = "spec.registry_secret_not_found"
+	ErrUnexpectedRegistrySecretData          = "spec.unexpected_registry_secret_data"
+	ErrNoECRImagesForRole                    = "spec.no_ecr_images_for_role"
+	ErrECRRoleNameMissingPrefix              = "spec.ecr_role_name_missing_prefix"
+	ErrInvalidLabel                          = "spec.invalid_label"
+	ErrReservedLabel                         = "spec.reserved_label"
+	ErrCanaryRequiresMinReplicas             = "spec.canary_requires_min_replicas"
+	ErrDuplicatePredictionMetricName         = "spec.duplicate_prediction_metric_name"
+	ErrBucketsNotIncreasing                  = "spec.buckets_not_increasing"
+	ErrTargetGPUUtilizationWithoutGPU        = "spec.target_gpu_utilization_without_gpu"
+	ErrMaxQueueWaitRequiresScaleToZero       = "spec.max_queue_wait_requires_scale_to_zero"
+	ErrInvalidAWSIAMPrincipal                = "spec.invalid_aws_iam_principal"
+	ErrFieldMustBeSpecifiedForAuthentication = "spec.field_must_be_specified_for_authentication"
+	ErrFieldRequiresAuthentication           = "spec.field_requires_authentication"
+	ErrFieldIsNotSupportedForGRPC            = "spec.field_is_not_supported_for_grpc"
+	ErrGRPCRequiresMinReplicas               = "spec.grpc_requires_min_replicas"
+	ErrMaxConnectionsLessThanMaxConcurrency  = "spec.max_connections_less_than_max_concurrency"
+	ErrNoModelVersionsFound                  = "spec.no_model_versions_found"
+	ErrInvalidModelCacheMountPath            = "spec.invalid_model_cache_mount_path"
+	ErrEnvVarAlsoSetFromSecret               = "spec.env_var_also_set_from_secret"
+	ErrInvalidIAMRoleARN                     = "spec.invalid_iam_role_arn"
+	ErrInvalidCIDR                           = "spec.invalid_cidr"
+	ErrInvalidSeccompProfile                 = "spec.invalid_seccomp_profile"
+	ErrRunAsNonRootWithRootUser              = "spec.run_as_non_root_with_root_user"
+	ErrRegistrySecretAccessDenied            = "spec.registry_secret_access_denied"
 )
 
 func ErrorMalformedConfig() error {
@@ -288,3 +316,178 @@ func ErrorUnexpectedDockerSecretData(reason string, secretData map[string][]byte
 		Message: fmt.Sprintf("docker registry secret named \"%s\" was found, but contains unexpected data (%s); got: %s", _dockerPullSecretName, reason, s.UserStr(secretDataStrMap)),
 	})
 }
+
+func ErrorRegistrySecretNotFound(secretName string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrRegistrySecretNotFound,
+		Message: fmt.Sprintf("registry credentials secret %s does not exist; create it with `kubectl create secret docker-registry %s --docker-server=<registry> --docker-username=<username> --docker-password=<password>`", s.UserStr(secretName), secretName),
+	})
+}
+
+func ErrorUnexpectedRegistrySecretData(secretName string, reason string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrUnexpectedRegistrySecretData,
+		Message: fmt.Sprintf("registry credentials secret %s contains unexpected data (%s)", s.UserStr(secretName), reason),
+	})
+}
+
+func ErrorNoECRImagesForRole() error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrNoECRImagesForRole,
+		Message: fmt.Sprintf("%s can only be used to pull images which are hosted on ECR, but none of the containers' images are hosted on ECR", userconfig.ECRRoleARNKey),
+	})
+}
+
+func ErrorECRRoleNameMissingPrefix(roleARN string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrECRRoleNameMissingPrefix,
+		Message: fmt.Sprintf("the operator is only allowed to assume roles whose name starts with \"%s\", so %s can't be used as the %s (e.g. arn:aws:iam::123456789012:role/%secr-pull)", _ecrRoleNamePrefix, roleARN, userconfig.ECRRoleARNKey, _ecrRoleNamePrefix),
+	})
+}
+
+func ErrorInvalidLabel(key string, value string, reasons []string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrInvalidLabel,
+		Message: fmt.Sprintf("invalid label %s: %s (%s)", s.UserStr(key), s.UserStr(value), strings.Join(reasons, "; ")),
+	})
+}
+
+func ErrorReservedLabel(key string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrReservedLabel,
+		Message: fmt.Sprintf("label %s is reserved for internal use by cortex; please choose a different label key", s.UserStr(key)),
+	})
+}
+
+func ErrorCanaryRequiresMinReplicas() error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrCanaryRequiresMinReplicas,
+		Message: fmt.Sprintf("%s cannot be used when %s.%s is 0, since the canary would not be able to receive traffic while the api is scaled to zero", userconfig.CanaryKey, userconfig.AutoscalingKey, userconfig.MinReplicasKey),
+	})
+}
+
+func ErrorDuplicatePredictionMetricName(name string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrDuplicatePredictionMetricName,
+		Message: fmt.Sprintf("prediction metric name %s must be unique", name),
+	})
+}
+
+func ErrorBucketsNotIncreasing(buckets []float64) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrBucketsNotIncreasing,
+		Message: fmt.Sprintf("%s must be in strictly increasing order (got %s)", userconfig.BucketsKey, s.ObjFlatNoQuotes(buckets)),
+	})
+}
+
+func ErrorTargetGPUUtilizationWithoutGPU() error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrTargetGPUUtilizationWithoutGPU,
+		Message: fmt.Sprintf("%s can only be specified if the api's containers request at least one gpu (via %s)", userconfig.TargetGPUUtilizationKey, userconfig.GPUKey),
+	})
+}
+
+func ErrorMaxQueueWaitRequiresScaleToZero(minReplicas int32) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrMaxQueueWaitRequiresScaleToZero,
+		Message: fmt.Sprintf("%s can only be specified if %s is 0 (got %d), since requests are only held while the api scales up from zero", userconfig.MaxQueueWaitKey, userconfig.MinReplicasKey, minReplicas),
+	})
+}
+
+func ErrorInvalidAWSIAMPrincipal(principal string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrInvalidAWSIAMPrincipal,
+		Message: fmt.Sprintf("%s is not a valid aws iam principal; it must be an aws account id (e.g. 123456789012), an iam role arn (e.g. arn:aws:iam::123456789012:role/my-role), or an iam user arn (e.g. arn:aws:iam::123456789012:user/my-user)", principal),
+	})
+}
+
+func ErrorFieldMustBeSpecifiedForAuthentication(field string, authentication string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrFieldMustBeSpecifiedForAuthentication,
+		Message: fmt.Sprintf("%s must be specified when %s is %s", field, userconfig.AuthenticationKey, authentication),
+	})
+}
+
+func ErrorFieldRequiresAuthentication(field string, authentication string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrFieldRequiresAuthentication,
+		Message: fmt.Sprintf("%s can only be specified when %s is %s", field, userconfig.AuthenticationKey, authentication),
+	})
+}
+
+func ErrorFieldIsNotSupportedForGRPC(field string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrFieldIsNotSupportedForGRPC,
+		Message: fmt.Sprintf("%s is not supported when %s.%s is %s", field, userconfig.PodKey, userconfig.ProtocolKey, userconfig.ProtocolGRPC),
+	})
+}
+
+func ErrorGRPCRequiresMinReplicas() error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrGRPCRequiresMinReplicas,
+		Message: fmt.Sprintf("%s.%s cannot be %s when %s.%s is 0, since requests to apis which are scaled to zero are handled by the activator, which only supports http/1.1", userconfig.PodKey, userconfig.ProtocolKey, userconfig.ProtocolGRPC, userconfig.AutoscalingKey, userconfig.MinReplicasKey),
+	})
+}
+
+func ErrorMaxConnectionsLessThanMaxConcurrency(maxConnections int64, maxConcurrency int64) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrMaxConnectionsLessThanMaxConcurrency,
+		Message: fmt.Sprintf("%s (%d) must be greater than or equal to %s (%d), since each concurrent request requires a connection", userconfig.MaxConnectionsKey, maxConnections, userconfig.MaxConcurrencyKey, maxConcurrency),
+	})
+}
+
+func ErrorNoModelVersionsFound(modelPath string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrNoModelVersionsFound,
+		Message: fmt.Sprintf("no model versions were found in %s; each version of the model should be uploaded to its own directory (e.g. %s)", modelPath, aws.JoinS3Path(modelPath, "1")),
+	})
+}
+
+func ErrorInvalidModelCacheMountPath(mountPath string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrInvalidModelCacheMountPath,
+		Message: fmt.Sprintf("%s is not a valid mount path; it must be an absolute path, and can't be /, /mnt, or within /cortex, /dev, /proc, or /sys", mountPath),
+	})
+}
+
+func ErrorEnvVarAlsoSetFromSecret(envVarName string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrEnvVarAlsoSetFromSecret,
+		Message: fmt.Sprintf("environment variable %s is specified in both %s and %s; it can only be specified in one of them", envVarName, userconfig.EnvKey, userconfig.EnvFromSecretsKey),
+	})
+}
+
+func ErrorInvalidIAMRoleARN(roleARN string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrInvalidIAMRoleARN,
+		Message: fmt.Sprintf("%s is not a valid iam role arn (e.g. arn:aws:iam::123456789012:role/my-role)", roleARN),
+	})
+}
+
+func ErrorInvalidCIDR(cidr string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrInvalidCIDR,
+		Message: fmt.Sprintf("%s is not a valid cidr block (e.g. 10.0.0.0/16 or 203.0.113.7/32)", cidr),
+	})
+}
+
+func ErrorInvalidSeccompProfile(profile string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrInvalidSeccompProfile,
+		Message: fmt.Sprintf("invalid seccomp profile %s; valid values are %s, %s, and %s<path> (where <path> is relative to the kubelet's seccomp profile directory on the nodes)", s.UserStr(profile), userconfig.SeccompProfileRuntimeDefault, userconfig.SeccompProfileUnconfined, userconfig.SeccompProfileLocalhostPrefix),
+	})
+}
+
+func ErrorRunAsNonRootWithRootUser() error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrRunAsNonRootWithRootUser,
+		Message: fmt.Sprintf("%s can't be 0 (root) when %s is true", userconfig.RunAsUserKey, userconfig.RunAsNonRootKey),
+	})
+}
+
+func ErrorRegistrySecretAccessDenied(secretARN string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrRegistrySecretAccessDenied,
+		Message: fmt.Sprintf("the operator is not allowed to read %s; the secret must be tagged with %s set to the name of your cluster", secretARN, clusterconfig.ClusterNameTag),
+	})
+}
@@ -47,6 +47,10 @@ func (j JobKey) SpecFilePath(clusterUID string) string {
 }
 
 // e.g. /<cluster UID>/jobs/<job_api_kind>/<cortex version>/<api_name>/<job_id>
+func (j JobKey) CheckpointRestoreFilePath(clusterUID string) string {
+	return path.Join(j.Prefix(clusterUID), "checkpoint_restore.json")
+}
+
 func (j JobKey) Prefix(clusterUID string) string {
 	return s.EnsureSuffix(path.Join(JobAPIPrefix(clusterUID, j.Kind, j.APIName), j.ID), "/")
 }
@@ -62,15 +66,42 @@ type SQSDeadLetterQueue struct {
 
 type RuntimeBatchJobConfig struct {
 	Workers            int                    `json:"workers" yaml:"workers"`
+	AutoWorkers        bool                   `json:"auto_workers,omitempty" yaml:"auto_workers,omitempty"` // the number of workers is scaled with the job's remaining batches (Workers is the initial number of workers)
+	MaxWorkers         *int                   `json:"max_workers,omitempty" yaml:"max_workers,omitempty"`   // only applies to auto workers
 	SQSDeadLetterQueue *SQSDeadLetterQueue    `json:"sqs_dead_letter_queue" yaml:"sqs_dead_letter_queue"`
 	Config             map[string]interface{} `json:"config" yaml:"config"`
 	Timeout            *int                   `json:"timeout" yaml:"timeout"`
+	MaxRetries         *int                   `json:"max_retries" yaml:"max_retries"`
+	OutputPath         string                 `json:"output_path,omitempty" yaml:"output_path,omitempty"`
+	DependsOn          []string               `json:"depends_on,omitempty" yaml:"depends_on,omitempty"`
 }
 
 type RuntimeTaskJobConfig struct {
-	Workers int                    `json:"workers" yaml:"workers"`
-	Config  map[string]interface{} `json:"config" yaml:"config"`
-	Timeout *int                   `json:"timeout" yaml:"timeout"`
+	Workers    int                    `json:"workers" yaml:"workers"`
+	Config     map[string]interface{} `json:"config" yaml:"config"`
+	Timeout    *int                   `json:"timeout" yaml:"timeout"`
+	MaxRetries *int                   `json:"max_retries" yaml:"max_retries"`
+	Params     map[string]string      `json:"params,omitempty" yaml:"params,omitempty"`
+	DependsOn  []string               `json:"depends_on,omitempty" yaml:"depends_on,omitempty"`
+	Checkpoint *TaskJobCheckpoint     `json:"checkpoint,omitempty" yaml:"checkpoint,omitempty"`
+}
+
+// TaskJobCheckpoint configures where a task job writes its checkpoints, so that it can be restarted from the latest one
+type TaskJobCheckpoint struct {
+	S3Path   string `json:"s3_path" yaml:"s3_path"`                       // checkpoints are written under <s3_path>/<job_id>/
+	Interval *int   `json:"interval,omitempty" yaml:"interval,omitempty"` // how often (in seconds) the job is expected to write a checkpoint
+}
+
+// e.g. s3://<bucket>/<prefix>/<job_id>
+func (c TaskJobCheckpoint) JobDir(jobID string) string {
+	return s.EnsureSuffix(c.S3Path, "/") + jobID
+}
+
+// CheckpointRestore records the restarts of a task job from its checkpoints
+type CheckpointRestore struct {
+	Restarts        int       `json:"restarts" yaml:"restarts"`
+	RestoredFrom    string    `json:"restored_from,omitempty" yaml:"restored_from,omitempty"` // empty if no checkpoint had been written yet
+	LastRestartTime time.Time `json:"last_restart_time" yaml:"last_restart_time"`
 }
 
 type BatchJob struct {
@@ -80,15 +111,19 @@ type BatchJob struct {
 	SQSUrl          string    `json:"sqs_url" yaml:"sqs_url"`
 	TotalBatchCount int       `json:"total_batch_count,omitempty" yaml:"total_batch_count,omitempty"`
 	StartTime       time.Time `json:"start_time,omitempty" yaml:"start_time,omitempty"`
+	Dependencies    []JobKey  `json:"dependencies,omitempty" yaml:"dependencies,omitempty"` // the resolved keys of the jobs in depends_on
 }
 
 type TaskJob struct {
 	JobKey
 	RuntimeTaskJobConfig
-	APIID     string    `json:"api_id" yaml:"api_id"`
-	SpecID    string    `json:"spec_id" yaml:"spec_id"`
-	PodID     string    `json:"pod_id" yaml:"pod_id"`
-	StartTime time.Time `json:"start_time" yaml:"start_time"`
+	APIID        string    `json:"api_id" yaml:"api_id"`
+	SpecID       string    `json:"spec_id" yaml:"spec_id"`
+	PodID        string    `json:"pod_id" yaml:"pod_id"`
+	StartTime    time.Time `json:"start_time" yaml:"start_time"`
+	Dependencies []JobKey  `json:"dependencies,omitempty" yaml:"dependencies,omitempty"` // the resolved keys of the jobs in depends_on
+
+	CheckpointRestore *CheckpointRestore `json:"checkpoint_restore,omitempty" yaml:"checkpoint_restore,omitempty"`
 }
 
 // e.g. /<cluster UID>/jobs/<job_api_kind>/<cortex version>/<api_name>
@@ -104,6 +139,34 @@ func JobBatchCountKey(clusterUID string, kind userconfig.Kind, apiName string, j
 	return filepath.Join(JobAPIPrefix(clusterUID, kind, apiName), jobID, "max_batch_count")
 }
 
+// e.g. /<cluster UID>/jobs/<job_api_kind>/<cortex version>/<api_name>/<job_id>/failed_batches/
+func JobFailedBatchesPrefix(clusterUID string, kind userconfig.Kind, apiName string, jobID string) string {
+	return s.EnsureSuffix(filepath.Join(JobAPIPrefix(clusterUID, kind, apiName), jobID, "failed_batches"), "/")
+}
+
+// the batch ID is the ID of the batch's SQS message
+func JobFailedBatchKey(clusterUID string, kind userconfig.Kind, apiName string, jobID string, batchID string) string {
+	return filepath.Join(JobFailedBatchesPrefix(clusterUID, kind, apiName, jobID), batchID+".json")
+}
+
+// e.g. /<cluster UID>/jobs/<job_api_kind>/<cortex version>/<api_name>/<job_id>/batch_states/
+func JobBatchStatesPrefix(clusterUID string, kind userconfig.Kind, apiName string, jobID string) string {
+	return s.EnsureSuffix(filepath.Join(JobAPIPrefix(clusterUID, kind, apiName), jobID, "batch_states"), "/")
+}
+
+// the batch state is stored as an empty file named after the batch index and the state, e.g. 12.succeeded
+func JobBatchStateKey(clusterUID string, kind userconfig.Kind, apiName string, jobID string, batchIndex int, state string) string {
+	return filepath.Join(JobBatchStatesPrefix(clusterUID, kind, apiName, jobID), fmt.Sprintf("%d.%s", batchIndex, state))
+}
+
+// the s3 prefix under which the job's containers are expected to write their results, e.g. s3://<bucket>/<output_path>/<job_id>/
+func (j BatchJob) OutputPrefix() string {
+	if j.OutputPath == "" {
+		return ""
+	}
+	return s.EnsureSuffix(s.EnsureSuffix(j.OutputPath, "/")+j.ID, "/")
+}
+
 func JobMetricsKey(clusterUID string, kind userconfig.Kind, apiName string, jobID string) string {
 	return filepath.Join(JobAPIPrefix(clusterUID, kind, apiName), jobID, MetricsFileKey)
 }
@@ -0,0 +1,54 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spec
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+)
+
+// JobSchedule is a job submission which the operator submits on a cron schedule
+type JobSchedule struct {
+	ID                string          `json:"schedule_id" yaml:"schedule_id"`
+	APIName           string          `json:"api_name" yaml:"api_name"`
+	Kind              userconfig.Kind `json:"kind" yaml:"kind"`
+	Schedule          string          `json:"schedule" yaml:"schedule"`
+	Paused            bool            `json:"paused" yaml:"paused"`
+	Submission        json.RawMessage `json:"submission" yaml:"-"` // the job submission (without the schedule)
+	CreatedTime       time.Time       `json:"created_time" yaml:"created_time"`
+	LastScheduledTime time.Time       `json:"last_scheduled_time" yaml:"last_scheduled_time"`
+	LastJobID         string          `json:"last_job_id,omitempty" yaml:"last_job_id,omitempty"`
+	LastError         string          `json:"last_error,omitempty" yaml:"last_error,omitempty"`
+}
+
+// e.g. /<cluster UID>/job_schedules
+func JobSchedulesPrefix(clusterUID string) string {
+	return filepath.Join(clusterUID, "job_schedules")
+}
+
+// e.g. /<cluster UID>/job_schedules/<api_name>
+func JobSchedulesAPIPrefix(clusterUID string, apiName string) string {
+	return filepath.Join(JobSchedulesPrefix(clusterUID), apiName)
+}
+
+// e.g. /<cluster UID>/job_schedules/<api_name>/<schedule_id>.json
+func JobScheduleKey(clusterUID string, apiName string, scheduleID string) string {
+	return filepath.Join(JobSchedulesAPIPrefix(clusterUID, apiName), scheduleID+".json")
+}
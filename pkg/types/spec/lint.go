@@ -0,0 +1,108 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spec
+
+import (
+	"github.com/cortexlabs/cortex/pkg/lib/cast"
+	cr "github.com/cortexlabs/cortex/pkg/lib/configreader"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/pointer"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+)
+
+// LintIssue is a problem which was found in an api configuration file; Code is the kind of the error, which doesn't change between releases
+type LintIssue struct {
+	Code    string `json:"code"`
+	API     string `json:"api,omitempty"`
+	Index   *int   `json:"index,omitempty"`
+	Message string `json:"message"`
+}
+
+// LintAPIConfigs validates the apis in the config file without access to the cluster, returning all of the issues which were found instead of only the first one.
+// Checks which require the cluster (e.g. whether the images, registry credentials, and custom domains are accessible) are skipped.
+func LintAPIConfigs(configBytes []byte, configFileName string) []LintIssue {
+	var issues []LintIssue
+	addIssues := func(api string, index *int, errs ...error) {
+		for _, err := range errs {
+			if err == nil {
+				continue
+			}
+			issues = append(issues, LintIssue{
+				Code:    errors.GetKind(err),
+				API:     api,
+				Index:   index,
+				Message: errors.Message(err),
+			})
+		}
+	}
+
+	configData, err := cr.ReadYAMLBytes(configBytes)
+	if err != nil {
+		addIssues("", nil, errors.Wrap(err, configFileName))
+		return issues
+	}
+
+	configDataSlice, ok := cast.InterfaceToStrInterfaceMapSlice(configData)
+	if !ok {
+		addIssues("", nil, errors.Wrap(ErrorMalformedConfig(), configFileName))
+		return issues
+	}
+
+	if len(configDataSlice) == 0 {
+		addIssues("", nil, ErrorNoAPIs())
+		return issues
+	}
+
+	// the uniqueness checks include the apis which have other issues, since those are likely to be fixed independently
+	var namedAPIs []userconfig.API
+	var apis []userconfig.API
+	for i, data := range configDataSlice {
+		name, _ := data[userconfig.NameKey].(string)
+		if name != "" {
+			namedAPIs = append(namedAPIs, userconfig.API{Resource: userconfig.Resource{Name: name}, Index: i, FileName: configFileName})
+		}
+
+		api, errs := extractAPIConfig(data, configFileName, i)
+		if errors.HasError(errs) {
+			addIssues(name, pointer.Int(i), errs...)
+			continue
+		}
+
+		switch api.Kind {
+		case userconfig.TrafficSplitterKind:
+			err = ValidateTrafficSplitter(api)
+		default:
+			err = ValidateAPI(api, nil, nil)
+		}
+		if err != nil {
+			addIssues(api.Name, pointer.Int(i), errors.Wrap(err, api.Identify()))
+		}
+
+		if api.Networking.Endpoint != nil {
+			apis = append(apis, *api)
+		}
+	}
+
+	if dups := FindDuplicateNames(namedAPIs); len(dups) > 0 {
+		addIssues(dups[1].Name, pointer.Int(dups[1].Index), ErrorDuplicateName(dups))
+	}
+	if dups := FindDuplicateEndpoints(apis); len(dups) > 0 {
+		addIssues(dups[1].Name, pointer.Int(dups[1].Index), ErrorDuplicateEndpointInOneDeploy(dups))
+	}
+
+	return issues
+}
@@ -17,8 +17,10 @@ limitations under the License.
 package spec
 
 import (
+	"path/filepath"
 	"strings"
 
+	"github.com/cortexlabs/cortex/pkg/lib/aws"
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
 	s "github.com/cortexlabs/cortex/pkg/lib/strings"
 	"github.com/cortexlabs/cortex/pkg/types/userconfig"
@@ -40,6 +42,66 @@ func FindDuplicateNames(apis []userconfig.API) []userconfig.API {
 	return nil
 }
 
+func FindDuplicateEndpoints(apis []userconfig.API) []userconfig.API {
+	endpoints := make(map[string][]userconfig.API)
+
+	for _, api := range apis {
+		endpoints[*api.Networking.Endpoint] = append(endpoints[*api.Networking.Endpoint], api)
+	}
+
+	for endpoint := range endpoints {
+		if len(endpoints[endpoint]) > 1 {
+			return endpoints[endpoint]
+		}
+	}
+
+	return nil
+}
+
+// LatestModelVersion returns the newest version directory under modelPath; if all of the version names are integers,
+// the largest one is returned, otherwise the last one in lexicographical order is returned
+func LatestModelVersion(modelPath string, awsClient *aws.Client) (string, error) {
+	bucket, key, err := aws.SplitS3Path(modelPath)
+	if err != nil {
+		return "", err
+	}
+
+	versions, err := awsClient.ListS3SubDirs(bucket, key)
+	if err != nil {
+		return "", err
+	}
+
+	if len(versions) == 0 {
+		return "", ErrorNoModelVersionsFound(modelPath)
+	}
+
+	var latestNumericVersion int64
+	for i, version := range versions {
+		parsed, ok := s.ParseInt64(version)
+		if !ok {
+			return versions[len(versions)-1], nil
+		}
+		if i == 0 || parsed > latestNumericVersion {
+			latestNumericVersion = parsed
+		}
+	}
+
+	return s.Int64(latestNumericVersion), nil
+}
+
+func modelCacheMountPathValidator(mountPath string) (string, error) {
+	mountPath = filepath.Clean(mountPath)
+	if !filepath.IsAbs(mountPath) || mountPath == "/" || mountPath == "/mnt" {
+		return "", ErrorInvalidModelCacheMountPath(mountPath)
+	}
+	for _, reservedDir := range []string{"/cortex", "/dev", "/proc", "/sys"} {
+		if mountPath == reservedDir || strings.HasPrefix(mountPath, reservedDir+"/") {
+			return "", ErrorInvalidModelCacheMountPath(mountPath)
+		}
+	}
+	return mountPath, nil
+}
+
 func surgeOrUnavailableValidator(str string) (string, error) {
 	if strings.HasSuffix(str, "%") {
 		parsed, ok := s.ParseInt32(strings.TrimSuffix(str, "%"))
@@ -18,7 +18,11 @@ package spec
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
+	"math"
+	"net"
+	"net/http"
 	"strings"
 	"time"
 
@@ -32,6 +36,7 @@ import (
 	"github.com/cortexlabs/cortex/pkg/lib/k8s"
 	"github.com/cortexlabs/cortex/pkg/lib/pointer"
 	"github.com/cortexlabs/cortex/pkg/lib/regex"
+	"github.com/cortexlabs/cortex/pkg/lib/sets/strset"
 	"github.com/cortexlabs/cortex/pkg/lib/slices"
 	s "github.com/cortexlabs/cortex/pkg/lib/strings"
 	libtime "github.com/cortexlabs/cortex/pkg/lib/time"
@@ -39,11 +44,28 @@ import (
 	"github.com/cortexlabs/cortex/pkg/types/userconfig"
 	dockertypes "github.com/docker/docker/api/types"
 	kresource "k8s.io/apimachinery/pkg/api/resource"
+	kvalidation "k8s.io/apimachinery/pkg/util/validation"
 )
 
 var AutoscalingTickInterval = 10 * time.Second
 
-const _dockerPullSecretName = "registry-credentials"
+const (
+	_dockerPullSecretName = "registry-credentials"
+	_ecrRoleSessionName   = "cortex-registry-credentials"
+	_ecrRoleNamePrefix    = "cortex-"
+)
+
+// labels which cortex sets on the resources it creates for an api
+var _reservedLabelKeys = strset.New(
+	"apiName",
+	"apiKind",
+	"apiID",
+	"specID",
+	"deploymentID",
+	"podID",
+	"initialDeploymentTime",
+	"jobID",
+)
 
 func apiValidation(resource userconfig.Resource) *cr.StructValidation {
 	var structFieldValidations []*cr.StructFieldValidation
@@ -51,36 +73,65 @@ func apiValidation(resource userconfig.Resource) *cr.StructValidation {
 	switch resource.Kind {
 	case userconfig.RealtimeAPIKind:
 		structFieldValidations = append(resourceStructValidations,
+			labelsValidation(),
+			projectValidation(),
 			podValidation(userconfig.RealtimeAPIKind),
 			nodegroupsValidation(),
-			networkingValidation(),
+			networkingValidation(userconfig.RealtimeAPIKind),
 			autoscalingValidation(),
 			updateStrategyValidation(),
+			canaryValidation(),
+			warmPoolValidation(),
+			requestLoggingValidation(),
+			predictionMetricsValidation(),
+			rateLimitValidation(),
+			webSocketValidation(),
+			modelWatchValidation(),
+			modelCacheValidation(),
+			prepullImagesValidation(),
+			authenticationValidation(),
+			awsIAMPrincipalsValidation(),
 		)
 	case userconfig.AsyncAPIKind:
 		structFieldValidations = append(resourceStructValidations,
+			labelsValidation(),
+			projectValidation(),
 			podValidation(userconfig.AsyncAPIKind),
 			nodegroupsValidation(),
-			networkingValidation(),
+			networkingValidation(userconfig.AsyncAPIKind),
 			autoscalingValidation(),
 			updateStrategyValidation(),
+			asyncValidation(),
+			predictionMetricsValidation(),
+			modelWatchValidation(),
+			modelCacheValidation(),
+			prepullImagesValidation(),
 		)
 	case userconfig.BatchAPIKind:
 		structFieldValidations = append(resourceStructValidations,
+			labelsValidation(),
+			projectValidation(),
 			podValidation(userconfig.BatchAPIKind),
 			nodegroupsValidation(),
-			networkingValidation(),
+			networkingValidation(userconfig.BatchAPIKind),
+			maxConcurrentJobsValidation(),
 		)
 	case userconfig.TaskAPIKind:
 		structFieldValidations = append(resourceStructValidations,
+			labelsValidation(),
+			projectValidation(),
 			podValidation(userconfig.TaskAPIKind),
 			nodegroupsValidation(),
-			networkingValidation(),
+			networkingValidation(userconfig.TaskAPIKind),
+			maxConcurrentJobsValidation(),
+			distributedValidation(),
 		)
 	case userconfig.TrafficSplitterKind:
 		structFieldValidations = append(resourceStructValidations,
+			labelsValidation(),
+			projectValidation(),
 			multiAPIsValidation(),
-			networkingValidation(),
+			networkingValidation(userconfig.TrafficSplitterKind),
 		)
 	}
 	return &cr.StructValidation{
@@ -110,6 +161,48 @@ var resourceStructValidations = []*cr.StructFieldValidation{
 	},
 }
 
+func labelsValidation() *cr.StructFieldValidation {
+	return &cr.StructFieldValidation{
+		StructField: "Labels",
+		StringMapValidation: &cr.StringMapValidation{
+			Required:           false,
+			Default:            map[string]string{},
+			AllowEmpty:         true,
+			AllowExplicitNull:  true,
+			ConvertNullToEmpty: true,
+			Validator:          labelsValidator,
+		},
+	}
+}
+
+func labelsValidator(labels map[string]string) (map[string]string, error) {
+	for key, value := range labels {
+		if _reservedLabelKeys.Has(key) || strings.HasPrefix(key, "cortex.dev/") {
+			return nil, ErrorReservedLabel(key)
+		}
+		if reasons := kvalidation.IsQualifiedName(key); len(reasons) > 0 {
+			return nil, ErrorInvalidLabel(key, value, reasons)
+		}
+		if reasons := kvalidation.IsValidLabelValue(value); len(reasons) > 0 {
+			return nil, ErrorInvalidLabel(key, value, reasons)
+		}
+	}
+	return labels, nil
+}
+
+func projectValidation() *cr.StructFieldValidation {
+	return &cr.StructFieldValidation{
+		StructField: "Project",
+		StringValidation: &cr.StringValidation{
+			Default:    "",
+			AllowEmpty: true,
+			Validator: func(project string) (string, error) {
+				return project, userconfig.ValidateProject(project)
+			},
+		},
+	}
+}
+
 func multiAPIsValidation() *cr.StructFieldValidation {
 	return &cr.StructFieldValidation{
 		StructField: "APIs",
@@ -159,6 +252,20 @@ func podValidation(kind userconfig.Kind) *cr.StructFieldValidation {
 						DisallowedValues:  consts.ReservedContainerPorts,
 					},
 				},
+				registryCredentialsValidation(),
+				{
+					StructField: "IAMRoleARN",
+					StringPtrValidation: &cr.StringPtrValidation{
+						Required: false,
+						Validator: func(roleARN string) (string, error) {
+							if !regex.IsValidIAMRoleARN(roleARN) {
+								return "", ErrorInvalidIAMRoleARN(roleARN)
+							}
+							return roleARN, nil
+						},
+					},
+				},
+				podSecurityValidation(),
 				containersValidation(kind),
 			},
 		},
@@ -166,6 +273,13 @@ func podValidation(kind userconfig.Kind) *cr.StructFieldValidation {
 
 	if kind == userconfig.RealtimeAPIKind {
 		validation.StructValidation.StructFieldValidations = append(validation.StructValidation.StructFieldValidations,
+			&cr.StructFieldValidation{
+				StructField: "Protocol",
+				StringValidation: &cr.StringValidation{
+					Default:       userconfig.ProtocolHTTP,
+					AllowedValues: []string{userconfig.ProtocolHTTP, userconfig.ProtocolGRPC},
+				},
+			},
 			&cr.StructFieldValidation{
 				StructField: "MaxQueueLength",
 				Int64Validation: &cr.Int64Validation{
@@ -186,6 +300,38 @@ func podValidation(kind userconfig.Kind) *cr.StructFieldValidation {
 					LessThanOrEqualTo: pointer.Int64(30000),
 				},
 			},
+			&cr.StructFieldValidation{
+				StructField: "MaxRequestBodySize",
+				StringPtrValidation: &cr.StringPtrValidation{
+					Default:           nil,
+					AllowExplicitNull: true,
+					CastNumeric:       true,
+				},
+				Parser: k8s.QuantityParser(&k8s.QuantityValidation{
+					GreaterThan: k8s.QuantityPtr(kresource.MustParse("0")),
+				}),
+			},
+			&cr.StructFieldValidation{
+				StructField: "RequestTimeout",
+				StringPtrValidation: &cr.StringPtrValidation{
+					Default:           nil,
+					AllowExplicitNull: true,
+				},
+				Parser: cr.DurationParser(&cr.DurationValidation{
+					GreaterThanOrEqualTo: pointer.Duration(libtime.MustParseDuration("1s")),
+				}),
+			},
+			&cr.StructFieldValidation{
+				StructField: "MaxConnections",
+				Int64PtrValidation: &cr.Int64PtrValidation{
+					Default:           nil,
+					AllowExplicitNull: true,
+					GreaterThan:       pointer.Int64(0),
+					// see MaxQueueLength
+					LessThanOrEqualTo: pointer.Int64(30000),
+				},
+			},
+			warmupValidation(),
 		)
 	}
 
@@ -205,6 +351,122 @@ func podValidation(kind userconfig.Kind) *cr.StructFieldValidation {
 	return validation
 }
 
+func warmupValidation() *cr.StructFieldValidation {
+	return &cr.StructFieldValidation{
+		StructField: "Warmup",
+		StructValidation: &cr.StructValidation{
+			Required:          false,
+			AllowExplicitNull: true,
+			DefaultNil:        true,
+			StructFieldValidations: []*cr.StructFieldValidation{
+				{
+					StructField: "Path",
+					StringValidation: &cr.StringValidation{
+						Required:  false,
+						Default:   "/",
+						Validator: urls.ValidateEndpointAllowEmptyPath,
+					},
+				},
+				{
+					StructField: "Payload",
+					StringPtrValidation: &cr.StringPtrValidation{
+						Required:   false,
+						AllowEmpty: true,
+						MaxLength:  65536, // the payload is passed to the proxy as a container argument
+					},
+				},
+				{
+					StructField: "Method",
+					StringValidation: &cr.StringValidation{
+						AllowedValues: []string{http.MethodGet, http.MethodPost},
+					},
+					DefaultDependentFields: []string{"Payload"},
+					DefaultDependentFieldsFunc: func(vals []interface{}) interface{} {
+						if vals[0].(*string) != nil {
+							return http.MethodPost
+						}
+						return http.MethodGet
+					},
+				},
+				{
+					StructField: "Headers",
+					StringMapValidation: &cr.StringMapValidation{
+						Required:   false,
+						Default:    map[string]string{},
+						AllowEmpty: true,
+					},
+				},
+				{
+					StructField: "NumRequests",
+					Int64Validation: &cr.Int64Validation{
+						Default:           1,
+						GreaterThan:       pointer.Int64(0),
+						LessThanOrEqualTo: pointer.Int64(1000),
+					},
+				},
+				{
+					StructField: "TimeoutSeconds",
+					Int64Validation: &cr.Int64Validation{
+						Default:     60,
+						GreaterThan: pointer.Int64(0),
+					},
+				},
+			},
+		},
+	}
+}
+
+func registryCredentialsValidation() *cr.StructFieldValidation {
+	return &cr.StructFieldValidation{
+		StructField: "RegistryCredentials",
+		StructValidation: &cr.StructValidation{
+			Required:          false,
+			AllowExplicitNull: true,
+			DefaultNil:        true,
+			StructFieldValidations: []*cr.StructFieldValidation{
+				{
+					StructField: "Secret",
+					StringPtrValidation: &cr.StringPtrValidation{
+						Required: false,
+						DNS1123:  true,
+					},
+				},
+				{
+					StructField: "SecretsManagerARN",
+					StringPtrValidation: &cr.StringPtrValidation{
+						Required: false,
+						Prefix:   "arn:",
+					},
+				},
+				{
+					StructField: "ECRRoleARN",
+					StringPtrValidation: &cr.StringPtrValidation{
+						Required: false,
+						Validator: func(roleARN string) (string, error) {
+							if !regex.IsValidIAMRoleARN(roleARN) {
+								return "", ErrorInvalidIAMRoleARN(roleARN)
+							}
+							// the operator's iam policy only allows it to assume roles whose name starts with "cortex-"
+							if !strings.HasPrefix(roleARN[strings.Index(roleARN, ":role/")+len(":role/"):], _ecrRoleNamePrefix) {
+								return "", ErrorECRRoleNameMissingPrefix(roleARN)
+							}
+							return roleARN, nil
+						},
+					},
+				},
+				{
+					StructField: "RegistryAuth",
+					StringPtrValidation: &cr.StringPtrValidation{
+						Required:                      false,
+						MaxLength:                     128,
+						AlphaNumericDashDotUnderscore: true,
+					},
+				},
+			},
+		},
+	}
+}
+
 func containersValidation(kind userconfig.Kind) *cr.StructFieldValidation {
 	validations := []*cr.StructFieldValidation{
 		{
@@ -233,6 +495,14 @@ func containersValidation(kind userconfig.Kind) *cr.StructFieldValidation {
 				AllowEmpty: true,
 			},
 		},
+		{
+			StructField: "EnvFromSecrets",
+			StringMapValidation: &cr.StringMapValidation{
+				Required:   false,
+				Default:    map[string]string{},
+				AllowEmpty: true,
+			},
+		},
 		{
 			StructField: "Command",
 			StringListValidation: &cr.StringListValidation{
@@ -291,16 +561,152 @@ func nodegroupsValidation() *cr.StructFieldValidation {
 	}
 }
 
-func networkingValidation() *cr.StructFieldValidation {
+func podSecurityValidation() *cr.StructFieldValidation {
+	return &cr.StructFieldValidation{
+		StructField: "Security",
+		StructValidation: &cr.StructValidation{
+			Required:          false,
+			AllowExplicitNull: true,
+			StructFieldValidations: []*cr.StructFieldValidation{
+				{
+					StructField: "Privileged",
+					BoolValidation: &cr.BoolValidation{
+						Default: false,
+					},
+				},
+				{
+					StructField: "ReadOnlyRootFS",
+					BoolValidation: &cr.BoolValidation{
+						Default: false,
+					},
+				},
+				{
+					StructField: "RunAsNonRoot",
+					BoolValidation: &cr.BoolValidation{
+						Default: false,
+					},
+				},
+				{
+					StructField: "RunAsUser",
+					Int64PtrValidation: &cr.Int64PtrValidation{
+						GreaterThanOrEqualTo: pointer.Int64(0),
+					},
+				},
+				{
+					StructField: "DropCapabilities",
+					StringListValidation: &cr.StringListValidation{
+						Default:           []string{},
+						AllowExplicitNull: true,
+						AllowEmpty:        true,
+						DisallowDups:      true,
+						Validator:         k8s.NormalizeCapabilities,
+					},
+				},
+				{
+					StructField: "SeccompProfile",
+					StringValidation: &cr.StringValidation{
+						Default:   userconfig.SeccompProfileRuntimeDefault,
+						Validator: validateSeccompProfile,
+					},
+				},
+			},
+		},
+	}
+}
+
+func validateSeccompProfile(profile string) (string, error) {
+	if profile == userconfig.SeccompProfileRuntimeDefault || profile == userconfig.SeccompProfileUnconfined {
+		return profile, nil
+	}
+
+	if strings.HasPrefix(profile, userconfig.SeccompProfileLocalhostPrefix) {
+		// the path is relative to the kubelet's seccomp profile directory
+		path := strings.TrimPrefix(profile, userconfig.SeccompProfileLocalhostPrefix)
+		if path != "" && !strings.HasPrefix(path, "/") && !slices.HasString(strings.Split(path, "/"), "..") {
+			return profile, nil
+		}
+	}
+
+	return "", ErrorInvalidSeccompProfile(profile)
+}
+
+func networkingValidation(kind userconfig.Kind) *cr.StructFieldValidation {
+	structFieldValidations := []*cr.StructFieldValidation{
+		{
+			StructField: "Endpoint",
+			StringPtrValidation: &cr.StringPtrValidation{
+				Validator: urls.ValidateEndpoint,
+				MaxLength: 1000, // no particular reason other than it works
+			},
+		},
+		{
+			StructField: "EndpointVisibility",
+			StringPtrValidation: &cr.StringPtrValidation{
+				AllowedValues: []string{userconfig.EndpointVisibilityPublic, userconfig.EndpointVisibilityInternal},
+			},
+		},
+		{
+			StructField: "CustomDomain",
+			StringPtrValidation: &cr.StringPtrValidation{
+				Validator: urls.ValidateDomainName,
+			},
+		},
+	}
+
+	// traffic splitters don't have pods
+	if kind != userconfig.TrafficSplitterKind {
+		structFieldValidations = append(structFieldValidations, egressValidation())
+	}
+
 	return &cr.StructFieldValidation{
 		StructField: "Networking",
 		StructValidation: &cr.StructValidation{
+			StructFieldValidations: structFieldValidations,
+		},
+	}
+}
+
+func egressValidation() *cr.StructFieldValidation {
+	return &cr.StructFieldValidation{
+		StructField: "Egress",
+		StructValidation: &cr.StructValidation{
+			Required:          false,
+			AllowExplicitNull: true,
+			DefaultNil:        true,
 			StructFieldValidations: []*cr.StructFieldValidation{
 				{
-					StructField: "Endpoint",
-					StringPtrValidation: &cr.StringPtrValidation{
-						Validator: urls.ValidateEndpoint,
-						MaxLength: 1000, // no particular reason other than it works
+					StructField: "AllowedCIDRs",
+					StringListValidation: &cr.StringListValidation{
+						Default:           []string{},
+						AllowExplicitNull: true,
+						AllowEmpty:        true,
+						Validator: func(cidrs []string) ([]string, error) {
+							for i, cidr := range cidrs {
+								if _, _, err := net.ParseCIDR(cidr); err != nil {
+									return nil, errors.Wrap(ErrorInvalidCIDR(cidr), s.Index(i))
+								}
+							}
+							return cidrs, nil
+						},
+					},
+				},
+				{
+					StructField: "AllowedDomains",
+					StringListValidation: &cr.StringListValidation{
+						Default:           []string{},
+						AllowExplicitNull: true,
+						AllowEmpty:        true,
+						Validator: func(domains []string) ([]string, error) {
+							validated := make([]string, len(domains))
+							for i, domain := range domains {
+								validatedDomain, err := urls.ValidateDomainName(domain)
+								if err != nil {
+									return nil, errors.Wrap(err, s.Index(i))
+								}
+								validated[i] = validatedDomain
+							}
+							return validated, nil
+						},
 					},
 				},
 			},
@@ -490,6 +896,13 @@ func computeValidation() *cr.StructFieldValidation {
 						GreaterThanOrEqualTo: pointer.Int64(0),
 					},
 				},
+				{
+					StructField: "NeuronCores",
+					Int64Validation: &cr.Int64Validation{
+						Default:              0,
+						GreaterThanOrEqualTo: pointer.Int64(0),
+					},
+				},
 				{
 					StructField: "Shm",
 					StringPtrValidation: &cr.StringPtrValidation{
@@ -536,6 +949,47 @@ func autoscalingValidation() *cr.StructFieldValidation {
 						GreaterThan: pointer.Float64(0),
 					},
 				},
+				{
+					StructField: "TargetGPUUtilization",
+					Float64PtrValidation: &cr.Float64PtrValidation{
+						Default:           nil,
+						GreaterThan:       pointer.Float64(0),
+						LessThanOrEqualTo: pointer.Float64(100),
+					},
+				},
+				{
+					StructField: "CustomMetric",
+					StructValidation: &cr.StructValidation{
+						DefaultNil:        true,
+						AllowExplicitNull: true,
+						StructFieldValidations: []*cr.StructFieldValidation{
+							{
+								StructField: "Query",
+								StringValidation: &cr.StringValidation{
+									Required: true,
+								},
+							},
+							{
+								StructField: "Target",
+								Float64Validation: &cr.Float64Validation{
+									Required:    true,
+									GreaterThan: pointer.Float64(0),
+								},
+							},
+						},
+					},
+				},
+				{
+					StructField: "MaxQueueWait",
+					StringPtrValidation: &cr.StringPtrValidation{
+						Default:           nil,
+						AllowExplicitNull: true,
+					},
+					Parser: cr.DurationParser(&cr.DurationValidation{
+						GreaterThanOrEqualTo: pointer.Duration(libtime.MustParseDuration("1s")),
+						LessThanOrEqualTo:    pointer.Duration(consts.WaitForReadyReplicasTimeout),
+					}),
+				},
 				{
 					StructField: "Window",
 					StringValidation: &cr.StringValidation{
@@ -625,59 +1079,469 @@ func updateStrategyValidation() *cr.StructFieldValidation {
 	}
 }
 
-var resourceStructValidation = cr.StructValidation{
-	AllowExtraFields:       true,
-	StructFieldValidations: resourceStructValidations,
-}
-
-func ExtractAPIConfigs(configBytes []byte, configFileName string) ([]userconfig.API, error) {
-	var err error
-
-	configData, err := cr.ReadYAMLBytes(configBytes)
-	if err != nil {
-		return nil, errors.Wrap(err, configFileName)
-	}
-
-	configDataSlice, ok := cast.InterfaceToStrInterfaceMapSlice(configData)
-	if !ok {
-		return nil, errors.Wrap(ErrorMalformedConfig(), configFileName)
+func asyncValidation() *cr.StructFieldValidation {
+	return &cr.StructFieldValidation{
+		StructField: "Async",
+		StructValidation: &cr.StructValidation{
+			StructFieldValidations: []*cr.StructFieldValidation{
+				{
+					StructField: "ResultTTL",
+					StringPtrValidation: &cr.StringPtrValidation{
+						Default:           nil,
+						AllowExplicitNull: true,
+					},
+					Parser: cr.DurationParser(&cr.DurationValidation{
+						GreaterThanOrEqualTo: pointer.Duration(libtime.MustParseDuration("1m")),
+						LessThanOrEqualTo:    pointer.Duration(time.Duration(consts.AsyncWorkloadsExpirationDays) * 24 * time.Hour), // workloads are deleted by the bucket's lifecycle policy after this
+					}),
+				},
+				{
+					StructField: "IdempotencyWindow",
+					StringValidation: &cr.StringValidation{
+						Default: "24h",
+					},
+					Parser: cr.DurationParser(&cr.DurationValidation{
+						GreaterThanOrEqualTo: pointer.Duration(libtime.MustParseDuration("1s")),
+						LessThanOrEqualTo:    pointer.Duration(time.Duration(consts.AsyncWorkloadsExpirationDays) * 24 * time.Hour), // idempotency keys are deleted by the bucket's lifecycle policy after this
+					}),
+				},
+				{
+					StructField: "OutputPath",
+					StringPtrValidation: &cr.StringPtrValidation{
+						Default:           nil,
+						AllowExplicitNull: true,
+						Validator:         cr.S3PathValidator,
+					},
+				},
+				{
+					StructField: "MaxReceiveCount",
+					Int64PtrValidation: &cr.Int64PtrValidation{
+						Default:              nil,
+						AllowExplicitNull:    true,
+						GreaterThanOrEqualTo: pointer.Int64(1),
+						LessThanOrEqualTo:    pointer.Int64(1000), // sqs limit for redrive policies
+					},
+				},
+			},
+		},
 	}
+}
 
-	apis := make([]userconfig.API, len(configDataSlice))
-	for i, data := range configDataSlice {
-		api := userconfig.API{}
-		var resourceStruct userconfig.Resource
-		errs := cr.Struct(&resourceStruct, data, &resourceStructValidation)
-		if errors.HasError(errs) {
-			name, _ := data[userconfig.NameKey].(string)
-			kindString, _ := data[userconfig.KindKey].(string)
-			kind := userconfig.KindFromString(kindString)
-			err = errors.Wrap(errors.FirstError(errs...), userconfig.IdentifyAPI(configFileName, name, kind, i))
-			return nil, errors.Append(err, fmt.Sprintf("\n\napi configuration schema can be found at https://docs.cortexlabs.com/v/%s/", consts.CortexVersionMinor))
-		}
+func warmPoolValidation() *cr.StructFieldValidation {
+	return &cr.StructFieldValidation{
+		StructField: "WarmPool",
+		StructValidation: &cr.StructValidation{
+			Required:          false,
+			AllowExplicitNull: true,
+			DefaultNil:        true,
+			StructFieldValidations: []*cr.StructFieldValidation{
+				{
+					StructField: "Replicas",
+					Int32Validation: &cr.Int32Validation{
+						Default:     1,
+						GreaterThan: pointer.Int32(0),
+					},
+				},
+			},
+		},
+	}
+}
 
-		errs = cr.Struct(&api, data, apiValidation(resourceStruct))
-		if errors.HasError(errs) {
-			name, _ := data[userconfig.NameKey].(string)
-			kindString, _ := data[userconfig.KindKey].(string)
-			kind := userconfig.KindFromString(kindString)
-			err = errors.Wrap(errors.FirstError(errs...), userconfig.IdentifyAPI(configFileName, name, kind, i))
-			return nil, errors.Append(err, fmt.Sprintf("\n\napi configuration schema can be found at https://docs.cortexlabs.com/v/%s/", consts.CortexVersionMinor))
-		}
-		api.Index = i
-		api.FileName = configFileName
+func canaryValidation() *cr.StructFieldValidation {
+	return &cr.StructFieldValidation{
+		StructField: "Canary",
+		StructValidation: &cr.StructValidation{
+			Required:          false,
+			AllowExplicitNull: true,
+			DefaultNil:        true,
+			StructFieldValidations: []*cr.StructFieldValidation{
+				{
+					StructField: "Weight",
+					Int32Validation: &cr.Int32Validation{
+						Default:              10,
+						GreaterThanOrEqualTo: pointer.Int32(1),
+						LessThanOrEqualTo:    pointer.Int32(99),
+					},
+				},
+			},
+		},
+	}
+}
 
-		interfaceMap, ok := cast.JSONMarshallable(data)
-		if !ok {
-			return nil, errors.ErrorUnexpected("unable to cast api spec to json") // unexpected
+func requestLoggingValidation() *cr.StructFieldValidation {
+	return &cr.StructFieldValidation{
+		StructField: "RequestLogging",
+		StructValidation: &cr.StructValidation{
+			Required:          false,
+			AllowExplicitNull: true,
+			DefaultNil:        true,
+			StructFieldValidations: []*cr.StructFieldValidation{
+				{
+					StructField: "SamplePercentage",
+					Float64Validation: &cr.Float64Validation{
+						Default:           100,
+						GreaterThan:       pointer.Float64(0),
+						LessThanOrEqualTo: pointer.Float64(100),
+					},
+				},
+				{
+					StructField: "S3Path",
+					StringPtrValidation: &cr.StringPtrValidation{
+						Default:           nil,
+						AllowExplicitNull: true,
+						Validator:         cr.S3PathValidator,
+					},
+				},
+				{
+					StructField: "KinesisStream",
+					StringPtrValidation: &cr.StringPtrValidation{
+						Default:                       nil,
+						AllowExplicitNull:             true,
+						AlphaNumericDashDotUnderscore: true,
+						MaxLength:                     128,
+					},
+				},
+				{
+					StructField: "RedactHeaders",
+					StringListValidation: &cr.StringListValidation{
+						Default:      []string{},
+						AllowEmpty:   true,
+						DisallowDups: true,
+					},
+				},
+				{
+					StructField: "RedactFields",
+					StringListValidation: &cr.StringListValidation{
+						Default:      []string{},
+						AllowEmpty:   true,
+						DisallowDups: true,
+					},
+				},
+			},
+		},
+	}
+}
+
+func rateLimitValidation() *cr.StructFieldValidation {
+	return &cr.StructFieldValidation{
+		StructField: "RateLimit",
+		StructValidation: &cr.StructValidation{
+			Required:          false,
+			AllowExplicitNull: true,
+			DefaultNil:        true,
+			StructFieldValidations: []*cr.StructFieldValidation{
+				{
+					StructField: "RequestsPerSecond",
+					Float64Validation: &cr.Float64Validation{
+						Required:    true,
+						GreaterThan: pointer.Float64(0),
+					},
+				},
+				{
+					StructField: "Burst",
+					Int64Validation: &cr.Int64Validation{
+						GreaterThan: pointer.Int64(0),
+					},
+					DefaultDependentFields: []string{"RequestsPerSecond"},
+					DefaultDependentFieldsFunc: func(vals []interface{}) interface{} {
+						return int64(math.Ceil(vals[0].(float64)))
+					},
+				},
+				{
+					StructField: "KeyHeader",
+					StringPtrValidation: &cr.StringPtrValidation{
+						Default:           nil,
+						AllowExplicitNull: true,
+						MaxLength:         256,
+						Validator: func(header string) (string, error) {
+							return http.CanonicalHeaderKey(header), nil
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func webSocketValidation() *cr.StructFieldValidation {
+	return &cr.StructFieldValidation{
+		StructField: "WebSocket",
+		StructValidation: &cr.StructValidation{
+			Required:          false,
+			AllowExplicitNull: true,
+			DefaultNil:        true,
+			StructFieldValidations: []*cr.StructFieldValidation{
+				{
+					StructField: "IdleTimeout",
+					StringValidation: &cr.StringValidation{
+						Default: "60s",
+					},
+					Parser: cr.DurationParser(&cr.DurationValidation{
+						GreaterThanOrEqualTo: pointer.Duration(libtime.MustParseDuration("1s")),
+						LessThanOrEqualTo:    pointer.Duration(libtime.MustParseDuration("5m")), // the ingress gateway closes streams which have been idle for 5 minutes
+					}),
+				},
+				{
+					StructField: "MaxConnections",
+					Int64Validation: &cr.Int64Validation{
+						Default:     100,
+						GreaterThan: pointer.Int64(0),
+					},
+				},
+			},
+		},
+	}
+}
+
+func modelWatchValidation() *cr.StructFieldValidation {
+	return &cr.StructFieldValidation{
+		StructField: "ModelWatch",
+		StructValidation: &cr.StructValidation{
+			Required:          false,
+			AllowExplicitNull: true,
+			DefaultNil:        true,
+			StructFieldValidations: []*cr.StructFieldValidation{
+				{
+					StructField: "Path",
+					StringValidation: &cr.StringValidation{
+						Required:  true,
+						Validator: cr.S3PathValidator,
+					},
+				},
+				{
+					StructField: "PollInterval",
+					StringValidation: &cr.StringValidation{
+						Default: "1m",
+					},
+					Parser: cr.DurationParser(&cr.DurationValidation{
+						GreaterThanOrEqualTo: pointer.Duration(libtime.MustParseDuration("30s")),
+					}),
+				},
+			},
+		},
+	}
+}
+
+func modelCacheValidation() *cr.StructFieldValidation {
+	return &cr.StructFieldValidation{
+		StructField: "ModelCache",
+		StructValidation: &cr.StructValidation{
+			Required:          false,
+			AllowExplicitNull: true,
+			DefaultNil:        true,
+			StructFieldValidations: []*cr.StructFieldValidation{
+				{
+					StructField: "Path",
+					StringPtrValidation: &cr.StringPtrValidation{
+						Default:           nil,
+						AllowExplicitNull: true,
+						Validator:         cr.S3PathValidator,
+					},
+				},
+				{
+					StructField: "MountPath",
+					StringValidation: &cr.StringValidation{
+						Default:   "/mnt/model",
+						Validator: modelCacheMountPathValidator,
+					},
+				},
+			},
+		},
+	}
+}
+
+func prepullImagesValidation() *cr.StructFieldValidation {
+	return &cr.StructFieldValidation{
+		StructField: "PrepullImages",
+		BoolValidation: &cr.BoolValidation{
+			Default: false,
+		},
+	}
+}
+
+func authenticationValidation() *cr.StructFieldValidation {
+	return &cr.StructFieldValidation{
+		StructField: "Authentication",
+		StringValidation: &cr.StringValidation{
+			Default:       userconfig.AuthenticationNone,
+			AllowedValues: []string{userconfig.AuthenticationNone, userconfig.AuthenticationAPIKey, userconfig.AuthenticationAWSIAM},
+		},
+	}
+}
+
+func maxConcurrentJobsValidation() *cr.StructFieldValidation {
+	return &cr.StructFieldValidation{
+		StructField: "MaxConcurrentJobs",
+		Int64PtrValidation: &cr.Int64PtrValidation{
+			Default:           nil,
+			AllowExplicitNull: true,
+			GreaterThan:       pointer.Int64(0),
+		},
+	}
+}
+
+func distributedValidation() *cr.StructFieldValidation {
+	return &cr.StructFieldValidation{
+		StructField: "Distributed",
+		StructValidation: &cr.StructValidation{
+			Required:          false,
+			AllowExplicitNull: true,
+			DefaultNil:        true,
+			StructFieldValidations: []*cr.StructFieldValidation{
+				{
+					StructField: "Workers",
+					Int32Validation: &cr.Int32Validation{
+						Required:             true,
+						GreaterThanOrEqualTo: pointer.Int32(2),
+					},
+				},
+				{
+					StructField: "ProcessesPerWorker",
+					Int32Validation: &cr.Int32Validation{
+						Default:     1,
+						GreaterThan: pointer.Int32(0),
+					},
+				},
+				{
+					StructField: "RendezvousBackend",
+					StringValidation: &cr.StringValidation{
+						Default:       userconfig.RendezvousBackendStatic,
+						AllowedValues: []string{userconfig.RendezvousBackendStatic, userconfig.RendezvousBackendC10d},
+					},
+				},
+				{
+					StructField: "Port",
+					Int32Validation: &cr.Int32Validation{
+						Default:           consts.DefaultDistributedPortInt32,
+						GreaterThan:       pointer.Int32(0),
+						LessThanOrEqualTo: pointer.Int32(65535),
+						DisallowedValues:  consts.ReservedContainerPorts,
+					},
+				},
+			},
+		},
+	}
+}
+
+func awsIAMPrincipalsValidation() *cr.StructFieldValidation {
+	return &cr.StructFieldValidation{
+		StructField: "AWSIAMPrincipals",
+		StringListValidation: &cr.StringListValidation{
+			Required:          false,
+			Default:           nil,
+			AllowExplicitNull: true,
+			AllowEmpty:        true,
+			DisallowDups:      true,
+			Validator: func(principals []string) ([]string, error) {
+				for _, principal := range principals {
+					if !regex.IsValidAWSIAMPrincipal(principal) {
+						return nil, ErrorInvalidAWSIAMPrincipal(principal)
+					}
+				}
+				return principals, nil
+			},
+		},
+	}
+}
+
+func predictionMetricsValidation() *cr.StructFieldValidation {
+	return &cr.StructFieldValidation{
+		StructField: "PredictionMetrics",
+		StructListValidation: &cr.StructListValidation{
+			Required:         false,
+			TreatNullAsEmpty: true,
+			MaxLength:        20,
+			StructValidation: &cr.StructValidation{
+				StructFieldValidations: []*cr.StructFieldValidation{
+					{
+						StructField: "Name",
+						StringValidation: &cr.StringValidation{
+							Required:                   true,
+							AlphaNumericDashUnderscore: true,
+							MaxLength:                  63,
+						},
+					},
+					{
+						StructField: "Buckets",
+						Float64ListValidation: &cr.Float64ListValidation{
+							Default:   []float64{0.1, 0.2, 0.3, 0.4, 0.5, 0.6, 0.7, 0.8, 0.9, 1},
+							MinLength: 1,
+							MaxLength: 50,
+							Validator: func(buckets []float64) ([]float64, error) {
+								for i := 1; i < len(buckets); i++ {
+									if buckets[i] <= buckets[i-1] {
+										return nil, ErrorBucketsNotIncreasing(buckets)
+									}
+								}
+								return buckets, nil
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+var resourceStructValidation = cr.StructValidation{
+	AllowExtraFields:       true,
+	StructFieldValidations: resourceStructValidations,
+}
+
+func ExtractAPIConfigs(configBytes []byte, configFileName string) ([]userconfig.API, error) {
+	var err error
+
+	configData, err := cr.ReadYAMLBytes(configBytes)
+	if err != nil {
+		return nil, errors.Wrap(err, configFileName)
+	}
+
+	configDataSlice, ok := cast.InterfaceToStrInterfaceMapSlice(configData)
+	if !ok {
+		return nil, errors.Wrap(ErrorMalformedConfig(), configFileName)
+	}
+
+	apis := make([]userconfig.API, len(configDataSlice))
+	for i, data := range configDataSlice {
+		api, errs := extractAPIConfig(data, configFileName, i)
+		if errors.HasError(errs) {
+			return nil, errors.Append(errors.FirstError(errs...), fmt.Sprintf("\n\napi configuration schema can be found at https://docs.cortexlabs.com/v/%s/", consts.CortexVersionMinor))
 		}
-		api.SubmittedAPISpec = interfaceMap
-		apis[i] = api
+		apis[i] = *api
 	}
 
 	return apis, nil
 }
 
+// extractAPIConfig parses the i-th resource of the config file, returning all of the schema errors which were found
+func extractAPIConfig(data map[string]interface{}, configFileName string, i int) (*userconfig.API, []error) {
+	name, _ := data[userconfig.NameKey].(string)
+	kindString, _ := data[userconfig.KindKey].(string)
+	identifier := userconfig.IdentifyAPI(configFileName, name, userconfig.KindFromString(kindString), i)
+
+	var resourceStruct userconfig.Resource
+	errs := cr.Struct(&resourceStruct, data, &resourceStructValidation)
+	if errors.HasError(errs) {
+		return nil, errors.WrapAll(errs, identifier)
+	}
+
+	api := userconfig.API{}
+	errs = cr.Struct(&api, data, apiValidation(resourceStruct))
+	if errors.HasError(errs) {
+		return nil, errors.WrapAll(errs, identifier)
+	}
+	api.Index = i
+	api.FileName = configFileName
+
+	interfaceMap, ok := cast.JSONMarshallable(data)
+	if !ok {
+		return nil, []error{errors.ErrorUnexpected("unable to cast api spec to json")} // unexpected
+	}
+	api.SubmittedAPISpec = interfaceMap
+
+	return &api, nil
+}
+
 func ValidateAPI(
 	api *userconfig.API,
 	awsClient *aws.Client,
@@ -688,6 +1552,12 @@ func ValidateAPI(
 		api.Networking.Endpoint = pointer.String("/" + api.Name)
 	}
 
+	if api.Networking.CustomDomain != nil {
+		if err := validateCustomDomain(api); err != nil {
+			return errors.Wrap(err, userconfig.NetworkingKey, userconfig.CustomDomainKey)
+		}
+	}
+
 	if api.Pod != nil {
 		if err := validatePod(api, awsClient, k8sClient); err != nil {
 			return errors.Wrap(err, userconfig.PodKey)
@@ -706,6 +1576,56 @@ func ValidateAPI(
 		}
 	}
 
+	if api.Canary != nil && api.Autoscaling != nil && api.Autoscaling.MinReplicas == 0 {
+		return ErrorCanaryRequiresMinReplicas()
+	}
+
+	if api.Pod != nil && api.Pod.Protocol == userconfig.ProtocolGRPC {
+		if err := validateGRPC(api); err != nil {
+			return err
+		}
+	}
+
+	if api.Authentication == userconfig.AuthenticationAWSIAM && len(api.AWSIAMPrincipals) == 0 {
+		return ErrorFieldMustBeSpecifiedForAuthentication(userconfig.AWSIAMPrincipalsKey, userconfig.AuthenticationAWSIAM)
+	}
+	if api.Authentication != userconfig.AuthenticationAWSIAM && len(api.AWSIAMPrincipals) > 0 {
+		return ErrorFieldRequiresAuthentication(userconfig.AWSIAMPrincipalsKey, userconfig.AuthenticationAWSIAM)
+	}
+
+	if api.RequestLogging != nil {
+		if err := validateRequestLogging(api.RequestLogging); err != nil {
+			return errors.Wrap(err, userconfig.RequestLoggingKey)
+		}
+	}
+
+	if api.ModelWatch != nil {
+		if err := validateModelWatch(api.ModelWatch, awsClient); err != nil {
+			return errors.Wrap(err, userconfig.ModelWatchKey)
+		}
+	}
+
+	if api.ModelCache != nil {
+		numSpecified := 0
+		if api.ModelCache.Path != nil {
+			numSpecified++
+		}
+		if api.ModelWatch != nil {
+			numSpecified++
+		}
+		if numSpecified != 1 {
+			return ErrorSpecifyExactlyOneField(numSpecified, userconfig.ModelCacheKey+"."+userconfig.PathKey, userconfig.ModelWatchKey)
+		}
+	}
+
+	predictionMetricNames := strset.New()
+	for _, predictionMetric := range api.PredictionMetrics {
+		if predictionMetricNames.Has(predictionMetric.Name) {
+			return errors.Wrap(ErrorDuplicatePredictionMetricName(predictionMetric.Name), userconfig.PredictionMetricsKey)
+		}
+		predictionMetricNames.Add(predictionMetric.Name)
+	}
+
 	return nil
 }
 
@@ -713,6 +1633,9 @@ func ValidateTrafficSplitter(api *userconfig.API) error {
 	if api.Networking.Endpoint == nil {
 		api.Networking.Endpoint = pointer.String("/" + api.Name)
 	}
+	if api.Networking.CustomDomain != nil {
+		return errors.Wrap(ErrorFieldIsNotSupportedForKind(userconfig.CustomDomainKey, api.Kind), userconfig.NetworkingKey)
+	}
 	if err := verifyTotalWeight(api.APIs); err != nil {
 		return err
 	}
@@ -733,6 +1656,41 @@ func ValidateTrafficSplitter(api *userconfig.API) error {
 	return nil
 }
 
+func validateCustomDomain(api *userconfig.API) error {
+	if api.Kind != userconfig.RealtimeAPIKind && api.Kind != userconfig.AsyncAPIKind {
+		return ErrorFieldIsNotSupportedForKind(userconfig.CustomDomainKey, api.Kind)
+	}
+
+	return nil
+}
+
+func validateRequestLogging(requestLogging *userconfig.RequestLogging) error {
+	numSpecified := 0
+	if requestLogging.S3Path != nil {
+		numSpecified++
+	}
+	if requestLogging.KinesisStream != nil {
+		numSpecified++
+	}
+	if numSpecified != 1 {
+		return ErrorSpecifyExactlyOneField(numSpecified, userconfig.S3PathKey, userconfig.KinesisStreamKey)
+	}
+
+	return nil
+}
+
+func validateModelWatch(modelWatch *userconfig.ModelWatch, awsClient *aws.Client) error {
+	if awsClient == nil {
+		return nil
+	}
+
+	if _, err := LatestModelVersion(modelWatch.Path, awsClient); err != nil {
+		return errors.Wrap(err, userconfig.PathKey)
+	}
+
+	return nil
+}
+
 func validatePod(
 	api *userconfig.API,
 	awsClient *aws.Client,
@@ -746,6 +1704,10 @@ func validatePod(
 		api.Pod.Port = pointer.Int32(consts.DefaultUserPodPortInt32)
 	}
 
+	if api.Pod.MaxConnections != nil && *api.Pod.MaxConnections < api.Pod.MaxConcurrency {
+		return ErrorMaxConnectionsLessThanMaxConcurrency(*api.Pod.MaxConnections, api.Pod.MaxConcurrency)
+	}
+
 	if err := validateCompute(api); err != nil {
 		return errors.Wrap(err, userconfig.ComputeKey)
 	}
@@ -754,9 +1716,132 @@ func validatePod(
 		return errors.Wrap(err, userconfig.ContainersKey)
 	}
 
+	if api.Pod.RegistryCredentials != nil {
+		if err := validateRegistryCredentials(api.Pod.RegistryCredentials, api.Pod.Containers, awsClient, k8sClient); err != nil {
+			return errors.Wrap(err, userconfig.RegistryCredentialsKey)
+		}
+	}
+
+	if api.Pod.Security != nil && api.Pod.Security.RunAsNonRoot && api.Pod.Security.RunAsUser != nil && *api.Pod.Security.RunAsUser == 0 {
+		return errors.Wrap(ErrorRunAsNonRootWithRootUser(), userconfig.SecurityKey)
+	}
+
 	return nil
 }
 
+func validateRegistryCredentials(
+	registryCredentials *userconfig.RegistryCredentials,
+	containers []*userconfig.Container,
+	awsClient *aws.Client,
+	k8sClient *k8s.Client,
+) error {
+	numSpecified := 0
+	if registryCredentials.Secret != nil {
+		numSpecified++
+	}
+	if registryCredentials.SecretsManagerARN != nil {
+		numSpecified++
+	}
+	if registryCredentials.ECRRoleARN != nil {
+		numSpecified++
+	}
+	if registryCredentials.RegistryAuth != nil {
+		numSpecified++
+	}
+	if numSpecified != 1 {
+		return ErrorSpecifyExactlyOneField(numSpecified, userconfig.SecretKey, userconfig.SecretsManagerARNKey, userconfig.ECRRoleARNKey, userconfig.RegistryAuthKey)
+	}
+
+	if registryCredentials.ECRRoleARN != nil && len(ECRImages(containers)) == 0 {
+		return errors.Wrap(ErrorNoECRImagesForRole(), userconfig.ECRRoleARNKey)
+	}
+
+	// the credentials can only be resolved from within the cluster (and secrets which were created with `cortex secrets set` are resolved by the operator)
+	if awsClient == nil || k8sClient == nil || registryCredentials.RegistryAuth != nil {
+		return nil
+	}
+
+	dockerConfig, err := GetRegistryDockerConfig(registryCredentials, containers, awsClient, k8sClient)
+	if err != nil {
+		return err
+	}
+
+	for i, container := range containers {
+		// ECR credentials are only verified when they were obtained by assuming the role
+		if regex.IsValidECRURL(container.Image) && registryCredentials.ECRRoleARN == nil {
+			continue
+		}
+
+		username, password, ok := dockerConfig.CredentialsForImage(container.Image)
+		if !ok {
+			continue
+		}
+
+		if err := docker.CheckImagePullable(container.Image, username, password); err != nil {
+			return errors.Wrap(err, userconfig.ContainersKey, s.Index(i), userconfig.ImageKey)
+		}
+	}
+
+	return nil
+}
+
+// GetRegistryDockerConfig resolves the api's registry credentials into a docker config (in the format of a kubernetes.io/dockerconfigjson secret)
+// Credentials which reference a secret that was created with `cortex secrets set` must be resolved by the operator instead
+func GetRegistryDockerConfig(
+	registryCredentials *userconfig.RegistryCredentials,
+	containers []*userconfig.Container,
+	awsClient *aws.Client,
+	k8sClient *k8s.Client,
+) (*docker.RegistryConfig, error) {
+	if registryCredentials.RegistryAuth != nil {
+		return nil, errors.ErrorUnexpected("registry credentials which reference a cortex secret must be resolved by the operator")
+	}
+
+	if registryCredentials.ECRRoleARN != nil {
+		dockerConfig, err := GetECRRegistryDockerConfig(*registryCredentials.ECRRoleARN, ECRImages(containers), awsClient)
+		if err != nil {
+			return nil, errors.Wrap(err, userconfig.ECRRoleARNKey)
+		}
+		return dockerConfig, nil
+	}
+
+	if registryCredentials.SecretsManagerARN != nil {
+		secretStr, err := awsClient.GetSecretString(*registryCredentials.SecretsManagerARN)
+		if err != nil {
+			if aws.IsErrCode(err, "AccessDeniedException") {
+				return nil, errors.Wrap(ErrorRegistrySecretAccessDenied(*registryCredentials.SecretsManagerARN), userconfig.SecretsManagerARNKey)
+			}
+			return nil, errors.Wrap(err, userconfig.SecretsManagerARNKey)
+		}
+
+		dockerConfig, err := docker.ParseRegistrySecret([]byte(secretStr))
+		if err != nil {
+			return nil, errors.Wrap(err, userconfig.SecretsManagerARNKey)
+		}
+		return dockerConfig, nil
+	}
+
+	secretData, err := k8sClient.GetSecretData(*registryCredentials.Secret)
+	if err != nil {
+		return nil, errors.Wrap(err, userconfig.SecretKey)
+	}
+	if secretData == nil {
+		return nil, errors.Wrap(ErrorRegistrySecretNotFound(*registryCredentials.Secret), userconfig.SecretKey)
+	}
+
+	authData, ok := secretData[".dockerconfigjson"]
+	if !ok {
+		return nil, errors.Wrap(ErrorUnexpectedRegistrySecretData(*registryCredentials.Secret, "should contain \".dockerconfigjson\" key"), userconfig.SecretKey)
+	}
+
+	dockerConfig, err := docker.ParseRegistrySecret(authData)
+	if err != nil {
+		return nil, errors.Wrap(ErrorUnexpectedRegistrySecretData(*registryCredentials.Secret, errors.Message(err)), userconfig.SecretKey)
+	}
+
+	return dockerConfig, nil
+}
+
 func validateContainers(
 	containers []*userconfig.Container,
 	kind userconfig.Kind,
@@ -786,6 +1871,18 @@ func validateContainers(
 			}
 		}
 
+		for key, secretName := range container.EnvFromSecrets {
+			if strings.HasPrefix(key, "CORTEX_") || strings.HasPrefix(key, "KUBEXIT_") {
+				return errors.Wrap(ErrorCortexPrefixedEnvVarNotAllowed("CORTEX_", "KUBEXIT_"), s.Index(i), userconfig.EnvFromSecretsKey, key)
+			}
+			if _, ok := container.Env[key]; ok {
+				return errors.Wrap(ErrorEnvVarAlsoSetFromSecret(key), s.Index(i), userconfig.EnvFromSecretsKey, key)
+			}
+			if err := userconfig.ValidateSecretName(secretName); err != nil {
+				return errors.Wrap(err, s.Index(i), userconfig.EnvFromSecretsKey, key)
+			}
+		}
+
 		if kind == userconfig.TaskAPIKind && container.ReadinessProbe != nil {
 			return errors.Wrap(ErrorFieldIsNotSupportedForKind(userconfig.ReadinessProbeKey, kind), s.Index(i), userconfig.ReadinessProbeKey)
 		}
@@ -858,10 +1955,59 @@ func validatePreStop(preStop userconfig.PreStop) error {
 	return nil
 }
 
+// grpc requests are proxied over http/2, so features which rely on sending or inspecting http/1.1 requests aren't supported
+func validateGRPC(api *userconfig.API) error {
+	if api.Autoscaling != nil && api.Autoscaling.MinReplicas == 0 {
+		return ErrorGRPCRequiresMinReplicas()
+	}
+	if api.Pod.Warmup != nil {
+		return errors.Wrap(ErrorFieldIsNotSupportedForGRPC(userconfig.WarmupKey), userconfig.PodKey)
+	}
+	if api.WebSocket != nil {
+		return ErrorFieldIsNotSupportedForGRPC(userconfig.WebSocketKey)
+	}
+	if api.RequestLogging != nil {
+		return ErrorFieldIsNotSupportedForGRPC(userconfig.RequestLoggingKey)
+	}
+	return nil
+}
+
 func validateAutoscaling(api *userconfig.API) error {
 	autoscaling := api.Autoscaling
 	pod := api.Pod
 
+	if autoscaling.TargetGPUUtilization != nil || autoscaling.CustomMetric != nil {
+		if api.Kind != userconfig.RealtimeAPIKind {
+			if autoscaling.TargetGPUUtilization != nil {
+				return ErrorFieldIsNotSupportedForKind(userconfig.TargetGPUUtilizationKey, api.Kind)
+			}
+			return ErrorFieldIsNotSupportedForKind(userconfig.CustomMetricKey, api.Kind)
+		}
+
+		numSpecified := 0
+		for _, isSpecified := range []bool{autoscaling.TargetInFlight != nil, autoscaling.TargetGPUUtilization != nil, autoscaling.CustomMetric != nil} {
+			if isSpecified {
+				numSpecified++
+			}
+		}
+		if numSpecified > 1 {
+			return ErrorSpecifyExactlyOneField(numSpecified, userconfig.TargetInFlightKey, userconfig.TargetGPUUtilizationKey, userconfig.CustomMetricKey)
+		}
+
+		if autoscaling.TargetGPUUtilization != nil && userconfig.GetPodComputeRequest(api).GPU == 0 {
+			return ErrorTargetGPUUtilizationWithoutGPU()
+		}
+	}
+
+	if autoscaling.MaxQueueWait != nil {
+		if api.Kind != userconfig.RealtimeAPIKind {
+			return ErrorFieldIsNotSupportedForKind(userconfig.MaxQueueWaitKey, api.Kind)
+		}
+		if autoscaling.MinReplicas != 0 {
+			return ErrorMaxQueueWaitRequiresScaleToZero(autoscaling.MinReplicas)
+		}
+	}
+
 	if api.Kind == userconfig.RealtimeAPIKind {
 		if autoscaling.TargetInFlight == nil {
 			autoscaling.TargetInFlight = pointer.Float64(float64(pod.MaxConcurrency))
@@ -899,6 +2045,15 @@ func validateCompute(api *userconfig.API) error {
 		return ErrorComputeResourceConflict(userconfig.GPUKey, userconfig.InfKey)
 	}
 
+	if compute.GPU > 0 && compute.NeuronCores > 0 {
+		return ErrorComputeResourceConflict(userconfig.GPUKey, userconfig.NeuronCoresKey)
+	}
+
+	// the neuron device plugin allocates either whole devices or individual cores to a pod, but not both
+	if compute.Inf > 0 && compute.NeuronCores > 0 {
+		return ErrorComputeResourceConflict(userconfig.InfKey, userconfig.NeuronCoresKey)
+	}
+
 	return nil
 }
 
@@ -910,6 +2065,56 @@ func validateUpdateStrategy(updateStrategy *userconfig.UpdateStrategy) error {
 	return nil
 }
 
+// GetECRRegistryDockerConfig assumes the role to obtain auth tokens for the ECR registries which host the images (which may belong to other AWS accounts)
+// ECR auth tokens expire after 12 hours
+func GetECRRegistryDockerConfig(roleARN string, images []string, awsClient *aws.Client) (*docker.RegistryConfig, error) {
+	registryIDsByRegion := map[string]strset.Set{}
+	for _, image := range images {
+		region := aws.GetRegionFromECRURL(image)
+		if _, ok := registryIDsByRegion[region]; !ok {
+			registryIDsByRegion[region] = strset.New()
+		}
+		registryIDsByRegion[region].Add(aws.GetAccountIDFromECRURL(image))
+	}
+
+	dockerConfig := &docker.RegistryConfig{
+		Auths: map[string]docker.RegistryAuth{},
+	}
+
+	for region, registryIDs := range registryIDsByRegion {
+		assumedRoleClient, err := awsClient.AssumeRole(roleARN, _ecrRoleSessionName, region)
+		if err != nil {
+			return nil, err
+		}
+
+		ecrAuthConfigs, err := assumedRoleClient.GetECRAuthConfigsForRegistries(registryIDs.SliceSorted()...)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, ecrAuthConfig := range ecrAuthConfigs {
+			dockerConfig.Auths[ecrAuthConfig.ProxyEndpoint] = docker.RegistryAuth{
+				Username: ecrAuthConfig.Username,
+				Password: ecrAuthConfig.AccessToken,
+				Auth:     base64.StdEncoding.EncodeToString([]byte(ecrAuthConfig.Username + ":" + ecrAuthConfig.AccessToken)),
+			}
+		}
+	}
+
+	return dockerConfig, nil
+}
+
+// ECRImages returns the containers' images which are hosted on ECR
+func ECRImages(containers []*userconfig.Container) []string {
+	var images []string
+	for _, container := range containers {
+		if regex.IsValidECRURL(container.Image) {
+			images = append(images, container.Image)
+		}
+	}
+	return images
+}
+
 func validateDockerImagePath(
 	image string,
 	awsClient *aws.Client,
@@ -0,0 +1,79 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+// BatchCode is an enum to represent the state of a batch in a batch job
+type BatchCode int
+
+// Possible values for BatchCode
+const (
+	BatchPending BatchCode = iota
+	BatchSucceeded
+	BatchFailed
+	BatchUnknown
+)
+
+var _batchCodes = []string{
+	"pending",
+	"succeeded",
+	"failed",
+	"unknown",
+}
+
+var _ = [1]int{}[int(BatchUnknown)-(len(_batchCodes)-1)] // Ensure list length matches
+
+func BatchCodeFromString(str string) BatchCode {
+	for i := 0; i < len(_batchCodes); i++ {
+		if str == _batchCodes[i] {
+			return BatchCode(i)
+		}
+	}
+	return BatchUnknown
+}
+
+func (code BatchCode) IsCompleted() bool {
+	return code == BatchSucceeded || code == BatchFailed
+}
+
+func (code BatchCode) String() string {
+	if int(code) < 0 || int(code) >= len(_batchCodes) {
+		return _batchCodes[BatchUnknown]
+	}
+	return _batchCodes[code]
+}
+
+// MarshalText satisfies TextMarshaler
+func (code BatchCode) MarshalText() ([]byte, error) {
+	return []byte(code.String()), nil
+}
+
+// UnmarshalText satisfies TextUnmarshaler
+func (code *BatchCode) UnmarshalText(text []byte) error {
+	*code = BatchCodeFromString(string(text))
+	return nil
+}
+
+// UnmarshalBinary satisfies BinaryUnmarshaler
+// Needed for msgpack
+func (code *BatchCode) UnmarshalBinary(data []byte) error {
+	return code.UnmarshalText(data)
+}
+
+// MarshalBinary satisfies BinaryMarshaler
+func (code BatchCode) MarshalBinary() ([]byte, error) {
+	return []byte(code.String()), nil
+}
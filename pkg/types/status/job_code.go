@@ -23,6 +23,8 @@ type JobCode int
 // Possible values for JobCode
 const (
 	JobPending JobCode = iota // pending should be the first status in this list
+	JobPendingDependencies
+	JobQueued
 	JobEnqueuing
 	JobRunning
 	JobEnqueueFailed
@@ -33,11 +35,14 @@ const (
 	JobWorkerOOM
 	JobTimedOut
 	JobStopped
+	JobDependencyFailed
 	JobUnknown
 )
 
 var _jobCodes = []string{
 	"pending",
+	"pending_dependencies",
+	"queued",
 	"enqueuing",
 	"running",
 	"enqueue_failed",
@@ -48,6 +53,7 @@ var _jobCodes = []string{
 	"worker_oom",
 	"timed_out",
 	"stopped",
+	"dependency_failed",
 	"unknown",
 }
 
@@ -55,6 +61,8 @@ var _ = [1]int{}[int(JobUnknown)-(len(_jobCodes)-1)] // Ensure list length match
 
 var _jobCodeMessages = []string{
 	"pending",
+	"waiting for dependencies",
+	"queued",
 	"enqueuing",
 	"running",
 	"failed while enqueuing",
@@ -65,13 +73,14 @@ var _jobCodeMessages = []string{
 	"out of memory",
 	"timed out",
 	"stopped",
+	"dependency failed",
 	"unknown",
 }
 
 var _ = [1]int{}[int(JobUnknown)-(len(_jobCodeMessages)-1)] // Ensure list length matches
 
 func (code JobCode) IsNotStarted() bool {
-	return code == JobPending || code == JobEnqueuing
+	return code == JobPending || code == JobPendingDependencies || code == JobQueued || code == JobEnqueuing
 }
 
 func (code JobCode) IsInProgress() bool {
@@ -82,7 +91,8 @@ func (code JobCode) IsCompleted() bool {
 	return code == JobEnqueueFailed || code == JobCompletedWithFailures ||
 		code == JobSucceeded || code == JobUnexpectedError ||
 		code == JobWorkerError || code == JobWorkerOOM ||
-		code == JobStopped || code == JobTimedOut
+		code == JobStopped || code == JobTimedOut ||
+		code == JobDependencyFailed
 }
 
 func (code JobCode) String() string {
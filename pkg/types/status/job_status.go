@@ -24,10 +24,16 @@ import (
 
 type BatchJobStatus struct {
 	spec.BatchJob
-	Status         JobCode       `json:"status" yaml:"status"`
-	EndTime        *time.Time    `json:"end_time,omitempty" yaml:"end_time,omitempty"`
-	BatchesInQueue int           `json:"batches_in_queue" yaml:"batches_in_queue"`
-	WorkerCounts   *WorkerCounts `json:"worker_counts,omitempty" yaml:"worker_counts,omitempty"`
+	Status         JobCode        `json:"status" yaml:"status"`
+	EndTime        *time.Time     `json:"end_time,omitempty" yaml:"end_time,omitempty"`
+	BatchesInQueue int            `json:"batches_in_queue" yaml:"batches_in_queue"`
+	WorkerCounts   *WorkerCounts  `json:"worker_counts,omitempty" yaml:"worker_counts,omitempty"`
+	BatchProgress  *BatchProgress `json:"batch_progress,omitempty" yaml:"batch_progress,omitempty"`
+}
+
+type BatchProgress struct {
+	CompletionPercentage float64     `json:"completion_percentage" yaml:"completion_percentage"`
+	BatchStates          []BatchCode `json:"batch_states" yaml:"batch_states"` // indexed by batch index
 }
 
 type TaskJobStatus struct {
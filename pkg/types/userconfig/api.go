@@ -23,8 +23,12 @@ import (
 
 	"github.com/PEAT-AI/yaml"
 	"github.com/cortexlabs/cortex/pkg/consts"
+	cr "github.com/cortexlabs/cortex/pkg/lib/configreader"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
 	"github.com/cortexlabs/cortex/pkg/lib/k8s"
+	"github.com/cortexlabs/cortex/pkg/lib/maps"
 	"github.com/cortexlabs/cortex/pkg/lib/pointer"
+	"github.com/cortexlabs/cortex/pkg/lib/regex"
 	"github.com/cortexlabs/cortex/pkg/lib/sets/strset"
 	s "github.com/cortexlabs/cortex/pkg/lib/strings"
 	"github.com/cortexlabs/cortex/pkg/lib/urls"
@@ -35,22 +39,87 @@ import (
 type API struct {
 	Resource
 
-	Pod              *Pod            `json:"pod" yaml:"pod"`
-	NodeGroups       []string        `json:"node_groups" yaml:"node_groups"`
-	APIs             []*TrafficSplit `json:"apis" yaml:"apis"`
-	Networking       *Networking     `json:"networking" yaml:"networking"`
-	Autoscaling      *Autoscaling    `json:"autoscaling" yaml:"autoscaling"`
-	UpdateStrategy   *UpdateStrategy `json:"update_strategy" yaml:"update_strategy"`
-	Index            int             `json:"index" yaml:"-"`
-	FileName         string          `json:"file_name" yaml:"-"`
-	SubmittedAPISpec interface{}     `json:"submitted_api_spec" yaml:"submitted_api_spec"`
+	Labels            map[string]string   `json:"labels" yaml:"labels"`
+	Project           string              `json:"project" yaml:"project"`
+	Pod               *Pod                `json:"pod" yaml:"pod"`
+	NodeGroups        []string            `json:"node_groups" yaml:"node_groups"`
+	APIs              []*TrafficSplit     `json:"apis" yaml:"apis"`
+	Networking        *Networking         `json:"networking" yaml:"networking"`
+	Autoscaling       *Autoscaling        `json:"autoscaling" yaml:"autoscaling"`
+	UpdateStrategy    *UpdateStrategy     `json:"update_strategy" yaml:"update_strategy"`
+	Canary            *Canary             `json:"canary" yaml:"canary"`
+	WarmPool          *WarmPool           `json:"warm_pool" yaml:"warm_pool"`
+	Async             *Async              `json:"async" yaml:"async"`
+	RequestLogging    *RequestLogging     `json:"request_logging" yaml:"request_logging"`
+	PredictionMetrics []*PredictionMetric `json:"prediction_metrics" yaml:"prediction_metrics"`
+	RateLimit         *RateLimit          `json:"rate_limit" yaml:"rate_limit"`
+	WebSocket         *WebSocket          `json:"websocket" yaml:"websocket"`
+	ModelWatch        *ModelWatch         `json:"model_watch" yaml:"model_watch"`
+	ModelCache        *ModelCache         `json:"model_cache" yaml:"model_cache"`
+	PrepullImages     bool                `json:"prepull_images" yaml:"prepull_images"`
+	Authentication    string              `json:"authentication" yaml:"authentication"`
+	AWSIAMPrincipals  []string            `json:"aws_iam_principals" yaml:"aws_iam_principals"`
+	MaxConcurrentJobs *int64              `json:"max_concurrent_jobs" yaml:"max_concurrent_jobs"` // batch and task apis only; additional jobs are queued
+	Distributed       *Distributed        `json:"distributed" yaml:"distributed"`                 // task apis only
+	Index             int                 `json:"index" yaml:"-"`
+	FileName          string              `json:"file_name" yaml:"-"`
+	SubmittedAPISpec  interface{}         `json:"submitted_api_spec" yaml:"submitted_api_spec"`
+	GitSource         *GitSource          `json:"git_source,omitempty" yaml:"-"` // set if the api was deployed from a git reference
+}
+
+// GitSource identifies the git commit which an api's configuration was deployed from
+type GitSource struct {
+	Repository string `json:"repository"`
+	Path       string `json:"path"` // path of the configuration file within the repository
+	Ref        string `json:"ref,omitempty"`
+	Commit     string `json:"commit"`
 }
 
 type Pod struct {
-	Port           *int32       `json:"port" yaml:"port"`
-	MaxQueueLength int64        `json:"max_queue_length" yaml:"max_queue_length"`
-	MaxConcurrency int64        `json:"max_concurrency" yaml:"max_concurrency"`
-	Containers     []*Container `json:"containers" yaml:"containers"`
+	Port                *int32               `json:"port" yaml:"port"`
+	Protocol            string               `json:"protocol" yaml:"protocol"`
+	MaxQueueLength      int64                `json:"max_queue_length" yaml:"max_queue_length"`
+	MaxConcurrency      int64                `json:"max_concurrency" yaml:"max_concurrency"`
+	MaxRequestBodySize  *k8s.Quantity        `json:"max_request_body_size" yaml:"max_request_body_size"`
+	RequestTimeout      *time.Duration       `json:"request_timeout" yaml:"request_timeout"`
+	MaxConnections      *int64               `json:"max_connections" yaml:"max_connections"`
+	RegistryCredentials *RegistryCredentials `json:"registry_credentials" yaml:"registry_credentials"`
+	IAMRoleARN          *string              `json:"iam_role_arn" yaml:"iam_role_arn"` // assumed by the api's containers via IRSA (IAM roles for service accounts)
+	Security            *PodSecurity         `json:"security" yaml:"security"`         // if nil, the defaults apply (the user containers are not privileged)
+	Warmup              *Warmup              `json:"warmup" yaml:"warmup"`
+	Containers          []*Container         `json:"containers" yaml:"containers"`
+}
+
+type Warmup struct {
+	Path           string            `json:"path" yaml:"path"`
+	Method         string            `json:"method" yaml:"method"`
+	Payload        *string           `json:"payload" yaml:"payload"`
+	Headers        map[string]string `json:"headers" yaml:"headers"`
+	NumRequests    int64             `json:"num_requests" yaml:"num_requests"`
+	TimeoutSeconds int64             `json:"timeout_seconds" yaml:"timeout_seconds"`
+}
+
+// PodSecurity is applied to the security context of each of the api's user containers
+type PodSecurity struct {
+	Privileged       bool     `json:"privileged" yaml:"privileged"`
+	ReadOnlyRootFS   bool     `json:"read_only_root_fs" yaml:"read_only_root_fs"`
+	RunAsNonRoot     bool     `json:"run_as_non_root" yaml:"run_as_non_root"`
+	RunAsUser        *int64   `json:"run_as_user" yaml:"run_as_user"`
+	DropCapabilities []string `json:"drop_capabilities" yaml:"drop_capabilities"`
+	SeccompProfile   string   `json:"seccomp_profile" yaml:"seccomp_profile"`
+}
+
+const (
+	SeccompProfileRuntimeDefault  = "runtime_default"
+	SeccompProfileUnconfined      = "unconfined"
+	SeccompProfileLocalhostPrefix = "localhost/"
+)
+
+type RegistryCredentials struct {
+	Secret            *string `json:"secret" yaml:"secret"`
+	SecretsManagerARN *string `json:"secrets_manager_arn" yaml:"secrets_manager_arn"`
+	ECRRoleARN        *string `json:"ecr_role_arn" yaml:"ecr_role_arn"`
+	RegistryAuth      *string `json:"registry_auth" yaml:"registry_auth"` // the name of a secret which was created with `cortex secrets set`
 }
 
 type Container struct {
@@ -58,6 +127,9 @@ type Container struct {
 	Image string            `json:"image" yaml:"image"`
 	Env   map[string]string `json:"env" yaml:"env"`
 
+	// maps environment variable names to the names of secrets which were created with `cortex secrets set`
+	EnvFromSecrets map[string]string `json:"env_from_secrets" yaml:"env_from_secrets"`
+
 	Command []string `json:"command" yaml:"command"`
 	Args    []string `json:"args" yaml:"args"`
 
@@ -66,6 +138,8 @@ type Container struct {
 	PreStop        *PreStop `json:"pre_stop" yaml:"pre_stop"`
 
 	Compute *Compute `json:"compute" yaml:"compute"`
+
+	ImageDigest string `json:"image_digest,omitempty" yaml:"-"` // the digest of the image's manifest, which is resolved when the api is deployed
 }
 
 type TrafficSplit struct {
@@ -75,9 +149,42 @@ type TrafficSplit struct {
 }
 
 type Networking struct {
-	Endpoint *string `json:"endpoint" yaml:"endpoint"`
+	Endpoint           *string `json:"endpoint" yaml:"endpoint"`
+	EndpointVisibility *string `json:"endpoint_visibility" yaml:"endpoint_visibility"`
+	CustomDomain       *string `json:"custom_domain" yaml:"custom_domain"`
+	Egress             *Egress `json:"egress" yaml:"egress"`
+}
+
+// Egress restricts the outbound traffic of the api's pods; all other destinations are denied
+type Egress struct {
+	AllowedCIDRs   []string `json:"allowed_cidrs" yaml:"allowed_cidrs"`
+	AllowedDomains []string `json:"allowed_domains" yaml:"allowed_domains"`
 }
 
+const (
+	EndpointVisibilityPublic   = "public"
+	EndpointVisibilityInternal = "internal"
+)
+
+// ReservedProjects are the namespaces of the cluster's own components, which can't be used as projects (since each project's apis are deployed to the namespace with the project's name)
+var ReservedProjects = strset.New(
+	consts.KubeSystemNamespace,
+	consts.IstioNamespace,
+	consts.PrometheusNamespace,
+	consts.LoggingNamespace,
+	"kube-public",
+	"kube-node-lease",
+)
+
+const (
+	// DefaultProject is the project of the apis which don't specify one
+	DefaultProject = "default"
+	// ProjectLabelKey is set on the virtual service of each api (and on the deployments of realtime and async apis), and on the namespace of each project
+	ProjectLabelKey = "cortex.dev/project"
+	// CortexAPIUIDLabelKey is set on the virtual service of each api which is deployed by a CortexAPI resource, and holds the uid of that resource
+	CortexAPIUIDLabelKey = "cortex.dev/cortex-api-uid"
+)
+
 type Probe struct {
 	HTTPGet             *HTTPGetHandler   `json:"http_get" yaml:"http_get"`
 	TCPSocket           *TCPSocketHandler `json:"tcp_socket" yaml:"tcp_socket"`
@@ -108,25 +215,36 @@ type ExecHandler struct {
 }
 
 type Compute struct {
-	CPU *k8s.Quantity `json:"cpu" yaml:"cpu"`
-	Mem *k8s.Quantity `json:"mem" yaml:"mem"`
-	GPU int64         `json:"gpu" yaml:"gpu"`
-	Inf int64         `json:"inf" yaml:"inf"`
-	Shm *k8s.Quantity `json:"shm" yaml:"shm"`
+	CPU         *k8s.Quantity `json:"cpu" yaml:"cpu"`
+	Mem         *k8s.Quantity `json:"mem" yaml:"mem"`
+	GPU         int64         `json:"gpu" yaml:"gpu"`
+	Inf         int64         `json:"inf" yaml:"inf"`
+	NeuronCores int64         `json:"neuron_cores" yaml:"neuron_cores"`
+	Shm         *k8s.Quantity `json:"shm" yaml:"shm"`
 }
 
 type Autoscaling struct {
-	MinReplicas                  int32         `json:"min_replicas" yaml:"min_replicas"`
-	MaxReplicas                  int32         `json:"max_replicas" yaml:"max_replicas"`
-	InitReplicas                 int32         `json:"init_replicas" yaml:"init_replicas"`
-	TargetInFlight               *float64      `json:"target_in_flight" yaml:"target_in_flight"`
-	Window                       time.Duration `json:"window" yaml:"window"`
-	DownscaleStabilizationPeriod time.Duration `json:"downscale_stabilization_period" yaml:"downscale_stabilization_period"`
-	UpscaleStabilizationPeriod   time.Duration `json:"upscale_stabilization_period" yaml:"upscale_stabilization_period"`
-	MaxDownscaleFactor           float64       `json:"max_downscale_factor" yaml:"max_downscale_factor"`
-	MaxUpscaleFactor             float64       `json:"max_upscale_factor" yaml:"max_upscale_factor"`
-	DownscaleTolerance           float64       `json:"downscale_tolerance" yaml:"downscale_tolerance"`
-	UpscaleTolerance             float64       `json:"upscale_tolerance" yaml:"upscale_tolerance"`
+	MinReplicas                  int32          `json:"min_replicas" yaml:"min_replicas"`
+	MaxReplicas                  int32          `json:"max_replicas" yaml:"max_replicas"`
+	InitReplicas                 int32          `json:"init_replicas" yaml:"init_replicas"`
+	TargetInFlight               *float64       `json:"target_in_flight" yaml:"target_in_flight"`
+	TargetGPUUtilization         *float64       `json:"target_gpu_utilization" yaml:"target_gpu_utilization"`
+	CustomMetric                 *CustomMetric  `json:"custom_metric" yaml:"custom_metric"`
+	MaxQueueWait                 *time.Duration `json:"max_queue_wait" yaml:"max_queue_wait"`
+	Window                       time.Duration  `json:"window" yaml:"window"`
+	DownscaleStabilizationPeriod time.Duration  `json:"downscale_stabilization_period" yaml:"downscale_stabilization_period"`
+	UpscaleStabilizationPeriod   time.Duration  `json:"upscale_stabilization_period" yaml:"upscale_stabilization_period"`
+	MaxDownscaleFactor           float64        `json:"max_downscale_factor" yaml:"max_downscale_factor"`
+	MaxUpscaleFactor             float64        `json:"max_upscale_factor" yaml:"max_upscale_factor"`
+	DownscaleTolerance           float64        `json:"downscale_tolerance" yaml:"downscale_tolerance"`
+	UpscaleTolerance             float64        `json:"upscale_tolerance" yaml:"upscale_tolerance"`
+}
+
+// CustomMetric is a prometheus query whose result is the total load of an API,
+// which the autoscaler divides by Target to compute the number of replicas
+type CustomMetric struct {
+	Query  string  `json:"query" yaml:"query"`
+	Target float64 `json:"target" yaml:"target"`
 }
 
 type UpdateStrategy struct {
@@ -134,6 +252,80 @@ type UpdateStrategy struct {
 	MaxUnavailable string `json:"max_unavailable" yaml:"max_unavailable"`
 }
 
+type Canary struct {
+	Weight int32 `json:"weight" yaml:"weight"`
+}
+
+// WarmPool replicas are kept running (but don't receive traffic) so that they can serve traffic immediately when the API scales up
+type WarmPool struct {
+	Replicas int32 `json:"replicas" yaml:"replicas"`
+}
+
+type Async struct {
+	ResultTTL         *time.Duration `json:"result_ttl" yaml:"result_ttl"`
+	IdempotencyWindow time.Duration  `json:"idempotency_window" yaml:"idempotency_window"`
+	OutputPath        *string        `json:"output_path" yaml:"output_path"`
+	MaxReceiveCount   *int64         `json:"max_receive_count" yaml:"max_receive_count"`
+}
+
+type RequestLogging struct {
+	SamplePercentage float64  `json:"sample_percentage" yaml:"sample_percentage"`
+	S3Path           *string  `json:"s3_path" yaml:"s3_path"`
+	KinesisStream    *string  `json:"kinesis_stream" yaml:"kinesis_stream"`
+	RedactHeaders    []string `json:"redact_headers" yaml:"redact_headers"`
+	RedactFields     []string `json:"redact_fields" yaml:"redact_fields"`
+}
+
+const (
+	ProtocolHTTP = "http"
+	ProtocolGRPC = "grpc"
+)
+
+const (
+	AuthenticationNone   = "none"
+	AuthenticationAPIKey = "api_key"
+	AuthenticationAWSIAM = "aws_iam"
+)
+
+type RateLimit struct {
+	RequestsPerSecond float64 `json:"requests_per_second" yaml:"requests_per_second"`
+	Burst             int64   `json:"burst" yaml:"burst"`
+	KeyHeader         *string `json:"key_header" yaml:"key_header"`
+}
+
+type WebSocket struct {
+	IdleTimeout    time.Duration `json:"idle_timeout" yaml:"idle_timeout"`
+	MaxConnections int64         `json:"max_connections" yaml:"max_connections"`
+}
+
+type ModelWatch struct {
+	Path         string        `json:"path" yaml:"path"`
+	PollInterval time.Duration `json:"poll_interval" yaml:"poll_interval"`
+}
+
+type ModelCache struct {
+	Path      *string `json:"path" yaml:"path"`
+	MountPath string  `json:"mount_path" yaml:"mount_path"`
+}
+
+// Distributed runs each task job as a group of coordinated pods (e.g. for multi-node training)
+type Distributed struct {
+	Workers            int32  `json:"workers" yaml:"workers"`
+	ProcessesPerWorker int32  `json:"processes_per_worker" yaml:"processes_per_worker"`
+	RendezvousBackend  string `json:"rendezvous_backend" yaml:"rendezvous_backend"`
+	Port               int32  `json:"port" yaml:"port"`
+}
+
+const (
+	RendezvousBackendStatic = "static"
+	RendezvousBackendC10d   = "c10d"
+)
+
+type PredictionMetric struct {
+	Name    string    `json:"name" yaml:"name"`
+	Buckets []float64 `json:"buckets" yaml:"buckets"`
+}
+
 func (api *API) Identify() string {
 	return IdentifyAPI(api.FileName, api.Name, api.Kind, api.Index)
 }
@@ -176,12 +368,28 @@ func (api *API) ToK8sAnnotations() map[string]string {
 
 	if api.Networking != nil {
 		annotations[EndpointAnnotationKey] = *api.Networking.Endpoint
+		if api.Networking.EndpointVisibility != nil {
+			annotations[EndpointVisibilityAnnotationKey] = *api.Networking.EndpointVisibility
+		}
+		if api.Networking.CustomDomain != nil {
+			annotations[CustomDomainAnnotationKey] = *api.Networking.CustomDomain
+		}
 	}
 
 	if api.Autoscaling != nil {
 		annotations[MinReplicasAnnotationKey] = s.Int32(api.Autoscaling.MinReplicas)
 		annotations[MaxReplicasAnnotationKey] = s.Int32(api.Autoscaling.MaxReplicas)
 		annotations[TargetInFlightAnnotationKey] = s.Float64(*api.Autoscaling.TargetInFlight)
+		if api.Autoscaling.TargetGPUUtilization != nil {
+			annotations[TargetGPUUtilizationAnnotationKey] = s.Float64(*api.Autoscaling.TargetGPUUtilization)
+		}
+		if api.Autoscaling.CustomMetric != nil {
+			annotations[CustomMetricQueryAnnotationKey] = api.Autoscaling.CustomMetric.Query
+			annotations[CustomMetricTargetAnnotationKey] = s.Float64(api.Autoscaling.CustomMetric.Target)
+		}
+		if api.Autoscaling.MaxQueueWait != nil {
+			annotations[MaxQueueWaitAnnotationKey] = api.Autoscaling.MaxQueueWait.String()
+		}
 		annotations[WindowAnnotationKey] = api.Autoscaling.Window.String()
 		annotations[DownscaleStabilizationPeriodAnnotationKey] = api.Autoscaling.DownscaleStabilizationPeriod.String()
 		annotations[UpscaleStabilizationPeriodAnnotationKey] = api.Autoscaling.UpscaleStabilizationPeriod.String()
@@ -214,6 +422,31 @@ func AutoscalingFromAnnotations(k8sObj kmeta.Object) (*Autoscaling, error) {
 	}
 	a.TargetInFlight = pointer.Float64(targetInFlight)
 
+	if _, ok := k8sObj.GetAnnotations()[TargetGPUUtilizationAnnotationKey]; ok {
+		targetGPUUtilization, err := k8s.ParseFloat64Annotation(k8sObj, TargetGPUUtilizationAnnotationKey)
+		if err != nil {
+			return nil, err
+		}
+		a.TargetGPUUtilization = pointer.Float64(targetGPUUtilization)
+	}
+
+	if customMetricQuery, ok := k8sObj.GetAnnotations()[CustomMetricQueryAnnotationKey]; ok {
+		customMetricTarget, err := k8s.ParseFloat64Annotation(k8sObj, CustomMetricTargetAnnotationKey)
+		if err != nil {
+			return nil, err
+		}
+		a.CustomMetric = &CustomMetric{
+			Query:  customMetricQuery,
+			Target: customMetricTarget,
+		}
+	}
+
+	maxQueueWait, err := MaxQueueWaitFromAnnotations(k8sObj)
+	if err != nil {
+		return nil, err
+	}
+	a.MaxQueueWait = maxQueueWait
+
 	window, err := k8s.ParseDurationAnnotation(k8sObj, WindowAnnotationKey)
 	if err != nil {
 		return nil, err
@@ -289,11 +522,102 @@ func ConcurrencyFromAnnotations(k8sObj kmeta.Object) (int, int, error) {
 	return maxQueueLength, maxConcurrency, nil
 }
 
+// MaxQueueWaitFromAnnotations returns nil if max_queue_wait was not specified
+func MaxQueueWaitFromAnnotations(k8sObj kmeta.Object) (*time.Duration, error) {
+	if _, ok := k8sObj.GetAnnotations()[MaxQueueWaitAnnotationKey]; !ok {
+		return nil, nil
+	}
+
+	maxQueueWait, err := k8s.ParseDurationAnnotation(k8sObj, MaxQueueWaitAnnotationKey)
+	if err != nil {
+		return nil, err
+	}
+	return &maxQueueWait, nil
+}
+
+// ValidateProject returns an error if project is not a valid project name (an empty project refers to the default project)
+func ValidateProject(project string) error {
+	if project == "" {
+		return nil
+	}
+	if len(project) > 63 {
+		return errors.Wrap(cr.ErrorTooLong(project, 63), ProjectKey)
+	}
+	if ReservedProjects.Has(project) {
+		return errors.Wrap(cr.ErrorDisallowedValue(project), ProjectKey)
+	}
+	return errors.Wrap(urls.CheckDNS1123(project), ProjectKey)
+}
+
+// ProjectNamespace returns the kubernetes namespace to which the apis of the project are deployed (the apis of the default project are deployed to the "default" namespace)
+func ProjectNamespace(project string) string {
+	if project == "" {
+		return consts.DefaultNamespace
+	}
+	return project
+}
+
+// Namespace returns the kubernetes namespace of the api's project
+func (api *API) Namespace() string {
+	return ProjectNamespace(api.Project)
+}
+
+// ValidateSecretName returns an error if name is not a valid name for a secret which is managed with `cortex secrets`
+func ValidateSecretName(name string) error {
+	if len(name) > 128 {
+		return cr.ErrorTooLong(name, 128)
+	}
+	if !regex.IsAlphaNumericDashDotUnderscore(name) {
+		return cr.ErrorAlphaNumericDashDotUnderscore(name)
+	}
+	return nil
+}
+
+// SecretNames returns the names of the secrets which are referenced by the containers' env_from_secrets
+func SecretNames(containers []*Container) []string {
+	secretNames := strset.New()
+	for _, container := range containers {
+		if container == nil {
+			continue
+		}
+		for _, secretName := range container.EnvFromSecrets {
+			secretNames.Add(secretName)
+		}
+	}
+	return secretNames.SliceSorted()
+}
+
+// ProjectFromLabels returns the project of an api from the labels of its kubernetes resources (apis which were deployed before projects were introduced belong to the default project)
+func ProjectFromLabels(labels map[string]string) string {
+	if project := labels[ProjectLabelKey]; project != "" {
+		return project
+	}
+	return DefaultProject
+}
+
+// LabelsWithProject returns the labels of an api's kubernetes resources with the project label set, so that apis which were deployed before projects were introduced can be selected by project
+func LabelsWithProject(labels map[string]string) map[string]string {
+	if labels[ProjectLabelKey] != "" {
+		return labels
+	}
+	return maps.MergeStrMapsString(labels, map[string]string{ProjectLabelKey: DefaultProject})
+}
+
 func (api *API) UserStr() string {
 	var sb strings.Builder
 	sb.WriteString(fmt.Sprintf("%s: %s\n", NameKey, api.Name))
 	sb.WriteString(fmt.Sprintf("%s: %s\n", KindKey, api.Kind.String()))
 
+	if api.Project != "" {
+		sb.WriteString(fmt.Sprintf("%s: %s\n", ProjectKey, api.Project))
+	}
+
+	if len(api.Labels) > 0 {
+		sb.WriteString(fmt.Sprintf("%s:\n", LabelsKey))
+		d, _ := yaml.Marshal(&api.Labels)
+		sb.WriteString(s.Indent(string(d), "  "))
+	}
+
 	if api.Kind == TrafficSplitterKind {
 		sb.WriteString(fmt.Sprintf("%s:\n", APIsKey))
 		for _, api := range api.APIs {
@@ -327,6 +651,75 @@ func (api *API) UserStr() string {
 		sb.WriteString(s.Indent(api.UpdateStrategy.UserStr(), "  "))
 	}
 
+	if api.Canary != nil {
+		sb.WriteString(fmt.Sprintf("%s:\n", CanaryKey))
+		sb.WriteString(s.Indent(api.Canary.UserStr(), "  "))
+	}
+
+	if api.WarmPool != nil {
+		sb.WriteString(fmt.Sprintf("%s:\n", WarmPoolKey))
+		sb.WriteString(s.Indent(api.WarmPool.UserStr(), "  "))
+	}
+
+	if api.Async != nil {
+		sb.WriteString(fmt.Sprintf("%s:\n", AsyncKey))
+		sb.WriteString(s.Indent(api.Async.UserStr(), "  "))
+	}
+
+	if api.RequestLogging != nil {
+		sb.WriteString(fmt.Sprintf("%s:\n", RequestLoggingKey))
+		sb.WriteString(s.Indent(api.RequestLogging.UserStr(), "  "))
+	}
+
+	if len(api.PredictionMetrics) > 0 {
+		sb.WriteString(fmt.Sprintf("%s:\n", PredictionMetricsKey))
+		for _, predictionMetric := range api.PredictionMetrics {
+			predictionMetricUserStr := s.Indent(predictionMetric.UserStr(), "    ")
+			predictionMetricUserStr = predictionMetricUserStr[:2] + "-" + predictionMetricUserStr[3:]
+			sb.WriteString(predictionMetricUserStr)
+		}
+	}
+
+	if api.RateLimit != nil {
+		sb.WriteString(fmt.Sprintf("%s:\n", RateLimitKey))
+		sb.WriteString(s.Indent(api.RateLimit.UserStr(), "  "))
+	}
+
+	if api.WebSocket != nil {
+		sb.WriteString(fmt.Sprintf("%s:\n", WebSocketKey))
+		sb.WriteString(s.Indent(api.WebSocket.UserStr(), "  "))
+	}
+
+	if api.ModelWatch != nil {
+		sb.WriteString(fmt.Sprintf("%s:\n", ModelWatchKey))
+		sb.WriteString(s.Indent(api.ModelWatch.UserStr(), "  "))
+	}
+
+	if api.ModelCache != nil {
+		sb.WriteString(fmt.Sprintf("%s:\n", ModelCacheKey))
+		sb.WriteString(s.Indent(api.ModelCache.UserStr(), "  "))
+	}
+
+	if api.PrepullImages {
+		sb.WriteString(fmt.Sprintf("%s: %t\n", PrepullImagesKey, api.PrepullImages))
+	}
+
+	if api.Authentication != "" {
+		sb.WriteString(fmt.Sprintf("%s: %s\n", AuthenticationKey, api.Authentication))
+	}
+	if len(api.AWSIAMPrincipals) > 0 {
+		sb.WriteString(fmt.Sprintf("%s: %s\n", AWSIAMPrincipalsKey, s.ObjFlatNoQuotes(api.AWSIAMPrincipals)))
+	}
+
+	if api.MaxConcurrentJobs != nil {
+		sb.WriteString(fmt.Sprintf("%s: %d\n", MaxConcurrentJobsKey, *api.MaxConcurrentJobs))
+	}
+
+	if api.Distributed != nil {
+		sb.WriteString(fmt.Sprintf("%s:\n", DistributedKey))
+		sb.WriteString(s.Indent(api.Distributed.UserStr(), "  "))
+	}
+
 	return sb.String()
 }
 
@@ -345,14 +738,43 @@ func (pod *Pod) UserStr(kind Kind) string {
 	}
 
 	if kind == RealtimeAPIKind {
+		sb.WriteString(fmt.Sprintf("%s: %s\n", ProtocolKey, pod.Protocol))
 		sb.WriteString(fmt.Sprintf("%s: %s\n", MaxConcurrencyKey, s.Int64(pod.MaxConcurrency)))
 		sb.WriteString(fmt.Sprintf("%s: %s\n", MaxQueueLengthKey, s.Int64(pod.MaxQueueLength)))
+		if pod.MaxRequestBodySize != nil {
+			sb.WriteString(fmt.Sprintf("%s: %s\n", MaxRequestBodySizeKey, pod.MaxRequestBodySize.UserString))
+		}
+		if pod.RequestTimeout != nil {
+			sb.WriteString(fmt.Sprintf("%s: %s\n", RequestTimeoutKey, pod.RequestTimeout.String()))
+		}
+		if pod.MaxConnections != nil {
+			sb.WriteString(fmt.Sprintf("%s: %s\n", MaxConnectionsKey, s.Int64(*pod.MaxConnections)))
+		}
 	}
 
 	if kind == AsyncAPIKind {
 		sb.WriteString(fmt.Sprintf("%s: %s\n", MaxConcurrencyKey, s.Int64(pod.MaxConcurrency)))
 	}
 
+	if pod.RegistryCredentials != nil {
+		sb.WriteString(fmt.Sprintf("%s:\n", RegistryCredentialsKey))
+		sb.WriteString(s.Indent(pod.RegistryCredentials.UserStr(), "  "))
+	}
+
+	if pod.IAMRoleARN != nil {
+		sb.WriteString(fmt.Sprintf("%s: %s\n", IAMRoleARNKey, *pod.IAMRoleARN))
+	}
+
+	if pod.Security != nil {
+		sb.WriteString(fmt.Sprintf("%s:\n", SecurityKey))
+		sb.WriteString(s.Indent(pod.Security.UserStr(), "  "))
+	}
+
+	if pod.Warmup != nil {
+		sb.WriteString(fmt.Sprintf("%s:\n", WarmupKey))
+		sb.WriteString(s.Indent(pod.Warmup.UserStr(), "  "))
+	}
+
 	sb.WriteString(fmt.Sprintf("%s:\n", ContainersKey))
 	for _, container := range pod.Containers {
 		containerUserStr := s.Indent(container.UserStr(), "    ")
@@ -363,6 +785,55 @@ func (pod *Pod) UserStr(kind Kind) string {
 	return sb.String()
 }
 
+func (warmup *Warmup) UserStr() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%s: %s\n", PathKey, warmup.Path))
+	sb.WriteString(fmt.Sprintf("%s: %s\n", MethodKey, warmup.Method))
+	if warmup.Payload != nil {
+		sb.WriteString(fmt.Sprintf("%s: %s\n", PayloadKey, s.UserStr(*warmup.Payload)))
+	}
+	if len(warmup.Headers) > 0 {
+		sb.WriteString(fmt.Sprintf("%s:\n", HeadersKey))
+		d, _ := yaml.Marshal(&warmup.Headers)
+		sb.WriteString(s.Indent(string(d), "  "))
+	}
+	sb.WriteString(fmt.Sprintf("%s: %s\n", NumRequestsKey, s.Int64(warmup.NumRequests)))
+	sb.WriteString(fmt.Sprintf("%s: %s\n", TimeoutSecondsKey, s.Int64(warmup.TimeoutSeconds)))
+	return sb.String()
+}
+
+func (security *PodSecurity) UserStr() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%s: %s\n", PrivilegedKey, s.Bool(security.Privileged)))
+	sb.WriteString(fmt.Sprintf("%s: %s\n", ReadOnlyRootFSKey, s.Bool(security.ReadOnlyRootFS)))
+	sb.WriteString(fmt.Sprintf("%s: %s\n", RunAsNonRootKey, s.Bool(security.RunAsNonRoot)))
+	if security.RunAsUser != nil {
+		sb.WriteString(fmt.Sprintf("%s: %d\n", RunAsUserKey, *security.RunAsUser))
+	}
+	if len(security.DropCapabilities) > 0 {
+		sb.WriteString(fmt.Sprintf("%s: %s\n", DropCapabilitiesKey, s.ObjFlatNoQuotes(security.DropCapabilities)))
+	}
+	sb.WriteString(fmt.Sprintf("%s: %s\n", SeccompProfileKey, security.SeccompProfile))
+	return sb.String()
+}
+
+func (registryCredentials *RegistryCredentials) UserStr() string {
+	var sb strings.Builder
+	if registryCredentials.Secret != nil {
+		sb.WriteString(fmt.Sprintf("%s: %s\n", SecretKey, *registryCredentials.Secret))
+	}
+	if registryCredentials.SecretsManagerARN != nil {
+		sb.WriteString(fmt.Sprintf("%s: %s\n", SecretsManagerARNKey, *registryCredentials.SecretsManagerARN))
+	}
+	if registryCredentials.ECRRoleARN != nil {
+		sb.WriteString(fmt.Sprintf("%s: %s\n", ECRRoleARNKey, *registryCredentials.ECRRoleARN))
+	}
+	if registryCredentials.RegistryAuth != nil {
+		sb.WriteString(fmt.Sprintf("%s: %s\n", RegistryAuthKey, *registryCredentials.RegistryAuth))
+	}
+	return sb.String()
+}
+
 func (container *Container) UserStr() string {
 	var sb strings.Builder
 
@@ -375,6 +846,12 @@ func (container *Container) UserStr() string {
 		sb.WriteString(s.Indent(string(d), "  "))
 	}
 
+	if len(container.EnvFromSecrets) > 0 {
+		sb.WriteString(fmt.Sprintf("%s:\n", EnvFromSecretsKey))
+		d, _ := yaml.Marshal(&container.EnvFromSecrets)
+		sb.WriteString(s.Indent(string(d), "  "))
+	}
+
 	if container.Command != nil {
 		sb.WriteString(fmt.Sprintf("%s: %s\n", CommandKey, s.ObjFlatNoQuotes(container.Command)))
 	}
@@ -411,6 +888,30 @@ func (networking *Networking) UserStr() string {
 	if networking.Endpoint != nil {
 		sb.WriteString(fmt.Sprintf("%s: %s\n", EndpointKey, *networking.Endpoint))
 	}
+	if networking.EndpointVisibility != nil {
+		sb.WriteString(fmt.Sprintf("%s: %s\n", EndpointVisibilityKey, *networking.EndpointVisibility))
+	}
+	if networking.CustomDomain != nil {
+		sb.WriteString(fmt.Sprintf("%s: %s\n", CustomDomainKey, *networking.CustomDomain))
+	}
+	if networking.Egress != nil {
+		sb.WriteString(fmt.Sprintf("%s:\n", EgressKey))
+		sb.WriteString(s.Indent(networking.Egress.UserStr(), "  "))
+	}
+	return sb.String()
+}
+
+func (egress *Egress) UserStr() string {
+	var sb strings.Builder
+	if len(egress.AllowedCIDRs) > 0 {
+		sb.WriteString(fmt.Sprintf("%s: %s\n", AllowedCIDRsKey, s.ObjFlatNoQuotes(egress.AllowedCIDRs)))
+	}
+	if len(egress.AllowedDomains) > 0 {
+		sb.WriteString(fmt.Sprintf("%s: %s\n", AllowedDomainsKey, s.ObjFlatNoQuotes(egress.AllowedDomains)))
+	}
+	if sb.Len() == 0 {
+		return fmt.Sprintf("%s: []\n", AllowedCIDRsKey)
+	}
 	return sb.String()
 }
 
@@ -488,6 +989,9 @@ func (compute *Compute) UserStr() string {
 	if compute.Inf > 0 {
 		sb.WriteString(fmt.Sprintf("%s: %s\n", InfKey, s.Int64(compute.Inf)))
 	}
+	if compute.NeuronCores > 0 {
+		sb.WriteString(fmt.Sprintf("%s: %s\n", NeuronCoresKey, s.Int64(compute.NeuronCores)))
+	}
 	if compute.Mem == nil {
 		sb.WriteString(fmt.Sprintf("%s: null  # no limit\n", MemKey))
 	} else {
@@ -507,6 +1011,16 @@ func (autoscaling *Autoscaling) UserStr() string {
 	sb.WriteString(fmt.Sprintf("%s: %s\n", MaxReplicasKey, s.Int32(autoscaling.MaxReplicas)))
 	sb.WriteString(fmt.Sprintf("%s: %s\n", InitReplicasKey, s.Int32(autoscaling.InitReplicas)))
 	sb.WriteString(fmt.Sprintf("%s: %s\n", TargetInFlightKey, s.Float64(*autoscaling.TargetInFlight)))
+	if autoscaling.TargetGPUUtilization != nil {
+		sb.WriteString(fmt.Sprintf("%s: %s\n", TargetGPUUtilizationKey, s.Float64(*autoscaling.TargetGPUUtilization)))
+	}
+	if autoscaling.CustomMetric != nil {
+		sb.WriteString(fmt.Sprintf("%s:\n", CustomMetricKey))
+		sb.WriteString(s.Indent(autoscaling.CustomMetric.UserStr(), "  "))
+	}
+	if autoscaling.MaxQueueWait != nil {
+		sb.WriteString(fmt.Sprintf("%s: %s\n", MaxQueueWaitKey, autoscaling.MaxQueueWait.String()))
+	}
 	sb.WriteString(fmt.Sprintf("%s: %s\n", WindowKey, autoscaling.Window.String()))
 	sb.WriteString(fmt.Sprintf("%s: %s\n", DownscaleStabilizationPeriodKey, autoscaling.DownscaleStabilizationPeriod.String()))
 	sb.WriteString(fmt.Sprintf("%s: %s\n", UpscaleStabilizationPeriodKey, autoscaling.UpscaleStabilizationPeriod.String()))
@@ -518,6 +1032,13 @@ func (autoscaling *Autoscaling) UserStr() string {
 	return sb.String()
 }
 
+func (customMetric *CustomMetric) UserStr() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%s: %s\n", QueryKey, s.UserStr(customMetric.Query)))
+	sb.WriteString(fmt.Sprintf("%s: %s\n", TargetKey, s.Float64(customMetric.Target)))
+	return sb.String()
+}
+
 func (updateStrategy *UpdateStrategy) UserStr() string {
 	var sb strings.Builder
 	sb.WriteString(fmt.Sprintf("%s: %s\n", MaxSurgeKey, updateStrategy.MaxSurge))
@@ -525,6 +1046,102 @@ func (updateStrategy *UpdateStrategy) UserStr() string {
 	return sb.String()
 }
 
+func (canary *Canary) UserStr() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%s: %d\n", WeightKey, canary.Weight))
+	return sb.String()
+}
+
+func (warmPool *WarmPool) UserStr() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%s: %s\n", ReplicasKey, s.Int32(warmPool.Replicas)))
+	return sb.String()
+}
+
+func (async *Async) UserStr() string {
+	var sb strings.Builder
+	if async.ResultTTL == nil {
+		sb.WriteString(fmt.Sprintf("%s: null\n", ResultTTLKey))
+	} else {
+		sb.WriteString(fmt.Sprintf("%s: %s\n", ResultTTLKey, async.ResultTTL.String()))
+	}
+	sb.WriteString(fmt.Sprintf("%s: %s\n", IdempotencyWindowKey, async.IdempotencyWindow.String()))
+	if async.OutputPath != nil {
+		sb.WriteString(fmt.Sprintf("%s: %s\n", OutputPathKey, *async.OutputPath))
+	}
+	if async.MaxReceiveCount != nil {
+		sb.WriteString(fmt.Sprintf("%s: %s\n", MaxReceiveCountKey, s.Int64(*async.MaxReceiveCount)))
+	}
+	return sb.String()
+}
+
+func (requestLogging *RequestLogging) UserStr() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%s: %s\n", SamplePercentageKey, s.Float64(requestLogging.SamplePercentage)))
+	if requestLogging.S3Path != nil {
+		sb.WriteString(fmt.Sprintf("%s: %s\n", S3PathKey, *requestLogging.S3Path))
+	}
+	if requestLogging.KinesisStream != nil {
+		sb.WriteString(fmt.Sprintf("%s: %s\n", KinesisStreamKey, *requestLogging.KinesisStream))
+	}
+	if len(requestLogging.RedactHeaders) > 0 {
+		sb.WriteString(fmt.Sprintf("%s: %s\n", RedactHeadersKey, s.ObjFlatNoQuotes(requestLogging.RedactHeaders)))
+	}
+	if len(requestLogging.RedactFields) > 0 {
+		sb.WriteString(fmt.Sprintf("%s: %s\n", RedactFieldsKey, s.ObjFlatNoQuotes(requestLogging.RedactFields)))
+	}
+	return sb.String()
+}
+
+func (rateLimit *RateLimit) UserStr() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%s: %s\n", RequestsPerSecondKey, s.Float64(rateLimit.RequestsPerSecond)))
+	sb.WriteString(fmt.Sprintf("%s: %s\n", BurstKey, s.Int64(rateLimit.Burst)))
+	if rateLimit.KeyHeader != nil {
+		sb.WriteString(fmt.Sprintf("%s: %s\n", KeyHeaderKey, *rateLimit.KeyHeader))
+	}
+	return sb.String()
+}
+
+func (webSocket *WebSocket) UserStr() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%s: %s\n", IdleTimeoutKey, webSocket.IdleTimeout.String()))
+	sb.WriteString(fmt.Sprintf("%s: %s\n", MaxConnectionsKey, s.Int64(webSocket.MaxConnections)))
+	return sb.String()
+}
+
+func (modelWatch *ModelWatch) UserStr() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%s: %s\n", PathKey, modelWatch.Path))
+	sb.WriteString(fmt.Sprintf("%s: %s\n", PollIntervalKey, modelWatch.PollInterval.String()))
+	return sb.String()
+}
+
+func (modelCache *ModelCache) UserStr() string {
+	var sb strings.Builder
+	if modelCache.Path != nil {
+		sb.WriteString(fmt.Sprintf("%s: %s\n", PathKey, *modelCache.Path))
+	}
+	sb.WriteString(fmt.Sprintf("%s: %s\n", MountPathKey, modelCache.MountPath))
+	return sb.String()
+}
+
+func (distributed *Distributed) UserStr() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%s: %s\n", WorkersKey, s.Int32(distributed.Workers)))
+	sb.WriteString(fmt.Sprintf("%s: %s\n", ProcessesPerWorkerKey, s.Int32(distributed.ProcessesPerWorker)))
+	sb.WriteString(fmt.Sprintf("%s: %s\n", RendezvousBackendKey, distributed.RendezvousBackend))
+	sb.WriteString(fmt.Sprintf("%s: %s\n", PortKey, s.Int32(distributed.Port)))
+	return sb.String()
+}
+
+func (predictionMetric *PredictionMetric) UserStr() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%s: %s\n", NameKey, predictionMetric.Name))
+	sb.WriteString(fmt.Sprintf("%s: %s\n", BucketsKey, s.ObjFlatNoQuotes(predictionMetric.Buckets)))
+	return sb.String()
+}
+
 func ZeroCompute() Compute {
 	return Compute{
 		CPU: &k8s.Quantity{},
@@ -539,6 +1156,7 @@ func GetPodComputeRequest(api *API) Compute {
 	var shmQtys []kresource.Quantity
 	var totalGPU int64
 	var totalInf int64
+	var totalNeuronCores int64
 
 	for _, container := range api.Pod.Containers {
 		if container == nil || container.Compute == nil {
@@ -555,6 +1173,7 @@ func GetPodComputeRequest(api *API) Compute {
 		}
 		totalGPU += container.Compute.GPU
 		totalInf += container.Compute.Inf
+		totalNeuronCores += container.Compute.NeuronCores
 	}
 
 	if api.Kind == RealtimeAPIKind {
@@ -566,11 +1185,12 @@ func GetPodComputeRequest(api *API) Compute {
 	}
 
 	return Compute{
-		CPU: k8s.NewSummed(cpuQtys...),
-		Mem: k8s.NewSummed(memQtys...),
-		Shm: k8s.NewSummed(shmQtys...),
-		GPU: totalGPU,
-		Inf: totalInf,
+		CPU:         k8s.NewSummed(cpuQtys...),
+		Mem:         k8s.NewSummed(memQtys...),
+		Shm:         k8s.NewSummed(shmQtys...),
+		GPU:         totalGPU,
+		Inf:         totalInf,
+		NeuronCores: totalNeuronCores,
 	}
 }
 
@@ -587,6 +1207,15 @@ func GetContainerNames(containers []*Container) strset.Set {
 func (api *API) TelemetryEvent() map[string]interface{} {
 	event := map[string]interface{}{"kind": api.Kind}
 
+	if len(api.Labels) > 0 {
+		event["labels._is_defined"] = true
+		event["labels._len"] = len(api.Labels)
+	}
+
+	if api.Project != "" && api.Project != DefaultProject {
+		event["project._is_defined"] = true
+	}
+
 	if len(api.APIs) > 0 {
 		event["apis._is_defined"] = true
 		event["apis._len"] = len(api.APIs)
@@ -600,6 +1229,17 @@ func (api *API) TelemetryEvent() map[string]interface{} {
 				event["networking.endpoint._is_custom"] = true
 			}
 		}
+		if api.Networking.EndpointVisibility != nil {
+			event["networking.endpoint_visibility"] = *api.Networking.EndpointVisibility
+		}
+		if api.Networking.CustomDomain != nil {
+			event["networking.custom_domain._is_defined"] = true
+		}
+		if api.Networking.Egress != nil {
+			event["networking.egress._is_defined"] = true
+			event["networking.egress.allowed_cidrs._len"] = len(api.Networking.Egress.AllowedCIDRs)
+			event["networking.egress.allowed_domains._len"] = len(api.Networking.Egress.AllowedDomains)
+		}
 	}
 
 	if api.Pod != nil {
@@ -608,15 +1248,63 @@ func (api *API) TelemetryEvent() map[string]interface{} {
 			event["pod.port"] = *api.Pod.Port
 		}
 
+		if api.Pod.Protocol != "" {
+			event["pod.protocol"] = api.Pod.Protocol
+		}
 		event["pod.max_concurrency"] = api.Pod.MaxConcurrency
 		event["pod.max_queue_length"] = api.Pod.MaxQueueLength
+		if api.Pod.MaxRequestBodySize != nil {
+			event["pod.max_request_body_size._is_defined"] = true
+			event["pod.max_request_body_size"] = api.Pod.MaxRequestBodySize.Value()
+		}
+		if api.Pod.RequestTimeout != nil {
+			event["pod.request_timeout._is_defined"] = true
+			event["pod.request_timeout"] = api.Pod.RequestTimeout.Seconds()
+		}
+		if api.Pod.MaxConnections != nil {
+			event["pod.max_connections._is_defined"] = true
+			event["pod.max_connections"] = *api.Pod.MaxConnections
+		}
+
+		if api.Pod.Warmup != nil {
+			event["pod.warmup._is_defined"] = true
+			event["pod.warmup.method"] = api.Pod.Warmup.Method
+			event["pod.warmup.payload._is_defined"] = api.Pod.Warmup.Payload != nil
+			event["pod.warmup.headers._len"] = len(api.Pod.Warmup.Headers)
+			event["pod.warmup.num_requests"] = api.Pod.Warmup.NumRequests
+			event["pod.warmup.timeout_seconds"] = api.Pod.Warmup.TimeoutSeconds
+		}
+
+		if api.Pod.RegistryCredentials != nil {
+			event["pod.registry_credentials._is_defined"] = true
+			event["pod.registry_credentials.secret._is_defined"] = api.Pod.RegistryCredentials.Secret != nil
+			event["pod.registry_credentials.secrets_manager_arn._is_defined"] = api.Pod.RegistryCredentials.SecretsManagerARN != nil
+			event["pod.registry_credentials.ecr_role_arn._is_defined"] = api.Pod.RegistryCredentials.ECRRoleARN != nil
+			event["pod.registry_credentials.registry_auth._is_defined"] = api.Pod.RegistryCredentials.RegistryAuth != nil
+		}
+
+		event["pod.iam_role_arn._is_defined"] = api.Pod.IAMRoleARN != nil
+		if api.Pod.Security != nil {
+			event["pod.security._is_defined"] = true
+			event["pod.security.privileged"] = api.Pod.Security.Privileged
+			event["pod.security.read_only_root_fs"] = api.Pod.Security.ReadOnlyRootFS
+			event["pod.security.run_as_non_root"] = api.Pod.Security.RunAsNonRoot
+			event["pod.security.drop_capabilities._len"] = len(api.Pod.Security.DropCapabilities)
+			if strings.HasPrefix(api.Pod.Security.SeccompProfile, SeccompProfileLocalhostPrefix) {
+				event["pod.security.seccomp_profile"] = "localhost"
+			} else {
+				event["pod.security.seccomp_profile"] = api.Pod.Security.SeccompProfile
+			}
+		}
 
 		event["pod.containers._len"] = len(api.Pod.Containers)
 
 		var numReadinessProbes int
 		var numLivenessProbes int
 		var numPreStops int
+		var numEnvFromSecrets int
 		for _, container := range api.Pod.Containers {
+			numEnvFromSecrets += len(container.EnvFromSecrets)
 			if container.ReadinessProbe != nil {
 				numReadinessProbes++
 			}
@@ -631,6 +1319,7 @@ func (api *API) TelemetryEvent() map[string]interface{} {
 		event["pod.containers._num_readiness_probes"] = numReadinessProbes
 		event["pod.containers._num_liveness_probes"] = numLivenessProbes
 		event["pod.containers._num_pre_stops"] = numPreStops
+		event["pod.containers._num_env_from_secrets"] = numEnvFromSecrets
 
 		totalCompute := GetPodComputeRequest(api)
 		if totalCompute.CPU != nil {
@@ -647,6 +1336,7 @@ func (api *API) TelemetryEvent() map[string]interface{} {
 		}
 		event["pod.containers.compute.gpu"] = totalCompute.GPU
 		event["pod.containers.compute.inf"] = totalCompute.Inf
+		event["pod.containers.compute.neuron_cores"] = totalCompute.NeuronCores
 	}
 
 	event["node_groups._len"] = len(api.NodeGroups)
@@ -657,6 +1347,83 @@ func (api *API) TelemetryEvent() map[string]interface{} {
 		event["update_strategy.max_unavailable"] = api.UpdateStrategy.MaxUnavailable
 	}
 
+	if api.Canary != nil {
+		event["canary._is_defined"] = true
+		event["canary.weight"] = api.Canary.Weight
+	}
+
+	if api.WarmPool != nil {
+		event["warm_pool._is_defined"] = true
+		event["warm_pool.replicas"] = api.WarmPool.Replicas
+	}
+
+	if api.Async != nil {
+		event["async._is_defined"] = true
+		if api.Async.ResultTTL != nil {
+			event["async.result_ttl._is_defined"] = true
+			event["async.result_ttl"] = api.Async.ResultTTL.Seconds()
+		}
+		event["async.idempotency_window"] = api.Async.IdempotencyWindow.Seconds()
+		event["async.output_path._is_defined"] = api.Async.OutputPath != nil
+		if api.Async.MaxReceiveCount != nil {
+			event["async.max_receive_count._is_defined"] = true
+			event["async.max_receive_count"] = *api.Async.MaxReceiveCount
+		}
+	}
+
+	if api.RequestLogging != nil {
+		event["request_logging._is_defined"] = true
+		event["request_logging.sample_percentage"] = api.RequestLogging.SamplePercentage
+		event["request_logging.s3_path._is_defined"] = api.RequestLogging.S3Path != nil
+		event["request_logging.kinesis_stream._is_defined"] = api.RequestLogging.KinesisStream != nil
+		event["request_logging.redact_headers._len"] = len(api.RequestLogging.RedactHeaders)
+		event["request_logging.redact_fields._len"] = len(api.RequestLogging.RedactFields)
+	}
+
+	event["prediction_metrics._len"] = len(api.PredictionMetrics)
+
+	if api.Authentication != "" {
+		event["authentication"] = api.Authentication
+	}
+	event["aws_iam_principals._len"] = len(api.AWSIAMPrincipals)
+
+	if api.MaxConcurrentJobs != nil {
+		event["max_concurrent_jobs._is_defined"] = true
+		event["max_concurrent_jobs"] = *api.MaxConcurrentJobs
+	}
+
+	if api.Distributed != nil {
+		event["distributed._is_defined"] = true
+		event["distributed.workers"] = api.Distributed.Workers
+		event["distributed.processes_per_worker"] = api.Distributed.ProcessesPerWorker
+		event["distributed.rendezvous_backend"] = api.Distributed.RendezvousBackend
+	}
+
+	if api.RateLimit != nil {
+		event["rate_limit._is_defined"] = true
+		event["rate_limit.requests_per_second"] = api.RateLimit.RequestsPerSecond
+		event["rate_limit.burst"] = api.RateLimit.Burst
+		event["rate_limit.key_header._is_defined"] = api.RateLimit.KeyHeader != nil
+	}
+
+	if api.WebSocket != nil {
+		event["websocket._is_defined"] = true
+		event["websocket.idle_timeout"] = api.WebSocket.IdleTimeout.Seconds()
+		event["websocket.max_connections"] = api.WebSocket.MaxConnections
+	}
+
+	if api.ModelWatch != nil {
+		event["model_watch._is_defined"] = true
+		event["model_watch.poll_interval"] = api.ModelWatch.PollInterval.Seconds()
+	}
+
+	if api.ModelCache != nil {
+		event["model_cache._is_defined"] = true
+		event["model_cache.path._is_defined"] = api.ModelCache.Path != nil
+	}
+
+	event["prepull_images"] = api.PrepullImages
+
 	if api.Autoscaling != nil {
 		event["autoscaling._is_defined"] = true
 		event["autoscaling.min_replicas"] = api.Autoscaling.MinReplicas
@@ -666,6 +1433,15 @@ func (api *API) TelemetryEvent() map[string]interface{} {
 			event["autoscaling.target_in_flight._is_defined"] = true
 			event["autoscaling.target_in_flight"] = *api.Autoscaling.TargetInFlight
 		}
+		if api.Autoscaling.TargetGPUUtilization != nil {
+			event["autoscaling.target_gpu_utilization._is_defined"] = true
+			event["autoscaling.target_gpu_utilization"] = *api.Autoscaling.TargetGPUUtilization
+		}
+		event["autoscaling.custom_metric._is_defined"] = api.Autoscaling.CustomMetric != nil
+		if api.Autoscaling.MaxQueueWait != nil {
+			event["autoscaling.max_queue_wait._is_defined"] = true
+			event["autoscaling.max_queue_wait"] = api.Autoscaling.MaxQueueWait.Seconds()
+		}
 		event["autoscaling.window"] = api.Autoscaling.Window.Seconds()
 		event["autoscaling.downscale_stabilization_period"] = api.Autoscaling.DownscaleStabilizationPeriod.Seconds()
 		event["autoscaling.upscale_stabilization_period"] = api.Autoscaling.UpscaleStabilizationPeriod.Seconds()
@@ -18,12 +18,67 @@ package userconfig
 
 const (
 	// API
-	NameKey           = "name"
-	KindKey           = "kind"
-	NetworkingKey     = "networking"
-	ComputeKey        = "compute"
-	AutoscalingKey    = "autoscaling"
-	UpdateStrategyKey = "update_strategy"
+	NameKey              = "name"
+	KindKey              = "kind"
+	LabelsKey            = "labels"
+	ProjectKey           = "project"
+	NetworkingKey        = "networking"
+	ComputeKey           = "compute"
+	AutoscalingKey       = "autoscaling"
+	UpdateStrategyKey    = "update_strategy"
+	CanaryKey            = "canary"
+	WarmPoolKey          = "warm_pool"
+	AsyncKey             = "async"
+	RequestLoggingKey    = "request_logging"
+	PredictionMetricsKey = "prediction_metrics"
+	RateLimitKey         = "rate_limit"
+	WebSocketKey         = "websocket"
+	ModelWatchKey        = "model_watch"
+	ModelCacheKey        = "model_cache"
+	PrepullImagesKey     = "prepull_images"
+	AuthenticationKey    = "authentication"
+	AWSIAMPrincipalsKey  = "aws_iam_principals"
+	MaxConcurrentJobsKey = "max_concurrent_jobs"
+	DistributedKey       = "distributed"
+
+	// Async
+	ResultTTLKey         = "result_ttl"
+	IdempotencyWindowKey = "idempotency_window"
+	OutputPathKey        = "output_path"
+	MaxReceiveCountKey   = "max_receive_count"
+
+	// RequestLogging
+	SamplePercentageKey = "sample_percentage"
+	S3PathKey           = "s3_path"
+	KinesisStreamKey    = "kinesis_stream"
+	RedactHeadersKey    = "redact_headers"
+	RedactFieldsKey     = "redact_fields"
+
+	// PredictionMetrics
+	BucketsKey = "buckets"
+
+	// RateLimit
+	RequestsPerSecondKey = "requests_per_second"
+	BurstKey             = "burst"
+	KeyHeaderKey         = "key_header"
+
+	// WebSocket
+	IdleTimeoutKey    = "idle_timeout"
+	MaxConnectionsKey = "max_connections"
+
+	// ModelWatch
+	PollIntervalKey = "poll_interval"
+
+	// WarmPool
+	ReplicasKey = "replicas"
+
+	// ModelCache
+	MountPathKey = "mount_path"
+
+	// Distributed
+	WorkersKey            = "workers"
+	ProcessesPerWorkerKey = "processes_per_worker"
+	RendezvousBackendKey  = "rendezvous_backend"
 
 	// TrafficSplitter
 	APIsKey   = "apis"
@@ -31,17 +86,45 @@ const (
 	ShadowKey = "shadow"
 
 	// Pod
-	PodKey            = "pod"
-	NodeGroupsKey     = "node_groups"
-	PortKey           = "port"
-	MaxConcurrencyKey = "max_concurrency"
-	MaxQueueLengthKey = "max_queue_length"
-	ContainersKey     = "containers"
+	PodKey                = "pod"
+	NodeGroupsKey         = "node_groups"
+	PortKey               = "port"
+	ProtocolKey           = "protocol"
+	MaxConcurrencyKey     = "max_concurrency"
+	MaxQueueLengthKey     = "max_queue_length"
+	MaxRequestBodySizeKey = "max_request_body_size"
+	RequestTimeoutKey     = "request_timeout"
+	ContainersKey         = "containers"
+	IAMRoleARNKey         = "iam_role_arn"
+
+	// RegistryCredentials
+	RegistryCredentialsKey = "registry_credentials"
+	SecretKey              = "secret"
+	SecretsManagerARNKey   = "secrets_manager_arn"
+	ECRRoleARNKey          = "ecr_role_arn"
+	RegistryAuthKey        = "registry_auth"
+
+	// PodSecurity
+	SecurityKey         = "security"
+	PrivilegedKey       = "privileged"
+	ReadOnlyRootFSKey   = "read_only_root_fs"
+	RunAsNonRootKey     = "run_as_non_root"
+	RunAsUserKey        = "run_as_user"
+	DropCapabilitiesKey = "drop_capabilities"
+	SeccompProfileKey   = "seccomp_profile"
+
+	// Warmup
+	WarmupKey      = "warmup"
+	MethodKey      = "method"
+	PayloadKey     = "payload"
+	HeadersKey     = "headers"
+	NumRequestsKey = "num_requests"
 
 	// Containers
 	ContainerNameKey  = "name"
 	ImageKey          = "image"
 	EnvKey            = "env"
+	EnvFromSecretsKey = "env_from_secrets"
 	CommandKey        = "command"
 	ArgsKey           = "args"
 	ReadinessProbeKey = "readiness_probe"
@@ -62,20 +145,31 @@ const (
 	PathKey = "path"
 
 	// Compute
-	CPUKey = "cpu"
-	MemKey = "mem"
-	GPUKey = "gpu"
-	InfKey = "inf"
-	ShmKey = "shm"
+	CPUKey         = "cpu"
+	MemKey         = "mem"
+	GPUKey         = "gpu"
+	InfKey         = "inf"
+	NeuronCoresKey = "neuron_cores"
+	ShmKey         = "shm"
 
 	// Networking
-	EndpointKey = "endpoint"
+	EndpointKey           = "endpoint"
+	EndpointVisibilityKey = "endpoint_visibility"
+	CustomDomainKey       = "custom_domain"
+	EgressKey             = "egress"
+	AllowedCIDRsKey       = "allowed_cidrs"
+	AllowedDomainsKey     = "allowed_domains"
 
 	// Autoscaling
 	MinReplicasKey                  = "min_replicas"
 	MaxReplicasKey                  = "max_replicas"
 	InitReplicasKey                 = "init_replicas"
 	TargetInFlightKey               = "target_in_flight"
+	TargetGPUUtilizationKey         = "target_gpu_utilization"
+	CustomMetricKey                 = "custom_metric"
+	MaxQueueWaitKey                 = "max_queue_wait"
+	QueryKey                        = "query"
+	TargetKey                       = "target"
 	WindowKey                       = "window"
 	DownscaleStabilizationPeriodKey = "downscale_stabilization_period"
 	UpscaleStabilizationPeriodKey   = "upscale_stabilization_period"
@@ -90,12 +184,19 @@ const (
 
 	// K8s annotation
 	EndpointAnnotationKey                     = "networking.cortex.dev/endpoint"
+	EndpointVisibilityAnnotationKey           = "networking.cortex.dev/endpoint-visibility"
+	CustomDomainAnnotationKey                 = "networking.cortex.dev/custom-domain"
 	MaxConcurrencyAnnotationKey               = "pod.cortex.dev/max-concurrency"
 	MaxQueueLengthAnnotationKey               = "pod.cortex.dev/max-queue-length"
+	ModelVersionAnnotationKey                 = "pod.cortex.dev/model-version"
 	NumTrafficSplitterTargetsAnnotationKey    = "apis.cortex.dev/traffic-splitter-targets"
 	MinReplicasAnnotationKey                  = "autoscaling.cortex.dev/min-replicas"
 	MaxReplicasAnnotationKey                  = "autoscaling.cortex.dev/max-replicas"
 	TargetInFlightAnnotationKey               = "autoscaling.cortex.dev/target-in-flight"
+	TargetGPUUtilizationAnnotationKey         = "autoscaling.cortex.dev/target-gpu-utilization"
+	CustomMetricQueryAnnotationKey            = "autoscaling.cortex.dev/custom-metric-query"
+	CustomMetricTargetAnnotationKey           = "autoscaling.cortex.dev/custom-metric-target"
+	MaxQueueWaitAnnotationKey                 = "autoscaling.cortex.dev/max-queue-wait"
 	WindowAnnotationKey                       = "autoscaling.cortex.dev/window"
 	DownscaleStabilizationPeriodAnnotationKey = "autoscaling.cortex.dev/downscale-stabilization-period"
 	UpscaleStabilizationPeriodAnnotationKey   = "autoscaling.cortex.dev/upscale-stabilization-period"
@@ -20,17 +20,106 @@ import (
 	"path"
 	"strings"
 
+	"github.com/cortexlabs/cortex/pkg/apikeys"
+	"github.com/cortexlabs/cortex/pkg/config"
+	"github.com/cortexlabs/cortex/pkg/consts"
+	"github.com/cortexlabs/cortex/pkg/lib/aws"
 	"github.com/cortexlabs/cortex/pkg/lib/k8s"
+	"github.com/cortexlabs/cortex/pkg/lib/pointer"
+	"github.com/cortexlabs/cortex/pkg/modelcache"
+	"github.com/cortexlabs/cortex/pkg/types/clusterconfig"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
 	"github.com/cortexlabs/cortex/pkg/types/userconfig"
 	kcore "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
+const (
+	APIsGateway         = "apis-gateway"
+	InternalAPIsGateway = "apis-internal-gateway"
+)
+
 func K8sName(apiName string) string {
 	return "api-" + apiName
 }
 
+// APIGateway returns the istio gateway which the api's virtual service is attached to; apis whose endpoint visibility is internal are served by the internal api load balancer,
+// unless the cluster's api load balancer is itself internal (in which case the internal api load balancer doesn't exist)
+func APIGateway(networking *userconfig.Networking) string {
+	if networking == nil || networking.EndpointVisibility == nil || *networking.EndpointVisibility != userconfig.EndpointVisibilityInternal {
+		return APIsGateway
+	}
+	if config.ClusterConfig.APILoadBalancerScheme == clusterconfig.InternalLoadBalancerScheme {
+		return APIsGateway
+	}
+	return InternalAPIsGateway
+}
+
+// APIGatewayRef returns the api's gateway qualified by the namespace of the gateways, since the virtual services of apis which belong to a project other than the default project are in the project's namespace
+func APIGatewayRef(networking *userconfig.Networking) string {
+	return consts.DefaultNamespace + "/" + APIGateway(networking)
+}
+
+// ClusterServiceHost returns the fully qualified host of one of cortex's own services (which are in the default namespace), so that it can be routed to from the virtual services of apis in any project's namespace
+func ClusterServiceHost(serviceName string) string {
+	return serviceName + "." + consts.DefaultNamespace + ".svc.cluster.local"
+}
+
+// SecretEnvSecretName is the name of the operator-managed secret which holds the values of the secrets referenced by the api's env_from_secrets
+func SecretEnvSecretName(apiName string) string {
+	return K8sName(apiName) + "-secret-env"
+}
+
+// RegistryCredentialsSecretName is the name of the operator-managed secret which holds the registry credentials fetched from Secrets Manager (or the cluster's secrets backend)
+func RegistryCredentialsSecretName(apiName string) string {
+	return K8sName(apiName) + "-registry-credentials"
+}
+
+// IAMRoleServiceAccountName is the name of the operator-managed service account which is annotated with the api's iam role (for IRSA)
+func IAMRoleServiceAccountName(apiName string) string {
+	return K8sName(apiName) + "-iam-role"
+}
+
+// PodServiceAccountName returns the api's iam role service account if the pod specifies an iam role, and the default service account otherwise
+func PodServiceAccountName(apiName string, pod *userconfig.Pod) string {
+	if pod == nil || pod.IAMRoleARN == nil {
+		return ServiceAccountName
+	}
+	return IAMRoleServiceAccountName(apiName)
+}
+
+// IAMRolePodAnnotations returns the annotations which prevent the api's iam role from being provided to cortex's containers (which use the node's role),
+// or nil if the pod doesn't specify an iam role
+func IAMRolePodAnnotations(pod *userconfig.Pod) map[string]string {
+	if pod == nil || pod.IAMRoleARN == nil {
+		return nil
+	}
+	return map[string]string{
+		"eks.amazonaws.com/skip-containers": strings.Join([]string{
+			ProxyContainerName,
+			DequeuerContainerName,
+			GatewayContainerName,
+			_kubexitInitContainerName,
+			_modelCacheInitContainerName,
+		}, ","),
+	}
+}
+
+// ImagePullSecrets returns the api's registry credentials secret; if none is configured, nil is returned so that the default service account's credentials are used
+func ImagePullSecrets(apiName string, pod *userconfig.Pod) []kcore.LocalObjectReference {
+	if pod == nil || pod.RegistryCredentials == nil {
+		return nil
+	}
+
+	secretName := RegistryCredentialsSecretName(apiName)
+	if pod.RegistryCredentials.Secret != nil {
+		secretName = *pod.RegistryCredentials.Secret
+	}
+
+	return []kcore.LocalObjectReference{{Name: secretName}}
+}
+
 func GetProbeSpec(probe *userconfig.Probe) *kcore.Probe {
 	if probe == nil {
 		return nil
@@ -238,6 +327,19 @@ func ClusterConfigVolume() kcore.Volume {
 	}
 }
 
+// APIKeysVolume mounts the api keys secret; the secret is optional since it is only created once the first key is created
+func APIKeysVolume() kcore.Volume {
+	return kcore.Volume{
+		Name: _apiKeysVolume,
+		VolumeSource: kcore.VolumeSource{
+			Secret: &kcore.SecretVolumeSource{
+				SecretName: apikeys.SecretName,
+				Optional:   pointer.Bool(true),
+			},
+		},
+	}
+}
+
 func ShmVolume(q resource.Quantity, volumeName string) kcore.Volume {
 	return kcore.Volume{
 		Name: volumeName,
@@ -254,6 +356,19 @@ func KubexitVolume() kcore.Volume {
 	return k8s.EmptyDirVolume(_kubexitGraveyardName)
 }
 
+// ModelCacheVolume is the node-local model cache, which is shared by all api replicas scheduled on the node
+func ModelCacheVolume() kcore.Volume {
+	return kcore.Volume{
+		Name: _modelCacheVolumeName,
+		VolumeSource: kcore.VolumeSource{
+			HostPath: &kcore.HostPathVolumeSource{
+				Path: _modelCacheHostPath,
+				Type: hostPathTypePtr(kcore.HostPathDirectoryOrCreate),
+			},
+		},
+	}
+}
+
 func MntMount() kcore.VolumeMount {
 	return k8s.EmptyDirVolumeMount(_emptyDirVolumeName, _emptyDirMountPath)
 }
@@ -285,10 +400,48 @@ func ClusterConfigMount() kcore.VolumeMount {
 	}
 }
 
+// APIKeysMount mounts the whole api keys secret directory (rather than a sub path), so that key updates are propagated to running pods
+func APIKeysMount() kcore.VolumeMount {
+	return kcore.VolumeMount{
+		Name:      _apiKeysVolume,
+		MountPath: _apiKeysDir,
+		ReadOnly:  true,
+	}
+}
+
 func ShmMount(volumeName string) kcore.VolumeMount {
 	return k8s.EmptyDirVolumeMount(volumeName, _shmDirMountPath)
 }
 
+// ModelCacheMount mounts the api's cached model (read-only) into the user's containers
+func ModelCacheMount(api spec.API) kcore.VolumeMount {
+	return kcore.VolumeMount{
+		Name:      _modelCacheVolumeName,
+		MountPath: api.ModelCache.MountPath,
+		SubPath:   modelcache.Key(ModelCachePath(api)),
+		ReadOnly:  true,
+	}
+}
+
+func modelCacheInitMount() kcore.VolumeMount {
+	return kcore.VolumeMount{
+		Name:      _modelCacheVolumeName,
+		MountPath: _modelCacheMountPath,
+	}
+}
+
+// ModelCachePath returns the s3 path of the model which is cached for the api
+func ModelCachePath(api spec.API) string {
+	if api.ModelCache.Path != nil {
+		return *api.ModelCache.Path
+	}
+	return aws.JoinS3Path(api.ModelWatch.Path, api.ModelVersion)
+}
+
+func hostPathTypePtr(hostPathType kcore.HostPathType) *kcore.HostPathType {
+	return &hostPathType
+}
+
 func KubexitMount() kcore.VolumeMount {
 	return k8s.EmptyDirVolumeMount(_kubexitGraveyardName, _kubexitGraveyardMountPath)
 }
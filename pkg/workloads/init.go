@@ -18,11 +18,14 @@ package workloads
 
 import (
 	"github.com/cortexlabs/cortex/pkg/config"
+	"github.com/cortexlabs/cortex/pkg/lib/pointer"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
 	kcore "k8s.io/api/core/v1"
 )
 
 const (
-	_kubexitInitContainerName = "kubexit"
+	_kubexitInitContainerName    = "kubexit"
+	_modelCacheInitContainerName = "model-cache"
 )
 
 func KubexitInitContainer() kcore.Container {
@@ -36,3 +39,61 @@ func KubexitInitContainer() kcore.Container {
 		},
 	}
 }
+
+// ModelCacheInitContainer populates the node-local model cache (if the model isn't already cached) before the api's containers start
+func ModelCacheInitContainer(api spec.API) kcore.Container {
+	return kcore.Container{
+		Name:            _modelCacheInitContainerName,
+		Image:           config.ClusterConfig.ImageModelCache,
+		ImagePullPolicy: kcore.PullAlways,
+		Args: []string{
+			"--cache-dir", _modelCacheMountPath,
+			"--s3-path", ModelCachePath(api),
+			"--api-name", api.Name,
+			"--region", config.ClusterConfig.Region,
+			"--statsd-address", _statsdAddress,
+		},
+		Env: append(BaseEnvVars, kcore.EnvVar{
+			Name: "NODE_NAME",
+			ValueFrom: &kcore.EnvVarSource{
+				FieldRef: &kcore.ObjectFieldSelector{
+					FieldPath: "spec.nodeName",
+				},
+			},
+		}),
+		SecurityContext: &kcore.SecurityContext{
+			RunAsUser: pointer.Int64(0),
+		},
+		VolumeMounts: []kcore.VolumeMount{
+			modelCacheInitMount(),
+		},
+	}
+}
+
+// ModelCacheRemovalContainer removes the api's model from the node-local model cache
+func ModelCacheRemovalContainer(api spec.API) kcore.Container {
+	return kcore.Container{
+		Name:            _modelCacheInitContainerName,
+		Image:           config.ClusterConfig.ImageModelCache,
+		ImagePullPolicy: kcore.PullAlways,
+		Args: []string{
+			"--cache-dir", _modelCacheMountPath,
+			"--s3-path", ModelCachePath(api),
+			"--remove",
+		},
+		SecurityContext: &kcore.SecurityContext{
+			RunAsUser: pointer.Int64(0),
+		},
+		VolumeMounts: []kcore.VolumeMount{
+			modelCacheInitMount(),
+		},
+	}
+}
+
+// InitContainers returns the init containers of realtime and async api pods
+func InitContainers(api spec.API) []kcore.Container {
+	if api.ModelCache == nil {
+		return nil
+	}
+	return []kcore.Container{ModelCacheInitContainer(api)}
+}
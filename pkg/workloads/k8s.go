@@ -24,12 +24,18 @@ import (
 
 	"github.com/cortexlabs/cortex/pkg/config"
 	"github.com/cortexlabs/cortex/pkg/consts"
+	"github.com/cortexlabs/cortex/pkg/lib/aws"
+	"github.com/cortexlabs/cortex/pkg/lib/docker"
+	libjson "github.com/cortexlabs/cortex/pkg/lib/json"
 	"github.com/cortexlabs/cortex/pkg/lib/k8s"
+	"github.com/cortexlabs/cortex/pkg/lib/maps"
 	"github.com/cortexlabs/cortex/pkg/lib/pointer"
+	"github.com/cortexlabs/cortex/pkg/lib/sets/strset"
 	s "github.com/cortexlabs/cortex/pkg/lib/strings"
 	"github.com/cortexlabs/cortex/pkg/types/clusterconfig"
 	"github.com/cortexlabs/cortex/pkg/types/spec"
 	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+	kbatch "k8s.io/api/batch/v1"
 	kcore "k8s.io/api/core/v1"
 	kresource "k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -56,12 +62,23 @@ const (
 
 	_shmDirMountPath = "/dev/shm"
 
+	// mounted in the user containers when their root filesystem is read-only
+	_tmpDirVolumeName = "tmp"
+	_tmpDirMountPath  = "/tmp"
+
+	_modelCacheVolumeName = "model-cache"
+	_modelCacheHostPath   = "/var/lib/cortex/model-cache"
+	_modelCacheMountPath  = "/model-cache"
+
 	_clientConfigDirVolume = "client-config"
 	_clientConfigConfigMap = "client-config"
 
 	_clusterConfigDirVolume = "cluster-config"
 	_clusterConfigConfigMap = "cluster-config"
 	_clusterConfigDir       = "/configs/cluster"
+
+	_apiKeysVolume = "api-keys"
+	_apiKeysDir    = "/configs/api-keys"
 )
 
 var (
@@ -69,9 +86,35 @@ var (
 
 	// each Inferentia chip requires 128 HugePages with each HugePage having a size of 2Mi
 	_hugePagesMemPerInf = int64(128 * 2 * 1024 * 1024) // bytes
+
+	// each Inferentia chip has 4 neuron cores, which share the chip's HugePages
+	_hugePagesMemPerNeuronCore = _hugePagesMemPerInf / 4 // bytes
 )
 
 func asyncDequeuerProxyContainer(api spec.API, queueURL string) (kcore.Container, kcore.Volume) {
+	args := []string{
+		"--cluster-config", consts.DefaultInClusterConfigPath,
+		"--cluster-uid", config.ClusterConfig.ClusterUID,
+		"--probes-path", path.Join(_cortexDirMountPath, "spec", "probes.json"),
+		"--queue", queueURL,
+		"--api-kind", api.Kind.String(),
+		"--api-name", api.Name,
+		"--statsd-address", _statsdAddress,
+		"--user-port", s.Int32(*api.Pod.Port),
+		"--admin-port", consts.AdminPortStr,
+		"--workers", s.Int64(api.Pod.MaxConcurrency),
+	}
+	if api.Async != nil && api.Async.OutputPath != nil {
+		args = append(args, "--output-path", *api.Async.OutputPath)
+	}
+	if api.Async != nil && api.Async.MaxReceiveCount != nil {
+		args = append(args, "--max-receive-count", s.Int64(*api.Async.MaxReceiveCount))
+	}
+	if len(api.PredictionMetrics) > 0 {
+		predictionMetricsBytes, _ := libjson.Marshal(api.PredictionMetrics)
+		args = append(args, "--prediction-metrics", string(predictionMetricsBytes))
+	}
+
 	return kcore.Container{
 		Name:            DequeuerContainerName,
 		Image:           config.ClusterConfig.ImageDequeuer,
@@ -79,18 +122,7 @@ func asyncDequeuerProxyContainer(api spec.API, queueURL string) (kcore.Container
 		Command: []string{
 			"/dequeuer",
 		},
-		Args: []string{
-			"--cluster-config", consts.DefaultInClusterConfigPath,
-			"--cluster-uid", config.ClusterConfig.ClusterUID,
-			"--probes-path", path.Join(_cortexDirMountPath, "spec", "probes.json"),
-			"--queue", queueURL,
-			"--api-kind", api.Kind.String(),
-			"--api-name", api.Name,
-			"--statsd-address", _statsdAddress,
-			"--user-port", s.Int32(*api.Pod.Port),
-			"--admin-port", consts.AdminPortStr,
-			"--workers", s.Int64(api.Pod.MaxConcurrency),
-		},
+		Args:    args,
 		Env:     BaseEnvVars,
 		EnvFrom: BaseClusterEnvVars(),
 		Ports: []kcore.ContainerPort{
@@ -124,7 +156,28 @@ func asyncDequeuerProxyContainer(api spec.API, queueURL string) (kcore.Container
 	}, ClusterConfigVolume()
 }
 
-func batchDequeuerProxyContainer(api spec.API, jobID, queueURL string) (kcore.Container, kcore.Volume) {
+func batchDequeuerProxyContainer(api spec.API, job *spec.BatchJob) (kcore.Container, kcore.Volume) {
+	args := []string{
+		"--cluster-config", consts.DefaultInClusterConfigPath,
+		"--cluster-uid", config.ClusterConfig.ClusterUID,
+		"--probes-path", path.Join(_cortexDirMountPath, "spec", "probes.json"),
+		"--queue", job.SQSUrl,
+		"--api-kind", api.Kind.String(),
+		"--api-name", api.Name,
+		"--job-id", job.ID,
+		"--statsd-address", _statsdAddress,
+		"--user-port", s.Int32(*api.Pod.Port),
+		"--admin-port", consts.AdminPortStr,
+	}
+
+	if job.MaxRetries != nil {
+		args = append(args, "--max-retries", s.Int(*job.MaxRetries))
+	}
+
+	if job.AutoWorkers {
+		args = append(args, "--auto-workers")
+	}
+
 	return kcore.Container{
 		Name:            DequeuerContainerName,
 		Image:           config.ClusterConfig.ImageDequeuer,
@@ -132,18 +185,7 @@ func batchDequeuerProxyContainer(api spec.API, jobID, queueURL string) (kcore.Co
 		Command: []string{
 			"/dequeuer",
 		},
-		Args: []string{
-			"--cluster-config", consts.DefaultInClusterConfigPath,
-			"--cluster-uid", config.ClusterConfig.ClusterUID,
-			"--probes-path", path.Join(_cortexDirMountPath, "spec", "probes.json"),
-			"--queue", queueURL,
-			"--api-kind", api.Kind.String(),
-			"--api-name", api.Name,
-			"--job-id", jobID,
-			"--statsd-address", _statsdAddress,
-			"--user-port", s.Int32(*api.Pod.Port),
-			"--admin-port", consts.AdminPortStr,
-		},
+		Args:    args,
 		Env:     BaseEnvVars,
 		EnvFrom: BaseClusterEnvVars(),
 		Resources: kcore.ResourceRequirements{
@@ -175,26 +217,80 @@ func batchDequeuerProxyContainer(api spec.API, jobID, queueURL string) (kcore.Co
 func realtimeProxyContainer(api spec.API) (kcore.Container, kcore.Volume) {
 	proxyHasTCPProbe := !HasReadinessProbesTargetingPort(api.Pod.Containers, *api.Pod.Port)
 
+	args := []string{
+		"--cluster-config",
+		consts.DefaultInClusterConfigPath,
+		"--port",
+		consts.ProxyPortStr,
+		"--admin-port",
+		consts.AdminPortStr,
+		"--user-port",
+		s.Int32(*api.Pod.Port),
+		"--max-concurrency",
+		s.Int32(int32(api.Pod.MaxConcurrency)),
+		"--max-queue-length",
+		s.Int32(int32(api.Pod.MaxQueueLength)),
+		"--has-tcp-probe",
+		s.Bool(proxyHasTCPProbe),
+		"--api-name",
+		api.Name,
+	}
+
+	if api.Pod.Protocol == userconfig.ProtocolGRPC {
+		args = append(args, "--protocol", api.Pod.Protocol)
+	}
+
+	if api.Pod.MaxRequestBodySize != nil {
+		args = append(args, "--max-request-body-size", s.Int64(api.Pod.MaxRequestBodySize.Value()))
+	}
+
+	if api.Pod.RequestTimeout != nil {
+		args = append(args, "--request-timeout", api.Pod.RequestTimeout.String())
+	}
+
+	if api.Pod.MaxConnections != nil {
+		args = append(args, "--max-connections", s.Int64(*api.Pod.MaxConnections))
+	}
+
+	if api.Pod.Warmup != nil {
+		warmupBytes, _ := libjson.Marshal(api.Pod.Warmup)
+		args = append(args, "--warmup", string(warmupBytes))
+	}
+
+	if api.RequestLogging != nil {
+		requestLoggingBytes, _ := libjson.Marshal(api.RequestLogging)
+		args = append(args, "--request-logging", string(requestLoggingBytes))
+	}
+
+	if len(api.PredictionMetrics) > 0 {
+		predictionMetricsBytes, _ := libjson.Marshal(api.PredictionMetrics)
+		args = append(args, "--prediction-metrics", string(predictionMetricsBytes))
+	}
+
+	if api.RateLimit != nil {
+		rateLimitBytes, _ := libjson.Marshal(api.RateLimit)
+		args = append(args, "--rate-limit", string(rateLimitBytes))
+	}
+
+	if api.Authentication == userconfig.AuthenticationAPIKey {
+		args = append(args, "--api-keys-dir", _apiKeysDir)
+	}
+
+	if api.Authentication == userconfig.AuthenticationAWSIAM {
+		awsIAMPrincipalsBytes, _ := libjson.Marshal(api.AWSIAMPrincipals)
+		args = append(args, "--aws-iam-principals", string(awsIAMPrincipalsBytes))
+	}
+
+	if api.WebSocket != nil {
+		webSocketBytes, _ := libjson.Marshal(api.WebSocket)
+		args = append(args, "--websocket", string(webSocketBytes))
+	}
+
 	return kcore.Container{
 		Name:            ProxyContainerName,
 		Image:           config.ClusterConfig.ImageProxy,
 		ImagePullPolicy: kcore.PullAlways,
-		Args: []string{
-			"--cluster-config",
-			consts.DefaultInClusterConfigPath,
-			"--port",
-			consts.ProxyPortStr,
-			"--admin-port",
-			consts.AdminPortStr,
-			"--user-port",
-			s.Int32(*api.Pod.Port),
-			"--max-concurrency",
-			s.Int32(int32(api.Pod.MaxConcurrency)),
-			"--max-queue-length",
-			s.Int32(int32(api.Pod.MaxQueueLength)),
-			"--has-tcp-probe",
-			s.Bool(proxyHasTCPProbe),
-		},
+		Args:            args,
 		Ports: []kcore.ContainerPort{
 			{Name: consts.AdminPortName, ContainerPort: consts.AdminPortInt32},
 			{ContainerPort: consts.ProxyPortInt32},
@@ -230,6 +326,11 @@ func RealtimeContainers(api spec.API) ([]kcore.Container, []kcore.Volume) {
 	containers, volumes := userPodContainers(api)
 	proxyContainer, proxyVolume := realtimeProxyContainer(api)
 
+	if api.Authentication == userconfig.AuthenticationAPIKey {
+		proxyContainer.VolumeMounts = append(proxyContainer.VolumeMounts, APIKeysMount())
+		volumes = append(volumes, APIKeysVolume())
+	}
+
 	containers = append(containers, proxyContainer)
 	volumes = append(volumes, proxyVolume)
 
@@ -249,9 +350,12 @@ func AsyncContainers(api spec.API, queueURL string) ([]kcore.Container, []kcore.
 	return containers, volumes
 }
 
-func TaskContainers(api spec.API, job *spec.JobKey) ([]kcore.Container, []kcore.Volume) {
+func TaskContainers(api spec.API, job *spec.TaskJob) ([]kcore.Container, []kcore.Volume) {
 	containers, volumes := userPodContainers(api)
 	k8sName := job.K8sName()
+	paramEnvVars := taskParamEnvVars(job.Params)
+	distributedEnvVars := taskDistributedEnvVars(api, job)
+	checkpointEnvVars := taskCheckpointEnvVars(job)
 
 	volumes = append(volumes,
 		KubexitVolume(),
@@ -270,6 +374,18 @@ func TaskContainers(api spec.API, job *spec.JobKey) ([]kcore.Container, []kcore.
 		containerDeathEnvVars := getKubexitEnvVars(c.Name, containerDeathDependencies.SliceSorted(), nil)
 		containers[i].Env = append(containers[i].Env, containerDeathEnvVars...)
 
+		if len(distributedEnvVars) > 0 {
+			containers[i].Env = overrideEnvVars(containers[i].Env, distributedEnvVars)
+		}
+
+		if len(checkpointEnvVars) > 0 {
+			containers[i].Env = overrideEnvVars(containers[i].Env, checkpointEnvVars)
+		}
+
+		if len(paramEnvVars) > 0 {
+			containers[i].Env = overrideEnvVars(containers[i].Env, paramEnvVars)
+		}
+
 		if c.Command[0] != "/cortex/kubexit" {
 			containers[i].Command = append([]string{"/cortex/kubexit"}, c.Command...)
 		}
@@ -278,9 +394,154 @@ func TaskContainers(api spec.API, job *spec.JobKey) ([]kcore.Container, []kcore.
 	return containers, volumes
 }
 
+// job params take precedence over env vars with the same name from the api spec
+func taskParamEnvVars(params map[string]string) []kcore.EnvVar {
+	names := maps.StrMapKeysString(params)
+	sort.Strings(names)
+
+	envVars := make([]kcore.EnvVar, 0, len(names))
+	for _, name := range names {
+		envVars = append(envVars, kcore.EnvVar{
+			Name:  name,
+			Value: params[name],
+		})
+	}
+	return envVars
+}
+
+// CHECKPOINT_PATH is only set when the job was restarted and a checkpoint had been written before the restart
+func taskCheckpointEnvVars(job *spec.TaskJob) []kcore.EnvVar {
+	if job.Checkpoint == nil {
+		return nil
+	}
+
+	envVars := []kcore.EnvVar{
+		{
+			Name:  "CHECKPOINT_DIR",
+			Value: job.Checkpoint.JobDir(job.ID),
+		},
+	}
+
+	if job.Checkpoint.Interval != nil {
+		envVars = append(envVars, kcore.EnvVar{
+			Name:  "CHECKPOINT_INTERVAL",
+			Value: s.Int(*job.Checkpoint.Interval),
+		})
+	}
+
+	if job.CheckpointRestore != nil && job.CheckpointRestore.RestoredFrom != "" {
+		envVars = append(envVars, kcore.EnvVar{
+			Name:  "CHECKPOINT_PATH",
+			Value: job.CheckpointRestore.RestoredFrom,
+		})
+	}
+
+	return envVars
+}
+
+// each worker of a distributed task job is reachable at <job name>-<rank>.<job name> via the job's headless service
+func taskDistributedEnvVars(api spec.API, job *spec.TaskJob) []kcore.EnvVar {
+	if api.Distributed == nil {
+		return nil
+	}
+
+	k8sName := job.K8sName()
+	workers := api.Distributed.Workers
+	processesPerWorker := api.Distributed.ProcessesPerWorker
+
+	masterAddr := distributedWorkerHost(k8sName, 0)
+	port := s.Int32(api.Distributed.Port)
+
+	workerHosts := make([]string, workers)
+	for rank := range workerHosts {
+		workerHosts[rank] = fmt.Sprintf("%s:%d", distributedWorkerHost(k8sName, rank), processesPerWorker)
+	}
+
+	rankEnvVarSource := &kcore.EnvVarSource{
+		FieldRef: &kcore.ObjectFieldSelector{
+			FieldPath: fmt.Sprintf("metadata.annotations['%s']", kbatch.JobCompletionIndexAnnotation),
+		},
+	}
+
+	envVars := []kcore.EnvVar{
+		{
+			Name:  "WORLD_SIZE",
+			Value: s.Int32(workers * processesPerWorker),
+		},
+		{
+			Name:  "NNODES",
+			Value: s.Int32(workers),
+		},
+		{
+			Name:  "NPROC_PER_NODE",
+			Value: s.Int32(processesPerWorker),
+		},
+		{
+			Name:      "NODE_RANK",
+			ValueFrom: rankEnvVarSource,
+		},
+		{
+			Name:      "RANK",
+			ValueFrom: rankEnvVarSource,
+		},
+		{
+			Name:  "MASTER_ADDR",
+			Value: masterAddr,
+		},
+		{
+			Name:  "MASTER_PORT",
+			Value: port,
+		},
+		{
+			Name:  "CORTEX_WORKER_HOSTS",
+			Value: strings.Join(workerHosts, ","),
+		},
+	}
+
+	if api.Distributed.RendezvousBackend == userconfig.RendezvousBackendC10d {
+		envVars = append(envVars,
+			kcore.EnvVar{
+				Name:  "RDZV_BACKEND",
+				Value: userconfig.RendezvousBackendC10d,
+			},
+			kcore.EnvVar{
+				Name:  "RDZV_ENDPOINT",
+				Value: masterAddr + ":" + port,
+			},
+			kcore.EnvVar{
+				Name:  "RDZV_ID",
+				Value: job.ID,
+			},
+		)
+	}
+
+	return envVars
+}
+
+// distributedWorkerHost returns the hostname of a distributed task job's worker with the given rank
+func distributedWorkerHost(jobK8sName string, rank int) string {
+	return fmt.Sprintf("%s-%d.%s", jobK8sName, rank, jobK8sName)
+}
+
+// env vars in overrides replace any env vars with the same name
+func overrideEnvVars(envVars []kcore.EnvVar, overrides []kcore.EnvVar) []kcore.EnvVar {
+	overrideNames := strset.New()
+	for _, envVar := range overrides {
+		overrideNames.Add(envVar.Name)
+	}
+
+	filtered := make([]kcore.EnvVar, 0, len(envVars)+len(overrides))
+	for _, envVar := range envVars {
+		if !overrideNames.Has(envVar.Name) {
+			filtered = append(filtered, envVar)
+		}
+	}
+	return append(filtered, overrides...)
+}
+
 func BatchContainers(api spec.API, job *spec.BatchJob) ([]kcore.Container, []kcore.Volume) {
 	userContainers, userVolumes := userPodContainers(api)
-	dequeuerContainer, dequeuerVolume := batchDequeuerProxyContainer(api, job.ID, job.SQSUrl)
+	dequeuerContainer, dequeuerVolume := batchDequeuerProxyContainer(api, job)
 
 	// make sure the dequeuer starts first to allow it to start watching the graveyard before user containers begin
 	containers := append([]kcore.Container{dequeuerContainer}, userContainers...)
@@ -315,6 +576,12 @@ func BatchContainers(api spec.API, job *spec.BatchJob) ([]kcore.Container, []kco
 	return containers, volumes
 }
 
+// UserPodContainers returns the api's containers (and the volumes which they mount) without the containers which cortex adds,
+// so that they can be run outside of the cluster with the same environment (e.g. by `cortex deploy --local`)
+func UserPodContainers(api spec.API) ([]kcore.Container, []kcore.Volume) {
+	return userPodContainers(api)
+}
+
 func userPodContainers(api spec.API) ([]kcore.Container, []kcore.Volume) {
 	volumes := []kcore.Volume{
 		MntVolume(),
@@ -327,13 +594,21 @@ func userPodContainers(api spec.API) ([]kcore.Container, []kcore.Volume) {
 		ClientConfigMount(),
 	}
 
+	if api.ModelCache != nil {
+		volumes = append(volumes, ModelCacheVolume())
+		containerMounts = append(containerMounts, ModelCacheMount(api))
+	}
+
+	if api.Pod.Security != nil && api.Pod.Security.ReadOnlyRootFS {
+		volumes = append(volumes, k8s.EmptyDirVolume(_tmpDirVolumeName))
+		containerMounts = append(containerMounts, k8s.EmptyDirVolumeMount(_tmpDirVolumeName, _tmpDirMountPath))
+	}
+
 	containers := make([]kcore.Container, len(api.Pod.Containers))
 	for i, container := range api.Pod.Containers {
 		containerResourceList := kcore.ResourceList{}
 		containerResourceLimitsList := kcore.ResourceList{}
-		securityContext := kcore.SecurityContext{
-			Privileged: pointer.Bool(true),
-		}
+		securityContext := userContainerSecurityContext(api.Pod.Security)
 
 		var readinessProbe *kcore.Probe
 		if api.Kind == userconfig.RealtimeAPIKind {
@@ -360,12 +635,17 @@ func userPodContainers(api spec.API) ([]kcore.Container, []kcore.Volume) {
 			containerResourceLimitsList["aws.amazon.com/neuron"] = *kresource.NewQuantity(container.Compute.Inf, kresource.DecimalSI)
 			containerResourceLimitsList["hugepages-2Mi"] = *kresource.NewQuantity(totalHugePages, kresource.BinarySI)
 
-			securityContext.Capabilities = &kcore.Capabilities{
-				Add: []kcore.Capability{
-					"SYS_ADMIN",
-					"IPC_LOCK",
-				},
-			}
+			addNeuronCapabilities(&securityContext)
+		}
+
+		if container.Compute.NeuronCores > 0 {
+			totalHugePages := container.Compute.NeuronCores * _hugePagesMemPerNeuronCore
+			containerResourceList["aws.amazon.com/neuroncore"] = *kresource.NewQuantity(container.Compute.NeuronCores, kresource.DecimalSI)
+			containerResourceList["hugepages-2Mi"] = *kresource.NewQuantity(totalHugePages, kresource.BinarySI)
+			containerResourceLimitsList["aws.amazon.com/neuroncore"] = *kresource.NewQuantity(container.Compute.NeuronCores, kresource.DecimalSI)
+			containerResourceLimitsList["hugepages-2Mi"] = *kresource.NewQuantity(totalHugePages, kresource.BinarySI)
+
+			addNeuronCapabilities(&securityContext)
 		}
 
 		if container.Compute.Shm != nil {
@@ -387,6 +667,26 @@ func userPodContainers(api spec.API) ([]kcore.Container, []kcore.Volume) {
 			})
 		}
 
+		if api.ModelCache != nil {
+			containerEnvVars = append(containerEnvVars, kcore.EnvVar{
+				Name:  "CORTEX_MODEL_DIR",
+				Value: api.ModelCache.MountPath,
+			})
+		}
+
+		if api.ModelWatch != nil && api.ModelVersion != "" {
+			containerEnvVars = append(containerEnvVars,
+				kcore.EnvVar{
+					Name:  "CORTEX_MODEL_PATH",
+					Value: aws.JoinS3Path(api.ModelWatch.Path, api.ModelVersion),
+				},
+				kcore.EnvVar{
+					Name:  "CORTEX_MODEL_VERSION",
+					Value: api.ModelVersion,
+				},
+			)
+		}
+
 		envVarNames := make([]string, 0, len(container.Env))
 		for envVarName := range container.Env {
 			envVarNames = append(envVarNames, envVarName)
@@ -402,9 +702,29 @@ func userPodContainers(api spec.API) ([]kcore.Container, []kcore.Volume) {
 			})
 		}
 
+		secretEnvVarNames := make([]string, 0, len(container.EnvFromSecrets))
+		for envVarName := range container.EnvFromSecrets {
+			secretEnvVarNames = append(secretEnvVarNames, envVarName)
+		}
+		sort.Strings(secretEnvVarNames)
+
+		for _, envVarName := range secretEnvVarNames {
+			containerEnvVars = append(containerEnvVars, kcore.EnvVar{
+				Name: envVarName,
+				ValueFrom: &kcore.EnvVarSource{
+					SecretKeyRef: &kcore.SecretKeySelector{
+						LocalObjectReference: kcore.LocalObjectReference{
+							Name: SecretEnvSecretName(api.Name),
+						},
+						Key: container.EnvFromSecrets[envVarName],
+					},
+				},
+			})
+		}
+
 		containers[i] = kcore.Container{
 			Name:           container.Name,
-			Image:          container.Image,
+			Image:          containerImage(container),
 			Command:        container.Command,
 			Args:           container.Args,
 			Env:            containerEnvVars,
@@ -424,6 +744,70 @@ func userPodContainers(api spec.API) ([]kcore.Container, []kcore.Volume) {
 	return containers, volumes
 }
 
+// containerImage returns the container's image, referenced by the digest which was resolved when the api was deployed (if available)
+func containerImage(container *userconfig.Container) string {
+	if container.ImageDigest == "" {
+		return container.Image
+	}
+	return docker.ImageWithDigest(container.Image, container.ImageDigest)
+}
+
+// the neuron runtime requires SYS_ADMIN and IPC_LOCK; kubernetes rejects containers which add SYS_ADMIN and disallow privilege escalation,
+// so privilege escalation is left unset for them
+func addNeuronCapabilities(securityContext *kcore.SecurityContext) {
+	if securityContext.Capabilities == nil {
+		securityContext.Capabilities = &kcore.Capabilities{}
+	}
+	securityContext.Capabilities.Add = []kcore.Capability{
+		"SYS_ADMIN",
+		"IPC_LOCK",
+	}
+	securityContext.AllowPrivilegeEscalation = nil
+}
+
+// userContainerSecurityContext returns the security context of a user container; containers are only privileged if the api explicitly requests it
+func userContainerSecurityContext(security *userconfig.PodSecurity) kcore.SecurityContext {
+	if security == nil {
+		// specs which were generated before the security section was defaulted
+		security = &userconfig.PodSecurity{SeccompProfile: userconfig.SeccompProfileRuntimeDefault}
+	}
+
+	securityContext := kcore.SecurityContext{
+		Privileged:             pointer.Bool(security.Privileged),
+		ReadOnlyRootFilesystem: pointer.Bool(security.ReadOnlyRootFS),
+		RunAsUser:              security.RunAsUser,
+	}
+
+	if !security.Privileged {
+		securityContext.AllowPrivilegeEscalation = pointer.Bool(false)
+	}
+
+	if security.RunAsNonRoot {
+		securityContext.RunAsNonRoot = pointer.Bool(true)
+	}
+
+	if len(security.DropCapabilities) > 0 {
+		securityContext.Capabilities = &kcore.Capabilities{}
+		for _, capability := range security.DropCapabilities {
+			securityContext.Capabilities.Drop = append(securityContext.Capabilities.Drop, kcore.Capability(capability))
+		}
+	}
+
+	switch {
+	case security.SeccompProfile == userconfig.SeccompProfileRuntimeDefault:
+		securityContext.SeccompProfile = &kcore.SeccompProfile{Type: kcore.SeccompProfileTypeRuntimeDefault}
+	case security.SeccompProfile == userconfig.SeccompProfileUnconfined:
+		securityContext.SeccompProfile = &kcore.SeccompProfile{Type: kcore.SeccompProfileTypeUnconfined}
+	case strings.HasPrefix(security.SeccompProfile, userconfig.SeccompProfileLocalhostPrefix):
+		securityContext.SeccompProfile = &kcore.SeccompProfile{
+			Type:             kcore.SeccompProfileTypeLocalhost,
+			LocalhostProfile: pointer.String(strings.TrimPrefix(security.SeccompProfile, userconfig.SeccompProfileLocalhostPrefix)),
+		}
+	}
+
+	return securityContext
+}
+
 func NodeSelectors() map[string]string {
 	return map[string]string{
 		"workload": "true",
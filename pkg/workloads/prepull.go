@@ -0,0 +1,112 @@
+/*
+Copyright 2022 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workloads
+
+import (
+	"fmt"
+
+	"github.com/cortexlabs/cortex/pkg/config"
+	"github.com/cortexlabs/cortex/pkg/lib/k8s"
+	"github.com/cortexlabs/cortex/pkg/lib/sets/strset"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+	kcore "k8s.io/api/core/v1"
+	kresource "k8s.io/apimachinery/pkg/api/resource"
+)
+
+const (
+	_prepullInitContainerName = "prepull"
+	_prepullVolumeName        = "prepull"
+	_prepullDir               = "/prepull"
+)
+
+// PrepullImages returns the images which are pulled when the api's replicas start on a node: the images of the user's
+// containers, the image of the api's sidecar, and the image of the model cache init container (if configured)
+func PrepullImages(api spec.API) []string {
+	var images []string
+	seen := strset.New()
+	add := func(image string) {
+		if !seen.Has(image) {
+			seen.Add(image)
+			images = append(images, image)
+		}
+	}
+
+	for _, container := range api.Pod.Containers {
+		add(containerImage(container))
+	}
+
+	switch api.Kind {
+	case userconfig.RealtimeAPIKind:
+		add(config.ClusterConfig.ImageProxy)
+	case userconfig.AsyncAPIKind:
+		add(config.ClusterConfig.ImageDequeuer)
+	}
+
+	if api.ModelCache != nil {
+		add(config.ClusterConfig.ImageModelCache)
+	}
+
+	return images
+}
+
+// PrepullContainers returns the containers of the api's image pre-pull pods: the prepull binary is copied into a shared volume
+// by the init container, and each image runs it in a container (which sleeps until it's terminated), so that the images are
+// pulled onto each node, and aren't garbage collected by the kubelet while they are in use
+func PrepullContainers(api spec.API) ([]kcore.Container, []kcore.Container, []kcore.Volume) {
+	initContainers := []kcore.Container{
+		{
+			Name:            _prepullInitContainerName,
+			Image:           config.ClusterConfig.ImagePrepull,
+			ImagePullPolicy: kcore.PullAlways,
+			Args:            []string{"--copy-to", _prepullDir},
+			VolumeMounts: []kcore.VolumeMount{
+				prepullMount(),
+			},
+		},
+	}
+
+	images := PrepullImages(api)
+	containers := make([]kcore.Container, len(images))
+	for i, image := range images {
+		containers[i] = kcore.Container{
+			Name:            fmt.Sprintf("image-%d", i),
+			Image:           image,
+			ImagePullPolicy: kcore.PullAlways,
+			Command:         []string{_prepullDir + "/prepull"},
+			Resources: kcore.ResourceRequirements{
+				Requests: kcore.ResourceList{
+					kcore.ResourceCPU:    kresource.MustParse("1m"),
+					kcore.ResourceMemory: kresource.MustParse("8Mi"),
+				},
+			},
+			VolumeMounts: []kcore.VolumeMount{
+				prepullMount(),
+			},
+		}
+	}
+
+	volumes := []kcore.Volume{
+		k8s.EmptyDirVolume(_prepullVolumeName),
+	}
+
+	return initContainers, containers, volumes
+}
+
+func prepullMount() kcore.VolumeMount {
+	return k8s.EmptyDirVolumeMount(_prepullVolumeName, _prepullDir)
+}